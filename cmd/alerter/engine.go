@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"log"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Alert states
+const (
+	statePending  = "pending"  // above the threshold, for less than the rule's For
+	stateFiring   = "firing"   // notified
+	stateResolved = "resolved" // back under the threshold; only ever sent, never kept
+)
+
+// alert is one rule's subject that is above the rule's threshold
+type alert struct {
+	Rule        string            `json:"rule"`
+	Kind        string            `json:"kind"`
+	Severity    string            `json:"severity"`
+	Labels      map[string]string `json:"labels"`
+	Value       float64           `json:"value"`
+	Threshold   float64           `json:"threshold"`
+	State       string            `json:"state"`
+	ActiveSince time.Time         `json:"active_since"`
+
+	lastNotified time.Time
+}
+
+// key identifies the alert across evaluations: its rule and labels
+func (a *alert) key() string {
+	return a.Rule + "{" + labelString(a.Labels) + "}"
+}
+
+// labelString renders labels as k=v pairs in key order
+func labelString(labels map[string]string) string {
+	keys := slices.Sorted(maps.Keys(labels))
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = k + "=" + labels[k]
+	}
+	return strings.Join(parts, ",")
+}
+
+// engine evaluates the rules and notifies when alerts start firing, keep firing past the
+// repeat interval, and resolve
+type engine struct {
+	rules     []*rule
+	src       sources
+	notifiers []notifier
+	repeat    time.Duration
+
+	mu     sync.Mutex
+	active map[string]*alert
+}
+
+func newEngine(rules []*rule, src sources, notifiers []notifier, repeat time.Duration) *engine {
+	return &engine{rules: rules, src: src, notifiers: notifiers, repeat: repeat, active: map[string]*alert{}}
+}
+
+// run evaluates the rules every interval until ctx is done
+func (e *engine) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		e.evaluate(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// evaluate runs every rule once. A rule that fails to evaluate keeps its alerts as they were,
+// so an unreachable database doesn't resolve everything.
+func (e *engine) evaluate(ctx context.Context, now time.Time) {
+	for _, r := range e.rules {
+		evalCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+		samples, err := r.eval(evalCtx, e.src)
+		cancel()
+		if err != nil {
+			ruleErrors.WithLabelValues(r.Name).Inc()
+			log.Printf("Alert rule %s failed: %v", r.Name, err)
+			continue
+		}
+		for _, n := range e.apply(r, samples, now) {
+			e.notify(ctx, n)
+		}
+	}
+	e.recordFiring()
+}
+
+// apply updates r's alerts from samples and returns the notifications they call for
+func (e *engine) apply(r *rule, samples []sample, now time.Time) []notification {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var out []notification
+	seen := map[string]bool{}
+	for _, s := range samples {
+		if s.Value <= r.Threshold {
+			continue
+		}
+		a := &alert{Rule: r.Name, Kind: r.Kind, Severity: r.Severity, Labels: s.Labels, Threshold: r.Threshold}
+		k := a.key()
+		seen[k] = true
+		if existing, ok := e.active[k]; ok {
+			a = existing
+		} else {
+			a.State = statePending
+			a.ActiveSince = now
+			e.active[k] = a
+		}
+		a.Value = s.Value
+
+		switch {
+		case a.State == statePending && now.Sub(a.ActiveSince) >= time.Duration(r.For):
+			a.State = stateFiring
+			a.lastNotified = now
+			out = append(out, notification{Status: stateFiring, Alert: *a, At: now})
+		case a.State == stateFiring && e.repeat > 0 && now.Sub(a.lastNotified) >= e.repeat:
+			a.lastNotified = now
+			out = append(out, notification{Status: stateFiring, Alert: *a, At: now})
+		}
+	}
+
+	for k, a := range e.active {
+		if a.Rule != r.Name || seen[k] {
+			continue
+		}
+		delete(e.active, k)
+		if a.State == stateFiring {
+			out = append(out, notification{Status: stateResolved, Alert: *a, At: now})
+		}
+	}
+	return out
+}
+
+// notify sends n through every notifier, logging the ones that fail
+func (e *engine) notify(ctx context.Context, n notification) {
+	log.Printf("Alert %s", n.text())
+	for _, nt := range e.notifiers {
+		sendCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+		err := nt.notify(sendCtx, n)
+		cancel()
+		if err != nil {
+			notificationsSent.WithLabelValues(nt.name(), "error").Inc()
+			log.Printf("Notifying %s of %s failed: %v", nt.name(), n.Alert.key(), err)
+			continue
+		}
+		notificationsSent.WithLabelValues(nt.name(), "ok").Inc()
+	}
+}
+
+// recordFiring publishes how many alerts each rule has firing
+func (e *engine) recordFiring() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	alertsFiring.Reset()
+	for _, r := range e.rules {
+		alertsFiring.WithLabelValues(r.Name, r.Severity).Set(0)
+	}
+	for _, a := range e.active {
+		if a.State == stateFiring {
+			alertsFiring.WithLabelValues(a.Rule, a.Severity).Inc()
+		}
+	}
+}
+
+// alerts returns the pending and firing alerts, by rule and then labels
+func (e *engine) alerts() []alert {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]alert, 0, len(e.active))
+	for _, a := range e.active {
+		out = append(out, *a)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].key() < out[j].key() })
+	return out
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+)
+
+// recordingNotifier keeps what it is sent, failing when err is set
+type recordingNotifier struct {
+	sent []notification
+	err  error
+}
+
+func (r *recordingNotifier) name() string { return "recording" }
+
+func (r *recordingNotifier) notify(_ context.Context, n notification) error {
+	r.sent = append(r.sent, n)
+	return r.err
+}
+
+func TestEngine_Lifecycle(t *testing.T) {
+	ages := map[string]float64{}
+	var dbErr error
+	pool := &dbfake.Pool{QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+		if dbErr != nil {
+			return nil, dbErr
+		}
+		var rows [][]any
+		for tenant, age := range ages {
+			rows = append(rows, []any{tenant, age})
+		}
+		return dbfake.NewRows(rows...), nil
+	}}
+	r := &rule{Name: "backlog-age", Kind: kindBacklogAge, Threshold: 300, For: duration(time.Minute), Severity: "warning"}
+	rec := &recordingNotifier{}
+	eng := newEngine([]*rule{r}, sources{pool: pool}, []notifier{rec}, time.Hour)
+	ctx := context.Background()
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	ages["tenant_a"] = 400
+	ages["tenant_b"] = 100 // under the threshold
+	eng.evaluate(ctx, start)
+	if got := eng.alerts(); len(got) != 1 || got[0].State != statePending || len(rec.sent) != 0 {
+		t.Fatalf("after first breach alerts = %+v, sent %d, want tenant_a pending and nothing sent", got, len(rec.sent))
+	}
+
+	eng.evaluate(ctx, start.Add(time.Minute))
+	if len(rec.sent) != 1 || rec.sent[0].Status != stateFiring || rec.sent[0].Alert.Labels["tenant_id"] != "tenant_a" {
+		t.Fatalf("after For sent = %+v, want tenant_a firing", rec.sent)
+	}
+	if got := testutil.ToFloat64(alertsFiring.WithLabelValues("backlog-age", "warning")); got != 1 {
+		t.Errorf("alerts firing = %v, want 1", got)
+	}
+
+	// A failed evaluation leaves the alert firing rather than resolving it
+	dbErr = errors.New("connection refused")
+	eng.evaluate(ctx, start.Add(2*time.Minute))
+	dbErr = nil
+	if got := eng.alerts(); len(got) != 1 || got[0].State != stateFiring || len(rec.sent) != 1 {
+		t.Fatalf("after a failed evaluation alerts = %+v, sent %d, want it still firing and nothing sent", got, len(rec.sent))
+	}
+
+	eng.evaluate(ctx, start.Add(time.Minute+time.Hour))
+	if len(rec.sent) != 2 || rec.sent[1].Status != stateFiring {
+		t.Fatalf("after the repeat interval sent = %+v, want a repeat", rec.sent)
+	}
+
+	delete(ages, "tenant_a")
+	eng.evaluate(ctx, start.Add(2*time.Hour))
+	if len(rec.sent) != 3 || rec.sent[2].Status != stateResolved || len(eng.alerts()) != 0 {
+		t.Fatalf("after recovery sent = %+v, alerts = %+v, want a resolution and no alerts", rec.sent, eng.alerts())
+	}
+	if got := testutil.ToFloat64(alertsFiring.WithLabelValues("backlog-age", "warning")); got != 0 {
+		t.Errorf("alerts firing = %v, want 0", got)
+	}
+
+	// Pending alerts resolve without a notification
+	ages["tenant_c"] = 500
+	eng.evaluate(ctx, start.Add(3*time.Hour))
+	delete(ages, "tenant_c")
+	eng.evaluate(ctx, start.Add(3*time.Hour+time.Second))
+	if len(rec.sent) != 3 {
+		t.Errorf("sent = %+v, want nothing for an alert that never fired", rec.sent)
+	}
+}
+
+func TestEngine_NotifierFailure(t *testing.T) {
+	pool := &dbfake.Pool{QueryRowFunc: func(string, []any) pgx.Row { return dbfake.Row{Values: []any{int64(100)}} }}
+	r := &rule{Name: "dlq-rate", Kind: kindDLQRate, Threshold: 10, Window: duration(time.Minute), Severity: "critical"}
+	failing, ok := &recordingNotifier{err: errors.New("HTTP 500")}, &recordingNotifier{}
+	eng := newEngine([]*rule{r}, sources{pool: pool}, []notifier{failing, ok}, time.Hour)
+
+	before := testutil.ToFloat64(notificationsSent.WithLabelValues("recording", "error"))
+	eng.evaluate(context.Background(), time.Now())
+	if len(ok.sent) != 1 || ok.sent[0].Alert.Value != 100 {
+		t.Errorf("second notifier sent = %+v, want the alert despite the first failing", ok.sent)
+	}
+	if got := testutil.ToFloat64(notificationsSent.WithLabelValues("recording", "error")); got != before+1 {
+		t.Errorf("failed notifications = %v, want %v", got, before+1)
+	}
+}
+
+func TestNotifiers(t *testing.T) {
+	var paths []string
+	var slackText string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		if r.URL.Path == "/slack" {
+			var body struct{ Text string }
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			slackText = body.Text
+		}
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+
+	got := notifiers(config.Alerter{SlackWebhookURL: srv.URL + "/slack", WebhookURL: srv.URL + "/broken", SMTPAddr: "smtp:25"})
+	if len(got) != 2 {
+		t.Fatalf("notifiers() = %d, want slack and webhook (email needs recipients)", len(got))
+	}
+	n := notification{Status: stateFiring, At: time.Now(), Alert: alert{Rule: "dlq-rate", Kind: kindDLQRate, Severity: "critical", Value: 12, Threshold: 10, Labels: map[string]string{}}}
+	if err := got[0].notify(context.Background(), n); err != nil {
+		t.Errorf("slack notify error = %v", err)
+	}
+	if err := got[1].notify(context.Background(), n); err == nil {
+		t.Error("webhook notify to a failing receiver succeeded, want an error")
+	}
+	if len(paths) != 2 || paths[0] != "/slack" || !strings.HasSuffix(slackText, n.text()) {
+		t.Errorf("requests = %v with Slack text %q, want one to each URL and the summary on Slack", paths, slackText)
+	}
+	if want := "[FIRING] dlq-rate (critical): dlq_rate 12, threshold 10"; n.text() != want {
+		t.Errorf("text() = %q, want %q", n.text(), want)
+	}
+
+	email := &emailNotifier{from: "alerts@example.com", to: []string{"ops@example.com", "oncall@example.com"}}
+	msg := string(email.message(n))
+	if !strings.Contains(msg, "To: ops@example.com, oncall@example.com\r\n") || !strings.Contains(msg, "Subject: [FIRING] dlq-rate") {
+		t.Errorf("email message = %q, want both recipients and the summary as subject", msg)
+	}
+}
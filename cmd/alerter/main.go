@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db"
+	"github.com/austindbirch/harbor_hook/internal/version"
+)
+
+var (
+	alertsFiring = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "harborhook_alerter_alerts_firing",
+		Help: "Alerts currently firing by rule and severity",
+	}, []string{"rule", "severity"})
+
+	notificationsSent = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "harborhook_alerter_notifications_total",
+		Help: "Notifications sent by notifier and result (ok or error)",
+	}, []string{"notifier", "result"})
+
+	ruleErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "harborhook_alerter_rule_errors_total",
+		Help: "Rule evaluations that failed, by rule",
+	}, []string{"rule"})
+)
+
+func init() {
+	prometheus.MustRegister(alertsFiring)
+	prometheus.MustRegister(notificationsSent)
+	prometheus.MustRegister(ruleErrors)
+}
+
+func main() {
+	cfg := config.FromEnv()
+	ctx := context.Background()
+
+	rules, err := loadRules(cfg.Alerter.Rules)
+	if err != nil {
+		log.Fatalf("Alert rules: %v", err)
+	}
+
+	var src sources
+	if needsDB(rules) {
+		pool, err := db.Connect(ctx, cfg.DSN())
+		if err != nil {
+			log.Fatalf("Database connection failed: %v", err)
+		}
+		defer pool.Close()
+		src.pool = pool
+	}
+	if cfg.Alerter.PrometheusURL != "" {
+		src.prom = newPromClient(cfg.Alerter.PrometheusURL)
+	}
+
+	notify := notifiers(cfg.Alerter)
+	if len(notify) == 0 {
+		log.Printf("No notifiers configured; alerts are only logged and listed on /alerts")
+	}
+	eng := newEngine(rules, src, notify, cfg.Alerter.RepeatInterval)
+
+	log.Printf("Alerter starting on port %s", cfg.Alerter.Port)
+	log.Printf("Evaluating %d rules every %s with %d notifiers", len(rules), cfg.Alerter.EvalInterval, len(notify))
+
+	go eng.run(ctx, cfg.Alerter.EvalInterval)
+
+	http.Handle("/metrics", promhttp.Handler())
+	http.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, "OK")
+	})
+	http.HandleFunc("/version", version.HTTPHandler())
+	http.HandleFunc("/alerts", handleAlerts(eng))
+
+	log.Fatal(http.ListenAndServe(":"+cfg.Alerter.Port, nil))
+}
+
+// handleAlerts lists the pending and firing alerts
+func handleAlerts(eng *engine) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"alerts": eng.alerts()})
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+// notification is an alert starting to fire, still firing, or resolving
+type notification struct {
+	Status string    `json:"status"` // firing or resolved
+	Alert  alert     `json:"alert"`
+	At     time.Time `json:"at"`
+}
+
+// text is the one-line summary Slack and email subjects carry
+func (n notification) text() string {
+	a := n.Alert
+	s := fmt.Sprintf("[%s] %s (%s): %s %g, threshold %g", strings.ToUpper(n.Status), a.Rule, a.Severity, a.Kind, a.Value, a.Threshold)
+	if labels := labelString(a.Labels); labels != "" {
+		s += " " + labels
+	}
+	return s
+}
+
+// notifier delivers notifications to operators
+type notifier interface {
+	name() string
+	notify(ctx context.Context, n notification) error
+}
+
+// notifiers returns a notifier for each destination cfg configures
+func notifiers(cfg config.Alerter) []notifier {
+	client := &http.Client{Timeout: 10 * time.Second}
+	var out []notifier
+	if cfg.SlackWebhookURL != "" {
+		out = append(out, &slackNotifier{url: cfg.SlackWebhookURL, client: client})
+	}
+	if cfg.WebhookURL != "" {
+		out = append(out, &webhookNotifier{url: cfg.WebhookURL, client: client})
+	}
+	if cfg.SMTPAddr != "" && len(cfg.EmailTo) > 0 {
+		out = append(out, &emailNotifier{addr: cfg.SMTPAddr, user: cfg.SMTPUser, pass: cfg.SMTPPass, from: cfg.EmailFrom, to: cfg.EmailTo})
+	}
+	return out
+}
+
+// slackNotifier posts to a Slack incoming webhook
+type slackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (s *slackNotifier) name() string { return "slack" }
+
+func (s *slackNotifier) notify(ctx context.Context, n notification) error {
+	icon := ":rotating_light:"
+	if n.Status == stateResolved {
+		icon = ":white_check_mark:"
+	}
+	return postJSON(ctx, s.client, s.url, map[string]string{"text": icon + " " + n.text()})
+}
+
+// webhookNotifier POSTs the notification as JSON, for paging tools and chat bridges
+type webhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+func (w *webhookNotifier) name() string { return "webhook" }
+
+func (w *webhookNotifier) notify(ctx context.Context, n notification) error {
+	return postJSON(ctx, w.client, w.url, n)
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body any) error {
+	b, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP %s", resp.Status)
+	}
+	return nil
+}
+
+// emailNotifier sends a plain-text email through an SMTP server
+type emailNotifier struct {
+	addr string
+	user string
+	pass string
+	from string
+	to   []string
+}
+
+func (e *emailNotifier) name() string { return "email" }
+
+func (e *emailNotifier) notify(_ context.Context, n notification) error {
+	var auth smtp.Auth
+	if e.user != "" {
+		host, _, _ := net.SplitHostPort(e.addr)
+		auth = smtp.PlainAuth("", e.user, e.pass, host)
+	}
+	return smtp.SendMail(e.addr, auth, e.from, e.to, e.message(n))
+}
+
+func (e *emailNotifier) message(n notification) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", e.from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", n.text())
+	fmt.Fprintf(&b, "Date: %s\r\n", n.At.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	a := n.Alert
+	fmt.Fprintf(&b, "Rule:      %s (%s)\r\n", a.Rule, a.Kind)
+	fmt.Fprintf(&b, "Status:    %s\r\n", n.Status)
+	fmt.Fprintf(&b, "Severity:  %s\r\n", a.Severity)
+	fmt.Fprintf(&b, "Value:     %g (threshold %g)\r\n", a.Value, a.Threshold)
+	fmt.Fprintf(&b, "Since:     %s\r\n", a.ActiveSince.Format(time.RFC3339))
+	if labels := labelString(a.Labels); labels != "" {
+		fmt.Fprintf(&b, "Labels:    %s\r\n", labels)
+	}
+	return []byte(b.String())
+}
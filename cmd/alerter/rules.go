@@ -0,0 +1,283 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/db"
+)
+
+// Rule kinds. Thresholds are per minute for dlq_rate, seconds for backlog_age and
+// endpoint_disabled, failed attempts for failure_streak and the query's value for promql.
+const (
+	kindDLQRate       = "dlq_rate"          // entries dead-lettered per minute over Window
+	kindBacklogAge    = "backlog_age"       // age of each tenant's oldest undelivered delivery
+	kindFailureStreak = "failure_streak"    // each endpoint's failed attempts since its last delivery, within Window
+	kindDisabled      = "endpoint_disabled" // how long each endpoint disabled for failing has been disabled
+	kindPromQL        = "promql"            // every series Expr returns
+)
+
+// defaultRules are evaluated when ALERTER_RULES is empty
+const defaultRules = `{"rules": [
+	{"name": "dlq-rate", "kind": "dlq_rate", "threshold": 10, "window": "5m", "severity": "critical"},
+	{"name": "backlog-age", "kind": "backlog_age", "threshold": 300, "for": "1m", "severity": "warning"},
+	{"name": "endpoint-failure-streak", "kind": "failure_streak", "threshold": 20, "window": "24h", "severity": "warning"},
+	{"name": "endpoint-disabled", "kind": "endpoint_disabled", "threshold": 0, "severity": "warning"}
+]}`
+
+// rule fires for each sample whose value is above Threshold for at least For
+type rule struct {
+	Name      string   `json:"name"`
+	Kind      string   `json:"kind"`
+	Threshold float64  `json:"threshold"`
+	Window    duration `json:"window"`
+	For       duration `json:"for"`
+	Severity  string   `json:"severity"`
+	Expr      string   `json:"expr"` // promql only
+}
+
+// duration reads a Go duration string such as "5m"
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\": %w", err)
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = duration(v)
+	return nil
+}
+
+func (d duration) MarshalJSON() ([]byte, error) { return json.Marshal(time.Duration(d).String()) }
+
+// loadRules parses raw, which is either inline JSON or the path of a file holding it. An
+// empty raw loads defaultRules.
+func loadRules(raw string) ([]*rule, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		raw = defaultRules
+	}
+	doc := []byte(raw)
+	if !strings.HasPrefix(raw, "{") {
+		b, err := os.ReadFile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("read alert rules: %w", err)
+		}
+		doc = b
+	}
+	var parsed struct {
+		Rules []*rule `json:"rules"`
+	}
+	if err := json.Unmarshal(doc, &parsed); err != nil {
+		return nil, fmt.Errorf("parse alert rules: %w", err)
+	}
+	seen := map[string]bool{}
+	for i, r := range parsed.Rules {
+		if r == nil || r.Name == "" {
+			return nil, fmt.Errorf("alert rule %d has no name", i+1)
+		}
+		if seen[r.Name] {
+			return nil, fmt.Errorf("alert rule %s is defined twice", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Severity == "" {
+			r.Severity = "warning"
+		}
+		switch r.Kind {
+		case kindDLQRate:
+			if r.Window <= 0 {
+				r.Window = duration(5 * time.Minute)
+			}
+		case kindFailureStreak:
+			if r.Window <= 0 {
+				r.Window = duration(24 * time.Hour)
+			}
+		case kindBacklogAge, kindDisabled:
+		case kindPromQL:
+			if r.Expr == "" {
+				return nil, fmt.Errorf("alert rule %s: promql rules need an expr", r.Name)
+			}
+		default:
+			return nil, fmt.Errorf("alert rule %s: unknown kind %q (want %s, %s, %s, %s or %s)", r.Name, r.Kind, kindDLQRate, kindBacklogAge, kindFailureStreak, kindDisabled, kindPromQL)
+		}
+	}
+	return parsed.Rules, nil
+}
+
+// needsDB reports whether any of rules reads Postgres
+func needsDB(rules []*rule) bool {
+	for _, r := range rules {
+		if r.Kind != kindPromQL {
+			return true
+		}
+	}
+	return false
+}
+
+// sample is one value a rule measured, for the subject its labels name
+type sample struct {
+	Labels map[string]string
+	Value  float64
+}
+
+// sources are what rules read from; either may be nil when no rule needs it
+type sources struct {
+	pool db.Pool
+	prom *promClient
+}
+
+// eval measures r. Samples at or below the threshold may be left out.
+func (r *rule) eval(ctx context.Context, src sources) ([]sample, error) {
+	if r.Kind == kindPromQL {
+		if src.prom == nil {
+			return nil, fmt.Errorf("promql rules need ALERTER_PROMETHEUS_URL")
+		}
+		return src.prom.query(ctx, r.Expr)
+	}
+	if src.pool == nil {
+		return nil, fmt.Errorf("no database to evaluate %s rules against", r.Kind)
+	}
+	window := time.Duration(r.Window).Seconds()
+	switch r.Kind {
+	case kindDLQRate:
+		var n int64
+		err := src.pool.QueryRow(ctx, `
+			SELECT count(*) FROM harborhook.dlq
+			WHERE created_at >= now() - make_interval(secs => $1)`, window).Scan(&n)
+		if err != nil {
+			return nil, err
+		}
+		return []sample{{Labels: map[string]string{}, Value: float64(n) / (window / 60)}}, nil
+	case kindBacklogAge:
+		return querySamples(ctx, src.pool, []string{"tenant_id"}, `
+			SELECT ep.tenant_id,
+			       EXTRACT(EPOCH FROM now() - min(COALESCE(d.enqueued_at, d.created_at)))::float8 AS age
+			FROM harborhook.deliveries d
+			JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+			WHERE d.status IN ('queued', 'inflight', 'failed')
+			GROUP BY ep.tenant_id
+			HAVING EXTRACT(EPOCH FROM now() - min(COALESCE(d.enqueued_at, d.created_at))) > $1`, r.Threshold)
+	case kindFailureStreak:
+		// Failed attempts after the endpoint's last delivered one, both within the window
+		return querySamples(ctx, src.pool, []string{"tenant_id", "endpoint_id"}, `
+			WITH last_ok AS (
+				SELECT d.endpoint_id, max(a.finished_at) AS at
+				FROM harborhook.delivery_attempts a
+				JOIN harborhook.deliveries d ON d.id = a.delivery_id
+				WHERE a.outcome = 'delivered' AND a.finished_at >= now() - make_interval(secs => $1)
+				GROUP BY d.endpoint_id
+			)
+			SELECT ep.tenant_id, ep.id::text, count(*)::float8
+			FROM harborhook.delivery_attempts a
+			JOIN harborhook.deliveries d ON d.id = a.delivery_id
+			JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+			LEFT JOIN last_ok l ON l.endpoint_id = d.endpoint_id
+			WHERE a.outcome = 'failed' AND a.finished_at >= now() - make_interval(secs => $1)
+			  AND (l.at IS NULL OR a.finished_at > l.at)
+			GROUP BY ep.tenant_id, ep.id
+			HAVING count(*) > $2`, window, r.Threshold)
+	case kindDisabled:
+		return querySamples(ctx, src.pool, []string{"tenant_id", "endpoint_id", "reason"}, `
+			SELECT tenant_id, id::text, COALESCE(disabled_reason, ''),
+			       EXTRACT(EPOCH FROM now() - disabled_at)::float8
+			FROM harborhook.endpoints
+			WHERE disabled_at IS NOT NULL`)
+	}
+	return nil, fmt.Errorf("unknown kind %q", r.Kind)
+}
+
+// querySamples runs a query returning the label columns followed by the value
+func querySamples(ctx context.Context, pool db.Pool, labels []string, sql string, args ...any) ([]sample, error) {
+	rows, err := pool.Query(ctx, sql, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []sample
+	for rows.Next() {
+		values := make([]string, len(labels))
+		dest := make([]any, 0, len(labels)+1)
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		var s sample
+		dest = append(dest, &s.Value)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		s.Labels = make(map[string]string, len(labels))
+		for i, l := range labels {
+			s.Labels[l] = values[i]
+		}
+		out = append(out, s)
+	}
+	return out, rows.Err()
+}
+
+// promClient runs instant queries against Prometheus's HTTP API
+type promClient struct {
+	base   string
+	client *http.Client
+}
+
+func newPromClient(base string) *promClient {
+	return &promClient{base: strings.TrimSuffix(base, "/"), client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// query returns a sample per series of expr's instant vector
+func (p *promClient) query(ctx context.Context, expr string) ([]sample, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.base+"/api/v1/query?query="+url.QueryEscape(expr), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("query prometheus: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string `json:"resultType"`
+			Result     []struct {
+				Metric map[string]string `json:"metric"`
+				Value  [2]any            `json:"value"`
+			} `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode prometheus response (HTTP %d): %w", resp.StatusCode, err)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", body.Error)
+	}
+	if body.Data.ResultType != "vector" {
+		return nil, fmt.Errorf("prometheus query returned a %s, want a vector", body.Data.ResultType)
+	}
+
+	out := make([]sample, 0, len(body.Data.Result))
+	for _, r := range body.Data.Result {
+		s, _ := r.Value[1].(string)
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return nil, fmt.Errorf("prometheus returned value %v: %w", r.Value[1], err)
+		}
+		delete(r.Metric, "__name__")
+		out = append(out, sample{Labels: r.Metric, Value: v})
+	}
+	return out, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+)
+
+func TestLoadRules(t *testing.T) {
+	defaults, err := loadRules("")
+	if err != nil {
+		t.Fatalf("loadRules(\"\") error = %v", err)
+	}
+	if len(defaults) != 4 || !needsDB(defaults) {
+		t.Errorf("default rules = %+v, want the four database rules", defaults)
+	}
+
+	file := filepath.Join(t.TempDir(), "rules.json")
+	if err := os.WriteFile(file, []byte(`{"rules":[{"name":"errors","kind":"promql","expr":"up == 0"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := loadRules(file)
+	if err != nil || len(fromFile) != 1 || fromFile[0].Severity != "warning" || needsDB(fromFile) {
+		t.Errorf("loadRules(file) = %+v, %v, want one promql rule defaulting to warning", fromFile, err)
+	}
+
+	inline, err := loadRules(`{"rules":[{"name":"dlq","kind":"dlq_rate","threshold":1,"for":"2m"}]}`)
+	if err != nil || time.Duration(inline[0].Window) != 5*time.Minute || time.Duration(inline[0].For) != 2*time.Minute {
+		t.Errorf("loadRules(inline) = %+v, %v, want a 5m window and 2m for", inline, err)
+	}
+
+	for _, bad := range []string{
+		`{"rules":[{"kind":"dlq_rate"}]}`,
+		`{"rules":[{"name":"a","kind":"dlq_rate"},{"name":"a","kind":"backlog_age"}]}`,
+		`{"rules":[{"name":"a","kind":"cpu"}]}`,
+		`{"rules":[{"name":"a","kind":"promql"}]}`,
+		`{"rules":[{"name":"a","kind":"dlq_rate","window":300}]}`,
+		`{"rules":`,
+	} {
+		if _, err := loadRules(bad); err == nil {
+			t.Errorf("loadRules(%s) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestRuleEval_Database(t *testing.T) {
+	pool := &dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			return dbfake.Row{Values: []any{int64(30)}}
+		},
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			if strings.Contains(sql, "delivery_attempts") {
+				return dbfake.NewRows([]any{"tenant_a", "ep_1", 25.0}), nil
+			}
+			if strings.Contains(sql, "disabled_at IS NOT NULL") {
+				return dbfake.NewRows([]any{"tenant_a", "ep_2", "dead_streak", 90.0}), nil
+			}
+			return dbfake.NewRows([]any{"tenant_a", 600.0}, []any{"tenant_b", 420.0}), nil
+		},
+	}
+	src := sources{pool: pool}
+
+	dlq := &rule{Name: "dlq", Kind: kindDLQRate, Window: duration(5 * time.Minute)}
+	if got, err := dlq.eval(context.Background(), src); err != nil || len(got) != 1 || got[0].Value != 6 {
+		t.Errorf("dlq_rate eval = %+v, %v, want 30 entries over 5m as 6/min", got, err)
+	}
+
+	age := &rule{Name: "age", Kind: kindBacklogAge, Threshold: 300}
+	got, err := age.eval(context.Background(), src)
+	if err != nil || len(got) != 2 || got[1].Labels["tenant_id"] != "tenant_b" || got[1].Value != 420 {
+		t.Errorf("backlog_age eval = %+v, %v, want a sample per tenant", got, err)
+	}
+
+	streak := &rule{Name: "streak", Kind: kindFailureStreak, Threshold: 20, Window: duration(24 * time.Hour)}
+	got, err = streak.eval(context.Background(), src)
+	if err != nil || len(got) != 1 || got[0].Labels["endpoint_id"] != "ep_1" || got[0].Value != 25 {
+		t.Errorf("failure_streak eval = %+v, %v, want ep_1 at 25", got, err)
+	}
+
+	disabled := &rule{Name: "disabled", Kind: kindDisabled}
+	got, err = disabled.eval(context.Background(), src)
+	if err != nil || len(got) != 1 || got[0].Labels["endpoint_id"] != "ep_2" || got[0].Labels["reason"] != "dead_streak" {
+		t.Errorf("endpoint_disabled eval = %+v, %v, want ep_2 disabled for its dead streak", got, err)
+	}
+
+	if _, err := dlq.eval(context.Background(), sources{}); err == nil {
+		t.Error("dlq_rate eval without a database succeeded, want an error")
+	}
+}
+
+func TestRuleEval_PromQL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" || r.URL.Query().Get("query") != `up{job="worker"} == 0` {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"status":"error","error":"bad query"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[
+			{"metric":{"__name__":"up","instance":"worker-1"},"value":[1700000000,"0"]}]}}`))
+	}))
+	defer srv.Close()
+	src := sources{prom: newPromClient(srv.URL + "/")}
+
+	r := &rule{Name: "down", Kind: kindPromQL, Expr: `up{job="worker"} == 0`}
+	got, err := r.eval(context.Background(), src)
+	if err != nil || len(got) != 1 || got[0].Labels["instance"] != "worker-1" || got[0].Labels["__name__"] != "" {
+		t.Errorf("promql eval = %+v, %v, want worker-1 without __name__", got, err)
+	}
+
+	bad := &rule{Name: "bad", Kind: kindPromQL, Expr: "nope("}
+	if _, err := bad.eval(context.Background(), src); err == nil || !strings.Contains(err.Error(), "bad query") {
+		t.Errorf("promql eval of a bad query error = %v, want Prometheus's error", err)
+	}
+	if _, err := r.eval(context.Background(), sources{}); err == nil {
+		t.Error("promql eval without ALERTER_PROMETHEUS_URL succeeded, want an error")
+	}
+}
@@ -0,0 +1,341 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/logging"
+)
+
+// chaosConfig is the CHAOS_CONFIG document: failure profiles keyed by request path, with "*"
+// matching paths that have none of their own.
+//
+//	{"seed": 42, "paths": {"/hook/flaky": {
+//	    "status": {"200": 0.9, "500": 0.08, "429": 0.02},
+//	    "latency": {"p50": "50ms", "p99": "2s"},
+//	    "reset": 0.01,
+//	    "dribble": {"probability": 0.05, "bytes": 256, "chunk": 16, "interval": "200ms"},
+//	    "outages": [{"every": "10m", "for": "30s", "status": 503}]}}}
+type chaosConfig struct {
+	Seed  uint64                   `json:"seed"` // 0 seeds from the clock
+	Paths map[string]*chaosProfile `json:"paths"`
+
+	start time.Time
+	mu    sync.Mutex
+	rng   *rand.Rand
+}
+
+// chaosProfile is how one path misbehaves. Each request rolls for an outage, a reset, a
+// status and a latency in that order; a profile without latency uses no delay at all.
+type chaosProfile struct {
+	Status  map[string]float64 `json:"status"`  // status code -> relative weight; empty answers 200
+	Latency map[string]string  `json:"latency"` // percentile ("p50", "p99.9") -> duration
+	Reset   float64            `json:"reset"`   // probability the connection is reset without a response
+	Dribble *chaosDribble      `json:"dribble"`
+	Outages []chaosOutage      `json:"outages"`
+
+	statuses  []int
+	weights   []float64 // cumulative
+	latencies []percentile
+}
+
+// chaosDribble sends some responses' bodies a chunk at a time, to exercise read timeouts
+type chaosDribble struct {
+	Probability float64       `json:"probability"`
+	Bytes       int           `json:"bytes"`    // body size, default 256
+	Chunk       int           `json:"chunk"`    // bytes per write, default 16
+	Interval    chaosDuration `json:"interval"` // pause between writes, default 100ms
+}
+
+// chaosOutage is a window in which every request fails: between Start and End, or for the
+// first For of every Every since the receiver started
+type chaosOutage struct {
+	Start  time.Time     `json:"start"`
+	End    time.Time     `json:"end"`
+	Every  chaosDuration `json:"every"`
+	For    chaosDuration `json:"for"`
+	Status int           `json:"status"` // default 503
+	Reset  bool          `json:"reset"`  // reset connections instead of answering
+}
+
+type percentile struct {
+	q float64
+	d time.Duration
+}
+
+// chaosDuration is a time.Duration written as a string such as "250ms" in CHAOS_CONFIG
+type chaosDuration time.Duration
+
+func (d *chaosDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = chaosDuration(v)
+	return nil
+}
+
+// loadChaos parses CHAOS_CONFIG: a JSON document, or the path of a file holding one. Empty
+// returns nil, which leaves FAIL_FIRST_N and RESPONSE_DELAY_MS in charge.
+func loadChaos(raw string, now time.Time) (*chaosConfig, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	doc := []byte(raw)
+	if !strings.HasPrefix(raw, "{") {
+		b, err := os.ReadFile(raw)
+		if err != nil {
+			return nil, fmt.Errorf("read chaos config: %w", err)
+		}
+		doc = b
+	}
+	var c chaosConfig
+	if err := json.Unmarshal(doc, &c); err != nil {
+		return nil, fmt.Errorf("parse chaos config: %w", err)
+	}
+	for path, p := range c.Paths {
+		if p == nil {
+			return nil, fmt.Errorf("chaos profile %s is empty", path)
+		}
+		if err := p.compile(); err != nil {
+			return nil, fmt.Errorf("chaos profile %s: %w", path, err)
+		}
+	}
+	seed := c.Seed
+	if seed == 0 {
+		seed = uint64(now.UnixNano())
+	}
+	c.start = now
+	c.rng = rand.New(rand.NewPCG(seed, seed))
+	return &c, nil
+}
+
+func (p *chaosProfile) compile() error {
+	codes := make([]int, 0, len(p.Status))
+	for k, w := range p.Status {
+		code, err := strconv.Atoi(k)
+		if err != nil || code < 100 || code > 599 {
+			return fmt.Errorf("invalid status code %q", k)
+		}
+		if w < 0 {
+			return fmt.Errorf("status %d has a negative weight", code)
+		}
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	var total float64
+	for _, code := range codes {
+		total += p.Status[strconv.Itoa(code)]
+		p.statuses = append(p.statuses, code)
+		p.weights = append(p.weights, total)
+	}
+	if len(codes) > 0 && total == 0 {
+		return fmt.Errorf("status weights sum to zero")
+	}
+
+	for k, v := range p.Latency {
+		q, err := strconv.ParseFloat(strings.TrimPrefix(k, "p"), 64)
+		if !strings.HasPrefix(k, "p") || err != nil || q <= 0 || q > 100 {
+			return fmt.Errorf("invalid latency percentile %q", k)
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid latency %q for %s", v, k)
+		}
+		p.latencies = append(p.latencies, percentile{q: q / 100, d: d})
+	}
+	sort.Slice(p.latencies, func(i, j int) bool { return p.latencies[i].q < p.latencies[j].q })
+	for i := 1; i < len(p.latencies); i++ {
+		if p.latencies[i].d < p.latencies[i-1].d {
+			return fmt.Errorf("latency percentiles must not decrease")
+		}
+	}
+
+	if p.Reset < 0 || p.Reset > 1 {
+		return fmt.Errorf("reset probability must be between 0 and 1")
+	}
+	if d := p.Dribble; d != nil {
+		if d.Probability < 0 || d.Probability > 1 {
+			return fmt.Errorf("dribble probability must be between 0 and 1")
+		}
+		if d.Bytes <= 0 {
+			d.Bytes = 256
+		}
+		if d.Chunk <= 0 {
+			d.Chunk = 16
+		}
+		if d.Interval <= 0 {
+			d.Interval = chaosDuration(100 * time.Millisecond)
+		}
+	}
+	for i, o := range p.Outages {
+		absolute := !o.Start.IsZero() || !o.End.IsZero()
+		switch {
+		case absolute && (o.Start.IsZero() || o.End.IsZero() || !o.End.After(o.Start)):
+			return fmt.Errorf("outage %d needs a start before its end", i)
+		case absolute && o.Every != 0:
+			return fmt.Errorf("outage %d sets both start/end and every", i)
+		case !absolute && (o.Every <= 0 || o.For <= 0 || o.For > o.Every):
+			return fmt.Errorf("outage %d needs start/end, or every with a shorter for", i)
+		}
+		if o.Status == 0 {
+			p.Outages[i].Status = http.StatusServiceUnavailable
+		}
+	}
+	return nil
+}
+
+// profile returns the profile for a request path, or nil when none applies
+func (c *chaosConfig) profile(path string) *chaosProfile {
+	if p, ok := c.Paths[path]; ok {
+		return p
+	}
+	return c.Paths["*"]
+}
+
+// chaosOutcome is what a profile rolled for one request
+type chaosOutcome struct {
+	status  int
+	delay   time.Duration
+	reset   bool
+	dribble bool
+	outage  bool
+}
+
+// roll decides one request's fate. The generator is shared, so a fixed seed reproduces a
+// sequence of outcomes only for requests that arrive in the same order.
+func (c *chaosConfig) roll(p *chaosProfile, now time.Time) chaosOutcome {
+	for _, o := range p.Outages {
+		if o.active(now, c.start) {
+			return chaosOutcome{status: o.Status, reset: o.Reset, outage: true}
+		}
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := chaosOutcome{status: http.StatusOK}
+	if p.Reset > 0 && c.rng.Float64() < p.Reset {
+		out.reset = true
+		return out
+	}
+	if n := len(p.weights); n > 0 {
+		r := c.rng.Float64() * p.weights[n-1]
+		out.status = p.statuses[sort.Search(n, func(i int) bool { return p.weights[i] > r })]
+	}
+	out.delay = p.latency(c.rng.Float64())
+	if p.Dribble != nil && p.Dribble.Probability > 0 {
+		out.dribble = c.rng.Float64() < p.Dribble.Probability
+	}
+	return out
+}
+
+func (o chaosOutage) active(now, start time.Time) bool {
+	if !o.Start.IsZero() {
+		return !now.Before(o.Start) && now.Before(o.End)
+	}
+	return now.Sub(start)%time.Duration(o.Every) < time.Duration(o.For)
+}
+
+// latency maps a uniform sample to a duration by interpolating between the configured
+// percentiles, from zero below the lowest one; past the highest it stays there
+func (p *chaosProfile) latency(u float64) time.Duration {
+	prev := percentile{}
+	for _, pt := range p.latencies {
+		if u <= pt.q {
+			frac := (u - prev.q) / (pt.q - prev.q)
+			return prev.d + time.Duration(math.Round(frac*float64(pt.d-prev.d)))
+		}
+		prev = pt
+	}
+	return prev.d
+}
+
+// resetConnection drops the client's connection without a response. On TCP it sets a zero
+// linger so the close sends RST; connections that can't be hijacked, such as HTTP/2, are
+// aborted instead.
+func resetConnection(w http.ResponseWriter) {
+	conn, _, err := http.NewResponseController(w).Hijack()
+	if err != nil {
+		panic(http.ErrAbortHandler)
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+}
+
+// dribble writes the status and then a body of d.Bytes a chunk at a time, flushing after
+// each, until the body is done or the client goes away
+func dribble(w http.ResponseWriter, r *http.Request, status int, d *chaosDribble) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Content-Length", strconv.Itoa(d.Bytes))
+	w.WriteHeader(status)
+	rc := http.NewResponseController(w)
+	chunk := []byte(strings.Repeat(".", d.Chunk))
+	for sent := 0; sent < d.Bytes; sent += len(chunk) {
+		if rest := d.Bytes - sent; rest < len(chunk) {
+			chunk = chunk[:rest]
+		}
+		if _, err := w.Write(chunk); err != nil {
+			return
+		}
+		_ = rc.Flush()
+		if sent+len(chunk) >= d.Bytes {
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(time.Duration(d.Interval)):
+		}
+	}
+}
+
+// serveChaos answers a request the way its profile rolled and logs what it did
+func serveChaos(w http.ResponseWriter, r *http.Request, out chaosOutcome, p *chaosProfile, body []byte) {
+	what := "CHAOS"
+	if out.outage {
+		what = "CHAOS OUTAGE"
+	}
+	if traceID := r.Header.Get("X-Trace-Id"); traceID != "" {
+		what += " trace_id=" + traceID
+	}
+	if out.reset {
+		log.Printf("fake-receiver %s %s reset body=%q", what, r.URL.Path, truncate(logging.RedactText(string(body)), 160))
+		resetConnection(w)
+		return
+	}
+
+	if out.delay > 0 {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(out.delay):
+		}
+	}
+	log.Printf("fake-receiver %s %s status=%d delay=%s dribble=%t body=%q", what, r.URL.Path, out.status, out.delay, out.dribble, truncate(logging.RedactText(string(body)), 160))
+	switch {
+	case out.dribble:
+		dribble(w, r, out.status, p.Dribble)
+	case out.status >= 200 && out.status < 300:
+		w.WriteHeader(out.status)
+		_, _ = w.Write([]byte(`ok`))
+	default:
+		http.Error(w, fmt.Sprintf("chaos: injected %d", out.status), out.status)
+	}
+}
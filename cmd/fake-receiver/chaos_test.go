@@ -0,0 +1,152 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+var chaosStart = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func mustLoadChaos(t *testing.T, doc string) *chaosConfig {
+	t.Helper()
+	c, err := loadChaos(doc, chaosStart)
+	if err != nil {
+		t.Fatalf("loadChaos() unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestLoadChaos_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{"bad json", `{"paths":`, "parse chaos config"},
+		{"missing file", "/nonexistent/chaos.json", "read chaos config"},
+		{"bad status", `{"paths":{"*":{"status":{"600":1}}}}`, `invalid status code "600"`},
+		{"zero weights", `{"paths":{"*":{"status":{"500":0}}}}`, "sum to zero"},
+		{"bad percentile", `{"paths":{"*":{"latency":{"50":"1s"}}}}`, `invalid latency percentile "50"`},
+		{"decreasing latency", `{"paths":{"*":{"latency":{"p50":"2s","p99":"1s"}}}}`, "must not decrease"},
+		{"reset probability", `{"paths":{"*":{"reset":1.5}}}`, "reset probability"},
+		{"outage without window", `{"paths":{"*":{"outages":[{"status":503}]}}}`, "outage 0 needs"},
+		{"outage longer than period", `{"paths":{"*":{"outages":[{"every":"1m","for":"2m"}]}}}`, "outage 0 needs"},
+		{"outage ends before start", `{"paths":{"*":{"outages":[{"start":"2026-03-01T13:00:00Z","end":"2026-03-01T12:00:00Z"}]}}}`, "start before its end"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadChaos(tt.doc, chaosStart)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadChaos() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if c, err := loadChaos("", chaosStart); c != nil || err != nil {
+		t.Errorf("loadChaos(\"\") = %v, %v, want no profiles", c, err)
+	}
+}
+
+func TestChaos_Latency(t *testing.T) {
+	c := mustLoadChaos(t, `{"paths":{"*":{"latency":{"p50":"50ms","p99":"2s"}}}}`)
+	p := c.profile("/hook")
+	tests := []struct {
+		u    float64
+		want time.Duration
+	}{
+		{0, 0},
+		{0.25, 25 * time.Millisecond},
+		{0.5, 50 * time.Millisecond},
+		{0.99, 2 * time.Second},
+		{0.999, 2 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.latency(tt.u); got != tt.want {
+			t.Errorf("latency(%v) = %s, want %s", tt.u, got, tt.want)
+		}
+	}
+}
+
+func TestChaos_StatusDistribution(t *testing.T) {
+	c := mustLoadChaos(t, `{"seed":7,"paths":{"/hook/flaky":{"status":{"200":0.8,"500":0.2,"429":0}}}}`)
+	if c.profile("/hook") != nil {
+		t.Error("profile(/hook) matched without a profile of its own or a * profile")
+	}
+	p := c.profile("/hook/flaky")
+	counts := map[int]int{}
+	for i := 0; i < 10000; i++ {
+		counts[c.roll(p, chaosStart).status]++
+	}
+	if counts[429] != 0 {
+		t.Errorf("429 rolled %d times with zero weight", counts[429])
+	}
+	if counts[500] < 1800 || counts[500] > 2200 {
+		t.Errorf("500 rolled %d times in 10000, want about 2000", counts[500])
+	}
+}
+
+func TestChaos_Outages(t *testing.T) {
+	c := mustLoadChaos(t, `{"paths":{"*":{"outages":[
+		{"every":"10m","for":"30s"},
+		{"start":"2026-03-01T13:00:00Z","end":"2026-03-01T13:05:00Z","status":502,"reset":true}]}}}`)
+	p := c.profile("/hook")
+	tests := []struct {
+		at         time.Duration
+		wantStatus int
+		wantReset  bool
+	}{
+		{10 * time.Second, http.StatusServiceUnavailable, false},
+		{time.Minute, http.StatusOK, false},
+		{10*time.Minute + 29*time.Second, http.StatusServiceUnavailable, false},
+		{61 * time.Minute, 502, true},
+		{65 * time.Minute, http.StatusOK, false},
+	}
+	for _, tt := range tests {
+		out := c.roll(p, chaosStart.Add(tt.at))
+		if out.status != tt.wantStatus || out.reset != tt.wantReset {
+			t.Errorf("roll at +%s = %d (reset %t), want %d (reset %t)", tt.at, out.status, out.reset, tt.wantStatus, tt.wantReset)
+		}
+	}
+}
+
+func TestHandleHook_Chaos(t *testing.T) {
+	cfg := config.FromEnv()
+	cfg.FakeReceiver = config.FakeReceiver{}
+	defer func() { chaos = nil }()
+	chaos = mustLoadChaos(t, `{"paths":{
+		"/hook/down":{"status":{"503":1}},
+		"/hook/slow":{"dribble":{"probability":1,"bytes":20,"chunk":8,"interval":"1ms"}},
+		"/hook/reset":{"reset":1}}}`)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { handleHook(w, r, cfg) }))
+	defer srv.Close()
+
+	post := func(path string) (*http.Response, string, error) {
+		resp, err := http.Post(srv.URL+path, "application/json", strings.NewReader(`{"a":1}`))
+		if err != nil {
+			return nil, "", err
+		}
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		return resp, string(b), err
+	}
+
+	if resp, _, err := post("/hook"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("/hook without a profile = %v, %v, want 200", resp, err)
+	}
+	if resp, body, err := post("/hook/down"); err != nil || resp.StatusCode != http.StatusServiceUnavailable || !strings.Contains(body, "chaos: injected 503") {
+		t.Errorf("/hook/down = %v %q, %v, want an injected 503", resp, body, err)
+	}
+	if resp, body, err := post("/hook/slow"); err != nil || resp.StatusCode != http.StatusOK || len(body) != 20 {
+		t.Errorf("/hook/slow = %v %q, %v, want a dribbled 20-byte body", resp, body, err)
+	}
+	if _, _, err := post("/hook/reset"); err == nil {
+		t.Error("/hook/reset answered, want the connection reset")
+	}
+}
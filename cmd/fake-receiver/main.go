@@ -1,36 +1,76 @@
 package main
 
 import (
-	"crypto/hmac"
-	"crypto/sha256"
-	"crypto/subtle"
-	"encoding/hex"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
-	"strconv"
 	"strings"
 	"sync/atomic"
 	"time"
 
 	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/logging"
+	"github.com/austindbirch/harbor_hook/internal/version"
+	"github.com/austindbirch/harbor_hook/pkg/webhookverify"
 )
 
 var (
 	reqCount = atomic.Int64{}
+	// signingKeys holds the public keys ed25519 signatures verify with; nil without SIGNING_KEYS_URL
+	signingKeys webhookverify.KeySource
+	// chaos holds the per-path failure profiles from CHAOS_CONFIG; nil without it
+	chaos *chaosConfig
+	// received records the requests to /hook for /received; nil records nothing
+	received *recorder
+	// personas answers the persona paths such as /flaky; nil leaves them to /hook's behavior
+	personas *personaSet
 )
 
 func main() {
+	defaults := personaFlags(flag.CommandLine)
+	flag.Parse()
+	personas = newPersonaSet(*defaults)
+
 	cfg := config.FromEnv()
+	// Bodies are logged, so the keys redacted from them follow the services' configuration
+	logging.SetRedactKeys(cfg.Log.RedactKeys)
 	listenPort := cfg.FakeReceiver.Port
 	if !strings.HasPrefix(listenPort, ":") {
 		listenPort = ":" + listenPort
 	}
 
+	if cfg.FakeReceiver.SigningKeysURL != "" {
+		signingKeys = webhookverify.NewKeySet(cfg.FakeReceiver.SigningKeysURL, &http.Client{Timeout: 5 * time.Second})
+	}
+	c, err := loadChaos(cfg.FakeReceiver.ChaosConfig, time.Now())
+	if err != nil {
+		log.Fatalf("fake-receiver: %v", err)
+	}
+	chaos = c
+	received, err = newRecorder(cfg.FakeReceiver.RecordLimit, cfg.FakeReceiver.RecordFile)
+	if err != nil {
+		log.Fatalf("fake-receiver: %v", err)
+	}
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) { _, _ = w.Write([]byte(`{"ok":true}`)) })
+	mux.HandleFunc("/version", version.HTTPHandler())
 	mux.HandleFunc("/hook", handleHookFactory(cfg))
+	// Paths under /hook/ behave like /hook, so endpoints can pick a chaos profile by URL
+	mux.HandleFunc("/hook/", handleHookFactory(cfg))
+	// Personas verify, answer challenges and record like /hook, then behave their own way
+	for _, path := range personaPaths {
+		mux.HandleFunc(path, handleHookFactory(cfg))
+	}
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) { handleEcho(w, r, cfg) })
+	mux.HandleFunc("/received", handleReceived(received, cfg.NSQ.DeliveryHeader))
+	mux.HandleFunc("/reset", handleReset(received))
 
 	server := &http.Server{
 		Addr:         listenPort,
@@ -50,36 +90,74 @@ func handleHookFactory(cfg config.Config) http.HandlerFunc {
 }
 
 func handleHook(w http.ResponseWriter, r *http.Request, cfg config.Config) {
-	n := reqCount.Add(1)
-	b, _ := io.ReadAll(r.Body)
+	b, _ := readBody(r)
 	defer r.Body.Close()
 
+	var sig echoSignature
 	if cfg.FakeReceiver.EndpointSecret != "" {
 		leeway := time.Duration(cfg.FakeReceiver.SigningLeewaySeconds) * time.Second
-		if ok, msg := verifySignature(cfg.FakeReceiver.EndpointSecret, b, r.Header.Get(cfg.NSQ.TimestampHeader), r.Header.Get(cfg.NSQ.SignatureHeader), leeway); !ok {
-			traceID := r.Header.Get("X-Trace-Id")
-			if traceID != "" {
-				log.Printf("fake-receiver failed to verify signature: %s trace_id=%s", msg, traceID)
-			} else {
-				log.Printf("fake-receiver failed to verify signature: %s", msg)
+		ok, msg := verifySignature(cfg.FakeReceiver.EndpointSecret, r.Method, r.URL.RequestURI(), b, r.Header.Get(cfg.NSQ.TimestampHeader), r.Header.Get(cfg.NSQ.SignatureHeader), leeway)
+		sig = echoSignature{Checked: true, Valid: ok, Error: msg}
+	}
+
+	// Record the request with whatever the handler ends up answering, even a reset
+	if received != nil {
+		sw := &statusWriter{ResponseWriter: w}
+		w = sw
+		entry := receivedRequest{ReceivedAt: time.Now().UTC(), Method: r.Method, Path: r.URL.Path, Headers: r.Header.Clone(), Body: string(b), Signature: sig}
+		defer func() {
+			entry.Status = sw.status
+			if err := received.add(entry); err != nil {
+				log.Printf("fake-receiver failed to record request: %v", err)
 			}
-			http.Error(w, "invalid signature: "+msg, http.StatusUnauthorized)
-			return
+		}()
+	}
+
+	if sig.Checked && !sig.Valid {
+		traceID := r.Header.Get("X-Trace-Id")
+		if traceID != "" {
+			log.Printf("fake-receiver failed to verify signature: %s trace_id=%s", sig.Error, traceID)
+		} else {
+			log.Printf("fake-receiver failed to verify signature: %s", sig.Error)
 		}
+		http.Error(w, "invalid signature: "+sig.Error, http.StatusUnauthorized)
+		return
+	}
+
+	// Answer verification challenges so new endpoints get deliveries; they don't count as requests
+	if token, ok := delivery.ParseChallenge(b); ok {
+		log.Printf("fake-receiver CHALLENGE %s", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"challenge": token})
+		return
+	}
+
+	if personas != nil && personas.serve(w, r) {
+		log.Printf("fake-receiver PERSONA %s?%s headers=%d body=%q", r.URL.Path, r.URL.RawQuery, len(r.Header), truncate(logging.RedactText(string(b)), 160))
+		return
 	}
 
 	// Simulate flakiness: first N request -> 500
+	n := reqCount.Add(1)
 	if n <= int64(cfg.FakeReceiver.FailFirstN) {
 		traceID := r.Header.Get("X-Trace-Id")
 		if traceID != "" {
-			log.Printf("FAILING (%d/%d) %s trace_id=%s headers=%d body=%s", n, cfg.FakeReceiver.FailFirstN, r.URL.Path, traceID, len(r.Header), truncate(string(b), 160))
+			log.Printf("FAILING (%d/%d) %s trace_id=%s headers=%d body=%s", n, cfg.FakeReceiver.FailFirstN, r.URL.Path, traceID, len(r.Header), truncate(logging.RedactText(string(b)), 160))
 		} else {
-			log.Printf("FAILING (%d/%d) %s headers=%d body=%s", n, cfg.FakeReceiver.FailFirstN, r.URL.Path, len(r.Header), truncate(string(b), 160))
+			log.Printf("FAILING (%d/%d) %s headers=%d body=%s", n, cfg.FakeReceiver.FailFirstN, r.URL.Path, len(r.Header), truncate(logging.RedactText(string(b)), 160))
 		}
 		http.Error(w, "temporary failure", http.StatusInternalServerError)
 		return
 	}
 
+	// A chaos profile for the path replaces the fixed delay and always-200 answer below
+	if chaos != nil {
+		if p := chaos.profile(r.URL.Path); p != nil {
+			serveChaos(w, r, chaos.roll(p, time.Now()), p, b)
+			return
+		}
+	}
+
 	// Simulate processing delay if configured
 	responseDelay := time.Duration(cfg.FakeReceiver.ResponseDelayMS) * time.Millisecond
 	if responseDelay > 0 {
@@ -89,56 +167,74 @@ func handleHook(w http.ResponseWriter, r *http.Request, cfg config.Config) {
 	// Extract trace ID for end-to-end traceability
 	traceID := r.Header.Get("X-Trace-Id")
 	if traceID != "" {
-		log.Printf("fake-receiver OK %s trace_id=%s headers=%d body=%q", r.URL.Path, traceID, len(r.Header), truncate(string(b), 160))
+		log.Printf("fake-receiver OK %s trace_id=%s headers=%d body=%q", r.URL.Path, traceID, len(r.Header), truncate(logging.RedactText(string(b)), 160))
 	} else {
-		log.Printf("fake-receiver OK %s headers=%d body=%q", r.URL.Path, len(r.Header), truncate(string(b), 160))
+		log.Printf("fake-receiver OK %s headers=%d body=%q", r.URL.Path, len(r.Header), truncate(logging.RedactText(string(b)), 160))
 	}
 	w.WriteHeader(http.StatusOK)
 	_, _ = w.Write([]byte(`ok`))
 }
 
-func verifySignature(secret string, body []byte, ts, sigHeaderVal string, leeway time.Duration) (bool, string) {
-	if ts == "" || sigHeaderVal == "" {
-		return false, "missing headers"
-	}
-	unix, err := strconv.ParseInt(ts, 10, 64)
-	if err != nil {
-		return false, "invalid timestamp"
-	}
-	// reject if timestamp is too old/new
-	if abs64(time.Now().Unix()-unix) > int64(leeway.Seconds()) {
-		return false, "timestamp outside leeway"
-	}
+// echoResponse is what /echo returns: the request as received plus the signature verdict
+type echoResponse struct {
+	Method    string              `json:"method"`
+	Path      string              `json:"path"`
+	Headers   map[string][]string `json:"headers"`
+	Body      string              `json:"body"`
+	Signature echoSignature       `json:"signature"`
+}
 
-	// expect "sha256=<hex>"
-	parts := strings.SplitN(sigHeaderVal, "=", 2)
-	if len(parts) != 2 || parts[0] != "sha256" {
-		return false, "bad signature scheme"
-	}
-	gotSig, err := hex.DecodeString(parts[1])
-	if err != nil {
-		return false, "signature not hex"
+type echoSignature struct {
+	Checked bool   `json:"checked"` // false when no ENDPOINT_SECRET is configured
+	Valid   bool   `json:"valid"`
+	Error   string `json:"error,omitempty"`
+}
+
+// handleEcho returns the received headers and body with the signature verdict, so curl tests
+// and traffic generation can see exactly what the worker sent. It always answers 200 and
+// skips failure injection and the response delay.
+func handleEcho(w http.ResponseWriter, r *http.Request, cfg config.Config) {
+	b, _ := readBody(r)
+	defer r.Body.Close()
+
+	resp := echoResponse{Method: r.Method, Path: r.URL.Path, Headers: r.Header, Body: string(b)}
+	if cfg.FakeReceiver.EndpointSecret != "" {
+		leeway := time.Duration(cfg.FakeReceiver.SigningLeewaySeconds) * time.Second
+		ok, msg := verifySignature(cfg.FakeReceiver.EndpointSecret, r.Method, r.URL.RequestURI(), b, r.Header.Get(cfg.NSQ.TimestampHeader), r.Header.Get(cfg.NSQ.SignatureHeader), leeway)
+		resp.Signature = echoSignature{Checked: true, Valid: ok, Error: msg}
 	}
+	log.Printf("fake-receiver ECHO %s headers=%d signature_valid=%t body=%q", r.URL.Path, len(r.Header), resp.Signature.Valid, truncate(logging.RedactText(string(b)), 160))
 
-	// Compute HMAC
-	mac := hmac.New(sha256.New, []byte(secret))
-	mac.Write(body)
-	mac.Write([]byte(ts))
-	want := mac.Sum(nil)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
 
-	// Check got against want
-	if len(gotSig) != len(want) || subtle.ConstantTimeCompare(gotSig, want) != 1 {
-		return false, "sig mismatch"
+// readBody returns the request body, decompressed when the worker gzipped it for an endpoint
+// with compression on. Signatures cover the decompressed body.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Header.Get("Content-Encoding") != "gzip" {
+		return io.ReadAll(r.Body)
 	}
-	return true, ""
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
 }
 
-// abs64 returns the absolute value of an int64
-func abs64(x int64) int64 {
-	if x < 0 {
-		return -x
+// verifySignature checks a request's signature header, in any scheme, with the package
+// customers use. target is the request's escaped path and query, which v2 signatures cover
+// along with the method.
+func verifySignature(secret, method, target string, body []byte, ts, sigHeaderVal string, leeway time.Duration) (bool, string) {
+	v := &webhookverify.Verifier{Secrets: []string{secret}, Leeway: leeway, PublicKeys: signingKeys}
+	if err := v.Verify(method, target, body, ts, sigHeaderVal); err != nil {
+		if ks, ok := signingKeys.(*webhookverify.KeySet); ok && errors.Is(err, webhookverify.ErrUnknownKeyID) && ks.Err() != nil {
+			log.Printf("fake-receiver failed to fetch signing keys: %v", ks.Err())
+		}
+		return false, err.Error()
 	}
-	return x
+	return true, ""
 }
 
 // truncate truncates a string to the specified length and adds an ellipsis if truncated
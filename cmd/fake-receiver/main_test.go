@@ -1,9 +1,14 @@
 package main
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
 	"crypto/hmac"
 	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strconv"
@@ -12,6 +17,9 @@ import (
 	"time"
 
 	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/delivery/signvectors"
+	"github.com/austindbirch/harbor_hook/pkg/webhookverify"
 )
 
 func TestVerifySignature(t *testing.T) {
@@ -140,7 +148,7 @@ func TestVerifySignature(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			valid, msg := verifySignature(tt.secret, tt.body, tt.timestamp, tt.signature, tt.leeway)
+			valid, msg := verifySignature(tt.secret, "POST", "/hook", tt.body, tt.timestamp, tt.signature, tt.leeway)
 
 			if valid != tt.expectValid {
 				t.Errorf("verifySignature() valid = %v, want %v", valid, tt.expectValid)
@@ -152,47 +160,82 @@ func TestVerifySignature(t *testing.T) {
 	}
 }
 
-func TestAbs64(t *testing.T) {
+func TestVerifySignature_V2(t *testing.T) {
+	body := []byte(`{"a":1}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	valid := delivery.SignV2("test-secret", "POST", "/hook?x=1", body, ts)
+
 	tests := []struct {
-		name     string
-		input    int64
-		expected int64
+		name      string
+		target    string
+		signature string
+		wantMsg   string
 	}{
-		{
-			name:     "positive number",
-			input:    42,
-			expected: 42,
-		},
-		{
-			name:     "negative number",
-			input:    -42,
-			expected: 42,
-		},
-		{
-			name:     "zero",
-			input:    0,
-			expected: 0,
-		},
-		{
-			name:     "max int64",
-			input:    9223372036854775807,
-			expected: 9223372036854775807,
-		},
-		{
-			name:     "min int64 + 1",
-			input:    -9223372036854775807,
-			expected: 9223372036854775807,
-		},
+		{"valid", "/hook?x=1", valid, ""},
+		{"other path", "/other?x=1", valid, "sig mismatch"},
+		{"unsupported alg", "/hook?x=1", strings.Replace(valid, "alg=HMAC-SHA256", "alg=HMAC-MD5", 1), "unsupported alg"},
+		{"unknown kid", "/hook?x=1", strings.Replace(valid, "kid=", "kid=00", 1), "unknown kid"},
+		{"stale", "/hook?x=1", delivery.SignV2("test-secret", "POST", "/hook?x=1", body, "1700000000"), "timestamp outside leeway"},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, msg := verifySignature("test-secret", "POST", tt.target, body, "", tt.signature, 5*time.Minute)
+			if ok != (tt.wantMsg == "") || msg != tt.wantMsg {
+				t.Errorf("verifySignature() = %v, %q, want %q", ok, msg, tt.wantMsg)
+			}
+		})
+	}
+}
 
+// withSigningKeys sets the receiver's signing keys for the rest of the test
+func withSigningKeys(t *testing.T, s webhookverify.KeySource) {
+	t.Helper()
+	prev := signingKeys
+	signingKeys = s
+	t.Cleanup(func() { signingKeys = prev })
+}
+
+func TestVerifySignature_Ed25519(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(nil)
+	_, other, _ := ed25519.GenerateKey(nil)
+	var fetches int
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "OKP", "crv": "Ed25519", "kid": delivery.PublicKeyID(pub),
+			"x": base64.RawURLEncoding.EncodeToString(pub), "use": "sig", "alg": "EdDSA",
+		}}})
+	}))
+	defer jwks.Close()
+	withSigningKeys(t, webhookverify.NewKeySet(jwks.URL, jwks.Client()))
+
+	body := []byte(`{"a":1}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	valid := delivery.SignEd25519(key, "POST", "/hook", body, ts)
+
+	tests := []struct {
+		name      string
+		target    string
+		signature string
+		wantMsg   string
+	}{
+		{"valid", "/hook", valid, ""},
+		{"other path", "/other", valid, "sig mismatch"},
+		{"unknown key", "/hook", delivery.SignEd25519(other, "POST", "/hook", body, ts), "unknown kid"},
+		{"hmac alg with the key's kid", "/hook", strings.Replace(valid, "alg=Ed25519", "alg=HMAC-SHA256", 1), "unknown kid"},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := abs64(tt.input)
-			if result != tt.expected {
-				t.Errorf("abs64(%d) = %d, want %d", tt.input, result, tt.expected)
+			ok, msg := verifySignature("test-secret", "POST", tt.target, body, "", tt.signature, 5*time.Minute)
+			if ok != (tt.wantMsg == "") || msg != tt.wantMsg {
+				t.Errorf("verifySignature() = %v, %q, want %q", ok, msg, tt.wantMsg)
 			}
 		})
 	}
+	// The first signature fetched the set; the unknown kid right after waits for KeySetRefetch
+	if fetches != 1 {
+		t.Errorf("fetched the key set %d times, want 1", fetches)
+	}
 }
 
 func TestTruncate(t *testing.T) {
@@ -327,6 +370,14 @@ func TestHandleHook(t *testing.T) {
 			expectedStatus:       http.StatusOK,
 			expectedBodyContains: "ok",
 		},
+		{
+			name:                 "verification challenge skips failure injection",
+			body:                 `{"type":"endpoint.verification","challenge":"tok_123"}`,
+			headers:              map[string]string{},
+			cfgOverrides:         config.FakeReceiver{FailFirstN: 1, EndpointSecret: ""},
+			expectedStatus:       http.StatusOK,
+			expectedBodyContains: `{"challenge":"tok_123"}`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -357,3 +408,103 @@ func TestHandleHook(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleHook_Gzip(t *testing.T) {
+	cfg := config.FromEnv()
+	cfg.FakeReceiver = config.FakeReceiver{EndpointSecret: "test-secret", SigningLeewaySeconds: 300}
+	reqCount.Store(0)
+
+	body := []byte(`{"notes":"` + strings.Repeat("large payload ", 200) + `"}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte("test-secret"))
+	mac.Write(body)
+	mac.Write([]byte(ts))
+
+	var zipped bytes.Buffer
+	zw := gzip.NewWriter(&zipped)
+	_, _ = zw.Write(body)
+	_ = zw.Close()
+
+	req := httptest.NewRequest("POST", "/hook", &zipped)
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set(cfg.NSQ.TimestampHeader, ts)
+	req.Header.Set(cfg.NSQ.SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	w := httptest.NewRecorder()
+
+	handleHook(w, req, cfg)
+	if w.Code != http.StatusOK {
+		t.Errorf("handleHook(gzip body) status = %d, want 200: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestHandleEcho(t *testing.T) {
+	cfg := config.FromEnv()
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte("test-secret"))
+	mac.Write([]byte(`{"a":1}`))
+	mac.Write([]byte(ts))
+	validSig := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		want      echoSignature
+	}{
+		{name: "no secret configured", want: echoSignature{}},
+		{name: "valid signature", secret: "test-secret", signature: validSig, want: echoSignature{Checked: true, Valid: true}},
+		{name: "bad signature", secret: "test-secret", signature: "sha256=00", want: echoSignature{Checked: true, Error: "sig mismatch"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			testCfg := cfg
+			testCfg.FakeReceiver = config.FakeReceiver{EndpointSecret: tt.secret, SigningLeewaySeconds: 300, FailFirstN: 10}
+
+			req := httptest.NewRequest("POST", "/echo", strings.NewReader(`{"a":1}`))
+			req.Header.Set("X-HarborHook-Timestamp", ts)
+			req.Header.Set("X-HarborHook-Signature", tt.signature)
+			req.Header.Set("X-HarborHook-Tenant", "tn_123")
+			w := httptest.NewRecorder()
+
+			handleEcho(w, req, testCfg)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("handleEcho() status = %d, want %d", w.Code, http.StatusOK)
+			}
+			var got echoResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("handleEcho() body is not JSON: %v", err)
+			}
+			if got.Method != "POST" || got.Path != "/echo" || got.Body != `{"a":1}` {
+				t.Errorf("handleEcho() echoed %s %s %q", got.Method, got.Path, got.Body)
+			}
+			if v := got.Headers["X-Harborhook-Tenant"]; len(v) != 1 || v[0] != "tn_123" {
+				t.Errorf("handleEcho() tenant header = %v, want [tn_123]", v)
+			}
+			if got.Signature != tt.want {
+				t.Errorf("handleEcho() signature = %+v, want %+v", got.Signature, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignature_Vectors(t *testing.T) {
+	for _, v := range signvectors.Load(t) {
+		t.Run(v.Name, func(t *testing.T) {
+			unix, _ := strconv.ParseInt(v.Timestamp, 10, 64)
+			// The vectors are fixed in time; allow for their age
+			leeway := time.Since(time.Unix(unix, 0)) + time.Hour
+			if pub := v.PublicKeyBytes(t); pub != nil {
+				withSigningKeys(t, webhookverify.StaticKeys{delivery.PublicKeyID(pub): pub})
+			}
+
+			if ok, msg := verifySignature(v.Secret, v.Method, v.Path, []byte(v.Payload), v.Timestamp, v.Signature, leeway); !ok {
+				t.Errorf("verifySignature() rejected vector: %s", msg)
+			}
+			if ok, _ := verifySignature(v.Secret, v.Method, v.Path, []byte(v.Payload+" "), v.Timestamp, v.Signature, leeway); ok {
+				t.Error("verifySignature() accepted a tampered body")
+			}
+		})
+	}
+}
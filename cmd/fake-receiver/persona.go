@@ -0,0 +1,151 @@
+package main
+
+import (
+	"flag"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// personaPaths are the paths that each behave like one kind of receiver, so one
+// fake-receiver can stand in for a mixed fleet of endpoints
+var personaPaths = []string{"/ok", "/flaky", "/slow", "/ratelimit", "/redirect", "/bigbody"}
+
+// personaDefaults are the persona settings a request doesn't override in its query string
+type personaDefaults struct {
+	FlakyRate    float64 // share of /flaky requests answered 500
+	SlowMS       int     // how long /slow waits before answering
+	RateLimitRPS float64 // requests per second /ratelimit accepts before answering 429
+	RedirectTo   string  // where /redirect sends requests
+	BigBodyBytes int     // size of /bigbody's response body
+}
+
+// personaFlags registers the persona defaults on fs
+func personaFlags(fs *flag.FlagSet) *personaDefaults {
+	d := &personaDefaults{}
+	fs.Float64Var(&d.FlakyRate, "flaky-rate", 0.3, "share of /flaky requests answered 500 (?rate= overrides)")
+	fs.IntVar(&d.SlowMS, "slow-ms", 5000, "milliseconds /slow waits before answering (?ms= overrides)")
+	fs.Float64Var(&d.RateLimitRPS, "ratelimit-rps", 2, "requests per second /ratelimit accepts before answering 429 (?rps= overrides)")
+	fs.StringVar(&d.RedirectTo, "redirect-to", "/ok", "where /redirect sends requests with a 307 (?to= overrides)")
+	fs.IntVar(&d.BigBodyBytes, "bigbody-bytes", 1<<20, "size of /bigbody's response body (?bytes= overrides)")
+	return d
+}
+
+// personaSet answers requests to the persona paths
+type personaSet struct {
+	defaults personaDefaults
+
+	mu      sync.Mutex
+	buckets map[float64]*tokenBucket // /ratelimit's buckets, one per rate in use
+}
+
+func newPersonaSet(d personaDefaults) *personaSet {
+	return &personaSet{defaults: d, buckets: map[float64]*tokenBucket{}}
+}
+
+// serve answers r if its path is a persona's, reporting whether it did
+func (ps *personaSet) serve(w http.ResponseWriter, r *http.Request) bool {
+	q := r.URL.Query()
+	switch r.URL.Path {
+	case "/ok":
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`ok`))
+	case "/flaky":
+		if rand.Float64() < queryFloat(q.Get("rate"), ps.defaults.FlakyRate) {
+			http.Error(w, "flaky: temporary failure", http.StatusInternalServerError)
+			return true
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`ok`))
+	case "/slow":
+		select {
+		case <-r.Context().Done():
+			return true
+		case <-time.After(time.Duration(queryInt(q.Get("ms"), ps.defaults.SlowMS)) * time.Millisecond):
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`ok`))
+	case "/ratelimit":
+		rps := queryFloat(q.Get("rps"), ps.defaults.RateLimitRPS)
+		if wait, ok := ps.bucket(rps).take(time.Now()); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+			http.Error(w, "rate limited", http.StatusTooManyRequests)
+			return true
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`ok`))
+	case "/redirect":
+		to := q.Get("to")
+		if to == "" {
+			to = ps.defaults.RedirectTo
+		}
+		// 307 keeps the method and body, so a client that follows it delivers to the target
+		http.Redirect(w, r, to, http.StatusTemporaryRedirect)
+	case "/bigbody":
+		n := queryInt(q.Get("bytes"), ps.defaults.BigBodyBytes)
+		w.Header().Set("Content-Type", "text/plain")
+		w.Header().Set("Content-Length", strconv.Itoa(n))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(strings.Repeat("x", n)))
+	default:
+		return false
+	}
+	return true
+}
+
+func (ps *personaSet) bucket(rps float64) *tokenBucket {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	b, ok := ps.buckets[rps]
+	if !ok {
+		b = &tokenBucket{rate: rps, burst: max(rps, 1), tokens: max(rps, 1)}
+		ps.buckets[rps] = b
+	}
+	return b
+}
+
+// tokenBucket admits rate requests a second, with bursts of up to burst
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// take spends a token, or reports how long until one is available
+func (b *tokenBucket) take(now time.Time) (time.Duration, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.last.IsZero() {
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	}
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	if b.rate <= 0 {
+		return time.Second, false
+	}
+	return time.Duration((1 - b.tokens) / b.rate * float64(time.Second)), false
+}
+
+// queryFloat parses a query parameter, falling back to def when it is missing or invalid
+func queryFloat(v string, def float64) float64 {
+	if f, err := strconv.ParseFloat(v, 64); err == nil && f >= 0 {
+		return f
+	}
+	return def
+}
+
+// queryInt parses a query parameter, falling back to def when it is missing or invalid
+func queryInt(v string, def int) int {
+	if n, err := strconv.Atoi(v); err == nil && n >= 0 {
+		return n
+	}
+	return def
+}
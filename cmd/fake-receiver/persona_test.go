@@ -0,0 +1,68 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+func TestHandleHook_Personas(t *testing.T) {
+	cfg := config.FromEnv()
+	cfg.FakeReceiver = config.FakeReceiver{FailFirstN: 100}
+	defaults := personaFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+	personas = newPersonaSet(*defaults)
+	defer func() { personas = nil }()
+
+	tests := []struct {
+		target     string
+		wantStatus int
+		wantHeader string
+		wantValue  string
+		wantLen    int
+	}{
+		{target: "/ok", wantStatus: http.StatusOK},
+		{target: "/flaky?rate=1", wantStatus: http.StatusInternalServerError},
+		{target: "/flaky?rate=0", wantStatus: http.StatusOK},
+		{target: "/slow?ms=1", wantStatus: http.StatusOK},
+		{target: "/redirect", wantStatus: http.StatusTemporaryRedirect, wantHeader: "Location", wantValue: "/ok"},
+		{target: "/redirect?to=/slow", wantStatus: http.StatusTemporaryRedirect, wantHeader: "Location", wantValue: "/slow"},
+		{target: "/bigbody?bytes=10", wantStatus: http.StatusOK, wantLen: 10},
+		{target: "/ratelimit?rps=1", wantStatus: http.StatusOK},
+		{target: "/ratelimit?rps=1", wantStatus: http.StatusTooManyRequests, wantHeader: "Retry-After", wantValue: "1"},
+		{target: "/hook", wantStatus: http.StatusInternalServerError}, // FAIL_FIRST_N still applies off the persona paths
+	}
+	for _, tt := range tests {
+		reqCount.Store(0)
+		w := httptest.NewRecorder()
+		handleHook(w, httptest.NewRequest("POST", tt.target, nil), cfg)
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s status = %d, want %d", tt.target, w.Code, tt.wantStatus)
+		}
+		if tt.wantHeader != "" && w.Header().Get(tt.wantHeader) != tt.wantValue {
+			t.Errorf("%s %s = %q, want %q", tt.target, tt.wantHeader, w.Header().Get(tt.wantHeader), tt.wantValue)
+		}
+		if tt.wantLen > 0 && w.Body.Len() != tt.wantLen {
+			t.Errorf("%s body is %d bytes, want %d", tt.target, w.Body.Len(), tt.wantLen)
+		}
+	}
+}
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	b := &tokenBucket{rate: 2, burst: 2, tokens: 2}
+	for i := 0; i < 2; i++ {
+		if _, ok := b.take(now); !ok {
+			t.Fatalf("take %d refused within the burst", i+1)
+		}
+	}
+	if wait, ok := b.take(now); ok || wait != 500*time.Millisecond {
+		t.Errorf("take past the burst = %s, %t, want a 500ms wait", wait, ok)
+	}
+	if _, ok := b.take(now.Add(500 * time.Millisecond)); !ok {
+		t.Error("take after refilling one token refused")
+	}
+}
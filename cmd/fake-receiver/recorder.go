@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// receivedRequest is one request to /hook as /received lists it
+type receivedRequest struct {
+	Seq        int64               `json:"seq"`
+	ReceivedAt time.Time           `json:"received_at"`
+	Method     string              `json:"method"`
+	Path       string              `json:"path"`
+	Headers    map[string][]string `json:"headers"`
+	Body       string              `json:"body"`
+	Signature  echoSignature       `json:"signature"`
+	Status     int                 `json:"status"` // what the receiver answered; 0 when it reset the connection
+}
+
+// recorder keeps the newest requests in memory and, with a file, appends each one to it as a
+// JSON line so a restarted receiver still lists them
+type recorder struct {
+	mu       sync.Mutex
+	limit    int
+	seq      int64
+	requests []receivedRequest
+	file     *os.File
+}
+
+// newRecorder returns a recorder holding up to limit requests, loading the newest of them
+// from path when it is set
+func newRecorder(limit int, path string) (*recorder, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+	rec := &recorder{limit: limit}
+	if path == "" {
+		return rec, nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open record file: %w", err)
+	}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for sc.Scan() {
+		var req receivedRequest
+		if err := json.Unmarshal(sc.Bytes(), &req); err != nil {
+			continue // a line cut short by a crash
+		}
+		rec.keep(req)
+	}
+	if err := sc.Err(); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("read record file: %w", err)
+	}
+	rec.file = f
+	return rec, nil
+}
+
+// keep appends req, dropping the oldest request past the limit. The caller holds mu or owns rec.
+func (rec *recorder) keep(req receivedRequest) {
+	if req.Seq > rec.seq {
+		rec.seq = req.Seq
+	}
+	rec.requests = append(rec.requests, req)
+	if over := len(rec.requests) - rec.limit; over > 0 {
+		rec.requests = append(rec.requests[:0:0], rec.requests[over:]...)
+	}
+}
+
+// add numbers req and records it
+func (rec *recorder) add(req receivedRequest) error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	req.Seq = rec.seq + 1
+	rec.keep(req)
+	if rec.file == nil {
+		return nil
+	}
+	line, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	_, err = rec.file.Write(append(line, '\n'))
+	return err
+}
+
+// list returns the recorded requests matching f, oldest first
+func (rec *recorder) list(f receivedFilter) []receivedRequest {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	out := []receivedRequest{}
+	for _, req := range rec.requests {
+		if f.match(req) {
+			out = append(out, req)
+		}
+	}
+	if f.limit > 0 && len(out) > f.limit {
+		out = out[len(out)-f.limit:]
+	}
+	return out
+}
+
+// reset forgets every request, truncating the record file
+func (rec *recorder) reset() error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.requests = nil
+	if rec.file == nil {
+		return nil
+	}
+	return rec.file.Truncate(0)
+}
+
+// receivedFilter narrows /received by its query parameters
+type receivedFilter struct {
+	path           string
+	deliveryID     string
+	deliveryHeader string
+	afterSeq       int64
+	valid          *bool
+	limit          int
+}
+
+func (f receivedFilter) match(req receivedRequest) bool {
+	switch {
+	case f.path != "" && req.Path != f.path:
+		return false
+	case f.deliveryID != "" && http.Header(req.Headers).Get(f.deliveryHeader) != f.deliveryID:
+		return false
+	case req.Seq <= f.afterSeq:
+		return false
+	case f.valid != nil && (!req.Signature.Checked || req.Signature.Valid != *f.valid):
+		return false
+	}
+	return true
+}
+
+// handleReceived lists recorded requests, oldest first. Query parameters: path, delivery_id
+// (matched against the delivery header), after (a seq, for polling), valid (true or false;
+// only requests whose signature was checked match) and limit (the newest n).
+func handleReceived(rec *recorder, deliveryHeader string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		q := r.URL.Query()
+		f := receivedFilter{path: q.Get("path"), deliveryID: q.Get("delivery_id"), deliveryHeader: deliveryHeader}
+		var err error
+		if v := q.Get("after"); v != "" {
+			if f.afterSeq, err = strconv.ParseInt(v, 10, 64); err != nil {
+				http.Error(w, "invalid after: "+v, http.StatusBadRequest)
+				return
+			}
+		}
+		if v := q.Get("limit"); v != "" {
+			if f.limit, err = strconv.Atoi(v); err != nil || f.limit < 0 {
+				http.Error(w, "invalid limit: "+v, http.StatusBadRequest)
+				return
+			}
+		}
+		if v := q.Get("valid"); v != "" {
+			valid, err := strconv.ParseBool(v)
+			if err != nil {
+				http.Error(w, "invalid valid: "+v, http.StatusBadRequest)
+				return
+			}
+			f.valid = &valid
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"requests": rec.list(f)})
+	}
+}
+
+// handleReset forgets the recorded requests and restarts FAIL_FIRST_N, so each test starts clean
+func handleReset(rec *recorder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if err := rec.reset(); err != nil {
+			http.Error(w, "reset: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
+		reqCount.Store(0)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok":true}`))
+	}
+}
+
+// statusWriter notes the status a handler answered with. Unwrap lets http.ResponseController
+// reach the connection underneath, to flush or hijack it.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *statusWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
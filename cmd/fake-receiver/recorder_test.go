@@ -0,0 +1,132 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+// listReceived calls /received with query and decodes the requests it lists
+func listReceived(t *testing.T, rec *recorder, deliveryHeader, query string) []receivedRequest {
+	t.Helper()
+	w := httptest.NewRecorder()
+	handleReceived(rec, deliveryHeader)(w, httptest.NewRequest("GET", "/received?"+query, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("/received?%s status = %d: %s", query, w.Code, w.Body.String())
+	}
+	var resp struct {
+		Requests []receivedRequest `json:"requests"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("/received?%s: %v", query, err)
+	}
+	return resp.Requests
+}
+
+func TestHandleHook_Records(t *testing.T) {
+	cfg := config.FromEnv()
+	cfg.FakeReceiver = config.FakeReceiver{EndpointSecret: "test-secret", SigningLeewaySeconds: 300}
+	reqCount.Store(0)
+	rec, err := newRecorder(10, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	received = rec
+	defer func() { received = nil }()
+
+	send := func(path, deliveryID, body string, signed bool) {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		mac := hmac.New(sha256.New, []byte("test-secret"))
+		mac.Write([]byte(body))
+		mac.Write([]byte(ts))
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set(cfg.NSQ.TimestampHeader, ts)
+		req.Header.Set(cfg.NSQ.DeliveryHeader, deliveryID)
+		if signed {
+			req.Header.Set(cfg.NSQ.SignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+		}
+		handleHook(httptest.NewRecorder(), req, cfg)
+	}
+	send("/hook", "del_1", `{"n":1}`, true)
+	send("/hook/b", "del_2", `{"n":2}`, false)
+	send("/hook", "del_3", `{"n":3}`, true)
+
+	all := listReceived(t, rec, cfg.NSQ.DeliveryHeader, "")
+	if len(all) != 3 || all[0].Seq != 1 || all[0].Body != `{"n":1}` || all[0].Status != http.StatusOK || !all[0].Signature.Valid {
+		t.Fatalf("/received = %+v, want the three requests oldest first", all)
+	}
+	if all[1].Status != http.StatusUnauthorized || !all[1].Signature.Checked || all[1].Signature.Valid || all[1].Signature.Error == "" {
+		t.Errorf("unsigned request recorded as %+v, want a 401 with the signature error", all[1])
+	}
+
+	tests := []struct {
+		query string
+		want  []int64
+	}{
+		{"delivery_id=del_2", []int64{2}},
+		{"path=/hook", []int64{1, 3}},
+		{"after=1", []int64{2, 3}},
+		{"valid=false", []int64{2}},
+		{"limit=2", []int64{2, 3}},
+	}
+	for _, tt := range tests {
+		var got []int64
+		for _, r := range listReceived(t, rec, cfg.NSQ.DeliveryHeader, tt.query) {
+			got = append(got, r.Seq)
+		}
+		if len(got) != len(tt.want) || (len(got) > 0 && (got[0] != tt.want[0] || got[len(got)-1] != tt.want[len(tt.want)-1])) {
+			t.Errorf("/received?%s seqs = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	handleReset(rec)(w, httptest.NewRequest("POST", "/reset", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("/reset status = %d", w.Code)
+	}
+	if got := listReceived(t, rec, cfg.NSQ.DeliveryHeader, ""); len(got) != 0 {
+		t.Errorf("/received after /reset = %+v, want none", got)
+	}
+}
+
+func TestRecorder_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "received.jsonl")
+	rec, err := newRecorder(2, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := rec.add(receivedRequest{Path: "/hook", Body: strconv.Itoa(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reloaded, err := newRecorder(2, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := reloaded.list(receivedFilter{})
+	if len(got) != 2 || got[0].Body != "1" || got[1].Seq != 3 {
+		t.Fatalf("reloaded requests = %+v, want the newest two", got)
+	}
+	if err := reloaded.add(receivedRequest{Path: "/hook"}); err != nil || reloaded.list(receivedFilter{})[1].Seq != 4 {
+		t.Errorf("request after reload numbered %+v, want seq 4", reloaded.list(receivedFilter{}))
+	}
+
+	if err := reloaded.reset(); err != nil {
+		t.Fatal(err)
+	}
+	if again, err := newRecorder(2, path); err != nil || len(again.list(receivedFilter{})) != 0 {
+		t.Errorf("requests after reset and reload = %v, %v, want none", again, err)
+	}
+}
@@ -0,0 +1,409 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"github.com/spf13/cobra"
+)
+
+// adminCmd represents the admin command
+var adminCmd = &cobra.Command{
+	Use:   "admin",
+	Short: "Incident controls for delivery dispatch",
+	Long:  `Freeze, drain and resume deliveries for a tenant or endpoint, or pause dispatch cluster-wide, without scaling workers.`,
+}
+
+// freezeCmd represents the freeze command
+var freezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Stop dispatching deliveries for a tenant or endpoint",
+	Long: `Create a freeze. Workers park matching tasks instead of sending them until the freeze is resumed.
+
+Example:
+  harborctl admin freeze --endpoint-id ep_456 --reason "receiver returning 500s"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID, endpointID := adminTargetFlags(cmd)
+		reason, _ := cmd.Flags().GetString("reason")
+
+		if useHTTP {
+			return adminHTTPRequest("/v1/admin/freezes", map[string]interface{}{
+				"tenantId":   tenantID,
+				"endpointId": endpointID,
+				"reason":     reason,
+			})
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.FreezeDeliveries(context.Background(), &webhookv1.FreezeDeliveriesRequest{
+			TenantId:   tenantID,
+			EndpointId: endpointID,
+			Reason:     reason,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to freeze deliveries: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Created freeze: %s\n", resp.Freeze.Id)
+			fmt.Printf("  Pending deliveries: %d\n", resp.PendingCount)
+			fmt.Println("  Run 'harborctl admin drain' to park them now")
+		}
+		return nil
+	},
+}
+
+// drainCmd represents the drain command
+var drainCmd = &cobra.Command{
+	Use:   "drain",
+	Short: "Park queued and retrying deliveries for a tenant or endpoint",
+	Long: `Park every queued or retrying delivery for the target. Parked deliveries are requeued by resume.
+
+Example:
+  harborctl admin drain --tenant-id tn_123`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID, endpointID := adminTargetFlags(cmd)
+
+		if useHTTP {
+			return adminHTTPRequest("/v1/admin/queue:drain", map[string]interface{}{
+				"tenantId":   tenantID,
+				"endpointId": endpointID,
+			})
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.DrainQueue(context.Background(), &webhookv1.DrainQueueRequest{
+			TenantId:   tenantID,
+			EndpointId: endpointID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to drain queue: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Parked deliveries: %d\n", resp.ParkedCount)
+		}
+		return nil
+	},
+}
+
+// resumeCmd represents the resume command
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Release freezes and requeue parked deliveries",
+	Long: `Release active freezes for the target and requeue its parked deliveries.
+
+Example:
+  harborctl admin resume --endpoint-id ep_456`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID, endpointID := adminTargetFlags(cmd)
+
+		if useHTTP {
+			return adminHTTPRequest("/v1/admin/freezes:resume", map[string]interface{}{
+				"tenantId":   tenantID,
+				"endpointId": endpointID,
+			})
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.ResumeDeliveries(context.Background(), &webhookv1.ResumeDeliveriesRequest{
+			TenantId:   tenantID,
+			EndpointId: endpointID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resume deliveries: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Released freezes: %d\n", resp.ReleasedFreezes)
+			fmt.Printf("Requeued deliveries: %d\n", resp.RequeuedCount)
+		}
+		return nil
+	},
+}
+
+// pauseDispatchCmd represents the pause command
+var pauseDispatchCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Emergency stop: pause all outbound deliveries",
+	Long: `Flip the cluster-wide kill switch. Workers hold every task (without spending
+an attempt) until dispatch is resumed.
+
+Example:
+  harborctl admin pause --reason "signing key leaked"`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reason, _ := cmd.Flags().GetString("reason")
+
+		if useHTTP {
+			return adminHTTPRequest("/v1/admin/dispatch:pause", map[string]interface{}{"reason": reason})
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.PauseDispatch(context.Background(), &webhookv1.PauseDispatchRequest{Reason: reason})
+		if err != nil {
+			return fmt.Errorf("failed to pause dispatch: %w", err)
+		}
+		printDispatchState(resp.State)
+		return nil
+	},
+}
+
+// resumeDispatchCmd represents the unpause command
+var resumeDispatchCmd = &cobra.Command{
+	Use:   "unpause",
+	Short: "Resume outbound deliveries with a gradual ramp-up",
+	Long: `Release the kill switch. With --ramp, workers admit --start-percent of tasks
+at first and ramp linearly to 100% so the backlog doesn't hit receivers at once.
+
+Example:
+  harborctl admin unpause --ramp 10m --start-percent 5`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ramp, _ := cmd.Flags().GetDuration("ramp")
+		startPercent, _ := cmd.Flags().GetInt32("start-percent")
+		rampSeconds := int32(ramp.Seconds())
+
+		if useHTTP {
+			return adminHTTPRequest("/v1/admin/dispatch:resume", map[string]interface{}{
+				"rampSeconds":  rampSeconds,
+				"startPercent": startPercent,
+			})
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.ResumeDispatch(context.Background(), &webhookv1.ResumeDispatchRequest{
+			RampSeconds:  rampSeconds,
+			StartPercent: startPercent,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to resume dispatch: %w", err)
+		}
+		printDispatchState(resp.State)
+		return nil
+	},
+}
+
+// dispatchStatusCmd represents the dispatch command
+var dispatchStatusCmd = &cobra.Command{
+	Use:   "dispatch",
+	Short: "Show the kill switch state",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if useHTTP {
+			resp, err := makeHTTPRequest("GET", "/v1/admin/dispatch", nil)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.GetDispatchState(context.Background(), &webhookv1.GetDispatchStateRequest{})
+		if err != nil {
+			return fmt.Errorf("failed to get dispatch state: %w", err)
+		}
+		printDispatchState(resp.State)
+		return nil
+	},
+}
+
+// quotaCmd represents the admin quota command
+var quotaCmd = &cobra.Command{
+	Use:   "quota [tenant-id]",
+	Short: "Set a tenant's publishing quotas",
+	Long: `Set how many events a tenant may publish per minute and how many endpoints a
+single event may fan out to. Publishes over quota are rejected with RESOURCE_EXHAUSTED
+(HTTP 429). 0 means unlimited.
+
+Example:
+  harborctl admin quota tn_123 --events-per-minute 600 --max-fanout 20`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID := args[0]
+		eventsPerMinute, _ := cmd.Flags().GetInt32("events-per-minute")
+		maxFanout, _ := cmd.Flags().GetInt32("max-fanout")
+
+		if useHTTP {
+			resp, err := makeHTTPRequest("PUT", fmt.Sprintf("/v1/admin/tenants/%s/quota", tenantID), map[string]interface{}{
+				"eventsPerMinute": eventsPerMinute,
+				"maxFanout":       maxFanout,
+			})
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.SetTenantQuota(context.Background(), &webhookv1.SetTenantQuotaRequest{
+			Quota: &webhookv1.TenantQuota{
+				TenantId:        tenantID,
+				EventsPerMinute: eventsPerMinute,
+				MaxFanout:       maxFanout,
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set quota: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Updated quotas for tenant %s\n", resp.Quota.TenantId)
+			printTenantQuota(resp.Quota)
+		}
+		return nil
+	},
+}
+
+// printTenantQuota prints a tenant's limits, with 0 shown as unlimited
+func printTenantQuota(q *webhookv1.TenantQuota) {
+	limit := func(n int32) string {
+		if n == 0 {
+			return "unlimited"
+		}
+		return fmt.Sprint(n)
+	}
+	fmt.Printf("  Events per minute: %s\n", limit(q.EventsPerMinute))
+	fmt.Printf("  Max fanout: %s\n", limit(q.MaxFanout))
+}
+
+// printDispatchState prints the kill switch state in the selected output format
+func printDispatchState(st *webhookv1.DispatchState) {
+	if outputJSON {
+		printOutput(st)
+		return
+	}
+	if st.Paused {
+		fmt.Printf("Dispatch: PAUSED (%s)\n", st.Reason)
+		return
+	}
+	fmt.Printf("Dispatch: running, admitting %.0f%% of tasks\n", st.AdmitPercent)
+	if st.RampSeconds > 0 && st.AdmitPercent < 100 {
+		fmt.Printf("  Ramping from %d%% over %ds\n", st.RampStartPercent, st.RampSeconds)
+	}
+}
+
+// adminTargetFlags reads the --tenant-id/--endpoint-id target shared by the admin commands
+func adminTargetFlags(cmd *cobra.Command) (tenantID, endpointID string) {
+	tenantID, _ = cmd.Flags().GetString("tenant-id")
+	endpointID, _ = cmd.Flags().GetString("endpoint-id")
+	return tenantID, endpointID
+}
+
+// adminHTTPRequest POSTs an admin request and prints the response
+func adminHTTPRequest(path string, payload map[string]interface{}) error {
+	resp, err := makeHTTPRequest("POST", path, payload)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("HTTP error: %s", resp.Status)
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	printOutput(result)
+	return nil
+}
+
+func init() {
+	rootCmd.AddCommand(adminCmd)
+	adminCmd.AddCommand(freezeCmd)
+	adminCmd.AddCommand(drainCmd)
+	adminCmd.AddCommand(resumeCmd)
+	adminCmd.AddCommand(pauseDispatchCmd)
+	adminCmd.AddCommand(resumeDispatchCmd)
+	adminCmd.AddCommand(dispatchStatusCmd)
+	adminCmd.AddCommand(quotaCmd)
+
+	for _, c := range []*cobra.Command{freezeCmd, drainCmd, resumeCmd} {
+		c.Flags().String("tenant-id", "", "target every endpoint of a tenant")
+		c.Flags().String("endpoint-id", "", "target a single endpoint")
+		c.MarkFlagsMutuallyExclusive("tenant-id", "endpoint-id")
+		c.MarkFlagsOneRequired("tenant-id", "endpoint-id")
+	}
+
+	// Flags for freeze command
+	freezeCmd.Flags().String("reason", "", "why deliveries are being frozen")
+
+	// Flags for kill switch commands
+	pauseDispatchCmd.Flags().String("reason", "", "why dispatch is being paused")
+	_ = pauseDispatchCmd.MarkFlagRequired("reason")
+	resumeDispatchCmd.Flags().Duration("ramp", 0, "ramp traffic back to 100% over this duration (0 resumes at full rate)")
+	resumeDispatchCmd.Flags().Int32("start-percent", 10, "percentage of traffic admitted when the ramp starts")
+
+	// Flags for quota command
+	quotaCmd.Flags().Int32("events-per-minute", 0, "events the tenant may publish per minute (0 is unlimited)")
+	quotaCmd.Flags().Int32("max-fanout", 0, "endpoints a single event may fan out to (0 is unlimited)")
+}
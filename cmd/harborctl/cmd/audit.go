@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"github.com/spf13/cobra"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Review who changed endpoints, replayed deliveries or purged the DLQ",
+	Long: `Every endpoint change, delivery replay and DLQ replay or purge is recorded with the caller's
+tenant, token subject, role and address, and the resource before and after.`,
+}
+
+// auditListCmd represents the audit list command
+var auditListCmd = &cobra.Command{
+	Use:   "list [tenant-id]",
+	Short: "List a tenant's audit log",
+	Long: `List a tenant's audit log entries, newest first.
+
+Use --page-token with the token printed at the end of a page to fetch the next one.
+
+Examples:
+  harborctl audit list tn_123 --since 24h
+  harborctl audit list tn_123 --action endpoint.delete
+  harborctl audit list tn_123 --resource ep_456 --actor alice@example.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID := args[0]
+		action, _ := cmd.Flags().GetString("action")
+		resourceID, _ := cmd.Flags().GetString("resource")
+		actor, _ := cmd.Flags().GetString("actor")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+		limit, _ := cmd.Flags().GetInt32("limit")
+
+		from, err := dlqSince(cmd)
+		if err != nil {
+			return err
+		}
+
+		if useHTTP {
+			params := url.Values{}
+			if action != "" {
+				params.Add("action", action)
+			}
+			if resourceID != "" {
+				params.Add("resourceId", resourceID)
+			}
+			if actor != "" {
+				params.Add("actorSubject", actor)
+			}
+			if from != nil {
+				params.Add("from", from.AsTime().Format(time.RFC3339))
+			}
+			if pageToken != "" {
+				params.Add("pageToken", pageToken)
+			}
+			if limit > 0 {
+				params.Add("limit", fmt.Sprint(limit))
+			}
+			return dlqPrintHTTP("GET", fmt.Sprintf("/v1/tenants/%s/audit-log?%s", tenantID, params.Encode()), nil)
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.ListAuditLog(context.Background(), &webhookv1.ListAuditLogRequest{
+			TenantId:     tenantID,
+			Action:       action,
+			ResourceId:   resourceID,
+			ActorSubject: actor,
+			From:         from,
+			Limit:        limit,
+			PageToken:    pageToken,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list audit log: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+			return nil
+		}
+		if len(resp.Entries) == 0 {
+			fmt.Println("No audit log entries found")
+			return nil
+		}
+		for _, e := range resp.Entries {
+			actor := e.ActorSubject
+			if actor == "" {
+				actor = e.ActorTenantId
+			}
+			fmt.Printf("%s  %-32s %s %s  by %s (%s) from %s\n",
+				e.CreatedAt.AsTime().Local().Format("2006-01-02 15:04:05"),
+				e.Action, e.ResourceType, e.ResourceId, actor, e.ActorRole, e.ClientIp)
+		}
+		if resp.NextPageToken != "" {
+			fmt.Printf("\nMore entries available: --page-token %s\n", resp.NextPageToken)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.AddCommand(auditListCmd)
+
+	auditListCmd.Flags().String("action", "", "only entries for this action (e.g. endpoint.delete, dlq.purge)")
+	auditListCmd.Flags().String("resource", "", "only entries for this endpoint, delivery or DLQ scope")
+	auditListCmd.Flags().String("actor", "", "only entries made by this token subject")
+	auditListCmd.Flags().Duration("since", 0, "only entries from the last duration (e.g. 24h)")
+	auditListCmd.Flags().Int32("limit", 0, "maximum entries per page (default 50, max 500)")
+	auditListCmd.Flags().String("page-token", "", "token from a previous page")
+}
@@ -0,0 +1,383 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// loginRefreshWindow is how close to expiry a cached login token is renewed
+const loginRefreshWindow = 5 * time.Minute
+
+// defaultJWKSURL is the local JWKS server's address
+const defaultJWKSURL = "http://localhost:8082"
+
+// jwksAPIKeyEnv names the environment variable holding the JWKS server's ADMIN_API_KEY, which
+// /token requires unless the server runs in DEV_MODE
+const jwksAPIKeyEnv = "JWKS_API_KEY"
+
+// loginToken is a token from auth login, with what's needed to renew it
+type loginToken struct {
+	Token      string    `json:"token"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Tenant     string    `json:"tenant"`
+	Roles      []string  `json:"roles,omitempty"`
+	Scope      string    `json:"scope,omitempty"`
+	TTLSeconds int       `json:"ttl_seconds"`
+	JWKSURL    string    `json:"jwks_url"`
+	// RefreshToken renews Token without requesting a new one; it's replaced on every renewal
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// credentialsPath is where login tokens are cached: credentials.json in the user's config
+// directory, e.g. ~/.config/harborctl on Linux
+func credentialsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "harborctl", "credentials.json"), nil
+}
+
+// credentialsKey is the active profile's entry in the credentials file
+func credentialsKey() string {
+	if profileName == "" {
+		return "default"
+	}
+	return strings.ToLower(profileName)
+}
+
+// loadCredentials reads the cached login tokens by profile; a missing file is empty
+func loadCredentials() (map[string]*loginToken, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	creds := map[string]*loginToken{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return creds, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return creds, nil
+}
+
+// saveCredentials writes the login tokens, readable by the user only
+func saveCredentials(creds map[string]*loginToken) error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(creds, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
+
+// issuedToken is a token from the JWKS server, with the refresh token that renews it if the
+// server issued one
+type issuedToken struct {
+	Token        string
+	ExpiresAt    time.Time
+	RefreshToken string
+}
+
+// requestToken asks the JWKS server for a token for tenantID, authenticating with apiKey when
+// it's set. No roles or scope and a zero ttl leave the server's defaults.
+func requestToken(jwksURL, apiKey, tenantID string, roles []string, scope string, ttl time.Duration) (issuedToken, error) {
+	return postTokenRequest(jwksURL, apiKey, map[string]any{"tenant_id": tenantID, "ttl_seconds": int(ttl.Seconds()), "roles": roles, "scope": scope})
+}
+
+// refreshLogin exchanges a refresh token for a new token with the same tenant and roles. The
+// refresh token is spent; the new one replaces it.
+func refreshLogin(jwksURL, refreshToken string, ttl time.Duration) (issuedToken, error) {
+	return postTokenRequest(jwksURL, "", map[string]any{"grant_type": "refresh_token", "refresh_token": refreshToken, "ttl_seconds": int(ttl.Seconds())})
+}
+
+func postTokenRequest(jwksURL, apiKey string, body map[string]any) (issuedToken, error) {
+	reqBody, _ := json.Marshal(body)
+	req, err := http.NewRequest(http.MethodPost, strings.TrimRight(jwksURL, "/")+"/token", bytes.NewReader(reqBody))
+	if err != nil {
+		return issuedToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return issuedToken{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return issuedToken{}, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	var out struct {
+		Token        string `json:"token"`
+		ExpiresIn    int    `json:"expires_in"`
+		RefreshToken string `json:"refresh_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return issuedToken{}, fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if out.Token == "" {
+		return issuedToken{}, errors.New("token response had no token")
+	}
+	return issuedToken{Token: out.Token, ExpiresAt: time.Now().Add(time.Duration(out.ExpiresIn) * time.Second), RefreshToken: out.RefreshToken}, nil
+}
+
+// cachedLoginToken is the active profile's login token, renewed from the JWKS server when it's
+// within loginRefreshWindow of expiring: with the login's refresh token when it has one, else
+// by requesting a new token. If renewal fails the old token is kept while it's still valid.
+// Problems are warnings: the command runs without a token.
+func cachedLoginToken() string {
+	creds, err := loadCredentials()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		return ""
+	}
+	login := creds[credentialsKey()]
+	if login == nil {
+		return ""
+	}
+	if time.Until(login.ExpiresAt) > loginRefreshWindow {
+		return login.Token
+	}
+
+	ttl := time.Duration(login.TTLSeconds) * time.Second
+	var issued issuedToken
+	if login.RefreshToken != "" {
+		issued, err = refreshLogin(login.JWKSURL, login.RefreshToken, ttl)
+	}
+	if login.RefreshToken == "" || err != nil {
+		issued, err = requestToken(login.JWKSURL, os.Getenv(jwksAPIKeyEnv), login.Tenant, login.Roles, login.Scope, ttl)
+	}
+	if err != nil {
+		if time.Now().Before(login.ExpiresAt) {
+			fmt.Fprintf(os.Stderr, "Warning: failed to refresh login token, using it until it expires at %s: %v\n", login.ExpiresAt.Format(time.RFC3339), err)
+			return login.Token
+		}
+		fmt.Fprintf(os.Stderr, "Warning: login token expired and couldn't be refreshed (run 'harborctl auth login'): %v\n", err)
+		return ""
+	}
+	login.Token, login.ExpiresAt, login.RefreshToken = issued.Token, issued.ExpiresAt, issued.RefreshToken
+	if err := saveCredentials(creds); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save refreshed login token: %v\n", err)
+	}
+	return issued.Token
+}
+
+// authCmd represents the auth command
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Log in and manage the cached token",
+	Long: `Get a token from the JWKS server and cache it, so other commands don't need --token.
+
+The token is kept in credentials.json in your config directory (~/.config/harborctl on Linux),
+one per profile, and renewed automatically when it's within 5 minutes of expiring. A token from
+--token, the config file or JWT_TOKEN takes precedence over it.`,
+}
+
+// authLoginCmd represents the auth login command
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Get a token for a tenant and cache it",
+	Long: `Get a token for a tenant from the JWKS server's /token endpoint and cache it for the
+active profile.
+
+Examples:
+  harborctl auth login --tenant tn_123
+  harborctl auth login --tenant tn_123 --role viewer --ttl 8h
+  harborctl auth login --tenant tn_123 --scope harborhook:publisher
+  harborctl --profile staging auth login --jwks-url https://jwks.staging.example.com
+
+Outside dev mode the JWKS server only issues tokens to callers with its API key: pass --api-key
+or set JWKS_API_KEY. Renewals use the refresh token issued at login, falling back to a new
+request with JWKS_API_KEY.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID, _ := cmd.Flags().GetString("tenant")
+		jwksURL, _ := cmd.Flags().GetString("jwks-url")
+		roles, _ := cmd.Flags().GetStringSlice("role")
+		scopes, _ := cmd.Flags().GetStringSlice("scope")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		apiKey, _ := cmd.Flags().GetString("api-key")
+		if apiKey == "" {
+			apiKey = os.Getenv(jwksAPIKeyEnv)
+		}
+		if tenantID == "" {
+			tenantID = defaultTenant
+		}
+		if tenantID == "" {
+			return errors.New("--tenant is required when the config doesn't set a tenant")
+		}
+		if !cmd.Flags().Changed("jwks-url") {
+			if u := setting("jwks_url").GetString("jwks_url"); u != "" {
+				jwksURL = u
+			}
+		}
+
+		scope := strings.Join(scopes, " ")
+		issued, err := requestToken(jwksURL, apiKey, tenantID, roles, scope, ttl)
+		if err != nil {
+			return fmt.Errorf("failed to get token from %s: %w", jwksURL, err)
+		}
+		creds, err := loadCredentials()
+		if err != nil {
+			return err
+		}
+		creds[credentialsKey()] = &loginToken{
+			Token:        issued.Token,
+			ExpiresAt:    issued.ExpiresAt,
+			Tenant:       tenantID,
+			Roles:        roles,
+			Scope:        scope,
+			TTLSeconds:   int(ttl.Seconds()),
+			JWKSURL:      jwksURL,
+			RefreshToken: issued.RefreshToken,
+		}
+		if err := saveCredentials(creds); err != nil {
+			return fmt.Errorf("failed to save token: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(map[string]any{"tenant": tenantID, "profile": credentialsKey(), "expires_at": issued.ExpiresAt.Format(time.RFC3339)})
+			return nil
+		}
+		fmt.Printf("✓ Logged in to tenant %s (profile %s)\n", tenantID, credentialsKey())
+		fmt.Printf("Token expires at %s and is renewed automatically\n", issued.ExpiresAt.Format(time.RFC3339))
+		return nil
+	},
+}
+
+// authStatusCmd represents the auth status command
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the cached login for the active profile",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		creds, err := loadCredentials()
+		if err != nil {
+			return err
+		}
+		login := creds[credentialsKey()]
+		if login == nil {
+			return fmt.Errorf("not logged in for profile %s (run 'harborctl auth login')", credentialsKey())
+		}
+		if outputJSON {
+			printOutput(map[string]any{
+				"profile":    credentialsKey(),
+				"tenant":     login.Tenant,
+				"roles":      login.Roles,
+				"scope":      login.Scope,
+				"jwks_url":   login.JWKSURL,
+				"expires_at": login.ExpiresAt.Format(time.RFC3339),
+				"expired":    time.Now().After(login.ExpiresAt),
+			})
+			return nil
+		}
+		fmt.Printf("Profile: %s\n", credentialsKey())
+		fmt.Printf("Tenant: %s\n", login.Tenant)
+		if len(login.Roles) > 0 {
+			fmt.Printf("Roles: %s\n", strings.Join(login.Roles, ", "))
+		}
+		if login.Scope != "" {
+			fmt.Printf("Scope: %s\n", login.Scope)
+		}
+		fmt.Printf("JWKS server: %s\n", login.JWKSURL)
+		if time.Now().After(login.ExpiresAt) {
+			fmt.Printf("Expired: %s (renewed on the next command)\n", login.ExpiresAt.Format(time.RFC3339))
+		} else {
+			fmt.Printf("Expires: %s (in %s)\n", login.ExpiresAt.Format(time.RFC3339), time.Until(login.ExpiresAt).Round(time.Second))
+		}
+		return nil
+	},
+}
+
+// authTokenCmd represents the auth token command
+var authTokenCmd = &cobra.Command{
+	Use:   "token",
+	Short: "Print the token commands authenticate with",
+	Long: `Print the token other commands would send, renewing a cached login first if it's close to
+expiring. Useful for scripts:
+
+  curl -H "Authorization: Bearer $(harborctl auth token)" ...`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if jwtToken == "" {
+			return errors.New("no token: run 'harborctl auth login' or set --token or JWT_TOKEN")
+		}
+		fmt.Println(jwtToken)
+		return nil
+	},
+}
+
+// authLogoutCmd represents the auth logout command
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the cached login for the active profile",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		creds, err := loadCredentials()
+		if err != nil {
+			return err
+		}
+		if creds[credentialsKey()] == nil {
+			fmt.Printf("Not logged in for profile %s\n", credentialsKey())
+			return nil
+		}
+		delete(creds, credentialsKey())
+		if err := saveCredentials(creds); err != nil {
+			return err
+		}
+		fmt.Printf("✓ Logged out of profile %s\n", credentialsKey())
+		return nil
+	},
+}
+
+// tokenCredentials sends the token with every gRPC call
+type tokenCredentials string
+
+func (t tokenCredentials) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity is false so the token is also sent to plaintext local servers
+func (tokenCredentials) RequireTransportSecurity() bool { return false }
+
+func init() {
+	rootCmd.AddCommand(authCmd)
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authStatusCmd)
+	authCmd.AddCommand(authTokenCmd)
+	authCmd.AddCommand(authLogoutCmd)
+
+	authLoginCmd.Flags().String("tenant", "", "tenant to log in to (default the config's tenant)")
+	authLoginCmd.Flags().String("jwks-url", defaultJWKSURL, "JWKS server to get the token from (or jwks_url in the config)")
+	authLoginCmd.Flags().StringSlice("role", nil, "role to request (viewer, publisher, operator, admin); repeatable, default full access")
+	authLoginCmd.Flags().StringSlice("scope", nil, "scope to request, e.g. harborhook:viewer to grant a role; repeatable")
+	authLoginCmd.Flags().Duration("ttl", time.Hour, "token lifetime")
+	authLoginCmd.Flags().String("api-key", "", "JWKS server API key, required unless it runs in dev mode (default $"+jwksAPIKeyEnv+")")
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// tokenServer is a JWKS server whose /token hands out tok-1, tok-2, ... with refresh-1,
+// refresh-2, ... until failing is set
+type tokenServer struct {
+	*httptest.Server
+	issued  int
+	failing bool
+	last    map[string]any
+	auth    string // the last request's Authorization header
+}
+
+func newTokenServer(t *testing.T) *tokenServer {
+	t.Helper()
+	ts := &tokenServer{}
+	ts.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ts.failing {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		ts.last = nil
+		ts.auth = r.Header.Get("Authorization")
+		_ = json.NewDecoder(r.Body).Decode(&ts.last)
+		ts.issued++
+		_ = json.NewEncoder(w).Encode(map[string]any{"token": fmt.Sprintf("tok-%d", ts.issued), "expires_in": 3600, "token_type": "Bearer", "refresh_token": fmt.Sprintf("refresh-%d", ts.issued)})
+	}))
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// useConfigDir points the user config and home directories at a temp dir for one test
+func useConfigDir(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("HOME", dir)
+}
+
+func TestCachedLoginToken(t *testing.T) {
+	useConfigDir(t)
+	jwks := newTokenServer(t)
+
+	if got := cachedLoginToken(); got != "" {
+		t.Errorf("cachedLoginToken() before login = %q, want none", got)
+	}
+
+	save := func(expiresIn time.Duration) {
+		t.Helper()
+		err := saveCredentials(map[string]*loginToken{"default": {
+			Token: "cached", ExpiresAt: time.Now().Add(expiresIn), Tenant: "tn_1", Roles: []string{"viewer"}, TTLSeconds: 3600, JWKSURL: jwks.URL,
+		}})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	save(time.Hour)
+	if got := cachedLoginToken(); got != "cached" || jwks.issued != 0 {
+		t.Errorf("cachedLoginToken() = %q after %d requests, want the cached token unrefreshed", got, jwks.issued)
+	}
+
+	save(time.Minute)
+	if got := cachedLoginToken(); got != "tok-1" {
+		t.Errorf("cachedLoginToken() near expiry = %q, want a refreshed token", got)
+	}
+	if jwks.last["tenant_id"] != "tn_1" || jwks.last["ttl_seconds"] != float64(3600) {
+		t.Errorf("refresh requested %v, want the login's tenant and ttl", jwks.last)
+	}
+	if creds, _ := loadCredentials(); creds["default"].Token != "tok-1" || time.Until(creds["default"].ExpiresAt) < 50*time.Minute {
+		t.Errorf("saved login = %+v, want the refreshed token and expiry", creds["default"])
+	}
+
+	// A login with a refresh token renews with it
+	creds, _ := loadCredentials()
+	creds["default"].ExpiresAt = time.Now().Add(time.Minute)
+	if err := saveCredentials(creds); err != nil {
+		t.Fatal(err)
+	}
+	if got := cachedLoginToken(); got != "tok-2" || jwks.last["grant_type"] != "refresh_token" || jwks.last["refresh_token"] != "refresh-1" {
+		t.Errorf("cachedLoginToken() with a refresh token = %q after requesting %v, want tok-2 from the refresh grant", got, jwks.last)
+	}
+	if creds, _ := loadCredentials(); creds["default"].RefreshToken != "refresh-2" {
+		t.Errorf("saved refresh token = %q, want the replacement refresh-2", creds["default"].RefreshToken)
+	}
+
+	jwks.failing = true
+	save(time.Minute)
+	if got := cachedLoginToken(); got != "cached" {
+		t.Errorf("cachedLoginToken() with the refresh failing = %q, want the still-valid token", got)
+	}
+	save(-time.Minute)
+	if got := cachedLoginToken(); got != "" {
+		t.Errorf("cachedLoginToken() expired with the refresh failing = %q, want none", got)
+	}
+}
+
+func TestAuthLogin(t *testing.T) {
+	useConfigDir(t)
+	jwks := newTokenServer(t)
+	origToken, origAddr := jwtToken, serverAddr
+	t.Cleanup(func() { jwtToken, serverAddr = origToken, origAddr })
+	t.Setenv("JWT_TOKEN", "")
+	t.Setenv(jwksAPIKeyEnv, "s3cret")
+
+	profileName = "Staging"
+	t.Cleanup(func() {
+		viper.Reset()
+		profileName, profileConfig, defaultTenant = "", nil, ""
+	})
+	if err := os.WriteFile(filepath.Join(os.Getenv("HOME"), ".harborctl.yaml"), []byte("profiles:\n  staging:\n    server: localhost:9443\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd.SetArgs([]string{"auth", "login", "--tenant", "tn_1", "--jwks-url", jwks.URL, "--role", "viewer,publisher"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("auth login: %v", err)
+	}
+	creds, err := loadCredentials()
+	if err != nil {
+		t.Fatal(err)
+	}
+	login := creds["staging"]
+	if login == nil || login.Token != "tok-1" || login.Tenant != "tn_1" || len(login.Roles) != 2 || login.JWKSURL != jwks.URL {
+		t.Fatalf("saved login = %+v, want tok-1 for tn_1 under the staging profile", login)
+	}
+	if jwks.auth != "Bearer s3cret" {
+		t.Errorf("login Authorization = %q, want the API key from %s", jwks.auth, jwksAPIKeyEnv)
+	}
+
+	jwtToken = ""
+	initConfig()
+	if jwtToken != "tok-1" {
+		t.Errorf("token after initConfig = %q, want the cached login", jwtToken)
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/austindbirch/harbor_hook/cmd/harborctl/cmd/ascii"
+	"github.com/austindbirch/harbor_hook/internal/version"
 	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -27,27 +29,31 @@ var configCmd = &cobra.Command{
 var configViewCmd = &cobra.Command{
 	Use:   "view",
 	Short: "View current configuration",
-	Long:  `Display the current configuration settings.`,
+	Long:  `Display the current configuration settings, with the active profile's in place of the top-level ones.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if outputJSON {
 			config := map[string]interface{}{
-				"server":  viper.GetString("server"),
-				"timeout": viper.GetDuration("timeout").String(),
-				"http":    viper.GetBool("http"),
-				"json":    viper.GetBool("json"),
-				"pretty":  viper.GetBool("pretty"),
+				"profile": profileName,
+				"server":  setting("server").GetString("server"),
+				"timeout": setting("timeout").GetDuration("timeout").String(),
+				"http":    setting("http").GetBool("http"),
+				"json":    setting("json").GetBool("json"),
+				"pretty":  setting("pretty").GetBool("pretty"),
+				"tenant":  defaultTenant,
 			}
 			printOutput(config)
 		} else {
 			fmt.Println("Current configuration:")
-			fmt.Printf("  Server: %s\n", viper.GetString("server"))
-			fmt.Printf("  Timeout: %s\n", viper.GetDuration("timeout"))
-			fmt.Printf("  Use HTTP: %v\n", viper.GetBool("http"))
-			fmt.Printf("  JSON Output: %v\n", viper.GetBool("json"))
-			fmt.Printf("  Pretty JSON: %v\n", viper.GetBool("pretty"))
-
-			if viper.GetBool("pretty") && !checkJQAvailable() {
-				fmt.Printf("  ⚠️  Warning: pretty=true but jq not found in PATH\n")
+			if profileName != "" {
+				fmt.Printf("  Profile: %s\n", profileName)
+			}
+			fmt.Printf("  Server: %s\n", setting("server").GetString("server"))
+			fmt.Printf("  Timeout: %s\n", setting("timeout").GetDuration("timeout"))
+			fmt.Printf("  Use HTTP: %v\n", setting("http").GetBool("http"))
+			fmt.Printf("  JSON Output: %v\n", setting("json").GetBool("json"))
+			fmt.Printf("  Pretty JSON: %v\n", setting("pretty").GetBool("pretty"))
+			if defaultTenant != "" {
+				fmt.Printf("  Tenant: %s\n", defaultTenant)
 			}
 
 			if viper.ConfigFileUsed() != "" {
@@ -63,13 +69,17 @@ var configViewCmd = &cobra.Command{
 var configSetCmd = &cobra.Command{
 	Use:   "set [key] [value]",
 	Short: "Set a configuration value",
-	Long: `Set a configuration value and save it to the config file.
+	Long: `Set a configuration value and save it to the config file. With a profile selected
+(--profile or HARBORCTL_PROFILE), the value is saved to that profile.
 	
 Examples:
   harborctl config set server localhost:8080
   harborctl config set timeout 60s
   harborctl config set http true
-  harborctl config set pretty true`,
+  harborctl config set pretty true
+  harborctl --profile prod config set server harborhook.example.com:8443
+  harborctl --profile prod config set tenant tn_123
+  harborctl --profile prod config set tls.ca_file /etc/harborhook/ca.pem`,
 	Args: cobra.ExactArgs(2),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		key := args[0]
@@ -77,43 +87,47 @@ Examples:
 
 		// Validate the key
 		validKeys := map[string]bool{
-			"server":  true,
-			"timeout": true,
-			"http":    true,
-			"json":    true,
-			"pretty":  true,
+			"server":          true,
+			"timeout":         true,
+			"http":            true,
+			"json":            true,
+			"pretty":          true,
+			"token":           true,
+			"tenant":          true,
+			"jwks_url":        true,
+			"tls.ca_file":     true,
+			"tls.client_cert": true,
+			"tls.client_key":  true,
+			"tls.server_name": true,
+			"tls.insecure":    true,
+			"tls.grpc":        true,
 		}
 
 		if !validKeys[key] {
-			return fmt.Errorf("invalid configuration key: %s. Valid keys are: server, timeout, http, json, pretty", key)
-		}
-
-		// Special handling for pretty - warn if jq is not available
-		if key == "pretty" && (value == "true" || value == "1") && !checkJQAvailable() {
-			fmt.Printf("⚠️  Warning: jq not found in PATH. Pretty formatting will fall back to standard formatting.\n")
-			fmt.Printf("To install jq: https://jqlang.github.io/jq/download/\n\n")
+			return fmt.Errorf("invalid configuration key: %s. Valid keys are: server, timeout, http, json, pretty, token, tenant, jwks_url, tls.ca_file, tls.client_cert, tls.client_key, tls.server_name, tls.insecure, tls.grpc", key)
 		}
+		target := profileKey(key)
 
 		// Handle boolean values properly
 		switch key {
-		case "http", "json", "pretty":
+		case "http", "json", "pretty", "tls.insecure", "tls.grpc":
 			switch value {
 			case "true", "1", "yes", "on":
-				viper.Set(key, true)
+				viper.Set(target, true)
 			case "false", "0", "no", "off":
-				viper.Set(key, false)
+				viper.Set(target, false)
 			default:
 				return fmt.Errorf("invalid boolean value for %s: %s (use true/false)", key, value)
 			}
 		case "timeout":
 			// Parse duration
 			if dur, err := time.ParseDuration(value); err == nil {
-				viper.Set(key, dur)
+				viper.Set(target, dur)
 			} else {
-				viper.Set(key, value)
+				viper.Set(target, value)
 			}
 		default:
-			viper.Set(key, value)
+			viper.Set(target, value)
 		}
 
 		// Ensure config directory exists
@@ -128,7 +142,11 @@ Examples:
 			return fmt.Errorf("failed to write config file: %w", err)
 		}
 
-		fmt.Printf("Set %s = %s\n", key, value)
+		if profileName != "" {
+			fmt.Printf("Set %s = %s (profile %s)\n", key, value, profileName)
+		} else {
+			fmt.Printf("Set %s = %s\n", key, value)
+		}
 		fmt.Printf("Configuration saved to: %s\n", configPath)
 
 		return nil
@@ -182,11 +200,11 @@ var configInitCmd = &cobra.Command{
 // configCheckCmd represents the config check command
 var configCheckCmd = &cobra.Command{
 	Use:   "check",
-	Short: "Check configuration and dependencies",
-	Long:  `Check the current configuration and verify that dependencies like jq are available.`,
+	Short: "Check configuration and connectivity",
+	Long:  `Check the current configuration and verify that the server is reachable.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Configuration check:")
-		fmt.Printf("  ✅ harborctl version: %s\n", Version)
+		fmt.Printf("  ✅ harborctl version: %s\n", version.Version)
 
 		if viper.ConfigFileUsed() != "" {
 			fmt.Printf("  ✅ Config file: %s\n", viper.ConfigFileUsed())
@@ -194,18 +212,7 @@ var configCheckCmd = &cobra.Command{
 			fmt.Printf("  ⚠️  Config file: not found (using defaults)\n")
 		}
 
-		if checkJQAvailable() {
-			fmt.Printf("  ✅ jq: available\n")
-		} else {
-			fmt.Printf("  ❌ jq: not found in PATH\n")
-			fmt.Printf("     Install from: https://jqlang.github.io/jq/download/\n")
-		}
-
-		fmt.Printf("  ✅ Server: %s\n", viper.GetString("server"))
-
-		if viper.GetBool("pretty") && !checkJQAvailable() {
-			fmt.Printf("  ⚠️  Pretty formatting enabled but jq not available\n")
-		}
+		fmt.Printf("  ✅ Server: %s\n", serverAddr)
 
 		fmt.Println("\nTesting server connectivity...")
 		if err := func() error {
@@ -226,12 +233,43 @@ var configCheckCmd = &cobra.Command{
 	},
 }
 
+// configProfilesCmd represents the config profiles command
+var configProfilesCmd = &cobra.Command{
+	Use:   "profiles",
+	Short: "List configuration profiles",
+	Long: `List the profiles in the config file. Select one with --profile or HARBORCTL_PROFILE;
+its settings take the place of the top-level ones.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		names := profileNames()
+		if outputJSON {
+			printOutput(map[string]interface{}{"profiles": names, "active": profileName})
+			return
+		}
+		if len(names) == 0 {
+			fmt.Printf("No profiles in %s\n", configFileName())
+			return
+		}
+		for _, name := range names {
+			marker := " "
+			if strings.EqualFold(name, profileName) {
+				marker = "*"
+			}
+			p := viper.Sub("profiles." + name)
+			if p == nil {
+				p = viper.New()
+			}
+			fmt.Printf("%s %-12s server=%s tenant=%s\n", marker, name, p.GetString("server"), p.GetString("tenant"))
+		}
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
 	configCmd.AddCommand(configViewCmd)
 	configCmd.AddCommand(configSetCmd)
 	configCmd.AddCommand(configInitCmd)
 	configCmd.AddCommand(configCheckCmd)
+	configCmd.AddCommand(configProfilesCmd)
 
 	// Flags for init command
 	configInitCmd.Flags().Bool("force", false, "overwrite existing config file")
@@ -5,17 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/austindbirch/harbor_hook/cmd/harborctl/cmd/ascii"
 	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/structpb"
 )
 
 // deliveryCmd represents the delivery command
 var deliveryCmd = &cobra.Command{
-	Use:   "delivery",
-	Short: "Manage webhook deliveries",
-	Long:  `Check delivery status, replay deliveries, and manage the dead letter queue.`,
+	Use:     "delivery",
+	Aliases: []string{"deliveries"},
+	Short:   "Manage webhook deliveries",
+	Long:    `Check delivery status, replay deliveries, and manage the dead letter queue.`,
 	Annotations: map[string]string{
 		ascii.AnnotationKey: ascii.Delivery,
 	},
@@ -37,6 +42,7 @@ Example:
 		fromStr, _ := cmd.Flags().GetString("from")
 		toStr, _ := cmd.Flags().GetString("to")
 		limitStr, _ := cmd.Flags().GetString("limit")
+		chains, _ := cmd.Flags().GetBool("chains")
 
 		// Parse optional parameters
 		from, err := parseTimestamp(fromStr)
@@ -69,6 +75,9 @@ Example:
 			if limitStr != "" {
 				params.Add("limit", limitStr)
 			}
+			if chains {
+				params.Add("includeReplayChains", "true")
+			}
 
 			path := fmt.Sprintf("/v1/events/%s/deliveries", eventID)
 			if len(params) > 0 {
@@ -107,6 +116,8 @@ Example:
 			From:       from,
 			To:         to,
 			Limit:      limit,
+
+			IncludeReplayChains: chains,
 		}
 
 		resp, err := client.GetDeliveryStatus(ctx, req)
@@ -143,12 +154,46 @@ Example:
 				if attempt.DeliveredAt != nil {
 					fmt.Printf("    Delivered: %s\n", attempt.DeliveredAt.AsTime().Format("2006-01-02 15:04:05"))
 				}
+				if attempt.AckedAt != nil {
+					fmt.Printf("    Acknowledged: %s\n", attempt.AckedAt.AsTime().Format("2006-01-02 15:04:05"))
+				}
 				if attempt.FailedAt != nil {
 					fmt.Printf("    Failed: %s\n", attempt.FailedAt.AsTime().Format("2006-01-02 15:04:05"))
 				}
 				if attempt.DlqAt != nil {
 					fmt.Printf("    Dead Lettered: %s\n", attempt.DlqAt.AsTime().Format("2006-01-02 15:04:05"))
 				}
+				if len(attempt.History) > 0 {
+					fmt.Printf("    Tries:\n")
+					for _, try := range attempt.History {
+						fmt.Printf("      #%d %s %s", try.Attempt, try.FinishedAt.AsTime().Format("2006-01-02 15:04:05"), try.Outcome)
+						if try.HttpStatus > 0 {
+							fmt.Printf(" HTTP %d", try.HttpStatus)
+						}
+						fmt.Printf(" in %dms", try.LatencyMs)
+						if try.Error != "" {
+							fmt.Printf(": %s", try.Error)
+						}
+						fmt.Println()
+						if try.ResponseBody != "" {
+							more := ""
+							if try.ResponseTruncated {
+								more = " ..."
+							}
+							fmt.Printf("         Response: %s%s\n", try.ResponseBody, more)
+						}
+					}
+				}
+			}
+
+			for _, chain := range resp.ReplayChains {
+				fmt.Printf("\n  Replay chain from %s:\n", chain.RootDeliveryId)
+				for _, attempt := range chain.Attempts {
+					fmt.Printf("    %s%s %s\n", strings.Repeat("  ", int(attempt.ReplayDepth)), attempt.DeliveryId, attempt.Status.String())
+				}
+				if chain.ActiveDeliveryId != "" {
+					fmt.Printf("    Active replay: %s\n", chain.ActiveDeliveryId)
+				}
 			}
 		}
 
@@ -161,19 +206,47 @@ var replayCmd = &cobra.Command{
 	Use:   "replay [delivery-id]",
 	Short: "Replay a failed delivery",
 	Long: `Replay a specific delivery attempt by creating a new delivery task.
+
+The replay is sent with a new idempotency key, so receivers that dedupe on it
+process it again. Use --same-key to keep the original delivery's key.
+
+--url sends the replay somewhere other than the endpoint's URL, and --patch
+applies a JSON merge patch to the payload; both are recorded on the replay.
 	
 Example:
-  harborctl delivery replay del_456 --reason "endpoint was down"`,
+  harborctl delivery replay del_456 --reason "endpoint was down"
+  harborctl delivery replay del_456 --same-key
+  harborctl delivery replay del_456 --url https://staging.example.com/webhook --patch '{"test": true}'`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		deliveryID := args[0]
 		reason, _ := cmd.Flags().GetString("reason")
+		sameKey, _ := cmd.Flags().GetBool("same-key")
+		urlOverride, _ := cmd.Flags().GetString("url")
+		patchJSON, _ := cmd.Flags().GetString("patch")
+
+		var patch *structpb.Struct
+		if patchJSON != "" {
+			var err error
+			if patch, err = parseJSON(patchJSON); err != nil {
+				return fmt.Errorf("invalid patch JSON: %w", err)
+			}
+		}
 
 		if useHTTP {
 			payload := map[string]interface{}{}
 			if reason != "" {
 				payload["reason"] = reason
 			}
+			if sameKey {
+				payload["sameKey"] = true
+			}
+			if urlOverride != "" {
+				payload["urlOverride"] = urlOverride
+			}
+			if patch != nil {
+				payload["payloadPatch"] = patch.AsMap()
+			}
 
 			resp, err := makeHTTPRequest("POST", fmt.Sprintf("/v1/deliveries/%s:replay", deliveryID), payload)
 			if err != nil {
@@ -202,8 +275,11 @@ Example:
 
 		ctx := context.Background()
 		req := &webhookv1.ReplayDeliveryRequest{
-			DeliveryId: deliveryID,
-			Reason:     reason,
+			DeliveryId:   deliveryID,
+			Reason:       reason,
+			SameKey:      sameKey,
+			UrlOverride:  urlOverride,
+			PayloadPatch: patch,
 		}
 
 		resp, err := client.ReplayDelivery(ctx, req)
@@ -214,7 +290,11 @@ Example:
 		if outputJSON {
 			printOutput(resp)
 		} else {
-			fmt.Printf("Replayed delivery: %s\n", resp.NewAttempt.DeliveryId)
+			if resp.Deduplicated {
+				fmt.Printf("Replay already in progress: %s\n", resp.NewAttempt.DeliveryId)
+			} else {
+				fmt.Printf("Replayed delivery: %s\n", resp.NewAttempt.DeliveryId)
+			}
 			fmt.Printf("  Event ID: %s\n", resp.NewAttempt.EventId)
 			fmt.Printf("  Endpoint ID: %s\n", resp.NewAttempt.EndpointId)
 			fmt.Printf("  Status: %s\n", resp.NewAttempt.Status.String())
@@ -225,15 +305,143 @@ Example:
 	},
 }
 
+// replayDLQCmd represents the replay-dlq command
+var replayDLQCmd = &cobra.Command{
+	Use:   "replay-dlq",
+	Short: "Replay dead-lettered deliveries in bulk",
+	Long: `Replay every dead-lettered delivery matching the filters, oldest first.
+
+Deliveries that already have a pending or successful replay are skipped.
+Use --dry-run to see how many deliveries would be replayed, and --same-key to
+keep each delivery's idempotency key instead of sending new ones.
+
+Example:
+  harborctl delivery replay-dlq --endpoint-id ep_456 --dry-run
+  harborctl delivery replay-dlq --event-type appointment.created --from 2025-01-01T00:00:00Z --max 500`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endpointID, _ := cmd.Flags().GetString("endpoint-id")
+		tenantID, _ := cmd.Flags().GetString("tenant-id")
+		eventType, _ := cmd.Flags().GetString("event-type")
+		fromStr, _ := cmd.Flags().GetString("from")
+		toStr, _ := cmd.Flags().GetString("to")
+		maxStr, _ := cmd.Flags().GetString("max")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		reason, _ := cmd.Flags().GetString("reason")
+		sameKey, _ := cmd.Flags().GetBool("same-key")
+
+		maxCount, err := parseInt32(maxStr)
+		if err != nil {
+			return fmt.Errorf("invalid max: %w", err)
+		}
+		from, err := parseTimestamp(fromStr)
+		if err != nil {
+			return fmt.Errorf("invalid 'from' timestamp: %w", err)
+		}
+		to, err := parseTimestamp(toStr)
+		if err != nil {
+			return fmt.Errorf("invalid 'to' timestamp: %w", err)
+		}
+
+		if useHTTP {
+			payload := map[string]interface{}{
+				"maxCount": maxCount,
+				"dryRun":   dryRun,
+				"sameKey":  sameKey,
+			}
+			for k, v := range map[string]string{
+				"endpointId": endpointID,
+				"tenantId":   tenantID,
+				"eventType":  eventType,
+				"from":       fromStr,
+				"to":         toStr,
+				"reason":     reason,
+			} {
+				if v != "" {
+					payload[k] = v
+				}
+			}
+
+			resp, err := makeHTTPRequest("POST", "/v1/dlq:replay", payload)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.ReplayDLQ(context.Background(), &webhookv1.ReplayDLQRequest{
+			EndpointId: endpointID,
+			TenantId:   tenantID,
+			EventType:  eventType,
+			From:       from,
+			To:         to,
+			MaxCount:   maxCount,
+			DryRun:     dryRun,
+			Reason:     reason,
+			SameKey:    sameKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replay DLQ: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else if resp.DryRun {
+			fmt.Printf("Dry run: %d dead deliveries match, %d would be replayed\n", resp.MatchedCount, resp.ReplayedCount)
+		} else {
+			fmt.Printf("Replayed %d of %d matching dead deliveries\n", resp.ReplayedCount, resp.MatchedCount)
+			for _, attempt := range resp.Replayed {
+				fmt.Printf("  %s (replay of %s)\n", attempt.DeliveryId, attempt.ReplayOf)
+			}
+		}
+
+		return nil
+	},
+}
+
+// dlqFilters holds the dlq command's filter flags
+type dlqFilters struct {
+	endpointID, tenantID, from, to, pageToken, limit string
+}
+
 // dlqHTTPRequest handles HTTP requests for DLQ operations
-func dlqHTTPRequest(endpointID, limitStr string) error {
+func dlqHTTPRequest(f dlqFilters) error {
 	// Build query parameters for HTTP request
 	params := url.Values{}
-	if endpointID != "" {
-		params.Add("endpointId", endpointID)
+	if f.endpointID != "" {
+		params.Add("endpointId", f.endpointID)
+	}
+	if f.tenantID != "" {
+		params.Add("tenantId", f.tenantID)
+	}
+	if f.from != "" {
+		params.Add("from", f.from)
+	}
+	if f.to != "" {
+		params.Add("to", f.to)
 	}
-	if limitStr != "" {
-		params.Add("limit", limitStr)
+	if f.pageToken != "" {
+		params.Add("pageToken", f.pageToken)
+	}
+	if f.limit != "" {
+		params.Add("limit", f.limit)
 	}
 
 	path := "/v1/dlq"
@@ -266,46 +474,67 @@ var dlqCmd = &cobra.Command{
 	Short: "List dead letter queue entries",
 	Long: `List all delivery attempts currently in the dead letter queue.
 	
+Results are newest first. Use --page-token with the token printed at the
+end of a page to fetch the next one.
+
 Example:
-  harborctl delivery dlq --limit 20`,
+  harborctl delivery dlq --limit 20
+  harborctl delivery dlq --tenant-id tn_123 --from 2025-01-01T00:00:00Z --page-token <token>`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		endpointID, _ := cmd.Flags().GetString("endpoint-id")
-		limitStr, _ := cmd.Flags().GetString("limit")
-
-		limit, err := parseInt32(limitStr)
+		var f dlqFilters
+		f.endpointID, _ = cmd.Flags().GetString("endpoint-id")
+		f.tenantID, _ = cmd.Flags().GetString("tenant-id")
+		f.from, _ = cmd.Flags().GetString("from")
+		f.to, _ = cmd.Flags().GetString("to")
+		f.pageToken, _ = cmd.Flags().GetString("page-token")
+		f.limit, _ = cmd.Flags().GetString("limit")
+
+		limit, err := parseInt32(f.limit)
 		if err != nil {
 			return fmt.Errorf("invalid limit: %w", err)
 		}
+		from, err := parseTimestamp(f.from)
+		if err != nil {
+			return fmt.Errorf("invalid 'from' timestamp: %w", err)
+		}
+		to, err := parseTimestamp(f.to)
+		if err != nil {
+			return fmt.Errorf("invalid 'to' timestamp: %w", err)
+		}
 
 		// Try HTTP first if explicitly requested
 		if useHTTP {
-			return dlqHTTPRequest(endpointID, limitStr)
+			return dlqHTTPRequest(f)
 		}
 
 		// Try gRPC first, fallback to HTTP on failure
 		client, cleanup, err := getClient()
 		if err != nil {
 			// gRPC failed, try HTTP fallback
-			return dlqHTTPRequest(endpointID, limitStr)
+			return dlqHTTPRequest(f)
 		}
 		defer cleanup()
 
 		ctx := context.Background()
 		req := &webhookv1.ListDLQRequest{
-			EndpointId: endpointID,
+			EndpointId: f.endpointID,
+			TenantId:   f.tenantID,
+			From:       from,
+			To:         to,
+			PageToken:  f.pageToken,
 			Limit:      limit,
 		}
 
 		resp, err := client.ListDLQ(ctx, req)
 		if err != nil {
 			// gRPC call failed, try HTTP fallback
-			return dlqHTTPRequest(endpointID, limitStr)
+			return dlqHTTPRequest(f)
 		}
 
 		if outputJSON {
 			printOutput(resp)
 		} else {
-			fmt.Println("Dead Letter Queue entries:")
+			fmt.Printf("Dead Letter Queue entries (%d total):\n", resp.TotalCount)
 			if len(resp.Dead) == 0 {
 				fmt.Println("  No entries found")
 				return nil
@@ -327,28 +556,246 @@ Example:
 					fmt.Printf("    Dead Lettered: %s\n", attempt.DlqAt.AsTime().Format("2006-01-02 15:04:05"))
 				}
 			}
+			if resp.NextPageToken != "" {
+				fmt.Printf("\nMore entries available: --page-token %s\n", resp.NextPageToken)
+			}
 		}
 
 		return nil
 	},
 }
 
+// backlogCmd represents the backlog command
+var backlogCmd = &cobra.Command{
+	Use:   "backlog",
+	Short: "Estimate when pending deliveries will clear",
+	Long: `Estimate when the pending deliveries for a tenant or endpoint will clear, from
+queue depth, recent throughput, rate limits, freezes and the kill switch.
+
+Example:
+  harborctl delivery backlog --tenant-id tn_123
+  harborctl delivery backlog --endpoint-id ep_456 --window 15m`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID, endpointID := adminTargetFlags(cmd)
+		window, _ := cmd.Flags().GetDuration("window")
+		windowSeconds := int32(window.Seconds())
+
+		if useHTTP {
+			params := url.Values{}
+			if tenantID != "" {
+				params.Add("tenantId", tenantID)
+			}
+			if endpointID != "" {
+				params.Add("endpointId", endpointID)
+			}
+			if windowSeconds > 0 {
+				params.Add("windowSeconds", strconv.Itoa(int(windowSeconds)))
+			}
+
+			resp, err := makeHTTPRequest("GET", "/v1/backlog/estimate?"+params.Encode(), nil)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.GetBacklogEstimate(context.Background(), &webhookv1.GetBacklogEstimateRequest{
+			TenantId:      tenantID,
+			EndpointId:    endpointID,
+			WindowSeconds: windowSeconds,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to estimate backlog: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Backlog (throughput over the last %ds):\n", resp.WindowSeconds)
+			printBacklogEstimate("Total", resp.Total)
+			if len(resp.Endpoints) > 1 {
+				for _, ep := range resp.Endpoints {
+					printBacklogEstimate(ep.EndpointId, ep)
+				}
+			}
+		}
+		return nil
+	},
+}
+
+// printBacklogEstimate prints one line of a backlog estimate
+func printBacklogEstimate(label string, e *webhookv1.BacklogEstimate) {
+	eta := "not draining (" + e.BlockedReason + ")"
+	switch {
+	case e.PendingCount == 0:
+		eta = "clear"
+	case e.HasEta:
+		eta = fmt.Sprintf("clears in %s (~%s)", time.Duration(e.EtaSeconds)*time.Second,
+			e.ClearsAt.AsTime().Local().Format("15:04:05"))
+	}
+	fmt.Printf("  %s: %d pending, %d parked, %.2f/s, %s\n", label, e.PendingCount, e.ParkedCount, e.ThroughputPerSec, eta)
+}
+
+// failuresCmd represents the failures command
+var failuresCmd = &cobra.Command{
+	Use:   "failures [tenant-id]",
+	Short: "Show failed deliveries over time by reason and endpoint",
+	Long: `Show a tenant's failed deliveries in time buckets, broken down by failure reason
+(http_503, timeout, connection_refused, tls, ...) and endpoint.
+
+Example:
+  harborctl delivery failures tn_123
+  harborctl delivery failures tn_123 --window 24h --bucket 1h --endpoint-id ep_456`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID := args[0]
+		endpointID, _ := cmd.Flags().GetString("endpoint-id")
+		window, _ := cmd.Flags().GetDuration("window")
+		bucket, _ := cmd.Flags().GetDuration("bucket")
+
+		if useHTTP {
+			params := url.Values{}
+			if endpointID != "" {
+				params.Add("endpointId", endpointID)
+			}
+			if window > 0 {
+				params.Add("windowSeconds", strconv.Itoa(int(window.Seconds())))
+			}
+			if bucket > 0 {
+				params.Add("bucketSeconds", strconv.Itoa(int(bucket.Seconds())))
+			}
+
+			resp, err := makeHTTPRequest("GET", fmt.Sprintf("/v1/tenants/%s/analytics/failures?%s", tenantID, params.Encode()), nil)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.GetFailureTrends(context.Background(), &webhookv1.GetFailureTrendsRequest{
+			TenantId:      tenantID,
+			EndpointId:    endpointID,
+			WindowSeconds: int32(window.Seconds()),
+			BucketSeconds: int32(bucket.Seconds()),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get failure trends: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+			return nil
+		}
+
+		fmt.Printf("Failures over the last %s in %s buckets:\n",
+			time.Duration(resp.WindowSeconds)*time.Second, time.Duration(resp.BucketSeconds)*time.Second)
+		if len(resp.Totals) == 0 {
+			fmt.Println("  none")
+			return nil
+		}
+		for _, b := range resp.Buckets {
+			if len(b.Failures) == 0 {
+				continue
+			}
+			fmt.Printf("  %s\n", b.Start.AsTime().Local().Format("2006-01-02 15:04"))
+			for _, f := range b.Failures {
+				fmt.Printf("    %-20s %6d  %s\n", f.Reason, f.Count, f.EndpointId)
+			}
+		}
+		fmt.Println("Totals:")
+		for _, f := range resp.Totals {
+			fmt.Printf("  %-20s %6d  %s\n", f.Reason, f.Count, f.EndpointId)
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(deliveryCmd)
+	deliveryCmd.AddCommand(backlogCmd)
+	deliveryCmd.AddCommand(failuresCmd)
 	deliveryCmd.AddCommand(statusCmd)
 	deliveryCmd.AddCommand(replayCmd)
 	deliveryCmd.AddCommand(dlqCmd)
+	deliveryCmd.AddCommand(replayDLQCmd)
 
 	// Flags for status command
 	statusCmd.Flags().String("endpoint-id", "", "filter by endpoint ID")
 	statusCmd.Flags().String("from", "", "start time (RFC3339 format)")
 	statusCmd.Flags().String("to", "", "end time (RFC3339 format)")
 	statusCmd.Flags().String("limit", "10", "maximum number of results")
+	statusCmd.Flags().Bool("chains", false, "group attempts into replay chains")
 
 	// Flags for replay command
 	replayCmd.Flags().String("reason", "", "reason for replaying the delivery")
+	replayCmd.Flags().Bool("same-key", false, "keep the delivery's idempotency key instead of sending a new one")
+	replayCmd.Flags().String("url", "", "send the replay to this URL instead of the endpoint's")
+	replayCmd.Flags().String("patch", "", "JSON merge patch applied to the payload (a null removes a field)")
 
 	// Flags for dlq command
 	dlqCmd.Flags().String("endpoint-id", "", "filter by endpoint ID")
+	dlqCmd.Flags().String("tenant-id", "", "filter by tenant ID (defaults to the token's tenant)")
+	dlqCmd.Flags().String("from", "", "only entries dead-lettered at or after this time (RFC3339 format)")
+	dlqCmd.Flags().String("to", "", "only entries dead-lettered before this time (RFC3339 format)")
+	dlqCmd.Flags().String("page-token", "", "page token from a previous result")
 	dlqCmd.Flags().String("limit", "10", "maximum number of results")
+
+	// Flags for backlog command
+	backlogCmd.Flags().String("tenant-id", "", "estimate every endpoint of a tenant")
+	backlogCmd.Flags().String("endpoint-id", "", "estimate a single endpoint")
+	backlogCmd.MarkFlagsMutuallyExclusive("tenant-id", "endpoint-id")
+	backlogCmd.MarkFlagsOneRequired("tenant-id", "endpoint-id")
+	backlogCmd.Flags().Duration("window", 0, "how far back to measure throughput (default 5m)")
+
+	// Flags for failures command
+	failuresCmd.Flags().String("endpoint-id", "", "only failures for this endpoint")
+	failuresCmd.Flags().Duration("window", 0, "how far back to look (default 6h, max 168h)")
+	failuresCmd.Flags().Duration("bucket", 0, "bucket width (default 15m, min 1m)")
+
+	// Flags for replay-dlq command
+	replayDLQCmd.Flags().String("endpoint-id", "", "only replay deliveries to this endpoint")
+	replayDLQCmd.Flags().String("tenant-id", "", "only replay deliveries for this tenant (defaults to the token's tenant)")
+	replayDLQCmd.Flags().String("event-type", "", "only replay deliveries of this event type")
+	replayDLQCmd.Flags().String("from", "", "only entries dead-lettered at or after this time (RFC3339 format)")
+	replayDLQCmd.Flags().String("to", "", "only entries dead-lettered before this time (RFC3339 format)")
+	replayDLQCmd.Flags().String("max", "100", "maximum number of deliveries to replay (max 1000)")
+	replayDLQCmd.Flags().Bool("dry-run", false, "only count what would be replayed")
+	replayDLQCmd.Flags().String("reason", "", "reason recorded on every replay")
+	replayDLQCmd.Flags().Bool("same-key", false, "keep each delivery's idempotency key instead of sending new ones")
 }
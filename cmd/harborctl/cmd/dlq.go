@@ -0,0 +1,611 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/cmd/harborctl/cmd/ascii"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// dlqRootCmd represents the dlq command
+var dlqRootCmd = &cobra.Command{
+	Use:   "dlq",
+	Short: "Manage the dead letter queue",
+	Long:  `List, inspect, replay and purge dead-lettered deliveries, and set how long they are kept.`,
+	Annotations: map[string]string{
+		ascii.AnnotationKey: ascii.Delivery,
+	},
+}
+
+// dlqListCmd represents the dlq list command
+var dlqListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List dead-lettered deliveries",
+	Long: `List deliveries in the dead letter queue, newest first.
+
+Use --page-token with the token printed at the end of a page to fetch the next one.
+
+Example:
+  harborctl dlq list --since 24h
+  harborctl dlq list --endpoint ep_456 --limit 50`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endpointID, _ := cmd.Flags().GetString("endpoint")
+		tenantID, _ := cmd.Flags().GetString("tenant-id")
+		pageToken, _ := cmd.Flags().GetString("page-token")
+		limitStr, _ := cmd.Flags().GetString("limit")
+
+		limit, err := parseInt32(limitStr)
+		if err != nil {
+			return fmt.Errorf("invalid limit: %w", err)
+		}
+		from, err := dlqSince(cmd)
+		if err != nil {
+			return err
+		}
+
+		if useHTTP {
+			params := url.Values{}
+			if endpointID != "" {
+				params.Add("endpointId", endpointID)
+			}
+			if tenantID != "" {
+				params.Add("tenantId", tenantID)
+			}
+			if from != nil {
+				params.Add("from", from.AsTime().Format(time.RFC3339))
+			}
+			if pageToken != "" {
+				params.Add("pageToken", pageToken)
+			}
+			if limitStr != "" {
+				params.Add("limit", limitStr)
+			}
+			return dlqPrintHTTP("GET", "/v1/dlq?"+params.Encode(), nil)
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.ListDLQ(context.Background(), &webhookv1.ListDLQRequest{
+			EndpointId: endpointID,
+			TenantId:   tenantID,
+			From:       from,
+			PageToken:  pageToken,
+			Limit:      limit,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list DLQ: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+			return nil
+		}
+
+		fmt.Printf("Dead Letter Queue entries (%d total):\n", resp.TotalCount)
+		if len(resp.Dead) == 0 {
+			fmt.Println("  No entries found")
+			return nil
+		}
+		for _, attempt := range resp.Dead {
+			dlqAt := ""
+			if attempt.DlqAt != nil {
+				dlqAt = attempt.DlqAt.AsTime().Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("  %s  %s  endpoint %s  %s\n", dlqAt, attempt.DeliveryId, attempt.EndpointId, attempt.ErrorReason)
+		}
+		if resp.NextPageToken != "" {
+			fmt.Printf("\nMore entries available: --page-token %s\n", resp.NextPageToken)
+		}
+		return nil
+	},
+}
+
+// dlqInspectCmd represents the dlq inspect command
+var dlqInspectCmd = &cobra.Command{
+	Use:   "inspect [delivery-id]",
+	Short: "Show a dead-lettered delivery and its attempt history",
+	Long: `Show a dead-lettered delivery with its attempt count, last error and why it was
+dead-lettered, followed by every delivery in its replay chain.
+
+Example:
+  harborctl dlq inspect del_456`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		deliveryID := args[0]
+		tenantID, _ := cmd.Flags().GetString("tenant-id")
+
+		if useHTTP {
+			path := "/v1/dlq/" + deliveryID
+			if tenantID != "" {
+				path += "?tenantId=" + url.QueryEscape(tenantID)
+			}
+			return dlqPrintHTTP("GET", path, nil)
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.GetDLQEntry(context.Background(), &webhookv1.GetDLQEntryRequest{
+			DeliveryId: deliveryID,
+			TenantId:   tenantID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to inspect DLQ entry: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+			return nil
+		}
+
+		a := resp.Entry.Attempt
+		fmt.Printf("Delivery %s\n", a.DeliveryId)
+		fmt.Printf("  Tenant ID: %s\n", resp.TenantId)
+		fmt.Printf("  Event ID: %s (%s)\n", a.EventId, resp.EventType)
+		fmt.Printf("  Endpoint ID: %s\n", a.EndpointId)
+		fmt.Printf("  Status: %s\n", a.Status.String())
+		fmt.Printf("  Attempts: %d\n", resp.Entry.AttemptCount)
+		if a.HttpStatus > 0 {
+			fmt.Printf("  Last HTTP Status: %d\n", a.HttpStatus)
+		}
+		if a.ErrorReason != "" {
+			fmt.Printf("  Last Error: %s\n", a.ErrorReason)
+		}
+		if resp.Entry.Reason != "" {
+			fmt.Printf("  DLQ Reason: %s\n", resp.Entry.Reason)
+		}
+		if a.DlqAt != nil {
+			fmt.Printf("  Dead Lettered: %s\n", a.DlqAt.AsTime().Local().Format("2006-01-02 15:04:05"))
+		}
+
+		fmt.Printf("\nHistory (replay chain from %s):\n", a.RootDeliveryId)
+		for _, e := range resp.History {
+			h := e.Attempt
+			marker := " "
+			if h.DeliveryId == a.DeliveryId {
+				marker = "*"
+			}
+			enqueued := ""
+			if h.EnqueuedAt != nil {
+				enqueued = h.EnqueuedAt.AsTime().Local().Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf(" %s %s%s  %s  %s  attempts=%d", marker, strings.Repeat("  ", int(h.ReplayDepth)), h.DeliveryId,
+				enqueued, h.Status.String(), e.AttemptCount)
+			if h.HttpStatus > 0 {
+				fmt.Printf("  http=%d", h.HttpStatus)
+			}
+			if h.ErrorReason != "" {
+				fmt.Printf("  %s", h.ErrorReason)
+			}
+			fmt.Println()
+		}
+		return nil
+	},
+}
+
+// dlqReplayCmd represents the dlq replay command
+var dlqReplayCmd = &cobra.Command{
+	Use:   "replay [delivery-id]",
+	Short: "Replay one dead-lettered delivery, or every one matching the filters",
+	Long: `Replay a single dead-lettered delivery, or with no delivery ID replay every
+dead-lettered delivery matching the filters, oldest first.
+
+Deliveries that already have a pending or successful replay are skipped in bulk
+replays. Use --dry-run to see how many deliveries would be replayed. Replays are
+sent with new idempotency keys unless --same-key is set.
+
+Example:
+  harborctl dlq replay del_456 --reason "endpoint fixed"
+  harborctl dlq replay --endpoint ep_456 --since 6h --dry-run
+  harborctl dlq replay --event-type appointment.created --max 500`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reason, _ := cmd.Flags().GetString("reason")
+
+		if len(args) == 1 {
+			if cmd.Flags().Changed("endpoint") || cmd.Flags().Changed("event-type") || cmd.Flags().Changed("since") ||
+				cmd.Flags().Changed("max") || cmd.Flags().Changed("dry-run") {
+				return fmt.Errorf("filters only apply to bulk replays; drop the delivery ID or the filters")
+			}
+			return replayCmd.RunE(cmd, args)
+		}
+
+		endpointID, _ := cmd.Flags().GetString("endpoint")
+		tenantID, _ := cmd.Flags().GetString("tenant-id")
+		eventType, _ := cmd.Flags().GetString("event-type")
+		maxStr, _ := cmd.Flags().GetString("max")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		sameKey, _ := cmd.Flags().GetBool("same-key")
+
+		maxCount, err := parseInt32(maxStr)
+		if err != nil {
+			return fmt.Errorf("invalid max: %w", err)
+		}
+		from, err := dlqSince(cmd)
+		if err != nil {
+			return err
+		}
+
+		if useHTTP {
+			payload := map[string]interface{}{
+				"maxCount": maxCount,
+				"dryRun":   dryRun,
+				"sameKey":  sameKey,
+			}
+			for k, v := range map[string]string{
+				"endpointId": endpointID,
+				"tenantId":   tenantID,
+				"eventType":  eventType,
+				"reason":     reason,
+			} {
+				if v != "" {
+					payload[k] = v
+				}
+			}
+			if from != nil {
+				payload["from"] = from.AsTime().Format(time.RFC3339)
+			}
+			return dlqPrintHTTP("POST", "/v1/dlq:replay", payload)
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.ReplayDLQ(context.Background(), &webhookv1.ReplayDLQRequest{
+			EndpointId: endpointID,
+			TenantId:   tenantID,
+			EventType:  eventType,
+			From:       from,
+			MaxCount:   maxCount,
+			DryRun:     dryRun,
+			Reason:     reason,
+			SameKey:    sameKey,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to replay DLQ: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else if resp.DryRun {
+			fmt.Printf("Dry run: %d dead deliveries match, %d would be replayed\n", resp.MatchedCount, resp.ReplayedCount)
+		} else {
+			fmt.Printf("Replayed %d of %d matching dead deliveries\n", resp.ReplayedCount, resp.MatchedCount)
+			for _, attempt := range resp.Replayed {
+				fmt.Printf("  %s (replay of %s)\n", attempt.DeliveryId, attempt.ReplayOf)
+			}
+		}
+		return nil
+	},
+}
+
+// dlqPurgeCmd represents the dlq purge command
+var dlqPurgeCmd = &cobra.Command{
+	Use:   "purge [delivery-id]",
+	Short: "Remove entries from the dead letter queue",
+	Long: `Remove dead letter queue entries matching the filters, or the single entry for a
+delivery ID. The deliveries stay in their event's history but can no longer be
+listed or bulk-replayed from the DLQ.
+
+The matching entries are counted first and you are asked to confirm. Use --yes to
+skip the prompt and --all to purge every entry when no filter is set. --archive
+copies the entries to the DLQ archive table before removing them.
+
+Example:
+  harborctl dlq purge del_456
+  harborctl dlq purge --endpoint ep_456 --since 168h
+  harborctl dlq purge --older-than 720h --archive
+  harborctl dlq purge --all --yes`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		endpointID, _ := cmd.Flags().GetString("endpoint")
+		tenantID, _ := cmd.Flags().GetString("tenant-id")
+		eventType, _ := cmd.Flags().GetString("event-type")
+		all, _ := cmd.Flags().GetBool("all")
+		yes, _ := cmd.Flags().GetBool("yes")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		archive, _ := cmd.Flags().GetBool("archive")
+		olderThan, _ := cmd.Flags().GetDuration("older-than")
+
+		from, err := dlqSince(cmd)
+		if err != nil {
+			return err
+		}
+		if olderThan < 0 {
+			return fmt.Errorf("invalid older-than: must not be negative")
+		}
+		req := &webhookv1.PurgeDLQRequest{
+			EndpointId: endpointID,
+			TenantId:   tenantID,
+			EventType:  eventType,
+			From:       from,
+			All:        all,
+			DryRun:     true,
+			Archive:    archive,
+		}
+		if olderThan > 0 {
+			req.To = timestamppb.New(time.Now().Add(-olderThan))
+		}
+		if len(args) == 1 {
+			req.DeliveryId = args[0]
+		}
+
+		purge, cleanup, err := dlqPurger()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		preview, err := purge(req)
+		if err != nil {
+			return fmt.Errorf("failed to count DLQ entries: %w", err)
+		}
+		if dryRun || preview.MatchedCount == 0 {
+			if outputJSON {
+				printOutput(preview)
+			} else {
+				fmt.Printf("%d DLQ entries match\n", preview.MatchedCount)
+			}
+			return nil
+		}
+
+		if !yes && !confirmPrompt(cmd.InOrStdin(), fmt.Sprintf("Purge %d DLQ entries? (y/N): ", preview.MatchedCount)) {
+			fmt.Println("Aborted")
+			return nil
+		}
+
+		req.DryRun = false
+		resp, err := purge(req)
+		if err != nil {
+			return fmt.Errorf("failed to purge DLQ: %w", err)
+		}
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Purged %d DLQ entries\n", resp.PurgedCount)
+			if archive {
+				fmt.Printf("Archived %d DLQ entries\n", resp.ArchivedCount)
+			}
+		}
+		return nil
+	},
+}
+
+// dlqRetentionCmd represents the dlq retention command
+var dlqRetentionCmd = &cobra.Command{
+	Use:   "retention [tenant-id]",
+	Short: "Show or set how long a tenant's DLQ entries are kept",
+	Long: `Show a tenant's DLQ retention, or set it with --max-age, --max-entries and
+--archive. Entries older than the max age, or past the newest max entries, are
+purged by the worker, and copied to the DLQ archive table first with --archive.
+0 keeps entries forever. Tenants without their own retention use the cluster
+default (DLQ_MAX_AGE, DLQ_MAX_ENTRIES and DLQ_ARCHIVE); --reset goes back to it.
+
+Example:
+  harborctl dlq retention tn_123
+  harborctl dlq retention tn_123 --max-age 720h --max-entries 10000 --archive
+  harborctl dlq retention tn_123 --reset`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID := args[0]
+		maxAge, _ := cmd.Flags().GetDuration("max-age")
+		maxEntries, _ := cmd.Flags().GetInt32("max-entries")
+		archive, _ := cmd.Flags().GetBool("archive")
+		reset, _ := cmd.Flags().GetBool("reset")
+
+		set := reset || cmd.Flags().Changed("max-age") || cmd.Flags().Changed("max-entries") || cmd.Flags().Changed("archive")
+		if maxAge < 0 || maxEntries < 0 {
+			return fmt.Errorf("max-age and max-entries must not be negative")
+		}
+		path := fmt.Sprintf("/v1/tenants/%s/dlq-retention", tenantID)
+
+		if useHTTP {
+			if !set {
+				return dlqPrintHTTP("GET", path, nil)
+			}
+			return dlqPrintHTTP("PUT", path, map[string]interface{}{
+				"maxAgeSeconds": int64(maxAge.Seconds()),
+				"maxEntries":    maxEntries,
+				"archive":       archive,
+				"reset":         reset,
+			})
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		var retention *webhookv1.DLQRetention
+		if set {
+			resp, err := client.SetDLQRetention(context.Background(), &webhookv1.SetDLQRetentionRequest{
+				TenantId:      tenantID,
+				MaxAgeSeconds: int64(maxAge.Seconds()),
+				MaxEntries:    maxEntries,
+				Archive:       archive,
+				Reset_:        reset,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to set DLQ retention: %w", err)
+			}
+			retention = resp.Retention
+		} else {
+			resp, err := client.GetDLQRetention(context.Background(), &webhookv1.GetDLQRetentionRequest{TenantId: tenantID})
+			if err != nil {
+				return fmt.Errorf("failed to get DLQ retention: %w", err)
+			}
+			retention = resp.Retention
+		}
+
+		if outputJSON {
+			printOutput(retention)
+			return nil
+		}
+		fmt.Printf("DLQ retention for tenant %s", retention.TenantId)
+		if retention.ClusterDefault {
+			fmt.Print(" (cluster default)")
+		}
+		fmt.Println()
+		printDLQRetention(retention)
+		return nil
+	},
+}
+
+// printDLQRetention prints a DLQ retention, with 0 shown as unlimited
+func printDLQRetention(r *webhookv1.DLQRetention) {
+	maxAge, maxEntries := "unlimited", "unlimited"
+	if r.MaxAgeSeconds > 0 {
+		maxAge = (time.Duration(r.MaxAgeSeconds) * time.Second).String()
+	}
+	if r.MaxEntries > 0 {
+		maxEntries = fmt.Sprint(r.MaxEntries)
+	}
+	fmt.Printf("  Max age: %s\n", maxAge)
+	fmt.Printf("  Max entries: %s\n", maxEntries)
+	fmt.Printf("  Archive: %t\n", r.Archive)
+}
+
+// dlqSince turns the --since flag into the start of the DLQ time filter
+func dlqSince(cmd *cobra.Command) (*timestamppb.Timestamp, error) {
+	since, _ := cmd.Flags().GetDuration("since")
+	if since < 0 {
+		return nil, fmt.Errorf("invalid since: must not be negative")
+	}
+	if since == 0 {
+		return nil, nil
+	}
+	return timestamppb.New(time.Now().Add(-since)), nil
+}
+
+// dlqPrintHTTP calls the REST gateway and prints the decoded response
+func dlqPrintHTTP(method, path string, payload map[string]interface{}) error {
+	var body interface{}
+	if payload != nil {
+		body = payload
+	}
+	resp, err := makeHTTPRequest(method, path, body)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("HTTP error: %s", resp.Status)
+	}
+
+	var result map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	printOutput(result)
+	return nil
+}
+
+// dlqPurger returns a PurgeDLQ call over the configured transport, so purge can
+// count and then delete with the same request
+func dlqPurger() (func(*webhookv1.PurgeDLQRequest) (*webhookv1.PurgeDLQResponse, error), func(), error) {
+	if useHTTP {
+		return func(req *webhookv1.PurgeDLQRequest) (*webhookv1.PurgeDLQResponse, error) {
+			out := &webhookv1.PurgeDLQResponse{}
+			payload := map[string]interface{}{
+				"endpointId": req.EndpointId,
+				"tenantId":   req.TenantId,
+				"eventType":  req.EventType,
+				"deliveryId": req.DeliveryId,
+				"all":        req.All,
+				"dryRun":     req.DryRun,
+				"archive":    req.Archive,
+			}
+			if req.From != nil {
+				payload["from"] = req.From.AsTime().Format(time.RFC3339)
+			}
+			if req.To != nil {
+				payload["to"] = req.To.AsTime().Format(time.RFC3339)
+			}
+			return out, doctorRequest("POST", "/v1/dlq:purge", payload, out)
+		}, func() {}, nil
+	}
+
+	client, cleanup, err := getClient()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	return func(req *webhookv1.PurgeDLQRequest) (*webhookv1.PurgeDLQResponse, error) {
+		return client.PurgeDLQ(context.Background(), req)
+	}, cleanup, nil
+}
+
+// confirmPrompt asks a yes/no question and reports whether the answer was yes
+func confirmPrompt(in io.Reader, prompt string) bool {
+	fmt.Print(prompt)
+	response, _ := bufio.NewReader(in).ReadString('\n')
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
+
+func init() {
+	rootCmd.AddCommand(dlqRootCmd)
+	dlqRootCmd.AddCommand(dlqListCmd)
+	dlqRootCmd.AddCommand(dlqInspectCmd)
+	dlqRootCmd.AddCommand(dlqReplayCmd)
+	dlqRootCmd.AddCommand(dlqPurgeCmd)
+	dlqRootCmd.AddCommand(dlqRetentionCmd)
+
+	// Flags for dlq list command
+	dlqListCmd.Flags().String("endpoint", "", "filter by endpoint ID")
+	dlqListCmd.Flags().String("tenant-id", "", "filter by tenant ID (defaults to the token's tenant)")
+	dlqListCmd.Flags().Duration("since", 0, "only entries dead-lettered within this long (e.g. 24h)")
+	dlqListCmd.Flags().String("page-token", "", "page token from a previous result")
+	dlqListCmd.Flags().String("limit", "10", "maximum number of results (max 500)")
+
+	// Flags for dlq inspect command
+	dlqInspectCmd.Flags().String("tenant-id", "", "tenant that owns the delivery (defaults to the token's tenant)")
+
+	// Flags for dlq replay command
+	dlqReplayCmd.Flags().String("endpoint", "", "only replay deliveries to this endpoint")
+	dlqReplayCmd.Flags().String("tenant-id", "", "only replay deliveries for this tenant (defaults to the token's tenant)")
+	dlqReplayCmd.Flags().String("event-type", "", "only replay deliveries of this event type")
+	dlqReplayCmd.Flags().Duration("since", 0, "only entries dead-lettered within this long (e.g. 24h)")
+	dlqReplayCmd.Flags().String("max", "100", "maximum number of deliveries to replay (max 1000)")
+	dlqReplayCmd.Flags().Bool("dry-run", false, "only count what would be replayed")
+	dlqReplayCmd.Flags().String("reason", "", "reason recorded on every replay")
+	dlqReplayCmd.Flags().Bool("same-key", false, "keep each delivery's idempotency key instead of sending new ones")
+
+	// Flags for dlq purge command
+	dlqPurgeCmd.Flags().String("endpoint", "", "only purge entries for this endpoint")
+	dlqPurgeCmd.Flags().String("tenant-id", "", "only purge entries for this tenant (defaults to the token's tenant)")
+	dlqPurgeCmd.Flags().String("event-type", "", "only purge entries of this event type")
+	dlqPurgeCmd.Flags().Duration("since", 0, "only entries dead-lettered within this long (e.g. 24h)")
+	dlqPurgeCmd.Flags().Bool("all", false, "purge every entry when no other filter is set")
+	dlqPurgeCmd.Flags().Bool("dry-run", false, "only count what would be purged")
+	dlqPurgeCmd.Flags().BoolP("yes", "y", false, "skip the confirmation prompt")
+	dlqPurgeCmd.Flags().Duration("older-than", 0, "only entries dead-lettered longer ago than this (e.g. 720h)")
+	dlqPurgeCmd.Flags().Bool("archive", false, "copy the entries to the DLQ archive before removing them")
+
+	// Flags for dlq retention command
+	dlqRetentionCmd.Flags().Duration("max-age", 0, "purge entries older than this (0 keeps them forever)")
+	dlqRetentionCmd.Flags().Int32("max-entries", 0, "keep at most this many of the newest entries (0 is unlimited)")
+	dlqRetentionCmd.Flags().Bool("archive", false, "archive entries before they are purged")
+	dlqRetentionCmd.Flags().Bool("reset", false, "remove the tenant's retention so it uses the cluster default")
+}
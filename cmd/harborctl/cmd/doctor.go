@@ -0,0 +1,411 @@
+package cmd
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+const doctorEventType = "harborctl.doctor"
+
+// doctorCheck is the outcome of one doctor step
+type doctorCheck struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	Detail   string `json:"detail,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// doctorReport collects the checks of one doctor run
+type doctorReport struct {
+	Checks []doctorCheck `json:"checks"`
+	Passed bool          `json:"passed"`
+}
+
+// run executes one step, records it and prints it unless the report is printed as JSON at the end
+func (r *doctorReport) run(name string, fn func() (string, error)) bool {
+	start := time.Now()
+	detail, err := fn()
+	c := doctorCheck{Name: name, OK: err == nil, Detail: detail, Duration: time.Since(start).Round(time.Millisecond).String()}
+	if err != nil {
+		c.Detail = err.Error()
+	}
+	r.Checks = append(r.Checks, c)
+
+	if !outputJSON {
+		mark := "✅"
+		if !c.OK {
+			mark = "❌"
+		}
+		line := fmt.Sprintf("  %s %s (%s)", mark, c.Name, c.Duration)
+		if c.Detail != "" {
+			line += ": " + c.Detail
+		}
+		fmt.Println(line)
+	}
+	return c.OK
+}
+
+// doctorDelivery is a webhook received by the local listener
+type doctorDelivery struct {
+	body []byte
+	err  error // signature verification result
+}
+
+// doctorCmd runs an end-to-end smoke test against a deployment
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Smoke test a deployment end to end",
+	Long: `Exercise the full stack against a deployment through the HTTPS gateway:
+mint a token, create a temporary endpoint pointed at an ephemeral local listener,
+publish an event, verify the signed delivery arrives and is marked delivered, then
+delete the endpoint. Exits non-zero if any check fails.
+
+The worker must be able to reach the listener. When it runs in Docker or Kubernetes,
+listen on a fixed port and pass the URL the worker should use, or point the endpoint
+at the fake-receiver instead (its ENDPOINT_SECRET must match --secret).
+
+Examples:
+  harborctl doctor
+  harborctl doctor --listen 0.0.0.0:9099 --callback-url http://host.docker.internal:9099/hook
+  harborctl doctor --receiver-url http://fake-receiver:8081/hook --secret demo_secret`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		o := doctorOptions{}
+		o.tenantID, _ = cmd.Flags().GetString("tenant-id")
+		o.jwksURL, _ = cmd.Flags().GetString("jwks-url")
+		o.listenAddr, _ = cmd.Flags().GetString("listen")
+		o.callbackURL, _ = cmd.Flags().GetString("callback-url")
+		o.receiverURL, _ = cmd.Flags().GetString("receiver-url")
+		o.secret, _ = cmd.Flags().GetString("secret")
+		o.wait, _ = cmd.Flags().GetDuration("wait")
+		o.sigHeader, _ = cmd.Flags().GetString("signature-header")
+		o.tsHeader, _ = cmd.Flags().GetString("timestamp-header")
+
+		if o.receiverURL != "" && o.secret == "" {
+			return errors.New("--secret is required with --receiver-url")
+		}
+
+		report := runDoctor(o)
+		if outputJSON {
+			printOutput(report)
+		}
+		if !report.Passed {
+			return errors.New("doctor found failing checks")
+		}
+		if !outputJSON {
+			fmt.Println("🎉 All checks passed")
+		}
+		return nil
+	},
+}
+
+// doctorOptions are the doctor command's flags
+type doctorOptions struct {
+	tenantID    string
+	jwksURL     string
+	listenAddr  string
+	callbackURL string
+	receiverURL string // when set, deliver there instead of to a local listener
+	secret      string
+	wait        time.Duration
+	sigHeader   string
+	tsHeader    string
+}
+
+// runDoctor runs every check in order, stopping at the first failure, and always deletes
+// the endpoint once it was created
+func runDoctor(o doctorOptions) *doctorReport {
+	useListener := o.receiverURL == ""
+	if o.secret == "" {
+		o.secret = randomHex(16)
+	}
+	runID := randomHex(8)
+
+	if !outputJSON {
+		fmt.Printf("harborctl doctor: %s (tenant %s, run %s)\n", serverAddr, o.tenantID, runID)
+	}
+	report := &doctorReport{}
+	ok := report.run("gateway reachable", func() (string, error) {
+		var resp webhookv1.PingResponse
+		if err := doctorRequest("GET", "/v1/ping", nil, &resp); err != nil {
+			return "", err
+		}
+		return resp.GetMessage(), nil
+	})
+
+	ok = ok && report.run("token minted", func() (string, error) {
+		if jwtToken != "" {
+			return "using the configured token", nil
+		}
+		token, err := mintDoctorToken(o.jwksURL, o.tenantID)
+		if err != nil {
+			return "", err
+		}
+		jwtToken = token
+		return "from " + o.jwksURL, nil
+	})
+
+	received := make(chan doctorDelivery, 16)
+	ok = ok && report.run("receiver ready", func() (string, error) {
+		if !useListener {
+			return "using " + o.receiverURL, nil
+		}
+		lis, err := net.Listen("tcp", o.listenAddr)
+		if err != nil {
+			return "", err
+		}
+		srv := &http.Server{Handler: doctorReceiver(o.secret, o.sigHeader, o.tsHeader, received), ReadHeaderTimeout: 5 * time.Second}
+		go func() { _ = srv.Serve(lis) }()
+
+		o.receiverURL = o.callbackURL
+		if o.receiverURL == "" {
+			o.receiverURL = "http://" + lis.Addr().String() + "/hook"
+		}
+		return "listening on " + lis.Addr().String() + ", endpoint url " + o.receiverURL, nil
+	})
+
+	var endpointID string
+	ok = ok && report.run("endpoint created", func() (string, error) {
+		var resp webhookv1.CreateEndpointResponse
+		body := map[string]any{"url": o.receiverURL, "secret": o.secret}
+		if err := doctorRequest("POST", fmt.Sprintf("/v1/tenants/%s/endpoints", o.tenantID), body, &resp); err != nil {
+			return "", err
+		}
+		endpointID = resp.GetEndpoint().GetId()
+		return endpointID, nil
+	})
+	if endpointID != "" {
+		// Clean up whatever happens after the endpoint exists
+		defer func() {
+			cleaned := report.run("cleanup", func() (string, error) {
+				var resp webhookv1.DeleteEndpointResponse
+				if err := doctorRequest("DELETE", fmt.Sprintf("/v1/tenants/%s/endpoints/%s", o.tenantID, endpointID), nil, &resp); err != nil {
+					return "", err
+				}
+				return fmt.Sprintf("deleted endpoint, %d subscriptions and %d deliveries", resp.GetDeletedSubscriptions(), resp.GetDeletedDeliveries()), nil
+			})
+			report.Passed = report.Passed && cleaned
+		}()
+	}
+
+	ok = ok && report.run("subscription created", func() (string, error) {
+		var resp webhookv1.CreateSubscriptionResponse
+		body := map[string]any{"endpointId": endpointID, "eventType": doctorEventType}
+		if err := doctorRequest("POST", fmt.Sprintf("/v1/tenants/%s/subscriptions", o.tenantID), body, &resp); err != nil {
+			return "", err
+		}
+		return resp.GetSubscription().GetId(), nil
+	})
+
+	var eventID string
+	ok = ok && report.run("event published", func() (string, error) {
+		var resp webhookv1.PublishEventResponse
+		body := map[string]any{
+			"eventType": doctorEventType,
+			"payload":   map[string]any{"doctor_run": runID, "sent_at": time.Now().UTC().Format(time.RFC3339)},
+		}
+		if err := doctorRequest("POST", fmt.Sprintf("/v1/tenants/%s/events:publish", o.tenantID), body, &resp); err != nil {
+			return "", err
+		}
+		eventID = resp.GetEventId()
+		if resp.GetFanoutCount() < 1 {
+			return "", fmt.Errorf("event %s fanned out to %d endpoints, want at least 1", eventID, resp.GetFanoutCount())
+		}
+		return eventID, nil
+	})
+
+	deadline := time.Now().Add(o.wait)
+	if useListener {
+		// Signatures can only be checked on our own listener; an external receiver checks its own
+		ok = ok && report.run("signed delivery received", func() (string, error) {
+			return awaitDoctorDelivery(received, runID, time.Until(deadline))
+		})
+	}
+
+	ok = ok && report.run("delivery marked delivered", func() (string, error) {
+		return awaitDoctorStatus(eventID, deadline)
+	})
+
+	report.Passed = ok
+	return report
+}
+
+// doctorRequest calls the REST gateway and decodes a successful response into out
+func doctorRequest(method, path string, body any, out proto.Message) error {
+	resp, err := makeHTTPRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	if out == nil {
+		return nil
+	}
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(b, out)
+}
+
+// mintDoctorToken asks the JWKS server for a short-lived token for tenantID
+func mintDoctorToken(jwksURL, tenantID string) (string, error) {
+	issued, err := requestToken(jwksURL, os.Getenv(jwksAPIKeyEnv), tenantID, nil, "", 10*time.Minute)
+	return issued.Token, err
+}
+
+// doctorReceiver accepts webhooks, rejecting bad signatures like a real receiver would,
+// and reports every request on received
+func doctorReceiver(secret, sigHeader, tsHeader string, received chan<- doctorDelivery) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		err := verifyDoctorSignature(secret, r.Method, r.URL.RequestURI(), body, r.Header.Get(tsHeader), r.Header.Get(sigHeader), time.Now(), 5*time.Minute)
+		select {
+		case received <- doctorDelivery{body: body, err: err}:
+		default:
+		}
+		if err != nil {
+			http.Error(w, "invalid signature: "+err.Error(), http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// verifyDoctorSignature checks a Harborhook signature in either scheme:
+// v1 is sha256=hex(HMAC(secret, body || timestamp)), and v2 is
+// v2,t=<ts>,kid=<id>,alg=HMAC-SHA256,sig=hex(HMAC(secret, "v2\n" ts "\n" METHOD "\n" target "\n" body))
+func verifyDoctorSignature(secret, method, target string, body []byte, ts, sig string, now time.Time, leeway time.Duration) error {
+	var signed []byte
+	if rest, ok := strings.CutPrefix(sig, "v2,"); ok {
+		// The v2 header carries its own timestamp. The doctor has one secret, so kid isn't needed to pick it.
+		fields := map[string]string{}
+		for _, kv := range strings.Split(rest, ",") {
+			if k, v, ok := strings.Cut(kv, "="); ok {
+				fields[k] = v
+			}
+		}
+		if fields["alg"] != "HMAC-SHA256" {
+			return fmt.Errorf("unsupported signature alg %q", fields["alg"])
+		}
+		ts, sig = fields["t"], "sha256="+fields["sig"]
+		signed = append([]byte("v2\n"+ts+"\n"+method+"\n"+target+"\n"), body...)
+	} else {
+		signed = append(append([]byte{}, body...), ts...)
+	}
+
+	if ts == "" || sig == "" {
+		return errors.New("missing signature headers")
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return errors.New("invalid timestamp")
+	}
+	if d := now.Sub(time.Unix(unix, 0)); d > leeway || d < -leeway {
+		return errors.New("timestamp outside leeway")
+	}
+	scheme, hexSig, found := strings.Cut(sig, "=")
+	if !found || scheme != "sha256" {
+		return errors.New("bad signature scheme")
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return errors.New("signature not hex")
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(signed)
+	if subtle.ConstantTimeCompare(got, mac.Sum(nil)) != 1 {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// awaitDoctorDelivery waits for the webhook carrying runID and reports whether its signature verified
+func awaitDoctorDelivery(received <-chan doctorDelivery, runID string, wait time.Duration) (string, error) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	for {
+		select {
+		case d := <-received:
+			if !bytes.Contains(d.body, []byte(runID)) {
+				continue // a stray request, e.g. another run
+			}
+			if d.err != nil {
+				return "", fmt.Errorf("delivery arrived with a bad signature: %w", d.err)
+			}
+			return fmt.Sprintf("%d bytes, signature verified", len(d.body)), nil
+		case <-timer.C:
+			return "", fmt.Errorf("no delivery within %s; is the listener reachable from the worker?", wait)
+		}
+	}
+}
+
+// awaitDoctorStatus polls the event's deliveries until one is delivered, dead or the deadline passes
+func awaitDoctorStatus(eventID string, deadline time.Time) (string, error) {
+	last := "no deliveries"
+	for {
+		var resp webhookv1.GetDeliveryStatusResponse
+		if err := doctorRequest("GET", fmt.Sprintf("/v1/events/%s/deliveries", eventID), nil, &resp); err != nil {
+			return "", err
+		}
+		for _, a := range resp.GetAttempts() {
+			switch a.GetStatus() {
+			case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED:
+				return fmt.Sprintf("delivery %s, HTTP %d", a.GetDeliveryId(), a.GetHttpStatus()), nil
+			case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED:
+				return "", fmt.Errorf("delivery %s is dead: %s", a.GetDeliveryId(), a.GetErrorReason())
+			}
+			last = a.GetStatus().String()
+			if a.GetErrorReason() != "" {
+				last += " (" + a.GetErrorReason() + ")"
+			}
+		}
+		if time.Now().After(deadline) {
+			return "", fmt.Errorf("not delivered in time, last status %s", last)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// randomHex returns n random bytes hex encoded
+func randomHex(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+
+	doctorCmd.Flags().String("tenant-id", "tn_doctor", "tenant to run the checks as")
+	doctorCmd.Flags().String("jwks-url", defaultJWKSURL, "JWKS server used to mint a token when none is configured")
+	doctorCmd.Flags().String("listen", "127.0.0.1:0", "address for the ephemeral webhook listener")
+	doctorCmd.Flags().String("callback-url", "", "URL the worker uses to reach the listener (default http://<listen address>/hook)")
+	doctorCmd.Flags().String("receiver-url", "", "deliver to an existing receiver (e.g. fake-receiver) instead of the local listener")
+	doctorCmd.Flags().String("secret", "", "endpoint secret (random by default; must match the receiver with --receiver-url)")
+	doctorCmd.Flags().Duration("wait", 60*time.Second, "how long to wait for the delivery")
+	doctorCmd.Flags().String("signature-header", "X-HarborHook-Signature", "signature header the worker sends")
+	doctorCmd.Flags().String("timestamp-header", "X-HarborHook-Timestamp", "timestamp header the worker sends")
+}
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/delivery/signvectors"
+)
+
+func signForTest(secret string, body []byte, ts string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	mac.Write([]byte(ts))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyDoctorSignature(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"doctor_run":"abc"}`)
+
+	tests := []struct {
+		name    string
+		ts      string
+		sig     string
+		now     time.Time
+		wantErr string
+	}{
+		{name: "valid", ts: ts, sig: signForTest("s3cret", body, ts), now: now},
+		{name: "missing headers", ts: "", sig: "", now: now, wantErr: "missing signature headers"},
+		{name: "bad timestamp", ts: "yesterday", sig: "sha256=00", now: now, wantErr: "invalid timestamp"},
+		{name: "stale timestamp", ts: ts, sig: signForTest("s3cret", body, ts), now: now.Add(10 * time.Minute), wantErr: "timestamp outside leeway"},
+		{name: "wrong scheme", ts: ts, sig: "md5=00", now: now, wantErr: "bad signature scheme"},
+		{name: "not hex", ts: ts, sig: "sha256=zz", now: now, wantErr: "signature not hex"},
+		{name: "wrong secret", ts: ts, sig: signForTest("other", body, ts), now: now, wantErr: "signature mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyDoctorSignature("s3cret", "POST", "/", body, tt.ts, tt.sig, tt.now, 5*time.Minute)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("verifyDoctorSignature() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("verifyDoctorSignature() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDoctorReceiver(t *testing.T) {
+	received := make(chan doctorDelivery, 2)
+	h := doctorReceiver("s3cret", "X-Sig", "X-Ts", received)
+
+	body := `{"doctor_run":"abc"}`
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	good := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+	good.Header.Set("X-Ts", ts)
+	good.Header.Set("X-Sig", signForTest("s3cret", []byte(body), ts))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, good)
+	if rec.Code != http.StatusOK {
+		t.Errorf("signed request status = %d, want 200", rec.Code)
+	}
+
+	bad := httptest.NewRequest(http.MethodPost, "/hook", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, bad)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("unsigned request status = %d, want 401", rec.Code)
+	}
+
+	if d := <-received; d.err != nil || string(d.body) != body {
+		t.Errorf("first delivery = %q, %v; want verified body", d.body, d.err)
+	}
+	if d := <-received; d.err == nil {
+		t.Error("second delivery verified, want a signature error")
+	}
+}
+
+func TestAwaitDoctorDelivery(t *testing.T) {
+	t.Run("skips stray requests", func(t *testing.T) {
+		received := make(chan doctorDelivery, 2)
+		received <- doctorDelivery{body: []byte(`{"doctor_run":"other"}`)}
+		received <- doctorDelivery{body: []byte(`{"doctor_run":"mine"}`)}
+
+		detail, err := awaitDoctorDelivery(received, "mine", time.Second)
+		if err != nil {
+			t.Fatalf("awaitDoctorDelivery() unexpected error: %v", err)
+		}
+		if !strings.Contains(detail, "signature verified") {
+			t.Errorf("detail = %q", detail)
+		}
+	})
+
+	t.Run("bad signature fails", func(t *testing.T) {
+		received := make(chan doctorDelivery, 1)
+		received <- doctorDelivery{body: []byte(`{"doctor_run":"mine"}`), err: errors.New("signature mismatch")}
+
+		if _, err := awaitDoctorDelivery(received, "mine", time.Second); err == nil {
+			t.Error("awaitDoctorDelivery() expected error for a bad signature")
+		}
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		if _, err := awaitDoctorDelivery(make(chan doctorDelivery), "mine", 10*time.Millisecond); err == nil {
+			t.Error("awaitDoctorDelivery() expected a timeout")
+		}
+	})
+}
+
+func TestDoctorReport_Run(t *testing.T) {
+	outputJSON = true // keep test output quiet
+	defer func() { outputJSON = false }()
+
+	r := &doctorReport{}
+	if !r.run("passes", func() (string, error) { return "fine", nil }) {
+		t.Error("run() = false for a passing step")
+	}
+	if r.run("fails", func() (string, error) { return "", errors.New("boom") }) {
+		t.Error("run() = true for a failing step")
+	}
+
+	if len(r.Checks) != 2 {
+		t.Fatalf("recorded %d checks, want 2", len(r.Checks))
+	}
+	if c := r.Checks[0]; !c.OK || c.Detail != "fine" {
+		t.Errorf("first check = %+v", c)
+	}
+	if c := r.Checks[1]; c.OK || c.Detail != "boom" {
+		t.Errorf("second check = %+v", c)
+	}
+}
+
+func TestVerifyDoctorSignature_Vectors(t *testing.T) {
+	for _, v := range signvectors.Load(t) {
+		t.Run(v.Name, func(t *testing.T) {
+			if v.Scheme == delivery.SignatureEd25519 {
+				t.Skip("the doctor's endpoint signs with its secret")
+			}
+			unix, _ := strconv.ParseInt(v.Timestamp, 10, 64)
+			now := time.Unix(unix, 0)
+
+			if err := verifyDoctorSignature(v.Secret, v.Method, v.Path, []byte(v.Payload), v.Timestamp, v.Signature, now, time.Minute); err != nil {
+				t.Errorf("verifyDoctorSignature() rejected vector: %v", err)
+			}
+			if err := verifyDoctorSignature(v.Secret+"x", v.Method, v.Path, []byte(v.Payload), v.Timestamp, v.Signature, now, time.Minute); err == nil {
+				t.Error("verifyDoctorSignature() accepted the wrong secret")
+			}
+		})
+	}
+}
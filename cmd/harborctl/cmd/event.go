@@ -4,10 +4,18 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/austindbirch/harbor_hook/cmd/harborctl/cmd/ascii"
 	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
 	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 // eventCmd represents the event command
@@ -26,8 +34,17 @@ var publishCmd = &cobra.Command{
 	Short: "Publish a webhook event",
 	Long: `Publish a webhook event with a JSON payload.
 	
+Use --ttl or --deliver-by to give up on deliveries that are still pending after a deadline;
+they are dead-lettered with reason "expired" instead of being retried. Use --publish-at or
+--delay to schedule the event for later; a --ttl then counts from the scheduled time. Use
+--priority high for urgent events and low for backfills; with priority topics enabled each
+priority's deliveries are queued separately.
+
 Example:
-  harborctl event publish tn_123 appointment.created '{"id":"apt_789","patient":"John Doe"}'`,
+  harborctl event publish tn_123 appointment.created '{"id":"apt_789","patient":"John Doe"}'
+  harborctl event publish tn_123 otp.issued '{"code":"123456"}' --ttl 5m
+  harborctl event publish tn_123 appointment.reminder '{"id":"apt_789"}' --delay 24h
+  harborctl event publish tn_123 invoice.backfilled '{"id":"inv_42"}' --priority low`,
 	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tenantID := args[0]
@@ -35,6 +52,43 @@ Example:
 		payloadJSON := args[2]
 
 		idempotencyKey, _ := cmd.Flags().GetString("idempotency-key")
+		ttl, _ := cmd.Flags().GetDuration("ttl")
+		deliverByFlag, _ := cmd.Flags().GetString("deliver-by")
+		if ttl != 0 && deliverByFlag != "" {
+			return fmt.Errorf("set at most one of --ttl and --deliver-by")
+		}
+		var deliverBy time.Time
+		if deliverByFlag != "" {
+			at, err := time.Parse(time.RFC3339, deliverByFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --deliver-by (want RFC3339): %w", err)
+			}
+			deliverBy = at
+		}
+		delay, _ := cmd.Flags().GetDuration("delay")
+		publishAtFlag, _ := cmd.Flags().GetString("publish-at")
+		if delay != 0 && publishAtFlag != "" {
+			return fmt.Errorf("set at most one of --delay and --publish-at")
+		}
+		var publishAt time.Time
+		if publishAtFlag != "" {
+			at, err := time.Parse(time.RFC3339, publishAtFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --publish-at (want RFC3339): %w", err)
+			}
+			publishAt = at
+		} else if delay > 0 {
+			publishAt = time.Now().Add(delay)
+		}
+		priorityFlag, _ := cmd.Flags().GetString("priority")
+		var priority webhookv1.EventPriority
+		if priorityFlag != "" {
+			v, ok := webhookv1.EventPriority_value["EVENT_PRIORITY_"+strings.ToUpper(priorityFlag)]
+			if !ok || v == 0 {
+				return fmt.Errorf("invalid --priority %q (want high, normal or low)", priorityFlag)
+			}
+			priority = webhookv1.EventPriority(v)
+		}
 
 		// Parse the JSON payload
 		payload, err := parseJSON(payloadJSON)
@@ -50,6 +104,18 @@ Example:
 			if idempotencyKey != "" {
 				httpPayload["idempotencyKey"] = idempotencyKey
 			}
+			if ttl != 0 {
+				httpPayload["ttl"] = strconv.FormatFloat(ttl.Seconds(), 'f', -1, 64) + "s" // JSON form of a Duration
+			}
+			if !deliverBy.IsZero() {
+				httpPayload["deliverBy"] = deliverBy.UTC().Format(time.RFC3339Nano)
+			}
+			if !publishAt.IsZero() {
+				httpPayload["publishAt"] = publishAt.UTC().Format(time.RFC3339Nano)
+			}
+			if priority != webhookv1.EventPriority_EVENT_PRIORITY_UNSPECIFIED {
+				httpPayload["priority"] = priority.String()
+			}
 
 			resp, err := makeHTTPRequest("POST", fmt.Sprintf("/v1/tenants/%s/events:publish", tenantID), httpPayload)
 			if err != nil {
@@ -82,6 +148,16 @@ Example:
 			EventType:      eventType,
 			Payload:        payload,
 			IdempotencyKey: idempotencyKey,
+			Priority:       priority,
+		}
+		if ttl != 0 {
+			req.Ttl = durationpb.New(ttl)
+		}
+		if !deliverBy.IsZero() {
+			req.DeliverBy = timestamppb.New(deliverBy)
+		}
+		if !publishAt.IsZero() {
+			req.PublishAt = timestamppb.New(publishAt)
 		}
 
 		resp, err := client.PublishEvent(ctx, req)
@@ -91,6 +167,11 @@ Example:
 
 		if outputJSON {
 			printOutput(resp)
+		} else if resp.Duplicate {
+			fmt.Printf("Duplicate of event: %s (idempotency key already used, nothing published)\n", resp.EventId)
+			fmt.Printf("  Fanout count: %d\n", resp.FanoutCount)
+		} else if resp.Scheduled {
+			fmt.Printf("Scheduled event: %s\n", resp.EventId)
 		} else {
 			fmt.Printf("Published event: %s\n", resp.EventId)
 			fmt.Printf("  Fanout count: %d\n", resp.FanoutCount)
@@ -100,10 +181,161 @@ Example:
 	},
 }
 
+// publishBatchCmd represents the publish-batch command
+var publishBatchCmd = &cobra.Command{
+	Use:   "publish-batch [tenant-id] [events-file]",
+	Short: "Publish many webhook events in one call",
+	Long: `Publish up to 500 events from a JSON file (or - for stdin) holding an array of
+{"eventType", "payload", "idempotencyKey", "ttl", "deliverBy"} objects. Each event gets its own result, so
+one bad event doesn't fail the batch.
+
+Example:
+  harborctl event publish-batch tn_123 events.json`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID := args[0]
+
+		var (
+			raw []byte
+			err error
+		)
+		if args[1] == "-" {
+			raw, err = io.ReadAll(os.Stdin)
+		} else {
+			raw, err = os.ReadFile(args[1])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read events: %w", err)
+		}
+
+		var events []json.RawMessage
+		if err := json.Unmarshal(raw, &events); err != nil {
+			return fmt.Errorf("events file must be a JSON array: %w", err)
+		}
+		body, _ := json.Marshal(map[string]interface{}{"tenantId": tenantID, "events": events})
+
+		if useHTTP {
+			resp, err := makeHTTPRequest("POST", fmt.Sprintf("/v1/tenants/%s/events:batchPublish", tenantID), json.RawMessage(body))
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		req := &webhookv1.PublishEventsRequest{}
+		if err := protojson.Unmarshal(body, req); err != nil {
+			return fmt.Errorf("invalid events: %w", err)
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.PublishEvents(context.Background(), req)
+		if err != nil {
+			return fmt.Errorf("failed to publish events: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Published %d of %d events\n", resp.PublishedCount, len(resp.Results))
+			for _, r := range resp.Results {
+				switch {
+				case r.Error != "":
+					fmt.Printf("  [%d] rejected: %s\n", r.Index, r.Error)
+				case r.Duplicate:
+					fmt.Printf("  [%d] %s (duplicate)\n", r.Index, r.EventId)
+				default:
+					fmt.Printf("  [%d] %s fanout %d\n", r.Index, r.EventId, r.FanoutCount)
+				}
+			}
+		}
+
+		return nil
+	},
+}
+
+// eventQuotaCmd represents the event quota command
+var eventQuotaCmd = &cobra.Command{
+	Use:   "quota [tenant-id]",
+	Short: "Show a tenant's publishing quotas and usage",
+	Long: `Show how many events a tenant may publish per minute, its fanout limit,
+and how many events it has published in the current minute.
+
+Example:
+  harborctl event quota tn_123`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID := args[0]
+
+		if useHTTP {
+			resp, err := makeHTTPRequest("GET", fmt.Sprintf("/v1/tenants/%s/quota", tenantID), nil)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.GetTenantQuota(context.Background(), &webhookv1.GetTenantQuotaRequest{TenantId: tenantID})
+		if err != nil {
+			return fmt.Errorf("failed to get quota: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Quotas for tenant %s:\n", tenantID)
+			printTenantQuota(resp.Quota)
+			fmt.Printf("  Published this minute: %d\n", resp.EventsThisMinute)
+		}
+		return nil
+	},
+}
+
 func init() {
 	rootCmd.AddCommand(eventCmd)
 	eventCmd.AddCommand(publishCmd)
+	eventCmd.AddCommand(publishBatchCmd)
+	eventCmd.AddCommand(eventQuotaCmd)
 
 	// Flags for publish
 	publishCmd.Flags().String("idempotency-key", "", "idempotency key for deduplication")
+	publishCmd.Flags().Duration("ttl", 0, "dead-letter deliveries still pending this long after publish (e.g. 5m)")
+	publishCmd.Flags().String("deliver-by", "", "dead-letter deliveries still pending at this RFC3339 time")
+	publishCmd.Flags().Duration("delay", 0, "schedule the event to go out this long from now (e.g. 1h)")
+	publishCmd.Flags().String("publish-at", "", "schedule the event to go out at this RFC3339 time")
+	publishCmd.Flags().String("priority", "", "event priority: high, normal or low")
 }
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+// ANSI colors for --pretty output, the same defaults jq uses
+const (
+	colorKey    = "\033[34;1m"
+	colorString = "\033[0;32m"
+	colorNull   = "\033[1;30m"
+	colorReset  = "\033[0m"
+)
+
+// colorOutput reports whether stdout is a terminal that wants color. NO_COLOR turns it off.
+func colorOutput() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	fi, err := os.Stdout.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// formatJSON indents jsonData two spaces per level, coloring keys, strings and nulls when
+// color is set. It ends with a newline.
+func formatJSON(jsonData []byte, color bool) (string, error) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, jsonData, "", "  "); err != nil {
+		return "", err
+	}
+	out.WriteByte('\n')
+	if !color {
+		return out.String(), nil
+	}
+	return colorize(out.Bytes()), nil
+}
+
+// colorize wraps the strings and nulls of valid JSON in color codes. A string is a key when
+// the next non-space byte after it is a colon.
+func colorize(data []byte) string {
+	var out bytes.Buffer
+	for i := 0; i < len(data); {
+		switch {
+		case data[i] == '"':
+			end := i + 1
+			for end < len(data) && data[end] != '"' {
+				if data[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			end++ // closing quote
+			next := end
+			for next < len(data) && (data[next] == ' ' || data[next] == '\n') {
+				next++
+			}
+			c := colorString
+			if next < len(data) && data[next] == ':' {
+				c = colorKey
+			}
+			out.WriteString(c)
+			out.Write(data[i:end])
+			out.WriteString(colorReset)
+			i = end
+		case bytes.HasPrefix(data[i:], []byte("null")):
+			out.WriteString(colorNull + "null" + colorReset)
+			i += len("null")
+		default:
+			out.WriteByte(data[i])
+			i++
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// profileEnv selects a profile when --profile isn't given
+const profileEnv = "HARBORCTL_PROFILE"
+
+var (
+	// profileName is the active profile, from --profile or HARBORCTL_PROFILE; empty uses the
+	// top-level settings only
+	profileName string
+	// profileConfig holds the active profile's settings
+	profileConfig *viper.Viper
+	// defaultTenant fills in a command's [tenant-id] argument when it's left out
+	defaultTenant string
+)
+
+// loadProfile selects the profile named by --profile or HARBORCTL_PROFILE from the config
+// file's profiles section:
+//
+//	profiles:
+//	  staging:
+//	    server: harborhook.staging.example.com:8443
+//	    token: eyJ...
+//	    tenant: tn_123
+//	    tls:
+//	      ca_file: /etc/harborhook/staging-ca.pem
+func loadProfile() error {
+	if profileName == "" {
+		profileName = os.Getenv(profileEnv)
+	}
+	profileConfig = nil
+	if profileName == "" {
+		return nil
+	}
+	profileConfig = viper.Sub("profiles." + strings.ToLower(profileName)) // viper lowercases keys
+	if profileConfig == nil {
+		return fmt.Errorf("profile %q not found in %s (profiles: %s)", profileName, configFileName(), strings.Join(profileNames(), ", "))
+	}
+	return nil
+}
+
+// setting returns the config key is read from: the active profile when it sets key, the top
+// level otherwise
+func setting(key string) *viper.Viper {
+	if profileConfig != nil && profileConfig.IsSet(key) {
+		return profileConfig
+	}
+	return viper.GetViper()
+}
+
+// profileKey is where config set writes key: under the active profile, if there is one
+func profileKey(key string) string {
+	if profileName == "" {
+		return key
+	}
+	return "profiles." + profileName + "." + key
+}
+
+// profileNames lists the config file's profiles, sorted
+func profileNames() []string {
+	var names []string
+	for name := range viper.GetStringMap("profiles") {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func configFileName() string {
+	if f := viper.ConfigFileUsed(); f != "" {
+		return f
+	}
+	return "the config file"
+}
+
+// tlsValue is a TLS setting from its flag when given, from the config otherwise
+func tlsValue(flag, key string) string {
+	if f := rootCmd.PersistentFlags().Lookup(flag); f != nil && f.Changed {
+		return f.Value.String()
+	}
+	return setting(key).GetString(key)
+}
+
+// grpcTLS reports whether gRPC is dialed over TLS: --tls, or a certificate flag, or tls.grpc
+func grpcTLS() bool {
+	flags := rootCmd.PersistentFlags()
+	if flags.Changed("tls") {
+		return tlsEnabled
+	}
+	if flags.Changed("ca-cert") || flags.Changed("client-cert") {
+		return true
+	}
+	return setting("tls.grpc").GetBool("tls.grpc")
+}
+
+// tlsConfig builds the client TLS settings from the TLS flags and tls.* keys. Without a CA,
+// and unless tls.insecure is set to false, the server's certificate isn't verified, as local
+// gateways use self-signed certificates. A client certificate is presented for mTLS.
+func tlsConfig() (*tls.Config, error) {
+	cfg := &tls.Config{ServerName: tlsValue("tls-server-name", "tls.server_name")}
+
+	certFile, keyFile := tlsValue("client-cert", "tls.client_cert"), tlsValue("client-key", "tls.client_key")
+	if (certFile == "") != (keyFile == "") {
+		return nil, fmt.Errorf("client certificate and key must be set together")
+	}
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile := tlsValue("ca-cert", "tls.ca_file"); caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s", caFile)
+		}
+		cfg.RootCAs = pool
+		return cfg, nil
+	}
+	cfg.InsecureSkipVerify = true // For development with self-signed certs
+	if v := setting("tls.insecure"); v.IsSet("tls.insecure") {
+		cfg.InsecureSkipVerify = v.GetBool("tls.insecure")
+	}
+	return cfg, nil
+}
+
+// applyProfileTenant lets commands whose first argument is [tenant-id] leave it out when the
+// config sets a tenant: if the arguments given don't fit but do with the tenant in front, it's
+// put there
+func applyProfileTenant(cmd *cobra.Command) {
+	for _, sub := range cmd.Commands() {
+		applyProfileTenant(sub)
+	}
+	if fields := strings.Fields(cmd.Use); len(fields) < 2 || fields[1] != "[tenant-id]" || cmd.Args == nil {
+		return
+	}
+	validate := cmd.Args
+	withTenant := func(c *cobra.Command, args []string) []string {
+		if defaultTenant == "" || validate(c, args) == nil {
+			return args
+		}
+		if full := append([]string{defaultTenant}, args...); validate(c, full) == nil {
+			return full
+		}
+		return args
+	}
+	cmd.Args = func(c *cobra.Command, args []string) error {
+		return validate(c, withTenant(c, args))
+	}
+	if run := cmd.RunE; run != nil {
+		cmd.RunE = func(c *cobra.Command, args []string) error {
+			return run(c, withTenant(c, args))
+		}
+	}
+	if run := cmd.Run; run != nil {
+		cmd.Run = func(c *cobra.Command, args []string) {
+			run(c, withTenant(c, args))
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// useConfig loads contents as the config file, with profile selected, for one test
+func useConfig(t *testing.T, contents, profile string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), ".harborctl.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	viper.Reset()
+	viper.SetConfigFile(path)
+	if err := viper.ReadInConfig(); err != nil {
+		t.Fatal(err)
+	}
+	profileName = profile
+	t.Cleanup(func() {
+		viper.Reset()
+		profileName, profileConfig, defaultTenant = "", nil, ""
+	})
+}
+
+const profilesConfig = `
+server: localhost:8443
+timeout: 30s
+profiles:
+  staging:
+    server: harborhook.staging.example.com:8443
+    tenant: tn_staging
+    tls:
+      insecure: false
+  Prod:
+    server: harborhook.example.com:8443
+`
+
+func TestLoadProfile(t *testing.T) {
+	useConfig(t, profilesConfig, "staging")
+	if err := loadProfile(); err != nil {
+		t.Fatalf("loadProfile() unexpected error: %v", err)
+	}
+	if got := setting("server").GetString("server"); got != "harborhook.staging.example.com:8443" {
+		t.Errorf("server = %q, want the profile's", got)
+	}
+	if got := setting("timeout").GetString("timeout"); got != "30s" {
+		t.Errorf("timeout = %q, want the top-level value the profile doesn't set", got)
+	}
+	if got := profileKey("tenant"); got != "profiles.staging.tenant" {
+		t.Errorf("profileKey(tenant) = %q", got)
+	}
+	if cfg, err := tlsConfig(); err != nil || cfg.InsecureSkipVerify {
+		t.Errorf("tlsConfig() = %+v, %v, want verification on", cfg, err)
+	}
+
+	profileName = "PROD"
+	if err := loadProfile(); err != nil || setting("server").GetString("server") != "harborhook.example.com:8443" {
+		t.Errorf("loadProfile(PROD) = %v, want profiles matched regardless of case", err)
+	}
+
+	profileName = "qa"
+	if err := loadProfile(); err == nil || !strings.Contains(err.Error(), "prod, staging") {
+		t.Errorf("loadProfile(qa) = %v, want an error listing the profiles", err)
+	}
+}
+
+func TestLoadProfile_Env(t *testing.T) {
+	useConfig(t, profilesConfig, "")
+	t.Setenv(profileEnv, "staging")
+	if err := loadProfile(); err != nil || profileName != "staging" {
+		t.Fatalf("loadProfile() = %v with profile %q, want %s to select staging", err, profileName, profileEnv)
+	}
+}
+
+func TestTLSConfig_CAFile(t *testing.T) {
+	srv := httptest.NewTLSServer(nil)
+	defer srv.Close()
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	if err := os.WriteFile(ca, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	useConfig(t, "profiles:\n  dev:\n    tls:\n      ca_file: "+ca+"\n      server_name: example.com\n", "dev")
+	if err := loadProfile(); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := tlsConfig()
+	if err != nil || cfg.RootCAs == nil || cfg.InsecureSkipVerify || cfg.ServerName != "example.com" {
+		t.Errorf("tlsConfig() = %+v, %v, want the CA trusted and verification on", cfg, err)
+	}
+
+	profileName = ""
+	_ = loadProfile()
+	if cfg, _ := tlsConfig(); !cfg.InsecureSkipVerify {
+		t.Error("tlsConfig() without settings verifies, want self-signed dev certs accepted as before")
+	}
+}
+
+func TestApplyProfileTenant(t *testing.T) {
+	var got []string
+	root := &cobra.Command{Use: "harborctl"}
+	root.AddCommand(&cobra.Command{
+		Use:  "publish [tenant-id] [event-type]",
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			got = args
+			return nil
+		},
+	})
+	applyProfileTenant(root)
+
+	tests := []struct {
+		name    string
+		tenant  string
+		args    []string
+		want    string
+		wantErr bool
+	}{
+		{name: "tenant given", tenant: "tn_profile", args: []string{"tn_1", "a.b"}, want: "tn_1 a.b"},
+		{name: "tenant from profile", tenant: "tn_profile", args: []string{"a.b"}, want: "tn_profile a.b"},
+		{name: "no profile tenant", args: []string{"a.b"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// initConfig runs on Execute and reads the tenant from the config
+			useConfig(t, "tenant: "+tt.tenant+"\n", "")
+			got = nil
+			root.SetArgs(append([]string{"publish"}, tt.args...))
+			root.SilenceErrors, root.SilenceUsage = true, true
+			err := root.Execute()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && strings.Join(got, " ") != tt.want {
+				t.Errorf("ran with %v, want %s", got, tt.want)
+			}
+		})
+	}
+}
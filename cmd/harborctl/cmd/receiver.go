@@ -0,0 +1,179 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// receivedRequest is one request the fake-receiver's /received lists
+type receivedRequest struct {
+	Seq        int64               `json:"seq"`
+	ReceivedAt time.Time           `json:"received_at"`
+	Method     string              `json:"method"`
+	Path       string              `json:"path"`
+	Headers    map[string][]string `json:"headers"`
+	Body       string              `json:"body"`
+	Signature  struct {
+		Checked bool   `json:"checked"`
+		Valid   bool   `json:"valid"`
+		Error   string `json:"error,omitempty"`
+	} `json:"signature"`
+	Status int `json:"status"`
+}
+
+// receiverCmd groups the commands that inspect the fake-receiver's recorded requests
+var receiverCmd = &cobra.Command{
+	Use:   "receiver",
+	Short: "Inspect what the fake-receiver was sent",
+	Long: `Inspect and reset the requests the fake-receiver recorded, so tests can assert
+exactly what was delivered instead of reading its logs.`,
+}
+
+// receiverReceivedCmd lists recorded requests, optionally waiting for them
+var receiverReceivedCmd = &cobra.Command{
+	Use:   "received",
+	Short: "List the requests the fake-receiver recorded",
+	Long: `List the requests the fake-receiver recorded on /hook, oldest first, with their
+headers, body, signature verdict and the status it answered.
+
+With --wait-for, poll until at least that many requests match and fail if they
+don't arrive within --timeout, which makes it usable as a test assertion.
+
+Example:
+  harborctl receiver received
+  harborctl receiver received --delivery-id 123e4567-e89b-12d3-a456-426614174000
+  harborctl receiver received --valid=false --wait-for 1 --timeout 30s`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base, _ := cmd.Flags().GetString("receiver-url")
+		waitFor, _ := cmd.Flags().GetInt("wait-for")
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		path, _ := cmd.Flags().GetString("path")
+		deliveryID, _ := cmd.Flags().GetString("delivery-id")
+		after, _ := cmd.Flags().GetInt64("after")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		params := url.Values{}
+		if path != "" {
+			params.Add("path", path)
+		}
+		if deliveryID != "" {
+			params.Add("delivery_id", deliveryID)
+		}
+		if after > 0 {
+			params.Add("after", fmt.Sprint(after))
+		}
+		if limit > 0 {
+			params.Add("limit", fmt.Sprint(limit))
+		}
+		if cmd.Flags().Changed("valid") {
+			valid, _ := cmd.Flags().GetBool("valid")
+			params.Add("valid", fmt.Sprint(valid))
+		}
+
+		deadline := time.Now().Add(timeout)
+		var requests []receivedRequest
+		for {
+			var err error
+			if requests, err = fetchReceived(base, params); err != nil {
+				return err
+			}
+			if len(requests) >= waitFor {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("received %d matching requests within %s, want at least %d", len(requests), timeout, waitFor)
+			}
+			time.Sleep(250 * time.Millisecond)
+		}
+
+		if outputJSON {
+			printOutput(map[string]any{"requests": requests})
+			return nil
+		}
+		if len(requests) == 0 {
+			fmt.Println("No requests received")
+			return nil
+		}
+		for _, r := range requests {
+			verdict := "unchecked"
+			if r.Signature.Checked && r.Signature.Valid {
+				verdict = "valid"
+			} else if r.Signature.Checked {
+				verdict = "invalid: " + r.Signature.Error
+			}
+			fmt.Printf("#%d  %s  %s %s  -> %d  signature %s\n", r.Seq, r.ReceivedAt.Local().Format("2006-01-02 15:04:05"), r.Method, r.Path, r.Status, verdict)
+			fmt.Printf("    %s\n", r.Body)
+		}
+		return nil
+	},
+}
+
+// receiverResetCmd clears the fake-receiver's recorded requests
+var receiverResetCmd = &cobra.Command{
+	Use:   "reset",
+	Short: "Forget the requests the fake-receiver recorded",
+	Long: `Forget the fake-receiver's recorded requests and restart its FAIL_FIRST_N count,
+so the next test starts clean.
+
+Example:
+  harborctl receiver reset`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		base, _ := cmd.Flags().GetString("receiver-url")
+		resp, err := http.Post(strings.TrimSuffix(base, "/")+"/reset", "application/json", nil)
+		if err != nil {
+			return fmt.Errorf("HTTP request failed: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("HTTP error: %s", resp.Status)
+		}
+		fmt.Println("Receiver reset")
+		return nil
+	},
+}
+
+// fetchReceived calls the fake-receiver's /received with params
+func fetchReceived(base string, params url.Values) ([]receivedRequest, error) {
+	resp, err := http.Get(strings.TrimSuffix(base, "/") + "/received?" + params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(b)))
+	}
+	var result struct {
+		Requests []receivedRequest `json:"requests"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return result.Requests, nil
+}
+
+func init() {
+	rootCmd.AddCommand(receiverCmd)
+	receiverCmd.AddCommand(receiverReceivedCmd)
+	receiverCmd.AddCommand(receiverResetCmd)
+
+	receiverCmd.PersistentFlags().String("receiver-url", "http://localhost:8081", "fake-receiver base URL")
+
+	// Flags for receiver received
+	receiverReceivedCmd.Flags().String("path", "", "only requests to this path, e.g. /hook")
+	receiverReceivedCmd.Flags().String("delivery-id", "", "only requests carrying this delivery ID")
+	receiverReceivedCmd.Flags().Int64("after", 0, "only requests after this sequence number")
+	receiverReceivedCmd.Flags().Int("limit", 0, "only the newest n matching requests (0 lists every one kept)")
+	receiverReceivedCmd.Flags().Bool("valid", false, "only requests whose signature checked valid (or invalid with --valid=false)")
+	receiverReceivedCmd.Flags().Int("wait-for", 0, "wait until at least this many requests match")
+	receiverReceivedCmd.Flags().Duration("timeout", 30*time.Second, "how long --wait-for waits")
+}
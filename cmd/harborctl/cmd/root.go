@@ -1,14 +1,11 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
-	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 	"time"
@@ -16,6 +13,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/protobuf/encoding/protojson"
 	"google.golang.org/protobuf/proto"
@@ -34,6 +32,12 @@ var (
 	outputJSON bool
 	prettyJSON bool
 	jwtToken   string
+
+	tlsEnabled    bool
+	caCert        string
+	clientCert    string
+	clientKey     string
+	tlsServerName string
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -61,6 +65,7 @@ func showASCIIArt(cmd *cobra.Command, args []string) {
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
+	applyProfileTenant(rootCmd)
 	return rootCmd.Execute()
 }
 
@@ -73,8 +78,14 @@ func init() {
 	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 30*time.Second, "request timeout")
 	rootCmd.PersistentFlags().BoolVar(&useHTTP, "http", false, "use HTTP instead of gRPC")
 	rootCmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "output in JSON format")
-	rootCmd.PersistentFlags().BoolVar(&prettyJSON, "pretty", false, "use jq for pretty JSON formatting (requires jq)")
+	rootCmd.PersistentFlags().BoolVar(&prettyJSON, "pretty", false, "pretty-print JSON output, in color on a terminal")
 	rootCmd.PersistentFlags().StringVar(&jwtToken, "token", "", "JWT token for authentication (overrides JWT_TOKEN env var)")
+	rootCmd.PersistentFlags().BoolVar(&tlsEnabled, "tls", false, "dial gRPC over TLS (implied by --ca-cert and --client-cert)")
+	rootCmd.PersistentFlags().StringVar(&caCert, "ca-cert", "", "CA certificate (PEM) to verify the server with")
+	rootCmd.PersistentFlags().StringVar(&clientCert, "client-cert", "", "client certificate (PEM) for mTLS")
+	rootCmd.PersistentFlags().StringVar(&clientKey, "client-key", "", "client private key (PEM) for mTLS")
+	rootCmd.PersistentFlags().StringVar(&tlsServerName, "tls-server-name", "", "server name to verify instead of the --server host, e.g. for dev certs")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "config profile to use (overrides "+profileEnv+" env var)")
 
 	// Bind flags to viper
 	viper.BindPFlag("server", rootCmd.PersistentFlags().Lookup("server"))
@@ -101,41 +112,58 @@ func initConfig() {
 	viper.AutomaticEnv()
 
 	viper.ReadInConfig()
+	cobra.CheckErr(loadProfile())
 
-	// Override global variables with config values if flags weren't explicitly set
+	// Override global variables with config values (the profile's first) if flags weren't explicitly set
 	if !rootCmd.PersistentFlags().Changed("server") {
-		if s := viper.GetString("server"); s != "" {
+		if s := setting("server").GetString("server"); s != "" {
 			serverAddr = s
 		}
 	}
 	if !rootCmd.PersistentFlags().Changed("timeout") {
-		if d := viper.GetDuration("timeout"); d > 0 {
+		if d := setting("timeout").GetDuration("timeout"); d > 0 {
 			timeout = d
 		}
 	}
 	if !rootCmd.PersistentFlags().Changed("http") {
-		useHTTP = viper.GetBool("http")
+		useHTTP = setting("http").GetBool("http")
 	}
 	if !rootCmd.PersistentFlags().Changed("json") {
-		outputJSON = viper.GetBool("json")
+		outputJSON = setting("json").GetBool("json")
 	}
 	if !rootCmd.PersistentFlags().Changed("pretty") {
-		prettyJSON = viper.GetBool("pretty")
+		prettyJSON = setting("pretty").GetBool("pretty")
 	}
+	defaultTenant = setting("tenant").GetString("tenant")
 	if !rootCmd.PersistentFlags().Changed("token") {
-		if t := viper.GetString("token"); t != "" {
+		if t := setting("token").GetString("token"); t != "" {
 			jwtToken = t
 		} else if t := os.Getenv("JWT_TOKEN"); t != "" {
 			jwtToken = t
+		} else {
+			jwtToken = cachedLoginToken()
 		}
 	}
 }
 
 // getClient returns a gRPC client for the webhook service
 func getClient() (webhookv1.WebhookServiceClient, func(), error) {
+	creds := insecure.NewCredentials()
+	if grpcTLS() {
+		tlsCfg, err := tlsConfig()
+		if err != nil {
+			return nil, nil, err
+		}
+		creds = credentials.NewTLS(tlsCfg)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 
-	conn, err := grpc.DialContext(ctx, serverAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if jwtToken != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(tokenCredentials(jwtToken)))
+	}
+	conn, err := grpc.DialContext(ctx, serverAddr, opts...)
 	if err != nil {
 		cancel()
 		return nil, nil, fmt.Errorf("failed to connect: %w", err)
@@ -153,9 +181,13 @@ func getClient() (webhookv1.WebhookServiceClient, func(), error) {
 // makeHTTPRequest makes an HTTP request to the REST API
 func makeHTTPRequest(method, path string, body interface{}) (*http.Response, error) {
 	// Create HTTP client with TLS support for HTTPS
+	tlsCfg, err := tlsConfig()
+	if err != nil {
+		return nil, err
+	}
 	tr := &http.Transport{
-		TLSClientConfig:    &tls.Config{InsecureSkipVerify: true}, // For development with self-signed certs
-		DisableCompression: true,                                  // Disable compression to avoid parsing issues
+		TLSClientConfig:    tlsCfg,
+		DisableCompression: true, // Disable compression to avoid parsing issues
 	}
 	client := &http.Client{
 		Timeout:   timeout,
@@ -192,33 +224,6 @@ func makeHTTPRequest(method, path string, body interface{}) (*http.Response, err
 	return client.Do(req)
 }
 
-// checkJQAvailable checks if jq is available in PATH
-func checkJQAvailable() bool {
-	_, err := exec.LookPath("jq")
-	return err == nil
-}
-
-// formatWithJQ formats JSON using jq for pretty printing
-func formatWithJQ(jsonData []byte) (string, error) {
-	if !checkJQAvailable() {
-		return "", fmt.Errorf("jq not found in PATH")
-	}
-
-	cmd := exec.Command("jq", ".")
-	cmd.Stdin = bytes.NewReader(jsonData)
-
-	var out bytes.Buffer
-	var stderr bytes.Buffer
-	cmd.Stdout = &out
-	cmd.Stderr = &stderr
-
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("jq formatting failed: %s", stderr.String())
-	}
-
-	return out.String(), nil
-}
-
 // printOutput prints the response in the requested format
 func printOutput(v interface{}) {
 	if outputJSON {
@@ -228,19 +233,14 @@ func printOutput(v interface{}) {
 		if msg, ok := v.(proto.Message); ok {
 			// Use protojson for protobuf messages
 			opts := protojson.MarshalOptions{
-				Multiline:       !prettyJSON, // Don't use multiline if we're using jq
+				Multiline:       true,
 				Indent:          "  ",
 				EmitUnpopulated: false,
 			}
 			jsonData, err = opts.Marshal(msg)
 		} else {
 			// Use standard JSON for other types
-			if prettyJSON {
-				// Compact JSON if we're going to format with jq
-				jsonData, err = json.Marshal(v)
-			} else {
-				jsonData, err = json.MarshalIndent(v, "", "  ")
-			}
+			jsonData, err = json.MarshalIndent(v, "", "  ")
 		}
 
 		if err != nil {
@@ -249,28 +249,14 @@ func printOutput(v interface{}) {
 		}
 
 		if prettyJSON {
-			formatted, jqErr := formatWithJQ(jsonData)
-			if jqErr != nil {
-				// Fall back to standard pretty printing if jq fails
-				fmt.Fprintf(os.Stderr, "Warning: %v, falling back to standard formatting\n", jqErr)
-				if msg, ok := v.(proto.Message); ok {
-					opts := protojson.MarshalOptions{
-						Multiline:       true,
-						Indent:          "  ",
-						EmitUnpopulated: false,
-					}
-					jsonData, _ = opts.Marshal(msg)
-				} else {
-					jsonData, _ = json.MarshalIndent(v, "", "  ")
-				}
-				fmt.Println(string(jsonData))
-			} else {
-				// Print jq-formatted output (already includes newline)
+			// Re-indent so protojson's randomized spacing is stable, and color on a terminal
+			formatted, fmtErr := formatJSON(jsonData, colorOutput())
+			if fmtErr == nil {
 				fmt.Print(formatted)
+				return
 			}
-		} else {
-			fmt.Println(string(jsonData))
 		}
+		fmt.Println(string(jsonData))
 	} else {
 		// Human-readable format
 		fmt.Printf("%+v\n", v)
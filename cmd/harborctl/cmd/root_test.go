@@ -1,81 +1,57 @@
 package cmd
 
 import (
-	"os/exec"
 	"testing"
 	"time"
 )
 
-func TestCheckJQAvailable(t *testing.T) {
-	tests := []struct {
-		name string
-		want bool
-	}{
-		{
-			name: "check jq availability",
-			want: func() bool {
-				_, err := exec.LookPath("jq")
-				return err == nil
-			}(),
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := checkJQAvailable()
-			if got != tt.want {
-				t.Errorf("checkJQAvailable() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
-func TestFormatWithJQ(t *testing.T) {
+func TestFormatJSON(t *testing.T) {
 	tests := []struct {
 		name     string
 		jsonData []byte
+		color    bool
+		want     string
 		wantErr  bool
-		skipTest bool
 	}{
 		{
 			name:     "valid json",
 			jsonData: []byte(`{"key":"value","number":42}`),
-			wantErr:  false,
-			skipTest: !checkJQAvailable(),
+			want:     "{\n  \"key\": \"value\",\n  \"number\": 42\n}\n",
 		},
 		{
 			name:     "invalid json",
 			jsonData: []byte(`{"key":"value",}`),
 			wantErr:  true,
-			skipTest: !checkJQAvailable(),
 		},
 		{
 			name:     "empty json object",
 			jsonData: []byte(`{}`),
-			wantErr:  false,
-			skipTest: !checkJQAvailable(),
+			want:     "{}\n",
 		},
 		{
 			name:     "json array",
 			jsonData: []byte(`[1,2,3]`),
-			wantErr:  false,
-			skipTest: !checkJQAvailable(),
+			want:     "[\n  1,\n  2,\n  3\n]\n",
+		},
+		{
+			name:     "color",
+			jsonData: []byte(`{"k":"a \"quoted\" value","n":null,"b":true}`),
+			color:    true,
+			want: "{\n  " + colorKey + `"k"` + colorReset + ": " + colorString + `"a \"quoted\" value"` + colorReset + ",\n  " +
+				colorKey + `"n"` + colorReset + ": " + colorNull + "null" + colorReset + ",\n  " +
+				colorKey + `"b"` + colorReset + ": true\n}\n",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.skipTest {
-				t.Skip("jq not available, skipping test")
-			}
-
-			got, err := formatWithJQ(tt.jsonData)
+			got, err := formatJSON(tt.jsonData, tt.color)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("formatWithJQ() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("formatJSON() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !tt.wantErr && got == "" {
-				t.Errorf("formatWithJQ() returned empty string for valid JSON")
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("formatJSON() = %q, want %q", got, tt.want)
 			}
 		})
 	}
@@ -0,0 +1,242 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"github.com/spf13/cobra"
+)
+
+// eventSchemaCmd represents the event schema command
+var eventSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Manage the JSON Schemas event payloads must match",
+	Long: `Register JSON Schemas per event type. Once an event type has a schema, publishes whose
+payload doesn't match its latest version are rejected with the violations listed.`,
+}
+
+// schemaCreateCmd represents the event schema create command
+var schemaCreateCmd = &cobra.Command{
+	Use:   "create [tenant-id] [event-type] [schema-file]",
+	Short: "Register a new schema version for an event type",
+	Long: `Register the JSON Schema in a file (or - for stdin) as the next version of an event
+type's schema. It applies to events published afterwards.
+
+Example:
+  harborctl event schema create tn_123 order.created order.schema.json`,
+	Args: cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID, eventType := args[0], args[1]
+
+		var (
+			raw []byte
+			err error
+		)
+		if args[2] == "-" {
+			raw, err = io.ReadAll(os.Stdin)
+		} else {
+			raw, err = os.ReadFile(args[2])
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read schema: %w", err)
+		}
+		schema, err := parseJSON(string(raw))
+		if err != nil {
+			return fmt.Errorf("schema must be a JSON object: %w", err)
+		}
+
+		if useHTTP {
+			payload := map[string]interface{}{
+				"eventType": eventType,
+				"schema":    json.RawMessage(raw),
+			}
+
+			resp, err := makeHTTPRequest("POST", fmt.Sprintf("/v1/tenants/%s/schemas", tenantID), payload)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.CreateEventSchema(context.Background(), &webhookv1.CreateEventSchemaRequest{
+			TenantId:  tenantID,
+			EventType: eventType,
+			Schema:    schema,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create schema: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+		} else {
+			fmt.Printf("Registered schema v%d for %s\n", resp.Schema.Version, resp.Schema.EventType)
+		}
+		return nil
+	},
+}
+
+// schemaListCmd represents the event schema list command
+var schemaListCmd = &cobra.Command{
+	Use:   "list [tenant-id]",
+	Short: "List registered schemas",
+	Long: `List the latest schema version of each event type, or with --event-type every version of
+one event type.
+
+Examples:
+  harborctl event schema list tn_123
+  harborctl event schema list tn_123 --event-type order.created`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID := args[0]
+		eventType, _ := cmd.Flags().GetString("event-type")
+
+		if useHTTP {
+			path := fmt.Sprintf("/v1/tenants/%s/schemas", tenantID)
+			if eventType != "" {
+				path += "?eventType=" + url.QueryEscape(eventType)
+			}
+
+			resp, err := makeHTTPRequest("GET", path, nil)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.ListEventSchemas(context.Background(), &webhookv1.ListEventSchemasRequest{
+			TenantId:  tenantID,
+			EventType: eventType,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to list schemas: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+			return nil
+		}
+		if len(resp.Schemas) == 0 {
+			fmt.Println("No schemas registered")
+			return nil
+		}
+		for _, s := range resp.Schemas {
+			fmt.Printf("%s v%d  registered %s\n", s.EventType, s.Version, s.CreatedAt.AsTime().Format("2006-01-02 15:04:05"))
+		}
+		return nil
+	},
+}
+
+// schemaGetCmd represents the event schema get command
+var schemaGetCmd = &cobra.Command{
+	Use:   "get [tenant-id] [event-type]",
+	Short: "Show an event type's schema",
+	Long: `Print a version of an event type's schema, the latest unless --version is given.
+
+Example:
+  harborctl event schema get tn_123 order.created --version 2`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID, eventType := args[0], args[1]
+		version, _ := cmd.Flags().GetInt32("version")
+
+		if useHTTP {
+			path := fmt.Sprintf("/v1/tenants/%s/schemas/%s", tenantID, url.PathEscape(eventType))
+			if version > 0 {
+				path += fmt.Sprintf("?version=%d", version)
+			}
+
+			resp, err := makeHTTPRequest("GET", path, nil)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.GetEventSchema(context.Background(), &webhookv1.GetEventSchemaRequest{
+			TenantId:  tenantID,
+			EventType: eventType,
+			Version:   version,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get schema: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+			return nil
+		}
+		doc, _ := json.MarshalIndent(resp.Schema.Schema.AsMap(), "", "  ")
+		fmt.Printf("%s v%d:\n%s\n", resp.Schema.EventType, resp.Schema.Version, doc)
+		return nil
+	},
+}
+
+func init() {
+	eventCmd.AddCommand(eventSchemaCmd)
+	eventSchemaCmd.AddCommand(schemaCreateCmd)
+	eventSchemaCmd.AddCommand(schemaListCmd)
+	eventSchemaCmd.AddCommand(schemaGetCmd)
+
+	schemaListCmd.Flags().String("event-type", "", "list every version of this event type")
+	schemaGetCmd.Flags().Int32("version", 0, "schema version (default latest)")
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats [tenant-id]",
+	Short: "Show delivery success rate, latency, retries and failure reasons",
+	Long: `Show a tenant's deliveries over a window: counts by status, success rate (delivered out of
+delivered and dead), p50/p95/p99 latency of successful attempts, retries and the most common
+failure reasons, across all endpoints and per endpoint.
+
+Example:
+  harborctl stats tn_123
+  harborctl stats tn_123 --window 1h --event-type order.created
+  harborctl stats tn_123 --endpoint-id ep_456 --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tenantID := args[0]
+		endpointID, _ := cmd.Flags().GetString("endpoint-id")
+		eventType, _ := cmd.Flags().GetString("event-type")
+		window, _ := cmd.Flags().GetDuration("window")
+
+		if useHTTP {
+			params := url.Values{}
+			if endpointID != "" {
+				params.Add("endpointId", endpointID)
+			}
+			if eventType != "" {
+				params.Add("eventType", eventType)
+			}
+			if window > 0 {
+				params.Add("windowSeconds", strconv.Itoa(int(window.Seconds())))
+			}
+
+			resp, err := makeHTTPRequest("GET", fmt.Sprintf("/v1/tenants/%s/analytics/deliveries?%s", tenantID, params.Encode()), nil)
+			if err != nil {
+				return fmt.Errorf("HTTP request failed: %w", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != 200 {
+				return fmt.Errorf("HTTP error: %s", resp.Status)
+			}
+
+			var result map[string]interface{}
+			if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+				return fmt.Errorf("failed to decode response: %w", err)
+			}
+
+			printOutput(result)
+			return nil
+		}
+
+		client, cleanup, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to connect: %w", err)
+		}
+		defer cleanup()
+
+		resp, err := client.GetDeliveryStats(context.Background(), &webhookv1.GetDeliveryStatsRequest{
+			TenantId:      tenantID,
+			EndpointId:    endpointID,
+			EventType:     eventType,
+			WindowSeconds: int32(window.Seconds()),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get delivery stats: %w", err)
+		}
+
+		if outputJSON {
+			printOutput(resp)
+			return nil
+		}
+
+		fmt.Printf("Deliveries over the last %s:\n", time.Duration(resp.WindowSeconds)*time.Second)
+		printDeliveryStats("  ", resp.Totals)
+		if endpointID != "" {
+			return nil // the totals are that endpoint's
+		}
+		for _, ep := range resp.Endpoints {
+			fmt.Printf("\nEndpoint %s:\n", ep.EndpointId)
+			printDeliveryStats("  ", ep)
+		}
+		return nil
+	},
+}
+
+// printDeliveryStats prints one set of delivery stats, each line indented by indent
+func printDeliveryStats(indent string, st *webhookv1.DeliveryStats) {
+	if st.GetTotal() == 0 {
+		fmt.Printf("%snone\n", indent)
+		return
+	}
+	fmt.Printf("%sTotal: %d (delivered %d, failed %d, dead %d, queued %d, inflight %d, parked %d)\n",
+		indent, st.Total, st.Delivered, st.Failed, st.Dead, st.Queued, st.Inflight, st.Parked)
+	fmt.Printf("%sSuccess rate: %.1f%%\n", indent, st.SuccessRate*100)
+	fmt.Printf("%sLatency: p50 %dms, p95 %dms, p99 %dms\n", indent, st.LatencyP50Ms, st.LatencyP95Ms, st.LatencyP99Ms)
+	fmt.Printf("%sRetries: %d\n", indent, st.Retries)
+	if len(st.TopFailures) > 0 {
+		reasons := make([]string, 0, len(st.TopFailures))
+		for _, f := range st.TopFailures {
+			reasons = append(reasons, fmt.Sprintf("%s x%d", f.Reason, f.Count))
+		}
+		fmt.Printf("%sTop failures: %s\n", indent, strings.Join(reasons, ", "))
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().String("endpoint-id", "", "only deliveries to this endpoint")
+	statsCmd.Flags().String("event-type", "", "only deliveries of this event type")
+	statsCmd.Flags().Duration("window", 0, "how far back to look (default 24h, max 168h)")
+}
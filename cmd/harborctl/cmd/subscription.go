@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/austindbirch/harbor_hook/cmd/harborctl/cmd/ascii"
 	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
@@ -25,20 +26,32 @@ var createSubscriptionCmd = &cobra.Command{
 	Use:   "create [tenant-id] [endpoint-id] [event-type]",
 	Short: "Create a new webhook subscription",
 	Long: `Create a new webhook subscription linking an endpoint to an event type.
+
+Use --include-fields and --exclude-fields to limit the payload fields delivered
+to the endpoint (dot paths reach into nested objects).
 	
 Example:
-  harborctl subscription create tn_123 ep_456 appointment.created`,
+  harborctl subscription create tn_123 ep_456 appointment.created
+  harborctl subscription create tn_123 ep_456 patient.updated --include-fields id,status --exclude-fields contact.ssn`,
 	Args: cobra.ExactArgs(3),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		tenantID := args[0]
 		endpointID := args[1]
 		eventType := args[2]
+		includeFields, _ := cmd.Flags().GetStringSlice("include-fields")
+		excludeFields, _ := cmd.Flags().GetStringSlice("exclude-fields")
 
 		if useHTTP {
 			payload := map[string]interface{}{
 				"endpointId": endpointID,
 				"eventType":  eventType,
 			}
+			if len(includeFields) > 0 {
+				payload["includeFields"] = includeFields
+			}
+			if len(excludeFields) > 0 {
+				payload["excludeFields"] = excludeFields
+			}
 
 			resp, err := makeHTTPRequest("POST", fmt.Sprintf("/v1/tenants/%s/subscriptions", tenantID), payload)
 			if err != nil {
@@ -67,9 +80,11 @@ Example:
 
 		ctx := context.Background()
 		req := &webhookv1.CreateSubscriptionRequest{
-			TenantId:   tenantID,
-			EndpointId: endpointID,
-			EventType:  eventType,
+			TenantId:      tenantID,
+			EndpointId:    endpointID,
+			EventType:     eventType,
+			IncludeFields: includeFields,
+			ExcludeFields: excludeFields,
 		}
 
 		resp, err := client.CreateSubscription(ctx, req)
@@ -84,6 +99,12 @@ Example:
 			fmt.Printf("  Tenant ID: %s\n", resp.Subscription.TenantId)
 			fmt.Printf("  Endpoint ID: %s\n", resp.Subscription.EndpointId)
 			fmt.Printf("  Event Type: %s\n", resp.Subscription.EventType)
+			if len(resp.Subscription.IncludeFields) > 0 {
+				fmt.Printf("  Include Fields: %s\n", strings.Join(resp.Subscription.IncludeFields, ", "))
+			}
+			if len(resp.Subscription.ExcludeFields) > 0 {
+				fmt.Printf("  Exclude Fields: %s\n", strings.Join(resp.Subscription.ExcludeFields, ", "))
+			}
 			fmt.Printf("  Created: %s\n", resp.Subscription.CreatedAt.AsTime().Format("2006-01-02 15:04:05"))
 		}
 
@@ -94,4 +115,8 @@ Example:
 func init() {
 	rootCmd.AddCommand(subscriptionCmd)
 	subscriptionCmd.AddCommand(createSubscriptionCmd)
+
+	// Flags for create subscription
+	createSubscriptionCmd.Flags().StringSlice("include-fields", nil, "payload fields to deliver (dot paths, comma-separated)")
+	createSubscriptionCmd.Flags().StringSlice("exclude-fields", nil, "payload fields to strip before delivery (dot paths, comma-separated)")
 }
@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// issue signs a certificate for cn with parent (self-signed when parent is nil), writing the
+// certificate and key as PEM files in dir
+func issue(t *testing.T, dir, cn string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, tmpl *x509.Certificate) (*x509.Certificate, *ecdsa.PrivateKey, string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl.SerialNumber = big.NewInt(time.Now().UnixNano())
+	tmpl.Subject = pkix.Name{CommonName: cn}
+	tmpl.NotBefore, tmpl.NotAfter = time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	if parent == nil {
+		parent, parentKey = tmpl, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _ := x509.ParseCertificate(der)
+	keyDER, _ := x509.MarshalECPrivateKey(key)
+	certFile, keyFile := filepath.Join(dir, cn+".pem"), filepath.Join(dir, cn+"-key.pem")
+	_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600)
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600)
+	return cert, key, certFile, keyFile
+}
+
+// setFlag sets a global flag for one test
+func setFlag(t *testing.T, name, value string) {
+	t.Helper()
+	f := rootCmd.PersistentFlags().Lookup(name)
+	if err := f.Value.Set(value); err != nil {
+		t.Fatal(err)
+	}
+	f.Changed = true
+	t.Cleanup(func() {
+		_ = f.Value.Set(f.DefValue)
+		f.Changed = false
+	})
+}
+
+type pingServer struct {
+	webhookv1.UnimplementedWebhookServiceServer
+}
+
+func (pingServer) Ping(context.Context, *webhookv1.PingRequest) (*webhookv1.PingResponse, error) {
+	return &webhookv1.PingResponse{Message: "pong"}, nil
+}
+
+func TestGetClient_MTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey, caFile, _ := issue(t, dir, "ca", nil, nil, &x509.Certificate{IsCA: true, BasicConstraintsValid: true, KeyUsage: x509.KeyUsageCertSign})
+	_, _, serverCert, serverKey := issue(t, dir, "server", ca, caKey, &x509.Certificate{DNSNames: []string{"ingest.harborhook.local"}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}})
+	_, _, clientCertFile, clientKeyFile := issue(t, dir, "client", ca, caKey, &x509.Certificate{ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+
+	pair, err := tls.LoadX509KeyPair(serverCert, serverKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer(grpc.Creds(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{pair},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+	})))
+	webhookv1.RegisterWebhookServiceServer(srv, pingServer{})
+	go func() { _ = srv.Serve(lis) }()
+	defer srv.Stop()
+
+	origAddr := serverAddr
+	serverAddr = lis.Addr().String()
+	defer func() { serverAddr = origAddr }()
+
+	ping := func() error {
+		client, cleanup, err := getClient()
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = client.Ping(ctx, &webhookv1.PingRequest{})
+		return err
+	}
+
+	setFlag(t, "ca-cert", caFile)
+	setFlag(t, "tls-server-name", "ingest.harborhook.local")
+	if err := ping(); err == nil {
+		t.Error("Ping() without a client certificate succeeded, want the server to refuse it")
+	}
+
+	setFlag(t, "client-cert", clientCertFile)
+	if _, err := tlsConfig(); err == nil {
+		t.Error("tlsConfig() with a certificate and no key succeeded, want an error")
+	}
+	setFlag(t, "client-key", clientKeyFile)
+	if err := ping(); err != nil {
+		t.Errorf("Ping() over mTLS = %v, want nil", err)
+	}
+
+	setFlag(t, "tls-server-name", "other.local")
+	if err := ping(); err == nil {
+		t.Error("Ping() verifying the wrong name succeeded, want a verification error")
+	}
+}
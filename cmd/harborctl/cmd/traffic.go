@@ -8,29 +8,29 @@ import (
 	"math/rand"
 	"net/http"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/austindbirch/harbor_hook/cmd/harborctl/cmd/ascii"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 // TrafficConfig holds the configuration for traffic generation
 type TrafficConfig struct {
-	Duration      int     `json:"duration"`
-	Volume        int     `json:"volume"`
-	TenantID      string  `json:"tenant_id"`
-	WebhookURL    string  `json:"webhook_url"`
-	EventType     string  `json:"event_type"`
-	ServerHost    string  `json:"server_host"`
-	JWKSHost      string  `json:"jwks_host"`
-	Mode          string  `json:"mode"`
-	FailureRate   float64 `json:"failure_rate"`   // Percentage of requests that should fail (0-100)
-	Burst         bool    `json:"burst"`          // Whether to generate burst traffic after normal traffic
-	BurstVolume   int     `json:"burst_volume"`   // Requests per second during burst (default: 50)
-	BurstDuration int     `json:"burst_duration"` // Duration of burst in seconds (default: 30)
+	Duration      int     `json:"duration" mapstructure:"duration"`
+	Volume        int     `json:"volume" mapstructure:"volume"`
+	TenantID      string  `json:"tenant_id" mapstructure:"tenant_id"`
+	WebhookURL    string  `json:"webhook_url" mapstructure:"webhook_url"`
+	EventType     string  `json:"event_type" mapstructure:"event_type"`
+	ServerHost    string  `json:"server_host" mapstructure:"server_host"`
+	JWKSHost      string  `json:"jwks_host" mapstructure:"jwks_host"`
+	Mode          string  `json:"mode" mapstructure:"mode"`
+	FailureRate   float64 `json:"failure_rate" mapstructure:"failure_rate"`     // Percentage of requests that should fail (0-100)
+	Burst         bool    `json:"burst" mapstructure:"burst"`                   // Whether to generate burst traffic after normal traffic
+	BurstVolume   int     `json:"burst_volume" mapstructure:"burst_volume"`     // Requests per second during burst (default: 50)
+	BurstDuration int     `json:"burst_duration" mapstructure:"burst_duration"` // Duration of burst in seconds (default: 30)
 }
 
 // TrafficSummary holds the summary of generated traffic
@@ -75,53 +75,241 @@ Supports two modes:
 // generateCmd represents the generate subcommand
 var generateCmd = &cobra.Command{
 	Use:   "generate",
-	Short: "Generate test traffic interactively",
-	Long: `Start an interactive session to configure and generate test traffic.
-You'll be prompted for parameters like duration, volume, tenant ID, etc.
+	Short: "Generate test traffic",
+	Long: `Configure and generate test traffic.
+Without flags, you'll be prompted for parameters like duration, volume, tenant ID, etc.
 
 Choose between two traffic modes:
 • good: Generates mostly successful traffic with configurable failure rate and optional burst (default: 120s, 10 req/s, 5% failures)
 • bad:  Generates DLQ traffic (default: 30s, 5 req/s) for testing failures
 
-After confirmation, the command will generate the specified traffic pattern.`,
+After confirmation, the command will generate the specified traffic pattern.
+
+Passing --config or any parameter flag runs without prompts or confirmation, for CI. Unset
+parameters take the mode's defaults; flags override the config file. The config file is YAML
+with the keys mode, duration, volume, tenant_id, webhook_url, event_type, jwks_host,
+failure_rate, burst, burst_volume and burst_duration (durations in seconds). Note that here
+--config names this file, not harborctl's own config.
+
+Example:
+  harborctl traffic generate --mode good --duration 60 --rps 20 --failure-rate 10 --summary-json summary.json
+  harborctl traffic generate --config ci/traffic.yaml --burst --burst-rps 50`,
 	RunE: runGenerateTraffic,
 }
 
+// trafficFlags are the flags that set a TrafficConfig parameter, by the config key they set
+var trafficFlags = map[string]string{
+	"mode":           "mode",
+	"duration":       "duration",
+	"rps":            "volume",
+	"tenant":         "tenant_id",
+	"webhook-url":    "webhook_url",
+	"event-type":     "event_type",
+	"jwks-host":      "jwks_host",
+	"failure-rate":   "failure_rate",
+	"burst":          "burst",
+	"burst-rps":      "burst_volume",
+	"burst-duration": "burst_duration",
+}
+
 func init() {
 	rootCmd.AddCommand(trafficCmd)
 	trafficCmd.AddCommand(generateCmd)
+	addTrafficFlags(generateCmd)
+}
+
+// addTrafficFlags registers generate's flags. Defaults depend on the mode, so they're applied
+// by loadTrafficConfig rather than here.
+func addTrafficFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.String("config", "", "YAML file of traffic parameters; runs without prompts")
+	f.String("mode", "", "traffic mode: good or bad (default good)")
+	f.Int("duration", 0, "traffic duration in seconds")
+	f.Int("rps", 0, "requests per second")
+	f.String("tenant", "", "tenant ID")
+	f.String("webhook-url", "", "URL of the endpoint traffic is delivered to")
+	f.String("event-type", "", "event type to publish")
+	f.String("jwks-host", "", "JWKS server (host:port) tokens are fetched from")
+	f.Float64("failure-rate", 0, "percentage of events sent to a failing endpoint, good mode only (0-100)")
+	f.Bool("burst", false, "generate burst traffic after normal traffic, good mode only")
+	f.Int("burst-rps", 0, "requests per second during the burst")
+	f.Int("burst-duration", 0, "burst duration in seconds")
+	f.String("summary-json", "", "write the traffic summary as JSON to this file")
+}
+
+// nonInteractive reports whether generate was given its parameters by flag or config file
+func nonInteractive(cmd *cobra.Command) bool {
+	if cmd.Flags().Changed("config") {
+		return true
+	}
+	for name := range trafficFlags {
+		if cmd.Flags().Changed(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// loadTrafficConfig builds the config from the mode's defaults, then the --config file, then
+// the flags that were set, and validates it
+func loadTrafficConfig(cmd *cobra.Command) (*TrafficConfig, error) {
+	var file *viper.Viper
+	if path, _ := cmd.Flags().GetString("config"); path != "" {
+		file = viper.New()
+		file.SetConfigFile(path)
+		if err := file.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("read traffic config: %w", err)
+		}
+	}
+
+	mode := "good"
+	if file != nil && file.IsSet("mode") {
+		mode = file.GetString("mode")
+	}
+	if cmd.Flags().Changed("mode") {
+		mode, _ = cmd.Flags().GetString("mode")
+	}
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode == "dlq" {
+		mode = "bad"
+	}
+	if mode != "good" && mode != "bad" {
+		return nil, fmt.Errorf("invalid mode %q (want good or bad)", mode)
+	}
+
+	config := defaultTrafficConfig(mode)
+	if file != nil {
+		if err := file.Unmarshal(config); err != nil {
+			return nil, fmt.Errorf("parse traffic config: %w", err)
+		}
+		config.Mode = mode
+	}
+
+	f := cmd.Flags()
+	if f.Changed("duration") {
+		config.Duration, _ = f.GetInt("duration")
+	}
+	if f.Changed("rps") {
+		config.Volume, _ = f.GetInt("rps")
+	}
+	if f.Changed("tenant") {
+		config.TenantID, _ = f.GetString("tenant")
+	}
+	if f.Changed("webhook-url") {
+		config.WebhookURL, _ = f.GetString("webhook-url")
+	}
+	if f.Changed("event-type") {
+		config.EventType, _ = f.GetString("event-type")
+	}
+	if f.Changed("jwks-host") {
+		config.JWKSHost, _ = f.GetString("jwks-host")
+	}
+	if f.Changed("failure-rate") {
+		config.FailureRate, _ = f.GetFloat64("failure-rate")
+	}
+	if f.Changed("burst") {
+		config.Burst, _ = f.GetBool("burst")
+	}
+	if f.Changed("burst-rps") {
+		config.BurstVolume, _ = f.GetInt("burst-rps")
+	}
+	if f.Changed("burst-duration") {
+		config.BurstDuration, _ = f.GetInt("burst-duration")
+	}
+
+	if err := validateTrafficConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// validateTrafficConfig rejects what the prompts would have refused to take
+func validateTrafficConfig(config *TrafficConfig) error {
+	switch {
+	case config.Duration <= 0:
+		return fmt.Errorf("duration must be positive, got %d", config.Duration)
+	case config.Volume <= 0:
+		return fmt.Errorf("rps must be positive, got %d", config.Volume)
+	case config.TenantID == "":
+		return fmt.Errorf("tenant is required")
+	case config.WebhookURL == "":
+		return fmt.Errorf("webhook URL is required")
+	case config.EventType == "":
+		return fmt.Errorf("event type is required")
+	case config.FailureRate < 0 || config.FailureRate > 100:
+		return fmt.Errorf("failure rate must be between 0 and 100, got %.1f", config.FailureRate)
+	case config.Mode == "bad" && (config.FailureRate > 0 || config.Burst):
+		return fmt.Errorf("failure rate and burst only apply to good mode")
+	case config.Burst && (config.BurstVolume <= 0 || config.BurstDuration <= 0):
+		return fmt.Errorf("burst rps and duration must be positive")
+	}
+	return nil
+}
+
+// defaultTrafficConfig returns the defaults for a traffic mode
+func defaultTrafficConfig(mode string) *TrafficConfig {
+	if mode == "bad" {
+		return &TrafficConfig{
+			Duration:   30, // Shorter duration for bad traffic
+			Volume:     5,  // Lower volume for bad traffic
+			TenantID:   "harborctl_badtraffic",
+			WebhookURL: "http://fake-receiver:8081/fail", // Failing endpoint
+			EventType:  "harborctl.traffic.failevent",
+			ServerHost: "localhost:8443",
+			JWKSHost:   "localhost:8082",
+			Mode:       "bad",
+		}
+	}
+	return &TrafficConfig{
+		Duration:      120,
+		Volume:        10,
+		TenantID:      "harborctl_traffic",
+		WebhookURL:    "http://fake-receiver:8081/hook",
+		EventType:     "harborctl.traffic.successevent",
+		ServerHost:    "localhost:8443",
+		JWKSHost:      "localhost:8082",
+		Mode:          "good",
+		FailureRate:   5.0,   // 5% failure rate by default
+		Burst:         false, // No burst by default
+		BurstVolume:   25,    // Reduced from 50 to 25 req/s for stability
+		BurstDuration: 30,    // 30 seconds of burst
+	}
 }
 
 // runGenerateTraffic handles the interactive traffic generation
 func runGenerateTraffic(cmd *cobra.Command, args []string) error {
 	printHeader("🚀 Harborhook Traffic Generator")
 
-	// Step 1: Collect parameters interactively
-	config, err := collectTrafficParameters()
-	if err != nil {
-		return fmt.Errorf("failed to collect parameters: %w", err)
+	// Step 1: Collect parameters from flags and --config, or interactively
+	var config *TrafficConfig
+	var err error
+	interactive := !nonInteractive(cmd)
+	if interactive {
+		config, err = collectTrafficParameters()
+		if err != nil {
+			return fmt.Errorf("failed to collect parameters: %w", err)
+		}
+	} else {
+		config, err = loadTrafficConfig(cmd)
+		if err != nil {
+			return err
+		}
 	}
 
 	// Step 2: Show parameters and get confirmation
-	if !confirmParameters(config) {
+	if !confirmParameters(config, interactive) {
 		printInfo("Traffic generation cancelled")
 		return nil
 	}
 
-	// Step 3: Check dependencies
-	if err := checkTrafficDependencies(); err != nil {
-		return err
-	}
-	printSuccess("All dependencies available")
-
-	// Step 4: Get JWT token
+	// Step 3: Get JWT token
 	jwtToken, err := getJWTToken(config.JWKSHost, config.TenantID)
 	if err != nil {
 		return fmt.Errorf("failed to get JWT token: %w", err)
 	}
 	printSuccess("Got JWT token")
 
-	// Step 5: Setup endpoints and subscriptions
+	// Step 4: Setup endpoints and subscriptions
 	endpointID, subscriptionID, badEndpointID, badSubscriptionID, err := setupTrafficEndpoints(config, jwtToken)
 	if err != nil {
 		return fmt.Errorf("failed to setup endpoints: %w", err)
@@ -133,7 +321,7 @@ func runGenerateTraffic(cmd *cobra.Command, args []string) error {
 		printSuccess(fmt.Sprintf("Bad Subscription ID: %s", badSubscriptionID))
 	}
 
-	// Step 6: Generate traffic
+	// Step 5: Generate traffic
 	summary, err := generateTrafficWithProgress(config, jwtToken)
 	if err != nil {
 		return fmt.Errorf("failed to generate traffic: %w", err)
@@ -144,9 +332,27 @@ func runGenerateTraffic(cmd *cobra.Command, args []string) error {
 	summary.BadSubscriptionID = badSubscriptionID
 	summary.Mode = config.Mode
 
-	// Step 7: Show summary
+	// Step 6: Show summary
 	printTrafficSummary(summary)
+	if path, _ := cmd.Flags().GetString("summary-json"); path != "" {
+		if err := writeTrafficSummary(path, summary); err != nil {
+			return err
+		}
+		printSuccess(fmt.Sprintf("Wrote summary to %s", path))
+	}
+
+	return nil
+}
 
+// writeTrafficSummary writes the summary as JSON for automation to read
+func writeTrafficSummary(path string, summary *TrafficSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("failed to write summary: %w", err)
+	}
 	return nil
 }
 
@@ -168,36 +374,7 @@ func collectTrafficParameters() (*TrafficConfig, error) {
 	}
 
 	// Set defaults based on traffic mode
-	var config *TrafficConfig
-	if mode == "bad" {
-		config = &TrafficConfig{
-			Duration:   30, // Shorter duration for bad traffic
-			Volume:     5,  // Lower volume for bad traffic
-			TenantID:   "harborctl_badtraffic",
-			WebhookURL: "http://fake-receiver:8081/fail", // Failing endpoint
-			EventType:  "harborctl.traffic.failevent",
-			ServerHost: "localhost:8443",
-			JWKSHost:   "localhost:8082",
-			Mode:       "bad",
-		}
-		//fmt.Printf("🔥 DLQ Mode: Using defaults optimized for Dead Letter Queue testing\n\n")
-	} else {
-		config = &TrafficConfig{
-			Duration:      120,
-			Volume:        10,
-			TenantID:      "harborctl_traffic",
-			WebhookURL:    "http://fake-receiver:8081/hook",
-			EventType:     "harborctl.traffic.successevent",
-			ServerHost:    "localhost:8443",
-			JWKSHost:      "localhost:8082",
-			Mode:          "good",
-			FailureRate:   5.0,   // 5% failure rate by default
-			Burst:         false, // No burst by default
-			BurstVolume:   25,    // Reduced from 50 to 25 req/s for stability
-			BurstDuration: 30,    // 30 seconds of burst
-		}
-		//fmt.Printf("✅ Good Mode: Using defaults for mixed success/failure traffic\n\n")
-	}
+	config := defaultTrafficConfig(mode)
 
 	// Traffic duration
 	fmt.Printf("Traffic duration in seconds [default: %d]: ", config.Duration)
@@ -278,8 +455,8 @@ func collectTrafficParameters() (*TrafficConfig, error) {
 	return config, nil
 }
 
-// confirmParameters displays the configuration and asks for confirmation
-func confirmParameters(config *TrafficConfig) bool {
+// confirmParameters displays the configuration and, when interactive, asks for confirmation
+func confirmParameters(config *TrafficConfig, interactive bool) bool {
 	fmt.Println()
 	printStep("Configuration Summary:")
 
@@ -333,6 +510,9 @@ func confirmParameters(config *TrafficConfig) bool {
 		fmt.Printf("   This provides realistic failure patterns for testing alerting and monitoring.\n")
 	}
 	fmt.Println()
+	if !interactive {
+		return true
+	}
 
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Print("Continue with traffic generation? (y/N): ")
@@ -342,23 +522,6 @@ func confirmParameters(config *TrafficConfig) bool {
 	return response == "y" || response == "yes"
 }
 
-// checkTrafficDependencies checks if required dependencies are available
-func checkTrafficDependencies() error {
-	printStep("Checking dependencies...")
-
-	// Check for curl
-	if _, err := exec.LookPath("curl"); err != nil {
-		return fmt.Errorf("curl is required but not installed")
-	}
-
-	// Check for jq
-	if _, err := exec.LookPath("jq"); err != nil {
-		return fmt.Errorf("jq is required but not installed")
-	}
-
-	return nil
-}
-
 // getJWTToken obtains a JWT token from the JWKS server
 func getJWTToken(jwksHost, tenantID string) (string, error) {
 	printStep("Getting JWT token...")
@@ -382,6 +545,9 @@ func getJWTToken(jwksHost, tenantID string) (string, error) {
 		return "", fmt.Errorf("failed to create token request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if apiKey := os.Getenv(jwksAPIKeyEnv); apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+apiKey)
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
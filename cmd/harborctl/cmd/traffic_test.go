@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// trafficTestCmd is a generate command parsed from args
+func trafficTestCmd(t *testing.T, args ...string) *cobra.Command {
+	t.Helper()
+	cmd := &cobra.Command{Use: "generate"}
+	addTrafficFlags(cmd)
+	if err := cmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags(%v): %v", args, err)
+	}
+	return cmd
+}
+
+func TestLoadTrafficConfig(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "traffic.yaml")
+	if err := os.WriteFile(file, []byte("mode: good\nduration: 45\nvolume: 8\ntenant_id: tn_ci\nfailure_rate: 20\nburst: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		check   func(*TrafficConfig) bool
+		wantErr string
+	}{
+		{
+			name: "flags over mode defaults",
+			args: []string{"--mode", "bad", "--rps", "3"},
+			check: func(c *TrafficConfig) bool {
+				return c.Mode == "bad" && c.Volume == 3 && c.Duration == 30 && c.TenantID == "harborctl_badtraffic"
+			},
+		},
+		{
+			name: "config file over defaults",
+			args: []string{"--config", file},
+			check: func(c *TrafficConfig) bool {
+				return c.Duration == 45 && c.Volume == 8 && c.TenantID == "tn_ci" && c.FailureRate == 20 && c.Burst && c.BurstVolume == 25 &&
+					c.EventType == "harborctl.traffic.successevent"
+			},
+		},
+		{
+			name:  "flags over config file",
+			args:  []string{"--config", file, "--duration", "10", "--burst=false"},
+			check: func(c *TrafficConfig) bool { return c.Duration == 10 && c.Volume == 8 && !c.Burst },
+		},
+		{name: "unknown mode", args: []string{"--mode", "chaos"}, wantErr: "invalid mode"},
+		{name: "failure rate out of range", args: []string{"--failure-rate", "150"}, wantErr: "failure rate"},
+		{name: "burst in bad mode", args: []string{"--mode", "bad", "--burst"}, wantErr: "only apply to good mode"},
+		{name: "missing config file", args: []string{"--config", filepath.Join(dir, "missing.yaml")}, wantErr: "read traffic config"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := trafficTestCmd(t, tt.args...)
+			if !nonInteractive(cmd) {
+				t.Errorf("nonInteractive(%v) = false, want true", tt.args)
+			}
+			got, err := loadTrafficConfig(cmd)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("loadTrafficConfig() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadTrafficConfig() unexpected error: %v", err)
+			}
+			if !tt.check(got) {
+				t.Errorf("loadTrafficConfig() = %+v", got)
+			}
+		})
+	}
+
+	if nonInteractive(trafficTestCmd(t, "--summary-json", "out.json")) {
+		t.Error("nonInteractive(--summary-json only) = true, want the prompts")
+	}
+}
+
+func TestWriteTrafficSummary(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "summary.json")
+	if err := writeTrafficSummary(path, &TrafficSummary{TotalRequests: 12, SuccessRequests: 11, Mode: "good"}); err != nil {
+		t.Fatalf("writeTrafficSummary() unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("summary is not JSON: %v", err)
+	}
+	if got["total_requests"] != float64(12) || got["success_requests"] != float64(11) || got["mode"] != "good" {
+		t.Errorf("summary = %v", got)
+	}
+}
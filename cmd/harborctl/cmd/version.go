@@ -1,49 +1,83 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
-	"runtime"
 
 	"github.com/austindbirch/harbor_hook/cmd/harborctl/cmd/ascii"
+	"github.com/austindbirch/harbor_hook/internal/version"
 	"github.com/spf13/cobra"
 )
 
-var (
-	// These will be set by ldflags during build
-	Version   = "dev"
-	GitCommit = "unknown"
-	BuildTime = "unknown"
-)
+var versionServer bool
 
 // versionCmd represents the version command
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print the version information",
-	Long:  `Print the version information for harborctl.`,
+	Long: `Print the version information for harborctl.
+
+With --server, also print the build of the ingest service harborctl is pointed at,
+read from its /version endpoint.`,
 	Annotations: map[string]string{
 		ascii.AnnotationKey: ascii.Version,
 	},
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		client := version.Get()
+
+		var server *version.Info
+		if versionServer {
+			info, err := fetchServerVersion()
+			if err != nil {
+				return fmt.Errorf("failed to get server version: %w", err)
+			}
+			server = &info
+		}
+
 		if outputJSON {
-			version := map[string]string{
-				"version":   Version,
-				"gitCommit": GitCommit,
-				"buildTime": BuildTime,
-				"goVersion": runtime.Version(),
-				"goos":      runtime.GOOS,
-				"goarch":    runtime.GOARCH,
+			if server == nil {
+				printOutput(client)
+				return nil
 			}
-			printOutput(version)
-		} else {
-			fmt.Printf("harborctl version %s\n", Version)
-			fmt.Printf("Git commit: %s\n", GitCommit)
-			fmt.Printf("Built: %s\n", BuildTime)
-			fmt.Printf("Go version: %s\n", runtime.Version())
-			fmt.Printf("OS/Arch: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+			printOutput(map[string]version.Info{"client": client, "server": *server})
+			return nil
 		}
+
+		fmt.Printf("harborctl version %s\n", client.Version)
+		printBuild(client)
+		if server != nil {
+			fmt.Printf("\nServer (%s) version %s\n", serverAddr, server.Version)
+			printBuild(*server)
+		}
+		return nil
 	},
 }
 
+func printBuild(info version.Info) {
+	fmt.Printf("Git commit: %s\n", info.GitCommit)
+	fmt.Printf("Built: %s\n", info.BuildTime)
+	fmt.Printf("Go version: %s\n", info.GoVersion)
+	fmt.Printf("OS/Arch: %s/%s\n", info.GOOS, info.GOARCH)
+}
+
+func fetchServerVersion() (version.Info, error) {
+	var info version.Info
+	resp, err := makeHTTPRequest("GET", "/version", nil)
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return info, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return info, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return info, nil
+}
+
 func init() {
+	versionCmd.Flags().BoolVar(&versionServer, "server", false, "Also print the server's build")
 	rootCmd.AddCommand(versionCmd)
 }
@@ -0,0 +1,292 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// watchPollLimit is how many deliveries each HTTP poll fetches
+const watchPollLimit = 500
+
+// watchCmd represents the delivery watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Follow deliveries live as they change status",
+	Long: `Follow the deliveries of an event or endpoint and print every status change
+as it happens, with the time since the delivery was enqueued. Over gRPC the
+server streams the changes; over HTTP harborctl polls for them.
+
+With --event, watch exits once every delivery is delivered or dead-lettered
+(use --follow to keep watching for replays). With --endpoint, it runs until
+interrupted; following an endpoint over HTTP lists recent deliveries, which
+requires the admin tenant's token.
+
+Example:
+  harborctl deliveries watch --event evt_123
+  harborctl deliveries watch --endpoint ep_456 --interval 2s`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		eventID, _ := cmd.Flags().GetString("event")
+		endpointID, _ := cmd.Flags().GetString("endpoint")
+		interval, _ := cmd.Flags().GetDuration("interval")
+		follow, _ := cmd.Flags().GetBool("follow")
+		noColor, _ := cmd.Flags().GetBool("no-color")
+		if interval <= 0 {
+			return fmt.Errorf("invalid interval: must be positive")
+		}
+		colors := !noColor && !outputJSON && os.Getenv("NO_COLOR") == ""
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		if !outputJSON {
+			if eventID != "" {
+				fmt.Printf("Watching deliveries for event %s (Ctrl-C to stop)\n", eventID)
+			} else {
+				fmt.Printf("Watching deliveries to endpoint %s (Ctrl-C to stop)\n", endpointID)
+			}
+		}
+
+		emit := func(c deliveryTransition) {
+			if outputJSON {
+				printOutput(c.attempt)
+			} else {
+				fmt.Println(formatTransition(c, colors))
+			}
+		}
+		if useHTTP {
+			return pollDeliveries(ctx, eventID, endpointID, interval, follow, emit)
+		}
+		return streamDeliveries(ctx, eventID, endpointID, interval, follow, emit)
+	},
+}
+
+// streamDeliveries follows deliveries over the WatchDeliveryStatus stream until the server
+// ends it or ctx is cancelled
+func streamDeliveries(ctx context.Context, eventID, endpointID string, interval time.Duration, follow bool, emit func(deliveryTransition)) error {
+	client, cleanup, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	defer cleanup()
+
+	stream, err := client.WatchDeliveryStatus(ctx, &webhookv1.WatchDeliveryStatusRequest{
+		EventId:        eventID,
+		EndpointId:     endpointID,
+		PollIntervalMs: int32(interval.Milliseconds()),
+		Follow:         follow,
+		// Deliveries that already finished when an endpoint watch starts are history, not news
+		SkipFinished: endpointID != "",
+	})
+	if err != nil {
+		return fmt.Errorf("failed to watch deliveries: %w", err)
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF || ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to watch deliveries: %w", err)
+		}
+		emit(deliveryTransition{attempt: resp.Delivery, from: resp.PreviousStatus})
+	}
+}
+
+// pollDeliveries follows deliveries by polling the REST API every interval
+func pollDeliveries(ctx context.Context, eventID, endpointID string, interval time.Duration, follow bool, emit func(deliveryTransition)) error {
+	poll := deliveryPoller(eventID, endpointID)
+	seen := map[string]webhookv1.DeliveryAttemptStatus{}
+	// Deliveries that already finished when an endpoint watch starts are history, not news
+	quietStart := endpointID != ""
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		attempts, err := poll()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to poll deliveries: %w", err)
+		}
+		for _, c := range deliveryTransitions(seen, attempts) {
+			if quietStart && c.from == webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED && watchTerminal(c.attempt.Status) {
+				continue
+			}
+			emit(c)
+		}
+		quietStart = false
+
+		if eventID != "" && !follow && len(attempts) > 0 && allTerminal(attempts) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliveryTransition is a delivery that is new or has changed status since the last poll
+type deliveryTransition struct {
+	attempt *webhookv1.DeliveryAttempt
+	from    webhookv1.DeliveryAttemptStatus // UNSPECIFIED for a delivery seen for the first time
+}
+
+// deliveryTransitions diffs a poll against the statuses already seen, recording the new ones
+func deliveryTransitions(seen map[string]webhookv1.DeliveryAttemptStatus, attempts []*webhookv1.DeliveryAttempt) []deliveryTransition {
+	var out []deliveryTransition
+	for _, a := range attempts {
+		prev, ok := seen[a.DeliveryId]
+		if ok && prev == a.Status {
+			continue
+		}
+		seen[a.DeliveryId] = a.Status
+		out = append(out, deliveryTransition{attempt: a, from: prev})
+	}
+	return out
+}
+
+// formatTransition renders one status change as a single line
+func formatTransition(c deliveryTransition, colors bool) string {
+	a := c.attempt
+	var b strings.Builder
+	b.WriteString(time.Now().Format("15:04:05"))
+	b.WriteString("  ")
+	b.WriteString(a.DeliveryId)
+	b.WriteString("  ")
+	if c.from != webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED {
+		b.WriteString(shortStatus(c.from))
+		b.WriteString(" → ")
+	}
+	status := shortStatus(a.Status)
+	if colors {
+		status = statusColor(a.Status) + status + "\033[0m"
+	}
+	b.WriteString(status)
+	if d, ok := statusLatency(a); ok {
+		fmt.Fprintf(&b, "  +%s", d.Round(time.Millisecond))
+	}
+	if a.HttpStatus > 0 {
+		fmt.Fprintf(&b, "  http=%d", a.HttpStatus)
+	}
+	if a.ErrorReason != "" {
+		fmt.Fprintf(&b, "  %s", a.ErrorReason)
+	}
+	if a.ReplayOf != "" {
+		fmt.Fprintf(&b, "  (replay of %s)", a.ReplayOf)
+	}
+	return b.String()
+}
+
+// shortStatus drops the enum prefix, e.g. DELIVERED
+func shortStatus(s webhookv1.DeliveryAttemptStatus) string {
+	return strings.TrimPrefix(s.String(), "DELIVERY_ATTEMPT_STATUS_")
+}
+
+// statusColor picks the ANSI color for a status, matching the traffic command's palette
+func statusColor(s webhookv1.DeliveryAttemptStatus) string {
+	switch s {
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED:
+		return "\033[0;32m"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED:
+		return "\033[0;31m"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_FAILED:
+		return "\033[0;33m"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_IN_FLIGHT:
+		return "\033[0;34m"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_PARKED:
+		return "\033[0;35m"
+	default:
+		return "\033[0;36m"
+	}
+}
+
+// statusLatency is how long after enqueueing the delivery reached its current status
+func statusLatency(a *webhookv1.DeliveryAttempt) (time.Duration, bool) {
+	var at *timestamppb.Timestamp
+	switch a.Status {
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_IN_FLIGHT:
+		at = a.DequeuedAt
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED:
+		at = a.DeliveredAt
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_FAILED:
+		at = a.FailedAt
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED:
+		at = a.DlqAt
+	}
+	if at == nil || a.EnqueuedAt == nil {
+		return 0, false
+	}
+	return at.AsTime().Sub(a.EnqueuedAt.AsTime()), true
+}
+
+// watchTerminal reports whether a delivery will not change status again without a replay
+func watchTerminal(s webhookv1.DeliveryAttemptStatus) bool {
+	return s == webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED ||
+		s == webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED
+}
+
+// allTerminal reports whether every delivery has finished
+func allTerminal(attempts []*webhookv1.DeliveryAttempt) bool {
+	for _, a := range attempts {
+		if !watchTerminal(a.Status) {
+			return false
+		}
+	}
+	return true
+}
+
+// deliveryPoller returns a function fetching the watched deliveries from the REST API
+func deliveryPoller(eventID, endpointID string) func() ([]*webhookv1.DeliveryAttempt, error) {
+	return func() ([]*webhookv1.DeliveryAttempt, error) {
+		if eventID != "" {
+			resp := &webhookv1.GetDeliveryStatusResponse{}
+			path := fmt.Sprintf("/v1/events/%s/deliveries?limit=%d", eventID, watchPollLimit)
+			if endpointID != "" {
+				path += "&endpointId=" + url.QueryEscape(endpointID)
+			}
+			if err := doctorRequest("GET", path, nil, resp); err != nil {
+				return nil, err
+			}
+			return resp.Attempts, nil
+		}
+		resp := &webhookv1.ListRecentDeliveriesResponse{}
+		path := fmt.Sprintf("/v1/admin/deliveries?endpointId=%s&limit=%d", url.QueryEscape(endpointID), watchPollLimit)
+		if err := doctorRequest("GET", path, nil, resp); err != nil {
+			return nil, err
+		}
+		return recentAttempts(resp), nil
+	}
+}
+
+// recentAttempts unwraps the deliveries of a ListRecentDeliveries response, oldest first
+func recentAttempts(resp *webhookv1.ListRecentDeliveriesResponse) []*webhookv1.DeliveryAttempt {
+	out := make([]*webhookv1.DeliveryAttempt, 0, len(resp.Deliveries))
+	for i := len(resp.Deliveries) - 1; i >= 0; i-- {
+		out = append(out, resp.Deliveries[i].Delivery)
+	}
+	return out
+}
+
+func init() {
+	deliveryCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().String("event", "", "watch the deliveries of this event")
+	watchCmd.Flags().String("endpoint", "", "watch deliveries to this endpoint (narrows --event when both are set)")
+	watchCmd.MarkFlagsOneRequired("event", "endpoint")
+	watchCmd.Flags().Duration("interval", time.Second, "how often to check for changes")
+	watchCmd.Flags().Bool("follow", false, "keep watching an event after every delivery has finished")
+	watchCmd.Flags().Bool("no-color", false, "disable colored output (also disabled by NO_COLOR)")
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestDeliveryTransitions(t *testing.T) {
+	const (
+		queued    = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_QUEUED
+		inFlight  = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_IN_FLIGHT
+		delivered = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED
+	)
+	seen := map[string]webhookv1.DeliveryAttemptStatus{}
+
+	first := deliveryTransitions(seen, []*webhookv1.DeliveryAttempt{
+		{DeliveryId: "a", Status: queued},
+		{DeliveryId: "b", Status: inFlight},
+	})
+	if len(first) != 2 || first[0].from != webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED {
+		t.Fatalf("first poll = %+v, want two new deliveries", first)
+	}
+
+	second := deliveryTransitions(seen, []*webhookv1.DeliveryAttempt{
+		{DeliveryId: "a", Status: queued},
+		{DeliveryId: "b", Status: delivered},
+	})
+	if len(second) != 1 || second[0].attempt.DeliveryId != "b" || second[0].from != inFlight {
+		t.Errorf("second poll = %+v, want only b moving from IN_FLIGHT", second)
+	}
+
+	if got := deliveryTransitions(seen, []*webhookv1.DeliveryAttempt{{DeliveryId: "b", Status: delivered}}); len(got) != 0 {
+		t.Errorf("unchanged poll = %+v, want none", got)
+	}
+}
+
+func TestFormatTransition(t *testing.T) {
+	enq := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := deliveryTransition{
+		attempt: &webhookv1.DeliveryAttempt{
+			DeliveryId:  "del_1",
+			Status:      webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED,
+			HttpStatus:  200,
+			EnqueuedAt:  timestamppb.New(enq),
+			DeliveredAt: timestamppb.New(enq.Add(1500 * time.Millisecond)),
+		},
+		from: webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_IN_FLIGHT,
+	}
+
+	plain := formatTransition(c, false)
+	for _, want := range []string{"del_1", "IN_FLIGHT → DELIVERED", "+1.5s", "http=200"} {
+		if !strings.Contains(plain, want) {
+			t.Errorf("formatTransition() = %q, missing %q", plain, want)
+		}
+	}
+	if strings.Contains(plain, "\033[") {
+		t.Errorf("formatTransition() = %q, want no color codes", plain)
+	}
+	if colored := formatTransition(c, true); !strings.Contains(colored, "\033[0;32mDELIVERED\033[0m") {
+		t.Errorf("formatTransition() = %q, want DELIVERED in green", colored)
+	}
+}
+
+func TestStatusLatency(t *testing.T) {
+	enq := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	queued := &webhookv1.DeliveryAttempt{
+		Status:     webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_QUEUED,
+		EnqueuedAt: timestamppb.New(enq),
+	}
+	if _, ok := statusLatency(queued); ok {
+		t.Error("statusLatency() ok for a queued delivery")
+	}
+
+	dead := &webhookv1.DeliveryAttempt{
+		Status:     webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED,
+		EnqueuedAt: timestamppb.New(enq),
+		DlqAt:      timestamppb.New(enq.Add(time.Minute)),
+	}
+	if d, ok := statusLatency(dead); !ok || d != time.Minute {
+		t.Errorf("statusLatency() = %v, %v, want 1m0s", d, ok)
+	}
+}
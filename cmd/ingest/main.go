@@ -8,20 +8,34 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
-	"github.com/nsqio/go-nsq"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/austindbirch/harbor_hook/internal/adminui"
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/blobstore"
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/compliance"
 	"github.com/austindbirch/harbor_hook/internal/config"
 	"github.com/austindbirch/harbor_hook/internal/db"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
 	"github.com/austindbirch/harbor_hook/internal/health"
 	"github.com/austindbirch/harbor_hook/internal/ingest"
 	"github.com/austindbirch/harbor_hook/internal/logging"
 	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+	"github.com/austindbirch/harbor_hook/internal/notify"
+	"github.com/austindbirch/harbor_hook/internal/queue"
+	"github.com/austindbirch/harbor_hook/internal/store"
 	"github.com/austindbirch/harbor_hook/internal/tracing"
+	"github.com/austindbirch/harbor_hook/internal/validation"
+	"github.com/austindbirch/harbor_hook/internal/version"
 	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
 
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
@@ -30,14 +44,38 @@ import (
 	"google.golang.org/grpc/credentials/insecure"
 	grpc_health "google.golang.org/grpc/health"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 )
 
+// grpcHealthInterval is how often the gRPC health service's status is checked
+const grpcHealthInterval = 5 * time.Second
+
 func main() {
 	cfg := config.FromEnv()
 	ctx := context.Background()
 
 	// Initialize structured logging
 	logger := logging.New("harborhook-ingest")
+	if err := logging.Configure(logging.Options{
+		Level:      cfg.Log.Level,
+		Format:     cfg.Log.Format,
+		File:       cfg.Log.File,
+		MaxSizeMB:  cfg.Log.MaxSizeMB,
+		MaxBackups: cfg.Log.MaxBackups,
+		RedactKeys: cfg.Log.RedactKeys,
+	}); err != nil {
+		logger.Plain().WithError(err).Fatal("invalid logging configuration")
+	}
+
+	// Bound tenant and endpoint metric labels before anything is recorded
+	if err := metrics.SetLabelPolicy(metrics.LabelPolicy{
+		Mode:        cfg.Metrics.LabelMode,
+		Allowlist:   cfg.Metrics.LabelAllowlist,
+		HashBuckets: cfg.Metrics.LabelHashBuckets,
+		TopK:        cfg.Metrics.LabelTopK,
+	}); err != nil {
+		logger.Plain().WithError(err).Fatal("invalid metrics label policy")
+	}
 
 	// Initialize OpenTelemetry tracing
 	shutdown, err := tracing.InitTracing(ctx, "harborhook-ingest")
@@ -53,11 +91,10 @@ func main() {
 	}
 	defer pool.Close()
 
-	// Create NSQ producer
-	nsqConf := nsq.NewConfig()
-	prod, err := nsq.NewProducer(cfg.NSQ.NsqdTCPAddr, nsqConf)
+	// Create queue publisher (NSQ or Kafka, per QUEUE_BACKEND)
+	prod, err := queue.NewPublisher(cfg)
 	if err != nil {
-		logger.Plain().WithError(err).Fatal("nsq producer creation failed")
+		logger.Plain().WithError(err).Fatal("queue publisher creation failed")
 	}
 	defer prod.Stop()
 
@@ -65,9 +102,12 @@ func main() {
 	var grpcOpts []grpc.ServerOption
 	var httpTLSConfig *tls.Config
 
-	// Add OpenTelemetry gRPC stats handler
+	// Add OpenTelemetry gRPC stats handler, and the error model outside every other interceptor
+	// so auth and audit errors get codes and ErrorInfo details too
 	grpcOpts = append(grpcOpts,
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(apierr.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(apierr.StreamServerInterceptor()),
 	)
 
 	if enableTLS := os.Getenv("ENABLE_TLS"); enableTLS == "true" {
@@ -113,28 +153,111 @@ func main() {
 		httpTLSConfig.ClientAuth = tls.NoClientCert // HTTP doesn't require client certs from Envoy
 	}
 
-	// Setup JWT configuration
-	if jwtIssuer := os.Getenv("JWT_ISSUER"); jwtIssuer != "" {
-		jwtAudience := os.Getenv("JWT_AUDIENCE")
-		if jwtAudience == "" {
-			jwtAudience = "harborhook-api"
-		}
-
-		// For development, we'll skip JWT validation as Envoy handles it
-		// TODO: implement JWT validation in the service as well as the HTTP edge
+	// Setup JWT validation. Envoy forwards the token, so the service validates it
+	// again and stays protected when it is reachable without the gateway.
+	jwtValidator, err := auth.NewJWTValidatorFromEnv(ctx, func(err error) {
+		logger.Plain().WithError(err).Warn("JWKS refresh failed, keeping cached keys")
+	})
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("Failed to create JWT validator")
+	}
+	if jwtValidator != nil {
+		grpcOpts = append(grpcOpts,
+			grpc.ChainUnaryInterceptor(jwtValidator.GRPCInterceptor()),
+			grpc.ChainStreamInterceptor(jwtValidator.GRPCStreamInterceptor()),
+		)
 		logger.Plain().WithFields(map[string]any{
-			"issuer":   jwtIssuer,
-			"audience": jwtAudience,
-		}).Info("JWT validation configured (handled by Envoy)")
+			"issuer":              os.Getenv("JWT_ISSUER"),
+			"trust_tenant_header": os.Getenv("JWT_TRUST_TENANT_HEADER") == "true",
+		}).Info("JWT validation enabled")
+	} else {
+		logger.Plain().Warn("JWT_ISSUER not set, requests are not authenticated")
 	}
 
-	// Start gRPC server
+	svc := ingest.NewServer(pool, prod)
+	if cfg.Compliance.RecordingKey != "" {
+		recordings, err := compliance.NewCipher(cfg.Compliance.RecordingKey)
+		if err != nil {
+			logger.Plain().WithError(err).Fatal("invalid RECORDING_ENCRYPTION_KEY")
+		}
+		svc.SetRecordingCipher(recordings)
+	}
+	svc.SetAdminTenant(os.Getenv("ADMIN_TENANT_ID"))
+	egress, err := netguard.New(cfg.EgressAllowlist)
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("invalid EGRESS_ALLOWLIST")
+	}
+	proxy := netguard.Proxy{URL: cfg.EgressProxy, Username: cfg.EgressProxyUsername, Password: cfg.EgressProxyPassword, FromEnvironment: cfg.EgressProxyFromEnv}
+	if err := egress.SetProxy(proxy); err != nil {
+		logger.Plain().WithError(err).Fatal("invalid EGRESS_PROXY")
+	}
+	svc.SetEgressGuard(egress)
+	if err := svc.SetEgressIPs(cfg.EgressIPs); err != nil {
+		logger.Plain().WithError(err).Fatal("invalid EGRESS_IPS")
+	}
+	svc.SetMaxPayloadBytes(cfg.MaxPayloadBytes)
+	blobs, err := blobstore.New(ctx, cfg.ClaimCheck, pool)
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("blob store creation failed")
+	}
+	svc.SetClaimCheck(blobs, cfg.ClaimCheck.ThresholdBytes)
+	if cfg.EndpointVerification {
+		svc.SetEndpointVerification(cfg.NSQ.SignatureHeader, cfg.NSQ.TimestampHeader, egress.Transport())
+	}
+	svc.SetChangefeed(changefeed.New(prod, cfg.NSQ.ChangefeedTopic))
+	svc.SetDLQRetentionDefaults(store.DLQRetention{MaxAge: cfg.DLQ.MaxAge, MaxEntries: cfg.DLQ.MaxEntries, Archive: cfg.DLQ.Archive})
+	svc.SetPriorityTopics(cfg.NSQ.PriorityTopics)
+	if cfg.OutboxRelayEvery <= 0 {
+		logger.Plain().Fatal("OUTBOX_RELAY_INTERVAL must be positive")
+	}
+	startOutboxRelay(svc, cfg.OutboxRelayEvery)
+	if cfg.ScheduledEvery <= 0 {
+		logger.Plain().Fatal("SCHEDULED_DISPATCH_INTERVAL must be positive")
+	}
+	startScheduledDispatch(svc, cfg.ScheduledEvery)
+	if cfg.AnomalyDetectEvery > 0 {
+		startAnomalyDetection(svc, cfg.AnomalyDetectEvery)
+	}
+	if cfg.AutoDisable.Every > 0 {
+		startAutoDisable(svc, cfg.AutoDisable)
+	}
+	if cfg.Notify.SMTPAddr != "" {
+		svc.SetNotificationMailer(&notify.Mailer{
+			Addr: cfg.Notify.SMTPAddr,
+			User: cfg.Notify.SMTPUser,
+			Pass: cfg.Notify.SMTPPass,
+			From: cfg.Notify.EmailFrom,
+		})
+	}
+	if cfg.Notify.Every > 0 {
+		startNotifications(svc, cfg.Notify.Every)
+	}
+
+	// Start gRPC server. Requests are validated against their proto rules after authentication,
+	// so callers without a token learn nothing about them, and auditing runs after both so entries
+	// name the caller.
+	grpcOpts = append(grpcOpts,
+		grpc.ChainUnaryInterceptor(validation.UnaryServerInterceptor(), svc.AuditInterceptor()),
+		grpc.ChainStreamInterceptor(validation.StreamServerInterceptor()),
+	)
 	grpcSrv := grpc.NewServer(grpcOpts...)
 	hs := grpc_health.NewServer()
 	healthpb.RegisterHealthServer(grpcSrv, hs)
-
-	svc := ingest.NewServer(pool, prod)
 	webhookv1.RegisterWebhookServiceServer(grpcSrv, svc)
+	if cfg.GRPCReflection {
+		reflection.Register(grpcSrv)
+	}
+
+	// Readiness is the database and queue producer and, reported but not required, the trace
+	// collector. gRPC health reports the server and WebhookService SERVING only while it holds.
+	readiness := []health.Check{
+		{Name: cfg.Queue.Backend, Ping: func(ctx context.Context) error { return queue.Ping(ctx, prod) }},
+		{Name: "tracing", Optional: true, Ping: tracing.Ping},
+	}
+	healthCtx, stopHealth := context.WithCancel(ctx)
+	defer stopHealth()
+	go health.WatchGRPC(healthCtx, hs, grpcHealthInterval, []string{webhookv1.WebhookService_ServiceDesc.ServiceName},
+		append([]health.Check{{Name: "db", Ping: pool.Ping}}, readiness...)...)
 
 	lis, err := net.Listen("tcp", cfg.GRPCPort)
 	if err != nil {
@@ -150,15 +273,34 @@ func main() {
 		}
 	}()
 
-	// HTTP mux: health, metrics, grpc-gateway
+	// HTTP mux: health, version, metrics, admin console, grpc-gateway
 	reg := prometheus.NewRegistry()
 	metrics.MustRegister(reg)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", health.HTTPHandler(pool))
+	mux.HandleFunc("/readyz", health.HTTPHandler(pool, readiness...))
+	mux.HandleFunc("/version", version.HTTPHandler())
 	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	if jwtValidator != nil {
+		mux.Handle("/admin/loglevel", jwtValidator.HTTPMiddleware(auth.RequireRole(auth.RoleAdmin, logging.LevelHandler(logger))))
+	}
+	if cfg.AdminUI {
+		// The console's static files are public; the APIs it calls require the admin tenant
+		ui := adminui.Handler()
+		mux.Handle("/admin/ui", ui)
+		mux.Handle(adminui.Prefix, ui)
+	}
+
+	// Business KPIs live on their own registry so exec dashboards scrape only the aggregates
+	if cfg.BusinessMetricsEvery > 0 {
+		bizReg := prometheus.NewRegistry()
+		metrics.MustRegisterBusiness(bizReg)
+		mux.Handle("/metrics/business", promhttp.HandlerFor(bizReg, promhttp.HandlerOpts{}))
+		startBusinessKPIs(svc, cfg.BusinessMetricsEvery)
+	}
 
-	gwmux := runtime.NewServeMux()
+	gwmux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(gatewayHeaderMatcher))
 
 	// Configure grpc-gateway dial options based on TLS
 	var dialOpts []grpc.DialOption
@@ -174,7 +316,11 @@ func main() {
 	if err := webhookv1.RegisterWebhookServiceHandlerFromEndpoint(ctx, gwmux, "localhost"+cfg.GRPCPort, dialOpts); err != nil {
 		logger.Plain().WithError(err).Fatal("Failed to register service handler for grpc-gateway")
 	}
-	mux.Handle("/", gwmux)
+	if jwtValidator != nil {
+		mux.Handle("/", jwtValidator.HTTPMiddleware(gwmux))
+	} else {
+		mux.Handle("/", gwmux)
+	}
 
 	// Start HTTP server
 	httpSrv := &http.Server{
@@ -205,7 +351,145 @@ func main() {
 	<-stop
 	
 	logger.Plain().Info("Shutting down ingest service")
+	stopHealth()
+	hs.Shutdown() // gRPC probes see NOT_SERVING while requests drain
 	grpcSrv.GracefulStop()
 	_ = httpSrv.Shutdown(context.Background())
 	logger.Plain().Info("ingest service stopped")
 }
+
+// startBusinessKPIs refreshes the business KPIs now and then on every tick
+func startBusinessKPIs(svc *ingest.Server, every time.Duration) {
+	go func() {
+		logger := logging.New("harborhook-ingest-kpis")
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		for {
+			k, err := svc.BusinessKPIs(context.Background())
+			if err != nil {
+				logger.Plain().WithError(err).Error("Failed to aggregate business KPIs")
+			} else {
+				metrics.UpdateBusinessKPIs(k, time.Now())
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// startOutboxRelay republishes delivery tasks whose inline NSQ publish failed
+func startOutboxRelay(svc *ingest.Server, every time.Duration) {
+	go func() {
+		logger := logging.New("harborhook-ingest-outbox")
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			n, err := svc.RelayOutbox(context.Background())
+			if err != nil {
+				logger.Plain().WithError(err).Error("Failed to relay outbox")
+				continue
+			}
+			if n > 0 {
+				logger.Plain().WithField("sent", n).Info("Relayed outbox tasks")
+			}
+		}
+	}()
+}
+
+// startScheduledDispatch fans out scheduled events as their publish_at comes
+func startScheduledDispatch(svc *ingest.Server, every time.Duration) {
+	go func() {
+		logger := logging.New("harborhook-ingest-scheduler")
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			n, err := svc.DispatchScheduled(context.Background())
+			if err != nil {
+				logger.Plain().WithError(err).Error("Failed to dispatch scheduled events")
+				continue
+			}
+			if n > 0 {
+				logger.Plain().WithField("dispatched", n).Info("Dispatched scheduled events")
+			}
+		}
+	}()
+}
+
+// startAnomalyDetection periodically flags endpoints whose response codes shift away from their baseline
+func startAnomalyDetection(svc *ingest.Server, every time.Duration) {
+	go func() {
+		logger := logging.New("harborhook-ingest-anomalies")
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			n, err := svc.DetectStatusAnomalies(context.Background())
+			if err != nil {
+				logger.Plain().WithError(err).Error("Failed to detect response-code anomalies")
+				continue
+			}
+			if n > 0 {
+				logger.Plain().WithField("recorded", n).Warn("Recorded endpoint response-code anomalies")
+			}
+		}
+	}()
+}
+
+// startAutoDisable periodically disables endpoints that keep failing
+func startAutoDisable(svc *ingest.Server, cfg config.AutoDisable) {
+	policy := delivery.DisablePolicy{DeadStreak: cfg.DeadStreak, FailureRate: cfg.FailureRate, MinDeliveries: cfg.MinDeliveries}
+	go func() {
+		logger := logging.New("harborhook-ingest-autodisable")
+		ticker := time.NewTicker(cfg.Every)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			n, err := svc.DisableFailingEndpoints(context.Background(), policy, cfg.Window)
+			if err != nil {
+				logger.Plain().WithError(err).Error("Failed to disable failing endpoints")
+				continue
+			}
+			if n > 0 {
+				logger.Plain().WithField("disabled", n).Warn("Disabled failing endpoints")
+			}
+		}
+	}()
+}
+
+// startNotifications periodically sends tenants' ops contacts the notices due to them
+func startNotifications(svc *ingest.Server, every time.Duration) {
+	go func() {
+		logger := logging.New("harborhook-ingest-notify")
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			n, err := svc.SendNotifications(context.Background())
+			if err != nil {
+				logger.Plain().WithError(err).Error("Failed to send notifications")
+				continue
+			}
+			if n > 0 {
+				logger.Plain().WithField("sent", n).Info("Sent notifications")
+			}
+		}
+	}()
+}
+
+// gatewayHeaderMatcher forwards the Idempotency-Key header to PublishEvent as metadata, so REST
+// clients can dedupe publishes without adding the key to the body, and the headers grpc-gateway
+// forwards by default. The identity metadata the gRPC server trusts from Envoy is never forwarded,
+// so a REST client can't claim a tenant or role with Grpc-Metadata-X-Tenant-Id and the like.
+func gatewayHeaderMatcher(key string) (string, bool) {
+	if http.CanonicalHeaderKey(key) == "Idempotency-Key" {
+		return ingest.IdempotencyKeyMetadata, true
+	}
+	md, ok := runtime.DefaultHeaderMatcher(key)
+	switch strings.ToLower(md) {
+	case "x-tenant-id", "x-subject", "x-roles":
+		return "", false
+	}
+	return md, ok
+}
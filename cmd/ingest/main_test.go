@@ -421,3 +421,26 @@ func TestHTTPServerConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestGatewayHeaderMatcher(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		ok     bool
+	}{
+		{header: "Idempotency-Key", want: "idempotency-key", ok: true},
+		{header: "idempotency-key", want: "idempotency-key", ok: true},
+		{header: "Grpc-Metadata-Trace", want: "Trace", ok: true},
+		{header: "X-Custom", ok: false},
+		{header: "Grpc-Metadata-X-Tenant-Id", ok: false},
+		{header: "grpc-metadata-x-roles", ok: false},
+		{header: "Grpc-Metadata-X-Subject", ok: false},
+		{header: "X-Tenant-Id", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := gatewayHeaderMatcher(tt.header)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("gatewayHeaderMatcher(%q) = %q, %v, want %q, %v", tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Key states, derived from a key's timestamps
+const (
+	keyNext    = "next"    // published so validators cache it, not yet signing
+	keyActive  = "active"  // signing new tokens
+	keyRetired = "retired" // published until the tokens it signed have expired
+)
+
+var (
+	keyAge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "harborhook_jwks_key_age_seconds",
+		Help: "Seconds since each published signing key was created, by kid and state",
+	}, []string{"kid", "state"})
+
+	keyRotations = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "harborhook_jwks_key_rotations_total",
+		Help: "Times a new signing key took over from the previous one",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(keyAge)
+	prometheus.MustRegister(keyRotations)
+}
+
+// signingKey is one RSA key the server publishes
+type signingKey struct {
+	Kid         string
+	Key         *rsa.PrivateKey
+	CreatedAt   time.Time
+	ActivatedAt time.Time // zero while the key is next
+	RetiredAt   time.Time // zero until a newer key takes over
+}
+
+func (k *signingKey) state() string {
+	switch {
+	case !k.RetiredAt.IsZero():
+		return keyRetired
+	case !k.ActivatedAt.IsZero():
+		return keyActive
+	}
+	return keyNext
+}
+
+// storedKey is a signingKey as the key file holds it
+type storedKey struct {
+	Kid         string    `json:"kid"`
+	PrivateKey  string    `json:"private_key"` // PKCS#1 PEM
+	CreatedAt   time.Time `json:"created_at"`
+	ActivatedAt time.Time `json:"activated_at,omitzero"`
+	RetiredAt   time.Time `json:"retired_at,omitzero"`
+}
+
+// keyring holds the signing keys. Every rotateEvery a new key is published, and publishAhead
+// later it takes over signing, so validators that cache the JWKS learn it before tokens carry
+// its kid. Retired keys stay published for retainFor, the longest token lifetime.
+type keyring struct {
+	path         string // key file; empty keeps keys in memory only
+	rotateEvery  time.Duration
+	publishAhead time.Duration
+	retainFor    time.Duration
+
+	mu      sync.RWMutex
+	keys    []*signingKey // oldest first
+	fileMod time.Time     // modification time of the key file when last read or written
+}
+
+// loadKeyring reads the key file at path. When there is none, the ring starts with seedPEM
+// (JWT_PRIVATE_KEY) or a generated key, and the file is created.
+func loadKeyring(path, seedPEM string, rotateEvery, publishAhead, retainFor time.Duration, now time.Time) (*keyring, error) {
+	kr := &keyring{path: path, rotateEvery: rotateEvery, publishAhead: publishAhead, retainFor: retainFor}
+	if path != "" {
+		loaded, err := kr.reload()
+		if err != nil {
+			return nil, err
+		}
+		if loaded {
+			return kr, nil
+		}
+	}
+
+	var key *signingKey
+	if seedPEM != "" {
+		priv, err := parsePrivateKey(seedPEM)
+		if err != nil {
+			return nil, fmt.Errorf("JWT_PRIVATE_KEY: %w", err)
+		}
+		// The kid a static key has always been published under
+		key = &signingKey{Kid: "harborhook-key-1", Key: priv, CreatedAt: now}
+	} else {
+		var err error
+		if key, err = generateKey(now); err != nil {
+			return nil, err
+		}
+	}
+	key.ActivatedAt = now
+	kr.keys = []*signingKey{key}
+	return kr, kr.save()
+}
+
+// reload replaces the keys with the key file's when it changed since it was last read, so
+// replicas sharing the file pick up each other's rotations. It reports whether the file exists.
+func (kr *keyring) reload() (bool, error) {
+	st, err := os.Stat(kr.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("stat key file: %w", err)
+	}
+	if st.ModTime().Equal(kr.fileMod) {
+		return true, nil
+	}
+	b, err := os.ReadFile(kr.path)
+	if err != nil {
+		return false, fmt.Errorf("read key file: %w", err)
+	}
+	var doc struct {
+		Keys []storedKey `json:"keys"`
+	}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return false, fmt.Errorf("parse key file: %w", err)
+	}
+	keys := make([]*signingKey, 0, len(doc.Keys))
+	for _, s := range doc.Keys {
+		priv, err := parsePrivateKey(s.PrivateKey)
+		if err != nil {
+			return false, fmt.Errorf("key %s: %w", s.Kid, err)
+		}
+		keys = append(keys, &signingKey{Kid: s.Kid, Key: priv, CreatedAt: s.CreatedAt, ActivatedAt: s.ActivatedAt, RetiredAt: s.RetiredAt})
+	}
+	if activeKey(keys) == nil {
+		return false, fmt.Errorf("key file %s has no active key", kr.path)
+	}
+	kr.keys = keys
+	kr.fileMod = st.ModTime()
+	return true, nil
+}
+
+// save writes the keys to the key file, replacing it atomically
+func (kr *keyring) save() error {
+	if kr.path == "" {
+		return nil
+	}
+	doc := struct {
+		Keys []storedKey `json:"keys"`
+	}{}
+	for _, k := range kr.keys {
+		der := x509.MarshalPKCS1PrivateKey(k.Key)
+		doc.Keys = append(doc.Keys, storedKey{
+			Kid:         k.Kid,
+			PrivateKey:  string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: der})),
+			CreatedAt:   k.CreatedAt,
+			ActivatedAt: k.ActivatedAt,
+			RetiredAt:   k.RetiredAt,
+		})
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(kr.path), ".jwks-keys-*")
+	if err != nil {
+		return fmt.Errorf("write key file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write key file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write key file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), kr.path); err != nil {
+		return fmt.Errorf("write key file: %w", err)
+	}
+	if st, err := os.Stat(kr.path); err == nil {
+		kr.fileMod = st.ModTime()
+	}
+	return nil
+}
+
+// maintain publishes a new key when the active one is due for rotation, promotes a next key
+// once it has been published for publishAhead, and drops retired keys past retainFor
+func (kr *keyring) maintain(now time.Time) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+
+	if kr.path != "" {
+		if _, err := kr.reload(); err != nil {
+			return err
+		}
+	}
+
+	changed := false
+	active := activeKey(kr.keys)
+	var next *signingKey
+	for _, k := range kr.keys {
+		if k.state() == keyNext {
+			next = k
+		}
+	}
+	switch {
+	case next != nil && now.Sub(next.CreatedAt) >= kr.publishAhead:
+		active.RetiredAt = now
+		next.ActivatedAt = now
+		keyRotations.Inc()
+		changed = true
+	case next == nil && kr.rotateEvery > 0 && now.Sub(active.ActivatedAt) >= kr.rotateEvery:
+		k, err := generateKey(now)
+		if err != nil {
+			return err
+		}
+		kr.keys = append(kr.keys, k)
+		changed = true
+	}
+
+	kept := kr.keys[:0]
+	for _, k := range kr.keys {
+		if k.state() == keyRetired && now.Sub(k.RetiredAt) >= kr.retainFor {
+			changed = true
+			continue
+		}
+		kept = append(kept, k)
+	}
+	kr.keys = kept
+
+	if changed {
+		return kr.save()
+	}
+	return nil
+}
+
+// signer returns the key that signs new tokens
+func (kr *keyring) signer() *signingKey {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return activeKey(kr.keys)
+}
+
+// published returns every key the JWKS lists, oldest first
+func (kr *keyring) published() []*signingKey {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return append([]*signingKey(nil), kr.keys...)
+}
+
+// recordAges sets the key age gauge for the published keys
+func (kr *keyring) recordAges(now time.Time) {
+	keyAge.Reset()
+	for _, k := range kr.published() {
+		keyAge.WithLabelValues(k.Kid, k.state()).Set(now.Sub(k.CreatedAt).Seconds())
+	}
+}
+
+func activeKey(keys []*signingKey) *signingKey {
+	for i := len(keys) - 1; i >= 0; i-- {
+		if keys[i].state() == keyActive {
+			return keys[i]
+		}
+	}
+	return nil
+}
+
+// generateKey creates a key whose kid is derived from its modulus, so replicas and restarts
+// never reuse a kid for a different key
+func generateKey(now time.Time) (*signingKey, error) {
+	priv, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, fmt.Errorf("generate RSA key: %w", err)
+	}
+	sum := sha256.Sum256(priv.N.Bytes())
+	return &signingKey{Kid: "harborhook-" + hex.EncodeToString(sum[:8]), Key: priv, CreatedAt: now}, nil
+}
+
+func parsePrivateKey(pemText string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemText))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM private key")
+	}
+	priv, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key: %w", err)
+	}
+	return priv, nil
+}
@@ -0,0 +1,202 @@
+package main
+
+import (
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// useTestKey makes priv, under kid, the only signing key for the duration of the test
+func useTestKey(t *testing.T, priv *rsa.PrivateKey, kid string) {
+	t.Helper()
+	original := keys
+	now := time.Now()
+	keys = &keyring{retainFor: 24 * time.Hour, keys: []*signingKey{{Kid: kid, Key: priv, CreatedAt: now, ActivatedAt: now}}}
+	t.Cleanup(func() { keys = original })
+}
+
+func TestKeyring_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	start := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	kr, err := loadKeyring(path, "", time.Hour, 10*time.Minute, 2*time.Hour, start)
+	if err != nil {
+		t.Fatalf("loadKeyring() error = %v", err)
+	}
+	first := kr.signer()
+	if first == nil || !strings.HasPrefix(first.Kid, "harborhook-") || len(kr.published()) != 1 {
+		t.Fatalf("new keyring = %+v, want one generated active key", kr.published())
+	}
+	if st, err := os.Stat(path); err != nil || st.Mode().Perm() != 0o600 {
+		t.Fatalf("key file = %v, %v, want it written with mode 0600", st, err)
+	}
+
+	step := func(at time.Duration) {
+		t.Helper()
+		if err := kr.maintain(start.Add(at)); err != nil {
+			t.Fatalf("maintain(+%s) error = %v", at, err)
+		}
+	}
+	step(30 * time.Minute)
+	if len(kr.published()) != 1 {
+		t.Fatalf("keys before rotation is due = %d, want 1", len(kr.published()))
+	}
+
+	// Rotation publishes the next key without signing with it
+	step(time.Hour)
+	if got := kr.published(); len(got) != 2 || got[1].state() != keyNext || kr.signer() != first {
+		t.Fatalf("keys once rotation is due = %+v, want the next key published and the first still signing", got)
+	}
+	step(time.Hour + 5*time.Minute)
+	if kr.signer() != first {
+		t.Fatal("next key took over before it had been published for publishAhead")
+	}
+
+	rotations := testutil.ToFloat64(keyRotations)
+	step(time.Hour + 10*time.Minute)
+	second := kr.signer()
+	if second == first || first.state() != keyRetired || len(kr.published()) != 2 {
+		t.Fatalf("keys after publishAhead = %+v, want the next key signing and the first retired but published", kr.published())
+	}
+	if got := testutil.ToFloat64(keyRotations); got != rotations+1 {
+		t.Errorf("rotations = %v, want %v", got, rotations+1)
+	}
+
+	// A restart picks up the same keys
+	reloaded, err := loadKeyring(path, "", time.Hour, 10*time.Minute, 2*time.Hour, start.Add(2*time.Hour))
+	if err != nil {
+		t.Fatalf("loadKeyring() after rotation error = %v", err)
+	}
+	if got := reloaded.published(); len(got) != 2 || reloaded.signer().Kid != second.Kid || got[0].Kid != first.Kid {
+		t.Fatalf("reloaded keys = %+v, want the retired and active keys", got)
+	}
+
+	// Retired keys are dropped once retainFor has passed; by then the next rotation is due
+	step(3*time.Hour + 10*time.Minute)
+	if got := kr.published(); len(got) != 2 || got[0].Kid != second.Kid || got[1].state() != keyNext {
+		t.Errorf("keys after retainFor = %+v, want the active key and the next one", got)
+	}
+
+	kr.recordAges(start.Add(3*time.Hour + 20*time.Minute))
+	if got := testutil.ToFloat64(keyAge.WithLabelValues(second.Kid, keyActive)); got != (2*time.Hour + 20*time.Minute).Seconds() {
+		t.Errorf("active key age = %v, want 2h20m in seconds, counted from its publication", got)
+	}
+}
+
+func TestKeyring_SharedFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	start := time.Now()
+	a, err := loadKeyring(path, "", time.Hour, 10*time.Minute, 24*time.Hour, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := loadKeyring(path, "", time.Hour, 10*time.Minute, 24*time.Hour, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.signer().Kid != b.signer().Kid {
+		t.Fatalf("replicas sign with %s and %s, want the same key", a.signer().Kid, b.signer().Kid)
+	}
+
+	if err := a.maintain(start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.maintain(start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.published(); len(got) != 2 || got[1].Kid != a.published()[1].Kid {
+		t.Errorf("second replica's keys = %+v, want the next key the first published", got)
+	}
+}
+
+func TestKeyring_Seed(t *testing.T) {
+	priv, err := generateKey(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	seed := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(priv.Key)}))
+	kr, err := loadKeyring("", seed, 0, 0, time.Hour, time.Now())
+	if err != nil || kr.signer().Kid != "harborhook-key-1" || !kr.signer().Key.Equal(priv.Key) {
+		t.Fatalf("loadKeyring(seed) = %+v, %v, want the seed under harborhook-key-1", kr, err)
+	}
+	// Without a rotation interval the key is kept
+	if err := kr.maintain(time.Now().Add(365 * 24 * time.Hour)); err != nil || len(kr.published()) != 1 {
+		t.Errorf("maintain() with rotation off = %d keys, %v, want the seed alone", len(kr.published()), err)
+	}
+
+	if _, err := loadKeyring("", "not a key", 0, 0, time.Hour, time.Now()); err == nil {
+		t.Error("loadKeyring() with an invalid seed succeeded, want an error")
+	}
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(`{"keys":[]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadKeyring(path, "", 0, 0, time.Hour, time.Now()); err == nil {
+		t.Error("loadKeyring() of a file without an active key succeeded, want an error")
+	}
+}
+
+func TestHandlers_RotatedKeys(t *testing.T) {
+	start := time.Now()
+	kr, err := loadKeyring("", "", time.Hour, 0, 2*time.Hour, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	original := keys
+	keys = kr
+	defer func() { keys = original }()
+
+	jwks := func() (JWKSResponse, string) {
+		w := httptest.NewRecorder()
+		jwksHandler(w, httptest.NewRequest("GET", "/.well-known/jwks.json", nil))
+		var resp JWKSResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return resp, w.Header().Get("ETag")
+	}
+	_, etag := jwks()
+	old := kr.signer()
+
+	// With no publishAhead the next key takes over on the following pass
+	if err := kr.maintain(start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if err := kr.maintain(start.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	resp, rotatedETag := jwks()
+	if len(resp.Keys) != 2 || resp.Keys[0].Kid != old.Kid || resp.Keys[1].Kid != kr.signer().Kid {
+		t.Fatalf("JWKS after rotation = %+v, want the retired and active keys", resp.Keys)
+	}
+	if rotatedETag == etag {
+		t.Error("JWKS ETag unchanged by rotation")
+	}
+
+	w := httptest.NewRecorder()
+	createTokenHandler(w, httptest.NewRequest("POST", "/token", strings.NewReader(`{"tenant_id":"tn_1"}`)))
+	var body struct{ Token string }
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	tok, _, err := jwt.NewParser().ParseUnverified(body.Token, jwt.MapClaims{})
+	if err != nil || tok.Header["kid"] != kr.signer().Kid {
+		t.Errorf("token kid = %v, %v, want the active key's %s", tok.Header["kid"], err, kr.signer().Kid)
+	}
+
+	w = httptest.NewRecorder()
+	createTokenHandler(w, httptest.NewRequest("POST", "/token", strings.NewReader(`{"tenant_id":"tn_1","ttl_seconds":7201}`)))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("token outliving retired keys status = %d, want 400", w.Code)
+	}
+}
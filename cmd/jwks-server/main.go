@@ -1,18 +1,21 @@
 package main
 
 import (
-	"crypto/rand"
-	"crypto/rsa"
-	"crypto/x509"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
-	"encoding/pem"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
-	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/austindbirch/harbor_hook/internal/version"
 )
 
 type JWKSResponse struct {
@@ -27,72 +30,52 @@ type JWK struct {
 	E   string `json:"e"`
 }
 
-var (
-	privateKey *rsa.PrivateKey
-	publicKey  *rsa.PublicKey
-	keyID      = "harborhook-key-1"
-)
+// keys are the signing keys, loaded in main
+var keys *keyring
 
-// Init attempts to load an existing RSA key pair from env vars. If none found, it generates a new pair
-func init() {
-	var err error
-
-	// Try to load existing key, else generate new one
-	if privateKeyPEM := os.Getenv("JWT_PRIVATE_KEY"); privateKeyPEM != "" {
-		block, _ := pem.Decode([]byte(privateKeyPEM))
-		if block == nil {
-			log.Fatal("Failed to decode PEM private key")
-		}
-
-		privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
-		if err != nil {
-			log.Fatalf("Failed to parse private key: %v", err)
-		}
-	} else {
-		// Generate new RSA key pair
-		privateKey, err = rsa.GenerateKey(rand.Reader, 2048)
-		if err != nil {
-			log.Fatalf("Failed to generate RSA key: %v", err)
-		}
-
-		// Store private key for use in other services
-		privateKeyDER := x509.MarshalPKCS1PrivateKey(privateKey)
-		_ = pem.EncodeToMemory(&pem.Block{
-			Type:  "RSA PRIVATE KEY",
-			Bytes: privateKeyDER,
-		})
-		log.Printf("Generated new RSA key pair for JWT signing")
-	}
-
-	publicKey = &privateKey.PublicKey
-}
-
-// jwksHandler serves the JWKS endpoint
+// jwksHandler serves the JWKS endpoint: the active key, the next key ahead of its use, and
+// retired keys until the tokens they signed have expired
 func jwksHandler(w http.ResponseWriter, r *http.Request) {
-	// Convert RSA public key to JWK format
-	jwk := JWK{
-		Kty: "RSA",
-		Use: "sig",
-		Kid: keyID,
-		N:   base64UrlEncode(publicKey.N.Bytes()),
-		E:   base64UrlEncode(intToBytes(publicKey.E)),
+	response := JWKSResponse{Keys: []JWK{}}
+	h := sha256.New()
+	for _, k := range keys.published() {
+		// Convert RSA public key to JWK format
+		jwk := JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Kid: k.Kid,
+			N:   base64UrlEncode(k.Key.PublicKey.N.Bytes()),
+			E:   base64UrlEncode(intToBytes(k.Key.PublicKey.E)),
+		}
+		response.Keys = append(response.Keys, jwk)
+		h.Write([]byte(jwk.Kid + "." + jwk.N + "." + jwk.E + ";"))
 	}
 
-	response := JWKSResponse{
-		Keys: []JWK{jwk},
-	}
+	// ETag lets validators revalidate their cached keyset with a cheap 304
+	etag := `"` + hex.EncodeToString(h.Sum(nil)[:8]) + `"`
 
 	w.Header().Set("Content-Type", "application/json")
 	w.Header().Set("Cache-Control", "public, max-age=300") // Cache for 5 minutes
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
 	json.NewEncoder(w).Encode(response)
 }
 
-// createTokenHandler handles token creation requests
+// createTokenHandler handles token requests. The default grant issues a token for tenant_id and
+// needs the API key outside dev mode; grant_type refresh_token exchanges a refresh token for a
+// new token and refresh token.
 func createTokenHandler(w http.ResponseWriter, r *http.Request) {
 	// Parse request
 	var req struct {
-		TenantID string `json:"tenant_id"`
-		TTL      int    `json:"ttl_seconds,omitempty"` // Optional, defaults to 1 hour
+		GrantType    string   `json:"grant_type,omitempty"` // Optional; client_credentials (default) or refresh_token
+		TenantID     string   `json:"tenant_id"`
+		TTL          int      `json:"ttl_seconds,omitempty"` // Optional, defaults to 1 hour
+		Roles        []string `json:"roles,omitempty"`       // Optional; without roles the token keeps full control of its tenant
+		Scope        string   `json:"scope,omitempty"`       // Optional, space-separated; harborhook:<role> scopes grant roles
+		RefreshToken string   `json:"refresh_token,omitempty"`
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -100,8 +83,37 @@ func createTokenHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if req.TenantID == "" {
-		http.Error(w, "tenant_id is required", http.StatusBadRequest)
+	now := time.Now()
+	var g grant
+	var spend *refreshClaims
+	switch req.GrantType {
+	case "", "client_credentials":
+		if req.TenantID == "" {
+			tokenError(w, req.GrantType, "tenant_id is required", http.StatusBadRequest)
+			return
+		}
+		g = grant{TenantID: req.TenantID, Roles: req.Roles, Scope: req.Scope}
+		if err := g.validate(); err != nil {
+			tokenError(w, req.GrantType, err.Error(), http.StatusBadRequest)
+			return
+		}
+	case "refresh_token":
+		rc, err := verifyRefresh(req.RefreshToken, now)
+		if err != nil {
+			tokenError(w, req.GrantType, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		g, spend = rc.grant, &rc
+	default:
+		tokenError(w, req.GrantType, fmt.Sprintf("unsupported grant_type %q", req.GrantType), http.StatusBadRequest)
+		return
+	}
+
+	if code, err := tokens.authorize(r, g.TenantID, spend != nil); err != nil {
+		if code == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="harborhook-jwks"`)
+		}
+		tokenError(w, req.GrantType, err.Error(), code)
 		return
 	}
 
@@ -109,48 +121,124 @@ func createTokenHandler(w http.ResponseWriter, r *http.Request) {
 	if ttl == 0 {
 		ttl = 3600 // Default to 1 hour
 	}
+	// A token must not outlive the key that signed it being published
+	if maxTTL := int(min(tokens.maxTTL, keys.retainFor).Seconds()); ttl > maxTTL {
+		tokenError(w, req.GrantType, fmt.Sprintf("ttl_seconds may be at most %d", maxTTL), http.StatusBadRequest)
+		return
+	}
 
-	// Create JWT token
-	token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
-		"iss":       "harborhook",
-		"aud":       "harborhook-api",
-		"sub":       req.TenantID,
-		"tenant_id": req.TenantID,
-		"iat":       time.Now().Unix(),
-		"exp":       time.Now().Add(time.Duration(ttl) * time.Second).Unix(),
-	})
-
-	token.Header["kid"] = keyID
+	if wait, ok := tokens.limiter.take(g.TenantID, now); !ok {
+		tokensIssued.WithLabelValues(grantLabel(req.GrantType), "rate_limited").Inc()
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+		http.Error(w, fmt.Sprintf("too many token requests for tenant %s", g.TenantID), http.StatusTooManyRequests)
+		return
+	}
+	// A refresh token is only spent once the request is otherwise good, so a rejected request
+	// can be retried with it
+	if spend != nil {
+		if err := tokens.spend(*spend, now); err != nil {
+			tokenError(w, req.GrantType, err.Error(), http.StatusUnauthorized)
+			return
+		}
+	}
 
-	// Sign the token
-	tokenString, err := token.SignedString(privateKey)
+	// Create and sign the JWT token
+	tokenString, err := sign(g.claims("harborhook-api", now, time.Duration(ttl)*time.Second))
 	if err != nil {
 		http.Error(w, "Failed to sign token", http.StatusInternalServerError)
 		return
 	}
+	refreshToken, err := tokens.newRefreshToken(g, now)
+	if err != nil {
+		http.Error(w, "Failed to sign refresh token", http.StatusInternalServerError)
+		return
+	}
 
 	response := map[string]any{
 		"token":      tokenString,
 		"expires_in": ttl,
 		"token_type": "Bearer",
 	}
+	if g.Scope != "" {
+		response["scope"] = g.Scope
+	}
+	if refreshToken != "" {
+		response["refresh_token"] = refreshToken
+		response["refresh_expires_in"] = int(min(tokens.refreshTTL, keys.retainFor).Seconds())
+	}
+	tokensIssued.WithLabelValues(grantLabel(req.GrantType), "issued").Inc()
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
 }
 
+// tokenError rejects a token request, counting it
+func tokenError(w http.ResponseWriter, grantType, msg string, code int) {
+	tokensIssued.WithLabelValues(grantLabel(grantType), "rejected").Inc()
+	http.Error(w, msg, code)
+}
+
+// grantLabel bounds the grant label to the grants the server knows
+func grantLabel(grantType string) string {
+	switch grantType {
+	case "", "client_credentials":
+		return "client_credentials"
+	case "refresh_token":
+		return grantType
+	}
+	return "other"
+}
+
 // healthHandler provides a simple health check endpoint
 func healthHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
 }
 
-// main starts the JWKS HTTP server
+// main loads the signing keys, starts rotating them and starts the JWKS HTTP server
 func main() {
-	// Register handlers (jwks, token, health)
+	var err error
+	keys, err = loadKeyring(
+		os.Getenv("JWKS_KEY_FILE"),
+		os.Getenv("JWT_PRIVATE_KEY"),
+		getEnvDuration("JWKS_ROTATION_INTERVAL", 30*24*time.Hour),
+		getEnvDuration("JWKS_PUBLISH_AHEAD", 10*time.Minute),
+		getEnvDuration("JWKS_RETAIN_RETIRED", 24*time.Hour),
+		time.Now(),
+	)
+	if err != nil {
+		log.Fatalf("Failed to load signing keys: %v", err)
+	}
+	log.Printf("Signing with key %s (%d published)", keys.signer().Kid, len(keys.published()))
+
+	tokens = newIssuer(
+		getEnvDuration("JWT_MAX_TTL", keys.retainFor),
+		getEnvDuration("JWT_REFRESH_TTL", 24*time.Hour),
+		getEnvInt("JWKS_ISSUE_RATE_PER_MIN", 60),
+	)
+	tokens.allowedTenants = parseTenants(os.Getenv("JWKS_ALLOWED_TENANTS"))
+	if devMode, _ := strconv.ParseBool(os.Getenv("DEV_MODE")); devMode {
+		log.Printf("DEV_MODE is on: /token issues tokens without an API key")
+	} else {
+		tokens.apiKey = os.Getenv("ADMIN_API_KEY")
+		if tokens.apiKey == "" {
+			log.Fatalf("ADMIN_API_KEY is required unless DEV_MODE is true")
+		}
+	}
+	if len(tokens.allowedTenants) > 0 {
+		log.Printf("Issuing tokens for %d allowed tenants", len(tokens.allowedTenants))
+	}
+	if tokens.maxTTL > keys.retainFor {
+		log.Printf("JWT_MAX_TTL %s is longer than JWKS_RETAIN_RETIRED; tokens are capped at %s", tokens.maxTTL, keys.retainFor)
+	}
+	go maintainKeys(keys, 30*time.Second)
+
+	// Register handlers (jwks, token, health, version, metrics)
 	http.HandleFunc("/.well-known/jwks.json", jwksHandler)
 	http.HandleFunc("/token", createTokenHandler)
 	http.HandleFunc("/healthz", healthHandler)
+	http.HandleFunc("/version", version.HTTPHandler())
+	http.Handle("/metrics", promhttp.Handler())
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -166,6 +254,43 @@ func main() {
 	}
 }
 
+// maintainKeys rotates and prunes keys every interval and keeps the key age metric current
+func maintainKeys(kr *keyring, interval time.Duration) {
+	kr.recordAges(time.Now())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		before := kr.signer().Kid
+		if err := kr.maintain(time.Now()); err != nil {
+			log.Printf("Key maintenance failed: %v", err)
+		}
+		if after := kr.signer().Kid; after != before {
+			log.Printf("Rotated signing key from %s to %s", before, after)
+		}
+		kr.recordAges(time.Now())
+	}
+}
+
+// getEnvDuration reads a duration such as 720h, falling back to def when unset or invalid
+func getEnvDuration(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil {
+			return d
+		}
+	}
+	return def
+}
+
+// getEnvInt reads an integer, falling back to def when unset or invalid
+func getEnvInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return def
+}
+
 // Helper functions for JWK encoding
 func base64UrlEncode(data []byte) string {
 	// Base64 URL encode without padding
@@ -132,18 +132,8 @@ func TestJwksHandler(t *testing.T) {
 		t.Fatalf("Failed to generate test RSA key: %v", err)
 	}
 
-	// Temporarily replace the global variables
-	originalPrivateKey := privateKey
-	originalPublicKey := publicKey
-	originalKeyID := keyID
-	privateKey = testPrivateKey
-	publicKey = &testPrivateKey.PublicKey
-	keyID = "test-key-1"
-	defer func() {
-		privateKey = originalPrivateKey
-		publicKey = originalPublicKey
-		keyID = originalKeyID
-	}()
+	// Temporarily replace the signing keys
+	useTestKey(t, testPrivateKey, "test-key-1")
 
 	req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
 	w := httptest.NewRecorder()
@@ -189,6 +179,22 @@ func TestJwksHandler(t *testing.T) {
 	if jwk.E == "" {
 		t.Error("jwksHandler() exponent E is empty")
 	}
+
+	// Revalidating with the ETag returns 304 and no body
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("jwksHandler() ETag is empty")
+	}
+	req = httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+	jwksHandler(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("jwksHandler() conditional status = %d, want %d", w.Code, http.StatusNotModified)
+	}
+	if w.Body.Len() != 0 {
+		t.Errorf("jwksHandler() conditional body = %q, want empty", w.Body.String())
+	}
 }
 
 func TestCreateTokenHandler(t *testing.T) {
@@ -198,15 +204,8 @@ func TestCreateTokenHandler(t *testing.T) {
 		t.Fatalf("Failed to generate test RSA key: %v", err)
 	}
 
-	// Temporarily replace the global variables
-	originalPrivateKey := privateKey
-	originalKeyID := keyID
-	privateKey = testPrivateKey
-	keyID = "test-key-1"
-	defer func() {
-		privateKey = originalPrivateKey
-		keyID = originalKeyID
-	}()
+	// Temporarily replace the signing keys
+	useTestKey(t, testPrivateKey, "test-key-1")
 
 	tests := []struct {
 		name               string
@@ -330,15 +329,8 @@ func TestCreateTokenHandler_TTLHandling(t *testing.T) {
 		t.Fatalf("Failed to generate test RSA key: %v", err)
 	}
 
-	// Temporarily replace the global variables
-	originalPrivateKey := privateKey
-	originalKeyID := keyID
-	privateKey = testPrivateKey
-	keyID = "test-key-1"
-	defer func() {
-		privateKey = originalPrivateKey
-		keyID = originalKeyID
-	}()
+	// Temporarily replace the signing keys
+	useTestKey(t, testPrivateKey, "test-key-1")
 
 	// Test default TTL (when ttl_seconds is 0 or not provided)
 	reqBody := `{"tenant_id":"test-tenant","ttl_seconds":0}`
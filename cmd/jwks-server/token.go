@@ -0,0 +1,259 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/austindbirch/harbor_hook/internal/auth"
+)
+
+// refreshAudience keeps refresh tokens from being accepted as access tokens, whose audience is
+// harborhook-api
+const refreshAudience = "harborhook-refresh"
+
+var tokensIssued = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "harborhook_jwks_tokens_issued_total",
+	Help: "Token requests by grant type and result (issued, rejected, rate_limited)",
+}, []string{"grant", "result"})
+
+func init() {
+	prometheus.MustRegister(tokensIssued)
+}
+
+// issuer holds the /token settings and the refresh tokens already spent
+type issuer struct {
+	maxTTL     time.Duration // longest access token; keys.retainFor caps it too
+	refreshTTL time.Duration // refresh token lifetime; zero issues none
+	limiter    *issueLimiter // nil issues without limit
+	// apiKey must be presented as a bearer credential to issue tokens for a tenant; empty (dev
+	// mode) lets anyone. Refresh grants are authenticated by the refresh token instead.
+	apiKey string
+	// allowedTenants are the only tenants tokens are issued for; empty allows any
+	allowedTenants map[string]bool
+
+	mu    sync.Mutex
+	spent map[string]time.Time // jti of used refresh tokens, until they expire
+}
+
+// tokens is the token issuer, configured in main
+var tokens = newIssuer(24*time.Hour, 24*time.Hour, 0)
+
+func newIssuer(maxTTL, refreshTTL time.Duration, perMinute int) *issuer {
+	return &issuer{maxTTL: maxTTL, refreshTTL: refreshTTL, limiter: newIssueLimiter(perMinute), spent: make(map[string]time.Time)}
+}
+
+// authorize checks a token request for tenantID: the API key for a new grant, then the tenant
+// allowlist. It returns the status to reject the request with.
+func (is *issuer) authorize(r *http.Request, tenantID string, refresh bool) (int, error) {
+	if is.apiKey != "" && !refresh {
+		key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(key), []byte(is.apiKey)) != 1 {
+			return http.StatusUnauthorized, errors.New("a valid API key is required")
+		}
+	}
+	if len(is.allowedTenants) > 0 && !is.allowedTenants[tenantID] {
+		return http.StatusForbidden, fmt.Errorf("tokens are not issued for tenant %s", tenantID)
+	}
+	return 0, nil
+}
+
+// parseTenants reads a comma-separated tenant allowlist
+func parseTenants(list string) map[string]bool {
+	tenants := map[string]bool{}
+	for _, t := range strings.Split(list, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tenants[t] = true
+		}
+	}
+	return tenants
+}
+
+// grant is what a token is issued for; a refresh token carries it to the tokens it renews
+type grant struct {
+	TenantID string
+	Roles    []string
+	Scope    string
+}
+
+// validate checks the roles, and the scopes that name roles
+func (g grant) validate() error {
+	for _, r := range g.Roles {
+		if _, err := auth.ParseRole(r); err != nil {
+			return err
+		}
+	}
+	for _, s := range strings.Fields(g.Scope) {
+		if r, ok := strings.CutPrefix(s, auth.RoleScopePrefix); ok {
+			if _, err := auth.ParseRole(r); err != nil {
+				return fmt.Errorf("scope %s: %w", s, err)
+			}
+		}
+	}
+	return nil
+}
+
+// claims returns the claims common to access and refresh tokens for g
+func (g grant) claims(audience string, now time.Time, ttl time.Duration) jwt.MapClaims {
+	claims := jwt.MapClaims{
+		"iss":       "harborhook",
+		"aud":       audience,
+		"sub":       g.TenantID,
+		"tenant_id": g.TenantID,
+		"iat":       now.Unix(),
+		"exp":       now.Add(ttl).Unix(),
+	}
+	if len(g.Roles) > 0 {
+		claims["roles"] = g.Roles
+	}
+	if g.Scope != "" {
+		claims["scope"] = g.Scope
+	}
+	return claims
+}
+
+// sign signs claims with the active key
+func sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	signer := keys.signer()
+	token.Header["kid"] = signer.Kid
+	return token.SignedString(signer.Key)
+}
+
+// newRefreshToken signs a refresh token for g, or returns "" when refresh tokens are off. It
+// lives no longer than the signing key stays published.
+func (is *issuer) newRefreshToken(g grant, now time.Time) (string, error) {
+	ttl := min(is.refreshTTL, keys.retainFor)
+	if ttl <= 0 {
+		return "", nil
+	}
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	claims := g.claims(refreshAudience, now, ttl)
+	claims["jti"] = hex.EncodeToString(jti)
+	return sign(claims)
+}
+
+// refreshClaims is a verified refresh token
+type refreshClaims struct {
+	grant
+	jti string
+	exp time.Time
+}
+
+// verifyRefresh checks a refresh token's signature, issuer, audience and expiry, returning the
+// grant it carries
+func verifyRefresh(refreshToken string, now time.Time) (refreshClaims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(refreshToken, claims, func(t *jwt.Token) (any, error) {
+		kid, _ := t.Header["kid"].(string)
+		for _, k := range keys.published() {
+			if k.Kid == kid {
+				return &k.Key.PublicKey, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg()}),
+		jwt.WithIssuer("harborhook"),
+		jwt.WithAudience(refreshAudience),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(func() time.Time { return now }),
+	)
+	if err != nil {
+		return refreshClaims{}, fmt.Errorf("invalid refresh token: %w", err)
+	}
+	rc := refreshClaims{}
+	rc.jti, _ = claims["jti"].(string)
+	rc.TenantID, _ = claims["tenant_id"].(string)
+	if rc.jti == "" || rc.TenantID == "" {
+		return refreshClaims{}, errors.New("invalid refresh token: missing jti or tenant_id")
+	}
+	exp, _ := claims.GetExpirationTime()
+	rc.exp = exp.Time
+	rc.Scope, _ = claims["scope"].(string)
+	if roles, ok := claims["roles"].([]any); ok {
+		for _, r := range roles {
+			if s, ok := r.(string); ok {
+				rc.Roles = append(rc.Roles, s)
+			}
+		}
+	}
+	return rc, nil
+}
+
+// spend marks a refresh token used. Each refresh token works once; the response that spends it
+// carries its replacement. Spent tokens are remembered per replica until they expire.
+func (is *issuer) spend(rc refreshClaims, now time.Time) error {
+	is.mu.Lock()
+	defer is.mu.Unlock()
+	for jti, until := range is.spent {
+		if now.After(until) {
+			delete(is.spent, jti)
+		}
+	}
+	if _, used := is.spent[rc.jti]; used {
+		return errors.New("refresh token already used")
+	}
+	is.spent[rc.jti] = rc.exp
+	return nil
+}
+
+// issueLimiter caps how many tokens each tenant is issued: a token bucket per tenant holding
+// perMinute issues and refilling at that rate. Limits are per replica.
+type issueLimiter struct {
+	perMinute float64
+
+	mu      sync.Mutex
+	buckets map[string]*issueBucket
+}
+
+type issueBucket struct {
+	tokens float64
+	at     time.Time // when tokens was last brought up to date
+}
+
+// newIssueLimiter returns a limit of perMinute issues per tenant, or nil (no limit) when
+// perMinute isn't positive
+func newIssueLimiter(perMinute int) *issueLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &issueLimiter{perMinute: float64(perMinute), buckets: make(map[string]*issueBucket)}
+}
+
+// take spends one of tenantID's issues at now, or reports how long until one is available. A
+// nil limiter always allows the issue.
+func (l *issueLimiter) take(tenantID string, now time.Time) (time.Duration, bool) {
+	if l == nil {
+		return 0, true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b, ok := l.buckets[tenantID]
+	if !ok {
+		b = &issueBucket{tokens: l.perMinute, at: now}
+		l.buckets[tenantID] = b
+	}
+	if elapsed := now.Sub(b.at); elapsed > 0 {
+		b.tokens = min(l.perMinute, b.tokens+elapsed.Minutes()*l.perMinute)
+		b.at = now
+	}
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0, true
+	}
+	return time.Duration((1 - b.tokens) / l.perMinute * float64(time.Minute)), false
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/austindbirch/harbor_hook/internal/auth"
+)
+
+// useTestIssuer makes is the token issuer for the duration of the test
+func useTestIssuer(t *testing.T, is *issuer) {
+	t.Helper()
+	original := tokens
+	tokens = is
+	t.Cleanup(func() { tokens = original })
+}
+
+type tokenResponse struct {
+	Token            string `json:"token"`
+	ExpiresIn        int    `json:"expires_in"`
+	Scope            string `json:"scope"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresIn int    `json:"refresh_expires_in"`
+}
+
+func postToken(t *testing.T, body string) (*httptest.ResponseRecorder, tokenResponse) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	createTokenHandler(w, httptest.NewRequest("POST", "/token", strings.NewReader(body)))
+	var resp tokenResponse
+	if w.Code == http.StatusOK {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w, resp
+}
+
+func TestCreateToken_ScopesAndRefresh(t *testing.T) {
+	priv, err := generateKey(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	useTestKey(t, priv.Key, priv.Kid)
+	useTestIssuer(t, newIssuer(2*time.Hour, 8*time.Hour, 0))
+	validator := auth.NewJWTValidatorFromKey(&priv.Key.PublicKey, "harborhook", "harborhook-api")
+
+	w, first := postToken(t, `{"tenant_id":"tn_1","scope":"openid harborhook:viewer"}`)
+	if w.Code != http.StatusOK || first.Scope != "openid harborhook:viewer" || first.RefreshToken == "" || first.RefreshExpiresIn != 8*3600 {
+		t.Fatalf("scoped token = %d %+v, want the scope echoed and an 8h refresh token", w.Code, first)
+	}
+	if p, err := validator.ValidateClaims(first.Token); err != nil || p.Role != auth.RoleViewer || p.TenantID != "tn_1" {
+		t.Errorf("scoped token principal = %+v, %v, want a tn_1 viewer", p, err)
+	}
+	if _, err := validator.ValidateClaims(first.RefreshToken); err == nil {
+		t.Error("refresh token accepted as an access token")
+	}
+
+	w, second := postToken(t, `{"grant_type":"refresh_token","refresh_token":"`+first.RefreshToken+`","ttl_seconds":600}`)
+	if w.Code != http.StatusOK || second.ExpiresIn != 600 || second.RefreshToken == "" || second.RefreshToken == first.RefreshToken {
+		t.Fatalf("refresh = %d %s %+v, want a new token and a new refresh token", w.Code, w.Body.String(), second)
+	}
+	if p, err := validator.ValidateClaims(second.Token); err != nil || p.Role != auth.RoleViewer {
+		t.Errorf("refreshed token principal = %+v, %v, want the original grant", p, err)
+	}
+
+	if w, _ := postToken(t, `{"grant_type":"refresh_token","refresh_token":"`+first.RefreshToken+`"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("reused refresh token status = %d, want 401", w.Code)
+	}
+	if w, _ := postToken(t, `{"grant_type":"refresh_token","refresh_token":"`+second.Token+`"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("access token as refresh token status = %d, want 401", w.Code)
+	}
+
+	for _, body := range []string{
+		`{"tenant_id":"tn_1","scope":"harborhook:root"}`,
+		`{"tenant_id":"tn_1","ttl_seconds":7201}`,
+		`{"grant_type":"password","tenant_id":"tn_1"}`,
+	} {
+		if w, _ := postToken(t, body); w.Code != http.StatusBadRequest {
+			t.Errorf("POST /token %s status = %d, want 400", body, w.Code)
+		}
+	}
+
+	// Refresh tokens are off with no refresh TTL
+	useTestIssuer(t, newIssuer(2*time.Hour, 0, 0))
+	if _, resp := postToken(t, `{"tenant_id":"tn_1"}`); resp.Token == "" || resp.RefreshToken != "" {
+		t.Errorf("token without refresh = %+v, want no refresh token", resp)
+	}
+}
+
+func TestCreateToken_RateLimit(t *testing.T) {
+	priv, err := generateKey(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	useTestKey(t, priv.Key, priv.Kid)
+	useTestIssuer(t, newIssuer(time.Hour, time.Hour, 2))
+
+	limited := testutil.ToFloat64(tokensIssued.WithLabelValues("client_credentials", "rate_limited"))
+	var refresh string
+	for i := 0; i < 2; i++ {
+		w, resp := postToken(t, `{"tenant_id":"tn_1"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("token %d status = %d, want 200", i, w.Code)
+		}
+		refresh = resp.RefreshToken
+	}
+	w, _ := postToken(t, `{"tenant_id":"tn_1"}`)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "30" {
+		t.Errorf("third token status = %d, Retry-After %q, want 429 after 30s", w.Code, w.Header().Get("Retry-After"))
+	}
+	if got := testutil.ToFloat64(tokensIssued.WithLabelValues("client_credentials", "rate_limited")); got != limited+1 {
+		t.Errorf("rate limited = %v, want %v", got, limited+1)
+	}
+	if w, _ := postToken(t, `{"tenant_id":"tn_2"}`); w.Code != http.StatusOK {
+		t.Errorf("other tenant status = %d, want 200", w.Code)
+	}
+
+	// A rate-limited refresh leaves the refresh token usable
+	if w, _ := postToken(t, `{"grant_type":"refresh_token","refresh_token":"`+refresh+`"}`); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("refresh over the limit status = %d, want 429", w.Code)
+	}
+	tokens.limiter = nil
+	if w, _ := postToken(t, `{"grant_type":"refresh_token","refresh_token":"`+refresh+`"}`); w.Code != http.StatusOK {
+		t.Errorf("refresh after the limit status = %d, want 200", w.Code)
+	}
+}
+
+func TestIssueLimiter(t *testing.T) {
+	l := newIssueLimiter(60)
+	start := time.Now()
+	for i := 0; i < 60; i++ {
+		if _, ok := l.take("tn_1", start); !ok {
+			t.Fatalf("issue %d refused, want a burst of 60", i)
+		}
+	}
+	if wait, ok := l.take("tn_1", start); ok || wait != time.Second {
+		t.Errorf("take() past the burst = %s, %v, want a 1s wait", wait, ok)
+	}
+	if _, ok := l.take("tn_1", start.Add(time.Second)); !ok {
+		t.Error("take() after refilling for 1s refused")
+	}
+	if _, ok := newIssueLimiter(0).take("tn_1", start); !ok {
+		t.Error("nil limiter refused an issue")
+	}
+}
+
+func TestCreateToken_Authorization(t *testing.T) {
+	priv, err := generateKey(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	useTestKey(t, priv.Key, priv.Kid)
+	is := newIssuer(time.Hour, time.Hour, 0)
+	is.apiKey = "s3cret"
+	is.allowedTenants = parseTenants("tn_1, tn_2,")
+	useTestIssuer(t, is)
+
+	post := func(body, authorization string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/token", strings.NewReader(body))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		createTokenHandler(w, req)
+		return w
+	}
+
+	if w := post(`{"tenant_id":"tn_1"}`, ""); w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("token without an API key status = %d, want 401 with WWW-Authenticate", w.Code)
+	}
+	if w := post(`{"tenant_id":"tn_1"}`, "Bearer wrong"); w.Code != http.StatusUnauthorized {
+		t.Errorf("token with a wrong API key status = %d, want 401", w.Code)
+	}
+	if w := post(`{"tenant_id":"tn_3"}`, "Bearer s3cret"); w.Code != http.StatusForbidden {
+		t.Errorf("token for a tenant off the allowlist status = %d, want 403", w.Code)
+	}
+	w := post(`{"tenant_id":"tn_2"}`, "Bearer s3cret")
+	if w.Code != http.StatusOK {
+		t.Fatalf("token with the API key status = %d, want 200", w.Code)
+	}
+
+	// The refresh token authenticates a refresh, but the tenant must still be allowed
+	var resp tokenResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	w = post(`{"grant_type":"refresh_token","refresh_token":"`+resp.RefreshToken+`"}`, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("refresh without the API key status = %d, want 200", w.Code)
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	is.allowedTenants = parseTenants("tn_1")
+	if w := post(`{"grant_type":"refresh_token","refresh_token":"`+resp.RefreshToken+`"}`, ""); w.Code != http.StatusForbidden {
+		t.Errorf("refresh for a tenant removed from the allowlist status = %d, want 403", w.Code)
+	}
+}
@@ -1,7 +1,7 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -11,56 +11,61 @@ import (
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-)
 
-// NSQStats represents the JSON structure returned by NSQ stats API
-type NSQStats struct {
-	Topics []struct {
-		TopicName string `json:"topic_name"`
-		Channels  []struct {
-			ChannelName string `json:"channel_name"`
-			Depth       int64  `json:"depth"`
-			InFlightCount int64 `json:"in_flight_count"`
-		} `json:"channels"`
-		Depth int64 `json:"depth"`
-	} `json:"topics"`
-}
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/queue"
+	"github.com/austindbirch/harbor_hook/internal/version"
+)
 
 var (
-	// Total queue backlog - what we really care about
+	// Total queue backlog across every nsqd - what we really care about
 	queueBacklog = prometheus.NewGauge(prometheus.GaugeOpts{
 		Name: "harborhook_queue_backlog",
 		Help: "Total number of messages waiting in the deliveries queue",
 	})
 
-	// Channel-specific metrics
+	// Channel-specific metrics (on Kafka, a channel is a consumer group and nsqd is "")
 	channelDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "harborhook_nsq_channel_depth",
-		Help: "Depth of NSQ channels by topic and channel",
-	}, []string{"topic", "channel"})
+		Help: "Depth of queue channels by nsqd, topic and channel",
+	}, []string{"nsqd", "topic", "channel"})
 
 	channelInflight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "harborhook_nsq_channel_inflight",
-		Help: "In-flight messages for NSQ channels by topic and channel",
-	}, []string{"topic", "channel"})
+		Help: "In-flight messages for queue channels by nsqd, topic and channel",
+	}, []string{"nsqd", "topic", "channel"})
+
+	// Whether each nsqd answered the last poll
+	nodeUp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "harborhook_nsq_node_up",
+		Help: "1 if the nsqd's stats were read on the last poll, 0 if not",
+	}, []string{"nsqd"})
 )
 
 func init() {
 	prometheus.MustRegister(queueBacklog)
 	prometheus.MustRegister(channelDepth)
 	prometheus.MustRegister(channelInflight)
+	prometheus.MustRegister(nodeUp)
 }
 
 func main() {
-	nsqdHost := getEnv("NSQD_HOST", "nsqd:4151")
+	cfg := config.FromEnv()
+	// NSQD_HOST predates NSQD_HTTP_ADDR and still wins when set
+	cfg.NSQ.NsqdHTTPAddr = getEnv("NSQD_HOST", cfg.NSQ.NsqdHTTPAddr)
 	port := getEnv("PORT", "8084")
 	interval := getEnvInt("POLL_INTERVAL_SECONDS", 15)
 
-	log.Printf("NSQ Monitor starting on port %s", port)
-	log.Printf("Monitoring NSQ at %s every %d seconds", nsqdHost, interval)
+	inspector, err := queue.NewInspector(cfg, queue.ChannelStats{Topic: cfg.NSQ.DeliveriesTopic, Channel: cfg.NSQ.WorkerChannel})
+	if err != nil {
+		log.Fatalf("Queue inspector creation failed: %v", err)
+	}
+
+	log.Printf("Queue Monitor starting on port %s", port)
+	log.Printf("Monitoring %s queue every %d seconds", cfg.Queue.Backend, interval)
 
 	// Start metrics collection in background
-	go collectMetrics(nsqdHost, time.Duration(interval)*time.Second)
+	go collectMetrics(inspector, cfg.NSQ.DeliveriesTopic, cfg.NSQ.WorkerChannel, time.Duration(interval)*time.Second)
 
 	// Expose metrics endpoint
 	http.Handle("/metrics", promhttp.Handler())
@@ -68,48 +73,62 @@ func main() {
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintln(w, "OK")
 	})
+	http.HandleFunc("/version", version.HTTPHandler())
 
 	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
 
-func collectMetrics(nsqdHost string, interval time.Duration) {
+func collectMetrics(inspector queue.Inspector, topic, channel string, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for range ticker.C {
-		if err := updateMetrics(nsqdHost); err != nil {
+		if err := updateMetrics(context.Background(), inspector, topic, channel); err != nil {
 			log.Printf("Error updating metrics: %v", err)
 		}
 	}
 }
 
-func updateMetrics(nsqdHost string) error {
-	resp, err := http.Get(fmt.Sprintf("http://%s/stats?format=json", nsqdHost))
-	if err != nil {
-		return fmt.Errorf("failed to get NSQ stats: %w", err)
+// updateMetrics records the depth of every channel on topic per nsqd; channel's depth summed
+// across nsqd is the backlog. When only some nsqd answered, their stats are still recorded, the
+// others are marked down and the error is returned.
+func updateMetrics(ctx context.Context, inspector queue.Inspector, topic, channel string) error {
+	stats, err := inspector.Stats(ctx)
+	if err != nil && len(stats) == 0 {
+		return fmt.Errorf("failed to get queue stats: %w", err)
 	}
-	defer resp.Body.Close()
 
-	var stats NSQStats
-	if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
-		return fmt.Errorf("failed to decode NSQ stats: %w", err)
+	// Reset so channels and nsqd that went away don't linger with their last value
+	channelDepth.Reset()
+	channelInflight.Reset()
+	nodeUp.Reset()
+	for _, c := range stats {
+		if c.Node != "" {
+			nodeUp.WithLabelValues(c.Node).Set(1)
+		}
+		if c.Topic != topic {
+			continue
+		}
+		// Update channel-specific metrics
+		channelDepth.WithLabelValues(c.Node, c.Topic, c.Channel).Set(float64(c.Depth))
+		channelInflight.WithLabelValues(c.Node, c.Topic, c.Channel).Set(float64(c.InFlight))
+	}
+	for _, node := range queue.FailedNodes(err) {
+		nodeUp.WithLabelValues(node).Set(0)
 	}
 
-	// Update metrics
-	for _, topic := range stats.Topics {
-		if topic.TopicName == "deliveries" {
-			for _, channel := range topic.Channels {
-				if channel.ChannelName == "workers" {
-					// This is the main queue backlog metric
-					queueBacklog.Set(float64(channel.Depth))
-				}
-				// Update channel-specific metrics
-				channelDepth.WithLabelValues(topic.TopicName, channel.ChannelName).Set(float64(channel.Depth))
-				channelInflight.WithLabelValues(topic.TopicName, channel.ChannelName).Set(float64(channel.InFlightCount))
-			}
+	// This is the main queue backlog metric
+	var backlog int64
+	for _, c := range queue.Totals(stats) {
+		if c.Topic == topic && c.Channel == channel {
+			backlog = c.Depth
 		}
 	}
+	queueBacklog.Set(float64(backlog))
 
+	if err != nil {
+		return fmt.Errorf("queue stats are partial: %w", err)
+	}
 	return nil
 }
 
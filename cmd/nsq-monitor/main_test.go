@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
 	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/queue"
 )
 
 func TestUpdateMetrics(t *testing.T) {
@@ -94,8 +100,16 @@ func TestUpdateMetrics(t *testing.T) {
 			}))
 			defer server.Close()
 
-			host := strings.TrimPrefix(server.URL, "http://")
-			err := updateMetrics(host)
+			cfg := config.Config{
+				Queue: config.Queue{Backend: queue.BackendNSQ},
+				NSQ:   config.NSQ{NsqdHTTPAddr: strings.TrimPrefix(server.URL, "http://")},
+			}
+			node := strings.TrimPrefix(server.URL, "http://")
+			inspector, err := queue.NewInspector(cfg)
+			if err != nil {
+				t.Fatalf("NewInspector returned error: %v", err)
+			}
+			err = updateMetrics(context.Background(), inspector, "deliveries", "workers")
 			if tc.wantErr {
 				if err == nil {
 					t.Fatalf("expected error, got nil")
@@ -111,22 +125,76 @@ func TestUpdateMetrics(t *testing.T) {
 			}
 
 			for lbl, want := range tc.wantDepth {
-				got := testutil.ToFloat64(channelDepth.WithLabelValues(lbl.topic, lbl.channel))
+				got := testutil.ToFloat64(channelDepth.WithLabelValues(node, lbl.topic, lbl.channel))
 				if got != want {
 					t.Fatalf("channelDepth[%s/%s] = %v, want %v", lbl.topic, lbl.channel, got, want)
 				}
 			}
 
 			for lbl, want := range tc.wantInflight {
-				got := testutil.ToFloat64(channelInflight.WithLabelValues(lbl.topic, lbl.channel))
+				got := testutil.ToFloat64(channelInflight.WithLabelValues(node, lbl.topic, lbl.channel))
 				if got != want {
 					t.Fatalf("channelInflight[%s/%s] = %v, want %v", lbl.topic, lbl.channel, got, want)
 				}
 			}
+			if got := testutil.ToFloat64(nodeUp.WithLabelValues(node)); got != 1 {
+				t.Fatalf("nodeUp[%s] = %v, want 1", node, got)
+			}
 		})
 	}
 }
 
+func TestUpdateMetrics_Lookupd(t *testing.T) {
+	queueBacklog.Set(0)
+	channelDepth.Reset()
+	channelInflight.Reset()
+	nodeUp.Reset()
+
+	nsqd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"topics":[{"topic_name":"deliveries","channels":[{"channel_name":"workers","depth":7,"in_flight_count":1}]}]}`))
+	}))
+	defer nsqd.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer down.Close()
+	up, downAddr := strings.TrimPrefix(nsqd.URL, "http://"), strings.TrimPrefix(down.URL, "http://")
+	lookupd := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		var producers []string
+		for _, addr := range []string{up, downAddr} {
+			host, port, _ := net.SplitHostPort(addr)
+			producers = append(producers, fmt.Sprintf(`{"broadcast_address":%q,"http_port":%s}`, host, port))
+		}
+		_, _ = w.Write([]byte(`{"producers":[` + strings.Join(producers, ",") + `]}`))
+	}))
+	defer lookupd.Close()
+
+	cfg := config.Config{
+		Queue: config.Queue{Backend: queue.BackendNSQ},
+		NSQ:   config.NSQ{LookupHTTPAddr: strings.TrimPrefix(lookupd.URL, "http://")},
+	}
+	inspector, err := queue.NewInspector(cfg)
+	if err != nil {
+		t.Fatalf("NewInspector returned error: %v", err)
+	}
+	if err := updateMetrics(context.Background(), inspector, "deliveries", "workers"); err == nil {
+		t.Fatal("expected an error naming the nsqd that failed, got nil")
+	}
+
+	if got := testutil.ToFloat64(queueBacklog); got != 7 {
+		t.Errorf("queueBacklog = %v, want the answering nsqd's 7", got)
+	}
+	if got := testutil.ToFloat64(channelDepth.WithLabelValues(up, "deliveries", "workers")); got != 7 {
+		t.Errorf("channelDepth[%s] = %v, want 7", up, got)
+	}
+	if got := testutil.ToFloat64(nodeUp.WithLabelValues(up)); got != 1 {
+		t.Errorf("nodeUp[%s] = %v, want 1", up, got)
+	}
+	if got := testutil.ToFloat64(nodeUp.WithLabelValues(downAddr)); got != 0 {
+		t.Errorf("nodeUp[%s] = %v, want 0", downAddr, got)
+	}
+}
+
 func TestGetEnv(t *testing.T) {
 	testCases := []struct {
 		name        string
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+)
+
+// retryBudget caps how many retries each endpoint schedules at normal backoff: a token bucket per
+// endpoint holding perMinute retries and refilling at that rate. During a receiver outage this
+// keeps retries from piling onto the first attempts of new events; retries past the budget still
+// happen, just later. The budget is per worker, not shared.
+type retryBudget struct {
+	perMinute float64
+
+	mu      sync.Mutex
+	buckets map[string]*budgetBucket
+}
+
+type budgetBucket struct {
+	tokens float64
+	at     time.Time // when tokens was last brought up to date
+}
+
+// newRetryBudget returns a budget of perMinute retries per endpoint, or nil (no budget) when
+// perMinute isn't positive
+func newRetryBudget(perMinute int) *retryBudget {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &retryBudget{perMinute: float64(perMinute), buckets: make(map[string]*budgetBucket)}
+}
+
+// take spends one of endpointID's retries at now, reporting false when none are left. A nil
+// budget always allows the retry.
+func (b *retryBudget) take(endpointID string, now time.Time) bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	k, ok := b.buckets[endpointID]
+	if !ok {
+		k = &budgetBucket{tokens: b.perMinute, at: now}
+		b.buckets[endpointID] = k
+	}
+	if elapsed := now.Sub(k.at); elapsed > 0 {
+		k.tokens = min(b.perMinute, k.tokens+elapsed.Minutes()*b.perMinute)
+		k.at = now
+	}
+	spent := k.tokens >= 1
+	if spent {
+		k.tokens--
+	}
+	metrics.RecordRetryBudget(endpointID, k.tokens, !spent)
+	return spent
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/logging"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+)
+
+func TestRetryBudget_Take(t *testing.T) {
+	b := newRetryBudget(3)
+	now := time.Now()
+	for i := range 3 {
+		if !b.take("ep_1", now) {
+			t.Fatalf("take() #%d = false, want the budget to cover it", i+1)
+		}
+	}
+	if b.take("ep_1", now) {
+		t.Error("take() after spending the budget = true, want false")
+	}
+	if !b.take("ep_2", now) {
+		t.Error("take() for another endpoint = false, want budgets kept per endpoint")
+	}
+	if got := testutil.ToFloat64(metrics.RetryBudgetRemaining.WithLabelValues("ep_1")); got != 0 {
+		t.Errorf("remaining budget gauge = %v, want 0", got)
+	}
+
+	// A third of a minute refills one of three retries
+	if !b.take("ep_1", now.Add(20*time.Second)) {
+		t.Error("take() after refilling = false, want true")
+	}
+	if b.take("ep_1", now.Add(20*time.Second)) {
+		t.Error("take() beyond the refill = true, want false")
+	}
+
+	var unlimited *retryBudget
+	if newRetryBudget(0) != nil || !unlimited.take("ep_1", now) {
+		t.Error("a zero budget should be nil and allow every retry")
+	}
+}
+
+func TestDeliveryHandler_DelayOverBudget(t *testing.T) {
+	cfg := config.FromEnv()
+	cfg.Worker.JitterPercent = 0
+	cfg.Worker.RetryBudgetDelay = 10 * time.Minute
+	h := &deliveryHandler{cfg: cfg, budget: newRetryBudget(1), logger: logging.New("harborhook-worker")}
+	a := &delivery.Attempt{
+		Task:     delivery.Task{DeliveryID: "dlv_1", EndpointID: "ep_delay"},
+		Endpoint: delivery.Endpoint{Policy: delivery.RetryPolicy{Backoff: []time.Duration{time.Minute}}},
+	}
+	exhausted := metrics.RetryBudgetExhaustedTotal.WithLabelValues("ep_delay")
+	before := testutil.ToFloat64(exhausted)
+
+	if got := h.Delay(a, 1); got != time.Minute {
+		t.Errorf("Delay() within budget = %v, want the 1m backoff", got)
+	}
+	if got := h.Delay(a, 1); got != 10*time.Minute {
+		t.Errorf("Delay() over budget = %v, want RetryBudgetDelay", got)
+	}
+	if got := testutil.ToFloat64(exhausted) - before; got != 1 {
+		t.Errorf("exhausted retries = %v, want 1", got)
+	}
+}
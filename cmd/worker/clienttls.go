@@ -0,0 +1,164 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+)
+
+const (
+	// clientTransportCacheSize bounds how many client certificates and proxies keep a transport,
+	// and with it a connection pool, at once
+	clientTransportCacheSize = 64
+	// clientSecretReload is how long a certificate read from a mounted secret is used before it
+	// is read again, so rotated secrets are picked up
+	clientSecretReload = 5 * time.Minute
+)
+
+// clientCert is the certificate an endpoint requires for mutual TLS: an uploaded PEM pair or
+// the name of a secret mounted under CLIENT_CERT_DIR
+type clientCert struct {
+	certPEM, keyPEM string
+	secretName      string
+}
+
+func (c clientCert) empty() bool {
+	return c.secretName == "" && c.certPEM == ""
+}
+
+// key identifies the certificate; uploads are keyed by content so a replaced pair gets a new transport
+func (c clientCert) key() string {
+	if c.secretName != "" {
+		return "secret:" + c.secretName
+	}
+	sum := sha256.Sum256([]byte(c.certPEM + "\x00" + c.keyPEM))
+	return "pem:" + hex.EncodeToString(sum[:])
+}
+
+// clientTransports hands out HTTP clients presenting an endpoint's client certificate and going
+// through its own proxy. Each certificate and proxy pair gets its own transport, built from the
+// worker's guarded base transport, and the least recently used ones are closed once there are
+// more than size.
+type clientTransports struct {
+	base    *http.Client    // shared by endpoints without a client certificate or proxy
+	dir     string          // where secrets are mounted
+	egress  *netguard.Guard // checks the hosts sent through endpoint proxies
+	size    int
+	reload  time.Duration
+	mu      sync.Mutex
+	order   *list.List // of *transportEntry, most recently used first
+	entries map[string]*list.Element
+}
+
+type transportEntry struct {
+	key      string
+	client   *http.Client
+	loadedAt time.Time
+}
+
+func newClientTransports(base *http.Client, dir string, egress *netguard.Guard) *clientTransports {
+	return &clientTransports{
+		base:    base,
+		dir:     dir,
+		egress:  egress,
+		size:    clientTransportCacheSize,
+		reload:  clientSecretReload,
+		order:   list.New(),
+		entries: map[string]*list.Element{},
+	}
+}
+
+// client returns the client to deliver with under cert, through proxy: the endpoint's proxy URL,
+// delivery.ProxyDirect, or "" for the base transport's
+func (c *clientTransports) client(cert clientCert, proxy string) (*http.Client, error) {
+	if cert.empty() && proxy == "" {
+		return c.base, nil
+	}
+	var key string
+	if !cert.empty() {
+		key = cert.key()
+	}
+	if proxy != "" {
+		sum := sha256.Sum256([]byte(proxy))
+		key += "|proxy:" + hex.EncodeToString(sum[:])
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		e := el.Value.(*transportEntry)
+		if cert.secretName == "" || time.Since(e.loadedAt) < c.reload {
+			c.order.MoveToFront(el)
+			return e.client, nil
+		}
+		c.remove(el)
+	}
+
+	base, ok := c.base.Transport.(*http.Transport)
+	if !ok {
+		base = http.DefaultTransport.(*http.Transport)
+	}
+	t := base.Clone()
+	if !cert.empty() {
+		tlsCert, err := c.load(cert)
+		if err != nil {
+			return nil, err
+		}
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		}
+		t.TLSClientConfig.Certificates = []tls.Certificate{tlsCert}
+	}
+	switch proxy {
+	case "":
+	case delivery.ProxyDirect:
+		t.Proxy = nil
+	default:
+		u, err := netguard.ParseProxyURL(proxy)
+		if err != nil {
+			return nil, err
+		}
+		t.Proxy = c.egress.ProxyVia(u)
+	}
+	e := &transportEntry{key: key, client: &http.Client{Timeout: c.base.Timeout, Transport: t}, loadedAt: time.Now()}
+	c.entries[key] = c.order.PushFront(e)
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+	return e.client, nil
+}
+
+// load parses an uploaded pair or reads a mounted secret
+func (c *clientTransports) load(cert clientCert) (tls.Certificate, error) {
+	if cert.secretName == "" {
+		tlsCert, _, err := delivery.ParseClientCertificate(cert.certPEM, cert.keyPEM)
+		return tlsCert, err
+	}
+	certFile, keyFile := delivery.ClientCertFiles(c.dir, cert.secretName)
+	certPEM, err := os.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("client certificate secret %s: %w", cert.secretName, err)
+	}
+	keyPEM, err := os.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("client certificate secret %s: %w", cert.secretName, err)
+	}
+	tlsCert, _, err := delivery.ParseClientCertificate(string(certPEM), string(keyPEM))
+	return tlsCert, err
+}
+
+// remove drops an entry and closes its idle connections; c.mu must be held
+func (c *clientTransports) remove(el *list.Element) {
+	e := c.order.Remove(el).(*transportEntry)
+	delete(c.entries, e.key)
+	e.client.CloseIdleConnections()
+}
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/logging"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+	"github.com/austindbirch/harbor_hook/internal/store"
+)
+
+// selfSignedPEM returns a throwaway client certificate and key for cn
+func selfSignedPEM(t *testing.T, cn string) (certPEM, keyPEM string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+}
+
+func TestClientTransports(t *testing.T) {
+	base := &http.Client{Transport: http.DefaultTransport.(*http.Transport).Clone()}
+	c := newClientTransports(base, t.TempDir(), nil)
+	c.size = 2
+
+	if got, err := c.client(clientCert{}, ""); err != nil || got != base {
+		t.Errorf("client(no certificate) = %p, %v, want the base client", got, err)
+	}
+
+	var certs []clientCert
+	for _, cn := range []string{"a", "b", "c"} {
+		certPEM, keyPEM := selfSignedPEM(t, cn)
+		certs = append(certs, clientCert{certPEM: certPEM, keyPEM: keyPEM})
+	}
+	first, err := c.client(certs[0], "")
+	if err != nil {
+		t.Fatalf("client() unexpected error: %v", err)
+	}
+	if again, _ := c.client(certs[0], ""); again != first {
+		t.Error("same certificate got a new client")
+	}
+	_, _ = c.client(certs[1], "")
+	_, _ = c.client(certs[2], "") // evicts certs[0], the least recently used
+	if _, ok := c.entries[certs[0].key()]; ok || c.order.Len() != 2 {
+		t.Errorf("cache holds %d entries including the oldest, want 2 without it", c.order.Len())
+	}
+
+	if _, err := c.client(clientCert{certPEM: certs[0].certPEM, keyPEM: certs[1].keyPEM}, ""); err == nil {
+		t.Error("client() with a mismatched key: expected error")
+	}
+}
+
+func TestClientTransports_Secret(t *testing.T) {
+	dir := t.TempDir()
+	c := newClientTransports(&http.Client{}, dir, nil)
+	secret := clientCert{secretName: "partner-mtls"}
+
+	if _, err := c.client(secret, ""); err == nil || !strings.Contains(err.Error(), "partner-mtls") {
+		t.Errorf("client(missing secret) error = %v, want it to name the secret", err)
+	}
+
+	certPEM, keyPEM := selfSignedPEM(t, "partner")
+	certFile, keyFile := delivery.ClientCertFiles(dir, "partner-mtls")
+	if err := os.MkdirAll(filepath.Dir(certFile), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.WriteFile(certFile, []byte(certPEM), 0o600)
+	_ = os.WriteFile(keyFile, []byte(keyPEM), 0o600)
+
+	first, err := c.client(secret, "")
+	if err != nil {
+		t.Fatalf("client(secret) unexpected error: %v", err)
+	}
+	c.reload = 0 // the mounted secret is read again on next use
+	if again, _ := c.client(secret, ""); again == first {
+		t.Error("secret was not reloaded")
+	}
+}
+
+func TestClientTransports_Proxy(t *testing.T) {
+	var seen []string
+	server := func(name string) *httptest.Server {
+		srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { seen = append(seen, name) }))
+		t.Cleanup(srv.Close)
+		return srv
+	}
+	sink, egressProxy, endpointProxy := server("sink"), server("egress proxy"), server("endpoint proxy")
+
+	egress, _ := netguard.New([]string{"127.0.0.1"})
+	if err := egress.SetProxy(netguard.Proxy{URL: egressProxy.URL}); err != nil {
+		t.Fatal(err)
+	}
+	c := newClientTransports(&http.Client{Transport: egress.Transport()}, t.TempDir(), egress)
+
+	for _, proxy := range []string{"", endpointProxy.URL, delivery.ProxyDirect} {
+		client, err := c.client(clientCert{}, proxy)
+		if err != nil {
+			t.Fatalf("client(proxy %q) unexpected error: %v", proxy, err)
+		}
+		resp, err := client.Get(sink.URL)
+		if err != nil {
+			t.Fatalf("GET through proxy %q: unexpected error: %v", proxy, err)
+		}
+		resp.Body.Close()
+	}
+	if want := []string{"egress proxy", "endpoint proxy", "sink"}; strings.Join(seen, ",") != strings.Join(want, ",") {
+		t.Errorf("requests reached %v, want %v", seen, want)
+	}
+
+	if _, err := c.client(clientCert{}, "socks5://proxy:1080"); err == nil {
+		t.Error("client(socks5 proxy) succeeded, want an error")
+	}
+}
+
+func TestHandle_ClientCertificate(t *testing.T) {
+	var peerCN string
+	sink := httptest.NewUnstartedServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		peerCN = r.TLS.PeerCertificates[0].Subject.CommonName
+	}))
+	sink.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	sink.StartTLS()
+	defer sink.Close()
+
+	certPEM, keyPEM := selfSignedPEM(t, "harborhook-worker")
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.created",
+	})
+
+	pool := handlerPool()
+	answer := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "SELECT e.secret") {
+			return dbfake.Row{Values: []any{"whsec_1", false, 0, 0, nil, nil, true, certPEM, keyPEM, "", "none", "v1", nil, 0, "http", "", false}}
+		}
+		return answer(sql, args)
+	}
+	h := &deliveryHandler{
+		cfg:        config.FromEnv(),
+		pool:       pool,
+		store:      store.New(pool),
+		feed:       changefeed.New(discardPublisher{}, "changefeed"),
+		retries:    discardPublisher{},
+		client:     sink.Client(),
+		transports: newClientTransports(sink.Client(), t.TempDir(), nil),
+		gate:       &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:      &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:     logging.New("harborhook-worker"),
+	}
+
+	h.handle(&benchMessage{body: body})
+	if peerCN != "harborhook-worker" {
+		t.Errorf("receiver saw client certificate %q, want harborhook-worker", peerCN)
+	}
+}
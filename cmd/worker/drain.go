@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// errDrainDeadline cancels sends still running when the drain deadline passes
+var errDrainDeadline = errors.New("worker drain deadline passed")
+
+// drainer tracks the deliveries a worker is sending, so that on shutdown it can stop taking new
+// tasks, wait up to a deadline for the sends in flight, and hand the rest back to the queue.
+// A nil drainer never drains.
+type drainer struct {
+	draining atomic.Bool
+	inflight atomic.Int64 // deliveries marked inflight whose outcome isn't recorded yet
+	requeued atomic.Int64 // tasks handed back since the drain began
+
+	mu       sync.Mutex
+	started  time.Time
+	deadline time.Time
+	abortCtx context.Context // cancelled with errDrainDeadline once the deadline passes
+	abortFn  context.CancelCauseFunc
+}
+
+// drainStatus is the drain progress /healthz reports
+type drainStatus struct {
+	OK        bool      `json:"ok"`
+	Draining  bool      `json:"draining"`
+	StartedAt time.Time `json:"started_at"`
+	Deadline  time.Time `json:"deadline"`
+	InFlight  int64     `json:"inflight"`
+	Requeued  int64     `json:"requeued"`
+}
+
+// abortContext returns the context aborted sends derive from, creating it on first use
+func (d *drainer) abortContext() context.Context {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.abortCtx == nil {
+		d.abortCtx, d.abortFn = context.WithCancelCause(context.Background())
+	}
+	return d.abortCtx
+}
+
+// begin starts draining: new tasks are handed back and sends in flight have until timeout
+func (d *drainer) begin(timeout time.Duration) {
+	d.mu.Lock()
+	d.started = time.Now()
+	d.deadline = d.started.Add(timeout)
+	d.mu.Unlock()
+	d.draining.Store(true)
+}
+
+// active reports whether the worker is draining
+func (d *drainer) active() bool {
+	return d != nil && d.draining.Load()
+}
+
+// abort cancels the sends still in flight; their tasks are requeued instead of failed
+func (d *drainer) abort() {
+	d.abortContext()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.abortFn(errDrainDeadline)
+}
+
+// track counts a delivery as in flight until done is called. Its send should use the returned
+// context, which is cancelled if the drain deadline passes first.
+func (d *drainer) track(ctx context.Context) (context.Context, func()) {
+	if d == nil {
+		return ctx, func() {}
+	}
+	d.inflight.Add(1)
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(d.abortContext(), func() { cancel(errDrainDeadline) })
+	return ctx, func() {
+		stop()
+		cancel(nil)
+		d.inflight.Add(-1)
+	}
+}
+
+// aborted reports whether a send's context from track was cancelled by the drain deadline
+func (d *drainer) aborted(ctx context.Context) bool {
+	return d != nil && errors.Is(context.Cause(ctx), errDrainDeadline)
+}
+
+// handedBack counts a task returned to the queue because the worker is draining
+func (d *drainer) handedBack() {
+	if d != nil {
+		d.requeued.Add(1)
+	}
+}
+
+// status reports the drain's progress
+func (d *drainer) status() drainStatus {
+	if !d.active() {
+		return drainStatus{OK: true}
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return drainStatus{
+		Draining:  true,
+		StartedAt: d.started,
+		Deadline:  d.deadline,
+		InFlight:  d.inflight.Load(),
+		Requeued:  d.requeued.Load(),
+	}
+}
+
+// healthHandler serves /healthz: ok, or 503 with the drain's progress once draining, so
+// readiness checks stop counting the worker while it finishes up
+func (d *drainer) healthHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		st := d.status()
+		if st.OK {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"ok":true}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_ = json.NewEncoder(w).Encode(st)
+	}
+}
+
+// drain stops consumer, waiting up to timeout for the deliveries in flight before aborting them.
+// It returns once every handler has returned.
+func (d *drainer) drain(stop func(), timeout time.Duration) (aborted bool) {
+	d.begin(timeout)
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-stopped:
+		return false
+	case <-timer.C:
+		d.abort()
+		<-stopped
+		return true
+	}
+}
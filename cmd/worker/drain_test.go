@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/logging"
+	"github.com/austindbirch/harbor_hook/internal/store"
+)
+
+func TestDrainer_Health(t *testing.T) {
+	d := &drainer{}
+	rec := httptest.NewRecorder()
+	d.healthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"ok":true}` {
+		t.Errorf("healthz before drain = %d %s, want 200 ok", rec.Code, rec.Body)
+	}
+
+	_, done := d.track(context.Background())
+	defer done()
+	d.begin(time.Minute)
+	d.handedBack()
+	rec = httptest.NewRecorder()
+	d.healthHandler()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	var st drainStatus
+	_ = json.Unmarshal(rec.Body.Bytes(), &st)
+	if rec.Code != http.StatusServiceUnavailable || st.OK || !st.Draining || st.InFlight != 1 || st.Requeued != 1 {
+		t.Errorf("healthz while draining = %d %+v, want 503 with 1 in flight and 1 requeued", rec.Code, st)
+	}
+	if got := st.Deadline.Sub(st.StartedAt); got != time.Minute {
+		t.Errorf("drain deadline %v after start, want 1m", got)
+	}
+
+	var nilDrainer *drainer
+	if nilDrainer.active() || !nilDrainer.status().OK {
+		t.Error("nil drainer reports draining")
+	}
+}
+
+func TestDrainer_Drain(t *testing.T) {
+	// Handlers finish before the deadline: nothing is aborted
+	d := &drainer{}
+	if d.drain(func() {}, time.Minute) {
+		t.Error("drain() aborted sends although the consumer stopped in time")
+	}
+
+	// A send still running at the deadline is cancelled, and the consumer stop then returns
+	d = &drainer{}
+	ctx, done := d.track(context.Background())
+	stop := func() {
+		<-ctx.Done()
+		done()
+	}
+	if !d.drain(stop, 10*time.Millisecond) {
+		t.Error("drain() didn't abort the send running at the deadline")
+	}
+	if !d.aborted(ctx) || d.inflight.Load() != 0 {
+		t.Errorf("aborted = %v with %d in flight, want the send aborted and none left", d.aborted(ctx), d.inflight.Load())
+	}
+}
+
+func TestHandle_DrainDeadline(t *testing.T) {
+	received, release := make(chan struct{}), make(chan struct{})
+	sink := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(received)
+		<-release // doesn't answer before the drain deadline
+	}))
+	defer sink.Close()
+	defer close(release)
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.created",
+		Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+	})
+
+	var (
+		mu        sync.Mutex
+		resetTo   any
+		attempted bool
+	)
+	pool := handlerPool()
+	pool.ExecFunc = func(sql string, args []any) (pgconn.CommandTag, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case strings.Contains(sql, "status='inflight'") && len(args) == 2:
+			resetTo = args[1]
+		case strings.Contains(sql, "attempt=attempt+1"):
+			attempted = true
+		}
+		return pgconn.CommandTag{}, nil
+	}
+	drain := &drainer{}
+	h := &deliveryHandler{
+		cfg:     config.FromEnv(),
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		drain:   drain,
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	m := &heldMessage{benchMessage: benchMessage{body: body}, held: -1}
+	handled := make(chan struct{})
+	go func() {
+		h.handle(m)
+		close(handled)
+	}()
+	<-received
+	if n := drain.inflight.Load(); n != 1 {
+		t.Errorf("%d deliveries in flight during the send, want 1", n)
+	}
+	drain.begin(0)
+	drain.abort()
+	<-handled
+
+	mu.Lock()
+	defer mu.Unlock()
+	if m.held != 0 || attempted || resetTo != "queued" {
+		t.Errorf("held %v, attempted %v, status reset to %v, want requeued at once as queued without an attempt", m.held, attempted, resetTo)
+	}
+	if drain.inflight.Load() != 0 || drain.requeued.Load() != 1 {
+		t.Errorf("%d in flight and %d requeued after the abort, want 0 and 1", drain.inflight.Load(), drain.requeued.Load())
+	}
+}
@@ -0,0 +1,411 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/blobstore"
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/compliance"
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/logging"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/queue"
+	"github.com/austindbirch/harbor_hook/internal/store"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// deliveryHandler sends each delivery task handed to the worker and records the outcome
+type deliveryHandler struct {
+	cfg     config.Config
+	pool    db.Pool
+	store   store.Store      // deliveries, endpoints and the DLQ, on pool
+	feed    *changefeed.Feed // nil disables changefeed publishing
+	retries queue.Publisher
+	dlq     queue.Publisher // nil unless PublishDLQ is set
+	client  *http.Client
+
+	transports *clientTransports // serves endpoints with a client certificate or proxy; nil uses client for all
+
+	recordings *compliance.Cipher // nil when request recording is not configured
+
+	blobs blobstore.Store // holds payloads tasks carry by reference
+
+	gate   *dispatchGate
+	ramps  *endpointRamps
+	budget *retryBudget // nil doesn't limit retries
+
+	// Drains on shutdown: tasks still buffered are handed back instead of sent, and sends still
+	// running at the drain deadline are aborted and requeued. nil never drains.
+	drain *drainer
+
+	logger *logging.Logger
+}
+
+// handle processes one delivery task; it is the consumer's queue.Handler
+func (h *deliveryHandler) handle(m queue.Message) {
+	defer func() {
+		if !m.HasResponded() {
+			h.logger.Plain().Warn("message had no response, finishing")
+			m.Finish()
+		}
+	}()
+
+	var t delivery.Task
+	if err := json.Unmarshal(m.Body(), &t); err != nil {
+		h.logger.Plain().WithError(err).Error("bad task payload")
+		metrics.RecordDelivery("failed", "unknown", "unknown", 0)
+		m.Finish() // terminal: don't retry bad payloads
+		return
+	}
+
+	// Extract trace context from NSQ message headers and start span
+	ctx := tracing.ExtractTraceFromNSQ(context.Background(), t.TraceHeaders)
+	ctx, span := tracing.StartSpan(ctx, "worker.delivery",
+		attribute.String("delivery_id", t.DeliveryID),
+		attribute.String("event_id", t.EventID),
+		attribute.String("tenant_id", t.TenantID),
+		attribute.String("endpoint_id", t.EndpointID),
+		attribute.String("endpoint_url", t.EndpointURL),
+		attribute.String("event_type", t.EventType),
+		attribute.Int("attempt", t.Attempt),
+		// A retry follows a failed attempt, so its trace is kept whatever the sample ratio
+		tracing.AlwaysSampleKey.Bool(t.Attempt > 0),
+	)
+	defer span.End()
+
+	// While draining, hand the task back with whatever is left of its retry delay so a
+	// restart doesn't move its next attempt
+	if h.drain.active() {
+		tracing.AddSpanEvent(ctx, "dispatch.held", attribute.String("reason", "draining"))
+		metrics.RecordDispatchHeld("draining")
+		h.drain.handedBack()
+		m.RequeueWithoutBackoff(min(t.Remaining(time.Now()), h.cfg.Worker.MaxDeferral))
+		return
+	}
+
+	// Past the event's deadline the delivery is of no use to the receiver, so it goes to the
+	// DLQ without another attempt, wherever it was waiting
+	if t.Expired(time.Now()) {
+		tracing.AddSpanEvent(ctx, "delivery.expired", attribute.String("deliver_by", t.DeliverBy))
+		h.deadLetter(ctx, t, pendingStatus(t), t.Attempt, 0, "", "expired")
+		span.SetAttributes(attribute.String("delivery.final_status", "dead"), tracing.ErrorKey.Bool(true))
+		metrics.RecordDLQ("expired")
+		metrics.RecordDeliveryOutcome(t.TenantID, t.EndpointID, false, 0)
+		m.Finish()
+		return
+	}
+
+	// Tasks that arrive before their retry is due (nsqd caps deferrals at MaxDeferral)
+	// wait out the rest of the delay without spending an attempt
+	if wait := t.Remaining(time.Now()); wait > 0 {
+		tracing.AddSpanEvent(ctx, "dispatch.held", attribute.String("reason", "not_due"))
+		metrics.RecordDispatchHeld("not_due")
+		m.RequeueWithoutBackoff(min(wait, h.cfg.Worker.MaxDeferral))
+		return
+	}
+
+	// Cluster-wide kill switch: hold the task (without spending an attempt) while paused or ramping up
+	st, err := h.gate.current(ctx)
+	if err != nil {
+		h.logger.WithContext(ctx).WithError(err).Warn("Failed to read dispatch state, using last known")
+	}
+	if !st.Admit(time.Now(), rand.Float64()) {
+		reason := "ramp"
+		if st.Paused {
+			reason = "paused"
+		}
+		tracing.AddSpanEvent(ctx, "dispatch.held", attribute.String("reason", reason))
+		metrics.RecordDispatchHeld(reason)
+		m.RequeueWithoutBackoff(holdDelay(st.Paused))
+		return
+	}
+
+	// Endpoints failing their health checks get nothing, and recently recovered ones only a share
+	// of their backlog until the ramp finishes
+	if ramp, err := h.ramps.get(ctx, t.EndpointID); err != nil {
+		h.logger.WithContext(ctx).WithEndpoint(t.EndpointID).WithError(err).Warn("Failed to read endpoint recovery ramp")
+	} else if !ramp.Admit(time.Now(), rand.Float64()) {
+		reason := "recovery"
+		if ramp.Unhealthy {
+			reason = "unhealthy"
+		}
+		tracing.AddSpanEvent(ctx, "dispatch.held", attribute.String("reason", reason))
+		metrics.RecordDispatchHeld(reason)
+		m.RequeueWithoutBackoff(holdDelay(false))
+		return
+	}
+
+	// Frozen or drained deliveries are parked instead of sent; ResumeDeliveries requeues them
+	if from, parked, err := parkIfFrozen(ctx, h.pool, t); err != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Warn("Failed to check delivery freezes")
+	} else if parked {
+		// Drained deliveries were already reported parked by DrainQueue
+		if from != "parked" {
+			h.feed.Publish(changefeed.FromTask(t, from, "parked"))
+		}
+		tracing.AddSpanEvent(ctx, "delivery.parked")
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithEndpoint(t.EndpointID).Info("Delivery parked by freeze")
+		metrics.RecordDelivery("parked", t.TenantID, t.EndpointID, 0)
+		m.Finish()
+		return
+	}
+
+	// Ordered endpoints get one delivery at a time per partition, in publish order. If the
+	// check fails the task is held too, since sending it could overtake an earlier event.
+	if t.Ordered {
+		wait, held, err := orderedHold(ctx, h.pool, t.DeliveryID)
+		if err != nil {
+			h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Warn("Failed to check delivery ordering, holding")
+			wait, held = orderedHoldDelay, true
+		}
+		if held {
+			tracing.AddSpanEvent(ctx, "dispatch.held", attribute.String("reason", "ordered"))
+			metrics.RecordDispatchHeld("ordered")
+			m.RequeueWithoutBackoff(min(wait, h.cfg.Worker.MaxDeferral))
+			return
+		}
+	}
+
+	// Payloads over the claim-check threshold travel as a reference; fetch them back before
+	// the delivery goes inflight so a blob store outage just hands the task back
+	payload := t.Payload
+	if t.PayloadRef != "" {
+		tracing.AddSpanEvent(ctx, "blobstore.get_payload")
+		payload, err = h.loadPayload(ctx, t.PayloadRef)
+		if errors.Is(err, blobstore.ErrNotFound) {
+			h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("Payload missing from blob store")
+			h.failTerminal(ctx, m, t, pendingStatus(t), "payload_missing")
+			return
+		}
+		if err != nil {
+			h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Warn("Failed to fetch payload, requeueing")
+			tracing.SetSpanError(ctx, err)
+			m.Requeue(minRetryDelay)
+			return
+		}
+	}
+
+	// The stored bytes are sent as they are, so retries and replays sign the same body; only a
+	// projected payload is encoded again
+	body, err := delivery.PayloadBody(payload, t.IncludeFields, t.ExcludeFields)
+	if err != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("Payload is not a JSON object")
+		h.failTerminal(ctx, m, t, pendingStatus(t), "payload_invalid")
+		return
+	}
+
+	// Mark dequeued/inflight
+	tracing.AddSpanEvent(ctx, "db.update_delivery_inflight")
+	_ = h.store.MarkInflight(ctx, t.DeliveryID)
+	h.feed.Publish(changefeed.FromTask(t, pendingStatus(t), "inflight"))
+
+	// In flight until its outcome is recorded; a drain waits for it up to its deadline
+	sendCtx, sent := h.drain.track(ctx)
+	defer sent()
+
+	// Fetch endpoint secret for signing (and the tenant's key, for ed25519), its retry policy, and the tenant's compliance mode
+	tracing.AddSpanEvent(ctx, "db.fetch_endpoint_secret")
+	ep, err := h.store.EndpointConfig(ctx, t.EndpointID)
+	if err != nil || ep.Secret == "" {
+		tracing.SetSpanError(ctx, err)
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithEndpoint(t.EndpointID).WithError(err).Error("No secret for endpoint")
+		h.failTerminal(ctx, m, t, "inflight", "endpoint_secret_missing") // can't sign without secret
+		return
+	}
+	if ep.Disabled {
+		// Disabled for failing since the task was queued; it can be replayed once re-enabled
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithEndpoint(t.EndpointID).Warn("Endpoint is disabled")
+		h.failTerminal(ctx, m, t, "inflight", "endpoint_disabled")
+		return
+	}
+	a := &delivery.Attempt{Task: t, Endpoint: delivery.Endpoint{
+		Secret:          ep.Secret,
+		SignatureScheme: ep.SignatureScheme,
+		Compression:     ep.Compression,
+		SenderHeaders:   ep.SenderHeaders,
+		Policy: delivery.RetryPolicyFromColumns(ep.RetryMaxAttempts, ep.RetryBackoffSeconds, ep.RetryOn).
+			Resolve(h.cfg.Worker.MaxAttempts, h.cfg.Worker.BackoffSchedule),
+		Timeout:          ep.Timeout,
+		Type:             ep.Type,
+		ProxyURL:         ep.ProxyURL,
+		RecordRequests:   ep.RecordRequests,
+		RetentionDays:    ep.RetentionDays,
+		ClientCertPEM:    ep.ClientCertPEM,
+		ClientKeyPEM:     ep.ClientKeyPEM,
+		ClientCertSecret: ep.ClientCertSecret,
+	}}
+	if ep.SignatureScheme == delivery.SignatureEd25519 {
+		if len(ep.SigningSeed) != ed25519.SeedSize {
+			h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithEndpoint(t.EndpointID).Error("No signing key for tenant")
+			h.failTerminal(ctx, m, t, "inflight", "signing_key_missing")
+			return
+		}
+		a.Endpoint.SigningKey = ed25519.NewKeyFromSeed(ep.SigningSeed)
+	}
+	a.Body = body
+
+	out := h.engine().Deliver(sendCtx, a)
+	if out.Result == delivery.ResultAbandoned {
+		h.requeueDrained(ctx, m, t)
+		return
+	}
+	r := out.Response
+
+	// Add HTTP response attributes to span
+	span.SetAttributes(
+		attribute.Int("http.status_code", r.Status),
+		attribute.Int64("http.latency_ms", r.Latency.Milliseconds()),
+	)
+	if r.Err != nil {
+		tracing.SetSpanAttributes(ctx, attribute.String("http.error", r.Err.Error()))
+	}
+
+	if out.Result == delivery.ResultDelivered {
+		// Record successful delivery with enhanced metrics
+		metrics.RecordDelivery("delivered", t.TenantID, t.EndpointID, r.Latency)
+		metrics.RecordHTTPDelivery(t.TenantID, t.EndpointID, strconv.Itoa(r.Status), r.Latency)
+		metrics.RecordDeliveryOutcome(t.TenantID, t.EndpointID, true, r.Latency)
+		m.Finish() // explicit ack
+		return
+	}
+
+	// record the failure class for metrics
+	span.SetAttributes(attribute.String("failure_reason", out.Class), tracing.ErrorKey.Bool(true))
+	metrics.RecordRetry(out.Class)
+	metrics.RecordDelivery("failed", t.TenantID, t.EndpointID, r.Latency)
+	if r.Status > 0 {
+		metrics.RecordHTTPDelivery(t.TenantID, t.EndpointID, strconv.Itoa(r.Status), r.Latency)
+	}
+
+	if out.Result == delivery.ResultDead {
+		span.SetAttributes(
+			attribute.String("delivery.final_status", "dead"),
+			attribute.Int("delivery.final_attempt", out.Attempt),
+		)
+		if out.Reason == "expired" {
+			metrics.RecordDLQ("expired")
+		} else {
+			metrics.RecordDLQ(out.Class)
+		}
+		metrics.RecordDeliveryOutcome(t.TenantID, t.EndpointID, false, r.Latency)
+		m.Finish() // drop from main topic
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("delivery.final_status", "requeued"),
+		attribute.Int("delivery.next_attempt", out.Attempt),
+	)
+	if out.Err != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(out.Err).Warn("retry publish failed, requeueing")
+		tracing.SetSpanError(ctx, out.Err)
+		m.Requeue(out.Delay)
+		return
+	}
+	m.Finish()
+}
+
+// engine runs the handler's delivery attempts, with the handler as each stage
+func (h *deliveryHandler) engine() *delivery.DeliveryEngine {
+	return &delivery.DeliveryEngine{
+		Signer:     h,
+		Sender:     h,
+		Classifier: delivery.ClassifierFunc(classifyReason),
+		Persister:  h,
+		Retrier:    h,
+	}
+}
+
+// deadLetter moves a delivery from status from into the DLQ: the dlq row, status dead, its change
+// and, when enabled, the DLQ topic. status and lastErr describe the last attempt, if there was one.
+func (h *deliveryHandler) deadLetter(ctx context.Context, t delivery.Task, from string, attempt, status int, lastErr, deadReason string) {
+	// Insert into DLQ table first
+	detail := deadReason
+	if status != 0 || lastErr != "" {
+		detail = fmt.Sprintf("%s, last status=%d, err=%s", deadReason, status, lastErr)
+	}
+	if qErr := h.store.AddToDLQ(ctx, t.DeliveryID, detail); qErr != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(qErr).Error("dlq insert failed")
+		tracing.SetSpanError(ctx, qErr)
+	}
+
+	// Update delivery status to dead (this will trigger our automatic dlq_at timestamp)
+	if updateErr := h.store.MarkDead(ctx, t.DeliveryID); updateErr != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(updateErr).Error("dlq status update failed")
+		tracing.SetSpanError(ctx, updateErr)
+	} else {
+		change := changefeed.FromTask(t, from, "dead")
+		change.Attempt, change.HTTPStatus, change.Error = attempt, status, lastErr
+		h.feed.Publish(change)
+	}
+
+	// DLQ (topic publish)
+	if h.cfg.Worker.PublishDLQ && h.dlq != nil {
+		env := delivery.NewDeadLetter(t, attempt, status, lastErr, deadReason)
+		b, _ := json.Marshal(env)
+		if err := h.dlq.Publish(h.cfg.NSQ.DLQTopic, b); err != nil {
+			h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("dlq publish failed")
+			tracing.SetSpanError(ctx, err)
+		} else {
+			h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithField("topic", h.cfg.NSQ.DLQTopic).Info("dlq published")
+			tracing.AddSpanEvent(ctx, "nsq.published_dlq", attribute.String("topic", h.cfg.NSQ.DLQTopic))
+		}
+	}
+}
+
+// requeueDrained hands back a task whose send was cut off by the drain deadline, without
+// spending an attempt. The receiver may still have seen the request, as with any redelivery.
+func (h *deliveryHandler) requeueDrained(ctx context.Context, m queue.Message, t delivery.Task) {
+	tracing.AddSpanEvent(ctx, "delivery.drain_aborted")
+	from := pendingStatus(t)
+	if err := h.store.ResetInflight(ctx, t.DeliveryID, from); err != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("drain status reset failed")
+	} else {
+		h.feed.Publish(changefeed.FromTask(t, "inflight", from))
+	}
+	h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).Warn("Send aborted by drain deadline, requeueing")
+	metrics.RecordDispatchHeld("draining")
+	h.drain.handedBack()
+	m.RequeueWithoutBackoff(0)
+}
+
+// failTerminal marks the delivery failed without a retry, for tasks that can never be sent. It
+// leaves its ordering partition so later events aren't held behind it.
+func (h *deliveryHandler) failTerminal(ctx context.Context, m queue.Message, t delivery.Task, from, lastError string) {
+	_ = h.store.FailTerminal(ctx, t.DeliveryID, lastError)
+	change := changefeed.FromTask(t, from, "failed")
+	change.Attempt, change.Error = t.Attempt+1, lastError
+	h.feed.Publish(change)
+	metrics.RecordDelivery("failed", t.TenantID, t.EndpointID, 0)
+	metrics.RecordDeliveryOutcome(t.TenantID, t.EndpointID, false, 0)
+	m.Finish()
+}
+
+// loadPayload fetches a payload the task carries by reference
+func (h *deliveryHandler) loadPayload(ctx context.Context, ref string) (json.RawMessage, error) {
+	if h.blobs == nil {
+		return nil, fmt.Errorf("task references payload %s but no blob store is configured", ref)
+	}
+	b, err := h.blobs.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	// The postgres store hands back jsonb text, which is not the form ingest published
+	payload, err := delivery.CanonicalPayload(b)
+	if err != nil {
+		return nil, fmt.Errorf("payload %s: %w", ref, err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,743 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/austindbirch/harbor_hook/internal/blobstore"
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/logging"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+	"github.com/austindbirch/harbor_hook/internal/store"
+)
+
+// discardPublisher accepts every message without sending it anywhere
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(string, []byte) error { return nil }
+func (discardPublisher) PublishBatch(_ string, bodies [][]byte) []error {
+	return make([]error, len(bodies))
+}
+func (discardPublisher) DeferredPublish(string, time.Duration, []byte) error { return nil }
+func (discardPublisher) Stop()                                               {}
+
+// statusStore fakes the worker's repositories, recording the status writes it is asked for
+type statusStore struct {
+	store.Store // calls the test doesn't expect panic
+	writes      []string
+}
+
+func (s *statusStore) FailTerminal(_ context.Context, id, lastErr string) error {
+	s.writes = append(s.writes, "failed "+id+": "+lastErr)
+	return nil
+}
+
+func (s *statusStore) ResetInflight(_ context.Context, id, to string) error {
+	s.writes = append(s.writes, to+" "+id)
+	return nil
+}
+
+// benchMessage is a queue.Message that only records whether it was answered
+type benchMessage struct {
+	body      []byte
+	responded bool
+}
+
+func (m *benchMessage) Body() []byte                        { return m.body }
+func (m *benchMessage) Finish()                             { m.responded = true }
+func (m *benchMessage) Requeue(time.Duration)               { m.responded = true }
+func (m *benchMessage) RequeueWithoutBackoff(time.Duration) { m.responded = true }
+func (m *benchMessage) HasResponded() bool                  { return m.responded }
+
+// handlerPool answers the statements a delivery runs: dispatch running, no recovery ramp,
+// no freeze, an endpoint with a secret and the default retry policy, and a first attempt
+func handlerPool() *dbfake.Pool {
+	return &dbfake.Pool{
+		QueryRowFunc: func(sql string, _ []any) pgx.Row {
+			switch {
+			case strings.Contains(sql, "FROM harborhook.dispatch_control"):
+				return dbfake.Row{Values: []any{false, "", nil, 0, 0}}
+			case strings.Contains(sql, "recovery_ramp_percents"):
+				return dbfake.Row{Values: []any{nil, nil, 0, false}}
+			case strings.Contains(sql, "SELECT e.secret"):
+				return dbfake.Row{Values: []any{"whsec_bench", false, 0, 0, nil, nil, true, "", "", "", "none", "v1", nil, 0, "http", "", false}}
+			case strings.Contains(sql, "SELECT attempt"):
+				return dbfake.Row{Values: []any{1}}
+			default: // no freeze covers the delivery
+				return dbfake.Row{Err: pgx.ErrNoRows}
+			}
+		},
+	}
+}
+
+func TestHandle_BlockedDestination(t *testing.T) {
+	var sent atomic.Int64
+	sink := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { sent.Add(1) }))
+	defer sink.Close()
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.created",
+		Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+	})
+
+	var deadLettered bool
+	pool := handlerPool()
+	pool.ExecFunc = func(sql string, _ []any) (pgconn.CommandTag, error) {
+		if strings.Contains(sql, "INSERT INTO harborhook.dlq") {
+			deadLettered = true
+		}
+		return pgconn.CommandTag{}, nil
+	}
+	var guard *netguard.Guard // no allowlist: the loopback sink is off limits
+	h := &deliveryHandler{
+		cfg:     config.FromEnv(),
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  &http.Client{Transport: guard.Transport()},
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	h.handle(&benchMessage{body: body})
+	if n := sent.Load(); n != 0 {
+		t.Errorf("sink received %d requests, want the connection refused", n)
+	}
+	if !deadLettered {
+		t.Error("blocked delivery was not dead-lettered on its first attempt")
+	}
+}
+
+func TestHandle_DisabledEndpoint(t *testing.T) {
+	var sent atomic.Int64
+	sink := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { sent.Add(1) }))
+	defer sink.Close()
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.created",
+		Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+	})
+
+	var failedWith string
+	pool := handlerPool()
+	answer := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "SELECT e.secret") {
+			return dbfake.Row{Values: []any{"whsec_1", false, 0, 0, nil, nil, true, "", "", "", "none", "v1", nil, 0, "http", "", true}}
+		}
+		return answer(sql, args)
+	}
+	pool.ExecFunc = func(sql string, args []any) (pgconn.CommandTag, error) {
+		if strings.Contains(sql, "SET status='failed'") {
+			failedWith, _ = args[1].(string)
+		}
+		return pgconn.CommandTag{}, nil
+	}
+	h := &deliveryHandler{
+		cfg:     config.FromEnv(),
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	h.handle(&benchMessage{body: body})
+	if n := sent.Load(); n != 0 {
+		t.Errorf("sink received %d requests for a disabled endpoint, want none", n)
+	}
+	if failedWith != "endpoint_disabled" {
+		t.Errorf("delivery failed with %q, want endpoint_disabled", failedWith)
+	}
+}
+
+// retryRecorder counts the retries a handler schedules
+type retryRecorder struct {
+	discardPublisher
+	deferred int
+}
+
+func (p *retryRecorder) DeferredPublish(string, time.Duration, []byte) error {
+	p.deferred++
+	return nil
+}
+
+func TestHandle_Expired(t *testing.T) {
+	var sent atomic.Int64
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		sent.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer sink.Close()
+
+	var dlqReasons []any
+	var attempted bool
+	pool := handlerPool()
+	pool.ExecFunc = func(sql string, args []any) (pgconn.CommandTag, error) {
+		switch {
+		case strings.Contains(sql, "INSERT INTO harborhook.dlq"):
+			dlqReasons = append(dlqReasons, args[1])
+		case strings.Contains(sql, "attempt=attempt+1"):
+			attempted = true
+		}
+		return pgconn.CommandTag{}, nil
+	}
+	retries := &retryRecorder{}
+	cfg := config.FromEnv()
+	cfg.Worker.BackoffSchedule = []time.Duration{time.Minute}
+	cfg.Worker.JitterPercent = 0
+	h := &deliveryHandler{
+		cfg:     cfg,
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: retries,
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+	task := func(deadline time.Time) []byte {
+		t := delivery.Task{
+			DeliveryID:  "del_1",
+			TenantID:    "tn_1",
+			EndpointID:  "ep_1",
+			EndpointURL: sink.URL,
+			EventType:   "order.created",
+			Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+		}
+		t.SetDeadline(deadline)
+		body, _ := json.Marshal(t)
+		return body
+	}
+
+	// Already past its deadline: dead-lettered without an attempt
+	h.handle(&benchMessage{body: task(time.Now().Add(-time.Second))})
+	if sent.Load() != 0 || attempted || len(dlqReasons) != 1 || dlqReasons[0] != "expired" {
+		t.Errorf("sent %d, attempted %v, dlq reasons %v, want nothing sent and an expired dead letter", sent.Load(), attempted, dlqReasons)
+	}
+
+	// Fails with the next retry a minute away but only ten seconds left: dead-lettered after the attempt
+	dlqReasons = nil
+	h.handle(&benchMessage{body: task(time.Now().Add(10 * time.Second))})
+	if sent.Load() != 1 || retries.deferred != 0 || len(dlqReasons) != 1 ||
+		!strings.HasPrefix(dlqReasons[0].(string), "expired, last status=503") {
+		t.Errorf("sent %d, retries %d, dlq reasons %v, want one attempt then an expired dead letter", sent.Load(), retries.deferred, dlqReasons)
+	}
+
+	// The retry is due before the deadline: retried as usual
+	dlqReasons = nil
+	h.handle(&benchMessage{body: task(time.Now().Add(time.Hour))})
+	if sent.Load() != 2 || retries.deferred != 1 || len(dlqReasons) != 0 {
+		t.Errorf("sent %d, retries %d, dlq reasons %v, want the retry scheduled", sent.Load(), retries.deferred, dlqReasons)
+	}
+}
+
+func TestHandle_GzipBody(t *testing.T) {
+	cfg := config.FromEnv()
+	var encoding string
+	var signed bool
+	sink := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		encoding = r.Header.Get("Content-Encoding")
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return
+		}
+		plain, _ := io.ReadAll(zr)
+		ts := r.Header.Get(cfg.NSQ.TimestampHeader)
+		signed = r.Header.Get(cfg.NSQ.SignatureHeader) == delivery.Sign("whsec_1", plain, ts)
+	}))
+	defer sink.Close()
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.created",
+		Payload:     json.RawMessage(`{"notes":"` + strings.Repeat("large payload ", 200) + `"}`),
+	})
+
+	pool := handlerPool()
+	answer := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "SELECT e.secret") {
+			return dbfake.Row{Values: []any{"whsec_1", false, 0, 0, nil, nil, true, "", "", "", "gzip", "v1", nil, 0, "http", "", false}}
+		}
+		return answer(sql, args)
+	}
+	h := &deliveryHandler{
+		cfg:     cfg,
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	h.handle(&benchMessage{body: body})
+	if encoding != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", encoding)
+	}
+	if !signed {
+		t.Error("signature does not cover the decompressed body")
+	}
+}
+
+func TestHandle_EndpointTimeout(t *testing.T) {
+	cfg := config.FromEnv()
+	cfg.Worker.HTTP.Timeout = 10 * time.Second
+	release := make(chan struct{})
+	sink := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { <-release }))
+	defer sink.Close()
+	defer close(release)
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.created",
+		Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+	})
+
+	var lastErr string
+	pool := handlerPool()
+	answer := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "SELECT e.secret") {
+			return dbfake.Row{Values: []any{"whsec_1", false, 0, 0, nil, nil, true, "", "", "", "none", "v1", nil, 100, "http", "", false}}
+		}
+		return answer(sql, args)
+	}
+	pool.ExecFunc = func(sql string, args []any) (pgconn.CommandTag, error) {
+		if strings.Contains(sql, "status='failed'") {
+			lastErr = args[2].(string)
+		}
+		return pgconn.CommandTag{}, nil
+	}
+	h := &deliveryHandler{
+		cfg:     cfg,
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	start := time.Now()
+	h.handle(&benchMessage{body: body})
+	if took := time.Since(start); took > 2*time.Second {
+		t.Errorf("handle() took %v, want the endpoint's 100ms timeout to cut the send short", took)
+	}
+	if !strings.Contains(lastErr, "deadline exceeded") || classifyReason(errors.New(lastErr), 0) != delivery.RetryClassTimeout {
+		t.Errorf("recorded error %q, want a timeout", lastErr)
+	}
+}
+
+func TestHandle_CapturesResponseBody(t *testing.T) {
+	cfg := config.FromEnv()
+	cfg.Worker.ResponseBodyLimit = 16
+	sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"error":"missing field customer_id"}`))
+	}))
+	defer sink.Close()
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.created",
+		Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+	})
+
+	var stored []any
+	pool := handlerPool()
+	pool.ExecFunc = func(sql string, args []any) (pgconn.CommandTag, error) {
+		if strings.Contains(sql, "status='failed'") {
+			stored = args
+		}
+		return pgconn.CommandTag{}, nil
+	}
+	h := &deliveryHandler{
+		cfg:     cfg,
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	h.handle(&benchMessage{body: body})
+	if len(stored) != 6 || stored[0] != http.StatusBadRequest || stored[4] != `{"error":"missin` || stored[5] != true {
+		t.Errorf("recorded failure %v, want the 400 with the first 16 bytes of its body, truncated", stored)
+	}
+}
+
+func TestHandle_SignatureV2(t *testing.T) {
+	cfg := config.FromEnv()
+	var header, want string
+	sink := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		header = r.Header.Get(cfg.NSQ.SignatureHeader)
+		want = delivery.SignV2("whsec_1", r.Method, r.URL.RequestURI(), b, r.Header.Get(cfg.NSQ.TimestampHeader))
+	}))
+	defer sink.Close()
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL + "/hooks/in?source=harborhook",
+		EventType:   "order.created",
+		Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+	})
+
+	pool := handlerPool()
+	answer := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "SELECT e.secret") {
+			return dbfake.Row{Values: []any{"whsec_1", false, 0, 0, nil, nil, true, "", "", "", "none", "v2", nil, 0, "http", "", false}}
+		}
+		return answer(sql, args)
+	}
+	h := &deliveryHandler{
+		cfg:     cfg,
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	h.handle(&benchMessage{body: body})
+	if !strings.HasPrefix(header, "v2,") || header != want {
+		t.Errorf("signature = %q, want %q", header, want)
+	}
+}
+
+func TestHandle_SignatureEd25519(t *testing.T) {
+	cfg := config.FromEnv()
+	key := ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+	var header, want string
+	sink := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		header = r.Header.Get(cfg.NSQ.SignatureHeader)
+		want = delivery.SignEd25519(key, r.Method, r.URL.RequestURI(), b, r.Header.Get(cfg.NSQ.TimestampHeader))
+	}))
+	defer sink.Close()
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL + "/hooks/in",
+		EventType:   "order.created",
+		Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+	})
+
+	var (
+		seed      any = key.Seed()
+		lastError any
+	)
+	pool := handlerPool()
+	answer := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "SELECT e.secret") {
+			return dbfake.Row{Values: []any{"whsec_1", false, 0, 0, nil, nil, true, "", "", "", "none", "ed25519", seed, 0, "http", "", false}}
+		}
+		return answer(sql, args)
+	}
+	pool.ExecFunc = func(sql string, args []any) (pgconn.CommandTag, error) {
+		if strings.Contains(sql, "last_error=$2") {
+			lastError = args[1]
+		}
+		return pgconn.CommandTag{}, nil
+	}
+	h := &deliveryHandler{
+		cfg:     cfg,
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	h.handle(&benchMessage{body: body})
+	if !strings.Contains(header, ",alg=Ed25519,") || header != want {
+		t.Errorf("signature = %q, want %q", header, want)
+	}
+
+	// Without the tenant's key nothing can be signed, and retrying won't change that
+	header, seed = "", nil
+	h.handle(&benchMessage{body: body})
+	if header != "" || lastError != "signing_key_missing" {
+		t.Errorf("no key: sent %q with last_error = %v, want nothing sent and signing_key_missing", header, lastError)
+	}
+}
+
+func TestHandle_PayloadRef(t *testing.T) {
+	var received string
+	sink := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received = string(b)
+	}))
+	defer sink.Close()
+
+	task := delivery.Task{
+		DeliveryID:  "del_1",
+		EventID:     "evt_1",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.created",
+		PayloadRef:  "postgres:event/evt_1",
+	}
+	body, _ := json.Marshal(task)
+
+	var lastError any
+	pool := handlerPool()
+	answer := pool.QueryRowFunc
+	stored := true
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "SELECT payload::text") {
+			if !stored {
+				return dbfake.Row{Err: pgx.ErrNoRows}
+			}
+			return dbfake.Row{Values: []any{`{"order_id": "ord_123"}`}}
+		}
+		return answer(sql, args)
+	}
+	pool.ExecFunc = func(sql string, args []any) (pgconn.CommandTag, error) {
+		if strings.Contains(sql, "last_error=$2") {
+			lastError = args[1]
+		}
+		return pgconn.CommandTag{}, nil
+	}
+	h := &deliveryHandler{
+		cfg:     config.FromEnv(),
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		blobs:   blobstore.NewPostgres(pool),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	h.handle(&benchMessage{body: body})
+	if received != `{"order_id":"ord_123"}` {
+		t.Errorf("receiver got %q, want the payload from the blob store in canonical form", received)
+	}
+
+	received, stored = "", false
+	h.handle(&benchMessage{body: body})
+	if received != "" || lastError != "payload_missing" {
+		t.Errorf("missing payload: receiver got %q and last_error = %v, want nothing sent and payload_missing", received, lastError)
+	}
+}
+
+// heldMessage records how long a task was held for
+type heldMessage struct {
+	benchMessage
+	held time.Duration
+}
+
+func (m *heldMessage) RequeueWithoutBackoff(d time.Duration) {
+	m.held = d
+	m.responded = true
+}
+
+func TestHandle_Ordered(t *testing.T) {
+	var sent atomic.Int64
+	sink := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) { sent.Add(1) }))
+	defer sink.Close()
+
+	body, _ := json.Marshal(delivery.Task{
+		DeliveryID:  "del_2",
+		TenantID:    "tn_1",
+		EndpointID:  "ep_1",
+		EndpointURL: sink.URL,
+		EventType:   "order.updated",
+		Payload:     json.RawMessage(`{"order_id":"ord_123"}`),
+		Ordered:     true,
+	})
+
+	var earlier []any // the blocking count and next retry of earlier deliveries in the partition
+	pool := handlerPool()
+	answer := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "ordering_key") {
+			return dbfake.Row{Values: earlier}
+		}
+		return answer(sql, args)
+	}
+	h := &deliveryHandler{
+		cfg:     config.FromEnv(),
+		pool:    pool,
+		store:   store.New(pool),
+		feed:    changefeed.New(discardPublisher{}, "changefeed"),
+		retries: discardPublisher{},
+		client:  sink.Client(),
+		gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+		ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+		logger:  logging.New("harborhook-worker"),
+	}
+
+	earlier = []any{1, time.Now().Add(time.Minute)} // retrying in a minute
+	m := &heldMessage{benchMessage: benchMessage{body: body}}
+	h.handle(m)
+	if sent.Load() != 0 || m.held < 59*time.Second {
+		t.Errorf("sent %d and held for %v, want nothing sent until the earlier retry is due", sent.Load(), m.held)
+	}
+
+	earlier = []any{1, nil} // queued or being sent
+	m = &heldMessage{benchMessage: benchMessage{body: body}}
+	h.handle(m)
+	if sent.Load() != 0 || m.held < orderedHoldDelay || m.held > 2*orderedHoldDelay {
+		t.Errorf("sent %d and held for %v, want a short hold", sent.Load(), m.held)
+	}
+
+	earlier = []any{0, nil}
+	h.handle(&heldMessage{benchMessage: benchMessage{body: body}})
+	if sent.Load() != 1 {
+		t.Errorf("sent %d requests once the partition was clear, want 1", sent.Load())
+	}
+}
+
+func TestDeliveryHandler_StatusWrites(t *testing.T) {
+	fake := &statusStore{}
+	drain := &drainer{}
+	h := &deliveryHandler{store: fake, drain: drain, logger: logging.New("harborhook-worker")}
+	task := delivery.Task{DeliveryID: "del_1", TenantID: "tn_1", EndpointID: "ep_1", Attempt: 2}
+
+	m := &benchMessage{}
+	h.failTerminal(context.Background(), m, task, "failed", "payload_missing")
+	if !m.responded {
+		t.Error("failTerminal() didn't finish the message")
+	}
+
+	held := &heldMessage{held: -1}
+	h.requeueDrained(context.Background(), held, task)
+	if held.held != 0 || drain.requeued.Load() != 1 {
+		t.Errorf("requeueDrained() held the task %v with %d requeued, want at once and 1", held.held, drain.requeued.Load())
+	}
+
+	want := []string{"failed del_1: payload_missing", "failed del_1"}
+	if strings.Join(fake.writes, "|") != strings.Join(want, "|") {
+		t.Errorf("status writes = %q, want %q", fake.writes, want)
+	}
+}
+
+func BenchmarkHandleDelivery(b *testing.B) {
+	for _, tc := range []struct {
+		name   string
+		status int
+	}{
+		{"delivered", http.StatusOK},
+		{"retried", http.StatusServiceUnavailable},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			var sent atomic.Int64
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				sent.Add(1)
+				w.WriteHeader(tc.status)
+			}))
+			defer sink.Close()
+
+			body, err := json.Marshal(delivery.Task{
+				DeliveryID:  "del_bench",
+				EventID:     "evt_bench",
+				TenantID:    "tn_bench",
+				EndpointID:  "ep_bench",
+				EndpointURL: sink.URL,
+				EventType:   "order.created",
+				Payload:     json.RawMessage(`{"amount":4999,"order_id":"ord_123"}`),
+			})
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			pool := handlerPool()
+			h := &deliveryHandler{
+				cfg:     config.FromEnv(),
+				pool:    pool,
+				store:   store.New(pool),
+				feed:    changefeed.New(discardPublisher{}, "changefeed"),
+				retries: discardPublisher{},
+				client:  sink.Client(),
+				gate:    &dispatchGate{pool: pool, ttl: dispatchStateTTL},
+				ramps:   &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}},
+				logger:  logging.New("harborhook-worker"),
+			}
+
+			// Retries log every requeue; keep them out of the benchmark output
+			defer logging.SetHandler(logging.Handler())
+			logging.SetHandler(slog.NewJSONHandler(io.Discard, nil))
+
+			b.ReportAllocs()
+			var handled int64
+			for b.Loop() {
+				m := &benchMessage{body: body}
+				h.handle(m)
+				if !m.responded {
+					b.Fatal("message was not answered")
+				}
+				handled++
+			}
+			if got := sent.Load(); got != handled {
+				b.Fatalf("sink received %d requests for %d messages", got, handled)
+			}
+		})
+	}
+}
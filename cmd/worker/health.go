@@ -0,0 +1,235 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/logging"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/store"
+)
+
+const (
+	// healthCheckTick is how often a worker looks for endpoints due a check
+	healthCheckTick = 5 * time.Second
+	// healthCheckBatch is how many due endpoints a worker claims at a time, and
+	// healthCheckConcurrency how many of them it checks at once
+	healthCheckBatch       = 100
+	healthCheckConcurrency = 16
+)
+
+// dueEndpoint is an endpoint claimed for a health check, with its health before it
+type dueEndpoint struct {
+	id, tenantID, url, method string
+	health                    delivery.EndpointHealth
+}
+
+// healthProber sends the health checks endpoints opt into and records what they find. Due
+// endpoints are claimed with SKIP LOCKED, so each check is sent by one worker.
+type healthProber struct {
+	pool       db.Pool
+	endpoints  store.EndpointStore
+	transports *clientTransports
+	cfg        config.HealthCheck
+	nsq        config.NSQ
+	logger     *logging.Logger
+}
+
+// startHealthProber checks due endpoints every healthCheckTick
+func startHealthProber(p *healthProber) {
+	if p.cfg.Every <= 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(healthCheckTick)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			n, err := p.run(context.Background())
+			if err != nil {
+				p.logger.Plain().WithError(err).Error("Failed to run endpoint health checks")
+				continue
+			}
+			if n > 0 {
+				p.logger.Plain().WithField("checked", n).Debug("Checked endpoint health")
+			}
+		}
+	}()
+}
+
+// run claims the endpoints due a check and checks them, returning how many it checked
+func (p *healthProber) run(ctx context.Context) (int, error) {
+	due, err := p.claim(ctx)
+	if err != nil {
+		return 0, err
+	}
+	sem := make(chan struct{}, healthCheckConcurrency)
+	var wg sync.WaitGroup
+	for _, ep := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			if err := p.check(ctx, ep); err != nil {
+				p.logger.Plain().WithEndpoint(ep.id).WithError(err).Error("Failed to record endpoint health")
+			}
+		}()
+	}
+	wg.Wait()
+	return len(due), nil
+}
+
+// claim moves the next check of due endpoints a full interval out and returns them
+func (p *healthProber) claim(ctx context.Context) ([]dueEndpoint, error) {
+	rows, err := p.pool.Query(ctx, `
+		WITH due AS (
+			SELECT e.id
+			FROM harborhook.endpoints e
+			LEFT JOIN harborhook.endpoint_health h ON h.endpoint_id = e.id
+			WHERE e.health_check_enabled AND e.endpoint_type = 'http'
+			  AND COALESCE(h.next_check_at, '-infinity') <= now()
+			ORDER BY h.next_check_at NULLS FIRST
+			LIMIT $2
+			FOR UPDATE OF e SKIP LOCKED
+		), claimed AS (
+			INSERT INTO harborhook.endpoint_health(endpoint_id, next_check_at)
+			SELECT id, now() + make_interval(secs => $1) FROM due
+			ON CONFLICT (endpoint_id) DO UPDATE SET next_check_at = EXCLUDED.next_check_at
+			RETURNING endpoint_id, status, consecutive_failures, consecutive_successes
+		)
+		SELECT e.id::text, e.tenant_id, e.url, e.health_check_method, c.status, c.consecutive_failures, c.consecutive_successes
+		FROM claimed c
+		JOIN harborhook.endpoints e ON e.id = c.endpoint_id`,
+		p.cfg.Every.Seconds(), healthCheckBatch)
+	if err != nil {
+		return nil, fmt.Errorf("claim health checks: %w", err)
+	}
+	defer rows.Close()
+
+	var due []dueEndpoint
+	for rows.Next() {
+		var ep dueEndpoint
+		if err := rows.Scan(&ep.id, &ep.tenantID, &ep.url, &ep.method, &ep.health.Status,
+			&ep.health.ConsecutiveFailures, &ep.health.ConsecutiveSuccesses); err != nil {
+			return nil, err
+		}
+		due = append(due, ep)
+	}
+	return due, rows.Err()
+}
+
+// check sends ep its health check and records the outcome
+func (p *healthProber) check(ctx context.Context, ep dueEndpoint) error {
+	// A check the worker can't make says nothing about the endpoint, so it isn't recorded
+	cfg, err := p.endpoints.EndpointConfig(ctx, ep.id)
+	if err != nil {
+		return fmt.Errorf("endpoint config: %w", err)
+	}
+	var key ed25519.PrivateKey
+	if cfg.SignatureScheme == delivery.SignatureEd25519 {
+		if len(cfg.SigningSeed) != ed25519.SeedSize {
+			return errors.New("no signing key for tenant")
+		}
+		key = ed25519.NewKeyFromSeed(cfg.SigningSeed)
+	}
+	client, err := p.transports.client(clientCert{certPEM: cfg.ClientCertPEM, keyPEM: cfg.ClientKeyPEM, secretName: cfg.ClientCertSecret}, cfg.ProxyURL)
+	if err != nil {
+		return err
+	}
+
+	status, latency, err := p.send(ctx, ep, cfg, key, client)
+	passed := delivery.HealthCheckPassed(status, err)
+	health, changed := ep.health.Observe(passed, delivery.HealthThresholds{Failures: p.cfg.Failures, Successes: p.cfg.Successes})
+	metrics.RecordHealthCheck(ep.tenantID, ep.id, passed, health.Status)
+
+	_, dbErr := p.pool.Exec(ctx, `
+		UPDATE harborhook.endpoint_health
+		SET status = $2, consecutive_failures = $3, consecutive_successes = $4, last_checked_at = now(),
+		    last_status_code = NULLIF($5, 0), last_error = NULLIF($6, ''), last_latency_ms = $7,
+		    changed_at = CASE WHEN status <> $2 THEN now() ELSE changed_at END
+		WHERE endpoint_id = $1`,
+		ep.id, health.Status, health.ConsecutiveFailures, health.ConsecutiveSuccesses,
+		status, errString(err), latency.Milliseconds())
+	if dbErr != nil {
+		return dbErr
+	}
+	if !changed || (health.Status != delivery.HealthUnhealthy && ep.health.Status != delivery.HealthUnhealthy) {
+		return nil
+	}
+
+	// Into or out of unhealthy: tell the tenant, and start the recovery ramp on the way out
+	p.logger.Plain().WithTenant(ep.tenantID).WithEndpoint(ep.id).WithFields(map[string]any{
+		"status":      health.Status,
+		"status_code": status,
+	}).WithError(err).Warn("Endpoint health changed")
+	if health.Status == delivery.HealthHealthy {
+		if _, err := p.pool.Exec(ctx, `UPDATE harborhook.endpoints SET recovered_at = now() WHERE id = $1`, ep.id); err != nil {
+			return err
+		}
+	}
+	return p.recordChange(ctx, ep, health.Status, status, err)
+}
+
+// send makes ep's health check: a signed request with no body, under the check timeout, that
+// isn't redirected
+func (p *healthProber) send(ctx context.Context, ep dueEndpoint, cfg store.EndpointConfig, key ed25519.PrivateKey, client *http.Client) (int, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, delivery.HealthCheckTimeout(cfg.Timeout, p.cfg.Timeout))
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, ep.method, ep.url, nil)
+	if err != nil {
+		return 0, 0, err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(p.nsq.TimestampHeader, ts)
+	req.Header.Set(p.nsq.SignatureHeader, delivery.SignRequest(cfg.SignatureScheme, cfg.Secret, key, req.Method, req.URL.RequestURI(), nil, ts))
+	req.Header.Set(delivery.HealthCheckHeader, "true")
+
+	noRedirects := *client
+	noRedirects.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	start := time.Now()
+	resp, err := noRedirects.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return 0, latency, err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	resp.Body.Close()
+	return resp.StatusCode, latency, nil
+}
+
+// recordChange stores a system event for ep becoming unhealthy or recovering
+func (p *healthProber) recordChange(ctx context.Context, ep dueEndpoint, healthStatus string, status int, checkErr error) error {
+	details, err := json.Marshal(map[string]any{
+		"status":      healthStatus,
+		"previous":    ep.health.Status,
+		"status_code": status,
+		"error":       errString(checkErr),
+	})
+	if err != nil {
+		return err
+	}
+	message := "Endpoint recovered: health checks are passing again"
+	if healthStatus == delivery.HealthUnhealthy {
+		message = fmt.Sprintf("Endpoint is unhealthy after %d failed health checks; deliveries are held", p.cfg.Failures)
+	}
+	if _, err := p.pool.Exec(ctx, `
+		INSERT INTO harborhook.system_events(tenant_id, endpoint_id, type, message, details)
+		VALUES ($1, $2, $3, $4, $5)`,
+		ep.tenantID, ep.id, delivery.SystemEventHealthChanged, message, details); err != nil {
+		return fmt.Errorf("record health change: %w", err)
+	}
+	metrics.RecordSystemEvent(delivery.SystemEventHealthChanged)
+	return nil
+}
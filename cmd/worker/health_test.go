@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/logging"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+	"github.com/austindbirch/harbor_hook/internal/store"
+)
+
+func TestHealthProber(t *testing.T) {
+	tests := []struct {
+		name        string
+		answer      int
+		before      []any // status, consecutive failures, consecutive successes
+		wantStatus  string
+		wantEvent   bool
+		wantRecover bool
+	}{
+		{"refused method passes", http.StatusMethodNotAllowed, []any{"unknown", 0, 0}, delivery.HealthHealthy, false, false},
+		{"third failure opens", http.StatusServiceUnavailable, []any{"healthy", 2, 0}, delivery.HealthUnhealthy, true, false},
+		{"failure below threshold", http.StatusServiceUnavailable, []any{"healthy", 0, 3}, delivery.HealthHealthy, false, false},
+		{"second success recovers", http.StatusOK, []any{"unhealthy", 0, 1}, delivery.HealthHealthy, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			sink := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				w.WriteHeader(tt.answer)
+			}))
+			defer sink.Close()
+
+			var (
+				mu        sync.Mutex
+				updated   []any
+				event     bool
+				recovered bool
+			)
+			pool := &dbfake.Pool{
+				QueryFunc: func(string, []any) (pgx.Rows, error) {
+					return dbfake.NewRows(append([]any{"ep_1", "tn_1", sink.URL + "/hooks?x=1", "HEAD"}, tt.before...)), nil
+				},
+				QueryRowFunc: func(string, []any) pgx.Row {
+					return dbfake.Row{Values: []any{"whsec_1", false, 0, 0, nil, nil, true, "", "", "", "none", "v2", nil, 0, "http", "", false}}
+				},
+				ExecFunc: func(sql string, args []any) (pgconn.CommandTag, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					switch {
+					case strings.Contains(sql, "UPDATE harborhook.endpoint_health"):
+						updated = args
+					case strings.Contains(sql, "INSERT INTO harborhook.system_events"):
+						event = args[2] == delivery.SystemEventHealthChanged
+					case strings.Contains(sql, "recovered_at = now()"):
+						recovered = true
+					}
+					return pgconn.NewCommandTag("UPDATE 1"), nil
+				},
+			}
+			egress, _ := netguard.New([]string{"127.0.0.1"})
+			client := &http.Client{Transport: egress.Transport()}
+			cfg := config.FromEnv()
+			p := &healthProber{
+				pool:       pool,
+				endpoints:  store.New(pool),
+				transports: newClientTransports(client, t.TempDir(), egress),
+				cfg:        config.HealthCheck{Every: time.Minute, Timeout: time.Second, Failures: 3, Successes: 2},
+				nsq:        cfg.NSQ,
+				logger:     logging.New("harborhook-worker-health"),
+			}
+
+			if n, err := p.run(context.Background()); err != nil || n != 1 {
+				t.Fatalf("run() = %d, %v, want 1 check", n, err)
+			}
+			if got == nil {
+				t.Fatal("receiver wasn't checked")
+			}
+			if got.Method != http.MethodHead || got.Header.Get(delivery.HealthCheckHeader) != "true" {
+				t.Errorf("check was %s with %s=%q, want a marked HEAD", got.Method, delivery.HealthCheckHeader, got.Header.Get(delivery.HealthCheckHeader))
+			}
+			ts := got.Header.Get(cfg.NSQ.TimestampHeader)
+			if want := delivery.SignV2("whsec_1", http.MethodHead, "/hooks?x=1", nil, ts); got.Header.Get(cfg.NSQ.SignatureHeader) != want {
+				t.Errorf("signature = %q, want %q", got.Header.Get(cfg.NSQ.SignatureHeader), want)
+			}
+			if updated == nil || updated[1] != tt.wantStatus || updated[4] != tt.answer {
+				t.Errorf("recorded %v, want status %s and code %d", updated, tt.wantStatus, tt.answer)
+			}
+			if event != tt.wantEvent || recovered != tt.wantRecover {
+				t.Errorf("system event %v, recovery ramp %v, want %v, %v", event, recovered, tt.wantEvent, tt.wantRecover)
+			}
+		})
+	}
+}
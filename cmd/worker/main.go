@@ -1,35 +1,55 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"crypto/hmac"
-	"crypto/sha256"
 	"database/sql"
-	"encoding/hex"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"math/rand"
 	"net/http"
 	"os"
 	"os/signal"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/nsqio/go-nsq"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/austindbirch/harbor_hook/internal/archive"
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/blobstore"
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/compliance"
 	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db"
 	"github.com/austindbirch/harbor_hook/internal/delivery"
 	"github.com/austindbirch/harbor_hook/internal/logging"
 	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+	"github.com/austindbirch/harbor_hook/internal/queue"
+	"github.com/austindbirch/harbor_hook/internal/store"
 	"github.com/austindbirch/harbor_hook/internal/tracing"
+	"github.com/austindbirch/harbor_hook/internal/version"
+)
+
+const (
+	dispatchStateTTL = time.Second            // how stale the kill switch may be when a task is dequeued
+	endpointRampTTL  = 5 * time.Second        // how stale an endpoint's recovery ramp may be
+	pausedHoldDelay  = 5 * time.Second        // requeue delay for tasks held while paused
+	rampHoldDelay    = time.Second            // requeue delay for tasks held back during ramp-up
+	orderedHoldDelay = 250 * time.Millisecond // requeue delay for ordered tasks waiting on an earlier delivery that is being sent
+
+	backlogEstimateEvery  = 30 * time.Second // how often tenant backlog ETAs are refreshed
+	backlogEstimateWindow = 5 * time.Minute  // how far back throughput is measured for ETAs
+
+	dlqPurgeBatch = 1000 // expired DLQ entries deleted per statement
 
-	"go.opentelemetry.io/otel/attribute"
+	minRetryDelay = 100 * time.Millisecond // floor for retries, so 0s or negative BACKOFF_SCHEDULE steps can't hot-loop
 )
 
 func main() {
@@ -39,14 +59,30 @@ func main() {
 
 	// Initialize structured logging
 	logger := logging.New("harborhook-worker")
+	if err := logging.Configure(logging.Options{
+		Level:      cfg.Log.Level,
+		Format:     cfg.Log.Format,
+		File:       cfg.Log.File,
+		MaxSizeMB:  cfg.Log.MaxSizeMB,
+		MaxBackups: cfg.Log.MaxBackups,
+		RedactKeys: cfg.Log.RedactKeys,
+	}); err != nil {
+		logger.Plain().WithError(err).Fatal("invalid logging configuration")
+	}
 
-	// Debug: Log the NSQ configuration
+	// Debug: Log the queue configuration
 	logger.Plain().WithFields(map[string]any{
-		"nsqd_tcp_addr":    cfg.NSQ.NsqdTCPAddr,
-		"lookup_http_addr": cfg.NSQ.LookupHTTPAddr,
-		"deliveries_topic": cfg.NSQ.DeliveriesTopic,
-		"worker_channel":   cfg.NSQ.WorkerChannel,
-	}).Info("NSQ configuration loaded")
+		"queue_backend":        cfg.Queue.Backend,
+		"nsqd_tcp_addr":        cfg.NSQ.NsqdTCPAddr,
+		"nsqd_direct":          cfg.NSQ.ConsumeNsqdDirect,
+		"lookup_http_addr":     cfg.NSQ.LookupHTTPAddr,
+		"lookup_http_addrs":    cfg.NSQ.LookupHTTPAddrs,
+		"lookup_poll_interval": cfg.NSQ.LookupPollInterval.String(),
+		"deliveries_topic":     cfg.NSQ.DeliveriesTopic,
+		"priority_topics":      cfg.NSQ.PriorityTopics,
+		"max_in_flight":        cfg.NSQ.MaxInFlight,
+		"worker_channel":       cfg.NSQ.WorkerChannel,
+	}).Info("Queue configuration loaded")
 
 	// Initialize OpenTelemetry tracing
 	shutdown, err := tracing.InitTracing(ctx, "harborhook-worker")
@@ -65,14 +101,36 @@ func main() {
 	// Prom metrics
 	reg := prometheus.NewRegistry()
 	metrics.MustRegister(reg)
+	metrics.SetSLILatencyTarget(cfg.Worker.SLILatencyTarget)
+	if err := metrics.SetLabelPolicy(metrics.LabelPolicy{
+		Mode:        cfg.Metrics.LabelMode,
+		Allowlist:   cfg.Metrics.LabelAllowlist,
+		HashBuckets: cfg.Metrics.LabelHashBuckets,
+		TopK:        cfg.Metrics.LabelTopK,
+	}); err != nil {
+		logger.Plain().WithError(err).Fatal("invalid metrics label policy")
+	}
 
-	// HTTP health/metrics
+	// HTTP health/version/metrics
 	mux := http.NewServeMux()
-	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`{"ok":true}`))
-	})
+	drain := &drainer{}
+	mux.HandleFunc("/healthz", drain.healthHandler())
+	mux.HandleFunc("/version", version.HTTPHandler())
 	mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+
+	// The log level can be changed at runtime by admins; without JWT validation there is no one
+	// to authenticate, so the endpoint isn't served
+	jwtValidator, err := auth.NewJWTValidatorFromEnv(ctx, func(err error) {
+		logger.Plain().WithError(err).Warn("JWKS refresh failed, keeping cached keys")
+	})
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("Failed to create JWT validator")
+	}
+	if jwtValidator != nil {
+		mux.Handle("/admin/loglevel", jwtValidator.HTTPMiddleware(auth.RequireRole(auth.RoleAdmin, logging.LevelHandler(logger))))
+	} else {
+		logger.Plain().Warn("JWT_ISSUER not set, /admin/loglevel is disabled")
+	}
 	httpPort := cfg.Worker.HTTPPort
 	httpSrv := &http.Server{Addr: httpPort, Handler: mux}
 	go func() {
@@ -82,257 +140,137 @@ func main() {
 		}
 	}()
 
-	// NSQ consumer
-	conf := nsq.NewConfig()
-	conf.MaxInFlight = 1500
-	consumer, err := nsq.NewConsumer(cfg.NSQ.DeliveriesTopic, cfg.NSQ.WorkerChannel, conf)
+	// Delivery consumers, one per delivery topic, sharing the in-flight limit by priority
+	if cfg.NSQ.MaxInFlight <= 0 {
+		logger.Plain().Fatal("NSQ_MAX_IN_FLIGHT must be positive")
+	}
+	topics := deliveryTopics(cfg.NSQ)
+	consumer, err := newDeliveryConsumer(cfg, topics)
 	if err != nil {
-		logger.Plain().WithError(err).Fatal("nsq consumer creation failed")
+		logger.Plain().WithError(err).Fatal("queue consumer creation failed")
 	}
+	topicNames := make([]string, len(topics))
+	watch := make([]queue.ChannelStats, len(topics))
+	for i, t := range topics {
+		topicNames[i] = t.name
+		watch[i] = queue.ChannelStats{Topic: t.name, Channel: cfg.NSQ.WorkerChannel}
+	}
+
+	// Readiness and liveness, served alongside /healthz once there is a consumer to check
+	probe := &probes{
+		pool:       pool,
+		consumer:   consumer,
+		drain:      drain,
+		topics:     topicNames,
+		channel:    cfg.NSQ.WorkerChannel,
+		stallAfter: cfg.Worker.StallTimeout,
+	}
+	go probe.beat(ctx)
+	mux.HandleFunc("/readyz", probe.readyHandler())
+	mux.HandleFunc("/livez", probe.liveHandler())
+
+	// Retry publisher: retries are republished with their updated envelope, since a requeue
+	// hands back the original message body
+	retryProducer, err := queue.NewPublisher(cfg)
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("queue publisher for retries creation failed")
+	}
+	defer retryProducer.Stop()
 
-	// DLQ producer
-	var dlqProducer *nsq.Producer
+	// DLQ publisher
+	var dlqProducer queue.Publisher
 	if cfg.Worker.PublishDLQ {
-		dlqProducer, err = nsq.NewProducer(cfg.NSQ.NsqdTCPAddr, nsq.NewConfig())
+		dlqProducer, err = queue.NewPublisher(cfg)
 		if err != nil {
-			logger.Plain().WithError(err).Fatal("nsq producer for DLQ creation failed")
+			logger.Plain().WithError(err).Fatal("queue publisher for DLQ creation failed")
 		}
 		defer dlqProducer.Stop()
 	}
 
-	httpClient := &http.Client{Timeout: 15 * time.Second}
-
-	// Start backlog monitoring
-	startBacklogMonitor(cfg)
-
-	consumer.AddHandler(nsq.HandlerFunc(func(m *nsq.Message) error {
-		m.DisableAutoResponse() // we manually requeue or finish
-		defer func() {
-			if !m.HasResponded() {
-				logger.Plain().Warn("message had no response, finishing")
-				m.Finish()
-			}
-		}()
-
-		var t delivery.Task
-		if err := json.Unmarshal(m.Body, &t); err != nil {
-			logger.Plain().WithError(err).Error("bad task payload")
-			metrics.RecordDelivery("failed", "unknown", "unknown", 0)
-			m.Finish() // terminal: don't retry bad payloads
-			return nil
-		}
-
-		// Extract trace context from NSQ message headers and start span
-		ctx := tracing.ExtractTraceFromNSQ(ctx, t.TraceHeaders)
-		ctx, span := tracing.StartSpan(ctx, "worker.delivery",
-			attribute.String("delivery_id", t.DeliveryID),
-			attribute.String("event_id", t.EventID),
-			attribute.String("tenant_id", t.TenantID),
-			attribute.String("endpoint_id", t.EndpointID),
-			attribute.String("endpoint_url", t.EndpointURL),
-			attribute.String("event_type", t.EventType),
-			attribute.Int("attempt", t.Attempt),
-		)
-		defer span.End()
-
-		// Mark dequeued/inflight
-		tracing.AddSpanEvent(ctx, "db.update_delivery_inflight")
-		_, _ = pool.Exec(ctx, `
-			UPDATE harborhook.deliveries
-			SET status='inflight', dequeued_at=now(), updated_at=now()
-			WHERE id=$1`, t.DeliveryID)
-
-		// Fetch endpoint secret for signing
-		tracing.AddSpanEvent(ctx, "db.fetch_endpoint_secret")
-		var secret sql.NullString
-		if err := pool.QueryRow(ctx, `SELECT secret FROM harborhook.endpoints WHERE id=$1`,
-			t.EndpointID).Scan(&secret); err != nil || !secret.Valid || secret.String == "" {
-			tracing.SetSpanError(ctx, err)
-			_, _ = pool.Exec(ctx, `
-				UPDATE harborhook.deliveries 
-				SET status='failed', attempt=attempt+1, failed_at=now(), updated_at=now(), last_error='endpoint_secret_missing' 
-				WHERE id=$1`, t.DeliveryID)
-			logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithEndpoint(t.EndpointID).WithError(err).Error("No secret for endpoint")
-			metrics.RecordDelivery("failed", t.TenantID, t.EndpointID, 0)
-			m.Finish() // terminal: can't sign without secret
-			return nil
-		}
-
-		// Build request (sign: HMAC over body||timestamp)
-		tracing.AddSpanEvent(ctx, "http.sign_request")
-		body, _ := json.Marshal(t.Payload)
-		ts := strconv.FormatInt(time.Now().Unix(), 10)
-		mac := hmac.New(sha256.New, []byte(secret.String))
-		mac.Write(body)
-		mac.Write([]byte(ts))
-		sig := hex.EncodeToString(mac.Sum(nil))
-
-		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, t.EndpointURL, bytes.NewReader(body))
-		req.Header.Set("Content-Type", "application/json")
-		req.Header.Set(cfg.NSQ.TimestampHeader, ts)
-		req.Header.Set(cfg.NSQ.SignatureHeader, "sha256="+sig)
-
-		// Add trace ID to HTTP headers for correlation
-		if traceID := tracing.GetTraceID(ctx); traceID != "" {
-			req.Header.Set("X-Trace-Id", traceID)
-		}
-
-		start := time.Now()
-		// record sent_at
-		tracing.AddSpanEvent(ctx, "db.update_delivery_sent")
-		_, _ = pool.Exec(ctx, `
-			UPDATE harborhook.deliveries
-			SET sent_at=$2, updated_at=now()
-			WHERE id=$1`, t.DeliveryID, start)
-
-		tracing.AddSpanEvent(ctx, "http.send_webhook")
-		resp, doErr := httpClient.Do(req)
-		latency := time.Since(start)
-		status := 0
-		if doErr == nil {
-			status = resp.StatusCode
-			_ = resp.Body.Close()
-		}
-
-		// Add HTTP response attributes to span
-		span.SetAttributes(
-			attribute.Int("http.status_code", status),
-			attribute.Int64("http.latency_ms", latency.Milliseconds()),
-		)
-		if doErr != nil {
-			span.SetAttributes(attribute.String("http.error", doErr.Error()))
-		}
-
-		ok := (doErr == nil && status >= 200 && status < 300)
-		if ok {
-			// success: attempt+=, status=ok
-			tracing.AddSpanEvent(ctx, "delivery.success")
-			_, updErr := pool.Exec(ctx, `
-				UPDATE harborhook.deliveries
-				SET status='delivered', delivered_at=now(), attempt=attempt+1, http_status=$1, latency_ms=$2, updated_at=now(), last_error=NULL
-				WHERE id=$3`,
-				status, int(latency.Milliseconds()), t.DeliveryID,
-			)
-			if updErr != nil {
-				logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(updErr).Error("db update success failed")
-				tracing.SetSpanError(ctx, updErr)
-			}
-			// Record successful delivery with enhanced metrics
-			metrics.RecordDelivery("delivered", t.TenantID, t.EndpointID, latency)
-			metrics.RecordHTTPDelivery(t.TenantID, t.EndpointID, strconv.Itoa(status), latency)
-			m.Finish() // explicit ack
-			return nil
-		}
-
-		// failure: increment attempt and decide requeue vs DLQ
-		tracing.AddSpanEvent(ctx, "delivery.failed")
-		var newAttempt int
-		_, updErr := pool.Exec(ctx, `
-			UPDATE harborhook.deliveries
-			SET status='failed', failed_at=now(), attempt=attempt+1, http_status=$1, latency_ms=$2, updated_at=now(), last_error=$3
-			WHERE id=$4`,
-			status, int(latency.Milliseconds()), errString(doErr), t.DeliveryID,
-		)
-		if updErr != nil {
-			logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(updErr).Error("db update fail failed")
-			tracing.SetSpanError(ctx, updErr)
-		}
-
-		// fetch current attempt
-		if err := pool.QueryRow(ctx, `SELECT attempt FROM harborhook.deliveries WHERE id=$1`, t.DeliveryID).Scan(&newAttempt); err != nil {
-			logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("read attempt failed")
-			tracing.SetSpanError(ctx, err)
-			newAttempt = cfg.Worker.MaxAttempts // be safe -> DLQ
-		}
+	// Changefeed publisher: every status transition is published for downstream consumers
+	feedProducer, err := queue.NewPublisher(cfg)
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("queue publisher for changefeed creation failed")
+	}
+	defer feedProducer.Stop()
+	feed := changefeed.New(feedProducer, cfg.NSQ.ChangefeedTopic)
 
-		// classify reason for metrics and record enhanced metrics
-		reason := classifyReason(doErr, status)
-		span.SetAttributes(attribute.String("failure_reason", reason))
-		metrics.RecordRetry(reason)
-		metrics.RecordDelivery("failed", t.TenantID, t.EndpointID, latency)
-		if status > 0 {
-			metrics.RecordHTTPDelivery(t.TenantID, t.EndpointID, strconv.Itoa(status), latency)
+	// Webhooks may not reach internal networks, checked on every connection's resolved address
+	egress, err := netguard.New(cfg.EgressAllowlist)
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("invalid EGRESS_ALLOWLIST")
+	}
+	// Optionally through an egress proxy, so webhooks leave from its published addresses
+	proxy := netguard.Proxy{URL: cfg.EgressProxy, Username: cfg.EgressProxyUsername, Password: cfg.EgressProxyPassword, FromEnvironment: cfg.EgressProxyFromEnv}
+	if err := egress.SetProxy(proxy); err != nil {
+		logger.Plain().WithError(err).Fatal("invalid EGRESS_PROXY")
+	}
+	// Receiver hosts are resolved through a cache, so a DNS blip doesn't fail every delivery
+	egress.SetResolver(netguard.NewResolver(cfg.Worker.HTTP.DNSCacheTTL, cfg.Worker.HTTP.DNSNegativeTTL, cfg.Worker.HTTP.DNSStaleTTL, metrics.RecordDNSLookup))
+	httpClient := newDeliveryClient(egress, cfg.Worker.HTTP)
+
+	// Compliance recording (tenants opt in; requests are encrypted before they are stored)
+	var recordings *compliance.Cipher
+	if cfg.Compliance.RecordingKey != "" {
+		recordings, err = compliance.NewCipher(cfg.Compliance.RecordingKey)
+		if err != nil {
+			logger.Plain().WithError(err).Fatal("invalid RECORDING_ENCRYPTION_KEY")
 		}
+	}
 
-		if newAttempt >= cfg.Worker.MaxAttempts {
-			// DLQ - Insert into DLQ table first
-			tracing.AddSpanEvent(ctx, "delivery.dlq", attribute.Int("attempt", newAttempt))
-			_, qErr := pool.Exec(ctx, `
-				INSERT INTO harborhook.dlq(delivery_id, reason) VALUES ($1,$2)`,
-				t.DeliveryID, fmt.Sprintf("max attempts reached (%d), last status=%d, err=%s", newAttempt, status, errString(doErr)),
-			)
-			if qErr != nil {
-				logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(qErr).Error("dlq insert failed")
-				tracing.SetSpanError(ctx, qErr)
-			}
-
-			// Update delivery status to dead (this will trigger our automatic dlq_at timestamp)
-			_, updateErr := pool.Exec(ctx, `
-				UPDATE harborhook.deliveries SET status='dead' WHERE id=$1`,
-				t.DeliveryID,
-			)
-			if updateErr != nil {
-				logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(updateErr).Error("dlq status update failed")
-				tracing.SetSpanError(ctx, updateErr)
-			}
-
-			// DLQ (topic publish)
-			if cfg.Worker.PublishDLQ && dlqProducer != nil {
-				env := delivery.NewDeadLetter(t, newAttempt, status, errString(doErr), fmt.Sprintf("max attempts reached (%d)", newAttempt))
-				b, _ := json.Marshal(env)
-				if err := dlqProducer.Publish(cfg.NSQ.DLQTopic, b); err != nil {
-					logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("dlq publish failed")
-					tracing.SetSpanError(ctx, err)
-				} else {
-					logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithField("topic", cfg.NSQ.DLQTopic).Info("dlq published")
-					tracing.AddSpanEvent(ctx, "nsq.published_dlq", attribute.String("topic", cfg.NSQ.DLQTopic))
-				}
-			}
-
-			span.SetAttributes(
-				attribute.String("delivery.final_status", "dead"),
-				attribute.Int("delivery.final_attempt", newAttempt),
-			)
+	// Payloads over the claim-check threshold are fetched back from the blob store ingest put them in
+	blobs, err := blobstore.New(ctx, cfg.ClaimCheck, pool)
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("blob store creation failed")
+	}
 
-			metrics.RecordDLQ(reason)
-			m.Finish() // drop from main topic
-			return nil
+	// Start backlog monitoring
+	inspector, err := queue.NewInspector(cfg, watch...)
+	if err != nil {
+		logger.Plain().WithError(err).Fatal("queue inspector creation failed")
+	}
+	startBacklogMonitor(inspector, probe)
+	startRecordingJanitor(pool, cfg.Compliance.RecordingPurgeEvery)
+	startDLQJanitor(pool, cfg.DLQ)
+	if cfg.Archive.AfterDays > 0 {
+		sink, err := archive.NewSink(ctx, cfg.Archive)
+		if err != nil {
+			logger.Plain().WithError(err).Fatal("archive store creation failed")
 		}
-
-		// compute backoff with jitter and requeue
-		delay := computeDelay(newAttempt, cfg.Worker.BackoffSchedule, cfg.Worker.JitterPercent)
-		tracing.AddSpanEvent(ctx, "delivery.requeue",
-			attribute.Int("attempt", newAttempt),
-			attribute.String("delay", delay.String()),
-		)
-		span.SetAttributes(
-			attribute.String("delivery.final_status", "requeued"),
-			attribute.Int("delivery.next_attempt", newAttempt),
-		)
-		logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithFields(map[string]any{
-			"attempt": newAttempt,
-			"delay":   delay.String(),
-		}).Info("requeue delivery")
-
-		// Update task attempt count before requeuing
-		t.Attempt = newAttempt
-		updatedBody, _ := json.Marshal(t)
-		m.Body = updatedBody
-
-		m.Requeue(delay) // explicit requeue with delay
-		return nil
-	}))
-
-	// Connecting directly to NSQD forces channel creation, instead of the channel being lazily created on first publish
-	if err := consumer.ConnectToNSQD(cfg.NSQ.NsqdTCPAddr); err != nil {
-		logger.Plain().WithError(err).Fatal("connect to nsqd failed")
+		startArchiver(archive.New(pool, sink, time.Duration(cfg.Archive.AfterDays)*24*time.Hour, cfg.Archive.BatchSize), cfg.Archive.Every)
 	}
 
-	// Extract host:port from the HTTP URL for NSQ lookupd connection
-	lookupAddr := strings.TrimPrefix(cfg.NSQ.LookupHTTPAddr, "http://")
-	lookupAddr = strings.TrimPrefix(lookupAddr, "https://")
-	if err := consumer.ConnectToNSQLookupd(lookupAddr); err != nil {
-		logger.Plain().WithError(err).Fatal("connect to lookupd failed")
+	gate := &dispatchGate{pool: pool, ttl: dispatchStateTTL}
+	ramps := &endpointRamps{endpoints: store.New(pool), ttl: endpointRampTTL, entries: map[string]rampEntry{}}
+	startBacklogEstimator(pool, gate)
+
+	h := &deliveryHandler{
+		cfg:        cfg,
+		pool:       pool,
+		store:      store.New(pool),
+		feed:       feed,
+		retries:    retryProducer,
+		dlq:        dlqProducer,
+		client:     httpClient,
+		transports: newClientTransports(httpClient, cfg.Worker.ClientCertDir, egress),
+		recordings: recordings,
+		blobs:      blobs,
+		gate:       gate,
+		ramps:      ramps,
+		budget:     newRetryBudget(cfg.Worker.RetryBudget),
+		drain:      drain,
+		logger:     logger,
+	}
+	startHealthProber(&healthProber{
+		pool:       pool,
+		endpoints:  h.store,
+		transports: h.transports,
+		cfg:        cfg.Worker.HealthCheck,
+		nsq:        cfg.NSQ,
+		logger:     logging.New("harborhook-worker-health"),
+	})
+	if err := consumer.Start(probe.observe(h.handle)); err != nil {
+		logger.Plain().WithError(err).Fatal("queue consumer start failed")
 	}
 
 	logger.Plain().Info("worker service started")
@@ -342,9 +280,16 @@ func main() {
 	signal.Notify(stop, syscall.SIGTERM, syscall.SIGINT)
 	<-stop
 
-	logger.Plain().Info("Shutting down worker service")
-	consumer.Stop()
-	<-consumer.StopChan
+	// Drain: stop taking tasks, give sends in flight until the deadline, then requeue the rest
+	logger.Plain().WithFields(map[string]any{
+		"inflight": drain.inflight.Load(),
+		"timeout":  cfg.Worker.DrainTimeout.String(),
+	}).Info("Shutting down worker service, draining")
+	aborted := drain.drain(consumer.Stop, cfg.Worker.DrainTimeout)
+	logger.Plain().WithFields(map[string]any{
+		"aborted":  aborted,
+		"requeued": drain.requeued.Load(),
+	}).Info("worker drained")
 	_ = httpSrv.Shutdown(context.Background())
 	logger.Plain().Info("worker service stopped")
 }
@@ -357,6 +302,9 @@ func errString(err error) string {
 }
 
 func computeDelay(attempt int, schedule []time.Duration, jitterPct float64) time.Duration {
+	if len(schedule) == 0 {
+		return minRetryDelay
+	}
 	// attempt is 1-based after increment; map to schedule index
 	idx := attempt - 1
 	if idx < 0 {
@@ -371,76 +319,459 @@ func computeDelay(attempt int, schedule []time.Duration, jitterPct float64) time
 	if j < 0.1 {
 		j = 0.1
 	}
-	return time.Duration(float64(base) * j)
+	return max(time.Duration(float64(base)*j), minRetryDelay)
 }
 
 func classifyReason(doErr error, status int) string {
 	if doErr != nil {
 		errLower := strings.ToLower(doErr.Error())
-		if strings.Contains(errLower, "timeout") {
-			return "timeout"
+		if strings.Contains(errLower, "timeout") || strings.Contains(errLower, "deadline exceeded") {
+			return delivery.RetryClassTimeout
 		}
 		if strings.Contains(errLower, "connection refused") {
-			return "connection_refused"
+			return delivery.RetryClassConnectionRefused
 		}
 		if strings.Contains(errLower, "no such host") || strings.Contains(errLower, "dns") {
-			return "dns_error"
+			return delivery.RetryClassDNS
 		}
-		return "network"
+		return delivery.RetryClassNetwork
 	}
 	if status >= 500 {
-		return "http_5xx"
+		return delivery.RetryClass5xx
 	}
 	if status == 429 {
-		return "http_429"
+		return delivery.RetryClass429
 	}
 	if status >= 400 {
-		return "http_4xx"
+		return delivery.RetryClass4xx
 	}
-	return "other"
+	return delivery.RetryClassOther
 }
 
-// startBacklogMonitor starts a goroutine to periodically update worker backlog metrics
-func startBacklogMonitor(cfg config.Config) {
+// setEventHeaders describes the delivery on every request, so receivers can dedupe and
+// correlate without parsing the body: its event, event type, delivery, attempt number and
+// idempotency key
+func setEventHeaders(h http.Header, nsqCfg config.NSQ, t delivery.Task) {
+	h.Set(nsqCfg.EventIDHeader, t.EventID)
+	h.Set(nsqCfg.EventTypeHeader, t.EventType)
+	h.Set(nsqCfg.DeliveryHeader, t.DeliveryID)
+	h.Set(nsqCfg.AttemptHeader, strconv.Itoa(t.Attempt+1))
+	h.Set(nsqCfg.IdempotencyHeader, t.IdempotencyKey())
+}
+
+// setSenderHeaders identifies harborhook as the sender and, unless the tenant opted out,
+// the tenant, which receivers use for routing and allowlisting
+func setSenderHeaders(h http.Header, nsqCfg config.NSQ, t delivery.Task, senderHeaders bool) {
+	h.Set("User-Agent", nsqCfg.UserAgent)
+	if !senderHeaders {
+		return
+	}
+	h.Set(nsqCfg.TenantHeader, t.TenantID)
+}
+
+// recordRequest encrypts and stores a delivery request for a tenant in compliance mode
+func recordRequest(ctx context.Context, pool db.Pool, c *compliance.Cipher, t delivery.Task, retentionDays int, r compliance.Request) error {
+	tracing.AddSpanEvent(ctx, "compliance.record_request")
+	sealed, err := c.Seal(r)
+	if err != nil {
+		return err
+	}
+	if retentionDays <= 0 {
+		retentionDays = compliance.DefaultRetentionDays
+	}
+	_, err = pool.Exec(ctx, `
+		INSERT INTO harborhook.delivery_recordings(delivery_id, tenant_id, attempt, key_id, sealed, expires_at)
+		VALUES ($1, $2, $3, $4, $5, now() + make_interval(days => $6))`,
+		t.DeliveryID, t.TenantID, t.Attempt+1, c.KeyID(), sealed, retentionDays,
+	)
+	return err
+}
+
+// captureRequest stores a request to a capture endpoint in place of sending it, and drops the
+// endpoint's oldest captures beyond delivery.CaptureLimit
+func captureRequest(ctx context.Context, pool db.Pool, t delivery.Task, signature string, r compliance.Request) error {
+	tracing.AddSpanEvent(ctx, "capture.store_request")
+	headers, err := json.Marshal(r.Headers)
+	if err != nil {
+		return err
+	}
+	// The delete doesn't see the row being inserted, so it keeps one fewer of the others
+	_, err = pool.Exec(ctx, `
+		WITH ins AS (
+			INSERT INTO harborhook.captured_deliveries(tenant_id, endpoint_id, delivery_id, event_id, event_type, attempt, headers, body, signature)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		)
+		DELETE FROM harborhook.captured_deliveries
+		WHERE id IN (
+			SELECT id FROM harborhook.captured_deliveries
+			WHERE endpoint_id = $2
+			ORDER BY captured_at DESC
+			OFFSET $10
+		)`,
+		t.TenantID, t.EndpointID, t.DeliveryID, t.EventID, t.EventType, t.Attempt+1, headers, string(r.Body), signature,
+		delivery.CaptureLimit-1,
+	)
+	return err
+}
+
+// dispatchGate caches the kill switch row so each dequeue can check it without a query per message
+type dispatchGate struct {
+	pool db.Pool
+	ttl  time.Duration
+
+	mu       sync.Mutex
+	state    delivery.DispatchState
+	loadedAt time.Time
+}
+
+// current returns the kill switch state, reloading it once the cache is older than ttl.
+// On a read error the last known state is returned with the error.
+func (g *dispatchGate) current(ctx context.Context) (delivery.DispatchState, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if time.Since(g.loadedAt) < g.ttl {
+		return g.state, nil
+	}
+
+	var (
+		st          delivery.DispatchState
+		resumedAt   sql.NullTime
+		rampSeconds int
+	)
+	err := g.pool.QueryRow(ctx, `
+		SELECT paused, COALESCE(reason, ''), resumed_at, ramp_seconds, ramp_start_percent
+		FROM harborhook.dispatch_control`).Scan(&st.Paused, &st.Reason, &resumedAt, &rampSeconds, &st.StartPercent)
+	// Retry on the next ttl rather than every message while the DB is unhappy
+	g.loadedAt = time.Now()
+	if err != nil {
+		return g.state, err
+	}
+	st.ResumedAt = resumedAt.Time
+	st.Ramp = time.Duration(rampSeconds) * time.Second
+	g.state = st
+	metrics.UpdateDispatchAdmitPercent(st.AdmitPercent(time.Now()))
+	return st, nil
+}
+
+type rampEntry struct {
+	ramp     delivery.RecoveryRamp
+	loadedAt time.Time
+}
+
+// endpointRamps caches each endpoint's recovery ramp. Endpoints that never recovered cost one
+// lookup per ttl and always admit.
+type endpointRamps struct {
+	endpoints store.EndpointStore
+	ttl       time.Duration
+
+	mu      sync.Mutex
+	entries map[string]rampEntry
+}
+
+func (c *endpointRamps) get(ctx context.Context, endpointID string) (delivery.RecoveryRamp, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[endpointID]; ok && time.Since(e.loadedAt) < c.ttl {
+		return e.ramp, nil
+	}
+
+	r, err := c.endpoints.RecoveryRamp(ctx, endpointID)
+	if err != nil {
+		return delivery.RecoveryRamp{}, err
+	}
+	c.entries[endpointID] = rampEntry{ramp: r, loadedAt: time.Now()}
+	return r, nil
+}
+
+// holdDelay is how long a held task waits before the worker sees it again, with jitter
+// so held tasks don't come back in one wave
+func holdDelay(paused bool) time.Duration {
+	d := rampHoldDelay
+	if paused {
+		d = pausedHoldDelay
+	}
+	return d + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// parkIfFrozen marks the delivery parked when it was drained or an active freeze covers its
+// tenant or endpoint, and reports whether the task should be dropped along with the status it had
+func parkIfFrozen(ctx context.Context, pool db.Pool, t delivery.Task) (from string, parked bool, err error) {
+	err = pool.QueryRow(ctx, `
+		WITH cur AS (
+			SELECT id, status::text AS status FROM harborhook.deliveries WHERE id = $1 FOR UPDATE
+		)
+		UPDATE harborhook.deliveries d
+		SET status = 'parked'
+		FROM cur, harborhook.endpoints ep
+		WHERE d.id = cur.id AND ep.id = d.endpoint_id
+		  AND (cur.status = 'parked' OR EXISTS (
+			SELECT 1 FROM harborhook.delivery_freezes f
+			WHERE f.released_at IS NULL AND (f.tenant_id = ep.tenant_id OR f.endpoint_id = ep.id)
+		  ))
+		RETURNING cur.status`, t.DeliveryID).Scan(&from)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return from, true, nil
+}
+
+// orderedHold reports whether an ordered delivery has to wait for an earlier event's delivery in
+// its partition that is still pending, and for how long: until that delivery's next retry is
+// due, or briefly while it is queued or being sent
+func orderedHold(ctx context.Context, pool db.Pool, deliveryID string) (time.Duration, bool, error) {
+	var (
+		blocking int
+		nextTry  sql.NullTime
+	)
+	err := pool.QueryRow(ctx, `
+		WITH cur AS (
+			SELECT d.id, d.endpoint_id, d.ordering_key, ev.seq
+			FROM harborhook.deliveries d
+			JOIN harborhook.events ev ON ev.id = d.event_id
+			WHERE d.id = $1
+		)
+		SELECT count(*), max(d.next_try_at)
+		FROM cur
+		JOIN harborhook.deliveries d ON d.endpoint_id = cur.endpoint_id AND d.ordering_key = cur.ordering_key
+		JOIN harborhook.events ev ON ev.id = d.event_id
+		WHERE d.status IN ('queued', 'inflight', 'failed', 'parked')
+		  AND (ev.seq, d.id) < (cur.seq, cur.id)`, deliveryID).Scan(&blocking, &nextTry)
+	if err != nil || blocking == 0 {
+		return 0, false, err
+	}
+	wait := orderedHoldDelay
+	if nextTry.Valid {
+		wait = max(time.Until(nextTry.Time), wait)
+	}
+	return wait + time.Duration(rand.Int63n(int64(orderedHoldDelay))), true, nil
+}
+
+// pendingStatus is the status a task's delivery has while it waits in NSQ
+func pendingStatus(t delivery.Task) string {
+	if t.Attempt == 0 {
+		return "queued"
+	}
+	return "failed"
+}
+
+// startRecordingJanitor periodically deletes compliance recordings past their retention
+func startRecordingJanitor(pool *pgxpool.Pool, every time.Duration) {
+	if every <= 0 {
+		return
+	}
 	go func() {
-		logger := logging.New("harborhook-worker-monitor")
-		ticker := time.NewTicker(15 * time.Second) // Update every 15 seconds
+		logger := logging.New("harborhook-worker-janitor")
+		ticker := time.NewTicker(every)
 		defer ticker.Stop()
 
-		httpClient := &http.Client{Timeout: 5 * time.Second}
-
 		for range ticker.C {
-			// Get NSQ stats from nsqd HTTP endpoint (port 4151)
-			nsqdHTTPAddr := strings.Replace(cfg.NSQ.NsqdTCPAddr, ":4150", ":4151", 1)
-			resp, err := httpClient.Get(fmt.Sprintf("http://%s/stats?format=json", nsqdHTTPAddr))
+			tag, err := pool.Exec(context.Background(), `DELETE FROM harborhook.delivery_recordings WHERE expires_at <= now()`)
 			if err != nil {
-				logger.Plain().WithError(err).Error("Failed to get NSQ stats")
+				logger.Plain().WithError(err).Error("Failed to purge expired recordings")
 				continue
 			}
+			if n := tag.RowsAffected(); n > 0 {
+				logger.Plain().WithField("deleted", n).Info("Purged expired recordings")
+			}
+		}
+	}()
+}
+
+// startDLQJanitor periodically purges DLQ entries past their tenant's retention, or past the
+// cluster default for tenants without one
+func startDLQJanitor(pool *pgxpool.Pool, cfg config.DLQ) {
+	if cfg.PurgeEvery <= 0 {
+		return
+	}
+	defaults := store.DLQRetention{MaxAge: cfg.MaxAge, MaxEntries: cfg.MaxEntries, Archive: cfg.Archive}
+	go func() {
+		logger := logging.New("harborhook-worker-janitor")
+		ticker := time.NewTicker(cfg.PurgeEvery)
+		defer ticker.Stop()
 
-			var stats struct {
-				Topics []struct {
-					Name     string `json:"topic_name"`
-					Channels []struct {
-						Name  string `json:"channel_name"`
-						Depth int64  `json:"depth"`
-					} `json:"channels"`
-				} `json:"topics"`
+		dlq := store.New(pool)
+		for range ticker.C {
+			// Purge in batches so one run doesn't hold a long transaction over a large backlog
+			var total store.DLQPurge
+			for {
+				n, err := dlq.PurgeExpiredDLQ(context.Background(), defaults, dlqPurgeBatch)
+				if err != nil {
+					logger.Plain().WithError(err).Error("Failed to purge expired DLQ entries")
+					break
+				}
+				total.Purged += n.Purged
+				total.Archived += n.Archived
+				if n.Purged < dlqPurgeBatch {
+					break
+				}
+			}
+			if total.Purged > 0 {
+				logger.Plain().WithFields(map[string]any{
+					"purged":   total.Purged,
+					"archived": total.Archived,
+				}).Info("Purged expired DLQ entries")
 			}
+		}
+	}()
+}
 
-			if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
-				resp.Body.Close()
-				logger.Plain().WithError(err).Error("Failed to decode NSQ stats")
-				continue
+// startArchiver periodically moves old events and delivered deliveries to the archive store
+func startArchiver(a *archive.Archiver, every time.Duration) {
+	if every <= 0 {
+		return
+	}
+	go func() {
+		logger := logging.New("harborhook-worker-janitor")
+		ticker := time.NewTicker(every)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			res, err := a.Run(context.Background())
+			if err != nil {
+				logger.Plain().WithError(err).Error("Failed to archive old events and deliveries")
+			}
+			if res.Deliveries > 0 || res.Events > 0 {
+				logger.Plain().WithFields(map[string]any{
+					"deliveries": res.Deliveries,
+					"events":     res.Events,
+				}).Info("Archived old events and deliveries")
 			}
-			resp.Body.Close()
+		}
+	}()
+}
 
-			// Update NSQ topic depth metrics only
-			for _, topic := range stats.Topics {
-				for _, channel := range topic.Channels {
-					metrics.UpdateNSQTopicDepth(topic.Name, channel.Name, float64(channel.Depth))
+// startBacklogEstimator periodically publishes each tenant's backlog size and estimated time to
+// clear, and which tenants and endpoints the backlog belongs to
+func startBacklogEstimator(pool *pgxpool.Pool, gate *dispatchGate) {
+	go func() {
+		logger := logging.New("harborhook-worker-estimator")
+		ticker := time.NewTicker(backlogEstimateEvery)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := updateBacklogEstimates(context.Background(), pool, gate); err != nil {
+				logger.Plain().WithError(err).Error("Failed to estimate backlogs")
+			}
+			if err := updateBacklogAttribution(context.Background(), pool); err != nil {
+				logger.Plain().WithError(err).Error("Failed to attribute backlog")
+			}
+		}
+	}()
+}
+
+func updateBacklogEstimates(ctx context.Context, pool *pgxpool.Pool, gate *dispatchGate) error {
+	st, err := gate.current(ctx)
+	if err != nil {
+		return err
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT ep.tenant_id, ep.rate_per_sec,
+		       count(d.id) FILTER (WHERE d.status IN ('queued', 'inflight', 'failed')),
+		       count(d.id) FILTER (WHERE d.status IN ('delivered', 'dead')),
+		       EXISTS (
+		           SELECT 1 FROM harborhook.delivery_freezes f
+		           WHERE f.released_at IS NULL AND (f.tenant_id = ep.tenant_id OR f.endpoint_id = ep.id)
+		       )
+		FROM harborhook.endpoints ep
+		LEFT JOIN harborhook.deliveries d ON d.endpoint_id = ep.id
+		     AND (d.status IN ('queued', 'inflight', 'failed')
+		          OR (d.status IN ('delivered', 'dead') AND d.updated_at >= now() - make_interval(secs => $1)))
+		GROUP BY ep.id`, backlogEstimateWindow.Seconds())
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	byTenant := map[string][]delivery.Estimate{}
+	for rows.Next() {
+		var (
+			tenantID  string
+			rateLimit int
+			b         = delivery.Backlog{Window: backlogEstimateWindow}
+		)
+		if err := rows.Scan(&tenantID, &rateLimit, &b.Pending, &b.Completed, &b.Frozen); err != nil {
+			return err
+		}
+		b.RateLimit = float64(rateLimit)
+		byTenant[tenantID] = append(byTenant[tenantID], delivery.EstimateBacklog(b, st.Paused))
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	// Drop tenants whose endpoints are gone
+	metrics.BacklogPending.Reset()
+	metrics.BacklogETASeconds.Reset()
+	for tenantID, parts := range byTenant {
+		total := delivery.CombineEstimates(parts)
+		metrics.UpdateBacklogEstimate(tenantID, total.Pending, total.ETA, total.OK)
+	}
+	return nil
+}
+
+// updateBacklogAttribution publishes each endpoint's pending deliveries and the age of its
+// oldest one. Queue depth can't say whose messages are waiting; this can, for alerts that
+// target the tenant or endpoint that is backed up.
+func updateBacklogAttribution(ctx context.Context, pool db.Pool) error {
+	rows, err := pool.Query(ctx, `
+		SELECT ep.tenant_id, ep.id::text, count(*),
+		       EXTRACT(EPOCH FROM now() - min(COALESCE(d.enqueued_at, d.created_at)))::float8
+		FROM harborhook.deliveries d
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		WHERE d.status IN ('queued', 'inflight', 'failed')
+		GROUP BY ep.tenant_id, ep.id`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var backlogs []metrics.EndpointBacklog
+	for rows.Next() {
+		var (
+			b         metrics.EndpointBacklog
+			oldestAge float64
+		)
+		if err := rows.Scan(&b.TenantID, &b.EndpointID, &b.Pending, &oldestAge); err != nil {
+			return err
+		}
+		b.OldestAge = time.Duration(max(oldestAge, 0) * float64(time.Second))
+		backlogs = append(backlogs, b)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	metrics.SetBacklogAttribution(backlogs)
+	return nil
+}
+
+// startBacklogMonitor starts a goroutine to periodically update worker backlog metrics
+func startBacklogMonitor(inspector queue.Inspector, probe *probes) {
+	go func() {
+		logger := logging.New("harborhook-worker-monitor")
+		ticker := time.NewTicker(15 * time.Second) // Update every 15 seconds
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			stats, err := inspector.Stats(ctx)
+			cancel()
+			if err != nil {
+				logger.Plain().WithError(err).Error("Failed to get queue stats")
+				if len(stats) == 0 {
+					continue
 				}
 			}
+
+			// On NSQ there is an entry per nsqd; the backlog is their sum
+			stats = queue.Totals(stats)
+			for _, c := range stats {
+				metrics.UpdateNSQTopicDepth(c.Topic, c.Channel, float64(c.Depth))
+			}
+			probe.sawBacklog(stats)
 		}
 	}()
 }
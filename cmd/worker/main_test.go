@@ -16,12 +16,24 @@ package main
 // - Error classification and failure reason testing
 
 import (
+	"context"
+	"math/rand"
+	"net/http"
 	"os"
+	"reflect"
+	"slices"
 	"strconv"
 	"testing"
+	"testing/quick"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
 	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
 )
 
 func TestWorkerConfig(t *testing.T) {
@@ -64,6 +76,15 @@ func TestWorkerConfig(t *testing.T) {
 				if cfg.Worker.PublishDLQ != false {
 					t.Errorf("Expected PublishDLQ false, got %v", cfg.Worker.PublishDLQ)
 				}
+				if cfg.Worker.MaxDeferral != time.Hour {
+					t.Errorf("Expected MaxDeferral 1h, got %v", cfg.Worker.MaxDeferral)
+				}
+				if cfg.Worker.HTTP.Timeout != 15*time.Second || cfg.Worker.HTTP.MaxIdleConnsPerHost != 32 || !cfg.Worker.HTTP.HTTP2 {
+					t.Errorf("Expected HTTP client 15s, 32 idle per host, HTTP/2, got %+v", cfg.Worker.HTTP)
+				}
+				if cfg.Worker.RetryBudget != 120 || cfg.Worker.RetryBudgetDelay != 10*time.Minute {
+					t.Errorf("Expected retry budget 120/min over budget at 10m, got %d, %v", cfg.Worker.RetryBudget, cfg.Worker.RetryBudgetDelay)
+				}
 				expectedSchedule := []time.Duration{
 					time.Second,
 					4 * time.Second,
@@ -296,6 +317,76 @@ func TestComputeDelay(t *testing.T) {
 	}
 }
 
+// backoffCase is a random retry policy and attempt for the computeDelay properties. Steps are
+// between 1s and 24h, the range ValidateRetryPolicy allows for per-endpoint policies
+type backoffCase struct {
+	Schedule []time.Duration
+	Jitter   float64
+	Attempt  int
+}
+
+func (backoffCase) Generate(r *rand.Rand, size int) reflect.Value {
+	c := backoffCase{
+		Schedule: make([]time.Duration, 1+r.Intn(20)),
+		Jitter:   r.Float64(),
+		Attempt:  r.Intn(30) - 2,
+	}
+	for i := range c.Schedule {
+		c.Schedule[i] = time.Second + time.Duration(r.Int63n(int64(24*time.Hour)))
+	}
+	return reflect.ValueOf(c)
+}
+
+func (c backoffCase) base() time.Duration {
+	idx := min(max(c.Attempt-1, 0), len(c.Schedule)-1)
+	return c.Schedule[idx]
+}
+
+func TestComputeDelay_WithinJitterBounds(t *testing.T) {
+	withinBounds := func(c backoffCase) bool {
+		got := computeDelay(c.Attempt, c.Schedule, c.Jitter)
+		base := float64(c.base())
+		lo := base * max(1-c.Jitter, 0.1)
+		hi := base * (1 + c.Jitter)
+		return float64(got) >= lo-1 && float64(got) <= hi
+	}
+	if err := quick.Check(withinBounds, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestComputeDelay_MonotonicWithoutJitter(t *testing.T) {
+	monotonic := func(c backoffCase) bool {
+		slices.Sort(c.Schedule)
+		prev := time.Duration(0)
+		for attempt := -1; attempt <= len(c.Schedule)+2; attempt++ {
+			d := computeDelay(attempt, c.Schedule, 0)
+			if d < prev {
+				return false
+			}
+			prev = d
+		}
+		return true
+	}
+	if err := quick.Check(monotonic, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestComputeDelay_AlwaysPositive(t *testing.T) {
+	// Unlike endpoint policies, BACKOFF_SCHEDULE isn't range checked, so any step is fair game
+	positive := func(steps []int64, jitter float64, attempt int) bool {
+		schedule := make([]time.Duration, len(steps))
+		for i, s := range steps {
+			schedule[i] = time.Duration(s)
+		}
+		return computeDelay(attempt, schedule, jitter) > 0
+	}
+	if err := quick.Check(positive, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
 func TestClassifyReason(t *testing.T) {
 	// Test with actual error types
 	t.Run("timeout error", func(t *testing.T) {
@@ -438,12 +529,74 @@ func TestNSQConfigDefaults(t *testing.T) {
 	if cfg.NSQ.DLQTopic != "deliveries_dlq" {
 		t.Errorf("Expected DLQTopic 'deliveries_dlq', got %q", cfg.NSQ.DLQTopic)
 	}
+	if cfg.NSQ.ChangefeedTopic != "delivery_changes" {
+		t.Errorf("Expected ChangefeedTopic 'delivery_changes', got %q", cfg.NSQ.ChangefeedTopic)
+	}
 	if cfg.NSQ.SignatureHeader != "X-HarborHook-Signature" {
 		t.Errorf("Expected SignatureHeader 'X-HarborHook-Signature', got %q", cfg.NSQ.SignatureHeader)
 	}
 	if cfg.NSQ.TimestampHeader != "X-HarborHook-Timestamp" {
 		t.Errorf("Expected TimestampHeader 'X-HarborHook-Timestamp', got %q", cfg.NSQ.TimestampHeader)
 	}
+	if cfg.NSQ.UserAgent != "harborhook/dev" {
+		t.Errorf("Expected UserAgent 'harborhook/dev', got %q", cfg.NSQ.UserAgent)
+	}
+}
+
+func TestSetEventHeaders(t *testing.T) {
+	nsqCfg := config.FromEnv().NSQ
+	h := http.Header{}
+	task := delivery.Task{DeliveryID: "del_1", EventID: "evt_1", EndpointID: "ep_1", EventType: "order.created", Attempt: 2}
+	setEventHeaders(h, nsqCfg, task)
+
+	want := map[string]string{
+		"X-HarborHook-Event-Id":    "evt_1",
+		"X-HarborHook-Event-Type":  "order.created",
+		"X-HarborHook-Delivery-Id": "del_1",
+		"X-HarborHook-Attempt":     "3",
+		"X-HarborHook-Idempotency": delivery.DeriveIdempotencyKey("evt_1", "ep_1"),
+	}
+	for name, value := range want {
+		if got := h.Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+
+	task.ReplayKey = "idk_replay"
+	setEventHeaders(h, nsqCfg, task)
+	if got := h.Get("X-HarborHook-Idempotency"); got != "idk_replay" {
+		t.Errorf("X-HarborHook-Idempotency of a replay = %q, want its replay key", got)
+	}
+}
+
+func TestSetSenderHeaders(t *testing.T) {
+	nsqCfg := config.NSQ{
+		TenantHeader: "X-HarborHook-Tenant",
+		UserAgent:    "harborhook/v1.2.3",
+	}
+	task := delivery.Task{TenantID: "tn_123", EventType: "order.created"}
+
+	tests := []struct {
+		name          string
+		senderHeaders bool
+		wantTenant    string
+	}{
+		{name: "identified", senderHeaders: true, wantTenant: "tn_123"},
+		{name: "tenant opted out", senderHeaders: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := http.Header{}
+			setSenderHeaders(h, nsqCfg, task, tt.senderHeaders)
+			if got := h.Get("User-Agent"); got != "harborhook/v1.2.3" {
+				t.Errorf("User-Agent = %q, want %q", got, "harborhook/v1.2.3")
+			}
+			if got := h.Get("X-HarborHook-Tenant"); got != tt.wantTenant {
+				t.Errorf("tenant header = %q, want %q", got, tt.wantTenant)
+			}
+		})
+	}
 }
 
 func TestWorkerConfigStruct(t *testing.T) {
@@ -470,3 +623,48 @@ func TestWorkerConfigStruct(t *testing.T) {
 		t.Errorf("Expected PublishDLQ true, got %v", cfg.Worker.PublishDLQ)
 	}
 }
+
+func TestHoldDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		paused bool
+		base   time.Duration
+	}{
+		{name: "paused", paused: true, base: pausedHoldDelay},
+		{name: "ramp", paused: false, base: rampHoldDelay},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				got := holdDelay(tt.paused)
+				if got < tt.base || got > tt.base+tt.base/2 {
+					t.Fatalf("holdDelay(%v) = %v, want within [%v, %v]", tt.paused, got, tt.base, tt.base+tt.base/2)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateBacklogAttribution(t *testing.T) {
+	pool := &dbfake.Pool{
+		QueryFunc: func(string, []any) (pgx.Rows, error) {
+			return dbfake.NewRows(
+				[]any{"tn_1", "ep_1", int64(40), 90.5},
+				[]any{"tn_1", "ep_2", int64(2), -0.01}, // enqueued a moment after the sample's now()
+			), nil
+		},
+	}
+	if err := updateBacklogAttribution(context.Background(), pool); err != nil {
+		t.Fatalf("updateBacklogAttribution() error: %v", err)
+	}
+	if got := testutil.ToFloat64(metrics.BacklogByTenant.WithLabelValues("tn_1")); got != 42 {
+		t.Errorf("tn_1 backlog = %f, want 42", got)
+	}
+	if got := testutil.ToFloat64(metrics.BacklogOldestAgeByEndpoint.WithLabelValues("tn_1", "ep_1")); got != 90.5 {
+		t.Errorf("ep_1 oldest age = %f, want 90.5", got)
+	}
+	if got := testutil.ToFloat64(metrics.BacklogOldestAgeByEndpoint.WithLabelValues("tn_1", "ep_2")); got != 0 {
+		t.Errorf("ep_2 oldest age = %f, want 0", got)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/queue"
+)
+
+// consumedTopic is a delivery topic the worker consumes and its share of in-flight messages
+type consumedTopic struct {
+	name   string
+	weight int
+}
+
+// deliveryTopics lists the delivery topics the worker consumes. With priority topics enabled
+// that is one per priority, weighted by NSQ_PRIORITY_WEIGHTS (1 when a priority has none), and
+// the shared topic at the low weight, which only drains tasks queued before the switch; their
+// retries move to the normal topic.
+func deliveryTopics(nsq config.NSQ) []consumedTopic {
+	if !nsq.PriorityTopics {
+		return []consumedTopic{{name: nsq.DeliveriesTopic, weight: 1}}
+	}
+	weight := func(priority string) int {
+		if w := nsq.PriorityWeights[priority]; w > 0 {
+			return w
+		}
+		return 1
+	}
+	topics := make([]consumedTopic, 0, len(delivery.Priorities)+1)
+	for _, p := range delivery.Priorities {
+		topics = append(topics, consumedTopic{name: delivery.PriorityTopic(nsq.DeliveriesTopic, p), weight: weight(p)})
+	}
+	return append(topics, consumedTopic{name: nsq.DeliveriesTopic, weight: weight(delivery.PriorityLow)})
+}
+
+// splitInFlight divides total in-flight messages between topics in proportion to their
+// weights, giving every topic at least one
+func splitInFlight(total int, topics []consumedTopic) []int {
+	sum := 0
+	for _, t := range topics {
+		sum += t.weight
+	}
+	out := make([]int, len(topics))
+	for i, t := range topics {
+		out[i] = max(total*t.weight/sum, 1)
+	}
+	return out
+}
+
+// retryTopic is the topic a retry of t is republished to: its priority's topic with priority
+// topics enabled, where tasks from before the switch count as normal
+func retryTopic(nsq config.NSQ, t delivery.Task) string {
+	if !nsq.PriorityTopics {
+		return nsq.DeliveriesTopic
+	}
+	return delivery.PriorityTopic(nsq.DeliveriesTopic, t.Priority)
+}
+
+// consumerGroup consumes several delivery topics with one handler
+type consumerGroup []queue.Consumer
+
+// newDeliveryConsumer returns a consumer of every delivery topic on the worker channel, with
+// NSQ_MAX_IN_FLIGHT split between them by weight
+func newDeliveryConsumer(cfg config.Config, topics []consumedTopic) (consumerGroup, error) {
+	group := make(consumerGroup, 0, len(topics))
+	for i, inFlight := range splitInFlight(cfg.NSQ.MaxInFlight, topics) {
+		c, err := queue.NewConsumer(cfg, topics[i].name, cfg.NSQ.WorkerChannel, inFlight)
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, c)
+	}
+	return group, nil
+}
+
+// Start starts every consumer, stopping those already started if one fails
+func (g consumerGroup) Start(h queue.Handler) error {
+	for i, c := range g {
+		if err := c.Start(h); err != nil {
+			g[:i].Stop()
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop stops the consumers together, so they all drain within the same deadline
+func (g consumerGroup) Stop() {
+	var wg sync.WaitGroup
+	for _, c := range g {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.Stop()
+		}()
+	}
+	wg.Wait()
+}
+
+// Connected reports whether every consumer is connected
+func (g consumerGroup) Connected() bool {
+	for _, c := range g {
+		if !c.Connected() {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+)
+
+func TestDeliveryTopics(t *testing.T) {
+	nsq := config.NSQ{DeliveriesTopic: "deliveries", MaxInFlight: 1500}
+	if got := deliveryTopics(nsq); len(got) != 1 || got[0] != (consumedTopic{name: "deliveries", weight: 1}) {
+		t.Errorf("deliveryTopics(disabled) = %v, want the deliveries topic only", got)
+	}
+
+	nsq.PriorityTopics = true
+	nsq.PriorityWeights = map[string]int{"high": 6, "normal": 3}
+	want := []consumedTopic{
+		{name: "deliveries_high", weight: 6},
+		{name: "deliveries_normal", weight: 3},
+		{name: "deliveries_low", weight: 1},
+		{name: "deliveries", weight: 1},
+	}
+	topics := deliveryTopics(nsq)
+	if !slices.Equal(topics, want) {
+		t.Fatalf("deliveryTopics() = %v, want %v", topics, want)
+	}
+	if got := splitInFlight(1500, topics); !slices.Equal(got, []int{818, 409, 136, 136}) {
+		t.Errorf("splitInFlight(1500) = %v, want 818, 409, 136, 136", got)
+	}
+	// Every topic keeps at least one message in flight
+	if got := splitInFlight(4, topics); !slices.Equal(got, []int{2, 1, 1, 1}) {
+		t.Errorf("splitInFlight(4) = %v, want 2, 1, 1, 1", got)
+	}
+}
+
+func TestRetryTopic(t *testing.T) {
+	nsq := config.NSQ{DeliveriesTopic: "deliveries"}
+	high := delivery.Task{Priority: delivery.PriorityHigh}
+	if got := retryTopic(nsq, high); got != "deliveries" {
+		t.Errorf("retryTopic(disabled) = %q, want deliveries", got)
+	}
+	nsq.PriorityTopics = true
+	if got := retryTopic(nsq, high); got != "deliveries_high" {
+		t.Errorf("retryTopic(high) = %q, want deliveries_high", got)
+	}
+	// Tasks queued before priority topics were enabled retry on the normal topic
+	if got := retryTopic(nsq, delivery.Task{}); got != "deliveries_normal" {
+		t.Errorf("retryTopic(no priority) = %q, want deliveries_normal", got)
+	}
+}
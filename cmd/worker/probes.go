@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/queue"
+)
+
+const (
+	heartbeatEvery   = time.Second      // how often the liveness heartbeat ticks
+	heartbeatTimeout = 15 * time.Second // how stale the heartbeat may get before /livez fails
+	readyPingTimeout = time.Second      // how long /readyz waits for the database
+)
+
+// pinger is the part of the database pool /readyz checks
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// probes backs /readyz and /livez. The worker is ready while its database answers, its
+// consumer is connected and not stalled, and it isn't draining. It is alive while the
+// heartbeat loop keeps ticking.
+type probes struct {
+	pool       pinger
+	consumer   queue.Consumer
+	drain      *drainer
+	topics     []string // the topics whose channel backlog tells a stalled consumer from an idle one
+	channel    string
+	stallAfter time.Duration
+
+	lastHandled atomic.Int64 // unix nanoseconds when a message was last handed to the handler
+	backlog     atomic.Int64 // the channels' depth as last seen by the backlog monitor
+	heartbeat   atomic.Int64 // unix nanoseconds of the last heartbeat tick
+}
+
+// probeStatus is what /readyz and /livez report: each check's result, "ok" when it passed
+type probeStatus struct {
+	OK     bool              `json:"ok"`
+	Checks map[string]string `json:"checks"`
+}
+
+// observe wraps the delivery handler to note when messages arrive. A fresh consumer counts as
+// having just handled one.
+func (p *probes) observe(h queue.Handler) queue.Handler {
+	p.lastHandled.Store(time.Now().UnixNano())
+	return func(m queue.Message) {
+		p.lastHandled.Store(time.Now().UnixNano())
+		h(m)
+	}
+}
+
+// sawBacklog records the worker channel's depth across the delivery topics from a backlog
+// monitor reading
+func (p *probes) sawBacklog(stats []queue.ChannelStats) {
+	var depth int64
+	for _, c := range stats {
+		if c.Channel == p.channel && slices.Contains(p.topics, c.Topic) {
+			depth += c.Depth
+		}
+	}
+	p.backlog.Store(depth)
+}
+
+// stalled reports how long the consumer has gone without a message while its channel has a
+// backlog, or 0 when it isn't stalled
+func (p *probes) stalled(now time.Time) time.Duration {
+	if p.backlog.Load() == 0 {
+		return 0
+	}
+	idle := now.Sub(time.Unix(0, p.lastHandled.Load()))
+	if idle < p.stallAfter {
+		return 0
+	}
+	return idle
+}
+
+// beat runs the liveness heartbeat until ctx is done
+func (p *probes) beat(ctx context.Context) {
+	p.heartbeat.Store(time.Now().UnixNano())
+	ticker := time.NewTicker(heartbeatEvery)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			p.heartbeat.Store(now.UnixNano())
+		}
+	}
+}
+
+// ready runs the readiness checks
+func (p *probes) ready(ctx context.Context) probeStatus {
+	st := probeStatus{OK: true, Checks: map[string]string{}}
+	check := func(name string, err error) {
+		if err != nil {
+			st.OK = false
+			st.Checks[name] = err.Error()
+			return
+		}
+		st.Checks[name] = "ok"
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, readyPingTimeout)
+	defer cancel()
+	check("database", p.pool.Ping(ctx))
+
+	var err error
+	if !p.consumer.Connected() {
+		err = errors.New("not connected")
+	}
+	check("queue", err)
+
+	err = nil
+	if idle := p.stalled(time.Now()); idle > 0 {
+		err = fmt.Errorf("stalled: no message for %s with %d waiting", idle.Truncate(time.Second), p.backlog.Load())
+	}
+	check("consumer", err)
+
+	err = nil
+	if p.drain.active() {
+		err = errors.New("draining")
+	}
+	check("drain", err)
+	return st
+}
+
+// live runs the liveness check
+func (p *probes) live(now time.Time) probeStatus {
+	st := probeStatus{OK: true, Checks: map[string]string{"heartbeat": "ok"}}
+	if age := now.Sub(time.Unix(0, p.heartbeat.Load())); age > heartbeatTimeout {
+		st.OK = false
+		st.Checks["heartbeat"] = fmt.Sprintf("last beat %s ago", age.Truncate(time.Second))
+	}
+	return st
+}
+
+// readyHandler serves /readyz, 503 while any check fails
+func (p *probes) readyHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeProbe(w, p.ready(r.Context()))
+	}
+}
+
+// liveHandler serves /livez, 503 once the heartbeat stops so the worker is restarted
+func (p *probes) liveHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		writeProbe(w, p.live(time.Now()))
+	}
+}
+
+func writeProbe(w http.ResponseWriter, st probeStatus) {
+	w.Header().Set("Content-Type", "application/json")
+	if !st.OK {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	_ = json.NewEncoder(w).Encode(st)
+}
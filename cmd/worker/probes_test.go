@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/queue"
+)
+
+type fakePinger struct{ err error }
+
+func (p fakePinger) Ping(context.Context) error { return p.err }
+
+type fakeConsumer struct {
+	queue.Consumer
+	connected bool
+}
+
+func (c fakeConsumer) Connected() bool { return c.connected }
+
+func TestProbes_Ready(t *testing.T) {
+	newProbes := func() *probes {
+		p := &probes{
+			pool:       fakePinger{},
+			consumer:   fakeConsumer{connected: true},
+			drain:      &drainer{},
+			topics:     []string{"deliveries", "deliveries_high"},
+			channel:    "workers",
+			stallAfter: time.Minute,
+		}
+		p.observe(func(queue.Message) {})
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		setup  func(p *probes)
+		failed string // the check expected to fail, "" when ready
+	}{
+		{name: "ready", setup: func(*probes) {}},
+		{name: "database down", setup: func(p *probes) { p.pool = fakePinger{err: errors.New("connection refused")} }, failed: "database"},
+		{name: "queue disconnected", setup: func(p *probes) { p.consumer = fakeConsumer{} }, failed: "queue"},
+		{name: "draining", setup: func(p *probes) { p.drain.begin(time.Minute) }, failed: "drain"},
+		{name: "stalled with a backlog", setup: func(p *probes) {
+			p.lastHandled.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+			p.sawBacklog([]queue.ChannelStats{{Topic: "deliveries", Channel: "other", Depth: 0}, {Topic: "deliveries", Channel: "workers", Depth: 40}})
+		}, failed: "consumer"},
+		{name: "stalled with a priority topic backlog", setup: func(p *probes) {
+			p.lastHandled.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+			p.sawBacklog([]queue.ChannelStats{{Topic: "deliveries", Channel: "workers", Depth: 0}, {Topic: "deliveries_high", Channel: "workers", Depth: 3}})
+		}, failed: "consumer"},
+		{name: "idle without a backlog", setup: func(p *probes) {
+			p.lastHandled.Store(time.Now().Add(-2 * time.Minute).UnixNano())
+			p.sawBacklog([]queue.ChannelStats{{Topic: "deliveries", Channel: "workers", Depth: 0}})
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newProbes()
+			tt.setup(p)
+			rec := httptest.NewRecorder()
+			p.readyHandler()(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			var st probeStatus
+			if err := json.Unmarshal(rec.Body.Bytes(), &st); err != nil {
+				t.Fatalf("decode /readyz: %v", err)
+			}
+			wantCode := http.StatusOK
+			if tt.failed != "" {
+				wantCode = http.StatusServiceUnavailable
+			}
+			if rec.Code != wantCode || st.OK != (tt.failed == "") {
+				t.Errorf("/readyz = %d %+v, want %d", rec.Code, st, wantCode)
+			}
+			for name, result := range st.Checks {
+				if (name == tt.failed) == (result == "ok") {
+					t.Errorf("check %s = %q", name, result)
+				}
+			}
+			if len(st.Checks) != 4 {
+				t.Errorf("checks = %v, want database, queue, consumer and drain", st.Checks)
+			}
+		})
+	}
+}
+
+func TestProbes_Live(t *testing.T) {
+	p := &probes{}
+	ctx, cancel := context.WithCancel(context.Background())
+	go p.beat(ctx)
+	defer cancel()
+	for p.heartbeat.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	rec := httptest.NewRecorder()
+	p.liveHandler()(rec, httptest.NewRequest(http.MethodGet, "/livez", nil))
+	if rec.Code != http.StatusOK {
+		t.Errorf("/livez with a running heartbeat = %d %s, want 200", rec.Code, rec.Body)
+	}
+
+	if st := p.live(time.Now().Add(heartbeatTimeout + time.Second)); st.OK || st.Checks["heartbeat"] == "ok" {
+		t.Errorf("live() with a stale heartbeat = %+v, want failed", st)
+	}
+}
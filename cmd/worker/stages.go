@@ -0,0 +1,176 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/compliance"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// The handler's delivery.DeliveryEngine stages: HTTP with the worker's headers and client
+// certificates, outcomes in Postgres and the changefeed, and retries through the queue.
+
+// Sign signs the request under the endpoint's scheme (v1: HMAC over body||timestamp) and sets
+// the event and sender headers
+func (h *deliveryHandler) Sign(req *http.Request, a *delivery.Attempt) {
+	tracing.AddSpanEvent(req.Context(), "http.sign_request")
+	ep := a.Endpoint
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set(h.cfg.NSQ.TimestampHeader, ts)
+	req.Header.Set(h.cfg.NSQ.SignatureHeader, delivery.SignRequest(ep.SignatureScheme, ep.Secret, ep.SigningKey, req.Method, req.URL.RequestURI(), a.Body, ts))
+	setEventHeaders(req.Header, h.cfg.NSQ, a.Task)
+	setSenderHeaders(req.Header, h.cfg.NSQ, a.Task, ep.SenderHeaders)
+
+	// Add trace ID to HTTP headers for correlation
+	if traceID := tracing.GetTraceID(req.Context()); traceID != "" {
+		req.Header.Set("X-Trace-Id", traceID)
+	}
+}
+
+// Send records the request when the tenant is in compliance mode, then sends it with the
+// endpoint's client certificate, if it has one, under the endpoint's timeout, keeping the start
+// of the body of a response that isn't 2xx. A send aborted by the drain deadline is abandoned.
+// A capture endpoint's request is stored instead, and counts as a 200 once it is.
+func (h *deliveryHandler) Send(req *http.Request, a *delivery.Attempt) delivery.Response {
+	ctx, t := req.Context(), a.Task
+
+	// Compliance mode: store the exact signed request before it goes out
+	if a.Endpoint.RecordRequests {
+		if h.recordings == nil {
+			h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).Warn("tenant requires request recording but RECORDING_ENCRYPTION_KEY is not set")
+		} else if err := recordRequest(ctx, h.pool, h.recordings, t, a.Endpoint.RetentionDays, compliance.Capture(req, a.Body)); err != nil {
+			h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("request recording failed")
+			tracing.SetSpanError(ctx, err)
+		}
+	}
+
+	start := time.Now()
+	// record sent_at
+	tracing.AddSpanEvent(ctx, "db.update_delivery_sent")
+	_ = h.store.MarkSent(ctx, t.DeliveryID, start)
+
+	if a.Endpoint.Type == delivery.EndpointCapture {
+		err := captureRequest(ctx, h.pool, t, req.Header.Get(h.cfg.NSQ.SignatureHeader), compliance.Capture(req, a.Body))
+		r := delivery.Response{Latency: time.Since(start), Err: err}
+		if err == nil {
+			r.Status = http.StatusOK
+		}
+		return r
+	}
+
+	tracing.AddSpanEvent(ctx, "http.send_webhook")
+	client, err := h.client, error(nil)
+	if h.transports != nil {
+		client, err = h.transports.client(clientCert{certPEM: a.Endpoint.ClientCertPEM, keyPEM: a.Endpoint.ClientKeyPEM, secretName: a.Endpoint.ClientCertSecret}, a.Endpoint.ProxyURL)
+	}
+	timeout := delivery.RequestTimeout(a.Endpoint.Timeout, h.cfg.Worker.HTTP.Timeout, h.cfg.Worker.HTTP.MaxTimeout)
+	sendCtx, cancel := context.WithTimeout(traceConnections(ctx), timeout)
+	defer cancel()
+	var resp *http.Response
+	if err == nil {
+		resp, err = client.Do(req.WithContext(sendCtx))
+	}
+	r := delivery.Response{Latency: time.Since(start), Err: err}
+	if err != nil {
+		if h.drain.aborted(ctx) {
+			r.Err = fmt.Errorf("%w: %w", delivery.ErrAbandoned, err)
+		}
+		return r
+	}
+	r.Status = resp.StatusCode
+	if !r.OK() {
+		// Kept on the attempt so tenants can see why the receiver refused it
+		r.Body = delivery.CaptureBody(resp.Body, h.cfg.Worker.ResponseBodyLimit)
+	}
+	_ = resp.Body.Close()
+	return r
+}
+
+// Delivered marks the delivery delivered
+func (h *deliveryHandler) Delivered(ctx context.Context, a *delivery.Attempt, r delivery.Response) {
+	t := a.Task
+	// success: attempt+=, status=ok
+	tracing.AddSpanEvent(ctx, "delivery.success")
+	if err := h.store.MarkDelivered(ctx, t.DeliveryID, r.Status, r.Latency); err != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("db update success failed")
+		tracing.SetSpanError(ctx, err)
+		return
+	}
+	change := changefeed.FromTask(t, "inflight", "delivered")
+	change.Attempt, change.HTTPStatus = t.Attempt+1, r.Status
+	h.feed.Publish(change)
+}
+
+// Failed marks the delivery failed and reads back its attempt count. If that can't be read the
+// delivery is treated as out of attempts, so it is dead-lettered rather than retried forever.
+func (h *deliveryHandler) Failed(ctx context.Context, a *delivery.Attempt, r delivery.Response) int {
+	t := a.Task
+	tracing.AddSpanEvent(ctx, "delivery.failed")
+	updErr := h.store.MarkFailed(ctx, t.DeliveryID, r.Status, r.Latency, errString(r.Err), r.Body)
+	if updErr != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(updErr).Error("db update fail failed")
+		tracing.SetSpanError(ctx, updErr)
+	}
+
+	attempt, err := h.store.Attempt(ctx, t.DeliveryID)
+	if err != nil {
+		h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithError(err).Error("read attempt failed")
+		tracing.SetSpanError(ctx, err)
+		attempt = a.Endpoint.Policy.MaxAttempts // be safe -> DLQ
+	}
+	if updErr == nil {
+		change := changefeed.FromTask(t, "inflight", "failed")
+		change.Attempt, change.HTTPStatus, change.Error = attempt, r.Status, errString(r.Err)
+		h.feed.Publish(change)
+	}
+	return attempt
+}
+
+// DeadLettered moves the failed delivery to the DLQ
+func (h *deliveryHandler) DeadLettered(ctx context.Context, a *delivery.Attempt, attempt int, r delivery.Response, reason string) {
+	tracing.AddSpanEvent(ctx, "delivery.dlq", attribute.Int("attempt", attempt))
+	h.deadLetter(ctx, a.Task, "failed", attempt, r.Status, errString(r.Err), reason)
+}
+
+// Delay is the policy's backoff step for attempt, with the worker's jitter. Once the endpoint's
+// retry budget is spent it is at least RetryBudgetDelay, so an outage's retries are spread out.
+func (h *deliveryHandler) Delay(a *delivery.Attempt, attempt int) time.Duration {
+	d := computeDelay(attempt, a.Endpoint.Policy.Backoff, h.cfg.Worker.JitterPercent)
+	if h.budget.take(a.Task.EndpointID, time.Now()) {
+		return d
+	}
+	long := computeDelay(1, []time.Duration{h.cfg.Worker.RetryBudgetDelay}, h.cfg.Worker.JitterPercent)
+	d = max(d, long)
+	h.logger.Plain().WithDelivery(a.Task.DeliveryID).WithEndpoint(a.Task.EndpointID).
+		WithFields(map[string]any{"delay": d.String()}).Info("endpoint retry budget exhausted, delaying retry")
+	return d
+}
+
+// Retry republishes the task to its delivery topic; nsqd defers it up to MaxDeferral and the
+// task's due time covers the rest
+func (h *deliveryHandler) Retry(ctx context.Context, t delivery.Task, delay time.Duration) error {
+	tracing.AddSpanEvent(ctx, "delivery.requeue",
+		attribute.Int("attempt", t.Attempt),
+		attribute.String("delay", delay.String()),
+	)
+	h.logger.WithContext(ctx).WithDelivery(t.DeliveryID).WithFields(map[string]any{
+		"attempt": t.Attempt,
+		"delay":   delay.String(),
+	}).Info("requeue delivery")
+
+	if t.Ordered {
+		// Later deliveries in the partition wait until the retry is due rather than polling
+		_ = h.store.SetNextTry(ctx, t.DeliveryID, time.Now().Add(delay))
+	}
+	body, _ := json.Marshal(t)
+	return h.retries.DeferredPublish(retryTopic(h.cfg.NSQ, t), min(delay, h.cfg.Worker.MaxDeferral), body)
+}
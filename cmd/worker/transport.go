@@ -0,0 +1,46 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+)
+
+// newDeliveryClient returns the client webhooks are sent with: the egress guard's transport,
+// pooled and timed out as cfg says. Endpoints with a client certificate get clones of its transport.
+// The client has no overall timeout; each send sets its endpoint's as a context deadline.
+func newDeliveryClient(egress *netguard.Guard, cfg config.WorkerHTTP) *http.Client {
+	t := egress.Transport()
+	t.DialContext = egress.Dial(net.Dialer{Timeout: cfg.DialTimeout, KeepAlive: cfg.KeepAlive})
+	t.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	t.ResponseHeaderTimeout = cfg.ResponseHeaderTimeout
+	t.IdleConnTimeout = cfg.IdleConnTimeout
+	t.MaxIdleConns = cfg.MaxIdleConns
+	t.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	t.MaxConnsPerHost = cfg.MaxConnsPerHost
+	if !cfg.HTTP2 {
+		// A non-nil, empty TLSNextProto is how net/http is told not to negotiate h2
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	return &http.Client{Transport: t}
+}
+
+// traceConnections records, for requests made with the returned context, whether their
+// connection came from the pool and how long getting it took
+func traceConnections(ctx context.Context) context.Context {
+	var asked time.Time
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		GetConn: func(string) { asked = time.Now() },
+		GotConn: func(info httptrace.GotConnInfo) {
+			metrics.RecordHTTPConnection(info.Reused, time.Since(asked))
+		},
+	})
+}
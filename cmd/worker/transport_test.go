@@ -0,0 +1,73 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+)
+
+func TestNewDeliveryClient(t *testing.T) {
+	cfg := config.WorkerHTTP{
+		Timeout:               20 * time.Second,
+		DialTimeout:           2 * time.Second,
+		TLSHandshakeTimeout:   3 * time.Second,
+		ResponseHeaderTimeout: 5 * time.Second,
+		IdleConnTimeout:       time.Minute,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   16,
+		MaxConnsPerHost:       64,
+		HTTP2:                 true,
+	}
+	c := newDeliveryClient(&netguard.Guard{}, cfg)
+	tr := c.Transport.(*http.Transport)
+	if c.Timeout != 0 || tr.TLSHandshakeTimeout != cfg.TLSHandshakeTimeout || tr.ResponseHeaderTimeout != cfg.ResponseHeaderTimeout ||
+		tr.IdleConnTimeout != cfg.IdleConnTimeout || tr.MaxIdleConns != 100 || tr.MaxIdleConnsPerHost != 16 || tr.MaxConnsPerHost != 64 {
+		t.Errorf("client timeout %v with transport %+v, want no client timeout and the transport to match %+v", c.Timeout, tr, cfg)
+	}
+	if !tr.ForceAttemptHTTP2 || tr.Proxy != nil {
+		t.Errorf("transport ForceAttemptHTTP2 = %v, proxy set = %v, want h2 and no proxy", tr.ForceAttemptHTTP2, tr.Proxy != nil)
+	}
+
+	cfg.HTTP2 = false
+	tr = newDeliveryClient(&netguard.Guard{}, cfg).Transport.(*http.Transport)
+	if tr.ForceAttemptHTTP2 || tr.TLSNextProto == nil || len(tr.TLSNextProto) != 0 {
+		t.Errorf("HTTP/2 disabled: ForceAttemptHTTP2 = %v, TLSNextProto = %v, want false and empty", tr.ForceAttemptHTTP2, tr.TLSNextProto)
+	}
+}
+
+func TestTraceConnections_RecordsReuse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	g, err := netguard.New([]string{"127.0.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := newDeliveryClient(g, config.WorkerHTTP{Timeout: 5 * time.Second, MaxIdleConnsPerHost: 4, HTTP2: true})
+	reused := metrics.HTTPConnectionsTotal.WithLabelValues("true")
+	fresh := metrics.HTTPConnectionsTotal.WithLabelValues("false")
+	reusedBefore, freshBefore := testutil.ToFloat64(reused), testutil.ToFloat64(fresh)
+
+	for range 3 {
+		req, _ := http.NewRequestWithContext(traceConnections(t.Context()), http.MethodPost, srv.URL, nil)
+		resp, err := c.Do(req)
+		if err != nil {
+			t.Fatalf("Do() unexpected error: %v", err)
+		}
+		_ = resp.Body.Close()
+	}
+
+	if got := testutil.ToFloat64(fresh) - freshBefore; got != 1 {
+		t.Errorf("new connections = %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(reused) - reusedBefore; got != 2 {
+		t.Errorf("reused connections = %v, want 2", got)
+	}
+}
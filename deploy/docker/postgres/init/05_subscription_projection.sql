@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Payload projection: subscriptions can limit which payload fields are delivered
+ALTER TABLE harborhook.subscriptions
+    ADD COLUMN IF NOT EXISTS include_fields TEXT[] NOT NULL DEFAULT '{}',
+    ADD COLUMN IF NOT EXISTS exclude_fields TEXT[] NOT NULL DEFAULT '{}';
+
+-- Remember which subscription produced a delivery so replays reuse its projection
+ALTER TABLE harborhook.deliveries
+    ADD COLUMN IF NOT EXISTS subscription_id UUID REFERENCES harborhook.subscriptions(id) ON DELETE SET NULL;
+
+COMMIT;
@@ -0,0 +1,38 @@
+BEGIN;
+
+-- Per-tenant compliance mode: record every signed delivery request
+CREATE TABLE IF NOT EXISTS harborhook.tenant_compliance (
+    tenant_id        TEXT PRIMARY KEY,
+    record_requests  BOOLEAN NOT NULL DEFAULT false,
+    retention_days   INT NOT NULL DEFAULT 30 CHECK (retention_days > 0),
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Recorded requests (headers + body), encrypted by the worker before insert
+CREATE TABLE IF NOT EXISTS harborhook.delivery_recordings (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id  UUID NOT NULL REFERENCES harborhook.deliveries(id) ON DELETE CASCADE,
+    tenant_id    TEXT NOT NULL,
+    attempt      INT NOT NULL,
+    key_id       TEXT NOT NULL,
+    sealed       BYTEA NOT NULL,
+    recorded_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_recordings_delivery ON harborhook.delivery_recordings(delivery_id, attempt);
+CREATE INDEX IF NOT EXISTS idx_delivery_recordings_expires  ON harborhook.delivery_recordings(expires_at);
+
+-- Audit trail: one row per read of a delivery's recordings
+CREATE TABLE IF NOT EXISTS harborhook.recording_access_log (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id    TEXT NOT NULL,
+    delivery_id  UUID NOT NULL,
+    actor        TEXT NOT NULL,
+    reason       TEXT NOT NULL,
+    accessed_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_recording_access_tenant_time ON harborhook.recording_access_log(tenant_id, accessed_at DESC);
+
+COMMIT;
@@ -0,0 +1,63 @@
+-- New enum values can't be used in the transaction that adds them, so this runs first
+ALTER TYPE delivery_status ADD VALUE IF NOT EXISTS 'parked';
+
+BEGIN;
+
+-- Incident controls: stop dispatching for a tenant or endpoint without scaling workers down
+CREATE TABLE IF NOT EXISTS harborhook.delivery_freezes (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id    TEXT,
+    endpoint_id  UUID REFERENCES harborhook.endpoints(id) ON DELETE CASCADE,
+    reason       TEXT,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    released_at  TIMESTAMPTZ,
+    CONSTRAINT delivery_freezes_one_target CHECK ((tenant_id IS NULL) <> (endpoint_id IS NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_delivery_freezes_active_tenant   ON harborhook.delivery_freezes(tenant_id)   WHERE released_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_delivery_freezes_active_endpoint ON harborhook.delivery_freezes(endpoint_id) WHERE released_at IS NULL;
+
+ALTER TABLE harborhook.deliveries ADD COLUMN IF NOT EXISTS parked_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_deliveries_parked ON harborhook.deliveries(endpoint_id) WHERE status = 'parked';
+
+-- Teach the timestamp trigger about 'parked'; the CASE needs an ELSE so unknown statuses don't raise
+CREATE OR REPLACE FUNCTION update_delivery_timestamps()
+RETURNS TRIGGER AS $$
+BEGIN
+    CASE NEW.status
+        WHEN 'queued' THEN
+            IF OLD.status IS DISTINCT FROM NEW.status AND NEW.enqueued_at IS NULL THEN
+                NEW.enqueued_at = now();
+            END IF;
+        WHEN 'inflight' THEN
+            IF OLD.status IS DISTINCT FROM NEW.status THEN
+                NEW.dequeued_at = now();
+            END IF;
+        WHEN 'delivered' THEN
+            IF OLD.status IS DISTINCT FROM NEW.status THEN
+                NEW.delivered_at = now();
+            END IF;
+        WHEN 'failed' THEN
+            IF OLD.status IS DISTINCT FROM NEW.status THEN
+                NEW.failed_at = now();
+            END IF;
+        WHEN 'dead' THEN
+            IF OLD.status IS DISTINCT FROM NEW.status THEN
+                NEW.dlq_at = now();
+            END IF;
+        WHEN 'parked' THEN
+            IF OLD.status IS DISTINCT FROM NEW.status THEN
+                NEW.parked_at = now();
+            END IF;
+        ELSE
+            NULL;
+    END CASE;
+
+    NEW.updated_at = now();
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Cluster-wide kill switch. A single row, read by every worker on dequeue.
+CREATE TABLE IF NOT EXISTS harborhook.dispatch_control (
+    id                  BOOLEAN PRIMARY KEY DEFAULT true CHECK (id),
+    paused              BOOLEAN NOT NULL DEFAULT false,
+    reason              TEXT,
+    paused_at           TIMESTAMPTZ,
+    resumed_at          TIMESTAMPTZ,
+    ramp_seconds        INT NOT NULL DEFAULT 0 CHECK (ramp_seconds >= 0),
+    ramp_start_percent  INT NOT NULL DEFAULT 100 CHECK (ramp_start_percent BETWEEN 0 AND 100),
+    updated_at          TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+INSERT INTO harborhook.dispatch_control(id) VALUES (true) ON CONFLICT (id) DO NOTHING;
+
+COMMIT;
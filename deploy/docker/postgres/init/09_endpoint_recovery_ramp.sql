@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Per-endpoint ramp applied after recovery (e.g. a freeze is lifted): each step admits a
+-- percentage of tasks for recovery_ramp_step_seconds. A step length of 0 disables the ramp.
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS recovery_ramp_percents INT[] NOT NULL DEFAULT '{10,50,100}';
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS recovery_ramp_step_seconds INT NOT NULL DEFAULT 60;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS recovered_at TIMESTAMPTZ;
+
+COMMIT;
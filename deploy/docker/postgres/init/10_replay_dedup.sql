@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Widen "one pending replay per source" to every active status. A replay that is retrying
+-- (failed) or parked is still active; delivered and dead replays leave the index, so a delivery
+-- can be replayed again once its last replay is done.
+DROP INDEX IF EXISTS harborhook.uq_single_pending_replay;
+CREATE UNIQUE INDEX IF NOT EXISTS uq_single_active_replay
+    ON harborhook.deliveries(replay_of)
+    WHERE status IN ('queued', 'inflight', 'failed', 'parked');
+
+COMMIT;
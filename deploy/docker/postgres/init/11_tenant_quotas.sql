@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Per-tenant publishing quotas. Tenants without a row, and limits of 0, are unlimited.
+CREATE TABLE IF NOT EXISTS harborhook.tenant_quotas (
+    tenant_id          TEXT PRIMARY KEY,
+    events_per_minute  INT NOT NULL DEFAULT 0 CHECK (events_per_minute >= 0),
+    max_fanout         INT NOT NULL DEFAULT 0 CHECK (max_fanout >= 0),
+    -- Fixed one-minute window; the first publish in a new minute resets the count
+    window_start       TIMESTAMPTZ NOT NULL DEFAULT date_trunc('minute', now()),
+    window_events      INT NOT NULL DEFAULT 0,
+    updated_at         TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMIT;
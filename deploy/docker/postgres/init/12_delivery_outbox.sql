@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Transactional outbox for delivery tasks. Rows are written in the same transaction as their
+-- deliveries, so a task is never lost when the NSQ publish fails after commit; the ingest relay
+-- republishes unsent rows and marks them sent (at-least-once).
+CREATE TABLE IF NOT EXISTS harborhook.delivery_outbox (
+    id          BIGSERIAL PRIMARY KEY,
+    delivery_id UUID NOT NULL REFERENCES harborhook.deliveries(id) ON DELETE CASCADE,
+    topic       TEXT NOT NULL,
+    body        BYTEA NOT NULL,
+    attempts    INT NOT NULL DEFAULT 0,
+    last_error  TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at     TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unsent ON harborhook.delivery_outbox(id) WHERE sent_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_outbox_sent_at ON harborhook.delivery_outbox(sent_at) WHERE sent_at IS NOT NULL;
+
+COMMIT;
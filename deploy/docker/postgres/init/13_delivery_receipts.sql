@@ -0,0 +1,6 @@
+BEGIN;
+
+-- Set when the receiver confirms processing through AcknowledgeDelivery (beyond an HTTP 2xx)
+ALTER TABLE harborhook.deliveries ADD COLUMN IF NOT EXISTS acked_at TIMESTAMPTZ;
+
+COMMIT;
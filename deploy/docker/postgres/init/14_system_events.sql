@@ -0,0 +1,18 @@
+BEGIN;
+
+-- Notable conditions detected by harborhook itself (e.g. an endpoint suddenly answering 401s),
+-- as opposed to the tenant events it delivers
+CREATE TABLE IF NOT EXISTS harborhook.system_events (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id   TEXT NOT NULL,
+    endpoint_id UUID REFERENCES harborhook.endpoints(id) ON DELETE CASCADE,
+    type        TEXT NOT NULL,
+    message     TEXT NOT NULL,
+    details     JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_events_tenant ON harborhook.system_events(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_system_events_endpoint ON harborhook.system_events(endpoint_id, type, created_at DESC);
+
+COMMIT;
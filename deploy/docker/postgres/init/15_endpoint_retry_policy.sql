@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Per-endpoint retry overrides. The defaults defer to the worker's global settings: 0 attempts
+-- uses MAX_ATTEMPTS, an empty backoff uses BACKOFF_SCHEDULE, and an empty retry_on retries
+-- every failure class (e.g. http_5xx, timeout).
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS retry_max_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS retry_backoff_seconds INT[] NOT NULL DEFAULT '{}';
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS retry_on TEXT[] NOT NULL DEFAULT '{}';
+
+COMMIT;
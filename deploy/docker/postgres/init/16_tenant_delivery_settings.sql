@@ -0,0 +1,11 @@
+BEGIN;
+
+-- Per-tenant delivery options. Tenants without a row get the defaults.
+CREATE TABLE IF NOT EXISTS harborhook.tenant_delivery_settings (
+    tenant_id       TEXT PRIMARY KEY,
+    -- Send the tenant ID and event type as headers so receivers can route and allowlist
+    sender_headers  BOOLEAN NOT NULL DEFAULT true,
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMIT;
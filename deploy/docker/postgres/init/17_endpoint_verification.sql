@@ -0,0 +1,9 @@
+BEGIN;
+
+-- New endpoints must echo a challenge token before they get deliveries. Endpoints that exist
+-- when this runs are taken as verified; later ones start unverified (NULL).
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ DEFAULT now();
+ALTER TABLE harborhook.endpoints ALTER COLUMN verified_at DROP DEFAULT;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS verification_token TEXT;
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Client certificate presented to endpoints that require mutual TLS: either an uploaded PEM
+-- pair or the name of a secret mounted into the workers
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS client_cert_pem TEXT;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS client_key_pem TEXT;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS client_cert_secret TEXT;
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+-- How webhook bodies are compressed for each endpoint ('none' or 'gzip')
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS compression TEXT NOT NULL DEFAULT 'none'
+    CHECK (compression IN ('none', 'gzip'));
+
+COMMIT;
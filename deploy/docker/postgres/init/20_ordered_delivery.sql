@@ -0,0 +1,17 @@
+BEGIN;
+
+-- Ordered endpoints get one delivery at a time per partition, in event publish order. The
+-- partition key is a dot-notation path into the payload; '' puts every delivery in one partition.
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS ordered BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS partition_key TEXT NOT NULL DEFAULT '';
+
+-- Publish order of events
+ALTER TABLE harborhook.events ADD COLUMN IF NOT EXISTS seq BIGSERIAL;
+
+-- The partition value a delivery was published with; NULL when its endpoint isn't ordered
+ALTER TABLE harborhook.deliveries ADD COLUMN IF NOT EXISTS ordering_key TEXT;
+CREATE INDEX IF NOT EXISTS idx_deliveries_ordering
+    ON harborhook.deliveries(endpoint_id, ordering_key)
+    WHERE ordering_key IS NOT NULL AND status IN ('queued', 'inflight', 'failed', 'parked');
+
+COMMIT;
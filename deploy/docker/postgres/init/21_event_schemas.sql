@@ -0,0 +1,14 @@
+BEGIN;
+
+-- JSON Schemas tenants register per event type. Versions only ever get added; publishes are
+-- validated against the highest version of their event type.
+CREATE TABLE IF NOT EXISTS harborhook.event_schemas (
+    tenant_id   TEXT NOT NULL,
+    event_type  TEXT NOT NULL,
+    version     INT NOT NULL CHECK (version > 0),
+    schema      JSONB NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (tenant_id, event_type, version)
+);
+
+COMMIT;
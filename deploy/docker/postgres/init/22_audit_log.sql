@@ -0,0 +1,21 @@
+BEGIN;
+
+-- Management operations: who changed an endpoint, replayed a delivery or purged the DLQ, and
+-- what the resource looked like before and after. Snapshots never hold secrets or keys.
+CREATE TABLE IF NOT EXISTS harborhook.audit_log (
+    id               BIGSERIAL PRIMARY KEY,
+    tenant_id        TEXT NOT NULL,            -- tenant whose resources changed
+    action           TEXT NOT NULL,            -- e.g. endpoint.create, dlq.purge
+    resource_type    TEXT NOT NULL,
+    resource_id      TEXT NOT NULL DEFAULT '',
+    actor_tenant_id  TEXT NOT NULL DEFAULT '', -- '' when requests aren't authenticated
+    actor_subject    TEXT NOT NULL DEFAULT '',
+    actor_role       TEXT NOT NULL DEFAULT '',
+    client_ip        TEXT NOT NULL DEFAULT '',
+    before           JSONB,
+    after            JSONB,
+    created_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS idx_audit_log_tenant ON harborhook.audit_log(tenant_id, id DESC);
+
+COMMIT;
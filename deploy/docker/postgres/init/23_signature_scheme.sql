@@ -0,0 +1,8 @@
+BEGIN;
+
+-- How webhooks to each endpoint are signed ('v1': sha256=<hex> over body || timestamp,
+-- 'v2': key id, algorithm and a signature over method, path and body)
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS signature_scheme TEXT NOT NULL DEFAULT 'v1'
+    CHECK (signature_scheme IN ('v1', 'v2'));
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+-- Ed25519 keypair per tenant for the ed25519 signature scheme, created when one of its endpoints
+-- first opts in. The public key is published by GetSigningKeys; the seed is read only by
+-- workers and verification challenges.
+CREATE TABLE IF NOT EXISTS harborhook.tenant_signing_keys (
+    tenant_id    TEXT PRIMARY KEY,
+    -- First 12 hex digits of SHA-256(public_key), the kid signatures carry
+    key_id       TEXT NOT NULL,
+    public_key   BYTEA NOT NULL,
+    -- 32-byte Ed25519 seed
+    private_key  BYTEA NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER TABLE harborhook.endpoints DROP CONSTRAINT IF EXISTS endpoints_signature_scheme_check;
+ALTER TABLE harborhook.endpoints ADD CONSTRAINT endpoints_signature_scheme_check
+    CHECK (signature_scheme IN ('v1', 'v2', 'ed25519'));
+
+COMMIT;
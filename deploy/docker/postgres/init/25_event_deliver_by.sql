@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Optional delivery deadline from PublishEvent's deliver_by or ttl. Deliveries still pending
+-- when it passes are dead-lettered with reason "expired" rather than retried.
+ALTER TABLE harborhook.events ADD COLUMN IF NOT EXISTS deliver_by TIMESTAMPTZ;
+
+COMMIT;
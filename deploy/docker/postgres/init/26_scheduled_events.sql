@@ -0,0 +1,12 @@
+BEGIN;
+
+-- Events published with a future publish_at are stored as 'scheduled' and fanned out by ingest's
+-- dispatcher once it comes; every other event is 'published' as soon as it is stored.
+ALTER TABLE harborhook.events ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published'
+    CHECK (status IN ('scheduled', 'published'));
+ALTER TABLE harborhook.events ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+CREATE INDEX IF NOT EXISTS idx_events_scheduled
+    ON harborhook.events(publish_at)
+    WHERE status = 'scheduled';
+
+COMMIT;
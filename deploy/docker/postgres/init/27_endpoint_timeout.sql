@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Per-endpoint request timeout in milliseconds; NULL uses the worker's HTTP_CLIENT_TIMEOUT.
+-- Workers cap it at WORKER_HTTP_MAX_TIMEOUT.
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS timeout_ms INTEGER CHECK (timeout_ms > 0);
+
+COMMIT;
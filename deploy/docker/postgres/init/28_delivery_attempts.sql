@@ -0,0 +1,17 @@
+BEGIN;
+
+-- One row per attempt at a delivery, written by the worker alongside the deliveries update.
+-- deliveries keeps only the latest attempt's status, latency and error.
+CREATE TABLE IF NOT EXISTS harborhook.delivery_attempts (
+    delivery_id UUID NOT NULL REFERENCES harborhook.deliveries(id) ON DELETE CASCADE,
+    attempt     INT NOT NULL,
+    outcome     TEXT NOT NULL CHECK (outcome IN ('delivered', 'failed')),
+    http_status INT,
+    latency_ms  INT,
+    error       TEXT,
+    sent_at     TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (delivery_id, attempt)
+);
+
+COMMIT;
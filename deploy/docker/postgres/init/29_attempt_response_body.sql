@@ -0,0 +1,7 @@
+BEGIN;
+
+-- The start of a failed attempt's response body (up to the worker's WORKER_RESPONSE_BODY_LIMIT)
+ALTER TABLE harborhook.delivery_attempts ADD COLUMN IF NOT EXISTS response_body TEXT;
+ALTER TABLE harborhook.delivery_attempts ADD COLUMN IF NOT EXISTS response_truncated BOOLEAN NOT NULL DEFAULT false;
+
+COMMIT;
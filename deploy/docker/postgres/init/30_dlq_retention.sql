@@ -0,0 +1,34 @@
+BEGIN;
+
+-- Per-tenant DLQ retention, overriding the worker's DLQ_MAX_AGE, DLQ_MAX_ENTRIES and DLQ_ARCHIVE.
+-- Zero keeps entries regardless of age or count.
+CREATE TABLE IF NOT EXISTS harborhook.tenant_dlq_retention (
+    tenant_id        TEXT PRIMARY KEY,
+    max_age_seconds  BIGINT NOT NULL DEFAULT 0 CHECK (max_age_seconds >= 0),
+    max_entries      INT NOT NULL DEFAULT 0 CHECK (max_entries >= 0),
+    archive          BOOLEAN NOT NULL DEFAULT false,
+    updated_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- DLQ entries copied out before they were purged. Deliveries may be deleted later, so the row
+-- keeps what is needed to understand and replay the delivery by hand.
+CREATE TABLE IF NOT EXISTS harborhook.dlq_archive (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    delivery_id       UUID NOT NULL,
+    tenant_id         TEXT NOT NULL,
+    endpoint_id       UUID NOT NULL,
+    event_id          UUID NOT NULL,
+    event_type        TEXT NOT NULL,
+    payload           JSONB,
+    reason            TEXT NOT NULL,
+    last_error        TEXT,
+    http_status       INT,
+    attempts          INT NOT NULL DEFAULT 0,
+    dead_lettered_at  TIMESTAMPTZ NOT NULL,
+    archived_at       TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_dlq_archive_tenant_time ON harborhook.dlq_archive(tenant_id, archived_at DESC);
+CREATE INDEX IF NOT EXISTS idx_dlq_created ON harborhook.dlq(created_at);
+
+COMMIT;
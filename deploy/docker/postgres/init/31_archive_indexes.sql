@@ -0,0 +1,7 @@
+BEGIN;
+
+-- The worker's archiver looks for delivered deliveries and events past ARCHIVE_AFTER_DAYS
+CREATE INDEX IF NOT EXISTS idx_deliveries_delivered_at ON harborhook.deliveries(delivered_at) WHERE status = 'delivered';
+CREATE INDEX IF NOT EXISTS idx_events_created ON harborhook.events(created_at) WHERE status = 'published';
+
+COMMIT;
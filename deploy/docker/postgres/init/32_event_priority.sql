@@ -0,0 +1,9 @@
+BEGIN;
+
+-- Priority picks the delivery topic an event's tasks go through when priority topics are
+-- enabled; it is kept on the event so scheduled events, replays and resumes keep it
+ALTER TABLE harborhook.events
+  ADD COLUMN IF NOT EXISTS priority TEXT NOT NULL DEFAULT 'normal'
+    CHECK (priority IN ('high', 'normal', 'low'));
+
+COMMIT;
@@ -0,0 +1,9 @@
+BEGIN;
+
+-- The receiver idempotency key of a replay that was given a new one, inherited by replays of it
+-- that keep their source's key. NULL uses the key derived from the event and endpoint, which the
+-- original delivery, its retries and same-key replays share.
+ALTER TABLE harborhook.deliveries
+  ADD COLUMN IF NOT EXISTS replay_key TEXT;
+
+COMMIT;
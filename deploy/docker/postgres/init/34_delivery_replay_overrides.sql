@@ -0,0 +1,10 @@
+BEGIN;
+
+-- Overrides a replay was sent with, kept for auditing and applied again when it is retried or
+-- resumed: the URL it went to instead of the endpoint's, and the merge patch applied to the
+-- event's payload. NULL means no override.
+ALTER TABLE harborhook.deliveries
+  ADD COLUMN IF NOT EXISTS override_url TEXT,
+  ADD COLUMN IF NOT EXISTS payload_patch JSONB;
+
+COMMIT;
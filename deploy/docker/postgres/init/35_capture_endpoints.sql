@@ -0,0 +1,25 @@
+BEGIN;
+
+-- Capture endpoints store their deliveries for GetCapturedDeliveries instead of sending them
+ALTER TABLE harborhook.endpoints
+  ADD COLUMN IF NOT EXISTS endpoint_type TEXT NOT NULL DEFAULT 'http'
+    CHECK (endpoint_type IN ('http', 'capture'));
+
+-- Requests captured in place of HTTP deliveries; the worker keeps the newest 500 per endpoint
+CREATE TABLE IF NOT EXISTS harborhook.captured_deliveries (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id    TEXT NOT NULL,
+    endpoint_id  UUID NOT NULL REFERENCES harborhook.endpoints(id) ON DELETE CASCADE,
+    delivery_id  UUID NOT NULL REFERENCES harborhook.deliveries(id) ON DELETE CASCADE,
+    event_id     UUID NOT NULL,
+    event_type   TEXT NOT NULL,
+    attempt      INT NOT NULL,
+    headers      JSONB NOT NULL,
+    body         TEXT NOT NULL,
+    signature    TEXT NOT NULL,
+    captured_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_captured_deliveries_endpoint ON harborhook.captured_deliveries(endpoint_id, captured_at DESC);
+
+COMMIT;
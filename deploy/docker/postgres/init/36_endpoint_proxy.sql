@@ -0,0 +1,7 @@
+BEGIN;
+
+-- Per-endpoint egress proxy: an http(s) proxy URL, optionally with user:password, or 'direct'
+-- to bypass the worker's global proxy. NULL uses the global setting.
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS proxy_url TEXT;
+
+COMMIT;
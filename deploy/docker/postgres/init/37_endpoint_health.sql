@@ -0,0 +1,23 @@
+BEGIN;
+
+-- Opt-in health checks: workers send a signed HEAD or GET to the endpoint's URL every
+-- HEALTH_CHECK_INTERVAL. An unhealthy endpoint's deliveries are held until it recovers.
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS health_check_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS health_check_method TEXT NOT NULL DEFAULT 'HEAD'
+    CHECK (health_check_method IN ('HEAD', 'GET'));
+
+-- The latest health of each probed endpoint. next_check_at is claimed by one worker at a time.
+CREATE TABLE IF NOT EXISTS harborhook.endpoint_health (
+    endpoint_id UUID PRIMARY KEY REFERENCES harborhook.endpoints(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'unknown' CHECK (status IN ('unknown', 'healthy', 'unhealthy')),
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    consecutive_successes INT NOT NULL DEFAULT 0,
+    last_checked_at TIMESTAMPTZ,
+    last_status_code INT,
+    last_error TEXT,
+    last_latency_ms INT,
+    changed_at TIMESTAMPTZ,
+    next_check_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- Endpoints that keep failing are disabled by ingest (AUTO_DISABLE_*): they get no new
+-- deliveries, and queued ones fail, until EnableEndpoint. enabled_at starts a fresh window.
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS disabled_at TIMESTAMPTZ;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS disabled_reason TEXT;
+ALTER TABLE harborhook.endpoints ADD COLUMN IF NOT EXISTS enabled_at TIMESTAMPTZ;
+
+-- Finished deliveries by endpoint and time, for the failure streak and rate
+CREATE INDEX IF NOT EXISTS idx_deliveries_endpoint_finished
+    ON harborhook.deliveries(endpoint_id, updated_at)
+    WHERE status IN ('delivered', 'dead', 'failed');
+
+COMMIT;
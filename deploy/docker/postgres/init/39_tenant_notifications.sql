@@ -0,0 +1,34 @@
+BEGIN;
+
+-- Each tenant's ops contact for operational notices: an email address and/or one of its
+-- endpoints, which receives notices as harborhook.notice.* events. The thresholds pick which
+-- notices are sent; 0 sends none of that kind.
+CREATE TABLE IF NOT EXISTS harborhook.tenant_notification_settings (
+    tenant_id            TEXT PRIMARY KEY,
+    email                TEXT,
+    endpoint_id          UUID REFERENCES harborhook.endpoints(id) ON DELETE SET NULL,
+    dlq_threshold        INT NOT NULL DEFAULT 0 CHECK (dlq_threshold >= 0),
+    secret_max_age_days  INT NOT NULL DEFAULT 0 CHECK (secret_max_age_days >= 0),
+    updated_at           TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Notices sent, and how: the delivery carrying it to the ops endpoint, and the email's outcome
+CREATE TABLE IF NOT EXISTS harborhook.notifications (
+    id            BIGSERIAL PRIMARY KEY,
+    tenant_id     TEXT NOT NULL,
+    kind          TEXT NOT NULL,
+    endpoint_id   UUID,        -- the endpoint the notice is about; NULL for the whole tenant
+    subject       TEXT NOT NULL,
+    body          TEXT NOT NULL,
+    details       JSONB NOT NULL DEFAULT '{}',
+    delivery_id   UUID,
+    email         TEXT,
+    email_sent_at TIMESTAMPTZ,
+    error         TEXT,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_tenant_kind
+    ON harborhook.notifications(tenant_id, kind, created_at DESC);
+
+COMMIT;
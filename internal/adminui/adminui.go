@@ -0,0 +1,39 @@
+// Package adminui serves the embedded admin console: a static single-page app that lists
+// tenants, endpoints, recent deliveries, and the DLQ, and replays deliveries.
+//
+// The page itself is public; every call it makes goes through the REST gateway with the
+// operator's bearer token, and the list APIs only answer for the admin tenant.
+package adminui
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+)
+
+// Prefix is where the console is mounted on the ingest HTTP mux
+const Prefix = "/admin/ui/"
+
+//go:embed static
+var static embed.FS
+
+// Handler serves the console under Prefix and redirects the bare path to it
+func Handler() http.Handler {
+	sub, err := fs.Sub(static, "static")
+	if err != nil {
+		panic(err) // the embed pattern guarantees the directory exists
+	}
+	files := http.StripPrefix(Prefix, http.FileServerFS(sub))
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == Prefix[:len(Prefix)-1] {
+			http.Redirect(w, r, Prefix, http.StatusMovedPermanently)
+			return
+		}
+		// The token lives in sessionStorage, so keep the page from being framed or loading foreign scripts
+		w.Header().Set("Content-Security-Policy", "default-src 'self'; frame-ancestors 'none'")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Cache-Control", "no-cache")
+		files.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,158 @@
+// Harborhook admin console. Talks to the REST gateway on the same origin with the
+// bearer token kept in sessionStorage (cleared when the tab closes).
+(function () {
+  "use strict";
+
+  const TOKEN_KEY = "harborhook.adminToken";
+  const STATUS_PREFIX = "DELIVERY_ATTEMPT_STATUS_";
+
+  const $ = (sel) => document.querySelector(sel);
+  let selectedTenant = "";
+
+  function token() {
+    return sessionStorage.getItem(TOKEN_KEY) || "";
+  }
+
+  function showError(msg) {
+    const el = $("#error");
+    el.textContent = msg;
+    el.hidden = !msg;
+  }
+
+  async function api(method, path, body) {
+    const headers = { "Accept": "application/json" };
+    if (token()) headers["Authorization"] = "Bearer " + token();
+    if (body !== undefined) headers["Content-Type"] = "application/json";
+
+    const res = await fetch(path, { method, headers, body: body === undefined ? undefined : JSON.stringify(body) });
+    const text = await res.text();
+    const data = text ? JSON.parse(text) : {};
+    if (!res.ok) {
+      throw new Error(data.message || res.status + " " + res.statusText);
+    }
+    return data;
+  }
+
+  // Builds a table cell; text is always set with textContent so API values are never parsed as HTML
+  function cell(text, className) {
+    const td = document.createElement("td");
+    td.textContent = text === undefined || text === null ? "" : String(text);
+    if (className) td.className = className;
+    return td;
+  }
+
+  function codeCell(text) {
+    const td = document.createElement("td");
+    const code = document.createElement("code");
+    code.textContent = text || "";
+    td.appendChild(code);
+    return td;
+  }
+
+  function when(ts) {
+    return ts ? new Date(ts).toLocaleString() : "";
+  }
+
+  async function loadTenants() {
+    const data = await api("GET", "/v1/admin/tenants");
+    const body = $("#tenants tbody");
+    body.replaceChildren();
+    for (const t of data.tenants || []) {
+      const tr = document.createElement("tr");
+      tr.className = "selectable" + (t.tenantId === selectedTenant ? " selected" : "");
+      tr.append(codeCell(t.tenantId), cell(t.name), cell(t.endpointCount || 0),
+        cell(t.recentDeliveries || 0), cell(t.deadLettered || 0));
+      tr.addEventListener("click", () => selectTenant(t.tenantId).catch((e) => showError(e.message)));
+      body.appendChild(tr);
+    }
+  }
+
+  async function loadEndpoints() {
+    const data = await api("GET", "/v1/admin/tenants/" + encodeURIComponent(selectedTenant) + "/endpoints");
+    const body = $("#endpoints tbody");
+    body.replaceChildren();
+    for (const ep of data.endpoints || []) {
+      const ramp = ep.recoveryRamp || {};
+      const rampText = ramp.stepSeconds ? (ramp.percents || []).join("% → ") + "% every " + ramp.stepSeconds + "s" : "off";
+      const tr = document.createElement("tr");
+      tr.append(codeCell(ep.id), cell(ep.url), cell(rampText), cell(when(ep.createdAt)));
+      body.appendChild(tr);
+    }
+  }
+
+  async function loadDeliveries() {
+    const params = new URLSearchParams({ tenant: selectedTenant });
+    if ($("#status").value) params.set("status", $("#status").value);
+
+    const data = await api("GET", "/v1/admin/deliveries?" + params);
+    const body = $("#deliveries tbody");
+    body.replaceChildren();
+    for (const rd of data.deliveries || []) {
+      const d = rd.delivery || {};
+      const status = (d.status || "").replace(STATUS_PREFIX, "");
+      const tr = document.createElement("tr");
+      tr.append(codeCell(d.deliveryId), cell(rd.eventType), codeCell(d.endpointId),
+        cell(status, "status-" + status), cell(d.httpStatus || ""), cell(d.errorReason), cell(when(d.enqueuedAt)));
+
+      const action = document.createElement("td");
+      if (status === "FAILED" || status === "DEAD_LETTERED" || status === "PARKED") {
+        const btn = document.createElement("button");
+        btn.type = "button";
+        btn.textContent = "Replay";
+        btn.addEventListener("click", () => replay(d.deliveryId, btn));
+        action.appendChild(btn);
+      }
+      tr.appendChild(action);
+      body.appendChild(tr);
+    }
+  }
+
+  async function replay(deliveryId, btn) {
+    btn.disabled = true;
+    try {
+      const res = await api("POST", "/v1/deliveries/" + encodeURIComponent(deliveryId) + ":replay",
+        { reason: "replayed from admin console" });
+      btn.textContent = res.deduplicated ? "Already replaying" : "Replayed";
+      showError("");
+      await Promise.all([loadDeliveries(), loadTenants()]);
+    } catch (e) {
+      btn.disabled = false;
+      showError("Replay failed: " + e.message);
+    }
+  }
+
+  async function selectTenant(tenantId) {
+    selectedTenant = tenantId;
+    document.querySelectorAll(".tenant-id").forEach((el) => { el.textContent = tenantId; });
+    $("#tenant").hidden = false;
+    await Promise.all([loadEndpoints(), loadDeliveries(), loadTenants()]);
+  }
+
+  async function refresh() {
+    showError("");
+    try {
+      await loadTenants();
+      if (selectedTenant) await Promise.all([loadEndpoints(), loadDeliveries()]);
+    } catch (e) {
+      showError(e.message);
+    }
+  }
+
+  $("#login").addEventListener("submit", (ev) => {
+    ev.preventDefault();
+    sessionStorage.setItem(TOKEN_KEY, $("#token").value.trim());
+    $("#token").value = "";
+    refresh();
+  });
+  $("#logout").addEventListener("click", () => {
+    sessionStorage.removeItem(TOKEN_KEY);
+    selectedTenant = "";
+    $("#tenant").hidden = true;
+    $("#tenants tbody").replaceChildren();
+  });
+  $("#refresh").addEventListener("click", refresh);
+  $("#status").addEventListener("change", () => loadDeliveries().catch((e) => showError(e.message)));
+
+  fetch("/version").then((r) => r.json()).then((v) => { $("#build").textContent = v.version + " (" + v.gitCommit + ")"; }).catch(() => {});
+  if (token()) refresh();
+})();
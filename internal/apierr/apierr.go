@@ -0,0 +1,122 @@
+// Package apierr is the error model of the harborhook API. Handlers return typed errors for
+// what they detect themselves (bad input, missing or conflicting resources); the interceptors
+// classify everything else, such as database errors, and give every error a gRPC code and a
+// google.rpc.ErrorInfo detail. The gateway turns the code into the matching HTTP status.
+package apierr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Domain is the ErrorInfo domain of every harborhook error
+const Domain = "harborhook"
+
+// Reasons, the stable ErrorInfo reasons clients can branch on
+const (
+	ReasonInvalidArgument     = "INVALID_ARGUMENT"
+	ReasonNotFound            = "NOT_FOUND"
+	ReasonAlreadyExists       = "ALREADY_EXISTS"
+	ReasonPermissionDenied    = "PERMISSION_DENIED"
+	ReasonFailedPrecondition  = "FAILED_PRECONDITION"
+	ReasonUnavailable         = "UNAVAILABLE"
+	ReasonDatabaseUnavailable = "DATABASE_UNAVAILABLE"
+	ReasonInternal            = "INTERNAL"
+)
+
+// Error is an API error: a gRPC code, the reason reported in its ErrorInfo and a message.
+// Error() is the message alone, so it reads the same wrapped in other errors.
+type Error struct {
+	Code     codes.Code
+	Reason   string
+	Message  string
+	Metadata map[string]string // ErrorInfo metadata, e.g. the field that failed validation
+
+	// Violations are sent as a google.rpc.BadRequest detail
+	Violations []*errdetails.BadRequest_FieldViolation
+
+	cause error
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// Unwrap returns the error e was classified from, if any
+func (e *Error) Unwrap() error { return e.cause }
+
+// GRPCStatus is the status gRPC and the gateway send for e
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(e.Code, e.Message)
+	withInfo, err := st.WithDetails(&errdetails.ErrorInfo{Reason: e.Reason, Domain: Domain, Metadata: e.Metadata})
+	if err != nil {
+		return st
+	}
+	if len(e.Violations) == 0 {
+		return withInfo
+	}
+	withViolations, err := withInfo.WithDetails(&errdetails.BadRequest{FieldViolations: e.Violations})
+	if err != nil {
+		return withInfo
+	}
+	return withViolations
+}
+
+// WithMetadata adds key=value to e's ErrorInfo metadata and returns e
+func (e *Error) WithMetadata(key, value string) *Error {
+	if e.Metadata == nil {
+		e.Metadata = map[string]string{}
+	}
+	e.Metadata[key] = value
+	return e
+}
+
+// newError formats the message like fmt.Errorf, keeping a %w operand as the cause
+func newError(code codes.Code, reason, format string, args []any) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, Reason: reason, Message: err.Error(), cause: errors.Unwrap(err)}
+}
+
+// Invalid is an InvalidArgument error for a request that fails validation
+func Invalid(format string, args ...any) *Error {
+	return newError(codes.InvalidArgument, ReasonInvalidArgument, format, args)
+}
+
+// InvalidFields is an InvalidArgument error listing the fields of a request that fail validation.
+// The message names each field with its violation, e.g. "tenant_id is required".
+func InvalidFields(violations []*errdetails.BadRequest_FieldViolation) *Error {
+	msgs := make([]string, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.GetField() + " " + v.GetDescription()
+	}
+	return &Error{Code: codes.InvalidArgument, Reason: ReasonInvalidArgument, Message: strings.Join(msgs, "; "), Violations: violations}
+}
+
+// NotFound is a NotFound error for a resource that doesn't exist
+func NotFound(format string, args ...any) *Error {
+	return newError(codes.NotFound, ReasonNotFound, format, args)
+}
+
+// Conflict is an AlreadyExists error for a request that clashes with a stored resource
+func Conflict(format string, args ...any) *Error {
+	return newError(codes.AlreadyExists, ReasonAlreadyExists, format, args)
+}
+
+// PermissionDenied is a PermissionDenied error for a caller the token doesn't entitle to the request
+func PermissionDenied(format string, args ...any) *Error {
+	return newError(codes.PermissionDenied, ReasonPermissionDenied, format, args)
+}
+
+// FailedPrecondition is a FailedPrecondition error for a request the system isn't set up for
+// or the resource isn't in a state for
+func FailedPrecondition(format string, args ...any) *Error {
+	return newError(codes.FailedPrecondition, ReasonFailedPrecondition, format, args)
+}
+
+// Unavailable is an Unavailable error for a dependency that is down; clients may retry
+func Unavailable(format string, args ...any) *Error {
+	return newError(codes.Unavailable, ReasonUnavailable, format, args)
+}
@@ -0,0 +1,135 @@
+package apierr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorInfo returns the ErrorInfo detail of err's status
+func errorInfo(t *testing.T, err error) *errdetails.ErrorInfo {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("%v has no gRPC status", err)
+	}
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info
+		}
+	}
+	t.Fatalf("status of %v has no ErrorInfo", err)
+	return nil
+}
+
+func TestError(t *testing.T) {
+	err := Invalid("window_seconds must be between 0 and %d", 3600).WithMetadata("field", "window_seconds")
+	if got := err.Error(); got != "window_seconds must be between 0 and 3600" {
+		t.Errorf("Error() = %q, want the bare message", got)
+	}
+	wrapped := fmt.Errorf("stats: %w", err)
+	if got := status.Code(wrapped); got != codes.InvalidArgument {
+		t.Errorf("code of wrapped error = %v, want InvalidArgument", got)
+	}
+	info := errorInfo(t, err)
+	if info.GetDomain() != Domain || info.GetReason() != ReasonInvalidArgument || info.GetMetadata()["field"] != "window_seconds" {
+		t.Errorf("ErrorInfo = %+v, want domain %s, reason %s and the field", info, Domain, ReasonInvalidArgument)
+	}
+
+	cause := errors.New("parse error")
+	if err := Invalid("invalid url: %w", cause); !errors.Is(err, cause) || err.Error() != "invalid url: parse error" {
+		t.Errorf("Invalid with %%w = %v, want it to wrap the cause", err)
+	}
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantCode   codes.Code
+		wantReason string
+	}{
+		{name: "typed", err: Conflict("endpoint exists"), wantCode: codes.AlreadyExists, wantReason: ReasonAlreadyExists},
+		{name: "no rows", err: fmt.Errorf("lookup: %w", pgx.ErrNoRows), wantCode: codes.NotFound, wantReason: ReasonNotFound},
+		{name: "unique violation", err: &pgconn.PgError{Code: "23505"}, wantCode: codes.AlreadyExists, wantReason: ReasonAlreadyExists},
+		{name: "bad uuid", err: &pgconn.PgError{Code: "22P02"}, wantCode: codes.InvalidArgument, wantReason: ReasonInvalidArgument},
+		{name: "connection failure", err: fmt.Errorf("insert: %w", &pgconn.PgError{Code: "08006"}), wantCode: codes.Unavailable, wantReason: ReasonDatabaseUnavailable},
+		{name: "serialization failure", err: &pgconn.PgError{Code: "40001"}, wantCode: codes.Unavailable, wantReason: ReasonDatabaseUnavailable},
+		{name: "other postgres error", err: &pgconn.PgError{Code: "42P01"}, wantCode: codes.Internal, wantReason: ReasonInternal},
+		{name: "deadline", err: context.DeadlineExceeded, wantCode: codes.DeadlineExceeded, wantReason: "DEADLINE_EXCEEDED"},
+		{name: "status without details", err: status.Error(codes.PermissionDenied, "admin only"), wantCode: codes.PermissionDenied, wantReason: "PERMISSION_DENIED"},
+		{name: "status reason", err: status.Error(codes.ResourceExhausted, "quota"), wantCode: codes.ResourceExhausted, wantReason: "RESOURCE_EXHAUSTED"},
+		{name: "plain", err: errors.New("boom"), wantCode: codes.Internal, wantReason: ReasonInternal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := From(tt.err)
+			if got := status.Code(err); got != tt.wantCode {
+				t.Errorf("code = %v, want %v", got, tt.wantCode)
+			}
+			if got := errorInfo(t, err).GetReason(); got != tt.wantReason {
+				t.Errorf("reason = %q, want %q", got, tt.wantReason)
+			}
+		})
+	}
+	if From(nil) != nil {
+		t.Error("From(nil) != nil")
+	}
+}
+
+func TestFrom_KeepsDetails(t *testing.T) {
+	st, err := status.New(codes.InvalidArgument, "bad payload").WithDetails(&errdetails.BadRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := status.FromError(From(st.Err()))
+	if len(got.Details()) != 1 {
+		t.Errorf("details = %v, want the original BadRequest only", got.Details())
+	}
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{err: Invalid("tenant_id is required"), want: http.StatusBadRequest},
+		{err: NotFound("endpoint ep_1 not found"), want: http.StatusNotFound},
+		{err: Conflict("endpoint exists"), want: http.StatusConflict},
+		{err: PermissionDenied("tenant_id does not match token"), want: http.StatusForbidden},
+		{err: FailedPrecondition("request recording is not configured"), want: http.StatusBadRequest},
+		{err: From(&pgconn.PgError{Code: "57P01"}), want: http.StatusServiceUnavailable},
+		{err: From(errors.New("boom")), want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := runtime.HTTPStatusFromCode(status.Code(tt.err)); got != tt.want {
+			t.Errorf("HTTP status of %q = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := UnaryServerInterceptor()
+	_, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("lookup delivery: %w", pgx.ErrNoRows)
+	})
+	if got := status.Code(err); got != codes.NotFound {
+		t.Errorf("code = %v, want NotFound", got)
+	}
+	resp, err := intercept(context.Background(), nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	if err != nil || resp != "ok" {
+		t.Errorf("intercept() = %v, %v, want the handler's response", resp, err)
+	}
+}
@@ -0,0 +1,118 @@
+package apierr
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Postgres error classes and codes classified by From
+const (
+	pgClassConnection      = "08" // connection exception
+	pgClassResources       = "53" // insufficient resources
+	pgClassOperator        = "57" // operator intervention, e.g. admin shutdown
+	pgSerializationFailure = "40001"
+	pgDeadlockDetected     = "40P01"
+	pgUniqueViolation      = "23505"
+	pgForeignKeyViolation  = "23503"
+	pgCheckViolation       = "23514"
+	pgInvalidText          = "22P02" // e.g. an ID that isn't a UUID
+)
+
+// From returns err as an API error. Errors that already carry a gRPC status keep their code and
+// gain an ErrorInfo if they have none. The rest are classified: missing rows are NotFound,
+// unique violations AlreadyExists, malformed values InvalidArgument, and connection failures,
+// timeouts and retryable transaction aborts Unavailable. Anything else is Internal.
+func From(err error) error {
+	if err == nil {
+		return nil
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return err
+	}
+	if st, ok := status.FromError(err); ok {
+		if st.Code() == codes.OK || len(st.Details()) > 0 {
+			return err
+		}
+		withInfo, detailErr := st.WithDetails(&errdetails.ErrorInfo{Reason: reasonFor(st.Code()), Domain: Domain})
+		if detailErr != nil {
+			return err
+		}
+		return withInfo.Err()
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return &Error{Code: codes.Canceled, Reason: reasonFor(codes.Canceled), Message: err.Error(), cause: err}
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Code: codes.DeadlineExceeded, Reason: reasonFor(codes.DeadlineExceeded), Message: err.Error(), cause: err}
+	case errors.Is(err, pgx.ErrNoRows):
+		return &Error{Code: codes.NotFound, Reason: ReasonNotFound, Message: err.Error(), cause: err}
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		e := &Error{Code: codes.Internal, Reason: ReasonInternal, Message: err.Error(), cause: err}
+		switch {
+		case pgErr.Code == pgUniqueViolation:
+			e.Code, e.Reason = codes.AlreadyExists, ReasonAlreadyExists
+		case pgErr.Code == pgForeignKeyViolation:
+			e.Code, e.Reason = codes.NotFound, ReasonNotFound
+		case pgErr.Code == pgCheckViolation, pgErr.Code == pgInvalidText:
+			e.Code, e.Reason = codes.InvalidArgument, ReasonInvalidArgument
+		case pgErr.Code == pgSerializationFailure, pgErr.Code == pgDeadlockDetected,
+			strings.HasPrefix(pgErr.Code, pgClassConnection), strings.HasPrefix(pgErr.Code, pgClassResources),
+			strings.HasPrefix(pgErr.Code, pgClassOperator):
+			e.Code, e.Reason = codes.Unavailable, ReasonDatabaseUnavailable
+		}
+		return e
+	}
+
+	var connectErr *pgconn.ConnectError
+	if errors.As(err, &connectErr) || pgconn.Timeout(err) {
+		return &Error{Code: codes.Unavailable, Reason: ReasonDatabaseUnavailable, Message: err.Error(), cause: err}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &Error{Code: codes.Unavailable, Reason: ReasonUnavailable, Message: err.Error(), cause: err}
+	}
+	return &Error{Code: codes.Internal, Reason: ReasonInternal, Message: err.Error(), cause: err}
+}
+
+// reasonFor is the ErrorInfo reason of a status error that has none: its code in upper snake
+// case, e.g. PERMISSION_DENIED
+func reasonFor(c codes.Code) string {
+	var b strings.Builder
+	for i, r := range c.String() {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return strings.ToUpper(b.String())
+}
+
+// UnaryServerInterceptor passes every error a unary handler (or an inner interceptor) returns
+// through From. Chain it first so authentication errors get the same model.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, From(err)
+	}
+}
+
+// StreamServerInterceptor passes every error a streaming handler returns through From
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return From(handler(srv, ss))
+	}
+}
@@ -0,0 +1,161 @@
+// Package archive moves events and delivered deliveries older than a cutoff out of Postgres.
+// Each batch is written to an object store as gzipped JSON lines, one row per line, and deleted
+// in the same transaction that selected it, so rows are only deleted once their archive object
+// exists. A failed delete leaves the rows to be archived again by the next run.
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/db"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+)
+
+// Archived tables, the table label of the archive metrics and the first part of object keys
+const (
+	TableDeliveries = "deliveries"
+	TableEvents     = "events"
+)
+
+// archiveDeliveriesQuery selects delivered deliveries finished before $1, each with its attempt
+// history, since the attempts are deleted with it
+const archiveDeliveriesQuery = `
+	SELECT d.id::text,
+	       (to_jsonb(d) || jsonb_build_object('attempts', COALESCE((
+	           SELECT jsonb_agg(to_jsonb(a) - 'delivery_id' ORDER BY a.attempt)
+	           FROM harborhook.delivery_attempts a
+	           WHERE a.delivery_id = d.id), '[]'::jsonb)))::text
+	FROM harborhook.deliveries d
+	WHERE d.status = 'delivered' AND d.delivered_at < $1
+	ORDER BY d.delivered_at
+	LIMIT $2
+	FOR UPDATE OF d SKIP LOCKED`
+
+// archiveEventsQuery selects published events created before $1 that have no deliveries left.
+// Events with deliveries that never succeeded stay, so failed and dead-lettered deliveries keep
+// their payload.
+const archiveEventsQuery = `
+	SELECT e.id::text, to_jsonb(e)::text
+	FROM harborhook.events e
+	WHERE e.status = 'published' AND e.created_at < $1
+	  AND NOT EXISTS (SELECT 1 FROM harborhook.deliveries d WHERE d.event_id = e.id)
+	ORDER BY e.created_at
+	LIMIT $2
+	FOR UPDATE OF e SKIP LOCKED`
+
+// Result counts the rows a run archived and deleted
+type Result struct {
+	Deliveries int64
+	Events     int64
+}
+
+// Archiver archives rows older than its retention to a Sink
+type Archiver struct {
+	pool  db.Pool
+	sink  Sink
+	after time.Duration
+	batch int
+	now   func() time.Time
+}
+
+// New returns an archiver for rows older than after, written batch rows per object
+func New(pool db.Pool, sink Sink, after time.Duration, batch int) *Archiver {
+	if batch <= 0 {
+		batch = 1000
+	}
+	return &Archiver{pool: pool, sink: sink, after: after, batch: batch, now: time.Now}
+}
+
+// Run archives everything past the cutoff, delivered deliveries first so their events can
+// follow in the same run
+func (a *Archiver) Run(ctx context.Context) (Result, error) {
+	cutoff := a.now().Add(-a.after)
+	var res Result
+	for _, t := range []struct {
+		table string
+		query string
+		count *int64
+	}{
+		{TableDeliveries, archiveDeliveriesQuery, &res.Deliveries},
+		{TableEvents, archiveEventsQuery, &res.Events},
+	} {
+		for {
+			n, err := a.archiveBatch(ctx, t.table, t.query, cutoff)
+			*t.count += n
+			if err != nil {
+				return res, fmt.Errorf("archive %s: %w", t.table, err)
+			}
+			if n < int64(a.batch) {
+				break
+			}
+		}
+	}
+	return res, nil
+}
+
+// archiveBatch archives and deletes up to one batch of table's rows, returning how many
+func (a *Archiver) archiveBatch(ctx context.Context, table, query string, cutoff time.Time) (int64, error) {
+	tx, err := a.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	rows, err := tx.Query(ctx, query, cutoff, a.batch)
+	if err != nil {
+		return 0, fmt.Errorf("select: %w", err)
+	}
+	var (
+		ids []string
+		buf bytes.Buffer
+	)
+	zw := gzip.NewWriter(&buf)
+	for rows.Next() {
+		var id, doc string
+		if err := rows.Scan(&id, &doc); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		ids = append(ids, id)
+		_, _ = zw.Write([]byte(doc))
+		_, _ = zw.Write([]byte{'\n'})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	if err := zw.Close(); err != nil {
+		return 0, err
+	}
+
+	key := objectKey(table, a.now(), ids[0])
+	if err := a.sink.PutObject(ctx, key, "application/gzip", buf.Bytes()); err != nil {
+		return 0, fmt.Errorf("write %s: %w", key, err)
+	}
+	archived := int64(len(ids))
+
+	tag, err := tx.Exec(ctx, `DELETE FROM harborhook.`+table+` WHERE id = ANY($1::uuid[])`, ids)
+	if err == nil {
+		err = tx.Commit(ctx)
+	}
+	if err != nil {
+		metrics.RecordArchive(table, archived, 0)
+		return 0, fmt.Errorf("delete archived rows: %w", err)
+	}
+	metrics.RecordArchive(table, archived, tag.RowsAffected())
+	return archived, nil
+}
+
+// objectKey names a batch's object table/yyyy/mm/dd/<time>-<first id>.jsonl.gz, so objects
+// list in the order they were written and never collide
+func objectKey(table string, at time.Time, firstID string) string {
+	at = at.UTC()
+	return fmt.Sprintf("%s/%s/%s-%s.jsonl.gz", table, at.Format("2006/01/02"), at.Format("20060102T150405.000000000Z"), firstID)
+}
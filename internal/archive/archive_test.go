@@ -0,0 +1,154 @@
+package archive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+)
+
+// memSink keeps objects in memory, or fails every write with err
+type memSink struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (m *memSink) PutObject(_ context.Context, key, _ string, body []byte) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.objects[key] = body
+	return nil
+}
+
+// lines decompresses an archive object into its JSON lines
+func lines(t *testing.T, body []byte) []string {
+	t.Helper()
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("archive object is not gzip: %v", err)
+	}
+	b, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatalf("read archive object: %v", err)
+	}
+	return strings.Split(strings.TrimSuffix(string(b), "\n"), "\n")
+}
+
+func TestArchiver_Run(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	deliveryBatches := [][][]any{
+		{{"del_1", `{"id":"del_1"}`}, {"del_2", `{"id":"del_2"}`}},
+		{{"del_3", `{"id":"del_3"}`}},
+	}
+	var cutoff time.Time
+	var deleted []string
+	pool := &dbfake.Pool{
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			cutoff = args[0].(time.Time)
+			if strings.Contains(sql, "FROM harborhook.deliveries d") && strings.Contains(sql, "delivered_at < $1") {
+				batch := deliveryBatches[0]
+				deliveryBatches = deliveryBatches[1:]
+				return dbfake.NewRows(batch...), nil
+			}
+			return dbfake.NewRows([]any{"evt_1", `{"id":"evt_1"}`}), nil
+		},
+		ExecFunc: func(sql string, args []any) (pgconn.CommandTag, error) {
+			ids := args[0].([]string)
+			deleted = append(deleted, strings.Fields(sql)[2]+":"+strings.Join(ids, ","))
+			return pgconn.NewCommandTag(fmt.Sprintf("DELETE %d", len(ids))), nil
+		},
+	}
+	sink := &memSink{objects: map[string][]byte{}}
+	a := New(pool, sink, 30*24*time.Hour, 2)
+	a.now = func() time.Time { return now }
+
+	res, err := a.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() unexpected error: %v", err)
+	}
+	if res != (Result{Deliveries: 3, Events: 1}) {
+		t.Errorf("Run() = %+v, want 3 deliveries and 1 event", res)
+	}
+	if want := now.Add(-30 * 24 * time.Hour); !cutoff.Equal(want) {
+		t.Errorf("cutoff = %v, want %v", cutoff, want)
+	}
+	wantDeleted := []string{"harborhook.deliveries:del_1,del_2", "harborhook.deliveries:del_3", "harborhook.events:evt_1"}
+	if strings.Join(deleted, " ") != strings.Join(wantDeleted, " ") {
+		t.Errorf("deleted %v, want %v", deleted, wantDeleted)
+	}
+
+	key := "deliveries/2026/03/01/20260301T120000.000000000Z-del_1.jsonl.gz"
+	if got := lines(t, sink.objects[key]); len(got) != 2 || got[1] != `{"id":"del_2"}` {
+		t.Errorf("object %s = %v, want both deliveries as JSON lines (objects %d)", key, got, len(sink.objects))
+	}
+	if len(sink.objects) != 3 {
+		t.Errorf("wrote %d objects, want one per batch", len(sink.objects))
+	}
+}
+
+func TestArchiver_Run_SinkError(t *testing.T) {
+	pool := &dbfake.Pool{
+		QueryFunc: func(string, []any) (pgx.Rows, error) {
+			return dbfake.NewRows([]any{"del_1", `{"id":"del_1"}`}), nil
+		},
+		ExecFunc: func(sql string, _ []any) (pgconn.CommandTag, error) {
+			t.Errorf("rows deleted after the archive write failed: %s", sql)
+			return pgconn.CommandTag{}, nil
+		},
+	}
+	a := New(pool, &memSink{err: errors.New("access denied")}, time.Hour, 10)
+
+	if _, err := a.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "access denied") {
+		t.Errorf("Run() error = %v, want the write error", err)
+	}
+}
+
+func TestDir_PutObject(t *testing.T) {
+	root := t.TempDir()
+	if err := NewDir(root).PutObject(context.Background(), "events/2026/03/01/a.jsonl.gz", "application/gzip", []byte("gz")); err != nil {
+		t.Fatalf("PutObject() unexpected error: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(root, "events", "2026", "03", "01", "a.jsonl.gz"))
+	if err != nil || string(b) != "gz" {
+		t.Errorf("object = %q, %v, want the body", b, err)
+	}
+	if left, _ := filepath.Glob(filepath.Join(root, "events", "2026", "03", "01", ".archive-*")); len(left) != 0 {
+		t.Errorf("temporary files left behind: %v", left)
+	}
+}
+
+func TestNewSink(t *testing.T) {
+	tests := []struct {
+		cfg     config.Archive
+		wantErr string
+	}{
+		{cfg: config.Archive{Store: "azure"}, wantErr: "unknown archive store"},
+		{cfg: config.Archive{Store: StoreS3}, wantErr: "ARCHIVE_BUCKET"},
+		{cfg: config.Archive{Store: StoreFile}, wantErr: "ARCHIVE_DIR"},
+		{cfg: config.Archive{Store: StoreFile, Dir: t.TempDir()}},
+		{cfg: config.Archive{Store: StoreGCS, Bucket: "hh-archive"}},
+	}
+	for _, tt := range tests {
+		_, err := NewSink(context.Background(), tt.cfg)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("NewSink(%+v) unexpected error: %v", tt.cfg, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("NewSink(%+v) error = %v, want %q", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/austindbirch/harbor_hook/internal/blobstore"
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+// Supported archive stores
+const (
+	StoreS3   = "s3"
+	StoreGCS  = "gcs"
+	StoreFile = "file"
+)
+
+// gcsEndpoint is GCS's XML API, which accepts S3 requests signed with an HMAC key
+const gcsEndpoint = "https://storage.googleapis.com"
+
+// Sink stores archive objects. blobstore.S3 is one, for S3 and GCS; Dir writes local files.
+type Sink interface {
+	// PutObject writes body to the object named key
+	PutObject(ctx context.Context, key, contentType string, body []byte) error
+}
+
+// NewSink returns the configured archive store. The s3 and gcs stores sign requests with the
+// AWS default credential chain; for GCS that is an HMAC key set as AWS_ACCESS_KEY_ID and
+// AWS_SECRET_ACCESS_KEY.
+func NewSink(ctx context.Context, cfg config.Archive) (Sink, error) {
+	switch cfg.Store {
+	case StoreS3, StoreGCS:
+		if cfg.Bucket == "" {
+			return nil, fmt.Errorf("ARCHIVE_BUCKET is required for the %s archive store", cfg.Store)
+		}
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("load aws config: %w", err)
+		}
+		endpoint, region := cfg.Endpoint, cfg.Region
+		if cfg.Store == StoreGCS {
+			if endpoint == "" {
+				endpoint = gcsEndpoint
+			}
+			if region == "" {
+				region = "auto"
+			}
+		}
+		if region == "" {
+			region = awsCfg.Region
+		}
+		if region == "" {
+			return nil, errors.New("no AWS region configured for the s3 archive store (set ARCHIVE_REGION)")
+		}
+		return blobstore.NewS3(endpoint, region, cfg.Bucket, cfg.Prefix, awsCfg.Credentials), nil
+	case StoreFile:
+		if cfg.Dir == "" {
+			return nil, errors.New("ARCHIVE_DIR is required for the file archive store")
+		}
+		return NewDir(filepath.Join(cfg.Dir, filepath.FromSlash(cfg.Prefix))), nil
+	default:
+		return nil, fmt.Errorf("unknown archive store %q (want %s, %s or %s)", cfg.Store, StoreS3, StoreGCS, StoreFile)
+	}
+}
+
+// Dir writes archive objects as files under a directory, for development or a mounted volume
+type Dir struct {
+	root string
+}
+
+// NewDir returns a sink writing objects under root
+func NewDir(root string) *Dir {
+	return &Dir{root: root}
+}
+
+// PutObject writes body to root/key, through a temporary file so a partly written object is
+// never left behind under its name
+func (d *Dir) PutObject(_ context.Context, key, _ string, body []byte) error {
+	path := filepath.Join(d.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), ".archive-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
@@ -0,0 +1,47 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// NewJWTValidatorFromEnv builds the validator a service is configured with: JWT_ISSUER,
+// JWT_AUDIENCE (default harborhook-api), the key from JWT_PUBLIC_KEY_PATH or JWT_JWKS_URL,
+// JWT_TRUST_TENANT_HEADER and ADMIN_TENANT_ID. It returns nil when JWT_ISSUER is unset, meaning
+// requests aren't authenticated. A JWKS is fetched before returning and then refreshed until ctx
+// is done; onRefreshError, if non-nil, is called with every failed refresh.
+func NewJWTValidatorFromEnv(ctx context.Context, onRefreshError func(error)) (*JWTValidator, error) {
+	issuer := os.Getenv("JWT_ISSUER")
+	if issuer == "" {
+		return nil, nil
+	}
+	audience := os.Getenv("JWT_AUDIENCE")
+	if audience == "" {
+		audience = "harborhook-api"
+	}
+
+	var v *JWTValidator
+	switch {
+	case os.Getenv("JWT_PUBLIC_KEY_PATH") != "":
+		keyPEM, err := os.ReadFile(os.Getenv("JWT_PUBLIC_KEY_PATH"))
+		if err != nil {
+			return nil, err
+		}
+		if v, err = NewJWTValidator(string(keyPEM), issuer, audience); err != nil {
+			return nil, err
+		}
+	case os.Getenv("JWT_JWKS_URL") != "":
+		keys := NewKeySet(os.Getenv("JWT_JWKS_URL"))
+		if err := keys.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		go keys.Run(ctx, onRefreshError)
+		v = NewJWTValidatorFromJWKS(keys, issuer, audience)
+	default:
+		return nil, errors.New("JWT_ISSUER set but neither JWT_PUBLIC_KEY_PATH nor JWT_JWKS_URL provided")
+	}
+	v.TrustTenantHeader(os.Getenv("JWT_TRUST_TENANT_HEADER") == "true")
+	v.SetAdminTenant(os.Getenv("ADMIN_TENANT_ID"))
+	return v, nil
+}
@@ -0,0 +1,204 @@
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultJWKSRefresh = 5 * time.Minute
+	minJWKSRefresh     = 30 * time.Second
+	maxJWKSRefresh     = time.Hour
+	jwksRefreshJitter  = 0.1 // +/- 10% so replicas don't refresh in lockstep
+)
+
+// KeySet is a cached JWKS keyed by kid. It is refreshed in the background and
+// on demand when a token references a kid it has not seen (key rotation).
+type KeySet struct {
+	url    string
+	client *http.Client
+
+	mu          sync.RWMutex
+	keys        map[string]*rsa.PublicKey
+	etag        string
+	maxAge      time.Duration // from Cache-Control; 0 means use the default interval
+	lastRefresh time.Time
+}
+
+// NewKeySet creates an empty KeySet for jwksURL. Call Refresh before using it
+func NewKeySet(jwksURL string) *KeySet {
+	return &KeySet{
+		url:    jwksURL,
+		client: &http.Client{Timeout: 10 * time.Second},
+		keys:   map[string]*rsa.PublicKey{},
+	}
+}
+
+// Refresh fetches the JWKS, sending the last ETag so an unchanged set costs a 304
+func (ks *KeySet) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return err
+	}
+	ks.mu.RLock()
+	if ks.etag != "" {
+		req.Header.Set("If-None-Match", ks.etag)
+	}
+	ks.mu.RUnlock()
+
+	resp, err := ks.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch JWKS: %v", err)
+	}
+	defer resp.Body.Close()
+
+	maxAge := parseMaxAge(resp.Header.Get("Cache-Control"))
+	if resp.StatusCode == http.StatusNotModified {
+		ks.mu.Lock()
+		ks.maxAge = maxAge
+		ks.lastRefresh = time.Now()
+		ks.mu.Unlock()
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("JWKS endpoint returned status %d", resp.StatusCode)
+	}
+
+	var jwks JSONWebKeySet
+	if err := json.NewDecoder(resp.Body).Decode(&jwks); err != nil {
+		return fmt.Errorf("failed to decode JWKS: %v", err)
+	}
+	keys, err := jwks.rsaSigningKeys()
+	if err != nil {
+		return err
+	}
+
+	ks.mu.Lock()
+	ks.keys = keys
+	ks.etag = resp.Header.Get("ETag")
+	ks.maxAge = maxAge
+	ks.lastRefresh = time.Now()
+	ks.mu.Unlock()
+	return nil
+}
+
+// Key returns the public key for kid. An empty kid is accepted when the set holds a single key.
+// An unknown kid triggers one refresh, rate limited to the minimum refresh interval.
+func (ks *KeySet) Key(kid string) (*rsa.PublicKey, error) {
+	if key, ok := ks.lookup(kid); ok {
+		return key, nil
+	}
+
+	ks.mu.RLock()
+	stale := time.Since(ks.lastRefresh) >= minJWKSRefresh
+	ks.mu.RUnlock()
+	if stale {
+		if err := ks.Refresh(context.Background()); err == nil {
+			if key, ok := ks.lookup(kid); ok {
+				return key, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (ks *KeySet) lookup(kid string) (*rsa.PublicKey, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	if kid == "" {
+		if len(ks.keys) != 1 {
+			return nil, false
+		}
+		for _, key := range ks.keys {
+			return key, true
+		}
+	}
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// Run refreshes the key set until ctx is cancelled. The interval follows the server's
+// Cache-Control max-age (clamped), with jitter; failures retry at the minimum interval.
+// onError, if non-nil, is called with every failed refresh.
+func (ks *KeySet) Run(ctx context.Context, onError func(error)) {
+	wait := ks.nextRefresh(false)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		err := ks.Refresh(ctx)
+		if err != nil && onError != nil {
+			onError(err)
+		}
+		wait = ks.nextRefresh(err != nil)
+	}
+}
+
+// nextRefresh returns how long to wait before the next background refresh
+func (ks *KeySet) nextRefresh(failed bool) time.Duration {
+	ks.mu.RLock()
+	d := ks.maxAge
+	ks.mu.RUnlock()
+
+	switch {
+	case failed:
+		d = minJWKSRefresh
+	case d == 0:
+		d = defaultJWKSRefresh
+	case d < minJWKSRefresh:
+		d = minJWKSRefresh
+	case d > maxJWKSRefresh:
+		d = maxJWKSRefresh
+	}
+	j := 1 + (rand.Float64()*2-1)*jwksRefreshJitter
+	return time.Duration(float64(d) * j)
+}
+
+// parseMaxAge extracts max-age from a Cache-Control header. no-cache/no-store map to the minimum interval
+func parseMaxAge(cacheControl string) time.Duration {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-cache" || directive == "no-store":
+			return minJWKSRefresh
+		case strings.HasPrefix(directive, "max-age="):
+			if secs, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age=")); err == nil && secs >= 0 {
+				return time.Duration(secs) * time.Second
+			}
+		}
+	}
+	return 0
+}
+
+// rsaSigningKeys decodes every RSA signing key in the set, keyed by kid
+func (jwks JSONWebKeySet) rsaSigningKeys() (map[string]*rsa.PublicKey, error) {
+	if len(jwks.Keys) == 0 {
+		return nil, fmt.Errorf("no keys found in JWKS")
+	}
+	keys := make(map[string]*rsa.PublicKey, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		pub, err := k.RSAPublicKey()
+		if err != nil {
+			return nil, err
+		}
+		keys[k.Kid] = pub
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no RSA signing key found in JWKS")
+	}
+	return keys, nil
+}
@@ -0,0 +1,185 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// jwksServer serves the given keys with an ETag, counting full (200) responses
+func jwksServer(t *testing.T, keys *atomic.Value, fullResponses *atomic.Int32) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		set := keys.Load().(JSONWebKeySet)
+		etag := `"` + set.Keys[0].Kid + `"`
+		w.Header().Set("Cache-Control", "public, max-age=120")
+		w.Header().Set("ETag", etag)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses.Add(1)
+		json.NewEncoder(w).Encode(set)
+	}))
+}
+
+func TestKeySet_Refresh(t *testing.T) {
+	k1, k2 := testKey(t), testKey(t)
+	var keys atomic.Value
+	keys.Store(JSONWebKeySet{Keys: []JSONWebKey{testJWK(&k1.PublicKey, "k1"), testJWK(&k2.PublicKey, "k2")}})
+	var full atomic.Int32
+	srv := jwksServer(t, &keys, &full)
+	defer srv.Close()
+
+	ks := NewKeySet(srv.URL)
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+
+	for _, kid := range []string{"k1", "k2"} {
+		if _, err := ks.Key(kid); err != nil {
+			t.Errorf("Key(%q) error: %v", kid, err)
+		}
+	}
+	if _, err := ks.Key(""); err == nil {
+		t.Error("Key(\"\") with two keys expected error but got none")
+	}
+	if ks.maxAge != 2*time.Minute {
+		t.Errorf("maxAge = %v, want 2m from Cache-Control", ks.maxAge)
+	}
+
+	// Second refresh revalidates with the ETag and keeps the cached keys
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh() revalidation error: %v", err)
+	}
+	if full.Load() != 1 {
+		t.Errorf("full JWKS responses = %d, want 1 (second should be 304)", full.Load())
+	}
+	if _, err := ks.Key("k2"); err != nil {
+		t.Errorf("Key(k2) after 304 error: %v", err)
+	}
+}
+
+func TestKeySet_UnknownKidRefreshes(t *testing.T) {
+	k1, k2 := testKey(t), testKey(t)
+	var keys atomic.Value
+	keys.Store(JSONWebKeySet{Keys: []JSONWebKey{testJWK(&k1.PublicKey, "k1")}})
+	var full atomic.Int32
+	srv := jwksServer(t, &keys, &full)
+	defer srv.Close()
+
+	ks := NewKeySet(srv.URL)
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Issuer rotates to k2
+	keys.Store(JSONWebKeySet{Keys: []JSONWebKey{testJWK(&k2.PublicKey, "k2")}})
+
+	// Within the minimum interval the unknown kid is rejected without hitting the server
+	if _, err := ks.Key("k2"); err == nil {
+		t.Error("Key(k2) right after refresh expected error but got none")
+	}
+
+	ks.lastRefresh = time.Now().Add(-minJWKSRefresh)
+	key, err := ks.Key("k2")
+	if err != nil {
+		t.Fatalf("Key(k2) after rotation error: %v", err)
+	}
+	if !key.Equal(&k2.PublicKey) {
+		t.Error("Key(k2) returned the wrong key")
+	}
+}
+
+func TestKeySet_NextRefresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		failed bool
+		want   time.Duration
+	}{
+		{name: "default interval", want: defaultJWKSRefresh},
+		{name: "follows max-age", maxAge: 10 * time.Minute, want: 10 * time.Minute},
+		{name: "clamped to minimum", maxAge: time.Second, want: minJWKSRefresh},
+		{name: "clamped to maximum", maxAge: 24 * time.Hour, want: maxJWKSRefresh},
+		{name: "retry soon after failure", maxAge: 10 * time.Minute, failed: true, want: minJWKSRefresh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks := &KeySet{maxAge: tt.maxAge}
+			got := ks.nextRefresh(tt.failed)
+			lo := time.Duration(float64(tt.want) * (1 - jwksRefreshJitter))
+			hi := time.Duration(float64(tt.want) * (1 + jwksRefreshJitter))
+			if got < lo || got > hi {
+				t.Errorf("nextRefresh() = %v, want within [%v, %v]", got, lo, hi)
+			}
+		})
+	}
+}
+
+func TestParseMaxAge(t *testing.T) {
+	tests := []struct {
+		header string
+		want   time.Duration
+	}{
+		{"public, max-age=300", 5 * time.Minute},
+		{"max-age=0", 0},
+		{"no-store", minJWKSRefresh},
+		{"private", 0},
+		{"max-age=abc", 0},
+		{"", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseMaxAge(tt.header); got != tt.want {
+			t.Errorf("parseMaxAge(%q) = %v, want %v", tt.header, got, tt.want)
+		}
+	}
+}
+
+func TestJWTValidator_FromJWKS(t *testing.T) {
+	k1, k2 := testKey(t), testKey(t)
+	var keys atomic.Value
+	keys.Store(JSONWebKeySet{Keys: []JSONWebKey{testJWK(&k1.PublicKey, "k1"), testJWK(&k2.PublicKey, "k2")}})
+	var full atomic.Int32
+	srv := jwksServer(t, &keys, &full)
+	defer srv.Close()
+
+	ks := NewKeySet(srv.URL)
+	if err := ks.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	validator := NewJWTValidatorFromJWKS(ks, "harborhook", "harborhook-api")
+
+	sign := func(key any, kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":       "harborhook",
+			"aud":       "harborhook-api",
+			"tenant_id": "tn_123",
+			"exp":       time.Now().Add(time.Hour).Unix(),
+		})
+		token.Header["kid"] = kid
+		s, err := token.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	if tenant, err := validator.ValidateToken(sign(k2, "k2")); err != nil || tenant != "tn_123" {
+		t.Errorf("ValidateToken(k2) = %q, %v; want tn_123", tenant, err)
+	}
+	if _, err := validator.ValidateToken(sign(k1, "k2")); err == nil {
+		t.Error("ValidateToken() with mismatched kid expected error but got none")
+	}
+	if _, err := validator.ValidateToken(sign(k1, "unknown")); err == nil {
+		t.Error("ValidateToken() with unknown kid expected error but got none")
+	}
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"crypto/rsa"
 	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"math/big"
 	"net/http"
 	"strings"
 
@@ -25,8 +27,13 @@ const TenantIDKey contextKey = "tenant_id"
 // JWTValidator handles JWT token validation
 type JWTValidator struct {
 	publicKey *rsa.PublicKey
+	keys      *KeySet // when set, keys are selected by the token's kid
 	issuer    string
 	audience  string
+	// trustTenantHeader accepts x-tenant-id, x-subject and x-roles as already validated. Only safe behind Envoy.
+	trustTenantHeader bool
+	// adminTenant's tokens without a roles claim are admins
+	adminTenant string
 }
 
 // NewJWTValidator creates a new JWT validator
@@ -51,69 +58,193 @@ func NewJWTValidator(publicKeyPEM, issuer, audience string) (*JWTValidator, erro
 		}
 	}
 
+	return NewJWTValidatorFromKey(publicKey, issuer, audience), nil
+}
+
+// NewJWTValidatorFromKey creates a JWT validator from an already parsed RSA public key
+func NewJWTValidatorFromKey(publicKey *rsa.PublicKey, issuer, audience string) *JWTValidator {
 	return &JWTValidator{
 		publicKey: publicKey,
 		issuer:    issuer,
 		audience:  audience,
-	}, nil
+	}
+}
+
+// NewJWTValidatorFromJWKS creates a JWT validator that selects keys from a refreshing JWKS
+func NewJWTValidatorFromJWKS(keys *KeySet, issuer, audience string) *JWTValidator {
+	return &JWTValidator{
+		keys:     keys,
+		issuer:   issuer,
+		audience: audience,
+	}
+}
+
+// TrustTenantHeader controls whether x-tenant-id, x-subject and x-roles headers set by Envoy are accepted
+// in place of a token. Leave it off when the service is reachable without Envoy.
+func (v *JWTValidator) TrustTenantHeader(trust bool) {
+	v.trustTenantHeader = trust
+}
+
+// SetAdminTenant makes tokens of tenantID that carry no roles claim admins, as they were before
+// roles existed. Tokens with roles get exactly the roles they name.
+func (v *JWTValidator) SetAdminTenant(tenantID string) {
+	v.adminTenant = tenantID
 }
 
 // ValidateToken validates a JWT token and returns the tenant ID
 func (v *JWTValidator) ValidateToken(tokenString string) (string, error) {
+	p, err := v.ValidateClaims(tokenString)
+	if err != nil {
+		return "", err
+	}
+	return p.TenantID, nil
+}
+
+// RoleScopePrefix marks scopes that grant a role, e.g. harborhook:viewer. Identity providers
+// that can't add a roles claim can grant roles through the scope claim instead.
+const RoleScopePrefix = "harborhook:"
+
+// ValidateClaims validates a JWT token and returns the caller it authenticates. The roles claim
+// is a list of role names (or one space-separated string); the strongest one applies. Without
+// it, role scopes in the scope claim grant the roles; other scopes are ignored.
+func (v *JWTValidator) ValidateClaims(tokenString string) (Principal, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodRSA); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
+		if v.keys != nil {
+			kid, _ := token.Header["kid"].(string)
+			return v.keys.Key(kid)
+		}
 		return v.publicKey, nil
 	})
 
 	if err != nil {
-		return "", fmt.Errorf("failed to parse token: %v", err)
+		return Principal{}, fmt.Errorf("failed to parse token: %v", err)
 	}
 
 	if !token.Valid {
-		return "", fmt.Errorf("invalid token")
+		return Principal{}, fmt.Errorf("invalid token")
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", fmt.Errorf("invalid claims")
+		return Principal{}, fmt.Errorf("invalid claims")
 	}
 
 	// Validate issuer
 	if iss, ok := claims["iss"].(string); !ok || iss != v.issuer {
-		return "", fmt.Errorf("invalid issuer")
+		return Principal{}, fmt.Errorf("invalid issuer")
 	}
 
 	// Validate audience
 	if aud, ok := claims["aud"].(string); !ok || aud != v.audience {
-		return "", fmt.Errorf("invalid audience")
+		return Principal{}, fmt.Errorf("invalid audience")
 	}
 
 	// Extract tenant ID
 	tenantID, ok := claims["tenant_id"].(string)
 	if !ok || tenantID == "" {
-		return "", fmt.Errorf("missing or invalid tenant_id claim")
+		return Principal{}, fmt.Errorf("missing or invalid tenant_id claim")
+	}
+
+	subject, _ := claims["sub"].(string)
+	names, err := roleNames(claims["roles"])
+	if err != nil {
+		return Principal{}, err
+	}
+	if names == nil {
+		names = scopeRoles(claims["scope"])
 	}
+	if names == nil {
+		p := legacyPrincipal(tenantID, v.adminTenant)
+		p.Subject = subject
+		return p, nil
+	}
+	role, err := highestRole(names)
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid roles claim: %v", err)
+	}
+	return Principal{TenantID: tenantID, Role: role, Subject: subject}, nil
+}
+
+// roleNames reads a roles claim; nil means the token has none
+func roleNames(claim interface{}) ([]string, error) {
+	switch c := claim.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return append([]string{}, strings.Fields(c)...), nil
+	case []interface{}:
+		names := make([]string, 0, len(c))
+		for _, n := range c {
+			s, ok := n.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid roles claim")
+			}
+			names = append(names, s)
+		}
+		return names, nil
+	default:
+		return nil, fmt.Errorf("invalid roles claim")
+	}
+}
+
+// scopeRoles returns the roles a scope claim's role scopes name; nil means it names none
+func scopeRoles(claim interface{}) []string {
+	scope, _ := claim.(string)
+	var names []string
+	for _, s := range strings.Fields(scope) {
+		if r, ok := strings.CutPrefix(s, RoleScopePrefix); ok {
+			names = append(names, r)
+		}
+	}
+	return names
+}
 
-	return tenantID, nil
+// headerPrincipal is the caller Envoy vouches for with x-tenant-id, x-subject and, when the token
+// has roles, a comma-separated x-roles
+func (v *JWTValidator) headerPrincipal(tenantID, subject string, roles []string) (Principal, error) {
+	var names []string
+	for _, r := range roles {
+		for _, n := range strings.Split(r, ",") {
+			if n = strings.TrimSpace(n); n != "" {
+				names = append(names, n)
+			}
+		}
+	}
+	if len(names) == 0 {
+		p := legacyPrincipal(tenantID, v.adminTenant)
+		p.Subject = subject
+		return p, nil
+	}
+	role, err := highestRole(names)
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid x-roles header: %v", err)
+	}
+	return Principal{TenantID: tenantID, Role: role, Subject: subject}, nil
 }
 
 // HTTPMiddleware returns an HTTP middleware that validates JWT tokens
 func (v *JWTValidator) HTTPMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip auth for health checks and ping endpoints
-		if r.URL.Path == "/healthz" || r.URL.Path == "/v1/ping" {
+		// Skip auth for health checks, ping, receiver acks (authenticated by their signed receipt)
+		// and tenants' public signing keys
+		if r.URL.Path == "/healthz" || r.URL.Path == "/v1/ping" || isReceiptPath(r.URL.Path) || isSigningKeysPath(r.URL.Path) {
 			next.ServeHTTP(w, r)
 			return
 		}
 
 		// Check for tenant ID header (set by Envoy)
 		tenantID := r.Header.Get("x-tenant-id")
-		if tenantID != "" {
+		if tenantID != "" && v.trustTenantHeader {
 			// If Envoy already validated and set tenant ID, use it
-			ctx := context.WithValue(r.Context(), TenantIDKey, tenantID)
-			next.ServeHTTP(w, r.WithContext(ctx))
+			p, err := v.headerPrincipal(tenantID, r.Header.Get("x-subject"), r.Header.Values("x-roles"))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
 			return
 		}
 
@@ -130,55 +261,142 @@ func (v *JWTValidator) HTTPMiddleware(next http.Handler) http.Handler {
 			return
 		}
 
-		tenantID, err := v.ValidateToken(tokenString)
+		p, err := v.ValidateClaims(tokenString)
 		if err != nil {
 			http.Error(w, fmt.Sprintf("Invalid token: %v", err), http.StatusUnauthorized)
 			return
 		}
 
-		ctx := context.WithValue(r.Context(), TenantIDKey, tenantID)
-		next.ServeHTTP(w, r.WithContext(ctx))
+		next.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
 	})
 }
 
-// GRPCInterceptor returns a gRPC unary interceptor that validates JWT tokens
+// GRPCInterceptor returns a gRPC unary interceptor that validates JWT tokens and authorizes the
+// caller's role for the method
 func (v *JWTValidator) GRPCInterceptor() grpc.UnaryServerInterceptor {
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		// Skip auth for health checks
-		if strings.Contains(info.FullMethod, "Health") {
+		// Skip auth for health checks, ping, receiver acks (authenticated by their signed receipt)
+		// and tenants' public signing keys
+		if strings.Contains(info.FullMethod, "Health") || strings.HasSuffix(info.FullMethod, "/Ping") ||
+			strings.HasSuffix(info.FullMethod, "/AcknowledgeDelivery") || strings.HasSuffix(info.FullMethod, "/GetSigningKeys") {
 			return handler(ctx, req)
 		}
 
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Errorf(codes.Unauthenticated, "missing metadata")
+		p, err := v.grpcPrincipal(ctx)
+		if err != nil {
+			return nil, err
 		}
-
-		// Check for tenant ID header (set by Envoy)
-		if tenantIDs := md.Get("x-tenant-id"); len(tenantIDs) > 0 {
-			ctx = context.WithValue(ctx, TenantIDKey, tenantIDs[0])
-			return handler(ctx, req)
+		if err := Authorize(p, info.FullMethod, req); err != nil {
+			return nil, err
 		}
 
-		// Fallback: validate JWT directly
-		authHeaders := md.Get("authorization")
-		if len(authHeaders) == 0 {
-			return nil, status.Errorf(codes.Unauthenticated, "missing authorization header")
+		return handler(WithPrincipal(ctx, p), req)
+	}
+}
+
+// GRPCStreamInterceptor returns a gRPC stream interceptor that validates JWT tokens the way
+// GRPCInterceptor does, checking the tenant of every message the client sends
+func (v *JWTValidator) GRPCStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		// Health watches and reflection, which only describes the API, are open to tooling
+		if strings.Contains(info.FullMethod, "Health") || strings.HasPrefix(info.FullMethod, "/grpc.reflection.") {
+			return handler(srv, ss)
 		}
 
-		tokenString := strings.TrimPrefix(authHeaders[0], "Bearer ")
-		if tokenString == authHeaders[0] {
-			return nil, status.Errorf(codes.Unauthenticated, "invalid authorization header format")
+		p, err := v.grpcPrincipal(ss.Context())
+		if err != nil {
+			return err
+		}
+		if err := Authorize(p, info.FullMethod, nil); err != nil {
+			return err
 		}
+		return handler(srv, &tenantStream{
+			ServerStream: ss,
+			ctx:          WithPrincipal(ss.Context(), p),
+			principal:    p,
+		})
+	}
+}
+
+// grpcPrincipal returns the caller a gRPC call is authenticated as, from the Envoy headers
+// when they are trusted or else the bearer token
+func (v *JWTValidator) grpcPrincipal(ctx context.Context) (Principal, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return Principal{}, status.Errorf(codes.Unauthenticated, "missing metadata")
+	}
 
-		tenantID, err := v.ValidateToken(tokenString)
+	// Check for tenant ID header (set by Envoy)
+	if tenantIDs := md.Get("x-tenant-id"); len(tenantIDs) > 0 && v.trustTenantHeader {
+		var subject string
+		if subjects := md.Get("x-subject"); len(subjects) > 0 {
+			subject = subjects[0]
+		}
+		p, err := v.headerPrincipal(tenantIDs[0], subject, md.Get("x-roles"))
 		if err != nil {
-			return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+			return Principal{}, status.Error(codes.Unauthenticated, err.Error())
 		}
+		return p, nil
+	}
+
+	// Fallback: validate JWT directly
+	authHeaders := md.Get("authorization")
+	if len(authHeaders) == 0 {
+		return Principal{}, status.Errorf(codes.Unauthenticated, "missing authorization header")
+	}
 
-		ctx = context.WithValue(ctx, TenantIDKey, tenantID)
-		return handler(ctx, req)
+	tokenString := strings.TrimPrefix(authHeaders[0], "Bearer ")
+	if tokenString == authHeaders[0] {
+		return Principal{}, status.Errorf(codes.Unauthenticated, "invalid authorization header format")
 	}
+
+	p, err := v.ValidateClaims(tokenString)
+	if err != nil {
+		return Principal{}, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+	return p, nil
+}
+
+// tenantStream carries the authenticated caller on a server stream
+type tenantStream struct {
+	grpc.ServerStream
+	ctx       context.Context
+	principal Principal
+}
+
+func (s *tenantStream) Context() context.Context { return s.ctx }
+
+func (s *tenantStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	return checkTenant(m, s.principal)
+}
+
+// isReceiptPath reports whether path is the REST route for AcknowledgeDelivery
+func isReceiptPath(path string) bool {
+	id, ok := strings.CutPrefix(path, "/v1/deliveries/")
+	return ok && strings.HasSuffix(id, ":ack") && !strings.Contains(id, "/")
+}
+
+// isSigningKeysPath reports whether path is the REST route for GetSigningKeys
+func isSigningKeysPath(path string) bool {
+	rest, ok := strings.CutPrefix(path, "/v1/tenants/")
+	tenant, ok2 := strings.CutSuffix(rest, "/signing-keys")
+	return ok && ok2 && tenant != "" && !strings.Contains(tenant, "/")
+}
+
+// checkTenant rejects requests addressed to a tenant other than the one in the token, unless
+// the caller is an admin
+func checkTenant(req interface{}, p Principal) error {
+	r, ok := req.(interface{ GetTenantId() string })
+	if !ok || r.GetTenantId() == "" || p.Role.Includes(RoleAdmin) {
+		return nil
+	}
+	if r.GetTenantId() != p.TenantID {
+		return status.Errorf(codes.PermissionDenied, "tenant_id %q does not match token", r.GetTenantId())
+	}
+	return nil
 }
 
 // GetTenantIDFromContext extracts tenant ID from context
@@ -201,7 +419,8 @@ type JSONWebKey struct {
 	E   string `json:"e"`
 }
 
-// FetchJWKS fetches the JWKS from a URL and returns the public key
+// FetchJWKS fetches the JWKS from a URL and returns the first signing key.
+// Use a KeySet when the issuer publishes several keys or rotates them.
 func FetchJWKS(jwksURL string) (*rsa.PublicKey, error) {
 	resp, err := http.Get(jwksURL)
 	if err != nil {
@@ -222,12 +441,36 @@ func FetchJWKS(jwksURL string) (*rsa.PublicKey, error) {
 		return nil, fmt.Errorf("no keys found in JWKS")
 	}
 
-	// For simplicity, use the first key
-	// In production, we'd want to select by kid
-	_ = jwks.Keys[0]
+	// Use the first RSA signing key; the jwks-server only publishes one
+	for _, key := range jwks.Keys {
+		if key.Kty != "RSA" || (key.Use != "" && key.Use != "sig") {
+			continue
+		}
+		return key.RSAPublicKey()
+	}
+	return nil, fmt.Errorf("no RSA signing key found in JWKS")
+}
+
+// RSAPublicKey decodes the base64url modulus and exponent into an RSA public key
+func (k JSONWebKey) RSAPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus for key %q: %v", k.Kid, err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent for key %q: %v", k.Kid, err)
+	}
+	if len(n) == 0 || len(e) == 0 {
+		return nil, fmt.Errorf("key %q is missing modulus or exponent", k.Kid)
+	}
 
-	// This is a simplified implementation
-	// In practice, we'd need to properly convert JWK to RSA public key
-	// For now, we'll return nil and expect the public key to be provided directly
-	return nil, fmt.Errorf("JWKS parsing not fully implemented - use direct public key")
+	exp := new(big.Int).SetBytes(e)
+	if !exp.IsInt64() || exp.Int64() > 1<<31-1 || exp.Int64() < 2 {
+		return nil, fmt.Errorf("invalid exponent for key %q", k.Kid)
+	}
+	return &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exp.Int64()),
+	}, nil
 }
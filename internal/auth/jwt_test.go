@@ -1,10 +1,6 @@
 // TODO: Add tests that require proper RSA key setup and JWT generation:
-// - Happy path JWT validation with valid tokens (requires RSA private/public key pairs)
 // - Full HTTP middleware integration tests with real JWT tokens
-// - gRPC interceptor tests with valid authorization headers
-// - JWKS endpoint integration tests with real key fetching
 // - Token expiration and renewal testing
-// - Multi-tenant token validation with different tenant_id claims
 // - RSA key rotation and validation with multiple keys
 // - Performance testing with high-volume token validation
 
@@ -12,12 +8,19 @@ package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/json"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -176,9 +179,10 @@ func TestJWTValidator_ValidateToken(t *testing.T) {
 func TestJWTValidator_HTTPMiddleware(t *testing.T) {
 	// Create validator directly since NewJWTValidator test keys fail
 	validator := &JWTValidator{
-		publicKey: nil,
-		issuer:    "test-issuer",
-		audience:  "test-audience",
+		publicKey:         nil,
+		issuer:            "test-issuer",
+		audience:          "test-audience",
+		trustTenantHeader: true,
 	}
 
 	// Mock handler that checks for tenant ID in context
@@ -214,6 +218,34 @@ func TestJWTValidator_HTTPMiddleware(t *testing.T) {
 			expectedStatus: http.StatusOK,
 			expectedTenant: "",
 		},
+		{
+			name:           "delivery ack bypass",
+			path:           "/v1/deliveries/123e4567-e89b-12d3-a456-426614174000:ack",
+			headers:        map[string]string{},
+			expectedStatus: http.StatusOK,
+			expectedTenant: "",
+		},
+		{
+			name:           "replay is not an ack",
+			path:           "/v1/deliveries/123e4567-e89b-12d3-a456-426614174000:replay",
+			headers:        map[string]string{},
+			expectedStatus: http.StatusUnauthorized,
+			expectedTenant: "",
+		},
+		{
+			name:           "signing keys bypass",
+			path:           "/v1/tenants/tn_123/signing-keys",
+			headers:        map[string]string{},
+			expectedStatus: http.StatusOK,
+			expectedTenant: "",
+		},
+		{
+			name:           "endpoints under a tenant still need a token",
+			path:           "/v1/tenants/tn_123/endpoints/signing-keys",
+			headers:        map[string]string{},
+			expectedStatus: http.StatusUnauthorized,
+			expectedTenant: "",
+		},
 		{
 			name: "valid tenant ID header from Envoy",
 			path: "/api/v1/events",
@@ -277,9 +309,10 @@ func TestJWTValidator_HTTPMiddleware(t *testing.T) {
 func TestJWTValidator_GRPCInterceptor(t *testing.T) {
 	// Create validator directly since NewJWTValidator test keys fail
 	validator := &JWTValidator{
-		publicKey: nil,
-		issuer:    "test-issuer",
-		audience:  "test-audience",
+		publicKey:         nil,
+		issuer:            "test-issuer",
+		audience:          "test-audience",
+		trustTenantHeader: true,
 	}
 
 	interceptor := validator.GRPCInterceptor()
@@ -307,6 +340,18 @@ func TestJWTValidator_GRPCInterceptor(t *testing.T) {
 			metadata:      metadata.New(map[string]string{}),
 			expectedError: false,
 		},
+		{
+			name:          "delivery ack bypass",
+			method:        "/api.webhook.v1.WebhookService/AcknowledgeDelivery",
+			metadata:      metadata.New(map[string]string{}),
+			expectedError: false,
+		},
+		{
+			name:          "signing keys bypass",
+			method:        "/api.webhook.v1.WebhookService/GetSigningKeys",
+			metadata:      metadata.New(map[string]string{}),
+			expectedError: false,
+		},
 		{
 			name:   "valid tenant ID header from Envoy",
 			method: "/api.v1.EventService/PublishEvent",
@@ -390,6 +435,82 @@ func TestJWTValidator_GRPCInterceptor(t *testing.T) {
 	}
 }
 
+// fakeServerStream is a grpc.ServerStream that hands out one request
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	req *webhookv1.WatchDeliveryStatusRequest
+}
+
+func (s *fakeServerStream) Context() context.Context { return s.ctx }
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	m.(*webhookv1.WatchDeliveryStatusRequest).TenantId = s.req.TenantId
+	return nil
+}
+
+func TestJWTValidator_GRPCStreamInterceptor(t *testing.T) {
+	validator := &JWTValidator{trustTenantHeader: true}
+	interceptor := validator.GRPCStreamInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: "/api.webhook.v1.WebhookService/WatchDeliveryStatus"}
+
+	tests := []struct {
+		name          string
+		metadata      metadata.MD
+		requestTenant string
+		expectedCode  codes.Code
+	}{
+		{
+			name:          "tenant header from Envoy",
+			metadata:      metadata.New(map[string]string{"x-tenant-id": "tn_123"}),
+			requestTenant: "tn_123",
+			expectedCode:  codes.OK,
+		},
+		{
+			name:          "request for another tenant",
+			metadata:      metadata.New(map[string]string{"x-tenant-id": "tn_123"}),
+			requestTenant: "tn_456",
+			expectedCode:  codes.PermissionDenied,
+		},
+		{
+			name:         "missing authorization header",
+			metadata:     metadata.New(map[string]string{}),
+			expectedCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ss := &fakeServerStream{
+				ctx: metadata.NewIncomingContext(context.Background(), tt.metadata),
+				req: &webhookv1.WatchDeliveryStatusRequest{TenantId: tt.requestTenant},
+			}
+			var gotTenant string
+			err := interceptor(nil, ss, info, func(_ interface{}, stream grpc.ServerStream) error {
+				if err := stream.RecvMsg(&webhookv1.WatchDeliveryStatusRequest{}); err != nil {
+					return err
+				}
+				gotTenant, _ = GetTenantIDFromContext(stream.Context())
+				return nil
+			})
+
+			if got := status.Code(err); got != tt.expectedCode {
+				t.Fatalf("GRPCStreamInterceptor() code = %v, want %v (err = %v)", got, tt.expectedCode, err)
+			}
+			if err == nil && gotTenant != "tn_123" {
+				t.Errorf("stream context tenant = %q, want %q", gotTenant, "tn_123")
+			}
+		})
+	}
+
+	// Reflection is open, so grpcurl can describe the API without a token
+	reflect := &grpc.StreamServerInfo{FullMethod: "/grpc.reflection.v1.ServerReflection/ServerReflectionInfo"}
+	ss := &fakeServerStream{ctx: context.Background()}
+	if err := interceptor(nil, ss, reflect, func(interface{}, grpc.ServerStream) error { return nil }); err != nil {
+		t.Errorf("GRPCStreamInterceptor(reflection) unexpected error: %v", err)
+	}
+}
+
 func TestGetTenantIDFromContext(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -450,20 +571,43 @@ func TestFetchJWKS(t *testing.T) {
 				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 					jwks := JSONWebKeySet{
 						Keys: []JSONWebKey{
-							{
-								Kty: "RSA",
-								Use: "sig",
-								Kid: "test-key-id",
-								N:   "base64-encoded-modulus",
-								E:   "AQAB",
-							},
+							testJWK(&testKey(t).PublicKey, "test-key-id"),
 						},
 					}
 					json.NewEncoder(w).Encode(jwks)
 				}))
 			},
-			expectError:   true, // Current implementation always returns error
-			errorContains: "JWKS parsing not fully implemented",
+			expectError: false,
+		},
+		{
+			name: "JWKS with invalid modulus",
+			setupServer: func() *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					jwks := JSONWebKeySet{
+						Keys: []JSONWebKey{
+							{Kty: "RSA", Use: "sig", Kid: "test-key-id", N: "not base64!", E: "AQAB"},
+						},
+					}
+					json.NewEncoder(w).Encode(jwks)
+				}))
+			},
+			expectError:   true,
+			errorContains: "invalid modulus",
+		},
+		{
+			name: "JWKS without RSA signing key",
+			setupServer: func() *httptest.Server {
+				return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					jwks := JSONWebKeySet{
+						Keys: []JSONWebKey{
+							{Kty: "RSA", Use: "enc", Kid: "enc-key", N: "AQAB", E: "AQAB"},
+						},
+					}
+					json.NewEncoder(w).Encode(jwks)
+				}))
+			},
+			expectError:   true,
+			errorContains: "no RSA signing key found",
 		},
 		{
 			name: "JWKS endpoint returns 404",
@@ -619,3 +763,145 @@ func TestContextKey(t *testing.T) {
 		t.Errorf("Context value = %v, want %q", value, "test-tenant")
 	}
 }
+
+// testKey generates a throwaway RSA key for signing test tokens
+func testKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate RSA key: %v", err)
+	}
+	return key
+}
+
+func testJWK(pub *rsa.PublicKey, kid string) JSONWebKey {
+	return JSONWebKey{
+		Kty: "RSA",
+		Use: "sig",
+		Kid: kid,
+		N:   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}
+}
+
+func signTestToken(t *testing.T, key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(key)
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return token
+}
+
+func TestJSONWebKey_RSAPublicKey(t *testing.T) {
+	key := testKey(t)
+
+	pub, err := testJWK(&key.PublicKey, "key-1").RSAPublicKey()
+	if err != nil {
+		t.Fatalf("RSAPublicKey() unexpected error: %v", err)
+	}
+	if !pub.Equal(&key.PublicKey) {
+		t.Error("RSAPublicKey() did not round-trip the public key")
+	}
+
+	if _, err := (JSONWebKey{Kid: "empty"}).RSAPublicKey(); err == nil {
+		t.Error("RSAPublicKey() expected error for empty key")
+	}
+}
+
+func TestJWTValidator_SignedTokens(t *testing.T) {
+	key := testKey(t)
+	validator := NewJWTValidatorFromKey(&key.PublicKey, "harborhook", "harborhook-api")
+	interceptor := validator.GRPCInterceptor()
+
+	claims := func(overrides jwt.MapClaims) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"iss":       "harborhook",
+			"aud":       "harborhook-api",
+			"tenant_id": "tn_123",
+			"exp":       time.Now().Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			c[k] = v
+		}
+		return c
+	}
+
+	tests := []struct {
+		name         string
+		token        string
+		headers      map[string]string
+		req          interface{}
+		expectedCode codes.Code
+	}{
+		{
+			name:         "valid token",
+			token:        signTestToken(t, key, claims(nil)),
+			req:          &webhookv1.PublishEventRequest{TenantId: "tn_123"},
+			expectedCode: codes.OK,
+		},
+		{
+			name:         "wrong issuer",
+			token:        signTestToken(t, key, claims(jwt.MapClaims{"iss": "someone-else"})),
+			req:          &webhookv1.PublishEventRequest{TenantId: "tn_123"},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "wrong audience",
+			token:        signTestToken(t, key, claims(jwt.MapClaims{"aud": "other-api"})),
+			req:          &webhookv1.PublishEventRequest{TenantId: "tn_123"},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "expired token",
+			token:        signTestToken(t, key, claims(jwt.MapClaims{"exp": time.Now().Add(-time.Minute).Unix()})),
+			req:          &webhookv1.PublishEventRequest{TenantId: "tn_123"},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "signed by another key",
+			token:        signTestToken(t, testKey(t), claims(nil)),
+			req:          &webhookv1.PublishEventRequest{TenantId: "tn_123"},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "tenant mismatch",
+			token:        signTestToken(t, key, claims(nil)),
+			req:          &webhookv1.PublishEventRequest{TenantId: "tn_other"},
+			expectedCode: codes.PermissionDenied,
+		},
+		{
+			name:         "untrusted tenant header is ignored",
+			headers:      map[string]string{"x-tenant-id": "tn_123"},
+			req:          &webhookv1.PublishEventRequest{TenantId: "tn_123"},
+			expectedCode: codes.Unauthenticated,
+		},
+		{
+			name:         "request without tenant field",
+			token:        signTestToken(t, key, claims(nil)),
+			req:          &webhookv1.GetDeliveryStatusRequest{EventId: "evt_1"},
+			expectedCode: codes.OK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			md := metadata.New(tt.headers)
+			if tt.token != "" {
+				md.Set("authorization", "Bearer "+tt.token)
+			}
+			ctx := metadata.NewIncomingContext(context.Background(), md)
+			info := &grpc.UnaryServerInfo{FullMethod: "/api.webhook.v1.WebhookService/PublishEvent"}
+
+			_, err := interceptor(ctx, tt.req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				if tenantID, _ := GetTenantIDFromContext(ctx); tenantID != "tn_123" {
+					t.Errorf("handler tenant = %q, want %q", tenantID, "tn_123")
+				}
+				return nil, nil
+			})
+			if status.Code(err) != tt.expectedCode {
+				t.Errorf("GRPCInterceptor() code = %v, want %v (err: %v)", status.Code(err), tt.expectedCode, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Role is what a token may do. Roles are ordered; each includes everything the ones below it may do.
+type Role string
+
+const (
+	// RoleViewer reads its tenant's endpoints, deliveries, DLQ, quotas and schemas
+	RoleViewer Role = "viewer"
+	// RolePublisher also publishes events
+	RolePublisher Role = "publisher"
+	// RoleOperator also manages its tenant's endpoints, subscriptions, schemas, replays and freezes
+	RoleOperator Role = "operator"
+	// RoleAdmin also acts on any tenant, deletes endpoints and uses cluster-wide controls
+	RoleAdmin Role = "admin"
+)
+
+// RoleKey is the context key for the caller's role
+const RoleKey contextKey = "role"
+
+// SubjectKey is the context key for the caller's token subject
+const SubjectKey contextKey = "subject"
+
+// rank orders the roles; unknown roles rank 0 and may do nothing
+var rank = map[Role]int{RoleViewer: 1, RolePublisher: 2, RoleOperator: 3, RoleAdmin: 4}
+
+// Includes reports whether r may do everything other may
+func (r Role) Includes(other Role) bool {
+	return rank[r] > 0 && rank[r] >= rank[other]
+}
+
+// ParseRole returns the role named s
+func ParseRole(s string) (Role, error) {
+	r := Role(strings.ToLower(strings.TrimSpace(s)))
+	if rank[r] == 0 {
+		return "", fmt.Errorf("unknown role %q", s)
+	}
+	return r, nil
+}
+
+// highestRole returns the strongest of the named roles
+func highestRole(names []string) (Role, error) {
+	var best Role
+	for _, n := range names {
+		r, err := ParseRole(n)
+		if err != nil {
+			return "", err
+		}
+		if !best.Includes(r) {
+			best = r
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no roles granted")
+	}
+	return best, nil
+}
+
+// Principal is an authenticated caller
+type Principal struct {
+	TenantID string
+	Role     Role
+	// Subject is the token's sub claim, which names who holds it; it may be empty
+	Subject string
+}
+
+// legacyPrincipal is the caller for a token or header without roles. Such tokens predate roles and
+// keep full control of their own tenant; the admin tenant's keep its cluster-wide controls.
+func legacyPrincipal(tenantID, adminTenant string) Principal {
+	if adminTenant != "" && tenantID == adminTenant {
+		return Principal{TenantID: tenantID, Role: RoleAdmin}
+	}
+	return Principal{TenantID: tenantID, Role: RoleOperator}
+}
+
+// WithPrincipal returns ctx carrying p's tenant, role and subject
+func WithPrincipal(ctx context.Context, p Principal) context.Context {
+	ctx = context.WithValue(ctx, TenantIDKey, p.TenantID)
+	ctx = context.WithValue(ctx, SubjectKey, p.Subject)
+	return context.WithValue(ctx, RoleKey, p.Role)
+}
+
+// GetRoleFromContext extracts the caller's role from context
+func GetRoleFromContext(ctx context.Context) (Role, bool) {
+	role, ok := ctx.Value(RoleKey).(Role)
+	return role, ok
+}
+
+// GetSubjectFromContext extracts the caller's token subject from context
+func GetSubjectFromContext(ctx context.Context) (string, bool) {
+	subject, ok := ctx.Value(SubjectKey).(string)
+	return subject, ok
+}
+
+// IsAdmin reports whether ctx belongs to an authenticated admin
+func IsAdmin(ctx context.Context) bool {
+	role, ok := GetRoleFromContext(ctx)
+	return ok && role.Includes(RoleAdmin)
+}
+
+// methodRoles is the least role each WebhookService method needs. Methods missing here need
+// RoleAdmin, so a new RPC is closed until it is given a policy.
+var methodRoles = map[string]Role{
+	// Reads
+	"GetDeliveryStatus":       RoleViewer,
+	"WatchDeliveryStatus":     RoleViewer,
+	"ListDLQ":                 RoleViewer,
+	"GetDLQEntry":             RoleViewer,
+	"GetDLQRetention":         RoleViewer,
+	"GetNotificationSettings": RoleViewer,
+	"GetBacklogEstimate":      RoleViewer,
+	"GetTenantQuota":          RoleViewer,
+	"GetFailureTrends":        RoleViewer,
+	"GetDeliveryStats":        RoleViewer,
+	"ListSystemEvents":        RoleViewer,
+	"ListEventSchemas":        RoleViewer,
+	"GetEventSchema":          RoleViewer,
+	"GetDispatchState":        RoleViewer,
+	"GetEgressIPs":            RoleViewer,
+
+	// Publishing
+	"PublishEvent":  RolePublisher,
+	"PublishEvents": RolePublisher,
+
+	// Managing a tenant
+	"CreateEndpoint":               RoleOperator,
+	"VerifyEndpoint":               RoleOperator,
+	"SetEndpointRecoveryRamp":      RoleOperator,
+	"SetEndpointRetryPolicy":       RoleOperator,
+	"SetEndpointClientCertificate": RoleOperator,
+	"SetEndpointCompression":       RoleOperator,
+	"SetEndpointTimeout":           RoleOperator,
+	"SetEndpointProxy":             RoleOperator,
+	"SetEndpointHealthCheck":       RoleOperator,
+	"EnableEndpoint":               RoleOperator,
+	"SetEndpointSignatureScheme":   RoleOperator,
+	"SetEndpointOrdering":          RoleOperator,
+	"CreateSubscription":           RoleOperator,
+	"CreateEventSchema":            RoleOperator,
+	"ReplayDelivery":               RoleOperator,
+	"ReplayDLQ":                    RoleOperator,
+	"PurgeDLQ":                     RoleOperator,
+	"SetDLQRetention":              RoleOperator,
+	"SetNotificationSettings":      RoleOperator,
+	"SetComplianceMode":            RoleOperator,
+	"SetDeliverySettings":          RoleOperator,
+	"ListDeliveryRecordings":       RoleOperator,
+	"GetCapturedDeliveries":        RoleOperator,
+	"ListAuditLog":                 RoleOperator,
+	"FreezeDeliveries":             RoleOperator,
+	"DrainQueue":                   RoleOperator,
+	"ResumeDeliveries":             RoleOperator,
+
+	// Destructive and cluster-wide
+	"DeleteEndpoint":       RoleAdmin,
+	"PauseDispatch":        RoleAdmin,
+	"ResumeDispatch":       RoleAdmin,
+	"SetTenantQuota":       RoleAdmin,
+	"ListTenants":          RoleAdmin,
+	"ListEndpoints":        RoleAdmin,
+	"ListRecentDeliveries": RoleAdmin,
+}
+
+// RequiredRole returns the least role that may call a gRPC method, given its full name
+func RequiredRole(fullMethod string) Role {
+	name := fullMethod[strings.LastIndex(fullMethod, "/")+1:]
+	if r, ok := methodRoles[name]; ok {
+		return r
+	}
+	return RoleAdmin
+}
+
+// Authorize decides whether p may call fullMethod with req: its role must cover the method, and
+// only admins may address a tenant other than their own
+func Authorize(p Principal, fullMethod string, req interface{}) error {
+	if need := RequiredRole(fullMethod); !p.Role.Includes(need) {
+		return status.Errorf(codes.PermissionDenied, "%s requires the %s role", fullMethod[strings.LastIndex(fullMethod, "/")+1:], need)
+	}
+	return checkTenant(req, p)
+}
+
+// RequireRole returns an HTTP handler that serves next only to callers whose role includes role.
+// It goes behind HTTPMiddleware, which authenticates the caller.
+func RequireRole(role Role, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if have, ok := GetRoleFromContext(r.Context()); !ok || !have.Includes(role) {
+			http.Error(w, fmt.Sprintf("requires the %s role", role), http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
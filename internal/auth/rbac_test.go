@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestRole_Includes(t *testing.T) {
+	if !RoleAdmin.Includes(RoleViewer) || !RolePublisher.Includes(RolePublisher) {
+		t.Error("stronger and equal roles should include the role")
+	}
+	if RoleViewer.Includes(RolePublisher) || RoleOperator.Includes(RoleAdmin) {
+		t.Error("weaker roles should not include the role")
+	}
+	if Role("").Includes(RoleViewer) || Role("root").Includes(RoleViewer) {
+		t.Error("unknown roles should include nothing")
+	}
+}
+
+func TestParseRole(t *testing.T) {
+	if r, err := ParseRole(" Operator "); err != nil || r != RoleOperator {
+		t.Errorf("ParseRole(Operator) = %q, %v", r, err)
+	}
+	if _, err := ParseRole("root"); err == nil {
+		t.Error("ParseRole(root) expected error")
+	}
+}
+
+// Every RPC gets an explicit policy rather than falling back to admin-only by accident
+func TestMethodRoles_CoverService(t *testing.T) {
+	public := map[string]bool{"Ping": true, "AcknowledgeDelivery": true, "GetSigningKeys": true}
+	var names []string
+	for _, m := range webhookv1.WebhookService_ServiceDesc.Methods {
+		names = append(names, m.MethodName)
+	}
+	for _, s := range webhookv1.WebhookService_ServiceDesc.Streams {
+		names = append(names, s.StreamName)
+	}
+	for _, name := range names {
+		if _, ok := methodRoles[name]; !ok && !public[name] {
+			t.Errorf("%s has no role policy", name)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	const svc = "/api.webhook.v1.WebhookService/"
+	tests := []struct {
+		name     string
+		p        Principal
+		method   string
+		req      interface{}
+		wantDeny bool
+	}{
+		{"viewer reads", Principal{TenantID: "tn_1", Role: RoleViewer}, svc + "ListDLQ", &webhookv1.ListDLQRequest{TenantId: "tn_1"}, false},
+		{"viewer can't publish", Principal{TenantID: "tn_1", Role: RoleViewer}, svc + "PublishEvent", &webhookv1.PublishEventRequest{TenantId: "tn_1"}, true},
+		{"publisher publishes", Principal{TenantID: "tn_1", Role: RolePublisher}, svc + "PublishEvent", &webhookv1.PublishEventRequest{TenantId: "tn_1"}, false},
+		{"publisher can't create endpoints", Principal{TenantID: "tn_1", Role: RolePublisher}, svc + "CreateEndpoint", &webhookv1.CreateEndpointRequest{TenantId: "tn_1"}, true},
+		{"operator can't delete endpoints", Principal{TenantID: "tn_1", Role: RoleOperator}, svc + "DeleteEndpoint", &webhookv1.DeleteEndpointRequest{TenantId: "tn_1"}, true},
+		{"admin deletes endpoints", Principal{TenantID: "ops", Role: RoleAdmin}, svc + "DeleteEndpoint", &webhookv1.DeleteEndpointRequest{TenantId: "tn_1"}, false},
+		{"operator stays in its tenant", Principal{TenantID: "tn_1", Role: RoleOperator}, svc + "ListDLQ", &webhookv1.ListDLQRequest{TenantId: "tn_2"}, true},
+		{"admin crosses tenants", Principal{TenantID: "ops", Role: RoleAdmin}, svc + "ListDLQ", &webhookv1.ListDLQRequest{TenantId: "tn_2"}, false},
+		{"unknown method needs admin", Principal{TenantID: "tn_1", Role: RoleOperator}, svc + "DropEverything", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.p, tt.method, tt.req)
+			if tt.wantDeny && status.Code(err) != codes.PermissionDenied {
+				t.Errorf("Authorize() = %v, want PermissionDenied", err)
+			}
+			if !tt.wantDeny && err != nil {
+				t.Errorf("Authorize() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestJWTValidator_ValidateClaims_Roles(t *testing.T) {
+	key := testKey(t)
+	validator := NewJWTValidatorFromKey(&key.PublicKey, "harborhook", "harborhook-api")
+	validator.SetAdminTenant("ops")
+	token := func(tenant string, roles interface{}) string {
+		claims := jwt.MapClaims{
+			"iss": "harborhook", "aud": "harborhook-api", "tenant_id": tenant,
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		if roles != nil {
+			claims["roles"] = roles
+		}
+		return signTestToken(t, key, claims)
+	}
+	scoped := func(scope string, roles interface{}) string {
+		claims := jwt.MapClaims{
+			"iss": "harborhook", "aud": "harborhook-api", "tenant_id": "tn_1", "scope": scope,
+			"exp": time.Now().Add(time.Hour).Unix(),
+		}
+		if roles != nil {
+			claims["roles"] = roles
+		}
+		return signTestToken(t, key, claims)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		wantRole Role
+		wantErr  bool
+	}{
+		{"strongest role applies", token("tn_1", []string{"viewer", "publisher"}), RolePublisher, false},
+		{"space-separated roles", token("tn_1", "viewer operator"), RoleOperator, false},
+		{"no roles claim", token("tn_1", nil), RoleOperator, false},
+		{"admin tenant without roles", token("ops", nil), RoleAdmin, false},
+		{"admin tenant with roles", token("ops", []string{"viewer"}), RoleViewer, false},
+		{"unknown role", token("tn_1", []string{"root"}), "", true},
+		{"empty roles", token("tn_1", []string{}), "", true},
+		{"malformed roles", token("tn_1", 7), "", true},
+		{"role scopes", scoped("openid harborhook:viewer harborhook:publisher", nil), RolePublisher, false},
+		{"roles claim over scopes", scoped("harborhook:admin", []string{"viewer"}), RoleViewer, false},
+		{"scopes without roles", scoped("openid profile", nil), RoleOperator, false},
+		{"unknown role scope", scoped("harborhook:root", nil), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := validator.ValidateClaims(tt.token)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ValidateClaims() = %+v, want error", p)
+				}
+				return
+			}
+			if err != nil || p.Role != tt.wantRole {
+				t.Errorf("ValidateClaims() = %+v, %v, want role %s", p, err, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestJWTValidator_GRPCInterceptor_Roles(t *testing.T) {
+	validator := &JWTValidator{trustTenantHeader: true}
+	interceptor := validator.GRPCInterceptor()
+	handler := func(ctx context.Context, _ interface{}) (interface{}, error) {
+		role, _ := GetRoleFromContext(ctx)
+		return role, nil
+	}
+	call := func(roles, method string, req interface{}) (interface{}, error) {
+		md := metadata.New(map[string]string{"x-tenant-id": "tn_1"})
+		if roles != "" {
+			md.Set("x-roles", roles)
+		}
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+		return interceptor(ctx, req, &grpc.UnaryServerInfo{FullMethod: "/api.webhook.v1.WebhookService/" + method}, handler)
+	}
+
+	if role, err := call("viewer,publisher", "PublishEvent", &webhookv1.PublishEventRequest{TenantId: "tn_1"}); err != nil || role != RolePublisher {
+		t.Errorf("publisher PublishEvent = %v, %v, want allowed as publisher", role, err)
+	}
+	if _, err := call("viewer", "PublishEvent", &webhookv1.PublishEventRequest{TenantId: "tn_1"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("viewer PublishEvent error = %v, want PermissionDenied", err)
+	}
+	if _, err := call("superuser", "ListDLQ", &webhookv1.ListDLQRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("unknown x-roles error = %v, want Unauthenticated", err)
+	}
+	if role, err := call("", "CreateEndpoint", &webhookv1.CreateEndpointRequest{TenantId: "tn_1"}); err != nil || role != RoleOperator {
+		t.Errorf("header without roles = %v, %v, want operator", role, err)
+	}
+}
+
+func TestRequireRole(t *testing.T) {
+	handler := RequireRole(RoleAdmin, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want int
+	}{
+		{name: "admin", ctx: WithPrincipal(context.Background(), Principal{TenantID: "ops", Role: RoleAdmin}), want: http.StatusNoContent},
+		{name: "operator", ctx: WithPrincipal(context.Background(), Principal{TenantID: "tn_1", Role: RoleOperator}), want: http.StatusForbidden},
+		{name: "unauthenticated", ctx: context.Background(), want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/loglevel", nil).WithContext(tt.ctx))
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+	}
+}
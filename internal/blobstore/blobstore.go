@@ -0,0 +1,79 @@
+// Package blobstore keeps event payloads too large to travel inside delivery tasks (the claim
+// check pattern): ingest stores the payload and publishes tasks carrying only a reference to it,
+// and the worker fetches it back when it delivers. BLOB_STORE selects Postgres (the default) or
+// S3.
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db"
+)
+
+// Supported stores
+const (
+	BackendPostgres = "postgres"
+	BackendS3       = "s3"
+)
+
+// ErrNotFound is returned by Get when the referenced payload no longer exists
+var ErrNotFound = errors.New("payload not found")
+
+// Store holds event payloads for delivery tasks to reference
+type Store interface {
+	// Put stores an event's JSON payload and returns the reference tasks carry in its place
+	Put(ctx context.Context, tenantID, eventID string, payload []byte) (string, error)
+	// Get returns the payload ref points at
+	Get(ctx context.Context, ref string) ([]byte, error)
+}
+
+// New returns the configured store; the Postgres store reads through pool
+func New(ctx context.Context, cfg config.ClaimCheck, pool db.Pool) (Store, error) {
+	switch cfg.Store {
+	case BackendPostgres:
+		return NewPostgres(pool), nil
+	case BackendS3:
+		return newS3(ctx, cfg)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q (want %s or %s)", cfg.Store, BackendPostgres, BackendS3)
+	}
+}
+
+const postgresRefPrefix = "postgres:event/"
+
+// Postgres serves payloads from the events table. Every published event's payload is already
+// stored there, so Put writes nothing and the reference is the event ID.
+type Postgres struct {
+	pool db.Pool
+}
+
+// NewPostgres returns a store reading event payloads through pool
+func NewPostgres(pool db.Pool) *Postgres {
+	return &Postgres{pool: pool}
+}
+
+func (p *Postgres) Put(_ context.Context, _, eventID string, _ []byte) (string, error) {
+	return postgresRefPrefix + eventID, nil
+}
+
+func (p *Postgres) Get(ctx context.Context, ref string) ([]byte, error) {
+	eventID, ok := strings.CutPrefix(ref, postgresRefPrefix)
+	if !ok {
+		return nil, fmt.Errorf("not a postgres payload reference: %q", ref)
+	}
+	var payload string
+	err := p.pool.QueryRow(ctx, `SELECT payload::text FROM harborhook.events WHERE id = $1`, eventID).Scan(&payload)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("event %s: %w", eventID, ErrNotFound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []byte(payload), nil
+}
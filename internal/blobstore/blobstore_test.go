@@ -0,0 +1,117 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+)
+
+func TestPostgres(t *testing.T) {
+	pool := &dbfake.Pool{QueryRowFunc: func(_ string, args []any) pgx.Row {
+		if args[0] == "evt_1" {
+			return dbfake.Row{Values: []any{`{"order_id":"ord_123"}`}}
+		}
+		return dbfake.Row{Err: pgx.ErrNoRows}
+	}}
+	store := NewPostgres(pool)
+
+	ref, err := store.Put(context.Background(), "tn_1", "evt_1", []byte(`{"order_id":"ord_123"}`))
+	if err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	got, err := store.Get(context.Background(), ref)
+	if err != nil || string(got) != `{"order_id":"ord_123"}` {
+		t.Errorf("Get(%q) = %s, %v, want the event payload", ref, got, err)
+	}
+	if _, err := store.Get(context.Background(), "postgres:event/evt_gone"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(deleted event) error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Get(context.Background(), "s3://bucket/key"); err == nil {
+		t.Error("Get(s3 reference) expected error")
+	}
+}
+
+// fakeS3 is a path-style object store that requires signed requests
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+		http.Error(w, "unsigned request", http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.Method {
+	case http.MethodPut:
+		f.objects[r.URL.Path], _ = io.ReadAll(r.Body)
+	case http.MethodGet:
+		b, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "NoSuchKey", http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write(b)
+	}
+}
+
+func TestS3(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	creds := aws.CredentialsProviderFunc(func(context.Context) (aws.Credentials, error) {
+		return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, nil
+	})
+	store := NewS3(srv.URL, "us-east-1", "hh-payloads", "payloads/", creds)
+
+	ref, err := store.Put(context.Background(), "tn_1", "evt_1", []byte(`{"order_id":"ord_123"}`))
+	if err != nil {
+		t.Fatalf("Put() unexpected error: %v", err)
+	}
+	if ref != "s3://hh-payloads/payloads/tn_1/evt_1.json" {
+		t.Errorf("Put() ref = %q", ref)
+	}
+	if _, ok := fake.objects["/hh-payloads/payloads/tn_1/evt_1.json"]; !ok {
+		t.Errorf("object not stored path-style, have %v", fake.objects)
+	}
+
+	if err := store.PutObject(context.Background(), "events/batch.jsonl.gz", "application/gzip", []byte("gz")); err != nil {
+		t.Fatalf("PutObject() unexpected error: %v", err)
+	}
+	if got := fake.objects["/hh-payloads/payloads/events/batch.jsonl.gz"]; string(got) != "gz" {
+		t.Errorf("PutObject() stored %q, want the body under the prefix", got)
+	}
+
+	got, err := store.Get(context.Background(), ref)
+	if err != nil || string(got) != `{"order_id":"ord_123"}` {
+		t.Errorf("Get(%q) = %s, %v, want the stored payload", ref, got, err)
+	}
+	if _, err := store.Get(context.Background(), "s3://hh-payloads/payloads/tn_1/evt_gone.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing object) error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Get(context.Background(), "postgres:event/evt_1"); err == nil {
+		t.Error("Get(postgres reference) expected error")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(context.Background(), config.ClaimCheck{Store: "redis"}, nil); err == nil {
+		t.Error("New(unknown store) expected error")
+	}
+	if _, err := New(context.Background(), config.ClaimCheck{Store: BackendS3}, nil); err == nil || !strings.Contains(err.Error(), "BLOB_S3_BUCKET") {
+		t.Errorf("New(s3 without bucket) error = %v, want BLOB_S3_BUCKET required", err)
+	}
+}
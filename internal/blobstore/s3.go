@@ -0,0 +1,144 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+// S3 keeps payloads as objects named prefix/tenant/event.json. Requests are signed with the AWS
+// default credential chain and use path-style URLs, so S3-compatible stores such as MinIO work
+// through BLOB_S3_ENDPOINT. Objects are never deleted here; expire them with a bucket lifecycle
+// rule longer than the longest retry window.
+type S3 struct {
+	client   *http.Client
+	endpoint string
+	bucket   string
+	prefix   string
+	region   string
+	creds    aws.CredentialsProvider
+	signer   *v4.Signer
+}
+
+func newS3(ctx context.Context, cfg config.ClaimCheck) (*S3, error) {
+	if cfg.S3Bucket == "" {
+		return nil, errors.New("BLOB_S3_BUCKET is required for the s3 blob store")
+	}
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	region := cfg.S3Region
+	if region == "" {
+		region = awsCfg.Region
+	}
+	if region == "" {
+		return nil, errors.New("no AWS region configured for the s3 blob store (set BLOB_S3_REGION)")
+	}
+	return NewS3(cfg.S3Endpoint, region, cfg.S3Bucket, cfg.S3Prefix, awsCfg.Credentials), nil
+}
+
+// NewS3 returns a store keeping payloads in bucket; an empty endpoint uses AWS in region
+func NewS3(endpoint, region, bucket, prefix string, creds aws.CredentialsProvider) *S3 {
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	return &S3{
+		client:   &http.Client{Timeout: 30 * time.Second},
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		bucket:   bucket,
+		prefix:   prefix,
+		region:   region,
+		creds:    aws.NewCredentialsCache(creds),
+		signer:   v4.NewSigner(),
+	}
+}
+
+func (s *S3) Put(ctx context.Context, tenantID, eventID string, payload []byte) (string, error) {
+	key := s.prefix + tenantID + "/" + eventID + ".json"
+	if err := s.put(ctx, key, "application/json", payload); err != nil {
+		return "", err
+	}
+	return "s3://" + s.bucket + "/" + key, nil
+}
+
+// PutObject writes body to the object named key under the store's prefix
+func (s *S3) PutObject(ctx context.Context, key, contentType string, body []byte) error {
+	return s.put(ctx, s.prefix+key, contentType, body)
+}
+
+func (s *S3) put(ctx context.Context, key, contentType string, body []byte) error {
+	resp, err := s.do(ctx, http.MethodPut, s.bucket, key, contentType, body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp, "put", key)
+	}
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, ref string) ([]byte, error) {
+	path, ok := strings.CutPrefix(ref, "s3://")
+	bucket, key, found := strings.Cut(path, "/")
+	if !ok || !found {
+		return nil, fmt.Errorf("not an s3 payload reference: %q", ref)
+	}
+	resp, err := s.do(ctx, http.MethodGet, bucket, key, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return io.ReadAll(resp.Body)
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%s: %w", ref, ErrNotFound)
+	default:
+		return nil, s3Error(resp, "get", key)
+	}
+}
+
+// do sends a signed request for one object
+func (s *S3) do(ctx context.Context, method, bucket, key, contentType string, body []byte) (*http.Response, error) {
+	u := s.endpoint + "/" + url.PathEscape(bucket) + "/" + (&url.URL{Path: key}).EscapedPath()
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	sum := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(sum[:])
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	creds, err := s.creds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieve aws credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, creds, req, payloadHash, "s3", s.region, time.Now()); err != nil {
+		return nil, fmt.Errorf("sign s3 request: %w", err)
+	}
+	return s.client.Do(req)
+}
+
+func s3Error(resp *http.Response, op, key string) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3 %s %s: %s: %s", op, key, resp.Status, bytes.TrimSpace(msg))
+}
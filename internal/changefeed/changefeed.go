@@ -0,0 +1,104 @@
+// Package changefeed publishes delivery status transitions to a queue topic, so stats rollups,
+// SLO calculations and live views can consume them instead of polling the deliveries table.
+package changefeed
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/queue"
+)
+
+// pendingBuffer bounds how many batches may wait for the sender; more are dropped
+const pendingBuffer = 1024
+
+// Change is one delivery status transition
+type Change struct {
+	DeliveryID string    `json:"delivery_id"`
+	EventID    string    `json:"event_id"`
+	TenantID   string    `json:"tenant_id"`
+	EndpointID string    `json:"endpoint_id"`
+	EventType  string    `json:"event_type,omitempty"`
+	From       string    `json:"from,omitempty"` // empty when the delivery was just created
+	To         string    `json:"to"`
+	Attempt    int       `json:"attempt"`
+	HTTPStatus int       `json:"http_status,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// FromTask describes a transition of the delivery carried by t
+func FromTask(t delivery.Task, from, to string) Change {
+	return Change{
+		DeliveryID: t.DeliveryID,
+		EventID:    t.EventID,
+		TenantID:   t.TenantID,
+		EndpointID: t.EndpointID,
+		EventType:  t.EventType,
+		From:       from,
+		To:         to,
+		Attempt:    t.Attempt,
+		At:         time.Now().UTC(),
+	}
+}
+
+// Feed publishes changes without making callers wait on the broker. Delivery is best effort:
+// changes that fail to publish are counted in harborhook_changefeed_dropped_total.
+// A nil *Feed drops everything, so callers don't need to check whether it is configured.
+type Feed struct {
+	pub     queue.Publisher
+	topic   string
+	pending chan [][]byte
+}
+
+// New returns a Feed publishing to topic through pub
+func New(pub queue.Publisher, topic string) *Feed {
+	f := &Feed{pub: pub, topic: topic, pending: make(chan [][]byte, pendingBuffer)}
+	go f.send()
+	return f
+}
+
+// Publish queues changes to be sent to the changefeed topic as one batch
+func (f *Feed) Publish(changes ...Change) {
+	if f == nil || len(changes) == 0 {
+		return
+	}
+	bodies, err := encode(changes)
+	if err != nil {
+		metrics.RecordChangefeedDropped(len(changes))
+		return
+	}
+	select {
+	case f.pending <- bodies:
+	default:
+		metrics.RecordChangefeedDropped(len(bodies))
+	}
+}
+
+func (f *Feed) send() {
+	for bodies := range f.pending {
+		dropped := 0
+		for _, err := range f.pub.PublishBatch(f.topic, bodies) {
+			if err != nil {
+				dropped++
+			}
+		}
+		if dropped > 0 {
+			metrics.RecordChangefeedDropped(dropped)
+		}
+	}
+}
+
+func encode(changes []Change) ([][]byte, error) {
+	bodies := make([][]byte, 0, len(changes))
+	for _, c := range changes {
+		b, err := json.Marshal(c)
+		if err != nil {
+			return nil, err
+		}
+		bodies = append(bodies, b)
+	}
+	return bodies, nil
+}
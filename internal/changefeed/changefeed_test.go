@@ -0,0 +1,72 @@
+package changefeed
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+)
+
+func TestFromTask(t *testing.T) {
+	task := delivery.Task{
+		DeliveryID: "delivery-1",
+		EventID:    "event-1",
+		TenantID:   "tn_1",
+		EndpointID: "endpoint-1",
+		EventType:  "user.created",
+		Attempt:    2,
+	}
+
+	before := time.Now().UTC()
+	c := FromTask(task, "failed", "inflight")
+	if c.DeliveryID != "delivery-1" || c.EventID != "event-1" || c.TenantID != "tn_1" ||
+		c.EndpointID != "endpoint-1" || c.EventType != "user.created" || c.Attempt != 2 {
+		t.Errorf("FromTask() = %+v, want the task's identifiers", c)
+	}
+	if c.From != "failed" || c.To != "inflight" {
+		t.Errorf("FromTask() transition = %s -> %s, want failed -> inflight", c.From, c.To)
+	}
+	if c.At.Before(before) {
+		t.Errorf("FromTask() At = %v, want at or after %v", c.At, before)
+	}
+}
+
+func TestEncode(t *testing.T) {
+	at := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	bodies, err := encode([]Change{
+		{DeliveryID: "d1", To: "queued", At: at},
+		{DeliveryID: "d1", From: "inflight", To: "failed", Attempt: 1, HTTPStatus: 503, Error: "unavailable", At: at},
+	})
+	if err != nil {
+		t.Fatalf("encode() error: %v", err)
+	}
+	if len(bodies) != 2 {
+		t.Fatalf("encode() returned %d bodies, want 2", len(bodies))
+	}
+
+	var created map[string]any
+	if err := json.Unmarshal(bodies[0], &created); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if _, ok := created["from"]; ok {
+		t.Error("new delivery change should omit from")
+	}
+	if created["to"] != "queued" || created["at"] != "2025-01-01T12:00:00Z" {
+		t.Errorf("created change = %v", created)
+	}
+
+	var failed Change
+	if err := json.Unmarshal(bodies[1], &failed); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if failed.HTTPStatus != 503 || failed.Error != "unavailable" || failed.From != "inflight" {
+		t.Errorf("failed change = %+v", failed)
+	}
+}
+
+func TestNilFeedPublish(t *testing.T) {
+	var f *Feed
+	// Must not panic when the changefeed isn't configured
+	f.Publish(Change{DeliveryID: "d1", To: "queued"})
+}
@@ -0,0 +1,104 @@
+package compliance
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// DefaultRetentionDays is used when a tenant enables recording without a retention period
+const DefaultRetentionDays = 30
+
+// MaxRetentionDays caps how long a recording may be kept
+const MaxRetentionDays = 3650
+
+// Request is a complete signed delivery request, as sent to the endpoint
+type Request struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+	Body    []byte            `json:"body"`
+}
+
+// Capture copies the method, URL and headers of req together with the body that was signed
+func Capture(req *http.Request, body []byte) Request {
+	headers := make(map[string]string, len(req.Header))
+	for k := range req.Header {
+		headers[k] = req.Header.Get(k)
+	}
+	return Request{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: headers,
+		Body:    append([]byte(nil), body...),
+	}
+}
+
+// Cipher encrypts recordings at rest with AES-256-GCM
+type Cipher struct {
+	aead  cipher.AEAD
+	keyID string
+}
+
+// NewCipher creates a Cipher from a base64-encoded 32 byte key
+func NewCipher(keyB64 string) (*Cipher, error) {
+	key, err := base64.StdEncoding.DecodeString(keyB64)
+	if err != nil {
+		return nil, fmt.Errorf("decode recording key: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("recording key must be 32 bytes, got %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(key)
+	return &Cipher{aead: aead, keyID: hex.EncodeToString(sum[:4])}, nil
+}
+
+// KeyID identifies the key without revealing it, so rows sealed with an old key can be told apart
+func (c *Cipher) KeyID() string {
+	return c.keyID
+}
+
+// Seal serializes and encrypts r. The random nonce is prepended to the ciphertext
+func (c *Cipher) Seal(r Request) ([]byte, error) {
+	plain, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, plain, []byte(c.keyID)), nil
+}
+
+// Open decrypts a recording produced by Seal
+func (c *Cipher) Open(sealed []byte) (Request, error) {
+	var r Request
+	if len(sealed) < c.aead.NonceSize() {
+		return r, errors.New("recording too short")
+	}
+	nonce, ct := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, ct, []byte(c.keyID))
+	if err != nil {
+		return r, fmt.Errorf("decrypt recording: %w", err)
+	}
+	if err := json.Unmarshal(plain, &r); err != nil {
+		return r, fmt.Errorf("decode recording: %w", err)
+	}
+	return r, nil
+}
@@ -0,0 +1,87 @@
+package compliance
+
+import (
+	"bytes"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func TestNewCipher(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		expectError bool
+	}{
+		{name: "valid key", key: testKey(1)},
+		{name: "not base64", key: "not-base64!", expectError: true},
+		{name: "short key", key: base64.StdEncoding.EncodeToString([]byte("short")), expectError: true},
+		{name: "empty key", key: "", expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewCipher(tt.key)
+			if tt.expectError {
+				if err == nil {
+					t.Error("NewCipher() expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewCipher() unexpected error: %v", err)
+			}
+			if c.KeyID() == "" {
+				t.Error("KeyID() is empty")
+			}
+		})
+	}
+}
+
+func TestCipher_SealOpen(t *testing.T) {
+	c, err := NewCipher(testKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body := []byte(`{"user":{"id":1}}`)
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/hook", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-HarborHook-Signature", "sha256=abc")
+	rec := Capture(req, body)
+
+	sealed, err := c.Seal(rec)
+	if err != nil {
+		t.Fatalf("Seal() error: %v", err)
+	}
+	if bytes.Contains(sealed, body) || strings.Contains(string(sealed), "sha256=abc") {
+		t.Error("Seal() output contains plaintext")
+	}
+
+	got, err := c.Open(sealed)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	if got.Method != http.MethodPost || got.URL != "https://example.com/hook" {
+		t.Errorf("Open() = %s %s, want POST https://example.com/hook", got.Method, got.URL)
+	}
+	if !bytes.Equal(got.Body, body) {
+		t.Errorf("Open() body = %s, want %s", got.Body, body)
+	}
+	if got.Headers["X-Harborhook-Signature"] != "sha256=abc" {
+		t.Errorf("Open() headers = %v, missing signature", got.Headers)
+	}
+
+	other, _ := NewCipher(testKey(2))
+	if _, err := other.Open(sealed); err == nil {
+		t.Error("Open() with another key expected error but got none")
+	}
+	if _, err := c.Open(sealed[:4]); err == nil {
+		t.Error("Open() of truncated recording expected error but got none")
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/version"
 )
 
 type DB struct {
@@ -16,43 +18,231 @@ type DB struct {
 	Name string
 }
 
+type Queue struct {
+	Backend      string   // Message broker: nsq (default), kafka or sqs
+	KafkaBrokers []string // Kafka bootstrap brokers, e.g. kafka:9092
+
+	SQSQueuePrefix       string        // Prepended to topic names to form SQS queue names
+	SQSEndpoint          string        // Overrides the SQS endpoint, e.g. for LocalStack; empty uses AWS
+	SQSVisibilityTimeout time.Duration // How long a received message stays hidden before SQS hands it out again
+	SQSMaxReceives       int           // Receives before SQS moves a message to the DLQ topic's queue; 0 disables redrive
+}
+
 type NSQ struct {
-	NsqdTCPAddr     string // e.g. nsqd:4150
-	LookupHTTPAddr  string // e.g. http://nsqlookupd:4161
-	DeliveriesTopic string // NSQ topic for webhook deliveries
-	DLQTopic        string // Dead letter queue topic
-	WorkerChannel   string // NSQ channel name for workers
-	SignatureHeader string // HTTP header for webhook signature
-	TimestampHeader string // HTTP header for webhook timestamp
+	NsqdTCPAddr       string // e.g. nsqd:4150
+	NsqdHTTPAddr      string // e.g. nsqd:4151, for queue stats
+	LookupHTTPAddr    string // e.g. http://nsqlookupd:4161
+	DeliveriesTopic   string // NSQ topic for webhook deliveries
+	DLQTopic          string // Dead letter queue topic
+	ChangefeedTopic   string // Topic for delivery state transitions
+	WorkerChannel     string // NSQ channel name for workers
+	SignatureHeader   string // HTTP header for webhook signature
+	TimestampHeader   string // HTTP header for webhook timestamp
+	DeliveryHeader    string // HTTP header carrying the delivery ID receivers acknowledge
+	EventIDHeader     string // HTTP header carrying the event ID, shared by an event's deliveries
+	EventTypeHeader   string // HTTP header carrying the event type
+	AttemptHeader     string // HTTP header carrying the attempt number, from 1
+	IdempotencyHeader string // HTTP header carrying the key receivers dedupe on, constant across retries
+	TenantHeader      string // HTTP header identifying the sending tenant (per-tenant toggle)
+	UserAgent         string // User-Agent sent with every delivery
+
+	// nsqd addresses publishers spread over and fail over between; empty uses NsqdTCPAddr
+	NsqdTCPAddrs []string
+	// nsqlookupd HTTP addresses consumers discover nsqd through; empty uses LookupHTTPAddr
+	LookupHTTPAddrs []string
+	// How often consumers ask nsqlookupd for nsqd that have appeared or gone
+	LookupPollInterval time.Duration
+	// Whether consumers also connect straight to NsqdTCPAddr, which creates their channel before
+	// anything is published
+	ConsumeNsqdDirect bool
+	// Whether ingest publishes tasks to a topic per event priority (DeliveriesTopic_high, _normal,
+	// _low) and workers consume those alongside DeliveriesTopic
+	PriorityTopics bool
+	// Messages a worker handles at once, across all the delivery topics it consumes
+	MaxInFlight int
+	// Share of MaxInFlight each priority topic gets, by priority, e.g. high=6,normal=3,low=1
+	PriorityWeights map[string]int
 }
 
 type Worker struct {
-	MaxAttempts     int             // Maximum delivery attempts
-	BackoffSchedule []time.Duration // Retry backoff durations
-	JitterPercent   float64         // Backoff jitter percentage (0.0-1.0)
-	PublishDLQ      bool            // Whether to publish failed deliveries to DLQ
-	HTTPPort        string          // Worker HTTP metrics port
+	MaxAttempts       int             // Maximum delivery attempts
+	BackoffSchedule   []time.Duration // Retry backoff durations
+	JitterPercent     float64         // Backoff jitter percentage (0.0-1.0)
+	PublishDLQ        bool            // Whether to publish failed deliveries to DLQ
+	HTTPPort          string          // Worker HTTP metrics port
+	MaxDeferral       time.Duration   // Longest delay nsqd accepts for a deferred publish (its --max-req-timeout)
+	ClientCertDir     string          // Where secrets holding endpoint client certificates are mounted, one directory each
+	DrainTimeout      time.Duration   // How long shutdown waits for deliveries in flight before requeueing them
+	StallTimeout      time.Duration   // How long the consumer may go without a message while its channel has a backlog before /readyz fails
+	HTTP              WorkerHTTP      // Outbound webhook client
+	RetryBudget       int             // Retries each endpoint may schedule per minute before the rest are pushed out to RetryBudgetDelay; 0 disables
+	RetryBudgetDelay  time.Duration   // Shortest delay for retries over the budget
+	ResponseBodyLimit int             // Bytes of a non-2xx response body kept on the attempt record; 0 keeps none
+	SLILatencyTarget  time.Duration   // Successful attempts at most this slow are good for the latency SLI
+	HealthCheck       HealthCheck     // Opt-in endpoint health checks
+}
+
+// AutoDisable disables endpoints that keep failing, so dead receivers stop taking deliveries
+type AutoDisable struct {
+	Every         time.Duration // How often ingest looks for failing endpoints; 0 never disables them
+	DeadStreak    int           // Deliveries dead-lettered in a row, within Window, that disable an endpoint; 0 ignores streaks
+	FailureRate   float64       // Share of deliveries failing over Window that disables an endpoint; 0 ignores the rate
+	Window        time.Duration // How long the failure rate must hold, and how far back streaks are counted
+	MinDeliveries int           // Deliveries that must finish within Window before its failure rate counts
+}
+
+// Notifications sends tenants' ops contacts notices about their endpoints and DLQ
+type Notifications struct {
+	Every     time.Duration // How often ingest looks for notices to send; 0 never sends them
+	SMTPAddr  string        // SMTP server host:port notices are emailed through; empty emails none
+	SMTPUser  string        // SMTP username; empty sends without authenticating
+	SMTPPass  string
+	EmailFrom string
+}
+
+// HealthCheck tunes the health checks workers send to endpoints that opt in
+type HealthCheck struct {
+	Every     time.Duration // How often each endpoint is checked; 0 stops this worker checking
+	Timeout   time.Duration // Longest a check may take; an endpoint's shorter request timeout wins
+	Failures  int           // Failed checks in a row that make an endpoint unhealthy
+	Successes int           // Passed checks in a row that make an unhealthy endpoint healthy again
+}
+
+// WorkerHTTP tunes the client webhooks are sent with. Zero timeouts and limits mean none.
+type WorkerHTTP struct {
+	Timeout               time.Duration // Whole request, including reading the response, for endpoints without their own timeout
+	MaxTimeout            time.Duration // Cap on endpoints' own timeouts
+	DialTimeout           time.Duration // TCP connect
+	KeepAlive             time.Duration // TCP keep-alive probe interval; negative disables probes
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration // From the request being written to the response headers
+	IdleConnTimeout       time.Duration // How long an idle pooled connection is kept
+	MaxIdleConns          int           // Idle connections kept across all hosts
+	MaxIdleConnsPerHost   int           // Idle connections kept per receiver host
+	MaxConnsPerHost       int           // Connections per receiver host, including active ones
+	HTTP2                 bool          // Negotiate HTTP/2 with receivers that offer it
+	DNSCacheTTL           time.Duration // How long a receiver host's DNS answer is reused; 0 resolves every dial
+	DNSNegativeTTL        time.Duration // How long a failed lookup is returned without asking again; 0 caches no failures
+	DNSStaleTTL           time.Duration // How long past DNSCacheTTL an answer is used while lookups fail
 }
 
 type FakeReceiver struct {
 	FailFirstN           int           // Number of requests to fail initially
 	EndpointSecret       string        // Secret for webhook signature verification
 	SigningLeewaySeconds int           // Allowed timestamp skew in seconds
+	SigningKeysURL       string        // JWK set of tenant Ed25519 public keys, for ed25519 signatures
 	ResponseDelayMS      int           // Simulated response delay in milliseconds
+	ChaosConfig          string        // JSON chaos profiles per path, or the path of a file with them
+	RecordLimit          int           // Most requests /received keeps; the oldest are dropped
+	RecordFile           string        // File recorded requests are appended to and reloaded from; empty keeps them in memory
 	Port                 string        // Server listen port
 	ReadTimeout          time.Duration // HTTP read timeout
 	WriteTimeout         time.Duration // HTTP write timeout
 	IdleTimeout          time.Duration // HTTP idle timeout
 }
 
+// Alerter evaluates operator alert rules and sends notifications without Alertmanager
+type Alerter struct {
+	Rules           string        // JSON alert rules, or the path of a file with them; empty uses the built-in rules
+	EvalInterval    time.Duration // How often rules are evaluated
+	RepeatInterval  time.Duration // How often a firing alert is notified again
+	PrometheusURL   string        // Prometheus base URL promql rules query
+	SlackWebhookURL string        // Slack incoming webhook notifications are posted to
+	WebhookURL      string        // URL notifications are POSTed to as JSON
+	SMTPAddr        string        // SMTP server host:port email notifications are sent through
+	SMTPUser        string        // SMTP username; empty sends without authenticating
+	SMTPPass        string
+	EmailFrom       string
+	EmailTo         []string // Recipients of email notifications; empty disables email
+	Port            string   // HTTP port for /alerts, /metrics and /health
+}
+
+type Compliance struct {
+	RecordingKey        string        // Base64 AES-256 key for encrypting recorded requests; empty disables recording
+	RecordingPurgeEvery time.Duration // How often expired recordings are deleted
+}
+
+type ClaimCheck struct {
+	ThresholdBytes int    // Payloads larger than this, as JSON, travel as a reference to the blob store; 0 keeps every payload in its tasks
+	Store          string // Blob store holding referenced payloads: postgres (default) or s3
+	S3Bucket       string // Bucket for the s3 store
+	S3Prefix       string // Prepended to object keys
+	S3Endpoint     string // Overrides the S3 endpoint, e.g. for MinIO or LocalStack; empty uses AWS
+	S3Region       string // Overrides the region from the AWS default config
+}
+
+// DLQ bounds the dead letter queue entries tenants keep, unless a tenant sets its own retention
+type DLQ struct {
+	MaxAge     time.Duration // Entries older than this are purged; 0 keeps them regardless of age
+	MaxEntries int           // Newest entries kept per tenant; 0 keeps any number
+	Archive    bool          // Copy purged entries to harborhook.dlq_archive first
+	PurgeEvery time.Duration // How often the worker purges expired entries; 0 disables it
+}
+
+// Archive moves old events and delivered deliveries out of Postgres into object storage
+type Archive struct {
+	AfterDays int           // Events and delivered deliveries older than this many days are archived; 0 disables archiving
+	Every     time.Duration // How often the worker archives
+	BatchSize int           // Rows per archive object
+	Store     string        // Where archives are written: s3 (default), gcs or file
+	Bucket    string        // Bucket for the s3 and gcs stores
+	Prefix    string        // Prepended to object keys
+	Endpoint  string        // Overrides the S3 endpoint, e.g. for MinIO; empty uses AWS, or GCS for gcs
+	Region    string        // Overrides the region from the AWS default config
+	Dir       string        // Directory for the file store
+}
+
+// Metrics bounds the tenant_id and endpoint_id label values metrics are recorded with
+type Metrics struct {
+	LabelMode        string   // all (default), allowlist, hash or topk
+	LabelAllowlist   []string // Tenant and endpoint IDs kept as labels in allowlist mode
+	LabelHashBuckets int      // Buckets IDs are hashed into in hash mode
+	LabelTopK        int      // IDs kept per label in topk mode; later ones are recorded as other
+}
+
+// Log selects how services log
+type Log struct {
+	Level      string   // debug, info, warn or error
+	Format     string   // json or text
+	File       string   // Path logs are appended to instead of stdout
+	MaxSizeMB  int      // Size at which File is rotated; 0 never rotates
+	MaxBackups int      // Rotated files kept
+	RedactKeys []string // Keys whose values are masked in logs and spans, on top of the defaults
+}
+
 type Config struct {
 	AppName      string
 	HTTPPort     string // :8080
 	GRPCPort     string // :50051
+	Log          Log
 	DB           DB
+	Queue        Queue
 	NSQ          NSQ
 	Worker       Worker
 	FakeReceiver FakeReceiver
+	Alerter      Alerter
+	Compliance   Compliance
+	ClaimCheck   ClaimCheck
+	Metrics      Metrics
+	DLQ          DLQ
+	Archive      Archive
+	AutoDisable  AutoDisable
+	Notify       Notifications
+
+	BusinessMetricsEvery time.Duration // How often business KPIs are aggregated; 0 disables them
+	OutboxRelayEvery     time.Duration // How often unsent outbox rows are republished to NSQ
+	ScheduledEvery       time.Duration // How often scheduled events that have come due are fanned out
+	AdminUI              bool          // Serve the embedded admin console at /admin/ui/
+	GRPCReflection       bool          // Serve the gRPC reflection service, so grpcurl and grpcui can describe the API
+	AnomalyDetectEvery   time.Duration // How often endpoint response codes are checked for anomalies; 0 disables it
+	EndpointVerification bool          // Challenge new endpoints and hold their deliveries until they echo the token
+	EgressAllowlist      []string      // Hostnames and CIDRs webhooks may reach even though they are private (development only)
+	EgressProxy          string        // HTTP(S) proxy webhooks and endpoint challenges are sent through; empty sends them directly
+	EgressProxyUsername  string        // Credentials for the proxy, replacing any in EgressProxy
+	EgressProxyPassword  string        // Password for EgressProxyUsername
+	EgressProxyFromEnv   bool          // Without EgressProxy, use HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+	EgressIPs            []string      // Addresses and CIDRs webhooks are sent from, published for receivers' firewalls
+	MaxPayloadBytes      int           // Largest event payload, as JSON, that publishes accept; 0 removes the limit
 }
 
 func getenv(key, def string) string {
@@ -121,11 +311,46 @@ func parseBackoffSchedule(schedule string) []time.Duration {
 	return durations
 }
 
+// splitList splits a comma-separated list, dropping empty items
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// parseWeights parses a comma-separated list of name=weight pairs, dropping pairs without a
+// positive integer weight
+func parseWeights(s string) map[string]int {
+	out := map[string]int{}
+	for _, part := range splitList(s) {
+		name, weight, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		if w, err := strconv.Atoi(strings.TrimSpace(weight)); err == nil && w > 0 {
+			out[strings.TrimSpace(name)] = w
+		}
+	}
+	return out
+}
+
 func FromEnv() Config {
 	return Config{
 		AppName:  getenv("APP_NAME", "harborhook"),
 		HTTPPort: getenv("HTTP_PORT", ":8080"),
 		GRPCPort: getenv("GRPC_PORT", ":50051"),
+		Log: Log{
+			Level:      getenv("LOG_LEVEL", "info"),
+			Format:     getenv("LOG_FORMAT", "json"),
+			File:       getenv("LOG_FILE", ""),
+			MaxSizeMB:  getenvInt("LOG_FILE_MAX_SIZE_MB", 100),
+			MaxBackups: getenvInt("LOG_FILE_MAX_BACKUPS", 5),
+			RedactKeys: splitList(getenv("LOG_REDACT_KEYS", "")),
+		},
 		DB: DB{
 			User: getenv("DB_USER", "postgres"),
 			Pass: getenv("DB_PASS", "postgres"),
@@ -133,14 +358,40 @@ func FromEnv() Config {
 			Port: getenv("DB_PORT", "5432"),
 			Name: getenv("DB_NAME", "harborhook"),
 		},
+		Queue: Queue{
+			Backend:      getenv("QUEUE_BACKEND", "nsq"),
+			KafkaBrokers: splitList(getenv("KAFKA_BROKERS", "kafka:9092")),
+
+			SQSQueuePrefix:       getenv("SQS_QUEUE_PREFIX", "harborhook-"),
+			SQSEndpoint:          getenv("SQS_ENDPOINT", ""),
+			SQSVisibilityTimeout: getenvDuration("SQS_VISIBILITY_TIMEOUT", time.Minute),
+			SQSMaxReceives:       getenvInt("SQS_MAX_RECEIVES", 0),
+		},
 		NSQ: NSQ{
-			NsqdTCPAddr:     getenv("NSQD_TCP_ADDR", "nsqd:4150"),
-			LookupHTTPAddr:  getenv("NSQ_LOOKUP_HTTP_ADDR", "http://nsqlookupd:4161"),
-			DeliveriesTopic: getenv("NSQ_DELIVERIES_TOPIC", "deliveries"),
-			DLQTopic:        getenv("NSQ_DLQ_TOPIC", "deliveries_dlq"),
-			WorkerChannel:   getenv("NSQ_WORKER_CHANNEL", "workers"),
-			SignatureHeader: getenv("WEBHOOK_SIGNATURE_HEADER", "X-HarborHook-Signature"),
-			TimestampHeader: getenv("WEBHOOK_TIMESTAMP_HEADER", "X-HarborHook-Timestamp"),
+			NsqdTCPAddr:       getenv("NSQD_TCP_ADDR", "nsqd:4150"),
+			NsqdTCPAddrs:      splitList(getenv("NSQD_TCP_ADDRS", "")),
+			NsqdHTTPAddr:      getenv("NSQD_HTTP_ADDR", "nsqd:4151"),
+			LookupHTTPAddr:    getenv("NSQ_LOOKUP_HTTP_ADDR", "http://nsqlookupd:4161"),
+			DeliveriesTopic:   getenv("NSQ_DELIVERIES_TOPIC", "deliveries"),
+			DLQTopic:          getenv("NSQ_DLQ_TOPIC", "deliveries_dlq"),
+			ChangefeedTopic:   getenv("NSQ_CHANGEFEED_TOPIC", "delivery_changes"),
+			WorkerChannel:     getenv("NSQ_WORKER_CHANNEL", "workers"),
+			SignatureHeader:   getenv("WEBHOOK_SIGNATURE_HEADER", "X-HarborHook-Signature"),
+			TimestampHeader:   getenv("WEBHOOK_TIMESTAMP_HEADER", "X-HarborHook-Timestamp"),
+			DeliveryHeader:    getenv("WEBHOOK_DELIVERY_HEADER", "X-HarborHook-Delivery-Id"),
+			EventIDHeader:     getenv("WEBHOOK_EVENT_ID_HEADER", "X-HarborHook-Event-Id"),
+			EventTypeHeader:   getenv("WEBHOOK_EVENT_TYPE_HEADER", "X-HarborHook-Event-Type"),
+			AttemptHeader:     getenv("WEBHOOK_ATTEMPT_HEADER", "X-HarborHook-Attempt"),
+			IdempotencyHeader: getenv("WEBHOOK_IDEMPOTENCY_HEADER", "X-HarborHook-Idempotency"),
+			TenantHeader:      getenv("WEBHOOK_TENANT_HEADER", "X-HarborHook-Tenant"),
+			UserAgent:         getenv("WEBHOOK_USER_AGENT", "harborhook/"+version.Version),
+
+			LookupHTTPAddrs:    splitList(getenv("NSQ_LOOKUP_HTTP_ADDRS", "")),
+			LookupPollInterval: getenvDuration("NSQ_LOOKUP_POLL_INTERVAL", time.Minute),
+			ConsumeNsqdDirect:  getenvBool("NSQ_CONSUME_NSQD_DIRECT", true),
+			PriorityTopics:     getenvBool("NSQ_PRIORITY_TOPICS", false),
+			MaxInFlight:        getenvInt("NSQ_MAX_IN_FLIGHT", 1500),
+			PriorityWeights:    parseWeights(getenv("NSQ_PRIORITY_WEIGHTS", "high=6,normal=3,low=1")),
 		},
 		Worker: Worker{
 			MaxAttempts:     getenvInt("MAX_ATTEMPTS", 6),
@@ -148,17 +399,129 @@ func FromEnv() Config {
 			JitterPercent:   getenvFloat("BACKOFF_JITTER_PCT", 0.25),
 			PublishDLQ:      getenvBool("PUBLISH_DLQ_TOPIC", false),
 			HTTPPort:        ":" + getenv("WORKER_HTTP_PORT", "8083"),
+			MaxDeferral:     getenvDuration("NSQ_MAX_REQ_TIMEOUT", time.Hour),
+			ClientCertDir:   getenv("CLIENT_CERT_DIR", "/etc/harborhook/client-certs"),
+			DrainTimeout:    getenvDuration("WORKER_DRAIN_TIMEOUT", 20*time.Second),
+			StallTimeout:    getenvDuration("WORKER_STALL_TIMEOUT", 2*time.Minute),
+			HTTP: WorkerHTTP{
+				Timeout:               getenvDuration("HTTP_CLIENT_TIMEOUT", 15*time.Second),
+				MaxTimeout:            getenvDuration("WORKER_HTTP_MAX_TIMEOUT", 2*time.Minute),
+				DialTimeout:           getenvDuration("WORKER_HTTP_DIAL_TIMEOUT", 10*time.Second),
+				KeepAlive:             getenvDuration("WORKER_HTTP_KEEPALIVE", 30*time.Second),
+				TLSHandshakeTimeout:   getenvDuration("WORKER_HTTP_TLS_HANDSHAKE_TIMEOUT", 10*time.Second),
+				ResponseHeaderTimeout: getenvDuration("WORKER_HTTP_RESPONSE_HEADER_TIMEOUT", 0),
+				IdleConnTimeout:       getenvDuration("WORKER_HTTP_IDLE_CONN_TIMEOUT", 90*time.Second),
+				MaxIdleConns:          getenvInt("WORKER_HTTP_MAX_IDLE_CONNS", 512),
+				MaxIdleConnsPerHost:   getenvInt("WORKER_HTTP_MAX_IDLE_CONNS_PER_HOST", 32),
+				MaxConnsPerHost:       getenvInt("WORKER_HTTP_MAX_CONNS_PER_HOST", 0),
+				HTTP2:                 getenvBool("WORKER_HTTP2", true),
+				DNSCacheTTL:           getenvDuration("WORKER_DNS_CACHE_TTL", 30*time.Second),
+				DNSNegativeTTL:        getenvDuration("WORKER_DNS_NEGATIVE_TTL", 5*time.Second),
+				DNSStaleTTL:           getenvDuration("WORKER_DNS_STALE_TTL", 5*time.Minute),
+			},
+			RetryBudget:       getenvInt("WORKER_RETRY_BUDGET", 120),
+			RetryBudgetDelay:  getenvDuration("WORKER_RETRY_BUDGET_DELAY", 10*time.Minute),
+			ResponseBodyLimit: getenvInt("WORKER_RESPONSE_BODY_LIMIT", 4096),
+			SLILatencyTarget:  getenvDuration("WORKER_SLI_LATENCY_TARGET", 5*time.Second),
+			HealthCheck: HealthCheck{
+				Every:     getenvDuration("HEALTH_CHECK_INTERVAL", 30*time.Second),
+				Timeout:   getenvDuration("HEALTH_CHECK_TIMEOUT", 5*time.Second),
+				Failures:  getenvInt("HEALTH_CHECK_FAILURES", 3),
+				Successes: getenvInt("HEALTH_CHECK_SUCCESSES", 2),
+			},
 		},
 		FakeReceiver: FakeReceiver{
 			FailFirstN:           getenvInt("FAIL_FIRST_N", 0),
 			EndpointSecret:       getenv("ENDPOINT_SECRET", ""),
 			SigningLeewaySeconds: getenvInt("SIGNING_LEEWAY_SECONDS", 300),
+			SigningKeysURL:       getenv("SIGNING_KEYS_URL", ""),
 			ResponseDelayMS:      getenvInt("RESPONSE_DELAY_MS", 0),
+			ChaosConfig:          getenv("CHAOS_CONFIG", ""),
+			RecordLimit:          getenvInt("RECORD_LIMIT", 1000),
+			RecordFile:           getenv("RECORD_FILE", ""),
 			Port:                 getenv("FAKE_RECEIVER_PORT", ":8081"),
 			ReadTimeout:          getenvDuration("FAKE_RECEIVER_READ_TIMEOUT", 10*time.Second),
 			WriteTimeout:         getenvDuration("FAKE_RECEIVER_WRITE_TIMEOUT", 10*time.Second),
 			IdleTimeout:          getenvDuration("FAKE_RECEIVER_IDLE_TIMEOUT", 60*time.Second),
 		},
+		Alerter: Alerter{
+			Rules:           getenv("ALERTER_RULES", ""),
+			EvalInterval:    getenvDuration("ALERTER_EVAL_INTERVAL", 30*time.Second),
+			RepeatInterval:  getenvDuration("ALERTER_REPEAT_INTERVAL", 4*time.Hour),
+			PrometheusURL:   getenv("ALERTER_PROMETHEUS_URL", ""),
+			SlackWebhookURL: getenv("ALERTER_SLACK_WEBHOOK_URL", ""),
+			WebhookURL:      getenv("ALERTER_WEBHOOK_URL", ""),
+			SMTPAddr:        getenv("ALERTER_SMTP_ADDR", ""),
+			SMTPUser:        getenv("ALERTER_SMTP_USER", ""),
+			SMTPPass:        getenv("ALERTER_SMTP_PASS", ""),
+			EmailFrom:       getenv("ALERTER_EMAIL_FROM", "harborhook-alerter@localhost"),
+			EmailTo:         splitList(getenv("ALERTER_EMAIL_TO", "")),
+			Port:            getenv("ALERTER_PORT", "8085"),
+		},
+		Compliance: Compliance{
+			RecordingKey:        getenv("RECORDING_ENCRYPTION_KEY", ""),
+			RecordingPurgeEvery: getenvDuration("RECORDING_PURGE_INTERVAL", time.Hour),
+		},
+		ClaimCheck: ClaimCheck{
+			ThresholdBytes: getenvInt("CLAIM_CHECK_THRESHOLD_BYTES", 64*1024),
+			Store:          getenv("BLOB_STORE", "postgres"),
+			S3Bucket:       getenv("BLOB_S3_BUCKET", ""),
+			S3Prefix:       getenv("BLOB_S3_PREFIX", "payloads/"),
+			S3Endpoint:     getenv("BLOB_S3_ENDPOINT", ""),
+			S3Region:       getenv("BLOB_S3_REGION", ""),
+		},
+		Metrics: Metrics{
+			LabelMode:        getenv("METRICS_LABEL_MODE", "all"),
+			LabelAllowlist:   splitList(getenv("METRICS_LABEL_ALLOWLIST", "")),
+			LabelHashBuckets: getenvInt("METRICS_LABEL_HASH_BUCKETS", 64),
+			LabelTopK:        getenvInt("METRICS_LABEL_TOPK", 200),
+		},
+		DLQ: DLQ{
+			MaxAge:     getenvDuration("DLQ_MAX_AGE", 0),
+			MaxEntries: getenvInt("DLQ_MAX_ENTRIES", 0),
+			Archive:    getenvBool("DLQ_ARCHIVE", false),
+			PurgeEvery: getenvDuration("DLQ_PURGE_INTERVAL", time.Hour),
+		},
+		AutoDisable: AutoDisable{
+			Every:         getenvDuration("AUTO_DISABLE_INTERVAL", 5*time.Minute),
+			DeadStreak:    getenvInt("AUTO_DISABLE_DEAD_STREAK", 25),
+			FailureRate:   getenvFloat("AUTO_DISABLE_FAILURE_RATE", 0.95),
+			Window:        getenvDuration("AUTO_DISABLE_WINDOW", 72*time.Hour),
+			MinDeliveries: getenvInt("AUTO_DISABLE_MIN_DELIVERIES", 20),
+		},
+		Notify: Notifications{
+			Every:     getenvDuration("NOTIFY_INTERVAL", time.Minute),
+			SMTPAddr:  getenv("NOTIFY_SMTP_ADDR", ""),
+			SMTPUser:  getenv("NOTIFY_SMTP_USER", ""),
+			SMTPPass:  getenv("NOTIFY_SMTP_PASS", ""),
+			EmailFrom: getenv("NOTIFY_EMAIL_FROM", "harborhook@localhost"),
+		},
+		Archive: Archive{
+			AfterDays: getenvInt("ARCHIVE_AFTER_DAYS", 0),
+			Every:     getenvDuration("ARCHIVE_INTERVAL", time.Hour),
+			BatchSize: getenvInt("ARCHIVE_BATCH_SIZE", 5000),
+			Store:     getenv("ARCHIVE_STORE", "s3"),
+			Bucket:    getenv("ARCHIVE_BUCKET", ""),
+			Prefix:    getenv("ARCHIVE_PREFIX", "archive/"),
+			Endpoint:  getenv("ARCHIVE_ENDPOINT", ""),
+			Region:    getenv("ARCHIVE_REGION", ""),
+			Dir:       getenv("ARCHIVE_DIR", ""),
+		},
+
+		BusinessMetricsEvery: getenvDuration("BUSINESS_METRICS_INTERVAL", 5*time.Minute),
+		OutboxRelayEvery:     getenvDuration("OUTBOX_RELAY_INTERVAL", 5*time.Second),
+		ScheduledEvery:       getenvDuration("SCHEDULED_DISPATCH_INTERVAL", time.Second),
+		AdminUI:              getenvBool("ADMIN_UI_ENABLED", true),
+		GRPCReflection:       getenvBool("GRPC_REFLECTION_ENABLED", true),
+		AnomalyDetectEvery:   getenvDuration("ANOMALY_DETECT_INTERVAL", 5*time.Minute),
+		EndpointVerification: getenvBool("ENDPOINT_VERIFICATION", true),
+		EgressAllowlist:      splitList(getenv("EGRESS_ALLOWLIST", "")),
+		EgressProxy:          getenv("EGRESS_PROXY", ""),
+		EgressProxyUsername:  getenv("EGRESS_PROXY_USERNAME", ""),
+		EgressProxyPassword:  getenv("EGRESS_PROXY_PASSWORD", ""),
+		EgressProxyFromEnv:   getenvBool("EGRESS_PROXY_FROM_ENV", false),
+		EgressIPs:            splitList(getenv("EGRESS_IPS", "")),
+		MaxPayloadBytes:      getenvInt("MAX_PAYLOAD_BYTES", 256*1024),
 	}
 }
 
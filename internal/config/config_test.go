@@ -619,3 +619,57 @@ func TestParseBackoffSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []string
+	}{
+		{name: "single", in: "kafka:9092", want: []string{"kafka:9092"}},
+		{name: "several with spaces", in: "k1:9092, k2:9092 ,k3:9092", want: []string{"k1:9092", "k2:9092", "k3:9092"}},
+		{name: "empty items dropped", in: "k1:9092,,", want: []string{"k1:9092"}},
+		{name: "empty", in: "", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitList(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("splitList(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("splitList(%q)[%d] = %q, want %q", tt.in, i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseWeights(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]int
+	}{
+		{name: "defaults", in: "high=6,normal=3,low=1", want: map[string]int{"high": 6, "normal": 3, "low": 1}},
+		{name: "spaces", in: " high = 2 , low=1", want: map[string]int{"high": 2, "low": 1}},
+		{name: "invalid pairs dropped", in: "high=x,normal,low=0,bulk=-1,urgent=4", want: map[string]int{"urgent": 4}},
+		{name: "empty", in: "", want: map[string]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseWeights(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("parseWeights(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			for name, w := range tt.want {
+				if got[name] != w {
+					t.Errorf("parseWeights(%q)[%q] = %d, want %d", tt.in, name, got[name], w)
+				}
+			}
+		})
+	}
+}
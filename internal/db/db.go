@@ -4,9 +4,22 @@ import (
 	"context"
 	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// Pool is the part of *pgxpool.Pool the services query through, so tests and benchmarks
+// can run them against dbfake instead of Postgres
+type Pool interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+var _ Pool = (*pgxpool.Pool)(nil)
+
 // Connect establishes a connection pool to the database and returns the pool
 func Connect(ctx context.Context, dsn string) (*pgxpool.Pool, error) {
 	// Parse config from DSN
@@ -0,0 +1,191 @@
+// Package dbfake is an in-memory stand-in for db.Pool. Callers route each statement by its SQL
+// text, which keeps tests and benchmarks free of Postgres while exercising the real query code.
+package dbfake
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Pool answers statements with the funcs it is given. A nil func answers with nothing: Exec
+// affects no rows, Query returns no rows and QueryRow returns pgx.ErrNoRows. Transactions
+// started with Begin run through the same funcs.
+type Pool struct {
+	ExecFunc     func(sql string, args []any) (pgconn.CommandTag, error)
+	QueryFunc    func(sql string, args []any) (pgx.Rows, error)
+	QueryRowFunc func(sql string, args []any) pgx.Row
+	// SendBatchFunc answers batches; nil answers each queued statement in order through the funcs above
+	SendBatchFunc func(b *pgx.Batch) pgx.BatchResults
+}
+
+func (p *Pool) Begin(context.Context) (pgx.Tx, error) {
+	return &tx{pool: p}, nil
+}
+
+func (p *Pool) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if p.ExecFunc == nil {
+		return pgconn.CommandTag{}, nil
+	}
+	return p.ExecFunc(sql, args)
+}
+
+func (p *Pool) Query(_ context.Context, sql string, args ...any) (pgx.Rows, error) {
+	if p.QueryFunc == nil {
+		return NewRows(), nil
+	}
+	return p.QueryFunc(sql, args)
+}
+
+func (p *Pool) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	if p.QueryRowFunc == nil {
+		return Row{Err: pgx.ErrNoRows}
+	}
+	return p.QueryRowFunc(sql, args)
+}
+
+func (p *Pool) sendBatch(b *pgx.Batch) pgx.BatchResults {
+	if p.SendBatchFunc != nil {
+		return p.SendBatchFunc(b)
+	}
+	return &batchResults{pool: p, queued: b.QueuedQueries}
+}
+
+// tx is a transaction that commits and rolls back nothing
+type tx struct {
+	pgx.Tx // methods the fake doesn't support panic
+	pool   *Pool
+}
+
+func (t *tx) Commit(context.Context) error   { return nil }
+func (t *tx) Rollback(context.Context) error { return nil }
+
+func (t *tx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return t.pool.Exec(ctx, sql, args...)
+}
+
+func (t *tx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return t.pool.Query(ctx, sql, args...)
+}
+
+func (t *tx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return t.pool.QueryRow(ctx, sql, args...)
+}
+
+func (t *tx) SendBatch(_ context.Context, b *pgx.Batch) pgx.BatchResults {
+	return t.pool.sendBatch(b)
+}
+
+// batchResults answers queued statements in order
+type batchResults struct {
+	pool   *Pool
+	queued []*pgx.QueuedQuery
+	next   int
+}
+
+func (b *batchResults) pop() *pgx.QueuedQuery {
+	if b.next >= len(b.queued) {
+		return &pgx.QueuedQuery{}
+	}
+	q := b.queued[b.next]
+	b.next++
+	return q
+}
+
+func (b *batchResults) Exec() (pgconn.CommandTag, error) {
+	q := b.pop()
+	return b.pool.Exec(context.Background(), q.SQL, q.Arguments...)
+}
+
+func (b *batchResults) Query() (pgx.Rows, error) {
+	q := b.pop()
+	return b.pool.Query(context.Background(), q.SQL, q.Arguments...)
+}
+
+func (b *batchResults) QueryRow() pgx.Row {
+	q := b.pop()
+	return b.pool.QueryRow(context.Background(), q.SQL, q.Arguments...)
+}
+
+func (b *batchResults) Close() error { return nil }
+
+// Row is a single result row, or the error scanning it returns
+type Row struct {
+	Values []any
+	Err    error
+}
+
+// Scan copies Values into dest. sql.Scanner destinations scan the value themselves;
+// anything else must be assignable or convertible from the value's type.
+func (r Row) Scan(dest ...any) error {
+	if r.Err != nil {
+		return r.Err
+	}
+	if len(dest) != len(r.Values) {
+		return fmt.Errorf("dbfake: scanning %d values into %d destinations", len(r.Values), len(dest))
+	}
+	for i, d := range dest {
+		if err := assign(d, r.Values[i]); err != nil {
+			return fmt.Errorf("dbfake: column %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func assign(dest, v any) error {
+	if s, ok := dest.(sql.Scanner); ok {
+		return s.Scan(v)
+	}
+	dv := reflect.ValueOf(dest)
+	if dv.Kind() != reflect.Pointer || dv.IsNil() {
+		return fmt.Errorf("destination %T is not a pointer", dest)
+	}
+	target := dv.Elem()
+	if v == nil {
+		target.SetZero()
+		return nil
+	}
+	sv := reflect.ValueOf(v)
+	switch {
+	case sv.Type().AssignableTo(target.Type()):
+		target.Set(sv)
+	case sv.Type().ConvertibleTo(target.Type()):
+		target.Set(sv.Convert(target.Type()))
+	default:
+		return fmt.Errorf("cannot assign %T to %s", v, target.Type())
+	}
+	return nil
+}
+
+// Rows is a result set served from memory
+type Rows struct {
+	pgx.Rows // methods the fake doesn't support panic
+	rows     [][]any
+	cur      int
+}
+
+// NewRows returns a result set with one entry per row
+func NewRows(rows ...[]any) *Rows {
+	return &Rows{rows: rows, cur: -1}
+}
+
+func (r *Rows) Next() bool {
+	r.cur++
+	return r.cur < len(r.rows)
+}
+
+func (r *Rows) Scan(dest ...any) error {
+	return Row{Values: r.rows[r.cur]}.Scan(dest...)
+}
+
+func (r *Rows) Values() ([]any, error) { return r.rows[r.cur], nil }
+func (r *Rows) Err() error             { return nil }
+func (r *Rows) Close()                 {}
+
+func (r *Rows) CommandTag() pgconn.CommandTag {
+	return pgconn.NewCommandTag(fmt.Sprintf("SELECT %d", len(r.rows)))
+}
@@ -0,0 +1,65 @@
+package delivery
+
+import "sort"
+
+// Thresholds for flagging a response-code anomaly
+const (
+	// AnomalyMinSamples is the fewest responses in the current window worth judging
+	AnomalyMinSamples = 20
+	// AnomalyMinShare is the smallest share of current responses a code must reach
+	AnomalyMinShare = 0.2
+	// AnomalyMinFactor is how many times its baseline share a known code must reach
+	AnomalyMinFactor = 3.0
+)
+
+// StatusAnomaly is a non-2xx response code an endpoint returns far more often than its baseline
+type StatusAnomaly struct {
+	Code          int
+	Count         int64   // responses with Code in the current window
+	CurrentShare  float64 // share of current responses with Code
+	BaselineShare float64 // share of baseline responses with Code; 0 when the code is new
+}
+
+// DetectStatusAnomalies compares an endpoint's current response-code counts with its trailing
+// baseline and returns the non-2xx codes whose share jumped, largest first. A code is flagged
+// when it makes up at least AnomalyMinShare of current responses and either never appeared
+// in the baseline or grew to AnomalyMinFactor times its baseline share.
+func DetectStatusAnomalies(current, baseline map[int]int64) []StatusAnomaly {
+	curTotal, baseTotal := total(current), total(baseline)
+	if curTotal < AnomalyMinSamples {
+		return nil
+	}
+
+	var out []StatusAnomaly
+	for code, n := range current {
+		if code >= 200 && code < 300 {
+			continue
+		}
+		a := StatusAnomaly{Code: code, Count: n, CurrentShare: float64(n) / float64(curTotal)}
+		if a.CurrentShare < AnomalyMinShare {
+			continue
+		}
+		if baseTotal > 0 {
+			a.BaselineShare = float64(baseline[code]) / float64(baseTotal)
+		}
+		if a.BaselineShare > 0 && a.CurrentShare < a.BaselineShare*AnomalyMinFactor {
+			continue
+		}
+		out = append(out, a)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].Code < out[j].Code
+	})
+	return out
+}
+
+func total(counts map[int]int64) int64 {
+	var n int64
+	for _, c := range counts {
+		n += c
+	}
+	return n
+}
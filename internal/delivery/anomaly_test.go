@@ -0,0 +1,70 @@
+package delivery
+
+import "testing"
+
+func TestDetectStatusAnomalies(t *testing.T) {
+	tests := []struct {
+		name      string
+		current   map[int]int64
+		baseline  map[int]int64
+		wantCodes []int
+	}{
+		{
+			name:      "sudden 401s after a credential rotation",
+			current:   map[int]int64{200: 10, 401: 40},
+			baseline:  map[int]int64{200: 1000},
+			wantCodes: []int{401},
+		},
+		{
+			name:      "new 413s alongside existing 500s",
+			current:   map[int]int64{200: 50, 413: 30, 500: 20},
+			baseline:  map[int]int64{200: 900, 500: 100},
+			wantCodes: []int{413},
+		},
+		{
+			name:      "known error grew past the factor",
+			current:   map[int]int64{200: 60, 503: 40},
+			baseline:  map[int]int64{200: 950, 503: 50},
+			wantCodes: []int{503},
+		},
+		{
+			name:     "steady error rate",
+			current:  map[int]int64{200: 70, 503: 30},
+			baseline: map[int]int64{200: 700, 503: 300},
+		},
+		{
+			name:     "too few samples",
+			current:  map[int]int64{401: 10},
+			baseline: map[int]int64{200: 1000},
+		},
+		{
+			name:     "small share is noise",
+			current:  map[int]int64{200: 90, 429: 10},
+			baseline: map[int]int64{200: 1000},
+		},
+		{
+			name:     "2xx shifts are not anomalies",
+			current:  map[int]int64{202: 100},
+			baseline: map[int]int64{200: 1000},
+		},
+		{
+			name:      "no baseline flags every large error",
+			current:   map[int]int64{200: 20, 404: 30, 500: 25},
+			wantCodes: []int{404, 500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectStatusAnomalies(tt.current, tt.baseline)
+			if len(got) != len(tt.wantCodes) {
+				t.Fatalf("DetectStatusAnomalies() = %+v, want codes %v", got, tt.wantCodes)
+			}
+			for i, code := range tt.wantCodes {
+				if got[i].Code != code {
+					t.Errorf("anomaly[%d].Code = %d, want %d", i, got[i].Code, code)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package delivery
+
+// Endpoint types, as stored in endpoints.endpoint_type
+const (
+	EndpointHTTP    = "http"
+	EndpointCapture = "capture"
+)
+
+// CaptureURL stands in for the URL of a capture endpoint, which is never dialled
+const CaptureURL = "capture://"
+
+// CaptureLimit is how many captured requests are kept per capture endpoint; older ones are
+// dropped as new ones arrive
+const CaptureLimit = 500
@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+)
+
+// secretNamePattern matches Kubernetes secret names, which are also safe as directory names
+var secretNamePattern = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+
+// ValidateClientCertSecretName checks the name of a secret mounted under the worker's CLIENT_CERT_DIR
+func ValidateClientCertSecretName(name string) error {
+	if !secretNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use lowercase letters, digits, '-' and '.'", name)
+	}
+	return nil
+}
+
+// ClientCertFiles returns where the worker finds the certificate and key of a mounted secret.
+// The layout matches a kubernetes.io/tls secret mounted at dir/name.
+func ClientCertFiles(dir, name string) (certFile, keyFile string) {
+	return filepath.Join(dir, name, "tls.crt"), filepath.Join(dir, name, "tls.key")
+}
+
+// ParseClientCertificate parses a PEM certificate chain and its key into a certificate the worker
+// can present, returning the leaf for display
+func ParseClientCertificate(certPEM, keyPEM string) (tls.Certificate, *x509.Certificate, error) {
+	if certPEM == "" || keyPEM == "" {
+		return tls.Certificate{}, nil, errors.New("cert_pem and key_pem are both required")
+	}
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+	cert.Leaf = leaf
+	return cert, leaf, nil
+}
@@ -0,0 +1,37 @@
+package delivery
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateClientCertSecretName(t *testing.T) {
+	for _, name := range []string{"partner-mtls", "acme.client-cert", "a"} {
+		if err := ValidateClientCertSecretName(name); err != nil {
+			t.Errorf("ValidateClientCertSecretName(%q) = %v, want valid", name, err)
+		}
+	}
+	for _, name := range []string{"", "../etc", "Partner", "partner/mtls", "-partner", "partner-"} {
+		if err := ValidateClientCertSecretName(name); err == nil {
+			t.Errorf("ValidateClientCertSecretName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestParseClientCertificate_Invalid(t *testing.T) {
+	tests := []struct {
+		name, cert, key, wantErr string
+	}{
+		{name: "missing key", cert: "-----BEGIN CERTIFICATE-----", wantErr: "cert_pem and key_pem are both required"},
+		{name: "not pem", cert: "cert", key: "key", wantErr: "invalid client certificate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParseClientCertificate(tt.cert, tt.key)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseClientCertificate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package delivery
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+// Body compressions an endpoint can ask for, as stored in endpoints.compression
+const (
+	CompressionNone = "none"
+	CompressionGzip = "gzip"
+)
+
+// GzipMinBytes is the smallest body that is compressed; smaller ones barely shrink
+const GzipMinBytes = 1024
+
+// CompressBody encodes a webhook body for an endpoint's compression. It returns the body to
+// send and its Content-Encoding, which is empty when the body is sent as is.
+func CompressBody(compression string, body []byte) ([]byte, string, error) {
+	if compression != CompressionGzip || len(body) < GzipMinBytes {
+		return body, "", nil
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, "", err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), "gzip", nil
+}
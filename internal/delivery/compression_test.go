@@ -0,0 +1,54 @@
+package delivery
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestCompressBody(t *testing.T) {
+	large := []byte(`{"items":"` + strings.Repeat("harborhook ", 200) + `"}`)
+	small := []byte(`{"order_id":"ord_1"}`)
+
+	tests := []struct {
+		name        string
+		compression string
+		body        []byte
+		wantEnc     string
+	}{
+		{name: "none", compression: CompressionNone, body: large},
+		{name: "unset", compression: "", body: large},
+		{name: "gzip small body", compression: CompressionGzip, body: small},
+		{name: "gzip", compression: CompressionGzip, body: large, wantEnc: "gzip"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, enc, err := CompressBody(tt.compression, tt.body)
+			if err != nil {
+				t.Fatalf("CompressBody() unexpected error: %v", err)
+			}
+			if enc != tt.wantEnc {
+				t.Fatalf("Content-Encoding = %q, want %q", enc, tt.wantEnc)
+			}
+			if enc == "" {
+				if !bytes.Equal(got, tt.body) {
+					t.Error("uncompressed body was changed")
+				}
+				return
+			}
+			if len(got) >= len(tt.body) {
+				t.Errorf("gzip body is %d bytes, want less than %d", len(got), len(tt.body))
+			}
+			zr, err := gzip.NewReader(bytes.NewReader(got))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if plain, _ := io.ReadAll(zr); !bytes.Equal(plain, tt.body) {
+				t.Error("gzip body does not decompress to the original")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package delivery
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 )
@@ -24,7 +25,7 @@ func TestNewDeadLetter(t *testing.T) {
 				EndpointID:  "endpoint-abc",
 				EndpointURL: "https://example.com/webhook",
 				EventType:   "user.created",
-				Payload:     map[string]any{"user_id": 123, "email": "test@example.com"},
+				Payload:     json.RawMessage(`{"email":"test@example.com","user_id":123}`),
 				Attempt:     3,
 				PublishedAt: "2023-01-01T12:00:00Z",
 				TraceHeaders: map[string]string{
@@ -139,7 +140,7 @@ func TestDeadLetterJSONSerialization(t *testing.T) {
 					EndpointID:   "endpoint-abc",
 					EndpointURL:  "https://example.com/webhook",
 					EventType:    "user.created",
-					Payload:      map[string]any{"user_id": 123, "active": true},
+					Payload:      json.RawMessage(`{"active":true,"user_id":123}`),
 					Attempt:      3,
 					PublishedAt:  "2023-01-01T11:00:00Z",
 					TraceHeaders: map[string]string{"traceparent": "trace-123"},
@@ -216,7 +217,7 @@ func TestTaskJSONSerialization(t *testing.T) {
 				EndpointID:   "endpoint-abc",
 				EndpointURL:  "https://example.com/webhook",
 				EventType:    "user.created",
-				Payload:      map[string]any{"user_id": 123, "data": map[string]any{"nested": true}},
+				Payload:      json.RawMessage(`{"data":{"nested":true},"user_id":123}`),
 				Attempt:      3,
 				PublishedAt:  "2023-01-01T12:00:00Z",
 				TraceHeaders: map[string]string{"traceparent": "trace-123", "tracestate": "state-456"},
@@ -268,13 +269,443 @@ func TestTaskJSONSerialization(t *testing.T) {
 			if unmarshaled.Attempt != tt.task.Attempt {
 				t.Errorf("JSON round-trip Attempt mismatch: got %d, want %d", unmarshaled.Attempt, tt.task.Attempt)
 			}
+			if string(unmarshaled.Payload) != string(tt.task.Payload) {
+				t.Errorf("JSON round-trip Payload mismatch: got %s, want %s", unmarshaled.Payload, tt.task.Payload)
+			}
 		})
 	}
 }
 
+func TestTask_Remaining(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var deferred Task
+	deferred.DeferUntil(now.Add(90 * time.Second))
+	data, err := json.Marshal(deferred)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var roundTrip Task
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		task Task
+		now  time.Time
+		want time.Duration
+	}{
+		{name: "never deferred", task: Task{}, now: now, want: 0},
+		{name: "not yet due", task: roundTrip, now: now, want: 90 * time.Second},
+		{name: "partly elapsed", task: roundTrip, now: now.Add(time.Minute), want: 30 * time.Second},
+		{name: "overdue", task: roundTrip, now: now.Add(time.Hour), want: 0},
+		{name: "unreadable", task: Task{NotBefore: "soon"}, now: now, want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.Remaining(tt.now); got != tt.want {
+				t.Errorf("Remaining() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTask_Expired(t *testing.T) {
+	deadline := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	var task Task
+	task.SetDeadline(deadline)
+	data, err := json.Marshal(task)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var roundTrip Task
+	if err := json.Unmarshal(data, &roundTrip); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		task Task
+		now  time.Time
+		want bool
+	}{
+		{name: "no deadline", task: Task{}, now: deadline.Add(time.Hour), want: false},
+		{name: "before deadline", task: roundTrip, now: deadline.Add(-time.Second), want: false},
+		{name: "at deadline", task: roundTrip, now: deadline, want: true},
+		{name: "after deadline", task: roundTrip, now: deadline.Add(time.Minute), want: true},
+		{name: "unreadable", task: Task{DeliverBy: "tomorrow"}, now: deadline, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.task.Expired(tt.now); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	task.SetDeadline(time.Time{})
+	if task.DeliverBy != "" {
+		t.Errorf("SetDeadline(zero) left DeliverBy = %q, want it cleared", task.DeliverBy)
+	}
+}
+
 func TestDLQTypeConstant(t *testing.T) {
 	expected := "delivery.dlq"
 	if DLQType != expected {
 		t.Errorf("DLQType constant = %q, want %q", DLQType, expected)
 	}
-}
\ No newline at end of file
+}
+func TestProjectPayload(t *testing.T) {
+	payload := map[string]any{
+		"id":     "evt_1",
+		"status": "active",
+		"user": map[string]any{
+			"email": "test@example.com",
+			"ssn":   "123-45-6789",
+			"name":  "Test User",
+		},
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    string
+	}{
+		{
+			name: "no projection returns full payload",
+			want: `{"id":"evt_1","status":"active","user":{"email":"test@example.com","name":"Test User","ssn":"123-45-6789"}}`,
+		},
+		{
+			name:    "include top-level fields",
+			include: []string{"id", "status"},
+			want:    `{"id":"evt_1","status":"active"}`,
+		},
+		{
+			name:    "include nested field",
+			include: []string{"id", "user.email"},
+			want:    `{"id":"evt_1","user":{"email":"test@example.com"}}`,
+		},
+		{
+			name:    "exclude nested field",
+			exclude: []string{"user.ssn"},
+			want:    `{"id":"evt_1","status":"active","user":{"email":"test@example.com","name":"Test User"}}`,
+		},
+		{
+			name:    "exclude applied after include",
+			include: []string{"user"},
+			exclude: []string{"user.ssn", "user.name"},
+			want:    `{"user":{"email":"test@example.com"}}`,
+		},
+		{
+			name:    "missing paths are ignored",
+			include: []string{"missing", "user.missing", "status.nested"},
+			exclude: []string{"nope.nope"},
+			want:    `{}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(ProjectPayload(payload, tt.include, tt.exclude))
+			if err != nil {
+				t.Fatalf("json.Marshal() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ProjectPayload() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	// The source payload must never be mutated
+	if user := payload["user"].(map[string]any); user["ssn"] != "123-45-6789" {
+		t.Error("ProjectPayload() mutated the source payload")
+	}
+}
+
+func TestCanonicalPayload(t *testing.T) {
+	// jsonb::text as Postgres returns it: spaced, keys ordered by length
+	got, err := CanonicalPayload([]byte(`{"id": "evt_1", "user": {"name": "Test User", "email": "test@example.com"}, "amount": 49.0}`))
+	if err != nil {
+		t.Fatalf("CanonicalPayload() error = %v", err)
+	}
+	want := `{"amount":49,"id":"evt_1","user":{"email":"test@example.com","name":"Test User"}}`
+	if string(got) != want {
+		t.Errorf("CanonicalPayload() = %s, want %s", got, want)
+	}
+	if again, _ := CanonicalPayload(got); string(again) != want {
+		t.Errorf("CanonicalPayload() of canonical JSON = %s, want it unchanged", again)
+	}
+	if _, err := CanonicalPayload([]byte(`{"id":`)); err == nil {
+		t.Error("CanonicalPayload() expected an error for truncated JSON")
+	}
+}
+
+func TestPayloadBody(t *testing.T) {
+	payload := json.RawMessage(`{"z":1,"a":{"secret":"s","id":2}}`)
+
+	got, err := PayloadBody(payload, nil, nil)
+	if err != nil || string(got) != string(payload) {
+		t.Errorf("PayloadBody(no projection) = %s, %v, want the payload bytes as they are", got, err)
+	}
+	got, err = PayloadBody(payload, nil, []string{"a.secret"})
+	if err != nil || string(got) != `{"a":{"id":2},"z":1}` {
+		t.Errorf("PayloadBody(exclude) = %s, %v, want the projected payload", got, err)
+	}
+	if got, _ := PayloadBody(nil, nil, nil); string(got) != "null" {
+		t.Errorf("PayloadBody(nil) = %s, want null", got)
+	}
+	if _, err := PayloadBody(json.RawMessage(`[1]`), []string{"id"}, nil); err == nil {
+		t.Error("PayloadBody() expected an error projecting a payload that isn't an object")
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		patch   string
+		want    string
+	}{
+		{name: "replace and add", payload: `{"id":1,"env":"prod"}`, patch: `{"env":"staging","test":true}`, want: `{"env":"staging","id":1,"test":true}`},
+		{name: "null removes", payload: `{"id":1,"secret":"s"}`, patch: `{"secret":null}`, want: `{"id":1}`},
+		{name: "nested object", payload: `{"user":{"id":1,"email":"a@example.com"}}`, patch: `{"user":{"email":null,"name":"A"}}`, want: `{"user":{"id":1,"name":"A"}}`},
+		{name: "object replaces scalar", payload: `{"user":"u_1"}`, patch: `{"user":{"id":1}}`, want: `{"user":{"id":1}}`},
+		{name: "array replaced whole", payload: `{"tags":["a","b"]}`, patch: `{"tags":["c"]}`, want: `{"tags":["c"]}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch(json.RawMessage(tt.payload), json.RawMessage(tt.patch))
+			if err != nil || string(got) != tt.want {
+				t.Errorf("MergePatch() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidFieldPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"id", true},
+		{"user.email", true},
+		{"", false},
+		{"user.", false},
+		{".user", false},
+		{"user..email", false},
+		{" user", false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidFieldPath(tt.path); got != tt.want {
+			t.Errorf("ValidFieldPath(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestDispatchState_AdmitPercent(t *testing.T) {
+	resumed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ramp := DispatchState{ResumedAt: resumed, Ramp: 10 * time.Minute, StartPercent: 10}
+
+	tests := []struct {
+		name  string
+		state DispatchState
+		now   time.Time
+		want  float64
+	}{
+		{name: "paused", state: DispatchState{Paused: true}, now: resumed, want: 0},
+		{name: "never paused", state: DispatchState{}, now: resumed, want: 100},
+		{name: "resumed without ramp", state: DispatchState{ResumedAt: resumed}, now: resumed, want: 100},
+		{name: "ramp start", state: ramp, now: resumed, want: 10},
+		{name: "ramp halfway", state: ramp, now: resumed.Add(5 * time.Minute), want: 55},
+		{name: "ramp done", state: ramp, now: resumed.Add(10 * time.Minute), want: 100},
+		{name: "clock skew before resume", state: ramp, now: resumed.Add(-time.Minute), want: 10},
+		{
+			name:  "start percent clamped",
+			state: DispatchState{ResumedAt: resumed, Ramp: time.Minute, StartPercent: 250},
+			now:   resumed,
+			want:  100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.state.AdmitPercent(tt.now); got != tt.want {
+				t.Errorf("AdmitPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatchState_Admit(t *testing.T) {
+	resumed := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	s := DispatchState{ResumedAt: resumed, Ramp: 10 * time.Minute, StartPercent: 20}
+
+	if !s.Admit(resumed, 0.19) {
+		t.Error("Admit(0.19) at 20% expected true")
+	}
+	if s.Admit(resumed, 0.2) {
+		t.Error("Admit(0.2) at 20% expected false")
+	}
+	if (DispatchState{Paused: true}).Admit(resumed, 0) {
+		t.Error("Admit() while paused expected false")
+	}
+}
+
+func TestRecoveryRamp_AdmitPercent(t *testing.T) {
+	recovered := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	ramp := RecoveryRamp{RecoveredAt: recovered, Percents: []int{10, 50, 100}, Step: time.Minute}
+
+	tests := []struct {
+		name string
+		ramp RecoveryRamp
+		now  time.Time
+		want float64
+	}{
+		{name: "never recovered", ramp: RecoveryRamp{Percents: []int{10}, Step: time.Minute}, now: recovered, want: 100},
+		{name: "ramp disabled", ramp: RecoveryRamp{RecoveredAt: recovered, Percents: []int{10}}, now: recovered, want: 100},
+		{name: "first step", ramp: ramp, now: recovered.Add(30 * time.Second), want: 10},
+		{name: "second step", ramp: ramp, now: recovered.Add(90 * time.Second), want: 50},
+		{name: "last step", ramp: ramp, now: recovered.Add(150 * time.Second), want: 100},
+		{name: "after ramp", ramp: ramp, now: recovered.Add(time.Hour), want: 100},
+		{name: "clock skew before recovery", ramp: ramp, now: recovered.Add(-time.Minute), want: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ramp.AdmitPercent(tt.now); got != tt.want {
+				t.Errorf("AdmitPercent() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateRecoveryRamp(t *testing.T) {
+	tests := []struct {
+		name        string
+		percents    []int32
+		stepSeconds int32
+		expectError bool
+	}{
+		{name: "default ramp", percents: []int32{10, 50, 100}, stepSeconds: 60},
+		{name: "disabled", stepSeconds: 0},
+		{name: "flat steps", percents: []int32{25, 25}, stepSeconds: 30},
+		{name: "negative step", percents: []int32{10}, stepSeconds: -1, expectError: true},
+		{name: "step too long", percents: []int32{10}, stepSeconds: 3601, expectError: true},
+		{name: "missing percents", stepSeconds: 60, expectError: true},
+		{name: "zero percent", percents: []int32{0, 50}, stepSeconds: 60, expectError: true},
+		{name: "over 100", percents: []int32{10, 150}, stepSeconds: 60, expectError: true},
+		{name: "decreasing", percents: []int32{50, 10}, stepSeconds: 60, expectError: true},
+		{name: "too many steps", percents: []int32{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, stepSeconds: 60, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRecoveryRamp(tt.percents, tt.stepSeconds)
+			if tt.expectError && err == nil {
+				t.Error("ValidateRecoveryRamp() expected error but got none")
+			}
+			if !tt.expectError && err != nil {
+				t.Errorf("ValidateRecoveryRamp() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestEstimateBacklog(t *testing.T) {
+	tests := []struct {
+		name           string
+		backlog        Backlog
+		paused         bool
+		wantOK         bool
+		wantETA        time.Duration
+		wantThroughput float64
+		wantBlocked    string
+	}{
+		{name: "empty backlog", backlog: Backlog{Window: 5 * time.Minute}, wantOK: true},
+		{name: "observed throughput", backlog: Backlog{Pending: 600, Completed: 300, Window: 5 * time.Minute}, wantOK: true, wantETA: 10 * time.Minute, wantThroughput: 1},
+		{name: "capped by rate limit", backlog: Backlog{Pending: 100, Completed: 3000, Window: 5 * time.Minute, RateLimit: 2}, wantOK: true, wantETA: 50 * time.Second, wantThroughput: 2},
+		{name: "no throughput", backlog: Backlog{Pending: 10, Window: 5 * time.Minute}, wantBlocked: BlockedNoThroughput},
+		{name: "paused", backlog: Backlog{Pending: 10, Completed: 300, Window: 5 * time.Minute}, paused: true, wantBlocked: BlockedPaused},
+		{name: "frozen", backlog: Backlog{Pending: 10, Completed: 300, Window: 5 * time.Minute, Frozen: true}, wantBlocked: BlockedFrozen},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimateBacklog(tt.backlog, tt.paused)
+			if got.OK != tt.wantOK || got.ETA != tt.wantETA || got.Blocked != tt.wantBlocked {
+				t.Errorf("EstimateBacklog() = %+v, want ok=%v eta=%v blocked=%q", got, tt.wantOK, tt.wantETA, tt.wantBlocked)
+			}
+			if got.Throughput != tt.wantThroughput {
+				t.Errorf("Throughput = %v, want %v", got.Throughput, tt.wantThroughput)
+			}
+		})
+	}
+}
+
+func TestCombineEstimates(t *testing.T) {
+	tests := []struct {
+		name        string
+		parts       []Estimate
+		wantPending int64
+		wantOK      bool
+		wantETA     time.Duration
+		wantBlocked string
+	}{
+		{name: "no endpoints", wantOK: true},
+		{
+			name:        "slowest endpoint wins",
+			parts:       []Estimate{{Pending: 10, ETA: time.Minute, OK: true}, {Pending: 5, ETA: time.Hour, OK: true}},
+			wantPending: 15, wantOK: true, wantETA: time.Hour,
+		},
+		{
+			name:        "idle blocked endpoint is ignored",
+			parts:       []Estimate{{Pending: 10, ETA: time.Minute, OK: true}, {Blocked: BlockedFrozen}},
+			wantPending: 10, wantOK: true, wantETA: time.Minute,
+		},
+		{
+			name:        "blocked endpoint blocks the tenant",
+			parts:       []Estimate{{Pending: 10, ETA: time.Minute, OK: true}, {Pending: 3, Blocked: BlockedFrozen}},
+			wantPending: 13, wantBlocked: BlockedFrozen,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CombineEstimates(tt.parts)
+			if got.Pending != tt.wantPending || got.OK != tt.wantOK || got.ETA != tt.wantETA || got.Blocked != tt.wantBlocked {
+				t.Errorf("CombineEstimates() = %+v, want pending=%d ok=%v eta=%v blocked=%q",
+					got, tt.wantPending, tt.wantOK, tt.wantETA, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestTask_IdempotencyKey(t *testing.T) {
+	task := Task{DeliveryID: "del_1", EventID: "evt_1", EndpointID: "ep_1", Attempt: 0}
+	key := task.IdempotencyKey()
+	if !strings.HasPrefix(key, IdempotencyKeyPrefix) || len(key) != len(IdempotencyKeyPrefix)+32 {
+		t.Fatalf("IdempotencyKey() = %q, want %s and 32 hex digits", key, IdempotencyKeyPrefix)
+	}
+
+	retry := task
+	retry.Attempt = 3
+	if got := retry.IdempotencyKey(); got != key {
+		t.Errorf("IdempotencyKey() of a retry = %q, want %q", got, key)
+	}
+	if got := (Task{EventID: "evt_1", EndpointID: "ep_2"}).IdempotencyKey(); got == key {
+		t.Errorf("IdempotencyKey() for another endpoint = %q, want a different key", got)
+	}
+
+	replay := task
+	replay.DeliveryID = "del_2"
+	replay.ReplayKey = "idk_0123456789abcdef0123456789abcdef"
+	if got := replay.IdempotencyKey(); got != replay.ReplayKey {
+		t.Errorf("IdempotencyKey() of a replay = %q, want its replay key", got)
+	}
+}
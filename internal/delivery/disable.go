@@ -0,0 +1,47 @@
+package delivery
+
+// Reasons an endpoint is disabled, as stored in endpoints.disabled_reason
+const (
+	DisabledDeadStreak  = "dead_streak"  // its last deliveries were all dead-lettered
+	DisabledFailureRate = "failure_rate" // most of its deliveries failed for the whole window
+)
+
+// SystemEventEndpointDisabled is recorded when an endpoint is disabled for failing
+const SystemEventEndpointDisabled = "endpoint.disabled"
+
+// DisablePolicy is when an endpoint that keeps failing is disabled. A zero DeadStreak or
+// FailureRate turns that test off.
+type DisablePolicy struct {
+	DeadStreak    int     // deliveries dead-lettered in a row
+	FailureRate   float64 // share of finished deliveries that failed over the window
+	MinDeliveries int     // finished deliveries the window must hold before its rate counts
+}
+
+// FailureStats are an endpoint's finished deliveries over the policy's window
+type FailureStats struct {
+	DeadStreak int  // deliveries dead-lettered since the last one delivered
+	Finished   int  // deliveries delivered, dead-lettered or failed
+	Failed     int  // of Finished, those dead-lettered or failed
+	Observed   bool // whether the endpoint existed, enabled, for the whole window
+}
+
+// Rate is the share of finished deliveries that failed
+func (s FailureStats) Rate() float64 {
+	if s.Finished == 0 {
+		return 0
+	}
+	return float64(s.Failed) / float64(s.Finished)
+}
+
+// Reason returns why s disables an endpoint, or "" when it doesn't. The failure rate only
+// counts once the endpoint has been watched for the whole window, so an endpoint created or
+// enabled again recently isn't judged on its first few deliveries.
+func (p DisablePolicy) Reason(s FailureStats) string {
+	switch {
+	case p.DeadStreak > 0 && s.DeadStreak >= p.DeadStreak:
+		return DisabledDeadStreak
+	case p.FailureRate > 0 && s.Observed && s.Finished >= max(p.MinDeliveries, 1) && s.Rate() >= p.FailureRate:
+		return DisabledFailureRate
+	}
+	return ""
+}
@@ -0,0 +1,28 @@
+package delivery
+
+import "testing"
+
+func TestDisablePolicy_Reason(t *testing.T) {
+	p := DisablePolicy{DeadStreak: 10, FailureRate: 0.9, MinDeliveries: 20}
+	tests := []struct {
+		name  string
+		p     DisablePolicy
+		stats FailureStats
+		want  string
+	}{
+		{"healthy", p, FailureStats{Finished: 100, Failed: 3, Observed: true}, ""},
+		{"dead streak", p, FailureStats{DeadStreak: 10, Finished: 10, Failed: 10}, DisabledDeadStreak},
+		{"short streak", p, FailureStats{DeadStreak: 9, Finished: 9, Failed: 9}, ""},
+		{"failure rate", p, FailureStats{Finished: 40, Failed: 38, Observed: true}, DisabledFailureRate},
+		{"rate before the window is watched", p, FailureStats{Finished: 40, Failed: 38}, ""},
+		{"rate on too few deliveries", p, FailureStats{Finished: 19, Failed: 19, Observed: true}, ""},
+		{"tests off", DisablePolicy{}, FailureStats{DeadStreak: 500, Finished: 500, Failed: 500, Observed: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Reason(tt.stats); got != tt.want {
+				t.Errorf("Reason(%+v) = %q, want %q", tt.stats, got, tt.want)
+			}
+		})
+	}
+}
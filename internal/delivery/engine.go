@@ -0,0 +1,189 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+)
+
+// ErrAbandoned is returned (wrapped in a Response's Err) by a Sender whose send was cut short
+// before it had an outcome, e.g. by a drain deadline. The attempt isn't counted or recorded.
+var ErrAbandoned = errors.New("send abandoned")
+
+// Endpoint is what sending one delivery to an endpoint needs besides the task
+type Endpoint struct {
+	Secret          string
+	SignatureScheme string
+	SigningKey      ed25519.PrivateKey // the tenant's key, for SignatureEd25519
+	Compression     string
+	SenderHeaders   bool
+	Policy          RetryPolicy   // already resolved against the worker's settings
+	Timeout         time.Duration // the endpoint's request timeout; 0 uses the sender's default
+	Type            string        // EndpointHTTP, or EndpointCapture to store requests instead of sending them
+	ProxyURL        string        // the endpoint's own proxy, or ProxyDirect; "" uses the egress proxy
+
+	RecordRequests bool // the tenant's compliance mode
+	RetentionDays  int
+
+	ClientCertPEM, ClientKeyPEM string
+	ClientCertSecret            string
+}
+
+// Attempt is one try at a delivery: the task, the payload as the receiver sees it (projected,
+// uncompressed; signatures cover these bytes) and the endpoint's settings
+type Attempt struct {
+	Task     Task
+	Body     []byte
+	Endpoint Endpoint
+}
+
+// Response is what sending an attempt produced. Status is 0 when there was no response, in
+// which case Err says why.
+type Response struct {
+	Status  int
+	Latency time.Duration
+	Err     error
+	Body    ResponseBody // the start of the body of a response that wasn't OK, when the Sender keeps it
+}
+
+// OK reports whether the receiver accepted the delivery
+func (r Response) OK() bool {
+	return r.Err == nil && r.Status >= 200 && r.Status < 300
+}
+
+// Signer sets the headers receivers verify (signature, timestamp, delivery id) on a built request
+type Signer interface {
+	Sign(req *http.Request, a *Attempt)
+}
+
+// Sender sends a signed request. Transport errors go in the Response rather than failing the
+// send; a send cut short without an outcome reports ErrAbandoned.
+type Sender interface {
+	Send(req *http.Request, a *Attempt) Response
+}
+
+// Classifier assigns a failed attempt one of the RetryClass values
+type Classifier interface {
+	Classify(err error, status int) string
+}
+
+// ClassifierFunc adapts a function to a Classifier
+type ClassifierFunc func(err error, status int) string
+
+func (f ClassifierFunc) Classify(err error, status int) string { return f(err, status) }
+
+// Persister records an attempt's outcome. Implementations report their own storage errors: the
+// outcome stands either way.
+type Persister interface {
+	// Delivered records a successful attempt
+	Delivered(ctx context.Context, a *Attempt, r Response)
+	// Failed records a failed attempt and returns how many attempts the delivery has now used
+	Failed(ctx context.Context, a *Attempt, r Response) int
+	// DeadLettered moves the delivery to the DLQ after its last attempt
+	DeadLettered(ctx context.Context, a *Attempt, attempt int, r Response, reason string)
+}
+
+// Retrier schedules failed deliveries for another attempt
+type Retrier interface {
+	// Delay is how long a's delivery waits after its attempt-th attempt before the next
+	Delay(a *Attempt, attempt int) time.Duration
+	// Retry hands t, which carries its new attempt count and due time, back for another attempt
+	// after delay
+	Retry(ctx context.Context, t Task, delay time.Duration) error
+}
+
+// Results of a DeliveryEngine attempt
+const (
+	ResultDelivered = "delivered"
+	ResultRetrying  = "retrying"
+	ResultDead      = "dead"
+	ResultAbandoned = "abandoned"
+)
+
+// Outcome is what became of an attempt
+type Outcome struct {
+	Result   string
+	Response Response
+	Class    string        // failure class; "" when delivered
+	Attempt  int           // attempts used, after a failure
+	Delay    time.Duration // until the next attempt, when retrying
+	Reason   string        // why the delivery was dead-lettered
+	Err      error         // the Retrier's error: the retry wasn't scheduled and the caller must keep the task
+}
+
+// DeliveryEngine runs one delivery attempt through its stages: build and Sign the request, Send
+// it, then Persist a success, or Classify the failure, Persist it and either Retry it or
+// dead-letter it under the endpoint's retry policy. Admission (kill switch, ordering, freezes)
+// is up to the caller.
+type DeliveryEngine struct {
+	Signer     Signer
+	Sender     Sender
+	Classifier Classifier
+	Persister  Persister
+	Retrier    Retrier
+}
+
+// Deliver runs a. The request carries ctx, so cancelling it aborts the send; the outcome is
+// recorded regardless, so a response the receiver already gave isn't lost.
+func (e *DeliveryEngine) Deliver(ctx context.Context, a *Attempt) Outcome {
+	r := e.send(ctx, a)
+	ctx = context.WithoutCancel(ctx)
+	if errors.Is(r.Err, ErrAbandoned) {
+		return Outcome{Result: ResultAbandoned, Response: r}
+	}
+	if r.OK() {
+		e.Persister.Delivered(ctx, a, r)
+		return Outcome{Result: ResultDelivered, Response: r}
+	}
+
+	class := e.Classifier.Classify(r.Err, r.Status)
+	attempt := e.Persister.Failed(ctx, a, r)
+	out := Outcome{Response: r, Class: class, Attempt: attempt}
+
+	out.Reason = a.Endpoint.Policy.DeadLetterReason(attempt, class)
+	if errors.Is(r.Err, netguard.ErrBlocked) {
+		out.Reason = "destination not allowed" // retrying can't help; the URL resolves to an internal address
+	}
+	var due time.Time
+	if out.Reason == "" {
+		// A retry that wouldn't be due until after the event's deadline is pointless
+		out.Delay = e.Retrier.Delay(a, attempt)
+		due = time.Now().Add(out.Delay)
+		if a.Task.Expired(due) {
+			out.Reason, out.Delay = "expired", 0
+		}
+	}
+	if out.Reason != "" {
+		out.Result = ResultDead
+		e.Persister.DeadLettered(ctx, a, attempt, r, out.Reason)
+		return out
+	}
+
+	out.Result = ResultRetrying
+	t := a.Task
+	t.Attempt = attempt
+	t.DeferUntil(due)
+	out.Err = e.Retrier.Retry(ctx, t, out.Delay)
+	return out
+}
+
+// send builds the request, compressed under the endpoint's setting, signs it and sends it
+func (e *DeliveryEngine) send(ctx context.Context, a *Attempt) Response {
+	// The signature covers the uncompressed body, which is what receivers see after decoding
+	wire, encoding, _ := CompressBody(a.Endpoint.Compression, a.Body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.Task.EndpointURL, bytes.NewReader(wire))
+	if err != nil {
+		return Response{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if encoding != "" {
+		req.Header.Set("Content-Encoding", encoding)
+	}
+	e.Signer.Sign(req, a)
+	return e.Sender.Send(req, a)
+}
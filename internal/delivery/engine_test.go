@@ -0,0 +1,134 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+)
+
+// fakeStages implements every DeliveryEngine stage, answering sends with resp and recording
+// what the engine asked of it
+type fakeStages struct {
+	resp     Response
+	attempt  int
+	retryErr error
+
+	req       *http.Request
+	delivered bool
+	failed    bool
+	dead      string
+	retried   *Task
+}
+
+func (f *fakeStages) Sign(req *http.Request, a *Attempt) { req.Header.Set("X-Signature", "sig") }
+func (f *fakeStages) Send(req *http.Request, a *Attempt) Response {
+	f.req = req
+	return f.resp
+}
+func (f *fakeStages) Delivered(context.Context, *Attempt, Response) { f.delivered = true }
+func (f *fakeStages) Failed(context.Context, *Attempt, Response) int {
+	f.failed = true
+	return f.attempt
+}
+func (f *fakeStages) DeadLettered(_ context.Context, _ *Attempt, _ int, _ Response, reason string) {
+	f.dead = reason
+}
+func (f *fakeStages) Delay(_ *Attempt, attempt int) time.Duration {
+	return time.Duration(attempt) * time.Minute
+}
+func (f *fakeStages) Retry(_ context.Context, t Task, _ time.Duration) error {
+	f.retried = &t
+	return f.retryErr
+}
+
+func (f *fakeStages) engine() *DeliveryEngine {
+	return &DeliveryEngine{
+		Signer: f, Sender: f, Persister: f, Retrier: f,
+		Classifier: ClassifierFunc(func(err error, status int) string {
+			if err != nil {
+				return RetryClassNetwork
+			}
+			return RetryClass5xx
+		}),
+	}
+}
+
+func TestDeliveryEngine_Deliver(t *testing.T) {
+	soon := time.Now().Add(30 * time.Second).Format(time.RFC3339Nano)
+	policy := RetryPolicy{MaxAttempts: 3, RetryOn: []string{RetryClass5xx}}
+
+	tests := []struct {
+		name       string
+		resp       Response
+		attempt    int
+		deliverBy  string
+		retryErr   error
+		wantResult string
+		wantReason string
+	}{
+		{name: "2xx is delivered", resp: Response{Status: 204}, wantResult: ResultDelivered},
+		{name: "5xx under the limit retries", resp: Response{Status: 503}, attempt: 1, wantResult: ResultRetrying},
+		{name: "5xx at the limit is dead", resp: Response{Status: 503}, attempt: 3, wantResult: ResultDead, wantReason: "max attempts reached (3)"},
+		{name: "class outside retry_on is dead", resp: Response{Err: errors.New("reset")}, attempt: 1, wantResult: ResultDead, wantReason: "not retryable (network)"},
+		{name: "blocked destination is dead", resp: Response{Err: fmt.Errorf("dial: %w", netguard.ErrBlocked)}, attempt: 1, wantResult: ResultDead, wantReason: "destination not allowed"},
+		{name: "retry due after the deadline is dead", resp: Response{Status: 500}, attempt: 1, deliverBy: soon, wantResult: ResultDead, wantReason: "expired"},
+		{name: "failed retry publish is reported", resp: Response{Status: 500}, attempt: 1, retryErr: errors.New("nsqd down"), wantResult: ResultRetrying},
+		{name: "abandoned send records nothing", resp: Response{Err: fmt.Errorf("%w: context canceled", ErrAbandoned)}, wantResult: ResultAbandoned},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeStages{resp: tt.resp, attempt: tt.attempt, retryErr: tt.retryErr}
+			a := &Attempt{
+				Task:     Task{DeliveryID: "dlv_1", EndpointURL: "https://example.com/hook", DeliverBy: tt.deliverBy},
+				Body:     []byte(`{"id":1}`),
+				Endpoint: Endpoint{Policy: policy},
+			}
+
+			out := f.engine().Deliver(context.Background(), a)
+			if out.Result != tt.wantResult || out.Reason != tt.wantReason {
+				t.Fatalf("Deliver() = %s (%q), want %s (%q)", out.Result, out.Reason, tt.wantResult, tt.wantReason)
+			}
+			if f.req == nil || f.req.Header.Get("X-Signature") != "sig" || f.req.Header.Get("Content-Type") != "application/json" {
+				t.Errorf("sent request %v, want it signed and typed", f.req)
+			}
+			if f.delivered != (tt.wantResult == ResultDelivered) {
+				t.Errorf("delivered recorded = %v", f.delivered)
+			}
+			if wantFailed := tt.wantResult == ResultRetrying || tt.wantResult == ResultDead; f.failed != wantFailed {
+				t.Errorf("failure recorded = %v, want %v", f.failed, wantFailed)
+			}
+			if f.dead != tt.wantReason {
+				t.Errorf("dead-lettered with %q, want %q", f.dead, tt.wantReason)
+			}
+			if tt.wantResult != ResultRetrying {
+				if f.retried != nil {
+					t.Errorf("retried %+v, want no retry", f.retried)
+				}
+				return
+			}
+			if f.retried == nil || f.retried.Attempt != tt.attempt || f.retried.Remaining(time.Now()) <= 0 {
+				t.Errorf("retried %+v, want attempt %d deferred", f.retried, tt.attempt)
+			}
+			if out.Delay != time.Duration(tt.attempt)*time.Minute || !errors.Is(out.Err, tt.retryErr) {
+				t.Errorf("Deliver() delay %v, err %v, want %v, %v", out.Delay, out.Err, time.Duration(tt.attempt)*time.Minute, tt.retryErr)
+			}
+		})
+	}
+}
+
+func TestDeliveryEngine_Compresses(t *testing.T) {
+	f := &fakeStages{resp: Response{Status: 200}}
+	body := []byte(`{"pad":"` + strings.Repeat("x", GzipMinBytes) + `"}`)
+	a := &Attempt{Task: Task{EndpointURL: "https://example.com/hook"}, Body: body, Endpoint: Endpoint{Compression: CompressionGzip}}
+
+	f.engine().Deliver(context.Background(), a)
+	if got := f.req.Header.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("Content-Encoding = %q, want gzip", got)
+	}
+}
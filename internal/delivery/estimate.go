@@ -0,0 +1,84 @@
+package delivery
+
+import "time"
+
+// Reasons a backlog is not draining
+const (
+	BlockedPaused       = "dispatch paused"
+	BlockedFrozen       = "frozen"
+	BlockedNoThroughput = "no recent throughput"
+)
+
+// Backlog is an endpoint's pending work and how fast it has recently been cleared
+type Backlog struct {
+	Pending   int64         // queued, inflight and retrying deliveries
+	Completed int64         // deliveries that reached delivered or dead within Window
+	Window    time.Duration // how far back Completed looks
+	RateLimit float64       // endpoint rate limit in deliveries/sec; 0 is unlimited
+	Frozen    bool          // an active freeze covers the endpoint
+}
+
+// Estimate predicts when a backlog clears. ETA is only meaningful when OK.
+type Estimate struct {
+	Pending    int64
+	Throughput float64 // expected deliveries/sec
+	ETA        time.Duration
+	OK         bool
+	Blocked    string // why OK is false, empty otherwise
+}
+
+// Throughput is the recently observed completion rate, capped at the endpoint's rate limit
+func (b Backlog) Throughput() float64 {
+	if b.Window <= 0 || b.Completed <= 0 {
+		return 0
+	}
+	rate := float64(b.Completed) / b.Window.Seconds()
+	if b.RateLimit > 0 {
+		rate = min(rate, b.RateLimit)
+	}
+	return rate
+}
+
+// EstimateBacklog predicts when b clears, assuming the recent throughput holds.
+// Nothing drains while dispatch is paused or the endpoint is frozen.
+func EstimateBacklog(b Backlog, paused bool) Estimate {
+	e := Estimate{Pending: b.Pending, Throughput: b.Throughput()}
+	switch {
+	case b.Pending <= 0:
+		e.OK = true
+	case paused:
+		e.Throughput, e.Blocked = 0, BlockedPaused
+	case b.Frozen:
+		e.Throughput, e.Blocked = 0, BlockedFrozen
+	case e.Throughput <= 0:
+		e.Blocked = BlockedNoThroughput
+	default:
+		e.ETA = time.Duration(float64(b.Pending) / e.Throughput * float64(time.Second))
+		e.OK = true
+	}
+	return e
+}
+
+// CombineEstimates rolls endpoint estimates up to a tenant. Endpoints drain in parallel,
+// so the backlog clears when the slowest one does; any blocked endpoint with pending
+// work blocks the whole estimate.
+func CombineEstimates(parts []Estimate) Estimate {
+	total := Estimate{OK: true}
+	for _, p := range parts {
+		total.Pending += p.Pending
+		total.Throughput += p.Throughput
+		if p.Pending <= 0 {
+			continue
+		}
+		if !p.OK {
+			if total.OK {
+				total.OK, total.ETA, total.Blocked = false, 0, p.Blocked
+			}
+			continue
+		}
+		if total.OK {
+			total.ETA = max(total.ETA, p.ETA)
+		}
+	}
+	return total
+}
@@ -0,0 +1,48 @@
+package delivery
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Failure reasons for deliveries that failed without a specific HTTP status
+const (
+	ReasonTimeout           = "timeout"
+	ReasonConnectionRefused = "connection_refused"
+	ReasonConnectionReset   = "connection_reset"
+	ReasonDNS               = "dns"
+	ReasonTLS               = "tls"
+	ReasonNetwork           = "network"
+	ReasonUnknown           = "unknown"
+)
+
+// FailureReason groups a failed attempt into a low-cardinality reason for trend analysis.
+// Raw transport errors embed URLs and addresses, so they are classified by kind; HTTP
+// failures become http_<status>; errors recorded as bare codes (e.g. endpoint_secret_missing)
+// are kept as they are.
+func FailureReason(httpStatus int, lastErr string) string {
+	e := strings.ToLower(lastErr)
+	switch {
+	case e == "":
+		if httpStatus > 0 {
+			return "http_" + strconv.Itoa(httpStatus)
+		}
+		return ReasonUnknown
+	case strings.Contains(e, "timeout") || strings.Contains(e, "deadline exceeded"):
+		return ReasonTimeout
+	case strings.Contains(e, "connection refused"):
+		return ReasonConnectionRefused
+	case strings.Contains(e, "connection reset") || strings.Contains(e, "broken pipe"):
+		return ReasonConnectionReset
+	case strings.Contains(e, "no such host") || strings.Contains(e, "server misbehaving"):
+		return ReasonDNS
+	case strings.Contains(e, "x509") || strings.Contains(e, "tls:") || strings.Contains(e, "certificate"):
+		return ReasonTLS
+	case !strings.ContainsAny(e, " :/"):
+		return e
+	case httpStatus > 0:
+		return "http_" + strconv.Itoa(httpStatus)
+	default:
+		return ReasonNetwork
+	}
+}
@@ -0,0 +1,32 @@
+package delivery
+
+import "testing"
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		httpStatus int
+		lastErr    string
+		want       string
+	}{
+		{name: "http status", httpStatus: 503, want: "http_503"},
+		{name: "nothing recorded", want: ReasonUnknown},
+		{name: "client timeout", lastErr: `Post "https://example.com/hook": context deadline exceeded (Client.Timeout exceeded while awaiting headers)`, want: ReasonTimeout},
+		{name: "refused", lastErr: `Post "http://10.0.0.1:8080/hook": dial tcp 10.0.0.1:8080: connect: connection refused`, want: ReasonConnectionRefused},
+		{name: "reset", lastErr: `read tcp 10.0.0.2:1234->10.0.0.1:443: read: connection reset by peer`, want: ReasonConnectionReset},
+		{name: "dns", lastErr: `dial tcp: lookup nope.example: no such host`, want: ReasonDNS},
+		{name: "tls", lastErr: `tls: failed to verify certificate: x509: certificate signed by unknown authority`, want: ReasonTLS},
+		{name: "bare code kept", lastErr: "endpoint_secret_missing", want: "endpoint_secret_missing"},
+		{name: "bare code lowercased", lastErr: "Frozen", want: "frozen"},
+		{name: "other error with status", httpStatus: 502, lastErr: "unexpected EOF reading body", want: "http_502"},
+		{name: "other transport error", lastErr: "unexpected EOF: stream closed", want: ReasonNetwork},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FailureReason(tt.httpStatus, tt.lastErr); got != tt.want {
+				t.Errorf("FailureReason(%d, %q) = %q, want %q", tt.httpStatus, tt.lastErr, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package delivery
+
+import (
+	"net/http"
+	"time"
+)
+
+// Endpoint health statuses, as stored in endpoint_health.status
+const (
+	HealthUnknown   = "unknown" // not probed yet
+	HealthHealthy   = "healthy"
+	HealthUnhealthy = "unhealthy"
+)
+
+// Health check methods, as stored in endpoints.health_check_method
+const (
+	HealthCheckHEAD = http.MethodHead
+	HealthCheckGET  = http.MethodGet
+)
+
+// SystemEventHealthChanged is recorded when an endpoint becomes unhealthy or recovers
+const SystemEventHealthChanged = "endpoint.health_changed"
+
+// HealthCheckHeader marks a health check, so receivers can answer it without treating it as a
+// webhook
+const HealthCheckHeader = "X-Harborhook-Health-Check"
+
+// HealthThresholds are how many checks in a row change an endpoint's health
+type HealthThresholds struct {
+	Failures  int // failed checks that make an endpoint unhealthy
+	Successes int // passed checks that make an unhealthy endpoint healthy again
+}
+
+// EndpointHealth is an endpoint's health as its checks left it
+type EndpointHealth struct {
+	Status               string
+	ConsecutiveFailures  int
+	ConsecutiveSuccesses int
+}
+
+// HealthCheckPassed reports whether a health check response shows the endpoint up. Any answer
+// below 500 other than 429 passes: receivers often refuse HEAD or GET on a webhook URL (405),
+// which still shows they are reachable and serving.
+func HealthCheckPassed(status int, err error) bool {
+	return err == nil && status > 0 && status < http.StatusInternalServerError && status != http.StatusTooManyRequests
+}
+
+// Observe applies one check to h, returning the new health and whether its status changed. An
+// unknown endpoint becomes healthy on its first passed check, and unhealthy, like a healthy one,
+// after Failures failed checks in a row.
+func (h EndpointHealth) Observe(passed bool, t HealthThresholds) (EndpointHealth, bool) {
+	if h.Status == "" {
+		h.Status = HealthUnknown
+	}
+	next := h
+	if passed {
+		next.ConsecutiveSuccesses++
+		next.ConsecutiveFailures = 0
+		if next.Status == HealthUnknown || (next.Status == HealthUnhealthy && next.ConsecutiveSuccesses >= max(t.Successes, 1)) {
+			next.Status = HealthHealthy
+		}
+	} else {
+		next.ConsecutiveFailures++
+		next.ConsecutiveSuccesses = 0
+		if next.ConsecutiveFailures >= max(t.Failures, 1) {
+			next.Status = HealthUnhealthy
+		}
+	}
+	return next, next.Status != h.Status
+}
+
+// HealthCheckTimeout is how long a health check may take: the endpoint's own request timeout
+// when it is shorter than limit
+func HealthCheckTimeout(endpoint, limit time.Duration) time.Duration {
+	if endpoint > 0 && endpoint < limit {
+		return endpoint
+	}
+	return limit
+}
@@ -0,0 +1,70 @@
+package delivery
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestHealthCheckPassed(t *testing.T) {
+	for _, tt := range []struct {
+		status int
+		err    error
+		want   bool
+	}{
+		{200, nil, true},
+		{204, nil, true},
+		{301, nil, true},
+		{405, nil, true},
+		{404, nil, true},
+		{429, nil, false},
+		{500, nil, false},
+		{503, nil, false},
+		{0, errors.New("connection refused"), false},
+	} {
+		if got := HealthCheckPassed(tt.status, tt.err); got != tt.want {
+			t.Errorf("HealthCheckPassed(%d, %v) = %v, want %v", tt.status, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestEndpointHealth_Observe(t *testing.T) {
+	th := HealthThresholds{Failures: 3, Successes: 2}
+	var (
+		h       EndpointHealth
+		changed bool
+	)
+	check := func(passed bool, wantStatus string, wantChanged bool) {
+		t.Helper()
+		h, changed = h.Observe(passed, th)
+		if h.Status != wantStatus || changed != wantChanged {
+			t.Fatalf("Observe(%v) = %+v, changed %v; want %s, changed %v", passed, h, changed, wantStatus, wantChanged)
+		}
+	}
+
+	check(false, HealthUnknown, false)
+	check(true, HealthHealthy, true)
+	check(false, HealthHealthy, false)
+	check(false, HealthHealthy, false)
+	check(false, HealthUnhealthy, true)
+	check(false, HealthUnhealthy, false)
+	check(true, HealthUnhealthy, false)
+	check(false, HealthUnhealthy, false) // a failure starts the successes over
+	check(true, HealthUnhealthy, false)
+	check(true, HealthHealthy, true)
+	if h.ConsecutiveSuccesses != 2 || h.ConsecutiveFailures != 0 {
+		t.Errorf("counts = %+v, want 2 successes", h)
+	}
+}
+
+func TestHealthCheckTimeout(t *testing.T) {
+	if got := HealthCheckTimeout(0, 5*time.Second); got != 5*time.Second {
+		t.Errorf("HealthCheckTimeout(none) = %s, want the limit", got)
+	}
+	if got := HealthCheckTimeout(time.Second, 5*time.Second); got != time.Second {
+		t.Errorf("HealthCheckTimeout(1s) = %s, want the endpoint's", got)
+	}
+	if got := HealthCheckTimeout(time.Minute, 5*time.Second); got != 5*time.Second {
+		t.Errorf("HealthCheckTimeout(1m) = %s, want the limit", got)
+	}
+}
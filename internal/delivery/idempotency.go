@@ -0,0 +1,26 @@
+package delivery
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// IdempotencyKeyPrefix starts every receiver idempotency key. Replays given a new key use the
+// same form: the prefix and 32 hex digits.
+const IdempotencyKeyPrefix = "idk_"
+
+// DeriveIdempotencyKey returns the receiver idempotency key of an event's delivery to an
+// endpoint. It depends on nothing else, so every retry of the delivery sends the same key.
+func DeriveIdempotencyKey(eventID, endpointID string) string {
+	sum := sha256.Sum256([]byte(eventID + ":" + endpointID))
+	return IdempotencyKeyPrefix + hex.EncodeToString(sum[:16])
+}
+
+// IdempotencyKey returns the key receivers dedupe the task's delivery on: its replay key, or the
+// key derived from its event and endpoint
+func (t Task) IdempotencyKey() string {
+	if t.ReplayKey != "" {
+		return t.ReplayKey
+	}
+	return DeriveIdempotencyKey(t.EventID, t.EndpointID)
+}
@@ -0,0 +1,38 @@
+package delivery
+
+import "time"
+
+// DispatchState is the cluster-wide kill switch. While Paused, workers hold every task.
+// After a resume with a Ramp, the share of tasks admitted grows linearly from StartPercent
+// to 100% so a backlog doesn't hit receivers all at once.
+type DispatchState struct {
+	Paused       bool
+	Reason       string
+	ResumedAt    time.Time
+	Ramp         time.Duration
+	StartPercent int
+}
+
+// AdmitPercent returns the percentage (0-100) of tasks that may be dispatched at now
+func (s DispatchState) AdmitPercent(now time.Time) float64 {
+	if s.Paused {
+		return 0
+	}
+	if s.Ramp <= 0 || s.ResumedAt.IsZero() {
+		return 100
+	}
+	elapsed := now.Sub(s.ResumedAt)
+	if elapsed >= s.Ramp {
+		return 100
+	}
+	start := float64(min(max(s.StartPercent, 0), 100))
+	if elapsed <= 0 {
+		return start
+	}
+	return start + (100-start)*float64(elapsed)/float64(s.Ramp)
+}
+
+// Admit reports whether a task may be dispatched at now. roll is a uniform random number in [0, 1).
+func (s DispatchState) Admit(now time.Time, roll float64) bool {
+	return roll*100 < s.AdmitPercent(now)
+}
@@ -0,0 +1,65 @@
+package delivery
+
+import "encoding/json"
+
+// CanonicalPayload encodes a JSON payload the way ingest stores it at publish: compact, object
+// keys sorted and numbers as float64. Postgres returns jsonb with its own spacing and key order,
+// so payloads read back from events go through this before they are put in a task, and a retry
+// or replay signs the same bytes as the original delivery.
+func CanonicalPayload(raw []byte) (json.RawMessage, error) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// PayloadBody returns the webhook body for a task's payload. Without a projection it is the
+// payload's bytes as they are; a projected payload is decoded, projected and encoded again.
+func PayloadBody(payload json.RawMessage, include, exclude []string) ([]byte, error) {
+	if len(include) == 0 && len(exclude) == 0 {
+		if len(payload) == 0 {
+			return []byte("null"), nil
+		}
+		return payload, nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(payload, &m); err != nil {
+		return nil, err
+	}
+	return json.Marshal(ProjectPayload(m, include, exclude))
+}
+
+// MergePatch applies a JSON merge patch (RFC 7396) to a payload and returns the result in
+// canonical form. A null in the patch removes the field; an object patches the object under it.
+func MergePatch(payload, patch json.RawMessage) (json.RawMessage, error) {
+	var target, p any
+	if len(payload) > 0 {
+		if err := json.Unmarshal(payload, &target); err != nil {
+			return nil, err
+		}
+	}
+	if err := json.Unmarshal(patch, &p); err != nil {
+		return nil, err
+	}
+	return json.Marshal(mergePatch(target, p))
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
@@ -0,0 +1,20 @@
+package delivery
+
+// Event priorities, as stored in events.priority
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+// Priorities lists the priorities from most to least urgent
+var Priorities = []string{PriorityHigh, PriorityNormal, PriorityLow}
+
+// PriorityTopic returns the topic a task of priority is published to when priority topics are
+// enabled: base_high, base_normal or base_low. An empty priority is normal.
+func PriorityTopic(base, priority string) string {
+	if priority == "" {
+		priority = PriorityNormal
+	}
+	return base + "_" + priority
+}
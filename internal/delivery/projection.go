@@ -0,0 +1,106 @@
+package delivery
+
+import "strings"
+
+// ProjectPayload returns the subset of payload a subscription asked for.
+// Field paths use dot notation to reach into nested objects (e.g. "user.email").
+// If include is non-empty only those paths are kept; exclude paths are then removed.
+// The input payload is never modified, so the same task can be safely requeued.
+func ProjectPayload(payload map[string]any, include, exclude []string) map[string]any {
+	if len(include) == 0 && len(exclude) == 0 {
+		return payload
+	}
+
+	var out map[string]any
+	if len(include) > 0 {
+		out = make(map[string]any)
+		for _, path := range include {
+			if v, ok := lookupPath(payload, splitPath(path)); ok {
+				setPath(out, splitPath(path), deepCopy(v))
+			}
+		}
+	} else {
+		out, _ = deepCopy(payload).(map[string]any)
+	}
+
+	for _, path := range exclude {
+		deletePath(out, splitPath(path))
+	}
+	return out
+}
+
+// ValidFieldPath reports whether path is a usable projection path
+func ValidFieldPath(path string) bool {
+	if strings.TrimSpace(path) != path || path == "" {
+		return false
+	}
+	for _, part := range strings.Split(path, ".") {
+		if part == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func splitPath(path string) []string {
+	return strings.Split(path, ".")
+}
+
+func lookupPath(m map[string]any, parts []string) (any, bool) {
+	var cur any = m
+	for _, p := range parts {
+		obj, ok := cur.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		cur, ok = obj[p]
+		if !ok {
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func setPath(m map[string]any, parts []string, v any) {
+	cur := m
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := cur[p].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			cur[p] = next
+		}
+		cur = next
+	}
+	cur[parts[len(parts)-1]] = v
+}
+
+func deletePath(m map[string]any, parts []string) {
+	cur := m
+	for _, p := range parts[:len(parts)-1] {
+		next, ok := cur[p].(map[string]any)
+		if !ok {
+			return
+		}
+		cur = next
+	}
+	delete(cur, parts[len(parts)-1])
+}
+
+func deepCopy(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(t))
+		for k, val := range t {
+			c[k] = deepCopy(val)
+		}
+		return c
+	case []any:
+		c := make([]any, len(t))
+		for i, val := range t {
+			c[i] = deepCopy(val)
+		}
+		return c
+	default:
+		return v
+	}
+}
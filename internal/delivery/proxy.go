@@ -0,0 +1,4 @@
+package delivery
+
+// ProxyDirect as an endpoint's proxy URL sends its deliveries without the egress proxy
+const ProxyDirect = "direct"
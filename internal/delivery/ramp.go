@@ -0,0 +1,69 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	maxRecoveryRampSteps       = 10
+	maxRecoveryRampStepSeconds = 3600
+)
+
+// DefaultRecoveryRamp matches the endpoints table defaults: 10%, 50%, then 100% for a minute each
+var DefaultRecoveryRamp = RecoveryRamp{Percents: []int{10, 50, 100}, Step: time.Minute}
+
+// RecoveryRamp throttles an endpoint after it recovers so its backlog isn't dumped on it at once.
+// Each step admits Percents[i] of tasks for Step; after the last step the endpoint is at full rate.
+type RecoveryRamp struct {
+	RecoveredAt time.Time
+	Percents    []int
+	Step        time.Duration
+	// Unhealthy is set while the endpoint fails its health checks: the circuit is open and no
+	// task is admitted. Its recovery starts the ramp.
+	Unhealthy bool
+}
+
+// AdmitPercent returns the percentage (0-100) of tasks that may be dispatched to the endpoint at now
+func (r RecoveryRamp) AdmitPercent(now time.Time) float64 {
+	if r.Unhealthy {
+		return 0
+	}
+	if r.RecoveredAt.IsZero() || r.Step <= 0 || len(r.Percents) == 0 {
+		return 100
+	}
+	elapsed := max(now.Sub(r.RecoveredAt), 0)
+	step := int(elapsed / r.Step)
+	if step >= len(r.Percents) {
+		return 100
+	}
+	return float64(min(max(r.Percents[step], 0), 100))
+}
+
+// Admit reports whether a task may be dispatched at now. roll is a uniform random number in [0, 1).
+func (r RecoveryRamp) Admit(now time.Time, roll float64) bool {
+	return roll*100 < r.AdmitPercent(now)
+}
+
+// ValidateRecoveryRamp checks ramp steps supplied by a client
+func ValidateRecoveryRamp(percents []int32, stepSeconds int32) error {
+	if stepSeconds < 0 || stepSeconds > maxRecoveryRampStepSeconds {
+		return fmt.Errorf("step_seconds must be between 0 and %d", maxRecoveryRampStepSeconds)
+	}
+	if len(percents) > maxRecoveryRampSteps {
+		return fmt.Errorf("at most %d ramp steps are allowed", maxRecoveryRampSteps)
+	}
+	if stepSeconds > 0 && len(percents) == 0 {
+		return errors.New("percents are required when step_seconds is set")
+	}
+	for i, p := range percents {
+		if p < 1 || p > 100 {
+			return fmt.Errorf("ramp percent %d must be between 1 and 100", p)
+		}
+		if i > 0 && p < percents[i-1] {
+			return errors.New("ramp percents must not decrease")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package delivery
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReceiptLeeway is how far a receipt timestamp may drift from the server clock
+const ReceiptLeeway = 5 * time.Minute
+
+// SignReceipt signs a receiver's acknowledgement of a delivery with the endpoint secret,
+// the same way deliveries are signed: sha256=hex(HMAC(secret, delivery_id || timestamp))
+func SignReceipt(secret, deliveryID string, timestamp int64) string {
+	return "sha256=" + hex.EncodeToString(receiptMAC(secret, deliveryID, timestamp))
+}
+
+// VerifyReceipt checks a receipt signature made by SignReceipt and that its timestamp is within leeway of now
+func VerifyReceipt(secret, deliveryID string, timestamp int64, signature string, now time.Time, leeway time.Duration) error {
+	if d := now.Sub(time.Unix(timestamp, 0)); d > leeway || d < -leeway {
+		return errors.New("timestamp outside leeway")
+	}
+	scheme, sig, ok := strings.Cut(signature, "=")
+	if !ok || scheme != "sha256" {
+		return errors.New("bad signature scheme")
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return errors.New("signature not hex")
+	}
+	if !hmac.Equal(got, receiptMAC(secret, deliveryID, timestamp)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func receiptMAC(secret, deliveryID string, timestamp int64) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(deliveryID))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,43 @@
+package delivery
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifyReceipt(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	const id = "123e4567-e89b-12d3-a456-426614174000"
+	good := SignReceipt("s3cret", id, now.Unix())
+
+	tests := []struct {
+		name      string
+		id        string
+		timestamp int64
+		signature string
+		wantErr   string
+	}{
+		{name: "valid", id: id, timestamp: now.Unix(), signature: good},
+		{name: "clock skew within leeway", id: id, timestamp: now.Unix() + 60, signature: SignReceipt("s3cret", id, now.Unix()+60)},
+		{name: "stale", id: id, timestamp: now.Add(-10 * time.Minute).Unix(), signature: SignReceipt("s3cret", id, now.Add(-10*time.Minute).Unix()), wantErr: "timestamp outside leeway"},
+		{name: "wrong scheme", id: id, timestamp: now.Unix(), signature: "md5=00", wantErr: "bad signature scheme"},
+		{name: "not hex", id: id, timestamp: now.Unix(), signature: "sha256=zz", wantErr: "signature not hex"},
+		{name: "wrong secret", id: id, timestamp: now.Unix(), signature: SignReceipt("other", id, now.Unix()), wantErr: "signature mismatch"},
+		{name: "other delivery", id: "other", timestamp: now.Unix(), signature: good, wantErr: "signature mismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyReceipt("s3cret", tt.id, tt.timestamp, tt.signature, now, ReceiptLeeway)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("VerifyReceipt() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("VerifyReceipt() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
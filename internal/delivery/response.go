@@ -0,0 +1,29 @@
+package delivery
+
+import (
+	"io"
+	"strings"
+)
+
+// ResponseBody is the start of a receiver's response body, kept so tenants can see why a
+// delivery was rejected
+type ResponseBody struct {
+	Text      string // valid UTF-8, so it can be stored as text and returned over the API
+	Truncated bool   // the body went on past the limit
+}
+
+// CaptureBody reads at most limit bytes of r. Bytes that aren't valid UTF-8, including a
+// character cut off at the limit, become U+FFFD, and NULs (which Postgres text can't hold) are
+// dropped. A read error keeps what was read before it.
+func CaptureBody(r io.Reader, limit int) ResponseBody {
+	if limit <= 0 {
+		return ResponseBody{}
+	}
+	buf, _ := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	var b ResponseBody
+	if len(buf) > limit {
+		buf, b.Truncated = buf[:limit], true
+	}
+	b.Text = strings.ReplaceAll(strings.ToValidUTF8(string(buf), "�"), "\x00", "")
+	return b
+}
@@ -0,0 +1,31 @@
+package delivery
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCaptureBody(t *testing.T) {
+	tests := []struct {
+		name          string
+		body          string
+		limit         int
+		wantText      string
+		wantTruncated bool
+	}{
+		{name: "under the limit", body: `{"error":"bad signature"}`, limit: 64, wantText: `{"error":"bad signature"}`},
+		{name: "exactly the limit", body: "abcd", limit: 4, wantText: "abcd"},
+		{name: "over the limit", body: "abcdef", limit: 4, wantText: "abcd", wantTruncated: true},
+		{name: "character cut at the limit", body: "abé", limit: 3, wantText: "ab�", wantTruncated: true},
+		{name: "binary", body: "ok\xff\x00!", limit: 64, wantText: "ok�!"},
+		{name: "capture disabled", body: "abc", limit: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CaptureBody(strings.NewReader(tt.body), tt.limit)
+			if got.Text != tt.wantText || got.Truncated != tt.wantTruncated {
+				t.Errorf("CaptureBody() = %+v, want {%q %v}", got, tt.wantText, tt.wantTruncated)
+			}
+		})
+	}
+}
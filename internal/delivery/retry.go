@@ -0,0 +1,103 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Failure classes the worker assigns to a failed attempt, used for retry metrics and
+// per-endpoint retry_on filters
+const (
+	RetryClassTimeout           = "timeout"
+	RetryClassConnectionRefused = "connection_refused"
+	RetryClassDNS               = "dns_error"
+	RetryClassNetwork           = "network"
+	RetryClass5xx               = "http_5xx"
+	RetryClass429               = "http_429"
+	RetryClass4xx               = "http_4xx"
+	RetryClassOther             = "other"
+)
+
+// RetryClasses lists every class a retry_on filter may name
+var RetryClasses = []string{
+	RetryClassTimeout, RetryClassConnectionRefused, RetryClassDNS, RetryClassNetwork,
+	RetryClass5xx, RetryClass429, RetryClass4xx, RetryClassOther,
+}
+
+const (
+	maxRetryAttempts       = 50
+	maxRetryBackoffSteps   = 20
+	maxRetryBackoffSeconds = 24 * 3600
+)
+
+// RetryPolicy is an endpoint's override of the worker's global retry settings. Zero values
+// defer to the globals: MaxAttempts 0 uses MAX_ATTEMPTS, an empty Backoff uses
+// BACKOFF_SCHEDULE, and an empty RetryOn retries every failure class.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     []time.Duration
+	RetryOn     []string
+}
+
+// Resolve fills the policy's unset fields from the global settings
+func (p RetryPolicy) Resolve(maxAttempts int, backoff []time.Duration) RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = maxAttempts
+	}
+	if len(p.Backoff) == 0 {
+		p.Backoff = backoff
+	}
+	return p
+}
+
+// Retries reports whether a failure of the given class should be retried
+func (p RetryPolicy) Retries(class string) bool {
+	return len(p.RetryOn) == 0 || slices.Contains(p.RetryOn, class)
+}
+
+// DeadLetterReason explains why a delivery whose attempt failed with class should be
+// dead-lettered after attempt attempts, or returns "" when it should be retried
+func (p RetryPolicy) DeadLetterReason(attempt int, class string) string {
+	if attempt >= p.MaxAttempts {
+		return fmt.Sprintf("max attempts reached (%d)", attempt)
+	}
+	if !p.Retries(class) {
+		return fmt.Sprintf("not retryable (%s)", class)
+	}
+	return ""
+}
+
+// RetryPolicyFromColumns builds a policy from the endpoints table's retry columns
+func RetryPolicyFromColumns(maxAttempts int, backoffSeconds []int, retryOn []string) RetryPolicy {
+	p := RetryPolicy{MaxAttempts: maxAttempts, RetryOn: retryOn}
+	for _, s := range backoffSeconds {
+		p.Backoff = append(p.Backoff, time.Duration(s)*time.Second)
+	}
+	return p
+}
+
+// ValidateRetryPolicy checks a retry policy supplied by a client
+func ValidateRetryPolicy(maxAttempts int32, backoffSeconds []int32, retryOn []string) error {
+	if maxAttempts < 0 || maxAttempts > maxRetryAttempts {
+		return fmt.Errorf("max_attempts must be between 0 and %d", maxRetryAttempts)
+	}
+	if len(backoffSeconds) > maxRetryBackoffSteps {
+		return fmt.Errorf("at most %d backoff steps are allowed", maxRetryBackoffSteps)
+	}
+	for _, s := range backoffSeconds {
+		if s < 1 || s > maxRetryBackoffSeconds {
+			return fmt.Errorf("backoff %ds must be between 1 and %d seconds", s, maxRetryBackoffSeconds)
+		}
+	}
+	for i, c := range retryOn {
+		if !slices.Contains(RetryClasses, c) {
+			return fmt.Errorf("unknown retry class %q (want one of %v)", c, RetryClasses)
+		}
+		if slices.Contains(retryOn[:i], c) {
+			return errors.New("retry classes must not repeat")
+		}
+	}
+	return nil
+}
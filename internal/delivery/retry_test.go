@@ -0,0 +1,105 @@
+package delivery
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_Resolve(t *testing.T) {
+	global := []time.Duration{time.Second, 4 * time.Second}
+
+	got := RetryPolicy{}.Resolve(6, global)
+	if got.MaxAttempts != 6 || !slices.Equal(got.Backoff, global) {
+		t.Errorf("empty policy resolved to %+v, want the globals", got)
+	}
+
+	own := []time.Duration{time.Minute}
+	got = RetryPolicy{MaxAttempts: 20, Backoff: own, RetryOn: []string{RetryClass5xx}}.Resolve(6, global)
+	if got.MaxAttempts != 20 || !slices.Equal(got.Backoff, own) || !slices.Equal(got.RetryOn, []string{RetryClass5xx}) {
+		t.Errorf("override resolved to %+v, want it kept", got)
+	}
+}
+
+func TestRetryPolicy_Retries(t *testing.T) {
+	tests := []struct {
+		name    string
+		retryOn []string
+		class   string
+		want    bool
+	}{
+		{name: "no filter retries everything", class: RetryClass4xx, want: true},
+		{name: "listed class", retryOn: []string{RetryClass5xx, RetryClassTimeout}, class: RetryClassTimeout, want: true},
+		{name: "unlisted class", retryOn: []string{RetryClass5xx}, class: RetryClass4xx, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (RetryPolicy{RetryOn: tt.retryOn}).Retries(tt.class); got != tt.want {
+				t.Errorf("Retries(%q) = %v, want %v", tt.class, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyFromColumns(t *testing.T) {
+	p := RetryPolicyFromColumns(20, []int{1, 30}, []string{RetryClass429})
+	want := []time.Duration{time.Second, 30 * time.Second}
+	if p.MaxAttempts != 20 || !slices.Equal(p.Backoff, want) || !slices.Equal(p.RetryOn, []string{RetryClass429}) {
+		t.Errorf("RetryPolicyFromColumns = %+v", p)
+	}
+}
+
+func TestValidateRetryPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int32
+		backoff     []int32
+		retryOn     []string
+		wantErr     bool
+	}{
+		{name: "all defaults"},
+		{name: "full override", maxAttempts: 20, backoff: []int32{1, 10, 60}, retryOn: []string{RetryClass5xx, RetryClass429}},
+		{name: "negative attempts", maxAttempts: -1, wantErr: true},
+		{name: "too many attempts", maxAttempts: 51, wantErr: true},
+		{name: "zero backoff", backoff: []int32{0}, wantErr: true},
+		{name: "backoff over a day", backoff: []int32{86401}, wantErr: true},
+		{name: "too many steps", backoff: make([]int32, 21), wantErr: true},
+		{name: "unknown class", retryOn: []string{"http_503"}, wantErr: true},
+		{name: "repeated class", retryOn: []string{RetryClassTimeout, RetryClassTimeout}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRetryPolicy(tt.maxAttempts, tt.backoff, tt.retryOn)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateRetryPolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_DeadLetterReason(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 20, RetryOn: []string{RetryClass5xx, RetryClassTimeout}}
+
+	tests := []struct {
+		name    string
+		policy  RetryPolicy
+		attempt int
+		class   string
+		want    string
+	}{
+		{name: "retryable class under limit", policy: policy, attempt: 6, class: RetryClass5xx, want: ""},
+		{name: "limit reached", policy: policy, attempt: 20, class: RetryClass5xx, want: "max attempts reached (20)"},
+		{name: "class not retried", policy: policy, attempt: 1, class: RetryClass4xx, want: "not retryable (http_4xx)"},
+		{name: "no filter retries any class", policy: RetryPolicy{MaxAttempts: 6}, attempt: 1, class: RetryClass4xx, want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.DeadLetterReason(tt.attempt, tt.class); got != tt.want {
+				t.Errorf("DeadLetterReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
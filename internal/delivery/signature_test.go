@@ -0,0 +1,59 @@
+package delivery
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery/signvectors"
+)
+
+func TestSignRequest_Vectors(t *testing.T) {
+	for _, v := range signvectors.Load(t) {
+		t.Run(v.Name, func(t *testing.T) {
+			if got := SignRequest(v.Scheme, v.Secret, v.PrivateKey(t), v.Method, v.Path, []byte(v.Payload), v.Timestamp); got != v.Signature {
+				t.Errorf("SignRequest() = %q, want %q", got, v.Signature)
+			}
+		})
+	}
+}
+
+func TestSignV2_CoversRequest(t *testing.T) {
+	base := SignV2("k", "POST", "/hook", []byte(`{}`), "1750000000")
+	for name, got := range map[string]string{
+		"method": SignV2("k", "PUT", "/hook", []byte(`{}`), "1750000000"),
+		"path":   SignV2("k", "POST", "/other", []byte(`{}`), "1750000000"),
+		"query":  SignV2("k", "POST", "/hook?x=1", []byte(`{}`), "1750000000"),
+		"body":   SignV2("k", "POST", "/hook", []byte(`{ }`), "1750000000"),
+	} {
+		if got == base {
+			t.Errorf("changing the %s didn't change the signature", name)
+		}
+	}
+	if KeyID("k") == KeyID("k2") || len(KeyID("k")) != 12 {
+		t.Errorf("KeyID() = %q, want 12 hex digits unique to the secret", KeyID("k"))
+	}
+}
+
+func TestSignEd25519_VerifiesWithPublicKey(t *testing.T) {
+	pub, key, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := SignEd25519(key, "POST", "/hook", []byte(`{}`), "1750000000")
+	prefix := "v2,t=1750000000,kid=" + PublicKeyID(pub) + ",alg=Ed25519,sig="
+	if !strings.HasPrefix(header, prefix) {
+		t.Fatalf("SignEd25519() = %q, want prefix %q", header, prefix)
+	}
+	sig, err := hex.DecodeString(strings.TrimPrefix(header, prefix))
+	if err != nil {
+		t.Fatalf("sig not hex: %v", err)
+	}
+	if !ed25519.Verify(pub, []byte("v2\n1750000000\nPOST\n/hook\n{}"), sig) {
+		t.Error("signature doesn't verify with the public key")
+	}
+	if ed25519.Verify(pub, []byte("v2\n1750000000\nPOST\n/other\n{}"), sig) {
+		t.Error("signature verifies for another path")
+	}
+}
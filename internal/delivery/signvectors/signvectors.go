@@ -0,0 +1,74 @@
+// Package signvectors holds the delivery signature test vectors shared by the worker's signer
+// and every receiver-side verifier in the repo, so the two sides can't drift apart. New
+// signing modes should add vectors here rather than to one side's tests.
+package signvectors
+
+import (
+	"crypto/ed25519"
+	_ "embed"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+)
+
+//go:embed signatures.json
+var signaturesJSON []byte
+
+// Vector is a payload signed with secret (or for ed25519, SigningKey) at timestamp, and the
+// signature header value expected for it
+type Vector struct {
+	Name string `json:"name"`
+	// Scheme is the signature scheme, v1 when empty. v2 and ed25519 also sign Method and Path.
+	Scheme string `json:"scheme,omitempty"`
+	Secret string `json:"secret,omitempty"`
+	// SigningKey is the hex Ed25519 seed an ed25519 vector is signed with, and PublicKey its
+	// public key as published in the JWK set: base64url without padding
+	SigningKey string `json:"signing_key,omitempty"`
+	PublicKey  string `json:"public_key,omitempty"`
+	Timestamp  string `json:"timestamp"`
+	// Method and Path (the escaped request target, with any query) of the signed request
+	Method    string `json:"method,omitempty"`
+	Path      string `json:"path,omitempty"`
+	Payload   string `json:"payload"`
+	Signature string `json:"signature"`
+}
+
+// PrivateKey returns the Ed25519 key an ed25519 vector is signed with, or nil for other schemes
+func (v Vector) PrivateKey(tb testing.TB) ed25519.PrivateKey {
+	tb.Helper()
+	if v.SigningKey == "" {
+		return nil
+	}
+	seed, err := hex.DecodeString(v.SigningKey)
+	if err != nil || len(seed) != ed25519.SeedSize {
+		tb.Fatalf("vector %q: bad signing_key", v.Name)
+	}
+	return ed25519.NewKeyFromSeed(seed)
+}
+
+// PublicKeyBytes returns the Ed25519 public key an ed25519 vector verifies with, or nil for other schemes
+func (v Vector) PublicKeyBytes(tb testing.TB) ed25519.PublicKey {
+	tb.Helper()
+	if v.PublicKey == "" {
+		return nil
+	}
+	pub, err := base64.RawURLEncoding.DecodeString(v.PublicKey)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		tb.Fatalf("vector %q: bad public_key", v.Name)
+	}
+	return pub
+}
+
+// Load returns the vectors, failing tb if they can't be read
+func Load(tb testing.TB) []Vector {
+	tb.Helper()
+	var vs []Vector
+	if err := json.Unmarshal(signaturesJSON, &vs); err != nil {
+		tb.Fatalf("decode signature vectors: %v", err)
+	}
+	if len(vs) == 0 {
+		tb.Fatal("no signature vectors")
+	}
+	return vs
+}
@@ -1,5 +1,10 @@
 package delivery
 
+import (
+	"encoding/json"
+	"time"
+)
+
 type Task struct {
 	DeliveryID   string            `json:"delivery_id"`
 	EventID      string            `json:"event_id"`
@@ -7,8 +12,72 @@ type Task struct {
 	EndpointID   string            `json:"endpoint_id"`
 	EndpointURL  string            `json:"endpoint_url"`
 	EventType    string            `json:"event_type"`
-	Payload      map[string]any    `json:"payload"`
+	Payload      json.RawMessage   `json:"payload,omitempty"`     // The event's payload as stored (see CanonicalPayload), sent and signed as is
+	PayloadRef   string            `json:"payload_ref,omitempty"` // Set instead of Payload for payloads kept in the blob store
 	Attempt      int               `json:"attempt"`
 	PublishedAt  string            `json:"published_at"` // RFC3339
 	TraceHeaders map[string]string `json:"trace_headers,omitempty"` // OTel trace propagation headers
+
+	IncludeFields []string `json:"include_fields,omitempty"` // Subscription payload projection (see ProjectPayload)
+	ExcludeFields []string `json:"exclude_fields,omitempty"` // Subscription payload fields to strip
+
+	// Ordered is set for deliveries to an ordered endpoint, which the worker holds while an earlier
+	// event's delivery in the same partition is still pending
+	Ordered bool `json:"ordered,omitempty"`
+
+	// NotBefore is when the next attempt is due (RFC3339Nano), set when a retry is scheduled.
+	// nsqd forgets a requeue's delay once the message is handed out again, so it travels with the task.
+	NotBefore string `json:"not_before,omitempty"`
+
+	// DeliverBy is the event's delivery deadline (RFC3339Nano), if it was published with one.
+	// Once it passes the worker dead-letters the delivery as "expired" instead of retrying it.
+	DeliverBy string `json:"deliver_by,omitempty"`
+
+	// Priority is the event's priority, set when priority topics are enabled. Retries go back to
+	// the same priority's topic.
+	Priority string `json:"priority,omitempty"`
+
+	// ReplayKey is the receiver idempotency key of a replay that was given a new one. Empty uses
+	// the key derived from the event and endpoint (see IdempotencyKey).
+	ReplayKey string `json:"replay_key,omitempty"`
+}
+
+// DeferUntil records that the task's next attempt is due at at
+func (t *Task) DeferUntil(at time.Time) {
+	t.NotBefore = at.UTC().Format(time.RFC3339Nano)
+}
+
+// Remaining returns how long until the task's next attempt is due, or 0 when it is due now
+// (including when no attempt was scheduled or NotBefore is unreadable)
+func (t Task) Remaining(now time.Time) time.Duration {
+	if t.NotBefore == "" {
+		return 0
+	}
+	at, err := time.Parse(time.RFC3339Nano, t.NotBefore)
+	if err != nil {
+		return 0
+	}
+	return max(at.Sub(now), 0)
+}
+
+// SetDeadline records that the task must be delivered by at; the zero time clears it
+func (t *Task) SetDeadline(at time.Time) {
+	if at.IsZero() {
+		t.DeliverBy = ""
+		return
+	}
+	t.DeliverBy = at.UTC().Format(time.RFC3339Nano)
+}
+
+// Expired reports whether the task's delivery deadline is at or before now. Tasks without a
+// deadline (or with an unreadable one) never expire.
+func (t Task) Expired(now time.Time) bool {
+	if t.DeliverBy == "" {
+		return false
+	}
+	at, err := time.Parse(time.RFC3339Nano, t.DeliverBy)
+	if err != nil {
+		return false
+	}
+	return !now.Before(at)
 }
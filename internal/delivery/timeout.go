@@ -0,0 +1,35 @@
+package delivery
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	minEndpointTimeoutMS = 100
+	// MaxEndpointTimeoutMS is the longest request timeout an endpoint may ask for. Workers may
+	// cap it lower (WORKER_HTTP_MAX_TIMEOUT).
+	MaxEndpointTimeoutMS = 120_000
+)
+
+// ValidateTimeout checks an endpoint request timeout supplied by a client; 0 restores the
+// worker default
+func ValidateTimeout(ms int32) error {
+	if ms != 0 && (ms < minEndpointTimeoutMS || ms > MaxEndpointTimeoutMS) {
+		return fmt.Errorf("timeout_ms must be 0 or between %d and %d", minEndpointTimeoutMS, MaxEndpointTimeoutMS)
+	}
+	return nil
+}
+
+// RequestTimeout is how long a request to an endpoint may take: its own timeout, or def when
+// it has none, at most limit
+func RequestTimeout(endpoint, def, limit time.Duration) time.Duration {
+	d := def
+	if endpoint > 0 {
+		d = endpoint
+	}
+	if limit > 0 && d > limit {
+		d = limit
+	}
+	return d
+}
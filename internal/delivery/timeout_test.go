@@ -0,0 +1,39 @@
+package delivery
+
+import (
+	"testing"
+	"time"
+)
+
+func TestValidateTimeout(t *testing.T) {
+	for _, ms := range []int32{0, 100, 2500, MaxEndpointTimeoutMS} {
+		if err := ValidateTimeout(ms); err != nil {
+			t.Errorf("ValidateTimeout(%d) unexpected error: %v", ms, err)
+		}
+	}
+	for _, ms := range []int32{-1, 99, MaxEndpointTimeoutMS + 1} {
+		if err := ValidateTimeout(ms); err == nil {
+			t.Errorf("ValidateTimeout(%d) = nil, want an error", ms)
+		}
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name                 string
+		endpoint, def, limit time.Duration
+		want                 time.Duration
+	}{
+		{name: "no endpoint timeout uses the default", def: 15 * time.Second, limit: time.Minute, want: 15 * time.Second},
+		{name: "endpoint timeout wins", endpoint: 2 * time.Second, def: 15 * time.Second, limit: time.Minute, want: 2 * time.Second},
+		{name: "capped at the limit", endpoint: 2 * time.Minute, def: 15 * time.Second, limit: time.Minute, want: time.Minute},
+		{name: "no limit", endpoint: 2 * time.Minute, def: 15 * time.Second, want: 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RequestTimeout(tt.endpoint, tt.def, tt.limit); got != tt.want {
+				t.Errorf("RequestTimeout() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package delivery
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// VerificationType is the type of the challenge sent to a new endpoint before it gets deliveries
+const VerificationType = "endpoint.verification"
+
+// Challenge is the body of a verification request. It is signed like a delivery; the receiver
+// answers 2xx with {"challenge": "<token>"} or the bare token to prove it expects our webhooks.
+type Challenge struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+// NewChallenge returns the verification request body for token
+func NewChallenge(token string) Challenge {
+	return Challenge{Type: VerificationType, Challenge: token}
+}
+
+// ParseChallenge returns the token of a verification request body, or false for any other body
+func ParseChallenge(body []byte) (string, bool) {
+	var c Challenge
+	if err := json.Unmarshal(body, &c); err != nil || c.Type != VerificationType || c.Challenge == "" {
+		return "", false
+	}
+	return c.Challenge, true
+}
+
+// ChallengeAnswered reports whether a receiver's response body echoes token
+func ChallengeAnswered(body []byte, token string) bool {
+	if token == "" {
+		return false
+	}
+	body = bytes.TrimSpace(body)
+	if string(body) == token {
+		return true
+	}
+	var c struct {
+		Challenge string `json:"challenge"`
+	}
+	return json.Unmarshal(body, &c) == nil && c.Challenge == token
+}
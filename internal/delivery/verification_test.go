@@ -0,0 +1,47 @@
+package delivery
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseChallenge(t *testing.T) {
+	body, _ := json.Marshal(NewChallenge("tok_123"))
+	if got, ok := ParseChallenge(body); !ok || got != "tok_123" {
+		t.Errorf("ParseChallenge(%s) = %q, %v, want tok_123", body, got, ok)
+	}
+
+	for _, body := range []string{
+		`{"order_id":"ord_1"}`,
+		`{"type":"order.created","challenge":"tok_123"}`,
+		`{"type":"endpoint.verification"}`,
+		`not json`,
+	} {
+		if got, ok := ParseChallenge([]byte(body)); ok {
+			t.Errorf("ParseChallenge(%s) = %q, want not a challenge", body, got)
+		}
+	}
+}
+
+func TestChallengeAnswered(t *testing.T) {
+	tests := []struct {
+		name  string
+		body  string
+		token string
+		want  bool
+	}{
+		{name: "json echo", body: `{"challenge":"tok_123"}`, token: "tok_123", want: true},
+		{name: "bare token", body: "tok_123\n", token: "tok_123", want: true},
+		{name: "wrong token", body: `{"challenge":"tok_456"}`, token: "tok_123"},
+		{name: "plain ok", body: "ok", token: "tok_123"},
+		{name: "empty token", body: "", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ChallengeAnswered([]byte(tt.body), tt.token); got != tt.want {
+				t.Errorf("ChallengeAnswered(%q, %q) = %v, want %v", tt.body, tt.token, got, tt.want)
+			}
+		})
+	}
+}
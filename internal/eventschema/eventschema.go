@@ -0,0 +1,708 @@
+// Package eventschema validates event payloads against the JSON Schemas tenants register per
+// event type.
+//
+// It implements the JSON Schema (draft 2020-12) keywords that constrain JSON data: type, enum,
+// const, the numeric, string, array and object keywords, the allOf/anyOf/oneOf/not combinators
+// and $ref to the schema's own $defs. Annotations such as title or format are accepted and
+// ignored. Any other keyword is rejected when the schema is compiled, so a schema never quietly
+// checks less than its author expects.
+package eventschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxSchemaBytes bounds the size of a registered schema
+const MaxSchemaBytes = 64 * 1024
+
+const (
+	// maxViolations bounds how many violations Validate reports
+	maxViolations = 20
+	// maxRefDepth bounds how many $refs validation follows, so a schema referring to itself
+	// without descending into the payload can't recurse forever
+	maxRefDepth = 64
+)
+
+// annotations are keywords that describe a schema without constraining data
+var annotations = map[string]bool{
+	"$schema": true, "$id": true, "$comment": true, "$anchor": true,
+	"title": true, "description": true, "default": true, "examples": true,
+	"format": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+	"contentMediaType": true, "contentEncoding": true,
+}
+
+var jsonTypes = []string{"null", "boolean", "object", "array", "number", "integer", "string"}
+
+// Violation is one way a payload fails its schema
+type Violation struct {
+	Path    string // JSON pointer to the offending value; "" is the payload itself
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Path == "" {
+		return v.Message
+	}
+	return v.Path + ": " + v.Message
+}
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	root *node
+}
+
+// node is one (sub)schema
+type node struct {
+	always *bool // set for the boolean schemas true and false
+
+	types    []string
+	enum     []any
+	constVal any
+	hasConst bool
+
+	minimum, maximum                   *float64
+	exclusiveMinimum, exclusiveMaximum *float64
+	multipleOf                         *float64
+
+	minLength, maxLength *int
+	pattern              *regexp.Regexp
+
+	items       *node
+	prefixItems []*node
+	minItems    *int
+	maxItems    *int
+	uniqueItems bool
+	contains    *node
+	minContains *int
+	maxContains *int
+
+	properties           map[string]*node
+	patternProperties    []patternNode
+	additionalProperties *node
+	propertyNames        *node
+	required             []string
+	minProperties        *int
+	maxProperties        *int
+	dependentRequired    map[string][]string
+
+	allOf, anyOf, oneOf []*node
+	not                 *node
+
+	ref    string // JSON pointer within the document, resolved through defs
+	target *node
+}
+
+type patternNode struct {
+	re   *regexp.Regexp
+	node *node
+}
+
+// compiler holds the document being compiled so $refs can be resolved once it is done
+type compiler struct {
+	doc  any
+	refs []*node
+	seen map[string]*node
+}
+
+// Compile parses and compiles a JSON Schema document
+func Compile(raw []byte) (*Schema, error) {
+	if len(raw) > MaxSchemaBytes {
+		return nil, fmt.Errorf("schema is %d bytes, over the %d byte limit", len(raw), MaxSchemaBytes)
+	}
+	var doc any
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	return CompileValue(doc)
+}
+
+// CompileValue compiles a JSON Schema already decoded with encoding/json
+func CompileValue(doc any) (*Schema, error) {
+	c := &compiler{doc: doc, seen: map[string]*node{}}
+	root, err := c.compile(doc, "")
+	if err != nil {
+		return nil, err
+	}
+	c.seen[""] = root
+	for _, n := range c.refs {
+		target, err := c.resolve(n.ref)
+		if err != nil {
+			return nil, err
+		}
+		n.target = target
+	}
+	return &Schema{root: root}, nil
+}
+
+// resolve finds the subschema a local $ref points at, compiling it if no keyword reached it yet
+func (c *compiler) resolve(ref string) (*node, error) {
+	ptr, ok := strings.CutPrefix(ref, "#")
+	if !ok {
+		return nil, fmt.Errorf("$ref %q: only references within the schema (#/...) are supported", ref)
+	}
+	if n, ok := c.seen[ptr]; ok {
+		return n, nil
+	}
+	v := c.doc
+	for _, tok := range splitPointer(ptr) {
+		switch cur := v.(type) {
+		case map[string]any:
+			next, ok := cur[tok]
+			if !ok {
+				return nil, fmt.Errorf("$ref %q does not resolve", ref)
+			}
+			v = next
+		case []any:
+			i, err := strconv.Atoi(tok)
+			if err != nil || i < 0 || i >= len(cur) {
+				return nil, fmt.Errorf("$ref %q does not resolve", ref)
+			}
+			v = cur[i]
+		default:
+			return nil, fmt.Errorf("$ref %q does not resolve", ref)
+		}
+	}
+	n, err := c.compile(v, ptr)
+	if err != nil {
+		return nil, err
+	}
+	c.seen[ptr] = n
+	return n, nil
+}
+
+func (c *compiler) compile(v any, at string) (*node, error) {
+	if b, ok := v.(bool); ok {
+		return &node{always: &b}, nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, schemaErr(at, "a schema must be an object or a boolean")
+	}
+	n := &node{}
+	if err := c.compileKeywords(n, m, at); err != nil {
+		return nil, err
+	}
+	c.seen[at] = n
+	return n, nil
+}
+
+// compileKeywords fills n from the keywords of schema object m at pointer at
+func (c *compiler) compileKeywords(n *node, m map[string]any, at string) error {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := m[k]
+		kat := at + "/" + escapeToken(k)
+		var err error
+		switch k {
+		case "type":
+			n.types, err = typeList(v, kat)
+		case "enum":
+			arr, ok := v.([]any)
+			if !ok {
+				return schemaErr(kat, "must be an array")
+			}
+			n.enum = arr
+		case "const":
+			n.constVal, n.hasConst = v, true
+		case "minimum":
+			n.minimum, err = number(v, kat)
+		case "maximum":
+			n.maximum, err = number(v, kat)
+		case "exclusiveMinimum":
+			n.exclusiveMinimum, err = number(v, kat)
+		case "exclusiveMaximum":
+			n.exclusiveMaximum, err = number(v, kat)
+		case "multipleOf":
+			if n.multipleOf, err = number(v, kat); err == nil && *n.multipleOf <= 0 {
+				err = schemaErr(kat, "must be greater than 0")
+			}
+		case "minLength":
+			n.minLength, err = count(v, kat)
+		case "maxLength":
+			n.maxLength, err = count(v, kat)
+		case "pattern":
+			n.pattern, err = pattern(v, kat)
+		case "items":
+			n.items, err = c.compile(v, kat)
+		case "prefixItems":
+			n.prefixItems, err = c.compileList(v, kat)
+		case "minItems":
+			n.minItems, err = count(v, kat)
+		case "maxItems":
+			n.maxItems, err = count(v, kat)
+		case "uniqueItems":
+			b, ok := v.(bool)
+			if !ok {
+				return schemaErr(kat, "must be a boolean")
+			}
+			n.uniqueItems = b
+		case "contains":
+			n.contains, err = c.compile(v, kat)
+		case "minContains":
+			n.minContains, err = count(v, kat)
+		case "maxContains":
+			n.maxContains, err = count(v, kat)
+		case "properties":
+			n.properties, err = c.compileMap(v, kat)
+		case "patternProperties":
+			var props map[string]*node
+			if props, err = c.compileMap(v, kat); err == nil {
+				for _, p := range slices.Sorted(maps.Keys(props)) {
+					re, err := regexp.Compile(p)
+					if err != nil {
+						return schemaErr(kat+"/"+escapeToken(p), "invalid pattern: "+err.Error())
+					}
+					n.patternProperties = append(n.patternProperties, patternNode{re: re, node: props[p]})
+				}
+			}
+		case "additionalProperties":
+			n.additionalProperties, err = c.compile(v, kat)
+		case "propertyNames":
+			n.propertyNames, err = c.compile(v, kat)
+		case "required":
+			n.required, err = stringList(v, kat)
+		case "minProperties":
+			n.minProperties, err = count(v, kat)
+		case "maxProperties":
+			n.maxProperties, err = count(v, kat)
+		case "dependentRequired":
+			deps, ok := v.(map[string]any)
+			if !ok {
+				return schemaErr(kat, "must be an object")
+			}
+			n.dependentRequired = map[string][]string{}
+			for name, list := range deps {
+				if n.dependentRequired[name], err = stringList(list, kat+"/"+escapeToken(name)); err != nil {
+					return err
+				}
+			}
+		case "allOf":
+			n.allOf, err = c.compileList(v, kat)
+		case "anyOf":
+			n.anyOf, err = c.compileList(v, kat)
+		case "oneOf":
+			n.oneOf, err = c.compileList(v, kat)
+		case "not":
+			n.not, err = c.compile(v, kat)
+		case "$ref":
+			ref, ok := v.(string)
+			if !ok {
+				return schemaErr(kat, "must be a string")
+			}
+			n.ref = ref
+			c.refs = append(c.refs, n)
+		case "$defs", "definitions":
+			defs, ok := v.(map[string]any)
+			if !ok {
+				return schemaErr(kat, "must be an object")
+			}
+			for name, def := range defs {
+				if _, err := c.compile(def, kat+"/"+escapeToken(name)); err != nil {
+					return err
+				}
+			}
+		default:
+			if !annotations[k] {
+				return schemaErr(kat, "keyword is not supported")
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compiler) compileList(v any, at string) ([]*node, error) {
+	arr, ok := v.([]any)
+	if !ok || len(arr) == 0 {
+		return nil, schemaErr(at, "must be a non-empty array of schemas")
+	}
+	out := make([]*node, len(arr))
+	for i, s := range arr {
+		n, err := c.compile(s, at+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+func (c *compiler) compileMap(v any, at string) (map[string]*node, error) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, schemaErr(at, "must be an object of schemas")
+	}
+	out := make(map[string]*node, len(m))
+	for name, s := range m {
+		n, err := c.compile(s, at+"/"+escapeToken(name))
+		if err != nil {
+			return nil, err
+		}
+		out[name] = n
+	}
+	return out, nil
+}
+
+// Validate checks a payload decoded with encoding/json (or structpb's AsMap) and returns how it
+// fails the schema; nil means it is valid. At most 20 violations are reported.
+func (s *Schema) Validate(payload any) []Violation {
+	v := &validation{}
+	v.check(s.root, payload, "")
+	return v.violations
+}
+
+type validation struct {
+	violations []Violation
+	refDepth   int
+}
+
+func (v *validation) add(path, format string, args ...any) {
+	if len(v.violations) < maxViolations {
+		v.violations = append(v.violations, Violation{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+}
+
+// matches reports whether value satisfies n, without recording violations
+func (v *validation) matches(n *node, value any) bool {
+	sub := &validation{refDepth: v.refDepth}
+	sub.check(n, value, "")
+	return len(sub.violations) == 0
+}
+
+func (v *validation) check(n *node, value any, path string) {
+	if n.always != nil {
+		if !*n.always {
+			v.add(path, "no value is allowed here")
+		}
+		return
+	}
+	if n.target != nil {
+		if v.refDepth >= maxRefDepth {
+			v.add(path, "schema references nest more than %d deep", maxRefDepth)
+			return
+		}
+		v.refDepth++
+		v.check(n.target, value, path)
+		v.refDepth--
+	}
+
+	if len(n.types) > 0 && !slices.ContainsFunc(n.types, func(t string) bool { return isType(value, t) }) {
+		v.add(path, "expected %s, got %s", strings.Join(n.types, " or "), typeOf(value))
+		return // the remaining keywords would only repeat the mismatch
+	}
+	if n.enum != nil && !slices.ContainsFunc(n.enum, func(e any) bool { return equal(e, value) }) {
+		v.add(path, "must be one of %s", compact(n.enum))
+	}
+	if n.hasConst && !equal(n.constVal, value) {
+		v.add(path, "must be %s", compact(n.constVal))
+	}
+
+	switch val := value.(type) {
+	case float64:
+		v.checkNumber(n, val, path)
+	case string:
+		v.checkString(n, val, path)
+	case []any:
+		v.checkArray(n, val, path)
+	case map[string]any:
+		v.checkObject(n, val, path)
+	}
+
+	for _, sub := range n.allOf {
+		v.check(sub, value, path)
+	}
+	if n.anyOf != nil && !slices.ContainsFunc(n.anyOf, func(sub *node) bool { return v.matches(sub, value) }) {
+		v.add(path, "must match at least one schema in anyOf")
+	}
+	if n.oneOf != nil {
+		matched := 0
+		for _, sub := range n.oneOf {
+			if v.matches(sub, value) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.add(path, "must match exactly one schema in oneOf, matched %d", matched)
+		}
+	}
+	if n.not != nil && v.matches(n.not, value) {
+		v.add(path, "must not match the schema in not")
+	}
+}
+
+func (v *validation) checkNumber(n *node, val float64, path string) {
+	if n.minimum != nil && val < *n.minimum {
+		v.add(path, "must be >= %s", formatNumber(*n.minimum))
+	}
+	if n.maximum != nil && val > *n.maximum {
+		v.add(path, "must be <= %s", formatNumber(*n.maximum))
+	}
+	if n.exclusiveMinimum != nil && val <= *n.exclusiveMinimum {
+		v.add(path, "must be > %s", formatNumber(*n.exclusiveMinimum))
+	}
+	if n.exclusiveMaximum != nil && val >= *n.exclusiveMaximum {
+		v.add(path, "must be < %s", formatNumber(*n.exclusiveMaximum))
+	}
+	if n.multipleOf != nil {
+		if q := val / *n.multipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+			v.add(path, "must be a multiple of %s", formatNumber(*n.multipleOf))
+		}
+	}
+}
+
+func (v *validation) checkString(n *node, val string, path string) {
+	length := utf8.RuneCountInString(val)
+	if n.minLength != nil && length < *n.minLength {
+		v.add(path, "must be at least %d characters", *n.minLength)
+	}
+	if n.maxLength != nil && length > *n.maxLength {
+		v.add(path, "must be at most %d characters", *n.maxLength)
+	}
+	if n.pattern != nil && !n.pattern.MatchString(val) {
+		v.add(path, "must match pattern %q", n.pattern.String())
+	}
+}
+
+func (v *validation) checkArray(n *node, val []any, path string) {
+	if n.minItems != nil && len(val) < *n.minItems {
+		v.add(path, "must have at least %d items", *n.minItems)
+	}
+	if n.maxItems != nil && len(val) > *n.maxItems {
+		v.add(path, "must have at most %d items", *n.maxItems)
+	}
+	for i, item := range val {
+		itemPath := path + "/" + strconv.Itoa(i)
+		switch {
+		case i < len(n.prefixItems):
+			v.check(n.prefixItems[i], item, itemPath)
+		case n.items != nil:
+			v.check(n.items, item, itemPath)
+		}
+	}
+	if n.uniqueItems {
+		for i := range val {
+			for j := i + 1; j < len(val); j++ {
+				if equal(val[i], val[j]) {
+					v.add(path, "items %d and %d are equal, items must be unique", i, j)
+					return
+				}
+			}
+		}
+	}
+	if n.contains != nil {
+		found := 0
+		for _, item := range val {
+			if v.matches(n.contains, item) {
+				found++
+			}
+		}
+		minContains := 1
+		if n.minContains != nil {
+			minContains = *n.minContains
+		}
+		if found < minContains {
+			v.add(path, "must contain at least %d matching items, found %d", minContains, found)
+		}
+		if n.maxContains != nil && found > *n.maxContains {
+			v.add(path, "must contain at most %d matching items, found %d", *n.maxContains, found)
+		}
+	}
+}
+
+func (v *validation) checkObject(n *node, val map[string]any, path string) {
+	for _, name := range n.required {
+		if _, ok := val[name]; !ok {
+			v.add(path, "missing required property %q", name)
+		}
+	}
+	if n.minProperties != nil && len(val) < *n.minProperties {
+		v.add(path, "must have at least %d properties", *n.minProperties)
+	}
+	if n.maxProperties != nil && len(val) > *n.maxProperties {
+		v.add(path, "must have at most %d properties", *n.maxProperties)
+	}
+	for _, name := range slices.Sorted(maps.Keys(n.dependentRequired)) {
+		if _, ok := val[name]; !ok {
+			continue
+		}
+		for _, dep := range n.dependentRequired[name] {
+			if _, ok := val[dep]; !ok {
+				v.add(path, "property %q requires property %q", name, dep)
+			}
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(val)) {
+		propPath := path + "/" + escapeToken(name)
+		if n.propertyNames != nil && !v.matches(n.propertyNames, name) {
+			v.add(propPath, "property name does not match propertyNames")
+		}
+		evaluated := false
+		if sub, ok := n.properties[name]; ok {
+			v.check(sub, val[name], propPath)
+			evaluated = true
+		}
+		for _, pp := range n.patternProperties {
+			if pp.re.MatchString(name) {
+				v.check(pp.node, val[name], propPath)
+				evaluated = true
+			}
+		}
+		if !evaluated && n.additionalProperties != nil {
+			if a := n.additionalProperties; a.always != nil && !*a.always {
+				v.add(propPath, "property is not allowed")
+			} else {
+				v.check(a, val[name], propPath)
+			}
+		}
+	}
+}
+
+func isType(value any, t string) bool {
+	switch t {
+	case "integer":
+		f, ok := value.(float64)
+		return ok && f == math.Trunc(f) && !math.IsInf(f, 0)
+	default:
+		return typeOf(value) == t
+	}
+}
+
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// equal compares JSON values; numbers are all float64 once decoded
+func equal(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func typeList(v any, at string) ([]string, error) {
+	var list []string
+	switch t := v.(type) {
+	case string:
+		list = []string{t}
+	case []any:
+		var err error
+		if list, err = stringList(t, at); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, schemaErr(at, "must be a string or an array of strings")
+	}
+	for _, t := range list {
+		if !slices.Contains(jsonTypes, t) {
+			return nil, schemaErr(at, fmt.Sprintf("unknown type %q", t))
+		}
+	}
+	return list, nil
+}
+
+func stringList(v any, at string) ([]string, error) {
+	arr, ok := v.([]any)
+	if !ok {
+		return nil, schemaErr(at, "must be an array of strings")
+	}
+	out := make([]string, len(arr))
+	for i, s := range arr {
+		str, ok := s.(string)
+		if !ok {
+			return nil, schemaErr(at, "must be an array of strings")
+		}
+		out[i] = str
+	}
+	return out, nil
+}
+
+func number(v any, at string) (*float64, error) {
+	f, ok := v.(float64)
+	if !ok {
+		return nil, schemaErr(at, "must be a number")
+	}
+	return &f, nil
+}
+
+func count(v any, at string) (*int, error) {
+	f, ok := v.(float64)
+	if !ok || f < 0 || f != math.Trunc(f) {
+		return nil, schemaErr(at, "must be a non-negative integer")
+	}
+	i := int(f)
+	return &i, nil
+}
+
+func pattern(v any, at string) (*regexp.Regexp, error) {
+	s, ok := v.(string)
+	if !ok {
+		return nil, schemaErr(at, "must be a string")
+	}
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return nil, schemaErr(at, "invalid pattern: "+err.Error())
+	}
+	return re, nil
+}
+
+func schemaErr(at, msg string) error {
+	if at == "" {
+		return fmt.Errorf("invalid schema: %s", msg)
+	}
+	return fmt.Errorf("invalid schema at %s: %s", at, msg)
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func compact(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// escapeToken escapes a property name for use in a JSON pointer
+func escapeToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+func splitPointer(ptr string) []string {
+	if ptr == "" {
+		return nil
+	}
+	parts := strings.Split(strings.TrimPrefix(ptr, "/"), "/")
+	for i, p := range parts {
+		parts[i] = strings.ReplaceAll(strings.ReplaceAll(p, "~1", "/"), "~0", "~")
+	}
+	return parts
+}
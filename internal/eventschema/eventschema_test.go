@@ -0,0 +1,151 @@
+package eventschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+const orderSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "order.created",
+	"type": "object",
+	"required": ["order_id", "amount", "items"],
+	"properties": {
+		"order_id": {"type": "string", "pattern": "^ord_"},
+		"amount": {"type": "number", "exclusiveMinimum": 0},
+		"currency": {"enum": ["usd", "eur"]},
+		"items": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/item"}},
+		"customer": {"type": "object", "properties": {"id": {"type": "string"}}}
+	},
+	"additionalProperties": false,
+	"$defs": {
+		"item": {
+			"type": "object",
+			"required": ["sku", "quantity"],
+			"properties": {"sku": {"type": "string"}, "quantity": {"type": "integer", "minimum": 1}}
+		}
+	}
+}`
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("decode %s: %v", s, err)
+	}
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	schema, err := Compile([]byte(orderSchema))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		payload string
+		want    []string
+	}{
+		{"valid", `{"order_id":"ord_1","amount":12.5,"currency":"usd","items":[{"sku":"A","quantity":2}]}`, nil},
+		{"missing required", `{"order_id":"ord_1","items":[{"sku":"A","quantity":1}]}`, []string{`missing required property "amount"`}},
+		{"wrong type", `{"order_id":7,"amount":1,"items":[{"sku":"A","quantity":1}]}`, []string{"/order_id: expected string, got number"}},
+		{"pattern and enum", `{"order_id":"x","amount":1,"currency":"gbp","items":[{"sku":"A","quantity":1}]}`, []string{
+			`/currency: must be one of ["usd","eur"]`, `/order_id: must match pattern "^ord_"`,
+		}},
+		{"bounds", `{"order_id":"ord_1","amount":0,"items":[]}`, []string{"/amount: must be > 0", "/items: must have at least 1 items"}},
+		{"ref into defs", `{"order_id":"ord_1","amount":1,"items":[{"sku":"A","quantity":1.5},{"quantity":0}]}`, []string{
+			"/items/0/quantity: expected integer, got number",
+			`/items/1: missing required property "sku"`,
+			"/items/1/quantity: must be >= 1",
+		}},
+		{"additional property", `{"order_id":"ord_1","amount":1,"items":[{"sku":"A","quantity":1}],"note":"hi"}`, []string{"/note: property is not allowed"}},
+		{"not an object", `[1]`, []string{"expected object, got array"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, v := range schema.Validate(decode(t, tt.payload)) {
+				got = append(got, v.String())
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_Combinators(t *testing.T) {
+	schema, err := Compile([]byte(`{
+		"oneOf": [
+			{"type": "object", "required": ["card"]},
+			{"type": "object", "required": ["bank"]}
+		],
+		"not": {"required": ["test"]}
+	}`))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+	if v := schema.Validate(decode(t, `{"card":"4242"}`)); v != nil {
+		t.Errorf("Validate(card) = %v, want valid", v)
+	}
+	if v := schema.Validate(decode(t, `{"card":"4242","bank":"x"}`)); len(v) != 1 || !strings.Contains(v[0].Message, "matched 2") {
+		t.Errorf("Validate(card and bank) = %v, want a oneOf violation", v)
+	}
+	if v := schema.Validate(decode(t, `{"card":"4242","test":true}`)); len(v) != 1 || !strings.Contains(v[0].Message, "not") {
+		t.Errorf("Validate(test) = %v, want a not violation", v)
+	}
+}
+
+func TestValidate_SelfReference(t *testing.T) {
+	schema, err := Compile([]byte(`{"anyOf": [{"$ref": "#"}]}`))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+	if v := schema.Validate(decode(t, `{}`)); len(v) == 0 {
+		t.Error("Validate() on a schema referring only to itself should fail instead of recursing")
+	}
+
+	tree, err := Compile([]byte(`{"type":"object","properties":{"children":{"type":"array","items":{"$ref":"#"}}}}`))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+	if v := tree.Validate(decode(t, `{"children":[{"children":[{"children":"leaf"}]}]}`)); len(v) != 1 || v[0].Path != "/children/0/children/0/children" {
+		t.Errorf("Validate(tree) = %v, want one violation at the nested children", v)
+	}
+}
+
+func TestValidate_ViolationLimit(t *testing.T) {
+	schema, err := Compile([]byte(`{"type":"array","items":{"type":"string"}}`))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+	if v := schema.Validate(decode(t, `[`+strings.Repeat(`1,`, 50)+`1]`)); len(v) != maxViolations {
+		t.Errorf("Validate() reported %d violations, want %d", len(v), maxViolations)
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		schema  string
+		wantErr string
+	}{
+		{"not json", `{`, "not valid JSON"},
+		{"not a schema", `"object"`, "must be an object or a boolean"},
+		{"unknown keyword", `{"properties":{"id":{"typ":"string"}}}`, "/properties/id/typ: keyword is not supported"},
+		{"unknown type", `{"type":"float"}`, `unknown type "float"`},
+		{"bad pattern", `{"pattern":"("}`, "invalid pattern"},
+		{"remote ref", `{"$ref":"https://example.com/schema.json"}`, "only references within the schema"},
+		{"dangling ref", `{"$ref":"#/$defs/missing"}`, "does not resolve"},
+		{"negative count", `{"minLength":-1}`, "non-negative integer"},
+		{"too large", `{"description":"` + strings.Repeat("x", MaxSchemaBytes) + `"}`, "byte limit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Compile([]byte(tt.schema)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Compile() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
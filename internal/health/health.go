@@ -4,33 +4,126 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	grpc_health "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
+// How long each component's ping may take
+const pingTimeout = 1 * time.Second
+
 type Status struct {
-	OK       bool   `json:"ok"`
-	Message  string `json:"message,omitempty"`
-	Database bool   `json:"database,omitempty"`
+	OK         bool        `json:"ok"`
+	Ready      bool        `json:"ready"` // every required component answered; OK mirrors it
+	Message    string      `json:"message,omitempty"`
+	Database   bool        `json:"database,omitempty"`
+	Components []Component `json:"components,omitempty"`
+}
+
+// Component is one dependency's health
+type Component struct {
+	Name      string  `json:"name"`
+	OK        bool    `json:"ok"`
+	Optional  bool    `json:"optional,omitempty"`
+	LatencyMS float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
 }
 
-// HTTPHandler returns an HTTP handler that reports the health status of the service
-func HTTPHandler(pool *pgxpool.Pool) http.HandlerFunc {
+// Check pings one dependency. An optional dependency is reported without affecting readiness.
+type Check struct {
+	Name     string
+	Optional bool
+	Ping     func(ctx context.Context) error
+}
+
+// HTTPHandler returns an HTTP handler that reports the health status of the service: the
+// database, when pool is set, and each of checks, pinged concurrently
+func HTTPHandler(pool *pgxpool.Pool, checks ...Check) http.HandlerFunc {
+	if pool != nil {
+		checks = append([]Check{{Name: "db", Ping: pool.Ping}}, checks...)
+	}
 	return func(w http.ResponseWriter, r *http.Request) {
-		st := Status{OK: true, Message: "ok", Database: true}
+		st := Status{OK: true, Ready: true, Message: "ok", Database: true}
+		st.Components = run(r.Context(), checks)
 
-		if pool != nil {
-			ctx, cancel := context.WithTimeout(r.Context(), 1*time.Second)
-			defer cancel()
-			if err := pool.Ping(ctx); err != nil {
-				st.OK = false
-				st.Message = "db ping failed"
+		var failed []string
+		for _, c := range st.Components {
+			if c.OK || c.Optional {
+				continue
+			}
+			failed = append(failed, c.Name)
+			if c.Name == "db" {
 				st.Database = false
-				w.WriteHeader(http.StatusServiceUnavailable)
 			}
 		}
 		w.Header().Set("Content-Type", "application/json")
+		if len(failed) > 0 {
+			st.OK, st.Ready = false, false
+			st.Message = strings.Join(failed, ", ") + " ping failed"
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
 		_ = json.NewEncoder(w).Encode(st)
 	}
 }
+
+// WatchGRPC keeps hs reporting each of services, and the server as a whole (""), as SERVING
+// while every required check passes and NOT_SERVING otherwise, so gRPC health probes fail
+// until the service's dependencies are ready. Checks run now and then every interval until
+// ctx is done.
+func WatchGRPC(ctx context.Context, hs *grpc_health.Server, every time.Duration, services []string, checks ...Check) {
+	services = append([]string{""}, services...)
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		st := healthpb.HealthCheckResponse_SERVING
+		for _, c := range run(ctx, checks) {
+			if !c.OK && !c.Optional {
+				st = healthpb.HealthCheckResponse_NOT_SERVING
+			}
+		}
+		for _, svc := range services {
+			hs.SetServingStatus(svc, st)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// run pings every check at once and reports them in order
+func run(ctx context.Context, checks []Check) []Component {
+	if len(checks) == 0 {
+		return nil
+	}
+	out := make([]Component, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+			defer cancel()
+			start := time.Now()
+			err := c.Ping(ctx)
+			out[i] = Component{
+				Name:      c.Name,
+				OK:        err == nil,
+				Optional:  c.Optional,
+				LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+			}
+			if err != nil {
+				out[i].Error = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+	return out
+}
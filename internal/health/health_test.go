@@ -3,12 +3,16 @@ package health
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+	grpc_health "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 // mockPool implements a mock pgxpool.Pool for testing
@@ -332,4 +336,101 @@ func TestStatusJSONOmitempty(t *testing.T) {
 			t.Logf("JSON output: %s", jsonStr)
 		})
 	}
-}
\ No newline at end of file
+}
+func TestHTTPHandler_Components(t *testing.T) {
+	up := func(context.Context) error { return nil }
+	down := func(context.Context) error { return errors.New("connection refused") }
+
+	tests := []struct {
+		name        string
+		checks      []Check
+		wantCode    int
+		wantMessage string
+	}{
+		{
+			name:        "all components up",
+			checks:      []Check{{Name: "nsq", Ping: up}, {Name: "tracing", Optional: true, Ping: up}},
+			wantCode:    http.StatusOK,
+			wantMessage: "ok",
+		},
+		{
+			name:        "optional component down",
+			checks:      []Check{{Name: "nsq", Ping: up}, {Name: "tracing", Optional: true, Ping: down}},
+			wantCode:    http.StatusOK,
+			wantMessage: "ok",
+		},
+		{
+			name:        "required component down",
+			checks:      []Check{{Name: "nsq", Ping: down}, {Name: "tracing", Optional: true, Ping: down}},
+			wantCode:    http.StatusServiceUnavailable,
+			wantMessage: "nsq ping failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			HTTPHandler(nil, tt.checks...)(w, httptest.NewRequest("GET", "/readyz", nil))
+
+			var status Status
+			if err := json.Unmarshal(w.Body.Bytes(), &status); err != nil {
+				t.Fatalf("response JSON parse error: %v", err)
+			}
+			ready := tt.wantCode == http.StatusOK
+			if w.Code != tt.wantCode || status.Ready != ready || status.OK != ready || status.Message != tt.wantMessage {
+				t.Errorf("HTTPHandler() = %d %+v, want %d ready=%v %q", w.Code, status, tt.wantCode, ready, tt.wantMessage)
+			}
+			if len(status.Components) != len(tt.checks) {
+				t.Fatalf("components = %+v, want one per check", status.Components)
+			}
+			for i, c := range status.Components {
+				if c.Name != tt.checks[i].Name || c.Optional != tt.checks[i].Optional || c.LatencyMS < 0 {
+					t.Errorf("components[%d] = %+v", i, c)
+				}
+				if c.OK != (c.Error == "") {
+					t.Errorf("components[%d] ok = %v with error %q", i, c.OK, c.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchGRPC(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	checks := []Check{
+		{Name: "db", Ping: func(context.Context) error {
+			if down.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		}},
+		{Name: "tracing", Optional: true, Ping: func(context.Context) error { return errors.New("unreachable") }},
+	}
+	hs := grpc_health.NewServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go WatchGRPC(ctx, hs, 10*time.Millisecond, []string{"api.webhook.v1.WebhookService"}, checks...)
+
+	// waitFor polls until every service reports want
+	waitFor := func(want healthpb.HealthCheckResponse_ServingStatus) {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for _, svc := range []string{"", "api.webhook.v1.WebhookService"} {
+			for {
+				resp, err := hs.Check(ctx, &healthpb.HealthCheckRequest{Service: svc})
+				if err == nil && resp.Status == want {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatalf("service %q = %v, %v, want %v", svc, resp, err, want)
+				}
+				time.Sleep(5 * time.Millisecond)
+			}
+		}
+	}
+	waitFor(healthpb.HealthCheckResponse_NOT_SERVING)
+	down.Store(false)
+	waitFor(healthpb.HealthCheckResponse_SERVING) // the optional check failing doesn't count
+	down.Store(true)
+	waitFor(healthpb.HealthCheckResponse_NOT_SERVING)
+}
@@ -0,0 +1,262 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// targetClause matches endpoints (aliased ep) by tenant ($1) or endpoint id ($2); exactly one is set
+const targetClause = `(ep.tenant_id = $1 OR ep.id = NULLIF($2, '')::uuid)`
+
+// adminTarget validates that exactly one of tenant_id/endpoint_id is set and, when the caller
+// is authenticated, that an endpoint target belongs to the caller's tenant unless the caller is an admin.
+// Tenant targets are already checked against the token by the auth interceptor.
+func (s *Server) adminTarget(ctx context.Context, tenantID, endpointID string) error {
+	if (tenantID == "") == (endpointID == "") {
+		return apierr.Invalid("exactly one of tenant_id or endpoint_id is required")
+	}
+	if endpointID == "" {
+		return nil
+	}
+
+	var owner string
+	err := s.pool.QueryRow(ctx, `SELECT tenant_id FROM harborhook.endpoints WHERE id = $1`, endpointID).Scan(&owner)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apierr.NotFound("endpoint %s not found", endpointID)
+	}
+	if err != nil {
+		return fmt.Errorf("lookup endpoint: %w", err)
+	}
+	if claim, ok := auth.GetTenantIDFromContext(ctx); ok && claim != "" && claim != owner && !auth.IsAdmin(ctx) {
+		return apierr.NotFound("endpoint %s not found", endpointID)
+	}
+	return nil
+}
+
+// FreezeDeliveries stops workers from dispatching deliveries for a tenant or endpoint.
+// Tasks that reach a worker while the freeze is active are parked rather than sent.
+func (s *Server) FreezeDeliveries(ctx context.Context, req *webhookv1.FreezeDeliveriesRequest) (*webhookv1.FreezeDeliveriesResponse, error) {
+	if err := s.adminTarget(ctx, req.GetTenantId(), req.GetEndpointId()); err != nil {
+		return nil, err
+	}
+
+	var (
+		id        string
+		createdAt time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.delivery_freezes(tenant_id, endpoint_id, reason)
+		VALUES (NULLIF($1, ''), NULLIF($2, '')::uuid, $3)
+		RETURNING id, created_at
+	`, req.GetTenantId(), req.GetEndpointId(), req.GetReason()).Scan(&id, &createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("create freeze: %w", err)
+	}
+
+	var pending int64
+	err = s.pool.QueryRow(ctx, `
+		SELECT count(*)
+		FROM harborhook.deliveries d
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		WHERE d.status IN ('queued', 'failed') AND `+targetClause,
+		req.GetTenantId(), req.GetEndpointId()).Scan(&pending)
+	if err != nil {
+		return nil, fmt.Errorf("count pending deliveries: %w", err)
+	}
+
+	tracing.AddSpanEvent(ctx, "admin.freeze_deliveries", attribute.Int64("pending_count", pending))
+	return &webhookv1.FreezeDeliveriesResponse{
+		Freeze: &webhookv1.DeliveryFreeze{
+			Id:         id,
+			TenantId:   req.GetTenantId(),
+			EndpointId: req.GetEndpointId(),
+			Reason:     req.GetReason(),
+			CreatedAt:  timestamppb.New(createdAt),
+		},
+		PendingCount: int32(pending),
+	}, nil
+}
+
+// DrainQueue parks every queued or retrying delivery for a tenant or endpoint.
+// Their NSQ messages are dropped by the worker when they arrive; ResumeDeliveries requeues them.
+func (s *Server) DrainQueue(ctx context.Context, req *webhookv1.DrainQueueRequest) (*webhookv1.DrainQueueResponse, error) {
+	if err := s.adminTarget(ctx, req.GetTenantId(), req.GetEndpointId()); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		WITH cur AS (
+			SELECT d.id, d.status::text AS status
+			FROM harborhook.deliveries d
+			JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+			WHERE d.status IN ('queued', 'failed') AND `+targetClause+`
+			FOR UPDATE OF d
+		)
+		UPDATE harborhook.deliveries d
+		SET status = 'parked'
+		FROM cur, harborhook.endpoints ep
+		WHERE d.id = cur.id AND ep.id = d.endpoint_id
+		RETURNING d.id, d.event_id, d.endpoint_id, ep.tenant_id, d.attempt, cur.status`,
+		req.GetTenantId(), req.GetEndpointId())
+	if err != nil {
+		return nil, fmt.Errorf("park deliveries: %w", err)
+	}
+
+	var changes []changefeed.Change
+	for rows.Next() {
+		c := changefeed.Change{To: "parked", At: time.Now().UTC()}
+		if err := rows.Scan(&c.DeliveryID, &c.EventID, &c.EndpointID, &c.TenantID, &c.Attempt, &c.From); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("park deliveries: %w", err)
+	}
+	s.feed.Publish(changes...)
+
+	tracing.AddSpanEvent(ctx, "admin.drain_queue", attribute.Int("parked_count", len(changes)))
+	return &webhookv1.DrainQueueResponse{ParkedCount: int32(len(changes))}, nil
+}
+
+// ResumeDeliveries releases active freezes for a tenant or endpoint and requeues its parked deliveries.
+// Deliveries still covered by another active freeze (e.g. an endpoint freeze inside a resumed tenant) stay parked.
+// Released endpoints are marked recovered, so workers ramp them back up per their recovery ramp.
+func (s *Server) ResumeDeliveries(ctx context.Context, req *webhookv1.ResumeDeliveriesRequest) (*webhookv1.ResumeDeliveriesResponse, error) {
+	if err := s.adminTarget(ctx, req.GetTenantId(), req.GetEndpointId()); err != nil {
+		return nil, err
+	}
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	released, err := tx.Exec(ctx, `
+		UPDATE harborhook.delivery_freezes
+		SET released_at = now()
+		WHERE released_at IS NULL
+		  AND (tenant_id = $1 OR endpoint_id = NULLIF($2, '')::uuid)
+	`, req.GetTenantId(), req.GetEndpointId())
+	if err != nil {
+		return nil, fmt.Errorf("release freezes: %w", err)
+	}
+
+	// Endpoints that are no longer frozen start their recovery ramp now
+	if released.RowsAffected() > 0 {
+		if _, err := tx.Exec(ctx, `
+			UPDATE harborhook.endpoints ep
+			SET recovered_at = now()
+			WHERE `+targetClause+`
+			  AND NOT EXISTS (
+				SELECT 1 FROM harborhook.delivery_freezes f
+				WHERE f.released_at IS NULL AND (f.tenant_id = ep.tenant_id OR f.endpoint_id = ep.id)
+			  )
+		`, req.GetTenantId(), req.GetEndpointId()); err != nil {
+			return nil, fmt.Errorf("mark endpoints recovered: %w", err)
+		}
+	}
+
+	rows, err := tx.Query(ctx, `
+		WITH requeued AS (
+			UPDATE harborhook.deliveries d
+			SET status = 'queued'
+			FROM harborhook.endpoints ep
+			WHERE ep.id = d.endpoint_id AND d.status = 'parked' AND `+targetClause+`
+			  AND NOT EXISTS (
+				SELECT 1 FROM harborhook.delivery_freezes f
+				WHERE f.released_at IS NULL AND (f.tenant_id = ep.tenant_id OR f.endpoint_id = ep.id)
+			  )
+			RETURNING d.id, d.event_id, d.endpoint_id, d.subscription_id, d.attempt, d.ordering_key, d.replay_key,
+			          d.override_url, d.payload_patch, ep.tenant_id, ep.url
+		)
+		SELECT r.id, r.event_id, r.endpoint_id, r.tenant_id, COALESCE(r.override_url, r.url), r.attempt,
+		       ev.event_type, ev.payload::text,
+		       COALESCE(sub.include_fields, '{}'), COALESCE(sub.exclude_fields, '{}'), r.ordering_key IS NOT NULL,
+		       ev.deliver_by, ev.priority, COALESCE(r.replay_key, ''), COALESCE(r.payload_patch::text, '')
+		FROM requeued r
+		JOIN harborhook.events ev ON ev.id = r.event_id
+		LEFT JOIN harborhook.subscriptions sub ON sub.id = r.subscription_id
+	`, req.GetTenantId(), req.GetEndpointId())
+	if err != nil {
+		return nil, fmt.Errorf("requeue parked deliveries: %w", err)
+	}
+
+	var tasks []delivery.Task
+	for rows.Next() {
+		var (
+			t           delivery.Task
+			payloadJSON string
+			deliverBy   sql.NullTime
+			priority    string
+			patch       string
+		)
+		if err := rows.Scan(&t.DeliveryID, &t.EventID, &t.EndpointID, &t.TenantID, &t.EndpointURL, &t.Attempt,
+			&t.EventType, &payloadJSON, &t.IncludeFields, &t.ExcludeFields, &t.Ordered, &deliverBy, &priority, &t.ReplayKey, &patch); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t.Priority = s.taskPriority(priority)
+		// A deadline that passed while parked is left to the worker, which dead-letters it as expired
+		if deliverBy.Valid {
+			t.SetDeadline(deliverBy.Time)
+		}
+		if t.Payload, t.PayloadRef, err = s.storedPayload(ctx, t.TenantID, t.EventID, payloadJSON, patch); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+
+	changes := make([]changefeed.Change, 0, len(tasks))
+	for _, t := range tasks {
+		changes = append(changes, changefeed.FromTask(t, "parked", "queued"))
+	}
+	s.feed.Publish(changes...)
+
+	// Publish after commit so workers see the queued status when the task arrives
+	traceHeaders := tracing.PropagateTraceToNSQ(ctx)
+	for _, t := range tasks {
+		t.PublishedAt = time.Now().UTC().Format(time.RFC3339)
+		t.TraceHeaders = traceHeaders
+		b, _ := json.Marshal(t)
+		if err := s.prod.Publish(taskTopic(t), b); err != nil {
+			return nil, fmt.Errorf("nsq publish: %w", err)
+		}
+	}
+
+	tracing.AddSpanEvent(ctx, "admin.resume_deliveries",
+		attribute.Int64("released_freezes", released.RowsAffected()),
+		attribute.Int("requeued_count", len(tasks)))
+	return &webhookv1.ResumeDeliveriesResponse{
+		ReleasedFreezes: int32(released.RowsAffected()),
+		RequeuedCount:   int32(len(tasks)),
+	}, nil
+}
@@ -0,0 +1,70 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_AdminControls_Validation(t *testing.T) {
+	const (
+		endpointID = "123e4567-e89b-12d3-a456-426614174000"
+		errorMsg   = "exactly one of tenant_id or endpoint_id is required"
+	)
+	server := &Server{}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		call func() error
+	}{
+		{
+			name: "freeze without target",
+			call: func() error {
+				_, err := server.FreezeDeliveries(ctx, &webhookv1.FreezeDeliveriesRequest{Reason: "incident"})
+				return err
+			},
+		},
+		{
+			name: "freeze with both targets",
+			call: func() error {
+				_, err := server.FreezeDeliveries(ctx, &webhookv1.FreezeDeliveriesRequest{TenantId: "tn_1", EndpointId: endpointID})
+				return err
+			},
+		},
+		{
+			name: "drain without target",
+			call: func() error {
+				_, err := server.DrainQueue(ctx, &webhookv1.DrainQueueRequest{})
+				return err
+			},
+		},
+		{
+			name: "drain with both targets",
+			call: func() error {
+				_, err := server.DrainQueue(ctx, &webhookv1.DrainQueueRequest{TenantId: "tn_1", EndpointId: endpointID})
+				return err
+			},
+		},
+		{
+			name: "resume without target",
+			call: func() error {
+				_, err := server.ResumeDeliveries(ctx, &webhookv1.ResumeDeliveriesRequest{})
+				return err
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if err == nil {
+				t.Fatal("expected error but got none")
+			}
+			if err.Error() != errorMsg {
+				t.Errorf("error = %q, want %q", err.Error(), errorMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SystemEventStatusAnomaly is recorded when an endpoint's response codes shift away from its baseline
+const SystemEventStatusAnomaly = "endpoint.status_anomaly"
+
+const (
+	// Responses in this window are compared against the anomalyBaseline before it
+	anomalyWindow   = 15 * time.Minute
+	anomalyBaseline = 24 * time.Hour
+	// An anomaly is not flagged again for the same endpoint and code within this long
+	anomalyCooldown = time.Hour
+
+	defaultSystemEventsPage = 50
+	maxSystemEventsPage     = 500
+)
+
+// statusCountsQuery counts each endpoint's latest response codes, split into the current
+// window ($1 seconds) and the baseline ($2 seconds) before it
+const statusCountsQuery = `
+	SELECT ep.tenant_id, d.endpoint_id::text, d.http_status,
+	       count(*) FILTER (WHERE d.updated_at >= now() - make_interval(secs => $1)),
+	       count(*) FILTER (WHERE d.updated_at < now() - make_interval(secs => $1))
+	FROM harborhook.deliveries d
+	JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+	WHERE d.http_status IS NOT NULL AND d.http_status > 0
+	  AND d.updated_at >= now() - make_interval(secs => $1 + $2)
+	GROUP BY 1, 2, 3`
+
+// endpointStatusCounts is one endpoint's response-code counts in both windows
+type endpointStatusCounts struct {
+	tenantID          string
+	current, baseline map[int]int64
+}
+
+// DetectStatusAnomalies compares every endpoint's recent response codes with its trailing
+// baseline and records a system event for each newly anomalous code. It returns how many
+// events were recorded. Replicas may run it concurrently; the cooldown check keeps
+// repeats rare rather than impossible.
+func (s *Server) DetectStatusAnomalies(ctx context.Context) (int, error) {
+	rows, err := s.pool.Query(ctx, statusCountsQuery, anomalyWindow.Seconds(), anomalyBaseline.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("count response codes: %w", err)
+	}
+	byEndpoint := map[string]*endpointStatusCounts{}
+	for rows.Next() {
+		var (
+			tenantID, endpointID string
+			code                 int
+			cur, base            int64
+		)
+		if err := rows.Scan(&tenantID, &endpointID, &code, &cur, &base); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		c, ok := byEndpoint[endpointID]
+		if !ok {
+			c = &endpointStatusCounts{tenantID: tenantID, current: map[int]int64{}, baseline: map[int]int64{}}
+			byEndpoint[endpointID] = c
+		}
+		c.current[code] += cur
+		c.baseline[code] += base
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for endpointID, c := range byEndpoint {
+		for _, a := range delivery.DetectStatusAnomalies(c.current, c.baseline) {
+			ok, err := s.recordStatusAnomaly(ctx, c.tenantID, endpointID, a)
+			if err != nil {
+				return recorded, err
+			}
+			if ok {
+				recorded++
+				metrics.RecordSystemEvent(SystemEventStatusAnomaly)
+			}
+		}
+	}
+	return recorded, nil
+}
+
+// recordStatusAnomaly stores a status anomaly unless the same endpoint and code were flagged
+// within anomalyCooldown. It reports whether an event was stored.
+func (s *Server) recordStatusAnomaly(ctx context.Context, tenantID, endpointID string, a delivery.StatusAnomaly) (bool, error) {
+	details, err := json.Marshal(map[string]any{
+		"status_code":    a.Code,
+		"count":          a.Count,
+		"current_share":  a.CurrentShare,
+		"baseline_share": a.BaselineShare,
+		"window_seconds": anomalyWindow.Seconds(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	tag, err := s.pool.Exec(ctx, `
+		INSERT INTO harborhook.system_events(tenant_id, endpoint_id, type, message, details)
+		SELECT $1, $2, $3, $4, $5
+		WHERE NOT EXISTS (
+			SELECT 1 FROM harborhook.system_events
+			WHERE endpoint_id = $2 AND type = $3
+			  AND details->>'status_code' = $6
+			  AND created_at >= now() - make_interval(secs => $7)
+		)`,
+		tenantID, endpointID, SystemEventStatusAnomaly, statusAnomalyMessage(a), details,
+		fmt.Sprint(a.Code), anomalyCooldown.Seconds())
+	if err != nil {
+		return false, fmt.Errorf("record status anomaly: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// statusAnomalyMessage summarises an anomaly for humans, e.g.
+// "401 Unauthorized rose to 85% of responses (baseline 0%)"
+func statusAnomalyMessage(a delivery.StatusAnomaly) string {
+	name := fmt.Sprint(a.Code)
+	if text := http.StatusText(a.Code); text != "" {
+		name += " " + text
+	}
+	if a.BaselineShare == 0 {
+		return fmt.Sprintf("%s is new: %.0f%% of responses in the last %s", name, a.CurrentShare*100, anomalyWindow)
+	}
+	return fmt.Sprintf("%s rose to %.0f%% of responses (baseline %.1f%%)", name, a.CurrentShare*100, a.BaselineShare*100)
+}
+
+// ListSystemEvents lists the conditions harborhook detected for a tenant, newest first
+func (s *Server) ListSystemEvents(ctx context.Context, req *webhookv1.ListSystemEventsRequest) (*webhookv1.ListSystemEventsResponse, error) {
+	limit := int32(defaultSystemEventsPage)
+	if req.GetLimit() > 0 {
+		limit = min(req.GetLimit(), maxSystemEventsPage)
+	}
+	var since *time.Time
+	if req.GetSince() != nil {
+		t := req.GetSince().AsTime()
+		since = &t
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id::text, COALESCE(endpoint_id::text, ''), type, message, details, created_at
+		FROM harborhook.system_events
+		WHERE tenant_id = $1
+		  AND (NULLIF($2, '') IS NULL OR type = $2)
+		  AND ($3::timestamptz IS NULL OR created_at >= $3)
+		ORDER BY created_at DESC
+		LIMIT $4`, req.GetTenantId(), req.GetType(), since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list system events: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.ListSystemEventsResponse{}
+	for rows.Next() {
+		var (
+			ev        = &webhookv1.SystemEvent{TenantId: req.GetTenantId()}
+			details   map[string]any
+			createdAt time.Time
+		)
+		if err := rows.Scan(&ev.Id, &ev.EndpointId, &ev.Type, &ev.Message, &details, &createdAt); err != nil {
+			return nil, err
+		}
+		if ev.Details, err = structpb.NewStruct(details); err != nil {
+			return nil, fmt.Errorf("decode details: %w", err)
+		}
+		ev.CreatedAt = timestamppb.New(createdAt)
+		resp.Events = append(resp.Events, ev)
+	}
+	return resp, rows.Err()
+}
@@ -0,0 +1,39 @@
+package ingest
+
+import (
+	"testing"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+)
+
+func TestStatusAnomalyMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		a    delivery.StatusAnomaly
+		want string
+	}{
+		{
+			name: "new code",
+			a:    delivery.StatusAnomaly{Code: 401, CurrentShare: 0.8},
+			want: "401 Unauthorized is new: 80% of responses in the last 15m0s",
+		},
+		{
+			name: "grown code",
+			a:    delivery.StatusAnomaly{Code: 503, CurrentShare: 0.4, BaselineShare: 0.05},
+			want: "503 Service Unavailable rose to 40% of responses (baseline 5.0%)",
+		},
+		{
+			name: "non-standard code",
+			a:    delivery.StatusAnomaly{Code: 599, CurrentShare: 0.5},
+			want: "599 is new: 50% of responses in the last 15m0s",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusAnomalyMessage(tt.a); got != tt.want {
+				t.Errorf("statusAnomalyMessage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package ingest
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// attachAttemptHistory fills in each delivery's tries from delivery_attempts. Deliveries from
+// before the table existed, and ones not yet tried, get an empty history.
+func (s *Server) attachAttemptHistory(ctx context.Context, deliveries []*webhookv1.DeliveryAttempt) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	ids := make([]string, len(deliveries))
+	byID := make(map[string]*webhookv1.DeliveryAttempt, len(deliveries))
+	for i, d := range deliveries {
+		ids[i] = d.GetDeliveryId()
+		byID[d.GetDeliveryId()] = d
+	}
+
+	history, err := s.store.AttemptHistory(ctx, ids)
+	if err != nil {
+		return err
+	}
+	for _, a := range history {
+		d, ok := byID[a.DeliveryID]
+		if !ok {
+			continue
+		}
+		d.History = append(d.History, &webhookv1.AttemptRecord{
+			Attempt:    a.Attempt,
+			Outcome:    a.Outcome,
+			HttpStatus: a.HTTPStatus,
+			LatencyMs:  a.LatencyMS,
+			Error:      a.Error,
+			SentAt:     toTS(a.SentAt),
+			FinishedAt: timestamppb.New(a.FinishedAt),
+
+			ResponseBody:      a.Response.Text,
+			ResponseTruncated: a.Response.Truncated,
+		})
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_GetDeliveryStatus_AttemptHistory(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	delivery := func(id, status string) []any {
+		return []any{id, "evt_1", "ep_1", nil, status, int32(200), nil,
+			at, at, at, nil, nil, nil, nil, id, int32(0)}
+	}
+	var historyArgs []any
+	server := NewServer(&dbfake.Pool{
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			switch {
+			case strings.Contains(sql, "FROM harborhook.delivery_attempts"):
+				historyArgs = args
+				return dbfake.NewRows(
+					[]any{"del_1", int32(1), "failed", int32(400), int32(120), "", `{"error":"unknown field"}`, true, at, at},
+					[]any{"del_1", int32(2), "failed", int32(0), int32(15000), "context deadline exceeded", "", false, at, at.Add(time.Minute)},
+					[]any{"del_1", int32(3), "delivered", int32(200), int32(80), "", "", false, at, at.Add(5 * time.Minute)},
+				), nil
+			case strings.Contains(sql, "FROM harborhook.deliveries d"):
+				return dbfake.NewRows(delivery("del_1", "delivered"), delivery("del_2", "queued")), nil
+			}
+			return nil, fmt.Errorf("unexpected query: %s", sql)
+		},
+	}, nil)
+
+	resp, err := server.GetDeliveryStatus(context.Background(), &webhookv1.GetDeliveryStatusRequest{EventId: "evt_1"})
+	if err != nil {
+		t.Fatalf("GetDeliveryStatus() unexpected error: %v", err)
+	}
+	if len(historyArgs) != 1 || fmt.Sprint(historyArgs[0]) != "[del_1 del_2]" {
+		t.Errorf("history queried with %v, want both deliveries", historyArgs)
+	}
+	if len(resp.Attempts) != 2 {
+		t.Fatalf("GetDeliveryStatus() returned %d deliveries, want 2", len(resp.Attempts))
+	}
+	h := resp.Attempts[0].History
+	if len(h) != 3 || h[0].Attempt != 1 || h[0].HttpStatus != 400 || h[1].Error != "context deadline exceeded" ||
+		h[2].Outcome != "delivered" || h[2].LatencyMs != 80 || !h[2].FinishedAt.AsTime().Equal(at.Add(5*time.Minute)) {
+		t.Errorf("del_1 history = %v, want its three tries in order", h)
+	}
+	if len(h) > 0 && (h[0].ResponseBody != `{"error":"unknown field"}` || !h[0].ResponseTruncated) {
+		t.Errorf("first try response body %q (truncated %v), want the captured body", h[0].ResponseBody, h[0].ResponseTruncated)
+	}
+	if len(resp.Attempts[1].History) != 0 {
+		t.Errorf("del_2 history = %v, want none before its first try", resp.Attempts[1].History)
+	}
+}
+
+func TestServer_GetDeliveryStatus_OtherTenant(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	server := NewServer(&dbfake.Pool{
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			switch {
+			case strings.Contains(sql, "FROM harborhook.delivery_attempts"):
+				return dbfake.NewRows(), nil
+			case strings.Contains(sql, "FROM harborhook.deliveries d"):
+				// evt_1 belongs to tn_b
+				if strings.Contains(sql, "ev.tenant_id = $2") && args[1] != "tn_b" {
+					return dbfake.NewRows(), nil
+				}
+				return dbfake.NewRows([]any{"del_1", "evt_1", "ep_1", nil, "delivered", int32(200), nil,
+					at, at, at, nil, nil, nil, nil, "del_1", int32(0)}), nil
+			}
+			return nil, fmt.Errorf("unexpected query: %s", sql)
+		},
+	}, nil)
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "tn_a", Role: auth.RoleOperator})
+	resp, err := server.GetDeliveryStatus(ctx, &webhookv1.GetDeliveryStatusRequest{EventId: "evt_1"})
+	if err != nil {
+		t.Fatalf("GetDeliveryStatus() unexpected error: %v", err)
+	}
+	if len(resp.Attempts) != 0 {
+		t.Errorf("GetDeliveryStatus(another tenant's event) returned %v, want nothing", resp.Attempts)
+	}
+}
@@ -0,0 +1,374 @@
+package ingest
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+const (
+	defaultAuditLogPage = 50
+	maxAuditLogPage     = 500
+)
+
+// auditSpec is what the audit log records a method as
+type auditSpec struct {
+	action       string
+	resourceType string // endpoint, delivery or dlq
+}
+
+// auditedMethods are the WebhookService methods that change what gets delivered where
+var auditedMethods = map[string]auditSpec{
+	"CreateEndpoint":               {"endpoint.create", "endpoint"},
+	"VerifyEndpoint":               {"endpoint.verify", "endpoint"},
+	"SetEndpointRecoveryRamp":      {"endpoint.set_recovery_ramp", "endpoint"},
+	"SetEndpointRetryPolicy":       {"endpoint.set_retry_policy", "endpoint"},
+	"SetEndpointClientCertificate": {"endpoint.set_client_certificate", "endpoint"},
+	"SetEndpointCompression":       {"endpoint.set_compression", "endpoint"},
+	"SetEndpointTimeout":           {"endpoint.set_timeout", "endpoint"},
+	"SetEndpointProxy":             {"endpoint.set_proxy", "endpoint"},
+	"SetEndpointSignatureScheme":   {"endpoint.set_signature_scheme", "endpoint"},
+	"SetEndpointOrdering":          {"endpoint.set_ordering", "endpoint"},
+	"SetEndpointHealthCheck":       {"endpoint.set_health_check", "endpoint"},
+	"EnableEndpoint":               {"endpoint.enable", "endpoint"},
+	"DeleteEndpoint":               {"endpoint.delete", "endpoint"},
+	"ReplayDelivery":               {"delivery.replay", "delivery"},
+	"ReplayDLQ":                    {"dlq.replay", "dlq"},
+	"PurgeDLQ":                     {"dlq.purge", "dlq"},
+	"SetDLQRetention":              {"dlq.set_retention", "dlq"},
+	"SetNotificationSettings":      {"tenant.set_notifications", "tenant"},
+}
+
+// auditEntry is one row of harborhook.audit_log
+type auditEntry struct {
+	tenantID      string
+	action        string
+	resourceType  string
+	resourceID    string
+	actorTenantID string
+	actorSubject  string
+	actorRole     string
+	clientIP      string
+	before, after []byte // JSON; nil when there is nothing to show
+}
+
+// endpointSnapshotSQL describes an endpoint for the audit log. Secrets, keys, verification tokens
+// and custom headers are left out; a secret shows only as a fingerprint so a rotation is visible,
+// and a proxy password is masked.
+const endpointSnapshotSQL = `
+	SELECT tenant_id, jsonb_build_object(
+		'id', id::text,
+		'tenant_id', tenant_id,
+		'url', url,
+		'rate_per_sec', rate_per_sec,
+		'secret_fingerprint', CASE WHEN COALESCE(secret, '') = '' THEN NULL
+		                           ELSE left(encode(sha256(convert_to(secret, 'UTF8')), 'hex'), 12) END,
+		'verified', verified_at IS NOT NULL,
+		'recovery_ramp', jsonb_build_object('percents', recovery_ramp_percents, 'step_seconds', recovery_ramp_step_seconds),
+		'retry_policy', jsonb_build_object('max_attempts', retry_max_attempts, 'backoff_seconds', retry_backoff_seconds, 'retry_on', retry_on),
+		'client_certificate', client_cert_pem IS NOT NULL,
+		'compression', compression,
+		'timeout_ms', timeout_ms,
+		'signature_scheme', signature_scheme,
+		'ordered', ordered,
+		'partition_key', partition_key,
+		'proxy_url', regexp_replace(proxy_url, ':[^:/@]*@', ':xxxxx@'),
+		'health_check', CASE WHEN health_check_enabled THEN health_check_method END,
+		'disabled_at', disabled_at,
+		'disabled_reason', disabled_reason,
+		'created_at', created_at)
+	FROM harborhook.endpoints
+	WHERE id = $1`
+
+// deliverySnapshotSQL describes a delivery for the audit log
+const deliverySnapshotSQL = `
+	SELECT ep.tenant_id, jsonb_build_object(
+		'id', d.id::text,
+		'event_id', d.event_id::text,
+		'endpoint_id', d.endpoint_id::text,
+		'replay_of', d.replay_of::text,
+		'status', d.status,
+		'http_status', d.http_status,
+		'error', COALESCE(d.error_reason, d.last_error))
+	FROM harborhook.deliveries d
+	JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+	WHERE d.id = $1`
+
+// AuditInterceptor records successful management operations in the audit log, with who made
+// them and the resource before and after. Chain it after authentication so the caller is known.
+// Dry runs aren't recorded. The operation has already happened when the entry is written, so
+// failing to write it is counted and traced rather than returned.
+func (s *Server) AuditInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		spec, ok := auditedMethods[info.FullMethod[strings.LastIndex(info.FullMethod, "/")+1:]]
+		if !ok {
+			return handler(ctx, req)
+		}
+		if r, ok := req.(interface{ GetDryRun() bool }); ok && r.GetDryRun() {
+			return handler(ctx, req)
+		}
+
+		entry := s.auditBefore(ctx, spec, req)
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, err
+		}
+		s.auditAfter(ctx, &entry, req, resp)
+		if err := s.writeAudit(ctx, entry); err != nil {
+			metrics.RecordAuditWriteFailure(spec.action)
+			tracing.AddSpanEvent(ctx, "audit_write_failed",
+				attribute.String("action", spec.action),
+				attribute.String("error", err.Error()),
+			)
+		}
+		return resp, nil
+	}
+}
+
+// auditBefore starts an entry for req, capturing the resource as it is before the handler runs
+func (s *Server) auditBefore(ctx context.Context, spec auditSpec, req interface{}) auditEntry {
+	e := auditEntry{action: spec.action, resourceType: spec.resourceType, clientIP: clientIP(ctx)}
+	e.actorTenantID, _ = auth.GetTenantIDFromContext(ctx)
+	e.actorSubject, _ = auth.GetSubjectFromContext(ctx)
+	if role, ok := auth.GetRoleFromContext(ctx); ok {
+		e.actorRole = string(role)
+	}
+	if r, ok := req.(interface{ GetTenantId() string }); ok {
+		e.tenantID = r.GetTenantId()
+	}
+
+	var snapshotTenant string
+	switch spec.resourceType {
+	case "endpoint":
+		if r, ok := req.(interface{ GetEndpointId() string }); ok {
+			e.resourceID = r.GetEndpointId()
+		}
+		if e.resourceID != "" {
+			snapshotTenant, e.before = s.snapshot(ctx, endpointSnapshotSQL, e.resourceID)
+		}
+	case "delivery":
+		e.resourceID = req.(interface{ GetDeliveryId() string }).GetDeliveryId()
+		snapshotTenant, e.before = s.snapshot(ctx, deliverySnapshotSQL, e.resourceID)
+	case "dlq":
+		// A DLQ operation matches many deliveries, so the request itself is the useful record
+		if r, ok := req.(interface{ GetEndpointId() string }); ok {
+			e.resourceID = r.GetEndpointId()
+		}
+		if e.resourceID == "" {
+			e.resourceID = e.tenantID
+		}
+		e.before = protoJSON(req)
+	case "tenant":
+		e.resourceID = e.tenantID
+	}
+
+	if e.tenantID == "" {
+		e.tenantID = snapshotTenant
+	}
+	if e.tenantID == "" {
+		e.tenantID = e.actorTenantID
+	}
+	return e
+}
+
+// auditAfter completes e with the resource as the handler left it
+func (s *Server) auditAfter(ctx context.Context, e *auditEntry, req, resp interface{}) {
+	switch e.resourceType {
+	case "endpoint":
+		if e.action == "endpoint.delete" {
+			return
+		}
+		if r, ok := resp.(*webhookv1.CreateEndpointResponse); ok {
+			e.resourceID = r.GetEndpoint().GetId()
+		}
+		tenantID, after := s.snapshot(ctx, endpointSnapshotSQL, e.resourceID)
+		e.after = after
+		if e.tenantID == "" {
+			e.tenantID = tenantID
+		}
+	default:
+		e.after = protoJSON(resp)
+	}
+}
+
+// snapshot runs a snapshot query for id, returning the resource's tenant and JSON. A resource
+// that can't be read is left out of the entry rather than holding up the operation.
+func (s *Server) snapshot(ctx context.Context, query, id string) (string, []byte) {
+	var (
+		tenantID string
+		doc      []byte
+	)
+	if err := s.pool.QueryRow(ctx, query, id).Scan(&tenantID, &doc); err != nil {
+		return "", nil
+	}
+	return tenantID, doc
+}
+
+func (s *Server) writeAudit(ctx context.Context, e auditEntry) error {
+	_, err := s.pool.Exec(ctx, `
+		INSERT INTO harborhook.audit_log
+			(tenant_id, action, resource_type, resource_id, actor_tenant_id, actor_subject, actor_role, client_ip, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::jsonb, $10::jsonb)`,
+		e.tenantID, e.action, e.resourceType, e.resourceID, e.actorTenantID, e.actorSubject, e.actorRole, e.clientIP,
+		nullJSON(e.before), nullJSON(e.after))
+	return err
+}
+
+// protoJSON renders a request or response for the audit log
+func protoJSON(v interface{}) []byte {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil
+	}
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// nullJSON passes an absent snapshot as SQL NULL
+func nullJSON(b []byte) any {
+	if b == nil {
+		return nil
+	}
+	return string(b)
+}
+
+// clientIP is the caller's address: the first X-Forwarded-For hop when a proxy (Envoy or the
+// grpc-gateway) set one, else the connection's peer
+func clientIP(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if xff := md.Get("x-forwarded-for"); len(xff) > 0 {
+			first, _, _ := strings.Cut(xff[0], ",")
+			if ip := strings.TrimSpace(first); ip != "" {
+				return ip
+			}
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// ListAuditLog returns a tenant's audit log entries, newest first
+func (s *Server) ListAuditLog(ctx context.Context, req *webhookv1.ListAuditLogRequest) (*webhookv1.ListAuditLogResponse, error) {
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	limit := int32(defaultAuditLogPage)
+	if req.GetLimit() > 0 {
+		limit = min(req.GetLimit(), maxAuditLogPage)
+	}
+	var before *int64
+	if tok := req.GetPageToken(); tok != "" {
+		id, err := decodeAuditCursor(tok)
+		if err != nil {
+			return nil, err
+		}
+		before = &id
+	}
+	var from, to *time.Time
+	if req.GetFrom() != nil {
+		t := req.GetFrom().AsTime()
+		from = &t
+	}
+	if req.GetTo() != nil {
+		t := req.GetTo().AsTime()
+		to = &t
+	}
+
+	// Fetch one extra row to know whether another page exists
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, action, resource_type, resource_id, actor_tenant_id, actor_subject, actor_role,
+		       client_ip, before, after, created_at
+		FROM harborhook.audit_log
+		WHERE tenant_id = $1
+		  AND (NULLIF($2, '') IS NULL OR action = $2)
+		  AND (NULLIF($3, '') IS NULL OR resource_id = $3)
+		  AND (NULLIF($4, '') IS NULL OR actor_subject = $4)
+		  AND ($5::timestamptz IS NULL OR created_at >= $5)
+		  AND ($6::timestamptz IS NULL OR created_at < $6)
+		  AND ($7::bigint IS NULL OR id < $7)
+		ORDER BY id DESC
+		LIMIT $8`,
+		tenantID, req.GetAction(), req.GetResourceId(), req.GetActorSubject(), from, to, before, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("list audit log: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.ListAuditLogResponse{}
+	for rows.Next() {
+		if int32(len(resp.Entries)) == limit {
+			resp.NextPageToken = encodeAuditCursor(resp.Entries[len(resp.Entries)-1].Id)
+			break
+		}
+		var (
+			e             = &webhookv1.AuditLogEntry{TenantId: tenantID}
+			before, after map[string]any
+			createdAt     time.Time
+		)
+		if err := rows.Scan(&e.Id, &e.Action, &e.ResourceType, &e.ResourceId, &e.ActorTenantId, &e.ActorSubject,
+			&e.ActorRole, &e.ClientIp, &before, &after, &createdAt); err != nil {
+			return nil, err
+		}
+		if e.Before, err = optionalStruct(before); err != nil {
+			return nil, fmt.Errorf("decode before: %w", err)
+		}
+		if e.After, err = optionalStruct(after); err != nil {
+			return nil, fmt.Errorf("decode after: %w", err)
+		}
+		e.CreatedAt = timestamppb.New(createdAt)
+		resp.Entries = append(resp.Entries, e)
+	}
+	return resp, rows.Err()
+}
+
+// optionalStruct converts a JSON object that may be NULL
+func optionalStruct(m map[string]any) (*structpb.Struct, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return structpb.NewStruct(m)
+}
+
+func encodeAuditCursor(id int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(id, 10)))
+}
+
+func decodeAuditCursor(tok string) (int64, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(tok)
+	if err != nil {
+		return 0, apierr.Invalid("invalid page_token")
+	}
+	id, err := strconv.ParseInt(string(raw), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, apierr.Invalid("invalid page_token")
+	}
+	return id, nil
+}
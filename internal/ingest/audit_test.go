@@ -0,0 +1,221 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// auditPool answers endpoint snapshots from url, which the test changes as the "handler" runs,
+// and keeps the audit rows written
+type auditPool struct {
+	url     string
+	written [][]any
+	failing bool
+}
+
+func (p *auditPool) pool() *dbfake.Pool {
+	return &dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			if !strings.Contains(sql, "FROM harborhook.endpoints") {
+				return dbfake.Row{Err: pgx.ErrNoRows}
+			}
+			return dbfake.Row{Values: []any{"tn_1", []byte(`{"id":"` + args[0].(string) + `","url":"` + p.url + `"}`)}}
+		},
+		ExecFunc: func(sql string, args []any) (pgconn.CommandTag, error) {
+			if p.failing {
+				return pgconn.CommandTag{}, errors.New("connection reset")
+			}
+			p.written = append(p.written, args)
+			return pgconn.NewCommandTag("INSERT 0 1"), nil
+		},
+	}
+}
+
+func auditCall(s *Server, method string, req interface{}, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "tn_1", Role: auth.RoleOperator, Subject: "alice@example.com"})
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("x-forwarded-for", "203.0.113.7, 10.0.0.2"))
+	info := &grpc.UnaryServerInfo{FullMethod: "/api.webhook.v1.WebhookService/" + method}
+	return s.AuditInterceptor()(ctx, req, info, handler)
+}
+
+func TestServer_AuditInterceptor_EndpointChange(t *testing.T) {
+	ap := &auditPool{url: "https://old.example.com"}
+	server := NewServer(ap.pool(), nil)
+
+	req := &webhookv1.SetEndpointCompressionRequest{TenantId: "tn_1", EndpointId: "ep_1"}
+	_, err := auditCall(server, "SetEndpointCompression", req, func(context.Context, interface{}) (interface{}, error) {
+		ap.url = "https://new.example.com"
+		return &webhookv1.SetEndpointCompressionResponse{}, nil
+	})
+	if err != nil {
+		t.Fatalf("interceptor unexpected error: %v", err)
+	}
+	if len(ap.written) != 1 {
+		t.Fatalf("wrote %d audit rows, want 1", len(ap.written))
+	}
+	row := ap.written[0]
+	want := []any{"tn_1", "endpoint.set_compression", "endpoint", "ep_1", "tn_1", "alice@example.com", "operator", "203.0.113.7"}
+	for i, w := range want {
+		if row[i] != w {
+			t.Errorf("column %d = %v, want %v", i, row[i], w)
+		}
+	}
+	if !strings.Contains(row[8].(string), "old.example.com") || !strings.Contains(row[9].(string), "new.example.com") {
+		t.Errorf("before/after = %v / %v, want the endpoint before and after", row[8], row[9])
+	}
+}
+
+func TestServer_AuditInterceptor_CreateAndDelete(t *testing.T) {
+	ap := &auditPool{url: "https://a.example.com"}
+	server := NewServer(ap.pool(), nil)
+
+	_, err := auditCall(server, "CreateEndpoint", &webhookv1.CreateEndpointRequest{TenantId: "tn_1", Secret: "s3cret"},
+		func(context.Context, interface{}) (interface{}, error) {
+			return &webhookv1.CreateEndpointResponse{Endpoint: &webhookv1.Endpoint{Id: "ep_new"}}, nil
+		})
+	if err != nil {
+		t.Fatalf("CreateEndpoint unexpected error: %v", err)
+	}
+	_, err = auditCall(server, "DeleteEndpoint", &webhookv1.DeleteEndpointRequest{TenantId: "tn_1", EndpointId: "ep_new"},
+		func(context.Context, interface{}) (interface{}, error) {
+			return &webhookv1.DeleteEndpointResponse{}, nil
+		})
+	if err != nil {
+		t.Fatalf("DeleteEndpoint unexpected error: %v", err)
+	}
+
+	if len(ap.written) != 2 {
+		t.Fatalf("wrote %d audit rows, want 2", len(ap.written))
+	}
+	created, deleted := ap.written[0], ap.written[1]
+	if created[3] != "ep_new" || created[8] != nil || created[9] == nil {
+		t.Errorf("create entry = %v, want the new endpoint with only an after snapshot", created)
+	}
+	if strings.Contains(created[9].(string), "s3cret") {
+		t.Error("create entry leaks the signing secret")
+	}
+	if deleted[3] != "ep_new" || deleted[8] == nil || deleted[9] != nil {
+		t.Errorf("delete entry = %v, want only a before snapshot", deleted)
+	}
+}
+
+func TestServer_AuditInterceptor_Skips(t *testing.T) {
+	ap := &auditPool{}
+	server := NewServer(ap.pool(), nil)
+	ok := func(context.Context, interface{}) (interface{}, error) { return &webhookv1.PurgeDLQResponse{}, nil }
+
+	if _, err := auditCall(server, "ListDLQ", &webhookv1.ListDLQRequest{}, ok); err != nil {
+		t.Fatalf("ListDLQ unexpected error: %v", err)
+	}
+	if _, err := auditCall(server, "PurgeDLQ", &webhookv1.PurgeDLQRequest{DryRun: true}, ok); err != nil {
+		t.Fatalf("PurgeDLQ dry run unexpected error: %v", err)
+	}
+	failed := func(context.Context, interface{}) (interface{}, error) { return nil, errors.New("endpoint not found") }
+	if _, err := auditCall(server, "DeleteEndpoint", &webhookv1.DeleteEndpointRequest{EndpointId: "ep_1"}, failed); err == nil {
+		t.Fatal("DeleteEndpoint error was swallowed")
+	}
+	if len(ap.written) != 0 {
+		t.Errorf("wrote %d audit rows for reads, dry runs and failures, want 0", len(ap.written))
+	}
+}
+
+func TestServer_AuditInterceptor_DLQ(t *testing.T) {
+	ap := &auditPool{}
+	server := NewServer(ap.pool(), nil)
+
+	_, err := auditCall(server, "PurgeDLQ", &webhookv1.PurgeDLQRequest{TenantId: "tn_1", All: true},
+		func(context.Context, interface{}) (interface{}, error) {
+			return &webhookv1.PurgeDLQResponse{MatchedCount: 4, PurgedCount: 4}, nil
+		})
+	if err != nil {
+		t.Fatalf("PurgeDLQ unexpected error: %v", err)
+	}
+	if len(ap.written) != 1 {
+		t.Fatalf("wrote %d audit rows, want 1", len(ap.written))
+	}
+	row := ap.written[0]
+	if row[3] != "tn_1" || !strings.Contains(row[8].(string), `"all":true`) || !strings.Contains(row[9].(string), `"purgedCount":4`) {
+		t.Errorf("purge entry = %v, want the tenant's request and response", row)
+	}
+}
+
+func TestServer_AuditInterceptor_WriteFailure(t *testing.T) {
+	ap := &auditPool{failing: true}
+	server := NewServer(ap.pool(), nil)
+
+	resp, err := auditCall(server, "ReplayDelivery", &webhookv1.ReplayDeliveryRequest{DeliveryId: "d_1"},
+		func(context.Context, interface{}) (interface{}, error) {
+			return &webhookv1.ReplayDeliveryResponse{}, nil
+		})
+	if err != nil || resp == nil {
+		t.Errorf("ReplayDelivery = %v, %v, want the replay to succeed without its audit entry", resp, err)
+	}
+}
+
+func TestServer_ListAuditLog(t *testing.T) {
+	now := time.Now()
+	entry := func(id int64) []any {
+		return []any{id, "endpoint.delete", "endpoint", "ep_1", "ops", "bob", "admin", "203.0.113.7",
+			map[string]any{"url": "https://a.example.com"}, map[string]any(nil), now}
+	}
+	var args []any
+	server := NewServer(&dbfake.Pool{QueryFunc: func(_ string, a []any) (pgx.Rows, error) {
+		args = a
+		return dbfake.NewRows(entry(9), entry(8), entry(7)), nil
+	}}, nil)
+
+	resp, err := server.ListAuditLog(context.Background(), &webhookv1.ListAuditLogRequest{TenantId: "tn_1", Limit: 2})
+	if err != nil {
+		t.Fatalf("ListAuditLog() unexpected error: %v", err)
+	}
+	if len(resp.Entries) != 2 || resp.Entries[1].Id != 8 || resp.NextPageToken == "" {
+		t.Fatalf("ListAuditLog() = %d entries, token %q, want 2 and a next page", len(resp.Entries), resp.NextPageToken)
+	}
+	if e := resp.Entries[0]; e.Before.AsMap()["url"] != "https://a.example.com" || e.After != nil || e.ActorSubject != "bob" {
+		t.Errorf("entry = %+v", e)
+	}
+
+	if _, err := server.ListAuditLog(context.Background(), &webhookv1.ListAuditLogRequest{TenantId: "tn_1", PageToken: resp.NextPageToken}); err != nil {
+		t.Fatalf("ListAuditLog(next page) unexpected error: %v", err)
+	}
+	if before := args[6].(*int64); *before != 8 {
+		t.Errorf("next page starts before id %d, want 8", *before)
+	}
+}
+
+func TestServer_ListAuditLog_Validation(t *testing.T) {
+	server := &Server{}
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		req     *webhookv1.ListAuditLogRequest
+		wantErr string
+	}{
+		{"bad page token", context.Background(), &webhookv1.ListAuditLogRequest{TenantId: "tn_1", PageToken: "!!!"}, "invalid page_token"},
+		{
+			"other tenant",
+			auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "tn_1", Role: auth.RoleOperator}),
+			&webhookv1.ListAuditLogRequest{TenantId: "tn_2"},
+			`tenant_id "tn_2" does not match token`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := server.ListAuditLog(tt.ctx, tt.req); err == nil || err.Error() != tt.wantErr {
+				t.Errorf("ListAuditLog() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const defaultBacklogWindowSeconds = 300
+
+// backlogQuery returns, per endpoint matching targetClause, the pending and parked counts,
+// deliveries completed within the last $3 seconds, the rate limit and whether a freeze covers it
+const backlogQuery = `
+	SELECT ep.id::text, ep.rate_per_sec,
+	       count(d.id) FILTER (WHERE d.status IN ('queued', 'inflight', 'failed')),
+	       count(d.id) FILTER (WHERE d.status = 'parked'),
+	       count(d.id) FILTER (WHERE d.status IN ('delivered', 'dead')),
+	       EXISTS (
+	           SELECT 1 FROM harborhook.delivery_freezes f
+	           WHERE f.released_at IS NULL AND (f.tenant_id = ep.tenant_id OR f.endpoint_id = ep.id)
+	       )
+	FROM harborhook.endpoints ep
+	LEFT JOIN harborhook.deliveries d ON d.endpoint_id = ep.id
+	     AND (d.status IN ('queued', 'inflight', 'failed', 'parked')
+	          OR (d.status IN ('delivered', 'dead') AND d.updated_at >= now() - make_interval(secs => $3)))
+	WHERE ` + targetClause + `
+	GROUP BY ep.id
+	ORDER BY ep.id`
+
+// GetBacklogEstimate predicts when the pending deliveries for a tenant or endpoint will clear,
+// from queue depth, recent throughput, endpoint rate limits, freezes and the kill switch
+func (s *Server) GetBacklogEstimate(ctx context.Context, req *webhookv1.GetBacklogEstimateRequest) (*webhookv1.GetBacklogEstimateResponse, error) {
+	windowSeconds := req.GetWindowSeconds()
+	if windowSeconds == 0 {
+		windowSeconds = defaultBacklogWindowSeconds
+	}
+	if err := s.adminTarget(ctx, req.GetTenantId(), req.GetEndpointId()); err != nil {
+		return nil, err
+	}
+
+	var paused bool
+	if err := s.pool.QueryRow(ctx, `SELECT paused FROM harborhook.dispatch_control`).Scan(&paused); err != nil {
+		return nil, fmt.Errorf("read dispatch state: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, backlogQuery, req.GetTenantId(), req.GetEndpointId(), float64(windowSeconds))
+	if err != nil {
+		return nil, fmt.Errorf("query backlog: %w", err)
+	}
+	defer rows.Close()
+
+	now := time.Now()
+	window := time.Duration(windowSeconds) * time.Second
+	var (
+		parts       []delivery.Estimate
+		endpoints   []*webhookv1.BacklogEstimate
+		totalParked int64
+	)
+	for rows.Next() {
+		var (
+			endpointID string
+			rateLimit  int32
+			parked     int64
+			b          = delivery.Backlog{Window: window}
+		)
+		if err := rows.Scan(&endpointID, &rateLimit, &b.Pending, &parked, &b.Completed, &b.Frozen); err != nil {
+			return nil, err
+		}
+		b.RateLimit = float64(rateLimit)
+
+		est := delivery.EstimateBacklog(b, paused)
+		parts = append(parts, est)
+		totalParked += parked
+		pb := toBacklogEstimate(est, parked, now)
+		pb.EndpointId = endpointID
+		endpoints = append(endpoints, pb)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	total := delivery.CombineEstimates(parts)
+	tracing.AddSpanEvent(ctx, "backlog.estimated",
+		attribute.Int64("pending_count", total.Pending),
+		attribute.Bool("has_eta", total.OK))
+	return &webhookv1.GetBacklogEstimateResponse{
+		Total:         toBacklogEstimate(total, totalParked, now),
+		Endpoints:     endpoints,
+		WindowSeconds: windowSeconds,
+	}, nil
+}
+
+func toBacklogEstimate(e delivery.Estimate, parked int64, now time.Time) *webhookv1.BacklogEstimate {
+	pb := &webhookv1.BacklogEstimate{
+		PendingCount:     int32(e.Pending),
+		ParkedCount:      int32(parked),
+		ThroughputPerSec: e.Throughput,
+		HasEta:           e.OK,
+		BlockedReason:    e.Blocked,
+	}
+	if e.OK {
+		pb.EtaSeconds = int64(e.ETA.Round(time.Second).Seconds())
+		pb.ClearsAt = timestamppb.New(now.Add(e.ETA))
+	}
+	return pb
+}
@@ -0,0 +1,52 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_GetBacklogEstimate_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *webhookv1.GetBacklogEstimateRequest
+		errorMsg string
+	}{
+		{
+			name:     "no target",
+			request:  &webhookv1.GetBacklogEstimateRequest{},
+			errorMsg: "exactly one of tenant_id or endpoint_id is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+
+			_, err := server.GetBacklogEstimate(context.Background(), tt.request)
+			if err == nil {
+				t.Fatal("GetBacklogEstimate() expected error but got none")
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("GetBacklogEstimate() error = %q, want %q", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
+
+func TestToBacklogEstimate(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	got := toBacklogEstimate(delivery.Estimate{Pending: 120, Throughput: 2, ETA: time.Minute, OK: true}, 4, now)
+	if got.EtaSeconds != 60 || !got.ClearsAt.AsTime().Equal(now.Add(time.Minute)) || got.ParkedCount != 4 {
+		t.Errorf("toBacklogEstimate() = %+v", got)
+	}
+
+	got = toBacklogEstimate(delivery.Estimate{Pending: 5, Blocked: delivery.BlockedPaused}, 0, now)
+	if got.HasEta || got.ClearsAt != nil || got.BlockedReason != delivery.BlockedPaused {
+		t.Errorf("toBacklogEstimate() blocked = %+v", got)
+	}
+}
@@ -0,0 +1,255 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	"github.com/austindbirch/harbor_hook/internal/validation"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// batchEvent is an accepted event from a batch publish and the tasks it fanned out to
+type batchEvent struct {
+	index       int
+	eventType   string
+	payloadJSON []byte
+	idemKey     string
+	deliverBy   *time.Time // nil when the event has no delivery deadline
+	priority    string
+	tasks       []delivery.Task
+}
+
+// PublishEvents publishes a batch of events for one tenant. Events that fail validation or
+// quotas are rejected individually; the rest are stored with their outbox rows in a single
+// transaction and their tasks are published to NSQ asynchronously. Results come back in request order.
+func (s *Server) PublishEvents(ctx context.Context, req *webhookv1.PublishEventsRequest) (*webhookv1.PublishEventsResponse, error) {
+	ctx, span := tracing.StartSpan(ctx, "ingest.PublishEvents",
+		attribute.String("tenant_id", req.GetTenantId()),
+		attribute.Int("event_count", len(req.GetEvents())),
+	)
+	defer span.End()
+
+	results := make([]*webhookv1.PublishEventResult, len(req.GetEvents()))
+	reject := func(i int, err error) {
+		st := status.Convert(err)
+		results[i].ErrorCode = int32(st.Code())
+		results[i].Error = st.Message()
+	}
+
+	var accepted []*batchEvent
+	for i, ev := range req.GetEvents() {
+		results[i] = &webhookv1.PublishEventResult{Index: int32(i)}
+		// The request's rules leave events to be validated one at a time
+		if err := validation.Validate(ev); err != nil {
+			reject(i, err)
+			continue
+		}
+		deliverBy, err := deliveryDeadline(ev.GetDeliverBy(), ev.GetTtl(), time.Now())
+		if err != nil {
+			reject(i, err)
+			continue
+		}
+		priority, err := priorityColumn(ev.GetPriority())
+		if err != nil {
+			reject(i, err)
+			continue
+		}
+		payload := ev.GetPayload().AsMap()
+		payloadJSON, err := json.Marshal(payload)
+		if err != nil {
+			reject(i, status.Errorf(codes.InvalidArgument, "invalid payload: %v", err))
+			continue
+		}
+		if err := s.checkPayloadSize(payloadJSON); err != nil {
+			reject(i, err)
+			continue
+		}
+		if err := s.validatePayload(ctx, req.GetTenantId(), ev.GetEventType(), payload); err != nil {
+			reject(i, err)
+			continue
+		}
+		if err := s.enforceQuota(ctx, req.GetTenantId(), ev.GetEventType()); err != nil {
+			reject(i, err)
+			continue
+		}
+		accepted = append(accepted, &batchEvent{
+			index:       i,
+			eventType:   ev.GetEventType(),
+			payloadJSON: payloadJSON,
+			idemKey:     ev.GetIdempotencyKey(),
+			deliverBy:   deliverBy,
+			priority:    priority,
+		})
+	}
+
+	if len(accepted) > 0 {
+		outbox, err := s.storeBatch(ctx, req.GetTenantId(), accepted, results)
+		if err != nil {
+			tracing.SetSpanError(ctx, err)
+			return nil, err
+		}
+		s.publishBatch(ctx, accepted, outbox, results)
+	}
+
+	resp := &webhookv1.PublishEventsResponse{Results: results}
+	for _, r := range results {
+		if r.Error != "" {
+			resp.FailedCount++
+			continue
+		}
+		resp.PublishedCount++
+		metrics.RecordEventPublished(req.GetTenantId())
+	}
+	span.SetAttributes(
+		attribute.Int("published_count", int(resp.PublishedCount)),
+		attribute.Int("failed_count", int(resp.FailedCount)),
+	)
+	return resp, nil
+}
+
+// storeBatch inserts the accepted events, their deliveries and outbox rows in one transaction.
+// Events whose idempotency key already fanned out are marked duplicate and get no tasks.
+func (s *Server) storeBatch(ctx context.Context, tenantID string, events []*batchEvent, results []*webhookv1.PublishEventResult) ([]outboxMessage, error) {
+	tracing.AddSpanEvent(ctx, "db.insert_events_batch", attribute.Int("event_count", len(events)))
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	traceHeaders := tracing.PropagateTraceToNSQ(ctx)
+	for _, ev := range events {
+		var eventID string
+		if ev.idemKey == "" {
+			err = tx.QueryRow(ctx, `
+				INSERT INTO harborhook.events(tenant_id, event_type, payload, deliver_by, priority)
+				VALUES ($1, $2, $3::jsonb, $4, $5)
+				RETURNING id`,
+				tenantID, ev.eventType, string(ev.payloadJSON), ev.deliverBy, ev.priority).Scan(&eventID)
+		} else {
+			err = tx.QueryRow(ctx, `
+				INSERT INTO harborhook.events(tenant_id, event_type, payload, idempotency_key, deliver_by, priority)
+				VALUES ($1, $2, $3::jsonb, $4, $5, $6)
+				ON CONFLICT ON CONSTRAINT uq_events_tenant_idem DO NOTHING
+				RETURNING id`,
+				tenantID, ev.eventType, string(ev.payloadJSON), ev.idemKey, ev.deliverBy, ev.priority).Scan(&eventID)
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Same rule as PublishEvent: an existing event that already has deliveries, or is
+				// scheduled, is not fanned out again
+				var (
+					fannedOut bool
+					fanout    int32
+				)
+				err = tx.QueryRow(ctx, `
+					SELECT ev.id, ev.status = 'scheduled' OR EXISTS (SELECT 1 FROM harborhook.deliveries d WHERE d.event_id = ev.id),
+					       (SELECT count(*)::int FROM harborhook.deliveries d WHERE d.event_id = ev.id AND d.replay_of IS NULL)
+					FROM harborhook.events ev
+					WHERE ev.tenant_id = $1 AND ev.idempotency_key = $2`,
+					tenantID, ev.idemKey).Scan(&eventID, &fannedOut, &fanout)
+				if err == nil && fannedOut {
+					results[ev.index].EventId = eventID
+					results[ev.index].Duplicate = true
+					results[ev.index].FanoutCount = fanout
+					continue
+				}
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("insert event %d: %w", ev.index, err)
+		}
+		results[ev.index].EventId = eventID
+		if ev.tasks, err = s.fanoutEvent(ctx, tx, tenantID, eventID, ev.eventType, ev.payloadJSON, ev.deliverBy, ev.priority, traceHeaders); err != nil {
+			return nil, fmt.Errorf("insert deliveries for event %d: %w", ev.index, err)
+		}
+	}
+
+	var tasks []delivery.Task
+	for _, ev := range events {
+		tasks = append(tasks, ev.tasks...)
+	}
+	outbox, err := writeOutbox(ctx, tx, tasks)
+	if err != nil {
+		return nil, err
+	}
+	return outbox, tx.Commit(ctx)
+}
+
+// fanoutEvent inserts a queued delivery inside tx for each verified endpoint subscribed to the
+// event and returns their tasks, which still need their outbox rows
+func (s *Server) fanoutEvent(ctx context.Context, tx pgx.Tx, tenantID, eventID, eventType string, payloadJSON []byte, deliverBy *time.Time, priority string, traceHeaders map[string]string) ([]delivery.Task, error) {
+	taskPayload, payloadRef, err := s.claimCheck(ctx, tenantID, eventID, payloadJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		WITH ins AS (
+			INSERT INTO harborhook.deliveries(event_id, endpoint_id, subscription_id, status, ordering_key)
+			SELECT $1, s.endpoint_id, s.id, 'queued', `+orderingKeySQL("$4")+`
+			FROM harborhook.subscriptions s
+			JOIN harborhook.endpoints e ON e.id = s.endpoint_id
+			WHERE s.tenant_id = $2 AND s.event_type = $3 AND e.verified_at IS NOT NULL AND e.disabled_at IS NULL
+			RETURNING id, endpoint_id, subscription_id, ordering_key IS NOT NULL AS ordered
+		)
+		SELECT ins.id, ins.endpoint_id, e.url, s.include_fields, s.exclude_fields, ins.ordered
+		FROM ins
+		JOIN harborhook.endpoints e ON e.id = ins.endpoint_id
+		JOIN harborhook.subscriptions s ON s.id = ins.subscription_id`,
+		eventID, tenantID, eventType, string(payloadJSON))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []delivery.Task
+	for rows.Next() {
+		t := delivery.Task{
+			EventID:      eventID,
+			TenantID:     tenantID,
+			EventType:    eventType,
+			Payload:      taskPayload,
+			PayloadRef:   payloadRef,
+			TraceHeaders: traceHeaders,
+			Priority:     s.taskPriority(priority),
+		}
+		if err := rows.Scan(&t.DeliveryID, &t.EndpointID, &t.EndpointURL, &t.IncludeFields, &t.ExcludeFields, &t.Ordered); err != nil {
+			return nil, err
+		}
+		if deliverBy != nil {
+			t.SetDeadline(*deliverBy)
+		}
+		tasks = append(tasks, t)
+	}
+	return tasks, rows.Err()
+}
+
+// publishBatch sends the committed outbox rows and reports each event's fanout. Its deliveries
+// are durable once stored, so tasks NSQ rejects are left for the outbox relay rather than failed.
+func (s *Server) publishBatch(ctx context.Context, events []*batchEvent, outbox []outboxMessage, results []*webhookv1.PublishEventResult) {
+	s.sendOutbox(ctx, outbox)
+
+	var changes []changefeed.Change
+	for _, ev := range events {
+		if results[ev.index].Duplicate {
+			continue // keeps the original event's fanout
+		}
+		results[ev.index].FanoutCount = int32(len(ev.tasks))
+		for _, t := range ev.tasks {
+			changes = append(changes, changefeed.FromTask(t, "", "queued"))
+		}
+	}
+	s.feed.Publish(changes...)
+}
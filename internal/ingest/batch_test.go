@@ -0,0 +1,39 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestServer_PublishEvents_RejectsInvalidEvents(t *testing.T) {
+	payload, _ := structpb.NewStruct(map[string]any{"id": "1"})
+	server := &Server{}
+
+	resp, err := server.PublishEvents(context.Background(), &webhookv1.PublishEventsRequest{
+		TenantId: "tn_1",
+		Events: []*webhookv1.BatchEvent{
+			{Payload: payload},
+			{EventType: "user created", Payload: payload},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PublishEvents() unexpected error: %v", err)
+	}
+	if resp.FailedCount != 2 || resp.PublishedCount != 0 {
+		t.Errorf("counts = %d failed, %d published, want 2 and 0", resp.FailedCount, resp.PublishedCount)
+	}
+	want := []string{"event_type is required", "event_type must match ^[A-Za-z0-9][A-Za-z0-9._:-]*$"}
+	for i, r := range resp.Results {
+		if r.Index != int32(i) {
+			t.Errorf("result %d index = %d", i, r.Index)
+		}
+		if codes.Code(r.ErrorCode) != codes.InvalidArgument || r.Error != want[i] {
+			t.Errorf("result %d = %+v, want InvalidArgument: %s", i, r, want[i])
+		}
+	}
+}
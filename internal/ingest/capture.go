@@ -0,0 +1,81 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+const defaultCaptureListLimit = 50
+
+// endpointTypeColumn maps an API endpoint type to its endpoints.endpoint_type value
+func endpointTypeColumn(t webhookv1.EndpointType) string {
+	if t == webhookv1.EndpointType_ENDPOINT_TYPE_CAPTURE {
+		return delivery.EndpointCapture
+	}
+	return delivery.EndpointHTTP
+}
+
+// endpointTypeFromColumn maps an endpoints.endpoint_type value to the API
+func endpointTypeFromColumn(t string) webhookv1.EndpointType {
+	if t == delivery.EndpointCapture {
+		return webhookv1.EndpointType_ENDPOINT_TYPE_CAPTURE
+	}
+	return webhookv1.EndpointType_ENDPOINT_TYPE_HTTP
+}
+
+// GetCapturedDeliveries lists the requests a capture endpoint has received, newest first. The
+// worker keeps the newest delivery.CaptureLimit of them.
+func (s *Server) GetCapturedDeliveries(ctx context.Context, req *webhookv1.GetCapturedDeliveriesRequest) (*webhookv1.GetCapturedDeliveriesResponse, error) {
+	var endpointType string
+	err := s.pool.QueryRow(ctx, `
+		SELECT endpoint_type FROM harborhook.endpoints WHERE id = $1 AND tenant_id = $2
+	`, req.GetEndpointId(), req.GetTenantId()).Scan(&endpointType)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup endpoint: %w", err)
+	}
+	if endpointType != delivery.EndpointCapture {
+		return nil, apierr.FailedPrecondition("endpoint %s is not a capture endpoint", req.GetEndpointId())
+	}
+
+	limit := int32(defaultCaptureListLimit)
+	if req.GetLimit() > 0 {
+		limit = req.GetLimit()
+	}
+	rows, err := s.pool.Query(ctx, `
+		SELECT id::text, delivery_id::text, event_id::text, event_type, attempt, headers, body, signature, captured_at
+		FROM harborhook.captured_deliveries
+		WHERE endpoint_id = $1 AND ($2 = '' OR delivery_id::text = $2)
+		ORDER BY captured_at DESC
+		LIMIT $3
+	`, req.GetEndpointId(), req.GetDeliveryId(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list captures: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.GetCapturedDeliveriesResponse{}
+	for rows.Next() {
+		var (
+			c          webhookv1.CapturedDelivery
+			capturedAt time.Time
+		)
+		if err := rows.Scan(&c.Id, &c.DeliveryId, &c.EventId, &c.EventType, &c.Attempt, &c.Headers, &c.Body, &c.Signature, &capturedAt); err != nil {
+			return nil, err
+		}
+		c.CapturedAt = timestamppb.New(capturedAt)
+		resp.Captures = append(resp.Captures, &c)
+	}
+	return resp, rows.Err()
+}
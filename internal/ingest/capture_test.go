@@ -0,0 +1,62 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_GetCapturedDeliveries(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	endpointType := "capture"
+	var listArgs []any
+	server := NewServer(&dbfake.Pool{
+		QueryRowFunc: func(sql string, _ []any) pgx.Row {
+			if endpointType == "" {
+				return dbfake.Row{Err: pgx.ErrNoRows}
+			}
+			return dbfake.Row{Values: []any{endpointType}}
+		},
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			if !strings.Contains(sql, "FROM harborhook.captured_deliveries") {
+				return nil, fmt.Errorf("unexpected query: %s", sql)
+			}
+			listArgs = args
+			return dbfake.NewRows(
+				[]any{"cap_2", "del_1", "evt_1", "order.created", int32(2), map[string]string{"X-HarborHook-Signature": "sha256=abc"}, `{"id":1}`, "sha256=abc", at.Add(time.Minute)},
+				[]any{"cap_1", "del_1", "evt_1", "order.created", int32(1), map[string]string{}, `{"id":1}`, "sha256=abc", at},
+			), nil
+		},
+	}, nil)
+	ctx := context.Background()
+
+	resp, err := server.GetCapturedDeliveries(ctx, &webhookv1.GetCapturedDeliveriesRequest{TenantId: "tn_1", EndpointId: "ep_1", DeliveryId: "del_1"})
+	if err != nil {
+		t.Fatalf("GetCapturedDeliveries() unexpected error: %v", err)
+	}
+	if fmt.Sprint(listArgs) != fmt.Sprint([]any{"ep_1", "del_1", int32(defaultCaptureListLimit)}) {
+		t.Errorf("captures queried with %v, want the endpoint, delivery filter and default limit", listArgs)
+	}
+	if len(resp.Captures) != 2 || resp.Captures[0].Id != "cap_2" || resp.Captures[0].Attempt != 2 ||
+		resp.Captures[0].Headers["X-HarborHook-Signature"] != "sha256=abc" || !resp.Captures[1].CapturedAt.AsTime().Equal(at) {
+		t.Errorf("GetCapturedDeliveries() = %v, want both captures newest first", resp.Captures)
+	}
+
+	endpointType = "http"
+	if _, err := server.GetCapturedDeliveries(ctx, &webhookv1.GetCapturedDeliveriesRequest{TenantId: "tn_1", EndpointId: "ep_1"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("http endpoint: error = %v, want FailedPrecondition", err)
+	}
+	endpointType = ""
+	if _, err := server.GetCapturedDeliveries(ctx, &webhookv1.GetCapturedDeliveriesRequest{TenantId: "tn_1", EndpointId: "ep_1"}); status.Code(err) != codes.NotFound {
+		t.Errorf("missing endpoint: error = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,84 @@
+package ingest
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetEndpointClientCertificate sets the certificate the worker presents to an endpoint that
+// requires mutual TLS: an uploaded PEM pair or a secret mounted into the workers. An empty
+// request removes it.
+func (s *Server) SetEndpointClientCertificate(ctx context.Context, req *webhookv1.SetEndpointClientCertificateRequest) (*webhookv1.SetEndpointClientCertificateResponse, error) {
+	switch {
+	case req.GetSecretName() != "":
+		if req.GetCertPem() != "" || req.GetKeyPem() != "" {
+			return nil, apierr.Invalid("set either secret_name or cert_pem and key_pem, not both")
+		}
+		if err := delivery.ValidateClientCertSecretName(req.GetSecretName()); err != nil {
+			return nil, apierr.Invalid("%w", err)
+		}
+	case req.GetCertPem() != "" || req.GetKeyPem() != "":
+		_, leaf, err := delivery.ParseClientCertificate(req.GetCertPem(), req.GetKeyPem())
+		if err != nil {
+			return nil, apierr.Invalid("%w", err)
+		}
+		if time.Now().After(leaf.NotAfter) {
+			return nil, apierr.Invalid("client certificate expired at %s", leaf.NotAfter.Format(time.RFC3339))
+		}
+	}
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET client_cert_pem = NULLIF($3, ''), client_key_pem = NULLIF($4, ''), client_cert_secret = NULLIF($5, '')
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at`,
+		req.GetEndpointId(), req.GetTenantId(), req.GetCertPem(), req.GetKeyPem(), req.GetSecretName(),
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointClientCertificateResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:                req.GetEndpointId(),
+			TenantId:          req.GetTenantId(),
+			Url:               endpointURL,
+			CreatedAt:         timestamppb.New(createdAt),
+			ClientCertificate: describeClientCert(req.GetCertPem(), req.GetSecretName()),
+		},
+	}, nil
+}
+
+// describeClientCert describes an endpoint's client certificate from its stored columns, or
+// returns nil when it has none
+func describeClientCert(certPEM, secretName string) *webhookv1.ClientCertificate {
+	if secretName != "" {
+		return &webhookv1.ClientCertificate{SecretName: secretName}
+	}
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return nil
+	}
+	leaf, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil
+	}
+	return &webhookv1.ClientCertificate{Subject: leaf.Subject.String(), NotAfter: timestamppb.New(leaf.NotAfter)}
+}
@@ -0,0 +1,75 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_SetEndpointClientCertificate_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *webhookv1.SetEndpointClientCertificateRequest
+		wantErr string
+	}{
+		{
+			name:    "secret and upload",
+			req:     &webhookv1.SetEndpointClientCertificateRequest{TenantId: "tn_1", EndpointId: "ep_1", SecretName: "partner", CertPem: "x"},
+			wantErr: "not both",
+		},
+		{
+			name:    "path in secret name",
+			req:     &webhookv1.SetEndpointClientCertificateRequest{TenantId: "tn_1", EndpointId: "ep_1", SecretName: "../../etc"},
+			wantErr: "invalid secret name",
+		},
+		{
+			name:    "key without certificate",
+			req:     &webhookv1.SetEndpointClientCertificateRequest{TenantId: "tn_1", EndpointId: "ep_1", KeyPem: "x"},
+			wantErr: "cert_pem and key_pem are both required",
+		},
+		{
+			name:    "not pem",
+			req:     &webhookv1.SetEndpointClientCertificateRequest{TenantId: "tn_1", EndpointId: "ep_1", CertPem: "x", KeyPem: "y"},
+			wantErr: "invalid client certificate",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+			_, err := server.SetEndpointClientCertificate(context.Background(), tt.req)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SetEndpointClientCertificate() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestServer_SetEndpointClientCertificate_Secret(t *testing.T) {
+	var args []any
+	server := NewServer(&dbfake.Pool{
+		QueryRowFunc: func(_ string, a []any) pgx.Row {
+			args = a
+			return dbfake.Row{Values: []any{"https://partner.example/hook", time.Now()}}
+		},
+	}, nil)
+
+	resp, err := server.SetEndpointClientCertificate(context.Background(), &webhookv1.SetEndpointClientCertificateRequest{
+		TenantId: "tn_1", EndpointId: "ep_1", SecretName: "partner-mtls",
+	})
+	if err != nil {
+		t.Fatalf("SetEndpointClientCertificate() unexpected error: %v", err)
+	}
+	if got := resp.Endpoint.ClientCertificate.GetSecretName(); got != "partner-mtls" {
+		t.Errorf("client certificate secret = %q, want partner-mtls", got)
+	}
+	if args[2] != "" || args[3] != "" {
+		t.Errorf("uploaded pair = %q, %q, want it cleared when a secret is set", args[2], args[3])
+	}
+}
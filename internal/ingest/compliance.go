@@ -0,0 +1,126 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/compliance"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SetComplianceMode turns request recording on or off for a tenant
+func (s *Server) SetComplianceMode(ctx context.Context, req *webhookv1.SetComplianceModeRequest) (*webhookv1.SetComplianceModeResponse, error) {
+	retention := req.GetRetentionDays()
+	if retention == 0 {
+		retention = compliance.DefaultRetentionDays
+	}
+
+	var updatedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.tenant_compliance(tenant_id, record_requests, retention_days)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET record_requests = EXCLUDED.record_requests, retention_days = EXCLUDED.retention_days, updated_at = now()
+		RETURNING updated_at
+	`, req.GetTenantId(), req.GetRecordRequests(), retention).Scan(&updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("save compliance settings: %w", err)
+	}
+
+	return &webhookv1.SetComplianceModeResponse{
+		Settings: &webhookv1.ComplianceSettings{
+			TenantId:       req.GetTenantId(),
+			RecordRequests: req.GetRecordRequests(),
+			RetentionDays:  retention,
+			UpdatedAt:      timestamppb.New(updatedAt),
+		},
+	}, nil
+}
+
+// ListDeliveryRecordings decrypts the recorded requests for a delivery.
+// The access is written to the audit log before anything is read, so a failed audit blocks the read.
+func (s *Server) ListDeliveryRecordings(ctx context.Context, req *webhookv1.ListDeliveryRecordingsRequest) (*webhookv1.ListDeliveryRecordingsResponse, error) {
+	if s.recordings == nil {
+		return nil, apierr.FailedPrecondition("request recording is not configured")
+	}
+
+	// The delivery must belong to the tenant
+	var found int
+	err := s.pool.QueryRow(ctx, `
+		SELECT 1
+		FROM harborhook.deliveries d
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		WHERE d.id = $1 AND ep.tenant_id = $2
+	`, req.GetDeliveryId(), req.GetTenantId()).Scan(&found)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("delivery %s not found", req.GetDeliveryId())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup delivery: %w", err)
+	}
+
+	actor, ok := auth.GetTenantIDFromContext(ctx)
+	if !ok || actor == "" {
+		actor = "unauthenticated"
+	}
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO harborhook.recording_access_log(tenant_id, delivery_id, actor, reason)
+		VALUES ($1, $2, $3, $4)
+	`, req.GetTenantId(), req.GetDeliveryId(), actor, req.GetReason()); err != nil {
+		return nil, fmt.Errorf("write access audit log: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT id, attempt, key_id, sealed, recorded_at, expires_at
+		FROM harborhook.delivery_recordings
+		WHERE delivery_id = $1 AND expires_at > now()
+		ORDER BY attempt, recorded_at
+	`, req.GetDeliveryId())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*webhookv1.DeliveryRecording
+	for rows.Next() {
+		var (
+			id, keyID             string
+			attempt               int32
+			sealed                []byte
+			recordedAt, expiresAt time.Time
+		)
+		if err := rows.Scan(&id, &attempt, &keyID, &sealed, &recordedAt, &expiresAt); err != nil {
+			return nil, err
+		}
+		if keyID != s.recordings.KeyID() {
+			return nil, apierr.FailedPrecondition("recording %s was sealed with key %s, current key is %s", id, keyID, s.recordings.KeyID())
+		}
+		r, err := s.recordings.Open(sealed)
+		if err != nil {
+			return nil, fmt.Errorf("recording %s: %w", id, err)
+		}
+		out = append(out, &webhookv1.DeliveryRecording{
+			Id:         id,
+			DeliveryId: req.GetDeliveryId(),
+			Attempt:    attempt,
+			Method:     r.Method,
+			Url:        r.URL,
+			Headers:    r.Headers,
+			Body:       string(r.Body),
+			RecordedAt: timestamppb.New(recordedAt),
+			ExpiresAt:  timestamppb.New(expiresAt),
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &webhookv1.ListDeliveryRecordingsResponse{Recordings: out}, nil
+}
@@ -0,0 +1,36 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_ListDeliveryRecordings_Validation(t *testing.T) {
+	tests := []struct {
+		name     string
+		request  *webhookv1.ListDeliveryRecordingsRequest
+		errorMsg string
+	}{
+		{
+			name:     "recording not configured",
+			request:  &webhookv1.ListDeliveryRecordingsRequest{TenantId: "tn_1", DeliveryId: "123e4567-e89b-12d3-a456-426614174000", Reason: "audit"},
+			errorMsg: "request recording is not configured",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+
+			_, err := server.ListDeliveryRecordings(context.Background(), tt.request)
+			if err == nil {
+				t.Fatal("ListDeliveryRecordings() expected error but got none")
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("ListDeliveryRecordings() error = %q, want %q", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultRecentDeliveries = 50
+	maxRecentDeliveries     = 500
+)
+
+// dbStatus is the inverse of mapStatus; UNSPECIFIED maps to "" (no filter)
+func dbStatus(st webhookv1.DeliveryAttemptStatus) string {
+	switch st {
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_QUEUED:
+		return "queued"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_IN_FLIGHT:
+		return "inflight"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED:
+		return "delivered"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_FAILED:
+		return "failed"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED:
+		return "dead"
+	case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_PARKED:
+		return "parked"
+	default:
+		return ""
+	}
+}
+
+// ListTenants lists every tenant known from the tenants table or from its endpoints, with
+// the counts the admin console shows. Only the admin tenant may list tenants.
+func (s *Server) ListTenants(ctx context.Context, _ *webhookv1.ListTenantsRequest) (*webhookv1.ListTenantsResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		WITH ids AS (
+			SELECT id AS tenant_id FROM harborhook.tenants
+			UNION
+			SELECT DISTINCT tenant_id FROM harborhook.endpoints
+		)
+		SELECT ids.tenant_id, COALESCE(t.name, ''),
+		       (SELECT count(*) FROM harborhook.endpoints ep WHERE ep.tenant_id = ids.tenant_id),
+		       (SELECT count(*) FROM harborhook.deliveries d
+		        JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		        WHERE ep.tenant_id = ids.tenant_id AND d.created_at >= now() - interval '24 hours'),
+		       (SELECT count(*) FROM harborhook.dlq q
+		        JOIN harborhook.deliveries d ON d.id = q.delivery_id
+		        JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		        WHERE ep.tenant_id = ids.tenant_id)
+		FROM ids
+		LEFT JOIN harborhook.tenants t ON t.id = ids.tenant_id
+		ORDER BY ids.tenant_id`)
+	if err != nil {
+		return nil, fmt.Errorf("list tenants: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.ListTenantsResponse{}
+	for rows.Next() {
+		var t webhookv1.TenantSummary
+		if err := rows.Scan(&t.TenantId, &t.Name, &t.EndpointCount, &t.RecentDeliveries, &t.DeadLettered); err != nil {
+			return nil, err
+		}
+		resp.Tenants = append(resp.Tenants, &t)
+	}
+	return resp, rows.Err()
+}
+
+// ListEndpoints lists a tenant's endpoints, newest first. Only the admin tenant may list them.
+func (s *Server) ListEndpoints(ctx context.Context, req *webhookv1.ListEndpointsRequest) (*webhookv1.ListEndpointsResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT e.id::text, e.url, e.created_at, e.recovery_ramp_percents, e.recovery_ramp_step_seconds,
+		       e.retry_max_attempts, e.retry_backoff_seconds, e.retry_on, e.verified_at,
+		       COALESCE(e.client_cert_pem, ''), COALESCE(e.client_cert_secret, ''), e.compression, e.ordered, e.partition_key,
+		       e.signature_scheme, COALESCE(e.timeout_ms, 0), e.endpoint_type, COALESCE(e.proxy_url, ''),
+		       e.health_check_enabled, e.health_check_method, e.disabled_at, COALESCE(e.disabled_reason, ''),
+		       `+endpointHealthColumns+`
+		FROM harborhook.endpoints e
+		LEFT JOIN harborhook.endpoint_health h ON h.endpoint_id = e.id
+		WHERE e.tenant_id = $1
+		ORDER BY e.created_at DESC`, req.GetTenant())
+	if err != nil {
+		return nil, fmt.Errorf("list endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.ListEndpointsResponse{}
+	for rows.Next() {
+		var (
+			ep = &webhookv1.Endpoint{
+				TenantId:     req.GetTenant(),
+				RecoveryRamp: &webhookv1.RecoveryRamp{},
+				RetryPolicy:  &webhookv1.RetryPolicy{},
+			}
+			createdAt              time.Time
+			verifiedAt             sql.NullTime
+			clientCert, certSecret string
+			compression            string
+			ordered                bool
+			partitionKey           string
+			signatureScheme        string
+			endpointType           string
+			proxyURL               string
+			healthCheck            bool
+			healthCheckMethod      string
+			disabledAt             sql.NullTime
+			health                 endpointHealthRow
+		)
+		dest := append([]any{&ep.Id, &ep.Url, &createdAt, &ep.RecoveryRamp.Percents, &ep.RecoveryRamp.StepSeconds,
+			&ep.RetryPolicy.MaxAttempts, &ep.RetryPolicy.BackoffSeconds, &ep.RetryPolicy.RetryOn, &verifiedAt,
+			&clientCert, &certSecret, &compression, &ordered, &partitionKey, &signatureScheme, &ep.TimeoutMs, &endpointType, &proxyURL,
+			&healthCheck, &healthCheckMethod, &disabledAt, &ep.DisabledReason}, health.dest()...)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		ep.CreatedAt = timestamppb.New(createdAt)
+		ep.VerifiedAt = toTS(verifiedAt)
+		ep.ClientCertificate = describeClientCert(clientCert, certSecret)
+		ep.Compression = compressionFromColumn(compression)
+		ep.Ordering = describeOrdering(ordered, partitionKey)
+		ep.SignatureScheme = signatureSchemeFromColumn(signatureScheme)
+		ep.Type = endpointTypeFromColumn(endpointType)
+		ep.ProxyUrl = redactProxyURL(proxyURL)
+		ep.HealthCheck = describeHealthCheck(healthCheck, healthCheckMethod)
+		ep.DisabledAt = toTS(disabledAt)
+		if healthCheck {
+			ep.Health = health.describe()
+		}
+		resp.Endpoints = append(resp.Endpoints, ep)
+	}
+	return resp, rows.Err()
+}
+
+// ListRecentDeliveries lists the newest deliveries across tenants, optionally filtered by
+// tenant, endpoint, or status. Only the admin tenant may list them.
+func (s *Server) ListRecentDeliveries(ctx context.Context, req *webhookv1.ListRecentDeliveriesRequest) (*webhookv1.ListRecentDeliveriesResponse, error) {
+	limit := int32(defaultRecentDeliveries)
+	if req.GetLimit() > 0 {
+		limit = min(req.GetLimit(), maxRecentDeliveries)
+	}
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(ctx, `
+		SELECT d.id, d.event_id, d.endpoint_id, d.replay_of, d.status, d.http_status,
+		       COALESCE(d.error_reason, d.last_error),
+		       d.enqueued_at, d.dequeued_at, d.sent_at, d.delivered_at, d.failed_at, d.dlq_at,
+		       ep.tenant_id, ev.event_type
+		FROM harborhook.deliveries d
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		JOIN harborhook.events ev ON ev.id = d.event_id
+		WHERE (NULLIF($1, '') IS NULL OR ep.tenant_id = $1)
+		  AND (NULLIF($2, '') IS NULL OR d.endpoint_id = NULLIF($2, '')::uuid)
+		  AND (NULLIF($3, '') IS NULL OR d.status::text = $3)
+		ORDER BY d.created_at DESC
+		LIMIT $4`,
+		req.GetTenant(), req.GetEndpointId(), dbStatus(req.GetStatus()), limit)
+	if err != nil {
+		return nil, fmt.Errorf("list deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.ListRecentDeliveriesResponse{}
+	for rows.Next() {
+		var (
+			id, eventID, endpointID          string
+			replayOf, statusStr, errReason   sql.NullString
+			httpStatus                       sql.NullInt32
+			enq, deq, sent, deliv, fail, dlq sql.NullTime
+			rd                               webhookv1.RecentDelivery
+		)
+		if err := rows.Scan(&id, &eventID, &endpointID, &replayOf, &statusStr, &httpStatus, &errReason,
+			&enq, &deq, &sent, &deliv, &fail, &dlq, &rd.TenantId, &rd.EventType,
+		); err != nil {
+			return nil, err
+		}
+		rd.Delivery = &webhookv1.DeliveryAttempt{
+			DeliveryId:  id,
+			EventId:     eventID,
+			EndpointId:  endpointID,
+			ReplayOf:    nullStr(replayOf),
+			Status:      mapStatus(nullStr(statusStr)),
+			HttpStatus:  nullI32(httpStatus),
+			ErrorReason: nullStr(errReason),
+			EnqueuedAt:  toTS(enq),
+			DequeuedAt:  toTS(deq),
+			SentAt:      toTS(sent),
+			DeliveredAt: toTS(deliv),
+			FailedAt:    toTS(fail),
+			DlqAt:       toTS(dlq),
+		}
+		resp.Deliveries = append(resp.Deliveries, &rd)
+	}
+	return resp, rows.Err()
+}
@@ -0,0 +1,36 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestDBStatus_RoundTrip(t *testing.T) {
+	for v := range webhookv1.DeliveryAttemptStatus_name {
+		st := webhookv1.DeliveryAttemptStatus(v)
+		if got := mapStatus(dbStatus(st)); got != st {
+			t.Errorf("mapStatus(dbStatus(%v)) = %v", st, got)
+		}
+	}
+}
+
+func TestServer_Console_RequireAdmin(t *testing.T) {
+	server := &Server{}
+	server.SetAdminTenant("ops")
+	ctx := context.WithValue(context.Background(), auth.TenantIDKey, "tn_1")
+
+	if _, err := server.ListTenants(ctx, &webhookv1.ListTenantsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListTenants() = %v, want PermissionDenied", err)
+	}
+	if _, err := server.ListEndpoints(ctx, &webhookv1.ListEndpointsRequest{Tenant: "tn_2"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListEndpoints() = %v, want PermissionDenied", err)
+	}
+	if _, err := server.ListRecentDeliveries(ctx, &webhookv1.ListRecentDeliveriesRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListRecentDeliveries() = %v, want PermissionDenied", err)
+	}
+}
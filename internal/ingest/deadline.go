@@ -0,0 +1,40 @@
+package ingest
+
+import (
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// deliveryDeadline resolves a publish's deliver_by or ttl to an absolute deadline. It returns
+// nil, stored as NULL, when the event has neither and its deliveries retry for as long as
+// the retry policy allows.
+func deliveryDeadline(deliverBy *timestamppb.Timestamp, ttl *durationpb.Duration, now time.Time) (*time.Time, error) {
+	switch {
+	case deliverBy != nil && ttl != nil:
+		return nil, status.Error(codes.InvalidArgument, "set at most one of deliver_by and ttl")
+	case deliverBy != nil:
+		if err := deliverBy.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid deliver_by: %v", err)
+		}
+		at := deliverBy.AsTime()
+		if !at.After(now) {
+			return nil, status.Error(codes.InvalidArgument, "deliver_by must be in the future")
+		}
+		return &at, nil
+	case ttl != nil:
+		if err := ttl.CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ttl: %v", err)
+		}
+		d := ttl.AsDuration()
+		if d <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "ttl must be positive")
+		}
+		at := now.Add(d).UTC()
+		return &at, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,115 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestDeliveryDeadline(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		deliverBy *timestamppb.Timestamp
+		ttl       *durationpb.Duration
+		want      time.Time
+		wantErr   string
+	}{
+		{name: "neither"},
+		{name: "deliver_by", deliverBy: timestamppb.New(now.Add(time.Hour)), want: now.Add(time.Hour)},
+		{name: "ttl", ttl: durationpb.New(5 * time.Minute), want: now.Add(5 * time.Minute)},
+		{name: "both", deliverBy: timestamppb.New(now.Add(time.Hour)), ttl: durationpb.New(time.Minute), wantErr: "set at most one of deliver_by and ttl"},
+		{name: "deliver_by in the past", deliverBy: timestamppb.New(now.Add(-time.Second)), wantErr: "deliver_by must be in the future"},
+		{name: "zero ttl", ttl: durationpb.New(0), wantErr: "ttl must be positive"},
+		{name: "negative ttl", ttl: durationpb.New(-time.Minute), wantErr: "ttl must be positive"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := deliveryDeadline(tt.deliverBy, tt.ttl, now)
+			if tt.wantErr != "" {
+				if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("deliveryDeadline() error = %v, want InvalidArgument %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("deliveryDeadline() unexpected error: %v", err)
+			}
+			if tt.want.IsZero() != (got == nil) || (got != nil && !got.Equal(tt.want)) {
+				t.Errorf("deliveryDeadline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_PublishEvent_Deadline(t *testing.T) {
+	pool := fanoutPool(2)
+	var stored any
+	fanout := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "INSERT INTO harborhook.events") {
+			stored = args[3]
+		}
+		return fanout(sql, args)
+	}
+	prod := &recordingPublisher{}
+	server := NewServer(pool, prod)
+
+	deadline := time.Now().Add(10 * time.Minute).UTC()
+	payload, _ := structpb.NewStruct(map[string]any{"order_id": "ord_1"})
+	if _, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{
+		TenantId: "tn_1", EventType: "order.created", Payload: payload, DeliverBy: timestamppb.New(deadline),
+	}); err != nil {
+		t.Fatalf("PublishEvent() unexpected error: %v", err)
+	}
+
+	if at, ok := stored.(*time.Time); !ok || !at.Equal(deadline) {
+		t.Errorf("stored deliver_by = %v, want %v", stored, deadline)
+	}
+	if len(prod.bodies) != 2 {
+		t.Fatalf("published %d tasks, want 2", len(prod.bodies))
+	}
+	for _, b := range prod.bodies {
+		var task delivery.Task
+		_ = json.Unmarshal(b, &task)
+		if task.Expired(deadline.Add(-time.Second)) || !task.Expired(deadline) {
+			t.Errorf("task %s deliver_by = %q, want %s", task.DeliveryID, task.DeliverBy, deadline.Format(time.RFC3339Nano))
+		}
+	}
+
+	_, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{
+		TenantId: "tn_1", EventType: "order.created", Payload: payload, Ttl: durationpb.New(-time.Minute),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("PublishEvent(negative ttl) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestServer_PublishEvents_RejectsBadDeadline(t *testing.T) {
+	payload, _ := structpb.NewStruct(map[string]any{"id": "1"})
+	resp, err := (&Server{}).PublishEvents(context.Background(), &webhookv1.PublishEventsRequest{
+		TenantId: "tn_1",
+		Events: []*webhookv1.BatchEvent{{
+			EventType: "user.created", Payload: payload,
+			DeliverBy: timestamppb.Now(), Ttl: durationpb.New(time.Minute),
+		}},
+	})
+	if err != nil {
+		t.Fatalf("PublishEvents() unexpected error: %v", err)
+	}
+	if r := resp.Results[0]; codes.Code(r.ErrorCode) != codes.InvalidArgument || r.Error != "set at most one of deliver_by and ttl" {
+		t.Errorf("result = %+v, want InvalidArgument for both deadlines", r)
+	}
+}
@@ -0,0 +1,181 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// autoDisableActor is who the audit log names for endpoints ingest disables itself
+const autoDisableActor = "harborhook"
+
+// failureStatsQuery counts each enabled endpoint's deliveries that finished in the last $1
+// seconds, and since it was last enabled: how many were dead-lettered since the last one
+// delivered, how many finished and how many failed, and whether the window saw it enabled
+// throughout
+const failureStatsQuery = `
+	SELECT e.id::text, e.tenant_id,
+	       count(*) FILTER (WHERE d.status = 'dead' AND d.updated_at > COALESCE(ok.at, '-infinity')),
+	       count(*),
+	       count(*) FILTER (WHERE d.status IN ('dead', 'failed')),
+	       COALESCE(e.enabled_at, e.created_at) <= now() - make_interval(secs => $1)
+	FROM harborhook.endpoints e
+	JOIN harborhook.deliveries d ON d.endpoint_id = e.id
+	LEFT JOIN LATERAL (
+		SELECT max(updated_at) AS at FROM harborhook.deliveries
+		WHERE endpoint_id = e.id AND status = 'delivered' AND updated_at >= now() - make_interval(secs => $1)
+	) ok ON true
+	WHERE e.disabled_at IS NULL AND e.endpoint_type = 'http'
+	  AND d.status IN ('delivered', 'dead', 'failed')
+	  AND d.updated_at >= now() - make_interval(secs => $1)
+	  AND d.updated_at > COALESCE(e.enabled_at, '-infinity')
+	GROUP BY e.id, e.tenant_id, e.enabled_at, e.created_at, ok.at`
+
+// DisableFailingEndpoints disables every endpoint whose deliveries over window meet p, as
+// GitHub and Stripe do with dead hooks: it gets no new deliveries and its queued ones fail
+// until EnableEndpoint. Each endpoint disabled is recorded in the audit log and as a system
+// event for its tenant. It returns how many endpoints were disabled; replicas may run it
+// concurrently, and only one of them disables a given endpoint.
+func (s *Server) DisableFailingEndpoints(ctx context.Context, p delivery.DisablePolicy, window time.Duration) (int, error) {
+	rows, err := s.pool.Query(ctx, failureStatsQuery, window.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("count endpoint failures: %w", err)
+	}
+	type failing struct {
+		tenantID, endpointID, reason string
+		stats                        delivery.FailureStats
+	}
+	var found []failing
+	for rows.Next() {
+		var f failing
+		if err := rows.Scan(&f.endpointID, &f.tenantID, &f.stats.DeadStreak, &f.stats.Finished, &f.stats.Failed, &f.stats.Observed); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if f.reason = p.Reason(f.stats); f.reason != "" {
+			found = append(found, f)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	disabled := 0
+	for _, f := range found {
+		ok, err := s.disableEndpoint(ctx, f.tenantID, f.endpointID, f.reason, f.stats, window)
+		if err != nil {
+			return disabled, err
+		}
+		if ok {
+			disabled++
+		}
+	}
+	return disabled, nil
+}
+
+// disableEndpoint disables an endpoint for reason and records it in the audit log and as a
+// system event. It reports false when the endpoint was already disabled.
+func (s *Server) disableEndpoint(ctx context.Context, tenantID, endpointID, reason string, stats delivery.FailureStats, window time.Duration) (bool, error) {
+	entry := auditEntry{
+		tenantID:      tenantID,
+		action:        "endpoint.auto_disable",
+		resourceType:  "endpoint",
+		resourceID:    endpointID,
+		actorTenantID: tenantID,
+		actorSubject:  autoDisableActor,
+		actorRole:     "system",
+	}
+	_, entry.before = s.snapshot(ctx, endpointSnapshotSQL, endpointID)
+
+	tag, err := s.pool.Exec(ctx, `
+		UPDATE harborhook.endpoints
+		SET disabled_at = now(), disabled_reason = $2
+		WHERE id = $1 AND disabled_at IS NULL`, endpointID, reason)
+	if err != nil {
+		return false, fmt.Errorf("disable endpoint %s: %w", endpointID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+	metrics.EndpointsDisabledTotal.WithLabelValues(reason).Inc()
+
+	// The endpoint is disabled whether or not these are written; like the audit interceptor,
+	// a failed audit write is counted rather than returned
+	_, entry.after = s.snapshot(ctx, endpointSnapshotSQL, endpointID)
+	if err := s.writeAudit(ctx, entry); err != nil {
+		metrics.RecordAuditWriteFailure(entry.action)
+	}
+
+	details, err := json.Marshal(map[string]any{
+		"reason":         reason,
+		"dead_streak":    stats.DeadStreak,
+		"finished":       stats.Finished,
+		"failed":         stats.Failed,
+		"failure_rate":   stats.Rate(),
+		"window_seconds": window.Seconds(),
+	})
+	if err != nil {
+		return true, err
+	}
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO harborhook.system_events(tenant_id, endpoint_id, type, message, details)
+		VALUES ($1, $2, $3, $4, $5)`,
+		tenantID, endpointID, delivery.SystemEventEndpointDisabled, disabledMessage(reason, stats, window), details); err != nil {
+		return true, fmt.Errorf("record endpoint disabled: %w", err)
+	}
+	metrics.RecordSystemEvent(delivery.SystemEventEndpointDisabled)
+	return true, nil
+}
+
+// disabledMessage tells the tenant why an endpoint was disabled and how to turn it back on
+func disabledMessage(reason string, stats delivery.FailureStats, window time.Duration) string {
+	why := fmt.Sprintf("its last %d deliveries were dead-lettered", stats.DeadStreak)
+	if reason == delivery.DisabledFailureRate {
+		why = fmt.Sprintf("%.0f%% of its %d deliveries failed over the last %s", stats.Rate()*100, stats.Finished, window)
+	}
+	return "Endpoint disabled because " + why + "; fix the receiver, then enable the endpoint to resume deliveries"
+}
+
+// EnableEndpoint enables an endpoint that was disabled for failing. Deliveries resume through
+// its recovery ramp, and its failures are counted afresh from now.
+func (s *Server) EnableEndpoint(ctx context.Context, req *webhookv1.EnableEndpointRequest) (*webhookv1.EnableEndpointResponse, error) {
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET enabled_at = CASE WHEN disabled_at IS NOT NULL THEN now() ELSE enabled_at END,
+		    recovered_at = CASE WHEN disabled_at IS NOT NULL THEN now() ELSE recovered_at END,
+		    disabled_at = NULL, disabled_reason = NULL
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at`,
+		req.GetEndpointId(), req.GetTenantId(),
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.EnableEndpointResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:        req.GetEndpointId(),
+			TenantId:  req.GetTenantId(),
+			Url:       endpointURL,
+			CreatedAt: timestamppb.New(createdAt),
+		},
+	}, nil
+}
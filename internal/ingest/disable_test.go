@@ -0,0 +1,70 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+)
+
+func TestServer_DisableFailingEndpoints(t *testing.T) {
+	var (
+		disabled []string
+		audited  []any
+		events   []any
+	)
+	server := NewServer(&dbfake.Pool{
+		QueryFunc: func(string, []any) (pgx.Rows, error) {
+			return dbfake.NewRows(
+				[]any{"ep_dead", "tn_1", 30, 30, 30, true},
+				[]any{"ep_ok", "tn_1", 0, 100, 2, true},
+				[]any{"ep_raced", "tn_1", 0, 50, 50, true}, // another replica disables it first
+			), nil
+		},
+		QueryRowFunc: func(string, []any) pgx.Row {
+			return dbfake.Row{Values: []any{"tn_1", []byte(`{"id":"ep"}`)}}
+		},
+		ExecFunc: func(sql string, args []any) (pgconn.CommandTag, error) {
+			switch {
+			case strings.Contains(sql, "SET disabled_at = now()"):
+				if args[0] == "ep_raced" {
+					return pgconn.NewCommandTag("UPDATE 0"), nil
+				}
+				disabled = append(disabled, args[0].(string)+":"+args[1].(string))
+			case strings.Contains(sql, "INSERT INTO harborhook.audit_log"):
+				audited = args
+			case strings.Contains(sql, "INSERT INTO harborhook.system_events"):
+				events = args
+			}
+			return pgconn.NewCommandTag("UPDATE 1"), nil
+		},
+	}, nil)
+
+	p := delivery.DisablePolicy{DeadStreak: 25, FailureRate: 0.95, MinDeliveries: 20}
+	n, err := server.DisableFailingEndpoints(context.Background(), p, 72*time.Hour)
+	if err != nil {
+		t.Fatalf("DisableFailingEndpoints() unexpected error: %v", err)
+	}
+	if n != 1 || len(disabled) != 1 || disabled[0] != "ep_dead:"+delivery.DisabledDeadStreak {
+		t.Fatalf("disabled %d endpoints (%v), want ep_dead for its dead streak", n, disabled)
+	}
+	if audited == nil || audited[1] != "endpoint.auto_disable" || audited[3] != "ep_dead" || audited[5] != autoDisableActor {
+		t.Errorf("audit entry %v, want endpoint.auto_disable of ep_dead by %s", audited, autoDisableActor)
+	}
+	if events == nil || events[1] != "ep_dead" || events[2] != delivery.SystemEventEndpointDisabled {
+		t.Errorf("system event %v, want %s for ep_dead", events, delivery.SystemEventEndpointDisabled)
+	}
+}
+
+func TestDisabledMessage(t *testing.T) {
+	got := disabledMessage(delivery.DisabledFailureRate, delivery.FailureStats{Finished: 40, Failed: 39}, 72*time.Hour)
+	if want := "Endpoint disabled because 98% of its 40 deliveries failed over the last 72h0m0s; "; !strings.HasPrefix(got, want) {
+		t.Errorf("disabledMessage() = %q, want it to start %q", got, want)
+	}
+}
@@ -0,0 +1,117 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const defaultRampStartPercent = 10
+
+// dispatchStateColumns are scanned by scanDispatchState
+const dispatchStateColumns = `paused, COALESCE(reason, ''), paused_at, resumed_at, ramp_seconds, ramp_start_percent`
+
+// requireAdmin allows cluster-wide controls (kill switch, tenant quotas) only for admins when the
+// caller is authenticated. Callers without a role (contexts not built by the auth interceptors)
+// must be the admin tenant.
+func (s *Server) requireAdmin(ctx context.Context) error {
+	claim, ok := auth.GetTenantIDFromContext(ctx)
+	if !ok || claim == "" {
+		return nil
+	}
+	if role, ok := auth.GetRoleFromContext(ctx); ok {
+		if !role.Includes(auth.RoleAdmin) {
+			return status.Error(codes.PermissionDenied, "admin controls require the admin role")
+		}
+		return nil
+	}
+	if s.adminTenant == "" || claim != s.adminTenant {
+		return status.Error(codes.PermissionDenied, "admin controls require the admin tenant")
+	}
+	return nil
+}
+
+// PauseDispatch flips the kill switch. Workers hold every task until ResumeDispatch.
+func (s *Server) PauseDispatch(ctx context.Context, req *webhookv1.PauseDispatchRequest) (*webhookv1.PauseDispatchResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	st, err := scanDispatchState(s.pool.QueryRow(ctx, `
+		UPDATE harborhook.dispatch_control
+		SET paused = true, reason = $1, paused_at = now(), updated_at = now()
+		RETURNING `+dispatchStateColumns, req.GetReason()))
+	if err != nil {
+		return nil, fmt.Errorf("pause dispatch: %w", err)
+	}
+
+	tracing.AddSpanEvent(ctx, "admin.dispatch_paused")
+	return &webhookv1.PauseDispatchResponse{State: st}, nil
+}
+
+// ResumeDispatch releases the kill switch, ramping traffic from start_percent to 100% over ramp_seconds
+func (s *Server) ResumeDispatch(ctx context.Context, req *webhookv1.ResumeDispatchRequest) (*webhookv1.ResumeDispatchResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	start := req.GetStartPercent()
+	if start == 0 {
+		start = defaultRampStartPercent
+	}
+
+	st, err := scanDispatchState(s.pool.QueryRow(ctx, `
+		UPDATE harborhook.dispatch_control
+		SET paused = false, resumed_at = now(), ramp_seconds = $1, ramp_start_percent = $2, updated_at = now()
+		RETURNING `+dispatchStateColumns, req.GetRampSeconds(), start))
+	if err != nil {
+		return nil, fmt.Errorf("resume dispatch: %w", err)
+	}
+
+	tracing.AddSpanEvent(ctx, "admin.dispatch_resumed")
+	return &webhookv1.ResumeDispatchResponse{State: st}, nil
+}
+
+// GetDispatchState returns the kill switch state and the share of traffic currently admitted
+func (s *Server) GetDispatchState(ctx context.Context, _ *webhookv1.GetDispatchStateRequest) (*webhookv1.GetDispatchStateResponse, error) {
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	st, err := scanDispatchState(s.pool.QueryRow(ctx, `SELECT `+dispatchStateColumns+` FROM harborhook.dispatch_control`))
+	if err != nil {
+		return nil, fmt.Errorf("read dispatch state: %w", err)
+	}
+	return &webhookv1.GetDispatchStateResponse{State: st}, nil
+}
+
+func scanDispatchState(row interface{ Scan(...any) error }) (*webhookv1.DispatchState, error) {
+	var (
+		st                  delivery.DispatchState
+		pausedAt, resumedAt sql.NullTime
+		rampSeconds, start  int32
+	)
+	if err := row.Scan(&st.Paused, &st.Reason, &pausedAt, &resumedAt, &rampSeconds, &start); err != nil {
+		return nil, err
+	}
+	st.ResumedAt = resumedAt.Time
+	st.Ramp = time.Duration(rampSeconds) * time.Second
+	st.StartPercent = int(start)
+
+	return &webhookv1.DispatchState{
+		Paused:           st.Paused,
+		Reason:           st.Reason,
+		PausedAt:         toTS(pausedAt),
+		ResumedAt:        toTS(resumedAt),
+		RampSeconds:      rampSeconds,
+		RampStartPercent: start,
+		AdmitPercent:     st.AdmitPercent(time.Now()),
+	}, nil
+}
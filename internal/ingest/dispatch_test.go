@@ -0,0 +1,60 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_RequireAdmin(t *testing.T) {
+	tests := []struct {
+		name        string
+		adminTenant string
+		claim       string
+		wantDenied  bool
+	}{
+		{name: "unauthenticated", adminTenant: "ops"},
+		{name: "admin tenant", adminTenant: "ops", claim: "ops"},
+		{name: "other tenant", adminTenant: "ops", claim: "tn_1", wantDenied: true},
+		{name: "no admin tenant configured", claim: "tn_1", wantDenied: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+			server.SetAdminTenant(tt.adminTenant)
+			ctx := context.Background()
+			if tt.claim != "" {
+				ctx = context.WithValue(ctx, auth.TenantIDKey, tt.claim)
+			}
+
+			err := server.requireAdmin(ctx)
+			if tt.wantDenied {
+				if status.Code(err) != codes.PermissionDenied {
+					t.Errorf("requireAdmin() = %v, want PermissionDenied", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("requireAdmin() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestServer_RequireAdmin_Roles(t *testing.T) {
+	// Roles from the auth interceptor decide, whatever the tenant
+	server := &Server{}
+	server.SetAdminTenant("ops")
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "tn_1", Role: auth.RoleAdmin})
+	if err := server.requireAdmin(admin); err != nil {
+		t.Errorf("requireAdmin(admin role) unexpected error: %v", err)
+	}
+	operator := auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "ops", Role: auth.RoleOperator})
+	if err := server.requireAdmin(operator); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("requireAdmin(operator of admin tenant) = %v, want PermissionDenied", err)
+	}
+}
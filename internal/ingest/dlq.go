@@ -0,0 +1,198 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/store"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GetDLQEntry returns a dead-lettered delivery with its attempt count and last error, plus every
+// delivery in its replay chain so earlier failures and later replays can be inspected together.
+func (s *Server) GetDLQEntry(ctx context.Context, req *webhookv1.GetDLQEntryRequest) (*webhookv1.GetDLQEntryResponse, error) {
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		eventID, owner, eventType string
+		inDLQ                     bool
+	)
+	err = s.pool.QueryRow(ctx, `
+		SELECT d.event_id, ep.tenant_id, ev.event_type,
+		       EXISTS (SELECT 1 FROM harborhook.dlq q WHERE q.delivery_id = d.id)
+		FROM harborhook.deliveries d
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		JOIN harborhook.events ev ON ev.id = d.event_id
+		WHERE d.id = $1
+	`, req.GetDeliveryId()).Scan(&eventID, &owner, &eventType, &inDLQ)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && tenantID != "" && owner != tenantID) {
+		return nil, apierr.NotFound("delivery %s not found", req.GetDeliveryId())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup delivery: %w", err)
+	}
+	if !inDLQ {
+		return nil, apierr.FailedPrecondition("delivery %s is not in the dead letter queue", req.GetDeliveryId())
+	}
+
+	// Replays keep the source's event_id, so the whole chain lives within the event
+	rows, err := s.pool.Query(ctx, `
+		WITH RECURSIVE lineage AS (
+			SELECT id, id AS root_id, 0 AS depth
+			FROM harborhook.deliveries
+			WHERE event_id = $1 AND replay_of IS NULL
+			UNION ALL
+			SELECT c.id, l.root_id, l.depth + 1
+			FROM harborhook.deliveries c
+			JOIN lineage l ON c.replay_of = l.id
+		)
+		SELECT d.id, d.event_id, d.endpoint_id, d.replay_of, d.status, d.http_status,
+		       COALESCE(d.error_reason, d.last_error) AS err,
+		       d.enqueued_at, d.dequeued_at, d.sent_at, d.delivered_at, d.failed_at, d.dlq_at, d.acked_at,
+		       ln.root_id, ln.depth, d.attempt,
+		       (SELECT q.reason FROM harborhook.dlq q WHERE q.delivery_id = d.id ORDER BY q.created_at DESC LIMIT 1)
+		FROM lineage ln
+		JOIN harborhook.deliveries d ON d.id = ln.id
+		WHERE ln.root_id = (SELECT root_id FROM lineage WHERE id = $2)
+		ORDER BY ln.depth, d.enqueued_at
+	`, eventID, req.GetDeliveryId())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.GetDLQEntryResponse{TenantId: owner, EventType: eventType}
+	for rows.Next() {
+		var (
+			id, evID, endpointID                    string
+			replayOf                                sql.NullString
+			statusStr                               sql.NullString
+			httpStatus                              sql.NullInt32
+			errReason                               sql.NullString
+			enq, deq, sent, deliv, fail, dlq, acked sql.NullTime
+			rootID                                  string
+			depth, attempts                         int32
+			reason                                  sql.NullString
+		)
+		if err := rows.Scan(&id, &evID, &endpointID, &replayOf, &statusStr, &httpStatus, &errReason,
+			&enq, &deq, &sent, &deliv, &fail, &dlq, &acked, &rootID, &depth, &attempts, &reason,
+		); err != nil {
+			return nil, err
+		}
+		entry := &webhookv1.DLQEntry{
+			Attempt: &webhookv1.DeliveryAttempt{
+				DeliveryId:     id,
+				EventId:        evID,
+				EndpointId:     endpointID,
+				ReplayOf:       nullStr(replayOf),
+				Status:         mapStatus(nullStr(statusStr)),
+				HttpStatus:     nullI32(httpStatus),
+				ErrorReason:    nullStr(errReason),
+				RootDeliveryId: rootID,
+				ReplayDepth:    depth,
+				EnqueuedAt:     toTS(enq),
+				DequeuedAt:     toTS(deq),
+				SentAt:         toTS(sent),
+				DeliveredAt:    toTS(deliv),
+				FailedAt:       toTS(fail),
+				DlqAt:          toTS(dlq),
+				AckedAt:        toTS(acked),
+			},
+			AttemptCount: attempts,
+			Reason:       nullStr(reason),
+		}
+		if id == req.GetDeliveryId() {
+			resp.Entry = entry
+		}
+		resp.History = append(resp.History, entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// PurgeDLQ removes DLQ entries matching the filters, copying them to the DLQ archive first when
+// asked. The dead deliveries stay behind as history, but no longer show up in ListDLQ or get
+// picked up by ReplayDLQ.
+func (s *Server) PurgeDLQ(ctx context.Context, req *webhookv1.PurgeDLQRequest) (*webhookv1.PurgeDLQResponse, error) {
+	filtered := req.GetEndpointId() != "" || req.GetEventType() != "" || req.GetDeliveryId() != "" ||
+		req.GetFrom() != nil || req.GetTo() != nil
+	if !filtered && !req.GetAll() {
+		return nil, apierr.Invalid("set a filter or all to purge every entry")
+	}
+	if req.GetFrom() != nil && req.GetTo() != nil && !req.GetFrom().AsTime().Before(req.GetTo().AsTime()) {
+		return nil, apierr.Invalid("from must be before to")
+	}
+
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+
+	args := []any{}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	where := "q.delivery_id = d.id AND ev.id = d.event_id AND ep.id = d.endpoint_id"
+	if did := req.GetDeliveryId(); did != "" {
+		where += " AND d.id = " + arg(did)
+	}
+	if eid := req.GetEndpointId(); eid != "" {
+		where += " AND d.endpoint_id = " + arg(eid)
+	}
+	if et := req.GetEventType(); et != "" {
+		where += " AND ev.event_type = " + arg(et)
+	}
+	if tenantID != "" {
+		where += " AND ep.tenant_id = " + arg(tenantID)
+	}
+	if req.GetFrom() != nil {
+		where += " AND q.created_at >= " + arg(req.GetFrom().AsTime())
+	}
+	if req.GetTo() != nil {
+		where += " AND q.created_at < " + arg(req.GetTo().AsTime())
+	}
+
+	var matched int32
+	if err := s.pool.QueryRow(ctx, `
+		SELECT count(DISTINCT d.id)
+		FROM harborhook.dlq q, harborhook.deliveries d, harborhook.events ev, harborhook.endpoints ep
+		WHERE `+where, args...).Scan(&matched); err != nil {
+		return nil, fmt.Errorf("count dlq entries: %w", err)
+	}
+
+	tracing.AddSpanEvent(ctx, "dlq.purge_matched",
+		attribute.Int("matched_count", int(matched)),
+		attribute.Bool("dry_run", req.GetDryRun()))
+	if req.GetDryRun() || matched == 0 {
+		return &webhookv1.PurgeDLQResponse{MatchedCount: matched, DryRun: req.GetDryRun()}, nil
+	}
+
+	// Archived entries are copied from the deleted rows, so a concurrent purge can't archive them twice
+	var purged, archived int32
+	if err := s.pool.QueryRow(ctx, `
+		WITH del AS (
+			DELETE FROM harborhook.dlq q
+			USING harborhook.deliveries d, harborhook.events ev, harborhook.endpoints ep
+			WHERE `+where+`
+			RETURNING q.delivery_id, q.reason, q.created_at
+		), archived AS (`+store.ArchiveDLQSQL("(SELECT * FROM del WHERE "+arg(req.GetArchive())+")")+`
+			RETURNING 1
+		)
+		SELECT count(DISTINCT delivery_id), (SELECT count(*) FROM archived) FROM del`, args...).Scan(&purged, &archived); err != nil {
+		return nil, fmt.Errorf("purge dlq entries: %w", err)
+	}
+	return &webhookv1.PurgeDLQResponse{MatchedCount: matched, PurgedCount: purged, ArchivedCount: archived}, nil
+}
@@ -0,0 +1,83 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/store"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetDLQRetentionDefaults sets the retention reported for tenants without their own. It should
+// match the worker's, which does the purging.
+func (s *Server) SetDLQRetentionDefaults(r store.DLQRetention) {
+	s.dlqRetention = r
+}
+
+// SetDLQRetention sets how long and how many DLQ entries a tenant keeps, or with reset removes
+// its retention so it inherits the cluster default
+func (s *Server) SetDLQRetention(ctx context.Context, req *webhookv1.SetDLQRetentionRequest) (*webhookv1.SetDLQRetentionResponse, error) {
+	if req.GetReset_() {
+		if _, err := s.pool.Exec(ctx, `DELETE FROM harborhook.tenant_dlq_retention WHERE tenant_id = $1`, req.GetTenantId()); err != nil {
+			return nil, fmt.Errorf("reset dlq retention: %w", err)
+		}
+		return &webhookv1.SetDLQRetentionResponse{Retention: s.defaultDLQRetention(req.GetTenantId())}, nil
+	}
+
+	var updatedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.tenant_dlq_retention(tenant_id, max_age_seconds, max_entries, archive)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET max_age_seconds = EXCLUDED.max_age_seconds, max_entries = EXCLUDED.max_entries,
+		    archive = EXCLUDED.archive, updated_at = now()
+		RETURNING updated_at
+	`, req.GetTenantId(), req.GetMaxAgeSeconds(), req.GetMaxEntries(), req.GetArchive()).Scan(&updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("save dlq retention: %w", err)
+	}
+
+	return &webhookv1.SetDLQRetentionResponse{
+		Retention: &webhookv1.DLQRetention{
+			TenantId:      req.GetTenantId(),
+			MaxAgeSeconds: req.GetMaxAgeSeconds(),
+			MaxEntries:    req.GetMaxEntries(),
+			Archive:       req.GetArchive(),
+			UpdatedAt:     timestamppb.New(updatedAt),
+		},
+	}, nil
+}
+
+// GetDLQRetention returns a tenant's DLQ retention, or the cluster default when it has none
+func (s *Server) GetDLQRetention(ctx context.Context, req *webhookv1.GetDLQRetentionRequest) (*webhookv1.GetDLQRetentionResponse, error) {
+	r := &webhookv1.DLQRetention{TenantId: req.GetTenantId()}
+	var updatedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		SELECT max_age_seconds, max_entries, archive, updated_at
+		FROM harborhook.tenant_dlq_retention
+		WHERE tenant_id = $1
+	`, req.GetTenantId()).Scan(&r.MaxAgeSeconds, &r.MaxEntries, &r.Archive, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &webhookv1.GetDLQRetentionResponse{Retention: s.defaultDLQRetention(req.GetTenantId())}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get dlq retention: %w", err)
+	}
+	r.UpdatedAt = timestamppb.New(updatedAt)
+	return &webhookv1.GetDLQRetentionResponse{Retention: r}, nil
+}
+
+func (s *Server) defaultDLQRetention(tenantID string) *webhookv1.DLQRetention {
+	return &webhookv1.DLQRetention{
+		TenantId:       tenantID,
+		MaxAgeSeconds:  int64(s.dlqRetention.MaxAge / time.Second),
+		MaxEntries:     int32(s.dlqRetention.MaxEntries),
+		Archive:        s.dlqRetention.Archive,
+		ClusterDefault: true,
+	}
+}
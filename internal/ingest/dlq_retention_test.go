@@ -0,0 +1,103 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/store"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_SetDLQRetention(t *testing.T) {
+	updated := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var upsertArgs []any
+	var deleted bool
+	pool := &dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			upsertArgs = args
+			return dbfake.Row{Values: []any{updated}}
+		},
+		ExecFunc: func(sql string, args []any) (pgconn.CommandTag, error) {
+			deleted = strings.Contains(sql, "DELETE FROM harborhook.tenant_dlq_retention") && args[0] == "tn_1"
+			return pgconn.NewCommandTag("DELETE 1"), nil
+		},
+	}
+	server := NewServer(pool, nil)
+	server.SetDLQRetentionDefaults(store.DLQRetention{MaxAge: time.Hour})
+
+	resp, err := server.SetDLQRetention(context.Background(), &webhookv1.SetDLQRetentionRequest{
+		TenantId: "tn_1", MaxAgeSeconds: 86400, MaxEntries: 1000, Archive: true,
+	})
+	if err != nil {
+		t.Fatalf("SetDLQRetention() unexpected error: %v", err)
+	}
+	r := resp.GetRetention()
+	if r.GetMaxAgeSeconds() != 86400 || r.GetMaxEntries() != 1000 || !r.GetArchive() || r.GetClusterDefault() || !r.GetUpdatedAt().AsTime().Equal(updated) {
+		t.Errorf("SetDLQRetention() = %v", r)
+	}
+	if len(upsertArgs) != 4 || upsertArgs[0] != "tn_1" || upsertArgs[1] != int64(86400) {
+		t.Errorf("upsert args = %v", upsertArgs)
+	}
+
+	resp, err = server.SetDLQRetention(context.Background(), &webhookv1.SetDLQRetentionRequest{TenantId: "tn_1", Reset_: true})
+	if err != nil {
+		t.Fatalf("SetDLQRetention(reset) unexpected error: %v", err)
+	}
+	if r := resp.GetRetention(); !deleted || !r.GetClusterDefault() || r.GetMaxAgeSeconds() != 3600 {
+		t.Errorf("SetDLQRetention(reset) = %v, deleted = %v; want the cluster default", r, deleted)
+	}
+}
+
+func TestServer_GetDLQRetention(t *testing.T) {
+	// No row for the tenant: the cluster default
+	server := NewServer(&dbfake.Pool{}, nil)
+	server.SetDLQRetentionDefaults(store.DLQRetention{MaxEntries: 500, Archive: true})
+	resp, err := server.GetDLQRetention(context.Background(), &webhookv1.GetDLQRetentionRequest{TenantId: "tn_1"})
+	if err != nil {
+		t.Fatalf("GetDLQRetention() unexpected error: %v", err)
+	}
+	if r := resp.GetRetention(); !r.GetClusterDefault() || r.GetMaxEntries() != 500 || !r.GetArchive() || r.GetTenantId() != "tn_1" {
+		t.Errorf("GetDLQRetention() = %v, want the cluster default", r)
+	}
+
+	server = NewServer(&dbfake.Pool{QueryRowFunc: func(string, []any) pgx.Row {
+		return dbfake.Row{Values: []any{int64(600), int32(0), false, time.Now()}}
+	}}, nil)
+	resp, err = server.GetDLQRetention(context.Background(), &webhookv1.GetDLQRetentionRequest{TenantId: "tn_1"})
+	if err != nil {
+		t.Fatalf("GetDLQRetention() unexpected error: %v", err)
+	}
+	if r := resp.GetRetention(); r.GetClusterDefault() || r.GetMaxAgeSeconds() != 600 {
+		t.Errorf("GetDLQRetention() = %v, want the tenant's own", r)
+	}
+}
+
+func TestServer_PurgeDLQ_Archive(t *testing.T) {
+	var purgeSQL string
+	var purgeArgs []any
+	pool := &dbfake.Pool{QueryRowFunc: func(sql string, args []any) pgx.Row {
+		if strings.HasPrefix(strings.TrimSpace(sql), "SELECT count") {
+			return dbfake.Row{Values: []any{int32(4)}}
+		}
+		purgeSQL, purgeArgs = sql, args
+		return dbfake.Row{Values: []any{int32(4), int32(4)}}
+	}}
+	server := NewServer(pool, nil)
+
+	resp, err := server.PurgeDLQ(context.Background(), &webhookv1.PurgeDLQRequest{EndpointId: "ep_1", Archive: true})
+	if err != nil {
+		t.Fatalf("PurgeDLQ() unexpected error: %v", err)
+	}
+	if resp.GetPurgedCount() != 4 || resp.GetArchivedCount() != 4 {
+		t.Errorf("PurgeDLQ() = %v, want 4 purged and archived", resp)
+	}
+	if !strings.Contains(purgeSQL, "INSERT INTO harborhook.dlq_archive") || purgeArgs[len(purgeArgs)-1] != true {
+		t.Errorf("purge query %q with %v, want entries archived", purgeSQL, purgeArgs)
+	}
+}
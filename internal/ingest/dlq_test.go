@@ -0,0 +1,52 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestServer_PurgeDLQ_Validation(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		request  *webhookv1.PurgeDLQRequest
+		errorMsg string
+	}{
+		{
+			name:     "no filter",
+			request:  &webhookv1.PurgeDLQRequest{},
+			errorMsg: "set a filter or all to purge every entry",
+		},
+		{
+			name:     "tenant alone is not a filter",
+			request:  &webhookv1.PurgeDLQRequest{TenantId: "tn_123", DryRun: true},
+			errorMsg: "set a filter or all to purge every entry",
+		},
+		{
+			name: "from after to",
+			request: &webhookv1.PurgeDLQRequest{
+				From: timestamppb.New(now),
+				To:   timestamppb.New(now.Add(-time.Hour)),
+			},
+			errorMsg: "from must be before to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+
+			_, err := server.PurgeDLQ(context.Background(), tt.request)
+			if err == nil {
+				t.Fatal("PurgeDLQ() expected error but got none")
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("PurgeDLQ() error = %q, want %q", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetEgressIPs sets the addresses webhooks are sent from (EGRESS_IPS), published by
+// GetEgressIPs so receivers can allowlist them. Entries are IP addresses or CIDRs; addresses are
+// published as single-address CIDRs.
+func (s *Server) SetEgressIPs(entries []string) error {
+	cidrs := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if p, err := netip.ParsePrefix(e); err == nil {
+			cidrs = append(cidrs, p.Masked().String())
+			continue
+		}
+		a, err := netip.ParseAddr(e)
+		if err != nil {
+			return fmt.Errorf("invalid egress IP %q", e)
+		}
+		cidrs = append(cidrs, netip.PrefixFrom(a.Unmap(), a.Unmap().BitLen()).String())
+	}
+	s.egressCIDRs = cidrs
+	return nil
+}
+
+// GetEgressIPs returns the addresses webhooks are sent from, and whether they leave through an
+// egress proxy
+func (s *Server) GetEgressIPs(ctx context.Context, req *webhookv1.GetEgressIPsRequest) (*webhookv1.GetEgressIPsResponse, error) {
+	return &webhookv1.GetEgressIPsResponse{
+		Cidrs:   append([]string{}, s.egressCIDRs...),
+		Proxied: s.egress.Proxied(),
+	}, nil
+}
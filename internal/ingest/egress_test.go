@@ -0,0 +1,36 @@
+package ingest
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_GetEgressIPs(t *testing.T) {
+	server := NewServer(nil, nil)
+	resp, err := server.GetEgressIPs(context.Background(), &webhookv1.GetEgressIPsRequest{})
+	if err != nil || len(resp.Cidrs) != 0 || resp.Proxied {
+		t.Fatalf("GetEgressIPs() unconfigured = %+v, %v, want nothing published", resp, err)
+	}
+
+	if err := server.SetEgressIPs([]string{"203.0.113.10", "198.51.100.7/24", "2001:db8::1"}); err != nil {
+		t.Fatal(err)
+	}
+	g, _ := netguard.New(nil)
+	if err := g.SetProxy(netguard.Proxy{URL: "http://egress-proxy:3128"}); err != nil {
+		t.Fatal(err)
+	}
+	server.SetEgressGuard(g)
+	resp, err = server.GetEgressIPs(context.Background(), &webhookv1.GetEgressIPsRequest{})
+	want := []string{"203.0.113.10/32", "198.51.100.0/24", "2001:db8::1/128"}
+	if err != nil || !slices.Equal(resp.Cidrs, want) || !resp.Proxied {
+		t.Errorf("GetEgressIPs() = %+v, %v, want %v through the proxy", resp, err, want)
+	}
+
+	if err := server.SetEgressIPs([]string{"egress.example.com"}); err == nil {
+		t.Error("SetEgressIPs() with a hostname succeeded, want an error")
+	}
+}
@@ -0,0 +1,120 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetEndpointHealthCheck turns an endpoint's health checks on or off. Workers check it from
+// their next round; while it fails its checks its deliveries are held. Turning checks off
+// forgets its health, so deliveries resume at once.
+func (s *Server) SetEndpointHealthCheck(ctx context.Context, req *webhookv1.SetEndpointHealthCheckRequest) (*webhookv1.SetEndpointHealthCheckResponse, error) {
+	check := req.GetHealthCheck()
+	method := healthCheckMethodColumn(check.GetMethod())
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		WITH updated AS (
+			UPDATE harborhook.endpoints
+			SET health_check_enabled = $3, health_check_method = $4
+			WHERE id = $1 AND tenant_id = $2
+			RETURNING id, url, created_at
+		), forgotten AS (
+			DELETE FROM harborhook.endpoint_health
+			WHERE NOT $3 AND endpoint_id IN (SELECT id FROM updated)
+		)
+		SELECT url, created_at FROM updated`,
+		req.GetEndpointId(), req.GetTenantId(), check != nil, method,
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointHealthCheckResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:          req.GetEndpointId(),
+			TenantId:    req.GetTenantId(),
+			Url:         endpointURL,
+			CreatedAt:   timestamppb.New(createdAt),
+			HealthCheck: describeHealthCheck(check != nil, method),
+		},
+	}, nil
+}
+
+// healthCheckMethodColumn maps the API's method to endpoints.health_check_method
+func healthCheckMethodColumn(m webhookv1.HealthCheckMethod) string {
+	if m == webhookv1.HealthCheckMethod_HEALTH_CHECK_METHOD_GET {
+		return delivery.HealthCheckGET
+	}
+	return delivery.HealthCheckHEAD
+}
+
+// describeHealthCheck returns an endpoint's health check settings, or nil when it isn't checked
+func describeHealthCheck(enabled bool, method string) *webhookv1.EndpointHealthCheck {
+	if !enabled {
+		return nil
+	}
+	if method == delivery.HealthCheckGET {
+		return &webhookv1.EndpointHealthCheck{Method: webhookv1.HealthCheckMethod_HEALTH_CHECK_METHOD_GET}
+	}
+	return &webhookv1.EndpointHealthCheck{Method: webhookv1.HealthCheckMethod_HEALTH_CHECK_METHOD_HEAD}
+}
+
+// endpointHealthColumns are the endpoint_health columns endpointHealthRow holds, for a query that
+// LEFT JOINs endpoint_health as h
+const endpointHealthColumns = `h.status, h.last_checked_at, COALESCE(h.last_status_code, 0), COALESCE(h.last_error, ''),
+		       COALESCE(h.last_latency_ms, 0), COALESCE(h.consecutive_failures, 0), h.changed_at`
+
+// endpointHealthRow holds endpointHealthColumns as scanned
+type endpointHealthRow struct {
+	status              sql.NullString
+	lastCheckedAt       sql.NullTime
+	lastStatusCode      int32
+	lastError           string
+	lastLatencyMS       int32
+	consecutiveFailures int32
+	changedAt           sql.NullTime
+}
+
+// dest returns the scan destinations for endpointHealthColumns
+func (r *endpointHealthRow) dest() []any {
+	return []any{&r.status, &r.lastCheckedAt, &r.lastStatusCode, &r.lastError, &r.lastLatencyMS, &r.consecutiveFailures, &r.changedAt}
+}
+
+// describe returns what an endpoint's checks found, or nil before its first check
+func (r endpointHealthRow) describe() *webhookv1.EndpointHealth {
+	if !r.lastCheckedAt.Valid {
+		return nil
+	}
+	h := &webhookv1.EndpointHealth{
+		Status:              webhookv1.EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_UNKNOWN,
+		LastCheckedAt:       toTS(r.lastCheckedAt),
+		LastStatusCode:      r.lastStatusCode,
+		LastError:           r.lastError,
+		LastLatencyMs:       r.lastLatencyMS,
+		ConsecutiveFailures: r.consecutiveFailures,
+		ChangedAt:           toTS(r.changedAt),
+	}
+	switch r.status.String {
+	case delivery.HealthHealthy:
+		h.Status = webhookv1.EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_HEALTHY
+	case delivery.HealthUnhealthy:
+		h.Status = webhookv1.EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_UNHEALTHY
+	}
+	return h
+}
@@ -0,0 +1,59 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_SetEndpointHealthCheck(t *testing.T) {
+	var stored []any
+	server := NewServer(&dbfake.Pool{
+		QueryRowFunc: func(_ string, args []any) pgx.Row {
+			stored = args
+			return dbfake.Row{Values: []any{"https://partner.example/hook", time.Now()}}
+		},
+	}, nil)
+
+	resp, err := server.SetEndpointHealthCheck(context.Background(), &webhookv1.SetEndpointHealthCheckRequest{
+		TenantId: "tn_1", EndpointId: "ep_1",
+		HealthCheck: &webhookv1.EndpointHealthCheck{Method: webhookv1.HealthCheckMethod_HEALTH_CHECK_METHOD_GET},
+	})
+	if err != nil {
+		t.Fatalf("SetEndpointHealthCheck() unexpected error: %v", err)
+	}
+	if stored[2] != true || stored[3] != "GET" || resp.Endpoint.HealthCheck.GetMethod() != webhookv1.HealthCheckMethod_HEALTH_CHECK_METHOD_GET {
+		t.Errorf("stored %v and returned %v, want GET checks on", stored[2:], resp.Endpoint.HealthCheck)
+	}
+
+	resp, err = server.SetEndpointHealthCheck(context.Background(), &webhookv1.SetEndpointHealthCheckRequest{TenantId: "tn_1", EndpointId: "ep_1"})
+	if err != nil {
+		t.Fatalf("SetEndpointHealthCheck(off) unexpected error: %v", err)
+	}
+	if stored[2] != false || resp.Endpoint.HealthCheck != nil {
+		t.Errorf("stored %v and returned %v, want checks off", stored[2:], resp.Endpoint.HealthCheck)
+	}
+}
+
+func TestEndpointHealthRow_Describe(t *testing.T) {
+	if h := (endpointHealthRow{}).describe(); h != nil {
+		t.Errorf("describe() before a check = %v, want nil", h)
+	}
+	checked := sql.NullTime{Time: time.Now(), Valid: true}
+	h := endpointHealthRow{
+		status:              sql.NullString{String: "unhealthy", Valid: true},
+		lastCheckedAt:       checked,
+		lastError:           "connection refused",
+		consecutiveFailures: 4,
+		changedAt:           checked,
+	}.describe()
+	if h.Status != webhookv1.EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_UNHEALTHY || h.ConsecutiveFailures != 4 || h.LastError != "connection refused" || h.ChangedAt == nil {
+		t.Errorf("describe() = %v, want an unhealthy endpoint", h)
+	}
+}
@@ -0,0 +1,50 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// IdempotencyKeyMetadata is the request metadata a publish's idempotency key can be sent in
+// instead of the body. The gateway maps the Idempotency-Key HTTP header to it.
+const IdempotencyKeyMetadata = "idempotency-key"
+
+// applyIdempotencyKey sets req's idempotency_key from the request metadata when the body has
+// none. A key in both that differs is rejected rather than picking one.
+func applyIdempotencyKey(ctx context.Context, req *webhookv1.PublishEventRequest) error {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil
+	}
+	values := md.Get(IdempotencyKeyMetadata)
+	if len(values) == 0 {
+		return nil
+	}
+	key := strings.TrimSpace(values[0])
+	switch {
+	case key == "":
+		return nil
+	case req.GetIdempotencyKey() == "":
+		req.IdempotencyKey = key
+		return nil
+	case req.GetIdempotencyKey() != key:
+		return status.Error(codes.InvalidArgument, "idempotency_key and the Idempotency-Key header differ")
+	}
+	return nil
+}
+
+// duplicateResponse is the reply to a publish whose idempotency key matched a stored event:
+// that event and how many deliveries it was fanned out to
+func (s *Server) duplicateResponse(ctx context.Context, eventID string) (*webhookv1.PublishEventResponse, error) {
+	fanout, scheduled, err := s.store.EventFanout(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	return &webhookv1.PublishEventResponse{EventId: eventID, FanoutCount: fanout, Scheduled: scheduled, Duplicate: true}, nil
+}
@@ -0,0 +1,115 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestApplyIdempotencyKey(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		header  string
+		want    string
+		wantErr bool
+	}{
+		{name: "header only", header: "k1", want: "k1"},
+		{name: "body only", body: "k1", want: "k1"},
+		{name: "both the same", body: "k1", header: "k1", want: "k1"},
+		{name: "both differ", body: "k1", header: "k2", wantErr: true},
+		{name: "blank header", body: "k1", header: " ", want: "k1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.header != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(IdempotencyKeyMetadata, tt.header))
+			}
+			req := &webhookv1.PublishEventRequest{IdempotencyKey: tt.body}
+			err := applyIdempotencyKey(ctx, req)
+			if tt.wantErr {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Errorf("applyIdempotencyKey() error = %v, want InvalidArgument", err)
+				}
+				return
+			}
+			if err != nil || req.IdempotencyKey != tt.want {
+				t.Errorf("applyIdempotencyKey() = %q, %v, want %q", req.IdempotencyKey, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_PublishEvent_Duplicate(t *testing.T) {
+	var lookedUp []any
+	pool := &dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			switch {
+			case strings.Contains(sql, "SELECT id FROM harborhook.events"):
+				lookedUp = args
+				return dbfake.Row{Values: []any{"evt_1"}}
+			case strings.Contains(sql, "EXISTS"):
+				return dbfake.Row{Values: []any{true}}
+			case strings.Contains(sql, "replay_of IS NULL"):
+				return dbfake.Row{Values: []any{int32(3), false}}
+			}
+			return dbfake.Row{Err: pgx.ErrNoRows} // no tenant quota or event schema
+		},
+	}
+	prod := &recordingPublisher{}
+	server := NewServer(pool, prod)
+
+	// The key comes from the Idempotency-Key header the gateway forwards
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(IdempotencyKeyMetadata, "order-1"))
+	payload, _ := structpb.NewStruct(map[string]any{"order_id": "ord_1"})
+	resp, err := server.PublishEvent(ctx, &webhookv1.PublishEventRequest{TenantId: "tn_1", EventType: "order.created", Payload: payload})
+	if err != nil {
+		t.Fatalf("PublishEvent() unexpected error: %v", err)
+	}
+	if resp.EventId != "evt_1" || !resp.Duplicate || resp.FanoutCount != 3 || resp.Scheduled {
+		t.Errorf("PublishEvent() = %+v, want evt_1 as a duplicate with its original fanout of 3", resp)
+	}
+	if len(lookedUp) != 2 || lookedUp[1] != "order-1" {
+		t.Errorf("looked up the event with %v, want the header's key", lookedUp)
+	}
+	if len(prod.bodies) != 0 {
+		t.Errorf("published %d tasks for a duplicate, want none", len(prod.bodies))
+	}
+}
+
+func TestServer_PublishEvents_Duplicate(t *testing.T) {
+	pool := &dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			switch {
+			case strings.Contains(sql, "INSERT INTO harborhook.events"):
+				return dbfake.Row{Err: pgx.ErrNoRows} // the key is taken
+			case strings.Contains(sql, "replay_of IS NULL"):
+				return dbfake.Row{Values: []any{"evt_1", true, int32(2)}}
+			}
+			return dbfake.Row{Err: pgx.ErrNoRows}
+		},
+	}
+	server := NewServer(pool, &recordingPublisher{})
+
+	payload, _ := structpb.NewStruct(map[string]any{"order_id": "ord_1"})
+	resp, err := server.PublishEvents(context.Background(), &webhookv1.PublishEventsRequest{
+		TenantId: "tn_1",
+		Events:   []*webhookv1.BatchEvent{{EventType: "order.created", Payload: payload, IdempotencyKey: "order-1"}},
+	})
+	if err != nil {
+		t.Fatalf("PublishEvents() unexpected error: %v", err)
+	}
+	if r := resp.Results[0]; r.EventId != "evt_1" || !r.Duplicate || r.FanoutCount != 2 {
+		t.Errorf("result = %+v, want evt_1 as a duplicate with its original fanout of 2", r)
+	}
+}
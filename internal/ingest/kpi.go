@@ -0,0 +1,30 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+)
+
+// businessKPIQuery aggregates the exec dashboard KPIs over the last 24 hours
+const businessKPIQuery = `
+	SELECT
+	    (SELECT count(DISTINCT tenant_id) FROM harborhook.events WHERE created_at >= now() - interval '24 hours'),
+	    (SELECT count(DISTINCT endpoint_id) FROM harborhook.deliveries WHERE created_at >= now() - interval '24 hours'),
+	    (SELECT count(*) FROM harborhook.events WHERE created_at >= now() - interval '24 hours'),
+	    count(*) FILTER (WHERE status = 'delivered'),
+	    count(*) FILTER (WHERE status = 'dead')
+	FROM harborhook.deliveries
+	WHERE status IN ('delivered', 'dead') AND updated_at >= now() - interval '24 hours'`
+
+// BusinessKPIs aggregates the business-level KPIs served on /metrics/business
+func (s *Server) BusinessKPIs(ctx context.Context) (metrics.BusinessKPIs, error) {
+	var k metrics.BusinessKPIs
+	err := s.pool.QueryRow(ctx, businessKPIQuery).Scan(
+		&k.ActiveTenants, &k.ActiveEndpoints, &k.Events, &k.Delivered, &k.Dead)
+	if err != nil {
+		return metrics.BusinessKPIs{}, fmt.Errorf("aggregate business kpis: %w", err)
+	}
+	return k, nil
+}
@@ -0,0 +1,311 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/notify"
+	"github.com/austindbirch/harbor_hook/internal/store"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetNotificationMailer emails notices to tenants' ops addresses through m. Without one, notices
+// only go to tenants' ops endpoints.
+func (s *Server) SetNotificationMailer(m *notify.Mailer) {
+	s.mailer = m
+}
+
+// SetNotificationSettings sets where a tenant's notices go and the thresholds that trigger them
+func (s *Server) SetNotificationSettings(ctx context.Context, req *webhookv1.SetNotificationSettingsRequest) (*webhookv1.SetNotificationSettingsResponse, error) {
+	if req.GetEndpointId() != "" {
+		var owned bool
+		if err := s.pool.QueryRow(ctx, `
+			SELECT EXISTS (SELECT 1 FROM harborhook.endpoints WHERE id = $1 AND tenant_id = $2)`,
+			req.GetEndpointId(), req.GetTenantId(),
+		).Scan(&owned); err != nil {
+			return nil, fmt.Errorf("find endpoint: %w", err)
+		}
+		if !owned {
+			return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+		}
+	}
+
+	var updatedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.tenant_notification_settings(tenant_id, email, endpoint_id, dlq_threshold, secret_max_age_days)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, '')::uuid, $4, $5)
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET email = EXCLUDED.email, endpoint_id = EXCLUDED.endpoint_id, dlq_threshold = EXCLUDED.dlq_threshold,
+		    secret_max_age_days = EXCLUDED.secret_max_age_days, updated_at = now()
+		RETURNING updated_at
+	`, req.GetTenantId(), req.GetEmail(), req.GetEndpointId(), req.GetDlqThreshold(), req.GetSecretMaxAgeDays()).Scan(&updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("save notification settings: %w", err)
+	}
+
+	return &webhookv1.SetNotificationSettingsResponse{
+		Settings: &webhookv1.NotificationSettings{
+			TenantId:         req.GetTenantId(),
+			Email:            req.GetEmail(),
+			EndpointId:       req.GetEndpointId(),
+			DlqThreshold:     req.GetDlqThreshold(),
+			SecretMaxAgeDays: req.GetSecretMaxAgeDays(),
+			UpdatedAt:        timestamppb.New(updatedAt),
+		},
+	}, nil
+}
+
+// GetNotificationSettings returns where a tenant's notices go; a tenant that never set them
+// gets none
+func (s *Server) GetNotificationSettings(ctx context.Context, req *webhookv1.GetNotificationSettingsRequest) (*webhookv1.GetNotificationSettingsResponse, error) {
+	n := &webhookv1.NotificationSettings{TenantId: req.GetTenantId()}
+	var updatedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		SELECT COALESCE(email, ''), COALESCE(endpoint_id::text, ''), dlq_threshold, secret_max_age_days, updated_at
+		FROM harborhook.tenant_notification_settings
+		WHERE tenant_id = $1
+	`, req.GetTenantId()).Scan(&n.Email, &n.EndpointId, &n.DlqThreshold, &n.SecretMaxAgeDays, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &webhookv1.GetNotificationSettingsResponse{Settings: n}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get notification settings: %w", err)
+	}
+	n.UpdatedAt = timestamppb.New(updatedAt)
+	return &webhookv1.GetNotificationSettingsResponse{Settings: n}, nil
+}
+
+// Notices are repeated no more often than this while their condition holds
+const (
+	dlqNoticeCooldown    = 24 * time.Hour
+	secretNoticeCooldown = 7 * 24 * time.Hour
+)
+
+// dueNoticesQuery finds the notices tenants with an ops contact haven't been sent yet: endpoints
+// disabled in the last day, DLQs over their threshold and signing secrets past their maximum age,
+// each with the tenant's ops email and endpoint (empty when it has none, or it is disabled). A
+// secret's age is its endpoint's, as secrets are only set when endpoints are created.
+const dueNoticesQuery = `
+	WITH contact AS (
+		SELECT ns.tenant_id, COALESCE(ns.email, '') AS email,
+		       COALESCE(ops.id::text, '') AS ops_endpoint_id, ns.dlq_threshold, ns.secret_max_age_days
+		FROM harborhook.tenant_notification_settings ns
+		LEFT JOIN harborhook.endpoints ops ON ops.id = ns.endpoint_id AND ops.disabled_at IS NULL
+		WHERE ns.email IS NOT NULL OR ops.id IS NOT NULL
+	), notice AS (
+		SELECT se.tenant_id, '` + notify.KindEndpointDisabled + `' AS kind, se.endpoint_id::text AS endpoint_id,
+		       jsonb_build_object('message', se.message, 'url', e.url, 'reason', se.details->>'reason') AS details,
+		       se.created_at AS at
+		FROM harborhook.system_events se
+		JOIN harborhook.endpoints e ON e.id = se.endpoint_id
+		WHERE se.type = '` + delivery.SystemEventEndpointDisabled + `' AND se.created_at >= now() - interval '1 day'
+		  AND NOT EXISTS (
+			SELECT 1 FROM harborhook.notifications n
+			WHERE n.tenant_id = se.tenant_id AND n.kind = '` + notify.KindEndpointDisabled + `'
+			  AND n.endpoint_id = se.endpoint_id AND n.created_at >= se.created_at)
+		UNION ALL
+		SELECT c.tenant_id, '` + notify.KindDLQThreshold + `', '',
+		       jsonb_build_object('entries', count(*), 'threshold', c.dlq_threshold), now()
+		FROM contact c
+		JOIN harborhook.endpoints e ON e.tenant_id = c.tenant_id
+		JOIN harborhook.deliveries d ON d.endpoint_id = e.id
+		JOIN harborhook.dlq q ON q.delivery_id = d.id
+		WHERE c.dlq_threshold > 0
+		  AND NOT EXISTS (
+			SELECT 1 FROM harborhook.notifications n
+			WHERE n.tenant_id = c.tenant_id AND n.kind = '` + notify.KindDLQThreshold + `'
+			  AND n.created_at >= now() - make_interval(secs => $1))
+		GROUP BY c.tenant_id, c.dlq_threshold
+		HAVING count(*) > c.dlq_threshold
+		UNION ALL
+		SELECT c.tenant_id, '` + notify.KindSecretRotationDue + `', e.id::text,
+		       jsonb_build_object('url', e.url, 'age_days', extract(day FROM now() - e.created_at)::int,
+		                          'max_age_days', c.secret_max_age_days),
+		       now()
+		FROM contact c
+		JOIN harborhook.endpoints e ON e.tenant_id = c.tenant_id
+		WHERE c.secret_max_age_days > 0 AND e.disabled_at IS NULL AND COALESCE(e.secret, '') <> ''
+		  AND e.created_at < now() - make_interval(days => c.secret_max_age_days)
+		  AND NOT EXISTS (
+			SELECT 1 FROM harborhook.notifications n
+			WHERE n.tenant_id = c.tenant_id AND n.kind = '` + notify.KindSecretRotationDue + `'
+			  AND n.endpoint_id = e.id AND n.created_at >= now() - make_interval(secs => $2))
+	)
+	SELECT n.tenant_id, n.kind, n.endpoint_id, n.details, n.at, c.email, c.ops_endpoint_id
+	FROM notice n
+	JOIN contact c ON c.tenant_id = n.tenant_id
+	ORDER BY n.at`
+
+// dueNotice is a notice and where it goes
+type dueNotice struct {
+	notify.Notice
+	email, opsEndpointID string
+}
+
+// SendNotifications sends tenants' ops contacts the notices due to them: by email when the tenant
+// has an address and a mailer is set, and as a harborhook.notice.* event to its ops endpoint. Each
+// notice sent is recorded, with any failure, so it isn't sent again. It returns how many were
+// sent. Replicas running it together may send a notice twice.
+func (s *Server) SendNotifications(ctx context.Context) (int, error) {
+	rows, err := s.pool.Query(ctx, dueNoticesQuery, dlqNoticeCooldown.Seconds(), secretNoticeCooldown.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("find due notices: %w", err)
+	}
+	var due []dueNotice
+	for rows.Next() {
+		var (
+			n       dueNotice
+			details []byte
+		)
+		if err := rows.Scan(&n.TenantID, &n.Kind, &n.EndpointID, &details, &n.At, &n.email, &n.opsEndpointID); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if err := json.Unmarshal(details, &n.Details); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("decode %s notice details: %w", n.Kind, err)
+		}
+		due = append(due, n)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, n := range due {
+		if err := s.sendNotice(ctx, n); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// sendNotice renders n, sends it to the tenant's ops contact and records it. A channel failing
+// is recorded with the notice rather than returned.
+func (s *Server) sendNotice(ctx context.Context, n dueNotice) error {
+	subject, body, err := notify.Render(n.Notice)
+	if err != nil {
+		return err
+	}
+
+	var (
+		deliveryID, email, failure string
+		emailedAt                  *time.Time
+	)
+	if n.opsEndpointID != "" {
+		deliveryID, err = s.publishNotice(ctx, n, subject, body)
+		metrics.RecordNotification(n.Kind, "webhook", err)
+		if err != nil {
+			failure = "webhook: " + err.Error()
+		}
+	}
+	if n.email != "" && s.mailer != nil {
+		email = n.email
+		now := time.Now()
+		err := s.mailer.Send(n.email, subject, body, now)
+		metrics.RecordNotification(n.Kind, "email", err)
+		if err != nil {
+			failure = joinFailure(failure, "email: "+err.Error())
+		} else {
+			emailedAt = &now
+		}
+	}
+
+	details, err := json.Marshal(n.Details)
+	if err != nil {
+		return err
+	}
+	if _, err := s.pool.Exec(ctx, `
+		INSERT INTO harborhook.notifications
+			(tenant_id, kind, endpoint_id, subject, body, details, delivery_id, email, email_sent_at, error)
+		VALUES ($1, $2, NULLIF($3, '')::uuid, $4, $5, $6, NULLIF($7, '')::uuid, NULLIF($8, ''), $9, NULLIF($10, ''))`,
+		n.TenantID, n.Kind, n.EndpointID, subject, body, details, deliveryID, email, emailedAt, failure); err != nil {
+		return fmt.Errorf("record %s notice: %w", n.Kind, err)
+	}
+	return nil
+}
+
+// publishNotice delivers n to the tenant's ops endpoint as a high priority event, through the
+// outbox like any publish. It returns the delivery's ID.
+func (s *Server) publishNotice(ctx context.Context, n dueNotice, subject, body string) (string, error) {
+	payload, err := json.Marshal(struct {
+		notify.Notice
+		Subject string `json:"subject"`
+		Body    string `json:"body"`
+	}{n.Notice, subject, body})
+	if err != nil {
+		return "", err
+	}
+	eventType := notify.EventTypePrefix + n.Kind
+
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	eventID, _, err := store.New(tx).InsertEvent(ctx, store.NewEvent{
+		TenantID:    n.TenantID,
+		EventType:   eventType,
+		PayloadJSON: payload,
+		Priority:    delivery.PriorityHigh,
+	})
+	if err != nil {
+		return "", err
+	}
+	var deliveryID, endpointURL string
+	if err := tx.QueryRow(ctx, `
+		WITH ep AS (SELECT id, url FROM harborhook.endpoints WHERE id = $2),
+		d AS (
+			INSERT INTO harborhook.deliveries(event_id, endpoint_id, status)
+			SELECT $1, id, 'queued' FROM ep
+			RETURNING id
+		)
+		SELECT d.id, ep.url FROM d, ep`,
+		eventID, n.opsEndpointID,
+	).Scan(&deliveryID, &endpointURL); err != nil {
+		return "", fmt.Errorf("insert notice delivery: %w", err)
+	}
+	taskPayload, payloadRef, err := s.claimCheck(ctx, n.TenantID, eventID, payload)
+	if err != nil {
+		return "", err
+	}
+	outbox, err := writeOutbox(ctx, tx, []delivery.Task{{
+		DeliveryID:  deliveryID,
+		EventID:     eventID,
+		TenantID:    n.TenantID,
+		EndpointID:  n.opsEndpointID,
+		EndpointURL: endpointURL,
+		EventType:   eventType,
+		Payload:     taskPayload,
+		PayloadRef:  payloadRef,
+		Priority:    s.taskPriority(delivery.PriorityHigh),
+	}})
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("commit notice: %w", err)
+	}
+	s.sendOutbox(ctx, outbox)
+	return deliveryID, nil
+}
+
+// joinFailure adds a channel's failure to those recorded for a notice
+func joinFailure(failures, failure string) string {
+	if failures == "" {
+		return failure
+	}
+	return failures + "; " + failure
+}
@@ -0,0 +1,110 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/notify"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_SendNotifications(t *testing.T) {
+	at := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	var (
+		recorded [][]any
+		priority any
+	)
+	pool := &dbfake.Pool{
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			switch {
+			case strings.Contains(sql, "WITH contact AS"):
+				return dbfake.NewRows(
+					[]any{"tn_1", notify.KindEndpointDisabled, "ep_dead", []byte(`{"message":"Endpoint disabled","url":"https://a.example/hook"}`), at, "", "ep_ops"},
+					[]any{"tn_2", notify.KindDLQThreshold, "", []byte(`{"entries":120,"threshold":100}`), at, "ops@b.example", ""},
+				), nil
+			case strings.Contains(sql, "INSERT INTO harborhook.delivery_outbox"):
+				return dbfake.NewRows([]any{int64(1), args[0].([]string)[0]}), nil
+			}
+			return nil, fmt.Errorf("unexpected query: %s", sql)
+		},
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			switch {
+			case strings.Contains(sql, "INSERT INTO harborhook.events"):
+				priority = args[4]
+				return dbfake.Row{Values: []any{"evt_1"}}
+			case strings.Contains(sql, "INSERT INTO harborhook.deliveries"):
+				return dbfake.Row{Values: []any{"del_1", "https://ops.example/hook"}}
+			}
+			return dbfake.Row{Err: fmt.Errorf("unexpected query: %s", sql)}
+		},
+		ExecFunc: func(sql string, args []any) (pgconn.CommandTag, error) {
+			if strings.Contains(sql, "INSERT INTO harborhook.notifications") {
+				recorded = append(recorded, args)
+			}
+			return pgconn.NewCommandTag("UPDATE 1"), nil
+		},
+	}
+	prod := &recordingPublisher{}
+	server := NewServer(pool, prod)
+
+	n, err := server.SendNotifications(context.Background())
+	if err != nil {
+		t.Fatalf("SendNotifications() unexpected error: %v", err)
+	}
+	if n != 2 || len(recorded) != 2 {
+		t.Fatalf("sent %d notices, recorded %d, want 2", n, len(recorded))
+	}
+
+	// The disabled endpoint's notice goes to the ops endpoint as a high priority event
+	if len(prod.bodies) != 1 {
+		t.Fatalf("published %d tasks, want 1 to the ops endpoint", len(prod.bodies))
+	}
+	var task delivery.Task
+	if err := json.Unmarshal(prod.bodies[0], &task); err != nil {
+		t.Fatal(err)
+	}
+	if task.EndpointID != "ep_ops" || task.EventType != notify.EventTypePrefix+notify.KindEndpointDisabled || priority != "high" {
+		t.Errorf("task = %+v with priority %v, want a high priority %s%s to ep_ops", task, priority, notify.EventTypePrefix, notify.KindEndpointDisabled)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(task.Payload, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if payload["endpoint_id"] != "ep_dead" || !strings.Contains(payload["subject"].(string), "ep_dead") {
+		t.Errorf("payload = %v, want the notice about ep_dead with its subject", payload)
+	}
+	if recorded[0][6] != "del_1" {
+		t.Errorf("recorded delivery %v, want del_1", recorded[0][6])
+	}
+
+	// Without a mailer the DLQ notice is recorded but not emailed
+	if recorded[1][1] != notify.KindDLQThreshold || recorded[1][7] != "" || recorded[1][8] != (*time.Time)(nil) {
+		t.Errorf("recorded %v, want the DLQ notice unsent by email", recorded[1])
+	}
+}
+
+func TestServer_SetNotificationSettings_ForeignEndpoint(t *testing.T) {
+	server := NewServer(&dbfake.Pool{
+		QueryRowFunc: func(string, []any) pgx.Row {
+			return dbfake.Row{Values: []any{false}}
+		},
+	}, nil)
+
+	_, err := server.SetNotificationSettings(context.Background(), &webhookv1.SetNotificationSettingsRequest{
+		TenantId: "tn_1", EndpointId: "6f1c1a36-7a51-4b8e-9a43-0c3f2a5d8e11",
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("SetNotificationSettings(another tenant's endpoint) error = %v, want NotFound", err)
+	}
+}
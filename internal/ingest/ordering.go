@@ -0,0 +1,66 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// orderingKeySQL is the ordering key of a delivery to endpoint e for the payload JSON in param:
+// the value at the endpoint's partition key path (the empty string when the path is empty or
+// missing), or NULL when the endpoint isn't ordered
+func orderingKeySQL(param string) string {
+	return `CASE WHEN e.ordered THEN COALESCE(` + param + `::jsonb #>> string_to_array(NULLIF(e.partition_key, ''), '.'), '') END`
+}
+
+// SetEndpointOrdering turns ordered delivery on or off for an endpoint. It applies to events
+// published afterwards; deliveries already queued keep the ordering they were published with.
+func (s *Server) SetEndpointOrdering(ctx context.Context, req *webhookv1.SetEndpointOrderingRequest) (*webhookv1.SetEndpointOrderingResponse, error) {
+	ordering := req.GetOrdering()
+	if key := ordering.GetPartitionKey(); key != "" && !delivery.ValidFieldPath(key) {
+		return nil, apierr.Invalid("invalid partition_key %q: use a dot-notation field path", key)
+	}
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET ordered = $3, partition_key = $4
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at`,
+		req.GetEndpointId(), req.GetTenantId(), ordering != nil, ordering.GetPartitionKey(),
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointOrderingResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:        req.GetEndpointId(),
+			TenantId:  req.GetTenantId(),
+			Url:       endpointURL,
+			CreatedAt: timestamppb.New(createdAt),
+			Ordering:  describeOrdering(ordering != nil, ordering.GetPartitionKey()),
+		},
+	}, nil
+}
+
+// describeOrdering maps an endpoint's ordering columns to the API, or returns nil when it isn't ordered
+func describeOrdering(ordered bool, partitionKey string) *webhookv1.DeliveryOrdering {
+	if !ordered {
+		return nil
+	}
+	return &webhookv1.DeliveryOrdering{PartitionKey: partitionKey}
+}
@@ -0,0 +1,119 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_SetEndpointOrdering(t *testing.T) {
+	server := &Server{}
+	tests := []struct {
+		name    string
+		req     *webhookv1.SetEndpointOrderingRequest
+		wantErr string
+	}{
+		{"bad partition key", &webhookv1.SetEndpointOrderingRequest{
+			TenantId: "tn_1", EndpointId: "ep_1", Ordering: &webhookv1.DeliveryOrdering{PartitionKey: "order..id"},
+		}, "invalid partition_key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := server.SetEndpointOrdering(context.Background(), tt.req); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("SetEndpointOrdering() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	var stored []any
+	server = NewServer(&dbfake.Pool{
+		QueryRowFunc: func(_ string, args []any) pgx.Row {
+			stored = args[2:]
+			return dbfake.Row{Values: []any{"https://partner.example/hook", time.Now()}}
+		},
+	}, nil)
+	resp, err := server.SetEndpointOrdering(context.Background(), &webhookv1.SetEndpointOrderingRequest{
+		TenantId: "tn_1", EndpointId: "ep_1", Ordering: &webhookv1.DeliveryOrdering{PartitionKey: "order.id"},
+	})
+	if err != nil {
+		t.Fatalf("SetEndpointOrdering() unexpected error: %v", err)
+	}
+	if stored[0] != true || stored[1] != "order.id" || resp.Endpoint.Ordering.GetPartitionKey() != "order.id" {
+		t.Errorf("stored %v and returned %v, want ordered by order.id", stored, resp.Endpoint.Ordering)
+	}
+
+	resp, err = server.SetEndpointOrdering(context.Background(), &webhookv1.SetEndpointOrderingRequest{TenantId: "tn_1", EndpointId: "ep_1"})
+	if err != nil || stored[0] != false || resp.Endpoint.Ordering != nil {
+		t.Errorf("SetEndpointOrdering(unset) stored %v and returned %v, %v, want ordering off", stored, resp.GetEndpoint().GetOrdering(), err)
+	}
+}
+
+// recordingPublisher keeps every message it is asked to publish
+type recordingPublisher struct {
+	discardPublisher
+	bodies [][]byte
+	topics []string // the topic of each body
+}
+
+func (p *recordingPublisher) PublishBatch(topic string, bodies [][]byte) []error {
+	p.bodies = append(p.bodies, bodies...)
+	for range bodies {
+		p.topics = append(p.topics, topic)
+	}
+	return make([]error, len(bodies))
+}
+
+func TestServer_PublishEvent_Ordered(t *testing.T) {
+	pool := fanoutPool(2)
+	subscribers := pool.QueryFunc
+	pool.QueryFunc = func(sql string, args []any) (pgx.Rows, error) {
+		if strings.Contains(sql, "FROM harborhook.subscriptions") {
+			// ep_0 is ordered by customer.id; ep_1 isn't ordered
+			return dbfake.NewRows(
+				[]any{"sub_0", "ep_0", "https://example.com/hook", []string(nil), []string(nil), "cus_456"},
+				[]any{"sub_1", "ep_1", "https://example.com/hook", []string(nil), []string(nil), nil},
+			), nil
+		}
+		return subscribers(sql, args)
+	}
+	inserted := map[string]any{}
+	fanout := pool.QueryRowFunc
+	pool.QueryRowFunc = func(query string, args []any) pgx.Row {
+		if strings.Contains(query, "INSERT INTO harborhook.deliveries") {
+			inserted[args[1].(string)] = args[3].(sql.NullString)
+		}
+		return fanout(query, args)
+	}
+	prod := &recordingPublisher{}
+	server := NewServer(pool, prod)
+
+	payload, _ := structpb.NewStruct(map[string]any{"customer": map[string]any{"id": "cus_456"}})
+	if _, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{
+		TenantId: "tn_1", EventType: "order.created", Payload: payload,
+	}); err != nil {
+		t.Fatalf("PublishEvent() unexpected error: %v", err)
+	}
+
+	if inserted["ep_0"] != (sql.NullString{String: "cus_456", Valid: true}) || inserted["ep_1"] != (sql.NullString{}) {
+		t.Errorf("inserted ordering keys %v, want cus_456 for ep_0 and none for ep_1", inserted)
+	}
+	ordered := map[string]bool{}
+	for _, b := range prod.bodies {
+		var task delivery.Task
+		_ = json.Unmarshal(b, &task)
+		ordered[task.EndpointID] = task.Ordered
+	}
+	if !ordered["ep_0"] || ordered["ep_1"] {
+		t.Errorf("tasks ordered = %v, want only ep_0", ordered)
+	}
+}
@@ -0,0 +1,223 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	outboxRelayBatch = 200
+	// Rows younger than this are left to the inline publish that follows their commit
+	outboxRelayGrace = 10 * time.Second
+	// Sent rows are kept this long for debugging, then purged by the relay
+	outboxRetention = 24 * time.Hour
+)
+
+// outboxMessage is a stored delivery task waiting to be published to the queue
+type outboxMessage struct {
+	id    int64
+	topic string
+	body  []byte
+}
+
+// writeOutbox stores one outbox row per task inside tx, so the tasks commit or roll back
+// together with their deliveries
+func writeOutbox(ctx context.Context, tx pgx.Tx, tasks []delivery.Task) ([]outboxMessage, error) {
+	if len(tasks) == 0 {
+		return nil, nil
+	}
+
+	publishedAt := time.Now().UTC().Format(time.RFC3339)
+	deliveryIDs := make([]string, len(tasks))
+	topics := make([]string, len(tasks))
+	bodies := make([][]byte, len(tasks))
+	byDelivery := make(map[string]int, len(tasks))
+	for i := range tasks {
+		tasks[i].PublishedAt = publishedAt
+		b, err := json.Marshal(tasks[i])
+		if err != nil {
+			return nil, fmt.Errorf("encode task %s: %w", tasks[i].DeliveryID, err)
+		}
+		deliveryIDs[i], topics[i], bodies[i] = tasks[i].DeliveryID, taskTopic(tasks[i]), b
+		byDelivery[tasks[i].DeliveryID] = i
+	}
+
+	rows, err := tx.Query(ctx, `
+		INSERT INTO harborhook.delivery_outbox(delivery_id, topic, body)
+		SELECT t.delivery_id, t.topic, t.body
+		FROM unnest($1::uuid[], $2::text[], $3::bytea[]) AS t(delivery_id, topic, body)
+		RETURNING id, delivery_id::text`,
+		deliveryIDs, topics, bodies)
+	if err != nil {
+		return nil, fmt.Errorf("insert outbox: %w", err)
+	}
+	defer rows.Close()
+
+	msgs := make([]outboxMessage, 0, len(tasks))
+	for rows.Next() {
+		var (
+			id         int64
+			deliveryID string
+		)
+		if err := rows.Scan(&id, &deliveryID); err != nil {
+			return nil, err
+		}
+		i := byDelivery[deliveryID]
+		msgs = append(msgs, outboxMessage{id: id, topic: topics[i], body: bodies[i]})
+	}
+	return msgs, rows.Err()
+}
+
+// publishOutbox publishes msgs in one batch per topic and waits for every acknowledgement.
+// It returns the ids the broker accepted and the error for each one it did not.
+func (s *Server) publishOutbox(msgs []outboxMessage) ([]int64, map[int64]error) {
+	failed := map[int64]error{}
+	sent := make([]int64, 0, len(msgs))
+
+	var topics []string
+	byTopic := map[string][]outboxMessage{}
+	for _, m := range msgs {
+		if _, ok := byTopic[m.topic]; !ok {
+			topics = append(topics, m.topic)
+		}
+		byTopic[m.topic] = append(byTopic[m.topic], m)
+	}
+	for _, topic := range topics {
+		batch := byTopic[topic]
+		bodies := make([][]byte, len(batch))
+		for i, m := range batch {
+			bodies[i] = m.body
+		}
+		for i, err := range s.prod.PublishBatch(topic, bodies) {
+			if err != nil {
+				failed[batch[i].id] = err
+				continue
+			}
+			sent = append(sent, batch[i].id)
+		}
+	}
+	return sent, failed
+}
+
+// sendOutbox publishes freshly committed outbox rows and marks the accepted ones sent.
+// Rows that fail stay unsent for the relay; the deliveries themselves are already durable.
+func (s *Server) sendOutbox(ctx context.Context, msgs []outboxMessage) int {
+	if len(msgs) == 0 {
+		return 0
+	}
+	sent, failed := s.publishOutbox(msgs)
+	metrics.RecordOutboxPublishes("inline", "sent", len(sent))
+	metrics.RecordOutboxPublishes("inline", "failed", len(failed))
+
+	if len(sent) > 0 {
+		if _, err := s.pool.Exec(ctx, `
+			UPDATE harborhook.delivery_outbox
+			SET sent_at = now(), attempts = attempts + 1
+			WHERE id = ANY($1) AND sent_at IS NULL`, sent); err != nil {
+			// The relay will publish these again; workers already tolerate duplicate tasks
+			tracing.SetSpanError(ctx, err)
+		}
+	}
+	if len(failed) > 0 {
+		tracing.AddSpanEvent(ctx, "outbox.publish_deferred", attribute.Int("task_count", len(failed)))
+	}
+
+	// One event per topic the batch was routed to, in the order the tasks were written
+	var topics []string
+	perTopic := map[string]int{}
+	sentIDs := make(map[int64]bool, len(sent))
+	for _, id := range sent {
+		sentIDs[id] = true
+	}
+	for _, m := range msgs {
+		if !sentIDs[m.id] {
+			continue
+		}
+		if perTopic[m.topic] == 0 {
+			topics = append(topics, m.topic)
+		}
+		perTopic[m.topic]++
+	}
+	for _, topic := range topics {
+		tracing.AddSpanEvent(ctx, "nsq.published_tasks",
+			attribute.Int("task_count", perTopic[topic]),
+			attribute.String("topic", topic))
+	}
+	return len(sent)
+}
+
+// RelayOutbox publishes outbox rows the inline path did not send and marks them sent, then
+// purges old sent rows. Rows are claimed with SKIP LOCKED so ingest replicas can relay together.
+// It returns how many rows were sent.
+func (s *Server) RelayOutbox(ctx context.Context) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, topic, body
+		FROM harborhook.delivery_outbox
+		WHERE sent_at IS NULL AND created_at <= now() - make_interval(secs => $1)
+		ORDER BY id
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED`,
+		outboxRelayGrace.Seconds(), outboxRelayBatch)
+	if err != nil {
+		return 0, fmt.Errorf("claim outbox: %w", err)
+	}
+	var msgs []outboxMessage
+	for rows.Next() {
+		var m outboxMessage
+		if err := rows.Scan(&m.id, &m.topic, &m.body); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		msgs = append(msgs, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	sent, failed := s.publishOutbox(msgs)
+	if len(sent) > 0 {
+		if _, err := tx.Exec(ctx, `
+			UPDATE harborhook.delivery_outbox
+			SET sent_at = now(), attempts = attempts + 1
+			WHERE id = ANY($1)`, sent); err != nil {
+			return 0, fmt.Errorf("mark outbox sent: %w", err)
+		}
+	}
+	for id, pubErr := range failed {
+		if _, err := tx.Exec(ctx, `
+			UPDATE harborhook.delivery_outbox
+			SET attempts = attempts + 1, last_error = $2
+			WHERE id = $1`, id, pubErr.Error()); err != nil {
+			return 0, fmt.Errorf("record outbox failure: %w", err)
+		}
+	}
+	if _, err := tx.Exec(ctx, `
+		DELETE FROM harborhook.delivery_outbox
+		WHERE sent_at < now() - make_interval(secs => $1)`, outboxRetention.Seconds()); err != nil {
+		return 0, fmt.Errorf("purge outbox: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	metrics.RecordOutboxPublishes("relay", "sent", len(sent))
+	metrics.RecordOutboxPublishes("relay", "failed", len(failed))
+	return len(sent), nil
+}
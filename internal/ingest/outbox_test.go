@@ -0,0 +1,34 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+)
+
+func TestWriteOutbox_NoTasks(t *testing.T) {
+	// Events without subscribers write no outbox rows, so no transaction is needed
+	msgs, err := writeOutbox(context.Background(), nil, nil)
+	if err != nil {
+		t.Fatalf("writeOutbox() unexpected error: %v", err)
+	}
+	if msgs != nil {
+		t.Errorf("writeOutbox() = %v, want nil", msgs)
+	}
+}
+
+func TestSendOutbox_Empty(t *testing.T) {
+	server := &Server{}
+
+	if got := server.sendOutbox(context.Background(), nil); got != 0 {
+		t.Errorf("sendOutbox() = %d, want 0", got)
+	}
+}
+
+func TestOutboxRelayTiming(t *testing.T) {
+	if outboxRelayGrace >= outboxRetention {
+		t.Errorf("outboxRelayGrace %v must be shorter than outboxRetention %v", outboxRelayGrace, outboxRetention)
+	}
+	if outboxRelayBatch <= 0 {
+		t.Errorf("outboxRelayBatch = %d, want > 0", outboxRelayBatch)
+	}
+}
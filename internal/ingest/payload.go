@@ -0,0 +1,121 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/blobstore"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetMaxPayloadBytes rejects published events whose JSON payload is larger than n bytes.
+// It also bounds what the blob store holds for the claim check. 0 removes the limit.
+func (s *Server) SetMaxPayloadBytes(n int) {
+	s.maxPayload = n
+}
+
+// checkPayloadSize rejects a marshalled payload over the limit
+func (s *Server) checkPayloadSize(payloadJSON []byte) error {
+	if s.maxPayload > 0 && len(payloadJSON) > s.maxPayload {
+		return status.Errorf(codes.InvalidArgument, "payload is %d bytes, over the %d byte limit", len(payloadJSON), s.maxPayload)
+	}
+	return nil
+}
+
+// SetClaimCheck stores payloads larger than thresholdBytes in store and publishes tasks that
+// carry only a reference, keeping queue messages small. 0 keeps every payload in its tasks.
+func (s *Server) SetClaimCheck(store blobstore.Store, thresholdBytes int) {
+	s.blobs, s.claimCheckBytes = store, thresholdBytes
+}
+
+// claimCheck decides how tasks carry an event's canonical payload: inline, or as a reference
+// returned by the blob store when it is over the claim-check threshold
+func (s *Server) claimCheck(ctx context.Context, tenantID, eventID string, payload json.RawMessage) (json.RawMessage, string, error) {
+	if s.blobs == nil || s.claimCheckBytes <= 0 || len(payload) <= s.claimCheckBytes {
+		return payload, "", nil
+	}
+	ref, err := s.blobs.Put(ctx, tenantID, eventID, payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("store payload of event %s: %w", eventID, err)
+	}
+	return nil, ref, nil
+}
+
+// storedPayload decides how a task built from the database carries its event's payload, read
+// back as jsonb text. A delivery with a payload patch (a replay's override) carries the patched
+// payload inline, since the claim-check store holds the event's own payload.
+func (s *Server) storedPayload(ctx context.Context, tenantID, eventID, payloadJSON, patch string) (json.RawMessage, string, error) {
+	payload, err := delivery.CanonicalPayload([]byte(payloadJSON))
+	if err != nil {
+		return nil, "", fmt.Errorf("payload of event %s: %w", eventID, err)
+	}
+	if patch == "" {
+		return s.claimCheck(ctx, tenantID, eventID, payload)
+	}
+	if payload, err = delivery.MergePatch(payload, json.RawMessage(patch)); err != nil {
+		return nil, "", fmt.Errorf("patch payload of event %s: %w", eventID, err)
+	}
+	if err := s.checkPayloadSize(payload); err != nil {
+		return nil, "", err
+	}
+	return payload, "", nil
+}
+
+// compressionColumn maps an API compression to its endpoints.compression value
+func compressionColumn(c webhookv1.PayloadCompression) string {
+	if c == webhookv1.PayloadCompression_PAYLOAD_COMPRESSION_GZIP {
+		return delivery.CompressionGzip
+	}
+	return delivery.CompressionNone
+}
+
+// compressionFromColumn maps an endpoints.compression value to the API
+func compressionFromColumn(c string) webhookv1.PayloadCompression {
+	if c == delivery.CompressionGzip {
+		return webhookv1.PayloadCompression_PAYLOAD_COMPRESSION_GZIP
+	}
+	return webhookv1.PayloadCompression_PAYLOAD_COMPRESSION_NONE
+}
+
+// SetEndpointCompression chooses whether webhook bodies sent to an endpoint are gzip-compressed
+func (s *Server) SetEndpointCompression(ctx context.Context, req *webhookv1.SetEndpointCompressionRequest) (*webhookv1.SetEndpointCompressionResponse, error) {
+	compression := compressionColumn(req.GetCompression())
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET compression = $3
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at`,
+		req.GetEndpointId(), req.GetTenantId(), compression,
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointCompressionResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:          req.GetEndpointId(),
+			TenantId:    req.GetTenantId(),
+			Url:         endpointURL,
+			CreatedAt:   timestamppb.New(createdAt),
+			Compression: compressionFromColumn(compression),
+		},
+	}, nil
+}
@@ -0,0 +1,116 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_PublishEvent_PayloadTooLarge(t *testing.T) {
+	server := &Server{} // rejected before the quota or the database is touched
+	server.SetMaxPayloadBytes(64)
+	payload, _ := structpb.NewStruct(map[string]any{"notes": strings.Repeat("x", 100)})
+
+	_, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{
+		TenantId: "tn_1", EventType: "order.created", Payload: payload,
+	})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "over the 64 byte limit") {
+		t.Errorf("PublishEvent() error = %v, want InvalidArgument over the 64 byte limit", err)
+	}
+}
+
+func TestServer_PublishEvents_PayloadTooLarge(t *testing.T) {
+	server := &Server{}
+	server.SetMaxPayloadBytes(64)
+	large, _ := structpb.NewStruct(map[string]any{"notes": strings.Repeat("x", 100)})
+
+	resp, err := server.PublishEvents(context.Background(), &webhookv1.PublishEventsRequest{
+		TenantId: "tn_1",
+		Events:   []*webhookv1.BatchEvent{{EventType: "order.created", Payload: large}},
+	})
+	if err != nil {
+		t.Fatalf("PublishEvents() unexpected error: %v", err)
+	}
+	r := resp.Results[0]
+	if r.ErrorCode != int32(codes.InvalidArgument) || !strings.Contains(r.Error, "byte limit") {
+		t.Errorf("result = %d %q, want InvalidArgument over the byte limit", r.ErrorCode, r.Error)
+	}
+}
+
+// memBlobs is a blobstore.Store keeping payloads in memory
+type memBlobs map[string][]byte
+
+func (b memBlobs) Put(_ context.Context, _, eventID string, payload []byte) (string, error) {
+	b["mem:"+eventID] = payload
+	return "mem:" + eventID, nil
+}
+
+func (b memBlobs) Get(_ context.Context, ref string) ([]byte, error) { return b[ref], nil }
+
+func TestServer_ClaimCheck(t *testing.T) {
+	blobs := memBlobs{}
+	server := &Server{}
+	server.SetClaimCheck(blobs, 32)
+	payload, ref, err := server.claimCheck(context.Background(), "tn_1", "evt_small", []byte(`{"id":1}`))
+	if err != nil || ref != "" || payload == nil {
+		t.Errorf("claimCheck(small) = %v, %q, %v, want the payload inline", payload, ref, err)
+	}
+
+	largeJSON := []byte(`{"notes":"` + strings.Repeat("x", 64) + `"}`)
+	payload, ref, err = server.claimCheck(context.Background(), "tn_1", "evt_large", largeJSON)
+	if err != nil || ref != "mem:evt_large" || payload != nil {
+		t.Errorf("claimCheck(large) = %v, %q, %v, want only a reference", payload, ref, err)
+	}
+	if string(blobs[ref]) != string(largeJSON) {
+		t.Errorf("stored %q, want the payload JSON", blobs[ref])
+	}
+}
+
+func TestServer_StoredPayload(t *testing.T) {
+	server := &Server{}
+	server.SetClaimCheck(memBlobs{}, 32)
+	stored := `{"notes": "` + strings.Repeat("x", 64) + `", "id": 1}`
+
+	payload, ref, err := server.storedPayload(context.Background(), "tn_1", "evt_1", stored, "")
+	if err != nil || ref != "mem:evt_1" || payload != nil {
+		t.Errorf("storedPayload(no patch) = %s, %q, %v, want the event's payload by reference", payload, ref, err)
+	}
+	payload, ref, err = server.storedPayload(context.Background(), "tn_1", "evt_1", stored, `{"id":2,"env":"staging"}`)
+	want := `{"env":"staging","id":2,"notes":"` + strings.Repeat("x", 64) + `"}`
+	if err != nil || ref != "" || string(payload) != want {
+		t.Errorf("storedPayload(patch) = %s, %q, %v, want the patched payload inline", payload, ref, err)
+	}
+
+	server.SetMaxPayloadBytes(80)
+	if _, _, err := server.storedPayload(context.Background(), "tn_1", "evt_1", stored, `{"more":"`+strings.Repeat("y", 32)+`"}`); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("storedPayload(patch over the limit) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestServer_SetEndpointCompression(t *testing.T) {
+	var stored any
+	server := NewServer(&dbfake.Pool{
+		QueryRowFunc: func(_ string, args []any) pgx.Row {
+			stored = args[2]
+			return dbfake.Row{Values: []any{"https://partner.example/hook", time.Now()}}
+		},
+	}, nil)
+	resp, err := server.SetEndpointCompression(context.Background(), &webhookv1.SetEndpointCompressionRequest{
+		TenantId: "tn_1", EndpointId: "ep_1", Compression: webhookv1.PayloadCompression_PAYLOAD_COMPRESSION_GZIP,
+	})
+	if err != nil {
+		t.Fatalf("SetEndpointCompression() unexpected error: %v", err)
+	}
+	if stored != "gzip" || resp.Endpoint.Compression != webhookv1.PayloadCompression_PAYLOAD_COMPRESSION_GZIP {
+		t.Errorf("stored %v and returned %v, want gzip", stored, resp.Endpoint.Compression)
+	}
+}
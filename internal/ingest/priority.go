@@ -0,0 +1,46 @@
+package ingest
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetPriorityTopics publishes each event's tasks to the delivery topic of its priority instead
+// of the shared deliveries topic. Workers must be consuming the priority topics first.
+func (s *Server) SetPriorityTopics(on bool) {
+	s.priorityTopics = on
+}
+
+// priorityColumn maps an API priority to its events.priority value
+func priorityColumn(p webhookv1.EventPriority) (string, error) {
+	switch p {
+	case webhookv1.EventPriority_EVENT_PRIORITY_UNSPECIFIED, webhookv1.EventPriority_EVENT_PRIORITY_NORMAL:
+		return delivery.PriorityNormal, nil
+	case webhookv1.EventPriority_EVENT_PRIORITY_HIGH:
+		return delivery.PriorityHigh, nil
+	case webhookv1.EventPriority_EVENT_PRIORITY_LOW:
+		return delivery.PriorityLow, nil
+	default:
+		return "", status.Errorf(codes.InvalidArgument, "unknown priority %d", p)
+	}
+}
+
+// taskPriority is the priority an event's tasks carry: the event's own with priority topics
+// enabled, otherwise none, which keeps them on the deliveries topic
+func (s *Server) taskPriority(priority string) string {
+	if !s.priorityTopics {
+		return ""
+	}
+	return priority
+}
+
+// taskTopic is the topic t is published to
+func taskTopic(t delivery.Task) string {
+	if t.Priority == "" {
+		return deliveriesTopic
+	}
+	return delivery.PriorityTopic(deliveriesTopic, t.Priority)
+}
@@ -0,0 +1,69 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_PublishEvent_Priority(t *testing.T) {
+	payload, _ := structpb.NewStruct(map[string]any{"order_id": "ord_1"})
+	tests := []struct {
+		name       string
+		enabled    bool
+		priority   webhookv1.EventPriority
+		wantStored string
+		wantTopic  string
+	}{
+		{name: "disabled", priority: webhookv1.EventPriority_EVENT_PRIORITY_HIGH, wantStored: "high", wantTopic: "deliveries"},
+		{name: "high", enabled: true, priority: webhookv1.EventPriority_EVENT_PRIORITY_HIGH, wantStored: "high", wantTopic: "deliveries_high"},
+		{name: "low", enabled: true, priority: webhookv1.EventPriority_EVENT_PRIORITY_LOW, wantStored: "low", wantTopic: "deliveries_low"},
+		{name: "unspecified", enabled: true, wantStored: "normal", wantTopic: "deliveries_normal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pool := fanoutPool(2)
+			var stored any
+			fanout := pool.QueryRowFunc
+			pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+				if strings.Contains(sql, "INSERT INTO harborhook.events") {
+					stored = args[len(args)-1]
+				}
+				return fanout(sql, args)
+			}
+			prod := &recordingPublisher{}
+			server := NewServer(pool, prod)
+			server.SetPriorityTopics(tt.enabled)
+
+			if _, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{
+				TenantId: "tn_1", EventType: "order.created", Payload: payload, Priority: tt.priority,
+			}); err != nil {
+				t.Fatalf("PublishEvent() unexpected error: %v", err)
+			}
+			if stored != tt.wantStored {
+				t.Errorf("stored priority = %v, want %q", stored, tt.wantStored)
+			}
+			if len(prod.topics) != 2 || prod.topics[0] != tt.wantTopic || prod.topics[1] != tt.wantTopic {
+				t.Errorf("published to %v, want both tasks on %s", prod.topics, tt.wantTopic)
+			}
+		})
+	}
+}
+
+func TestServer_PublishEvent_UnknownPriority(t *testing.T) {
+	payload, _ := structpb.NewStruct(map[string]any{"order_id": "ord_1"})
+	server := NewServer(fanoutPool(1), &recordingPublisher{})
+	_, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{
+		TenantId: "tn_1", EventType: "order.created", Payload: payload, Priority: webhookv1.EventPriority(9),
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("PublishEvent(priority 9) error = %v, want InvalidArgument", err)
+	}
+}
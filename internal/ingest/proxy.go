@@ -0,0 +1,69 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetEndpointProxy routes an endpoint's deliveries through its own HTTP proxy instead of the
+// egress proxy, or directly with "direct"; empty restores the egress proxy. The proxy must be
+// reachable through the egress guard, like the endpoint itself.
+func (s *Server) SetEndpointProxy(ctx context.Context, req *webhookv1.SetEndpointProxyRequest) (*webhookv1.SetEndpointProxyResponse, error) {
+	proxyURL := req.GetProxyUrl()
+	if proxyURL != "" && proxyURL != delivery.ProxyDirect {
+		if _, err := netguard.ParseProxyURL(proxyURL); err != nil {
+			return nil, apierr.Invalid("%w", err)
+		}
+		if s.egress != nil {
+			if err := s.egress.CheckURL(ctx, proxyURL); err != nil {
+				return nil, apierr.Invalid("proxy url not allowed: %w", err)
+			}
+		}
+	}
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET proxy_url = NULLIF($3, '')
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at`,
+		req.GetEndpointId(), req.GetTenantId(), proxyURL,
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointProxyResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:        req.GetEndpointId(),
+			TenantId:  req.GetTenantId(),
+			Url:       endpointURL,
+			CreatedAt: timestamppb.New(createdAt),
+			ProxyUrl:  redactProxyURL(proxyURL),
+		},
+	}, nil
+}
+
+// redactProxyURL masks the password in a stored proxy URL
+func redactProxyURL(raw string) string {
+	if u, err := url.Parse(raw); err == nil && u.User != nil {
+		return u.Redacted()
+	}
+	return raw
+}
@@ -0,0 +1,91 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// discardPublisher accepts every message without sending it anywhere
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(string, []byte) error { return nil }
+func (discardPublisher) PublishBatch(_ string, bodies [][]byte) []error {
+	return make([]error, len(bodies))
+}
+func (discardPublisher) DeferredPublish(string, time.Duration, []byte) error { return nil }
+func (discardPublisher) Stop()                                               {}
+
+// fanoutPool answers the statements PublishEvent runs for a tenant with n subscribers to the event type
+func fanoutPool(n int) *dbfake.Pool {
+	subs := make([][]any, n)
+	for i := range subs {
+		subs[i] = []any{fmt.Sprintf("sub_%d", i), fmt.Sprintf("ep_%d", i), "https://example.com/hook", []string(nil), []string(nil), nil}
+	}
+	return &dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			switch {
+			case strings.Contains(sql, "INSERT INTO harborhook.events"):
+				return dbfake.Row{Values: []any{"evt_1"}}
+			case strings.Contains(sql, "INSERT INTO harborhook.deliveries"):
+				return dbfake.Row{Values: []any{"del_" + args[1].(string)}}
+			default: // no tenant quota or event schema
+				return dbfake.Row{Err: pgx.ErrNoRows}
+			}
+		},
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			switch {
+			case strings.Contains(sql, "FROM harborhook.subscriptions"):
+				return dbfake.NewRows(subs...), nil
+			case strings.Contains(sql, "INSERT INTO harborhook.delivery_outbox"):
+				ids := args[0].([]string)
+				rows := make([][]any, len(ids))
+				for i, id := range ids {
+					rows[i] = []any{int64(i + 1), id}
+				}
+				return dbfake.NewRows(rows...), nil
+			}
+			return nil, fmt.Errorf("unexpected query: %s", sql)
+		},
+	}
+}
+
+func BenchmarkPublishEvent(b *testing.B) {
+	payload, err := structpb.NewStruct(map[string]any{
+		"order_id": "ord_123",
+		"amount":   4999,
+		"customer": map[string]any{"id": "cus_456", "email": "jane@example.com"},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	req := &webhookv1.PublishEventRequest{
+		TenantId:  "tn_bench",
+		EventType: "order.created",
+		Payload:   payload,
+	}
+
+	for _, n := range []int{1, 10, 1000} {
+		b.Run(fmt.Sprintf("subscribers=%d", n), func(b *testing.B) {
+			server := NewServer(fanoutPool(n), discardPublisher{})
+			b.ReportAllocs()
+			for b.Loop() {
+				resp, err := server.PublishEvent(context.Background(), req)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if resp.FanoutCount != int32(n) {
+					b.Fatalf("FanoutCount = %d, want %d", resp.FanoutCount, n)
+				}
+			}
+		})
+	}
+}
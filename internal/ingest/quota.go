@@ -0,0 +1,140 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Quota names used as the metric label
+const (
+	quotaEventsPerMinute = "events_per_minute"
+	quotaFanout          = "fanout"
+)
+
+// tenantQuota is a tenant's publishing limits; zero means unlimited
+type tenantQuota struct {
+	eventsPerMinute int32
+	maxFanout       int32
+}
+
+// checkFanout rejects an event that would fan out to more endpoints than the tenant allows
+func (q tenantQuota) checkFanout(fanout int) error {
+	if q.maxFanout > 0 && fanout > int(q.maxFanout) {
+		return status.Errorf(codes.ResourceExhausted, "event fans out to %d endpoints, over the tenant limit of %d", fanout, q.maxFanout)
+	}
+	return nil
+}
+
+// enforceQuota checks a publish against the tenant's quotas and counts it towards the current minute.
+// Tenants without a quota row are not limited.
+func (s *Server) enforceQuota(ctx context.Context, tenantID, eventType string) error {
+	var q tenantQuota
+	err := s.pool.QueryRow(ctx, `
+		SELECT events_per_minute, max_fanout FROM harborhook.tenant_quotas WHERE tenant_id = $1`,
+		tenantID).Scan(&q.eventsPerMinute, &q.maxFanout)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load tenant quota: %w", err)
+	}
+
+	if q.maxFanout > 0 {
+		var fanout int
+		if err := s.pool.QueryRow(ctx, `
+			SELECT count(*) FROM harborhook.subscriptions WHERE tenant_id = $1 AND event_type = $2`,
+			tenantID, eventType).Scan(&fanout); err != nil {
+			return fmt.Errorf("count subscribers: %w", err)
+		}
+		if err := q.checkFanout(fanout); err != nil {
+			metrics.RecordQuotaRejection(tenantID, quotaFanout)
+			tracing.AddSpanEvent(ctx, "quota.rejected", attribute.String("quota", quotaFanout))
+			return err
+		}
+	}
+
+	if q.eventsPerMinute > 0 {
+		// Take a slot in the current minute; no row is updated once the minute is used up
+		tag, err := s.pool.Exec(ctx, `
+			UPDATE harborhook.tenant_quotas
+			SET window_events = CASE WHEN window_start < date_trunc('minute', now()) THEN 1 ELSE window_events + 1 END,
+			    window_start = date_trunc('minute', now())
+			WHERE tenant_id = $1
+			  AND (window_start < date_trunc('minute', now()) OR window_events < events_per_minute)`,
+			tenantID)
+		if err != nil {
+			return fmt.Errorf("count quota usage: %w", err)
+		}
+		if tag.RowsAffected() == 0 {
+			metrics.RecordQuotaRejection(tenantID, quotaEventsPerMinute)
+			tracing.AddSpanEvent(ctx, "quota.rejected", attribute.String("quota", quotaEventsPerMinute))
+			return status.Errorf(codes.ResourceExhausted, "tenant %s exceeded its quota of %d events per minute", tenantID, q.eventsPerMinute)
+		}
+	}
+	return nil
+}
+
+// SetTenantQuota sets a tenant's publishing quotas. Only the admin tenant may change quotas.
+func (s *Server) SetTenantQuota(ctx context.Context, req *webhookv1.SetTenantQuotaRequest) (*webhookv1.SetTenantQuotaResponse, error) {
+	q := req.GetQuota()
+	if err := s.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var (
+		saved     webhookv1.TenantQuota
+		updatedAt time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.tenant_quotas(tenant_id, events_per_minute, max_fanout)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET events_per_minute = EXCLUDED.events_per_minute, max_fanout = EXCLUDED.max_fanout, updated_at = now()
+		RETURNING tenant_id, events_per_minute, max_fanout, updated_at
+	`, q.GetTenantId(), q.GetEventsPerMinute(), q.GetMaxFanout()).Scan(&saved.TenantId, &saved.EventsPerMinute, &saved.MaxFanout, &updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("save tenant quota: %w", err)
+	}
+	saved.UpdatedAt = timestamppb.New(updatedAt)
+
+	tracing.AddSpanEvent(ctx, "admin.tenant_quota_set",
+		attribute.Int("events_per_minute", int(saved.EventsPerMinute)),
+		attribute.Int("max_fanout", int(saved.MaxFanout)))
+	return &webhookv1.SetTenantQuotaResponse{Quota: &saved}, nil
+}
+
+// GetTenantQuota returns a tenant's publishing quotas and how much of this minute's quota is used
+func (s *Server) GetTenantQuota(ctx context.Context, req *webhookv1.GetTenantQuotaRequest) (*webhookv1.GetTenantQuotaResponse, error) {
+	quota := &webhookv1.TenantQuota{TenantId: req.GetTenantId()}
+	var (
+		used      int32
+		updatedAt time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		SELECT events_per_minute, max_fanout, updated_at,
+		       CASE WHEN window_start >= date_trunc('minute', now()) THEN window_events ELSE 0 END
+		FROM harborhook.tenant_quotas WHERE tenant_id = $1`,
+		req.GetTenantId()).Scan(&quota.EventsPerMinute, &quota.MaxFanout, &updatedAt, &used)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &webhookv1.GetTenantQuotaResponse{Quota: quota}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load tenant quota: %w", err)
+	}
+	quota.UpdatedAt = timestamppb.New(updatedAt)
+
+	return &webhookv1.GetTenantQuotaResponse{Quota: quota, EventsThisMinute: used}, nil
+}
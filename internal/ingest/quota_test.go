@@ -0,0 +1,54 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTenantQuota_CheckFanout(t *testing.T) {
+	tests := []struct {
+		name        string
+		quota       tenantQuota
+		fanout      int
+		expectError bool
+	}{
+		{name: "unlimited", quota: tenantQuota{}, fanout: 500},
+		{name: "under limit", quota: tenantQuota{maxFanout: 10}, fanout: 9},
+		{name: "at limit", quota: tenantQuota{maxFanout: 10}, fanout: 10},
+		{name: "over limit", quota: tenantQuota{maxFanout: 10}, fanout: 11, expectError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.quota.checkFanout(tt.fanout)
+			if tt.expectError {
+				if status.Code(err) != codes.ResourceExhausted {
+					t.Errorf("checkFanout() = %v, want ResourceExhausted", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("checkFanout() unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestServer_SetTenantQuota_RequiresAdmin(t *testing.T) {
+	server := &Server{}
+	server.SetAdminTenant("ops")
+	ctx := context.WithValue(context.Background(), auth.TenantIDKey, "tn_1")
+
+	_, err := server.SetTenantQuota(ctx, &webhookv1.SetTenantQuotaRequest{
+		Quota: &webhookv1.TenantQuota{TenantId: "tn_1", EventsPerMinute: 1000000},
+	})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("SetTenantQuota() = %v, want PermissionDenied", err)
+	}
+}
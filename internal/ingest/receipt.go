@@ -0,0 +1,67 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// AcknowledgeDelivery records that a receiver finished processing a delivery. Receivers have
+// no token, so the call is authenticated by a receipt signed with the endpoint secret instead.
+// Acknowledging twice is harmless; the first acked_at is kept.
+func (s *Server) AcknowledgeDelivery(ctx context.Context, req *webhookv1.AcknowledgeDeliveryRequest) (*webhookv1.AcknowledgeDeliveryResponse, error) {
+	var (
+		secret  sql.NullString
+		ackedAt sql.NullTime
+	)
+	err := s.pool.QueryRow(ctx, `
+		SELECT ep.secret, d.acked_at
+		FROM harborhook.deliveries d
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		WHERE d.id = $1`, req.GetDeliveryId()).Scan(&secret, &ackedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("delivery %s not found", req.GetDeliveryId())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("lookup delivery: %w", err)
+	}
+	if !secret.Valid || secret.String == "" {
+		return nil, status.Error(codes.FailedPrecondition, "endpoint has no signing secret")
+	}
+	if err := delivery.VerifyReceipt(secret.String, req.GetDeliveryId(), req.GetTimestamp(), req.GetSignature(), time.Now(), delivery.ReceiptLeeway); err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid receipt: %v", err)
+	}
+
+	resp := &webhookv1.AcknowledgeDeliveryResponse{DeliveryId: req.GetDeliveryId()}
+	if ackedAt.Valid {
+		resp.AckedAt, resp.AlreadyAcked = timestamppb.New(ackedAt.Time), true
+		return resp, nil
+	}
+
+	var at time.Time
+	if err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.deliveries
+		SET acked_at = COALESCE(acked_at, now())
+		WHERE id = $1
+		RETURNING acked_at`, req.GetDeliveryId()).Scan(&at); err != nil {
+		return nil, fmt.Errorf("record ack: %w", err)
+	}
+	resp.AckedAt = timestamppb.New(at)
+
+	tracing.AddSpanEvent(ctx, "delivery.acked", attribute.String("delivery_id", req.GetDeliveryId()))
+	return resp, nil
+}
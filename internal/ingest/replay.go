@@ -0,0 +1,181 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const defaultDLQReplayCount = 100
+
+// freshReplayKey is the SQL for a new receiver idempotency key, in the form of
+// delivery.DeriveIdempotencyKey's
+const freshReplayKey = `'` + delivery.IdempotencyKeyPrefix + `' || replace(gen_random_uuid()::text, '-', '')`
+
+// ReplayDLQ replays dead deliveries matching the filters, oldest first.
+// Deliveries that already have a live replay (anything but dead) are skipped, so repeating
+// a bulk replay doesn't fan out duplicates. Each replay gets a new receiver idempotency key unless
+// same_key is set.
+func (s *Server) ReplayDLQ(ctx context.Context, req *webhookv1.ReplayDLQRequest) (*webhookv1.ReplayDLQResponse, error) {
+	maxCount := int32(defaultDLQReplayCount)
+	if req.GetMaxCount() > 0 {
+		maxCount = req.GetMaxCount()
+	}
+	if req.GetFrom() != nil && req.GetTo() != nil && !req.GetFrom().AsTime().Before(req.GetTo().AsTime()) {
+		return nil, apierr.Invalid("from must be before to")
+	}
+
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+
+	args := []any{}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	where := `d.status = 'dead' AND NOT EXISTS (
+			SELECT 1 FROM harborhook.deliveries r WHERE r.replay_of = d.id AND r.status <> 'dead'
+		)`
+	if eid := req.GetEndpointId(); eid != "" {
+		where += " AND d.endpoint_id = " + arg(eid)
+	}
+	if et := req.GetEventType(); et != "" {
+		where += " AND ev.event_type = " + arg(et)
+	}
+	if tenantID != "" {
+		where += " AND ep.tenant_id = " + arg(tenantID)
+	}
+	if req.GetFrom() != nil {
+		where += " AND q.created_at >= " + arg(req.GetFrom().AsTime())
+	}
+	if req.GetTo() != nil {
+		where += " AND q.created_at < " + arg(req.GetTo().AsTime())
+	}
+	from := `
+		FROM harborhook.deliveries d
+		JOIN harborhook.dlq q ON q.delivery_id = d.id
+		JOIN harborhook.events ev ON ev.id = d.event_id
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		WHERE ` + where
+
+	var matched int32
+	if err := s.pool.QueryRow(ctx, `SELECT count(DISTINCT d.id)`+from, args...).Scan(&matched); err != nil {
+		return nil, fmt.Errorf("count dead deliveries: %w", err)
+	}
+	replayCount := min(matched, maxCount)
+
+	tracing.AddSpanEvent(ctx, "dlq.replay_matched",
+		attribute.Int("matched_count", int(matched)),
+		attribute.Bool("dry_run", req.GetDryRun()))
+	if req.GetDryRun() || replayCount == 0 {
+		return &webhookv1.ReplayDLQResponse{
+			MatchedCount:  matched,
+			ReplayedCount: replayCount,
+			DryRun:        req.GetDryRun(),
+		}, nil
+	}
+
+	reason := req.GetReason()
+	if reason == "" {
+		reason = "bulk dlq replay"
+	}
+	rows, err := s.pool.Query(ctx, fmt.Sprintf(`
+		WITH src AS (
+			SELECT d.id, d.event_id, d.endpoint_id, d.subscription_id, d.ordering_key, d.replay_key, min(q.created_at) AS dead_at
+			%s
+			GROUP BY d.id
+			ORDER BY dead_at, d.id
+			LIMIT %d
+		), ins AS (
+			INSERT INTO harborhook.deliveries(event_id, endpoint_id, subscription_id, status, replay_of, replay_reason, ordering_key, replay_key)
+			SELECT event_id, endpoint_id, subscription_id, 'queued', id, %s, ordering_key,
+			       CASE WHEN %s THEN replay_key ELSE %s END
+			FROM src
+			ON CONFLICT DO NOTHING
+			RETURNING id, event_id, endpoint_id, subscription_id, replay_of, ordering_key, replay_key
+		)
+		SELECT ins.id, ins.event_id, ins.endpoint_id, ins.replay_of, ep.tenant_id, ep.url,
+		       ev.event_type, ev.payload::text,
+		       COALESCE(sub.include_fields, '{}'), COALESCE(sub.exclude_fields, '{}'), ins.ordering_key IS NOT NULL,
+		       ev.priority, COALESCE(ins.replay_key, '')
+		FROM ins
+		JOIN harborhook.events ev ON ev.id = ins.event_id
+		JOIN harborhook.endpoints ep ON ep.id = ins.endpoint_id
+		LEFT JOIN harborhook.subscriptions sub ON sub.id = ins.subscription_id`,
+		from, replayCount, arg(reason), arg(req.GetSameKey()), freshReplayKey), args...)
+	if err != nil {
+		return nil, fmt.Errorf("insert replays: %w", err)
+	}
+
+	var (
+		tasks    []delivery.Task
+		replayed []*webhookv1.DeliveryAttempt
+	)
+	for rows.Next() {
+		var (
+			t           delivery.Task
+			replayOf    string
+			payloadJSON string
+			priority    string
+		)
+		if err := rows.Scan(&t.DeliveryID, &t.EventID, &t.EndpointID, &replayOf, &t.TenantID, &t.EndpointURL,
+			&t.EventType, &payloadJSON, &t.IncludeFields, &t.ExcludeFields, &t.Ordered, &priority, &t.ReplayKey); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		t.Priority = s.taskPriority(priority)
+		if t.Payload, t.PayloadRef, err = s.storedPayload(ctx, t.TenantID, t.EventID, payloadJSON, ""); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		tasks = append(tasks, t)
+		replayed = append(replayed, &webhookv1.DeliveryAttempt{
+			DeliveryId: t.DeliveryID,
+			EventId:    t.EventID,
+			EndpointId: t.EndpointID,
+			ReplayOf:   replayOf,
+			Status:     webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_QUEUED,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	changes := make([]changefeed.Change, 0, len(tasks))
+	for _, t := range tasks {
+		changes = append(changes, changefeed.FromTask(t, "", "queued"))
+	}
+	s.feed.Publish(changes...)
+
+	traceHeaders := tracing.PropagateTraceToNSQ(ctx)
+	for _, t := range tasks {
+		t.PublishedAt = time.Now().UTC().Format(time.RFC3339)
+		t.TraceHeaders = traceHeaders
+		b, _ := json.Marshal(t)
+		if err := s.prod.Publish(taskTopic(t), b); err != nil {
+			tracing.SetSpanError(ctx, err)
+			return nil, fmt.Errorf("nsq publish: %w", err)
+		}
+	}
+
+	tracing.AddSpanEvent(ctx, "nsq.published_replays",
+		attribute.Int("task_count", len(tasks)),
+		attribute.String("topic", deliveriesTopic))
+	return &webhookv1.ReplayDLQResponse{
+		MatchedCount:  matched,
+		ReplayedCount: int32(len(tasks)),
+		Replayed:      replayed,
+	}, nil
+}
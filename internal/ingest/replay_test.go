@@ -0,0 +1,42 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestServer_ReplayDLQ_Validation(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		request  *webhookv1.ReplayDLQRequest
+		errorMsg string
+	}{
+		{
+			name: "from after to",
+			request: &webhookv1.ReplayDLQRequest{
+				From: timestamppb.New(now),
+				To:   timestamppb.New(now.Add(-time.Hour)),
+			},
+			errorMsg: "from must be before to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &Server{}
+
+			_, err := server.ReplayDLQ(context.Background(), tt.request)
+			if err == nil {
+				t.Fatal("ReplayDLQ() expected error but got none")
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("ReplayDLQ() error = %q, want %q", err.Error(), tt.errorMsg)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/store"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Most due scheduled events one dispatch fans out; the rest wait for the next tick
+const scheduledDispatchBatch = 100
+
+// scheduledAt resolves a publish's publish_at. It returns nil, publishing right away, when the
+// field is unset or not in the future.
+func scheduledAt(publishAt *timestamppb.Timestamp, now time.Time) (*time.Time, error) {
+	if publishAt == nil {
+		return nil, nil
+	}
+	if err := publishAt.CheckValid(); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid publish_at: %v", err)
+	}
+	at := publishAt.AsTime()
+	if !at.After(now) {
+		return nil, nil
+	}
+	return &at, nil
+}
+
+// scheduleEvent stores an event as scheduled for publishAt without fanning it out. A repeated
+// idempotency key returns the event already stored under it.
+func (s *Server) scheduleEvent(ctx context.Context, req *webhookv1.PublishEventRequest, payloadJSON []byte, publishAt time.Time, deliverBy *time.Time, priority string) (*webhookv1.PublishEventResponse, error) {
+	tracing.AddSpanEvent(ctx, "db.insert_event_scheduled", attribute.String("publish_at", publishAt.UTC().Format(time.RFC3339)))
+	eventID, scheduled, duplicate, err := s.store.ScheduleEvent(ctx, store.NewEvent{
+		TenantID:       req.GetTenantId(),
+		EventType:      req.GetEventType(),
+		PayloadJSON:    payloadJSON,
+		IdempotencyKey: req.GetIdempotencyKey(),
+		DeliverBy:      deliverBy,
+		Priority:       priority,
+	}, publishAt)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate {
+		return s.duplicateResponse(ctx, eventID)
+	}
+	return &webhookv1.PublishEventResponse{EventId: eventID, Scheduled: scheduled}, nil
+}
+
+// DispatchScheduled fans out scheduled events whose publish_at has come, the same way a publish
+// does, and marks them published. They take their place in publish order (events.seq) now rather
+// than when they were scheduled. Events are claimed with SKIP LOCKED so ingest replicas can
+// dispatch together. It returns how many events were dispatched.
+func (s *Server) DispatchScheduled(ctx context.Context) (int, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	type dueEvent struct {
+		id, tenantID, eventType string
+		payloadJSON             string
+		deliverBy               *time.Time
+		priority                string
+	}
+	rows, err := tx.Query(ctx, `
+		SELECT id, tenant_id, event_type, payload::text, deliver_by, priority
+		FROM harborhook.events
+		WHERE status = 'scheduled' AND publish_at <= now()
+		ORDER BY publish_at
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED`,
+		scheduledDispatchBatch)
+	if err != nil {
+		return 0, fmt.Errorf("claim scheduled events: %w", err)
+	}
+	var due []dueEvent
+	for rows.Next() {
+		var ev dueEvent
+		if err := rows.Scan(&ev.id, &ev.tenantID, &ev.eventType, &ev.payloadJSON, &ev.deliverBy, &ev.priority); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		due = append(due, ev)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	var (
+		tasks []delivery.Task
+		ids   = make([]string, 0, len(due))
+	)
+	for _, ev := range due {
+		payload, err := delivery.CanonicalPayload([]byte(ev.payloadJSON))
+		if err != nil {
+			return 0, fmt.Errorf("payload of event %s: %w", ev.id, err)
+		}
+		evTasks, err := s.fanoutEvent(ctx, tx, ev.tenantID, ev.id, ev.eventType, payload, ev.deliverBy, ev.priority, nil)
+		if err != nil {
+			return 0, fmt.Errorf("insert deliveries for event %s: %w", ev.id, err)
+		}
+		tasks = append(tasks, evTasks...)
+		ids = append(ids, ev.id)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE harborhook.events
+		SET status = 'published', seq = nextval(pg_get_serial_sequence('harborhook.events', 'seq'))
+		WHERE id = ANY($1::uuid[])`, ids); err != nil {
+		return 0, fmt.Errorf("mark events published: %w", err)
+	}
+	outbox, err := writeOutbox(ctx, tx, tasks)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+
+	s.sendOutbox(ctx, outbox)
+	changes := make([]changefeed.Change, 0, len(tasks))
+	for _, t := range tasks {
+		changes = append(changes, changefeed.FromTask(t, "", "queued"))
+	}
+	s.feed.Publish(changes...)
+	return len(due), nil
+}
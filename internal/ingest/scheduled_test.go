@@ -0,0 +1,139 @@
+package ingest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_PublishEvent_Scheduled(t *testing.T) {
+	pool := fanoutPool(2)
+	var stored []any
+	fanout := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if strings.Contains(sql, "'scheduled'") {
+			stored = args
+			return dbfake.Row{Values: []any{"evt_later"}}
+		}
+		return fanout(sql, args)
+	}
+	prod := &recordingPublisher{}
+	server := NewServer(pool, prod)
+
+	publishAt := time.Now().Add(time.Hour).UTC()
+	payload, _ := structpb.NewStruct(map[string]any{"order_id": "ord_1"})
+	resp, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{
+		TenantId: "tn_1", EventType: "order.created", Payload: payload,
+		PublishAt: timestamppb.New(publishAt), Ttl: durationpb.New(5 * time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("PublishEvent() unexpected error: %v", err)
+	}
+	if resp.EventId != "evt_later" || !resp.Scheduled || resp.FanoutCount != 0 {
+		t.Errorf("PublishEvent() = %+v, want evt_later scheduled without deliveries", resp)
+	}
+	if len(prod.bodies) != 0 {
+		t.Errorf("published %d tasks for a scheduled event, want none", len(prod.bodies))
+	}
+	if at, ok := stored[5].(time.Time); !ok || !at.Equal(publishAt) {
+		t.Errorf("stored publish_at = %v, want %v", stored[5], publishAt)
+	}
+	// The ttl counts from publish_at, not from when the event was scheduled
+	if deadline, ok := stored[4].(*time.Time); !ok || !deadline.Equal(publishAt.Add(5*time.Minute)) {
+		t.Errorf("stored deliver_by = %v, want publish_at + ttl", stored[4])
+	}
+
+	// A publish_at that has already passed publishes right away
+	resp, err = server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{
+		TenantId: "tn_1", EventType: "order.created", Payload: payload, PublishAt: timestamppb.New(time.Now().Add(-time.Minute)),
+	})
+	if err != nil {
+		t.Fatalf("PublishEvent(past publish_at) unexpected error: %v", err)
+	}
+	if resp.Scheduled || resp.FanoutCount != 2 {
+		t.Errorf("PublishEvent(past publish_at) = %+v, want 2 deliveries now", resp)
+	}
+}
+
+func TestServer_DispatchScheduled(t *testing.T) {
+	deadline := time.Now().Add(time.Hour).UTC()
+	var published []any
+	pool := &dbfake.Pool{
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			switch {
+			case strings.Contains(sql, "status = 'scheduled'"):
+				return dbfake.NewRows(
+					[]any{"evt_1", "tn_1", "order.created", `{"order_id":"ord_1"}`, &deadline, "high"},
+					[]any{"evt_2", "tn_1", "order.paid", `{"order_id": "ord_1", "amount": 10}`, nil, "normal"},
+				), nil
+			case strings.Contains(sql, "INSERT INTO harborhook.deliveries"):
+				return dbfake.NewRows([]any{"del_" + args[0].(string), "ep_1", "https://example.com/hook", []string(nil), []string(nil), false}), nil
+			case strings.Contains(sql, "INSERT INTO harborhook.delivery_outbox"):
+				ids := args[0].([]string)
+				rows := make([][]any, len(ids))
+				for i, id := range ids {
+					rows[i] = []any{int64(i + 1), id}
+				}
+				return dbfake.NewRows(rows...), nil
+			}
+			return nil, fmt.Errorf("unexpected query: %s", sql)
+		},
+		ExecFunc: func(sql string, args []any) (pgconn.CommandTag, error) {
+			if strings.Contains(sql, "SET status = 'published'") {
+				published = append(published, args[0])
+			}
+			return pgconn.CommandTag{}, nil
+		},
+	}
+	prod := &recordingPublisher{}
+	server := NewServer(pool, prod)
+	server.SetPriorityTopics(true)
+
+	n, err := server.DispatchScheduled(context.Background())
+	if err != nil {
+		t.Fatalf("DispatchScheduled() unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("DispatchScheduled() = %d, want 2", n)
+	}
+	if len(published) != 1 || fmt.Sprint(published[0]) != "[evt_1 evt_2]" {
+		t.Errorf("marked published %v, want both events", published)
+	}
+	tasks := map[string]delivery.Task{}
+	topics := map[string]string{}
+	for i, b := range prod.bodies {
+		var task delivery.Task
+		_ = json.Unmarshal(b, &task)
+		tasks[task.EventID] = task
+		topics[task.EventID] = prod.topics[i]
+	}
+	if len(tasks) != 2 || tasks["evt_1"].EventType != "order.created" || string(tasks["evt_2"].Payload) != `{"amount":10,"order_id":"ord_1"}` {
+		t.Errorf("published tasks %+v, want one per event with its payload in canonical form", tasks)
+	}
+	if !tasks["evt_1"].Expired(deadline) || tasks["evt_2"].DeliverBy != "" {
+		t.Errorf("deadlines %q and %q, want only evt_1's", tasks["evt_1"].DeliverBy, tasks["evt_2"].DeliverBy)
+	}
+	// Scheduled events keep the priority they were published with
+	if topics["evt_1"] != "deliveries_high" || topics["evt_2"] != "deliveries_normal" {
+		t.Errorf("published to %v, want evt_1 on deliveries_high and evt_2 on deliveries_normal", topics)
+	}
+
+	// Nothing due: no transaction work beyond the claim
+	pool.QueryFunc = func(string, []any) (pgx.Rows, error) { return dbfake.NewRows(), nil }
+	if n, err := server.DispatchScheduled(context.Background()); n != 0 || err != nil {
+		t.Errorf("DispatchScheduled(nothing due) = %d, %v, want 0", n, err)
+	}
+}
@@ -0,0 +1,254 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/eventschema"
+	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// schemaKey identifies the schema of one tenant's event type
+type schemaKey struct {
+	tenantID  string
+	eventType string
+}
+
+// schemaCache keeps compiled schemas so a publish only compiles one when a new version appears
+type schemaCache struct {
+	mu      sync.Mutex
+	schemas map[schemaKey]compiledSchema
+}
+
+type compiledSchema struct {
+	version int32
+	schema  *eventschema.Schema
+}
+
+func (c *schemaCache) get(key schemaKey) (compiledSchema, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cs, ok := c.schemas[key]
+	return cs, ok
+}
+
+func (c *schemaCache) put(key schemaKey, cs compiledSchema) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.schemas == nil {
+		c.schemas = map[schemaKey]compiledSchema{}
+	}
+	if cs.version > c.schemas[key].version {
+		c.schemas[key] = cs
+	}
+}
+
+// validatePayload checks a payload against the latest schema registered for its event type.
+// Event types without a schema accept any payload.
+func (s *Server) validatePayload(ctx context.Context, tenantID, eventType string, payload map[string]any) error {
+	key := schemaKey{tenantID: tenantID, eventType: eventType}
+	cached, _ := s.schemas.get(key)
+
+	// The schema document only comes back when the cached version is stale
+	var (
+		version int32
+		raw     sql.NullString
+	)
+	err := s.pool.QueryRow(ctx, `
+		SELECT version, CASE WHEN version = $3 THEN NULL ELSE schema::text END
+		FROM harborhook.event_schemas
+		WHERE tenant_id = $1 AND event_type = $2
+		ORDER BY version DESC LIMIT 1`,
+		tenantID, eventType, cached.version).Scan(&version, &raw)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load event schema: %w", err)
+	}
+
+	if version != cached.version || cached.schema == nil {
+		schema, err := eventschema.Compile([]byte(raw.String))
+		if err != nil {
+			return fmt.Errorf("compile schema v%d for %s: %w", version, eventType, err)
+		}
+		cached = compiledSchema{version: version, schema: schema}
+		s.schemas.put(key, cached)
+	}
+
+	violations := cached.schema.Validate(payload)
+	if len(violations) == 0 {
+		return nil
+	}
+	metrics.RecordSchemaRejection(tenantID, eventType)
+	tracing.AddSpanEvent(ctx, "schema.rejected",
+		attribute.Int("schema_version", int(version)),
+		attribute.Int("violations", len(violations)))
+	return schemaViolationError(eventType, version, violations)
+}
+
+// schemaViolationError is an InvalidArgument status listing every violation in its message and
+// as BadRequest field violations, with fields named payload/<JSON pointer>
+func schemaViolationError(eventType string, version int32, violations []eventschema.Violation) error {
+	msgs := make([]string, len(violations))
+	fields := make([]*errdetails.BadRequest_FieldViolation, len(violations))
+	for i, v := range violations {
+		msgs[i] = v.String()
+		fields[i] = &errdetails.BadRequest_FieldViolation{Field: "payload" + v.Path, Description: v.Message}
+	}
+	st := status.Newf(codes.InvalidArgument, "payload does not match schema v%d for %s: %s", version, eventType, strings.Join(msgs, "; "))
+	if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: fields}); err == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// CreateEventSchema registers the next version of an event type's schema. Events published
+// afterwards must match it; events already published are not re-checked.
+func (s *Server) CreateEventSchema(ctx context.Context, req *webhookv1.CreateEventSchemaRequest) (*webhookv1.CreateEventSchemaResponse, error) {
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(req.GetSchema().AsMap())
+	if err != nil {
+		return nil, apierr.Invalid("invalid schema: %w", err)
+	}
+	if _, err := eventschema.Compile(raw); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	// The primary key turns a concurrent registration of the same version into an error
+	// instead of a silent overwrite
+	var (
+		version   int32
+		createdAt time.Time
+	)
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.event_schemas(tenant_id, event_type, version, schema)
+		SELECT $1, $2, COALESCE(max(version), 0) + 1, $3::jsonb
+		FROM harborhook.event_schemas
+		WHERE tenant_id = $1 AND event_type = $2
+		RETURNING version, created_at`,
+		tenantID, req.GetEventType(), string(raw)).Scan(&version, &createdAt)
+	if err != nil {
+		return nil, fmt.Errorf("save event schema: %w", err)
+	}
+
+	tracing.AddSpanEvent(ctx, "schema.created",
+		attribute.String("event_type", req.GetEventType()),
+		attribute.Int("schema_version", int(version)))
+	return &webhookv1.CreateEventSchemaResponse{Schema: &webhookv1.EventSchema{
+		TenantId:  tenantID,
+		EventType: req.GetEventType(),
+		Version:   version,
+		Schema:    req.GetSchema(),
+		CreatedAt: timestamppb.New(createdAt),
+	}}, nil
+}
+
+// ListEventSchemas lists the latest schema of each event type, or every version of one event type
+func (s *Server) ListEventSchemas(ctx context.Context, req *webhookv1.ListEventSchemasRequest) (*webhookv1.ListEventSchemasResponse, error) {
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	if tenantID == "" {
+		return nil, apierr.Invalid("tenant_id is required")
+	}
+
+	query := `
+		SELECT DISTINCT ON (event_type) event_type, version, schema::text, created_at
+		FROM harborhook.event_schemas
+		WHERE tenant_id = $1
+		ORDER BY event_type, version DESC`
+	args := []any{tenantID}
+	if req.GetEventType() != "" {
+		query = `
+			SELECT event_type, version, schema::text, created_at
+			FROM harborhook.event_schemas
+			WHERE tenant_id = $1 AND event_type = $2
+			ORDER BY version DESC`
+		args = append(args, req.GetEventType())
+	}
+	rows, err := s.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list event schemas: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.ListEventSchemasResponse{}
+	for rows.Next() {
+		es := &webhookv1.EventSchema{TenantId: tenantID}
+		if err := scanEventSchema(rows, es); err != nil {
+			return nil, err
+		}
+		resp.Schemas = append(resp.Schemas, es)
+	}
+	return resp, rows.Err()
+}
+
+// GetEventSchema returns one version of an event type's schema; version 0 is the latest
+func (s *Server) GetEventSchema(ctx context.Context, req *webhookv1.GetEventSchemaRequest) (*webhookv1.GetEventSchemaResponse, error) {
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+
+	es := &webhookv1.EventSchema{TenantId: tenantID}
+	err = scanEventSchema(s.pool.QueryRow(ctx, `
+		SELECT event_type, version, schema::text, created_at
+		FROM harborhook.event_schemas
+		WHERE tenant_id = $1 AND event_type = $2 AND ($3 = 0 OR version = $3)
+		ORDER BY version DESC LIMIT 1`,
+		tenantID, req.GetEventType(), req.GetVersion()), es)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if req.GetVersion() > 0 {
+			return nil, status.Errorf(codes.NotFound, "schema v%d for %s not found", req.GetVersion(), req.GetEventType())
+		}
+		return nil, status.Errorf(codes.NotFound, "no schema registered for %s", req.GetEventType())
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &webhookv1.GetEventSchemaResponse{Schema: es}, nil
+}
+
+// scanEventSchema fills es from an event_type, version, schema::text, created_at row
+func scanEventSchema(row pgx.Row, es *webhookv1.EventSchema) error {
+	var (
+		raw       string
+		createdAt time.Time
+	)
+	if err := row.Scan(&es.EventType, &es.Version, &raw, &createdAt); err != nil {
+		return err
+	}
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(raw), &doc); err != nil {
+		return fmt.Errorf("decode schema v%d for %s: %w", es.Version, es.EventType, err)
+	}
+	schema, err := structpb.NewStruct(doc)
+	if err != nil {
+		return fmt.Errorf("decode schema v%d for %s: %w", es.Version, es.EventType, err)
+	}
+	es.Schema = schema
+	es.CreatedAt = timestamppb.New(createdAt)
+	return nil
+}
@@ -0,0 +1,151 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+const orderSchemaJSON = `{"type":"object","required":["order_id"],"properties":{"order_id":{"type":"string"},"amount":{"type":"number","minimum":0}}}`
+
+func TestServer_CreateEventSchema(t *testing.T) {
+	schema, _ := structpb.NewStruct(map[string]any{"type": "object", "propertys": map[string]any{}})
+	if _, err := (&Server{}).CreateEventSchema(context.Background(), &webhookv1.CreateEventSchemaRequest{TenantId: "tn_1", Schema: schema}); err == nil {
+		t.Error("CreateEventSchema(no event_type) expected error")
+	}
+	_, err := (&Server{}).CreateEventSchema(context.Background(), &webhookv1.CreateEventSchemaRequest{
+		TenantId: "tn_1", EventType: "order.created", Schema: schema,
+	})
+	if status.Code(err) != codes.InvalidArgument || !strings.Contains(err.Error(), "/propertys: keyword is not supported") {
+		t.Errorf("CreateEventSchema(unknown keyword) error = %v, want InvalidArgument naming the keyword", err)
+	}
+
+	var stored string
+	server := NewServer(&dbfake.Pool{QueryRowFunc: func(_ string, args []any) pgx.Row {
+		stored = args[2].(string)
+		return dbfake.Row{Values: []any{int32(3), time.Now()}}
+	}}, nil)
+	schema, _ = structpb.NewStruct(map[string]any{"type": "object", "required": []any{"order_id"}})
+	resp, err := server.CreateEventSchema(context.Background(), &webhookv1.CreateEventSchemaRequest{
+		TenantId: "tn_1", EventType: "order.created", Schema: schema,
+	})
+	if err != nil {
+		t.Fatalf("CreateEventSchema() unexpected error: %v", err)
+	}
+	if resp.Schema.Version != 3 || stored != `{"required":["order_id"],"type":"object"}` {
+		t.Errorf("CreateEventSchema() version %d storing %s", resp.Schema.Version, stored)
+	}
+}
+
+func TestServer_GetEventSchema(t *testing.T) {
+	server := NewServer(&dbfake.Pool{QueryRowFunc: func(_ string, args []any) pgx.Row {
+		if args[2] == int32(9) {
+			return dbfake.Row{Err: pgx.ErrNoRows}
+		}
+		return dbfake.Row{Values: []any{"order.created", int32(2), orderSchemaJSON, time.Now()}}
+	}}, nil)
+
+	resp, err := server.GetEventSchema(context.Background(), &webhookv1.GetEventSchemaRequest{TenantId: "tn_1", EventType: "order.created"})
+	if err != nil {
+		t.Fatalf("GetEventSchema() unexpected error: %v", err)
+	}
+	if resp.Schema.Version != 2 || resp.Schema.Schema.Fields["type"].GetStringValue() != "object" {
+		t.Errorf("GetEventSchema() = %v, want v2 with its document", resp.Schema)
+	}
+	_, err = server.GetEventSchema(context.Background(), &webhookv1.GetEventSchemaRequest{TenantId: "tn_1", EventType: "order.created", Version: 9})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetEventSchema(missing version) error = %v, want NotFound", err)
+	}
+}
+
+// schemaPool serves orderSchemaJSON as v1 of order.created and counts how often the document is sent
+func schemaPool(sent *int) *dbfake.Pool {
+	pool := fanoutPool(1)
+	fanout := pool.QueryRowFunc
+	pool.QueryRowFunc = func(sql string, args []any) pgx.Row {
+		if !strings.Contains(sql, "FROM harborhook.event_schemas") {
+			return fanout(sql, args)
+		}
+		if args[1] != "order.created" {
+			return dbfake.Row{Err: pgx.ErrNoRows}
+		}
+		if args[2] == int32(1) {
+			return dbfake.Row{Values: []any{int32(1), nil}}
+		}
+		*sent++
+		return dbfake.Row{Values: []any{int32(1), orderSchemaJSON}}
+	}
+	return pool
+}
+
+func TestServer_PublishEvent_Schema(t *testing.T) {
+	var sent int
+	server := NewServer(schemaPool(&sent), discardPublisher{})
+
+	bad, _ := structpb.NewStruct(map[string]any{"amount": -1})
+	_, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{TenantId: "tn_1", EventType: "order.created", Payload: bad})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument || st.Message() != `payload does not match schema v1 for order.created: missing required property "order_id"; /amount: must be >= 0` {
+		t.Fatalf("PublishEvent(invalid) error = %v", err)
+	}
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, f := range br.GetFieldViolations() {
+				fields = append(fields, f.GetField())
+			}
+		}
+	}
+	if strings.Join(fields, ",") != "payload,payload/amount" {
+		t.Errorf("field violations = %v, want payload and payload/amount", fields)
+	}
+
+	good, _ := structpb.NewStruct(map[string]any{"order_id": "ord_1", "amount": 10})
+	for range 2 {
+		if _, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{TenantId: "tn_1", EventType: "order.created", Payload: good}); err != nil {
+			t.Fatalf("PublishEvent(valid) unexpected error: %v", err)
+		}
+	}
+	if sent != 1 {
+		t.Errorf("schema document loaded %d times, want once while the version is unchanged", sent)
+	}
+
+	// Event types without a schema take any payload
+	if _, err := server.PublishEvent(context.Background(), &webhookv1.PublishEventRequest{TenantId: "tn_1", EventType: "user.created", Payload: bad}); err != nil {
+		t.Errorf("PublishEvent(no schema) unexpected error: %v", err)
+	}
+}
+
+func TestServer_PublishEvents_Schema(t *testing.T) {
+	var sent int
+	server := NewServer(schemaPool(&sent), discardPublisher{})
+	good, _ := structpb.NewStruct(map[string]any{"order_id": "ord_1"})
+	bad, _ := structpb.NewStruct(map[string]any{"order_id": 7})
+
+	resp, err := server.PublishEvents(context.Background(), &webhookv1.PublishEventsRequest{
+		TenantId: "tn_1",
+		Events: []*webhookv1.BatchEvent{
+			{EventType: "order.created", Payload: good},
+			{EventType: "order.created", Payload: bad},
+		},
+	})
+	if err != nil {
+		t.Fatalf("PublishEvents() unexpected error: %v", err)
+	}
+	if resp.PublishedCount != 1 || resp.FailedCount != 1 {
+		t.Fatalf("counts = %d published, %d failed, want 1 and 1", resp.PublishedCount, resp.FailedCount)
+	}
+	if r := resp.Results[1]; codes.Code(r.ErrorCode) != codes.InvalidArgument || !strings.Contains(r.Error, "/order_id: expected string, got number") {
+		t.Errorf("result 1 = %v, want a schema violation", r)
+	}
+}
@@ -2,21 +2,31 @@ package ingest
 
 import (
 	"context"
+	"crypto/ed25519"
 	"crypto/rand"
 	"database/sql"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/url"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/nsqio/go-nsq"
 
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/blobstore"
+	"github.com/austindbirch/harbor_hook/internal/changefeed"
+	"github.com/austindbirch/harbor_hook/internal/compliance"
+	"github.com/austindbirch/harbor_hook/internal/db"
 	"github.com/austindbirch/harbor_hook/internal/delivery"
 	"github.com/austindbirch/harbor_hook/internal/metrics"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
+	"github.com/austindbirch/harbor_hook/internal/notify"
+	"github.com/austindbirch/harbor_hook/internal/queue"
+	"github.com/austindbirch/harbor_hook/internal/store"
 	"github.com/austindbirch/harbor_hook/internal/tracing"
 	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
 
@@ -28,13 +38,61 @@ const deliveriesTopic = "deliveries"
 
 type Server struct {
 	webhookv1.UnimplementedWebhookServiceServer
-	pool *pgxpool.Pool
-	prod *nsq.Producer
+	pool  db.Pool
+	store store.Store // repositories on pool; statements that must share a transaction use store.New(tx)
+	prod  queue.Publisher
+
+	recordings *compliance.Cipher // nil when request recording is not configured
+
+	verifier *endpointVerifier // nil when new endpoints are verified without a challenge
+
+	egress *netguard.Guard // nil when endpoint URLs aren't checked against internal networks
+	// egressCIDRs are the addresses webhooks are sent from, as GetEgressIPs publishes them
+	egressCIDRs []string
+
+	maxPayload int // largest accepted payload in bytes; 0 is unlimited
+
+	blobs           blobstore.Store // nil keeps every payload inside its tasks
+	claimCheckBytes int             // payloads over this many bytes travel as a blob store reference
+
+	schemas schemaCache // compiled event schemas, by tenant and event type
+
+	adminTenant string // tenant whose tokens may use cluster-wide controls
+
+	feed *changefeed.Feed // nil when the changefeed is not configured
+
+	dlqRetention store.DLQRetention // retention reported for tenants without their own
+
+	priorityTopics bool // tasks go to their event priority's topic rather than deliveriesTopic
+
+	mailer *notify.Mailer // nil sends notices only to tenants' ops endpoints
+}
+
+// NewServer inits and returns a new Server struct, containing a webhookv1 Server, a db.Pool, and a queue.Publisher
+func NewServer(pool db.Pool, prod queue.Publisher) *Server {
+	return &Server{pool: pool, store: store.New(pool), prod: prod}
+}
+
+// SetRecordingCipher enables reading compliance recordings sealed with c
+func (s *Server) SetRecordingCipher(c *compliance.Cipher) {
+	s.recordings = c
+}
+
+// SetEgressGuard rejects endpoint URLs that point at internal networks
+func (s *Server) SetEgressGuard(g *netguard.Guard) {
+	s.egress = g
+}
+
+// SetChangefeed publishes delivery state transitions made by the API (new deliveries, replays,
+// drains and resumes) to f
+func (s *Server) SetChangefeed(f *changefeed.Feed) {
+	s.feed = f
 }
 
-// NewServer inits and returns a new Server struct, containing a webhookv1 Server, a pgxpool.Pool, and an nsq.Producer
-func NewServer(pool *pgxpool.Pool, prod *nsq.Producer) *Server {
-	return &Server{pool: pool, prod: prod}
+// SetAdminTenant sets the operator tenant allowed to use cluster-wide controls when an authenticated
+// request carries no role; callers with a role need the admin role instead
+func (s *Server) SetAdminTenant(tenantID string) {
+	s.adminTenant = tenantID
 }
 
 // Ping attempts to ping the server, returning "pong" if successful
@@ -51,14 +109,39 @@ func generateSecret(n int) (string, error) {
 	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
-// CreateEndpoint creates a new webhook endpoint
+// CreateEndpoint creates a new webhook endpoint. A capture endpoint takes no URL and needs no
+// verification, since nothing is sent to it.
 func (s *Server) CreateEndpoint(ctx context.Context, req *webhookv1.CreateEndpointRequest) (*webhookv1.CreateEndpointResponse, error) {
-	// Ensure required fields are present
-	if req.GetTenantId() == "" || req.GetUrl() == "" {
-		return nil, errors.New("tenant_id and url are required")
+	endpointType := endpointTypeColumn(req.GetType())
+	endpointURL := req.GetUrl()
+	switch {
+	case endpointType == delivery.EndpointCapture && endpointURL != "":
+		return nil, apierr.Invalid("url must be empty for capture endpoints")
+	case endpointType == delivery.EndpointCapture:
+		endpointURL = delivery.CaptureURL
+	case endpointURL == "":
+		return nil, apierr.Invalid("url is required")
+	case s.egress != nil:
+		if err := s.egress.CheckURL(ctx, endpointURL); err != nil {
+			return nil, apierr.Invalid("url not allowed: %w", err)
+		}
+	}
+	ramp := req.GetRecoveryRamp()
+	if ramp == nil {
+		ramp = defaultRecoveryRamp()
+	}
+	if err := delivery.ValidateRecoveryRamp(ramp.GetPercents(), ramp.GetStepSeconds()); err != nil {
+		return nil, apierr.Invalid("%w", err)
+	}
+	retry := req.GetRetryPolicy()
+	if retry == nil {
+		retry = &webhookv1.RetryPolicy{}
+	}
+	if err := delivery.ValidateRetryPolicy(retry.GetMaxAttempts(), retry.GetBackoffSeconds(), retry.GetRetryOn()); err != nil {
+		return nil, apierr.Invalid("%w", err)
 	}
-	if _, err := url.ParseRequestURI(req.GetUrl()); err != nil {
-		return nil, fmt.Errorf("invalid url: %w", err)
+	if err := delivery.ValidateTimeout(req.GetTimeoutMs()); err != nil {
+		return nil, apierr.Invalid("%w", err)
 	}
 
 	// Check for secret; if not present, generate one
@@ -71,37 +154,198 @@ func (s *Server) CreateEndpoint(ctx context.Context, req *webhookv1.CreateEndpoi
 		}
 	}
 
+	// ed25519 endpoints sign with the tenant's key, which must exist before the first delivery
+	scheme := signatureSchemeColumn(req.GetSignatureScheme())
+	var signingKey ed25519.PrivateKey
+	if scheme == delivery.SignatureEd25519 {
+		var err error
+		if signingKey, err = s.ensureSigningKey(ctx, req.GetTenantId()); err != nil {
+			return nil, fmt.Errorf("create signing key: %w", err)
+		}
+	}
+
+	// With verification on, the endpoint gets no deliveries until it echoes this token
+	var token string
+	if s.verifier != nil && endpointType != delivery.EndpointCapture {
+		var err error
+		if token, err = generateSecret(24); err != nil {
+			return nil, err
+		}
+	}
+
 	// Insert into database
 	var id string
 	var createdAt time.Time
+	var verifiedAt sql.NullTime
 	// This is some funky formatting, but it makes sense given the db query
 	// In a real system, we'd NEVER return the secret after creation
 	err := s.pool.QueryRow(ctx, `
-		INSERT INTO harborhook.endpoints(tenant_id, url, secret)
-		VALUES ($1, $2, $3)
-		RETURNING id, created_at`,
-		req.GetTenantId(), req.GetUrl(), secret,
-	).Scan(&id, &createdAt)
+		INSERT INTO harborhook.endpoints(tenant_id, url, secret, recovery_ramp_percents, recovery_ramp_step_seconds,
+			retry_max_attempts, retry_backoff_seconds, retry_on, verification_token, verified_at, compression,
+			signature_scheme, timeout_ms, endpoint_type)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9, ''), CASE WHEN $9 = '' THEN now() END, $10, $11, NULLIF($12, 0), $13)
+		RETURNING id, created_at, verified_at`,
+		req.GetTenantId(), endpointURL, secret, nonNilInt32s(ramp.GetPercents()), ramp.GetStepSeconds(),
+		retry.GetMaxAttempts(), nonNilInt32s(retry.GetBackoffSeconds()), nonNilStrings(retry.GetRetryOn()), token,
+		compressionColumn(req.GetCompression()), scheme, req.GetTimeoutMs(), endpointType,
+	).Scan(&id, &createdAt, &verifiedAt)
 	if err != nil {
 		return nil, err
 	}
 
+	// A failed challenge leaves the endpoint unverified; VerifyEndpoint can retry it or take the token
+	var verificationErr string
+	if token != "" {
+		tracing.AddSpanEvent(ctx, "endpoint.challenge")
+		if err := s.verifier.challenge(ctx, endpointURL, secret, signingKey, scheme, token); err != nil {
+			verificationErr = err.Error()
+		} else if at, err := s.markVerified(ctx, id); err != nil {
+			return nil, err
+		} else {
+			verifiedAt = sql.NullTime{Time: at, Valid: true}
+		}
+	}
+
 	// Return API response
 	return &webhookv1.CreateEndpointResponse{
 		Endpoint: &webhookv1.Endpoint{
-			Id:        id,
-			TenantId:  req.GetTenantId(),
-			Url:       req.GetUrl(),
-			CreatedAt: timestamppb.New(createdAt),
+			Id:              id,
+			TenantId:        req.GetTenantId(),
+			Url:             endpointURL,
+			CreatedAt:       timestamppb.New(createdAt),
+			RecoveryRamp:    ramp,
+			RetryPolicy:     retry,
+			VerifiedAt:      toTS(verifiedAt),
+			Compression:     compressionFromColumn(compressionColumn(req.GetCompression())),
+			SignatureScheme: signatureSchemeFromColumn(scheme),
+			TimeoutMs:       req.GetTimeoutMs(),
+			Type:            endpointTypeFromColumn(endpointType),
+		},
+		VerificationError: verificationErr,
+	}, nil
+}
+
+// SetEndpointRecoveryRamp changes how delivery ramps back up after an endpoint recovers
+func (s *Server) SetEndpointRecoveryRamp(ctx context.Context, req *webhookv1.SetEndpointRecoveryRampRequest) (*webhookv1.SetEndpointRecoveryRampResponse, error) {
+	ramp := req.GetRecoveryRamp()
+	if err := delivery.ValidateRecoveryRamp(ramp.GetPercents(), ramp.GetStepSeconds()); err != nil {
+		return nil, apierr.Invalid("%w", err)
+	}
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET recovery_ramp_percents = $3, recovery_ramp_step_seconds = $4
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at`,
+		req.GetEndpointId(), req.GetTenantId(), nonNilInt32s(ramp.GetPercents()), ramp.GetStepSeconds(),
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointRecoveryRampResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:           req.GetEndpointId(),
+			TenantId:     req.GetTenantId(),
+			Url:          endpointURL,
+			CreatedAt:    timestamppb.New(createdAt),
+			RecoveryRamp: ramp,
+		},
+	}, nil
+}
+
+// SetEndpointRetryPolicy overrides the worker's global retry settings for one endpoint
+func (s *Server) SetEndpointRetryPolicy(ctx context.Context, req *webhookv1.SetEndpointRetryPolicyRequest) (*webhookv1.SetEndpointRetryPolicyResponse, error) {
+	retry := req.GetRetryPolicy()
+	if retry == nil {
+		retry = &webhookv1.RetryPolicy{}
+	}
+	if err := delivery.ValidateRetryPolicy(retry.GetMaxAttempts(), retry.GetBackoffSeconds(), retry.GetRetryOn()); err != nil {
+		return nil, apierr.Invalid("%w", err)
+	}
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+		ramp        = &webhookv1.RecoveryRamp{}
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET retry_max_attempts = $3, retry_backoff_seconds = $4, retry_on = $5
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at, recovery_ramp_percents, recovery_ramp_step_seconds`,
+		req.GetEndpointId(), req.GetTenantId(),
+		retry.GetMaxAttempts(), nonNilInt32s(retry.GetBackoffSeconds()), nonNilStrings(retry.GetRetryOn()),
+	).Scan(&endpointURL, &createdAt, &ramp.Percents, &ramp.StepSeconds)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointRetryPolicyResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:           req.GetEndpointId(),
+			TenantId:     req.GetTenantId(),
+			Url:          endpointURL,
+			CreatedAt:    timestamppb.New(createdAt),
+			RecoveryRamp: ramp,
+			RetryPolicy:  retry,
 		},
 	}, nil
 }
 
+// DeleteEndpoint removes an endpoint; its subscriptions and deliveries go with it
+func (s *Server) DeleteEndpoint(ctx context.Context, req *webhookv1.DeleteEndpointRequest) (*webhookv1.DeleteEndpointResponse, error) {
+	// CTEs share one snapshot, so the counts are taken before the cascade runs
+	resp := &webhookv1.DeleteEndpointResponse{}
+	err := s.pool.QueryRow(ctx, `
+		WITH counts AS (
+			SELECT (SELECT count(*) FROM harborhook.subscriptions WHERE endpoint_id = $1) AS subs,
+			       (SELECT count(*) FROM harborhook.deliveries WHERE endpoint_id = $1) AS dels
+		), del AS (
+			DELETE FROM harborhook.endpoints
+			WHERE id = $1 AND tenant_id = $2
+			RETURNING id
+		)
+		SELECT del.id::text, counts.subs, counts.dels FROM del, counts`,
+		req.GetEndpointId(), req.GetTenantId(),
+	).Scan(&resp.EndpointId, &resp.DeletedSubscriptions, &resp.DeletedDeliveries)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	tracing.AddSpanEvent(ctx, "endpoint.deleted",
+		attribute.String("endpoint_id", resp.EndpointId),
+		attribute.Int("deleted_deliveries", int(resp.DeletedDeliveries)))
+	return resp, nil
+}
+
+// defaultRecoveryRamp mirrors delivery.DefaultRecoveryRamp in API form
+func defaultRecoveryRamp() *webhookv1.RecoveryRamp {
+	r := &webhookv1.RecoveryRamp{StepSeconds: int32(delivery.DefaultRecoveryRamp.Step / time.Second)}
+	for _, p := range delivery.DefaultRecoveryRamp.Percents {
+		r.Percents = append(r.Percents, int32(p))
+	}
+	return r
+}
+
 // CreateSubscription creates a new webhook subscription and associates it with an endpoint
 func (s *Server) CreateSubscription(ctx context.Context, req *webhookv1.CreateSubscriptionRequest) (*webhookv1.CreateSubscriptionResponse, error) {
-	// Ensure required fields are present
-	if req.GetTenantId() == "" || req.GetEventType() == "" || req.GetEndpointId() == "" {
-		return nil, errors.New("tenant_id, event_type, and endpoint_id are required")
+	for _, f := range append(req.GetIncludeFields(), req.GetExcludeFields()...) {
+		if !delivery.ValidFieldPath(f) {
+			return nil, apierr.Invalid("invalid field path %q", f)
+		}
 	}
 
 	// Ensure endpoint exists and belongs to tenant
@@ -115,17 +359,18 @@ func (s *Server) CreateSubscription(ctx context.Context, req *webhookv1.CreateSu
 		return nil, err
 	}
 	if !exists {
-		return nil, fmt.Errorf("endpoint %s not found for tenant %s", req.GetEndpointId(), req.GetTenantId())
+		return nil, apierr.NotFound("endpoint %s not found for tenant %s", req.GetEndpointId(), req.GetTenantId())
 	}
 
 	// Insert into database
 	var id string
 	var createdAt time.Time
 	err := s.pool.QueryRow(ctx, `
-		INSERT INTO harborhook.subscriptions(tenant_id, event_type, endpoint_id)
-		VALUES ($1, $2, $3)
+		INSERT INTO harborhook.subscriptions(tenant_id, event_type, endpoint_id, include_fields, exclude_fields)
+		VALUES ($1, $2, $3, $4, $5)
 		RETURNING id, created_at`,
 		req.GetTenantId(), req.GetEventType(), req.GetEndpointId(),
+		nonNilStrings(req.GetIncludeFields()), nonNilStrings(req.GetExcludeFields()),
 	).Scan(&id, &createdAt)
 	if err != nil {
 		return nil, err
@@ -134,17 +379,24 @@ func (s *Server) CreateSubscription(ctx context.Context, req *webhookv1.CreateSu
 	// Return API response
 	return &webhookv1.CreateSubscriptionResponse{
 		Subscription: &webhookv1.Subscription{
-			Id:         id,
-			TenantId:   req.GetTenantId(),
-			EventType:  req.GetEventType(),
-			EndpointId: req.GetEndpointId(),
-			CreatedAt:  timestamppb.New(createdAt),
+			Id:            id,
+			TenantId:      req.GetTenantId(),
+			EventType:     req.GetEventType(),
+			EndpointId:    req.GetEndpointId(),
+			CreatedAt:     timestamppb.New(createdAt),
+			IncludeFields: req.GetIncludeFields(),
+			ExcludeFields: req.GetExcludeFields(),
 		},
 	}, nil
 }
 
 // Publish event publishes an arbitrary JSON payload to all subscribed endpoints
 func (s *Server) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRequest) (*webhookv1.PublishEventResponse, error) {
+	// REST clients may send the idempotency key as a header instead
+	if err := applyIdempotencyKey(ctx, req); err != nil {
+		return nil, err
+	}
+
 	// Start tracing span
 	ctx, span := tracing.StartSpan(ctx, "ingest.PublishEvent",
 		attribute.String("tenant_id", req.GetTenantId()),
@@ -153,100 +405,116 @@ func (s *Server) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRe
 	)
 	defer span.End()
 
-	// Ensure required fields are present
-	if req.GetTenantId() == "" || req.GetEventType() == "" || req.GetPayload() == nil {
-		err := errors.New("tenant_id, event_type, and payload are required")
+	// A deadline given as a ttl counts from when the event goes out, which may be scheduled
+	now := time.Now()
+	publishAt, err := scheduledAt(req.GetPublishAt(), now)
+	if err != nil {
+		tracing.SetSpanError(ctx, err)
+		return nil, err
+	}
+	from := now
+	if publishAt != nil {
+		from = *publishAt
+	}
+	deliverBy, err := deliveryDeadline(req.GetDeliverBy(), req.GetTtl(), from)
+	if err != nil {
+		tracing.SetSpanError(ctx, err)
+		return nil, err
+	}
+	priority, err := priorityColumn(req.GetPriority())
+	if err != nil {
 		tracing.SetSpanError(ctx, err)
 		return nil, err
 	}
+	span.SetAttributes(attribute.String("priority", priority))
 
-	// Insert event
-	var eventID string
-	var fanout int32
 	payloadMap := req.GetPayload().AsMap()
 	// Marshal once, pass as TEXT and cast to ::jsonb in SQL (avoids some driver type ambiguity issues)
 	payloadJSON, err := json.Marshal(payloadMap)
 	if err != nil {
-		return nil, fmt.Errorf("invalid payload: %w", err)
-	}
-
-	// Try insert; if conflict on idempotency, fetch existing id and DO NOT fanout
-	if req.GetIdempotencyKey() != "" {
-		// 1) Insert-or-ignore (no RETURNING here)
-		tracing.AddSpanEvent(ctx, "db.insert_event_idempotent")
-		ct, err := s.pool.Exec(ctx, `
-			INSERT INTO harborhook.events(tenant_id, event_type, payload, idempotency_key)
-			VALUES ($1, $2, $3::jsonb, $4)
-			ON CONFLICT ON CONSTRAINT uq_events_tenant_idem DO NOTHING`,
-			req.GetTenantId(), req.GetEventType(), string(payloadJSON), req.GetIdempotencyKey(),
-		)
+		return nil, apierr.Invalid("invalid payload: %w", err)
+	}
+	if err := s.checkPayloadSize(payloadJSON); err != nil {
+		tracing.SetSpanError(ctx, err)
+		return nil, err
+	}
+	if err := s.validatePayload(ctx, req.GetTenantId(), req.GetEventType(), payloadMap); err != nil {
+		tracing.SetSpanError(ctx, err)
+		return nil, err
+	}
+
+	// Reject before anything is stored when the tenant is over its quota
+	if err := s.enforceQuota(ctx, req.GetTenantId(), req.GetEventType()); err != nil {
+		tracing.SetSpanError(ctx, err)
+		return nil, err
+	}
+
+	// Scheduled events are only stored; DispatchScheduled fans them out once publish_at comes
+	if publishAt != nil {
+		resp, err := s.scheduleEvent(ctx, req, payloadJSON, *publishAt, deliverBy, priority)
 		if err != nil {
 			tracing.SetSpanError(ctx, err)
-			return nil, fmt.Errorf("insert events (idempotent): %w", err)
+			return nil, err
 		}
+		metrics.RecordEventPublished(req.GetTenantId())
+		span.SetAttributes(attribute.String("event_id", resp.EventId), attribute.Bool("scheduled", true))
+		return resp, nil
+	}
 
-		// 2) Fetch the event id whether inserted now or already existed
-		tracing.AddSpanEvent(ctx, "db.select_event_id")
-		if err := s.pool.QueryRow(ctx, `
-			SELECT id FROM harborhook.events
-		 	WHERE tenant_id = $1 AND idempotency_key = $2
-		 	LIMIT 1`,
-			req.GetTenantId(), req.GetIdempotencyKey(),
-		).Scan(&eventID); err != nil {
-			tracing.SetSpanError(ctx, err)
-			return nil, fmt.Errorf("select event id (idempotent): %w", err)
-		}
+	var fanout int32
 
-		// 3) If we did NOT insert now (rows affected == 0), check if deliveries already exist.
-		//    If they do, treat as duplicate publish → no fanout.
-		if ct.RowsAffected() == 0 {
-			tracing.AddSpanEvent(ctx, "db.check_duplicate_deliveries")
-			var existingCount int
-			if err := s.pool.QueryRow(ctx, `
-				SELECT COUNT(*) FROM harborhook.deliveries WHERE event_id = $1`,
-				eventID,
-			).Scan(&existingCount); err != nil {
-				tracing.SetSpanError(ctx, err)
-				return nil, fmt.Errorf("count existing deliveries: %w", err)
-			}
-			if existingCount > 0 {
-				tracing.AddSpanEvent(ctx, "duplicate_event_detected")
-				span.SetAttributes(attribute.String("event_id", eventID))
-				return &webhookv1.PublishEventResponse{
-					EventId:     eventID,
-					FanoutCount: 0,
-				}, nil
-			}
-		}
-	} else {
-		// No idempotency key → always create a new event
-		tracing.AddSpanEvent(ctx, "db.insert_event_new")
-		if err := s.pool.QueryRow(ctx, `
-			INSERT INTO harborhook.events(tenant_id, event_type, payload)
-			VALUES ($1, $2, $3::jsonb)
-			RETURNING id`,
-			req.GetTenantId(), req.GetEventType(), string(payloadJSON),
-		).Scan(&eventID); err != nil {
+	// Event, deliveries and their outbox rows commit together, so a failed NSQ publish
+	// can never leave queued deliveries without a task on the way
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		tracing.SetSpanError(ctx, err)
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Insert the event; an idempotent retry of a publish that already fanned out stops here
+	eventID, duplicate, err := store.New(tx).InsertEvent(ctx, store.NewEvent{
+		TenantID:       req.GetTenantId(),
+		EventType:      req.GetEventType(),
+		PayloadJSON:    payloadJSON,
+		IdempotencyKey: req.GetIdempotencyKey(),
+		DeliverBy:      deliverBy,
+		Priority:       priority,
+	})
+	if err != nil {
+		tracing.SetSpanError(ctx, err)
+		return nil, err
+	}
+	if duplicate {
+		tracing.AddSpanEvent(ctx, "duplicate_event_detected")
+		span.SetAttributes(attribute.String("event_id", eventID), attribute.Bool("duplicate", true))
+		resp, err := s.duplicateResponse(ctx, eventID)
+		if err != nil {
 			tracing.SetSpanError(ctx, err)
-			return nil, fmt.Errorf("insert events (no-idem): %w", err)
+			return nil, err
 		}
+		return resp, nil
 	}
 	
 	// Add event ID to span attributes
 	span.SetAttributes(attribute.String("event_id", eventID))
 
-	// Fetch subscribers + insert deliveries (pending), then enqueue
+	// Fetch subscribers + insert deliveries (queued) and their outbox rows
 	tracing.AddSpanEvent(ctx, "db.query_subscribers")
 	type subRow struct {
-		EndpointID string
-		URL        string
+		SubscriptionID string
+		EndpointID     string
+		URL            string
+		IncludeFields  []string
+		ExcludeFields  []string
+		OrderingKey    sql.NullString
 	}
-	rows, err := s.pool.Query(ctx, `
-		SELECT e.id, e.url
+	rows, err := tx.Query(ctx, `
+		SELECT s.id, e.id, e.url, s.include_fields, s.exclude_fields, `+orderingKeySQL("$3")+`
 		FROM harborhook.subscriptions s
 		JOIN harborhook.endpoints e ON e.id = s.endpoint_id
-		WHERE s.tenant_id = $1 AND s.event_type = $2`,
-		req.GetTenantId(), req.GetEventType(),
+		WHERE s.tenant_id = $1 AND s.event_type = $2 AND e.verified_at IS NOT NULL AND e.disabled_at IS NULL`,
+		req.GetTenantId(), req.GetEventType(), string(payloadJSON),
 	)
 	if err != nil {
 		tracing.SetSpanError(ctx, err)
@@ -258,16 +526,16 @@ func (s *Server) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRe
 	var targets []subRow
 	for rows.Next() {
 		var r subRow
-		if err := rows.Scan(&r.EndpointID, &r.URL); err != nil {
+		if err := rows.Scan(&r.SubscriptionID, &r.EndpointID, &r.URL, &r.IncludeFields, &r.ExcludeFields, &r.OrderingKey); err != nil {
 			return nil, err
 		}
 		targets = append(targets, r)
 		// Create queued delivery
 		batch.Queue(`
-			INSERT INTO harborhook.deliveries(event_id, endpoint_id, status)
-			VALUES ($1, $2, 'queued')
+			INSERT INTO harborhook.deliveries(event_id, endpoint_id, subscription_id, status, ordering_key)
+			VALUES ($1, $2, $3, 'queued', $4)
 			RETURNING id`,
-			eventID, r.EndpointID)
+			eventID, r.EndpointID, r.SubscriptionID, r.OrderingKey)
 	}
 	if err := rows.Err(); err != nil {
 		tracing.SetSpanError(ctx, err)
@@ -277,10 +545,19 @@ func (s *Server) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRe
 	// Add subscriber count to tracing
 	span.SetAttributes(attribute.Int("subscribers_count", len(targets)))
 	
+	var (
+		tasks  []delivery.Task
+		outbox []outboxMessage
+	)
 	if len(targets) > 0 {
+		taskPayload, payloadRef, err := s.claimCheck(ctx, req.GetTenantId(), eventID, payloadJSON)
+		if err != nil {
+			tracing.SetSpanError(ctx, err)
+			return nil, err
+		}
+
 		tracing.AddSpanEvent(ctx, "db.create_deliveries_batch", attribute.Int("delivery_count", len(targets)))
-		br := s.pool.SendBatch(ctx, batch)
-		defer br.Close()
+		br := tx.SendBatch(ctx, batch)
 
 		// Extract trace headers for NSQ propagation
 		traceHeaders := tracing.PropagateTraceToNSQ(ctx)
@@ -288,6 +565,7 @@ func (s *Server) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRe
 		for _, t := range targets {
 			var deliveryID string
 			if err := br.QueryRow().Scan(&deliveryID); err != nil {
+				br.Close()
 				tracing.SetSpanError(ctx, err)
 				return nil, err
 			}
@@ -298,23 +576,46 @@ func (s *Server) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRe
 				EndpointID:   t.EndpointID,
 				EndpointURL:  t.URL,
 				EventType:    req.GetEventType(),
-				Payload:      payloadMap,
+				Payload:      taskPayload,
+				PayloadRef:   payloadRef,
 				Attempt:      0,
-				PublishedAt:  time.Now().UTC().Format(time.RFC3339),
 				TraceHeaders: traceHeaders,
+				Ordered:      t.OrderingKey.Valid,
+				Priority:     s.taskPriority(priority),
+
+				IncludeFields: t.IncludeFields,
+				ExcludeFields: t.ExcludeFields,
 			}
-			b, _ := json.Marshal(task)
-			if err := s.prod.Publish(deliveriesTopic, b); err != nil {
-				tracing.SetSpanError(ctx, err)
-				return nil, fmt.Errorf("nsq publish: %w", err)
+			if deliverBy != nil {
+				task.SetDeadline(*deliverBy)
 			}
-			fanout++
+			tasks = append(tasks, task)
 		}
-		
-		tracing.AddSpanEvent(ctx, "nsq.published_tasks", 
-			attribute.Int("task_count", int(fanout)),
-			attribute.String("topic", deliveriesTopic))
+		if err := br.Close(); err != nil {
+			tracing.SetSpanError(ctx, err)
+			return nil, err
+		}
+
+		tracing.AddSpanEvent(ctx, "db.insert_outbox", attribute.Int("task_count", len(tasks)))
+		if outbox, err = writeOutbox(ctx, tx, tasks); err != nil {
+			tracing.SetSpanError(ctx, err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		tracing.SetSpanError(ctx, err)
+		return nil, fmt.Errorf("commit event: %w", err)
+	}
+	fanout = int32(len(tasks))
+
+	// Enqueue now; anything NSQ rejects is left in the outbox for the relay
+	s.sendOutbox(ctx, outbox)
+	changes := make([]changefeed.Change, 0, len(tasks))
+	for _, t := range tasks {
+		changes = append(changes, changefeed.FromTask(t, "", "queued"))
 	}
+	s.feed.Publish(changes...)
 
 	// Increment Prometheus counter with tenant_id label
 	metrics.RecordEventPublished(req.GetTenantId())
@@ -334,14 +635,21 @@ func (s *Server) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRe
 
 // GetDeliveryStatus returns delivery attempts for a given event, with optional filters
 func (s *Server) GetDeliveryStatus(ctx context.Context, req *webhookv1.GetDeliveryStatusRequest) (*webhookv1.GetDeliveryStatusResponse, error) {
-    if req.GetEventId() == "" {
-        return nil, errors.New("event_id is required")
+    // Callers see only their own tenant's deliveries; admins see any
+    scope, err := scopeTenant(ctx, "")
+    if err != nil {
+        return nil, err
     }
 
     // Build dynamic WHERE clause
     args := []any{req.GetEventId()}
     where := "d.event_id = $1"
     argn := 1
+    if scope != "" {
+        argn++
+        where += fmt.Sprintf(" AND ev.tenant_id = $%d", argn)
+        args = append(args, scope)
+    }
     if eid := req.GetEndpointId(); eid != "" {
         argn++
         where += fmt.Sprintf(" AND d.endpoint_id = $%d", argn)
@@ -364,11 +672,24 @@ func (s *Server) GetDeliveryStatus(ctx context.Context, req *webhookv1.GetDelive
     argn++
     args = append(args, limit)
 
+    // Replays keep the source's event_id, so the whole lineage lives within the event
     q := fmt.Sprintf(`
+        WITH RECURSIVE lineage AS (
+            SELECT id, id AS root_id, 0 AS depth
+            FROM harborhook.deliveries
+            WHERE event_id = $1 AND replay_of IS NULL
+            UNION ALL
+            SELECT c.id, l.root_id, l.depth + 1
+            FROM harborhook.deliveries c
+            JOIN lineage l ON c.replay_of = l.id
+        )
         SELECT d.id, d.event_id, d.endpoint_id, d.replay_of, d.status, d.http_status,
                COALESCE(d.error_reason, d.last_error) AS err,
-               d.enqueued_at, d.dequeued_at, d.sent_at, d.delivered_at, d.failed_at, d.dlq_at
+               d.enqueued_at, d.dequeued_at, d.sent_at, d.delivered_at, d.failed_at, d.dlq_at, d.acked_at,
+               COALESCE(ln.root_id, d.id), COALESCE(ln.depth, 0)
         FROM harborhook.deliveries d
+        JOIN harborhook.events ev ON ev.id = d.event_id
+        LEFT JOIN lineage ln ON ln.id = d.id
         WHERE %s
         ORDER BY d.enqueued_at ASC
         LIMIT $%d`, where, argn)
@@ -387,71 +708,186 @@ func (s *Server) GetDeliveryStatus(ctx context.Context, req *webhookv1.GetDelive
             statusStr sql.NullString
             httpStatus sql.NullInt32
             errReason sql.NullString
-            enq, deq, sent, deliv, fail, dlq sql.NullTime
+            enq, deq, sent, deliv, fail, dlq, acked sql.NullTime
+            rootID string
+            depth int32
         )
         if err := rows.Scan(&id, &eventID, &endpointID, &replayOf, &statusStr, &httpStatus, &errReason,
-            &enq, &deq, &sent, &deliv, &fail, &dlq,
+            &enq, &deq, &sent, &deliv, &fail, &dlq, &acked, &rootID, &depth,
         ); err != nil {
             return nil, err
         }
         out = append(out, &webhookv1.DeliveryAttempt{
-            DeliveryId:  id,
-            EventId:     eventID,
-            EndpointId:  endpointID,
-            ReplayOf:    nullStr(replayOf),
-            Status:      mapStatus(nullStr(statusStr)),
-            HttpStatus:  nullI32(httpStatus),
-            ErrorReason: nullStr(errReason),
-            EnqueuedAt:  toTS(enq),
-            DequeuedAt:  toTS(deq),
-            SentAt:      toTS(sent),
-            DeliveredAt: toTS(deliv),
-            FailedAt:    toTS(fail),
-            DlqAt:       toTS(dlq),
+            DeliveryId:     id,
+            EventId:        eventID,
+            EndpointId:     endpointID,
+            ReplayOf:       nullStr(replayOf),
+            Status:         mapStatus(nullStr(statusStr)),
+            HttpStatus:     nullI32(httpStatus),
+            ErrorReason:    nullStr(errReason),
+            RootDeliveryId: rootID,
+            ReplayDepth:    depth,
+            EnqueuedAt:     toTS(enq),
+            DequeuedAt:     toTS(deq),
+            SentAt:         toTS(sent),
+            DeliveredAt:    toTS(deliv),
+            FailedAt:       toTS(fail),
+            DlqAt:          toTS(dlq),
+            AckedAt:        toTS(acked),
         })
     }
     if err := rows.Err(); err != nil {
         return nil, err
     }
-    return &webhookv1.GetDeliveryStatusResponse{Attempts: out}, nil
+    if err := s.attachAttemptHistory(ctx, out); err != nil {
+        return nil, err
+    }
+    resp := &webhookv1.GetDeliveryStatusResponse{Attempts: out}
+    if req.GetIncludeReplayChains() {
+        resp.ReplayChains = buildReplayChains(out)
+    }
+    return resp, nil
 }
 
-// ReplayDelivery enqueues a new delivery referencing a previous attempt
+// buildReplayChains groups attempts by root delivery, keeping only originals that were replayed.
+// Chains are ordered by first appearance; attempts within a chain by depth, then input order.
+func buildReplayChains(attempts []*webhookv1.DeliveryAttempt) []*webhookv1.ReplayChain {
+    var chains []*webhookv1.ReplayChain
+    byRoot := map[string]*webhookv1.ReplayChain{}
+    for _, a := range attempts {
+        root := a.GetRootDeliveryId()
+        if root == "" {
+            root = a.GetDeliveryId()
+        }
+        c, ok := byRoot[root]
+        if !ok {
+            c = &webhookv1.ReplayChain{RootDeliveryId: root}
+            byRoot[root] = c
+            chains = append(chains, c)
+        }
+        c.Attempts = append(c.Attempts, a)
+        if a.GetReplayOf() != "" && isActiveStatus(a.GetStatus()) {
+            c.ActiveDeliveryId = a.GetDeliveryId()
+        }
+    }
+
+    out := chains[:0]
+    for _, c := range chains {
+        if len(c.Attempts) < 2 && c.Attempts[0].GetReplayDepth() == 0 {
+            continue
+        }
+        sort.SliceStable(c.Attempts, func(i, j int) bool {
+            return c.Attempts[i].GetReplayDepth() < c.Attempts[j].GetReplayDepth()
+        })
+        out = append(out, c)
+    }
+    return out
+}
+
+// isActiveStatus reports whether a delivery in status s may still be sent
+func isActiveStatus(s webhookv1.DeliveryAttemptStatus) bool {
+    switch s {
+    case webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_QUEUED,
+        webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_IN_FLIGHT,
+        webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_FAILED,
+        webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_PARKED:
+        return true
+    }
+    return false
+}
+
+// ReplayDelivery enqueues a new delivery referencing a previous attempt. The replay gets a new
+// receiver idempotency key unless same_key is set, in which case it keeps the source's. A URL
+// override or payload patch is recorded on the replay and applies to it alone.
 func (s *Server) ReplayDelivery(ctx context.Context, req *webhookv1.ReplayDeliveryRequest) (*webhookv1.ReplayDeliveryResponse, error) {
-    if req.GetDeliveryId() == "" {
-        return nil, errors.New("delivery_id is required")
+    // Callers replay only their own tenant's deliveries; admins may replay any
+    scope, err := scopeTenant(ctx, "")
+    if err != nil {
+        return nil, err
+    }
+    overrideURL := req.GetUrlOverride()
+    if overrideURL != "" && scope != "" {
+        // Outside admins, a replay may only be redirected to one of the tenant's own receivers,
+        // so a delivery's payload and signature can't be sent anywhere else
+        var owned bool
+        if err := s.pool.QueryRow(ctx, `
+            SELECT EXISTS (SELECT 1 FROM harborhook.endpoints WHERE tenant_id = $1 AND url = $2)`,
+            scope, overrideURL).Scan(&owned); err != nil {
+            return nil, fmt.Errorf("check url_override: %w", err)
+        }
+        if !owned {
+            return nil, apierr.PermissionDenied("url_override must be the URL of one of your endpoints")
+        }
+    }
+    if overrideURL != "" && s.egress != nil {
+        if err := s.egress.CheckURL(ctx, overrideURL); err != nil {
+            return nil, apierr.Invalid("url_override not allowed: %w", err)
+        }
+    }
+    var patch string
+    if req.GetPayloadPatch() != nil {
+        b, err := json.Marshal(req.GetPayloadPatch().AsMap())
+        if err != nil {
+            return nil, apierr.Invalid("invalid payload_patch: %w", err)
+        }
+        patch = string(b)
     }
 
     // Fetch source delivery + event/endpoint details
     var (
         eventID, endpointID, tenantID, eventType, endpointURL string
         payloadJSON string
+        subscriptionID sql.NullString
+        includeFields, excludeFields []string
+        orderingKey sql.NullString
+        priority string
+        sourceKey string
     )
-    err := s.pool.QueryRow(ctx, `
-        SELECT d.event_id, d.endpoint_id, ev.tenant_id, ev.event_type, ev.payload::text, ep.url
+    err = s.pool.QueryRow(ctx, `
+        SELECT d.event_id, d.endpoint_id, ev.tenant_id, ev.event_type, ev.payload::text, ep.url,
+               d.subscription_id, COALESCE(sub.include_fields, '{}'), COALESCE(sub.exclude_fields, '{}'),
+               d.ordering_key, ev.priority, COALESCE(d.replay_key, '')
         FROM harborhook.deliveries d
         JOIN harborhook.events ev ON ev.id = d.event_id
         JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
-        WHERE d.id = $1
-    `, req.GetDeliveryId()).Scan(&eventID, &endpointID, &tenantID, &eventType, &payloadJSON, &endpointURL)
+        LEFT JOIN harborhook.subscriptions sub ON sub.id = d.subscription_id
+        WHERE d.id = $1 AND ($2 = '' OR ev.tenant_id = $2)
+    `, req.GetDeliveryId(), scope).Scan(&eventID, &endpointID, &tenantID, &eventType, &payloadJSON, &endpointURL,
+        &subscriptionID, &includeFields, &excludeFields, &orderingKey, &priority, &sourceKey)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return nil, apierr.NotFound("source delivery %s not found", req.GetDeliveryId())
+    }
+    if err != nil {
+        return nil, fmt.Errorf("load source delivery: %w", err)
+    }
+    payload, payloadRef, err := s.storedPayload(ctx, tenantID, eventID, payloadJSON, patch)
     if err != nil {
-        return nil, fmt.Errorf("source delivery not found: %w", err)
+        return nil, err
+    }
+    if overrideURL != "" {
+        endpointURL = overrideURL
     }
 
-    // Insert new delivery referencing replay_of
-    var newID string
+    // Insert new delivery referencing replay_of. The active-replay unique index turns a
+    // duplicate into a no-op, in which case the replay already in progress is returned.
+    var newID, replayKey string
     err = s.pool.QueryRow(ctx, `
-        INSERT INTO harborhook.deliveries(event_id, endpoint_id, status, replay_of, replay_reason)
-        VALUES ($1,$2,'queued',$3,$4)
-        RETURNING id
-    `, eventID, endpointID, req.GetDeliveryId(), req.GetReason()).Scan(&newID)
+        INSERT INTO harborhook.deliveries(event_id, endpoint_id, subscription_id, status, replay_of, replay_reason, ordering_key,
+                                          replay_key, override_url, payload_patch)
+        VALUES ($1,$2,$3,'queued',$4,$5,$6, CASE WHEN $7 THEN NULLIF($8, '') ELSE `+freshReplayKey+` END,
+                NULLIF($9, ''), NULLIF($10, '')::jsonb)
+        ON CONFLICT DO NOTHING
+        RETURNING id, COALESCE(replay_key, '')
+    `, eventID, endpointID, subscriptionID, req.GetDeliveryId(), req.GetReason(), orderingKey,
+        req.GetSameKey(), sourceKey, overrideURL, patch).Scan(&newID, &replayKey)
+    if errors.Is(err, pgx.ErrNoRows) {
+        return s.activeReplay(ctx, req.GetDeliveryId())
+    }
     if err != nil {
         return nil, fmt.Errorf("insert replay: %w", err)
     }
 
     // Publish the new task
-    var payload map[string]any
-    _ = json.Unmarshal([]byte(payloadJSON), &payload)
     task := delivery.Task{
         DeliveryID:  newID,
         EventID:     eventID,
@@ -460,11 +896,19 @@ func (s *Server) ReplayDelivery(ctx context.Context, req *webhookv1.ReplayDelive
         EndpointURL: endpointURL,
         EventType:   eventType,
         Payload:     payload,
+        PayloadRef:  payloadRef,
         Attempt:     0,
         PublishedAt: time.Now().UTC().Format(time.RFC3339),
+        Ordered:     orderingKey.Valid,
+        Priority:    s.taskPriority(priority),
+        ReplayKey:   replayKey,
+
+        IncludeFields: includeFields,
+        ExcludeFields: excludeFields,
     }
+    s.feed.Publish(changefeed.FromTask(task, "", "queued"))
     b, _ := json.Marshal(task)
-    if err := s.prod.Publish(deliveriesTopic, b); err != nil {
+    if err := s.prod.Publish(taskTopic(task), b); err != nil {
         return nil, fmt.Errorf("nsq publish: %w", err)
     }
 
@@ -480,75 +924,148 @@ func (s *Server) ReplayDelivery(ctx context.Context, req *webhookv1.ReplayDelive
     }, nil
 }
 
-// ListDLQ returns deliveries present in the DLQ
+// activeReplay returns the replay of sourceID that is still queued, in flight, retrying or parked
+func (s *Server) activeReplay(ctx context.Context, sourceID string) (*webhookv1.ReplayDeliveryResponse, error) {
+    var (
+        id, eventID, endpointID string
+        statusStr sql.NullString
+    )
+    err := s.pool.QueryRow(ctx, `
+        SELECT id, event_id, endpoint_id, status
+        FROM harborhook.deliveries
+        WHERE replay_of = $1 AND status IN ('queued', 'inflight', 'failed', 'parked')
+    `, sourceID).Scan(&id, &eventID, &endpointID, &statusStr)
+    if err != nil {
+        return nil, fmt.Errorf("lookup active replay: %w", err)
+    }
+    return &webhookv1.ReplayDeliveryResponse{
+        NewAttempt: &webhookv1.DeliveryAttempt{
+            DeliveryId: id,
+            EventId:    eventID,
+            EndpointId: endpointID,
+            ReplayOf:   sourceID,
+            Status:     mapStatus(nullStr(statusStr)),
+        },
+        Deduplicated: true,
+    }, nil
+}
+
+// ListDLQ returns deliveries present in the DLQ, newest first.
+// Results are keyset-paginated on (dlq.created_at, dlq.id) so pages stay stable while new entries arrive.
 func (s *Server) ListDLQ(ctx context.Context, req *webhookv1.ListDLQRequest) (*webhookv1.ListDLQResponse, error) {
     limit := int32(10)
     if req.GetLimit() > 0 {
         limit = req.GetLimit()
     }
+    if limit > maxDLQPageSize {
+        limit = maxDLQPageSize
+    }
 
-    args := []any{}
-    where := "1=1"
-    if eid := req.GetEndpointId(); eid != "" {
-        where += " AND d.endpoint_id = $1"
-        args = append(args, eid)
+    var cur *dlqCursor
+    if tok := req.GetPageToken(); tok != "" {
+        c, err := decodeDLQCursor(tok)
+        if err != nil {
+            return nil, err
+        }
+        cur = &c
     }
-    // Use DLQ table ordering
-    q := fmt.Sprintf(`
-        SELECT d.id, d.event_id, d.endpoint_id, d.replay_of, d.status, d.http_status,
-               COALESCE(d.error_reason, d.last_error) AS err,
-               d.enqueued_at, d.dequeued_at, d.sent_at, d.delivered_at, d.failed_at, d.dlq_at
-        FROM harborhook.deliveries d
-        JOIN harborhook.dlq q ON q.delivery_id = d.id
-        WHERE %s
-        ORDER BY q.created_at DESC
-        LIMIT %d`, where, limit)
 
-    rows, err := s.pool.Query(ctx, q, args...)
+    tenantID, err := scopeTenant(ctx, req.GetTenantId())
     if err != nil {
         return nil, err
     }
-    defer rows.Close()
-    var out []*webhookv1.DeliveryAttempt
-    for rows.Next() {
-        var (
-            id, eventID, endpointID string
-            replayOf sql.NullString
-            statusStr sql.NullString
-            httpStatus sql.NullInt32
-            errReason sql.NullString
-            enq, deq, sent, deliv, fail, dlq sql.NullTime
-        )
-        if err := rows.Scan(&id, &eventID, &endpointID, &replayOf, &statusStr, &httpStatus, &errReason,
-            &enq, &deq, &sent, &deliv, &fail, &dlq,
-        ); err != nil {
-            return nil, err
-        }
+
+    f := store.DLQFilter{TenantID: tenantID, EndpointID: req.GetEndpointId(), Limit: limit}
+    if req.GetFrom() != nil {
+        from := req.GetFrom().AsTime()
+        f.From = &from
+    }
+    if req.GetTo() != nil {
+        to := req.GetTo().AsTime()
+        f.To = &to
+    }
+    if cur != nil {
+        f.After = &store.DLQCursor{CreatedAt: cur.createdAt, ID: cur.id}
+    }
+    page, err := s.store.ListDLQ(ctx, f)
+    if err != nil {
+        return nil, err
+    }
+
+    out := make([]*webhookv1.DeliveryAttempt, 0, len(page.Dead))
+    for _, d := range page.Dead {
         out = append(out, &webhookv1.DeliveryAttempt{
-            DeliveryId:  id,
-            EventId:     eventID,
-            EndpointId:  endpointID,
-            ReplayOf:    nullStr(replayOf),
-            Status:      mapStatus(nullStr(statusStr)),
-            HttpStatus:  nullI32(httpStatus),
-            ErrorReason: nullStr(errReason),
-            EnqueuedAt:  toTS(enq),
-            DequeuedAt:  toTS(deq),
-            SentAt:      toTS(sent),
-            DeliveredAt: toTS(deliv),
-            FailedAt:    toTS(fail),
-            DlqAt:       toTS(dlq),
+            DeliveryId:  d.ID,
+            EventId:     d.EventID,
+            EndpointId:  d.EndpointID,
+            ReplayOf:    d.ReplayOf,
+            Status:      mapStatus(d.Status),
+            HttpStatus:  d.HTTPStatus,
+            ErrorReason: d.Error,
+            EnqueuedAt:  toTS(d.EnqueuedAt),
+            DequeuedAt:  toTS(d.DequeuedAt),
+            SentAt:      toTS(d.SentAt),
+            DeliveredAt: toTS(d.DeliveredAt),
+            FailedAt:    toTS(d.FailedAt),
+            DlqAt:       toTS(d.DLQAt),
         })
     }
-    if err := rows.Err(); err != nil {
-        return nil, err
+
+    resp := &webhookv1.ListDLQResponse{Dead: out, TotalCount: page.Total}
+    if page.Next != nil {
+        resp.NextPageToken = dlqCursor{createdAt: page.Next.CreatedAt, id: page.Next.ID}.encode()
+    }
+    return resp, nil
+}
+
+// maxDLQPageSize caps ListDLQ's limit
+const maxDLQPageSize = 500
+
+// dlqCursor is the position of the last DLQ row on a page
+type dlqCursor struct {
+    createdAt time.Time
+    id        string
+}
+
+func (c dlqCursor) encode() string {
+    return base64.RawURLEncoding.EncodeToString([]byte(c.createdAt.UTC().Format(time.RFC3339Nano) + "|" + c.id))
+}
+
+func decodeDLQCursor(tok string) (dlqCursor, error) {
+    var c dlqCursor
+    raw, err := base64.RawURLEncoding.DecodeString(tok)
+    if err != nil {
+        return c, apierr.Invalid("invalid page_token")
+    }
+    ts, id, ok := strings.Cut(string(raw), "|")
+    if !ok || id == "" {
+        return c, apierr.Invalid("invalid page_token")
+    }
+    if c.createdAt, err = time.Parse(time.RFC3339Nano, ts); err != nil {
+        return c, apierr.Invalid("invalid page_token")
     }
-    return &webhookv1.ListDLQResponse{Dead: out}, nil
+    c.id = id
+    return c, nil
 }
 
 // --- helpers ---
 
+// scopeTenant returns the tenant a query is limited to. The token's tenant wins when present;
+// an explicit tenant_id must agree with it. Admins may name any tenant, or none for all of them.
+func scopeTenant(ctx context.Context, requested string) (string, error) {
+    claimed, ok := auth.GetTenantIDFromContext(ctx)
+    if !ok || claimed == "" || auth.IsAdmin(ctx) {
+        return requested, nil
+    }
+    if requested != "" && requested != claimed {
+        return "", apierr.PermissionDenied("tenant_id %q does not match token", requested)
+    }
+    return claimed, nil
+}
+
 func nullStr(ns sql.NullString) string { if ns.Valid { return ns.String }; return "" }
+func nonNilStrings(ss []string) []string { if ss == nil { return []string{} }; return ss }
+func nonNilInt32s(is []int32) []int32 { if is == nil { return []int32{} }; return is }
 func nullI32(ni sql.NullInt32) int32 { if ni.Valid { return ni.Int32 }; return 0 }
 func toTS(nt sql.NullTime) *timestamppb.Timestamp { if nt.Valid { return timestamppb.New(nt.Time) }; return nil }
 
@@ -564,6 +1081,8 @@ func mapStatus(s string) webhookv1.DeliveryAttemptStatus {
         return webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_FAILED
     case "dead":
         return webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED
+    case "parked":
+        return webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_PARKED
     default:
         return webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED
     }
@@ -13,12 +13,21 @@ package ingest
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
-	"google.golang.org/protobuf/types/known/structpb"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/auth"
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/netguard"
 	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
 )
 
@@ -121,42 +130,64 @@ func TestServer_CreateEndpoint_Validation(t *testing.T) {
 	tests := []struct {
 		name        string
 		request     *webhookv1.CreateEndpointRequest
+		guarded     bool // check the url against internal networks
 		expectError bool
 		errorMsg    string
 	}{
 		{
-			name: "missing tenant_id",
+			name: "decreasing recovery ramp",
 			request: &webhookv1.CreateEndpointRequest{
-				Url:    "https://example.com/webhook",
-				Secret: "test-secret",
+				TenantId:     "tenant-123",
+				Url:          "https://example.com/webhook",
+				RecoveryRamp: &webhookv1.RecoveryRamp{Percents: []int32{50, 10}, StepSeconds: 60},
 			},
 			expectError: true,
-			errorMsg:    "tenant_id and url are required",
+			errorMsg:    "ramp percents must not decrease",
 		},
 		{
-			name: "missing url",
+			name: "cloud metadata url",
 			request: &webhookv1.CreateEndpointRequest{
 				TenantId: "tenant-123",
-				Secret:   "test-secret",
+				Url:      "http://169.254.169.254/latest/meta-data/",
 			},
+			guarded:     true,
 			expectError: true,
-			errorMsg:    "tenant_id and url are required",
+			errorMsg:    "url not allowed",
 		},
 		{
-			name: "invalid url format",
+			name: "loopback url",
 			request: &webhookv1.CreateEndpointRequest{
 				TenantId: "tenant-123",
-				Url:      "not-a-valid-url",
-				Secret:   "test-secret",
+				Url:      "http://127.0.0.1:8080/admin",
 			},
+			guarded:     true,
 			expectError: true,
-			errorMsg:    "invalid url",
+			errorMsg:    "url not allowed",
+		},
+		{
+			name:        "missing url",
+			request:     &webhookv1.CreateEndpointRequest{TenantId: "tenant-123"},
+			expectError: true,
+			errorMsg:    "url is required",
+		},
+		{
+			name: "capture endpoint with url",
+			request: &webhookv1.CreateEndpointRequest{
+				TenantId: "tenant-123",
+				Url:      "https://example.com/webhook",
+				Type:     webhookv1.EndpointType_ENDPOINT_TYPE_CAPTURE,
+			},
+			expectError: true,
+			errorMsg:    "url must be empty for capture endpoints",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			server := &Server{} // No database connection, will fail on DB operations
+			if tt.guarded {
+				server.SetEgressGuard(&netguard.Guard{})
+			}
 
 			_, err := server.CreateEndpoint(context.Background(), tt.request)
 
@@ -182,31 +213,26 @@ func TestServer_CreateSubscription_Validation(t *testing.T) {
 		errorMsg    string
 	}{
 		{
-			name: "missing tenant_id",
-			request: &webhookv1.CreateSubscriptionRequest{
-				EventType:  "user.created",
-				EndpointId: "endpoint-abc",
-			},
-			expectError: true,
-			errorMsg:    "tenant_id, event_type, and endpoint_id are required",
-		},
-		{
-			name: "missing event_type",
+			name: "invalid include field path",
 			request: &webhookv1.CreateSubscriptionRequest{
-				TenantId:   "tenant-123",
-				EndpointId: "endpoint-abc",
+				TenantId:      "tenant-123",
+				EventType:     "user.created",
+				EndpointId:    "endpoint-abc",
+				IncludeFields: []string{"user..email"},
 			},
 			expectError: true,
-			errorMsg:    "tenant_id, event_type, and endpoint_id are required",
+			errorMsg:    `invalid field path "user..email"`,
 		},
 		{
-			name: "missing endpoint_id",
+			name: "empty exclude field path",
 			request: &webhookv1.CreateSubscriptionRequest{
-				TenantId:  "tenant-123",
-				EventType: "user.created",
+				TenantId:      "tenant-123",
+				EventType:     "user.created",
+				EndpointId:    "endpoint-abc",
+				ExcludeFields: []string{""},
 			},
 			expectError: true,
-			errorMsg:    "tenant_id, event_type, and endpoint_id are required",
+			errorMsg:    `invalid field path ""`,
 		},
 	}
 
@@ -230,45 +256,21 @@ func TestServer_CreateSubscription_Validation(t *testing.T) {
 	}
 }
 
-func TestServer_PublishEvent_Validation(t *testing.T) {
-	// Create test payload
-	payload, _ := structpb.NewStruct(map[string]any{
-		"user_id": "123",
-		"email":   "test@example.com",
-	})
-
+func TestServer_SetEndpointRecoveryRamp_Validation(t *testing.T) {
+	const endpointID = "123e4567-e89b-12d3-a456-426614174000"
 	tests := []struct {
-		name        string
-		request     *webhookv1.PublishEventRequest
-		expectError bool
-		errorMsg    string
+		name     string
+		request  *webhookv1.SetEndpointRecoveryRampRequest
+		errorMsg string
 	}{
 		{
-			name: "missing tenant_id",
-			request: &webhookv1.PublishEventRequest{
-				EventType: "user.created",
-				Payload:   payload,
-			},
-			expectError: true,
-			errorMsg:    "tenant_id, event_type, and payload are required",
-		},
-		{
-			name: "missing event_type",
-			request: &webhookv1.PublishEventRequest{
-				TenantId: "tenant-123",
-				Payload:  payload,
-			},
-			expectError: true,
-			errorMsg:    "tenant_id, event_type, and payload are required",
-		},
-		{
-			name: "missing payload",
-			request: &webhookv1.PublishEventRequest{
-				TenantId:  "tenant-123",
-				EventType: "user.created",
+			name: "percent out of range",
+			request: &webhookv1.SetEndpointRecoveryRampRequest{
+				TenantId:     "tn_1",
+				EndpointId:   endpointID,
+				RecoveryRamp: &webhookv1.RecoveryRamp{Percents: []int32{10, 200}, StepSeconds: 60},
 			},
-			expectError: true,
-			errorMsg:    "tenant_id, event_type, and payload are required",
+			errorMsg: "ramp percent 200 must be between 1 and 100",
 		},
 	}
 
@@ -276,36 +278,41 @@ func TestServer_PublishEvent_Validation(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			server := &Server{}
 
-			_, err := server.PublishEvent(context.Background(), tt.request)
-
-			if tt.expectError {
-				if err == nil {
-					t.Error("PublishEvent() expected error but got none")
-				}
-				if tt.errorMsg != "" && err != nil {
-					if err.Error() != tt.errorMsg {
-						t.Errorf("PublishEvent() error = %q, want %q", err.Error(), tt.errorMsg)
-					}
-				}
+			_, err := server.SetEndpointRecoveryRamp(context.Background(), tt.request)
+			if err == nil {
+				t.Fatal("SetEndpointRecoveryRamp() expected error but got none")
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("SetEndpointRecoveryRamp() error = %q, want %q", err.Error(), tt.errorMsg)
 			}
 		})
 	}
 }
 
-func TestServer_GetDeliveryStatus_Validation(t *testing.T) {
+func TestServer_ListDLQ_Validation(t *testing.T) {
 	tests := []struct {
-		name        string
-		request     *webhookv1.GetDeliveryStatusRequest
-		expectError bool
-		errorMsg    string
+		name     string
+		ctx      context.Context
+		request  *webhookv1.ListDLQRequest
+		errorMsg string
 	}{
 		{
-			name: "missing event_id",
-			request: &webhookv1.GetDeliveryStatusRequest{
-				EndpointId: "endpoint-123",
-			},
-			expectError: true,
-			errorMsg:    "event_id is required",
+			name:     "tenant_id does not match token",
+			ctx:      context.WithValue(context.Background(), auth.TenantIDKey, "tn_a"),
+			request:  &webhookv1.ListDLQRequest{TenantId: "tn_b"},
+			errorMsg: `tenant_id "tn_b" does not match token`,
+		},
+		{
+			name:     "page token not base64",
+			ctx:      context.Background(),
+			request:  &webhookv1.ListDLQRequest{PageToken: "!!!"},
+			errorMsg: "invalid page_token",
+		},
+		{
+			name:     "page token without id",
+			ctx:      context.Background(),
+			request:  &webhookv1.ListDLQRequest{PageToken: "MjAyNS0wMS0wMVQwMDowMDowMFo"},
+			errorMsg: "invalid page_token",
 		},
 	}
 
@@ -313,61 +320,70 @@ func TestServer_GetDeliveryStatus_Validation(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			server := &Server{}
 
-			_, err := server.GetDeliveryStatus(context.Background(), tt.request)
-
-			if tt.expectError {
-				if err == nil {
-					t.Error("GetDeliveryStatus() expected error but got none")
-				}
-				if tt.errorMsg != "" && err != nil {
-					if err.Error() != tt.errorMsg {
-						t.Errorf("GetDeliveryStatus() error = %q, want %q", err.Error(), tt.errorMsg)
-					}
-				}
+			_, err := server.ListDLQ(tt.ctx, tt.request)
+			if err == nil {
+				t.Fatal("ListDLQ() expected error but got none")
+			}
+			if err.Error() != tt.errorMsg {
+				t.Errorf("ListDLQ() error = %q, want %q", err.Error(), tt.errorMsg)
 			}
 		})
 	}
 }
 
-func TestServer_ReplayDelivery_Validation(t *testing.T) {
-	tests := []struct {
-		name        string
-		request     *webhookv1.ReplayDeliveryRequest
-		expectError bool
-		errorMsg    string
-	}{
-		{
-			name: "missing delivery_id",
-			request: &webhookv1.ReplayDeliveryRequest{
-				Reason: "test replay",
-			},
-			expectError: true,
-			errorMsg:    "delivery_id is required",
-		},
+func TestDLQCursor(t *testing.T) {
+	c := dlqCursor{
+		createdAt: time.Date(2025, 3, 4, 5, 6, 7, 123456000, time.UTC),
+		id:        "123e4567-e89b-12d3-a456-426614174000",
+	}
+	got, err := decodeDLQCursor(c.encode())
+	if err != nil {
+		t.Fatalf("decodeDLQCursor() error: %v", err)
 	}
+	if !got.createdAt.Equal(c.createdAt) || got.id != c.id {
+		t.Errorf("decodeDLQCursor() = %+v, want %+v", got, c)
+	}
+}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			server := &Server{}
+func TestBuildReplayChains(t *testing.T) {
+	const (
+		queued    = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_QUEUED
+		dead      = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED
+		delivered = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED
+	)
+	attempts := []*webhookv1.DeliveryAttempt{
+		{DeliveryId: "a", RootDeliveryId: "a", Status: dead},
+		{DeliveryId: "b", RootDeliveryId: "b", Status: delivered},
+		{DeliveryId: "a2", RootDeliveryId: "a", ReplayOf: "a1", ReplayDepth: 2, Status: queued},
+		{DeliveryId: "a1", RootDeliveryId: "a", ReplayOf: "a", ReplayDepth: 1, Status: dead},
+		// Replay whose original fell outside the result window
+		{DeliveryId: "c1", RootDeliveryId: "c", ReplayOf: "c", ReplayDepth: 1, Status: delivered},
+	}
 
-			_, err := server.ReplayDelivery(context.Background(), tt.request)
+	chains := buildReplayChains(attempts)
+	if len(chains) != 2 {
+		t.Fatalf("buildReplayChains() returned %d chains, want 2 (unreplayed b skipped)", len(chains))
+	}
 
-			if tt.expectError {
-				if err == nil {
-					t.Error("ReplayDelivery() expected error but got none")
-				}
-				if tt.errorMsg != "" && err != nil {
-					if err.Error() != tt.errorMsg {
-						t.Errorf("ReplayDelivery() error = %q, want %q", err.Error(), tt.errorMsg)
-					}
-				}
-			}
-		})
+	a := chains[0]
+	if a.RootDeliveryId != "a" {
+		t.Errorf("chains[0].RootDeliveryId = %q, want a", a.RootDeliveryId)
+	}
+	var order []string
+	for _, at := range a.Attempts {
+		order = append(order, at.DeliveryId)
+	}
+	if strings.Join(order, ",") != "a,a1,a2" {
+		t.Errorf("chain a order = %v, want [a a1 a2]", order)
+	}
+	if a.ActiveDeliveryId != "a2" {
+		t.Errorf("chain a ActiveDeliveryId = %q, want a2", a.ActiveDeliveryId)
 	}
-}
 
-// TestServer_ListDLQ is omitted because ListDLQ has no validation logic to test
-// It immediately builds and executes a database query with optional filters
+	if chains[1].RootDeliveryId != "c" || chains[1].ActiveDeliveryId != "" {
+		t.Errorf("chains[1] = %q active %q, want c with no active replay", chains[1].RootDeliveryId, chains[1].ActiveDeliveryId)
+	}
+}
 
 func TestHelperFunctions(t *testing.T) {
 	t.Run("nullStr", func(t *testing.T) {
@@ -501,6 +517,11 @@ func TestHelperFunctions(t *testing.T) {
 				input:    "dead",
 				expected: webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED,
 			},
+			{
+				name:     "parked status",
+				input:    "parked",
+				expected: webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_PARKED,
+			},
 			{
 				name:     "unknown status",
 				input:    "unknown",
@@ -547,3 +568,74 @@ func indexOf(s, substr string) int {
 	}
 	return -1
 }
+
+func TestScopeTenant(t *testing.T) {
+	tenant := context.WithValue(context.Background(), auth.TenantIDKey, "tn_a")
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "ops", Role: auth.RoleAdmin})
+	operator := auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "tn_a", Role: auth.RoleOperator})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		requested string
+		want      string
+		wantErr   bool
+	}{
+		{"unauthenticated", context.Background(), "tn_b", "tn_b", false},
+		{"token tenant wins", tenant, "", "tn_a", false},
+		{"other tenant", tenant, "tn_b", "", true},
+		{"operator stays in its tenant", operator, "tn_b", "", true},
+		{"admin names a tenant", admin, "tn_b", "tn_b", false},
+		{"admin spans every tenant", admin, "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scopeTenant(tt.ctx, tt.requested)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("scopeTenant(%q) = %q, %v, want %q", tt.requested, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestServer_ReplayDelivery_TenantScope(t *testing.T) {
+	const deliveryID = "6f1c1a36-7a51-4b8e-9a43-0c3f2a5d8e11"
+	var dbErr error
+	server := NewServer(&dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			switch {
+			case dbErr != nil:
+				return dbfake.Row{Err: dbErr}
+			case strings.Contains(sql, "url = $2"):
+				// tn_a owns only https://a.example/hook
+				return dbfake.Row{Values: []any{args[0] == "tn_a" && args[1] == "https://a.example/hook"}}
+			case strings.Contains(sql, "FROM harborhook.deliveries d"):
+				// The delivery belongs to tn_b
+				if args[1] != "" && args[1] != "tn_b" {
+					return dbfake.Row{Err: pgx.ErrNoRows}
+				}
+				return dbfake.Row{Values: []any{"evt_1", "ep_b", "tn_b", "order.created", `{}`, "https://b.example/hook",
+					nil, []string{}, []string{}, nil, "normal", ""}}
+			}
+			return dbfake.Row{Err: fmt.Errorf("unexpected query: %s", sql)}
+		},
+	}, nil)
+	operator := auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "tn_a", Role: auth.RoleOperator})
+
+	_, err := server.ReplayDelivery(operator, &webhookv1.ReplayDeliveryRequest{DeliveryId: deliveryID})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("ReplayDelivery(another tenant's delivery) error = %v, want NotFound", err)
+	}
+	_, err = server.ReplayDelivery(operator, &webhookv1.ReplayDeliveryRequest{DeliveryId: deliveryID, UrlOverride: "https://attacker.example/"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ReplayDelivery(url_override elsewhere) error = %v, want PermissionDenied", err)
+	}
+
+	// A failing database is not reported as a missing delivery
+	dbErr = context.Canceled
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{TenantID: "ops", Role: auth.RoleAdmin})
+	_, err = server.ReplayDelivery(admin, &webhookv1.ReplayDeliveryRequest{DeliveryId: deliveryID})
+	if err == nil || status.Code(apierr.From(err)) == codes.NotFound || !errors.Is(err, context.Canceled) {
+		t.Errorf("ReplayDelivery(database down) error = %v, want the database error", err)
+	}
+}
@@ -0,0 +1,35 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// SetDeliverySettings changes a tenant's delivery options, such as whether deliveries
+// identify the tenant in their headers
+func (s *Server) SetDeliverySettings(ctx context.Context, req *webhookv1.SetDeliverySettingsRequest) (*webhookv1.SetDeliverySettingsResponse, error) {
+	var updatedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.tenant_delivery_settings(tenant_id, sender_headers)
+		VALUES ($1, $2)
+		ON CONFLICT (tenant_id) DO UPDATE
+		SET sender_headers = EXCLUDED.sender_headers, updated_at = now()
+		RETURNING updated_at
+	`, req.GetTenantId(), req.GetSenderHeaders()).Scan(&updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("save delivery settings: %w", err)
+	}
+
+	return &webhookv1.SetDeliverySettingsResponse{
+		Settings: &webhookv1.DeliverySettings{
+			TenantId:      req.GetTenantId(),
+			SenderHeaders: req.GetSenderHeaders(),
+			UpdatedAt:     timestamppb.New(updatedAt),
+		},
+	}, nil
+}
@@ -0,0 +1,163 @@
+package ingest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// signatureSchemeColumn maps an API signature scheme to its endpoints.signature_scheme value
+func signatureSchemeColumn(s webhookv1.SignatureScheme) string {
+	switch s {
+	case webhookv1.SignatureScheme_SIGNATURE_SCHEME_V2:
+		return delivery.SignatureV2
+	case webhookv1.SignatureScheme_SIGNATURE_SCHEME_ED25519:
+		return delivery.SignatureEd25519
+	default:
+		return delivery.SignatureV1
+	}
+}
+
+// signatureSchemeFromColumn maps an endpoints.signature_scheme value to the API
+func signatureSchemeFromColumn(s string) webhookv1.SignatureScheme {
+	switch s {
+	case delivery.SignatureV2:
+		return webhookv1.SignatureScheme_SIGNATURE_SCHEME_V2
+	case delivery.SignatureEd25519:
+		return webhookv1.SignatureScheme_SIGNATURE_SCHEME_ED25519
+	default:
+		return webhookv1.SignatureScheme_SIGNATURE_SCHEME_V1
+	}
+}
+
+// ensureSigningKey returns a tenant's Ed25519 signing key, generating it the first time one of
+// its endpoints opts into ed25519 signatures. Concurrent callers all get the key stored first.
+func (s *Server) ensureSigningKey(ctx context.Context, tenantID string) (ed25519.PrivateKey, error) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	var seed []byte
+	err = s.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.tenant_signing_keys (tenant_id, key_id, public_key, private_key)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id) DO UPDATE SET tenant_id = EXCLUDED.tenant_id
+		RETURNING private_key`,
+		tenantID, delivery.PublicKeyID(pub), []byte(pub), key.Seed(),
+	).Scan(&seed)
+	if err != nil {
+		return nil, err
+	}
+	return signingKeyFromSeed(seed)
+}
+
+// signingKey returns a tenant's Ed25519 signing key
+func (s *Server) signingKey(ctx context.Context, tenantID string) (ed25519.PrivateKey, error) {
+	var seed []byte
+	err := s.pool.QueryRow(ctx, `
+		SELECT private_key FROM harborhook.tenant_signing_keys WHERE tenant_id = $1`,
+		tenantID,
+	).Scan(&seed)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("tenant %s has no signing key", tenantID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return signingKeyFromSeed(seed)
+}
+
+// signingKeyFromSeed expands a stored tenant_signing_keys.private_key
+func signingKeyFromSeed(seed []byte) (ed25519.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, errors.New("stored signing key is not an Ed25519 seed")
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// GetSigningKeys returns a tenant's public keys as a JWK set. It takes no token, like a JWKS
+// endpoint: receivers fetch it to verify ed25519 signatures, and the keys are public.
+func (s *Server) GetSigningKeys(ctx context.Context, req *webhookv1.GetSigningKeysRequest) (*webhookv1.GetSigningKeysResponse, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT key_id, public_key
+		FROM harborhook.tenant_signing_keys
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC`,
+		req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.GetSigningKeysResponse{}
+	for rows.Next() {
+		var (
+			kid string
+			pub []byte
+		)
+		if err := rows.Scan(&kid, &pub); err != nil {
+			return nil, err
+		}
+		resp.Keys = append(resp.Keys, &webhookv1.SigningKey{
+			Kty: "OKP",
+			Crv: "Ed25519",
+			Kid: kid,
+			X:   base64.RawURLEncoding.EncodeToString(pub),
+			Use: "sig",
+			Alg: "EdDSA",
+		})
+	}
+	return resp, rows.Err()
+}
+
+// SetEndpointSignatureScheme chooses how webhooks sent to an endpoint are signed. Workers read
+// the scheme as they send, so queued deliveries and retries switch too; receivers should accept
+// both schemes while the change rolls out.
+func (s *Server) SetEndpointSignatureScheme(ctx context.Context, req *webhookv1.SetEndpointSignatureSchemeRequest) (*webhookv1.SetEndpointSignatureSchemeResponse, error) {
+	scheme := signatureSchemeColumn(req.GetSignatureScheme())
+	// The key must exist before workers sign with it
+	if scheme == delivery.SignatureEd25519 {
+		if _, err := s.ensureSigningKey(ctx, req.GetTenantId()); err != nil {
+			return nil, fmt.Errorf("create signing key: %w", err)
+		}
+	}
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET signature_scheme = $3
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at`,
+		req.GetEndpointId(), req.GetTenantId(), scheme,
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointSignatureSchemeResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:              req.GetEndpointId(),
+			TenantId:        req.GetTenantId(),
+			Url:             endpointURL,
+			CreatedAt:       timestamppb.New(createdAt),
+			SignatureScheme: signatureSchemeFromColumn(scheme),
+		},
+	}, nil
+}
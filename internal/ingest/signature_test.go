@@ -0,0 +1,118 @@
+package ingest
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_SetEndpointSignatureScheme(t *testing.T) {
+	tests := []struct {
+		name   string
+		scheme webhookv1.SignatureScheme
+		stored string
+		want   webhookv1.SignatureScheme
+	}{
+		{"v2", webhookv1.SignatureScheme_SIGNATURE_SCHEME_V2, "v2", webhookv1.SignatureScheme_SIGNATURE_SCHEME_V2},
+		{"v1", webhookv1.SignatureScheme_SIGNATURE_SCHEME_V1, "v1", webhookv1.SignatureScheme_SIGNATURE_SCHEME_V1},
+		{"unspecified restores v1", webhookv1.SignatureScheme_SIGNATURE_SCHEME_UNSPECIFIED, "v1", webhookv1.SignatureScheme_SIGNATURE_SCHEME_V1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored any
+			server := NewServer(&dbfake.Pool{
+				QueryRowFunc: func(_ string, args []any) pgx.Row {
+					stored = args[2]
+					return dbfake.Row{Values: []any{"https://partner.example/hook", time.Now()}}
+				},
+			}, nil)
+			resp, err := server.SetEndpointSignatureScheme(context.Background(), &webhookv1.SetEndpointSignatureSchemeRequest{
+				TenantId: "tn_1", EndpointId: "ep_1", SignatureScheme: tt.scheme,
+			})
+			if err != nil {
+				t.Fatalf("SetEndpointSignatureScheme() unexpected error: %v", err)
+			}
+			if stored != tt.stored || resp.Endpoint.SignatureScheme != tt.want {
+				t.Errorf("stored %v and returned %v, want %s", stored, resp.Endpoint.SignatureScheme, tt.stored)
+			}
+		})
+	}
+
+	if _, err := NewServer(&dbfake.Pool{}, nil).SetEndpointSignatureScheme(context.Background(), &webhookv1.SetEndpointSignatureSchemeRequest{
+		TenantId: "tn_1", EndpointId: "ep_missing",
+	}); err == nil || err.Error() != "endpoint ep_missing not found" {
+		t.Errorf("SetEndpointSignatureScheme(missing) error = %v, want endpoint ep_missing not found", err)
+	}
+}
+
+func TestServer_SetEndpointSignatureScheme_Ed25519CreatesKey(t *testing.T) {
+	var stored []any
+	server := NewServer(&dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			if strings.Contains(sql, "INSERT INTO harborhook.tenant_signing_keys") {
+				stored = args
+				return dbfake.Row{Values: []any{args[3]}}
+			}
+			return dbfake.Row{Values: []any{"https://partner.example/hook", time.Now()}}
+		},
+	}, nil)
+
+	resp, err := server.SetEndpointSignatureScheme(context.Background(), &webhookv1.SetEndpointSignatureSchemeRequest{
+		TenantId: "tn_1", EndpointId: "ep_1", SignatureScheme: webhookv1.SignatureScheme_SIGNATURE_SCHEME_ED25519,
+	})
+	if err != nil {
+		t.Fatalf("SetEndpointSignatureScheme() unexpected error: %v", err)
+	}
+	if resp.Endpoint.SignatureScheme != webhookv1.SignatureScheme_SIGNATURE_SCHEME_ED25519 {
+		t.Errorf("returned %v, want ed25519", resp.Endpoint.SignatureScheme)
+	}
+	if stored == nil {
+		t.Fatal("no signing key stored for the tenant")
+	}
+	pub, seed := stored[2].([]byte), stored[3].([]byte)
+	if stored[0] != "tn_1" || stored[1] != delivery.PublicKeyID(pub) || len(seed) != ed25519.SeedSize {
+		t.Errorf("stored key = %v, want tn_1's key with its kid and a 32-byte seed", stored)
+	}
+	if !ed25519.NewKeyFromSeed(seed).Public().(ed25519.PublicKey).Equal(ed25519.PublicKey(pub)) {
+		t.Error("stored public key doesn't match the seed")
+	}
+}
+
+func TestServer_GetSigningKeys(t *testing.T) {
+	pub, _, _ := ed25519.GenerateKey(nil)
+	server := NewServer(&dbfake.Pool{QueryFunc: func(_ string, args []any) (pgx.Rows, error) {
+		if args[0] != "tn_1" {
+			return dbfake.NewRows(), nil
+		}
+		return dbfake.NewRows([]any{delivery.PublicKeyID(pub), []byte(pub)}), nil
+	}}, nil)
+
+	resp, err := server.GetSigningKeys(context.Background(), &webhookv1.GetSigningKeysRequest{TenantId: "tn_1"})
+	if err != nil {
+		t.Fatalf("GetSigningKeys() unexpected error: %v", err)
+	}
+	if len(resp.Keys) != 1 {
+		t.Fatalf("GetSigningKeys() = %d keys, want 1", len(resp.Keys))
+	}
+	k := resp.Keys[0]
+	if k.Kty != "OKP" || k.Crv != "Ed25519" || k.Alg != "EdDSA" || k.Use != "sig" || k.Kid != delivery.PublicKeyID(pub) {
+		t.Errorf("key = %+v, want an Ed25519 signing JWK", k)
+	}
+	if x, _ := base64.RawURLEncoding.DecodeString(k.X); !ed25519.PublicKey(x).Equal(pub) {
+		t.Errorf("x = %q, want the base64url public key", k.X)
+	}
+
+	resp, err = server.GetSigningKeys(context.Background(), &webhookv1.GetSigningKeysRequest{TenantId: "tn_2"})
+	if err != nil || len(resp.Keys) != 0 {
+		t.Errorf("GetSigningKeys(no key yet) = %v, %v, want an empty set", resp.Keys, err)
+	}
+}
@@ -0,0 +1,160 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+const (
+	defaultStatsWindowSeconds = 24 * 3600
+	maxStatsTopFailures       = 5
+)
+
+// deliveryStatsQuery aggregates a tenant's deliveries created since $2, per endpoint and (the
+// row with a NULL endpoint) across all of them, so the percentiles of the totals are exact
+// rather than combined from the endpoints'
+const deliveryStatsQuery = `
+	SELECT d.endpoint_id::text,
+	       count(*),
+	       count(*) FILTER (WHERE d.status = 'queued'),
+	       count(*) FILTER (WHERE d.status = 'inflight'),
+	       count(*) FILTER (WHERE d.status = 'delivered'),
+	       count(*) FILTER (WHERE d.status = 'failed'),
+	       count(*) FILTER (WHERE d.status = 'dead'),
+	       count(*) FILTER (WHERE d.status = 'parked'),
+	       COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY d.latency_ms) FILTER (WHERE d.status = 'delivered'), 0),
+	       COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY d.latency_ms) FILTER (WHERE d.status = 'delivered'), 0),
+	       COALESCE(percentile_cont(0.99) WITHIN GROUP (ORDER BY d.latency_ms) FILTER (WHERE d.status = 'delivered'), 0),
+	       COALESCE(sum(GREATEST(d.attempt - 1, 0)), 0)
+	FROM harborhook.deliveries d
+	JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+	JOIN harborhook.events e ON e.id = d.event_id
+	WHERE ep.tenant_id = $1
+	  AND d.created_at >= $2
+	  AND (NULLIF($3, '') IS NULL OR d.endpoint_id = NULLIF($3, '')::uuid)
+	  AND (NULLIF($4, '') IS NULL OR e.event_type = $4)
+	GROUP BY GROUPING SETS ((d.endpoint_id), ())`
+
+// deliveryFailureQuery groups the same deliveries whose latest attempt failed by endpoint, HTTP
+// status and error, for classifying into failure reasons
+const deliveryFailureQuery = `
+	SELECT d.endpoint_id::text, COALESCE(d.http_status, 0), COALESCE(d.error_reason, d.last_error, ''), count(*)
+	FROM harborhook.deliveries d
+	JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+	JOIN harborhook.events e ON e.id = d.event_id
+	WHERE ep.tenant_id = $1
+	  AND d.created_at >= $2
+	  AND d.status IN ('failed', 'dead')
+	  AND (NULLIF($3, '') IS NULL OR d.endpoint_id = NULLIF($3, '')::uuid)
+	  AND (NULLIF($4, '') IS NULL OR e.event_type = $4)
+	GROUP BY 1, 2, 3`
+
+// GetDeliveryStats aggregates a tenant's deliveries over a window: counts by status, success
+// rate, latency percentiles, retries and the most common failure reasons, across all endpoints
+// and per endpoint
+func (s *Server) GetDeliveryStats(ctx context.Context, req *webhookv1.GetDeliveryStatsRequest) (*webhookv1.GetDeliveryStatsResponse, error) {
+	window := req.GetWindowSeconds()
+	if window == 0 {
+		window = defaultStatsWindowSeconds
+	}
+	since := time.Now().Add(-time.Duration(window) * time.Second)
+	args := []any{req.GetTenantId(), since, req.GetEndpointId(), req.GetEventType()}
+
+	rows, err := s.pool.Query(ctx, deliveryStatsQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query delivery stats: %w", err)
+	}
+	defer rows.Close()
+
+	resp := &webhookv1.GetDeliveryStatsResponse{Totals: &webhookv1.DeliveryStats{}, WindowSeconds: window}
+	for rows.Next() {
+		var (
+			endpointID    *string
+			p50, p95, p99 float64
+			st            webhookv1.DeliveryStats
+		)
+		if err := rows.Scan(&endpointID, &st.Total, &st.Queued, &st.Inflight, &st.Delivered, &st.Failed, &st.Dead, &st.Parked,
+			&p50, &p95, &p99, &st.Retries); err != nil {
+			return nil, err
+		}
+		st.LatencyP50Ms, st.LatencyP95Ms, st.LatencyP99Ms = int32(math.Round(p50)), int32(math.Round(p95)), int32(math.Round(p99))
+		if finished := st.Delivered + st.Dead; finished > 0 {
+			st.SuccessRate = float64(st.Delivered) / float64(finished)
+		}
+		if endpointID == nil {
+			resp.Totals = &st
+			continue
+		}
+		st.EndpointId = *endpointID
+		resp.Endpoints = append(resp.Endpoints, &st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(resp.Endpoints, func(i, j int) bool {
+		if resp.Endpoints[i].Total != resp.Endpoints[j].Total {
+			return resp.Endpoints[i].Total > resp.Endpoints[j].Total
+		}
+		return resp.Endpoints[i].EndpointId < resp.Endpoints[j].EndpointId
+	})
+
+	frows, err := s.pool.Query(ctx, deliveryFailureQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query delivery failures: %w", err)
+	}
+	defer frows.Close()
+
+	var groups []failureRow
+	for frows.Next() {
+		var r failureRow
+		if err := frows.Scan(&r.endpointID, &r.httpStatus, &r.lastErr, &r.count); err != nil {
+			return nil, err
+		}
+		groups = append(groups, r)
+	}
+	if err := frows.Err(); err != nil {
+		return nil, err
+	}
+	addTopFailures(resp, groups)
+	return resp, nil
+}
+
+// addTopFailures classifies grouped failures into reasons and sets the most common ones on the
+// totals and on each endpoint
+func addTopFailures(resp *webhookv1.GetDeliveryStatsResponse, groups []failureRow) {
+	totals := map[string]int64{}
+	perEndpoint := map[string]map[string]int64{}
+	for _, g := range groups {
+		reason := delivery.FailureReason(g.httpStatus, g.lastErr)
+		totals[reason] += g.count
+		if perEndpoint[g.endpointID] == nil {
+			perEndpoint[g.endpointID] = map[string]int64{}
+		}
+		perEndpoint[g.endpointID][reason] += g.count
+	}
+
+	top := func(counts map[string]int64, endpointID string) []*webhookv1.FailureCount {
+		out := make([]*webhookv1.FailureCount, 0, len(counts))
+		for reason, n := range counts {
+			out = append(out, &webhookv1.FailureCount{Reason: reason, EndpointId: endpointID, Count: n})
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].Count != out[j].Count {
+				return out[i].Count > out[j].Count
+			}
+			return out[i].Reason < out[j].Reason
+		})
+		return out[:min(len(out), maxStatsTopFailures)]
+	}
+
+	resp.Totals.TopFailures = top(totals, "")
+	for _, ep := range resp.Endpoints {
+		ep.TopFailures = top(perEndpoint[ep.EndpointId], ep.EndpointId)
+	}
+}
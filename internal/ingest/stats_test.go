@@ -0,0 +1,81 @@
+package ingest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_GetDeliveryStats(t *testing.T) {
+	epA, epB := "ep_a", "ep_b"
+	var statsArgs []any
+	pool := &dbfake.Pool{
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			switch {
+			case strings.Contains(sql, "GROUPING SETS"):
+				statsArgs = args
+				// endpoint, total, queued, inflight, delivered, failed, dead, parked, p50, p95, p99, retries
+				return dbfake.NewRows(
+					[]any{&epA, int64(10), int64(1), int64(0), int64(6), int64(1), int64(2), int64(0), 80.0, 240.4, 300.0, int64(7)},
+					[]any{&epB, int64(30), int64(0), int64(0), int64(30), int64(0), int64(0), int64(0), 20.0, 40.0, 60.0, int64(0)},
+					[]any{nil, int64(40), int64(1), int64(0), int64(36), int64(1), int64(2), int64(0), 25.0, 200.6, 290.0, int64(7)},
+				), nil
+			case strings.Contains(sql, "status IN ('failed', 'dead')"):
+				return dbfake.NewRows(
+					[]any{"ep_a", 503, "", int64(2)},
+					[]any{"ep_a", 0, "dial tcp 10.0.0.1:80: connect: connection refused", int64(1)},
+				), nil
+			}
+			t.Fatalf("unexpected query: %s", sql)
+			return nil, nil
+		},
+	}
+	server := NewServer(pool, nil)
+
+	resp, err := server.GetDeliveryStats(context.Background(), &webhookv1.GetDeliveryStatsRequest{TenantId: "tn_1", EventType: "order.created"})
+	if err != nil {
+		t.Fatalf("GetDeliveryStats() unexpected error: %v", err)
+	}
+	if resp.GetWindowSeconds() != 86400 || statsArgs[0] != "tn_1" || statsArgs[3] != "order.created" {
+		t.Errorf("window = %d, args = %v; want the 24h default for tn_1's order.created deliveries", resp.GetWindowSeconds(), statsArgs)
+	}
+
+	totals := resp.GetTotals()
+	if totals.GetTotal() != 40 || totals.GetDelivered() != 36 || totals.GetLatencyP95Ms() != 201 || totals.GetRetries() != 7 {
+		t.Errorf("totals = %v", totals)
+	}
+	if got := totals.GetSuccessRate(); got < 0.947 || got > 0.948 {
+		t.Errorf("totals success rate = %v, want 36/38", got)
+	}
+	if f := totals.GetTopFailures(); len(f) != 2 || f[0].GetReason() != "http_503" || f[0].GetCount() != 2 || f[1].GetReason() != "connection_refused" {
+		t.Errorf("totals top failures = %v", f)
+	}
+
+	eps := resp.GetEndpoints()
+	if len(eps) != 2 || eps[0].GetEndpointId() != "ep_b" || eps[1].GetEndpointId() != "ep_a" {
+		t.Fatalf("endpoints = %v, want ep_b (most deliveries) first", eps)
+	}
+	if eps[0].GetSuccessRate() != 1 || len(eps[0].GetTopFailures()) != 0 {
+		t.Errorf("ep_b = %v, want all delivered and no failures", eps[0])
+	}
+	if f := eps[1].GetTopFailures(); len(f) != 2 || f[0].GetEndpointId() != "ep_a" {
+		t.Errorf("ep_a top failures = %v", f)
+	}
+}
+
+func TestAddTopFailures_Limit(t *testing.T) {
+	resp := &webhookv1.GetDeliveryStatsResponse{Totals: &webhookv1.DeliveryStats{}}
+	var groups []failureRow
+	for status := 500; status < 508; status++ {
+		groups = append(groups, failureRow{endpointID: "ep_a", httpStatus: status, count: int64(status - 499)})
+	}
+	addTopFailures(resp, groups)
+	if f := resp.GetTotals().GetTopFailures(); len(f) != maxStatsTopFailures || f[0].GetReason() != "http_507" {
+		t.Errorf("top failures = %v, want the %d largest, http_507 first", f, maxStatsTopFailures)
+	}
+}
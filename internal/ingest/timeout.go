@@ -0,0 +1,50 @@
+package ingest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// SetEndpointTimeout sets how long workers wait for an endpoint to answer. Workers read it as they
+// send, so queued deliveries and retries use it too; 0 restores the worker default.
+func (s *Server) SetEndpointTimeout(ctx context.Context, req *webhookv1.SetEndpointTimeoutRequest) (*webhookv1.SetEndpointTimeoutResponse, error) {
+	if err := delivery.ValidateTimeout(req.GetTimeoutMs()); err != nil {
+		return nil, apierr.Invalid("%w", err)
+	}
+
+	var (
+		endpointURL string
+		createdAt   time.Time
+	)
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET timeout_ms = NULLIF($3, 0)
+		WHERE id = $1 AND tenant_id = $2
+		RETURNING url, created_at`,
+		req.GetEndpointId(), req.GetTenantId(), req.GetTimeoutMs(),
+	).Scan(&endpointURL, &createdAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &webhookv1.SetEndpointTimeoutResponse{
+		Endpoint: &webhookv1.Endpoint{
+			Id:        req.GetEndpointId(),
+			TenantId:  req.GetTenantId(),
+			Url:       endpointURL,
+			CreatedAt: timestamppb.New(createdAt),
+			TimeoutMs: req.GetTimeoutMs(),
+		},
+	}, nil
+}
@@ -0,0 +1,38 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+func TestServer_SetEndpointTimeout(t *testing.T) {
+	server := &Server{} // rejected before the database is touched
+	if _, err := server.SetEndpointTimeout(context.Background(), &webhookv1.SetEndpointTimeoutRequest{
+		TenantId: "tn_1", EndpointId: "ep_1", TimeoutMs: 10,
+	}); err == nil {
+		t.Error("SetEndpointTimeout(10ms) = nil error, want it rejected")
+	}
+
+	var stored any
+	server = NewServer(&dbfake.Pool{
+		QueryRowFunc: func(_ string, args []any) pgx.Row {
+			stored = args[2]
+			return dbfake.Row{Values: []any{"https://partner.example/hook", time.Now()}}
+		},
+	}, nil)
+	resp, err := server.SetEndpointTimeout(context.Background(), &webhookv1.SetEndpointTimeoutRequest{
+		TenantId: "tn_1", EndpointId: "ep_1", TimeoutMs: 45000,
+	})
+	if err != nil {
+		t.Fatalf("SetEndpointTimeout() unexpected error: %v", err)
+	}
+	if stored != int32(45000) || resp.Endpoint.TimeoutMs != 45000 {
+		t.Errorf("stored %v and returned %d, want 45000", stored, resp.Endpoint.TimeoutMs)
+	}
+}
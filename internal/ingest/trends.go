@@ -0,0 +1,143 @@
+package ingest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultTrendWindowSeconds = 6 * 3600
+	defaultTrendBucketSeconds = 900
+	minTrendBucketSeconds     = 60
+	maxTrendBuckets           = 1000
+)
+
+// failureTrendQuery groups a tenant's failed deliveries by bucket ($2 seconds wide, aligned to
+// the Unix epoch), endpoint, HTTP status and error since $3. Only a delivery's latest failure
+// is stored, so each delivery counts once, in the bucket of its most recent failed attempt.
+const failureTrendQuery = `
+	SELECT (floor(extract(epoch FROM d.failed_at) / $2) * $2)::bigint,
+	       d.endpoint_id::text, COALESCE(d.http_status, 0), COALESCE(d.error_reason, d.last_error, ''),
+	       count(*)
+	FROM harborhook.deliveries d
+	JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+	WHERE ep.tenant_id = $1
+	  AND d.failed_at >= $3
+	  AND (NULLIF($4, '') IS NULL OR d.endpoint_id = NULLIF($4, '')::uuid)
+	GROUP BY 1, 2, 3, 4`
+
+// failureRow is one group from failureTrendQuery
+type failureRow struct {
+	bucket     int64 // bucket start, Unix seconds
+	endpointID string
+	httpStatus int
+	lastErr    string
+	count      int64
+}
+
+// GetFailureTrends returns a tenant's failed deliveries over a window, bucketed by time and
+// broken down by failure reason and endpoint, for spotting when and where failures started
+func (s *Server) GetFailureTrends(ctx context.Context, req *webhookv1.GetFailureTrendsRequest) (*webhookv1.GetFailureTrendsResponse, error) {
+	window, bucket, err := trendWindow(req.GetWindowSeconds(), req.GetBucketSeconds())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	// Align to the epoch like the query does (time.Truncate aligns to year 1)
+	start := time.Unix((now.Unix()-int64(window))/int64(bucket)*int64(bucket), 0)
+	rows, err := s.pool.Query(ctx, failureTrendQuery, req.GetTenantId(), bucket, start, req.GetEndpointId())
+	if err != nil {
+		return nil, fmt.Errorf("query failure trends: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []failureRow
+	for rows.Next() {
+		var r failureRow
+		if err := rows.Scan(&r.bucket, &r.endpointID, &r.httpStatus, &r.lastErr, &r.count); err != nil {
+			return nil, err
+		}
+		groups = append(groups, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	buckets, totals := buildFailureTrends(start, now, time.Duration(bucket)*time.Second, groups)
+	return &webhookv1.GetFailureTrendsResponse{
+		Buckets:       buckets,
+		Totals:        totals,
+		WindowSeconds: window,
+		BucketSeconds: bucket,
+	}, nil
+}
+
+// trendWindow applies the defaults and limits for a failure trend window and bucket width. The
+// window itself is bounded by the request's validation rules.
+func trendWindow(window, bucket int32) (int32, int32, error) {
+	if window == 0 {
+		window = defaultTrendWindowSeconds
+	}
+	if bucket == 0 {
+		bucket = min(defaultTrendBucketSeconds, window)
+	}
+	if bucket < minTrendBucketSeconds || bucket > window {
+		return 0, 0, apierr.Invalid("bucket_seconds must be between %d and window_seconds", minTrendBucketSeconds)
+	}
+	if window/bucket > maxTrendBuckets {
+		return 0, 0, apierr.Invalid("window_seconds / bucket_seconds must be at most %d", maxTrendBuckets)
+	}
+	return window, bucket, nil
+}
+
+// buildFailureTrends classifies grouped failures into reasons and lays them out in every
+// bucket from start through now, so gaps show up as empty buckets rather than missing ones
+func buildFailureTrends(start, now time.Time, width time.Duration, groups []failureRow) ([]*webhookv1.FailureBucket, []*webhookv1.FailureCount) {
+	type key struct{ reason, endpointID string }
+
+	perBucket := map[int64]map[key]int64{}
+	totals := map[key]int64{}
+	for _, g := range groups {
+		k := key{delivery.FailureReason(g.httpStatus, g.lastErr), g.endpointID}
+		if perBucket[g.bucket] == nil {
+			perBucket[g.bucket] = map[key]int64{}
+		}
+		perBucket[g.bucket][k] += g.count
+		totals[k] += g.count
+	}
+
+	sorted := func(m map[key]int64) []*webhookv1.FailureCount {
+		out := make([]*webhookv1.FailureCount, 0, len(m))
+		for k, n := range m {
+			out = append(out, &webhookv1.FailureCount{Reason: k.reason, EndpointId: k.endpointID, Count: n})
+		}
+		sort.Slice(out, func(i, j int) bool {
+			if out[i].Count != out[j].Count {
+				return out[i].Count > out[j].Count
+			}
+			if out[i].Reason != out[j].Reason {
+				return out[i].Reason < out[j].Reason
+			}
+			return out[i].EndpointId < out[j].EndpointId
+		})
+		return out
+	}
+
+	var buckets []*webhookv1.FailureBucket
+	for t := start; !t.After(now); t = t.Add(width) {
+		buckets = append(buckets, &webhookv1.FailureBucket{
+			Start:    timestamppb.New(t),
+			Failures: sorted(perBucket[t.Unix()]),
+		})
+	}
+	return buckets, sorted(totals)
+}
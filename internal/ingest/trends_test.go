@@ -0,0 +1,87 @@
+package ingest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrendWindow(t *testing.T) {
+	tests := []struct {
+		name               string
+		window, bucket     int32
+		wantWindow, wantBk int32
+		wantErr            string
+	}{
+		{name: "defaults", wantWindow: 21600, wantBk: 900},
+		{name: "short window narrows default bucket", window: 600, wantWindow: 600, wantBk: 600},
+		{name: "explicit", window: 3600, bucket: 60, wantWindow: 3600, wantBk: 60},
+		{name: "bucket too small", window: 3600, bucket: 30, wantErr: "bucket_seconds must be between 60 and window_seconds"},
+		{name: "bucket wider than window", window: 3600, bucket: 7200, wantErr: "bucket_seconds must be between 60 and window_seconds"},
+		{name: "too many buckets", window: 604800, bucket: 60, wantErr: "window_seconds / bucket_seconds must be at most 1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			window, bucket, err := trendWindow(tt.window, tt.bucket)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("trendWindow() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("trendWindow() unexpected error: %v", err)
+			}
+			if window != tt.wantWindow || bucket != tt.wantBk {
+				t.Errorf("trendWindow() = %d, %d; want %d, %d", window, bucket, tt.wantWindow, tt.wantBk)
+			}
+		})
+	}
+}
+
+func TestBuildFailureTrends(t *testing.T) {
+	start := time.Unix(1_700_000_000/900*900, 0)
+	now := start.Add(40 * time.Minute) // buckets at +0, +15m, +30m
+	b0, b2 := start.Unix(), start.Add(30*time.Minute).Unix()
+
+	groups := []failureRow{
+		{bucket: b0, endpointID: "ep_a", httpStatus: 503, count: 2},
+		{bucket: b2, endpointID: "ep_a", httpStatus: 503, count: 1},
+		// Different raw errors of the same kind fold into one reason
+		{bucket: b2, endpointID: "ep_b", lastErr: "dial tcp 10.0.0.1:80: connect: connection refused", count: 3},
+		{bucket: b2, endpointID: "ep_b", lastErr: "dial tcp 10.0.0.2:80: connect: connection refused", count: 2},
+	}
+
+	buckets, totals := buildFailureTrends(start, now, 15*time.Minute, groups)
+
+	if len(buckets) != 3 {
+		t.Fatalf("got %d buckets, want 3", len(buckets))
+	}
+	if got := buckets[1].GetStart().AsTime(); !got.Equal(start.Add(15 * time.Minute)) {
+		t.Errorf("bucket[1] start = %v", got)
+	}
+	if len(buckets[1].GetFailures()) != 0 {
+		t.Errorf("bucket[1] = %v, want empty", buckets[1].GetFailures())
+	}
+	if f := buckets[0].GetFailures(); len(f) != 1 || f[0].GetReason() != "http_503" || f[0].GetCount() != 2 {
+		t.Errorf("bucket[0] = %v", f)
+	}
+
+	last := buckets[2].GetFailures()
+	if len(last) != 2 || last[0].GetReason() != "connection_refused" || last[0].GetEndpointId() != "ep_b" || last[0].GetCount() != 5 {
+		t.Errorf("bucket[2] = %v, want connection_refused x5 first", last)
+	}
+
+	want := []struct {
+		reason string
+		count  int64
+	}{{"connection_refused", 5}, {"http_503", 3}}
+	if len(totals) != len(want) {
+		t.Fatalf("totals = %v", totals)
+	}
+	for i, w := range want {
+		if totals[i].GetReason() != w.reason || totals[i].GetCount() != w.count {
+			t.Errorf("totals[%d] = %v, want %s x%d", i, totals[i], w.reason, w.count)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+const (
+	verificationTimeout = 5 * time.Second
+	// verificationResponseLimit caps how much of a challenge response is read
+	verificationResponseLimit = 4096
+)
+
+// endpointVerifier sends verification challenges, signed like deliveries
+type endpointVerifier struct {
+	client          *http.Client
+	signatureHeader string
+	timestampHeader string
+}
+
+// SetEndpointVerification makes new endpoints answer a challenge, signed with the given
+// headers and sent through transport (nil for the default), before they get deliveries.
+// Without it endpoints are verified when created.
+func (s *Server) SetEndpointVerification(signatureHeader, timestampHeader string, transport http.RoundTripper) {
+	s.verifier = &endpointVerifier{
+		client:          &http.Client{Timeout: verificationTimeout, Transport: transport},
+		signatureHeader: signatureHeader,
+		timestampHeader: timestampHeader,
+	}
+}
+
+// challenge POSTs a verification challenge for token to url, signed under scheme (with key for
+// ed25519), and checks the response echoes it
+func (v *endpointVerifier) challenge(ctx context.Context, url, secret string, key ed25519.PrivateKey, scheme, token string) error {
+	body, err := json.Marshal(delivery.NewChallenge(token))
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(v.timestampHeader, ts)
+	req.Header.Set(v.signatureHeader, delivery.SignRequest(scheme, secret, key, req.Method, req.URL.RequestURI(), body, ts))
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	answer, err := io.ReadAll(io.LimitReader(resp.Body, verificationResponseLimit))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("endpoint answered HTTP %d", resp.StatusCode)
+	}
+	if !delivery.ChallengeAnswered(answer, token) {
+		return errors.New("endpoint did not echo the challenge token")
+	}
+	return nil
+}
+
+// markVerified records that an endpoint answered its challenge and returns when
+func (s *Server) markVerified(ctx context.Context, endpointID string) (time.Time, error) {
+	var verifiedAt time.Time
+	err := s.pool.QueryRow(ctx, `
+		UPDATE harborhook.endpoints
+		SET verified_at = COALESCE(verified_at, now()), verification_token = NULL
+		WHERE id = $1
+		RETURNING verified_at`, endpointID).Scan(&verifiedAt)
+	return verifiedAt, err
+}
+
+// VerifyEndpoint verifies an endpoint with the token from its challenge. Without a token the
+// challenge is sent again, and the endpoint is verified if it echoes it.
+func (s *Server) VerifyEndpoint(ctx context.Context, req *webhookv1.VerifyEndpointRequest) (*webhookv1.VerifyEndpointResponse, error) {
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		endpointURL string
+		secret      sql.NullString
+		scheme      string
+		token       sql.NullString
+		createdAt   time.Time
+		verifiedAt  sql.NullTime
+	)
+	err = s.pool.QueryRow(ctx, `
+		SELECT url, secret, signature_scheme, verification_token, created_at, verified_at
+		FROM harborhook.endpoints
+		WHERE id = $1 AND tenant_id = $2`,
+		req.GetEndpointId(), tenantID,
+	).Scan(&endpointURL, &secret, &scheme, &token, &createdAt, &verifiedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apierr.NotFound("endpoint %s not found", req.GetEndpointId())
+	}
+	if err != nil {
+		return nil, err
+	}
+	ep := &webhookv1.Endpoint{
+		Id:         req.GetEndpointId(),
+		TenantId:   tenantID,
+		Url:        endpointURL,
+		CreatedAt:  timestamppb.New(createdAt),
+		VerifiedAt: toTS(verifiedAt),
+	}
+	if verifiedAt.Valid {
+		return &webhookv1.VerifyEndpointResponse{Endpoint: ep}, nil
+	}
+
+	switch {
+	case req.GetToken() != "":
+		if !token.Valid || subtle.ConstantTimeCompare([]byte(req.GetToken()), []byte(token.String)) != 1 {
+			return nil, apierr.Invalid("token does not match the endpoint's challenge")
+		}
+	case s.verifier == nil:
+		return nil, apierr.Invalid("token is required when endpoint verification is disabled")
+	case !token.Valid:
+		return nil, apierr.FailedPrecondition("endpoint %s has no pending challenge", req.GetEndpointId())
+	default:
+		var key ed25519.PrivateKey
+		if scheme == delivery.SignatureEd25519 {
+			if key, err = s.signingKey(ctx, tenantID); err != nil {
+				return nil, err
+			}
+		}
+		tracing.AddSpanEvent(ctx, "endpoint.challenge")
+		if err := s.verifier.challenge(ctx, endpointURL, secret.String, key, scheme, token.String); err != nil {
+			return nil, apierr.FailedPrecondition("verification challenge failed: %w", err)
+		}
+	}
+
+	at, err := s.markVerified(ctx, req.GetEndpointId())
+	if err != nil {
+		return nil, err
+	}
+	ep.VerifiedAt = timestamppb.New(at)
+	return &webhookv1.VerifyEndpointResponse{Endpoint: ep}, nil
+}
@@ -0,0 +1,169 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// testSigningKey is the tenant signing key verifyPool serves
+var testSigningKey = ed25519.NewKeyFromSeed(bytes.Repeat([]byte{7}, ed25519.SeedSize))
+
+// verifyPool serves an unverified endpoint at url, signed under scheme, with challenge token,
+// and records whether it was marked verified
+func verifyPool(url, scheme, token string, marked *bool) *dbfake.Pool {
+	now := time.Now()
+	return &dbfake.Pool{
+		QueryRowFunc: func(sql string, args []any) pgx.Row {
+			switch {
+			case strings.Contains(sql, "INSERT INTO harborhook.endpoints"):
+				return dbfake.Row{Values: []any{"ep_1", now, nil}}
+			case strings.Contains(sql, "UPDATE harborhook.endpoints"):
+				*marked = true
+				return dbfake.Row{Values: []any{now}}
+			case strings.Contains(sql, "FROM harborhook.endpoints"):
+				return dbfake.Row{Values: []any{url, "s3cret", scheme, token, now, nil}}
+			case strings.Contains(sql, "harborhook.tenant_signing_keys"):
+				return dbfake.Row{Values: []any{testSigningKey.Seed()}}
+			}
+			return dbfake.Row{Err: pgx.ErrNoRows}
+		},
+	}
+}
+
+// challengeReceiver answers verification challenges with answer(token), after checking they are
+// signed under scheme
+func challengeReceiver(t *testing.T, scheme string, answer func(token string) string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts := r.Header.Get("X-HarborHook-Timestamp")
+		if r.Header.Get("X-HarborHook-Signature") != delivery.SignRequest(scheme, "s3cret", testSigningKey, r.Method, r.URL.RequestURI(), body, ts) {
+			http.Error(w, "bad signature", http.StatusUnauthorized)
+			return
+		}
+		token, ok := delivery.ParseChallenge(body)
+		if !ok {
+			http.Error(w, "not a challenge", http.StatusBadRequest)
+			return
+		}
+		_, _ = io.WriteString(w, answer(token))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestServer_VerifyEndpoint_Token(t *testing.T) {
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{name: "matching token", token: "tok_123"},
+		{name: "wrong token", token: "tok_456", wantErr: "token does not match the endpoint's challenge"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var marked bool
+			server := NewServer(verifyPool("http://receiver.invalid/hook", delivery.SignatureV1, "tok_123", &marked), nil)
+
+			resp, err := server.VerifyEndpoint(context.Background(), &webhookv1.VerifyEndpointRequest{
+				TenantId: "tn_1", EndpointId: "ep_1", Token: tt.token,
+			})
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("VerifyEndpoint() error = %v, want %q", err, tt.wantErr)
+				}
+				if marked {
+					t.Error("endpoint marked verified with the wrong token")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyEndpoint() unexpected error: %v", err)
+			}
+			if !marked || resp.Endpoint.VerifiedAt == nil {
+				t.Errorf("endpoint not verified: marked=%v verified_at=%v", marked, resp.Endpoint.VerifiedAt)
+			}
+		})
+	}
+}
+
+func TestServer_VerifyEndpoint_ResendsChallenge(t *testing.T) {
+	// The resent challenge is signed the way the endpoint's deliveries will be
+	for _, scheme := range []string{delivery.SignatureV2, delivery.SignatureEd25519} {
+		t.Run(scheme, func(t *testing.T) {
+			receiver := challengeReceiver(t, scheme, func(token string) string { return token })
+			var marked bool
+			server := NewServer(verifyPool(receiver.URL, scheme, "tok_123", &marked), nil)
+			server.SetEndpointVerification("X-HarborHook-Signature", "X-HarborHook-Timestamp", nil)
+
+			resp, err := server.VerifyEndpoint(context.Background(), &webhookv1.VerifyEndpointRequest{TenantId: "tn_1", EndpointId: "ep_1"})
+			if err != nil {
+				t.Fatalf("VerifyEndpoint() unexpected error: %v", err)
+			}
+			if !marked || resp.Endpoint.VerifiedAt == nil {
+				t.Errorf("endpoint not verified after echoing the challenge: marked=%v", marked)
+			}
+		})
+	}
+}
+
+func TestServer_CreateEndpoint_Challenge(t *testing.T) {
+	tests := []struct {
+		name     string
+		answer   func(token string) string
+		verified bool
+		wantErr  string
+	}{
+		{
+			name: "echoed as json",
+			answer: func(token string) string {
+				b, _ := json.Marshal(map[string]string{"challenge": token})
+				return string(b)
+			},
+			verified: true,
+		},
+		{
+			name:    "not echoed",
+			answer:  func(string) string { return "ok" },
+			wantErr: "endpoint did not echo the challenge token",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := challengeReceiver(t, delivery.SignatureV1, tt.answer)
+			var marked bool
+			server := NewServer(verifyPool(receiver.URL, delivery.SignatureV1, "", &marked), nil)
+			server.SetEndpointVerification("X-HarborHook-Signature", "X-HarborHook-Timestamp", nil)
+
+			resp, err := server.CreateEndpoint(context.Background(), &webhookv1.CreateEndpointRequest{
+				TenantId: "tn_1", Url: receiver.URL, Secret: "s3cret",
+			})
+			if err != nil {
+				t.Fatalf("CreateEndpoint() unexpected error: %v", err)
+			}
+			if got := resp.Endpoint.VerifiedAt != nil; got != tt.verified || marked != tt.verified {
+				t.Errorf("verified = %v (marked %v), want %v", got, marked, tt.verified)
+			}
+			if resp.VerificationError != tt.wantErr {
+				t.Errorf("VerificationError = %q, want %q", resp.VerificationError, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package ingest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+const (
+	defaultWatchInterval = time.Second
+	minWatchInterval     = 250 * time.Millisecond
+	// watchWindow is how many deliveries each poll compares; an endpoint watch follows its newest ones
+	watchWindow = 500
+)
+
+// WatchDeliveryStatus streams every status change of an event's or endpoint's deliveries.
+// The first poll sends each delivery's current status (only unfinished ones with skip_finished).
+// The deliveries table is polled rather than LISTENed to, so watches don't each hold a
+// database connection.
+func (s *Server) WatchDeliveryStatus(req *webhookv1.WatchDeliveryStatusRequest, stream grpc.ServerStreamingServer[webhookv1.WatchDeliveryStatusResponse]) error {
+	if req.GetEventId() == "" && req.GetEndpointId() == "" {
+		return apierr.Invalid("event_id or endpoint_id is required")
+	}
+	interval := defaultWatchInterval
+	if ms := req.GetPollIntervalMs(); ms > 0 {
+		interval = max(time.Duration(ms)*time.Millisecond, minWatchInterval)
+	}
+	ctx := stream.Context()
+	tenantID, err := scopeTenant(ctx, req.GetTenantId())
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]webhookv1.DeliveryAttemptStatus{}
+	first := true
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		attempts, err := s.watchedDeliveries(ctx, req.GetEventId(), req.GetEndpointId(), tenantID)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		next := make(map[string]webhookv1.DeliveryAttemptStatus, len(attempts))
+		done := len(attempts) > 0
+		for _, a := range attempts {
+			next[a.DeliveryId] = a.Status
+			if isActiveStatus(a.Status) {
+				done = false
+			}
+			prev, ok := seen[a.DeliveryId]
+			if ok && prev == a.Status {
+				continue
+			}
+			if first && req.GetSkipFinished() && !isActiveStatus(a.Status) {
+				continue
+			}
+			if err := stream.Send(&webhookv1.WatchDeliveryStatusResponse{Delivery: a, PreviousStatus: prev}); err != nil {
+				return err
+			}
+		}
+		// Only the current window is kept, so a long endpoint watch doesn't grow without bound
+		seen, first = next, false
+
+		if req.GetEventId() != "" && !req.GetFollow() && done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// watchedDeliveries returns the watched deliveries, oldest first. Deliveries outside tenantID's
+// endpoints are left out when it is set.
+func (s *Server) watchedDeliveries(ctx context.Context, eventID, endpointID, tenantID string) ([]*webhookv1.DeliveryAttempt, error) {
+	rows, err := s.pool.Query(ctx, `
+		SELECT * FROM (
+			SELECT d.id, d.event_id, d.endpoint_id, d.replay_of, d.status, d.http_status,
+			       COALESCE(d.error_reason, d.last_error),
+			       d.enqueued_at, d.dequeued_at, d.sent_at, d.delivered_at, d.failed_at, d.dlq_at, d.acked_at
+			FROM harborhook.deliveries d
+			JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+			WHERE (NULLIF($1, '') IS NULL OR d.event_id = NULLIF($1, '')::uuid)
+			  AND (NULLIF($2, '') IS NULL OR d.endpoint_id = NULLIF($2, '')::uuid)
+			  AND (NULLIF($3, '') IS NULL OR ep.tenant_id = $3)
+			ORDER BY d.created_at DESC
+			LIMIT $4
+		) w
+		ORDER BY w.enqueued_at ASC`,
+		eventID, endpointID, tenantID, watchWindow)
+	if err != nil {
+		return nil, fmt.Errorf("watch deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var out []*webhookv1.DeliveryAttempt
+	for rows.Next() {
+		var (
+			id, evID, epID                          string
+			replayOf, statusStr, errReason          sql.NullString
+			httpStatus                              sql.NullInt32
+			enq, deq, sent, deliv, fail, dlq, acked sql.NullTime
+		)
+		if err := rows.Scan(&id, &evID, &epID, &replayOf, &statusStr, &httpStatus, &errReason,
+			&enq, &deq, &sent, &deliv, &fail, &dlq, &acked,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, &webhookv1.DeliveryAttempt{
+			DeliveryId:  id,
+			EventId:     evID,
+			EndpointId:  epID,
+			ReplayOf:    nullStr(replayOf),
+			Status:      mapStatus(nullStr(statusStr)),
+			HttpStatus:  nullI32(httpStatus),
+			ErrorReason: nullStr(errReason),
+			EnqueuedAt:  toTS(enq),
+			DequeuedAt:  toTS(deq),
+			SentAt:      toTS(sent),
+			DeliveredAt: toTS(deliv),
+			FailedAt:    toTS(fail),
+			DlqAt:       toTS(dlq),
+			AckedAt:     toTS(acked),
+		})
+	}
+	return out, rows.Err()
+}
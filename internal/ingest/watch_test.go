@@ -0,0 +1,109 @@
+package ingest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// fakeWatchStream collects what the server sends
+type fakeWatchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*webhookv1.WatchDeliveryStatusResponse
+}
+
+func (s *fakeWatchStream) Context() context.Context { return s.ctx }
+
+func (s *fakeWatchStream) Send(r *webhookv1.WatchDeliveryStatusResponse) error {
+	s.sent = append(s.sent, r)
+	return nil
+}
+
+// watchRow is a deliveries row as watchedDeliveries selects it
+func watchRow(id, status string) []any {
+	return []any{id, "evt_1", "ep_1", nil, status, nil, nil, nil, nil, nil, nil, nil, nil, nil}
+}
+
+func TestServer_WatchDeliveryStatus_Validation(t *testing.T) {
+	server := &Server{}
+
+	err := server.WatchDeliveryStatus(&webhookv1.WatchDeliveryStatusRequest{}, &fakeWatchStream{ctx: context.Background()})
+	if err == nil || err.Error() != "event_id or endpoint_id is required" {
+		t.Errorf("WatchDeliveryStatus() error = %v, want %q", err, "event_id or endpoint_id is required")
+	}
+}
+
+func TestServer_WatchDeliveryStatus_SkipFinished(t *testing.T) {
+	server := NewServer(&dbfake.Pool{
+		QueryFunc: func(string, []any) (pgx.Rows, error) {
+			return dbfake.NewRows(watchRow("del_a", "delivered"), watchRow("del_b", "dead")), nil
+		},
+	}, nil)
+
+	stream := &fakeWatchStream{ctx: context.Background()}
+	req := &webhookv1.WatchDeliveryStatusRequest{EventId: "evt_1", SkipFinished: true}
+	if err := server.WatchDeliveryStatus(req, stream); err != nil {
+		t.Fatalf("WatchDeliveryStatus() unexpected error: %v", err)
+	}
+	if len(stream.sent) != 0 {
+		t.Errorf("sent %v, want nothing for deliveries finished before the watch", stream.sent)
+	}
+}
+
+func TestServer_WatchDeliveryStatus_StreamsChanges(t *testing.T) {
+	polls := [][][]any{
+		{watchRow("del_a", "queued"), watchRow("del_b", "inflight")},
+		{watchRow("del_a", "queued"), watchRow("del_b", "delivered")},
+		{watchRow("del_a", "dead"), watchRow("del_b", "delivered")},
+	}
+	var n int
+	server := NewServer(&dbfake.Pool{
+		QueryFunc: func(string, []any) (pgx.Rows, error) {
+			rows := polls[min(n, len(polls)-1)]
+			n++
+			return dbfake.NewRows(rows...), nil
+		},
+	}, nil)
+
+	stream := &fakeWatchStream{ctx: context.Background()}
+	err := server.WatchDeliveryStatus(&webhookv1.WatchDeliveryStatusRequest{EventId: "evt_1", PollIntervalMs: 1}, stream)
+	if err != nil {
+		t.Fatalf("WatchDeliveryStatus() unexpected error: %v", err)
+	}
+
+	const (
+		unspecified = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED
+		queued      = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_QUEUED
+		inFlight    = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_IN_FLIGHT
+		delivered   = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED
+		dead        = webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED
+	)
+	want := []struct {
+		id       string
+		from, to webhookv1.DeliveryAttemptStatus
+	}{
+		{"del_a", unspecified, queued},
+		{"del_b", unspecified, inFlight},
+		{"del_b", inFlight, delivered},
+		{"del_a", queued, dead},
+	}
+	if len(stream.sent) != len(want) {
+		t.Fatalf("sent %d changes, want %d: %v", len(stream.sent), len(want), stream.sent)
+	}
+	for i, w := range want {
+		got := stream.sent[i]
+		if got.Delivery.DeliveryId != w.id || got.PreviousStatus != w.from || got.Delivery.Status != w.to {
+			t.Errorf("change %d = %s %v → %v, want %s %v → %v", i,
+				got.Delivery.DeliveryId, got.PreviousStatus, got.Delivery.Status, w.id, w.from, w.to)
+		}
+	}
+	if n != len(polls) {
+		t.Errorf("polled %d times, want the stream to end after %d", n, len(polls))
+	}
+}
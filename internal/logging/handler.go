@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Attribute keys entries are written with
+const (
+	ServiceKey    = "service"
+	TraceIDKey    = "trace_id"
+	SpanIDKey     = "span_id"
+	TenantIDKey   = "tenant_id"
+	EventIDKey    = "event_id"
+	DeliveryIDKey = "delivery_id"
+	EndpointIDKey = "endpoint_id"
+	FieldsKey     = "fields"
+)
+
+// Tenant is the tenant_id attribute, for use with slog loggers
+func Tenant(id string) slog.Attr { return slog.String(TenantIDKey, id) }
+
+// Event is the event_id attribute
+func Event(id string) slog.Attr { return slog.String(EventIDKey, id) }
+
+// Delivery is the delivery_id attribute
+func Delivery(id string) slog.Attr { return slog.String(DeliveryIDKey, id) }
+
+// Endpoint is the endpoint_id attribute
+func Endpoint(id string) slog.Attr { return slog.String(EndpointIDKey, id) }
+
+// TraceID is the trace_id attribute, which correlates logs with traces
+func TraceID(id string) slog.Attr { return slog.String(TraceIDKey, id) }
+
+// LevelFatal's slog level, above slog.LevelError
+const slogLevelFatal = slog.LevelError + 4
+
+var slogLevels = map[LogLevel]slog.Level{
+	LevelDebug: slog.LevelDebug,
+	LevelInfo:  slog.LevelInfo,
+	LevelWarn:  slog.LevelWarn,
+	LevelError: slog.LevelError,
+	LevelFatal: slogLevelFatal,
+}
+
+func slogLevel(l LogLevel) slog.Level {
+	return slogLevels[l]
+}
+
+// levelName is the LogLevel an slog level is written as
+func levelName(l slog.Level) LogLevel {
+	switch {
+	case l >= slogLevelFatal:
+		return LevelFatal
+	case l >= slog.LevelError:
+		return LevelError
+	case l >= slog.LevelWarn:
+		return LevelWarn
+	case l >= slog.LevelInfo:
+		return LevelInfo
+	}
+	return LevelDebug
+}
+
+// minLevel is the least severe level written. It starts at debug, so everything is written until
+// SetLevel is called.
+var minLevel = func() *slog.LevelVar {
+	v := &slog.LevelVar{}
+	v.Set(slog.LevelDebug)
+	return v
+}()
+
+// ParseLevel returns the level named s
+func ParseLevel(s string) (LogLevel, error) {
+	l := LogLevel(strings.ToLower(strings.TrimSpace(s)))
+	if l == "warning" {
+		l = LevelWarn
+	}
+	if _, ok := slogLevels[l]; !ok {
+		return "", fmt.Errorf("unknown log level %q (want debug, info, warn or error)", s)
+	}
+	return l, nil
+}
+
+// SetLevel drops entries less severe than l from now on, for every logger
+func SetLevel(l LogLevel) {
+	minLevel.Set(slogLevel(l))
+}
+
+// GetLevel returns the least severe level written
+func GetLevel() LogLevel {
+	return levelName(minLevel.Level())
+}
+
+// Enabled reports whether entries at l are written
+func Enabled(l LogLevel) bool {
+	return slogLevel(l) >= minLevel.Level()
+}
+
+// Log formats
+const (
+	FormatJSON = "json" // one JSON object per line (the default)
+	FormatText = "text" // slog's key=value lines
+)
+
+// Options selects the handler entries are written with
+type Options struct {
+	Level      string   // debug, info, warn or error; empty leaves the level as is
+	Format     string   // FormatJSON or FormatText; empty is FormatJSON
+	File       string   // Path entries are appended to instead of stdout
+	MaxSizeMB  int      // Size at which File is rotated; 0 never rotates
+	MaxBackups int      // Rotated files kept, as File.1 (the newest) to File.<n>
+	RedactKeys []string // Keys redacted on top of DefaultRedactKeys
+}
+
+// handler writes every logger's entries
+var handler atomic.Pointer[slog.Handler]
+
+func init() {
+	h, _ := NewHandler(Options{})
+	SetHandler(h)
+}
+
+// Handler returns the handler entries are currently written with
+func Handler() slog.Handler {
+	return *handler.Load()
+}
+
+// SetHandler writes every logger's entries with h from now on. Any slog.Handler may be used,
+// e.g. to ship logs elsewhere or to fan them out to several handlers. Entries are redacted before
+// they reach h.
+func SetHandler(h slog.Handler) {
+	if _, ok := h.(redactHandler); !ok {
+		h = redactHandler{next: h}
+	}
+	handler.Store(&h)
+}
+
+// Configure sets the level, the redacted keys and the handler from o
+func Configure(o Options) error {
+	if o.Level != "" {
+		level, err := ParseLevel(o.Level)
+		if err != nil {
+			return err
+		}
+		SetLevel(level)
+	}
+	h, err := NewHandler(o)
+	if err != nil {
+		return err
+	}
+	SetRedactKeys(o.RedactKeys)
+	SetHandler(h)
+	return nil
+}
+
+// NewHandler builds the handler o selects. Its level is the package's, so SetLevel applies to it.
+func NewHandler(o Options) (slog.Handler, error) {
+	var w io.Writer = stdout{}
+	if o.File != "" {
+		f, err := openRotatingFile(o.File, int64(o.MaxSizeMB)<<20, o.MaxBackups)
+		if err != nil {
+			return nil, err
+		}
+		w = f
+	}
+
+	opts := &slog.HandlerOptions{Level: minLevel, ReplaceAttr: replaceAttr}
+	switch o.Format {
+	case "", FormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	case FormatText:
+		return slog.NewTextHandler(w, opts), nil
+	}
+	return nil, fmt.Errorf("unknown log format %q (want %s or %s)", o.Format, FormatJSON, FormatText)
+}
+
+// replaceAttr writes levels by their LogLevel names, e.g. "warn" rather than slog's "WARN"
+func replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 && a.Key == slog.LevelKey {
+		if l, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String(slog.LevelKey, string(levelName(l)))
+		}
+	}
+	return a
+}
+
+// stdout writes to whatever os.Stdout is at the time of the write
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) {
+	return os.Stdout.Write(p)
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// useHandler restores the handler and level a test changes
+func useHandler(t *testing.T) {
+	t.Helper()
+	h, level := Handler(), GetLevel()
+	t.Cleanup(func() {
+		SetHandler(h)
+		SetLevel(level)
+	})
+}
+
+func TestLogEntry_SlogRecord(t *testing.T) {
+	useHandler(t)
+	var buf bytes.Buffer
+	SetHandler(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: minLevel, ReplaceAttr: replaceAttr}))
+
+	New("test-service").Plain().WithTenant("tn_1").WithDelivery("dl_1").WithField("attempt", 2).Warn("retrying")
+
+	var got LogEntry
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output %q is not a log entry: %v", buf.String(), err)
+	}
+	if got.Level != LevelWarn || got.Message != "retrying" || got.Service != "test-service" ||
+		got.TenantID != "tn_1" || got.DeliveryID != "dl_1" || got.Fields["attempt"] != 2.0 {
+		t.Errorf("entry = %+v", got)
+	}
+	if strings.Contains(buf.String(), EventIDKey) {
+		t.Errorf("output %q has an empty event_id", buf.String())
+	}
+}
+
+func TestLogger_Slog(t *testing.T) {
+	useHandler(t)
+	var buf bytes.Buffer
+	SetHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: minLevel, ReplaceAttr: replaceAttr}))
+	SetLevel(LevelInfo)
+
+	logger := New("test-service").Slog()
+	logger.Debug("dropped")
+	logger.Info("delivered", Tenant("tn_1"), Endpoint("ep_1"))
+
+	out := buf.String()
+	for _, want := range []string{"level=info", "service=test-service", "tenant_id=tn_1", "endpoint_id=ep_1"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output %q missing %q", out, want)
+		}
+	}
+	if strings.Contains(out, "dropped") {
+		t.Errorf("output %q has an entry below the level", out)
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	if _, err := NewHandler(Options{Format: "xml"}); err == nil {
+		t.Error("NewHandler() expected an error for an unknown format")
+	}
+	if err := Configure(Options{Level: "loud"}); err == nil {
+		t.Error("Configure() expected an error for an unknown level")
+	}
+
+	useHandler(t)
+	path := filepath.Join(t.TempDir(), "harborhook.log")
+	if err := Configure(Options{Level: "warn", Format: FormatText, File: path}); err != nil {
+		t.Fatalf("Configure() unexpected error: %v", err)
+	}
+	New("test-service").Plain().Info("dropped")
+	New("test-service").Plain().Error("written")
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(data); !strings.Contains(got, "level=error") || !strings.Contains(got, `msg=written`) || strings.Contains(got, "dropped") {
+		t.Errorf("log file = %q, want only the error entry as text", got)
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "harborhook.log")
+	f, err := openRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write(%q): %v", line, err)
+		}
+	}
+
+	for name, want := range map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"} {
+		if got, err := os.ReadFile(name); err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(name), got, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 exists, want at most 2 backups", filepath.Base(path))
+	}
+}
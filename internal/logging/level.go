@@ -0,0 +1,46 @@
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// levelBody is the body LevelHandler accepts and returns
+type levelBody struct {
+	Level LogLevel `json:"level"`
+}
+
+// LevelHandler serves the log level: GET returns it, POST sets it from a {"level": "debug"} body
+// or a level query parameter. Callers must be authenticated before reaching it.
+func LevelHandler(logger *Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPost:
+			var body levelBody
+			if name := r.URL.Query().Get("level"); name != "" {
+				body.Level = LogLevel(name)
+			} else if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, "invalid body: want {\"level\": \"debug|info|warn|error\"}", http.StatusBadRequest)
+				return
+			}
+			level, err := ParseLevel(string(body.Level))
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			previous := GetLevel()
+			SetLevel(level)
+			// Written whatever the new level, so the change itself is always on record
+			entry := logger.Plain().WithFields(map[string]any{"from": previous, "to": level})
+			entry.Level, entry.Message = LevelInfo, "log level changed"
+			entry.write()
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levelBody{Level: GetLevel()})
+	}
+}
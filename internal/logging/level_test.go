@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// useLevel restores the level SetLevel changes once the test is done
+func useLevel(t *testing.T) {
+	t.Helper()
+	previous := GetLevel()
+	t.Cleanup(func() { SetLevel(previous) })
+}
+
+func TestParseLevel(t *testing.T) {
+	for in, want := range map[string]LogLevel{"debug": LevelDebug, " INFO ": LevelInfo, "warning": LevelWarn, "error": LevelError} {
+		if got, err := ParseLevel(in); err != nil || got != want {
+			t.Errorf("ParseLevel(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("ParseLevel(verbose) expected an error")
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	useLevel(t)
+	SetLevel(LevelWarn)
+	if GetLevel() != LevelWarn {
+		t.Errorf("GetLevel() = %q, want warn", GetLevel())
+	}
+	if Enabled(LevelInfo) || !Enabled(LevelWarn) || !Enabled(LevelError) {
+		t.Error("at warn, info should be dropped and warn and error written")
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	useLevel(t)
+	SetLevel(LevelInfo)
+	handler := LevelHandler(New("test-service"))
+
+	tests := []struct {
+		name     string
+		method   string
+		target   string
+		body     string
+		wantCode int
+		want     LogLevel
+	}{
+		{name: "get", method: http.MethodGet, target: "/admin/loglevel", wantCode: http.StatusOK, want: LevelInfo},
+		{name: "post body", method: http.MethodPost, target: "/admin/loglevel", body: `{"level":"debug"}`, wantCode: http.StatusOK, want: LevelDebug},
+		{name: "post query", method: http.MethodPost, target: "/admin/loglevel?level=error", wantCode: http.StatusOK, want: LevelError},
+		{name: "unknown level", method: http.MethodPost, target: "/admin/loglevel", body: `{"level":"loud"}`, wantCode: http.StatusBadRequest, want: LevelError},
+		{name: "bad body", method: http.MethodPost, target: "/admin/loglevel", body: `debug`, wantCode: http.StatusBadRequest, want: LevelError},
+		{name: "wrong method", method: http.MethodDelete, target: "/admin/loglevel", wantCode: http.StatusMethodNotAllowed, want: LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body)))
+			if rec.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantCode, rec.Body.String())
+			}
+			if GetLevel() != tt.want {
+				t.Errorf("level = %q, want %q", GetLevel(), tt.want)
+			}
+			if tt.wantCode == http.StatusOK && !strings.Contains(rec.Body.String(), `"level":"`+string(tt.want)+`"`) {
+				t.Errorf("body = %s, want level %q", rec.Body.String(), tt.want)
+			}
+		})
+	}
+}
@@ -2,9 +2,10 @@ package logging
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/austindbirch/harbor_hook/internal/tracing"
@@ -36,7 +37,8 @@ type LogEntry struct {
 	Fields     map[string]any    `json:"fields,omitempty"`
 }
 
-// Logger provides structured logging with trace correlation
+// Logger provides structured logging with trace correlation. Entries are written through the
+// package's slog handler; see Configure and SetHandler.
 type Logger struct {
 	service string
 }
@@ -83,6 +85,12 @@ func (l *Logger) Plain() *LogEntry {
 	}
 }
 
+// Slog returns a slog.Logger writing through the current handler with the service attribute,
+// for code and libraries that take a *slog.Logger
+func (l *Logger) Slog() *slog.Logger {
+	return slog.New(Handler()).With(ServiceKey, l.service)
+}
+
 // Fluent interface methods for LogEntry
 
 // WithTraceID sets the trace ID for the log entry
@@ -220,22 +228,46 @@ func (e *LogEntry) Fatalf(format string, args ...any) {
 	os.Exit(1)
 }
 
-// output writes the log entry to stdout as JSON
+// output writes the log entry unless its level is disabled
 func (e *LogEntry) output() {
-	// Clean up empty fields
-	if len(e.Fields) == 0 {
-		e.Fields = nil
+	if Enabled(e.Level) {
+		e.write()
 	}
-	
-	data, err := json.Marshal(e)
-	if err != nil {
-		// Fallback to plain text if JSON marshaling fails
+}
+
+// write hands the log entry to the current handler as a slog record
+func (e *LogEntry) write() {
+	r := slog.NewRecord(e.Time, slogLevel(e.Level), e.Message, 0)
+	for _, a := range []slog.Attr{
+		slog.String(ServiceKey, e.Service),
+		TraceID(e.TraceID),
+		slog.String(SpanIDKey, e.SpanID),
+		Tenant(e.TenantID),
+		Event(e.EventID),
+		Delivery(e.DeliveryID),
+		Endpoint(e.EndpointID),
+	} {
+		if a.Value.String() != "" {
+			r.AddAttrs(a)
+		}
+	}
+	if len(e.Fields) > 0 {
+		keys := make([]string, 0, len(e.Fields))
+		for k := range e.Fields {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := make([]slog.Attr, 0, len(keys))
+		for _, k := range keys {
+			fields = append(fields, slog.Any(k, e.Fields[k]))
+		}
+		r.AddAttrs(slog.Attr{Key: FieldsKey, Value: slog.GroupValue(fields...)})
+	}
+
+	if err := Handler().Handle(context.Background(), r); err != nil {
 		fmt.Fprintf(os.Stderr, "logging error: %v\n", err)
 		fmt.Printf("%s [%s] %s\n", e.Time.Format(time.RFC3339), e.Level, e.Message)
-		return
 	}
-	
-	fmt.Println(string(data))
 }
 
 // Global convenience functions
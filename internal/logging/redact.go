@@ -0,0 +1,183 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+)
+
+// Redacted replaces sensitive values in logs and spans
+const Redacted = "[REDACTED]"
+
+// DefaultRedactKeys are the JSON keys, field names, query parameters and headers whose values are
+// always redacted. Keys match case-insensitively, with - and _ interchangeable.
+var DefaultRedactKeys = []string{
+	"password", "passwd", "secret", "client_secret", "token", "access_token", "refresh_token",
+	"id_token", "api_key", "apikey", "private_key", "authorization", "proxy_authorization",
+	"cookie", "set_cookie", "x_api_key", "x_harborhook_signature", "signature",
+	"recording_encryption_key",
+}
+
+// redactor masks the values of a set of keys
+type redactor struct {
+	keys map[string]bool
+	// keyValue matches key=value and key: value pairs, with an optional auth scheme, in text
+	keyValue *regexp.Regexp
+}
+
+var (
+	// bearerRe matches bearer credentials outside a key: value pair
+	bearerRe = regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`)
+	// userinfoRe matches the user:password@ of a URL
+	userinfoRe = regexp.MustCompile(`(://)[^/\s:@]+:[^/\s@]+@`)
+)
+
+var currentRedactor atomic.Pointer[redactor]
+
+func init() {
+	SetRedactKeys(nil)
+	tracing.SetRedactor(redactValue)
+}
+
+// SetRedactKeys redacts the values of keys, on top of DefaultRedactKeys, from now on
+func SetRedactKeys(keys []string) {
+	r := &redactor{keys: map[string]bool{}}
+	var alts []string
+	for _, k := range append(append([]string{}, DefaultRedactKeys...), keys...) {
+		k = normalizeKey(k)
+		if k == "" || r.keys[k] {
+			continue
+		}
+		r.keys[k] = true
+		// Either separator matches in text, as headers use - and JSON keys usually _
+		alts = append(alts, strings.ReplaceAll(regexp.QuoteMeta(k), "_", "[-_]"))
+	}
+	r.keyValue = regexp.MustCompile(`(?i)\b(` + strings.Join(alts, "|") + `)("?\s*[:=]\s*"?)(?:(?:bearer|basic)\s+)?[^\s,;&"'}]+`)
+	currentRedactor.Store(r)
+}
+
+func normalizeKey(k string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(k)), "-", "_")
+}
+
+// RedactText masks secrets in free text: values of the redacted keys in key=value and key: value
+// pairs (query strings, headers), bearer tokens, URL passwords, and the redacted keys of text that
+// is a JSON object or array
+func RedactText(s string) string {
+	return currentRedactor.Load().text(s)
+}
+
+// redactValue is the redactor tracing applies to span attributes and errors
+func redactValue(key, value string) string {
+	r := currentRedactor.Load()
+	if r.keys[normalizeKey(key)] {
+		return Redacted
+	}
+	return r.text(value)
+}
+
+func (r *redactor) text(s string) string {
+	if t := strings.TrimSpace(s); strings.HasPrefix(t, "{") || strings.HasPrefix(t, "[") {
+		var v any
+		if json.Unmarshal([]byte(t), &v) == nil {
+			if b, err := json.Marshal(r.json(v)); err == nil {
+				return string(b)
+			}
+		}
+	}
+	s = r.keyValue.ReplaceAllString(s, "${1}${2}"+Redacted)
+	s = bearerRe.ReplaceAllString(s, "Bearer "+Redacted)
+	return userinfoRe.ReplaceAllString(s, "${1}"+Redacted+"@")
+}
+
+// json redacts a decoded JSON value
+func (r *redactor) json(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			if r.keys[normalizeKey(k)] {
+				out[k] = Redacted
+				continue
+			}
+			out[k] = r.json(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = r.json(val)
+		}
+		return out
+	case string:
+		return r.text(v)
+	}
+	return v
+}
+
+func (r *redactor) attr(a slog.Attr) slog.Attr {
+	if r.keys[normalizeKey(a.Key)] {
+		return slog.String(a.Key, Redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.text(v.String()))
+	case slog.KindGroup:
+		attrs := v.Group()
+		out := make([]slog.Attr, len(attrs))
+		for i, ga := range attrs {
+			out[i] = r.attr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(out...)}
+	case slog.KindAny:
+		switch x := v.Any().(type) {
+		case map[string]any, []any:
+			return slog.Any(a.Key, r.json(x))
+		case json.RawMessage:
+			return slog.String(a.Key, r.text(string(x)))
+		case []byte:
+			return slog.String(a.Key, r.text(string(x)))
+		case error:
+			return slog.String(a.Key, r.text(x.Error()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// redactHandler redacts records' messages and attributes before handing them on
+type redactHandler struct {
+	next slog.Handler
+}
+
+func (h redactHandler) Enabled(ctx context.Context, l slog.Level) bool {
+	return h.next.Enabled(ctx, l)
+}
+
+func (h redactHandler) Handle(ctx context.Context, rec slog.Record) error {
+	r := currentRedactor.Load()
+	out := slog.NewRecord(rec.Time, rec.Level, r.text(rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(r.attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	r := currentRedactor.Load()
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = r.attr(a)
+	}
+	return redactHandler{next: h.next.WithAttrs(out)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,84 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Business KPIs for exec dashboards. They come from a periodic DB aggregation and are served
+// from their own registry on /metrics/business, apart from the operational metrics.
+var (
+	BusinessActiveTenants = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "harborhook_business_active_tenants",
+			Help: "Tenants that published at least one event in the last 24 hours.",
+		},
+	)
+
+	BusinessActiveEndpoints = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "harborhook_business_active_endpoints",
+			Help: "Endpoints that were sent at least one delivery in the last 24 hours.",
+		},
+	)
+
+	BusinessEventsPerDay = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "harborhook_business_events_per_day",
+			Help: "Events published in the last 24 hours.",
+		},
+	)
+
+	BusinessDLQRatio = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "harborhook_business_dlq_ratio",
+			Help: "Share of deliveries finished in the last 24 hours that ended in the DLQ (0-1).",
+		},
+	)
+
+	BusinessRefreshedAt = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "harborhook_business_refreshed_timestamp_seconds",
+			Help: "Unix time of the last successful KPI aggregation.",
+		},
+	)
+)
+
+// BusinessKPIs is one aggregation of the business metrics over the last 24 hours
+type BusinessKPIs struct {
+	ActiveTenants   int64
+	ActiveEndpoints int64
+	Events          int64
+	Delivered       int64 // deliveries that finished delivered
+	Dead            int64 // deliveries that finished in the DLQ
+}
+
+// DLQRatio is the share of finished deliveries that ended in the DLQ, 0 when none finished
+func (k BusinessKPIs) DLQRatio() float64 {
+	finished := k.Delivered + k.Dead
+	if finished <= 0 {
+		return 0
+	}
+	return float64(k.Dead) / float64(finished)
+}
+
+// MustRegisterBusiness registers the business KPIs with the provided registry
+func MustRegisterBusiness(reg *prometheus.Registry) {
+	reg.MustRegister(
+		BusinessActiveTenants,
+		BusinessActiveEndpoints,
+		BusinessEventsPerDay,
+		BusinessDLQRatio,
+		BusinessRefreshedAt,
+	)
+}
+
+// UpdateBusinessKPIs sets the business gauges from an aggregation taken at now
+func UpdateBusinessKPIs(k BusinessKPIs, now time.Time) {
+	BusinessActiveTenants.Set(float64(k.ActiveTenants))
+	BusinessActiveEndpoints.Set(float64(k.ActiveEndpoints))
+	BusinessEventsPerDay.Set(float64(k.Events))
+	BusinessDLQRatio.Set(k.DLQRatio())
+	BusinessRefreshedAt.Set(float64(now.Unix()))
+}
@@ -0,0 +1,62 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBusinessKPIs_DLQRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		kpis BusinessKPIs
+		want float64
+	}{
+		{name: "nothing finished", kpis: BusinessKPIs{}, want: 0},
+		{name: "all delivered", kpis: BusinessKPIs{Delivered: 10}, want: 0},
+		{name: "one in four dead", kpis: BusinessKPIs{Delivered: 3, Dead: 1}, want: 0.25},
+		{name: "all dead", kpis: BusinessKPIs{Dead: 5}, want: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.kpis.DLQRatio(); got != tt.want {
+				t.Errorf("DLQRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateBusinessKPIs(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	MustRegisterBusiness(reg)
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	UpdateBusinessKPIs(BusinessKPIs{ActiveTenants: 4, ActiveEndpoints: 9, Events: 1200, Delivered: 90, Dead: 10}, now)
+
+	checks := map[string]struct {
+		gauge prometheus.Gauge
+		want  float64
+	}{
+		"active tenants":   {BusinessActiveTenants, 4},
+		"active endpoints": {BusinessActiveEndpoints, 9},
+		"events per day":   {BusinessEventsPerDay, 1200},
+		"dlq ratio":        {BusinessDLQRatio, 0.1},
+		"refreshed at":     {BusinessRefreshedAt, float64(now.Unix())},
+	}
+	for name, c := range checks {
+		if got := testutil.ToFloat64(c.gauge); got != c.want {
+			t.Errorf("%s = %v, want %v", name, got, c.want)
+		}
+	}
+
+	families, err := reg.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error: %v", err)
+	}
+	if len(families) != 5 {
+		t.Errorf("business registry has %d metrics, want 5", len(families))
+	}
+}
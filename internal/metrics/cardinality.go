@@ -0,0 +1,123 @@
+package metrics
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sync"
+)
+
+// OtherLabel is the value tenants and endpoints outside the label policy are recorded as
+const OtherLabel = "other"
+
+// Label modes: how tenant_id and endpoint_id label values are bounded
+const (
+	LabelModeAll       = "all"       // every ID as is (the default)
+	LabelModeAllowlist = "allowlist" // listed IDs as is, the rest as other
+	LabelModeHash      = "hash"      // IDs hashed into HashBuckets buckets, hash_0 to hash_<n-1>
+	LabelModeTopK      = "topk"      // the first TopK IDs seen per label as is, the rest as other
+)
+
+// LabelPolicy bounds the tenant_id and endpoint_id label values, which otherwise grow a series
+// per tenant and endpoint. Counters and histograms are aggregated into the bounded values;
+// gauges are only recorded for IDs kept as they are, since gauges of different IDs can't be
+// combined.
+type LabelPolicy struct {
+	Mode        string
+	Allowlist   []string // Tenant and endpoint IDs kept in allowlist mode
+	HashBuckets int      // Buckets in hash mode
+	TopK        int      // IDs kept per label in topk mode; under load the first seen are the busiest
+}
+
+// labelLimiter applies a LabelPolicy
+type labelLimiter struct {
+	policy LabelPolicy
+	allow  map[string]bool
+
+	mu   sync.Mutex
+	seen map[string]map[string]bool // label -> IDs admitted in topk mode
+}
+
+var (
+	limiterMu sync.RWMutex
+	limiter   = &labelLimiter{policy: LabelPolicy{Mode: LabelModeAll}}
+)
+
+// SetLabelPolicy bounds the tenant and endpoint label values recorded from now on. An empty
+// mode is LabelModeAll.
+func SetLabelPolicy(p LabelPolicy) error {
+	l := &labelLimiter{policy: p, seen: map[string]map[string]bool{}}
+	switch p.Mode {
+	case "":
+		l.policy.Mode = LabelModeAll
+	case LabelModeAll:
+	case LabelModeAllowlist:
+		l.allow = make(map[string]bool, len(p.Allowlist))
+		for _, id := range p.Allowlist {
+			l.allow[id] = true
+		}
+	case LabelModeHash:
+		if p.HashBuckets < 1 {
+			return fmt.Errorf("hash label mode needs at least 1 bucket, got %d", p.HashBuckets)
+		}
+	case LabelModeTopK:
+		if p.TopK < 0 {
+			return fmt.Errorf("topk label mode needs a non-negative limit, got %d", p.TopK)
+		}
+	default:
+		return fmt.Errorf("unknown label mode %q (want %s, %s, %s or %s)", p.Mode, LabelModeAll, LabelModeAllowlist, LabelModeHash, LabelModeTopK)
+	}
+	limiterMu.Lock()
+	limiter = l
+	limiterMu.Unlock()
+	return nil
+}
+
+func currentLimiter() *labelLimiter {
+	limiterMu.RLock()
+	defer limiterMu.RUnlock()
+	return limiter
+}
+
+// tenantLabel is the tenant_id value counters and histograms are recorded with
+func tenantLabel(id string) string {
+	v, _ := currentLimiter().value("tenant_id", id)
+	return v
+}
+
+// endpointLabel is the endpoint_id value counters and histograms are recorded with
+func endpointLabel(id string) string {
+	v, _ := currentLimiter().value("endpoint_id", id)
+	return v
+}
+
+// value maps id to its label value under the policy, reporting whether it's id itself
+func (l *labelLimiter) value(label, id string) (string, bool) {
+	switch l.policy.Mode {
+	case LabelModeAllowlist:
+		if l.allow[id] {
+			return id, true
+		}
+		return OtherLabel, false
+	case LabelModeHash:
+		h := fnv.New32a()
+		_, _ = h.Write([]byte(id))
+		return fmt.Sprintf("hash_%d", h.Sum32()%uint32(l.policy.HashBuckets)), false
+	case LabelModeTopK:
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		admitted := l.seen[label]
+		if admitted == nil {
+			admitted = map[string]bool{}
+			l.seen[label] = admitted
+		}
+		if admitted[id] {
+			return id, true
+		}
+		if len(admitted) < l.policy.TopK {
+			admitted[id] = true
+			return id, true
+		}
+		return OtherLabel, false
+	}
+	return id, true
+}
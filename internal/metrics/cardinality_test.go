@@ -0,0 +1,118 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+// useLabelPolicy applies p for one test
+func useLabelPolicy(t *testing.T, p LabelPolicy) {
+	t.Helper()
+	if err := SetLabelPolicy(p); err != nil {
+		t.Fatalf("SetLabelPolicy(%+v): %v", p, err)
+	}
+	t.Cleanup(func() { _ = SetLabelPolicy(LabelPolicy{}) })
+}
+
+func TestSetLabelPolicy_Invalid(t *testing.T) {
+	tests := []struct {
+		policy  LabelPolicy
+		wantErr string
+	}{
+		{policy: LabelPolicy{Mode: "sample"}, wantErr: "unknown label mode"},
+		{policy: LabelPolicy{Mode: LabelModeHash}, wantErr: "at least 1 bucket"},
+		{policy: LabelPolicy{Mode: LabelModeTopK, TopK: -1}, wantErr: "non-negative"},
+	}
+	for _, tt := range tests {
+		if err := SetLabelPolicy(tt.policy); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("SetLabelPolicy(%+v) error = %v, want %q", tt.policy, err, tt.wantErr)
+		}
+	}
+	if got := tenantLabel("tn_1"); got != "tn_1" {
+		t.Errorf("tenantLabel() after rejected policies = %q, want IDs kept as is", got)
+	}
+}
+
+func TestLabelPolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy LabelPolicy
+		ids    []string
+		want   []string
+	}{
+		{name: "all", policy: LabelPolicy{}, ids: []string{"tn_1", "tn_2"}, want: []string{"tn_1", "tn_2"}},
+		{
+			name:   "allowlist",
+			policy: LabelPolicy{Mode: LabelModeAllowlist, Allowlist: []string{"tn_big"}},
+			ids:    []string{"tn_big", "tn_small"},
+			want:   []string{"tn_big", OtherLabel},
+		},
+		{
+			name:   "topk",
+			policy: LabelPolicy{Mode: LabelModeTopK, TopK: 2},
+			ids:    []string{"tn_1", "tn_2", "tn_3", "tn_1"},
+			want:   []string{"tn_1", "tn_2", OtherLabel, "tn_1"},
+		},
+		{name: "hash", policy: LabelPolicy{Mode: LabelModeHash, HashBuckets: 1}, ids: []string{"tn_1", "tn_2"}, want: []string{"hash_0", "hash_0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useLabelPolicy(t, tt.policy)
+			for i, id := range tt.ids {
+				if got := tenantLabel(id); got != tt.want[i] {
+					t.Errorf("tenantLabel(%q) = %q, want %q", id, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLabelPolicy_Hash(t *testing.T) {
+	useLabelPolicy(t, LabelPolicy{Mode: LabelModeHash, HashBuckets: 8})
+	buckets := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		id := "ep_" + strings.Repeat("x", i%50) + string(rune('a'+i%26))
+		v := endpointLabel(id)
+		if v != endpointLabel(id) {
+			t.Fatalf("endpointLabel(%q) is not stable", id)
+		}
+		buckets[v] = true
+	}
+	if len(buckets) > 8 {
+		t.Errorf("got %d label values, want at most 8", len(buckets))
+	}
+}
+
+func TestLabelPolicy_Recording(t *testing.T) {
+	DeliveriesTotal.Reset()
+	RetryBudgetRemaining.Reset()
+	RetryBudgetExhaustedTotal.Reset()
+	BacklogPending.Reset()
+	useLabelPolicy(t, LabelPolicy{Mode: LabelModeAllowlist, Allowlist: []string{"tn_big", "ep_big"}})
+
+	RecordDelivery("delivered", "tn_big", "ep_big", time.Millisecond)
+	RecordDelivery("delivered", "tn_a", "ep_a", time.Millisecond)
+	RecordDelivery("delivered", "tn_b", "ep_b", time.Millisecond)
+	if got := testutil.ToFloat64(DeliveriesTotal.WithLabelValues("delivered", OtherLabel, OtherLabel)); got != 2 {
+		t.Errorf("deliveries for other = %f, want 2", got)
+	}
+	if got := testutil.CollectAndCount(DeliveriesTotal); got != 2 {
+		t.Errorf("delivery series = %d, want 2 (tn_big and other)", got)
+	}
+
+	// Gauges can't be summed across IDs, so only kept IDs are set; counters still aggregate
+	RecordRetryBudget("ep_a", 5, true)
+	UpdateBacklogEstimate("tn_a", 10, time.Minute, true)
+	if got := testutil.CollectAndCount(RetryBudgetRemaining); got != 0 {
+		t.Errorf("retry budget series = %d, want none for an endpoint outside the allowlist", got)
+	}
+	if got := testutil.ToFloat64(RetryBudgetExhaustedTotal.WithLabelValues(OtherLabel)); got != 1 {
+		t.Errorf("retry budget exhausted for other = %f, want 1", got)
+	}
+	if got := testutil.CollectAndCount(BacklogPending); got != 0 {
+		t.Errorf("backlog series = %d, want none for a tenant outside the allowlist", got)
+	}
+}
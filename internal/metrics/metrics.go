@@ -1,9 +1,14 @@
 package metrics
 
 import (
+	"math"
+	"runtime"
+	"strconv"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/austindbirch/harbor_hook/internal/version"
 )
 
 var (
@@ -37,6 +42,31 @@ var (
 
 	// Note: WorkerBacklog moved to dedicated nsq-monitor service
 
+	// SLIs for error budgets: finished deliveries counted toward each SLI, and the good ones,
+	// so a burn rate over any window is 1 - rate(good) / rate(total)
+	SLIDeliveriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_sli_deliveries_total",
+			Help: "Total finished deliveries counted toward an SLI (success, latency) by tenant and endpoint.",
+		},
+		[]string{"sli", "tenant_id", "endpoint_id"},
+	)
+
+	SLIDeliveriesGoodTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_sli_deliveries_good_total",
+			Help: "Total finished deliveries that met an SLI (success: delivered; latency: delivered within the target) by tenant and endpoint.",
+		},
+		[]string{"sli", "tenant_id", "endpoint_id"},
+	)
+
+	SLILatencyTargetSeconds = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "harborhook_sli_latency_target_seconds",
+			Help: "Latency a successful attempt must be within to count as good for the latency SLI.",
+		},
+	)
+
 	// Retries with reason label (Phase 5 requirement)
 	RetriesTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -65,6 +95,59 @@ var (
 		[]string{"tenant_id", "endpoint_id", "status_code"},
 	)
 
+	// Connections webhook requests got from the worker's pool: reused from it or newly dialed
+	HTTPConnectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_http_connections_total",
+			Help: "Total connections obtained for webhook requests, by whether an idle pooled connection was reused.",
+		},
+		[]string{"reused"},
+	)
+
+	HTTPConnectionWaitSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "harborhook_http_connection_wait_seconds",
+			Help:    "Time webhook requests waited for a connection (including dial and TLS handshake for new ones).",
+			Buckets: prometheus.ExponentialBuckets(0.0001, 4, 10), // 100µs to ~26s
+		},
+		[]string{"reused"},
+	)
+
+	// DNS lookups of receiver hosts through the worker's cache, by how each was answered
+	DNSLookupsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_dns_lookups_total",
+			Help: "Total DNS lookups of receiver hosts by result (hit, negative_hit, resolved, stale, failed).",
+		},
+		[]string{"result"},
+	)
+
+	DNSLookupSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "harborhook_dns_lookup_duration_seconds",
+			Help:    "Time DNS lookups of receiver hosts took, including waiting on a shared query.",
+			Buckets: prometheus.ExponentialBuckets(0.00001, 4, 10), // 10µs to ~2.6s
+		},
+		[]string{"result"},
+	)
+
+	// Retry budget: retries an endpoint may still schedule at normal backoff, and those pushed out
+	RetryBudgetRemaining = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_retry_budget_remaining",
+			Help: "Retries an endpoint may still schedule this minute before they are delayed (per worker).",
+		},
+		[]string{"endpoint_id"},
+	)
+
+	RetryBudgetExhaustedTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_retry_budget_exhausted_total",
+			Help: "Total retries scheduled with the longer delay because their endpoint's retry budget was spent.",
+		},
+		[]string{"endpoint_id"},
+	)
+
 	// NSQ topic depth (optional Phase 5 requirement)
 	NSQTopicDepth = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -73,6 +156,182 @@ var (
 		},
 		[]string{"topic", "channel"},
 	)
+
+	// Kill switch: share of tasks currently admitted, and tasks held back
+	DispatchAdmitPercent = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "harborhook_dispatch_admit_percent",
+			Help: "Percentage of tasks workers currently dispatch (0 while paused, ramps to 100 after resume).",
+		},
+	)
+
+	DispatchHeldTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_dispatch_held_total",
+			Help: "Total tasks held back by the dispatch kill switch.",
+		},
+		[]string{"reason"}, // paused, ramp, recovery, unhealthy, not_due, draining, ordered
+	)
+
+	// Backlog estimates per tenant, refreshed by the worker
+	BacklogPending = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_backlog_pending",
+			Help: "Queued, in-flight and retrying deliveries per tenant.",
+		},
+		[]string{"tenant_id"},
+	)
+
+	BacklogETASeconds = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_backlog_eta_seconds",
+			Help: "Estimated seconds until a tenant's backlog clears (+Inf when it is not draining).",
+		},
+		[]string{"tenant_id"},
+	)
+
+	// Backlog attribution, sampled from Postgres by the worker
+	BacklogByTenant = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_backlog_by_tenant",
+			Help: "Queued, in-flight and retrying deliveries per tenant, sampled from Postgres.",
+		},
+		[]string{"tenant_id"},
+	)
+
+	BacklogByEndpoint = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_backlog_by_endpoint",
+			Help: "Queued, in-flight and retrying deliveries per endpoint, sampled from Postgres.",
+		},
+		[]string{"tenant_id", "endpoint_id"},
+	)
+
+	BacklogOldestAgeByTenant = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_backlog_oldest_age_seconds_by_tenant",
+			Help: "Age of a tenant's oldest queued, in-flight or retrying delivery.",
+		},
+		[]string{"tenant_id"},
+	)
+
+	BacklogOldestAgeByEndpoint = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_backlog_oldest_age_seconds_by_endpoint",
+			Help: "Age of an endpoint's oldest queued, in-flight or retrying delivery.",
+		},
+		[]string{"tenant_id", "endpoint_id"},
+	)
+
+	// Publishes rejected by tenant quotas
+	QuotaRejectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_quota_rejections_total",
+			Help: "Total events rejected by tenant publishing quotas.",
+		},
+		[]string{"tenant_id", "quota"}, // events_per_minute, fanout
+	)
+
+	// Publishes rejected because their payload didn't match the event type's schema
+	SchemaRejectionsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_schema_rejections_total",
+			Help: "Total events rejected because their payload did not match the registered schema.",
+		},
+		[]string{"tenant_id", "event_type"},
+	)
+
+	// Delivery state changes that could not be published to the changefeed topic
+	ChangefeedDroppedTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Name: "harborhook_changefeed_dropped_total",
+			Help: "Total delivery state changes dropped because they could not be published to the changefeed.",
+		},
+	)
+
+	// Management operations whose audit log entry could not be written
+	AuditWriteFailuresTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_audit_write_failures_total",
+			Help: "Total management operations that succeeded but could not be recorded in the audit log.",
+		},
+		[]string{"action"},
+	)
+
+	// Outbox rows published to NSQ, inline right after commit or later by the relay
+	OutboxPublishesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_outbox_publishes_total",
+			Help: "Total delivery tasks published from the outbox, by path (inline, relay) and result (sent, failed).",
+		},
+		[]string{"path", "result"},
+	)
+
+	SystemEventsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_system_events_total",
+			Help: "Total system events recorded, by type (e.g. endpoint.status_anomaly).",
+		},
+		[]string{"type"},
+	)
+
+	// Endpoint health checks: their results, and each checked endpoint's health
+	HealthChecksTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_health_checks_total",
+			Help: "Total endpoint health checks sent, by result (passed, failed).",
+		},
+		[]string{"result"},
+	)
+
+	EndpointHealthy = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_endpoint_healthy",
+			Help: "1 while a health-checked endpoint passes its checks, 0 while it is unhealthy and its deliveries are held.",
+		},
+		[]string{"tenant_id", "endpoint_id"},
+	)
+
+	EndpointsDisabledTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_endpoints_disabled_total",
+			Help: "Total endpoints disabled for failing, by reason (dead_streak, failure_rate).",
+		},
+		[]string{"reason"},
+	)
+
+	NotificationsSentTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_notifications_sent_total",
+			Help: "Total notices sent to tenants' ops contacts, by kind, channel (email, webhook) and result (ok, error).",
+		},
+		[]string{"kind", "channel", "result"},
+	)
+
+	ArchivedRowsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_archived_rows_total",
+			Help: "Total rows written to the archive store, by table (events, deliveries).",
+		},
+		[]string{"table"},
+	)
+
+	ArchiveDeletedRowsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "harborhook_archive_deleted_rows_total",
+			Help: "Total archived rows deleted from Postgres, by table (events, deliveries).",
+		},
+		[]string{"table"},
+	)
+
+	// Always 1; the labels identify the running build
+	BuildInfo = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "harborhook_build_info",
+			Help: "Build information of the running binary, always 1.",
+		},
+		[]string{"version", "commit", "go_version"},
+	)
 )
 
 // MustRegister registers all metrics with the provided registry
@@ -83,27 +342,105 @@ func MustRegister(reg *prometheus.Registry) {
 		DeliveryLatencySeconds,
 		RetriesTotal,
 		DLQTotal,
+		SLIDeliveriesTotal,
+		SLIDeliveriesGoodTotal,
+		SLILatencyTargetSeconds,
 		HTTPDeliveryDuration,
+		HTTPConnectionsTotal,
+		HTTPConnectionWaitSeconds,
+		DNSLookupsTotal,
+		DNSLookupSeconds,
+		RetryBudgetRemaining,
+		RetryBudgetExhaustedTotal,
 		NSQTopicDepth,
+		DispatchAdmitPercent,
+		DispatchHeldTotal,
+		BacklogPending,
+		BacklogETASeconds,
+		BacklogByTenant,
+		BacklogByEndpoint,
+		BacklogOldestAgeByTenant,
+		BacklogOldestAgeByEndpoint,
+		QuotaRejectionsTotal,
+		SchemaRejectionsTotal,
+		ChangefeedDroppedTotal,
+		AuditWriteFailuresTotal,
+		OutboxPublishesTotal,
+		SystemEventsTotal,
+		HealthChecksTotal,
+		EndpointHealthy,
+		EndpointsDisabledTotal,
+		NotificationsSentTotal,
+		ArchivedRowsTotal,
+		ArchiveDeletedRowsTotal,
+		BuildInfo,
 	)
+	BuildInfo.WithLabelValues(version.Version, version.GitCommit, runtime.Version()).Set(1)
+	SLILatencyTargetSeconds.Set(sliLatencyTarget.Seconds())
+}
+
+// SLI names, the sli label of the SLI counters
+const (
+	SLISuccess = "success"
+	SLILatency = "latency"
+)
+
+// sliLatencyTarget is the latency SLI's threshold; set once at startup with SetSLILatencyTarget
+var sliLatencyTarget = 5 * time.Second
+
+// SetSLILatencyTarget sets how fast a successful attempt must be to count as good for the
+// latency SLI
+func SetSLILatencyTarget(target time.Duration) {
+	sliLatencyTarget = target
+	SLILatencyTargetSeconds.Set(target.Seconds())
 }
 
 // Helper functions for recording common metric patterns
 
 // RecordEventPublished increments the events published counter
 func RecordEventPublished(tenantID string) {
-	EventsPublishedTotal.WithLabelValues(tenantID).Inc()
+	EventsPublishedTotal.WithLabelValues(tenantLabel(tenantID)).Inc()
 }
 
 // RecordDelivery increments delivery counter and records latency
 func RecordDelivery(status, tenantID, endpointID string, duration time.Duration) {
-	DeliveriesTotal.WithLabelValues(status, tenantID, endpointID).Inc()
-	DeliveryLatencySeconds.WithLabelValues(tenantID).Observe(duration.Seconds())
+	tenant := tenantLabel(tenantID)
+	DeliveriesTotal.WithLabelValues(status, tenant, endpointLabel(endpointID)).Inc()
+	DeliveryLatencySeconds.WithLabelValues(tenant).Observe(duration.Seconds())
+}
+
+// RecordDeliveryOutcome counts a finished delivery toward the SLIs. Toward success it is good
+// when delivered and bad when it failed for good or was dead-lettered; a delivered one also
+// counts toward latency, good when its successful attempt took at most the latency target.
+func RecordDeliveryOutcome(tenantID, endpointID string, delivered bool, latency time.Duration) {
+	tenant, endpoint := tenantLabel(tenantID), endpointLabel(endpointID)
+	SLIDeliveriesTotal.WithLabelValues(SLISuccess, tenant, endpoint).Inc()
+	if !delivered {
+		return
+	}
+	SLIDeliveriesGoodTotal.WithLabelValues(SLISuccess, tenant, endpoint).Inc()
+	SLIDeliveriesTotal.WithLabelValues(SLILatency, tenant, endpoint).Inc()
+	if latency <= sliLatencyTarget {
+		SLIDeliveriesGoodTotal.WithLabelValues(SLILatency, tenant, endpoint).Inc()
+	}
 }
 
 // RecordHTTPDelivery records HTTP delivery metrics
 func RecordHTTPDelivery(tenantID, endpointID, statusCode string, duration time.Duration) {
-	HTTPDeliveryDuration.WithLabelValues(tenantID, endpointID, statusCode).Observe(duration.Seconds())
+	HTTPDeliveryDuration.WithLabelValues(tenantLabel(tenantID), endpointLabel(endpointID), statusCode).Observe(duration.Seconds())
+}
+
+// RecordHTTPConnection records a connection obtained for a webhook request and how long it took
+func RecordHTTPConnection(reused bool, wait time.Duration) {
+	label := strconv.FormatBool(reused)
+	HTTPConnectionsTotal.WithLabelValues(label).Inc()
+	HTTPConnectionWaitSeconds.WithLabelValues(label).Observe(wait.Seconds())
+}
+
+// RecordDNSLookup records a lookup of a receiver host, as netguard reports it
+func RecordDNSLookup(result string, took time.Duration) {
+	DNSLookupsTotal.WithLabelValues(result).Inc()
+	DNSLookupSeconds.WithLabelValues(result).Observe(took.Seconds())
 }
 
 // RecordRetry increments retry counter with reason
@@ -116,9 +453,149 @@ func RecordDLQ(reason string) {
 	DLQTotal.WithLabelValues(reason).Inc()
 }
 
+// RecordRetryBudget sets an endpoint's remaining retry budget, counting a retry it didn't cover.
+// The gauge is only set for endpoints the label policy keeps.
+func RecordRetryBudget(endpointID string, remaining float64, exhausted bool) {
+	endpoint, kept := currentLimiter().value("endpoint_id", endpointID)
+	if kept {
+		RetryBudgetRemaining.WithLabelValues(endpoint).Set(remaining)
+	}
+	if exhausted {
+		RetryBudgetExhaustedTotal.WithLabelValues(endpoint).Inc()
+	}
+}
+
 // Note: UpdateWorkerBacklog removed - now handled by nsq-monitor service
 
 // UpdateNSQTopicDepth updates NSQ topic depth
 func UpdateNSQTopicDepth(topic, channel string, depth float64) {
 	NSQTopicDepth.WithLabelValues(topic, channel).Set(depth)
 }
+
+// UpdateDispatchAdmitPercent sets the kill switch admit percentage
+func UpdateDispatchAdmitPercent(percent float64) {
+	DispatchAdmitPercent.Set(percent)
+}
+
+// RecordDispatchHeld increments the held-task counter with reason
+func RecordDispatchHeld(reason string) {
+	DispatchHeldTotal.WithLabelValues(reason).Inc()
+}
+
+// RecordQuotaRejection increments the quota rejection counter
+func RecordQuotaRejection(tenantID, quota string) {
+	QuotaRejectionsTotal.WithLabelValues(tenantLabel(tenantID), quota).Inc()
+}
+
+// RecordSchemaRejection increments the schema rejection counter
+func RecordSchemaRejection(tenantID, eventType string) {
+	SchemaRejectionsTotal.WithLabelValues(tenantLabel(tenantID), eventType).Inc()
+}
+
+// RecordChangefeedDropped counts changes that never reached the changefeed topic
+func RecordChangefeedDropped(n int) {
+	ChangefeedDroppedTotal.Add(float64(n))
+}
+
+// RecordAuditWriteFailure counts an operation that is missing from the audit log
+func RecordAuditWriteFailure(action string) {
+	AuditWriteFailuresTotal.WithLabelValues(action).Inc()
+}
+
+// RecordOutboxPublishes counts n outbox rows published by path with the given result
+func RecordOutboxPublishes(path, result string, n int) {
+	if n > 0 {
+		OutboxPublishesTotal.WithLabelValues(path, result).Add(float64(n))
+	}
+}
+
+// RecordSystemEvent increments the system events counter
+func RecordSystemEvent(eventType string) {
+	SystemEventsTotal.WithLabelValues(eventType).Inc()
+}
+
+// RecordNotification counts a notice sent to a tenant's ops contact through channel
+func RecordNotification(kind, channel string, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	NotificationsSentTotal.WithLabelValues(kind, channel, result).Inc()
+}
+
+// RecordHealthCheck records an endpoint health check and the endpoint's health after it. Only
+// healthy and unhealthy endpoints have a gauge, and only those kept by the label policy.
+func RecordHealthCheck(tenantID, endpointID string, passed bool, status string) {
+	result := "failed"
+	if passed {
+		result = "passed"
+	}
+	HealthChecksTotal.WithLabelValues(result).Inc()
+	if _, kept := currentLimiter().value("endpoint_id", endpointID); !kept || (status != "healthy" && status != "unhealthy") {
+		return
+	}
+	healthy := 0.0
+	if status == "healthy" {
+		healthy = 1
+	}
+	EndpointHealthy.WithLabelValues(tenantID, endpointID).Set(healthy)
+}
+
+// RecordArchive counts a table's rows written to the archive store and deleted from Postgres
+func RecordArchive(table string, archived, deleted int64) {
+	ArchivedRowsTotal.WithLabelValues(table).Add(float64(archived))
+	ArchiveDeletedRowsTotal.WithLabelValues(table).Add(float64(deleted))
+}
+
+// UpdateBacklogEstimate sets a tenant's backlog size and estimated time to clear.
+// Pass ok=false when the backlog is not draining. Tenants the label policy doesn't keep are
+// skipped.
+func UpdateBacklogEstimate(tenantID string, pending int64, eta time.Duration, ok bool) {
+	if _, kept := currentLimiter().value("tenant_id", tenantID); !kept {
+		return
+	}
+	BacklogPending.WithLabelValues(tenantID).Set(float64(pending))
+	if !ok {
+		BacklogETASeconds.WithLabelValues(tenantID).Set(math.Inf(1))
+		return
+	}
+	BacklogETASeconds.WithLabelValues(tenantID).Set(eta.Seconds())
+}
+
+// EndpointBacklog is one endpoint's share of the delivery backlog
+type EndpointBacklog struct {
+	TenantID   string
+	EndpointID string
+	Pending    int64
+	OldestAge  time.Duration // age of the oldest pending delivery
+}
+
+// SetBacklogAttribution replaces the per-tenant and per-endpoint backlog gauges with backlogs,
+// so endpoints that drained drop out. A tenant's gauges sum its endpoints' backlogs and take
+// the oldest age among them. Tenants and endpoints the label policy doesn't keep are skipped.
+func SetBacklogAttribution(backlogs []EndpointBacklog) {
+	BacklogByTenant.Reset()
+	BacklogByEndpoint.Reset()
+	BacklogOldestAgeByTenant.Reset()
+	BacklogOldestAgeByEndpoint.Reset()
+
+	l := currentLimiter()
+	pending := map[string]int64{}
+	oldest := map[string]time.Duration{}
+	for _, b := range backlogs {
+		if _, kept := l.value("tenant_id", b.TenantID); !kept {
+			continue
+		}
+		pending[b.TenantID] += b.Pending
+		oldest[b.TenantID] = max(oldest[b.TenantID], b.OldestAge)
+		if _, kept := l.value("endpoint_id", b.EndpointID); !kept {
+			continue
+		}
+		BacklogByEndpoint.WithLabelValues(b.TenantID, b.EndpointID).Set(float64(b.Pending))
+		BacklogOldestAgeByEndpoint.WithLabelValues(b.TenantID, b.EndpointID).Set(b.OldestAge.Seconds())
+	}
+	for tenantID, n := range pending {
+		BacklogByTenant.WithLabelValues(tenantID).Set(float64(n))
+		BacklogOldestAgeByTenant.WithLabelValues(tenantID).Set(oldest[tenantID].Seconds())
+	}
+}
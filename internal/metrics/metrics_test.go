@@ -1,12 +1,16 @@
 package metrics
 
 import (
+	"math"
+	"runtime"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/austindbirch/harbor_hook/internal/version"
 )
 
 func TestMustRegister(t *testing.T) {
@@ -37,6 +41,16 @@ func TestMustRegister(t *testing.T) {
 			RecordRetry("timeout")
 			RecordDLQ("max_retries")
 			UpdateNSQTopicDepth("test-topic", "test-channel", 3)
+			UpdateDispatchAdmitPercent(100)
+			RecordDispatchHeld("paused")
+			UpdateBacklogEstimate("test-tenant", 10, time.Minute, true)
+			RecordQuotaRejection("test-tenant", "fanout")
+			RecordSchemaRejection("test-tenant", "order.created")
+			RecordChangefeedDropped(1)
+			RecordAuditWriteFailure("endpoint.create")
+			RecordOutboxPublishes("relay", "sent", 1)
+			RecordSystemEvent("endpoint.status_anomaly")
+			RecordDeliveryOutcome("test-tenant", "test-endpoint", true, 100*time.Millisecond)
 
 			// Verify all metrics are registered by checking gather
 			metricFamilies, err := tt.registry.Gather()
@@ -50,7 +64,19 @@ func TestMustRegister(t *testing.T) {
 				"harborhook_delivery_latency_seconds",
 				"harborhook_retries_total",
 				"harborhook_dlq_total",
+				"harborhook_sli_deliveries_total",
+				"harborhook_sli_deliveries_good_total",
+				"harborhook_sli_latency_target_seconds",
 				"harborhook_nsq_topic_depth",
+				"harborhook_dispatch_admit_percent",
+				"harborhook_dispatch_held_total",
+				"harborhook_backlog_pending",
+				"harborhook_backlog_eta_seconds",
+				"harborhook_quota_rejections_total",
+				"harborhook_changefeed_dropped_total",
+				"harborhook_outbox_publishes_total",
+				"harborhook_system_events_total",
+				"harborhook_build_info",
 			}
 
 			registeredMetrics := make(map[string]bool)
@@ -361,6 +387,152 @@ func TestUpdateNSQTopicDepth(t *testing.T) {
 	}
 }
 
+func TestRecordDispatchHeld(t *testing.T) {
+	DispatchHeldTotal.Reset()
+
+	RecordDispatchHeld("paused")
+	RecordDispatchHeld("paused")
+	RecordDispatchHeld("ramp")
+
+	if got := testutil.ToFloat64(DispatchHeldTotal.WithLabelValues("paused")); got != 2 {
+		t.Errorf("paused count = %f, want 2", got)
+	}
+	if got := testutil.ToFloat64(DispatchHeldTotal.WithLabelValues("ramp")); got != 1 {
+		t.Errorf("ramp count = %f, want 1", got)
+	}
+
+	UpdateDispatchAdmitPercent(42.5)
+	if got := testutil.ToFloat64(DispatchAdmitPercent); got != 42.5 {
+		t.Errorf("admit percent = %f, want 42.5", got)
+	}
+}
+
+func TestUpdateBacklogEstimate(t *testing.T) {
+	BacklogPending.Reset()
+	BacklogETASeconds.Reset()
+
+	UpdateBacklogEstimate("draining", 120, 2*time.Minute, true)
+	UpdateBacklogEstimate("stuck", 7, 0, false)
+
+	if got := testutil.ToFloat64(BacklogPending.WithLabelValues("draining")); got != 120 {
+		t.Errorf("pending = %f, want 120", got)
+	}
+	if got := testutil.ToFloat64(BacklogETASeconds.WithLabelValues("draining")); got != 120 {
+		t.Errorf("eta = %f, want 120", got)
+	}
+	if got := testutil.ToFloat64(BacklogETASeconds.WithLabelValues("stuck")); !math.IsInf(got, 1) {
+		t.Errorf("eta for a stuck backlog = %f, want +Inf", got)
+	}
+}
+
+func TestSetBacklogAttribution(t *testing.T) {
+	useLabelPolicy(t, LabelPolicy{Mode: LabelModeAllowlist, Allowlist: []string{"tn_1", "ep_1", "ep_2"}})
+	SetBacklogAttribution([]EndpointBacklog{{TenantID: "tn_gone", EndpointID: "ep_gone", Pending: 1}})
+
+	SetBacklogAttribution([]EndpointBacklog{
+		{TenantID: "tn_1", EndpointID: "ep_1", Pending: 40, OldestAge: 90 * time.Second},
+		{TenantID: "tn_1", EndpointID: "ep_2", Pending: 2, OldestAge: 5 * time.Second},
+		{TenantID: "tn_1", EndpointID: "ep_3", Pending: 8, OldestAge: 10 * time.Minute},
+		{TenantID: "tn_2", EndpointID: "ep_1", Pending: 3, OldestAge: time.Second},
+	})
+
+	if got := testutil.ToFloat64(BacklogByTenant.WithLabelValues("tn_1")); got != 50 {
+		t.Errorf("tn_1 backlog = %f, want 50 across its endpoints, kept or not", got)
+	}
+	if got := testutil.ToFloat64(BacklogOldestAgeByTenant.WithLabelValues("tn_1")); got != 600 {
+		t.Errorf("tn_1 oldest age = %f, want 600", got)
+	}
+	if got := testutil.ToFloat64(BacklogByEndpoint.WithLabelValues("tn_1", "ep_1")); got != 40 {
+		t.Errorf("ep_1 backlog = %f, want 40", got)
+	}
+	if got := testutil.ToFloat64(BacklogOldestAgeByEndpoint.WithLabelValues("tn_1", "ep_2")); got != 5 {
+		t.Errorf("ep_2 oldest age = %f, want 5", got)
+	}
+	if got := testutil.CollectAndCount(BacklogByTenant); got != 1 {
+		t.Errorf("tenant series = %d, want only tn_1 (tn_gone drained, tn_2 outside the allowlist)", got)
+	}
+	if got := testutil.CollectAndCount(BacklogByEndpoint); got != 2 {
+		t.Errorf("endpoint series = %d, want ep_1 and ep_2", got)
+	}
+}
+
+func TestRecordQuotaRejection(t *testing.T) {
+	QuotaRejectionsTotal.Reset()
+
+	RecordQuotaRejection("tn_1", "events_per_minute")
+	RecordQuotaRejection("tn_1", "events_per_minute")
+	RecordQuotaRejection("tn_1", "fanout")
+
+	if got := testutil.ToFloat64(QuotaRejectionsTotal.WithLabelValues("tn_1", "events_per_minute")); got != 2 {
+		t.Errorf("events_per_minute rejections = %f, want 2", got)
+	}
+	if got := testutil.ToFloat64(QuotaRejectionsTotal.WithLabelValues("tn_1", "fanout")); got != 1 {
+		t.Errorf("fanout rejections = %f, want 1", got)
+	}
+}
+
+func TestRecordDeliveryOutcome(t *testing.T) {
+	SLIDeliveriesTotal.Reset()
+	SLIDeliveriesGoodTotal.Reset()
+	orig := sliLatencyTarget
+	defer SetSLILatencyTarget(orig)
+	SetSLILatencyTarget(500 * time.Millisecond)
+
+	RecordDeliveryOutcome("tn_1", "ep_1", true, 120*time.Millisecond)
+	RecordDeliveryOutcome("tn_1", "ep_1", true, 500*time.Millisecond)
+	RecordDeliveryOutcome("tn_1", "ep_1", true, 2*time.Second)
+	RecordDeliveryOutcome("tn_1", "ep_1", false, 0)
+
+	tests := []struct {
+		sli         string
+		total, good float64
+	}{
+		{sli: SLISuccess, total: 4, good: 3},
+		{sli: SLILatency, total: 3, good: 2}, // only delivered ones count; the target is inclusive
+	}
+	for _, tt := range tests {
+		if got := testutil.ToFloat64(SLIDeliveriesTotal.WithLabelValues(tt.sli, "tn_1", "ep_1")); got != tt.total {
+			t.Errorf("%s total = %f, want %f", tt.sli, got, tt.total)
+		}
+		if got := testutil.ToFloat64(SLIDeliveriesGoodTotal.WithLabelValues(tt.sli, "tn_1", "ep_1")); got != tt.good {
+			t.Errorf("%s good = %f, want %f", tt.sli, got, tt.good)
+		}
+	}
+	if got := testutil.ToFloat64(SLILatencyTargetSeconds); got != 0.5 {
+		t.Errorf("latency target = %f, want 0.5", got)
+	}
+}
+
+func TestRecordOutboxPublishes(t *testing.T) {
+	OutboxPublishesTotal.Reset()
+
+	RecordOutboxPublishes("inline", "sent", 3)
+	RecordOutboxPublishes("relay", "failed", 2)
+	RecordOutboxPublishes("relay", "sent", 0)
+
+	if got := testutil.ToFloat64(OutboxPublishesTotal.WithLabelValues("inline", "sent")); got != 3 {
+		t.Errorf("inline sent = %f, want 3", got)
+	}
+	if got := testutil.ToFloat64(OutboxPublishesTotal.WithLabelValues("relay", "failed")); got != 2 {
+		t.Errorf("relay failed = %f, want 2", got)
+	}
+	if got := testutil.CollectAndCount(OutboxPublishesTotal); got != 2 {
+		t.Errorf("series = %d, want 2 (zero counts are not recorded)", got)
+	}
+}
+
+func TestBuildInfo(t *testing.T) {
+	BuildInfo.Reset()
+	MustRegister(prometheus.NewRegistry())
+
+	if got := testutil.ToFloat64(BuildInfo.WithLabelValues(version.Version, version.GitCommit, runtime.Version())); got != 1 {
+		t.Errorf("build info = %f, want 1", got)
+	}
+	if got := testutil.CollectAndCount(BuildInfo); got != 1 {
+		t.Errorf("build info series = %d, want 1", got)
+	}
+}
+
 func TestMetricsIntegration(t *testing.T) {
 	// Create a new registry for integration test
 	registry := prometheus.NewRegistry()
@@ -481,4 +653,14 @@ func TestPrometheusTextOutput(t *testing.T) {
 			t.Errorf("Metric name %s does not have expected prefix 'harborhook_'", name)
 		}
 	}
-}
\ No newline at end of file
+}
+func TestRecordSystemEvent(t *testing.T) {
+	SystemEventsTotal.Reset()
+
+	RecordSystemEvent("endpoint.status_anomaly")
+	RecordSystemEvent("endpoint.status_anomaly")
+
+	if got := testutil.ToFloat64(SystemEventsTotal.WithLabelValues("endpoint.status_anomaly")); got != 2 {
+		t.Errorf("status anomalies = %f, want 2", got)
+	}
+}
@@ -0,0 +1,198 @@
+package netguard
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGuard_CheckAddr(t *testing.T) {
+	var g *Guard
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"93.184.216.34", false},
+		{"2606:2800:220:1:248:1893:25c8:1946", false},
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.10", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00:ec2::254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"255.255.255.255", true},
+		{"224.0.0.1", true},
+		{"::ffff:169.254.169.254", true},
+		{"64:ff9b::a9fe:a9fe", true},
+		{"::7f00:1", true},
+		{"::a9fe:a9fe", true},
+	}
+
+	for _, tt := range tests {
+		err := g.CheckAddr(netip.MustParseAddr(tt.addr))
+		if blocked := errors.Is(err, ErrBlocked); blocked != tt.blocked {
+			t.Errorf("CheckAddr(%s) = %v, want blocked=%v", tt.addr, err, tt.blocked)
+		}
+	}
+}
+
+func TestGuard_Allowlist(t *testing.T) {
+	g, err := New([]string{"10.0.0.0/8", "127.0.0.1", "Fake-Receiver"})
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	for _, addr := range []string{"10.20.30.40", "127.0.0.1", "::ffff:127.0.0.1"} {
+		if err := g.CheckAddr(netip.MustParseAddr(addr)); err != nil {
+			t.Errorf("CheckAddr(%s) = %v, want allowed", addr, err)
+		}
+	}
+	if err := g.CheckAddr(netip.MustParseAddr("127.0.0.2")); !errors.Is(err, ErrBlocked) {
+		t.Errorf("CheckAddr(127.0.0.2) = %v, want blocked", err)
+	}
+	if err := g.CheckURL(context.Background(), "http://fake-receiver:8081/hook"); err != nil {
+		t.Errorf("CheckURL(allowlisted host) = %v, want allowed", err)
+	}
+
+	if _, err := New([]string{"10.0.0.0/33"}); err == nil {
+		t.Error("New() with an invalid CIDR: expected error")
+	}
+}
+
+func TestGuard_CheckURL(t *testing.T) {
+	var g *Guard
+	tests := []struct {
+		url     string
+		wantErr string
+	}{
+		{url: "https://93.184.216.34/hook"},
+		{url: "http://169.254.169.254/latest/meta-data/", wantErr: "not publicly routable"},
+		{url: "http://[::1]:8080/hook", wantErr: "not publicly routable"},
+		{url: "http://localhost:8081/hook", wantErr: "not publicly routable"},
+		{url: "ftp://93.184.216.34/hook", wantErr: `scheme "ftp" is not allowed`},
+		{url: "http:///hook", wantErr: "url has no host"},
+	}
+
+	for _, tt := range tests {
+		err := g.CheckURL(context.Background(), tt.url)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("CheckURL(%s) unexpected error: %v", tt.url, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("CheckURL(%s) = %v, want error containing %q", tt.url, err, tt.wantErr)
+		}
+	}
+}
+
+func TestGuard_Transport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer srv.Close()
+
+	var blocked *Guard
+	_, err := (&http.Client{Transport: blocked.Transport()}).Get(srv.URL)
+	if !errors.Is(err, ErrBlocked) {
+		t.Errorf("GET loopback through the guard = %v, want ErrBlocked", err)
+	}
+
+	allowed, _ := New([]string{"127.0.0.0/8"})
+	resp, err := (&http.Client{Transport: allowed.Transport()}).Get(srv.URL)
+	if err != nil {
+		t.Fatalf("GET allowlisted loopback: unexpected error: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestGuard_Proxy(t *testing.T) {
+	var proxied, auth []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.String())
+		auth = append(auth, r.Header.Get("Proxy-Authorization"))
+	}))
+	defer proxy.Close()
+
+	g, _ := New(nil)
+	if err := g.SetProxy(Proxy{URL: proxy.URL, Username: "harborhook", Password: "s3cret"}); err != nil || !g.Proxied() {
+		t.Fatalf("SetProxy(%s) = %v, proxied %v", proxy.URL, err, g.Proxied())
+	}
+	client := &http.Client{Transport: g.Transport()}
+
+	// The proxy is on loopback, but it's dialed; the target is checked before it's handed over
+	resp, err := client.Get("http://93.184.216.34/hook")
+	if err != nil {
+		t.Fatalf("GET a public address through the proxy: unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if _, err := client.Get("http://10.1.2.3/hook"); !errors.Is(err, ErrBlocked) {
+		t.Errorf("GET a private address through the proxy = %v, want ErrBlocked", err)
+	}
+	wantAuth := "Basic " + base64.StdEncoding.EncodeToString([]byte("harborhook:s3cret"))
+	if len(proxied) != 1 || proxied[0] != "http://93.184.216.34/hook" || auth[0] != wantAuth {
+		t.Errorf("proxied requests = %v with auth %v, want only the public one, authenticated", proxied, auth)
+	}
+
+	for _, bad := range []string{"socks5://proxy:1080", "http://", "://bad"} {
+		if err := g.SetProxy(Proxy{URL: bad}); err == nil {
+			t.Errorf("SetProxy(%q) succeeded, want an error", bad)
+		}
+	}
+	if err := g.SetProxy(Proxy{}); err != nil || g.Proxied() {
+		t.Errorf("SetProxy(Proxy{}) = %v, proxied %v, want direct sends", err, g.Proxied())
+	}
+}
+
+func TestGuard_ProxyFromEnvironment(t *testing.T) {
+	var proxied []string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = append(proxied, r.URL.Host)
+	}))
+	defer proxy.Close()
+	t.Setenv("HTTP_PROXY", strings.TrimPrefix(proxy.URL, "http://"))
+	t.Setenv("NO_PROXY", "93.184.216.35")
+
+	g, _ := New(nil)
+	if err := g.SetProxy(Proxy{FromEnvironment: true}); err != nil || !g.Proxied() {
+		t.Fatalf("SetProxy(FromEnvironment) = %v, proxied %v", err, g.Proxied())
+	}
+	client := &http.Client{Transport: g.Transport(), Timeout: 2 * time.Second}
+	resp, err := client.Get("http://93.184.216.34/hook")
+	if err != nil {
+		t.Fatalf("GET through HTTP_PROXY: unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if len(proxied) != 1 || proxied[0] != "93.184.216.34" {
+		t.Errorf("proxied hosts = %v, want the request through HTTP_PROXY", proxied)
+	}
+
+	// NO_PROXY hosts are sent directly; a short timeout is enough to see it skipped the proxy
+	_, _ = (&http.Client{Transport: g.Transport(), Timeout: 100 * time.Millisecond}).Get("http://93.184.216.35/hook")
+	if len(proxied) != 1 {
+		t.Errorf("proxied hosts = %v, want NO_PROXY hosts sent directly", proxied)
+	}
+}
+
+func TestGuard_ProxyVia(t *testing.T) {
+	g, _ := New(nil)
+	if g.ProxyVia(nil) != nil {
+		t.Error("ProxyVia(nil) returned a proxy function, want direct sends")
+	}
+	u, _ := ParseProxyURL("http://gateway.example.com:8080")
+	proxy := g.ProxyVia(u)
+	req, _ := http.NewRequest(http.MethodPost, "http://93.184.216.34/hook", nil)
+	if got, err := proxy(req); err != nil || got != u {
+		t.Errorf("proxy(public) = %v, %v, want the endpoint's proxy", got, err)
+	}
+	req, _ = http.NewRequest(http.MethodPost, "http://169.254.169.254/latest", nil)
+	if _, err := proxy(req); !errors.Is(err, ErrBlocked) {
+		t.Errorf("proxy(metadata address) = %v, want ErrBlocked", err)
+	}
+}
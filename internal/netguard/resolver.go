@@ -0,0 +1,151 @@
+package netguard
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Lookup results reported to a Resolver's observer
+const (
+	LookupHit         = "hit"          // answered from the cache
+	LookupNegativeHit = "negative_hit" // a cached failure
+	LookupResolved    = "resolved"     // resolved and cached
+	LookupStale       = "stale"        // resolution failed; an expired answer was used
+	LookupFailed      = "failed"       // resolution failed and nothing was cached
+)
+
+// Resolver caches DNS answers for the hosts webhooks are sent to, so a burst of deliveries to
+// one receiver resolves it once, and a receiver whose DNS briefly fails keeps its last answer.
+// Concurrent lookups of a host share one query.
+type Resolver struct {
+	ttl         time.Duration // how long an answer is used
+	negativeTTL time.Duration // how long a failure is returned without asking again; 0 caches none
+	staleTTL    time.Duration // how long past its ttl an answer is used when resolution fails
+	lookup      func(ctx context.Context, network, host string) ([]netip.Addr, error)
+	// observe is told how each lookup went and how long it took; nil ignores them
+	observe func(result string, took time.Duration)
+	now     func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*dnsEntry
+	inflight map[string]*dnsCall
+}
+
+type dnsEntry struct {
+	addrs    []netip.Addr
+	err      error // set for a cached failure
+	resolved time.Time
+}
+
+type dnsCall struct {
+	done  chan struct{}
+	addrs []netip.Addr
+	err   error
+}
+
+// NewResolver returns a Resolver keeping answers for ttl and failures for negativeTTL, and
+// using an expired answer for up to staleTTL more while resolution fails. observe, when not nil,
+// is called for each lookup with one of the Lookup results.
+func NewResolver(ttl, negativeTTL, staleTTL time.Duration, observe func(result string, took time.Duration)) *Resolver {
+	return &Resolver{
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+		staleTTL:    staleTTL,
+		lookup:      net.DefaultResolver.LookupNetIP,
+		observe:     observe,
+		now:         time.Now,
+		entries:     map[string]*dnsEntry{},
+		inflight:    map[string]*dnsCall{},
+	}
+}
+
+// LookupNetIP resolves host to its IP addresses, from the cache when it can
+func (r *Resolver) LookupNetIP(ctx context.Context, host string) ([]netip.Addr, error) {
+	start := r.now()
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	r.mu.Lock()
+	if e, ok := r.entries[host]; ok {
+		age := start.Sub(e.resolved)
+		switch {
+		case e.err == nil && age < r.ttl:
+			r.mu.Unlock()
+			r.report(LookupHit, start)
+			return e.addrs, nil
+		case e.err != nil && age < r.negativeTTL:
+			r.mu.Unlock()
+			r.report(LookupNegativeHit, start)
+			return nil, e.err
+		}
+	}
+	call, ok := r.inflight[host]
+	if !ok {
+		call = &dnsCall{done: make(chan struct{})}
+		r.inflight[host] = call
+		go r.resolve(host, call)
+	}
+	r.mu.Unlock()
+
+	select {
+	case <-call.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if call.err == nil {
+		r.report(LookupResolved, start)
+		return call.addrs, nil
+	}
+
+	r.mu.Lock()
+	e, ok := r.entries[host]
+	r.mu.Unlock()
+	if ok && e.err == nil && r.now().Sub(e.resolved) < r.ttl+r.staleTTL {
+		r.report(LookupStale, start)
+		return e.addrs, nil
+	}
+	r.report(LookupFailed, start)
+	return nil, call.err
+}
+
+// resolve queries host for call and caches the outcome. It doesn't use the caller's context, so
+// a caller giving up doesn't fail the others waiting on the same query.
+func (r *Resolver) resolve(host string, call *dnsCall) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	call.addrs, call.err = r.lookup(ctx, "ip", host)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.inflight, host)
+	switch {
+	case call.err == nil:
+		r.entries[host] = &dnsEntry{addrs: call.addrs, resolved: r.now()}
+	case r.negativeTTL > 0:
+		// A failure replaces a stale answer only once the answer is too old to fall back on
+		if e, ok := r.entries[host]; !ok || e.err != nil || r.now().Sub(e.resolved) >= r.ttl+r.staleTTL {
+			r.entries[host] = &dnsEntry{err: call.err, resolved: r.now()}
+		}
+	}
+	r.prune()
+	close(call.done)
+}
+
+// prune drops entries that can no longer be used; r.mu must be held
+func (r *Resolver) prune() {
+	now := r.now()
+	for host, e := range r.entries {
+		if (e.err != nil && now.Sub(e.resolved) >= r.negativeTTL) || (e.err == nil && now.Sub(e.resolved) >= r.ttl+r.staleTTL) {
+			delete(r.entries, host)
+		}
+	}
+}
+
+func (r *Resolver) report(result string, start time.Time) {
+	if r.observe != nil {
+		r.observe(result, r.now().Sub(start))
+	}
+}
@@ -0,0 +1,153 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeDNS answers lookups from a map, counting them, at a clock the test moves
+type fakeDNS struct {
+	answers map[string][]netip.Addr
+	fail    bool
+	queries int
+	now     time.Time
+	results []string
+}
+
+func (f *fakeDNS) resolver(ttl, negativeTTL, staleTTL time.Duration) *Resolver {
+	r := NewResolver(ttl, negativeTTL, staleTTL, func(result string, _ time.Duration) { f.results = append(f.results, result) })
+	r.now = func() time.Time { return f.now }
+	r.lookup = func(_ context.Context, _, host string) ([]netip.Addr, error) {
+		f.queries++
+		if a, ok := f.answers[host]; ok && !f.fail {
+			return a, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return r
+}
+
+func TestResolver(t *testing.T) {
+	dns := &fakeDNS{
+		answers: map[string][]netip.Addr{"receiver.example": {netip.MustParseAddr("93.184.216.34")}},
+		now:     time.Unix(1_700_000_000, 0),
+	}
+	r := dns.resolver(30*time.Second, 5*time.Second, time.Minute)
+	ctx := context.Background()
+
+	for range 3 {
+		if addrs, err := r.LookupNetIP(ctx, "Receiver.Example."); err != nil || len(addrs) != 1 {
+			t.Fatalf("LookupNetIP() = %v, %v", addrs, err)
+		}
+	}
+	if _, err := r.LookupNetIP(ctx, "missing.example"); err == nil {
+		t.Fatal("LookupNetIP(missing) succeeded")
+	}
+	if _, err := r.LookupNetIP(ctx, "missing.example"); err == nil {
+		t.Fatal("LookupNetIP(missing) from the negative cache succeeded")
+	}
+	if dns.queries != 2 {
+		t.Errorf("%d queries, want one per host", dns.queries)
+	}
+
+	// Past the ttl the host is asked again; when that fails, the old answer stands in
+	dns.now = dns.now.Add(45 * time.Second)
+	dns.fail = true
+	if addrs, err := r.LookupNetIP(ctx, "receiver.example"); err != nil || len(addrs) != 1 {
+		t.Errorf("LookupNetIP() while DNS fails = %v, %v, want the stale answer", addrs, err)
+	}
+	// Past the stale window too, the failure is returned, and then cached
+	dns.now = dns.now.Add(time.Minute)
+	if _, err := r.LookupNetIP(ctx, "receiver.example"); err == nil {
+		t.Error("LookupNetIP() past the stale window succeeded")
+	}
+	queries := dns.queries
+	_, _ = r.LookupNetIP(ctx, "receiver.example")
+	if dns.queries != queries {
+		t.Error("a failure within the negative ttl was asked again")
+	}
+
+	want := []string{LookupResolved, LookupHit, LookupHit, LookupFailed, LookupNegativeHit, LookupStale, LookupFailed, LookupNegativeHit}
+	if strings.Join(dns.results, ",") != strings.Join(want, ",") {
+		t.Errorf("observed %v, want %v", dns.results, want)
+	}
+}
+
+func TestResolver_SharesQueries(t *testing.T) {
+	release := make(chan struct{})
+	queries := 0
+	r := NewResolver(time.Minute, 0, 0, nil)
+	r.lookup = func(context.Context, string, string) ([]netip.Addr, error) {
+		queries++ // only one query runs at a time for a host
+		<-release
+		return []netip.Addr{netip.MustParseAddr("93.184.216.34")}, nil
+	}
+
+	errs := make(chan error)
+	for range 5 {
+		go func() {
+			_, err := r.LookupNetIP(context.Background(), "receiver.example")
+			errs <- err
+		}()
+	}
+	// A caller that gives up doesn't fail the query for the others
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.LookupNetIP(ctx, "receiver.example"); !errors.Is(err, context.Canceled) {
+		t.Errorf("LookupNetIP(canceled) = %v, want context.Canceled", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	for range 5 {
+		if err := <-errs; err != nil {
+			t.Errorf("LookupNetIP() unexpected error: %v", err)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("%d queries for concurrent lookups, want 1", queries)
+	}
+}
+
+func TestGuard_DialResolved(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	defer srv.Close()
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(srv.URL, "http://"))
+
+	dns := &fakeDNS{answers: map[string][]netip.Addr{
+		"receiver.test": {netip.MustParseAddr("127.0.0.1")},
+		"internal.test": {netip.MustParseAddr("10.0.0.5")},
+		"compat.test":   {netip.MustParseAddr("::7f00:1")},
+	}}
+	g, _ := New([]string{"127.0.0.1"})
+	g.SetResolver(dns.resolver(time.Minute, time.Minute, 0))
+	client := &http.Client{Transport: g.Transport(), Timeout: 2 * time.Second}
+
+	for range 2 {
+		resp, err := client.Get("http://receiver.test:" + port)
+		if err != nil {
+			t.Fatalf("GET through the resolver: unexpected error: %v", err)
+		}
+		resp.Body.Close()
+		client.CloseIdleConnections()
+	}
+	if dns.queries != 1 {
+		t.Errorf("%d queries for two dials, want 1", dns.queries)
+	}
+	if _, err := client.Get("http://internal.test:" + port); !errors.Is(err, ErrBlocked) {
+		t.Errorf("GET a host resolving to a private address = %v, want ErrBlocked", err)
+	}
+	if _, err := client.Get("http://compat.test:" + port); !errors.Is(err, ErrBlocked) {
+		t.Errorf("GET a host resolving to an IPv4-compatible loopback = %v, want ErrBlocked", err)
+	}
+	var dnsErr *net.DNSError
+	if _, err := client.Get("http://missing.test:" + port); !errors.As(err, &dnsErr) || !strings.Contains(err.Error(), "no such host") {
+		t.Errorf("GET an unknown host = %v, want a DNS error", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Package notify renders the operational notices harborhook sends tenants' ops contacts, and
+// emails them.
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Notice kinds
+const (
+	KindEndpointDisabled  = "endpoint_disabled"   // an endpoint was disabled for failing
+	KindSecretRotationDue = "secret_rotation_due" // an endpoint's signing secret is older than the tenant allows
+	KindDLQThreshold      = "dlq_threshold"       // the tenant's DLQ holds more entries than it allows
+)
+
+// EventTypePrefix prefixes the event type notices are delivered to an ops webhook as, e.g.
+// harborhook.notice.endpoint_disabled
+const EventTypePrefix = "harborhook.notice."
+
+// Notice is one notice for a tenant. Details hold what its kind's template shows.
+type Notice struct {
+	Kind       string         `json:"kind"`
+	TenantID   string         `json:"tenant_id"`
+	EndpointID string         `json:"endpoint_id,omitempty"` // "" for notices about the whole tenant
+	Details    map[string]any `json:"details"`
+	At         time.Time      `json:"at"`
+}
+
+// templates are each kind's subject and body
+var templates = map[string]*template.Template{
+	KindEndpointDisabled: template.Must(template.New(KindEndpointDisabled).Parse(
+		`{{define "subject"}}Endpoint {{.EndpointID}} was disabled{{end}}` +
+			`{{.Details.message}}
+
+Endpoint: {{.EndpointID}} ({{.Details.url}})
+Disabled: {{.At.Format "2006-01-02 15:04 MST"}}
+
+New events are not delivered to the endpoint until it is enabled again:
+  harborctl endpoint enable {{.TenantID}} {{.EndpointID}}
+`)),
+	KindSecretRotationDue: template.Must(template.New(KindSecretRotationDue).Parse(
+		`{{define "subject"}}Signing secret of endpoint {{.EndpointID}} is due for rotation{{end}}` +
+			`The signing secret of endpoint {{.EndpointID}} ({{.Details.url}}) is {{.Details.age_days}} days old; your policy is to rotate secrets every {{.Details.max_age_days}} days.
+
+Create an endpoint with a new secret for the same receiver, move its subscriptions over, and delete this one.
+`)),
+	KindDLQThreshold: template.Must(template.New(KindDLQThreshold).Parse(
+		`{{define "subject"}}Dead letter queue holds {{.Details.entries}} entries{{end}}` +
+			`Your dead letter queue holds {{.Details.entries}} entries, over your threshold of {{.Details.threshold}}.
+
+Deliveries land there after exhausting their retries. Inspect them, then replay or purge them:
+  harborctl dlq list --tenant-id {{.TenantID}}
+`)),
+}
+
+// Render returns n's subject and body from its kind's template
+func Render(n Notice) (subject, body string, err error) {
+	t, ok := templates[n.Kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for notice kind %q", n.Kind)
+	}
+	var s, b bytes.Buffer
+	if err := t.ExecuteTemplate(&s, "subject", n); err != nil {
+		return "", "", fmt.Errorf("render %s subject: %w", n.Kind, err)
+	}
+	if err := t.Execute(&b, n); err != nil {
+		return "", "", fmt.Errorf("render %s body: %w", n.Kind, err)
+	}
+	return s.String(), b.String(), nil
+}
+
+// Mailer sends plain-text email through an SMTP server
+type Mailer struct {
+	Addr string // host:port
+	User string // empty sends without authenticating
+	Pass string
+	From string
+}
+
+// Send emails subject and body to to
+func (m *Mailer) Send(to, subject, body string, at time.Time) error {
+	var auth smtp.Auth
+	if m.User != "" {
+		host, _, _ := net.SplitHostPort(m.Addr)
+		auth = smtp.PlainAuth("", m.User, m.Pass, host)
+	}
+	return smtp.SendMail(m.Addr, auth, m.From, []string{to}, m.message(to, subject, body, at))
+}
+
+func (m *Mailer) message(to, subject, body string, at time.Time) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.From)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", at.Format(time.RFC1123Z))
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return []byte(b.String())
+}
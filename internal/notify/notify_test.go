@@ -0,0 +1,60 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		n           Notice
+		wantSubject string
+		wantBody    string
+	}{
+		{
+			Notice{Kind: KindEndpointDisabled, TenantID: "tn_1", EndpointID: "ep_1", At: at, Details: map[string]any{
+				"message": "Endpoint disabled because its last 25 deliveries were dead-lettered", "url": "https://partner.example/hook",
+			}},
+			"Endpoint ep_1 was disabled",
+			"harborctl endpoint enable tn_1 ep_1",
+		},
+		{
+			Notice{Kind: KindSecretRotationDue, TenantID: "tn_1", EndpointID: "ep_1", At: at, Details: map[string]any{
+				"url": "https://partner.example/hook", "age_days": 400, "max_age_days": 365,
+			}},
+			"Signing secret of endpoint ep_1 is due for rotation",
+			"is 400 days old; your policy is to rotate secrets every 365 days",
+		},
+		{
+			Notice{Kind: KindDLQThreshold, TenantID: "tn_1", At: at, Details: map[string]any{"entries": 1200, "threshold": 1000}},
+			"Dead letter queue holds 1200 entries",
+			"over your threshold of 1000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.n.Kind, func(t *testing.T) {
+			subject, body, err := Render(tt.n)
+			if err != nil {
+				t.Fatalf("Render() unexpected error: %v", err)
+			}
+			if subject != tt.wantSubject || !strings.Contains(body, tt.wantBody) {
+				t.Errorf("Render() = %q, %q; want subject %q and a body with %q", subject, body, tt.wantSubject, tt.wantBody)
+			}
+		})
+	}
+
+	if _, _, err := Render(Notice{Kind: "unknown"}); err == nil {
+		t.Error("Render(unknown kind) succeeded, want an error")
+	}
+}
+
+func TestMailer_Message(t *testing.T) {
+	m := &Mailer{From: "harborhook@example.com"}
+	msg := string(m.message("ops@partner.example", "Endpoint ep_1 was disabled", "line one\nline two\n", time.Unix(1_700_000_000, 0)))
+	if !strings.Contains(msg, "To: ops@partner.example\r\n") || !strings.Contains(msg, "Subject: Endpoint ep_1 was disabled\r\n") ||
+		!strings.HasSuffix(msg, "\r\n\r\nline one\r\nline two\r\n") {
+		t.Errorf("message() = %q", msg)
+	}
+}
@@ -0,0 +1,391 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// deliverAtHeader carries a deferred message's due time in Unix milliseconds. Kafka has no
+	// delayed delivery, so consumers hold such messages until they are due.
+	deliverAtHeader = "harborhook-deliver-at"
+
+	kafkaPublishTimeout = 10 * time.Second
+	kafkaCommitTimeout  = 5 * time.Second
+	kafkaFetchRetry     = time.Second // pause after a failed fetch
+)
+
+type kafkaPublisher struct {
+	w       *kafka.Writer
+	brokers []string
+}
+
+func newKafkaPublisher(brokers []string) (*kafkaPublisher, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("KAFKA_BROKERS is required for the kafka queue backend")
+	}
+	return &kafkaPublisher{w: &kafka.Writer{
+		Addr:                   kafka.TCP(brokers...),
+		Balancer:               &kafka.LeastBytes{},
+		RequiredAcks:           kafka.RequireAll,
+		AllowAutoTopicCreation: true,
+		BatchTimeout:           10 * time.Millisecond, // every publish is waited on; don't hold it for the 1s default
+	}, brokers: brokers}, nil
+}
+
+func (p *kafkaPublisher) Publish(topic string, body []byte) error {
+	return p.write(kafka.Message{Topic: topic, Value: body})
+}
+
+func (p *kafkaPublisher) PublishBatch(topic string, bodies [][]byte) []error {
+	msgs := make([]kafka.Message, len(bodies))
+	for i, b := range bodies {
+		msgs[i] = kafka.Message{Topic: topic, Value: b}
+	}
+	errs := make([]error, len(bodies))
+	err := p.write(msgs...)
+	var perMessage kafka.WriteErrors
+	switch {
+	case errors.As(err, &perMessage) && len(perMessage) == len(errs):
+		copy(errs, perMessage)
+	case err != nil:
+		for i := range errs {
+			errs[i] = err
+		}
+	}
+	return errs
+}
+
+func (p *kafkaPublisher) DeferredPublish(topic string, delay time.Duration, body []byte) error {
+	return p.write(kafka.Message{
+		Topic:   topic,
+		Value:   body,
+		Headers: []kafka.Header{{Key: deliverAtHeader, Value: []byte(strconv.FormatInt(time.Now().Add(delay).UnixMilli(), 10))}},
+	})
+}
+
+func (p *kafkaPublisher) write(msgs ...kafka.Message) error {
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaPublishTimeout)
+	defer cancel()
+	return p.w.WriteMessages(ctx, msgs...)
+}
+
+// Ping checks that any broker accepts a connection
+func (p *kafkaPublisher) Ping(ctx context.Context) error {
+	var errs []error
+	for _, b := range p.brokers {
+		conn, err := kafka.DialContext(ctx, "tcp", b)
+		if err == nil {
+			return conn.Close()
+		}
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+func (p *kafkaPublisher) Stop() {
+	_ = p.w.Close()
+}
+
+// kafkaConsumer reads a topic as a consumer group. Offsets are committed only up to the
+// oldest unfinished message of each partition, so a restart or rebalance redelivers anything
+// still held (deferred or requeued) along with whatever came after it: delivery is
+// at-least-once, as with NSQ.
+type kafkaConsumer struct {
+	reader  *kafka.Reader
+	commits *commitTracker
+	slots   chan struct{} // one per message handed out and not yet finished
+	work    chan kafkaMessage
+
+	connected atomic.Bool // set by Start, false while fetches fail
+
+	fetchCtx    context.Context
+	stopFetch   context.CancelFunc
+	handleCtx   context.Context
+	stopHandle  context.CancelFunc
+	fetchDone   chan struct{}
+	handlerDone chan struct{}
+}
+
+func newKafkaConsumer(brokers []string, topic, group string, maxInFlight int) (*kafkaConsumer, error) {
+	if len(brokers) == 0 {
+		return nil, errors.New("KAFKA_BROKERS is required for the kafka queue backend")
+	}
+	c := &kafkaConsumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: brokers,
+			GroupID: group,
+			Topic:   topic,
+		}),
+		commits:     newCommitTracker(),
+		slots:       make(chan struct{}, max(maxInFlight, 1)),
+		work:        make(chan kafkaMessage),
+		fetchDone:   make(chan struct{}),
+		handlerDone: make(chan struct{}),
+	}
+	c.fetchCtx, c.stopFetch = context.WithCancel(context.Background())
+	c.handleCtx, c.stopHandle = context.WithCancel(context.Background())
+	return c, nil
+}
+
+func (c *kafkaConsumer) Start(h Handler) error {
+	c.connected.Store(true)
+	go c.fetch()
+	go c.handle(h)
+	return nil
+}
+
+// Stop stops fetching, lets the running handler return, then closes the reader. Held messages
+// are dropped uncommitted and will be redelivered.
+func (c *kafkaConsumer) Stop() {
+	c.connected.Store(false)
+	c.stopFetch()
+	<-c.fetchDone
+	c.stopHandle()
+	<-c.handlerDone
+	_ = c.reader.Close()
+}
+
+// Connected reports whether the consumer is started and its last fetch didn't fail
+func (c *kafkaConsumer) Connected() bool {
+	return c.connected.Load()
+}
+
+func (c *kafkaConsumer) fetch() {
+	defer close(c.fetchDone)
+	for {
+		select {
+		case c.slots <- struct{}{}:
+		case <-c.fetchCtx.Done():
+			return
+		}
+		msg, err := c.reader.FetchMessage(c.fetchCtx)
+		if err != nil {
+			<-c.slots
+			if c.fetchCtx.Err() != nil {
+				return
+			}
+			c.connected.Store(false)
+			time.Sleep(kafkaFetchRetry)
+			continue
+		}
+		c.connected.Store(true)
+		c.commits.fetched(msg.Partition, msg.Offset)
+		c.after(time.Until(deliverAt(msg)), msg)
+	}
+}
+
+func (c *kafkaConsumer) handle(h Handler) {
+	defer close(c.handlerDone)
+	for {
+		select {
+		case m := <-c.work:
+			h(m)
+		case <-c.handleCtx.Done():
+			return
+		}
+	}
+}
+
+// after hands msg to the handler once delay has passed
+func (c *kafkaConsumer) after(delay time.Duration, msg kafka.Message) {
+	dispatch := func() {
+		select {
+		case c.work <- kafkaMessage{c: c, msg: msg, responded: new(bool)}:
+		case <-c.handleCtx.Done():
+		}
+	}
+	if delay <= 0 {
+		dispatch()
+		return
+	}
+	time.AfterFunc(delay, dispatch)
+}
+
+// finish commits as far as msg allows and frees its slot
+func (c *kafkaConsumer) finish(msg kafka.Message) {
+	if offset, ok := c.commits.done(msg.Partition, msg.Offset); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), kafkaCommitTimeout)
+		// A failed commit only means redelivery after a restart or rebalance
+		_ = c.reader.CommitMessages(ctx, kafka.Message{Topic: msg.Topic, Partition: msg.Partition, Offset: offset})
+		cancel()
+	}
+	<-c.slots
+}
+
+type kafkaMessage struct {
+	c         *kafkaConsumer
+	msg       kafka.Message
+	responded *bool // handlers run one at a time, so no lock is needed
+}
+
+func (m kafkaMessage) Body() []byte { return m.msg.Value }
+
+func (m kafkaMessage) Finish() {
+	*m.responded = true
+	m.c.finish(m.msg)
+}
+
+// Requeue holds the message in memory and hands it out again after delay; its offset stays
+// uncommitted until it is finished
+func (m kafkaMessage) Requeue(delay time.Duration) {
+	*m.responded = true
+	go m.c.after(delay, m.msg)
+}
+
+func (m kafkaMessage) RequeueWithoutBackoff(delay time.Duration) {
+	m.Requeue(delay)
+}
+
+func (m kafkaMessage) HasResponded() bool { return *m.responded }
+
+// deliverAt returns when a deferred message is due, or the zero time when it isn't deferred
+func deliverAt(msg kafka.Message) time.Time {
+	for _, h := range msg.Headers {
+		if h.Key != deliverAtHeader {
+			continue
+		}
+		if ms, err := strconv.ParseInt(string(h.Value), 10, 64); err == nil {
+			return time.UnixMilli(ms)
+		}
+	}
+	return time.Time{}
+}
+
+// commitTracker works out how far each partition may be committed when messages finish out of
+// order: only up to the oldest message that is still unfinished
+type commitTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+type partitionOffsets struct {
+	pending  []int64 // fetched offsets not yet committed, in fetch order
+	finished map[int64]bool
+}
+
+func newCommitTracker() *commitTracker {
+	return &commitTracker{partitions: map[int]*partitionOffsets{}}
+}
+
+// fetched records a message handed out from partition. A fetch that goes backwards means the
+// partition was reassigned and rewound, so earlier tracking is dropped.
+func (t *commitTracker) fetched(partition int, offset int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partitions[partition]
+	if p == nil || (len(p.pending) > 0 && offset <= p.pending[len(p.pending)-1]) {
+		p = &partitionOffsets{finished: map[int64]bool{}}
+		t.partitions[partition] = p
+	}
+	p.pending = append(p.pending, offset)
+}
+
+// done records that offset finished. It returns the offset to commit (the newest message
+// with nothing unfinished before it) and whether the commit point moved.
+func (t *commitTracker) done(partition int, offset int64) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.partitions[partition]
+	if p == nil {
+		return 0, false
+	}
+	p.finished[offset] = true
+
+	commit, moved := int64(0), false
+	for len(p.pending) > 0 && p.finished[p.pending[0]] {
+		commit, moved = p.pending[0], true
+		delete(p.finished, p.pending[0])
+		p.pending = p.pending[1:]
+	}
+	return commit, moved
+}
+
+type kafkaInspector struct {
+	client *kafka.Client
+	watch  []ChannelStats
+}
+
+func newKafkaInspector(brokers []string, watch []ChannelStats) *kafkaInspector {
+	return &kafkaInspector{
+		client: &kafka.Client{Addr: kafka.TCP(brokers...), Timeout: 10 * time.Second},
+		watch:  watch,
+	}
+}
+
+// Stats reports each watched consumer group's lag: messages past its committed offsets
+func (i *kafkaInspector) Stats(ctx context.Context) ([]ChannelStats, error) {
+	out := make([]ChannelStats, 0, len(i.watch))
+	for _, w := range i.watch {
+		depth, err := i.lag(ctx, w.Topic, w.Channel)
+		if err != nil {
+			return nil, fmt.Errorf("kafka lag for %s/%s: %w", w.Topic, w.Channel, err)
+		}
+		out = append(out, ChannelStats{Topic: w.Topic, Channel: w.Channel, Depth: depth})
+	}
+	return out, nil
+}
+
+func (i *kafkaInspector) lag(ctx context.Context, topic, group string) (int64, error) {
+	meta, err := i.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return 0, err
+	}
+	var partitions []int
+	for _, t := range meta.Topics {
+		if t.Error != nil {
+			return 0, t.Error
+		}
+		for _, p := range t.Partitions {
+			partitions = append(partitions, p.ID)
+		}
+	}
+	if len(partitions) == 0 {
+		return 0, nil
+	}
+
+	requests := make([]kafka.OffsetRequest, 0, 2*len(partitions))
+	for _, p := range partitions {
+		requests = append(requests, kafka.FirstOffsetOf(p), kafka.LastOffsetOf(p))
+	}
+	offsets, err := i.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{Topics: map[string][]kafka.OffsetRequest{topic: requests}})
+	if err != nil {
+		return 0, err
+	}
+	committed, err := i.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{GroupID: group, Topics: map[string][]int{topic: partitions}})
+	if err != nil {
+		return 0, err
+	}
+	if committed.Error != nil {
+		return 0, committed.Error
+	}
+
+	from := map[int]int64{}
+	for _, p := range committed.Topics[topic] {
+		from[p.Partition] = p.CommittedOffset
+	}
+	var lag int64
+	for _, p := range offsets.Topics[topic] {
+		if p.Error != nil {
+			return 0, p.Error
+		}
+		lag += partitionLag(p.FirstOffset, p.LastOffset, from[p.Partition])
+	}
+	return lag, nil
+}
+
+// partitionLag is how many messages in [first, last) a group has not consumed. committed is
+// the next offset the group will read, or negative when it has never committed.
+func partitionLag(first, last, committed int64) int64 {
+	if committed < first {
+		committed = first
+	}
+	return max(last-committed, 0)
+}
@@ -0,0 +1,114 @@
+package queue
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestCommitTracker(t *testing.T) {
+	type step struct {
+		finish     int64
+		wantCommit int64
+		wantMoved  bool
+	}
+	tests := []struct {
+		name    string
+		fetched []int64
+		steps   []step
+	}{
+		{
+			name:    "in order",
+			fetched: []int64{10, 11, 12},
+			steps:   []step{{10, 10, true}, {11, 11, true}, {12, 12, true}},
+		},
+		{
+			name:    "held message blocks later commits",
+			fetched: []int64{10, 11, 12},
+			steps:   []step{{11, 0, false}, {12, 0, false}, {10, 12, true}},
+		},
+		{
+			name:    "gaps in offsets",
+			fetched: []int64{10, 14, 20},
+			steps:   []step{{14, 0, false}, {10, 14, true}, {20, 20, true}},
+		},
+		{
+			name:    "rewind after rebalance drops earlier tracking",
+			fetched: []int64{10, 11, 5},
+			steps:   []step{{10, 0, false}, {5, 5, true}},
+		},
+		{
+			name:    "unknown offset",
+			fetched: nil,
+			steps:   []step{{3, 0, false}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := newCommitTracker()
+			for _, o := range tt.fetched {
+				tr.fetched(0, o)
+			}
+			for _, s := range tt.steps {
+				commit, moved := tr.done(0, s.finish)
+				if commit != s.wantCommit || moved != s.wantMoved {
+					t.Errorf("done(%d) = (%d, %v), want (%d, %v)", s.finish, commit, moved, s.wantCommit, s.wantMoved)
+				}
+			}
+		})
+	}
+}
+
+func TestCommitTracker_PartitionsAreIndependent(t *testing.T) {
+	tr := newCommitTracker()
+	tr.fetched(0, 1)
+	tr.fetched(1, 1)
+	if _, moved := tr.done(1, 1); !moved {
+		t.Error("partition 1 should commit regardless of partition 0")
+	}
+}
+
+func TestDeliverAt(t *testing.T) {
+	due := time.UnixMilli(1_750_000_000_000)
+	tests := []struct {
+		name    string
+		headers []kafka.Header
+		want    time.Time
+	}{
+		{name: "not deferred", want: time.Time{}},
+		{name: "deferred", headers: []kafka.Header{{Key: "other", Value: []byte("x")}, {Key: deliverAtHeader, Value: []byte(strconv.FormatInt(due.UnixMilli(), 10))}}, want: due},
+		{name: "unreadable", headers: []kafka.Header{{Key: deliverAtHeader, Value: []byte("soon")}}, want: time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deliverAt(kafka.Message{Headers: tt.headers}); !got.Equal(tt.want) {
+				t.Errorf("deliverAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPartitionLag(t *testing.T) {
+	tests := []struct {
+		name                   string
+		first, last, committed int64
+		want                   int64
+	}{
+		{name: "caught up", first: 0, last: 100, committed: 100, want: 0},
+		{name: "behind", first: 0, last: 100, committed: 60, want: 40},
+		{name: "never committed", first: 20, last: 100, committed: -1, want: 80},
+		{name: "committed before retention", first: 50, last: 100, committed: 10, want: 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := partitionLag(tt.first, tt.last, tt.committed); got != tt.want {
+				t.Errorf("partitionLag(%d, %d, %d) = %d, want %d", tt.first, tt.last, tt.committed, got, tt.want)
+			}
+		})
+	}
+}
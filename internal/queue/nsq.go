@@ -0,0 +1,391 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+)
+
+// nsqdDownFor is how long a publisher skips an nsqd after a failed publish, unless every other
+// nsqd has failed too
+const nsqdDownFor = 5 * time.Second
+
+// nsqProducer is the part of *nsq.Producer the publisher uses
+type nsqProducer interface {
+	Publish(topic string, body []byte) error
+	PublishAsync(topic string, body []byte, done chan *nsq.ProducerTransaction, args ...interface{}) error
+	DeferredPublish(topic string, delay time.Duration, body []byte) error
+	Ping() error
+	Stop()
+}
+
+// nsqPublisher spreads publishes round-robin over one or more nsqd and fails over between them,
+// so one nsqd outage doesn't stop publishing. Consumers find every nsqd through nsqlookupd.
+// A publish that timed out may still have been accepted, so failover can duplicate a message;
+// consumers already handle redelivery.
+type nsqPublisher struct {
+	nodes []*nsqNode
+	next  atomic.Uint64
+	now   func() time.Time
+}
+
+// nsqNode is one nsqd and when it may be tried again after failing
+type nsqNode struct {
+	addr      string
+	prod      nsqProducer
+	downUntil atomic.Int64 // unix nanoseconds; 0 while healthy
+}
+
+func newNSQPublisher(nsqdTCPAddrs []string) (*nsqPublisher, error) {
+	if len(nsqdTCPAddrs) == 0 {
+		return nil, errors.New("no nsqd address to publish to")
+	}
+	p := &nsqPublisher{now: time.Now}
+	for _, addr := range nsqdTCPAddrs {
+		prod, err := nsq.NewProducer(addr, nsq.NewConfig())
+		if err != nil {
+			p.Stop()
+			return nil, fmt.Errorf("nsqd %s: %w", addr, err)
+		}
+		p.nodes = append(p.nodes, &nsqNode{addr: addr, prod: prod})
+	}
+	return p, nil
+}
+
+// order returns the nodes to try for one publish: healthy ones first, starting at the next in
+// the rotation, then the ones marked down in case they have recovered
+func (p *nsqPublisher) order() []*nsqNode {
+	start := int(p.next.Add(1) - 1)
+	now := p.now().UnixNano()
+	healthy := make([]*nsqNode, 0, len(p.nodes))
+	var down []*nsqNode
+	for i := range p.nodes {
+		n := p.nodes[(start+i)%len(p.nodes)]
+		if n.downUntil.Load() > now {
+			down = append(down, n)
+		} else {
+			healthy = append(healthy, n)
+		}
+	}
+	return append(healthy, down...)
+}
+
+// try calls fn with each node in turn until one succeeds, marking the ones that fail down
+func (p *nsqPublisher) try(fn func(n *nsqNode) error) error {
+	var errs []error
+	for _, n := range p.order() {
+		err := fn(n)
+		if err == nil {
+			n.downUntil.Store(0)
+			return nil
+		}
+		n.downUntil.Store(p.now().Add(nsqdDownFor).UnixNano())
+		errs = append(errs, fmt.Errorf("nsqd %s: %w", n.addr, err))
+	}
+	return errors.Join(errs...)
+}
+
+func (p *nsqPublisher) Publish(topic string, body []byte) error {
+	return p.try(func(n *nsqNode) error { return n.prod.Publish(topic, body) })
+}
+
+// PublishBatch publishes every body with PublishAsync and waits for all acknowledgements. Bodies
+// an nsqd fails are published again to the next one.
+func (p *nsqPublisher) PublishBatch(topic string, bodies [][]byte) []error {
+	errs := make([]error, len(bodies))
+	remaining := make([]int, len(bodies))
+	for i := range bodies {
+		remaining[i] = i
+	}
+	for _, n := range p.order() {
+		failed := publishAsync(n.prod, topic, bodies, remaining, errs)
+		if len(failed) == 0 {
+			n.downUntil.Store(0)
+			return errs
+		}
+		n.downUntil.Store(p.now().Add(nsqdDownFor).UnixNano())
+		remaining = failed
+	}
+	return errs
+}
+
+// publishAsync publishes bodies[i] for each i in idx to prod, setting errs[i] to each result,
+// and returns the indexes that failed
+func publishAsync(prod nsqProducer, topic string, bodies [][]byte, idx []int, errs []error) []int {
+	// Buffered for every message so the producer never blocks on an unread acknowledgement
+	done := make(chan *nsq.ProducerTransaction, len(idx))
+	pending := 0
+	for _, i := range idx {
+		if err := prod.PublishAsync(topic, bodies[i], done, i); err != nil {
+			errs[i] = err
+			continue
+		}
+		pending++
+	}
+	for range pending {
+		tr := <-done
+		errs[tr.Args[0].(int)] = tr.Error
+	}
+	var failed []int
+	for _, i := range idx {
+		if errs[i] != nil {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+func (p *nsqPublisher) DeferredPublish(topic string, delay time.Duration, body []byte) error {
+	return p.try(func(n *nsqNode) error { return n.prod.DeferredPublish(topic, delay, body) })
+}
+
+// Ping checks at least one nsqd answers, connecting first if needed, and marks the ones that
+// don't down. nsqd's dial timeout bounds it rather than ctx.
+func (p *nsqPublisher) Ping(context.Context) error {
+	var errs []error
+	for _, n := range p.nodes {
+		if err := n.prod.Ping(); err != nil {
+			n.downUntil.Store(p.now().Add(nsqdDownFor).UnixNano())
+			errs = append(errs, fmt.Errorf("nsqd %s: %w", n.addr, err))
+			continue
+		}
+		n.downUntil.Store(0)
+	}
+	if len(errs) == len(p.nodes) {
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func (p *nsqPublisher) Stop() {
+	for _, n := range p.nodes {
+		n.prod.Stop()
+	}
+}
+
+// nsqConsumer reads a channel from every nsqd its nsqlookupd know of, and optionally straight
+// from one nsqd as well
+type nsqConsumer struct {
+	consumer    *nsq.Consumer
+	nsqdAddr    string   // "" when not connecting straight to nsqd
+	lookupAddrs []string // nsqlookupd HTTP addresses, with or without a scheme
+}
+
+func newNSQConsumer(nsqdTCPAddr string, lookupHTTPAddrs []string, lookupPoll time.Duration, topic, channel string, maxInFlight int) (*nsqConsumer, error) {
+	if nsqdTCPAddr == "" && len(lookupHTTPAddrs) == 0 {
+		return nil, errors.New("no nsqd or nsqlookupd address to consume from")
+	}
+	conf := nsq.NewConfig()
+	conf.MaxInFlight = maxInFlight
+	conf.MaxAttempts = 0 // attempts are tracked by callers; holds must never exhaust them
+	if lookupPoll > 0 {
+		conf.LookupdPollInterval = lookupPoll
+	}
+	c, err := nsq.NewConsumer(topic, channel, conf)
+	if err != nil {
+		return nil, err
+	}
+	return &nsqConsumer{consumer: c, nsqdAddr: nsqdTCPAddr, lookupAddrs: lookupHTTPAddrs}, nil
+}
+
+func (c *nsqConsumer) Start(h Handler) error {
+	c.consumer.AddHandler(nsq.HandlerFunc(func(m *nsq.Message) error {
+		m.DisableAutoResponse() // handlers finish or requeue explicitly
+		h(nsqMessage{m})
+		return nil
+	}))
+	// Connecting directly to nsqd forces channel creation, instead of the channel being lazily created on first publish
+	if c.nsqdAddr != "" {
+		if err := c.consumer.ConnectToNSQD(c.nsqdAddr); err != nil {
+			return fmt.Errorf("connect to nsqd: %w", err)
+		}
+	}
+	if len(c.lookupAddrs) > 0 {
+		if err := c.consumer.ConnectToNSQLookupds(c.lookupAddrs); err != nil {
+			return fmt.Errorf("connect to lookupd: %w", err)
+		}
+	}
+	return nil
+}
+
+func (c *nsqConsumer) Stop() {
+	c.consumer.Stop()
+	<-c.consumer.StopChan
+}
+
+// Connected reports whether any nsqd connection is open
+func (c *nsqConsumer) Connected() bool {
+	return c.consumer.Stats().Connections > 0
+}
+
+type nsqMessage struct {
+	m *nsq.Message
+}
+
+func (m nsqMessage) Body() []byte                              { return m.m.Body }
+func (m nsqMessage) Finish()                                   { m.m.Finish() }
+func (m nsqMessage) Requeue(delay time.Duration)               { m.m.Requeue(delay) }
+func (m nsqMessage) RequeueWithoutBackoff(delay time.Duration) { m.m.RequeueWithoutBackoff(delay) }
+func (m nsqMessage) HasResponded() bool                        { return m.m.HasResponded() }
+
+// nsqStats is the part of nsqd's /stats response we read
+type nsqStats struct {
+	Topics []struct {
+		TopicName string `json:"topic_name"`
+		Channels  []struct {
+			ChannelName   string `json:"channel_name"`
+			Depth         int64  `json:"depth"`
+			InFlightCount int64  `json:"in_flight_count"`
+		} `json:"channels"`
+	} `json:"topics"`
+}
+
+// nsqLookupNodes is the part of nsqlookupd's /nodes response we read
+type nsqLookupNodes struct {
+	Producers []struct {
+		BroadcastAddress string `json:"broadcast_address"`
+		HTTPPort         int    `json:"http_port"`
+	} `json:"producers"`
+}
+
+// nsqInspector reads the stats of every nsqd registered with its nsqlookupd, so a multi-node
+// cluster is covered without listing its nodes
+type nsqInspector struct {
+	httpAddr    string   // nsqd HTTP address read when there is no nsqlookupd, or none answers or knows any nsqd
+	lookupAddrs []string // nsqlookupd HTTP addresses, with or without a scheme
+}
+
+// Stats reads every channel's depth from each nsqd's HTTP stats endpoint
+func (i *nsqInspector) Stats(ctx context.Context) ([]ChannelStats, error) {
+	nodes, err := i.nodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		out  []ChannelStats
+		errs []error
+		read int
+	)
+	for _, node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stats, err := nsqdStats(ctx, node)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, &NodeError{Node: node, Err: err})
+				return
+			}
+			read++
+			out = append(out, stats...)
+		}()
+	}
+	wg.Wait()
+	if read == 0 {
+		return nil, errors.Join(errs...)
+	}
+	// Node order follows whichever nsqd answered first; sort for stable output
+	slices.SortStableFunc(out, func(a, b ChannelStats) int { return strings.Compare(a.Node, b.Node) })
+	return out, errors.Join(errs...)
+}
+
+// nodes returns the HTTP addresses of the nsqd to read: every producer any nsqlookupd knows
+// of, or httpAddr when none answers or knows any
+func (i *nsqInspector) nodes(ctx context.Context) ([]string, error) {
+	var (
+		nodes []string
+		errs  []error
+	)
+	for _, addr := range i.lookupAddrs {
+		found, err := lookupNSQDs(ctx, addr)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("nsqlookupd %s: %w", addr, err))
+			continue
+		}
+		for _, n := range found {
+			if !slices.Contains(nodes, n) {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	if len(nodes) > 0 {
+		return nodes, nil
+	}
+	if i.httpAddr != "" {
+		return []string{i.httpAddr}, nil
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return nil, errors.New("no nsqd to inspect")
+}
+
+// lookupNSQDs lists the HTTP addresses of the nsqd registered with one nsqlookupd
+func lookupNSQDs(ctx context.Context, addr string) ([]string, error) {
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	var nodes nsqLookupNodes
+	if err := getJSON(ctx, strings.TrimSuffix(addr, "/")+"/nodes", &nodes); err != nil {
+		return nil, err
+	}
+	out := make([]string, 0, len(nodes.Producers))
+	for _, p := range nodes.Producers {
+		out = append(out, net.JoinHostPort(p.BroadcastAddress, strconv.Itoa(p.HTTPPort)))
+	}
+	return out, nil
+}
+
+// nsqdStats reads one nsqd's channels
+func nsqdStats(ctx context.Context, node string) ([]ChannelStats, error) {
+	var stats nsqStats
+	if err := getJSON(ctx, fmt.Sprintf("http://%s/stats?format=json", node), &stats); err != nil {
+		return nil, err
+	}
+	return stats.channels(node), nil
+}
+
+// getJSON decodes the JSON body of a successful GET of url into out
+func getJSON(ctx context.Context, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s: %w", url, err)
+	}
+	return nil
+}
+
+func (s nsqStats) channels(node string) []ChannelStats {
+	var out []ChannelStats
+	for _, t := range s.Topics {
+		for _, c := range t.Channels {
+			out = append(out, ChannelStats{Node: node, Topic: t.TopicName, Channel: c.ChannelName, Depth: c.Depth, InFlight: c.InFlightCount})
+		}
+	}
+	return out
+}
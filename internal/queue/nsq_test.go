@@ -0,0 +1,237 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nsqio/go-nsq"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+func TestNSQInspector_Stats(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/stats" || r.URL.Query().Get("format") != "json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"topics":[
+			{"topic_name":"deliveries","depth":3,"channels":[{"channel_name":"workers","depth":42,"in_flight_count":7}]},
+			{"topic_name":"delivery_changes","channels":[]}
+		]}`))
+	}))
+	defer srv.Close()
+
+	i := &nsqInspector{httpAddr: strings.TrimPrefix(srv.URL, "http://")}
+	got, err := i.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	want := ChannelStats{Node: i.httpAddr, Topic: "deliveries", Channel: "workers", Depth: 42, InFlight: 7}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Stats() = %+v, want [%+v]", got, want)
+	}
+}
+
+func TestNSQInspector_Lookupd(t *testing.T) {
+	nsqd := func(depth int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			fmt.Fprintf(w, `{"topics":[{"topic_name":"deliveries","channels":[{"channel_name":"workers","depth":%d,"in_flight_count":1}]}]}`, depth)
+		}))
+	}
+	a, b := nsqd(10), nsqd(32)
+	defer a.Close()
+	defer b.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) }))
+	defer down.Close()
+
+	producers := func(srvs ...*httptest.Server) string {
+		var ps []string
+		for _, s := range srvs {
+			host, port, _ := net.SplitHostPort(strings.TrimPrefix(s.URL, "http://"))
+			ps = append(ps, fmt.Sprintf(`{"broadcast_address":%q,"http_port":%s}`, host, port))
+		}
+		return `{"producers":[` + strings.Join(ps, ",") + `]}`
+	}
+	// Two nsqlookupd that each know of some nsqd, one of which is down
+	lookupA := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/nodes" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(producers(a, b)))
+	}))
+	defer lookupA.Close()
+	lookupB := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(producers(b, down)))
+	}))
+	defer lookupB.Close()
+
+	i := &nsqInspector{httpAddr: "unused:4151", lookupAddrs: []string{lookupA.URL, strings.TrimPrefix(lookupB.URL, "http://")}}
+	got, err := i.Stats(context.Background())
+	downAddr := strings.TrimPrefix(down.URL, "http://")
+	if failed := FailedNodes(err); len(failed) != 1 || failed[0] != downAddr {
+		t.Errorf("Stats() error = %v, want it to name only %s", err, downAddr)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Stats() = %+v, want an entry from each nsqd that answered", got)
+	}
+	totals := Totals(got)
+	if len(totals) != 1 || totals[0].Depth != 42 || totals[0].InFlight != 2 || totals[0].Node != "" {
+		t.Errorf("Totals() = %+v, want one deliveries/workers entry with depth 42", totals)
+	}
+
+	// Without an answering nsqlookupd, the configured nsqd is read
+	i = &nsqInspector{httpAddr: strings.TrimPrefix(a.URL, "http://"), lookupAddrs: []string{downAddr}}
+	if got, err := i.Stats(context.Background()); err != nil || len(got) != 1 || got[0].Depth != 10 {
+		t.Errorf("Stats() with nsqlookupd down = %+v, %v, want the configured nsqd's stats", got, err)
+	}
+}
+
+// fakeNSQProducer records publishes, failing them all while down
+type fakeNSQProducer struct {
+	down      bool
+	published []string
+}
+
+func (f *fakeNSQProducer) Publish(_ string, body []byte) error {
+	if f.down {
+		return errors.New("connection refused")
+	}
+	f.published = append(f.published, string(body))
+	return nil
+}
+
+func (f *fakeNSQProducer) PublishAsync(topic string, body []byte, done chan *nsq.ProducerTransaction, args ...interface{}) error {
+	done <- &nsq.ProducerTransaction{Error: f.Publish(topic, body), Args: args}
+	return nil
+}
+
+func (f *fakeNSQProducer) DeferredPublish(topic string, _ time.Duration, body []byte) error {
+	return f.Publish(topic, body)
+}
+
+func (f *fakeNSQProducer) Ping() error {
+	if f.down {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (f *fakeNSQProducer) Stop() {}
+
+func newFakeNSQPublisher(prods ...*fakeNSQProducer) *nsqPublisher {
+	p := &nsqPublisher{now: time.Now}
+	for i, prod := range prods {
+		p.nodes = append(p.nodes, &nsqNode{addr: fmt.Sprintf("nsqd-%d:4150", i), prod: prod})
+	}
+	return p
+}
+
+func TestNSQPublisher_RoundRobin(t *testing.T) {
+	a, b := &fakeNSQProducer{}, &fakeNSQProducer{}
+	p := newFakeNSQPublisher(a, b)
+	for i := range 4 {
+		if err := p.Publish("deliveries", []byte(fmt.Sprint(i))); err != nil {
+			t.Fatalf("Publish() unexpected error: %v", err)
+		}
+	}
+	if len(a.published) != 2 || len(b.published) != 2 {
+		t.Errorf("published %v and %v, want the messages spread evenly", a.published, b.published)
+	}
+}
+
+func TestNSQPublisher_Failover(t *testing.T) {
+	a, b := &fakeNSQProducer{down: true}, &fakeNSQProducer{}
+	p := newFakeNSQPublisher(a, b)
+
+	for i := range 3 {
+		if err := p.Publish("deliveries", []byte(fmt.Sprint(i))); err != nil {
+			t.Fatalf("Publish() with one nsqd down unexpected error: %v", err)
+		}
+	}
+	if len(b.published) != 3 {
+		t.Errorf("healthy nsqd got %v, want every message", b.published)
+	}
+	if p.nodes[0].downUntil.Load() == 0 {
+		t.Error("failed nsqd not marked down")
+	}
+
+	errs := p.PublishBatch("deliveries", [][]byte{[]byte("x"), []byte("y")})
+	if errs[0] != nil || errs[1] != nil || len(b.published) != 5 {
+		t.Errorf("PublishBatch() = %v, healthy nsqd got %v; want the batch failed over", errs, b.published)
+	}
+
+	// A recovered nsqd is used again once it answers a ping
+	a.down = false
+	if err := p.Ping(context.Background()); err != nil || p.nodes[0].downUntil.Load() != 0 {
+		t.Errorf("Ping() = %v, down until %d; want the nsqd back in rotation", err, p.nodes[0].downUntil.Load())
+	}
+}
+
+func TestNSQPublisher_AllDown(t *testing.T) {
+	a, b := &fakeNSQProducer{down: true}, &fakeNSQProducer{down: true}
+	p := newFakeNSQPublisher(a, b)
+
+	err := p.Publish("deliveries", []byte("x"))
+	if err == nil || !strings.Contains(err.Error(), "nsqd-0:4150") || !strings.Contains(err.Error(), "nsqd-1:4150") {
+		t.Errorf("Publish() error = %v, want both nsqd failures", err)
+	}
+	if errs := p.PublishBatch("deliveries", [][]byte{[]byte("x")}); errs[0] == nil {
+		t.Error("PublishBatch() expected an error with every nsqd down")
+	}
+	if err := p.Ping(context.Background()); err == nil {
+		t.Error("Ping() expected an error with every nsqd down")
+	}
+
+	// Nodes marked down are still tried when nothing else is left
+	b.down = false
+	if err := p.Publish("deliveries", []byte("y")); err != nil || len(b.published) != 1 {
+		t.Errorf("Publish() = %v after an nsqd recovered, want it delivered", err)
+	}
+}
+
+func TestNewConsumer_NSQTopology(t *testing.T) {
+	cfg := config.Config{
+		Queue: config.Queue{Backend: BackendNSQ},
+		NSQ: config.NSQ{
+			NsqdTCPAddr:        "nsqd:4150",
+			LookupHTTPAddr:     "http://nsqlookupd:4161",
+			LookupHTTPAddrs:    []string{"http://lookupd-1:4161", "lookupd-2:4161"},
+			LookupPollInterval: 15 * time.Second,
+		},
+	}
+
+	c, err := NewConsumer(cfg, "deliveries", "workers", 1)
+	if err != nil {
+		t.Fatalf("NewConsumer() unexpected error: %v", err)
+	}
+	nc := c.(*nsqConsumer)
+	if nc.nsqdAddr != "" || len(nc.lookupAddrs) != 2 || nc.lookupAddrs[1] != "lookupd-2:4161" {
+		t.Errorf("consumer nsqd %q, lookupd %v; want only the listed lookupd", nc.nsqdAddr, nc.lookupAddrs)
+	}
+
+	cfg.NSQ.ConsumeNsqdDirect = true
+	cfg.NSQ.LookupHTTPAddrs = nil
+	c, err = NewConsumer(cfg, "deliveries", "workers", 1)
+	if err != nil {
+		t.Fatalf("NewConsumer() unexpected error: %v", err)
+	}
+	if nc := c.(*nsqConsumer); nc.nsqdAddr != "nsqd:4150" || len(nc.lookupAddrs) != 1 || nc.lookupAddrs[0] != "http://nsqlookupd:4161" {
+		t.Errorf("consumer nsqd %q, lookupd %v; want the direct nsqd and NSQ_LOOKUP_HTTP_ADDR", nc.nsqdAddr, nc.lookupAddrs)
+	}
+
+	if _, err := newNSQConsumer("", nil, 0, "deliveries", "workers", 1); err == nil {
+		t.Error("newNSQConsumer() expected an error without nsqd or nsqlookupd")
+	}
+	if _, err := newNSQConsumer("nsqd:4150", nil, time.Hour, "deliveries", "workers", 1); err == nil {
+		t.Error("newNSQConsumer() expected an error for a poll interval past nsq's 5m limit")
+	}
+}
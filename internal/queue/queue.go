@@ -0,0 +1,217 @@
+// Package queue hides the message broker behind publisher, consumer and inspector interfaces,
+// so ingest, the worker and the monitor run on NSQ (the default), Kafka or Amazon SQS, selected
+// by QUEUE_BACKEND.
+//
+// Topics and channels keep NSQ's vocabulary: on Kafka a channel is a consumer group, and on SQS
+// each topic is one queue shared by all of its consumers.
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+// Supported backends
+const (
+	BackendNSQ   = "nsq"
+	BackendKafka = "kafka"
+	BackendSQS   = "sqs"
+)
+
+// Publisher sends messages to topics
+type Publisher interface {
+	// Publish sends body to topic and waits for the broker to accept it
+	Publish(topic string, body []byte) error
+	// PublishBatch sends bodies to topic and waits for every one; errs[i] is the result for bodies[i]
+	PublishBatch(topic string, bodies [][]byte) (errs []error)
+	// DeferredPublish sends body to topic to be handed to consumers no earlier than delay from now
+	DeferredPublish(topic string, delay time.Duration, body []byte) error
+	// Stop flushes and closes the publisher
+	Stop()
+}
+
+// Pinger is implemented by publishers that can check their broker is reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Ping checks p's broker is reachable. Publishers that can't tell are assumed healthy.
+func Ping(ctx context.Context, p Publisher) error {
+	if pinger, ok := p.(Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// Message is a message handed to a Handler. Exactly one of Finish, Requeue or
+// RequeueWithoutBackoff must be called for it.
+type Message interface {
+	Body() []byte
+	// Finish acknowledges the message so it is not delivered again
+	Finish()
+	// Requeue hands the message back to be delivered again after delay, as a failure
+	// (NSQ slows the consumer down in response)
+	Requeue(delay time.Duration)
+	// RequeueWithoutBackoff hands the message back to be delivered again after delay
+	RequeueWithoutBackoff(delay time.Duration)
+	// HasResponded reports whether Finish or a requeue was called
+	HasResponded() bool
+}
+
+// Handler processes one message. A consumer calls its handler for one message at a time.
+type Handler func(m Message)
+
+// Consumer hands messages from one topic and channel to a Handler
+type Consumer interface {
+	// Start begins handing messages to h
+	Start(h Handler) error
+	// Stop stops fetching messages and waits for running handlers to return
+	Stop()
+	// Connected reports whether the consumer is connected to the broker: false before Start,
+	// and while its last fetch failed
+	Connected() bool
+}
+
+// ChannelStats is the backlog of one channel (consumer group) on a topic
+type ChannelStats struct {
+	Node     string // nsqd the stats are from, as host:port; "" on Kafka and SQS
+	Topic    string
+	Channel  string
+	Depth    int64 // messages waiting to be handed out
+	InFlight int64 // messages handed out and not yet finished; 0 when the broker can't tell
+}
+
+// Inspector reports queue backlogs. On NSQ there is an entry per nsqd; when some nsqd can't be
+// read, Stats returns the others' stats with an error naming the ones that failed (FailedNodes).
+type Inspector interface {
+	Stats(ctx context.Context) ([]ChannelStats, error)
+}
+
+// Totals sums stats across nodes into one entry per topic and channel, in first-seen order
+func Totals(stats []ChannelStats) []ChannelStats {
+	type key struct{ topic, channel string }
+	idx := map[key]int{}
+	var out []ChannelStats
+	for _, c := range stats {
+		k := key{c.Topic, c.Channel}
+		i, ok := idx[k]
+		if !ok {
+			i = len(out)
+			idx[k] = i
+			out = append(out, ChannelStats{Topic: c.Topic, Channel: c.Channel})
+		}
+		out[i].Depth += c.Depth
+		out[i].InFlight += c.InFlight
+	}
+	return out
+}
+
+// NodeError is a broker node whose stats couldn't be read
+type NodeError struct {
+	Node string
+	Err  error
+}
+
+func (e *NodeError) Error() string { return fmt.Sprintf("nsqd %s: %v", e.Node, e.Err) }
+func (e *NodeError) Unwrap() error { return e.Err }
+
+// FailedNodes returns the nodes named by the NodeErrors in err
+func FailedNodes(err error) []string {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var nodes []string
+		for _, e := range joined.Unwrap() {
+			nodes = append(nodes, FailedNodes(e)...)
+		}
+		return nodes
+	}
+	var ne *NodeError
+	if errors.As(err, &ne) {
+		return []string{ne.Node}
+	}
+	return nil
+}
+
+// NewPublisher returns a publisher for the configured backend
+func NewPublisher(cfg config.Config) (Publisher, error) {
+	switch cfg.Queue.Backend {
+	case BackendNSQ:
+		addrs := cfg.NSQ.NsqdTCPAddrs
+		if len(addrs) == 0 {
+			addrs = []string{cfg.NSQ.NsqdTCPAddr}
+		}
+		return newNSQPublisher(addrs)
+	case BackendKafka:
+		return newKafkaPublisher(cfg.Queue.KafkaBrokers)
+	case BackendSQS:
+		queues, err := newSQSQueues(cfg.Queue)
+		if err != nil {
+			return nil, err
+		}
+		return &sqsPublisher{queues: queues}, nil
+	default:
+		return nil, unknownBackend(cfg.Queue.Backend)
+	}
+}
+
+// NewConsumer returns a consumer of topic on channel for the configured backend, handling at
+// most maxInFlight messages at once. On SQS, received messages are moved to the DLQ topic's
+// queue after SQS_MAX_RECEIVES receives when that is set.
+func NewConsumer(cfg config.Config, topic, channel string, maxInFlight int) (Consumer, error) {
+	switch cfg.Queue.Backend {
+	case BackendNSQ:
+		nsqd := cfg.NSQ.NsqdTCPAddr
+		if !cfg.NSQ.ConsumeNsqdDirect {
+			nsqd = ""
+		}
+		return newNSQConsumer(nsqd, lookupAddrs(cfg), cfg.NSQ.LookupPollInterval, topic, channel, maxInFlight)
+	case BackendKafka:
+		return newKafkaConsumer(cfg.Queue.KafkaBrokers, topic, channel, maxInFlight)
+	case BackendSQS:
+		queues, err := newSQSQueues(cfg.Queue)
+		if err != nil {
+			return nil, err
+		}
+		return newSQSConsumer(queues, cfg, topic, maxInFlight), nil
+	default:
+		return nil, unknownBackend(cfg.Queue.Backend)
+	}
+}
+
+// NewInspector returns an inspector for the configured backend. On NSQ it reads every nsqd the
+// nsqlookupd know of, or NsqdHTTPAddr without them. On Kafka and SQS only the listed topic and
+// channel pairs are reported, since neither can list channels per topic.
+func NewInspector(cfg config.Config, watch ...ChannelStats) (Inspector, error) {
+	switch cfg.Queue.Backend {
+	case BackendNSQ:
+		return &nsqInspector{httpAddr: cfg.NSQ.NsqdHTTPAddr, lookupAddrs: lookupAddrs(cfg)}, nil
+	case BackendKafka:
+		return newKafkaInspector(cfg.Queue.KafkaBrokers, watch), nil
+	case BackendSQS:
+		queues, err := newSQSQueues(cfg.Queue)
+		if err != nil {
+			return nil, err
+		}
+		return &sqsInspector{queues: queues, watch: watch}, nil
+	default:
+		return nil, unknownBackend(cfg.Queue.Backend)
+	}
+}
+
+// lookupAddrs returns the nsqlookupd HTTP addresses: LookupHTTPAddrs, or LookupHTTPAddr alone
+func lookupAddrs(cfg config.Config) []string {
+	if len(cfg.NSQ.LookupHTTPAddrs) > 0 {
+		return cfg.NSQ.LookupHTTPAddrs
+	}
+	if cfg.NSQ.LookupHTTPAddr != "" {
+		return []string{cfg.NSQ.LookupHTTPAddr}
+	}
+	return nil
+}
+
+func unknownBackend(name string) error {
+	return fmt.Errorf("unknown queue backend %q (want %s, %s or %s)", name, BackendNSQ, BackendKafka, BackendSQS)
+}
@@ -0,0 +1,32 @@
+package queue
+
+import (
+	"testing"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+func TestUnknownBackend(t *testing.T) {
+	cfg := config.Config{Queue: config.Queue{Backend: "rabbitmq"}}
+
+	if _, err := NewPublisher(cfg); err == nil {
+		t.Error("NewPublisher() expected an error for an unknown backend")
+	}
+	if _, err := NewConsumer(cfg, "deliveries", "workers", 1); err == nil {
+		t.Error("NewConsumer() expected an error for an unknown backend")
+	}
+	if _, err := NewInspector(cfg); err == nil {
+		t.Error("NewInspector() expected an error for an unknown backend")
+	}
+}
+
+func TestKafkaRequiresBrokers(t *testing.T) {
+	cfg := config.Config{Queue: config.Queue{Backend: BackendKafka}}
+
+	if _, err := NewPublisher(cfg); err == nil {
+		t.Error("NewPublisher() expected an error without brokers")
+	}
+	if _, err := NewConsumer(cfg, "deliveries", "workers", 1); err == nil {
+		t.Error("NewConsumer() expected an error without brokers")
+	}
+}
@@ -0,0 +1,367 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+
+	"github.com/austindbirch/harbor_hook/internal/config"
+)
+
+const (
+	sqsMaxDelay      = 15 * time.Minute // longest DelaySeconds SQS accepts
+	sqsMaxVisibility = 12 * time.Hour   // longest visibility timeout SQS accepts
+	sqsBatchSize     = 10               // most messages per send or receive call
+	sqsWaitSeconds   = 20               // long poll duration for receives
+
+	sqsRequestTimeout = 10 * time.Second
+	sqsReceiveRetry   = time.Second // pause after a failed receive
+)
+
+// sqsAPI is the part of the SQS client the backend uses
+type sqsAPI interface {
+	GetQueueUrl(ctx context.Context, in *sqs.GetQueueUrlInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error)
+	CreateQueue(ctx context.Context, in *sqs.CreateQueueInput, optFns ...func(*sqs.Options)) (*sqs.CreateQueueOutput, error)
+	GetQueueAttributes(ctx context.Context, in *sqs.GetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error)
+	SetQueueAttributes(ctx context.Context, in *sqs.SetQueueAttributesInput, optFns ...func(*sqs.Options)) (*sqs.SetQueueAttributesOutput, error)
+	SendMessage(ctx context.Context, in *sqs.SendMessageInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageOutput, error)
+	SendMessageBatch(ctx context.Context, in *sqs.SendMessageBatchInput, optFns ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error)
+	ReceiveMessage(ctx context.Context, in *sqs.ReceiveMessageInput, optFns ...func(*sqs.Options)) (*sqs.ReceiveMessageOutput, error)
+	DeleteMessage(ctx context.Context, in *sqs.DeleteMessageInput, optFns ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error)
+	ChangeMessageVisibility(ctx context.Context, in *sqs.ChangeMessageVisibilityInput, optFns ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error)
+	ListQueues(ctx context.Context, in *sqs.ListQueuesInput, optFns ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error)
+}
+
+// sqsQueues maps topics to SQS queues, one queue per topic, creating them on first use
+type sqsQueues struct {
+	api    sqsAPI
+	prefix string
+
+	mu   sync.Mutex
+	urls map[string]string
+}
+
+func newSQSQueues(cfg config.Queue) (*sqsQueues, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	client := sqs.NewFromConfig(awsCfg, func(o *sqs.Options) {
+		if cfg.SQSEndpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.SQSEndpoint)
+		}
+	})
+	return &sqsQueues{api: client, prefix: cfg.SQSQueuePrefix, urls: map[string]string{}}, nil
+}
+
+// url returns the URL of topic's queue, creating the queue if it doesn't exist
+func (q *sqsQueues) url(ctx context.Context, topic string) (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if u, ok := q.urls[topic]; ok {
+		return u, nil
+	}
+
+	name := aws.String(q.prefix + topic)
+	var u *string
+	got, err := q.api.GetQueueUrl(ctx, &sqs.GetQueueUrlInput{QueueName: name})
+	var missing *types.QueueDoesNotExist
+	switch {
+	case err == nil:
+		u = got.QueueUrl
+	case errors.As(err, &missing):
+		created, err := q.api.CreateQueue(ctx, &sqs.CreateQueueInput{QueueName: name})
+		if err != nil {
+			return "", fmt.Errorf("create sqs queue %s: %w", *name, err)
+		}
+		u = created.QueueUrl
+	default:
+		return "", fmt.Errorf("get sqs queue %s: %w", *name, err)
+	}
+	q.urls[topic] = aws.ToString(u)
+	return q.urls[topic], nil
+}
+
+// redrive makes SQS move messages of topic's queue to dlqTopic's queue once they have been
+// received maxReceives times
+func (q *sqsQueues) redrive(ctx context.Context, topic, dlqTopic string, maxReceives int) error {
+	src, err := q.url(ctx, topic)
+	if err != nil {
+		return err
+	}
+	dst, err := q.url(ctx, dlqTopic)
+	if err != nil {
+		return err
+	}
+	attrs, err := q.api.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(dst),
+		AttributeNames: []types.QueueAttributeName{types.QueueAttributeNameQueueArn},
+	})
+	if err != nil {
+		return fmt.Errorf("get dlq arn: %w", err)
+	}
+	policy, err := json.Marshal(map[string]string{
+		"deadLetterTargetArn": attrs.Attributes[string(types.QueueAttributeNameQueueArn)],
+		"maxReceiveCount":     strconv.Itoa(maxReceives),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := q.api.SetQueueAttributes(ctx, &sqs.SetQueueAttributesInput{
+		QueueUrl:   aws.String(src),
+		Attributes: map[string]string{string(types.QueueAttributeNameRedrivePolicy): string(policy)},
+	}); err != nil {
+		return fmt.Errorf("set redrive policy: %w", err)
+	}
+	return nil
+}
+
+type sqsPublisher struct {
+	queues *sqsQueues
+}
+
+func (p *sqsPublisher) Publish(topic string, body []byte) error {
+	return p.send(topic, 0, body)
+}
+
+// PublishBatch sends bodies in batches of ten, the most SQS takes in one call
+func (p *sqsPublisher) PublishBatch(topic string, bodies [][]byte) []error {
+	errs := make([]error, len(bodies))
+	ctx, cancel := context.WithTimeout(context.Background(), sqsRequestTimeout)
+	defer cancel()
+
+	u, err := p.queues.url(ctx, topic)
+	if err != nil {
+		for i := range errs {
+			errs[i] = err
+		}
+		return errs
+	}
+	for start := 0; start < len(bodies); start += sqsBatchSize {
+		chunk := bodies[start:min(start+sqsBatchSize, len(bodies))]
+		entries := make([]types.SendMessageBatchRequestEntry, len(chunk))
+		for i, b := range chunk {
+			entries[i] = types.SendMessageBatchRequestEntry{Id: aws.String(strconv.Itoa(start + i)), MessageBody: aws.String(string(b))}
+		}
+		out, err := p.queues.api.SendMessageBatch(ctx, &sqs.SendMessageBatchInput{QueueUrl: aws.String(u), Entries: entries})
+		if err != nil {
+			for i := range chunk {
+				errs[start+i] = err
+			}
+			continue
+		}
+		for _, f := range out.Failed {
+			if i, err := strconv.Atoi(aws.ToString(f.Id)); err == nil && i < len(errs) {
+				errs[i] = fmt.Errorf("sqs send failed: %s: %s", aws.ToString(f.Code), aws.ToString(f.Message))
+			}
+		}
+	}
+	return errs
+}
+
+// DeferredPublish delays the message by at most 15 minutes, the SQS limit; consumers hold
+// messages that arrive before they are due
+func (p *sqsPublisher) DeferredPublish(topic string, delay time.Duration, body []byte) error {
+	return p.send(topic, delay, body)
+}
+
+func (p *sqsPublisher) send(topic string, delay time.Duration, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), sqsRequestTimeout)
+	defer cancel()
+	u, err := p.queues.url(ctx, topic)
+	if err != nil {
+		return err
+	}
+	_, err = p.queues.api.SendMessage(ctx, &sqs.SendMessageInput{
+		QueueUrl:     aws.String(u),
+		MessageBody:  aws.String(string(body)),
+		DelaySeconds: seconds(delay, sqsMaxDelay),
+	})
+	return err
+}
+
+// Stop is a no-op: sends are synchronous and the client holds no connections to close
+// Ping checks SQS answers by listing at most one of the prefix's queues
+func (p *sqsPublisher) Ping(ctx context.Context) error {
+	_, err := p.queues.api.ListQueues(ctx, &sqs.ListQueuesInput{QueueNamePrefix: aws.String(p.queues.prefix), MaxResults: aws.Int32(1)})
+	return err
+}
+
+func (p *sqsPublisher) Stop() {}
+
+// sqsConsumer long-polls a topic's queue. Channels don't apply: every consumer of a topic
+// shares its queue. Messages stay invisible while they are handled and are deleted when
+// finished; requeues shorten or extend their visibility instead of sending them again, so
+// SQS counts every receive toward the queue's redrive limit.
+type sqsConsumer struct {
+	queues      *sqsQueues
+	topic       string
+	dlqTopic    string
+	maxReceives int
+	batch       int32
+	visibility  int32
+	connected   atomic.Bool // set once the queue is resolved, false while receives fail
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func newSQSConsumer(queues *sqsQueues, cfg config.Config, topic string, maxInFlight int) *sqsConsumer {
+	c := &sqsConsumer{
+		queues:      queues,
+		topic:       topic,
+		dlqTopic:    cfg.NSQ.DLQTopic,
+		maxReceives: cfg.Queue.SQSMaxReceives,
+		// Received messages wait for the handler with their visibility timeout running, so
+		// fetch no more than one call's worth ahead
+		batch:      int32(min(max(maxInFlight, 1), sqsBatchSize)),
+		visibility: seconds(cfg.Queue.SQSVisibilityTimeout, sqsMaxVisibility),
+		done:       make(chan struct{}),
+	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+	return c
+}
+
+// Start resolves the queue, applies the redrive policy when SQS_MAX_RECEIVES is set, and
+// begins handing messages to h
+func (c *sqsConsumer) Start(h Handler) error {
+	ctx, cancel := context.WithTimeout(c.ctx, sqsRequestTimeout)
+	defer cancel()
+	u, err := c.queues.url(ctx, c.topic)
+	if err != nil {
+		return err
+	}
+	if c.maxReceives > 0 && c.dlqTopic != "" && c.dlqTopic != c.topic {
+		if err := c.queues.redrive(ctx, c.topic, c.dlqTopic, c.maxReceives); err != nil {
+			return err
+		}
+	}
+	c.connected.Store(true)
+	go c.receive(u, h)
+	return nil
+}
+
+// Stop stops receiving and waits for the running handler to return. Messages received but
+// not yet handled become visible again once their visibility timeout runs out.
+func (c *sqsConsumer) Stop() {
+	c.connected.Store(false)
+	c.cancel()
+	<-c.done
+}
+
+// Connected reports whether the consumer is started and its last receive didn't fail
+func (c *sqsConsumer) Connected() bool {
+	return c.connected.Load()
+}
+
+func (c *sqsConsumer) receive(u string, h Handler) {
+	defer close(c.done)
+	for c.ctx.Err() == nil {
+		out, err := c.queues.api.ReceiveMessage(c.ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(u),
+			MaxNumberOfMessages: c.batch,
+			WaitTimeSeconds:     sqsWaitSeconds,
+			VisibilityTimeout:   c.visibility,
+		})
+		if err != nil {
+			if c.ctx.Err() != nil {
+				return
+			}
+			c.connected.Store(false)
+			time.Sleep(sqsReceiveRetry)
+			continue
+		}
+		c.connected.Store(true)
+		for _, m := range out.Messages {
+			if c.ctx.Err() != nil {
+				return
+			}
+			h(&sqsMessage{api: c.queues.api, queueURL: u, msg: m})
+		}
+	}
+}
+
+type sqsMessage struct {
+	api       sqsAPI
+	queueURL  string
+	msg       types.Message
+	responded bool
+}
+
+func (m *sqsMessage) Body() []byte { return []byte(aws.ToString(m.msg.Body)) }
+
+// Finish deletes the message. A failed delete only means it is handed out again after its
+// visibility timeout, as delivery is at-least-once.
+func (m *sqsMessage) Finish() {
+	m.responded = true
+	ctx, cancel := context.WithTimeout(context.Background(), sqsRequestTimeout)
+	defer cancel()
+	_, _ = m.api.DeleteMessage(ctx, &sqs.DeleteMessageInput{QueueUrl: aws.String(m.queueURL), ReceiptHandle: m.msg.ReceiptHandle})
+}
+
+// Requeue makes the message visible again after delay (at most 12 hours, the SQS limit)
+func (m *sqsMessage) Requeue(delay time.Duration) {
+	m.responded = true
+	ctx, cancel := context.WithTimeout(context.Background(), sqsRequestTimeout)
+	defer cancel()
+	_, _ = m.api.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+		QueueUrl:          aws.String(m.queueURL),
+		ReceiptHandle:     m.msg.ReceiptHandle,
+		VisibilityTimeout: seconds(delay, sqsMaxVisibility),
+	})
+}
+
+func (m *sqsMessage) RequeueWithoutBackoff(delay time.Duration) {
+	m.Requeue(delay)
+}
+
+func (m *sqsMessage) HasResponded() bool { return m.responded }
+
+type sqsInspector struct {
+	queues *sqsQueues
+	watch  []ChannelStats
+}
+
+// Stats reports each watched topic's queue: visible messages as depth and received,
+// unfinished ones as in flight
+func (i *sqsInspector) Stats(ctx context.Context) ([]ChannelStats, error) {
+	out := make([]ChannelStats, 0, len(i.watch))
+	for _, w := range i.watch {
+		u, err := i.queues.url(ctx, w.Topic)
+		if err != nil {
+			return nil, err
+		}
+		attrs, err := i.queues.api.GetQueueAttributes(ctx, &sqs.GetQueueAttributesInput{
+			QueueUrl: aws.String(u),
+			AttributeNames: []types.QueueAttributeName{
+				types.QueueAttributeNameApproximateNumberOfMessages,
+				types.QueueAttributeNameApproximateNumberOfMessagesNotVisible,
+			},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("sqs attributes for %s: %w", w.Topic, err)
+		}
+		depth, _ := strconv.ParseInt(attrs.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessages)], 10, 64)
+		inFlight, _ := strconv.ParseInt(attrs.Attributes[string(types.QueueAttributeNameApproximateNumberOfMessagesNotVisible)], 10, 64)
+		out = append(out, ChannelStats{Topic: w.Topic, Channel: w.Channel, Depth: depth, InFlight: inFlight})
+	}
+	return out, nil
+}
+
+// seconds converts d to whole seconds for SQS, rounding up and clamping to [0, limit]
+func seconds(d, limit time.Duration) int32 {
+	d = min(max(d, 0), limit)
+	return int32(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,238 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+)
+
+// fakeSQS records calls and serves queues from memory
+type fakeSQS struct {
+	sqsAPI // calls the tests don't expect panic
+
+	queues     map[string]bool
+	created    []string
+	batches    [][]types.SendMessageBatchRequestEntry
+	failIDs    map[string]bool
+	sent       []*sqs.SendMessageInput
+	deleted    []string
+	visibility map[string]int32
+	attrs      map[string]map[string]string
+	set        map[string]map[string]string
+	listErr    error
+	listed     []*sqs.ListQueuesInput
+}
+
+func newFakeSQS(existing ...string) *fakeSQS {
+	f := &fakeSQS{
+		queues:     map[string]bool{},
+		failIDs:    map[string]bool{},
+		visibility: map[string]int32{},
+		attrs:      map[string]map[string]string{},
+		set:        map[string]map[string]string{},
+	}
+	for _, q := range existing {
+		f.queues[q] = true
+	}
+	return f
+}
+
+func (f *fakeSQS) GetQueueUrl(_ context.Context, in *sqs.GetQueueUrlInput, _ ...func(*sqs.Options)) (*sqs.GetQueueUrlOutput, error) {
+	if !f.queues[*in.QueueName] {
+		return nil, &types.QueueDoesNotExist{}
+	}
+	return &sqs.GetQueueUrlOutput{QueueUrl: aws.String("https://sqs/" + *in.QueueName)}, nil
+}
+
+func (f *fakeSQS) CreateQueue(_ context.Context, in *sqs.CreateQueueInput, _ ...func(*sqs.Options)) (*sqs.CreateQueueOutput, error) {
+	f.queues[*in.QueueName] = true
+	f.created = append(f.created, *in.QueueName)
+	return &sqs.CreateQueueOutput{QueueUrl: aws.String("https://sqs/" + *in.QueueName)}, nil
+}
+
+func (f *fakeSQS) SendMessage(_ context.Context, in *sqs.SendMessageInput, _ ...func(*sqs.Options)) (*sqs.SendMessageOutput, error) {
+	f.sent = append(f.sent, in)
+	return &sqs.SendMessageOutput{}, nil
+}
+
+func (f *fakeSQS) SendMessageBatch(_ context.Context, in *sqs.SendMessageBatchInput, _ ...func(*sqs.Options)) (*sqs.SendMessageBatchOutput, error) {
+	f.batches = append(f.batches, in.Entries)
+	out := &sqs.SendMessageBatchOutput{}
+	for _, e := range in.Entries {
+		if f.failIDs[*e.Id] {
+			out.Failed = append(out.Failed, types.BatchResultErrorEntry{Id: e.Id, Code: aws.String("InvalidMessageContents"), Message: aws.String("bad")})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeSQS) DeleteMessage(_ context.Context, in *sqs.DeleteMessageInput, _ ...func(*sqs.Options)) (*sqs.DeleteMessageOutput, error) {
+	f.deleted = append(f.deleted, *in.ReceiptHandle)
+	return &sqs.DeleteMessageOutput{}, nil
+}
+
+func (f *fakeSQS) ChangeMessageVisibility(_ context.Context, in *sqs.ChangeMessageVisibilityInput, _ ...func(*sqs.Options)) (*sqs.ChangeMessageVisibilityOutput, error) {
+	f.visibility[*in.ReceiptHandle] = in.VisibilityTimeout
+	return &sqs.ChangeMessageVisibilityOutput{}, nil
+}
+
+func (f *fakeSQS) GetQueueAttributes(_ context.Context, in *sqs.GetQueueAttributesInput, _ ...func(*sqs.Options)) (*sqs.GetQueueAttributesOutput, error) {
+	return &sqs.GetQueueAttributesOutput{Attributes: f.attrs[*in.QueueUrl]}, nil
+}
+
+func (f *fakeSQS) SetQueueAttributes(_ context.Context, in *sqs.SetQueueAttributesInput, _ ...func(*sqs.Options)) (*sqs.SetQueueAttributesOutput, error) {
+	f.set[*in.QueueUrl] = in.Attributes
+	return &sqs.SetQueueAttributesOutput{}, nil
+}
+
+func (f *fakeSQS) ListQueues(_ context.Context, in *sqs.ListQueuesInput, _ ...func(*sqs.Options)) (*sqs.ListQueuesOutput, error) {
+	f.listed = append(f.listed, in)
+	return &sqs.ListQueuesOutput{}, f.listErr
+}
+
+func TestSQSQueues_CreatesMissingQueueOnce(t *testing.T) {
+	api := newFakeSQS("hh-deliveries")
+	q := &sqsQueues{api: api, prefix: "hh-", urls: map[string]string{}}
+
+	for range 2 {
+		if u, err := q.url(context.Background(), "delivery_changes"); err != nil || u != "https://sqs/hh-delivery_changes" {
+			t.Fatalf("url() = %q, %v", u, err)
+		}
+	}
+	if _, err := q.url(context.Background(), "deliveries"); err != nil {
+		t.Fatalf("url() error = %v", err)
+	}
+	if len(api.created) != 1 || api.created[0] != "hh-delivery_changes" {
+		t.Errorf("created = %v, want only hh-delivery_changes", api.created)
+	}
+}
+
+func TestSQSPublisher_PublishBatch(t *testing.T) {
+	api := newFakeSQS()
+	api.failIDs["11"] = true
+	p := &sqsPublisher{queues: &sqsQueues{api: api, urls: map[string]string{}}}
+
+	bodies := make([][]byte, 23)
+	for i := range bodies {
+		bodies[i] = []byte(strconv.Itoa(i))
+	}
+	errs := p.PublishBatch("deliveries", bodies)
+
+	if len(api.batches) != 3 || len(api.batches[0]) != 10 || len(api.batches[2]) != 3 {
+		t.Fatalf("batches of %d, want 10, 10, 3", len(api.batches))
+	}
+	for i, err := range errs {
+		if (err != nil) != (i == 11) {
+			t.Errorf("errs[%d] = %v", i, err)
+		}
+	}
+}
+
+func TestSQSPublisher_DeferredPublishClampsDelay(t *testing.T) {
+	api := newFakeSQS()
+	p := &sqsPublisher{queues: &sqsQueues{api: api, urls: map[string]string{}}}
+
+	_ = p.DeferredPublish("deliveries", 1500*time.Millisecond, []byte("a"))
+	_ = p.DeferredPublish("deliveries", time.Hour, []byte("b"))
+	if got := api.sent[0].DelaySeconds; got != 2 {
+		t.Errorf("DelaySeconds = %d, want 2", got)
+	}
+	if got := api.sent[1].DelaySeconds; got != 900 {
+		t.Errorf("DelaySeconds = %d, want 900", got)
+	}
+}
+
+func TestSQSPublisher_Ping(t *testing.T) {
+	api := newFakeSQS()
+	var p Publisher = &sqsPublisher{queues: &sqsQueues{api: api, prefix: "hh-", urls: map[string]string{}}}
+
+	if err := Ping(context.Background(), p); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+	if len(api.listed) != 1 || aws.ToString(api.listed[0].QueueNamePrefix) != "hh-" {
+		t.Errorf("listed %v, want one call for the hh- prefix", api.listed)
+	}
+	api.listErr = errors.New("no credentials")
+	if err := Ping(context.Background(), p); err == nil {
+		t.Error("Ping() expected the list error")
+	}
+}
+
+func TestSQSMessage_Responses(t *testing.T) {
+	api := newFakeSQS()
+
+	finished := &sqsMessage{api: api, queueURL: "u", msg: types.Message{ReceiptHandle: aws.String("r1")}}
+	finished.Finish()
+	requeued := &sqsMessage{api: api, queueURL: "u", msg: types.Message{ReceiptHandle: aws.String("r2")}}
+	requeued.RequeueWithoutBackoff(30 * time.Second)
+
+	if !finished.HasResponded() || !requeued.HasResponded() {
+		t.Error("HasResponded() = false after a response")
+	}
+	if len(api.deleted) != 1 || api.deleted[0] != "r1" {
+		t.Errorf("deleted = %v, want [r1]", api.deleted)
+	}
+	if got := api.visibility["r2"]; got != 30 {
+		t.Errorf("visibility = %d, want 30", got)
+	}
+}
+
+func TestSQSQueues_Redrive(t *testing.T) {
+	api := newFakeSQS("deliveries", "deliveries_dlq")
+	api.attrs["https://sqs/deliveries_dlq"] = map[string]string{"QueueArn": "arn:aws:sqs:us-east-1:1:deliveries_dlq"}
+	q := &sqsQueues{api: api, urls: map[string]string{}}
+
+	if err := q.redrive(context.Background(), "deliveries", "deliveries_dlq", 50); err != nil {
+		t.Fatalf("redrive() error = %v", err)
+	}
+	var policy map[string]string
+	if err := json.Unmarshal([]byte(api.set["https://sqs/deliveries"]["RedrivePolicy"]), &policy); err != nil {
+		t.Fatalf("RedrivePolicy is not JSON: %v", err)
+	}
+	if policy["deadLetterTargetArn"] != "arn:aws:sqs:us-east-1:1:deliveries_dlq" || policy["maxReceiveCount"] != "50" {
+		t.Errorf("RedrivePolicy = %v", policy)
+	}
+}
+
+func TestSQSInspector_Stats(t *testing.T) {
+	api := newFakeSQS("deliveries")
+	api.attrs["https://sqs/deliveries"] = map[string]string{
+		"ApproximateNumberOfMessages":           "42",
+		"ApproximateNumberOfMessagesNotVisible": "7",
+	}
+	i := &sqsInspector{queues: &sqsQueues{api: api, urls: map[string]string{}}, watch: []ChannelStats{{Topic: "deliveries", Channel: "workers"}}}
+
+	got, err := i.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+	want := ChannelStats{Topic: "deliveries", Channel: "workers", Depth: 42, InFlight: 7}
+	if len(got) != 1 || got[0] != want {
+		t.Errorf("Stats() = %+v, want [%+v]", got, want)
+	}
+}
+
+func TestSeconds(t *testing.T) {
+	tests := []struct {
+		d, limit time.Duration
+		want     int32
+	}{
+		{d: -time.Second, limit: time.Minute, want: 0},
+		{d: 0, limit: time.Minute, want: 0},
+		{d: 100 * time.Millisecond, limit: time.Minute, want: 1},
+		{d: 5 * time.Second, limit: time.Minute, want: 5},
+		{d: time.Hour, limit: time.Minute, want: 60},
+	}
+	for _, tt := range tests {
+		if got := seconds(tt.d, tt.limit); got != tt.want {
+			t.Errorf("seconds(%v, %v) = %d, want %d", tt.d, tt.limit, got, tt.want)
+		}
+	}
+}
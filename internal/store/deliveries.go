@@ -0,0 +1,158 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+)
+
+// DeliveryStore records a delivery's progress through its attempts
+type DeliveryStore interface {
+	// MarkInflight records that the worker has dequeued the delivery and is about to send it
+	MarkInflight(ctx context.Context, id string) error
+	// MarkSent records when the attempt's request went out
+	MarkSent(ctx context.Context, id string, at time.Time) error
+	// MarkDelivered records a successful attempt, in the delivery and its attempt history
+	MarkDelivered(ctx context.Context, id string, httpStatus int, latency time.Duration) error
+	// MarkFailed records a failed attempt, in the delivery and its attempt history with the
+	// start of the response body; httpStatus is 0 when there was no response
+	MarkFailed(ctx context.Context, id string, httpStatus int, latency time.Duration, lastErr string, body delivery.ResponseBody) error
+	// FailTerminal fails the delivery for good without sending it, and takes it out of its
+	// ordering partition so later events aren't held behind it
+	FailTerminal(ctx context.Context, id, lastErr string) error
+	// MarkDead sets the delivery dead; the schema stamps dlq_at
+	MarkDead(ctx context.Context, id string) error
+	// ResetInflight returns an inflight delivery to status to, for a send that was abandoned
+	ResetInflight(ctx context.Context, id, to string) error
+	// SetNextTry records when the delivery's retry is due
+	SetNextTry(ctx context.Context, id string, at time.Time) error
+	// Attempt returns how many attempts the delivery has used
+	Attempt(ctx context.Context, id string) (int, error)
+	// AttemptHistory returns the recorded attempts of the given deliveries, in attempt order
+	AttemptHistory(ctx context.Context, ids []string) ([]AttemptRecord, error)
+}
+
+// AttemptRecord is one try at sending a delivery
+type AttemptRecord struct {
+	DeliveryID string
+	Attempt    int32
+	Outcome    string // delivered or failed
+	HTTPStatus int32  // 0 when there was no response
+	LatencyMS  int32
+	Error      string
+	Response   delivery.ResponseBody // the start of a failed attempt's response body
+	SentAt     sql.NullTime
+	FinishedAt time.Time
+}
+
+func (p *Postgres) MarkInflight(ctx context.Context, id string) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE harborhook.deliveries
+		SET status='inflight', dequeued_at=now(), updated_at=now()
+		WHERE id=$1`, id)
+	return err
+}
+
+func (p *Postgres) MarkSent(ctx context.Context, id string, at time.Time) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE harborhook.deliveries
+		SET sent_at=$2, updated_at=now()
+		WHERE id=$1`, id, at)
+	return err
+}
+
+func (p *Postgres) MarkDelivered(ctx context.Context, id string, httpStatus int, latency time.Duration) error {
+	_, err := p.pool.Exec(ctx, `
+		WITH d AS (
+			UPDATE harborhook.deliveries
+			SET status='delivered', delivered_at=now(), attempt=attempt+1, http_status=$1, latency_ms=$2, updated_at=now(), last_error=NULL
+			WHERE id=$3
+			RETURNING id, attempt, sent_at
+		)
+		INSERT INTO harborhook.delivery_attempts (delivery_id, attempt, outcome, http_status, latency_ms, sent_at)
+		SELECT id, attempt, 'delivered', $1, $2, sent_at FROM d
+		ON CONFLICT (delivery_id, attempt) DO NOTHING`,
+		httpStatus, int(latency.Milliseconds()), id,
+	)
+	return err
+}
+
+func (p *Postgres) MarkFailed(ctx context.Context, id string, httpStatus int, latency time.Duration, lastErr string, body delivery.ResponseBody) error {
+	_, err := p.pool.Exec(ctx, `
+		WITH d AS (
+			UPDATE harborhook.deliveries
+			SET status='failed', failed_at=now(), attempt=attempt+1, http_status=$1, latency_ms=$2, updated_at=now(), last_error=$3
+			WHERE id=$4
+			RETURNING id, attempt, sent_at
+		)
+		INSERT INTO harborhook.delivery_attempts
+			(delivery_id, attempt, outcome, http_status, latency_ms, error, response_body, response_truncated, sent_at)
+		SELECT id, attempt, 'failed', NULLIF($1, 0), $2, $3, NULLIF($5, ''), $6, sent_at FROM d
+		ON CONFLICT (delivery_id, attempt) DO NOTHING`,
+		httpStatus, int(latency.Milliseconds()), lastErr, id, body.Text, body.Truncated,
+	)
+	return err
+}
+
+func (p *Postgres) FailTerminal(ctx context.Context, id, lastErr string) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE harborhook.deliveries
+		SET status='failed', attempt=attempt+1, failed_at=now(), updated_at=now(), last_error=$2, ordering_key=NULL
+		WHERE id=$1`, id, lastErr)
+	return err
+}
+
+func (p *Postgres) MarkDead(ctx context.Context, id string) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE harborhook.deliveries SET status='dead' WHERE id=$1`,
+		id,
+	)
+	return err
+}
+
+func (p *Postgres) ResetInflight(ctx context.Context, id, to string) error {
+	_, err := p.pool.Exec(ctx, `
+		UPDATE harborhook.deliveries SET status=$2, updated_at=now()
+		WHERE id=$1 AND status='inflight'`, id, to)
+	return err
+}
+
+func (p *Postgres) SetNextTry(ctx context.Context, id string, at time.Time) error {
+	_, err := p.pool.Exec(ctx, `UPDATE harborhook.deliveries SET next_try_at=$2 WHERE id=$1`, id, at)
+	return err
+}
+
+func (p *Postgres) Attempt(ctx context.Context, id string) (int, error) {
+	var attempt int
+	err := p.pool.QueryRow(ctx, `SELECT attempt FROM harborhook.deliveries WHERE id=$1`, id).Scan(&attempt)
+	return attempt, err
+}
+
+func (p *Postgres) AttemptHistory(ctx context.Context, ids []string) ([]AttemptRecord, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	rows, err := p.pool.Query(ctx, `
+		SELECT delivery_id, attempt, outcome, COALESCE(http_status, 0), COALESCE(latency_ms, 0), COALESCE(error, ''),
+		       COALESCE(response_body, ''), response_truncated, sent_at, finished_at
+		FROM harborhook.delivery_attempts
+		WHERE delivery_id = ANY($1::uuid[])
+		ORDER BY delivery_id, attempt`, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []AttemptRecord
+	for rows.Next() {
+		var a AttemptRecord
+		if err := rows.Scan(&a.DeliveryID, &a.Attempt, &a.Outcome, &a.HTTPStatus, &a.LatencyMS, &a.Error,
+			&a.Response.Text, &a.Response.Truncated, &a.SentAt, &a.FinishedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, a)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,208 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// DLQStore holds dead-lettered deliveries
+type DLQStore interface {
+	// AddToDLQ records why a delivery was dead-lettered
+	AddToDLQ(ctx context.Context, deliveryID, reason string) error
+	// ListDLQ pages through dead-lettered deliveries, newest first
+	ListDLQ(ctx context.Context, f DLQFilter) (DLQPage, error)
+	// PurgeExpiredDLQ removes up to limit DLQ entries past their tenant's retention, or past
+	// defaults for tenants without one, archiving them first where the retention says so
+	PurgeExpiredDLQ(ctx context.Context, defaults DLQRetention, limit int) (DLQPurge, error)
+}
+
+// DLQRetention bounds the DLQ entries a tenant keeps
+type DLQRetention struct {
+	MaxAge     time.Duration // Entries older than this are purged; 0 keeps them regardless of age
+	MaxEntries int           // Only the newest this many are kept; 0 keeps any number
+	Archive    bool          // Purged entries are copied to harborhook.dlq_archive first
+}
+
+// DLQPurge counts the entries a purge removed and, of those, the ones it archived
+type DLQPurge struct {
+	Purged   int64
+	Archived int64
+}
+
+// ArchiveDLQSQL copies the DLQ entries in from, a relation with delivery_id, reason and
+// created_at columns, to harborhook.dlq_archive
+func ArchiveDLQSQL(from string) string {
+	return `
+		INSERT INTO harborhook.dlq_archive
+			(delivery_id, tenant_id, endpoint_id, event_id, event_type, payload, reason, last_error, http_status, attempts, dead_lettered_at)
+		SELECT d.id, ep.tenant_id, d.endpoint_id, d.event_id, ev.event_type, ev.payload, x.reason,
+		       COALESCE(d.error_reason, d.last_error), d.http_status, d.attempt, x.created_at
+		FROM ` + from + ` x
+		JOIN harborhook.deliveries d ON d.id = x.delivery_id
+		JOIN harborhook.events ev ON ev.id = d.event_id
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id`
+}
+
+// DLQFilter selects a page of the DLQ
+type DLQFilter struct {
+	TenantID   string // "" lists every tenant
+	EndpointID string // "" lists every endpoint
+	From, To   *time.Time
+	After      *DLQCursor // the last row of the previous page
+	Limit      int32
+}
+
+// DLQCursor is the position of a DLQ row in ListDLQ's order
+type DLQCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// DeadDelivery is a dead-lettered delivery. Its error is the classified reason when there is
+// one, else the last attempt's error.
+type DeadDelivery struct {
+	ID          string
+	EventID     string
+	EndpointID  string
+	ReplayOf    string
+	Status      string
+	HTTPStatus  int32
+	Error       string
+	EnqueuedAt  sql.NullTime
+	DequeuedAt  sql.NullTime
+	SentAt      sql.NullTime
+	DeliveredAt sql.NullTime
+	FailedAt    sql.NullTime
+	DLQAt       sql.NullTime
+}
+
+// DLQPage is one page of ListDLQ: Total counts every match regardless of the cursor, and Next is
+// nil on the last page
+type DLQPage struct {
+	Dead  []DeadDelivery
+	Total int32
+	Next  *DLQCursor
+}
+
+func (p *Postgres) AddToDLQ(ctx context.Context, deliveryID, reason string) error {
+	_, err := p.pool.Exec(ctx, `
+		INSERT INTO harborhook.dlq(delivery_id, reason) VALUES ($1,$2)`,
+		deliveryID, reason,
+	)
+	return err
+}
+
+func (p *Postgres) ListDLQ(ctx context.Context, f DLQFilter) (DLQPage, error) {
+	args := []any{}
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	where := "1=1"
+	if f.EndpointID != "" {
+		where += " AND d.endpoint_id = " + arg(f.EndpointID)
+	}
+	if f.TenantID != "" {
+		where += " AND ep.tenant_id = " + arg(f.TenantID)
+	}
+	if f.From != nil {
+		where += " AND q.created_at >= " + arg(*f.From)
+	}
+	if f.To != nil {
+		where += " AND q.created_at < " + arg(*f.To)
+	}
+
+	// Total across all pages (ignores the cursor)
+	var page DLQPage
+	if err := p.pool.QueryRow(ctx, fmt.Sprintf(`
+		SELECT count(*)
+		FROM harborhook.deliveries d
+		JOIN harborhook.dlq q ON q.delivery_id = d.id
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		WHERE %s`, where), args...).Scan(&page.Total); err != nil {
+		return DLQPage{}, err
+	}
+
+	if f.After != nil {
+		where += fmt.Sprintf(" AND (q.created_at, q.id) < (%s, %s)", arg(f.After.CreatedAt), arg(f.After.ID))
+	}
+
+	// Use DLQ table ordering; fetch one extra row to know whether another page exists
+	rows, err := p.pool.Query(ctx, fmt.Sprintf(`
+		SELECT d.id, d.event_id, d.endpoint_id, d.replay_of, d.status, d.http_status,
+		       COALESCE(d.error_reason, d.last_error) AS err,
+		       d.enqueued_at, d.dequeued_at, d.sent_at, d.delivered_at, d.failed_at, d.dlq_at,
+		       q.id, q.created_at
+		FROM harborhook.deliveries d
+		JOIN harborhook.dlq q ON q.delivery_id = d.id
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		WHERE %s
+		ORDER BY q.created_at DESC, q.id DESC
+		LIMIT %d`, where, f.Limit+1), args...)
+	if err != nil {
+		return DLQPage{}, err
+	}
+	defer rows.Close()
+
+	var last DLQCursor
+	for rows.Next() {
+		if int32(len(page.Dead)) == f.Limit {
+			page.Next = &last
+			break
+		}
+		var (
+			d                DeadDelivery
+			replayOf, status sql.NullString
+			httpStatus       sql.NullInt32
+			errReason        sql.NullString
+		)
+		if err := rows.Scan(&d.ID, &d.EventID, &d.EndpointID, &replayOf, &status, &httpStatus, &errReason,
+			&d.EnqueuedAt, &d.DequeuedAt, &d.SentAt, &d.DeliveredAt, &d.FailedAt, &d.DLQAt, &last.ID, &last.CreatedAt,
+		); err != nil {
+			return DLQPage{}, err
+		}
+		d.ReplayOf, d.Status, d.HTTPStatus, d.Error = replayOf.String, status.String, httpStatus.Int32, errReason.String
+		page.Dead = append(page.Dead, d)
+	}
+	if err := rows.Err(); err != nil {
+		return DLQPage{}, err
+	}
+	return page, nil
+}
+
+// purgeExpiredDLQQuery ranks each tenant's entries newest first, so an entry is expired once it
+// is older than the tenant's max age or ranks past its max entries
+var purgeExpiredDLQQuery = `
+	WITH ranked AS (
+		SELECT q.id, q.delivery_id, q.reason, q.created_at,
+		       COALESCE(r.max_age_seconds, $1) AS max_age,
+		       COALESCE(r.max_entries, $2) AS max_entries,
+		       COALESCE(r.archive, $3) AS archive,
+		       row_number() OVER (PARTITION BY ep.tenant_id ORDER BY q.created_at DESC, q.id DESC) AS rn
+		FROM harborhook.dlq q
+		JOIN harborhook.deliveries d ON d.id = q.delivery_id
+		JOIN harborhook.endpoints ep ON ep.id = d.endpoint_id
+		LEFT JOIN harborhook.tenant_dlq_retention r ON r.tenant_id = ep.tenant_id
+	), expired AS (
+		SELECT id, delivery_id, reason, created_at, archive
+		FROM ranked
+		WHERE (max_age > 0 AND created_at < now() - make_interval(secs => max_age::double precision))
+		   OR (max_entries > 0 AND rn > max_entries)
+		LIMIT $4
+	), archived AS (` + ArchiveDLQSQL("(SELECT * FROM expired WHERE archive)") + `
+		RETURNING 1
+	), purged AS (
+		DELETE FROM harborhook.dlq q USING expired x WHERE q.id = x.id
+		RETURNING 1
+	)
+	SELECT (SELECT count(*) FROM purged), (SELECT count(*) FROM archived)`
+
+func (p *Postgres) PurgeExpiredDLQ(ctx context.Context, defaults DLQRetention, limit int) (DLQPurge, error) {
+	var out DLQPurge
+	err := p.pool.QueryRow(ctx, purgeExpiredDLQQuery,
+		int64(defaults.MaxAge/time.Second), defaults.MaxEntries, defaults.Archive, limit,
+	).Scan(&out.Purged, &out.Archived)
+	return out, err
+}
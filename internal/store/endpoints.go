@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+)
+
+// EndpointStore reads the endpoint settings deliveries are sent under
+type EndpointStore interface {
+	// EndpointConfig returns what sending to the endpoint needs
+	EndpointConfig(ctx context.Context, endpointID string) (EndpointConfig, error)
+	// RecoveryRamp returns the endpoint's recovery ramp; an endpoint that never recovered has a
+	// zero RecoveredAt. Unhealthy is set while its enabled health checks are failing.
+	RecoveryRamp(ctx context.Context, endpointID string) (delivery.RecoveryRamp, error)
+}
+
+// EndpointConfig is an endpoint's signing material and delivery policies, with its tenant's
+// compliance and delivery settings
+type EndpointConfig struct {
+	Secret              string // "" when the endpoint has none
+	RecordRequests      bool
+	RetentionDays       int
+	RetryMaxAttempts    int
+	RetryBackoffSeconds []int
+	RetryOn             []string
+	SenderHeaders       bool
+	ClientCertPEM       string
+	ClientKeyPEM        string
+	ClientCertSecret    string
+	Compression         string
+	SignatureScheme     string
+	SigningSeed         []byte        // the tenant's Ed25519 seed; nil when it has no key
+	Timeout             time.Duration // 0 when the endpoint uses the worker default
+	Type                string        // delivery.EndpointHTTP or delivery.EndpointCapture
+	ProxyURL            string        // "" when deliveries use the egress proxy
+	Disabled            bool          // disabled for failing; its deliveries aren't sent
+}
+
+func (p *Postgres) EndpointConfig(ctx context.Context, endpointID string) (EndpointConfig, error) {
+	var (
+		c         EndpointConfig
+		secret    sql.NullString
+		timeoutMS int
+	)
+	err := p.pool.QueryRow(ctx, `
+		SELECT e.secret, COALESCE(tc.record_requests, false), COALESCE(tc.retention_days, 0),
+		       e.retry_max_attempts, e.retry_backoff_seconds, e.retry_on, COALESCE(ds.sender_headers, true),
+		       COALESCE(e.client_cert_pem, ''), COALESCE(e.client_key_pem, ''), COALESCE(e.client_cert_secret, ''),
+		       e.compression, e.signature_scheme, sk.private_key, COALESCE(e.timeout_ms, 0), e.endpoint_type,
+		       COALESCE(e.proxy_url, ''), e.disabled_at IS NOT NULL
+		FROM harborhook.endpoints e
+		LEFT JOIN harborhook.tenant_compliance tc ON tc.tenant_id = e.tenant_id
+		LEFT JOIN harborhook.tenant_delivery_settings ds ON ds.tenant_id = e.tenant_id
+		LEFT JOIN harborhook.tenant_signing_keys sk ON sk.tenant_id = e.tenant_id
+		WHERE e.id=$1`,
+		endpointID).Scan(&secret, &c.RecordRequests, &c.RetentionDays, &c.RetryMaxAttempts, &c.RetryBackoffSeconds, &c.RetryOn, &c.SenderHeaders,
+		&c.ClientCertPEM, &c.ClientKeyPEM, &c.ClientCertSecret, &c.Compression, &c.SignatureScheme, &c.SigningSeed, &timeoutMS, &c.Type, &c.ProxyURL, &c.Disabled)
+	c.Secret = secret.String
+	c.Timeout = time.Duration(timeoutMS) * time.Millisecond
+	return c, err
+}
+
+func (p *Postgres) RecoveryRamp(ctx context.Context, endpointID string) (delivery.RecoveryRamp, error) {
+	var (
+		r           delivery.RecoveryRamp
+		recoveredAt sql.NullTime
+		stepSeconds int
+	)
+	err := p.pool.QueryRow(ctx, `
+		SELECT e.recovered_at, e.recovery_ramp_percents, e.recovery_ramp_step_seconds,
+		       COALESCE(e.health_check_enabled AND h.status = 'unhealthy', false)
+		FROM harborhook.endpoints e
+		LEFT JOIN harborhook.endpoint_health h ON h.endpoint_id = e.id
+		WHERE e.id = $1`, endpointID).Scan(&recoveredAt, &r.Percents, &stepSeconds, &r.Unhealthy)
+	if err != nil {
+		return delivery.RecoveryRamp{}, err
+	}
+	r.RecoveredAt = recoveredAt.Time
+	r.Step = time.Duration(stepSeconds) * time.Second
+	return r, nil
+}
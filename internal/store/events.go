@@ -0,0 +1,136 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"github.com/austindbirch/harbor_hook/internal/tracing"
+)
+
+// EventStore stores published and scheduled events
+type EventStore interface {
+	// InsertEvent stores an event to be fanned out now. An event already stored under the same
+	// idempotency key is returned instead; duplicate reports that it was already fanned out or
+	// is scheduled, so it must not be fanned out again.
+	InsertEvent(ctx context.Context, e NewEvent) (id string, duplicate bool, err error)
+	// ScheduleEvent stores an event to be fanned out at publishAt. An event already stored under
+	// the same idempotency key is returned instead as a duplicate, with scheduled reporting
+	// whether it still is.
+	ScheduleEvent(ctx context.Context, e NewEvent, publishAt time.Time) (id string, scheduled, duplicate bool, err error)
+	// EventFanout counts the deliveries an event was fanned out to, leaving out replays, and
+	// reports whether it is still scheduled
+	EventFanout(ctx context.Context, eventID string) (fanout int32, scheduled bool, err error)
+}
+
+// NewEvent is an event to store
+type NewEvent struct {
+	TenantID       string
+	EventType      string
+	PayloadJSON    []byte
+	IdempotencyKey string     // "" always stores a new event
+	DeliverBy      *time.Time // nil without a deadline
+	Priority       string     // "" is normal
+}
+
+func (p *Postgres) InsertEvent(ctx context.Context, e NewEvent) (string, bool, error) {
+	var eventID string
+	if e.IdempotencyKey == "" {
+		tracing.AddSpanEvent(ctx, "db.insert_event_new")
+		if err := p.pool.QueryRow(ctx, `
+			INSERT INTO harborhook.events(tenant_id, event_type, payload, deliver_by, priority)
+			VALUES ($1, $2, $3::jsonb, $4, COALESCE(NULLIF($5, ''), 'normal'))
+			RETURNING id`,
+			e.TenantID, e.EventType, string(e.PayloadJSON), e.DeliverBy, e.Priority,
+		).Scan(&eventID); err != nil {
+			return "", false, fmt.Errorf("insert events (no-idem): %w", err)
+		}
+		return eventID, false, nil
+	}
+
+	// 1) Insert-or-ignore (no RETURNING here)
+	tracing.AddSpanEvent(ctx, "db.insert_event_idempotent")
+	ct, err := p.pool.Exec(ctx, `
+		INSERT INTO harborhook.events(tenant_id, event_type, payload, idempotency_key, deliver_by, priority)
+		VALUES ($1, $2, $3::jsonb, $4, $5, COALESCE(NULLIF($6, ''), 'normal'))
+		ON CONFLICT ON CONSTRAINT uq_events_tenant_idem DO NOTHING`,
+		e.TenantID, e.EventType, string(e.PayloadJSON), e.IdempotencyKey, e.DeliverBy, e.Priority,
+	)
+	if err != nil {
+		return "", false, fmt.Errorf("insert events (idempotent): %w", err)
+	}
+
+	// 2) Fetch the event id whether inserted now or already existed
+	tracing.AddSpanEvent(ctx, "db.select_event_id")
+	if err := p.pool.QueryRow(ctx, `
+		SELECT id FROM harborhook.events
+		WHERE tenant_id = $1 AND idempotency_key = $2
+		LIMIT 1`,
+		e.TenantID, e.IdempotencyKey,
+	).Scan(&eventID); err != nil {
+		return "", false, fmt.Errorf("select event id (idempotent): %w", err)
+	}
+	if ct.RowsAffected() != 0 {
+		return eventID, false, nil
+	}
+
+	// 3) Not inserted now: it is a duplicate if deliveries already exist or it is scheduled.
+	//    Otherwise an earlier publish failed before fanning out, and this one finishes it.
+	tracing.AddSpanEvent(ctx, "db.check_duplicate_deliveries")
+	var duplicate bool
+	if err := p.pool.QueryRow(ctx, `
+		SELECT ev.status = 'scheduled' OR EXISTS (SELECT 1 FROM harborhook.deliveries d WHERE d.event_id = ev.id)
+		FROM harborhook.events ev WHERE ev.id = $1`,
+		eventID,
+	).Scan(&duplicate); err != nil {
+		return "", false, fmt.Errorf("count existing deliveries: %w", err)
+	}
+	return eventID, duplicate, nil
+}
+
+func (p *Postgres) ScheduleEvent(ctx context.Context, e NewEvent, publishAt time.Time) (string, bool, bool, error) {
+	var eventID string
+	err := p.pool.QueryRow(ctx, `
+		INSERT INTO harborhook.events(tenant_id, event_type, payload, idempotency_key, deliver_by, status, publish_at, priority)
+		VALUES ($1, $2, $3::jsonb, NULLIF($4, ''), $5, 'scheduled', $6, COALESCE(NULLIF($7, ''), 'normal'))
+		ON CONFLICT ON CONSTRAINT uq_events_tenant_idem DO NOTHING
+		RETURNING id`,
+		e.TenantID, e.EventType, string(e.PayloadJSON), e.IdempotencyKey, e.DeliverBy, publishAt, e.Priority,
+	).Scan(&eventID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		var status string
+		if err := p.pool.QueryRow(ctx, `
+			SELECT id, status FROM harborhook.events
+			WHERE tenant_id = $1 AND idempotency_key = $2`,
+			e.TenantID, e.IdempotencyKey,
+		).Scan(&eventID, &status); err != nil {
+			return "", false, false, fmt.Errorf("select event id (idempotent): %w", err)
+		}
+		tracing.AddSpanEvent(ctx, "duplicate_event_detected")
+		return eventID, status == "scheduled", true, nil
+	}
+	if err != nil {
+		return "", false, false, fmt.Errorf("insert scheduled event: %w", err)
+	}
+	return eventID, true, false, nil
+}
+
+func (p *Postgres) EventFanout(ctx context.Context, eventID string) (int32, bool, error) {
+	var (
+		fanout    int32
+		scheduled bool
+	)
+	if err := p.pool.QueryRow(ctx, `
+		SELECT (SELECT count(*)::int FROM harborhook.deliveries d WHERE d.event_id = ev.id AND d.replay_of IS NULL),
+		       ev.status = 'scheduled'
+		FROM harborhook.events ev
+		WHERE ev.id = $1`,
+		eventID,
+	).Scan(&fanout, &scheduled); err != nil {
+		return 0, false, fmt.Errorf("count event deliveries: %w", err)
+	}
+	return fanout, scheduled, nil
+}
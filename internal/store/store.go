@@ -0,0 +1,31 @@
+// Package store holds the SQL for harborhook's core tables behind typed repositories: events,
+// deliveries, endpoints and the DLQ. Business logic depends on the small interfaces, so tests
+// can swap in fakes, and the statements live in one place.
+//
+// Postgres implements every repository on a db.Pool. A pgx.Tx is a db.Pool too, so New(tx)
+// runs the same statements inside a transaction.
+package store
+
+import (
+	"github.com/austindbirch/harbor_hook/internal/db"
+)
+
+// Store is every repository at once, as Postgres provides them
+type Store interface {
+	EventStore
+	DeliveryStore
+	EndpointStore
+	DLQStore
+}
+
+// Postgres is the Store backed by harborhook's Postgres schema
+type Postgres struct {
+	pool db.Pool
+}
+
+var _ Store = (*Postgres)(nil)
+
+// New returns the repositories backed by pool
+func New(pool db.Pool) *Postgres {
+	return &Postgres{pool: pool}
+}
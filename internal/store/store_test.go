@@ -0,0 +1,171 @@
+package store
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/austindbirch/harbor_hook/internal/db/dbfake"
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+)
+
+func TestPostgres_InsertEvent(t *testing.T) {
+	tests := []struct {
+		name          string
+		idemKey       string
+		inserted      bool
+		fannedOut     bool
+		wantDuplicate bool
+	}{
+		{name: "without an idempotency key"},
+		{name: "new idempotency key", idemKey: "k1", inserted: true},
+		{name: "repeated key, already fanned out", idemKey: "k1", fannedOut: true, wantDuplicate: true},
+		{name: "repeated key, fan-out never happened", idemKey: "k1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var checked bool
+			pool := &dbfake.Pool{
+				ExecFunc: func(sql string, args []any) (pgconn.CommandTag, error) {
+					if tt.inserted {
+						return pgconn.NewCommandTag("INSERT 0 1"), nil
+					}
+					return pgconn.NewCommandTag("INSERT 0 0"), nil
+				},
+				QueryRowFunc: func(sql string, args []any) pgx.Row {
+					switch {
+					case strings.Contains(sql, "RETURNING id"), strings.Contains(sql, "SELECT id FROM"):
+						return dbfake.Row{Values: []any{"evt_1"}}
+					case strings.Contains(sql, "EXISTS"):
+						checked = true
+						return dbfake.Row{Values: []any{tt.fannedOut}}
+					}
+					t.Fatalf("unexpected query: %s", sql)
+					return nil
+				},
+			}
+
+			id, duplicate, err := New(pool).InsertEvent(context.Background(), NewEvent{
+				TenantID: "tn_1", EventType: "order.created", PayloadJSON: []byte(`{}`), IdempotencyKey: tt.idemKey,
+			})
+			if err != nil {
+				t.Fatalf("InsertEvent() unexpected error: %v", err)
+			}
+			if id != "evt_1" || duplicate != tt.wantDuplicate {
+				t.Errorf("InsertEvent() = %q, duplicate %v, want evt_1, %v", id, duplicate, tt.wantDuplicate)
+			}
+			if wantCheck := tt.idemKey != "" && !tt.inserted; checked != wantCheck {
+				t.Errorf("checked for deliveries = %v, want %v", checked, wantCheck)
+			}
+		})
+	}
+}
+
+func TestPostgres_EndpointConfig(t *testing.T) {
+	pool := &dbfake.Pool{QueryRowFunc: func(string, []any) pgx.Row {
+		return dbfake.Row{Values: []any{
+			nil, true, 30, 3, []int{1, 5}, []string{"http_5xx"}, true,
+			"", "", "tenant-cert", "gzip", "v2", nil, 2500, "capture",
+			"direct", true,
+		}}
+	}}
+
+	c, err := New(pool).EndpointConfig(context.Background(), "ep_1")
+	if err != nil {
+		t.Fatalf("EndpointConfig() unexpected error: %v", err)
+	}
+	if c.Secret != "" || !c.RecordRequests || c.RetentionDays != 30 || c.RetryMaxAttempts != 3 ||
+		c.ClientCertSecret != "tenant-cert" || c.Compression != "gzip" || c.SignatureScheme != "v2" || c.SigningSeed != nil || c.Timeout != 2500*time.Millisecond ||
+		c.Type != "capture" || c.ProxyURL != "direct" || !c.Disabled {
+		t.Errorf("EndpointConfig() = %+v", c)
+	}
+}
+
+func TestPostgres_MarkFailed_RecordsAttempt(t *testing.T) {
+	var stmt string
+	var args []any
+	pool := &dbfake.Pool{ExecFunc: func(sql string, a []any) (pgconn.CommandTag, error) {
+		stmt, args = sql, a
+		return pgconn.CommandTag{}, nil
+	}}
+
+	body := delivery.ResponseBody{Text: `{"error":"try later"}`}
+	if err := New(pool).MarkFailed(context.Background(), "del_1", 503, 120*time.Millisecond, "HTTP 503", body); err != nil {
+		t.Fatalf("MarkFailed() unexpected error: %v", err)
+	}
+	// One statement updates the delivery and appends the attempt, so the history can't miss a try
+	if !strings.Contains(stmt, "attempt=attempt+1") || !strings.Contains(stmt, "INSERT INTO harborhook.delivery_attempts") {
+		t.Errorf("MarkFailed() ran %q, want the update and the history insert together", stmt)
+	}
+	if len(args) != 6 || args[0] != 503 || args[1] != 120 || args[3] != "del_1" || args[4] != body.Text {
+		t.Errorf("MarkFailed() args = %v", args)
+	}
+}
+
+func TestPostgres_ListDLQ(t *testing.T) {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	row := func(id string) []any {
+		return []any{id, "evt_1", "ep_1", nil, "dead", int32(500), "http_5xx",
+			created, nil, nil, nil, nil, created,
+			"q_" + id, created}
+	}
+	var listSQL string
+	var listArgs []any
+	pool := &dbfake.Pool{
+		QueryRowFunc: func(string, []any) pgx.Row { return dbfake.Row{Values: []any{int32(3)}} },
+		QueryFunc: func(sql string, args []any) (pgx.Rows, error) {
+			listSQL, listArgs = sql, args
+			return dbfake.NewRows(row("del_1"), row("del_2"), row("del_3")), nil
+		},
+	}
+
+	from := created.Add(-time.Hour)
+	page, err := New(pool).ListDLQ(context.Background(), DLQFilter{
+		TenantID: "tn_1", From: &from, Limit: 2,
+		After: &DLQCursor{CreatedAt: created.Add(time.Minute), ID: "q_0"},
+	})
+	if err != nil {
+		t.Fatalf("ListDLQ() unexpected error: %v", err)
+	}
+	if page.Total != 3 || len(page.Dead) != 2 || page.Dead[1].ID != "del_2" || page.Dead[0].HTTPStatus != 500 || page.Dead[0].ReplayOf != "" {
+		t.Errorf("ListDLQ() = %+v, want the first two of 3", page)
+	}
+	if page.Next == nil || page.Next.ID != "q_del_2" || !page.Next.CreatedAt.Equal(created) {
+		t.Errorf("next cursor = %+v, want after q_del_2", page.Next)
+	}
+	if !strings.Contains(listSQL, "LIMIT 3") || !strings.Contains(listSQL, "(q.created_at, q.id) < ($3, $4)") || len(listArgs) != 4 {
+		t.Errorf("page query %q with %v, want tenant, from and cursor bound and one extra row", listSQL, listArgs)
+	}
+
+	// The last page has no cursor
+	pool.QueryFunc = func(string, []any) (pgx.Rows, error) { return dbfake.NewRows(row("del_3")), nil }
+	if page, _ := New(pool).ListDLQ(context.Background(), DLQFilter{Limit: 2}); page.Next != nil || len(page.Dead) != 1 {
+		t.Errorf("ListDLQ(last page) = %+v, want one row and no cursor", page)
+	}
+}
+
+func TestPostgres_PurgeExpiredDLQ(t *testing.T) {
+	var purgeArgs []any
+	pool := &dbfake.Pool{QueryRowFunc: func(sql string, args []any) pgx.Row {
+		if !strings.Contains(sql, "harborhook.dlq_archive") || !strings.Contains(sql, "tenant_dlq_retention") {
+			t.Errorf("purge query %q, want tenant retention applied and entries archived", sql)
+		}
+		purgeArgs = args
+		return dbfake.Row{Values: []any{int64(7), int64(3)}}
+	}}
+
+	got, err := New(pool).PurgeExpiredDLQ(context.Background(), DLQRetention{MaxAge: 48 * time.Hour, MaxEntries: 100, Archive: true}, 500)
+	if err != nil {
+		t.Fatalf("PurgeExpiredDLQ() unexpected error: %v", err)
+	}
+	if got != (DLQPurge{Purged: 7, Archived: 3}) {
+		t.Errorf("PurgeExpiredDLQ() = %+v, want 7 purged and 3 archived", got)
+	}
+	if len(purgeArgs) != 4 || purgeArgs[0] != int64(172800) || purgeArgs[1] != 100 || purgeArgs[2] != true || purgeArgs[3] != 500 {
+		t.Errorf("purge args = %v, want the defaults in seconds, entries, archive and the batch limit", purgeArgs)
+	}
+}
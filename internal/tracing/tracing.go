@@ -2,8 +2,15 @@ package tracing
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
 	"os"
+	"strconv"
 	"strings"
+	"sync/atomic"
+
+	"github.com/austindbirch/harbor_hook/internal/version"
 
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -19,13 +26,65 @@ import (
 // TracerName is the instrumentation name for this application
 const TracerName = "github.com/austindbirch/harbor_hook"
 
-// InitTracing initializes OpenTelemetry tracing for the service
+// ErrorKey marks spans of failed operations, so a tail-sampling collector can keep every trace
+// with a failure whatever the head sampler decided for the rest
+const ErrorKey = attribute.Key("error")
+
+// AlwaysSampleKey, set true among a span's start attributes, samples the span regardless of the
+// configured ratio or its parent's decision
+const AlwaysSampleKey = attribute.Key("harborhook.sample.always")
+
+// redact masks secrets in string attribute values and error messages; see SetRedactor
+var redact atomic.Pointer[func(key, value string) string]
+
+// SetRedactor has fn mask the string attributes and errors recorded through this package, given
+// the attribute key ("error" for errors) and value. internal/logging installs its redactor.
+func SetRedactor(fn func(key, value string) string) {
+	redact.Store(&fn)
+}
+
+// redactAttrs returns attrs with their string values redacted
+func redactAttrs(attrs []attribute.KeyValue) []attribute.KeyValue {
+	fn := redact.Load()
+	if fn == nil {
+		return attrs
+	}
+	out := make([]attribute.KeyValue, len(attrs))
+	for i, kv := range attrs {
+		if kv.Value.Type() == attribute.STRING {
+			kv = attribute.String(string(kv.Key), (*fn)(string(kv.Key), kv.Value.AsString()))
+		}
+		out[i] = kv
+	}
+	return out
+}
+
+// redactError returns err with its message redacted
+func redactError(err error) error {
+	fn := redact.Load()
+	if fn == nil {
+		return err
+	}
+	if msg := (*fn)(string(ErrorKey), err.Error()); msg != err.Error() {
+		return errors.New(msg)
+	}
+	return err
+}
+
+// InitTracing initializes OpenTelemetry tracing for the service. The sampler is configured from
+// the environment; see NewSampler.
 func InitTracing(ctx context.Context, serviceName string) (func(), error) {
+	sampler, err := NewSampler(serviceName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create resource with service information
 	res, err := resource.New(ctx,
 		resource.WithAttributes(
 			semconv.ServiceNameKey.String(serviceName),
 			semconv.ServiceVersionKey.String(getVersion()),
+			attribute.String("service.commit", version.GitCommit),
 			attribute.String("service.instance.id", getInstanceID()),
 		),
 		resource.WithFromEnv(),
@@ -50,7 +109,7 @@ func InitTracing(ctx context.Context, serviceName string) (func(), error) {
 	tp := trace.NewTracerProvider(
 		trace.WithBatcher(exporter),
 		trace.WithResource(res),
-		trace.WithSampler(trace.AlwaysSample()), // Sample all traces for development
+		trace.WithSampler(sampler),
 	)
 
 	// Set global trace provider and propagator
@@ -73,33 +132,117 @@ func GetTracer() oteltrace.Tracer {
 
 // StartSpan starts a new span with the given name and attributes
 func StartSpan(ctx context.Context, spanName string, attrs ...attribute.KeyValue) (context.Context, oteltrace.Span) {
-	tracer := GetTracer()
-	ctx, span := tracer.Start(ctx, spanName)
-
-	if len(attrs) > 0 {
-		span.SetAttributes(attrs...)
-	}
-
-	return ctx, span
+	// Attributes are passed at start so the sampler sees them
+	return GetTracer().Start(ctx, spanName, oteltrace.WithAttributes(redactAttrs(attrs)...))
 }
 
 // AddSpanEvent adds an event to the current span
 func AddSpanEvent(ctx context.Context, name string, attrs ...attribute.KeyValue) {
 	span := oteltrace.SpanFromContext(ctx)
 	if span != nil {
-		span.AddEvent(name, oteltrace.WithAttributes(attrs...))
+		span.AddEvent(name, oteltrace.WithAttributes(redactAttrs(attrs)...))
 	}
 }
 
+// SetSpanAttributes sets attributes on the current span, redacting their string values
+func SetSpanAttributes(ctx context.Context, attrs ...attribute.KeyValue) {
+	oteltrace.SpanFromContext(ctx).SetAttributes(redactAttrs(attrs)...)
+}
+
 // SetSpanError records an error on the current span
 func SetSpanError(ctx context.Context, err error) {
 	span := oteltrace.SpanFromContext(ctx)
 	if span != nil && err != nil {
+		err = redactError(err)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, err.Error())
+		span.SetAttributes(ErrorKey.Bool(true))
 	}
 }
 
+// NewSampler builds the sampler for serviceName from the environment:
+//
+//   - OTEL_TRACES_SAMPLER: always_on, always_off, traceidratio, parentbased_always_on (the
+//     default), parentbased_always_off or parentbased_traceidratio
+//   - OTEL_TRACES_SAMPLER_ARG: the ratio for the traceidratio samplers, 0 to 1 (default 1)
+//   - TRACE_SAMPLE_RATIOS: per-service ratios overriding OTEL_TRACES_SAMPLER_ARG, as
+//     service=ratio pairs, e.g. "harborhook-worker=0.1,harborhook-ingest=0.5"
+//
+// Whatever the configuration, spans started with AlwaysSampleKey set are sampled.
+func NewSampler(serviceName string) (trace.Sampler, error) {
+	ratio := 1.0
+	if arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); arg != "" {
+		r, err := parseRatio(arg)
+		if err != nil {
+			return nil, fmt.Errorf("OTEL_TRACES_SAMPLER_ARG: %w", err)
+		}
+		ratio = r
+	}
+	if perService := os.Getenv("TRACE_SAMPLE_RATIOS"); perService != "" {
+		for _, pair := range strings.Split(perService, ",") {
+			name, arg, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				return nil, fmt.Errorf("TRACE_SAMPLE_RATIOS: %q is not service=ratio", pair)
+			}
+			r, err := parseRatio(arg)
+			if err != nil {
+				return nil, fmt.Errorf("TRACE_SAMPLE_RATIOS: %s: %w", name, err)
+			}
+			if strings.TrimSpace(name) == serviceName {
+				ratio = r
+			}
+		}
+	}
+
+	var base trace.Sampler
+	switch name := os.Getenv("OTEL_TRACES_SAMPLER"); name {
+	case "always_on":
+		base = trace.AlwaysSample()
+	case "always_off":
+		base = trace.NeverSample()
+	case "traceidratio":
+		base = trace.TraceIDRatioBased(ratio)
+	case "", "parentbased_always_on":
+		base = trace.ParentBased(trace.AlwaysSample())
+	case "parentbased_always_off":
+		base = trace.ParentBased(trace.NeverSample())
+	case "parentbased_traceidratio":
+		base = trace.ParentBased(trace.TraceIDRatioBased(ratio))
+	default:
+		return nil, fmt.Errorf("unknown OTEL_TRACES_SAMPLER %q", name)
+	}
+	return alwaysSampler{base: base}, nil
+}
+
+func parseRatio(s string) (float64, error) {
+	r, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil || r < 0 || r > 1 {
+		return 0, fmt.Errorf("ratio %q must be between 0 and 1", s)
+	}
+	return r, nil
+}
+
+// alwaysSampler samples spans started with AlwaysSampleKey set and defers to base for the rest
+type alwaysSampler struct {
+	base trace.Sampler
+}
+
+func (s alwaysSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	for _, kv := range p.Attributes {
+		if kv.Key == AlwaysSampleKey && kv.Value.AsBool() {
+			return trace.SamplingResult{
+				Decision:   trace.RecordAndSample,
+				Tracestate: oteltrace.SpanContextFromContext(p.ParentContext).TraceState(),
+			}
+		}
+	}
+	return s.base.ShouldSample(p)
+}
+
+func (s alwaysSampler) Description() string {
+	return fmt.Sprintf("AlwaysSampleOn{%s,%s}", AlwaysSampleKey, s.base.Description())
+}
+
 // GetTraceID extracts the trace ID from the context
 func GetTraceID(ctx context.Context) string {
 	span := oteltrace.SpanFromContext(ctx)
@@ -109,12 +252,12 @@ func GetTraceID(ctx context.Context) string {
 	return ""
 }
 
-// getVersion returns the service version from environment or default
+// getVersion returns the service version from environment, falling back to the stamped build version
 func getVersion() string {
 	if v := os.Getenv("SERVICE_VERSION"); v != "" {
 		return v
 	}
-	return "dev"
+	return version.Version
 }
 
 // getInstanceID returns a unique instance identifier
@@ -144,6 +287,16 @@ func getOTLPEndpoint() string {
 	return "tempo:4318"
 }
 
+// Ping checks the OTLP exporter's collector accepts connections
+func Ping(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", getOTLPEndpoint())
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
 // PropagateTraceToNSQ extracts trace context and returns it as a map for NSQ message headers
 func PropagateTraceToNSQ(ctx context.Context) map[string]string {
 	headers := make(map[string]string)
@@ -2,6 +2,7 @@ package tracing
 
 import (
 	"context"
+	"errors"
 	"os"
 	"strings"
 	"testing"
@@ -552,4 +553,107 @@ func TestTracerNameConstant(t *testing.T) {
 	if TracerName != expected {
 		t.Errorf("TracerName constant = %q, want %q", TracerName, expected)
 	}
-}
\ No newline at end of file
+}
+func TestNewSampler(t *testing.T) {
+	tests := []struct {
+		name        string
+		sampler     string
+		arg         string
+		perService  string
+		wantErr     bool
+		wantSampled bool
+	}{
+		{name: "default samples everything", wantSampled: true},
+		{name: "always off", sampler: "always_off", wantSampled: false},
+		{name: "zero ratio", sampler: "parentbased_traceidratio", arg: "0", wantSampled: false},
+		{name: "per-service ratio overrides arg", sampler: "traceidratio", arg: "0", perService: "other=0,harborhook-worker=1", wantSampled: true},
+		{name: "unknown sampler", sampler: "sometimes", wantErr: true},
+		{name: "ratio out of range", sampler: "traceidratio", arg: "1.5", wantErr: true},
+		{name: "malformed per-service ratio", perService: "harborhook-worker", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_TRACES_SAMPLER", tt.sampler)
+			t.Setenv("OTEL_TRACES_SAMPLER_ARG", tt.arg)
+			t.Setenv("TRACE_SAMPLE_RATIOS", tt.perService)
+
+			sampler, err := NewSampler("harborhook-worker")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewSampler() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			tp := trace.NewTracerProvider(trace.WithSampler(sampler))
+			_, span := tp.Tracer("test").Start(context.Background(), "test-span")
+			defer span.End()
+			if got := span.SpanContext().IsSampled(); got != tt.wantSampled {
+				t.Errorf("sampled = %v, want %v", got, tt.wantSampled)
+			}
+		})
+	}
+}
+
+func TestNewSampler_AlwaysSample(t *testing.T) {
+	t.Setenv("OTEL_TRACES_SAMPLER", "always_off")
+	sampler, err := NewSampler("harborhook-worker")
+	if err != nil {
+		t.Fatalf("NewSampler() unexpected error: %v", err)
+	}
+
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(trace.NewTracerProvider(trace.WithSyncer(exporter), trace.WithSampler(sampler)))
+
+	_, dropped := StartSpan(context.Background(), "first-attempt", AlwaysSampleKey.Bool(false))
+	dropped.End()
+	ctx, kept := StartSpan(context.Background(), "retry", AlwaysSampleKey.Bool(true))
+	SetSpanError(ctx, context.DeadlineExceeded)
+	kept.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "retry" {
+		t.Fatalf("exported spans = %v, want only the retry", spans)
+	}
+	var hasError bool
+	for _, kv := range spans[0].Attributes {
+		if kv.Key == ErrorKey && kv.Value.AsBool() {
+			hasError = true
+		}
+	}
+	if !hasError {
+		t.Errorf("span attributes = %v, want error=true", spans[0].Attributes)
+	}
+}
+
+func TestSetRedactor(t *testing.T) {
+	SetRedactor(func(key, value string) string {
+		if key == "secret" {
+			return "[REDACTED]"
+		}
+		return strings.ReplaceAll(value, "t0k", "[REDACTED]")
+	})
+	t.Cleanup(func() { redact.Store(nil) })
+
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(trace.NewTracerProvider(trace.WithSyncer(exporter)))
+
+	ctx, span := StartSpan(context.Background(), "delivery", attribute.String("secret", "whsec_1"), attribute.Int("attempt", 2))
+	SetSpanAttributes(ctx, attribute.String("http.error", "GET /?token=t0k: EOF"))
+	SetSpanError(ctx, errors.New("dial https://hooks.example.com/?token=t0k"))
+	span.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("exported %d spans, want 1", len(spans))
+	}
+	for _, kv := range spans[0].Attributes {
+		if strings.Contains(kv.Value.Emit(), "whsec_1") || strings.Contains(kv.Value.Emit(), "t0k") {
+			t.Errorf("attribute %s = %q leaks a secret", kv.Key, kv.Value.Emit())
+		}
+	}
+	if desc := spans[0].Status.Description; strings.Contains(desc, "t0k") {
+		t.Errorf("status %q leaks a secret", desc)
+	}
+}
@@ -0,0 +1,75 @@
+package validation
+
+import (
+	"fmt"
+
+	validatepb "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// supportedRules are the rules Validate enforces, by rules message
+var supportedRules = map[protoreflect.FullName]map[protoreflect.Name]bool{
+	"buf.validate.FieldRules": {
+		"required": true, "ignore": true, "string": true, "int32": true, "int64": true,
+		"enum": true, "repeated": true, "timestamp": true,
+	},
+	"buf.validate.StringRules":    {"min_len": true, "max_len": true, "pattern": true, "uuid": true, "uri": true, "email": true},
+	"buf.validate.Int32Rules":     {"gt": true, "gte": true, "lt": true, "lte": true},
+	"buf.validate.Int64Rules":     {"gt": true, "gte": true, "lt": true, "lte": true},
+	"buf.validate.EnumRules":      {"defined_only": true},
+	"buf.validate.RepeatedRules":  {"min_items": true, "max_items": true, "unique": true, "items": true},
+	"buf.validate.TimestampRules": {"gt": true, "gte": true, "lt": true, "lte": true},
+}
+
+// Unsupported lists the rules declared on md, and the messages it holds, that Validate doesn't
+// enforce, as "field: rule". Message and oneof rules aren't enforced at all.
+func Unsupported(md protoreflect.MessageDescriptor) []string {
+	var out []string
+	unsupported(md, map[protoreflect.FullName]bool{}, &out)
+	return out
+}
+
+func unsupported(md protoreflect.MessageDescriptor, seen map[protoreflect.FullName]bool, out *[]string) {
+	if seen[md.FullName()] {
+		return
+	}
+	seen[md.FullName()] = true
+	if proto.HasExtension(md.Options(), validatepb.E_Message) {
+		*out = append(*out, fmt.Sprintf("%s: message rules", md.FullName()))
+	}
+	for i := 0; i < md.Oneofs().Len(); i++ {
+		if od := md.Oneofs().Get(i); proto.HasExtension(od.Options(), validatepb.E_Oneof) {
+			*out = append(*out, fmt.Sprintf("%s: oneof rules", od.FullName()))
+		}
+	}
+	for i := 0; i < md.Fields().Len(); i++ {
+		fd := md.Fields().Get(i)
+		if rules := fieldRules(fd); rules != nil {
+			unsupportedRules(rules.ProtoReflect(), string(fd.FullName()), out)
+			if p := rules.GetString().GetPattern(); p != "" {
+				if _, err := pattern(p); err != nil {
+					*out = append(*out, fmt.Sprintf("%s: invalid pattern: %v", fd.FullName(), err))
+				}
+			}
+		}
+		if nested(fd) {
+			unsupported(fd.Message(), seen, out)
+		}
+	}
+}
+
+// unsupportedRules walks the set fields of a rules message, descending into nested rules
+func unsupportedRules(rules protoreflect.Message, field string, out *[]string) {
+	allowed := supportedRules[rules.Descriptor().FullName()]
+	rules.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		if !allowed[fd.Name()] {
+			*out = append(*out, fmt.Sprintf("%s: %s.%s", field, rules.Descriptor().Name(), fd.Name()))
+			return true
+		}
+		if fd.Message() != nil && supportedRules[fd.Message().FullName()] != nil {
+			unsupportedRules(v.Message(), field, out)
+		}
+		return true
+	})
+}
@@ -0,0 +1,283 @@
+// Package validation enforces the buf.validate rules declared on the API's request messages,
+// so handlers receive requests whose fields are present, well formed and in bounds. It
+// implements the rules the API uses without CEL; Unsupported reports any other rule so one
+// added to the proto isn't silently skipped.
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+
+	validatepb "buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/austindbirch/harbor_hook/internal/apierr"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// patterns caches compiled string.pattern rules by their source
+var patterns sync.Map
+
+// Validate checks msg, and the messages it holds, against their declared rules. It returns an
+// InvalidArgument error whose BadRequest detail lists every violation, or nil.
+func Validate(msg proto.Message) error {
+	var violations []*errdetails.BadRequest_FieldViolation
+	checkMessage(msg.ProtoReflect(), "", &violations)
+	if len(violations) == 0 {
+		return nil
+	}
+	return apierr.InvalidFields(violations)
+}
+
+// checkMessage checks the fields of m, naming them under prefix (e.g. "quota.")
+func checkMessage(m protoreflect.Message, prefix string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	fields := m.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		path := prefix + string(fd.Name())
+		if rules := fieldRules(fd); rules != nil && !checkField(m, fd, rules, path, violations) {
+			continue
+		}
+		if !nested(fd) || !m.Has(fd) {
+			continue
+		}
+		if fd.IsList() {
+			// Ignoring the items leaves them to the handler, e.g. to reject one batch entry alone
+			if rules := fieldRules(fd); rules.GetRepeated().GetItems().GetIgnore() == validatepb.Ignore_IGNORE_ALWAYS {
+				continue
+			}
+			list := m.Get(fd).List()
+			for j := 0; j < list.Len(); j++ {
+				checkMessage(list.Get(j).Message(), fmt.Sprintf("%s[%d].", path, j), violations)
+			}
+			continue
+		}
+		checkMessage(m.Get(fd).Message(), path+".", violations)
+	}
+}
+
+// nested reports whether fd holds API messages whose own rules apply. Well-known types such as
+// Timestamp and Struct carry none.
+func nested(fd protoreflect.FieldDescriptor) bool {
+	return fd.Message() != nil && !fd.IsMap() && fd.Message().ParentFile().Package() != "google.protobuf"
+}
+
+// fieldRules returns the rules declared on fd, or nil
+func fieldRules(fd protoreflect.FieldDescriptor) *validatepb.FieldRules {
+	if !proto.HasExtension(fd.Options(), validatepb.E_Field) {
+		return nil
+	}
+	rules, _ := proto.GetExtension(fd.Options(), validatepb.E_Field).(*validatepb.FieldRules)
+	return rules
+}
+
+// checkField applies rules to fd of m, reporting whether the field is present and worth
+// checking further
+func checkField(m protoreflect.Message, fd protoreflect.FieldDescriptor, rules *validatepb.FieldRules, path string, violations *[]*errdetails.BadRequest_FieldViolation) bool {
+	if rules.GetIgnore() == validatepb.Ignore_IGNORE_ALWAYS {
+		return true
+	}
+	has := m.Has(fd)
+	if !has {
+		if rules.GetRequired() {
+			addViolation(violations, path, "is required")
+		}
+		// Rules on an absent message don't apply; a zero scalar is checked unless ignored
+		if rules.GetRequired() || (fd.Message() != nil && !fd.IsList()) || rules.GetIgnore() == validatepb.Ignore_IGNORE_IF_ZERO_VALUE {
+			return false
+		}
+	}
+	if !fd.IsList() {
+		checkValue(fd, rules, m.Get(fd), path, violations)
+		return true
+	}
+
+	list := m.Get(fd).List()
+	repeated := rules.GetRepeated()
+	switch {
+	case repeated.HasMinItems() && repeated.HasMaxItems() && (uint64(list.Len()) < repeated.GetMinItems() || uint64(list.Len()) > repeated.GetMaxItems()):
+		addViolation(violations, path, fmt.Sprintf("must contain between %d and %d items", repeated.GetMinItems(), repeated.GetMaxItems()))
+	case repeated.HasMinItems() && uint64(list.Len()) < repeated.GetMinItems():
+		addViolation(violations, path, fmt.Sprintf("must contain at least %d items", repeated.GetMinItems()))
+	case repeated.HasMaxItems() && uint64(list.Len()) > repeated.GetMaxItems():
+		addViolation(violations, path, fmt.Sprintf("must contain at most %d items", repeated.GetMaxItems()))
+	}
+	if repeated.GetUnique() && fd.Message() == nil {
+		seen := make(map[any]bool, list.Len())
+		for j := 0; j < list.Len(); j++ {
+			v := list.Get(j).Interface()
+			if seen[v] {
+				addViolation(violations, path, "must not contain duplicates")
+				break
+			}
+			seen[v] = true
+		}
+	}
+	if items := repeated.GetItems(); items != nil {
+		for j := 0; j < list.Len(); j++ {
+			checkValue(fd, items, list.Get(j), fmt.Sprintf("%s[%d]", path, j), violations)
+		}
+	}
+	return true
+}
+
+// checkValue applies the type-specific rules to one value of fd
+func checkValue(fd protoreflect.FieldDescriptor, rules *validatepb.FieldRules, v protoreflect.Value, path string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	switch {
+	case rules.GetString() != nil:
+		checkString(rules.GetString(), v.String(), path, violations)
+	case rules.GetInt32() != nil:
+		r := rules.GetInt32()
+		checkBounds(int64(v.Int()), bounds{
+			gt: int64(r.GetGt()), hasGt: r.HasGt(), gte: int64(r.GetGte()), hasGte: r.HasGte(),
+			lt: int64(r.GetLt()), hasLt: r.HasLt(), lte: int64(r.GetLte()), hasLte: r.HasLte(),
+		}, path, violations)
+	case rules.GetInt64() != nil:
+		r := rules.GetInt64()
+		checkBounds(v.Int(), bounds{
+			gt: r.GetGt(), hasGt: r.HasGt(), gte: r.GetGte(), hasGte: r.HasGte(),
+			lt: r.GetLt(), hasLt: r.HasLt(), lte: r.GetLte(), hasLte: r.HasLte(),
+		}, path, violations)
+	case rules.GetEnum() != nil:
+		if rules.GetEnum().GetDefinedOnly() && fd.Enum().Values().ByNumber(v.Enum()) == nil {
+			addViolation(violations, path, fmt.Sprintf("has unknown value %d", v.Enum()))
+		}
+	case rules.GetTimestamp() != nil:
+		checkTimestamp(rules.GetTimestamp(), v.Message(), path, violations)
+	}
+}
+
+func checkString(r *validatepb.StringRules, s, path string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	n := uint64(utf8.RuneCountInString(s))
+	if r.HasMinLen() && n < r.GetMinLen() {
+		addViolation(violations, path, fmt.Sprintf("must be at least %d characters", r.GetMinLen()))
+	}
+	if r.HasMaxLen() && n > r.GetMaxLen() {
+		addViolation(violations, path, fmt.Sprintf("must be at most %d characters", r.GetMaxLen()))
+	}
+	if r.HasPattern() {
+		if re, err := pattern(r.GetPattern()); err != nil || !re.MatchString(s) {
+			addViolation(violations, path, fmt.Sprintf("must match %s", r.GetPattern()))
+		}
+	}
+	if r.GetUuid() && !uuidPattern.MatchString(s) {
+		addViolation(violations, path, "must be a UUID")
+	}
+	if r.GetUri() {
+		if u, err := url.Parse(s); err != nil || !u.IsAbs() {
+			addViolation(violations, path, "must be an absolute URI")
+		}
+	}
+	if r.GetEmail() {
+		if a, err := mail.ParseAddress(s); err != nil || a.Address != s {
+			addViolation(violations, path, "must be an email address")
+		}
+	}
+}
+
+// pattern compiles a string.pattern rule once
+func pattern(src string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(src); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(src)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(src, re)
+	return re, nil
+}
+
+// bounds are the limits of an integer rule; has* marks the ones set
+type bounds struct {
+	gt, gte, lt, lte             int64
+	hasGt, hasGte, hasLt, hasLte bool
+}
+
+func checkBounds(v int64, b bounds, path string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	tooLow := (b.hasGt && v <= b.gt) || (b.hasGte && v < b.gte)
+	tooHigh := (b.hasLt && v >= b.lt) || (b.hasLte && v > b.lte)
+	if !tooLow && !tooHigh {
+		return
+	}
+	switch {
+	case b.hasGte && b.hasLte:
+		addViolation(violations, path, fmt.Sprintf("must be between %d and %d", b.gte, b.lte))
+	case tooLow && b.hasGte && b.gte == 0:
+		addViolation(violations, path, "must not be negative")
+	case tooLow && b.hasGte:
+		addViolation(violations, path, fmt.Sprintf("must be at least %d", b.gte))
+	case tooLow:
+		addViolation(violations, path, fmt.Sprintf("must be greater than %d", b.gt))
+	case b.hasLte:
+		addViolation(violations, path, fmt.Sprintf("must be at most %d", b.lte))
+	default:
+		addViolation(violations, path, fmt.Sprintf("must be less than %d", b.lt))
+	}
+}
+
+func checkTimestamp(r *validatepb.TimestampRules, m protoreflect.Message, path string, violations *[]*errdetails.BadRequest_FieldViolation) {
+	ts, ok := m.Interface().(*timestamppb.Timestamp)
+	if !ok {
+		return
+	}
+	t := ts.AsTime()
+	switch {
+	case r.HasGte() && t.Before(r.GetGte().AsTime()):
+		addViolation(violations, path, "must not be before "+r.GetGte().AsTime().Format("2006-01-02T15:04:05Z07:00"))
+	case r.HasGt() && !t.After(r.GetGt().AsTime()):
+		addViolation(violations, path, "must be after "+r.GetGt().AsTime().Format("2006-01-02T15:04:05Z07:00"))
+	case r.HasLte() && t.After(r.GetLte().AsTime()):
+		addViolation(violations, path, "must not be after "+r.GetLte().AsTime().Format("2006-01-02T15:04:05Z07:00"))
+	case r.HasLt() && !t.Before(r.GetLt().AsTime()):
+		addViolation(violations, path, "must be before "+r.GetLt().AsTime().Format("2006-01-02T15:04:05Z07:00"))
+	}
+}
+
+func addViolation(violations *[]*errdetails.BadRequest_FieldViolation, field, description string) {
+	*violations = append(*violations, &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+}
+
+// UnaryServerInterceptor rejects a request that fails validation before it reaches the handler
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if msg, ok := req.(proto.Message); ok {
+			if err := Validate(msg); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor validates each message a streaming handler receives
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: ss})
+	}
+}
+
+// validatingStream validates the messages received on a server stream
+type validatingStream struct {
+	grpc.ServerStream
+}
+
+func (s *validatingStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(proto.Message); ok {
+		return Validate(msg)
+	}
+	return nil
+}
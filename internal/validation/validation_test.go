@@ -0,0 +1,294 @@
+package validation
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+const endpointID = "123e4567-e89b-12d3-a456-426614174000"
+
+func TestValidate(t *testing.T) {
+	payload, _ := structpb.NewStruct(map[string]any{"user_id": "123"})
+
+	tests := []struct {
+		name    string
+		msg     proto.Message
+		wantErr string
+	}{
+		{
+			name:    "missing required fields",
+			msg:     &webhookv1.CreateEndpointRequest{},
+			wantErr: "tenant_id is required",
+		},
+		{
+			name:    "relative url",
+			msg:     &webhookv1.CreateEndpointRequest{TenantId: "tn_1", Url: "not-a-valid-url"},
+			wantErr: "url must be an absolute URI",
+		},
+		{
+			name: "nested item out of range",
+			msg: &webhookv1.CreateEndpointRequest{
+				TenantId:     "tn_1",
+				Url:          "https://example.com/webhook",
+				RecoveryRamp: &webhookv1.RecoveryRamp{Percents: []int32{10, 200}, StepSeconds: 60},
+			},
+			wantErr: "recovery_ramp.percents[1] must be between 1 and 100",
+		},
+		{
+			name: "duplicate items",
+			msg: &webhookv1.CreateEndpointRequest{
+				TenantId:    "tn_1",
+				Url:         "https://example.com/webhook",
+				RetryPolicy: &webhookv1.RetryPolicy{RetryOn: []string{"timeout", "timeout"}},
+			},
+			wantErr: "retry_policy.retry_on must not contain duplicates",
+		},
+		{
+			name:    "unknown enum value",
+			msg:     &webhookv1.CreateEndpointRequest{TenantId: "tn_1", Url: "https://example.com/webhook", SignatureScheme: 99},
+			wantErr: "signature_scheme has unknown value 99",
+		},
+		{
+			name: "valid endpoint",
+			msg: &webhookv1.CreateEndpointRequest{
+				TenantId:     "tn_1",
+				Url:          "https://example.com/webhook",
+				RecoveryRamp: &webhookv1.RecoveryRamp{Percents: []int32{10, 50}, StepSeconds: 60},
+			},
+		},
+		{
+			name:    "event type pattern",
+			msg:     &webhookv1.PublishEventRequest{TenantId: "tn_1", EventType: "user created", Payload: payload},
+			wantErr: "event_type must match ^[A-Za-z0-9][A-Za-z0-9._:-]*$",
+		},
+		{
+			name:    "missing payload",
+			msg:     &webhookv1.PublishEventRequest{TenantId: "tn_1", EventType: "user.created"},
+			wantErr: "payload is required",
+		},
+		{
+			name:    "empty batch",
+			msg:     &webhookv1.PublishEventsRequest{TenantId: "tn_1"},
+			wantErr: "events must contain between 1 and 500 items",
+		},
+		{
+			name: "batch events left to the handler",
+			msg:  &webhookv1.PublishEventsRequest{TenantId: "tn_1", Events: []*webhookv1.BatchEvent{{}}},
+		},
+		{
+			name:    "missing message",
+			msg:     &webhookv1.SetTenantQuotaRequest{},
+			wantErr: "quota is required",
+		},
+		{
+			name:    "nested message",
+			msg:     &webhookv1.SetTenantQuotaRequest{Quota: &webhookv1.TenantQuota{EventsPerMinute: -1}},
+			wantErr: "quota.tenant_id is required; quota.events_per_minute must not be negative",
+		},
+		{
+			name:    "retention out of range",
+			msg:     &webhookv1.SetComplianceModeRequest{TenantId: "tn_1", RetentionDays: 4000},
+			wantErr: "retention_days must be between 1 and 3650",
+		},
+		{
+			name: "zero value ignored",
+			msg:  &webhookv1.SetComplianceModeRequest{TenantId: "tn_1"},
+		},
+		{
+			name:    "replay count and endpoint",
+			msg:     &webhookv1.ReplayDLQRequest{EndpointId: "ep_1", MaxCount: 1001},
+			wantErr: "endpoint_id must be a UUID; max_count must be between 1 and 1000",
+		},
+		{
+			name:    "window too long",
+			msg:     &webhookv1.GetBacklogEstimateRequest{EndpointId: endpointID, WindowSeconds: 3601},
+			wantErr: "window_seconds must be between 0 and 3600",
+		},
+		{
+			name:    "email with a display name",
+			msg:     &webhookv1.SetNotificationSettingsRequest{TenantId: "tn_1", Email: "Ops <ops@example.com>"},
+			wantErr: "email must be an email address",
+		},
+		{
+			name: "email",
+			msg:  &webhookv1.SetNotificationSettingsRequest{TenantId: "tn_1", Email: "ops@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.msg)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %q", err, tt.wantErr)
+			}
+			if got := status.Code(err); got != codes.InvalidArgument {
+				t.Errorf("code = %v, want InvalidArgument", got)
+			}
+		})
+	}
+}
+
+func TestValidate_FieldViolations(t *testing.T) {
+	err := Validate(&webhookv1.SetTenantQuotaRequest{Quota: &webhookv1.TenantQuota{TenantId: "tn_1", MaxFanout: -2}})
+	st, _ := status.FromError(err)
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			v := br.GetFieldViolations()
+			if len(v) != 1 || v[0].GetField() != "quota.max_fanout" || v[0].GetDescription() != "must not be negative" {
+				t.Errorf("field violations = %v, want quota.max_fanout only", v)
+			}
+			return
+		}
+	}
+	t.Fatalf("status of %v has no BadRequest detail", err)
+}
+
+func TestUnsupported(t *testing.T) {
+	methods := webhookv1.File_api_webhook_v1_service_proto.Services().Get(0).Methods()
+	for i := 0; i < methods.Len(); i++ {
+		md := methods.Get(i)
+		if rules := Unsupported(md.Input()); len(rules) != 0 {
+			t.Errorf("%s declares rules Validate doesn't enforce: %v", md.Name(), rules)
+		}
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	intercept := UnaryServerInterceptor()
+	called := false
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		called = true
+		return "ok", nil
+	}
+
+	_, err := intercept(context.Background(), &webhookv1.GetDeliveryStatusRequest{EventId: "evt_1"}, &grpc.UnaryServerInfo{}, handler)
+	if status.Code(err) != codes.InvalidArgument || called {
+		t.Errorf("intercept(invalid) = %v, handler called %v, want InvalidArgument before the handler", err, called)
+	}
+
+	resp, err := intercept(context.Background(), &webhookv1.GetDeliveryStatusRequest{EventId: endpointID}, &grpc.UnaryServerInfo{}, handler)
+	if err != nil || resp != "ok" {
+		t.Errorf("intercept(valid) = %v, %v, want the handler's response", resp, err)
+	}
+}
+
+// TestUnaryServerInterceptor_HandlerRules covers the checks the handlers made before the
+// interceptor took them over
+func TestUnaryServerInterceptor_HandlerRules(t *testing.T) {
+	payload, _ := structpb.NewStruct(map[string]any{"user_id": "123", "email": "test@example.com"})
+
+	tests := []struct {
+		name    string
+		req     proto.Message
+		wantErr string
+	}{
+		{"CreateEndpoint missing tenant_id", &webhookv1.CreateEndpointRequest{Url: "https://example.com/webhook", Secret: "test-secret"}, "tenant_id is required"},
+		{"DeleteEndpoint missing tenant_id", &webhookv1.DeleteEndpointRequest{EndpointId: endpointID}, "tenant_id is required"},
+		{"DeleteEndpoint missing endpoint_id", &webhookv1.DeleteEndpointRequest{TenantId: "tn_1"}, "endpoint_id is required"},
+		{"ListEndpoints missing tenant", &webhookv1.ListEndpointsRequest{}, "tenant is required"},
+		{"VerifyEndpoint missing tenant_id", &webhookv1.VerifyEndpointRequest{EndpointId: endpointID}, "tenant_id is required"},
+		{"VerifyEndpoint missing endpoint_id", &webhookv1.VerifyEndpointRequest{TenantId: "tn_1"}, "endpoint_id is required"},
+		{"SetEndpointOrdering missing endpoint_id", &webhookv1.SetEndpointOrderingRequest{TenantId: "tn_1"}, "endpoint_id is required"},
+		{"SetEndpointCompression missing endpoint_id", &webhookv1.SetEndpointCompressionRequest{TenantId: "tn_1"}, "endpoint_id is required"},
+		{"SetEndpointSignatureScheme missing endpoint_id", &webhookv1.SetEndpointSignatureSchemeRequest{TenantId: "tn_1"}, "endpoint_id is required"},
+		{"SetEndpointClientCertificate missing endpoint_id", &webhookv1.SetEndpointClientCertificateRequest{TenantId: "tn_1"}, "endpoint_id is required"},
+		{"SetEndpointRecoveryRamp missing endpoint_id", &webhookv1.SetEndpointRecoveryRampRequest{TenantId: "tn_1", RecoveryRamp: &webhookv1.RecoveryRamp{}}, "endpoint_id is required"},
+		{"SetEndpointRecoveryRamp missing ramp", &webhookv1.SetEndpointRecoveryRampRequest{TenantId: "tn_1", EndpointId: endpointID}, "recovery_ramp is required"},
+		{"CreateSubscription missing tenant_id", &webhookv1.CreateSubscriptionRequest{EventType: "user.created", EndpointId: endpointID}, "tenant_id is required"},
+		{"CreateSubscription missing event_type", &webhookv1.CreateSubscriptionRequest{TenantId: "tn_1", EndpointId: endpointID}, "event_type is required"},
+		{"CreateSubscription missing endpoint_id", &webhookv1.CreateSubscriptionRequest{TenantId: "tn_1", EventType: "user.created"}, "endpoint_id is required"},
+		{"PublishEvent missing tenant_id", &webhookv1.PublishEventRequest{EventType: "user.created", Payload: payload}, "tenant_id is required"},
+		{"PublishEvent missing event_type", &webhookv1.PublishEventRequest{TenantId: "tn_1", Payload: payload}, "event_type is required"},
+		{"PublishEvents missing tenant_id", &webhookv1.PublishEventsRequest{Events: []*webhookv1.BatchEvent{{EventType: "a"}}}, "tenant_id is required"},
+		{"PublishEvents batch too large", &webhookv1.PublishEventsRequest{TenantId: "tn_1", Events: make([]*webhookv1.BatchEvent, 501)}, "events must contain between 1 and 500 items"},
+		{"GetDeliveryStatus missing event_id", &webhookv1.GetDeliveryStatusRequest{EndpointId: endpointID}, "event_id is required"},
+		{"ReplayDelivery missing delivery_id", &webhookv1.ReplayDeliveryRequest{Reason: "test replay"}, "delivery_id is required"},
+		{"AcknowledgeDelivery missing delivery_id", &webhookv1.AcknowledgeDeliveryRequest{Timestamp: 1, Signature: "sha256=00"}, "delivery_id is required"},
+		{"AcknowledgeDelivery missing timestamp", &webhookv1.AcknowledgeDeliveryRequest{DeliveryId: endpointID, Signature: "sha256=00"}, "timestamp is required"},
+		{"AcknowledgeDelivery missing signature", &webhookv1.AcknowledgeDeliveryRequest{DeliveryId: endpointID, Timestamp: 1}, "signature is required"},
+		{"GetSigningKeys missing tenant_id", &webhookv1.GetSigningKeysRequest{}, "tenant_id is required"},
+		{"GetDLQEntry missing delivery_id", &webhookv1.GetDLQEntryRequest{}, "delivery_id is required"},
+		{"ReplayDLQ negative max_count", &webhookv1.ReplayDLQRequest{MaxCount: -1}, "max_count must be between 1 and 1000"},
+		{"SetDLQRetention missing tenant_id", &webhookv1.SetDLQRetentionRequest{}, "tenant_id is required"},
+		{"SetDLQRetention negative max_entries", &webhookv1.SetDLQRetentionRequest{TenantId: "tn_1", MaxEntries: -1}, "max_entries must not be negative"},
+		{"SetTenantQuota negative events per minute", &webhookv1.SetTenantQuotaRequest{Quota: &webhookv1.TenantQuota{TenantId: "tn_1", EventsPerMinute: -1}}, "quota.events_per_minute must not be negative"},
+		{"SetComplianceMode missing tenant_id", &webhookv1.SetComplianceModeRequest{RecordRequests: true}, "tenant_id is required"},
+		{"SetComplianceMode negative retention", &webhookv1.SetComplianceModeRequest{TenantId: "tn_1", RetentionDays: -1}, "retention_days must be between 1 and 3650"},
+		{"ListDeliveryRecordings missing tenant_id", &webhookv1.ListDeliveryRecordingsRequest{DeliveryId: endpointID, Reason: "audit"}, "tenant_id is required"},
+		{"ListDeliveryRecordings missing delivery_id", &webhookv1.ListDeliveryRecordingsRequest{TenantId: "tn_1", Reason: "audit"}, "delivery_id is required"},
+		{"ListDeliveryRecordings missing reason", &webhookv1.ListDeliveryRecordingsRequest{TenantId: "tn_1", DeliveryId: endpointID}, "reason is required"},
+		{"SetDeliverySettings missing tenant_id", &webhookv1.SetDeliverySettingsRequest{SenderHeaders: true}, "tenant_id is required"},
+		{"PauseDispatch missing reason", &webhookv1.PauseDispatchRequest{}, "reason is required"},
+		{"ResumeDispatch negative ramp", &webhookv1.ResumeDispatchRequest{RampSeconds: -1}, "ramp_seconds must be between 0 and 86400"},
+		{"ResumeDispatch ramp too long", &webhookv1.ResumeDispatchRequest{RampSeconds: 86401}, "ramp_seconds must be between 0 and 86400"},
+		{"ResumeDispatch start percent too high", &webhookv1.ResumeDispatchRequest{RampSeconds: 60, StartPercent: 101}, "start_percent must be between 0 and 100"},
+		{"GetBacklogEstimate negative window", &webhookv1.GetBacklogEstimateRequest{TenantId: "tn_1", WindowSeconds: -1}, "window_seconds must be between 0 and 3600"},
+		{"GetDeliveryStats missing tenant_id", &webhookv1.GetDeliveryStatsRequest{}, "tenant_id is required"},
+		{"GetDeliveryStats window over 7 days", &webhookv1.GetDeliveryStatsRequest{TenantId: "tn_1", WindowSeconds: 604801}, "window_seconds must be between 0 and 604800"},
+		{"GetFailureTrends missing tenant_id", &webhookv1.GetFailureTrendsRequest{}, "tenant_id is required"},
+		{"GetFailureTrends negative window", &webhookv1.GetFailureTrendsRequest{TenantId: "tn_1", WindowSeconds: -5}, "window_seconds must be between 0 and 604800"},
+		{"ListSystemEvents missing tenant_id", &webhookv1.ListSystemEventsRequest{}, "tenant_id is required"},
+		{"ListAuditLog missing tenant_id", &webhookv1.ListAuditLogRequest{}, "tenant_id is required"},
+	}
+
+	intercept := UnaryServerInterceptor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			handler := func(context.Context, interface{}) (interface{}, error) {
+				called = true
+				return nil, nil
+			}
+			_, err := intercept(context.Background(), tt.req, &grpc.UnaryServerInfo{}, handler)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("intercept() error = %v, want %q", err, tt.wantErr)
+			}
+			if status.Code(err) != codes.InvalidArgument || called {
+				t.Errorf("intercept() code = %v, handler called %v, want InvalidArgument before the handler", status.Code(err), called)
+			}
+		})
+	}
+}
+
+// fakeServerStream is a grpc.ServerStream that hands out one request
+type fakeServerStream struct {
+	grpc.ServerStream
+	req *webhookv1.WatchDeliveryStatusRequest
+}
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	proto.Merge(m.(proto.Message), s.req)
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	intercept := StreamServerInterceptor()
+	recv := func(_ interface{}, stream grpc.ServerStream) error {
+		return stream.RecvMsg(&webhookv1.WatchDeliveryStatusRequest{})
+	}
+
+	ss := &fakeServerStream{req: &webhookv1.WatchDeliveryStatusRequest{EventId: "evt_1"}}
+	if err := intercept(nil, ss, &grpc.StreamServerInfo{}, recv); err == nil || err.Error() != "event_id must be a UUID" {
+		t.Errorf("intercept(invalid) error = %v, want event_id must be a UUID", err)
+	}
+
+	ss = &fakeServerStream{req: &webhookv1.WatchDeliveryStatusRequest{EventId: endpointID}}
+	if err := intercept(nil, ss, &grpc.StreamServerInfo{}, recv); err != nil {
+		t.Errorf("intercept(valid) unexpected error: %v", err)
+	}
+}
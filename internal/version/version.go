@@ -0,0 +1,49 @@
+// Package version holds the build information stamped into every binary at link time:
+//
+//	go build -ldflags "-X github.com/austindbirch/harbor_hook/internal/version.Version=v1.2.3 \
+//	  -X github.com/austindbirch/harbor_hook/internal/version.GitCommit=abc1234 \
+//	  -X github.com/austindbirch/harbor_hook/internal/version.BuildTime=2025-01-01T00:00:00Z"
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"runtime"
+)
+
+var (
+	// These will be set by ldflags during build
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"gitCommit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+}
+
+// Get returns the build information of the running binary
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+}
+
+// HTTPHandler serves the build information as JSON, for /version
+func HTTPHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(Get())
+	}
+}
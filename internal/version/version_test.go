@@ -0,0 +1,47 @@
+package version
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	orig := [3]string{Version, GitCommit, BuildTime}
+	defer func() { Version, GitCommit, BuildTime = orig[0], orig[1], orig[2] }()
+	Version, GitCommit, BuildTime = "v1.2.3", "abc1234", "2025-01-01T00:00:00Z"
+
+	got := Get()
+	want := Info{
+		Version:   "v1.2.3",
+		GitCommit: "abc1234",
+		BuildTime: "2025-01-01T00:00:00Z",
+		GoVersion: runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+	if got != want {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestHTTPHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HTTPHandler()(rec, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", ct)
+	}
+	var info Info
+	if err := json.Unmarshal(rec.Body.Bytes(), &info); err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	if info != Get() {
+		t.Errorf("body = %+v, want %+v", info, Get())
+	}
+}
@@ -0,0 +1,280 @@
+// Package client is a Go SDK for producers publishing to harborhook. It wraps the gRPC API, or
+// the REST gateway, with bearer-token handling, retries with backoff, and idempotency keys, so
+// a publish that is retried after a dropped connection is fanned out once.
+//
+//	c, err := client.New("harborhook:50051", client.Options{
+//		TenantID: "tn_123",
+//		Token:    client.StaticToken(os.Getenv("HARBORHOOK_TOKEN")),
+//	})
+//	...
+//	resp, err := c.Publish(ctx, "order.created", order, client.WithIdempotencyKey(client.IdempotencyKey("order", order.ID)))
+//
+// Errors are gRPC statuses on either transport, so status.Code(err) works the same for both.
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// Defaults for a zero Options field
+const (
+	DefaultMaxAttempts = 4
+	DefaultBackoff     = 200 * time.Millisecond
+	DefaultMaxBackoff  = 5 * time.Second
+	DefaultTimeout     = 30 * time.Second
+)
+
+// Options configure a Client. The zero value of each field uses its default.
+type Options struct {
+	// TenantID is the tenant events and endpoints are created under
+	TenantID string
+	// Token supplies the bearer token sent with each call; nil sends none
+	Token TokenSource
+	// MaxAttempts bounds how many times a retryable call is sent, including the first
+	MaxAttempts int
+	// Backoff is the delay before the first retry; it doubles per retry up to MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds each attempt, on top of the caller's context
+	Timeout time.Duration
+	// TLS secures the connection; nil dials gRPC in plaintext and uses the HTTP client's default
+	TLS *tls.Config
+	// HTTPClient sends REST calls; nil uses a client built from TLS
+	HTTPClient *http.Client
+}
+
+// Client calls harborhook for one tenant. It is safe for concurrent use.
+type Client struct {
+	opts  Options
+	t     transport
+	close func() error
+}
+
+// New dials harborhook's gRPC API at target (host:port)
+func New(target string, opts Options) (*Client, error) {
+	creds := insecure.NewCredentials()
+	if opts.TLS != nil {
+		creds = credentials.NewTLS(opts.TLS)
+	}
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", target, err)
+	}
+	c := NewFromConn(conn, opts)
+	c.close = conn.Close
+	return c, nil
+}
+
+// NewFromConn uses an existing gRPC connection; Close leaves it open
+func NewFromConn(conn grpc.ClientConnInterface, opts Options) *Client {
+	return &Client{opts: opts, t: &grpcTransport{api: webhookv1.NewWebhookServiceClient(conn)}}
+}
+
+// NewHTTP calls harborhook's REST gateway at baseURL, e.g. https://harborhook.example.com:8443
+func NewHTTP(baseURL string, opts Options) (*Client, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base URL %q", baseURL)
+	}
+	hc := opts.HTTPClient
+	if hc == nil {
+		hc = &http.Client{Transport: &http.Transport{TLSClientConfig: opts.TLS}}
+	}
+	return &Client{opts: opts, t: &httpTransport{base: u, client: hc}}, nil
+}
+
+// Close releases the connection New dialed
+func (c *Client) Close() error {
+	if c.close == nil {
+		return nil
+	}
+	return c.close()
+}
+
+// PublishOption sets an optional field of a publish
+type PublishOption func(*webhookv1.PublishEventRequest)
+
+// WithIdempotencyKey deduplicates the event against earlier publishes with the same key.
+// Without it Publish generates a random key, which only protects its own retries.
+func WithIdempotencyKey(key string) PublishOption {
+	return func(r *webhookv1.PublishEventRequest) { r.IdempotencyKey = key }
+}
+
+// WithTTL dead-letters deliveries still pending ttl after publish
+func WithTTL(ttl time.Duration) PublishOption {
+	return func(r *webhookv1.PublishEventRequest) { r.Ttl = durationpb.New(ttl) }
+}
+
+// WithDeliverBy dead-letters deliveries still pending at t
+func WithDeliverBy(t time.Time) PublishOption {
+	return func(r *webhookv1.PublishEventRequest) { r.DeliverBy = timestamppb.New(t) }
+}
+
+// WithPublishAt schedules the event to fan out at t
+func WithPublishAt(t time.Time) PublishOption {
+	return func(r *webhookv1.PublishEventRequest) { r.PublishAt = timestamppb.New(t) }
+}
+
+// Publish publishes an event. payload is a *structpb.Struct, a map[string]any, or anything
+// that marshals to a JSON object. Every publish carries an idempotency key, so it is retried
+// like a read.
+func (c *Client) Publish(ctx context.Context, eventType string, payload any, opts ...PublishOption) (*webhookv1.PublishEventResponse, error) {
+	p, err := toStruct(payload)
+	if err != nil {
+		return nil, err
+	}
+	req := &webhookv1.PublishEventRequest{TenantId: c.opts.TenantID, EventType: eventType, Payload: p}
+	for _, opt := range opts {
+		opt(req)
+	}
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = NewIdempotencyKey()
+	}
+	var resp *webhookv1.PublishEventResponse
+	err = c.call(ctx, true, func(ctx context.Context) (err error) {
+		resp, err = c.t.publish(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// CreateEndpoint registers an endpoint; TenantId defaults to the client's tenant. It is not
+// retried, since a retry after a lost response would create a second endpoint.
+func (c *Client) CreateEndpoint(ctx context.Context, req *webhookv1.CreateEndpointRequest) (*webhookv1.CreateEndpointResponse, error) {
+	if req.TenantId == "" {
+		req.TenantId = c.opts.TenantID
+	}
+	var resp *webhookv1.CreateEndpointResponse
+	err := c.call(ctx, false, func(ctx context.Context) (err error) {
+		resp, err = c.t.createEndpoint(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// GetDeliveryStatus lists an event's deliveries
+func (c *Client) GetDeliveryStatus(ctx context.Context, req *webhookv1.GetDeliveryStatusRequest) (*webhookv1.GetDeliveryStatusResponse, error) {
+	var resp *webhookv1.GetDeliveryStatusResponse
+	err := c.call(ctx, true, func(ctx context.Context) (err error) {
+		resp, err = c.t.getDeliveryStatus(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// ReplayDelivery sends a delivery again. It is not retried, since a retry after a lost
+// response would replay it twice.
+func (c *Client) ReplayDelivery(ctx context.Context, deliveryID, reason string) (*webhookv1.ReplayDeliveryResponse, error) {
+	req := &webhookv1.ReplayDeliveryRequest{DeliveryId: deliveryID, Reason: reason}
+	var resp *webhookv1.ReplayDeliveryResponse
+	err := c.call(ctx, false, func(ctx context.Context) (err error) {
+		resp, err = c.t.replayDelivery(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// call runs fn with a token and a per-attempt timeout. Retryable calls are sent again while
+// harborhook is unavailable or rate limiting, backing off between attempts.
+func (c *Client) call(ctx context.Context, retryable bool, fn func(context.Context) error) error {
+	attempts := 1
+	if retryable {
+		attempts = c.opts.MaxAttempts
+		if attempts <= 0 {
+			attempts = DefaultMaxAttempts
+		}
+	}
+	var err error
+	for n := 1; ; n++ {
+		err = c.attempt(ctx, fn)
+		if n >= attempts || !retry(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(c.backoff(n)):
+		}
+	}
+}
+
+func (c *Client) attempt(ctx context.Context, fn func(context.Context) error) error {
+	timeout := c.opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if c.opts.Token != nil {
+		tok, err := c.opts.Token.Token(ctx)
+		if err != nil {
+			return fmt.Errorf("get token: %w", err)
+		}
+		ctx = withToken(ctx, tok)
+	}
+	return fn(ctx)
+}
+
+// backoff is the delay before retry n: Backoff doubled per retry, capped, with up to half
+// of it jittered off so clients that failed together don't retry together
+func (c *Client) backoff(n int) time.Duration {
+	base, ceil := c.opts.Backoff, c.opts.MaxBackoff
+	if base <= 0 {
+		base = DefaultBackoff
+	}
+	if ceil <= 0 {
+		ceil = DefaultMaxBackoff
+	}
+	d := base << (n - 1)
+	if d > ceil || d <= 0 {
+		d = ceil
+	}
+	return d - time.Duration(rand.Int64N(int64(d/2)+1))
+}
+
+// retry reports whether err is worth sending the call again for
+func retry(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.ResourceExhausted:
+		return true
+	}
+	return false
+}
+
+// toStruct converts a publish payload to the Struct the API takes
+func toStruct(payload any) (*structpb.Struct, error) {
+	switch p := payload.(type) {
+	case *structpb.Struct:
+		return p, nil
+	case map[string]any:
+		return structpb.NewStruct(p)
+	}
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal payload: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(raw, &m); err != nil || m == nil {
+		return nil, errors.New("payload must marshal to a JSON object")
+	}
+	return structpb.NewStruct(m)
+}
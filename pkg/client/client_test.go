@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// fakeAPI records the calls it gets and fails the first failures of them with fail
+type fakeAPI struct {
+	webhookv1.UnimplementedWebhookServiceServer
+	fail     codes.Code
+	failures int
+	calls    int
+	keys     []string
+	auth     []string
+}
+
+func (f *fakeAPI) record(ctx context.Context) error {
+	f.calls++
+	md, _ := metadata.FromIncomingContext(ctx)
+	f.auth = append(f.auth, md.Get("authorization")...)
+	if f.calls <= f.failures {
+		return status.Error(f.fail, "try again")
+	}
+	return nil
+}
+
+func (f *fakeAPI) PublishEvent(ctx context.Context, req *webhookv1.PublishEventRequest) (*webhookv1.PublishEventResponse, error) {
+	f.keys = append(f.keys, req.TenantId+"/"+req.IdempotencyKey)
+	if err := f.record(ctx); err != nil {
+		return nil, err
+	}
+	return &webhookv1.PublishEventResponse{EventId: "evt_1", FanoutCount: 2}, nil
+}
+
+func (f *fakeAPI) CreateEndpoint(ctx context.Context, req *webhookv1.CreateEndpointRequest) (*webhookv1.CreateEndpointResponse, error) {
+	if err := f.record(ctx); err != nil {
+		return nil, err
+	}
+	return &webhookv1.CreateEndpointResponse{}, nil
+}
+
+func serveFake(t *testing.T, api *fakeAPI, opts Options) *Client {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := grpc.NewServer()
+	webhookv1.RegisterWebhookServiceServer(srv, api)
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	opts.Backoff = time.Millisecond
+	c, err := New(lis.Addr().String(), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = c.Close() })
+	return c
+}
+
+func TestClient_Publish_RetriesWithOneKey(t *testing.T) {
+	api := &fakeAPI{fail: codes.Unavailable, failures: 2}
+	c := serveFake(t, api, Options{TenantID: "tn_1", Token: StaticToken("jwt")})
+
+	resp, err := c.Publish(context.Background(), "order.created", struct {
+		ID string `json:"id"`
+	}{"ord_1"})
+	if err != nil || resp.EventId != "evt_1" {
+		t.Fatalf("Publish() = %v, %v, want evt_1", resp, err)
+	}
+	if api.calls != 3 || api.keys[0] != api.keys[2] || api.keys[0] == "tn_1/" {
+		t.Errorf("sent %d times with keys %v, want 3 tries under one generated key", api.calls, api.keys)
+	}
+	if api.auth[0] != "Bearer jwt" {
+		t.Errorf("authorization = %v, want the bearer token", api.auth)
+	}
+
+	api.calls, api.keys = 0, nil
+	if _, err := c.Publish(context.Background(), "order.created", map[string]any{"id": "ord_1"}, WithIdempotencyKey("order-1")); err != nil {
+		t.Fatal(err)
+	}
+	if api.keys[0] != "tn_1/order-1" {
+		t.Errorf("sent key %v, want the caller's", api.keys)
+	}
+}
+
+func TestClient_GivesUp(t *testing.T) {
+	api := &fakeAPI{fail: codes.Unavailable, failures: 10}
+	c := serveFake(t, api, Options{TenantID: "tn_1", MaxAttempts: 3})
+	if _, err := c.Publish(context.Background(), "a", map[string]any{}); status.Code(err) != codes.Unavailable {
+		t.Errorf("Publish() = %v, want Unavailable", err)
+	}
+	if api.calls != 3 {
+		t.Errorf("sent %d times, want MaxAttempts", api.calls)
+	}
+
+	api.calls = 0
+	if _, err := c.CreateEndpoint(context.Background(), &webhookv1.CreateEndpointRequest{Url: "https://example.com/hook"}); status.Code(err) != codes.Unavailable {
+		t.Errorf("CreateEndpoint() = %v, want Unavailable", err)
+	}
+	if api.calls != 1 {
+		t.Errorf("CreateEndpoint sent %d times, want once", api.calls)
+	}
+
+	api.calls, api.fail = 0, codes.InvalidArgument
+	if _, err := c.Publish(context.Background(), "a", map[string]any{}); status.Code(err) != codes.InvalidArgument || api.calls != 1 {
+		t.Errorf("Publish() = %v after %d sends, want InvalidArgument without retrying", err, api.calls)
+	}
+}
+
+func TestClient_HTTP(t *testing.T) {
+	var gotAuth, gotPath, gotQuery string
+	var gotBody map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth, gotPath, gotQuery = r.Header.Get("Authorization"), r.URL.Path, r.URL.RawQuery
+		gotBody = nil
+		raw, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(raw, &gotBody)
+		switch r.URL.Path {
+		case "/v1/tenants/tn_1/events:publish":
+			fmt.Fprint(w, `{"eventId":"evt_1","fanoutCount":3,"unknownField":1}`)
+		case "/v1/events/evt_1/deliveries":
+			fmt.Fprint(w, `{"attempts":[{"deliveryId":"del_1","status":"DELIVERY_ATTEMPT_STATUS_DELIVERED"}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"code":5,"message":"delivery not found"}`)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := NewHTTP(srv.URL, Options{TenantID: "tn_1", Token: StaticToken("jwt")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := c.Publish(context.Background(), "order.created", map[string]any{"id": "ord_1"}, WithIdempotencyKey("k1"))
+	if err != nil || resp.FanoutCount != 3 {
+		t.Fatalf("Publish() = %v, %v, want 3 deliveries", resp, err)
+	}
+	if gotAuth != "Bearer jwt" || gotBody["idempotencyKey"] != "k1" || gotBody["eventType"] != "order.created" {
+		t.Errorf("publish sent auth %q body %v", gotAuth, gotBody)
+	}
+
+	status1, err := c.GetDeliveryStatus(context.Background(), &webhookv1.GetDeliveryStatusRequest{EventId: "evt_1", Limit: 5})
+	if err != nil || len(status1.Attempts) != 1 || status1.Attempts[0].Status != webhookv1.DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DELIVERED {
+		t.Errorf("GetDeliveryStatus() = %v, %v", status1, err)
+	}
+	if gotQuery != "limit=5" {
+		t.Errorf("query = %q, want limit=5", gotQuery)
+	}
+
+	_, err = c.ReplayDelivery(context.Background(), "del_9", "")
+	if status.Code(err) != codes.NotFound || status.Convert(err).Message() != "delivery not found" {
+		t.Errorf("ReplayDelivery() = %v, want the gateway's NotFound", err)
+	}
+	if gotPath != "/v1/deliveries/del_9:replay" {
+		t.Errorf("replay path = %q", gotPath)
+	}
+}
+
+func TestGatewayError(t *testing.T) {
+	if err := gatewayError(http.StatusServiceUnavailable, []byte("upstream down")); status.Code(err) != codes.Unavailable {
+		t.Errorf("gatewayError(503 from a proxy) = %v, want Unavailable", err)
+	}
+	if err := gatewayError(http.StatusTooManyRequests, []byte(`{"code":8,"message":"quota"}`)); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("gatewayError(429) = %v, want ResourceExhausted", err)
+	}
+}
+
+func jwtExpiring(at time.Time) string {
+	claims, _ := json.Marshal(map[string]any{"exp": at.Unix()})
+	return "e30." + base64.RawURLEncoding.EncodeToString(claims) + ".sig"
+}
+
+func TestCachedToken(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	fetches := 0
+	src := CachedToken(func(context.Context) (string, error) {
+		fetches++
+		return jwtExpiring(now.Add(time.Hour)), nil
+	}).(*cachedToken)
+	src.now = func() time.Time { return now }
+
+	for range 3 {
+		if _, err := src.Token(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("fetched %d times, want the token reused", fetches)
+	}
+	now = now.Add(time.Hour - TokenRefreshSkew)
+	_, _ = src.Token(context.Background())
+	if fetches != 2 {
+		t.Errorf("fetched %d times, want a refresh near expiry", fetches)
+	}
+}
+
+func TestTokenEndpoint(t *testing.T) {
+	var got map[string]any
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		fmt.Fprintf(w, `{"token":%q,"expires_in":3600,"token_type":"Bearer"}`, jwtExpiring(time.Now().Add(time.Hour)))
+	}))
+	defer issuer.Close()
+
+	tok, err := TokenEndpoint(issuer.URL, "tn_1", "publisher").Token(context.Background())
+	if err != nil || expiry(tok).IsZero() {
+		t.Fatalf("Token() = %q, %v", tok, err)
+	}
+	if got["tenant_id"] != "tn_1" || fmt.Sprint(got["roles"]) != "[publisher]" {
+		t.Errorf("issuer got %v, want the tenant and roles", got)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	if a, b := IdempotencyKey("order.created", "ord_1"), IdempotencyKey("order.created", "ord_1"); a != b {
+		t.Errorf("IdempotencyKey not stable: %q vs %q", a, b)
+	}
+	if IdempotencyKey("a", "bc") == IdempotencyKey("ab", "c") {
+		t.Error("IdempotencyKey collides across part boundaries")
+	}
+	if a, b := NewIdempotencyKey(), NewIdempotencyKey(); a == b || len(a) != 36 {
+		t.Errorf("NewIdempotencyKey() = %q, %q, want distinct UUIDs", a, b)
+	}
+}
@@ -0,0 +1,27 @@
+package client
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// NewIdempotencyKey returns a random UUID. Publish uses one when no key is given, which makes
+// its own retries safe; use IdempotencyKey to also dedupe publishes the producer repeats.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// IdempotencyKey derives a stable key from what identifies the event to the producer, e.g.
+// IdempotencyKey("order.created", orderID), so publishing it again after a crash or a redeploy
+// is deduplicated too
+func IdempotencyKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return "ik_" + hex.EncodeToString(sum[:16])
+}
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenRefreshSkew is how long before a cached token expires it is fetched again
+const TokenRefreshSkew = time.Minute
+
+// TokenSource supplies the JWT sent with each call
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is a fixed token, e.g. from the environment
+type StaticToken string
+
+func (t StaticToken) Token(context.Context) (string, error) { return string(t), nil }
+
+// TokenFunc fetches a fresh token
+type TokenFunc func(ctx context.Context) (string, error)
+
+// CachedToken reuses the token fetch returns until TokenRefreshSkew before its exp claim.
+// Tokens without an exp claim are reused until fetched again after an error.
+func CachedToken(fetch TokenFunc) TokenSource {
+	return &cachedToken{fetch: fetch}
+}
+
+type cachedToken struct {
+	fetch TokenFunc
+	now   func() time.Time
+
+	mu  sync.Mutex
+	tok string
+	exp time.Time
+}
+
+func (c *cachedToken) Token(ctx context.Context) (string, error) {
+	now := time.Now
+	if c.now != nil {
+		now = c.now
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.tok != "" && (c.exp.IsZero() || now().Before(c.exp.Add(-TokenRefreshSkew))) {
+		return c.tok, nil
+	}
+	tok, err := c.fetch(ctx)
+	if err != nil {
+		c.tok = ""
+		return "", err
+	}
+	c.tok, c.exp = tok, expiry(tok)
+	return tok, nil
+}
+
+// expiry reads a JWT's exp claim without verifying it; the server does that
+func expiry(tok string) time.Time {
+	parts := strings.Split(tok, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+	var claims struct {
+		Exp int64 `json:"exp"`
+	}
+	if json.Unmarshal(raw, &claims) != nil || claims.Exp == 0 {
+		return time.Time{}
+	}
+	return time.Unix(claims.Exp, 0)
+}
+
+// TokenEndpoint fetches tokens for tenantID from a harborhook token issuer (the jwks-server's
+// POST /token), caching each until shortly before it expires. Roles narrow the token; without
+// them it has full control of the tenant.
+func TokenEndpoint(tokenURL, tenantID string, roles ...string) TokenSource {
+	return CachedToken(func(ctx context.Context) (string, error) {
+		body, _ := json.Marshal(map[string]any{"tenant_id": tenantID, "roles": roles})
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, bytes.NewReader(body))
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return "", fmt.Errorf("token issuer: %s", resp.Status)
+		}
+		var out struct {
+			Token string `json:"token"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&out); err != nil || out.Token == "" {
+			return "", fmt.Errorf("token issuer: no token in response")
+		}
+		return out.Token, nil
+	})
+}
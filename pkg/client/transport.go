@@ -0,0 +1,189 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+
+	webhookv1 "github.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1"
+)
+
+// transport sends one attempt of each call, over gRPC or the REST gateway
+type transport interface {
+	publish(context.Context, *webhookv1.PublishEventRequest) (*webhookv1.PublishEventResponse, error)
+	createEndpoint(context.Context, *webhookv1.CreateEndpointRequest) (*webhookv1.CreateEndpointResponse, error)
+	getDeliveryStatus(context.Context, *webhookv1.GetDeliveryStatusRequest) (*webhookv1.GetDeliveryStatusResponse, error)
+	replayDelivery(context.Context, *webhookv1.ReplayDeliveryRequest) (*webhookv1.ReplayDeliveryResponse, error)
+}
+
+type tokenKey struct{}
+
+// withToken carries the bearer token to the transport, which sends it as gRPC metadata or
+// an Authorization header
+func withToken(ctx context.Context, tok string) context.Context {
+	return context.WithValue(ctx, tokenKey{}, tok)
+}
+
+func tokenFrom(ctx context.Context) string {
+	tok, _ := ctx.Value(tokenKey{}).(string)
+	return tok
+}
+
+type grpcTransport struct {
+	api webhookv1.WebhookServiceClient
+}
+
+func (g *grpcTransport) outgoing(ctx context.Context) context.Context {
+	if tok := tokenFrom(ctx); tok != "" {
+		return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+tok)
+	}
+	return ctx
+}
+
+func (g *grpcTransport) publish(ctx context.Context, req *webhookv1.PublishEventRequest) (*webhookv1.PublishEventResponse, error) {
+	return g.api.PublishEvent(g.outgoing(ctx), req)
+}
+
+func (g *grpcTransport) createEndpoint(ctx context.Context, req *webhookv1.CreateEndpointRequest) (*webhookv1.CreateEndpointResponse, error) {
+	return g.api.CreateEndpoint(g.outgoing(ctx), req)
+}
+
+func (g *grpcTransport) getDeliveryStatus(ctx context.Context, req *webhookv1.GetDeliveryStatusRequest) (*webhookv1.GetDeliveryStatusResponse, error) {
+	return g.api.GetDeliveryStatus(g.outgoing(ctx), req)
+}
+
+func (g *grpcTransport) replayDelivery(ctx context.Context, req *webhookv1.ReplayDeliveryRequest) (*webhookv1.ReplayDeliveryResponse, error) {
+	return g.api.ReplayDelivery(g.outgoing(ctx), req)
+}
+
+type httpTransport struct {
+	base   *url.URL
+	client *http.Client
+}
+
+func (h *httpTransport) publish(ctx context.Context, req *webhookv1.PublishEventRequest) (*webhookv1.PublishEventResponse, error) {
+	resp := &webhookv1.PublishEventResponse{}
+	path := "/v1/tenants/" + url.PathEscape(req.TenantId) + "/events:publish"
+	return resp, h.do(ctx, http.MethodPost, path, nil, req, resp)
+}
+
+func (h *httpTransport) createEndpoint(ctx context.Context, req *webhookv1.CreateEndpointRequest) (*webhookv1.CreateEndpointResponse, error) {
+	resp := &webhookv1.CreateEndpointResponse{}
+	path := "/v1/tenants/" + url.PathEscape(req.TenantId) + "/endpoints"
+	return resp, h.do(ctx, http.MethodPost, path, nil, req, resp)
+}
+
+func (h *httpTransport) getDeliveryStatus(ctx context.Context, req *webhookv1.GetDeliveryStatusRequest) (*webhookv1.GetDeliveryStatusResponse, error) {
+	q := url.Values{}
+	if req.EndpointId != "" {
+		q.Set("endpoint_id", req.EndpointId)
+	}
+	if req.From != nil {
+		q.Set("from", req.From.AsTime().Format(time.RFC3339Nano))
+	}
+	if req.To != nil {
+		q.Set("to", req.To.AsTime().Format(time.RFC3339Nano))
+	}
+	if req.Limit != 0 {
+		q.Set("limit", strconv.Itoa(int(req.Limit)))
+	}
+	if req.IncludeReplayChains {
+		q.Set("include_replay_chains", "true")
+	}
+	resp := &webhookv1.GetDeliveryStatusResponse{}
+	path := "/v1/events/" + url.PathEscape(req.EventId) + "/deliveries"
+	return resp, h.do(ctx, http.MethodGet, path, q, nil, resp)
+}
+
+func (h *httpTransport) replayDelivery(ctx context.Context, req *webhookv1.ReplayDeliveryRequest) (*webhookv1.ReplayDeliveryResponse, error) {
+	resp := &webhookv1.ReplayDeliveryResponse{}
+	path := "/v1/deliveries/" + url.PathEscape(req.DeliveryId) + ":replay"
+	return resp, h.do(ctx, http.MethodPost, path, nil, req, resp)
+}
+
+// do sends a gateway request and decodes its response into out. The gateway ignores body
+// fields that are also path parameters, so the whole request is sent as the body.
+func (h *httpTransport) do(ctx context.Context, method, path string, query url.Values, in, out proto.Message) error {
+	u := h.base.JoinPath(path)
+	u.RawQuery = query.Encode()
+	var body io.Reader
+	if in != nil {
+		raw, err := protojson.Marshal(in)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "marshal request: %v", err)
+		}
+		body = bytes.NewReader(raw)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "build request: %v", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if tok := tokenFrom(ctx); tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return status.FromContextError(ctx.Err()).Err()
+		}
+		return status.Errorf(codes.Unavailable, "%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "read response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return gatewayError(resp.StatusCode, raw)
+	}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(raw, out); err != nil {
+		return status.Errorf(codes.Internal, "decode response: %v", err)
+	}
+	return nil
+}
+
+// gatewayError turns a gateway error response back into the gRPC status it was made from.
+// Bodies that aren't gateway errors (from a proxy in front of it, say) map by HTTP status.
+func gatewayError(httpStatus int, raw []byte) error {
+	var e struct {
+		Code    *int32 `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(raw, &e) == nil && e.Code != nil {
+		return status.Error(codes.Code(*e.Code), e.Message)
+	}
+	code := codes.Unknown
+	switch httpStatus {
+	case http.StatusBadRequest:
+		code = codes.InvalidArgument
+	case http.StatusUnauthorized:
+		code = codes.Unauthenticated
+	case http.StatusForbidden:
+		code = codes.PermissionDenied
+	case http.StatusNotFound:
+		code = codes.NotFound
+	case http.StatusConflict:
+		code = codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		code = codes.ResourceExhausted
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		code = codes.Unavailable
+	}
+	return status.Error(code, fmt.Sprintf("HTTP %d: %s", httpStatus, bytes.TrimSpace(raw)))
+}
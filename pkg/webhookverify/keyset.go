@@ -0,0 +1,99 @@
+package webhookverify
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// KeySetRefetch is the least time between fetches of a KeySet
+const KeySetRefetch = 10 * time.Second
+
+// KeySet is a KeySource backed by a tenant's published signing keys
+// (GET /v1/tenants/{tenant_id}/signing-keys, a JWK set). It fetches the set again when a
+// signature names a kid it doesn't hold, at most every KeySetRefetch, so a new key is picked up.
+type KeySet struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]ed25519.PublicKey
+	fetched time.Time
+	lastErr error
+}
+
+// NewKeySet returns a KeySet for the JWK set at url, fetched with client (http.DefaultClient
+// when nil) on first use
+func NewKeySet(url string, client *http.Client) *KeySet {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &KeySet{url: url, client: client}
+}
+
+// PublicKey returns the key kid names
+func (s *KeySet) PublicKey(kid string) (ed25519.PublicKey, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pub, ok := s.keys[kid]; ok {
+		return pub, true
+	}
+	if time.Since(s.fetched) < KeySetRefetch {
+		return nil, false
+	}
+	s.fetched = time.Now()
+	keys, err := fetchKeySet(s.client, s.url)
+	s.lastErr = err
+	if err != nil {
+		return nil, false
+	}
+	s.keys = keys
+	pub, ok := s.keys[kid]
+	return pub, ok
+}
+
+// Err returns the error from the last fetch, if it failed
+func (s *KeySet) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.lastErr
+}
+
+// fetchKeySet GETs a JWK set and returns its Ed25519 keys by kid
+func fetchKeySet(client *http.Client, url string) (map[string]ed25519.PublicKey, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch signing keys: HTTP %d", resp.StatusCode)
+	}
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Crv string `json:"crv"`
+			Kid string `json:"kid"`
+			X   string `json:"x"`
+		} `json:"keys"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("decode signing keys: %w", err)
+	}
+	keys := map[string]ed25519.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "OKP" || k.Crv != "Ed25519" {
+			continue
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			continue
+		}
+		keys[k.Kid] = x
+	}
+	return keys, nil
+}
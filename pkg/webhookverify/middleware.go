@@ -0,0 +1,32 @@
+package webhookverify
+
+import (
+	"errors"
+	"net/http"
+)
+
+// Middleware verifies deliveries with secret and the default settings before passing them to
+// next. See Verifier.Middleware.
+func Middleware(secret string, next http.Handler) http.Handler {
+	return (&Verifier{Secrets: []string{secret}}).Middleware(next)
+}
+
+// Middleware verifies each request before passing it to next, with its body readable again
+// and already decompressed. Requests that fail are answered 401 (413 for an oversized body,
+// 400 for one that can't be read) with the reason, and don't reach next.
+func (v *Verifier) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := v.VerifyRequest(r); err != nil {
+			code := http.StatusUnauthorized
+			switch {
+			case errors.Is(err, ErrBodyTooLarge):
+				code = http.StatusRequestEntityTooLarge
+			case errors.Is(err, ErrUnreadableBody):
+				code = http.StatusBadRequest
+			}
+			http.Error(w, "invalid signature: "+err.Error(), code)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,46 @@
+package webhookverify
+
+import "testing"
+
+func TestAbs64(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    int64
+		expected int64
+	}{
+		{
+			name:     "positive number",
+			input:    42,
+			expected: 42,
+		},
+		{
+			name:     "negative number",
+			input:    -42,
+			expected: 42,
+		},
+		{
+			name:     "zero",
+			input:    0,
+			expected: 0,
+		},
+		{
+			name:     "max int64",
+			input:    9223372036854775807,
+			expected: 9223372036854775807,
+		},
+		{
+			name:     "min int64 + 1",
+			input:    -9223372036854775807,
+			expected: 9223372036854775807,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := abs64(tt.input)
+			if result != tt.expected {
+				t.Errorf("abs64(%d) = %d, want %d", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,274 @@
+// Package webhookverify checks harborhook webhook signatures in Go receivers. It verifies
+// every scheme harborhook signs with (v1, v2 and ed25519), with the same timestamp leeway and
+// constant-time comparisons as harborhook's own test receiver, and decompresses gzipped
+// deliveries before verifying, since signatures cover the uncompressed body.
+//
+// The package depends only on the standard library.
+//
+//	http.Handle("/webhook", webhookverify.Middleware("whsec_...", handler))
+//
+// or, verifying by hand:
+//
+//	body, err := webhookverify.VerifyRequest("whsec_...", r)
+package webhookverify
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Defaults for a zero Verifier field, matching harborhook's defaults
+const (
+	DefaultSignatureHeader = "X-HarborHook-Signature"
+	DefaultTimestampHeader = "X-HarborHook-Timestamp"
+	DefaultLeeway          = 5 * time.Minute
+	DefaultMaxBodyBytes    = 10 << 20
+)
+
+// Algorithms a v2 signature header names
+const (
+	AlgHMACSHA256 = "HMAC-SHA256"
+	AlgEd25519    = "Ed25519"
+)
+
+// Reasons a signature is rejected. Verify's errors match one of them with errors.Is.
+var (
+	ErrMissingHeaders    = errors.New("missing headers")
+	ErrInvalidTimestamp  = errors.New("invalid timestamp")
+	ErrStaleTimestamp    = errors.New("timestamp outside leeway")
+	ErrBadScheme         = errors.New("bad signature scheme")
+	ErrSignatureEncoding = errors.New("signature not hex")
+	ErrSignatureMismatch = errors.New("sig mismatch")
+	ErrUnknownKeyID      = errors.New("unknown kid")
+	ErrUnsupportedAlg    = errors.New("unsupported alg")
+	ErrNoPublicKeys      = errors.New("no signing keys configured")
+	ErrNoSecrets         = errors.New("no secrets or keys configured")
+	ErrBodyTooLarge      = errors.New("body too large")
+	ErrUnreadableBody    = errors.New("unreadable body")
+)
+
+// KeySource returns the Ed25519 public key a signature's kid names. KeySet fetches them from
+// the tenant's published JWK set; StaticKeys holds them in memory.
+type KeySource interface {
+	PublicKey(kid string) (ed25519.PublicKey, bool)
+}
+
+// StaticKeys is a fixed KeySource, by kid
+type StaticKeys map[string]ed25519.PublicKey
+
+func (k StaticKeys) PublicKey(kid string) (ed25519.PublicKey, bool) {
+	pub, ok := k[kid]
+	return pub, ok
+}
+
+// Verifier checks deliveries for one endpoint. The zero value of each field uses its default.
+type Verifier struct {
+	// Secrets are the endpoint's signing secrets. Hold the old and new secret while rotating:
+	// v2 signatures pick the one their kid names, v1 signatures are tried against each.
+	Secrets []string
+	// PublicKeys verifies ed25519 signatures; nil rejects them
+	PublicKeys KeySource
+	// Leeway is how far a delivery's timestamp may be from now, either way
+	Leeway time.Duration
+	// SignatureHeader and TimestampHeader name the headers, if harborhook is configured
+	// with other names (WEBHOOK_SIGNATURE_HEADER, WEBHOOK_TIMESTAMP_HEADER)
+	SignatureHeader string
+	TimestampHeader string
+	// MaxBodyBytes caps the body VerifyRequest reads, after decompression
+	MaxBodyBytes int64
+	// Now is the clock timestamps are checked against; nil uses time.Now
+	Now func() time.Time
+}
+
+// VerifyRequest verifies r with secret and the default settings. See Verifier.VerifyRequest.
+func VerifyRequest(secret string, r *http.Request) ([]byte, error) {
+	return (&Verifier{Secrets: []string{secret}}).VerifyRequest(r)
+}
+
+// VerifyRequest reads r's body, decompressing it if it was gzipped, and verifies its
+// signature. It returns the body, and replaces r.Body with it (dropping Content-Encoding) so
+// handlers can read it again.
+func (v *Verifier) VerifyRequest(r *http.Request) ([]byte, error) {
+	body, err := v.readBody(r)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.Header.Del("Content-Encoding")
+	return body, v.Verify(r.Method, r.URL.RequestURI(), body, r.Header.Get(v.timestampHeader()), r.Header.Get(v.signatureHeader()))
+}
+
+// Verify checks a signature header value against a request's method, target (its escaped path
+// and query, as in the request line), uncompressed body and timestamp header value
+func (v *Verifier) Verify(method, target string, body []byte, timestamp, signature string) error {
+	if len(v.Secrets) == 0 && v.PublicKeys == nil {
+		return ErrNoSecrets
+	}
+	if strings.HasPrefix(signature, "v2,") {
+		return v.verifyV2(method, target, body, signature)
+	}
+	if timestamp == "" || signature == "" {
+		return ErrMissingHeaders
+	}
+	if err := v.checkTimestamp(timestamp); err != nil {
+		return err
+	}
+	// expect "sha256=<hex>"
+	scheme, hexSig, ok := strings.Cut(signature, "=")
+	if !ok || scheme != "sha256" {
+		return ErrBadScheme
+	}
+	got, err := hex.DecodeString(hexSig)
+	if err != nil {
+		return ErrSignatureEncoding
+	}
+	for _, secret := range v.Secrets {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		mac.Write([]byte(timestamp))
+		if subtle.ConstantTimeCompare(got, mac.Sum(nil)) == 1 {
+			return nil
+		}
+	}
+	return ErrSignatureMismatch
+}
+
+// verifyV2 checks "v2,t=<ts>,kid=<id>,alg=<alg>,sig=<hex>" over
+// v2 \n ts \n METHOD \n target \n body. The timestamp comes from the header itself.
+func (v *Verifier) verifyV2(method, target string, body []byte, signature string) error {
+	fields := map[string]string{}
+	for _, kv := range strings.Split(signature, ",")[1:] {
+		if k, val, ok := strings.Cut(kv, "="); ok {
+			fields[k] = val
+		}
+	}
+	ts := fields["t"]
+	if err := v.checkTimestamp(ts); err != nil {
+		return err
+	}
+	got, err := hex.DecodeString(fields["sig"])
+	if err != nil || len(got) == 0 {
+		return ErrSignatureEncoding
+	}
+	signed := append([]byte("v2\n"+ts+"\n"+method+"\n"+target+"\n"), body...)
+
+	switch fields["alg"] {
+	case AlgHMACSHA256:
+		kid, matched := fields["kid"], false
+		for _, secret := range v.Secrets {
+			if kid != "" && kid != KeyID(secret) {
+				continue
+			}
+			matched = true
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(signed)
+			if subtle.ConstantTimeCompare(got, mac.Sum(nil)) == 1 {
+				return nil
+			}
+		}
+		if !matched {
+			return ErrUnknownKeyID
+		}
+		return ErrSignatureMismatch
+	case AlgEd25519:
+		if v.PublicKeys == nil {
+			return ErrNoPublicKeys
+		}
+		pub, ok := v.PublicKeys.PublicKey(fields["kid"])
+		if !ok {
+			return ErrUnknownKeyID
+		}
+		if !ed25519.Verify(pub, signed, got) {
+			return ErrSignatureMismatch
+		}
+		return nil
+	default:
+		return ErrUnsupportedAlg
+	}
+}
+
+// checkTimestamp rejects a timestamp (Unix seconds) further than the leeway from now
+func (v *Verifier) checkTimestamp(ts string) error {
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	now := time.Now
+	if v.Now != nil {
+		now = v.Now
+	}
+	leeway := v.Leeway
+	if leeway <= 0 {
+		leeway = DefaultLeeway
+	}
+	if abs64(now().Unix()-unix) > int64(leeway.Seconds()) {
+		return ErrStaleTimestamp
+	}
+	return nil
+}
+
+// abs64 returns the absolute value of an int64
+func abs64(x int64) int64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// readBody reads r's body, gunzipping it when the delivery was compressed, up to MaxBodyBytes
+func (v *Verifier) readBody(r *http.Request) ([]byte, error) {
+	limit := v.MaxBodyBytes
+	if limit <= 0 {
+		limit = DefaultMaxBodyBytes
+	}
+	var src io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrUnreadableBody, err)
+		}
+		defer zr.Close()
+		src = zr
+	}
+	body, err := io.ReadAll(io.LimitReader(src, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnreadableBody, err)
+	}
+	if int64(len(body)) > limit {
+		return nil, ErrBodyTooLarge
+	}
+	return body, nil
+}
+
+func (v *Verifier) signatureHeader() string {
+	if v.SignatureHeader != "" {
+		return v.SignatureHeader
+	}
+	return DefaultSignatureHeader
+}
+
+func (v *Verifier) timestampHeader() string {
+	if v.TimestampHeader != "" {
+		return v.TimestampHeader
+	}
+	return DefaultTimestampHeader
+}
+
+// KeyID is the kid a v2 HMAC signature names its secret by: the first 12 hex digits of the
+// secret's SHA-256. An Ed25519 key's kid is KeyID of its public key bytes.
+func KeyID(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,175 @@
+package webhookverify_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/austindbirch/harbor_hook/internal/delivery"
+	"github.com/austindbirch/harbor_hook/internal/delivery/signvectors"
+	"github.com/austindbirch/harbor_hook/pkg/webhookverify"
+)
+
+func TestVerifier_Vectors(t *testing.T) {
+	for _, vec := range signvectors.Load(t) {
+		t.Run(vec.Name, func(t *testing.T) {
+			unix, _ := strconv.ParseInt(vec.Timestamp, 10, 64)
+			v := &webhookverify.Verifier{
+				Secrets: []string{vec.Secret},
+				Now:     func() time.Time { return time.Unix(unix, 0).Add(time.Minute) },
+			}
+			if pub := vec.PublicKeyBytes(t); pub != nil {
+				v.PublicKeys = webhookverify.StaticKeys{webhookverify.KeyID(string(pub)): pub}
+			}
+
+			if err := v.Verify(vec.Method, vec.Path, []byte(vec.Payload), vec.Timestamp, vec.Signature); err != nil {
+				t.Errorf("Verify() rejected vector: %v", err)
+			}
+			if err := v.Verify(vec.Method, vec.Path, []byte(vec.Payload+" "), vec.Timestamp, vec.Signature); !errors.Is(err, webhookverify.ErrSignatureMismatch) {
+				t.Errorf("Verify(tampered body) = %v, want ErrSignatureMismatch", err)
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	body := []byte(`{"a":1}`)
+	v1 := delivery.Sign("new-secret", body, ts)
+	v2 := delivery.SignV2("new-secret", "POST", "/hook", body, ts)
+
+	tests := []struct {
+		name      string
+		secrets   []string
+		timestamp string
+		signature string
+		at        time.Time
+		want      error
+	}{
+		{name: "v1", secrets: []string{"new-secret"}, timestamp: ts, signature: v1},
+		{name: "v1 during rotation", secrets: []string{"old-secret", "new-secret"}, timestamp: ts, signature: v1},
+		{name: "v2 during rotation", secrets: []string{"old-secret", "new-secret"}, signature: v2},
+		{name: "v2 with a retired secret", secrets: []string{"old-secret"}, signature: v2, want: webhookverify.ErrUnknownKeyID},
+		{name: "wrong secret", secrets: []string{"other"}, timestamp: ts, signature: v1, want: webhookverify.ErrSignatureMismatch},
+		{name: "missing timestamp", secrets: []string{"new-secret"}, signature: v1, want: webhookverify.ErrMissingHeaders},
+		{name: "other scheme", secrets: []string{"new-secret"}, timestamp: ts, signature: strings.Replace(v1, "sha256", "sha1", 1), want: webhookverify.ErrBadScheme},
+		{name: "just inside the leeway", secrets: []string{"new-secret"}, timestamp: ts, signature: v1, at: now.Add(-5 * time.Minute)},
+		{name: "outside the leeway", secrets: []string{"new-secret"}, timestamp: ts, signature: v1, at: now.Add(5*time.Minute + time.Second), want: webhookverify.ErrStaleTimestamp},
+		{name: "unsupported alg", secrets: []string{"new-secret"}, signature: strings.Replace(v2, "HMAC-SHA256", "HMAC-MD5", 1), want: webhookverify.ErrUnsupportedAlg},
+		{name: "ed25519 without keys", secrets: []string{"new-secret"}, signature: strings.Replace(v2, "HMAC-SHA256", "Ed25519", 1), want: webhookverify.ErrNoPublicKeys},
+		{name: "nothing configured", timestamp: ts, signature: v1, want: webhookverify.ErrNoSecrets},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			at := now
+			if !tt.at.IsZero() {
+				at = tt.at
+			}
+			v := &webhookverify.Verifier{Secrets: tt.secrets, Now: func() time.Time { return at }}
+			if err := v.Verify("POST", "/hook", body, tt.timestamp, tt.signature); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// signedRequest is a delivery to /hook signed with secret, gzipped when compress is set
+func signedRequest(t *testing.T, secret string, body []byte, compress bool) *http.Request {
+	t.Helper()
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	wire := body
+	if compress {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		_, _ = zw.Write(body)
+		_ = zw.Close()
+		wire = buf.Bytes()
+	}
+	r := httptest.NewRequest(http.MethodPost, "/hook?src=hh", bytes.NewReader(wire))
+	r.Header.Set(webhookverify.DefaultTimestampHeader, ts)
+	r.Header.Set(webhookverify.DefaultSignatureHeader, delivery.SignV2(secret, http.MethodPost, "/hook?src=hh", body, ts))
+	if compress {
+		r.Header.Set("Content-Encoding", "gzip")
+	}
+	return r
+}
+
+func TestVerifyRequest(t *testing.T) {
+	body := []byte(`{"event_type":"order.created"}`)
+	for _, compress := range []bool{false, true} {
+		r := signedRequest(t, "whsec_1", body, compress)
+		got, err := webhookverify.VerifyRequest("whsec_1", r)
+		if err != nil || !bytes.Equal(got, body) {
+			t.Fatalf("VerifyRequest(gzip %v) = %q, %v, want the body", compress, got, err)
+		}
+		again, _ := io.ReadAll(r.Body)
+		if !bytes.Equal(again, body) || r.Header.Get("Content-Encoding") != "" {
+			t.Errorf("request body afterwards %q (encoding %q), want it readable and decompressed", again, r.Header.Get("Content-Encoding"))
+		}
+	}
+
+	v := &webhookverify.Verifier{Secrets: []string{"whsec_1"}, MaxBodyBytes: 8}
+	if _, err := v.VerifyRequest(signedRequest(t, "whsec_1", body, false)); !errors.Is(err, webhookverify.ErrBodyTooLarge) {
+		t.Errorf("VerifyRequest(oversized) = %v, want ErrBodyTooLarge", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var reached []byte
+	h := webhookverify.Middleware("whsec_1", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reached, _ = io.ReadAll(r.Body)
+	}))
+	body := []byte(`{"id":1}`)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "whsec_1", body, true))
+	if w.Code != http.StatusOK || !bytes.Equal(reached, body) {
+		t.Errorf("signed delivery: %d, handler read %q, want 200 and the body", w.Code, reached)
+	}
+
+	reached = nil
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, signedRequest(t, "other", body, false))
+	if w.Code != http.StatusUnauthorized || reached != nil || !strings.Contains(w.Body.String(), "unknown kid") {
+		t.Errorf("forged delivery: %d %q, handler reached %v, want 401 before the handler", w.Code, w.Body.String(), reached != nil)
+	}
+}
+
+func TestKeySet(t *testing.T) {
+	pub, key, _ := ed25519.GenerateKey(nil)
+	_, other, _ := ed25519.GenerateKey(nil)
+	var fetches int
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		fetches++
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{
+			{"kty": "OKP", "crv": "Ed25519", "kid": delivery.PublicKeyID(pub), "x": base64.RawURLEncoding.EncodeToString(pub)},
+			{"kty": "RSA", "kid": "rsa-1", "n": "AQAB"},
+		}})
+	}))
+	defer jwks.Close()
+
+	v := &webhookverify.Verifier{PublicKeys: webhookverify.NewKeySet(jwks.URL, jwks.Client())}
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	body := []byte(`{"a":1}`)
+	if err := v.Verify("POST", "/hook", body, "", delivery.SignEd25519(key, "POST", "/hook", body, ts)); err != nil {
+		t.Errorf("Verify(tenant key) = %v, want nil", err)
+	}
+	if err := v.Verify("POST", "/hook", body, "", delivery.SignEd25519(other, "POST", "/hook", body, ts)); !errors.Is(err, webhookverify.ErrUnknownKeyID) {
+		t.Errorf("Verify(other key) = %v, want ErrUnknownKeyID", err)
+	}
+	// The unknown kid right after the first fetch waits for KeySetRefetch
+	if fetches != 1 {
+		t.Errorf("fetched the key set %d times, want 1", fetches)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	structpb "google.golang.org/protobuf/types/known/structpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
@@ -26,6 +27,338 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// How webhook bodies are compressed. The signature always covers the uncompressed body
+type HealthCheckMethod int32
+
+const (
+	// Method is unspecified; checks use HEAD
+	HealthCheckMethod_HEALTH_CHECK_METHOD_UNSPECIFIED HealthCheckMethod = 0
+	// Checks are HEAD requests
+	HealthCheckMethod_HEALTH_CHECK_METHOD_HEAD HealthCheckMethod = 1
+	// Checks are GET requests, for receivers that don't answer HEAD
+	HealthCheckMethod_HEALTH_CHECK_METHOD_GET HealthCheckMethod = 2
+)
+
+// Enum value maps for HealthCheckMethod.
+var (
+	HealthCheckMethod_name = map[int32]string{
+		0: "HEALTH_CHECK_METHOD_UNSPECIFIED",
+		1: "HEALTH_CHECK_METHOD_HEAD",
+		2: "HEALTH_CHECK_METHOD_GET",
+	}
+	HealthCheckMethod_value = map[string]int32{
+		"HEALTH_CHECK_METHOD_UNSPECIFIED": 0,
+		"HEALTH_CHECK_METHOD_HEAD":        1,
+		"HEALTH_CHECK_METHOD_GET":         2,
+	}
+)
+
+func (x HealthCheckMethod) Enum() *HealthCheckMethod {
+	p := new(HealthCheckMethod)
+	*p = x
+	return p
+}
+
+func (x HealthCheckMethod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HealthCheckMethod) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_webhook_v1_service_proto_enumTypes[0].Descriptor()
+}
+
+func (HealthCheckMethod) Type() protoreflect.EnumType {
+	return &file_api_webhook_v1_service_proto_enumTypes[0]
+}
+
+func (x HealthCheckMethod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HealthCheckMethod.Descriptor instead.
+func (HealthCheckMethod) EnumDescriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{0}
+}
+
+type EndpointHealthStatus int32
+
+const (
+	// Health is unspecified
+	EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_UNSPECIFIED EndpointHealthStatus = 0
+	// Not checked successfully yet
+	EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_UNKNOWN EndpointHealthStatus = 1
+	// Answering its health checks
+	EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_HEALTHY EndpointHealthStatus = 2
+	// Failing its health checks; deliveries are held
+	EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_UNHEALTHY EndpointHealthStatus = 3
+)
+
+// Enum value maps for EndpointHealthStatus.
+var (
+	EndpointHealthStatus_name = map[int32]string{
+		0: "ENDPOINT_HEALTH_STATUS_UNSPECIFIED",
+		1: "ENDPOINT_HEALTH_STATUS_UNKNOWN",
+		2: "ENDPOINT_HEALTH_STATUS_HEALTHY",
+		3: "ENDPOINT_HEALTH_STATUS_UNHEALTHY",
+	}
+	EndpointHealthStatus_value = map[string]int32{
+		"ENDPOINT_HEALTH_STATUS_UNSPECIFIED": 0,
+		"ENDPOINT_HEALTH_STATUS_UNKNOWN":     1,
+		"ENDPOINT_HEALTH_STATUS_HEALTHY":     2,
+		"ENDPOINT_HEALTH_STATUS_UNHEALTHY":   3,
+	}
+)
+
+func (x EndpointHealthStatus) Enum() *EndpointHealthStatus {
+	p := new(EndpointHealthStatus)
+	*p = x
+	return p
+}
+
+func (x EndpointHealthStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EndpointHealthStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_webhook_v1_service_proto_enumTypes[1].Descriptor()
+}
+
+func (EndpointHealthStatus) Type() protoreflect.EnumType {
+	return &file_api_webhook_v1_service_proto_enumTypes[1]
+}
+
+func (x EndpointHealthStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EndpointHealthStatus.Descriptor instead.
+func (EndpointHealthStatus) EnumDescriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{1}
+}
+
+type PayloadCompression int32
+
+const (
+	// Compression is unspecified; bodies are sent uncompressed
+	PayloadCompression_PAYLOAD_COMPRESSION_UNSPECIFIED PayloadCompression = 0
+	// Bodies are sent uncompressed
+	PayloadCompression_PAYLOAD_COMPRESSION_NONE PayloadCompression = 1
+	// Bodies of 1 KiB and more are sent with Content-Encoding: gzip
+	PayloadCompression_PAYLOAD_COMPRESSION_GZIP PayloadCompression = 2
+)
+
+// Enum value maps for PayloadCompression.
+var (
+	PayloadCompression_name = map[int32]string{
+		0: "PAYLOAD_COMPRESSION_UNSPECIFIED",
+		1: "PAYLOAD_COMPRESSION_NONE",
+		2: "PAYLOAD_COMPRESSION_GZIP",
+	}
+	PayloadCompression_value = map[string]int32{
+		"PAYLOAD_COMPRESSION_UNSPECIFIED": 0,
+		"PAYLOAD_COMPRESSION_NONE":        1,
+		"PAYLOAD_COMPRESSION_GZIP":        2,
+	}
+)
+
+func (x PayloadCompression) Enum() *PayloadCompression {
+	p := new(PayloadCompression)
+	*p = x
+	return p
+}
+
+func (x PayloadCompression) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PayloadCompression) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_webhook_v1_service_proto_enumTypes[2].Descriptor()
+}
+
+func (PayloadCompression) Type() protoreflect.EnumType {
+	return &file_api_webhook_v1_service_proto_enumTypes[2]
+}
+
+func (x PayloadCompression) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PayloadCompression.Descriptor instead.
+func (PayloadCompression) EnumDescriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{2}
+}
+
+// Where an endpoint's deliveries go
+type EndpointType int32
+
+const (
+	// Type is unspecified; deliveries are sent over HTTP
+	EndpointType_ENDPOINT_TYPE_UNSPECIFIED EndpointType = 0
+	// Deliveries are sent over HTTP to the endpoint's URL
+	EndpointType_ENDPOINT_TYPE_HTTP EndpointType = 1
+	// Deliveries are signed as usual but stored instead of sent, for GetCapturedDeliveries, so a
+	// tenant can integrate before standing up a receiver
+	EndpointType_ENDPOINT_TYPE_CAPTURE EndpointType = 2
+)
+
+// Enum value maps for EndpointType.
+var (
+	EndpointType_name = map[int32]string{
+		0: "ENDPOINT_TYPE_UNSPECIFIED",
+		1: "ENDPOINT_TYPE_HTTP",
+		2: "ENDPOINT_TYPE_CAPTURE",
+	}
+	EndpointType_value = map[string]int32{
+		"ENDPOINT_TYPE_UNSPECIFIED": 0,
+		"ENDPOINT_TYPE_HTTP":        1,
+		"ENDPOINT_TYPE_CAPTURE":     2,
+	}
+)
+
+func (x EndpointType) Enum() *EndpointType {
+	p := new(EndpointType)
+	*p = x
+	return p
+}
+
+func (x EndpointType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EndpointType) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_webhook_v1_service_proto_enumTypes[3].Descriptor()
+}
+
+func (EndpointType) Type() protoreflect.EnumType {
+	return &file_api_webhook_v1_service_proto_enumTypes[3]
+}
+
+func (x EndpointType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EndpointType.Descriptor instead.
+func (EndpointType) EnumDescriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{3}
+}
+
+// How webhooks are signed with the endpoint's secret. Both schemes send the timestamp header too
+type SignatureScheme int32
+
+const (
+	// Scheme is unspecified; webhooks are signed with v1
+	SignatureScheme_SIGNATURE_SCHEME_UNSPECIFIED SignatureScheme = 0
+	// sha256=hex(HMAC-SHA256(secret, body || timestamp))
+	SignatureScheme_SIGNATURE_SCHEME_V1 SignatureScheme = 1
+	// v2,t=<ts>,kid=<key id>,alg=HMAC-SHA256,sig=<hex>, signing "v2\n<ts>\n<METHOD>\n<path?query>\n<body>".
+	// kid is the first 12 hex digits of SHA-256(secret), so receivers can hold several secrets
+	SignatureScheme_SIGNATURE_SCHEME_V2 SignatureScheme = 2
+	// The v2 format with alg=Ed25519 and sig the hex Ed25519 signature, made with the tenant's
+	// signing key; receivers verify with the public key kid names in GetSigningKeys
+	SignatureScheme_SIGNATURE_SCHEME_ED25519 SignatureScheme = 3
+)
+
+// Enum value maps for SignatureScheme.
+var (
+	SignatureScheme_name = map[int32]string{
+		0: "SIGNATURE_SCHEME_UNSPECIFIED",
+		1: "SIGNATURE_SCHEME_V1",
+		2: "SIGNATURE_SCHEME_V2",
+		3: "SIGNATURE_SCHEME_ED25519",
+	}
+	SignatureScheme_value = map[string]int32{
+		"SIGNATURE_SCHEME_UNSPECIFIED": 0,
+		"SIGNATURE_SCHEME_V1":          1,
+		"SIGNATURE_SCHEME_V2":          2,
+		"SIGNATURE_SCHEME_ED25519":     3,
+	}
+)
+
+func (x SignatureScheme) Enum() *SignatureScheme {
+	p := new(SignatureScheme)
+	*p = x
+	return p
+}
+
+func (x SignatureScheme) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SignatureScheme) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_webhook_v1_service_proto_enumTypes[4].Descriptor()
+}
+
+func (SignatureScheme) Type() protoreflect.EnumType {
+	return &file_api_webhook_v1_service_proto_enumTypes[4]
+}
+
+func (x SignatureScheme) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SignatureScheme.Descriptor instead.
+func (SignatureScheme) EnumDescriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{4}
+}
+
+// Which delivery topic an event's deliveries go through, so urgent events aren't queued behind
+// a bulk backfill. Workers split their in-flight capacity between the topics by weight
+type EventPriority int32
+
+const (
+	// Priority is unspecified; the event is normal priority
+	EventPriority_EVENT_PRIORITY_UNSPECIFIED EventPriority = 0
+	// Deliveries go through the high priority topic
+	EventPriority_EVENT_PRIORITY_HIGH EventPriority = 1
+	// Deliveries go through the normal priority topic
+	EventPriority_EVENT_PRIORITY_NORMAL EventPriority = 2
+	// Deliveries go through the low priority topic, e.g. for backfills
+	EventPriority_EVENT_PRIORITY_LOW EventPriority = 3
+)
+
+// Enum value maps for EventPriority.
+var (
+	EventPriority_name = map[int32]string{
+		0: "EVENT_PRIORITY_UNSPECIFIED",
+		1: "EVENT_PRIORITY_HIGH",
+		2: "EVENT_PRIORITY_NORMAL",
+		3: "EVENT_PRIORITY_LOW",
+	}
+	EventPriority_value = map[string]int32{
+		"EVENT_PRIORITY_UNSPECIFIED": 0,
+		"EVENT_PRIORITY_HIGH":        1,
+		"EVENT_PRIORITY_NORMAL":      2,
+		"EVENT_PRIORITY_LOW":         3,
+	}
+)
+
+func (x EventPriority) Enum() *EventPriority {
+	p := new(EventPriority)
+	*p = x
+	return p
+}
+
+func (x EventPriority) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventPriority) Descriptor() protoreflect.EnumDescriptor {
+	return file_api_webhook_v1_service_proto_enumTypes[5].Descriptor()
+}
+
+func (EventPriority) Type() protoreflect.EnumType {
+	return &file_api_webhook_v1_service_proto_enumTypes[5]
+}
+
+func (x EventPriority) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventPriority.Descriptor instead.
+func (EventPriority) EnumDescriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{5}
+}
+
 type DeliveryAttemptStatus int32
 
 const (
@@ -41,6 +374,8 @@ const (
 	DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_FAILED DeliveryAttemptStatus = 4
 	// Delivery attempt was dead-lettered
 	DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED DeliveryAttemptStatus = 5
+	// Delivery attempt is parked by a freeze or drain
+	DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_PARKED DeliveryAttemptStatus = 6
 )
 
 // Enum value maps for DeliveryAttemptStatus.
@@ -52,6 +387,7 @@ var (
 		3: "DELIVERY_ATTEMPT_STATUS_DELIVERED",
 		4: "DELIVERY_ATTEMPT_STATUS_FAILED",
 		5: "DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED",
+		6: "DELIVERY_ATTEMPT_STATUS_PARKED",
 	}
 	DeliveryAttemptStatus_value = map[string]int32{
 		"DELIVERY_ATTEMPT_STATUS_UNSPECIFIED":   0,
@@ -60,6 +396,7 @@ var (
 		"DELIVERY_ATTEMPT_STATUS_DELIVERED":     3,
 		"DELIVERY_ATTEMPT_STATUS_FAILED":        4,
 		"DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED": 5,
+		"DELIVERY_ATTEMPT_STATUS_PARKED":        6,
 	}
 )
 
@@ -74,11 +411,11 @@ func (x DeliveryAttemptStatus) String() string {
 }
 
 func (DeliveryAttemptStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_api_webhook_v1_service_proto_enumTypes[0].Descriptor()
+	return file_api_webhook_v1_service_proto_enumTypes[6].Descriptor()
 }
 
 func (DeliveryAttemptStatus) Type() protoreflect.EnumType {
-	return &file_api_webhook_v1_service_proto_enumTypes[0]
+	return &file_api_webhook_v1_service_proto_enumTypes[6]
 }
 
 func (x DeliveryAttemptStatus) Number() protoreflect.EnumNumber {
@@ -87,7 +424,7 @@ func (x DeliveryAttemptStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use DeliveryAttemptStatus.Descriptor instead.
 func (DeliveryAttemptStatus) EnumDescriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{0}
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{6}
 }
 
 type PingRequest struct {
@@ -180,9 +517,39 @@ type Endpoint struct {
 	// Target URL that we will send events to
 	Url string `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
 	// Created at timestamp (must be after 2025-01-01 00:00:00 UTC)
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// How delivery ramps back up after the endpoint recovers
+	RecoveryRamp *RecoveryRamp `protobuf:"bytes,5,opt,name=recovery_ramp,json=recoveryRamp,proto3" json:"recovery_ramp,omitempty"`
+	// How failed deliveries to the endpoint are retried
+	RetryPolicy *RetryPolicy `protobuf:"bytes,6,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	// When the endpoint answered its verification challenge. Unverified endpoints get no deliveries
+	VerifiedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=verified_at,json=verifiedAt,proto3" json:"verified_at,omitempty"`
+	// Client certificate presented to the endpoint for mutual TLS; unset when there is none
+	ClientCertificate *ClientCertificate `protobuf:"bytes,8,opt,name=client_certificate,json=clientCertificate,proto3" json:"client_certificate,omitempty"`
+	// How webhook bodies are compressed on the way to the endpoint
+	Compression PayloadCompression `protobuf:"varint,9,opt,name=compression,proto3,enum=api.webhook.v1.PayloadCompression" json:"compression,omitempty"`
+	// Ordered delivery settings; unset when deliveries are sent as they come
+	Ordering *DeliveryOrdering `protobuf:"bytes,10,opt,name=ordering,proto3" json:"ordering,omitempty"`
+	// How webhooks sent to the endpoint are signed
+	SignatureScheme SignatureScheme `protobuf:"varint,11,opt,name=signature_scheme,json=signatureScheme,proto3,enum=api.webhook.v1.SignatureScheme" json:"signature_scheme,omitempty"`
+	// Request timeout in milliseconds; 0 uses the worker default
+	TimeoutMs int32 `protobuf:"varint,12,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// Whether deliveries are sent over HTTP or captured for GetCapturedDeliveries
+	Type EndpointType `protobuf:"varint,13,opt,name=type,proto3,enum=api.webhook.v1.EndpointType" json:"type,omitempty"`
+	// HTTP proxy deliveries to the endpoint go through, with any password redacted; "direct"
+	// bypasses the egress proxy, and empty uses it
+	ProxyUrl string `protobuf:"bytes,14,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+	// Health check settings; unset when the endpoint isn't checked
+	HealthCheck *EndpointHealthCheck `protobuf:"bytes,15,opt,name=health_check,json=healthCheck,proto3" json:"health_check,omitempty"`
+	// What the endpoint's health checks found; unset until it has been checked
+	Health *EndpointHealth `protobuf:"bytes,16,opt,name=health,proto3" json:"health,omitempty"`
+	// When the endpoint was disabled after failing for too long; disabled endpoints get no
+	// deliveries until they are enabled again
+	DisabledAt *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=disabled_at,json=disabledAt,proto3" json:"disabled_at,omitempty"`
+	// Why the endpoint was disabled
+	DisabledReason string `protobuf:"bytes,18,opt,name=disabled_reason,json=disabledReason,proto3" json:"disabled_reason,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *Endpoint) Reset() {
@@ -243,115 +610,187 @@ func (x *Endpoint) GetCreatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-// A subscription is a relationship between an endpoint and an event type
-type Subscription struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Unique ID for the subscription
-	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	// ID for the tenant
-	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	// Event type that this subscription is for
-	EventType string `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
-	// Endpoint ID that this subscription is a member of
-	EndpointId string `protobuf:"bytes,4,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
-	// Created at timestamp (must be after 2025-01-01 00:00:00 UTC)
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *Endpoint) GetRecoveryRamp() *RecoveryRamp {
+	if x != nil {
+		return x.RecoveryRamp
+	}
+	return nil
 }
 
-func (x *Subscription) Reset() {
-	*x = Subscription{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[3]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *Endpoint) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
 }
 
-func (x *Subscription) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *Endpoint) GetVerifiedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.VerifiedAt
+	}
+	return nil
 }
 
-func (*Subscription) ProtoMessage() {}
+func (x *Endpoint) GetClientCertificate() *ClientCertificate {
+	if x != nil {
+		return x.ClientCertificate
+	}
+	return nil
+}
 
-func (x *Subscription) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[3]
+func (x *Endpoint) GetCompression() PayloadCompression {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Compression
 	}
-	return mi.MessageOf(x)
+	return PayloadCompression_PAYLOAD_COMPRESSION_UNSPECIFIED
 }
 
-// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
-func (*Subscription) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{3}
+func (x *Endpoint) GetOrdering() *DeliveryOrdering {
+	if x != nil {
+		return x.Ordering
+	}
+	return nil
 }
 
-func (x *Subscription) GetId() string {
+func (x *Endpoint) GetSignatureScheme() SignatureScheme {
 	if x != nil {
-		return x.Id
+		return x.SignatureScheme
 	}
-	return ""
+	return SignatureScheme_SIGNATURE_SCHEME_UNSPECIFIED
 }
 
-func (x *Subscription) GetTenantId() string {
+func (x *Endpoint) GetTimeoutMs() int32 {
 	if x != nil {
-		return x.TenantId
+		return x.TimeoutMs
 	}
-	return ""
+	return 0
 }
 
-func (x *Subscription) GetEventType() string {
+func (x *Endpoint) GetType() EndpointType {
 	if x != nil {
-		return x.EventType
+		return x.Type
 	}
-	return ""
+	return EndpointType_ENDPOINT_TYPE_UNSPECIFIED
 }
 
-func (x *Subscription) GetEndpointId() string {
+func (x *Endpoint) GetProxyUrl() string {
 	if x != nil {
-		return x.EndpointId
+		return x.ProxyUrl
 	}
 	return ""
 }
 
-func (x *Subscription) GetCreatedAt() *timestamppb.Timestamp {
+func (x *Endpoint) GetHealthCheck() *EndpointHealthCheck {
 	if x != nil {
-		return x.CreatedAt
+		return x.HealthCheck
 	}
 	return nil
 }
 
-// Create endpoint request message
-type CreateEndpointRequest struct {
+func (x *Endpoint) GetHealth() *EndpointHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+func (x *Endpoint) GetDisabledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisabledAt
+	}
+	return nil
+}
+
+func (x *Endpoint) GetDisabledReason() string {
+	if x != nil {
+		return x.DisabledReason
+	}
+	return ""
+}
+
+// Health checks: workers send a signed request with no body and an X-Harborhook-Health-Check
+// header to the endpoint's URL every HEALTH_CHECK_INTERVAL
+type EndpointHealthCheck struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// ID for the tenant
-	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	// Target URL that we will send events to, e.g. http://fake-receiver:8081/hook
-	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	// Optional secret. If empty, server generates a secret for you
-	Secret        string `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	// HTTP method of the check
+	Method        HealthCheckMethod `protobuf:"varint,1,opt,name=method,proto3,enum=api.webhook.v1.HealthCheckMethod" json:"method,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateEndpointRequest) Reset() {
-	*x = CreateEndpointRequest{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[4]
+func (x *EndpointHealthCheck) Reset() {
+	*x = EndpointHealthCheck{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateEndpointRequest) String() string {
+func (x *EndpointHealthCheck) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateEndpointRequest) ProtoMessage() {}
+func (*EndpointHealthCheck) ProtoMessage() {}
 
-func (x *CreateEndpointRequest) ProtoReflect() protoreflect.Message {
+func (x *EndpointHealthCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EndpointHealthCheck.ProtoReflect.Descriptor instead.
+func (*EndpointHealthCheck) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *EndpointHealthCheck) GetMethod() HealthCheckMethod {
+	if x != nil {
+		return x.Method
+	}
+	return HealthCheckMethod_HEALTH_CHECK_METHOD_UNSPECIFIED
+}
+
+// An endpoint's health as its checks found it. Deliveries to an unhealthy endpoint are held
+// without spending attempts, and resume through its recovery ramp once it is healthy again
+type EndpointHealth struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Current health
+	Status EndpointHealthStatus `protobuf:"varint,1,opt,name=status,proto3,enum=api.webhook.v1.EndpointHealthStatus" json:"status,omitempty"`
+	// When the endpoint was last checked
+	LastCheckedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=last_checked_at,json=lastCheckedAt,proto3" json:"last_checked_at,omitempty"`
+	// Status code of the last check; 0 when it got no response
+	LastStatusCode int32 `protobuf:"varint,3,opt,name=last_status_code,json=lastStatusCode,proto3" json:"last_status_code,omitempty"`
+	// Why the last check failed, when it got no response
+	LastError string `protobuf:"bytes,4,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	// How long the last check took, in milliseconds
+	LastLatencyMs int32 `protobuf:"varint,5,opt,name=last_latency_ms,json=lastLatencyMs,proto3" json:"last_latency_ms,omitempty"`
+	// Failed checks in a row
+	ConsecutiveFailures int32 `protobuf:"varint,6,opt,name=consecutive_failures,json=consecutiveFailures,proto3" json:"consecutive_failures,omitempty"`
+	// When the status last changed
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EndpointHealth) Reset() {
+	*x = EndpointHealth{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EndpointHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EndpointHealth) ProtoMessage() {}
+
+func (x *EndpointHealth) ProtoReflect() protoreflect.Message {
 	mi := &file_api_webhook_v1_service_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -363,56 +802,8262 @@ func (x *CreateEndpointRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateEndpointRequest.ProtoReflect.Descriptor instead.
-func (*CreateEndpointRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use EndpointHealth.ProtoReflect.Descriptor instead.
+func (*EndpointHealth) Descriptor() ([]byte, []int) {
 	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *CreateEndpointRequest) GetTenantId() string {
+func (x *EndpointHealth) GetStatus() EndpointHealthStatus {
 	if x != nil {
-		return x.TenantId
+		return x.Status
+	}
+	return EndpointHealthStatus_ENDPOINT_HEALTH_STATUS_UNSPECIFIED
+}
+
+func (x *EndpointHealth) GetLastCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastCheckedAt
+	}
+	return nil
+}
+
+func (x *EndpointHealth) GetLastStatusCode() int32 {
+	if x != nil {
+		return x.LastStatusCode
+	}
+	return 0
+}
+
+func (x *EndpointHealth) GetLastError() string {
+	if x != nil {
+		return x.LastError
 	}
 	return ""
 }
 
-func (x *CreateEndpointRequest) GetUrl() string {
+func (x *EndpointHealth) GetLastLatencyMs() int32 {
 	if x != nil {
-		return x.Url
+		return x.LastLatencyMs
+	}
+	return 0
+}
+
+func (x *EndpointHealth) GetConsecutiveFailures() int32 {
+	if x != nil {
+		return x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *EndpointHealth) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
+// Ordered delivery: an endpoint's deliveries are sent one at a time per partition, in the order
+// their events were published, and later events wait while an earlier one is retried
+type DeliveryOrdering struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Dot-notation path into the payload whose value partitions deliveries (e.g. "order.id").
+	// Empty puts every delivery to the endpoint in one partition
+	PartitionKey  string `protobuf:"bytes,1,opt,name=partition_key,json=partitionKey,proto3" json:"partition_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryOrdering) Reset() {
+	*x = DeliveryOrdering{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryOrdering) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryOrdering) ProtoMessage() {}
+
+func (x *DeliveryOrdering) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryOrdering.ProtoReflect.Descriptor instead.
+func (*DeliveryOrdering) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeliveryOrdering) GetPartitionKey() string {
+	if x != nil {
+		return x.PartitionKey
 	}
 	return ""
 }
 
-func (x *CreateEndpointRequest) GetSecret() string {
+// Delivery rate steps applied after an endpoint recovers (e.g. a freeze is lifted).
+// Each step admits a percentage of tasks for step_seconds, then full rate resumes.
+type RecoveryRamp struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Percentage of tasks admitted in each step, non-decreasing (e.g. 10, 50, 100)
+	Percents []int32 `protobuf:"varint,1,rep,packed,name=percents,proto3" json:"percents,omitempty"`
+	// Length of each step in seconds. 0 disables the ramp
+	StepSeconds   int32 `protobuf:"varint,2,opt,name=step_seconds,json=stepSeconds,proto3" json:"step_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RecoveryRamp) Reset() {
+	*x = RecoveryRamp{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecoveryRamp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecoveryRamp) ProtoMessage() {}
+
+func (x *RecoveryRamp) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[6]
 	if x != nil {
-		return x.Secret
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecoveryRamp.ProtoReflect.Descriptor instead.
+func (*RecoveryRamp) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RecoveryRamp) GetPercents() []int32 {
+	if x != nil {
+		return x.Percents
+	}
+	return nil
+}
+
+func (x *RecoveryRamp) GetStepSeconds() int32 {
+	if x != nil {
+		return x.StepSeconds
+	}
+	return 0
+}
+
+// Per-endpoint override of the worker's global retry settings. Unset fields use the globals.
+type RetryPolicy struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Attempts before a delivery is dead-lettered. 0 uses the worker's MAX_ATTEMPTS
+	MaxAttempts int32 `protobuf:"varint,1,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	// Delay in seconds before each retry; the last step repeats. Empty uses BACKOFF_SCHEDULE
+	BackoffSeconds []int32 `protobuf:"varint,2,rep,packed,name=backoff_seconds,json=backoffSeconds,proto3" json:"backoff_seconds,omitempty"`
+	// Failure classes to retry (timeout, connection_refused, dns_error, network, http_5xx,
+	// http_429, http_4xx, other). Other failures are dead-lettered at once. Empty retries all
+	RetryOn       []string `protobuf:"bytes,3,rep,name=retry_on,json=retryOn,proto3" json:"retry_on,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryPolicy) Reset() {
+	*x = RetryPolicy{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPolicy) ProtoMessage() {}
+
+func (x *RetryPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPolicy.ProtoReflect.Descriptor instead.
+func (*RetryPolicy) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RetryPolicy) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *RetryPolicy) GetBackoffSeconds() []int32 {
+	if x != nil {
+		return x.BackoffSeconds
+	}
+	return nil
+}
+
+func (x *RetryPolicy) GetRetryOn() []string {
+	if x != nil {
+		return x.RetryOn
+	}
+	return nil
+}
+
+// The client certificate an endpoint is delivered to with. Uploaded certificates are described by
+// their subject and expiry; their key is never returned
+type ClientCertificate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Name of a secret mounted into the workers that holds tls.crt and tls.key
+	SecretName string `protobuf:"bytes,1,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	// Subject of the uploaded certificate
+	Subject string `protobuf:"bytes,2,opt,name=subject,proto3" json:"subject,omitempty"`
+	// When the uploaded certificate expires
+	NotAfter      *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=not_after,json=notAfter,proto3" json:"not_after,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClientCertificate) Reset() {
+	*x = ClientCertificate{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClientCertificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientCertificate) ProtoMessage() {}
+
+func (x *ClientCertificate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientCertificate.ProtoReflect.Descriptor instead.
+func (*ClientCertificate) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ClientCertificate) GetSecretName() string {
+	if x != nil {
+		return x.SecretName
 	}
 	return ""
 }
 
-// Create endpoint response message
-type CreateEndpointResponse struct {
+func (x *ClientCertificate) GetSubject() string {
+	if x != nil {
+		return x.Subject
+	}
+	return ""
+}
+
+func (x *ClientCertificate) GetNotAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NotAfter
+	}
+	return nil
+}
+
+// A subscription is a relationship between an endpoint and an event type
+type Subscription struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique ID for the subscription
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Event type that this subscription is for
+	EventType string `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Endpoint ID that this subscription is a member of
+	EndpointId string `protobuf:"bytes,4,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Created at timestamp (must be after 2025-01-01 00:00:00 UTC)
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Payload fields (dot paths) delivered to the endpoint. Empty means the full payload
+	IncludeFields []string `protobuf:"bytes,6,rep,name=include_fields,json=includeFields,proto3" json:"include_fields,omitempty"`
+	// Payload fields (dot paths) stripped before delivery, applied after include_fields
+	ExcludeFields []string `protobuf:"bytes,7,rep,name=exclude_fields,json=excludeFields,proto3" json:"exclude_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Subscription) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Subscription) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Subscription) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *Subscription) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *Subscription) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Subscription) GetIncludeFields() []string {
+	if x != nil {
+		return x.IncludeFields
+	}
+	return nil
+}
+
+func (x *Subscription) GetExcludeFields() []string {
+	if x != nil {
+		return x.ExcludeFields
+	}
+	return nil
+}
+
+// Create endpoint request message
+type CreateEndpointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Target URL that we will send events to, e.g. http://fake-receiver:8081/hook. Required unless
+	// type is capture, which takes no URL
+	Url string `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	// Optional secret. If empty, server generates a secret for you
+	Secret string `protobuf:"bytes,3,opt,name=secret,proto3" json:"secret,omitempty"`
+	// Optional recovery ramp. If empty, the default ramp (10%, 50%, 100% for 60s each) is used
+	RecoveryRamp *RecoveryRamp `protobuf:"bytes,4,opt,name=recovery_ramp,json=recoveryRamp,proto3" json:"recovery_ramp,omitempty"`
+	// Optional retry policy. If empty, the worker's global retry settings apply
+	RetryPolicy *RetryPolicy `protobuf:"bytes,5,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	// Optional body compression. If unspecified, bodies are sent uncompressed
+	Compression PayloadCompression `protobuf:"varint,6,opt,name=compression,proto3,enum=api.webhook.v1.PayloadCompression" json:"compression,omitempty"`
+	// Optional signature scheme. If unspecified, webhooks are signed with v1
+	SignatureScheme SignatureScheme `protobuf:"varint,7,opt,name=signature_scheme,json=signatureScheme,proto3,enum=api.webhook.v1.SignatureScheme" json:"signature_scheme,omitempty"`
+	// Optional request timeout in milliseconds. If 0, the worker default applies
+	TimeoutMs int32 `protobuf:"varint,8,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	// Optional endpoint type. If unspecified, deliveries are sent over HTTP
+	Type          EndpointType `protobuf:"varint,9,opt,name=type,proto3,enum=api.webhook.v1.EndpointType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEndpointRequest) Reset() {
+	*x = CreateEndpointRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEndpointRequest) ProtoMessage() {}
+
+func (x *CreateEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEndpointRequest.ProtoReflect.Descriptor instead.
+func (*CreateEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateEndpointRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *CreateEndpointRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateEndpointRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *CreateEndpointRequest) GetRecoveryRamp() *RecoveryRamp {
+	if x != nil {
+		return x.RecoveryRamp
+	}
+	return nil
+}
+
+func (x *CreateEndpointRequest) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+func (x *CreateEndpointRequest) GetCompression() PayloadCompression {
+	if x != nil {
+		return x.Compression
+	}
+	return PayloadCompression_PAYLOAD_COMPRESSION_UNSPECIFIED
+}
+
+func (x *CreateEndpointRequest) GetSignatureScheme() SignatureScheme {
+	if x != nil {
+		return x.SignatureScheme
+	}
+	return SignatureScheme_SIGNATURE_SCHEME_UNSPECIFIED
+}
+
+func (x *CreateEndpointRequest) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+func (x *CreateEndpointRequest) GetType() EndpointType {
+	if x != nil {
+		return x.Type
+	}
+	return EndpointType_ENDPOINT_TYPE_UNSPECIFIED
+}
+
+type SetEndpointRecoveryRampRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// The ramp to apply
+	RecoveryRamp  *RecoveryRamp `protobuf:"bytes,3,opt,name=recovery_ramp,json=recoveryRamp,proto3" json:"recovery_ramp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointRecoveryRampRequest) Reset() {
+	*x = SetEndpointRecoveryRampRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointRecoveryRampRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointRecoveryRampRequest) ProtoMessage() {}
+
+func (x *SetEndpointRecoveryRampRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointRecoveryRampRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointRecoveryRampRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SetEndpointRecoveryRampRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointRecoveryRampRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointRecoveryRampRequest) GetRecoveryRamp() *RecoveryRamp {
+	if x != nil {
+		return x.RecoveryRamp
+	}
+	return nil
+}
+
+type SetEndpointRecoveryRampResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointRecoveryRampResponse) Reset() {
+	*x = SetEndpointRecoveryRampResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointRecoveryRampResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointRecoveryRampResponse) ProtoMessage() {}
+
+func (x *SetEndpointRecoveryRampResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointRecoveryRampResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointRecoveryRampResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetEndpointRecoveryRampResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type SetEndpointRetryPolicyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// The policy to apply; an empty policy restores the global settings
+	RetryPolicy   *RetryPolicy `protobuf:"bytes,3,opt,name=retry_policy,json=retryPolicy,proto3" json:"retry_policy,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointRetryPolicyRequest) Reset() {
+	*x = SetEndpointRetryPolicyRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointRetryPolicyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointRetryPolicyRequest) ProtoMessage() {}
+
+func (x *SetEndpointRetryPolicyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointRetryPolicyRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointRetryPolicyRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SetEndpointRetryPolicyRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointRetryPolicyRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointRetryPolicyRequest) GetRetryPolicy() *RetryPolicy {
+	if x != nil {
+		return x.RetryPolicy
+	}
+	return nil
+}
+
+type SetEndpointRetryPolicyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointRetryPolicyResponse) Reset() {
+	*x = SetEndpointRetryPolicyResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointRetryPolicyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointRetryPolicyResponse) ProtoMessage() {}
+
+func (x *SetEndpointRetryPolicyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointRetryPolicyResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointRetryPolicyResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *SetEndpointRetryPolicyResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type SetEndpointClientCertificateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// PEM certificate chain to present, with key_pem
+	CertPem string `protobuf:"bytes,3,opt,name=cert_pem,json=certPem,proto3" json:"cert_pem,omitempty"`
+	// PEM private key of cert_pem
+	KeyPem string `protobuf:"bytes,4,opt,name=key_pem,json=keyPem,proto3" json:"key_pem,omitempty"`
+	// Instead of uploading a certificate, use this secret mounted into the workers.
+	// Leaving all fields empty removes the endpoint's client certificate
+	SecretName    string `protobuf:"bytes,5,opt,name=secret_name,json=secretName,proto3" json:"secret_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointClientCertificateRequest) Reset() {
+	*x = SetEndpointClientCertificateRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointClientCertificateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointClientCertificateRequest) ProtoMessage() {}
+
+func (x *SetEndpointClientCertificateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointClientCertificateRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointClientCertificateRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *SetEndpointClientCertificateRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointClientCertificateRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointClientCertificateRequest) GetCertPem() string {
+	if x != nil {
+		return x.CertPem
+	}
+	return ""
+}
+
+func (x *SetEndpointClientCertificateRequest) GetKeyPem() string {
+	if x != nil {
+		return x.KeyPem
+	}
+	return ""
+}
+
+func (x *SetEndpointClientCertificateRequest) GetSecretName() string {
+	if x != nil {
+		return x.SecretName
+	}
+	return ""
+}
+
+type SetEndpointClientCertificateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointClientCertificateResponse) Reset() {
+	*x = SetEndpointClientCertificateResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointClientCertificateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointClientCertificateResponse) ProtoMessage() {}
+
+func (x *SetEndpointClientCertificateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointClientCertificateResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointClientCertificateResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SetEndpointClientCertificateResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type SetEndpointCompressionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// The compression to apply; unspecified sends bodies uncompressed
+	Compression   PayloadCompression `protobuf:"varint,3,opt,name=compression,proto3,enum=api.webhook.v1.PayloadCompression" json:"compression,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointCompressionRequest) Reset() {
+	*x = SetEndpointCompressionRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointCompressionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointCompressionRequest) ProtoMessage() {}
+
+func (x *SetEndpointCompressionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointCompressionRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointCompressionRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SetEndpointCompressionRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointCompressionRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointCompressionRequest) GetCompression() PayloadCompression {
+	if x != nil {
+		return x.Compression
+	}
+	return PayloadCompression_PAYLOAD_COMPRESSION_UNSPECIFIED
+}
+
+type SetEndpointCompressionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointCompressionResponse) Reset() {
+	*x = SetEndpointCompressionResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointCompressionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointCompressionResponse) ProtoMessage() {}
+
+func (x *SetEndpointCompressionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointCompressionResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointCompressionResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetEndpointCompressionResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type SetEndpointTimeoutRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// How long to wait for the endpoint's response, in milliseconds (100 to 120000); 0 restores
+	// the worker default
+	TimeoutMs     int32 `protobuf:"varint,3,opt,name=timeout_ms,json=timeoutMs,proto3" json:"timeout_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointTimeoutRequest) Reset() {
+	*x = SetEndpointTimeoutRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointTimeoutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointTimeoutRequest) ProtoMessage() {}
+
+func (x *SetEndpointTimeoutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointTimeoutRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointTimeoutRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetEndpointTimeoutRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointTimeoutRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointTimeoutRequest) GetTimeoutMs() int32 {
+	if x != nil {
+		return x.TimeoutMs
+	}
+	return 0
+}
+
+type SetEndpointTimeoutResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointTimeoutResponse) Reset() {
+	*x = SetEndpointTimeoutResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointTimeoutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointTimeoutResponse) ProtoMessage() {}
+
+func (x *SetEndpointTimeoutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointTimeoutResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointTimeoutResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *SetEndpointTimeoutResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type SetEndpointProxyRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// http(s) URL of the proxy, optionally with user:password credentials; "direct" sends without
+	// a proxy, and empty restores the egress proxy
+	ProxyUrl      string `protobuf:"bytes,3,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointProxyRequest) Reset() {
+	*x = SetEndpointProxyRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointProxyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointProxyRequest) ProtoMessage() {}
+
+func (x *SetEndpointProxyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointProxyRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointProxyRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *SetEndpointProxyRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointProxyRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointProxyRequest) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+type SetEndpointProxyResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointProxyResponse) Reset() {
+	*x = SetEndpointProxyResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointProxyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointProxyResponse) ProtoMessage() {}
+
+func (x *SetEndpointProxyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointProxyResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointProxyResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *SetEndpointProxyResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type SetEndpointSignatureSchemeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// The scheme to sign with; unspecified restores v1
+	SignatureScheme SignatureScheme `protobuf:"varint,3,opt,name=signature_scheme,json=signatureScheme,proto3,enum=api.webhook.v1.SignatureScheme" json:"signature_scheme,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SetEndpointSignatureSchemeRequest) Reset() {
+	*x = SetEndpointSignatureSchemeRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointSignatureSchemeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointSignatureSchemeRequest) ProtoMessage() {}
+
+func (x *SetEndpointSignatureSchemeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointSignatureSchemeRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointSignatureSchemeRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *SetEndpointSignatureSchemeRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointSignatureSchemeRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointSignatureSchemeRequest) GetSignatureScheme() SignatureScheme {
+	if x != nil {
+		return x.SignatureScheme
+	}
+	return SignatureScheme_SIGNATURE_SCHEME_UNSPECIFIED
+}
+
+type SetEndpointSignatureSchemeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointSignatureSchemeResponse) Reset() {
+	*x = SetEndpointSignatureSchemeResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointSignatureSchemeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointSignatureSchemeResponse) ProtoMessage() {}
+
+func (x *SetEndpointSignatureSchemeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointSignatureSchemeResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointSignatureSchemeResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *SetEndpointSignatureSchemeResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type GetSigningKeysRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId      string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSigningKeysRequest) Reset() {
+	*x = GetSigningKeysRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSigningKeysRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSigningKeysRequest) ProtoMessage() {}
+
+func (x *GetSigningKeysRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSigningKeysRequest.ProtoReflect.Descriptor instead.
+func (*GetSigningKeysRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetSigningKeysRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+// SigningKey is an Ed25519 public key in JWK form (RFC 8037)
+type SigningKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Key type, always OKP
+	Kty string `protobuf:"bytes,1,opt,name=kty,proto3" json:"kty,omitempty"`
+	// Curve, always Ed25519
+	Crv string `protobuf:"bytes,2,opt,name=crv,proto3" json:"crv,omitempty"`
+	// Key ID, as named by the kid in ed25519 signatures
+	Kid string `protobuf:"bytes,3,opt,name=kid,proto3" json:"kid,omitempty"`
+	// The public key, base64url without padding
+	X string `protobuf:"bytes,4,opt,name=x,proto3" json:"x,omitempty"`
+	// Key use, always sig
+	Use string `protobuf:"bytes,5,opt,name=use,proto3" json:"use,omitempty"`
+	// Algorithm, always EdDSA
+	Alg           string `protobuf:"bytes,6,opt,name=alg,proto3" json:"alg,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SigningKey) Reset() {
+	*x = SigningKey{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SigningKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SigningKey) ProtoMessage() {}
+
+func (x *SigningKey) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SigningKey.ProtoReflect.Descriptor instead.
+func (*SigningKey) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SigningKey) GetKty() string {
+	if x != nil {
+		return x.Kty
+	}
+	return ""
+}
+
+func (x *SigningKey) GetCrv() string {
+	if x != nil {
+		return x.Crv
+	}
+	return ""
+}
+
+func (x *SigningKey) GetKid() string {
+	if x != nil {
+		return x.Kid
+	}
+	return ""
+}
+
+func (x *SigningKey) GetX() string {
+	if x != nil {
+		return x.X
+	}
+	return ""
+}
+
+func (x *SigningKey) GetUse() string {
+	if x != nil {
+		return x.Use
+	}
+	return ""
+}
+
+func (x *SigningKey) GetAlg() string {
+	if x != nil {
+		return x.Alg
+	}
+	return ""
+}
+
+type GetSigningKeysResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tenant's public keys; empty until one of its endpoints signs with ed25519
+	Keys          []*SigningKey `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSigningKeysResponse) Reset() {
+	*x = GetSigningKeysResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSigningKeysResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSigningKeysResponse) ProtoMessage() {}
+
+func (x *GetSigningKeysResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSigningKeysResponse.ProtoReflect.Descriptor instead.
+func (*GetSigningKeysResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetSigningKeysResponse) GetKeys() []*SigningKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type GetEgressIPsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEgressIPsRequest) Reset() {
+	*x = GetEgressIPsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEgressIPsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEgressIPsRequest) ProtoMessage() {}
+
+func (x *GetEgressIPsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEgressIPsRequest.ProtoReflect.Descriptor instead.
+func (*GetEgressIPsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{28}
+}
+
+type GetEgressIPsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// CIDRs webhooks are sent from, e.g. 203.0.113.10/32; empty when the deployment doesn't
+	// publish its egress addresses
+	Cidrs []string `protobuf:"bytes,1,rep,name=cidrs,proto3" json:"cidrs,omitempty"`
+	// Whether webhooks leave through an egress proxy, whose addresses the CIDRs are
+	Proxied       bool `protobuf:"varint,2,opt,name=proxied,proto3" json:"proxied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEgressIPsResponse) Reset() {
+	*x = GetEgressIPsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEgressIPsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEgressIPsResponse) ProtoMessage() {}
+
+func (x *GetEgressIPsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEgressIPsResponse.ProtoReflect.Descriptor instead.
+func (*GetEgressIPsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetEgressIPsResponse) GetCidrs() []string {
+	if x != nil {
+		return x.Cidrs
+	}
+	return nil
+}
+
+func (x *GetEgressIPsResponse) GetProxied() bool {
+	if x != nil {
+		return x.Proxied
+	}
+	return false
+}
+
+type SetEndpointHealthCheckRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// The checks to send; unset turns health checks off
+	HealthCheck   *EndpointHealthCheck `protobuf:"bytes,3,opt,name=health_check,json=healthCheck,proto3" json:"health_check,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointHealthCheckRequest) Reset() {
+	*x = SetEndpointHealthCheckRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointHealthCheckRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointHealthCheckRequest) ProtoMessage() {}
+
+func (x *SetEndpointHealthCheckRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointHealthCheckRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointHealthCheckRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SetEndpointHealthCheckRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointHealthCheckRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointHealthCheckRequest) GetHealthCheck() *EndpointHealthCheck {
+	if x != nil {
+		return x.HealthCheck
+	}
+	return nil
+}
+
+type SetEndpointHealthCheckResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointHealthCheckResponse) Reset() {
+	*x = SetEndpointHealthCheckResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointHealthCheckResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointHealthCheckResponse) ProtoMessage() {}
+
+func (x *SetEndpointHealthCheckResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointHealthCheckResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointHealthCheckResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SetEndpointHealthCheckResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type EnableEndpointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to enable
+	EndpointId    string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableEndpointRequest) Reset() {
+	*x = EnableEndpointRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableEndpointRequest) ProtoMessage() {}
+
+func (x *EnableEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableEndpointRequest.ProtoReflect.Descriptor instead.
+func (*EnableEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *EnableEndpointRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *EnableEndpointRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+type EnableEndpointResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The enabled endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EnableEndpointResponse) Reset() {
+	*x = EnableEndpointResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EnableEndpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EnableEndpointResponse) ProtoMessage() {}
+
+func (x *EnableEndpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EnableEndpointResponse.ProtoReflect.Descriptor instead.
+func (*EnableEndpointResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *EnableEndpointResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type SetEndpointOrderingRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to configure
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// The ordering to apply; unset sends deliveries as they come
+	Ordering      *DeliveryOrdering `protobuf:"bytes,3,opt,name=ordering,proto3" json:"ordering,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointOrderingRequest) Reset() {
+	*x = SetEndpointOrderingRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointOrderingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointOrderingRequest) ProtoMessage() {}
+
+func (x *SetEndpointOrderingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointOrderingRequest.ProtoReflect.Descriptor instead.
+func (*SetEndpointOrderingRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *SetEndpointOrderingRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetEndpointOrderingRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SetEndpointOrderingRequest) GetOrdering() *DeliveryOrdering {
+	if x != nil {
+		return x.Ordering
+	}
+	return nil
+}
+
+type SetEndpointOrderingResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The updated endpoint
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetEndpointOrderingResponse) Reset() {
+	*x = SetEndpointOrderingResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetEndpointOrderingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetEndpointOrderingResponse) ProtoMessage() {}
+
+func (x *SetEndpointOrderingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetEndpointOrderingResponse.ProtoReflect.Descriptor instead.
+func (*SetEndpointOrderingResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SetEndpointOrderingResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+type DeleteEndpointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint to delete
+	EndpointId    string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteEndpointRequest) Reset() {
+	*x = DeleteEndpointRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEndpointRequest) ProtoMessage() {}
+
+func (x *DeleteEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEndpointRequest.ProtoReflect.Descriptor instead.
+func (*DeleteEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *DeleteEndpointRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DeleteEndpointRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+type DeleteEndpointResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the deleted endpoint
+	EndpointId string `protobuf:"bytes,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Subscriptions removed with the endpoint
+	DeletedSubscriptions int32 `protobuf:"varint,2,opt,name=deleted_subscriptions,json=deletedSubscriptions,proto3" json:"deleted_subscriptions,omitempty"`
+	// Deliveries removed with the endpoint
+	DeletedDeliveries int32 `protobuf:"varint,3,opt,name=deleted_deliveries,json=deletedDeliveries,proto3" json:"deleted_deliveries,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *DeleteEndpointResponse) Reset() {
+	*x = DeleteEndpointResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteEndpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteEndpointResponse) ProtoMessage() {}
+
+func (x *DeleteEndpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteEndpointResponse.ProtoReflect.Descriptor instead.
+func (*DeleteEndpointResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *DeleteEndpointResponse) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *DeleteEndpointResponse) GetDeletedSubscriptions() int32 {
+	if x != nil {
+		return x.DeletedSubscriptions
+	}
+	return 0
+}
+
+func (x *DeleteEndpointResponse) GetDeletedDeliveries() int32 {
+	if x != nil {
+		return x.DeletedDeliveries
+	}
+	return 0
+}
+
+// Create endpoint response message
+type CreateEndpointResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The newly created endpoint
+	Endpoint *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// Why the endpoint did not answer its verification challenge; empty once it is verified
+	VerificationError string `protobuf:"bytes,2,opt,name=verification_error,json=verificationError,proto3" json:"verification_error,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateEndpointResponse) Reset() {
+	*x = CreateEndpointResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEndpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEndpointResponse) ProtoMessage() {}
+
+func (x *CreateEndpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEndpointResponse.ProtoReflect.Descriptor instead.
+func (*CreateEndpointResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *CreateEndpointResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+func (x *CreateEndpointResponse) GetVerificationError() string {
+	if x != nil {
+		return x.VerificationError
+	}
+	return ""
+}
+
+type VerifyEndpointRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Endpoint to verify
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Token from the challenge the endpoint received. If empty, the challenge is sent again
+	Token         string `protobuf:"bytes,3,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEndpointRequest) Reset() {
+	*x = VerifyEndpointRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEndpointRequest) ProtoMessage() {}
+
+func (x *VerifyEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEndpointRequest.ProtoReflect.Descriptor instead.
+func (*VerifyEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *VerifyEndpointRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *VerifyEndpointRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *VerifyEndpointRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type VerifyEndpointResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The endpoint, with verified_at set once it is verified
+	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyEndpointResponse) Reset() {
+	*x = VerifyEndpointResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyEndpointResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyEndpointResponse) ProtoMessage() {}
+
+func (x *VerifyEndpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyEndpointResponse.ProtoReflect.Descriptor instead.
+func (*VerifyEndpointResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *VerifyEndpointResponse) GetEndpoint() *Endpoint {
+	if x != nil {
+		return x.Endpoint
+	}
+	return nil
+}
+
+// Create subscription request message
+type CreateSubscriptionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant ID for the subscription
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Event type that this subscription is for
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Endpoint ID that this subscription is a member of
+	EndpointId string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Optional payload fields (dot paths, e.g. "user.id") to deliver. If empty, the full payload is delivered
+	IncludeFields []string `protobuf:"bytes,4,rep,name=include_fields,json=includeFields,proto3" json:"include_fields,omitempty"`
+	// Optional payload fields (dot paths, e.g. "user.ssn") to strip before delivery
+	ExcludeFields []string `protobuf:"bytes,5,rep,name=exclude_fields,json=excludeFields,proto3" json:"exclude_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequest) Reset() {
+	*x = CreateSubscriptionRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *CreateSubscriptionRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetIncludeFields() []string {
+	if x != nil {
+		return x.IncludeFields
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionRequest) GetExcludeFields() []string {
+	if x != nil {
+		return x.ExcludeFields
+	}
+	return nil
+}
+
+// Create subscription response message
+type CreateSubscriptionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The newly created subscription
+	Subscription  *Subscription `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionResponse) Reset() {
+	*x = CreateSubscriptionResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionResponse) ProtoMessage() {}
+
+func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *CreateSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+// Publish event request message
+type PublishEventRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Event type to be published
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Payload data for the event (arbitrary JSON)
+	Payload *structpb.Struct `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	// Required for deduplication, if empty, no dedup
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Optional deadline; deliveries still pending after it are dead-lettered as "expired"
+	DeliverBy *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=deliver_by,json=deliverBy,proto3" json:"deliver_by,omitempty"`
+	// Optional time-to-live, relative to publish; set at most one of deliver_by and ttl
+	Ttl *durationpb.Duration `protobuf:"bytes,6,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Optional time to fan the event out at; the event is stored as scheduled until then
+	PublishAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=publish_at,json=publishAt,proto3" json:"publish_at,omitempty"`
+	// Optional priority; with priority topics enabled each priority has its own delivery topic
+	Priority      EventPriority `protobuf:"varint,8,opt,name=priority,proto3,enum=api.webhook.v1.EventPriority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventRequest) Reset() {
+	*x = PublishEventRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventRequest) ProtoMessage() {}
+
+func (x *PublishEventRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventRequest.ProtoReflect.Descriptor instead.
+func (*PublishEventRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PublishEventRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *PublishEventRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *PublishEventRequest) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *PublishEventRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *PublishEventRequest) GetDeliverBy() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliverBy
+	}
+	return nil
+}
+
+func (x *PublishEventRequest) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *PublishEventRequest) GetPublishAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishAt
+	}
+	return nil
+}
+
+func (x *PublishEventRequest) GetPriority() EventPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return EventPriority_EVENT_PRIORITY_UNSPECIFIED
+}
+
+// Publish event response message
+type PublishEventResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Event ID
+	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// How many deliveries for this event are enqueued
+	FanoutCount int32 `protobuf:"varint,2,opt,name=fanout_count,json=fanoutCount,proto3" json:"fanout_count,omitempty"`
+	// Set when the event was scheduled for a later publish_at; it has no deliveries yet
+	Scheduled bool `protobuf:"varint,3,opt,name=scheduled,proto3" json:"scheduled,omitempty"`
+	// The idempotency key matched an event already stored; event_id and fanout_count are that
+	// event's, and nothing was fanned out again
+	Duplicate     bool `protobuf:"varint,4,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventResponse) Reset() {
+	*x = PublishEventResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventResponse) ProtoMessage() {}
+
+func (x *PublishEventResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventResponse.ProtoReflect.Descriptor instead.
+func (*PublishEventResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *PublishEventResponse) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *PublishEventResponse) GetFanoutCount() int32 {
+	if x != nil {
+		return x.FanoutCount
+	}
+	return 0
+}
+
+func (x *PublishEventResponse) GetScheduled() bool {
+	if x != nil {
+		return x.Scheduled
+	}
+	return false
+}
+
+func (x *PublishEventResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+// One event in a batch publish
+type BatchEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Event type to be published
+	EventType string `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Payload data for the event (arbitrary JSON)
+	Payload *structpb.Struct `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+	// Required for deduplication, if empty, no dedup
+	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Optional deadline; deliveries still pending after it are dead-lettered as "expired"
+	DeliverBy *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=deliver_by,json=deliverBy,proto3" json:"deliver_by,omitempty"`
+	// Optional time-to-live, relative to publish; set at most one of deliver_by and ttl
+	Ttl *durationpb.Duration `protobuf:"bytes,5,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// Optional priority; with priority topics enabled each priority has its own delivery topic
+	Priority      EventPriority `protobuf:"varint,6,opt,name=priority,proto3,enum=api.webhook.v1.EventPriority" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchEvent) Reset() {
+	*x = BatchEvent{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchEvent) ProtoMessage() {}
+
+func (x *BatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchEvent.ProtoReflect.Descriptor instead.
+func (*BatchEvent) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *BatchEvent) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *BatchEvent) GetPayload() *structpb.Struct {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *BatchEvent) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *BatchEvent) GetDeliverBy() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliverBy
+	}
+	return nil
+}
+
+func (x *BatchEvent) GetTtl() *durationpb.Duration {
+	if x != nil {
+		return x.Ttl
+	}
+	return nil
+}
+
+func (x *BatchEvent) GetPriority() EventPriority {
+	if x != nil {
+		return x.Priority
+	}
+	return EventPriority_EVENT_PRIORITY_UNSPECIFIED
+}
+
+type PublishEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Events to publish, in order. Each is validated on its own, so a bad event fails alone
+	Events        []*BatchEvent `protobuf:"bytes,2,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventsRequest) Reset() {
+	*x = PublishEventsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventsRequest) ProtoMessage() {}
+
+func (x *PublishEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventsRequest.ProtoReflect.Descriptor instead.
+func (*PublishEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *PublishEventsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *PublishEventsRequest) GetEvents() []*BatchEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// Outcome of one event in a batch publish
+type PublishEventResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Position of the event in the request
+	Index int32 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	// Event ID (empty when the event was rejected)
+	EventId string `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// How many deliveries for this event are enqueued; for a duplicate, how many the original
+	// event was fanned out to
+	FanoutCount int32 `protobuf:"varint,3,opt,name=fanout_count,json=fanoutCount,proto3" json:"fanout_count,omitempty"`
+	// The idempotency key matched an event that was already fanned out
+	Duplicate bool `protobuf:"varint,4,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	// gRPC status code for a rejected event (0 on success)
+	ErrorCode int32 `protobuf:"varint,5,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	// Why the event was rejected; empty on success
+	Error         string `protobuf:"bytes,6,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventResult) Reset() {
+	*x = PublishEventResult{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventResult) ProtoMessage() {}
+
+func (x *PublishEventResult) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventResult.ProtoReflect.Descriptor instead.
+func (*PublishEventResult) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *PublishEventResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *PublishEventResult) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *PublishEventResult) GetFanoutCount() int32 {
+	if x != nil {
+		return x.FanoutCount
+	}
+	return 0
+}
+
+func (x *PublishEventResult) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+func (x *PublishEventResult) GetErrorCode() int32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *PublishEventResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type PublishEventsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One result per requested event, in request order
+	Results []*PublishEventResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	// Events stored and fanned out (including duplicates)
+	PublishedCount int32 `protobuf:"varint,2,opt,name=published_count,json=publishedCount,proto3" json:"published_count,omitempty"`
+	// Events rejected
+	FailedCount   int32 `protobuf:"varint,3,opt,name=failed_count,json=failedCount,proto3" json:"failed_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishEventsResponse) Reset() {
+	*x = PublishEventsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishEventsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishEventsResponse) ProtoMessage() {}
+
+func (x *PublishEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishEventsResponse.ProtoReflect.Descriptor instead.
+func (*PublishEventsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *PublishEventsResponse) GetResults() []*PublishEventResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+func (x *PublishEventsResponse) GetPublishedCount() int32 {
+	if x != nil {
+		return x.PublishedCount
+	}
+	return 0
+}
+
+func (x *PublishEventsResponse) GetFailedCount() int32 {
+	if x != nil {
+		return x.FailedCount
+	}
+	return 0
+}
+
+// A JSON Schema registered for an event type. Published payloads of that type must match the
+// latest version.
+type EventSchema struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Event type the schema applies to
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Version, starting at 1 and increasing with each registration
+	Version int32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// The JSON Schema (draft 2020-12 validation keywords)
+	Schema *structpb.Struct `protobuf:"bytes,4,opt,name=schema,proto3" json:"schema,omitempty"`
+	// When this version was registered
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventSchema) Reset() {
+	*x = EventSchema{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventSchema) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventSchema) ProtoMessage() {}
+
+func (x *EventSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventSchema.ProtoReflect.Descriptor instead.
+func (*EventSchema) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *EventSchema) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *EventSchema) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *EventSchema) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *EventSchema) GetSchema() *structpb.Struct {
+	if x != nil {
+		return x.Schema
+	}
+	return nil
+}
+
+func (x *EventSchema) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreateEventSchemaRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Event type the schema applies to
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// The JSON Schema; it becomes the next version and applies to events published afterwards
+	Schema        *structpb.Struct `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventSchemaRequest) Reset() {
+	*x = CreateEventSchemaRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventSchemaRequest) ProtoMessage() {}
+
+func (x *CreateEventSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventSchemaRequest.ProtoReflect.Descriptor instead.
+func (*CreateEventSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *CreateEventSchemaRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *CreateEventSchemaRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *CreateEventSchemaRequest) GetSchema() *structpb.Struct {
+	if x != nil {
+		return x.Schema
+	}
+	return nil
+}
+
+type CreateEventSchemaResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The registered schema version
+	Schema        *EventSchema `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateEventSchemaResponse) Reset() {
+	*x = CreateEventSchemaResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateEventSchemaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateEventSchemaResponse) ProtoMessage() {}
+
+func (x *CreateEventSchemaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateEventSchemaResponse.ProtoReflect.Descriptor instead.
+func (*CreateEventSchemaResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *CreateEventSchemaResponse) GetSchema() *EventSchema {
+	if x != nil {
+		return x.Schema
+	}
+	return nil
+}
+
+type ListEventSchemasRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// List every version of this event type, newest first; empty lists the latest version of each type
+	EventType     string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventSchemasRequest) Reset() {
+	*x = ListEventSchemasRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventSchemasRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventSchemasRequest) ProtoMessage() {}
+
+func (x *ListEventSchemasRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventSchemasRequest.ProtoReflect.Descriptor instead.
+func (*ListEventSchemasRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *ListEventSchemasRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListEventSchemasRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+type ListEventSchemasResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Schemas, by event type then newest version first
+	Schemas       []*EventSchema `protobuf:"bytes,1,rep,name=schemas,proto3" json:"schemas,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListEventSchemasResponse) Reset() {
+	*x = ListEventSchemasResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListEventSchemasResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListEventSchemasResponse) ProtoMessage() {}
+
+func (x *ListEventSchemasResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListEventSchemasResponse.ProtoReflect.Descriptor instead.
+func (*ListEventSchemasResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ListEventSchemasResponse) GetSchemas() []*EventSchema {
+	if x != nil {
+		return x.Schemas
+	}
+	return nil
+}
+
+type GetEventSchemaRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Event type the schema applies to
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Version to get; 0 gets the latest
+	Version       int32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventSchemaRequest) Reset() {
+	*x = GetEventSchemaRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventSchemaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventSchemaRequest) ProtoMessage() {}
+
+func (x *GetEventSchemaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventSchemaRequest.ProtoReflect.Descriptor instead.
+func (*GetEventSchemaRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *GetEventSchemaRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetEventSchemaRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *GetEventSchemaRequest) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type GetEventSchemaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Schema        *EventSchema           `protobuf:"bytes,1,opt,name=schema,proto3" json:"schema,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetEventSchemaResponse) Reset() {
+	*x = GetEventSchemaResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetEventSchemaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetEventSchemaResponse) ProtoMessage() {}
+
+func (x *GetEventSchemaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetEventSchemaResponse.ProtoReflect.Descriptor instead.
+func (*GetEventSchemaResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetEventSchemaResponse) GetSchema() *EventSchema {
+	if x != nil {
+		return x.Schema
+	}
+	return nil
+}
+
+type DeliveryAttempt struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique ID for the delivery attempt
+	DeliveryId string `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// ID of the event being delivered
+	EventId string `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// ID of the endpoint being delivered to
+	EndpointId string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// link to source attempt (if replayed)
+	ReplayOf string `protobuf:"bytes,4,opt,name=replay_of,json=replayOf,proto3" json:"replay_of,omitempty"`
+	// queued|inflight|delivered|failed|dead|parked
+	Status DeliveryAttemptStatus `protobuf:"varint,5,opt,name=status,proto3,enum=api.webhook.v1.DeliveryAttemptStatus" json:"status,omitempty"`
+	// HTTP status code
+	HttpStatus int32 `protobuf:"varint,6,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+	// Optional error reason
+	ErrorReason string `protobuf:"bytes,7,opt,name=error_reason,json=errorReason,proto3" json:"error_reason,omitempty"`
+	// First delivery in this attempt's replay chain (itself when it is not a replay)
+	RootDeliveryId string `protobuf:"bytes,8,opt,name=root_delivery_id,json=rootDeliveryId,proto3" json:"root_delivery_id,omitempty"`
+	// Number of replays between the root and this attempt (0 for the original)
+	ReplayDepth int32 `protobuf:"varint,9,opt,name=replay_depth,json=replayDepth,proto3" json:"replay_depth,omitempty"`
+	// Timestamp of when the delivery was enqueued
+	EnqueuedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=enqueued_at,json=enqueuedAt,proto3" json:"enqueued_at,omitempty"`
+	// Timestamp of when the delivery was dequeued
+	DequeuedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=dequeued_at,json=dequeuedAt,proto3" json:"dequeued_at,omitempty"`
+	// Timestamp of when the delivery was sent
+	SentAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	// Timestamp of when the delivery was delivered
+	DeliveredAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	// Timestamp of when the delivery failed
+	FailedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
+	// Timestamp of when the delivery was dead-lettered
+	DlqAt *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=dlq_at,json=dlqAt,proto3" json:"dlq_at,omitempty"`
+	// Timestamp of when the receiver acknowledged processing the delivery
+	AckedAt *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=acked_at,json=ackedAt,proto3" json:"acked_at,omitempty"`
+	// Every try at this delivery so far, oldest first
+	History       []*AttemptRecord `protobuf:"bytes,17,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryAttempt) Reset() {
+	*x = DeliveryAttempt{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryAttempt) ProtoMessage() {}
+
+func (x *DeliveryAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryAttempt.ProtoReflect.Descriptor instead.
+func (*DeliveryAttempt) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *DeliveryAttempt) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetReplayOf() string {
+	if x != nil {
+		return x.ReplayOf
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetStatus() DeliveryAttemptStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED
+}
+
+func (x *DeliveryAttempt) GetHttpStatus() int32 {
+	if x != nil {
+		return x.HttpStatus
+	}
+	return 0
+}
+
+func (x *DeliveryAttempt) GetErrorReason() string {
+	if x != nil {
+		return x.ErrorReason
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetRootDeliveryId() string {
+	if x != nil {
+		return x.RootDeliveryId
+	}
+	return ""
+}
+
+func (x *DeliveryAttempt) GetReplayDepth() int32 {
+	if x != nil {
+		return x.ReplayDepth
+	}
+	return 0
+}
+
+func (x *DeliveryAttempt) GetEnqueuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EnqueuedAt
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetDequeuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DequeuedAt
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetFailedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FailedAt
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetDlqAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DlqAt
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetAckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AckedAt
+	}
+	return nil
+}
+
+func (x *DeliveryAttempt) GetHistory() []*AttemptRecord {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+// One try at sending a delivery
+type AttemptRecord struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 1 for the first try
+	Attempt int32 `protobuf:"varint,1,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// delivered|failed
+	Outcome string `protobuf:"bytes,2,opt,name=outcome,proto3" json:"outcome,omitempty"`
+	// HTTP status code (0 when there was no response)
+	HttpStatus int32 `protobuf:"varint,3,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
+	// How long the request took
+	LatencyMs int32 `protobuf:"varint,4,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	// Why the try failed
+	Error string `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	// Timestamp of when the request was sent
+	SentAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	// Timestamp of when the outcome was recorded
+	FinishedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	// The start of the receiver's response body, for failed tries that got a response
+	ResponseBody string `protobuf:"bytes,8,opt,name=response_body,json=responseBody,proto3" json:"response_body,omitempty"`
+	// The response body went on past what the worker keeps
+	ResponseTruncated bool `protobuf:"varint,9,opt,name=response_truncated,json=responseTruncated,proto3" json:"response_truncated,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *AttemptRecord) Reset() {
+	*x = AttemptRecord{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttemptRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttemptRecord) ProtoMessage() {}
+
+func (x *AttemptRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttemptRecord.ProtoReflect.Descriptor instead.
+func (*AttemptRecord) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *AttemptRecord) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *AttemptRecord) GetOutcome() string {
+	if x != nil {
+		return x.Outcome
+	}
+	return ""
+}
+
+func (x *AttemptRecord) GetHttpStatus() int32 {
+	if x != nil {
+		return x.HttpStatus
+	}
+	return 0
+}
+
+func (x *AttemptRecord) GetLatencyMs() int32 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *AttemptRecord) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AttemptRecord) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *AttemptRecord) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *AttemptRecord) GetResponseBody() string {
+	if x != nil {
+		return x.ResponseBody
+	}
+	return ""
+}
+
+func (x *AttemptRecord) GetResponseTruncated() bool {
+	if x != nil {
+		return x.ResponseTruncated
+	}
+	return false
+}
+
+type GetDeliveryStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the event to check deliveries for
+	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// ID of the endpoint to check deliveries for
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Start datetime to search
+	From *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// End datetime to search
+	To *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	// Limit the number of results (default 10)
+	Limit int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Group the returned attempts into replay chains
+	IncludeReplayChains bool `protobuf:"varint,6,opt,name=include_replay_chains,json=includeReplayChains,proto3" json:"include_replay_chains,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatusRequest) Reset() {
+	*x = GetDeliveryStatusRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatusRequest) ProtoMessage() {}
+
+func (x *GetDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetDeliveryStatusRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *GetDeliveryStatusRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *GetDeliveryStatusRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetDeliveryStatusRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *GetDeliveryStatusRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetDeliveryStatusRequest) GetIncludeReplayChains() bool {
+	if x != nil {
+		return x.IncludeReplayChains
+	}
+	return false
+}
+
+type GetDeliveryStatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// List of delivery attempts
+	Attempts []*DeliveryAttempt `protobuf:"bytes,1,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	// Returned attempts grouped by replay lineage. Only set when include_replay_chains is true
+	ReplayChains  []*ReplayChain `protobuf:"bytes,2,rep,name=replay_chains,json=replayChains,proto3" json:"replay_chains,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatusResponse) Reset() {
+	*x = GetDeliveryStatusResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatusResponse) ProtoMessage() {}
+
+func (x *GetDeliveryStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetDeliveryStatusResponse) GetAttempts() []*DeliveryAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+func (x *GetDeliveryStatusResponse) GetReplayChains() []*ReplayChain {
+	if x != nil {
+		return x.ReplayChains
+	}
+	return nil
+}
+
+type WatchDeliveryStatusRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Watch the deliveries of this event
+	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Watch deliveries to this endpoint (narrows event_id when both are set)
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Tenant owning the event or endpoint (defaults to the token's tenant)
+	TenantId string `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// How often the server checks for changes, in milliseconds (default 1000, minimum 250)
+	PollIntervalMs int32 `protobuf:"varint,4,opt,name=poll_interval_ms,json=pollIntervalMs,proto3" json:"poll_interval_ms,omitempty"`
+	// Keep an event's stream open after every delivery has been delivered or dead-lettered,
+	// e.g. to see replays. Endpoint streams always stay open until the client cancels.
+	Follow bool `protobuf:"varint,5,opt,name=follow,proto3" json:"follow,omitempty"`
+	// Leave out deliveries that were already delivered or dead-lettered when the watch started
+	SkipFinished  bool `protobuf:"varint,6,opt,name=skip_finished,json=skipFinished,proto3" json:"skip_finished,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDeliveryStatusRequest) Reset() {
+	*x = WatchDeliveryStatusRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDeliveryStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDeliveryStatusRequest) ProtoMessage() {}
+
+func (x *WatchDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDeliveryStatusRequest.ProtoReflect.Descriptor instead.
+func (*WatchDeliveryStatusRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *WatchDeliveryStatusRequest) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *WatchDeliveryStatusRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *WatchDeliveryStatusRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *WatchDeliveryStatusRequest) GetPollIntervalMs() int32 {
+	if x != nil {
+		return x.PollIntervalMs
+	}
+	return 0
+}
+
+func (x *WatchDeliveryStatusRequest) GetFollow() bool {
+	if x != nil {
+		return x.Follow
+	}
+	return false
+}
+
+func (x *WatchDeliveryStatusRequest) GetSkipFinished() bool {
+	if x != nil {
+		return x.SkipFinished
+	}
+	return false
+}
+
+type WatchDeliveryStatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The delivery in its new status
+	Delivery *DeliveryAttempt `protobuf:"bytes,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	// Status before the change; unspecified the first time a delivery is sent
+	PreviousStatus DeliveryAttemptStatus `protobuf:"varint,2,opt,name=previous_status,json=previousStatus,proto3,enum=api.webhook.v1.DeliveryAttemptStatus" json:"previous_status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchDeliveryStatusResponse) Reset() {
+	*x = WatchDeliveryStatusResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDeliveryStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDeliveryStatusResponse) ProtoMessage() {}
+
+func (x *WatchDeliveryStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDeliveryStatusResponse.ProtoReflect.Descriptor instead.
+func (*WatchDeliveryStatusResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *WatchDeliveryStatusResponse) GetDelivery() *DeliveryAttempt {
+	if x != nil {
+		return x.Delivery
+	}
+	return nil
+}
+
+func (x *WatchDeliveryStatusResponse) GetPreviousStatus() DeliveryAttemptStatus {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED
+}
+
+// An original delivery and every replay descended from it (replays of replays included)
+type ReplayChain struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The original delivery
+	RootDeliveryId string `protobuf:"bytes,1,opt,name=root_delivery_id,json=rootDeliveryId,proto3" json:"root_delivery_id,omitempty"`
+	// Attempts in the chain, ordered by replay depth then enqueue time
+	Attempts []*DeliveryAttempt `protobuf:"bytes,2,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	// ID of the replay still queued, in flight or retrying, if any
+	ActiveDeliveryId string `protobuf:"bytes,3,opt,name=active_delivery_id,json=activeDeliveryId,proto3" json:"active_delivery_id,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ReplayChain) Reset() {
+	*x = ReplayChain{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayChain) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayChain) ProtoMessage() {}
+
+func (x *ReplayChain) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayChain.ProtoReflect.Descriptor instead.
+func (*ReplayChain) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *ReplayChain) GetRootDeliveryId() string {
+	if x != nil {
+		return x.RootDeliveryId
+	}
+	return ""
+}
+
+func (x *ReplayChain) GetAttempts() []*DeliveryAttempt {
+	if x != nil {
+		return x.Attempts
+	}
+	return nil
+}
+
+func (x *ReplayChain) GetActiveDeliveryId() string {
+	if x != nil {
+		return x.ActiveDeliveryId
+	}
+	return ""
+}
+
+type ReplayDeliveryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the delivery to replay
+	DeliveryId string `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// Optional reason for replaying the delivery
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Keep the source delivery's receiver idempotency key instead of sending a new one
+	SameKey bool `protobuf:"varint,3,opt,name=same_key,json=sameKey,proto3" json:"same_key,omitempty"`
+	// Send the replay to this URL instead of the endpoint's, e.g. a staging receiver. The endpoint's
+	// secret, signature scheme and settings still apply
+	UrlOverride string `protobuf:"bytes,4,opt,name=url_override,json=urlOverride,proto3" json:"url_override,omitempty"`
+	// JSON merge patch (RFC 7396) applied to the event's payload for this replay; a null value
+	// removes the field
+	PayloadPatch  *structpb.Struct `protobuf:"bytes,5,opt,name=payload_patch,json=payloadPatch,proto3" json:"payload_patch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeliveryRequest) Reset() {
+	*x = ReplayDeliveryRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeliveryRequest) ProtoMessage() {}
+
+func (x *ReplayDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*ReplayDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *ReplayDeliveryRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *ReplayDeliveryRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReplayDeliveryRequest) GetSameKey() bool {
+	if x != nil {
+		return x.SameKey
+	}
+	return false
+}
+
+func (x *ReplayDeliveryRequest) GetUrlOverride() string {
+	if x != nil {
+		return x.UrlOverride
+	}
+	return ""
+}
+
+func (x *ReplayDeliveryRequest) GetPayloadPatch() *structpb.Struct {
+	if x != nil {
+		return x.PayloadPatch
+	}
+	return nil
+}
+
+type ReplayDeliveryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The newly enqueued attempt, or the replay already in progress when deduplicated
+	NewAttempt *DeliveryAttempt `protobuf:"bytes,1,opt,name=new_attempt,json=newAttempt,proto3" json:"new_attempt,omitempty"`
+	// True when an active replay of the delivery already existed and no new one was created
+	Deduplicated  bool `protobuf:"varint,2,opt,name=deduplicated,proto3" json:"deduplicated,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeliveryResponse) Reset() {
+	*x = ReplayDeliveryResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeliveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeliveryResponse) ProtoMessage() {}
+
+func (x *ReplayDeliveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeliveryResponse.ProtoReflect.Descriptor instead.
+func (*ReplayDeliveryResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ReplayDeliveryResponse) GetNewAttempt() *DeliveryAttempt {
+	if x != nil {
+		return x.NewAttempt
+	}
+	return nil
+}
+
+func (x *ReplayDeliveryResponse) GetDeduplicated() bool {
+	if x != nil {
+		return x.Deduplicated
+	}
+	return false
+}
+
+type AcknowledgeDeliveryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The ID of the delivery being acknowledged (sent to receivers in the delivery ID header)
+	DeliveryId string `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// Unix seconds when the receipt was signed; must be within 5 minutes of the server clock
+	Timestamp int64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// sha256=hex(HMAC(endpoint_secret, delivery_id || timestamp))
+	Signature     string `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeDeliveryRequest) Reset() {
+	*x = AcknowledgeDeliveryRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeDeliveryRequest) ProtoMessage() {}
+
+func (x *AcknowledgeDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*AcknowledgeDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *AcknowledgeDeliveryRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *AcknowledgeDeliveryRequest) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *AcknowledgeDeliveryRequest) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type AcknowledgeDeliveryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The acknowledged delivery
+	DeliveryId string `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// When the delivery was first acknowledged
+	AckedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=acked_at,json=ackedAt,proto3" json:"acked_at,omitempty"`
+	// True when the delivery had already been acknowledged
+	AlreadyAcked  bool `protobuf:"varint,3,opt,name=already_acked,json=alreadyAcked,proto3" json:"already_acked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcknowledgeDeliveryResponse) Reset() {
+	*x = AcknowledgeDeliveryResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcknowledgeDeliveryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcknowledgeDeliveryResponse) ProtoMessage() {}
+
+func (x *AcknowledgeDeliveryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcknowledgeDeliveryResponse.ProtoReflect.Descriptor instead.
+func (*AcknowledgeDeliveryResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *AcknowledgeDeliveryResponse) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *AcknowledgeDeliveryResponse) GetAckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AckedAt
+	}
+	return nil
+}
+
+func (x *AcknowledgeDeliveryResponse) GetAlreadyAcked() bool {
+	if x != nil {
+		return x.AlreadyAcked
+	}
+	return false
+}
+
+type ListDLQRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the endpoint to filter by
+	EndpointId string `protobuf:"bytes,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Limit the number of results (default 10, max 500)
+	Limit int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// ID of the tenant to filter by. Defaults to the tenant in the caller's token
+	TenantId string `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Only entries dead-lettered at or after this time
+	From *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	// Only entries dead-lettered before this time
+	To *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	// Page token from a previous response's next_page_token
+	PageToken     string `protobuf:"bytes,6,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDLQRequest) Reset() {
+	*x = ListDLQRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDLQRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDLQRequest) ProtoMessage() {}
+
+func (x *ListDLQRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDLQRequest.ProtoReflect.Descriptor instead.
+func (*ListDLQRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *ListDLQRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *ListDLQRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListDLQRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListDLQRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListDLQRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListDLQRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListDLQResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// List of delivery attempts in the DLQ
+	Dead []*DeliveryAttempt `protobuf:"bytes,1,rep,name=dead,proto3" json:"dead,omitempty"`
+	// Token for the next page. Empty when there are no more results
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	// Total number of entries matching the filters, across all pages
+	TotalCount    int32 `protobuf:"varint,3,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDLQResponse) Reset() {
+	*x = ListDLQResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDLQResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDLQResponse) ProtoMessage() {}
+
+func (x *ListDLQResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDLQResponse.ProtoReflect.Descriptor instead.
+func (*ListDLQResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *ListDLQResponse) GetDead() []*DeliveryAttempt {
+	if x != nil {
+		return x.Dead
+	}
+	return nil
+}
+
+func (x *ListDLQResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+func (x *ListDLQResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+type ReplayDLQRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the endpoint to filter by
+	EndpointId string `protobuf:"bytes,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Event type to filter by
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Only entries dead-lettered at or after this time
+	From *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// Only entries dead-lettered before this time
+	To *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	// Maximum number of deliveries to replay, oldest first (default 100, max 1000)
+	MaxCount int32 `protobuf:"varint,5,opt,name=max_count,json=maxCount,proto3" json:"max_count,omitempty"`
+	// Count what would be replayed without enqueuing anything
+	DryRun bool `protobuf:"varint,6,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Optional reason recorded on every replay
+	Reason string `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	// ID of the tenant to filter by. Defaults to the tenant in the caller's token
+	TenantId string `protobuf:"bytes,8,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Keep each dead delivery's receiver idempotency key instead of sending new ones
+	SameKey       bool `protobuf:"varint,9,opt,name=same_key,json=sameKey,proto3" json:"same_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDLQRequest) Reset() {
+	*x = ReplayDLQRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDLQRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDLQRequest) ProtoMessage() {}
+
+func (x *ReplayDLQRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDLQRequest.ProtoReflect.Descriptor instead.
+func (*ReplayDLQRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *ReplayDLQRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *ReplayDLQRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *ReplayDLQRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReplayDLQRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ReplayDLQRequest) GetMaxCount() int32 {
+	if x != nil {
+		return x.MaxCount
+	}
+	return 0
+}
+
+func (x *ReplayDLQRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ReplayDLQRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ReplayDLQRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ReplayDLQRequest) GetSameKey() bool {
+	if x != nil {
+		return x.SameKey
+	}
+	return false
+}
+
+type ReplayDLQResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of dead deliveries matching the filters
+	MatchedCount int32 `protobuf:"varint,1,opt,name=matched_count,json=matchedCount,proto3" json:"matched_count,omitempty"`
+	// Number of deliveries replayed, or that would be replayed on a dry run
+	ReplayedCount int32 `protobuf:"varint,2,opt,name=replayed_count,json=replayedCount,proto3" json:"replayed_count,omitempty"`
+	// Whether this was a dry run
+	DryRun bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// The newly enqueued attempts. Empty on a dry run
+	Replayed      []*DeliveryAttempt `protobuf:"bytes,4,rep,name=replayed,proto3" json:"replayed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDLQResponse) Reset() {
+	*x = ReplayDLQResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDLQResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDLQResponse) ProtoMessage() {}
+
+func (x *ReplayDLQResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDLQResponse.ProtoReflect.Descriptor instead.
+func (*ReplayDLQResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *ReplayDLQResponse) GetMatchedCount() int32 {
+	if x != nil {
+		return x.MatchedCount
+	}
+	return 0
+}
+
+func (x *ReplayDLQResponse) GetReplayedCount() int32 {
+	if x != nil {
+		return x.ReplayedCount
+	}
+	return 0
+}
+
+func (x *ReplayDLQResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *ReplayDLQResponse) GetReplayed() []*DeliveryAttempt {
+	if x != nil {
+		return x.Replayed
+	}
+	return nil
+}
+
+// A delivery in a dead-lettered delivery's replay chain
+type DLQEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The delivery
+	Attempt *DeliveryAttempt `protobuf:"bytes,1,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// Number of send attempts the delivery made
+	AttemptCount int32 `protobuf:"varint,2,opt,name=attempt_count,json=attemptCount,proto3" json:"attempt_count,omitempty"`
+	// Why the delivery was dead-lettered. Empty when it never was
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DLQEntry) Reset() {
+	*x = DLQEntry{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DLQEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DLQEntry) ProtoMessage() {}
+
+func (x *DLQEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DLQEntry.ProtoReflect.Descriptor instead.
+func (*DLQEntry) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *DLQEntry) GetAttempt() *DeliveryAttempt {
+	if x != nil {
+		return x.Attempt
+	}
+	return nil
+}
+
+func (x *DLQEntry) GetAttemptCount() int32 {
+	if x != nil {
+		return x.AttemptCount
+	}
+	return 0
+}
+
+func (x *DLQEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type GetDLQEntryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the dead-lettered delivery
+	DeliveryId string `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// ID of the tenant that owns the delivery. Defaults to the tenant in the caller's token
+	TenantId      string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDLQEntryRequest) Reset() {
+	*x = GetDLQEntryRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDLQEntryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDLQEntryRequest) ProtoMessage() {}
+
+func (x *GetDLQEntryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDLQEntryRequest.ProtoReflect.Descriptor instead.
+func (*GetDLQEntryRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *GetDLQEntryRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *GetDLQEntryRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type GetDLQEntryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The requested delivery
+	Entry *DLQEntry `protobuf:"bytes,1,opt,name=entry,proto3" json:"entry,omitempty"`
+	// ID of the tenant that owns the delivery
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Event type of the delivered event
+	EventType string `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Every delivery in the replay chain, ordered by replay depth then enqueue time
+	History       []*DLQEntry `protobuf:"bytes,4,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDLQEntryResponse) Reset() {
+	*x = GetDLQEntryResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDLQEntryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDLQEntryResponse) ProtoMessage() {}
+
+func (x *GetDLQEntryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDLQEntryResponse.ProtoReflect.Descriptor instead.
+func (*GetDLQEntryResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *GetDLQEntryResponse) GetEntry() *DLQEntry {
+	if x != nil {
+		return x.Entry
+	}
+	return nil
+}
+
+func (x *GetDLQEntryResponse) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetDLQEntryResponse) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *GetDLQEntryResponse) GetHistory() []*DLQEntry {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type PurgeDLQRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the endpoint to filter by
+	EndpointId string `protobuf:"bytes,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Event type to filter by
+	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Only entries dead-lettered at or after this time
+	From *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
+	// Only entries dead-lettered before this time
+	To *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
+	// Only the entry for this delivery
+	DeliveryId string `protobuf:"bytes,5,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// ID of the tenant to filter by. Defaults to the tenant in the caller's token
+	TenantId string `protobuf:"bytes,6,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Count what would be purged without deleting anything
+	DryRun bool `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Purge every entry in scope when no other filter is set
+	All bool `protobuf:"varint,8,opt,name=all,proto3" json:"all,omitempty"`
+	// Copy the entries to the DLQ archive before removing them
+	Archive       bool `protobuf:"varint,9,opt,name=archive,proto3" json:"archive,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDLQRequest) Reset() {
+	*x = PurgeDLQRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDLQRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDLQRequest) ProtoMessage() {}
+
+func (x *PurgeDLQRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDLQRequest.ProtoReflect.Descriptor instead.
+func (*PurgeDLQRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *PurgeDLQRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *PurgeDLQRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *PurgeDLQRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *PurgeDLQRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *PurgeDLQRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *PurgeDLQRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *PurgeDLQRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *PurgeDLQRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *PurgeDLQRequest) GetArchive() bool {
+	if x != nil {
+		return x.Archive
+	}
+	return false
+}
+
+type PurgeDLQResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of dead-lettered deliveries matching the filters
+	MatchedCount int32 `protobuf:"varint,1,opt,name=matched_count,json=matchedCount,proto3" json:"matched_count,omitempty"`
+	// Number of deliveries removed from the DLQ. Zero on a dry run
+	PurgedCount int32 `protobuf:"varint,2,opt,name=purged_count,json=purgedCount,proto3" json:"purged_count,omitempty"`
+	// Whether this was a dry run
+	DryRun bool `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	// Number of entries copied to the DLQ archive
+	ArchivedCount int32 `protobuf:"varint,4,opt,name=archived_count,json=archivedCount,proto3" json:"archived_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PurgeDLQResponse) Reset() {
+	*x = PurgeDLQResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PurgeDLQResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PurgeDLQResponse) ProtoMessage() {}
+
+func (x *PurgeDLQResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PurgeDLQResponse.ProtoReflect.Descriptor instead.
+func (*PurgeDLQResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *PurgeDLQResponse) GetMatchedCount() int32 {
+	if x != nil {
+		return x.MatchedCount
+	}
+	return 0
+}
+
+func (x *PurgeDLQResponse) GetPurgedCount() int32 {
+	if x != nil {
+		return x.PurgedCount
+	}
+	return 0
+}
+
+func (x *PurgeDLQResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+func (x *PurgeDLQResponse) GetArchivedCount() int32 {
+	if x != nil {
+		return x.ArchivedCount
+	}
+	return 0
+}
+
+// How long and how many dead letter queue entries a tenant keeps. Entries past either limit are
+// purged in the background.
+type DLQRetention struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Entries older than this many seconds are purged; 0 keeps them regardless of age
+	MaxAgeSeconds int64 `protobuf:"varint,2,opt,name=max_age_seconds,json=maxAgeSeconds,proto3" json:"max_age_seconds,omitempty"`
+	// Only the newest this many entries are kept; 0 keeps any number
+	MaxEntries int32 `protobuf:"varint,3,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	// Purged entries are copied to the DLQ archive first
+	Archive bool `protobuf:"varint,4,opt,name=archive,proto3" json:"archive,omitempty"`
+	// Timestamp of the last change; unset for the cluster default
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Whether the tenant has no retention of its own and inherits the cluster default
+	ClusterDefault bool `protobuf:"varint,6,opt,name=cluster_default,json=clusterDefault,proto3" json:"cluster_default,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *DLQRetention) Reset() {
+	*x = DLQRetention{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DLQRetention) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DLQRetention) ProtoMessage() {}
+
+func (x *DLQRetention) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DLQRetention.ProtoReflect.Descriptor instead.
+func (*DLQRetention) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *DLQRetention) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DLQRetention) GetMaxAgeSeconds() int64 {
+	if x != nil {
+		return x.MaxAgeSeconds
+	}
+	return 0
+}
+
+func (x *DLQRetention) GetMaxEntries() int32 {
+	if x != nil {
+		return x.MaxEntries
+	}
+	return 0
+}
+
+func (x *DLQRetention) GetArchive() bool {
+	if x != nil {
+		return x.Archive
+	}
+	return false
+}
+
+func (x *DLQRetention) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *DLQRetention) GetClusterDefault() bool {
+	if x != nil {
+		return x.ClusterDefault
+	}
+	return false
+}
+
+type SetDLQRetentionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Purge entries older than this many seconds; 0 keeps them regardless of age
+	MaxAgeSeconds int64 `protobuf:"varint,2,opt,name=max_age_seconds,json=maxAgeSeconds,proto3" json:"max_age_seconds,omitempty"`
+	// Keep only the newest this many entries; 0 keeps any number
+	MaxEntries int32 `protobuf:"varint,3,opt,name=max_entries,json=maxEntries,proto3" json:"max_entries,omitempty"`
+	// Copy purged entries to the DLQ archive first
+	Archive bool `protobuf:"varint,4,opt,name=archive,proto3" json:"archive,omitempty"`
+	// Remove the tenant's retention so it inherits the cluster default; the other fields are ignored
+	Reset_        bool `protobuf:"varint,5,opt,name=reset,proto3" json:"reset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDLQRetentionRequest) Reset() {
+	*x = SetDLQRetentionRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDLQRetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDLQRetentionRequest) ProtoMessage() {}
+
+func (x *SetDLQRetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDLQRetentionRequest.ProtoReflect.Descriptor instead.
+func (*SetDLQRetentionRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *SetDLQRetentionRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetDLQRetentionRequest) GetMaxAgeSeconds() int64 {
+	if x != nil {
+		return x.MaxAgeSeconds
+	}
+	return 0
+}
+
+func (x *SetDLQRetentionRequest) GetMaxEntries() int32 {
+	if x != nil {
+		return x.MaxEntries
+	}
+	return 0
+}
+
+func (x *SetDLQRetentionRequest) GetArchive() bool {
+	if x != nil {
+		return x.Archive
+	}
+	return false
+}
+
+func (x *SetDLQRetentionRequest) GetReset_() bool {
+	if x != nil {
+		return x.Reset_
+	}
+	return false
+}
+
+type SetDLQRetentionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tenant's retention after the change
+	Retention     *DLQRetention `protobuf:"bytes,1,opt,name=retention,proto3" json:"retention,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDLQRetentionResponse) Reset() {
+	*x = SetDLQRetentionResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDLQRetentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDLQRetentionResponse) ProtoMessage() {}
+
+func (x *SetDLQRetentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDLQRetentionResponse.ProtoReflect.Descriptor instead.
+func (*SetDLQRetentionResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *SetDLQRetentionResponse) GetRetention() *DLQRetention {
+	if x != nil {
+		return x.Retention
+	}
+	return nil
+}
+
+type GetDLQRetentionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId      string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDLQRetentionRequest) Reset() {
+	*x = GetDLQRetentionRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDLQRetentionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDLQRetentionRequest) ProtoMessage() {}
+
+func (x *GetDLQRetentionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDLQRetentionRequest.ProtoReflect.Descriptor instead.
+func (*GetDLQRetentionRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *GetDLQRetentionRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type GetDLQRetentionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tenant's retention
+	Retention     *DLQRetention `protobuf:"bytes,1,opt,name=retention,proto3" json:"retention,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDLQRetentionResponse) Reset() {
+	*x = GetDLQRetentionResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDLQRetentionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDLQRetentionResponse) ProtoMessage() {}
+
+func (x *GetDLQRetentionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDLQRetentionResponse.ProtoReflect.Descriptor instead.
+func (*GetDLQRetentionResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *GetDLQRetentionResponse) GetRetention() *DLQRetention {
+	if x != nil {
+		return x.Retention
+	}
+	return nil
+}
+
+// Compliance settings for a tenant
+type ComplianceSettings struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Whether every delivery attempt is recorded
+	RecordRequests bool `protobuf:"varint,2,opt,name=record_requests,json=recordRequests,proto3" json:"record_requests,omitempty"`
+	// Number of days recordings are kept before being purged
+	RetentionDays int32 `protobuf:"varint,3,opt,name=retention_days,json=retentionDays,proto3" json:"retention_days,omitempty"`
+	// Timestamp of the last settings change
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComplianceSettings) Reset() {
+	*x = ComplianceSettings{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComplianceSettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComplianceSettings) ProtoMessage() {}
+
+func (x *ComplianceSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComplianceSettings.ProtoReflect.Descriptor instead.
+func (*ComplianceSettings) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *ComplianceSettings) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ComplianceSettings) GetRecordRequests() bool {
+	if x != nil {
+		return x.RecordRequests
+	}
+	return false
+}
+
+func (x *ComplianceSettings) GetRetentionDays() int32 {
+	if x != nil {
+		return x.RetentionDays
+	}
+	return 0
+}
+
+func (x *ComplianceSettings) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SetComplianceModeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Record the full signed request for every delivery attempt
+	RecordRequests bool `protobuf:"varint,2,opt,name=record_requests,json=recordRequests,proto3" json:"record_requests,omitempty"`
+	// Days to keep recordings (default 30, max 3650)
+	RetentionDays int32 `protobuf:"varint,3,opt,name=retention_days,json=retentionDays,proto3" json:"retention_days,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetComplianceModeRequest) Reset() {
+	*x = SetComplianceModeRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetComplianceModeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetComplianceModeRequest) ProtoMessage() {}
+
+func (x *SetComplianceModeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetComplianceModeRequest.ProtoReflect.Descriptor instead.
+func (*SetComplianceModeRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *SetComplianceModeRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetComplianceModeRequest) GetRecordRequests() bool {
+	if x != nil {
+		return x.RecordRequests
+	}
+	return false
+}
+
+func (x *SetComplianceModeRequest) GetRetentionDays() int32 {
+	if x != nil {
+		return x.RetentionDays
+	}
+	return 0
+}
+
+type SetComplianceModeResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tenant's settings after the change
+	Settings      *ComplianceSettings `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetComplianceModeResponse) Reset() {
+	*x = SetComplianceModeResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetComplianceModeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetComplianceModeResponse) ProtoMessage() {}
+
+func (x *SetComplianceModeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetComplianceModeResponse.ProtoReflect.Descriptor instead.
+func (*SetComplianceModeResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *SetComplianceModeResponse) GetSettings() *ComplianceSettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+// Per-tenant delivery options
+type DeliverySettings struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Whether deliveries carry the tenant ID header (on by default)
+	SenderHeaders bool `protobuf:"varint,2,opt,name=sender_headers,json=senderHeaders,proto3" json:"sender_headers,omitempty"`
+	// Timestamp of the last settings change
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliverySettings) Reset() {
+	*x = DeliverySettings{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliverySettings) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliverySettings) ProtoMessage() {}
+
+func (x *DeliverySettings) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliverySettings.ProtoReflect.Descriptor instead.
+func (*DeliverySettings) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *DeliverySettings) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DeliverySettings) GetSenderHeaders() bool {
+	if x != nil {
+		return x.SenderHeaders
+	}
+	return false
+}
+
+func (x *DeliverySettings) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SetDeliverySettingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Send the tenant ID header with every delivery
+	SenderHeaders bool `protobuf:"varint,2,opt,name=sender_headers,json=senderHeaders,proto3" json:"sender_headers,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDeliverySettingsRequest) Reset() {
+	*x = SetDeliverySettingsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDeliverySettingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDeliverySettingsRequest) ProtoMessage() {}
+
+func (x *SetDeliverySettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDeliverySettingsRequest.ProtoReflect.Descriptor instead.
+func (*SetDeliverySettingsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{85}
+}
+
+func (x *SetDeliverySettingsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SetDeliverySettingsRequest) GetSenderHeaders() bool {
+	if x != nil {
+		return x.SenderHeaders
+	}
+	return false
+}
+
+type SetDeliverySettingsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tenant's settings after the change
+	Settings      *DeliverySettings `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDeliverySettingsResponse) Reset() {
+	*x = SetDeliverySettingsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDeliverySettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDeliverySettingsResponse) ProtoMessage() {}
+
+func (x *SetDeliverySettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDeliverySettingsResponse.ProtoReflect.Descriptor instead.
+func (*SetDeliverySettingsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *SetDeliverySettingsResponse) GetSettings() *DeliverySettings {
+	if x != nil {
+		return x.Settings
+	}
+	return nil
+}
+
+// A delivery request exactly as it was sent to the endpoint
+type DeliveryRecording struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique ID for the recording
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// ID of the delivery the request belongs to
+	DeliveryId string `protobuf:"bytes,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// Attempt number (1-based)
+	Attempt int32 `protobuf:"varint,3,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// HTTP method
+	Method string `protobuf:"bytes,4,opt,name=method,proto3" json:"method,omitempty"`
+	// Target URL
+	Url string `protobuf:"bytes,5,opt,name=url,proto3" json:"url,omitempty"`
+	// Request headers, including the signature and timestamp
+	Headers map[string]string `protobuf:"bytes,6,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Request body as sent
+	Body string `protobuf:"bytes,7,opt,name=body,proto3" json:"body,omitempty"`
+	// Timestamp of when the request was recorded
+	RecordedAt *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=recorded_at,json=recordedAt,proto3" json:"recorded_at,omitempty"`
+	// Timestamp after which the recording is purged
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryRecording) Reset() {
+	*x = DeliveryRecording{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryRecording) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryRecording) ProtoMessage() {}
+
+func (x *DeliveryRecording) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryRecording.ProtoReflect.Descriptor instead.
+func (*DeliveryRecording) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *DeliveryRecording) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeliveryRecording) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *DeliveryRecording) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *DeliveryRecording) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *DeliveryRecording) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DeliveryRecording) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *DeliveryRecording) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *DeliveryRecording) GetRecordedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RecordedAt
+	}
+	return nil
+}
+
+func (x *DeliveryRecording) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// A request a capture endpoint received in place of an HTTP delivery
+type CapturedDelivery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique ID for the capture
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// ID of the delivery the request belongs to
+	DeliveryId string `protobuf:"bytes,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// ID of the event delivered
+	EventId string `protobuf:"bytes,3,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Type of the event delivered
+	EventType string `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	// Attempt number (1-based)
+	Attempt int32 `protobuf:"varint,5,opt,name=attempt,proto3" json:"attempt,omitempty"`
+	// Request headers, including the signature and timestamp
+	Headers map[string]string `protobuf:"bytes,6,rep,name=headers,proto3" json:"headers,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Request body as signed
+	Body string `protobuf:"bytes,7,opt,name=body,proto3" json:"body,omitempty"`
+	// Value of the signature header, to check a receiver's verification code against
+	Signature string `protobuf:"bytes,8,opt,name=signature,proto3" json:"signature,omitempty"`
+	// Timestamp of when the request was captured
+	CapturedAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=captured_at,json=capturedAt,proto3" json:"captured_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CapturedDelivery) Reset() {
+	*x = CapturedDelivery{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapturedDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapturedDelivery) ProtoMessage() {}
+
+func (x *CapturedDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapturedDelivery.ProtoReflect.Descriptor instead.
+func (*CapturedDelivery) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *CapturedDelivery) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CapturedDelivery) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *CapturedDelivery) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *CapturedDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *CapturedDelivery) GetAttempt() int32 {
+	if x != nil {
+		return x.Attempt
+	}
+	return 0
+}
+
+func (x *CapturedDelivery) GetHeaders() map[string]string {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *CapturedDelivery) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+func (x *CapturedDelivery) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *CapturedDelivery) GetCapturedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CapturedAt
+	}
+	return nil
+}
+
+type GetCapturedDeliveriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant that owns the endpoint
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the capture endpoint
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Maximum number of captures to return (default 50, max 500)
+	Limit int32 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Only captures of this delivery
+	DeliveryId    string `protobuf:"bytes,4,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapturedDeliveriesRequest) Reset() {
+	*x = GetCapturedDeliveriesRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapturedDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapturedDeliveriesRequest) ProtoMessage() {}
+
+func (x *GetCapturedDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapturedDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*GetCapturedDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *GetCapturedDeliveriesRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetCapturedDeliveriesRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *GetCapturedDeliveriesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetCapturedDeliveriesRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+type GetCapturedDeliveriesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Captured requests, newest first
+	Captures      []*CapturedDelivery `protobuf:"bytes,1,rep,name=captures,proto3" json:"captures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCapturedDeliveriesResponse) Reset() {
+	*x = GetCapturedDeliveriesResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCapturedDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCapturedDeliveriesResponse) ProtoMessage() {}
+
+func (x *GetCapturedDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCapturedDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*GetCapturedDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *GetCapturedDeliveriesResponse) GetCaptures() []*CapturedDelivery {
+	if x != nil {
+		return x.Captures
+	}
+	return nil
+}
+
+type ListDeliveryRecordingsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant that owns the delivery
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the delivery
+	DeliveryId string `protobuf:"bytes,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	// Why the recording is being accessed, written to the audit log
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeliveryRecordingsRequest) Reset() {
+	*x = ListDeliveryRecordingsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeliveryRecordingsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveryRecordingsRequest) ProtoMessage() {}
+
+func (x *ListDeliveryRecordingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveryRecordingsRequest.ProtoReflect.Descriptor instead.
+func (*ListDeliveryRecordingsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *ListDeliveryRecordingsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListDeliveryRecordingsRequest) GetDeliveryId() string {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return ""
+}
+
+func (x *ListDeliveryRecordingsRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ListDeliveryRecordingsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Recorded requests, oldest attempt first
+	Recordings    []*DeliveryRecording `protobuf:"bytes,1,rep,name=recordings,proto3" json:"recordings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeliveryRecordingsResponse) Reset() {
+	*x = ListDeliveryRecordingsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeliveryRecordingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveryRecordingsResponse) ProtoMessage() {}
+
+func (x *ListDeliveryRecordingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveryRecordingsResponse.ProtoReflect.Descriptor instead.
+func (*ListDeliveryRecordingsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *ListDeliveryRecordingsResponse) GetRecordings() []*DeliveryRecording {
+	if x != nil {
+		return x.Recordings
+	}
+	return nil
+}
+
+// A management operation recorded in the audit log
+type AuditLogEntry struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique ID for the entry; IDs increase over time
+	Id int64 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Tenant whose resources the operation changed
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// What was done, such as endpoint.create or dlq.purge
+	Action string `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	// Kind of resource changed: endpoint, delivery or dlq
+	ResourceType string `protobuf:"bytes,4,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	// ID of the changed resource (for dlq, the endpoint or tenant the operation was scoped to)
+	ResourceId string `protobuf:"bytes,5,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	// Tenant of the caller's token (empty when the request was not authenticated)
+	ActorTenantId string `protobuf:"bytes,6,opt,name=actor_tenant_id,json=actorTenantId,proto3" json:"actor_tenant_id,omitempty"`
+	// Subject of the caller's token
+	ActorSubject string `protobuf:"bytes,7,opt,name=actor_subject,json=actorSubject,proto3" json:"actor_subject,omitempty"`
+	// Role the caller acted with
+	ActorRole string `protobuf:"bytes,8,opt,name=actor_role,json=actorRole,proto3" json:"actor_role,omitempty"`
+	// Client address as seen by ingest, from X-Forwarded-For when a proxy set it
+	ClientIp string `protobuf:"bytes,9,opt,name=client_ip,json=clientIp,proto3" json:"client_ip,omitempty"`
+	// The resource before the operation; for DLQ operations, the request
+	Before *structpb.Struct `protobuf:"bytes,10,opt,name=before,proto3" json:"before,omitempty"`
+	// The resource after the operation; for DLQ operations, the response
+	After *structpb.Struct `protobuf:"bytes,11,opt,name=after,proto3" json:"after,omitempty"`
+	// When the operation completed
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuditLogEntry) Reset() {
+	*x = AuditLogEntry{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuditLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuditLogEntry) ProtoMessage() {}
+
+func (x *AuditLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuditLogEntry.ProtoReflect.Descriptor instead.
+func (*AuditLogEntry) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *AuditLogEntry) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AuditLogEntry) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetActorTenantId() string {
+	if x != nil {
+		return x.ActorTenantId
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetActorSubject() string {
+	if x != nil {
+		return x.ActorSubject
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetActorRole() string {
+	if x != nil {
+		return x.ActorRole
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetClientIp() string {
+	if x != nil {
+		return x.ClientIp
+	}
+	return ""
+}
+
+func (x *AuditLogEntry) GetBefore() *structpb.Struct {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *AuditLogEntry) GetAfter() *structpb.Struct {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *AuditLogEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListAuditLogRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Only entries for this action
+	Action string `protobuf:"bytes,2,opt,name=action,proto3" json:"action,omitempty"`
+	// Only entries for this resource
+	ResourceId string `protobuf:"bytes,3,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	// Only entries made by this token subject
+	ActorSubject string `protobuf:"bytes,4,opt,name=actor_subject,json=actorSubject,proto3" json:"actor_subject,omitempty"`
+	// Only entries recorded at or after this time
+	From *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	// Only entries recorded before this time
+	To *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	// Limit the number of results (default 50, max 500)
+	Limit int32 `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Page token from a previous response's next_page_token
+	PageToken     string `protobuf:"bytes,8,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogRequest) Reset() {
+	*x = ListAuditLogRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogRequest) ProtoMessage() {}
+
+func (x *ListAuditLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogRequest.ProtoReflect.Descriptor instead.
+func (*ListAuditLogRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *ListAuditLogRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetActorSubject() string {
+	if x != nil {
+		return x.ActorSubject
+	}
+	return ""
+}
+
+func (x *ListAuditLogRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListAuditLogRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListAuditLogRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAuditLogRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+type ListAuditLogResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Entries, newest first
+	Entries []*AuditLogEntry `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	// Token for the next page. Empty when there are no more results
+	NextPageToken string `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAuditLogResponse) Reset() {
+	*x = ListAuditLogResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAuditLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAuditLogResponse) ProtoMessage() {}
+
+func (x *ListAuditLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAuditLogResponse.ProtoReflect.Descriptor instead.
+func (*ListAuditLogResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *ListAuditLogResponse) GetEntries() []*AuditLogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+func (x *ListAuditLogResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// An active or released delivery freeze
+type DeliveryFreeze struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique ID for the freeze
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Frozen tenant (set when the freeze targets a tenant)
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Frozen endpoint (set when the freeze targets an endpoint)
+	EndpointId string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Why deliveries were frozen
+	Reason string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Timestamp of when the freeze started
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Timestamp of when the freeze was lifted, unset while active
+	ReleasedAt    *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=released_at,json=releasedAt,proto3" json:"released_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryFreeze) Reset() {
+	*x = DeliveryFreeze{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryFreeze) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryFreeze) ProtoMessage() {}
+
+func (x *DeliveryFreeze) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryFreeze.ProtoReflect.Descriptor instead.
+func (*DeliveryFreeze) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{96}
+}
+
+func (x *DeliveryFreeze) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeliveryFreeze) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DeliveryFreeze) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *DeliveryFreeze) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DeliveryFreeze) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *DeliveryFreeze) GetReleasedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReleasedAt
+	}
+	return nil
+}
+
+// Set exactly one of tenant_id or endpoint_id
+type FreezeDeliveriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant whose deliveries are frozen
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Endpoint whose deliveries are frozen
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Why deliveries are being frozen
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeDeliveriesRequest) Reset() {
+	*x = FreezeDeliveriesRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeDeliveriesRequest) ProtoMessage() {}
+
+func (x *FreezeDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*FreezeDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *FreezeDeliveriesRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *FreezeDeliveriesRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *FreezeDeliveriesRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type FreezeDeliveriesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The freeze that was created
+	Freeze *DeliveryFreeze `protobuf:"bytes,1,opt,name=freeze,proto3" json:"freeze,omitempty"`
+	// Deliveries currently queued or awaiting retry that will be parked when dequeued
+	PendingCount  int32 `protobuf:"varint,2,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FreezeDeliveriesResponse) Reset() {
+	*x = FreezeDeliveriesResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FreezeDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FreezeDeliveriesResponse) ProtoMessage() {}
+
+func (x *FreezeDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FreezeDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*FreezeDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *FreezeDeliveriesResponse) GetFreeze() *DeliveryFreeze {
+	if x != nil {
+		return x.Freeze
+	}
+	return nil
+}
+
+func (x *FreezeDeliveriesResponse) GetPendingCount() int32 {
+	if x != nil {
+		return x.PendingCount
+	}
+	return 0
+}
+
+// Set exactly one of tenant_id or endpoint_id
+type DrainQueueRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant whose pending deliveries are parked
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Endpoint whose pending deliveries are parked
+	EndpointId    string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainQueueRequest) Reset() {
+	*x = DrainQueueRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainQueueRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainQueueRequest) ProtoMessage() {}
+
+func (x *DrainQueueRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainQueueRequest.ProtoReflect.Descriptor instead.
+func (*DrainQueueRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *DrainQueueRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DrainQueueRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+type DrainQueueResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of deliveries parked
+	ParkedCount   int32 `protobuf:"varint,1,opt,name=parked_count,json=parkedCount,proto3" json:"parked_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DrainQueueResponse) Reset() {
+	*x = DrainQueueResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DrainQueueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DrainQueueResponse) ProtoMessage() {}
+
+func (x *DrainQueueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DrainQueueResponse.ProtoReflect.Descriptor instead.
+func (*DrainQueueResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *DrainQueueResponse) GetParkedCount() int32 {
+	if x != nil {
+		return x.ParkedCount
+	}
+	return 0
+}
+
+// Set exactly one of tenant_id or endpoint_id
+type ResumeDeliveriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant to resume
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Endpoint to resume
+	EndpointId    string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeDeliveriesRequest) Reset() {
+	*x = ResumeDeliveriesRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[101]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeDeliveriesRequest) ProtoMessage() {}
+
+func (x *ResumeDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[101]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ResumeDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{101}
+}
+
+func (x *ResumeDeliveriesRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ResumeDeliveriesRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+type ResumeDeliveriesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Number of freezes lifted
+	ReleasedFreezes int32 `protobuf:"varint,1,opt,name=released_freezes,json=releasedFreezes,proto3" json:"released_freezes,omitempty"`
+	// Number of parked deliveries requeued. Deliveries still covered by another freeze stay parked
+	RequeuedCount int32 `protobuf:"varint,2,opt,name=requeued_count,json=requeuedCount,proto3" json:"requeued_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeDeliveriesResponse) Reset() {
+	*x = ResumeDeliveriesResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[102]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeDeliveriesResponse) ProtoMessage() {}
+
+func (x *ResumeDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[102]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ResumeDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{102}
+}
+
+func (x *ResumeDeliveriesResponse) GetReleasedFreezes() int32 {
+	if x != nil {
+		return x.ReleasedFreezes
+	}
+	return 0
+}
+
+func (x *ResumeDeliveriesResponse) GetRequeuedCount() int32 {
+	if x != nil {
+		return x.RequeuedCount
+	}
+	return 0
+}
+
+// State of the cluster-wide dispatch kill switch
+type DispatchState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Whether all outbound deliveries are paused
+	Paused bool `protobuf:"varint,1,opt,name=paused,proto3" json:"paused,omitempty"`
+	// Reason given when pausing
+	Reason string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Timestamp of the last pause
+	PausedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=paused_at,json=pausedAt,proto3" json:"paused_at,omitempty"`
+	// Timestamp of the last resume
+	ResumedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=resumed_at,json=resumedAt,proto3" json:"resumed_at,omitempty"`
+	// Length of the ramp-up after the last resume, in seconds
+	RampSeconds int32 `protobuf:"varint,5,opt,name=ramp_seconds,json=rampSeconds,proto3" json:"ramp_seconds,omitempty"`
+	// Percentage of traffic admitted when the ramp-up starts
+	RampStartPercent int32 `protobuf:"varint,6,opt,name=ramp_start_percent,json=rampStartPercent,proto3" json:"ramp_start_percent,omitempty"`
+	// Percentage of traffic workers currently dispatch
+	AdmitPercent  float64 `protobuf:"fixed64,7,opt,name=admit_percent,json=admitPercent,proto3" json:"admit_percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DispatchState) Reset() {
+	*x = DispatchState{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[103]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DispatchState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DispatchState) ProtoMessage() {}
+
+func (x *DispatchState) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[103]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DispatchState.ProtoReflect.Descriptor instead.
+func (*DispatchState) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{103}
+}
+
+func (x *DispatchState) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *DispatchState) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DispatchState) GetPausedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PausedAt
+	}
+	return nil
+}
+
+func (x *DispatchState) GetResumedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResumedAt
+	}
+	return nil
+}
+
+func (x *DispatchState) GetRampSeconds() int32 {
+	if x != nil {
+		return x.RampSeconds
+	}
+	return 0
+}
+
+func (x *DispatchState) GetRampStartPercent() int32 {
+	if x != nil {
+		return x.RampStartPercent
+	}
+	return 0
+}
+
+func (x *DispatchState) GetAdmitPercent() float64 {
+	if x != nil {
+		return x.AdmitPercent
+	}
+	return 0
+}
+
+type PauseDispatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Why deliveries are being paused
+	Reason        string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseDispatchRequest) Reset() {
+	*x = PauseDispatchRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[104]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseDispatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseDispatchRequest) ProtoMessage() {}
+
+func (x *PauseDispatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[104]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseDispatchRequest.ProtoReflect.Descriptor instead.
+func (*PauseDispatchRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{104}
+}
+
+func (x *PauseDispatchRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type PauseDispatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The kill switch state after pausing
+	State         *DispatchState `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseDispatchResponse) Reset() {
+	*x = PauseDispatchResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[105]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseDispatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseDispatchResponse) ProtoMessage() {}
+
+func (x *PauseDispatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[105]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseDispatchResponse.ProtoReflect.Descriptor instead.
+func (*PauseDispatchResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{105}
+}
+
+func (x *PauseDispatchResponse) GetState() *DispatchState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type ResumeDispatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ramp traffic up to 100% over this many seconds. 0 resumes at full rate
+	RampSeconds int32 `protobuf:"varint,1,opt,name=ramp_seconds,json=rampSeconds,proto3" json:"ramp_seconds,omitempty"`
+	// Percentage of traffic admitted when the ramp-up starts (default 10)
+	StartPercent  int32 `protobuf:"varint,2,opt,name=start_percent,json=startPercent,proto3" json:"start_percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeDispatchRequest) Reset() {
+	*x = ResumeDispatchRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[106]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeDispatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeDispatchRequest) ProtoMessage() {}
+
+func (x *ResumeDispatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[106]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeDispatchRequest.ProtoReflect.Descriptor instead.
+func (*ResumeDispatchRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{106}
+}
+
+func (x *ResumeDispatchRequest) GetRampSeconds() int32 {
+	if x != nil {
+		return x.RampSeconds
+	}
+	return 0
+}
+
+func (x *ResumeDispatchRequest) GetStartPercent() int32 {
+	if x != nil {
+		return x.StartPercent
+	}
+	return 0
+}
+
+type ResumeDispatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The kill switch state after resuming
+	State         *DispatchState `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeDispatchResponse) Reset() {
+	*x = ResumeDispatchResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[107]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeDispatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeDispatchResponse) ProtoMessage() {}
+
+func (x *ResumeDispatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[107]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeDispatchResponse.ProtoReflect.Descriptor instead.
+func (*ResumeDispatchResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{107}
+}
+
+func (x *ResumeDispatchResponse) GetState() *DispatchState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type GetDispatchStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDispatchStateRequest) Reset() {
+	*x = GetDispatchStateRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[108]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDispatchStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDispatchStateRequest) ProtoMessage() {}
+
+func (x *GetDispatchStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[108]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDispatchStateRequest.ProtoReflect.Descriptor instead.
+func (*GetDispatchStateRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{108}
+}
+
+type GetDispatchStateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The current kill switch state
+	State         *DispatchState `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDispatchStateResponse) Reset() {
+	*x = GetDispatchStateResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[109]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDispatchStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDispatchStateResponse) ProtoMessage() {}
+
+func (x *GetDispatchStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[109]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDispatchStateResponse.ProtoReflect.Descriptor instead.
+func (*GetDispatchStateResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{109}
+}
+
+func (x *GetDispatchStateResponse) GetState() *DispatchState {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type GetBacklogEstimateRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Tenant whose backlog is estimated
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Endpoint whose backlog is estimated
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// How far back to measure throughput, in seconds (default 300)
+	WindowSeconds int32 `protobuf:"varint,3,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBacklogEstimateRequest) Reset() {
+	*x = GetBacklogEstimateRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[110]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBacklogEstimateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBacklogEstimateRequest) ProtoMessage() {}
+
+func (x *GetBacklogEstimateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[110]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBacklogEstimateRequest.ProtoReflect.Descriptor instead.
+func (*GetBacklogEstimateRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{110}
+}
+
+func (x *GetBacklogEstimateRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetBacklogEstimateRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *GetBacklogEstimateRequest) GetWindowSeconds() int32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+type BacklogEstimate struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Endpoint the estimate is for (empty for the combined estimate)
+	EndpointId string `protobuf:"bytes,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Queued, in-flight and retrying deliveries
+	PendingCount int32 `protobuf:"varint,2,opt,name=pending_count,json=pendingCount,proto3" json:"pending_count,omitempty"`
+	// Deliveries parked by a freeze or drain; not counted as pending
+	ParkedCount int32 `protobuf:"varint,3,opt,name=parked_count,json=parkedCount,proto3" json:"parked_count,omitempty"`
+	// Expected deliveries per second, from recent throughput capped by rate limits
+	ThroughputPerSec float64 `protobuf:"fixed64,4,opt,name=throughput_per_sec,json=throughputPerSec,proto3" json:"throughput_per_sec,omitempty"`
+	// Whether the backlog is draining and eta_seconds is meaningful
+	HasEta bool `protobuf:"varint,5,opt,name=has_eta,json=hasEta,proto3" json:"has_eta,omitempty"`
+	// Seconds until the pending deliveries clear
+	EtaSeconds int64 `protobuf:"varint,6,opt,name=eta_seconds,json=etaSeconds,proto3" json:"eta_seconds,omitempty"`
+	// When the pending deliveries are expected to clear
+	ClearsAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=clears_at,json=clearsAt,proto3" json:"clears_at,omitempty"`
+	// Why there is no estimate (dispatch paused, frozen, no recent throughput)
+	BlockedReason string `protobuf:"bytes,8,opt,name=blocked_reason,json=blockedReason,proto3" json:"blocked_reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BacklogEstimate) Reset() {
+	*x = BacklogEstimate{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[111]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BacklogEstimate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BacklogEstimate) ProtoMessage() {}
+
+func (x *BacklogEstimate) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[111]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BacklogEstimate.ProtoReflect.Descriptor instead.
+func (*BacklogEstimate) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{111}
+}
+
+func (x *BacklogEstimate) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *BacklogEstimate) GetPendingCount() int32 {
+	if x != nil {
+		return x.PendingCount
+	}
+	return 0
+}
+
+func (x *BacklogEstimate) GetParkedCount() int32 {
+	if x != nil {
+		return x.ParkedCount
+	}
+	return 0
+}
+
+func (x *BacklogEstimate) GetThroughputPerSec() float64 {
+	if x != nil {
+		return x.ThroughputPerSec
+	}
+	return 0
+}
+
+func (x *BacklogEstimate) GetHasEta() bool {
+	if x != nil {
+		return x.HasEta
+	}
+	return false
+}
+
+func (x *BacklogEstimate) GetEtaSeconds() int64 {
+	if x != nil {
+		return x.EtaSeconds
+	}
+	return 0
+}
+
+func (x *BacklogEstimate) GetClearsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ClearsAt
+	}
+	return nil
+}
+
+func (x *BacklogEstimate) GetBlockedReason() string {
+	if x != nil {
+		return x.BlockedReason
+	}
+	return ""
+}
+
+type GetBacklogEstimateResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Estimate for the whole target; it clears when the slowest endpoint does
+	Total *BacklogEstimate `protobuf:"bytes,1,opt,name=total,proto3" json:"total,omitempty"`
+	// Per-endpoint estimates
+	Endpoints []*BacklogEstimate `protobuf:"bytes,2,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	// Throughput window used, in seconds
+	WindowSeconds int32 `protobuf:"varint,3,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBacklogEstimateResponse) Reset() {
+	*x = GetBacklogEstimateResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[112]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBacklogEstimateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBacklogEstimateResponse) ProtoMessage() {}
+
+func (x *GetBacklogEstimateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[112]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBacklogEstimateResponse.ProtoReflect.Descriptor instead.
+func (*GetBacklogEstimateResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{112}
+}
+
+func (x *GetBacklogEstimateResponse) GetTotal() *BacklogEstimate {
+	if x != nil {
+		return x.Total
+	}
+	return nil
+}
+
+func (x *GetBacklogEstimateResponse) GetEndpoints() []*BacklogEstimate {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+func (x *GetBacklogEstimateResponse) GetWindowSeconds() int32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+// Publishing quotas for a tenant; 0 means unlimited
+type TenantQuota struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Events the tenant may publish per minute
+	EventsPerMinute int32 `protobuf:"varint,2,opt,name=events_per_minute,json=eventsPerMinute,proto3" json:"events_per_minute,omitempty"`
+	// Endpoints a single event may fan out to
+	MaxFanout int32 `protobuf:"varint,3,opt,name=max_fanout,json=maxFanout,proto3" json:"max_fanout,omitempty"`
+	// Timestamp of the last quota change
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TenantQuota) Reset() {
+	*x = TenantQuota{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[113]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TenantQuota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TenantQuota) ProtoMessage() {}
+
+func (x *TenantQuota) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[113]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TenantQuota.ProtoReflect.Descriptor instead.
+func (*TenantQuota) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{113}
+}
+
+func (x *TenantQuota) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *TenantQuota) GetEventsPerMinute() int32 {
+	if x != nil {
+		return x.EventsPerMinute
+	}
+	return 0
+}
+
+func (x *TenantQuota) GetMaxFanout() int32 {
+	if x != nil {
+		return x.MaxFanout
+	}
+	return 0
+}
+
+func (x *TenantQuota) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SetTenantQuotaRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The quotas to apply
+	Quota         *TenantQuota `protobuf:"bytes,1,opt,name=quota,proto3" json:"quota,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTenantQuotaRequest) Reset() {
+	*x = SetTenantQuotaRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[114]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTenantQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTenantQuotaRequest) ProtoMessage() {}
+
+func (x *SetTenantQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[114]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTenantQuotaRequest.ProtoReflect.Descriptor instead.
+func (*SetTenantQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{114}
+}
+
+func (x *SetTenantQuotaRequest) GetQuota() *TenantQuota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+type SetTenantQuotaResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The saved quotas
+	Quota         *TenantQuota `protobuf:"bytes,1,opt,name=quota,proto3" json:"quota,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetTenantQuotaResponse) Reset() {
+	*x = SetTenantQuotaResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[115]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetTenantQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetTenantQuotaResponse) ProtoMessage() {}
+
+func (x *SetTenantQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[115]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetTenantQuotaResponse.ProtoReflect.Descriptor instead.
+func (*SetTenantQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{115}
+}
+
+func (x *SetTenantQuotaResponse) GetQuota() *TenantQuota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+type GetTenantQuotaRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId      string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTenantQuotaRequest) Reset() {
+	*x = GetTenantQuotaRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[116]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTenantQuotaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantQuotaRequest) ProtoMessage() {}
+
+func (x *GetTenantQuotaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[116]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantQuotaRequest.ProtoReflect.Descriptor instead.
+func (*GetTenantQuotaRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{116}
+}
+
+func (x *GetTenantQuotaRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type GetTenantQuotaResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tenant's quotas (unlimited when none were set)
+	Quota *TenantQuota `protobuf:"bytes,1,opt,name=quota,proto3" json:"quota,omitempty"`
+	// Events published in the current minute
+	EventsThisMinute int32 `protobuf:"varint,2,opt,name=events_this_minute,json=eventsThisMinute,proto3" json:"events_this_minute,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetTenantQuotaResponse) Reset() {
+	*x = GetTenantQuotaResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[117]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTenantQuotaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantQuotaResponse) ProtoMessage() {}
+
+func (x *GetTenantQuotaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[117]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantQuotaResponse.ProtoReflect.Descriptor instead.
+func (*GetTenantQuotaResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{117}
+}
+
+func (x *GetTenantQuotaResponse) GetQuota() *TenantQuota {
+	if x != nil {
+		return x.Quota
+	}
+	return nil
+}
+
+func (x *GetTenantQuotaResponse) GetEventsThisMinute() int32 {
+	if x != nil {
+		return x.EventsThisMinute
+	}
+	return 0
+}
+
+type GetFailureTrendsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// How far back to look, in seconds (default 21600 = 6h, max 604800 = 7d)
+	WindowSeconds int32 `protobuf:"varint,2,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	// Bucket width in seconds (default 900 = 15m, min 60); at most 1000 buckets per window
+	BucketSeconds int32 `protobuf:"varint,3,opt,name=bucket_seconds,json=bucketSeconds,proto3" json:"bucket_seconds,omitempty"`
+	// Only failures for this endpoint
+	EndpointId    string `protobuf:"bytes,4,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFailureTrendsRequest) Reset() {
+	*x = GetFailureTrendsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[118]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFailureTrendsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFailureTrendsRequest) ProtoMessage() {}
+
+func (x *GetFailureTrendsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[118]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFailureTrendsRequest.ProtoReflect.Descriptor instead.
+func (*GetFailureTrendsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{118}
+}
+
+func (x *GetFailureTrendsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetFailureTrendsRequest) GetWindowSeconds() int32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *GetFailureTrendsRequest) GetBucketSeconds() int32 {
+	if x != nil {
+		return x.BucketSeconds
+	}
+	return 0
+}
+
+func (x *GetFailureTrendsRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+// Failed deliveries for one reason and endpoint
+type FailureCount struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Failure reason, e.g. http_503, timeout, connection_refused, tls
+	Reason string `protobuf:"bytes,1,opt,name=reason,proto3" json:"reason,omitempty"`
+	// ID of the endpoint the deliveries failed against
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Number of failed deliveries
+	Count         int64 `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailureCount) Reset() {
+	*x = FailureCount{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[119]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailureCount) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailureCount) ProtoMessage() {}
+
+func (x *FailureCount) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[119]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailureCount.ProtoReflect.Descriptor instead.
+func (*FailureCount) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{119}
+}
+
+func (x *FailureCount) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *FailureCount) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *FailureCount) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+// Failures whose latest attempt failed within [start, start + bucket_seconds)
+type FailureBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Start of the bucket
+	Start *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	// Counts by reason and endpoint, largest first
+	Failures      []*FailureCount `protobuf:"bytes,2,rep,name=failures,proto3" json:"failures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FailureBucket) Reset() {
+	*x = FailureBucket{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[120]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FailureBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FailureBucket) ProtoMessage() {}
+
+func (x *FailureBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[120]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FailureBucket.ProtoReflect.Descriptor instead.
+func (*FailureBucket) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{120}
+}
+
+func (x *FailureBucket) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *FailureBucket) GetFailures() []*FailureCount {
+	if x != nil {
+		return x.Failures
+	}
+	return nil
+}
+
+type GetFailureTrendsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Every bucket in the window, oldest first (empty buckets included)
+	Buckets []*FailureBucket `protobuf:"bytes,1,rep,name=buckets,proto3" json:"buckets,omitempty"`
+	// Counts across the whole window by reason and endpoint, largest first
+	Totals []*FailureCount `protobuf:"bytes,2,rep,name=totals,proto3" json:"totals,omitempty"`
+	// Window used, in seconds
+	WindowSeconds int32 `protobuf:"varint,3,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	// Bucket width used, in seconds
+	BucketSeconds int32 `protobuf:"varint,4,opt,name=bucket_seconds,json=bucketSeconds,proto3" json:"bucket_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetFailureTrendsResponse) Reset() {
+	*x = GetFailureTrendsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[121]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetFailureTrendsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetFailureTrendsResponse) ProtoMessage() {}
+
+func (x *GetFailureTrendsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[121]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetFailureTrendsResponse.ProtoReflect.Descriptor instead.
+func (*GetFailureTrendsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{121}
+}
+
+func (x *GetFailureTrendsResponse) GetBuckets() []*FailureBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
+func (x *GetFailureTrendsResponse) GetTotals() []*FailureCount {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+func (x *GetFailureTrendsResponse) GetWindowSeconds() int32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *GetFailureTrendsResponse) GetBucketSeconds() int32 {
+	if x != nil {
+		return x.BucketSeconds
+	}
+	return 0
+}
+
+type GetDeliveryStatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// How far back to look, by delivery creation time, in seconds (default 86400 = 24h, max 604800 = 7d)
+	WindowSeconds int32 `protobuf:"varint,2,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	// Only deliveries to this endpoint
+	EndpointId string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Only deliveries of this event type
+	EventType     string `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatsRequest) Reset() {
+	*x = GetDeliveryStatsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[122]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatsRequest) ProtoMessage() {}
+
+func (x *GetDeliveryStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[122]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{122}
+}
+
+func (x *GetDeliveryStatsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetDeliveryStatsRequest) GetWindowSeconds() int32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+func (x *GetDeliveryStatsRequest) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *GetDeliveryStatsRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+// Aggregates over a set of deliveries
+type DeliveryStats struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID of the endpoint, empty for the totals across endpoints
+	EndpointId string `protobuf:"bytes,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Number of deliveries
+	Total int64 `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	// Deliveries by current status
+	Queued    int64 `protobuf:"varint,3,opt,name=queued,proto3" json:"queued,omitempty"`
+	Inflight  int64 `protobuf:"varint,4,opt,name=inflight,proto3" json:"inflight,omitempty"`
+	Delivered int64 `protobuf:"varint,5,opt,name=delivered,proto3" json:"delivered,omitempty"`
+	Failed    int64 `protobuf:"varint,6,opt,name=failed,proto3" json:"failed,omitempty"`
+	Dead      int64 `protobuf:"varint,7,opt,name=dead,proto3" json:"dead,omitempty"`
+	Parked    int64 `protobuf:"varint,8,opt,name=parked,proto3" json:"parked,omitempty"`
+	// delivered / (delivered + dead): the share of finished deliveries that succeeded, 0 when none finished
+	SuccessRate float64 `protobuf:"fixed64,9,opt,name=success_rate,json=successRate,proto3" json:"success_rate,omitempty"`
+	// Latency of the successful attempt of delivered deliveries, in milliseconds
+	LatencyP50Ms int32 `protobuf:"varint,10,opt,name=latency_p50_ms,json=latencyP50Ms,proto3" json:"latency_p50_ms,omitempty"`
+	LatencyP95Ms int32 `protobuf:"varint,11,opt,name=latency_p95_ms,json=latencyP95Ms,proto3" json:"latency_p95_ms,omitempty"`
+	LatencyP99Ms int32 `protobuf:"varint,12,opt,name=latency_p99_ms,json=latencyP99Ms,proto3" json:"latency_p99_ms,omitempty"`
+	// Attempts after the first, summed over the deliveries
+	Retries int64 `protobuf:"varint,13,opt,name=retries,proto3" json:"retries,omitempty"`
+	// Deliveries whose latest attempt failed (failed or dead) by failure reason, largest first, at most 5
+	TopFailures   []*FailureCount `protobuf:"bytes,14,rep,name=top_failures,json=topFailures,proto3" json:"top_failures,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryStats) Reset() {
+	*x = DeliveryStats{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[123]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryStats) ProtoMessage() {}
+
+func (x *DeliveryStats) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[123]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryStats.ProtoReflect.Descriptor instead.
+func (*DeliveryStats) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{123}
+}
+
+func (x *DeliveryStats) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *DeliveryStats) GetTotal() int64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetQueued() int64 {
+	if x != nil {
+		return x.Queued
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetInflight() int64 {
+	if x != nil {
+		return x.Inflight
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetDelivered() int64 {
+	if x != nil {
+		return x.Delivered
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetFailed() int64 {
+	if x != nil {
+		return x.Failed
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetDead() int64 {
+	if x != nil {
+		return x.Dead
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetParked() int64 {
+	if x != nil {
+		return x.Parked
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetSuccessRate() float64 {
+	if x != nil {
+		return x.SuccessRate
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetLatencyP50Ms() int32 {
+	if x != nil {
+		return x.LatencyP50Ms
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetLatencyP95Ms() int32 {
+	if x != nil {
+		return x.LatencyP95Ms
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetLatencyP99Ms() int32 {
+	if x != nil {
+		return x.LatencyP99Ms
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetRetries() int64 {
+	if x != nil {
+		return x.Retries
+	}
+	return 0
+}
+
+func (x *DeliveryStats) GetTopFailures() []*FailureCount {
+	if x != nil {
+		return x.TopFailures
+	}
+	return nil
+}
+
+type GetDeliveryStatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Across every matching delivery
+	Totals *DeliveryStats `protobuf:"bytes,1,opt,name=totals,proto3" json:"totals,omitempty"`
+	// One entry per endpoint, most deliveries first
+	Endpoints []*DeliveryStats `protobuf:"bytes,2,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
+	// Window used, in seconds
+	WindowSeconds int32 `protobuf:"varint,3,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDeliveryStatsResponse) Reset() {
+	*x = GetDeliveryStatsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[124]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDeliveryStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDeliveryStatsResponse) ProtoMessage() {}
+
+func (x *GetDeliveryStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[124]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDeliveryStatsResponse.ProtoReflect.Descriptor instead.
+func (*GetDeliveryStatsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{124}
+}
+
+func (x *GetDeliveryStatsResponse) GetTotals() *DeliveryStats {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+func (x *GetDeliveryStatsResponse) GetEndpoints() []*DeliveryStats {
+	if x != nil {
+		return x.Endpoints
+	}
+	return nil
+}
+
+func (x *GetDeliveryStatsResponse) GetWindowSeconds() int32 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+// A condition harborhook detected itself, as opposed to an event a tenant published
+type SystemEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique ID for the system event
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// ID of the endpoint the event is about, if any
+	EndpointId string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Event type, e.g. endpoint.status_anomaly
+	Type string `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`
+	// Human-readable summary
+	Message string `protobuf:"bytes,5,opt,name=message,proto3" json:"message,omitempty"`
+	// Type-specific details
+	Details *structpb.Struct `protobuf:"bytes,6,opt,name=details,proto3" json:"details,omitempty"`
+	// When the event was recorded
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemEvent) Reset() {
+	*x = SystemEvent{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[125]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemEvent) ProtoMessage() {}
+
+func (x *SystemEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[125]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemEvent.ProtoReflect.Descriptor instead.
+func (*SystemEvent) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{125}
+}
+
+func (x *SystemEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SystemEvent) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *SystemEvent) GetEndpointId() string {
+	if x != nil {
+		return x.EndpointId
+	}
+	return ""
+}
+
+func (x *SystemEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SystemEvent) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *SystemEvent) GetDetails() *structpb.Struct {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+func (x *SystemEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListSystemEventsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Only events of this type
+	Type string `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	// Only events recorded at or after this time
+	Since *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=since,proto3" json:"since,omitempty"`
+	// Limit the number of results (default 50, max 500)
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSystemEventsRequest) Reset() {
+	*x = ListSystemEventsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[126]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSystemEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSystemEventsRequest) ProtoMessage() {}
+
+func (x *ListSystemEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[126]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSystemEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListSystemEventsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{126}
+}
+
+func (x *ListSystemEventsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListSystemEventsRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ListSystemEventsRequest) GetSince() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Since
+	}
+	return nil
+}
+
+func (x *ListSystemEventsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListSystemEventsResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The newly created endpoint
-	Endpoint      *Endpoint `protobuf:"bytes,1,opt,name=endpoint,proto3" json:"endpoint,omitempty"`
+	// System events, newest first
+	Events        []*SystemEvent `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateEndpointResponse) Reset() {
-	*x = CreateEndpointResponse{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[5]
+func (x *ListSystemEventsResponse) Reset() {
+	*x = ListSystemEventsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[127]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateEndpointResponse) String() string {
+func (x *ListSystemEventsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateEndpointResponse) ProtoMessage() {}
+func (*ListSystemEventsResponse) ProtoMessage() {}
 
-func (x *CreateEndpointResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[5]
+func (x *ListSystemEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[127]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -423,46 +9068,53 @@ func (x *CreateEndpointResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateEndpointResponse.ProtoReflect.Descriptor instead.
-func (*CreateEndpointResponse) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListSystemEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListSystemEventsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{127}
 }
 
-func (x *CreateEndpointResponse) GetEndpoint() *Endpoint {
+func (x *ListSystemEventsResponse) GetEvents() []*SystemEvent {
 	if x != nil {
-		return x.Endpoint
+		return x.Events
 	}
 	return nil
 }
 
-// Create subscription request message
-type CreateSubscriptionRequest struct {
+// Where a tenant's operational notices go. Notices of a disabled endpoint are always sent;
+// the thresholds turn on the others.
+type NotificationSettings struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Tenant ID for the subscription
+	// ID for the tenant
 	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	// Event type that this subscription is for
-	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
-	// Endpoint ID that this subscription is a member of
-	EndpointId    string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Email address notices are sent to; empty sends none by email
+	Email string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	// Endpoint notices are delivered to as harborhook.notice.* events; empty delivers none
+	EndpointId string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Notify when the tenant's DLQ holds more than this many entries; 0 never notifies
+	DlqThreshold int32 `protobuf:"varint,4,opt,name=dlq_threshold,json=dlqThreshold,proto3" json:"dlq_threshold,omitempty"`
+	// Notify when an endpoint's signing secret is older than this many days; 0 never notifies
+	SecretMaxAgeDays int32 `protobuf:"varint,5,opt,name=secret_max_age_days,json=secretMaxAgeDays,proto3" json:"secret_max_age_days,omitempty"`
+	// Timestamp of the last change; unset when the tenant has no settings
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateSubscriptionRequest) Reset() {
-	*x = CreateSubscriptionRequest{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[6]
+func (x *NotificationSettings) Reset() {
+	*x = NotificationSettings{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[128]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateSubscriptionRequest) String() string {
+func (x *NotificationSettings) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSubscriptionRequest) ProtoMessage() {}
+func (*NotificationSettings) ProtoMessage() {}
 
-func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[6]
+func (x *NotificationSettings) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[128]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -473,108 +9125,84 @@ func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
-func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use NotificationSettings.ProtoReflect.Descriptor instead.
+func (*NotificationSettings) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{128}
 }
 
-func (x *CreateSubscriptionRequest) GetTenantId() string {
+func (x *NotificationSettings) GetTenantId() string {
 	if x != nil {
 		return x.TenantId
 	}
 	return ""
 }
 
-func (x *CreateSubscriptionRequest) GetEventType() string {
+func (x *NotificationSettings) GetEmail() string {
 	if x != nil {
-		return x.EventType
+		return x.Email
 	}
 	return ""
 }
 
-func (x *CreateSubscriptionRequest) GetEndpointId() string {
+func (x *NotificationSettings) GetEndpointId() string {
 	if x != nil {
 		return x.EndpointId
 	}
 	return ""
 }
 
-// Create subscription response message
-type CreateSubscriptionResponse struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// The newly created subscription
-	Subscription  *Subscription `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *CreateSubscriptionResponse) Reset() {
-	*x = CreateSubscriptionResponse{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *CreateSubscriptionResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
-}
-
-func (*CreateSubscriptionResponse) ProtoMessage() {}
-
-func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[7]
+func (x *NotificationSettings) GetDlqThreshold() int32 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.DlqThreshold
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use CreateSubscriptionResponse.ProtoReflect.Descriptor instead.
-func (*CreateSubscriptionResponse) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{7}
+func (x *NotificationSettings) GetSecretMaxAgeDays() int32 {
+	if x != nil {
+		return x.SecretMaxAgeDays
+	}
+	return 0
 }
 
-func (x *CreateSubscriptionResponse) GetSubscription() *Subscription {
+func (x *NotificationSettings) GetUpdatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Subscription
+		return x.UpdatedAt
 	}
 	return nil
 }
 
-// Publish event request message
-type PublishEventRequest struct {
+type SetNotificationSettingsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// ID for the tenant
 	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	// Event type to be published
-	EventType string `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
-	// Payload data for the event (arbitrary JSON)
-	Payload *structpb.Struct `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
-	// Required for deduplication, if empty, no dedup
-	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Email address to send notices to
+	Email string `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	// One of the tenant's endpoints to deliver notices to
+	EndpointId string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Notify when the DLQ holds more than this many entries; 0 never notifies
+	DlqThreshold int32 `protobuf:"varint,4,opt,name=dlq_threshold,json=dlqThreshold,proto3" json:"dlq_threshold,omitempty"`
+	// Notify when a signing secret is older than this many days; 0 never notifies
+	SecretMaxAgeDays int32 `protobuf:"varint,5,opt,name=secret_max_age_days,json=secretMaxAgeDays,proto3" json:"secret_max_age_days,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *PublishEventRequest) Reset() {
-	*x = PublishEventRequest{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[8]
+func (x *SetNotificationSettingsRequest) Reset() {
+	*x = SetNotificationSettingsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[129]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PublishEventRequest) String() string {
+func (x *SetNotificationSettingsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PublishEventRequest) ProtoMessage() {}
+func (*SetNotificationSettingsRequest) ProtoMessage() {}
 
-func (x *PublishEventRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[8]
+func (x *SetNotificationSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[129]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -585,65 +9213,69 @@ func (x *PublishEventRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PublishEventRequest.ProtoReflect.Descriptor instead.
-func (*PublishEventRequest) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use SetNotificationSettingsRequest.ProtoReflect.Descriptor instead.
+func (*SetNotificationSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{129}
 }
 
-func (x *PublishEventRequest) GetTenantId() string {
+func (x *SetNotificationSettingsRequest) GetTenantId() string {
 	if x != nil {
 		return x.TenantId
 	}
 	return ""
 }
 
-func (x *PublishEventRequest) GetEventType() string {
+func (x *SetNotificationSettingsRequest) GetEmail() string {
 	if x != nil {
-		return x.EventType
+		return x.Email
 	}
 	return ""
 }
 
-func (x *PublishEventRequest) GetPayload() *structpb.Struct {
+func (x *SetNotificationSettingsRequest) GetEndpointId() string {
 	if x != nil {
-		return x.Payload
+		return x.EndpointId
 	}
-	return nil
+	return ""
 }
 
-func (x *PublishEventRequest) GetIdempotencyKey() string {
+func (x *SetNotificationSettingsRequest) GetDlqThreshold() int32 {
 	if x != nil {
-		return x.IdempotencyKey
+		return x.DlqThreshold
 	}
-	return ""
+	return 0
 }
 
-// Publish event response message
-type PublishEventResponse struct {
+func (x *SetNotificationSettingsRequest) GetSecretMaxAgeDays() int32 {
+	if x != nil {
+		return x.SecretMaxAgeDays
+	}
+	return 0
+}
+
+type SetNotificationSettingsResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Event ID
-	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
-	// How many deliveries for this event are enqueued
-	FanoutCount   int32 `protobuf:"varint,2,opt,name=fanout_count,json=fanoutCount,proto3" json:"fanout_count,omitempty"`
+	// The tenant's settings after the change
+	Settings      *NotificationSettings `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PublishEventResponse) Reset() {
-	*x = PublishEventResponse{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[9]
+func (x *SetNotificationSettingsResponse) Reset() {
+	*x = SetNotificationSettingsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[130]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PublishEventResponse) String() string {
+func (x *SetNotificationSettingsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PublishEventResponse) ProtoMessage() {}
+func (*SetNotificationSettingsResponse) ProtoMessage() {}
 
-func (x *PublishEventResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[9]
+func (x *SetNotificationSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[130]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -654,72 +9286,41 @@ func (x *PublishEventResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PublishEventResponse.ProtoReflect.Descriptor instead.
-func (*PublishEventResponse) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{9}
-}
-
-func (x *PublishEventResponse) GetEventId() string {
-	if x != nil {
-		return x.EventId
-	}
-	return ""
+// Deprecated: Use SetNotificationSettingsResponse.ProtoReflect.Descriptor instead.
+func (*SetNotificationSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{130}
 }
 
-func (x *PublishEventResponse) GetFanoutCount() int32 {
+func (x *SetNotificationSettingsResponse) GetSettings() *NotificationSettings {
 	if x != nil {
-		return x.FanoutCount
+		return x.Settings
 	}
-	return 0
+	return nil
 }
 
-type DeliveryAttempt struct {
+type GetNotificationSettingsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// Unique ID for the delivery attempt
-	DeliveryId string `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
-	// ID of the event being delivered
-	EventId string `protobuf:"bytes,2,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
-	// ID of the endpoint being delivered to
-	EndpointId string `protobuf:"bytes,3,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
-	// link to source attempt (if replayed)
-	ReplayOf string `protobuf:"bytes,4,opt,name=replay_of,json=replayOf,proto3" json:"replay_of,omitempty"`
-	// queued|inflight|delivered|failed|dead
-	Status DeliveryAttemptStatus `protobuf:"varint,5,opt,name=status,proto3,enum=api.webhook.v1.DeliveryAttemptStatus" json:"status,omitempty"`
-	// HTTP status code
-	HttpStatus int32 `protobuf:"varint,6,opt,name=http_status,json=httpStatus,proto3" json:"http_status,omitempty"`
-	// Optional error reason
-	ErrorReason string `protobuf:"bytes,7,opt,name=error_reason,json=errorReason,proto3" json:"error_reason,omitempty"`
-	// Timestamp of when the delivery was enqueued
-	EnqueuedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=enqueued_at,json=enqueuedAt,proto3" json:"enqueued_at,omitempty"`
-	// Timestamp of when the delivery was dequeued
-	DequeuedAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=dequeued_at,json=dequeuedAt,proto3" json:"dequeued_at,omitempty"`
-	// Timestamp of when the delivery was sent
-	SentAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
-	// Timestamp of when the delivery was delivered
-	DeliveredAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
-	// Timestamp of when the delivery failed
-	FailedAt *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=failed_at,json=failedAt,proto3" json:"failed_at,omitempty"`
-	// Timestamp of when the delivery was dead-lettered
-	DlqAt         *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=dlq_at,json=dlqAt,proto3" json:"dlq_at,omitempty"`
+	// ID for the tenant
+	TenantId      string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeliveryAttempt) Reset() {
-	*x = DeliveryAttempt{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[10]
+func (x *GetNotificationSettingsRequest) Reset() {
+	*x = GetNotificationSettingsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[131]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeliveryAttempt) String() string {
+func (x *GetNotificationSettingsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeliveryAttempt) ProtoMessage() {}
+func (*GetNotificationSettingsRequest) ProtoMessage() {}
 
-func (x *DeliveryAttempt) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[10]
+func (x *GetNotificationSettingsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[131]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -730,133 +9331,131 @@ func (x *DeliveryAttempt) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeliveryAttempt.ProtoReflect.Descriptor instead.
-func (*DeliveryAttempt) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use GetNotificationSettingsRequest.ProtoReflect.Descriptor instead.
+func (*GetNotificationSettingsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{131}
 }
 
-func (x *DeliveryAttempt) GetDeliveryId() string {
+func (x *GetNotificationSettingsRequest) GetTenantId() string {
 	if x != nil {
-		return x.DeliveryId
+		return x.TenantId
 	}
 	return ""
 }
 
-func (x *DeliveryAttempt) GetEventId() string {
-	if x != nil {
-		return x.EventId
-	}
-	return ""
+type GetNotificationSettingsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The tenant's settings
+	Settings      *NotificationSettings `protobuf:"bytes,1,opt,name=settings,proto3" json:"settings,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeliveryAttempt) GetEndpointId() string {
-	if x != nil {
-		return x.EndpointId
-	}
-	return ""
+func (x *GetNotificationSettingsResponse) Reset() {
+	*x = GetNotificationSettingsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[132]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeliveryAttempt) GetReplayOf() string {
-	if x != nil {
-		return x.ReplayOf
-	}
-	return ""
+func (x *GetNotificationSettingsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *DeliveryAttempt) GetStatus() DeliveryAttemptStatus {
-	if x != nil {
-		return x.Status
-	}
-	return DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED
-}
+func (*GetNotificationSettingsResponse) ProtoMessage() {}
 
-func (x *DeliveryAttempt) GetHttpStatus() int32 {
+func (x *GetNotificationSettingsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[132]
 	if x != nil {
-		return x.HttpStatus
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return 0
+	return mi.MessageOf(x)
 }
 
-func (x *DeliveryAttempt) GetErrorReason() string {
-	if x != nil {
-		return x.ErrorReason
-	}
-	return ""
+// Deprecated: Use GetNotificationSettingsResponse.ProtoReflect.Descriptor instead.
+func (*GetNotificationSettingsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{132}
 }
 
-func (x *DeliveryAttempt) GetEnqueuedAt() *timestamppb.Timestamp {
+func (x *GetNotificationSettingsResponse) GetSettings() *NotificationSettings {
 	if x != nil {
-		return x.EnqueuedAt
+		return x.Settings
 	}
 	return nil
 }
 
-func (x *DeliveryAttempt) GetDequeuedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.DequeuedAt
-	}
-	return nil
+type ListTenantsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeliveryAttempt) GetSentAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.SentAt
-	}
-	return nil
+func (x *ListTenantsRequest) Reset() {
+	*x = ListTenantsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[133]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeliveryAttempt) GetDeliveredAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.DeliveredAt
-	}
-	return nil
+func (x *ListTenantsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func (x *DeliveryAttempt) GetFailedAt() *timestamppb.Timestamp {
-	if x != nil {
-		return x.FailedAt
-	}
-	return nil
-}
+func (*ListTenantsRequest) ProtoMessage() {}
 
-func (x *DeliveryAttempt) GetDlqAt() *timestamppb.Timestamp {
+func (x *ListTenantsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[133]
 	if x != nil {
-		return x.DlqAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type GetDeliveryStatusRequest struct {
+// Deprecated: Use ListTenantsRequest.ProtoReflect.Descriptor instead.
+func (*ListTenantsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{133}
+}
+
+// A tenant with counts for the admin console
+type TenantSummary struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// ID of the event to check deliveries for
-	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
-	// ID of the endpoint to check deliveries for
-	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
-	// Start datetime to search
-	From *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=from,proto3" json:"from,omitempty"`
-	// End datetime to search
-	To *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=to,proto3" json:"to,omitempty"`
-	// Limit the number of results (default 10)
-	Limit         int32 `protobuf:"varint,5,opt,name=limit,proto3" json:"limit,omitempty"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Display name (empty when the tenant is only known from its endpoints)
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Number of endpoints the tenant owns
+	EndpointCount int32 `protobuf:"varint,3,opt,name=endpoint_count,json=endpointCount,proto3" json:"endpoint_count,omitempty"`
+	// Deliveries created in the last 24 hours
+	RecentDeliveries int32 `protobuf:"varint,4,opt,name=recent_deliveries,json=recentDeliveries,proto3" json:"recent_deliveries,omitempty"`
+	// Deliveries currently in the DLQ
+	DeadLettered  int32 `protobuf:"varint,5,opt,name=dead_lettered,json=deadLettered,proto3" json:"dead_lettered,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDeliveryStatusRequest) Reset() {
-	*x = GetDeliveryStatusRequest{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[11]
+func (x *TenantSummary) Reset() {
+	*x = TenantSummary{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[134]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDeliveryStatusRequest) String() string {
+func (x *TenantSummary) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDeliveryStatusRequest) ProtoMessage() {}
+func (*TenantSummary) ProtoMessage() {}
 
-func (x *GetDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[11]
+func (x *TenantSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[134]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -867,69 +9466,69 @@ func (x *GetDeliveryStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDeliveryStatusRequest.ProtoReflect.Descriptor instead.
-func (*GetDeliveryStatusRequest) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use TenantSummary.ProtoReflect.Descriptor instead.
+func (*TenantSummary) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{134}
 }
 
-func (x *GetDeliveryStatusRequest) GetEventId() string {
+func (x *TenantSummary) GetTenantId() string {
 	if x != nil {
-		return x.EventId
+		return x.TenantId
 	}
 	return ""
 }
 
-func (x *GetDeliveryStatusRequest) GetEndpointId() string {
+func (x *TenantSummary) GetName() string {
 	if x != nil {
-		return x.EndpointId
+		return x.Name
 	}
 	return ""
 }
 
-func (x *GetDeliveryStatusRequest) GetFrom() *timestamppb.Timestamp {
+func (x *TenantSummary) GetEndpointCount() int32 {
 	if x != nil {
-		return x.From
+		return x.EndpointCount
 	}
-	return nil
+	return 0
 }
 
-func (x *GetDeliveryStatusRequest) GetTo() *timestamppb.Timestamp {
+func (x *TenantSummary) GetRecentDeliveries() int32 {
 	if x != nil {
-		return x.To
+		return x.RecentDeliveries
 	}
-	return nil
+	return 0
 }
 
-func (x *GetDeliveryStatusRequest) GetLimit() int32 {
+func (x *TenantSummary) GetDeadLettered() int32 {
 	if x != nil {
-		return x.Limit
+		return x.DeadLettered
 	}
 	return 0
 }
 
-type GetDeliveryStatusResponse struct {
+type ListTenantsResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// List of delivery attempts
-	Attempts      []*DeliveryAttempt `protobuf:"bytes,1,rep,name=attempts,proto3" json:"attempts,omitempty"`
+	// Tenants ordered by ID
+	Tenants       []*TenantSummary `protobuf:"bytes,1,rep,name=tenants,proto3" json:"tenants,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetDeliveryStatusResponse) Reset() {
-	*x = GetDeliveryStatusResponse{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[12]
+func (x *ListTenantsResponse) Reset() {
+	*x = ListTenantsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[135]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetDeliveryStatusResponse) String() string {
+func (x *ListTenantsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetDeliveryStatusResponse) ProtoMessage() {}
+func (*ListTenantsResponse) ProtoMessage() {}
 
-func (x *GetDeliveryStatusResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[12]
+func (x *ListTenantsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[135]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -940,43 +9539,42 @@ func (x *GetDeliveryStatusResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetDeliveryStatusResponse.ProtoReflect.Descriptor instead.
-func (*GetDeliveryStatusResponse) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use ListTenantsResponse.ProtoReflect.Descriptor instead.
+func (*ListTenantsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{135}
 }
 
-func (x *GetDeliveryStatusResponse) GetAttempts() []*DeliveryAttempt {
+func (x *ListTenantsResponse) GetTenants() []*TenantSummary {
 	if x != nil {
-		return x.Attempts
+		return x.Tenants
 	}
 	return nil
 }
 
-type ReplayDeliveryRequest struct {
+type ListEndpointsRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The ID of the delivery to replay
-	DeliveryId string `protobuf:"bytes,1,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
-	// Optional reason for replaying the delivery
-	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	// ID of the tenant whose endpoints to list. Named tenant rather than tenant_id so the
+	// admin tenant may list other tenants' endpoints
+	Tenant        string `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReplayDeliveryRequest) Reset() {
-	*x = ReplayDeliveryRequest{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[13]
+func (x *ListEndpointsRequest) Reset() {
+	*x = ListEndpointsRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[136]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReplayDeliveryRequest) String() string {
+func (x *ListEndpointsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplayDeliveryRequest) ProtoMessage() {}
+func (*ListEndpointsRequest) ProtoMessage() {}
 
-func (x *ReplayDeliveryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[13]
+func (x *ListEndpointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[136]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -987,48 +9585,41 @@ func (x *ReplayDeliveryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplayDeliveryRequest.ProtoReflect.Descriptor instead.
-func (*ReplayDeliveryRequest) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{13}
-}
-
-func (x *ReplayDeliveryRequest) GetDeliveryId() string {
-	if x != nil {
-		return x.DeliveryId
-	}
-	return ""
+// Deprecated: Use ListEndpointsRequest.ProtoReflect.Descriptor instead.
+func (*ListEndpointsRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{136}
 }
 
-func (x *ReplayDeliveryRequest) GetReason() string {
+func (x *ListEndpointsRequest) GetTenant() string {
 	if x != nil {
-		return x.Reason
+		return x.Tenant
 	}
 	return ""
 }
 
-type ReplayDeliveryResponse struct {
+type ListEndpointsResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// The newly enqueued attempt
-	NewAttempt    *DeliveryAttempt `protobuf:"bytes,1,opt,name=new_attempt,json=newAttempt,proto3" json:"new_attempt,omitempty"`
+	// Endpoints, newest first
+	Endpoints     []*Endpoint `protobuf:"bytes,1,rep,name=endpoints,proto3" json:"endpoints,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ReplayDeliveryResponse) Reset() {
-	*x = ReplayDeliveryResponse{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[14]
+func (x *ListEndpointsResponse) Reset() {
+	*x = ListEndpointsResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[137]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ReplayDeliveryResponse) String() string {
+func (x *ListEndpointsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ReplayDeliveryResponse) ProtoMessage() {}
+func (*ListEndpointsResponse) ProtoMessage() {}
 
-func (x *ReplayDeliveryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[14]
+func (x *ListEndpointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[137]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1039,43 +9630,47 @@ func (x *ReplayDeliveryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ReplayDeliveryResponse.ProtoReflect.Descriptor instead.
-func (*ReplayDeliveryResponse) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ListEndpointsResponse.ProtoReflect.Descriptor instead.
+func (*ListEndpointsResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{137}
 }
 
-func (x *ReplayDeliveryResponse) GetNewAttempt() *DeliveryAttempt {
+func (x *ListEndpointsResponse) GetEndpoints() []*Endpoint {
 	if x != nil {
-		return x.NewAttempt
+		return x.Endpoints
 	}
 	return nil
 }
 
-type ListDLQRequest struct {
+type ListRecentDeliveriesRequest struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// ID of the endpoint to filter by
-	EndpointId string `protobuf:"bytes,1,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
-	// Limit the number of results (default 10)
-	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	// Only deliveries for this tenant
+	Tenant string `protobuf:"bytes,1,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// Only deliveries to this endpoint
+	EndpointId string `protobuf:"bytes,2,opt,name=endpoint_id,json=endpointId,proto3" json:"endpoint_id,omitempty"`
+	// Only deliveries in this status
+	Status DeliveryAttemptStatus `protobuf:"varint,3,opt,name=status,proto3,enum=api.webhook.v1.DeliveryAttemptStatus" json:"status,omitempty"`
+	// Limit the number of results (default 50, max 500)
+	Limit         int32 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDLQRequest) Reset() {
-	*x = ListDLQRequest{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[15]
+func (x *ListRecentDeliveriesRequest) Reset() {
+	*x = ListRecentDeliveriesRequest{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[138]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDLQRequest) String() string {
+func (x *ListRecentDeliveriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDLQRequest) ProtoMessage() {}
+func (*ListRecentDeliveriesRequest) ProtoMessage() {}
 
-func (x *ListDLQRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[15]
+func (x *ListRecentDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[138]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1086,48 +9681,67 @@ func (x *ListDLQRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDLQRequest.ProtoReflect.Descriptor instead.
-func (*ListDLQRequest) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ListRecentDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListRecentDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{138}
 }
 
-func (x *ListDLQRequest) GetEndpointId() string {
+func (x *ListRecentDeliveriesRequest) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ListRecentDeliveriesRequest) GetEndpointId() string {
 	if x != nil {
 		return x.EndpointId
 	}
 	return ""
 }
 
-func (x *ListDLQRequest) GetLimit() int32 {
+func (x *ListRecentDeliveriesRequest) GetStatus() DeliveryAttemptStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DeliveryAttemptStatus_DELIVERY_ATTEMPT_STATUS_UNSPECIFIED
+}
+
+func (x *ListRecentDeliveriesRequest) GetLimit() int32 {
 	if x != nil {
 		return x.Limit
 	}
 	return 0
 }
 
-type ListDLQResponse struct {
+// A delivery with the tenant and event type it belongs to
+type RecentDelivery struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	// List of delivery attempts in the DLQ
-	Dead          []*DeliveryAttempt `protobuf:"bytes,1,rep,name=dead,proto3" json:"dead,omitempty"`
+	// The delivery attempt
+	Delivery *DeliveryAttempt `protobuf:"bytes,1,opt,name=delivery,proto3" json:"delivery,omitempty"`
+	// ID for the tenant
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// Type of the delivered event
+	EventType     string `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListDLQResponse) Reset() {
-	*x = ListDLQResponse{}
-	mi := &file_api_webhook_v1_service_proto_msgTypes[16]
+func (x *RecentDelivery) Reset() {
+	*x = RecentDelivery{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[139]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListDLQResponse) String() string {
+func (x *RecentDelivery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListDLQResponse) ProtoMessage() {}
+func (*RecentDelivery) ProtoMessage() {}
 
-func (x *ListDLQResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_api_webhook_v1_service_proto_msgTypes[16]
+func (x *RecentDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[139]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1138,14 +9752,73 @@ func (x *ListDLQResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListDLQResponse.ProtoReflect.Descriptor instead.
-func (*ListDLQResponse) Descriptor() ([]byte, []int) {
-	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use RecentDelivery.ProtoReflect.Descriptor instead.
+func (*RecentDelivery) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{139}
 }
 
-func (x *ListDLQResponse) GetDead() []*DeliveryAttempt {
+func (x *RecentDelivery) GetDelivery() *DeliveryAttempt {
 	if x != nil {
-		return x.Dead
+		return x.Delivery
+	}
+	return nil
+}
+
+func (x *RecentDelivery) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *RecentDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+type ListRecentDeliveriesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deliveries, newest first
+	Deliveries    []*RecentDelivery `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRecentDeliveriesResponse) Reset() {
+	*x = ListRecentDeliveriesResponse{}
+	mi := &file_api_webhook_v1_service_proto_msgTypes[140]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRecentDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRecentDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListRecentDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_api_webhook_v1_service_proto_msgTypes[140]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRecentDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListRecentDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_api_webhook_v1_service_proto_rawDescGZIP(), []int{140}
+}
+
+func (x *ListRecentDeliveriesResponse) GetDeliveries() []*RecentDelivery {
+	if x != nil {
+		return x.Deliveries
 	}
 	return nil
 }
@@ -1154,16 +9827,63 @@ var File_api_webhook_v1_service_proto protoreflect.FileDescriptor
 
 const file_api_webhook_v1_service_proto_rawDesc = "" +
 	"\n" +
-	"\x1capi/webhook/v1/service.proto\x12\x0eapi.webhook.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a#openapi/openapiv3/annotations.proto\"\r\n" +
+	"\x1capi/webhook/v1/service.proto\x12\x0eapi.webhook.v1\x1a\x1bbuf/validate/validate.proto\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\x1a#openapi/openapiv3/annotations.proto\"\r\n" +
 	"\vPingRequest\"(\n" +
 	"\fPingResponse\x12\x18\n" +
-	"\amessage\x18\x01 \x01(\tR\amessage\"\xa8\x01\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\"\xee\a\n" +
 	"\bEndpoint\x12\x18\n" +
 	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x1b\n" +
 	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x1a\n" +
 	"\x03url\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x88\x01\x01R\x03url\x12I\n" +
 	"\n" +
-	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x0e\xbaH\v\xb2\x01\b2\x06\b\x80\x8bһ\x06R\tcreatedAt\"\xda\x01\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\x0e\xbaH\v\xb2\x01\b2\x06\b\x80\x8bһ\x06R\tcreatedAt\x12A\n" +
+	"\rrecovery_ramp\x18\x05 \x01(\v2\x1c.api.webhook.v1.RecoveryRampR\frecoveryRamp\x12>\n" +
+	"\fretry_policy\x18\x06 \x01(\v2\x1b.api.webhook.v1.RetryPolicyR\vretryPolicy\x12C\n" +
+	"\vverified_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampB\x06\xbaH\x03\xd8\x01\x01R\n" +
+	"verifiedAt\x12P\n" +
+	"\x12client_certificate\x18\b \x01(\v2!.api.webhook.v1.ClientCertificateR\x11clientCertificate\x12D\n" +
+	"\vcompression\x18\t \x01(\x0e2\".api.webhook.v1.PayloadCompressionR\vcompression\x12<\n" +
+	"\bordering\x18\n" +
+	" \x01(\v2 .api.webhook.v1.DeliveryOrderingR\bordering\x12J\n" +
+	"\x10signature_scheme\x18\v \x01(\x0e2\x1f.api.webhook.v1.SignatureSchemeR\x0fsignatureScheme\x12\x1d\n" +
+	"\n" +
+	"timeout_ms\x18\f \x01(\x05R\ttimeoutMs\x120\n" +
+	"\x04type\x18\r \x01(\x0e2\x1c.api.webhook.v1.EndpointTypeR\x04type\x12\x1b\n" +
+	"\tproxy_url\x18\x0e \x01(\tR\bproxyUrl\x12F\n" +
+	"\fhealth_check\x18\x0f \x01(\v2#.api.webhook.v1.EndpointHealthCheckR\vhealthCheck\x126\n" +
+	"\x06health\x18\x10 \x01(\v2\x1e.api.webhook.v1.EndpointHealthR\x06health\x12C\n" +
+	"\vdisabled_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampB\x06\xbaH\x03\xd8\x01\x01R\n" +
+	"disabledAt\x12'\n" +
+	"\x0fdisabled_reason\x18\x12 \x01(\tR\x0edisabledReason\"P\n" +
+	"\x13EndpointHealthCheck\x129\n" +
+	"\x06method\x18\x01 \x01(\x0e2!.api.webhook.v1.HealthCheckMethodR\x06method\"\xf1\x02\n" +
+	"\x0eEndpointHealth\x12<\n" +
+	"\x06status\x18\x01 \x01(\x0e2$.api.webhook.v1.EndpointHealthStatusR\x06status\x12B\n" +
+	"\x0flast_checked_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\rlastCheckedAt\x12(\n" +
+	"\x10last_status_code\x18\x03 \x01(\x05R\x0elastStatusCode\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x04 \x01(\tR\tlastError\x12&\n" +
+	"\x0flast_latency_ms\x18\x05 \x01(\x05R\rlastLatencyMs\x121\n" +
+	"\x14consecutive_failures\x18\x06 \x01(\x05R\x13consecutiveFailures\x129\n" +
+	"\n" +
+	"changed_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"A\n" +
+	"\x10DeliveryOrdering\x12-\n" +
+	"\rpartition_key\x18\x01 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x02R\fpartitionKey\"k\n" +
+	"\fRecoveryRamp\x12,\n" +
+	"\bpercents\x18\x01 \x03(\x05B\x10\xbaH\r\x92\x01\n" +
+	"\x10\n" +
+	"\"\x06\x1a\x04\x18d(\x01R\bpercents\x12-\n" +
+	"\fstep_seconds\x18\x02 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\x90\x1c(\x00R\vstepSeconds\"\x9d\x01\n" +
+	"\vRetryPolicy\x12,\n" +
+	"\fmax_attempts\x18\x01 \x01(\x05B\t\xbaH\x06\x1a\x04\x182(\x00R\vmaxAttempts\x12;\n" +
+	"\x0fbackoff_seconds\x18\x02 \x03(\x05B\x12\xbaH\x0f\x92\x01\f\x10\x14\"\b\x1a\x06\x18\x80\xa3\x05(\x01R\x0ebackoffSeconds\x12#\n" +
+	"\bretry_on\x18\x03 \x03(\tB\b\xbaH\x05\x92\x01\x02\x18\x01R\aretryOn\"\x87\x01\n" +
+	"\x11ClientCertificate\x12\x1f\n" +
+	"\vsecret_name\x18\x01 \x01(\tR\n" +
+	"secretName\x12\x18\n" +
+	"\asubject\x18\x02 \x01(\tR\asubject\x127\n" +
+	"\tnot_after\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bnotAfter\"\xa8\x02\n" +
 	"\fSubscription\x12\x18\n" +
 	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x1b\n" +
 	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x1d\n" +
@@ -1172,30 +9892,209 @@ const file_api_webhook_v1_service_proto_rawDesc = "" +
 	"\vendpoint_id\x18\x04 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\n" +
 	"endpointId\x12I\n" +
 	"\n" +
-	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\x0e\xbaH\v\xb2\x01\b2\x06\b\x80\x8bһ\x06R\tcreatedAt\"{\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\x0e\xbaH\v\xb2\x01\b2\x06\b\x80\x8bһ\x06R\tcreatedAt\x12%\n" +
+	"\x0einclude_fields\x18\x06 \x03(\tR\rincludeFields\x12%\n" +
+	"\x0eexclude_fields\x18\a \x03(\tR\rexcludeFields\"\x92\x04\n" +
 	"\x15CreateEndpointRequest\x12#\n" +
 	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12\x1d\n" +
-	"\x03url\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\x88\x01\x01R\x03url\x12\x1e\n" +
-	"\x06secret\x18\x03 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x06secret\"N\n" +
+	"\x03url\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\x88\x01\x01R\x03url\x12\x1e\n" +
+	"\x06secret\x18\x03 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x06secret\x12I\n" +
+	"\rrecovery_ramp\x18\x04 \x01(\v2\x1c.api.webhook.v1.RecoveryRampB\x06\xbaH\x03\xd8\x01\x01R\frecoveryRamp\x12F\n" +
+	"\fretry_policy\x18\x05 \x01(\v2\x1b.api.webhook.v1.RetryPolicyB\x06\xbaH\x03\xd8\x01\x01R\vretryPolicy\x12D\n" +
+	"\vcompression\x18\x06 \x01(\x0e2\".api.webhook.v1.PayloadCompressionR\vcompression\x12T\n" +
+	"\x10signature_scheme\x18\a \x01(\x0e2\x1f.api.webhook.v1.SignatureSchemeB\b\xbaH\x05\x82\x01\x02\x10\x01R\x0fsignatureScheme\x12*\n" +
+	"\n" +
+	"timeout_ms\x18\b \x01(\x05B\v\xbaH\b\x1a\x06\x18\xc0\xa9\a(\x00R\ttimeoutMs\x12:\n" +
+	"\x04type\x18\t \x01(\x0e2\x1c.api.webhook.v1.EndpointTypeB\b\xbaH\x05\x82\x01\x02\x10\x01R\x04type\"\xbe\x01\n" +
+	"\x1eSetEndpointRecoveryRampRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12I\n" +
+	"\rrecovery_ramp\x18\x03 \x01(\v2\x1c.api.webhook.v1.RecoveryRampB\x06\xbaH\x03\xc8\x01\x01R\frecoveryRamp\"W\n" +
+	"\x1fSetEndpointRecoveryRampResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\xb2\x01\n" +
+	"\x1dSetEndpointRetryPolicyRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12>\n" +
+	"\fretry_policy\x18\x03 \x01(\v2\x1b.api.webhook.v1.RetryPolicyR\vretryPolicy\"V\n" +
+	"\x1eSetEndpointRetryPolicyResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\xcd\x01\n" +
+	"#SetEndpointClientCertificateRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12\x19\n" +
+	"\bcert_pem\x18\x03 \x01(\tR\acertPem\x12\x17\n" +
+	"\akey_pem\x18\x04 \x01(\tR\x06keyPem\x12\x1f\n" +
+	"\vsecret_name\x18\x05 \x01(\tR\n" +
+	"secretName\"\\\n" +
+	"$SetEndpointClientCertificateResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\xc2\x01\n" +
+	"\x1dSetEndpointCompressionRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12N\n" +
+	"\vcompression\x18\x03 \x01(\x0e2\".api.webhook.v1.PayloadCompressionB\b\xbaH\x05\x82\x01\x02\x10\x01R\vcompression\"V\n" +
+	"\x1eSetEndpointCompressionResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\x9a\x01\n" +
+	"\x19SetEndpointTimeoutRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12*\n" +
+	"\n" +
+	"timeout_ms\x18\x03 \x01(\x05B\v\xbaH\b\x1a\x06\x18\xc0\xa9\a(\x00R\ttimeoutMs\"R\n" +
+	"\x1aSetEndpointTimeoutResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\x93\x01\n" +
+	"\x17SetEndpointProxyRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12%\n" +
+	"\tproxy_url\x18\x03 \x01(\tB\b\xbaH\x05r\x03\x18\x80\x10R\bproxyUrl\"P\n" +
+	"\x18SetEndpointProxyResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\xcc\x01\n" +
+	"!SetEndpointSignatureSchemeRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12T\n" +
+	"\x10signature_scheme\x18\x03 \x01(\x0e2\x1f.api.webhook.v1.SignatureSchemeB\b\xbaH\x05\x82\x01\x02\x10\x01R\x0fsignatureScheme\"Z\n" +
+	"\"SetEndpointSignatureSchemeResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"<\n" +
+	"\x15GetSigningKeysRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\"t\n" +
+	"\n" +
+	"SigningKey\x12\x10\n" +
+	"\x03kty\x18\x01 \x01(\tR\x03kty\x12\x10\n" +
+	"\x03crv\x18\x02 \x01(\tR\x03crv\x12\x10\n" +
+	"\x03kid\x18\x03 \x01(\tR\x03kid\x12\f\n" +
+	"\x01x\x18\x04 \x01(\tR\x01x\x12\x10\n" +
+	"\x03use\x18\x05 \x01(\tR\x03use\x12\x10\n" +
+	"\x03alg\x18\x06 \x01(\tR\x03alg\"H\n" +
+	"\x16GetSigningKeysResponse\x12.\n" +
+	"\x04keys\x18\x01 \x03(\v2\x1a.api.webhook.v1.SigningKeyR\x04keys\"\x15\n" +
+	"\x13GetEgressIPsRequest\"F\n" +
+	"\x14GetEgressIPsResponse\x12\x14\n" +
+	"\x05cidrs\x18\x01 \x03(\tR\x05cidrs\x12\x18\n" +
+	"\aproxied\x18\x02 \x01(\bR\aproxied\"\xba\x01\n" +
+	"\x1dSetEndpointHealthCheckRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12F\n" +
+	"\fhealth_check\x18\x03 \x01(\v2#.api.webhook.v1.EndpointHealthCheckR\vhealthCheck\"V\n" +
+	"\x1eSetEndpointHealthCheckResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"j\n" +
+	"\x15EnableEndpointRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\"N\n" +
+	"\x16EnableEndpointResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\xad\x01\n" +
+	"\x1aSetEndpointOrderingRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12<\n" +
+	"\bordering\x18\x03 \x01(\v2 .api.webhook.v1.DeliveryOrderingR\bordering\"S\n" +
+	"\x1bSetEndpointOrderingResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"j\n" +
+	"\x15DeleteEndpointRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\"\x9d\x01\n" +
+	"\x16DeleteEndpointResponse\x12\x1f\n" +
+	"\vendpoint_id\x18\x01 \x01(\tR\n" +
+	"endpointId\x123\n" +
+	"\x15deleted_subscriptions\x18\x02 \x01(\x05R\x14deletedSubscriptions\x12-\n" +
+	"\x12deleted_deliveries\x18\x03 \x01(\x05R\x11deletedDeliveries\"}\n" +
 	"\x16CreateEndpointResponse\x124\n" +
-	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\x95\x01\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\x12-\n" +
+	"\x12verification_error\x18\x02 \x01(\tR\x11verificationError\"\x80\x01\n" +
+	"\x15VerifyEndpointRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12\x14\n" +
+	"\x05token\x18\x03 \x01(\tR\x05token\"N\n" +
+	"\x16VerifyEndpointResponse\x124\n" +
+	"\bendpoint\x18\x01 \x01(\v2\x18.api.webhook.v1.EndpointR\bendpoint\"\x97\x02\n" +
 	"\x19CreateSubscriptionRequest\x12#\n" +
-	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12%\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12I\n" +
 	"\n" +
-	"event_type\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\teventType\x12,\n" +
+	"event_type\x18\x02 \x01(\tB*\xbaH'\xc8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\x12,\n" +
 	"\vendpoint_id\x18\x03 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
-	"endpointId\"^\n" +
+	"endpointId\x12-\n" +
+	"\x0einclude_fields\x18\x04 \x03(\tB\x06\xbaH\x03\xd8\x01\x01R\rincludeFields\x12-\n" +
+	"\x0eexclude_fields\x18\x05 \x03(\tB\x06\xbaH\x03\xd8\x01\x01R\rexcludeFields\"^\n" +
 	"\x1aCreateSubscriptionResponse\x12@\n" +
-	"\fsubscription\x18\x01 \x01(\v2\x1c.api.webhook.v1.SubscriptionR\fsubscription\"\xcd\x01\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1c.api.webhook.v1.SubscriptionR\fsubscription\"\xcf\x03\n" +
 	"\x13PublishEventRequest\x12#\n" +
-	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12%\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12I\n" +
 	"\n" +
-	"event_type\x18\x02 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\teventType\x129\n" +
+	"event_type\x18\x02 \x01(\tB*\xbaH'\xc8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\x129\n" +
 	"\apayload\x18\x03 \x01(\v2\x17.google.protobuf.StructB\x06\xbaH\x03\xc8\x01\x01R\apayload\x12/\n" +
-	"\x0fidempotency_key\x18\x04 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x0eidempotencyKey\"i\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x0eidempotencyKey\x129\n" +
+	"\n" +
+	"deliver_by\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tdeliverBy\x12+\n" +
+	"\x03ttl\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x129\n" +
+	"\n" +
+	"publish_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tpublishAt\x129\n" +
+	"\bpriority\x18\b \x01(\x0e2\x1d.api.webhook.v1.EventPriorityR\bpriority\"\xa5\x01\n" +
 	"\x14PublishEventResponse\x12&\n" +
 	"\bevent_id\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\aeventId\x12)\n" +
-	"\ffanout_count\x18\x02 \x01(\x05B\x06\xbaH\x03\xc8\x01\x01R\vfanoutCount\"\xd2\x05\n" +
+	"\ffanout_count\x18\x02 \x01(\x05B\x06\xbaH\x03\xc8\x01\x01R\vfanoutCount\x12\x1c\n" +
+	"\tscheduled\x18\x03 \x01(\bR\tscheduled\x12\x1c\n" +
+	"\tduplicate\x18\x04 \x01(\bR\tduplicate\"\xe6\x02\n" +
+	"\n" +
+	"BatchEvent\x12I\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tB*\xbaH'\xc8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\x129\n" +
+	"\apayload\x18\x02 \x01(\v2\x17.google.protobuf.StructB\x06\xbaH\x03\xc8\x01\x01R\apayload\x12/\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x0eidempotencyKey\x129\n" +
+	"\n" +
+	"deliver_by\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tdeliverBy\x12+\n" +
+	"\x03ttl\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\x03ttl\x129\n" +
+	"\bpriority\x18\x06 \x01(\x0e2\x1d.api.webhook.v1.EventPriorityR\bpriority\"\x81\x01\n" +
+	"\x14PublishEventsRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12D\n" +
+	"\x06events\x18\x02 \x03(\v2\x1a.api.webhook.v1.BatchEventB\x10\xbaH\r\x92\x01\n" +
+	"\b\x01\x10\xf4\x03\"\x03\xd8\x01\x03R\x06events\"\xbb\x01\n" +
+	"\x12PublishEventResult\x12\x14\n" +
+	"\x05index\x18\x01 \x01(\x05R\x05index\x12\x19\n" +
+	"\bevent_id\x18\x02 \x01(\tR\aeventId\x12!\n" +
+	"\ffanout_count\x18\x03 \x01(\x05R\vfanoutCount\x12\x1c\n" +
+	"\tduplicate\x18\x04 \x01(\bR\tduplicate\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x05 \x01(\x05R\terrorCode\x12\x14\n" +
+	"\x05error\x18\x06 \x01(\tR\x05error\"\xa1\x01\n" +
+	"\x15PublishEventsResponse\x12<\n" +
+	"\aresults\x18\x01 \x03(\v2\".api.webhook.v1.PublishEventResultR\aresults\x12'\n" +
+	"\x0fpublished_count\x18\x02 \x01(\x05R\x0epublishedCount\x12!\n" +
+	"\ffailed_count\x18\x03 \x01(\x05R\vfailedCount\"\xcf\x01\n" +
+	"\vEventSchema\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x05R\aversion\x12/\n" +
+	"\x06schema\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06schema\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xc3\x01\n" +
+	"\x18CreateEventSchemaRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12I\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tB*\xbaH'\xc8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\x127\n" +
+	"\x06schema\x18\x03 \x01(\v2\x17.google.protobuf.StructB\x06\xbaH\x03\xc8\x01\x01R\x06schema\"P\n" +
+	"\x19CreateEventSchemaResponse\x123\n" +
+	"\x06schema\x18\x01 \x01(\v2\x1b.api.webhook.v1.EventSchemaR\x06schema\"\x89\x01\n" +
+	"\x17ListEventSchemasRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12I\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tB*\xbaH'\xd8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\"Q\n" +
+	"\x18ListEventSchemasResponse\x125\n" +
+	"\aschemas\x18\x01 \x03(\v2\x1b.api.webhook.v1.EventSchemaR\aschemas\"\xaa\x01\n" +
+	"\x15GetEventSchemaRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12I\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tB*\xbaH'\xc8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\x12!\n" +
+	"\aversion\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\aversion\"M\n" +
+	"\x16GetEventSchemaResponse\x123\n" +
+	"\x06schema\x18\x01 \x01(\v2\x1b.api.webhook.v1.EventSchemaR\x06schema\"\x9a\a\n" +
 	"\x0fDeliveryAttempt\x12)\n" +
 	"\vdelivery_id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\n" +
 	"deliveryId\x12#\n" +
@@ -1206,7 +10105,9 @@ const file_api_webhook_v1_service_proto_rawDesc = "" +
 	"\x06status\x18\x05 \x01(\x0e2%.api.webhook.v1.DeliveryAttemptStatusR\x06status\x12\x1f\n" +
 	"\vhttp_status\x18\x06 \x01(\x05R\n" +
 	"httpStatus\x12!\n" +
-	"\ferror_reason\x18\a \x01(\tR\verrorReason\x12F\n" +
+	"\ferror_reason\x18\a \x01(\tR\verrorReason\x12(\n" +
+	"\x10root_delivery_id\x18\b \x01(\tR\x0erootDeliveryId\x12!\n" +
+	"\freplay_depth\x18\t \x01(\x05R\vreplayDepth\x12F\n" +
 	"\venqueued_at\x18\n" +
 	" \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\n" +
 	"enqueuedAt\x12F\n" +
@@ -1215,53 +10116,630 @@ const file_api_webhook_v1_service_proto_rawDesc = "" +
 	"\asent_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x06sentAt\x12H\n" +
 	"\fdelivered_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\vdeliveredAt\x12B\n" +
 	"\tfailed_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\bfailedAt\x12<\n" +
-	"\x06dlq_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x05dlqAt\"\x80\x02\n" +
+	"\x06dlq_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x05dlqAt\x12@\n" +
+	"\backed_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\aackedAt\x127\n" +
+	"\ahistory\x18\x11 \x03(\v2\x1d.api.webhook.v1.AttemptRecordR\ahistory\"\xdf\x02\n" +
+	"\rAttemptRecord\x12\x18\n" +
+	"\aattempt\x18\x01 \x01(\x05R\aattempt\x12\x18\n" +
+	"\aoutcome\x18\x02 \x01(\tR\aoutcome\x12\x1f\n" +
+	"\vhttp_status\x18\x03 \x01(\x05R\n" +
+	"httpStatus\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x04 \x01(\x05R\tlatencyMs\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x123\n" +
+	"\asent_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12;\n" +
+	"\vfinished_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12#\n" +
+	"\rresponse_body\x18\b \x01(\tR\fresponseBody\x12-\n" +
+	"\x12response_truncated\x18\t \x01(\bR\x11responseTruncated\"\xbb\x02\n" +
 	"\x18GetDeliveryStatusRequest\x12&\n" +
 	"\bevent_id\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\aeventId\x12,\n" +
 	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
 	"endpointId\x129\n" +
 	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x04from\x125\n" +
-	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x02to\x12\x1c\n" +
-	"\x05limit\x18\x05 \x01(\x05B\x06\xbaH\x03\xd8\x01\x01R\x05limit\"X\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x02to\x12#\n" +
+	"\x05limit\x18\x05 \x01(\x05B\r\xbaH\n" +
+	"\xd8\x01\x01\x1a\x05\x18\xe8\a(\x00R\x05limit\x122\n" +
+	"\x15include_replay_chains\x18\x06 \x01(\bR\x13includeReplayChains\"\x9a\x01\n" +
 	"\x19GetDeliveryStatusResponse\x12;\n" +
-	"\battempts\x18\x01 \x03(\v2\x1f.api.webhook.v1.DeliveryAttemptR\battempts\"e\n" +
+	"\battempts\x18\x01 \x03(\v2\x1f.api.webhook.v1.DeliveryAttemptR\battempts\x12@\n" +
+	"\rreplay_chains\x18\x02 \x03(\v2\x1b.api.webhook.v1.ReplayChainR\freplayChains\"\x82\x02\n" +
+	"\x1aWatchDeliveryStatusRequest\x12&\n" +
+	"\bevent_id\x18\x01 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\aeventId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12\x1b\n" +
+	"\ttenant_id\x18\x03 \x01(\tR\btenantId\x124\n" +
+	"\x10poll_interval_ms\x18\x04 \x01(\x05B\n" +
+	"\xbaH\a\xd8\x01\x01\x1a\x02(\x00R\x0epollIntervalMs\x12\x16\n" +
+	"\x06follow\x18\x05 \x01(\bR\x06follow\x12#\n" +
+	"\rskip_finished\x18\x06 \x01(\bR\fskipFinished\"\xaa\x01\n" +
+	"\x1bWatchDeliveryStatusResponse\x12;\n" +
+	"\bdelivery\x18\x01 \x01(\v2\x1f.api.webhook.v1.DeliveryAttemptR\bdelivery\x12N\n" +
+	"\x0fprevious_status\x18\x02 \x01(\x0e2%.api.webhook.v1.DeliveryAttemptStatusR\x0epreviousStatus\"\xa2\x01\n" +
+	"\vReplayChain\x12(\n" +
+	"\x10root_delivery_id\x18\x01 \x01(\tR\x0erootDeliveryId\x12;\n" +
+	"\battempts\x18\x02 \x03(\v2\x1f.api.webhook.v1.DeliveryAttemptR\battempts\x12,\n" +
+	"\x12active_delivery_id\x18\x03 \x01(\tR\x10activeDeliveryId\"\xee\x01\n" +
 	"\x15ReplayDeliveryRequest\x12,\n" +
 	"\vdelivery_id\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
 	"deliveryId\x12\x1e\n" +
-	"\x06reason\x18\x02 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x06reason\"b\n" +
+	"\x06reason\x18\x02 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x06reason\x12\x19\n" +
+	"\bsame_key\x18\x03 \x01(\bR\asameKey\x12.\n" +
+	"\furl_override\x18\x04 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\x88\x01\x01R\vurlOverride\x12<\n" +
+	"\rpayload_patch\x18\x05 \x01(\v2\x17.google.protobuf.StructR\fpayloadPatch\"\x86\x01\n" +
 	"\x16ReplayDeliveryResponse\x12H\n" +
 	"\vnew_attempt\x18\x01 \x01(\v2\x1f.api.webhook.v1.DeliveryAttemptB\x06\xbaH\x03\xc8\x01\x01R\n" +
-	"newAttempt\"W\n" +
-	"\x0eListDLQRequest\x12'\n" +
-	"\vendpoint_id\x18\x01 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\n" +
-	"endpointId\x12\x1c\n" +
-	"\x05limit\x18\x02 \x01(\x05B\x06\xbaH\x03\xd8\x01\x01R\x05limit\"N\n" +
+	"newAttempt\x12\"\n" +
+	"\fdeduplicated\x18\x02 \x01(\bR\fdeduplicated\"\x96\x01\n" +
+	"\x1aAcknowledgeDeliveryRequest\x12,\n" +
+	"\vdelivery_id\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"deliveryId\x12$\n" +
+	"\ttimestamp\x18\x02 \x01(\x03B\x06\xbaH\x03\xc8\x01\x01R\ttimestamp\x12$\n" +
+	"\tsignature\x18\x03 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\tsignature\"\x9a\x01\n" +
+	"\x1bAcknowledgeDeliveryResponse\x12\x1f\n" +
+	"\vdelivery_id\x18\x01 \x01(\tR\n" +
+	"deliveryId\x125\n" +
+	"\backed_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aackedAt\x12#\n" +
+	"\ralready_acked\x18\x03 \x01(\bR\falreadyAcked\"\x9e\x02\n" +
+	"\x0eListDLQRequest\x12,\n" +
+	"\vendpoint_id\x18\x01 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12 \n" +
+	"\x05limit\x18\x02 \x01(\x05B\n" +
+	"\xbaH\a\xd8\x01\x01\x1a\x02(\x00R\x05limit\x12#\n" +
+	"\ttenant_id\x18\x03 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\btenantId\x129\n" +
+	"\x04from\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x04from\x125\n" +
+	"\x02to\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x02to\x12%\n" +
+	"\n" +
+	"page_token\x18\x06 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\tpageToken\"\x97\x01\n" +
 	"\x0fListDLQResponse\x12;\n" +
-	"\x04dead\x18\x01 \x03(\v2\x1f.api.webhook.v1.DeliveryAttemptB\x06\xbaH\x03\xd8\x01\x01R\x04dead*\x81\x02\n" +
+	"\x04dead\x18\x01 \x03(\v2\x1f.api.webhook.v1.DeliveryAttemptB\x06\xbaH\x03\xd8\x01\x01R\x04dead\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\x12\x1f\n" +
+	"\vtotal_count\x18\x03 \x01(\x05R\n" +
+	"totalCount\"\xa2\x03\n" +
+	"\x10ReplayDLQRequest\x12,\n" +
+	"\vendpoint_id\x18\x01 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12I\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tB*\xbaH'\xd8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\x129\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x04from\x125\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x02to\x12*\n" +
+	"\tmax_count\x18\x05 \x01(\x05B\r\xbaH\n" +
+	"\xd8\x01\x01\x1a\x05\x18\xe8\a(\x01R\bmaxCount\x12\x17\n" +
+	"\adry_run\x18\x06 \x01(\bR\x06dryRun\x12\x1e\n" +
+	"\x06reason\x18\a \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x06reason\x12#\n" +
+	"\ttenant_id\x18\b \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\btenantId\x12\x19\n" +
+	"\bsame_key\x18\t \x01(\bR\asameKey\"\xb5\x01\n" +
+	"\x11ReplayDLQResponse\x12#\n" +
+	"\rmatched_count\x18\x01 \x01(\x05R\fmatchedCount\x12%\n" +
+	"\x0ereplayed_count\x18\x02 \x01(\x05R\rreplayedCount\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12;\n" +
+	"\breplayed\x18\x04 \x03(\v2\x1f.api.webhook.v1.DeliveryAttemptR\breplayed\"\x82\x01\n" +
+	"\bDLQEntry\x129\n" +
+	"\aattempt\x18\x01 \x01(\v2\x1f.api.webhook.v1.DeliveryAttemptR\aattempt\x12#\n" +
+	"\rattempt_count\x18\x02 \x01(\x05R\fattemptCount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"g\n" +
+	"\x12GetDLQEntryRequest\x12,\n" +
+	"\vdelivery_id\x18\x01 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"deliveryId\x12#\n" +
+	"\ttenant_id\x18\x02 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\btenantId\"\xb5\x01\n" +
+	"\x13GetDLQEntryResponse\x12.\n" +
+	"\x05entry\x18\x01 \x01(\v2\x18.api.webhook.v1.DLQEntryR\x05entry\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\x122\n" +
+	"\ahistory\x18\x04 \x03(\v2\x18.api.webhook.v1.DLQEntryR\ahistory\"\x94\x03\n" +
+	"\x0fPurgeDLQRequest\x12,\n" +
+	"\vendpoint_id\x18\x01 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12I\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tB*\xbaH'\xd8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\x129\n" +
+	"\x04from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x04from\x125\n" +
+	"\x02to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x02to\x12,\n" +
+	"\vdelivery_id\x18\x05 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"deliveryId\x12#\n" +
+	"\ttenant_id\x18\x06 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\btenantId\x12\x17\n" +
+	"\adry_run\x18\a \x01(\bR\x06dryRun\x12\x10\n" +
+	"\x03all\x18\b \x01(\bR\x03all\x12\x18\n" +
+	"\aarchive\x18\t \x01(\bR\aarchive\"\x9a\x01\n" +
+	"\x10PurgeDLQResponse\x12#\n" +
+	"\rmatched_count\x18\x01 \x01(\x05R\fmatchedCount\x12!\n" +
+	"\fpurged_count\x18\x02 \x01(\x05R\vpurgedCount\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\x12%\n" +
+	"\x0earchived_count\x18\x04 \x01(\x05R\rarchivedCount\"\xf2\x01\n" +
+	"\fDLQRetention\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12&\n" +
+	"\x0fmax_age_seconds\x18\x02 \x01(\x03R\rmaxAgeSeconds\x12\x1f\n" +
+	"\vmax_entries\x18\x03 \x01(\x05R\n" +
+	"maxEntries\x12\x18\n" +
+	"\aarchive\x18\x04 \x01(\bR\aarchive\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12'\n" +
+	"\x0fcluster_default\x18\x06 \x01(\bR\x0eclusterDefault\"\xc8\x01\n" +
+	"\x16SetDLQRetentionRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12/\n" +
+	"\x0fmax_age_seconds\x18\x02 \x01(\x03B\a\xbaH\x04\"\x02(\x00R\rmaxAgeSeconds\x12(\n" +
+	"\vmax_entries\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\n" +
+	"maxEntries\x12\x18\n" +
+	"\aarchive\x18\x04 \x01(\bR\aarchive\x12\x14\n" +
+	"\x05reset\x18\x05 \x01(\bR\x05reset\"U\n" +
+	"\x17SetDLQRetentionResponse\x12:\n" +
+	"\tretention\x18\x01 \x01(\v2\x1c.api.webhook.v1.DLQRetentionR\tretention\"=\n" +
+	"\x16GetDLQRetentionRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\"U\n" +
+	"\x17GetDLQRetentionResponse\x12:\n" +
+	"\tretention\x18\x01 \x01(\v2\x1c.api.webhook.v1.DLQRetentionR\tretention\"\xbc\x01\n" +
+	"\x12ComplianceSettings\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12'\n" +
+	"\x0frecord_requests\x18\x02 \x01(\bR\x0erecordRequests\x12%\n" +
+	"\x0eretention_days\x18\x03 \x01(\x05R\rretentionDays\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x9e\x01\n" +
+	"\x18SetComplianceModeRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12'\n" +
+	"\x0frecord_requests\x18\x02 \x01(\bR\x0erecordRequests\x124\n" +
+	"\x0eretention_days\x18\x03 \x01(\x05B\r\xbaH\n" +
+	"\xd8\x01\x01\x1a\x05\x18\xc2\x1c(\x01R\rretentionDays\"[\n" +
+	"\x19SetComplianceModeResponse\x12>\n" +
+	"\bsettings\x18\x01 \x01(\v2\".api.webhook.v1.ComplianceSettingsR\bsettings\"\x91\x01\n" +
+	"\x10DeliverySettings\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12%\n" +
+	"\x0esender_headers\x18\x02 \x01(\bR\rsenderHeaders\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"h\n" +
+	"\x1aSetDeliverySettingsRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12%\n" +
+	"\x0esender_headers\x18\x02 \x01(\bR\rsenderHeaders\"[\n" +
+	"\x1bSetDeliverySettingsResponse\x12<\n" +
+	"\bsettings\x18\x01 \x01(\v2 .api.webhook.v1.DeliverySettingsR\bsettings\"\xae\x03\n" +
+	"\x11DeliveryRecording\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12)\n" +
+	"\vdelivery_id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\n" +
+	"deliveryId\x12\x18\n" +
+	"\aattempt\x18\x03 \x01(\x05R\aattempt\x12\x16\n" +
+	"\x06method\x18\x04 \x01(\tR\x06method\x12\x10\n" +
+	"\x03url\x18\x05 \x01(\tR\x03url\x12H\n" +
+	"\aheaders\x18\x06 \x03(\v2..api.webhook.v1.DeliveryRecording.HeadersEntryR\aheaders\x12\x12\n" +
+	"\x04body\x18\a \x01(\tR\x04body\x12;\n" +
+	"\vrecorded_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"recordedAt\x129\n" +
+	"\n" +
+	"expires_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xa9\x03\n" +
+	"\x10CapturedDelivery\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12)\n" +
+	"\vdelivery_id\x18\x02 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\n" +
+	"deliveryId\x12#\n" +
+	"\bevent_id\x18\x03 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tR\teventType\x12\x18\n" +
+	"\aattempt\x18\x05 \x01(\x05R\aattempt\x12G\n" +
+	"\aheaders\x18\x06 \x03(\v2-.api.webhook.v1.CapturedDelivery.HeadersEntryR\aheaders\x12\x12\n" +
+	"\x04body\x18\a \x01(\tR\x04body\x12\x1c\n" +
+	"\tsignature\x18\b \x01(\tR\tsignature\x12;\n" +
+	"\vcaptured_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"capturedAt\x1a:\n" +
+	"\fHeadersEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc4\x01\n" +
+	"\x1cGetCapturedDeliveriesRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12#\n" +
+	"\x05limit\x18\x03 \x01(\x05B\r\xbaH\n" +
+	"\xd8\x01\x01\x1a\x05\x18\xf4\x03(\x01R\x05limit\x12,\n" +
+	"\vdelivery_id\x18\x04 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"deliveryId\"]\n" +
+	"\x1dGetCapturedDeliveriesResponse\x12<\n" +
+	"\bcaptures\x18\x01 \x03(\v2 .api.webhook.v1.CapturedDeliveryR\bcaptures\"\x92\x01\n" +
+	"\x1dListDeliveryRecordingsRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12,\n" +
+	"\vdelivery_id\x18\x02 \x01(\tB\v\xbaH\b\xc8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"deliveryId\x12\x1e\n" +
+	"\x06reason\x18\x03 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06reason\"c\n" +
+	"\x1eListDeliveryRecordingsResponse\x12A\n" +
+	"\n" +
+	"recordings\x18\x01 \x03(\v2!.api.webhook.v1.DeliveryRecordingR\n" +
+	"recordings\"\xbe\x03\n" +
+	"\rAuditLogEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12#\n" +
+	"\rresource_type\x18\x04 \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vresource_id\x18\x05 \x01(\tR\n" +
+	"resourceId\x12&\n" +
+	"\x0factor_tenant_id\x18\x06 \x01(\tR\ractorTenantId\x12#\n" +
+	"\ractor_subject\x18\a \x01(\tR\factorSubject\x12\x1d\n" +
+	"\n" +
+	"actor_role\x18\b \x01(\tR\tactorRole\x12\x1b\n" +
+	"\tclient_ip\x18\t \x01(\tR\bclientIp\x12/\n" +
+	"\x06before\x18\n" +
+	" \x01(\v2\x17.google.protobuf.StructR\x06before\x12-\n" +
+	"\x05after\x18\v \x01(\v2\x17.google.protobuf.StructR\x05after\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xeb\x02\n" +
+	"\x13ListAuditLogRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12\x1e\n" +
+	"\x06action\x18\x02 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x06action\x12'\n" +
+	"\vresource_id\x18\x03 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\n" +
+	"resourceId\x12+\n" +
+	"\ractor_subject\x18\x04 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\factorSubject\x129\n" +
+	"\x04from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x04from\x125\n" +
+	"\x02to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x02to\x12 \n" +
+	"\x05limit\x18\a \x01(\x05B\n" +
+	"\xbaH\a\xd8\x01\x01\x1a\x02(\x00R\x05limit\x12%\n" +
+	"\n" +
+	"page_token\x18\b \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\tpageToken\"w\n" +
+	"\x14ListAuditLogResponse\x127\n" +
+	"\aentries\x18\x01 \x03(\v2\x1d.api.webhook.v1.AuditLogEntryR\aentries\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xf8\x01\n" +
+	"\x0eDeliveryFreeze\x12\x18\n" +
+	"\x02id\x18\x01 \x01(\tB\b\xbaH\x05r\x03\xb0\x01\x01R\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x1f\n" +
+	"\vendpoint_id\x18\x03 \x01(\tR\n" +
+	"endpointId\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vreleased_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"releasedAt\"\x8c\x01\n" +
+	"\x17FreezeDeliveriesRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12\x1e\n" +
+	"\x06reason\x18\x03 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x06reason\"w\n" +
+	"\x18FreezeDeliveriesResponse\x126\n" +
+	"\x06freeze\x18\x01 \x01(\v2\x1e.api.webhook.v1.DeliveryFreezeR\x06freeze\x12#\n" +
+	"\rpending_count\x18\x02 \x01(\x05R\fpendingCount\"f\n" +
+	"\x11DrainQueueRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\"7\n" +
+	"\x12DrainQueueResponse\x12!\n" +
+	"\fparked_count\x18\x01 \x01(\x05R\vparkedCount\"l\n" +
+	"\x17ResumeDeliveriesRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\"l\n" +
+	"\x18ResumeDeliveriesResponse\x12)\n" +
+	"\x10released_freezes\x18\x01 \x01(\x05R\x0freleasedFreezes\x12%\n" +
+	"\x0erequeued_count\x18\x02 \x01(\x05R\rrequeuedCount\"\xa9\x02\n" +
+	"\rDispatchState\x12\x16\n" +
+	"\x06paused\x18\x01 \x01(\bR\x06paused\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x127\n" +
+	"\tpaused_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\bpausedAt\x129\n" +
+	"\n" +
+	"resumed_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tresumedAt\x12!\n" +
+	"\framp_seconds\x18\x05 \x01(\x05R\vrampSeconds\x12,\n" +
+	"\x12ramp_start_percent\x18\x06 \x01(\x05R\x10rampStartPercent\x12#\n" +
+	"\radmit_percent\x18\a \x01(\x01R\fadmitPercent\"6\n" +
+	"\x14PauseDispatchRequest\x12\x1e\n" +
+	"\x06reason\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06reason\"L\n" +
+	"\x15PauseDispatchResponse\x123\n" +
+	"\x05state\x18\x01 \x01(\v2\x1d.api.webhook.v1.DispatchStateR\x05state\"z\n" +
+	"\x15ResumeDispatchRequest\x12.\n" +
+	"\framp_seconds\x18\x01 \x01(\x05B\v\xbaH\b\x1a\x06\x18\x80\xa3\x05(\x00R\vrampSeconds\x121\n" +
+	"\rstart_percent\x18\x02 \x01(\x05B\f\xbaH\t\xd8\x01\x01\x1a\x04\x18d(\x00R\fstartPercent\"M\n" +
+	"\x16ResumeDispatchResponse\x123\n" +
+	"\x05state\x18\x01 \x01(\v2\x1d.api.webhook.v1.DispatchStateR\x05state\"\x19\n" +
+	"\x17GetDispatchStateRequest\"O\n" +
+	"\x18GetDispatchStateResponse\x123\n" +
+	"\x05state\x18\x01 \x01(\v2\x1d.api.webhook.v1.DispatchStateR\x05state\"\xa1\x01\n" +
+	"\x19GetBacklogEstimateRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\btenantId\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x121\n" +
+	"\x0ewindow_seconds\x18\x03 \x01(\x05B\n" +
+	"\xbaH\a\x1a\x05\x18\x90\x1c(\x00R\rwindowSeconds\"\xc2\x02\n" +
+	"\x0fBacklogEstimate\x12\x1f\n" +
+	"\vendpoint_id\x18\x01 \x01(\tR\n" +
+	"endpointId\x12#\n" +
+	"\rpending_count\x18\x02 \x01(\x05R\fpendingCount\x12!\n" +
+	"\fparked_count\x18\x03 \x01(\x05R\vparkedCount\x12,\n" +
+	"\x12throughput_per_sec\x18\x04 \x01(\x01R\x10throughputPerSec\x12\x17\n" +
+	"\ahas_eta\x18\x05 \x01(\bR\x06hasEta\x12\x1f\n" +
+	"\veta_seconds\x18\x06 \x01(\x03R\n" +
+	"etaSeconds\x127\n" +
+	"\tclears_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bclearsAt\x12%\n" +
+	"\x0eblocked_reason\x18\b \x01(\tR\rblockedReason\"\xb9\x01\n" +
+	"\x1aGetBacklogEstimateResponse\x125\n" +
+	"\x05total\x18\x01 \x01(\v2\x1f.api.webhook.v1.BacklogEstimateR\x05total\x12=\n" +
+	"\tendpoints\x18\x02 \x03(\v2\x1f.api.webhook.v1.BacklogEstimateR\tendpoints\x12%\n" +
+	"\x0ewindow_seconds\x18\x03 \x01(\x05R\rwindowSeconds\"\xca\x01\n" +
+	"\vTenantQuota\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x123\n" +
+	"\x11events_per_minute\x18\x02 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x0feventsPerMinute\x12&\n" +
+	"\n" +
+	"max_fanout\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\tmaxFanout\x129\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"R\n" +
+	"\x15SetTenantQuotaRequest\x129\n" +
+	"\x05quota\x18\x01 \x01(\v2\x1b.api.webhook.v1.TenantQuotaB\x06\xbaH\x03\xc8\x01\x01R\x05quota\"K\n" +
+	"\x16SetTenantQuotaResponse\x121\n" +
+	"\x05quota\x18\x01 \x01(\v2\x1b.api.webhook.v1.TenantQuotaR\x05quota\"<\n" +
+	"\x15GetTenantQuotaRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\"y\n" +
+	"\x16GetTenantQuotaResponse\x121\n" +
+	"\x05quota\x18\x01 \x01(\v2\x1b.api.webhook.v1.TenantQuotaR\x05quota\x12,\n" +
+	"\x12events_this_minute\x18\x02 \x01(\x05R\x10eventsThisMinute\"\xd0\x01\n" +
+	"\x17GetFailureTrendsRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x122\n" +
+	"\x0ewindow_seconds\x18\x02 \x01(\x05B\v\xbaH\b\x1a\x06\x18\x80\xf5$(\x00R\rwindowSeconds\x12.\n" +
+	"\x0ebucket_seconds\x18\x03 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\rbucketSeconds\x12,\n" +
+	"\vendpoint_id\x18\x04 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\"]\n" +
+	"\fFailureCount\x12\x16\n" +
+	"\x06reason\x18\x01 \x01(\tR\x06reason\x12\x1f\n" +
+	"\vendpoint_id\x18\x02 \x01(\tR\n" +
+	"endpointId\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05count\"{\n" +
+	"\rFailureBucket\x120\n" +
+	"\x05start\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x05start\x128\n" +
+	"\bfailures\x18\x02 \x03(\v2\x1c.api.webhook.v1.FailureCountR\bfailures\"\xd7\x01\n" +
+	"\x18GetFailureTrendsResponse\x127\n" +
+	"\abuckets\x18\x01 \x03(\v2\x1d.api.webhook.v1.FailureBucketR\abuckets\x124\n" +
+	"\x06totals\x18\x02 \x03(\v2\x1c.api.webhook.v1.FailureCountR\x06totals\x12%\n" +
+	"\x0ewindow_seconds\x18\x03 \x01(\x05R\rwindowSeconds\x12%\n" +
+	"\x0ebucket_seconds\x18\x04 \x01(\x05R\rbucketSeconds\"\xeb\x01\n" +
+	"\x17GetDeliveryStatsRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x122\n" +
+	"\x0ewindow_seconds\x18\x02 \x01(\x05B\v\xbaH\b\x1a\x06\x18\x80\xf5$(\x00R\rwindowSeconds\x12,\n" +
+	"\vendpoint_id\x18\x03 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12I\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tB*\xbaH'\xd8\x01\x01r\"\x18\x80\x012\x1d^[A-Za-z0-9][A-Za-z0-9._:-]*$R\teventType\"\xcc\x03\n" +
+	"\rDeliveryStats\x12\x1f\n" +
+	"\vendpoint_id\x18\x01 \x01(\tR\n" +
+	"endpointId\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x03R\x05total\x12\x16\n" +
+	"\x06queued\x18\x03 \x01(\x03R\x06queued\x12\x1a\n" +
+	"\binflight\x18\x04 \x01(\x03R\binflight\x12\x1c\n" +
+	"\tdelivered\x18\x05 \x01(\x03R\tdelivered\x12\x16\n" +
+	"\x06failed\x18\x06 \x01(\x03R\x06failed\x12\x12\n" +
+	"\x04dead\x18\a \x01(\x03R\x04dead\x12\x16\n" +
+	"\x06parked\x18\b \x01(\x03R\x06parked\x12!\n" +
+	"\fsuccess_rate\x18\t \x01(\x01R\vsuccessRate\x12$\n" +
+	"\x0elatency_p50_ms\x18\n" +
+	" \x01(\x05R\flatencyP50Ms\x12$\n" +
+	"\x0elatency_p95_ms\x18\v \x01(\x05R\flatencyP95Ms\x12$\n" +
+	"\x0elatency_p99_ms\x18\f \x01(\x05R\flatencyP99Ms\x12\x18\n" +
+	"\aretries\x18\r \x01(\x03R\aretries\x12?\n" +
+	"\ftop_failures\x18\x0e \x03(\v2\x1c.api.webhook.v1.FailureCountR\vtopFailures\"\xb5\x01\n" +
+	"\x18GetDeliveryStatsResponse\x125\n" +
+	"\x06totals\x18\x01 \x01(\v2\x1d.api.webhook.v1.DeliveryStatsR\x06totals\x12;\n" +
+	"\tendpoints\x18\x02 \x03(\v2\x1d.api.webhook.v1.DeliveryStatsR\tendpoints\x12%\n" +
+	"\x0ewindow_seconds\x18\x03 \x01(\x05R\rwindowSeconds\"\xf7\x01\n" +
+	"\vSystemEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x1f\n" +
+	"\vendpoint_id\x18\x03 \x01(\tR\n" +
+	"endpointId\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x18\n" +
+	"\amessage\x18\x05 \x01(\tR\amessage\x121\n" +
+	"\adetails\x18\x06 \x01(\v2\x17.google.protobuf.StructR\adetails\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb9\x01\n" +
+	"\x17ListSystemEventsRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12\x1a\n" +
+	"\x04type\x18\x02 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x04type\x12;\n" +
+	"\x05since\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampB\t\xbaH\x06\xd8\x01\x01\xb2\x01\x00R\x05since\x12 \n" +
+	"\x05limit\x18\x04 \x01(\x05B\n" +
+	"\xbaH\a\xd8\x01\x01\x1a\x02(\x00R\x05limit\"O\n" +
+	"\x18ListSystemEventsResponse\x123\n" +
+	"\x06events\x18\x01 \x03(\v2\x1b.api.webhook.v1.SystemEventR\x06events\"\xf9\x01\n" +
+	"\x14NotificationSettings\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1f\n" +
+	"\vendpoint_id\x18\x03 \x01(\tR\n" +
+	"endpointId\x12#\n" +
+	"\rdlq_threshold\x18\x04 \x01(\x05R\fdlqThreshold\x12-\n" +
+	"\x13secret_max_age_days\x18\x05 \x01(\x05R\x10secretMaxAgeDays\x129\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xfb\x01\n" +
+	"\x1eSetNotificationSettingsRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\x12 \n" +
+	"\x05email\x18\x02 \x01(\tB\n" +
+	"\xbaH\a\xd8\x01\x01r\x02`\x01R\x05email\x12,\n" +
+	"\vendpoint_id\x18\x03 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12,\n" +
+	"\rdlq_threshold\x18\x04 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\fdlqThreshold\x126\n" +
+	"\x13secret_max_age_days\x18\x05 \x01(\x05B\a\xbaH\x04\x1a\x02(\x00R\x10secretMaxAgeDays\"c\n" +
+	"\x1fSetNotificationSettingsResponse\x12@\n" +
+	"\bsettings\x18\x01 \x01(\v2$.api.webhook.v1.NotificationSettingsR\bsettings\"E\n" +
+	"\x1eGetNotificationSettingsRequest\x12#\n" +
+	"\ttenant_id\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\btenantId\"c\n" +
+	"\x1fGetNotificationSettingsResponse\x12@\n" +
+	"\bsettings\x18\x01 \x01(\v2$.api.webhook.v1.NotificationSettingsR\bsettings\"\x14\n" +
+	"\x12ListTenantsRequest\"\xb9\x01\n" +
+	"\rTenantSummary\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12%\n" +
+	"\x0eendpoint_count\x18\x03 \x01(\x05R\rendpointCount\x12+\n" +
+	"\x11recent_deliveries\x18\x04 \x01(\x05R\x10recentDeliveries\x12#\n" +
+	"\rdead_lettered\x18\x05 \x01(\x05R\fdeadLettered\"N\n" +
+	"\x13ListTenantsResponse\x127\n" +
+	"\atenants\x18\x01 \x03(\v2\x1d.api.webhook.v1.TenantSummaryR\atenants\"6\n" +
+	"\x14ListEndpointsRequest\x12\x1e\n" +
+	"\x06tenant\x18\x01 \x01(\tB\x06\xbaH\x03\xc8\x01\x01R\x06tenant\"O\n" +
+	"\x15ListEndpointsResponse\x126\n" +
+	"\tendpoints\x18\x01 \x03(\v2\x18.api.webhook.v1.EndpointR\tendpoints\"\xd4\x01\n" +
+	"\x1bListRecentDeliveriesRequest\x12\x1e\n" +
+	"\x06tenant\x18\x01 \x01(\tB\x06\xbaH\x03\xd8\x01\x01R\x06tenant\x12,\n" +
+	"\vendpoint_id\x18\x02 \x01(\tB\v\xbaH\b\xd8\x01\x01r\x03\xb0\x01\x01R\n" +
+	"endpointId\x12E\n" +
+	"\x06status\x18\x03 \x01(\x0e2%.api.webhook.v1.DeliveryAttemptStatusB\x06\xbaH\x03\xd8\x01\x01R\x06status\x12 \n" +
+	"\x05limit\x18\x04 \x01(\x05B\n" +
+	"\xbaH\a\xd8\x01\x01\x1a\x02(\x00R\x05limit\"\x89\x01\n" +
+	"\x0eRecentDelivery\x12;\n" +
+	"\bdelivery\x18\x01 \x01(\v2\x1f.api.webhook.v1.DeliveryAttemptR\bdelivery\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\"^\n" +
+	"\x1cListRecentDeliveriesResponse\x12>\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x1e.api.webhook.v1.RecentDeliveryR\n" +
+	"deliveries*s\n" +
+	"\x11HealthCheckMethod\x12#\n" +
+	"\x1fHEALTH_CHECK_METHOD_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18HEALTH_CHECK_METHOD_HEAD\x10\x01\x12\x1b\n" +
+	"\x17HEALTH_CHECK_METHOD_GET\x10\x02*\xac\x01\n" +
+	"\x14EndpointHealthStatus\x12&\n" +
+	"\"ENDPOINT_HEALTH_STATUS_UNSPECIFIED\x10\x00\x12\"\n" +
+	"\x1eENDPOINT_HEALTH_STATUS_UNKNOWN\x10\x01\x12\"\n" +
+	"\x1eENDPOINT_HEALTH_STATUS_HEALTHY\x10\x02\x12$\n" +
+	" ENDPOINT_HEALTH_STATUS_UNHEALTHY\x10\x03*u\n" +
+	"\x12PayloadCompression\x12#\n" +
+	"\x1fPAYLOAD_COMPRESSION_UNSPECIFIED\x10\x00\x12\x1c\n" +
+	"\x18PAYLOAD_COMPRESSION_NONE\x10\x01\x12\x1c\n" +
+	"\x18PAYLOAD_COMPRESSION_GZIP\x10\x02*`\n" +
+	"\fEndpointType\x12\x1d\n" +
+	"\x19ENDPOINT_TYPE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12ENDPOINT_TYPE_HTTP\x10\x01\x12\x19\n" +
+	"\x15ENDPOINT_TYPE_CAPTURE\x10\x02*\x83\x01\n" +
+	"\x0fSignatureScheme\x12 \n" +
+	"\x1cSIGNATURE_SCHEME_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13SIGNATURE_SCHEME_V1\x10\x01\x12\x17\n" +
+	"\x13SIGNATURE_SCHEME_V2\x10\x02\x12\x1c\n" +
+	"\x18SIGNATURE_SCHEME_ED25519\x10\x03*{\n" +
+	"\rEventPriority\x12\x1e\n" +
+	"\x1aEVENT_PRIORITY_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13EVENT_PRIORITY_HIGH\x10\x01\x12\x19\n" +
+	"\x15EVENT_PRIORITY_NORMAL\x10\x02\x12\x16\n" +
+	"\x12EVENT_PRIORITY_LOW\x10\x03*\xa5\x02\n" +
 	"\x15DeliveryAttemptStatus\x12'\n" +
 	"#DELIVERY_ATTEMPT_STATUS_UNSPECIFIED\x10\x00\x12\"\n" +
 	"\x1eDELIVERY_ATTEMPT_STATUS_QUEUED\x10\x01\x12%\n" +
 	"!DELIVERY_ATTEMPT_STATUS_IN_FLIGHT\x10\x02\x12%\n" +
 	"!DELIVERY_ATTEMPT_STATUS_DELIVERED\x10\x03\x12\"\n" +
 	"\x1eDELIVERY_ATTEMPT_STATUS_FAILED\x10\x04\x12)\n" +
-	"%DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED\x10\x052\xf3\t\n" +
+	"%DELIVERY_ATTEMPT_STATUS_DEAD_LETTERED\x10\x05\x12\"\n" +
+	"\x1eDELIVERY_ATTEMPT_STATUS_PARKED\x10\x062\xd5e\n" +
 	"\x0eWebhookService\x12S\n" +
 	"\x04Ping\x12\x1b.api.webhook.v1.PingRequest\x1a\x1c.api.webhook.v1.PingResponse\"\x10\x82\xd3\xe4\x93\x02\n" +
 	"\x12\b/v1/ping\x12\xc5\x01\n" +
 	"\x0eCreateEndpoint\x12%.api.webhook.v1.CreateEndpointRequest\x1a&.api.webhook.v1.CreateEndpointResponse\"d\xbaG5\n" +
-	"\tEndpoints\x1a(Register a new URL as a webhook endpoint\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/tenants/{tenant_id}/endpoints\x12\xdf\x01\n" +
+	"\tEndpoints\x1a(Register a new URL as a webhook endpoint\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/tenants/{tenant_id}/endpoints\x12\x84\x02\n" +
+	"\x0eVerifyEndpoint\x12%.api.webhook.v1.VerifyEndpointRequest\x1a&.api.webhook.v1.VerifyEndpointResponse\"\xa2\x01\xbaG^\n" +
+	"\tEndpoints\x1aQVerify an endpoint with the token from its challenge, or send the challenge again\x82\xd3\xe4\x93\x02;:\x01*\"6/v1/tenants/{tenant_id}/endpoints/{endpoint_id}:verify\x12\x86\x02\n" +
+	"\x15GetCapturedDeliveries\x12,.api.webhook.v1.GetCapturedDeliveriesRequest\x1a-.api.webhook.v1.GetCapturedDeliveriesResponse\"\x8f\x01\xbaGL\n" +
+	"\tEndpoints\x1a?List the requests a capture endpoint has received, newest first\x82\xd3\xe4\x93\x02:\x128/v1/tenants/{tenant_id}/endpoints/{endpoint_id}/captures\x12\x94\x02\n" +
+	"\x17SetEndpointRecoveryRamp\x12..api.webhook.v1.SetEndpointRecoveryRampRequest\x1a/.api.webhook.v1.SetEndpointRecoveryRampResponse\"\x97\x01\xbaGL\n" +
+	"\tEndpoints\x1a?Configure how delivery ramps back up after an endpoint recovers\x82\xd3\xe4\x93\x02B:\x01*\x1a=/v1/tenants/{tenant_id}/endpoints/{endpoint_id}/recovery-ramp\x12\xa8\x02\n" +
+	"\x16SetEndpointRetryPolicy\x12-.api.webhook.v1.SetEndpointRetryPolicyRequest\x1a..api.webhook.v1.SetEndpointRetryPolicyResponse\"\xae\x01\xbaGd\n" +
+	"\tEndpoints\x1aWOverride the global retry attempts, backoff and retried failure classes for an endpoint\x82\xd3\xe4\x93\x02A:\x01*\x1a</v1/tenants/{tenant_id}/endpoints/{endpoint_id}/retry-policy\x12\xad\x02\n" +
+	"\x1cSetEndpointClientCertificate\x123.api.webhook.v1.SetEndpointClientCertificateRequest\x1a4.api.webhook.v1.SetEndpointClientCertificateResponse\"\xa1\x01\xbaGQ\n" +
+	"\tEndpoints\x1aDPresent a client certificate to an endpoint that requires mutual TLS\x82\xd3\xe4\x93\x02G:\x01*\x1aB/v1/tenants/{tenant_id}/endpoints/{endpoint_id}/client-certificate\x12\x95\x02\n" +
+	"\x16SetEndpointCompression\x12-.api.webhook.v1.SetEndpointCompressionRequest\x1a..api.webhook.v1.SetEndpointCompressionResponse\"\x9b\x01\xbaGR\n" +
+	"\tEndpoints\x1aEChoose whether webhook bodies sent to an endpoint are gzip-compressed\x82\xd3\xe4\x93\x02@:\x01*\x1a;/v1/tenants/{tenant_id}/endpoints/{endpoint_id}/compression\x12\x81\x02\n" +
+	"\x12SetEndpointTimeout\x12).api.webhook.v1.SetEndpointTimeoutRequest\x1a*.api.webhook.v1.SetEndpointTimeoutResponse\"\x93\x01\xbaGN\n" +
+	"\tEndpoints\x1aASet how long the worker waits for an endpoint to answer a webhook\x82\xd3\xe4\x93\x02<:\x01*\x1a7/v1/tenants/{tenant_id}/endpoints/{endpoint_id}/timeout\x12\x88\x02\n" +
+	"\x10SetEndpointProxy\x12'.api.webhook.v1.SetEndpointProxyRequest\x1a(.api.webhook.v1.SetEndpointProxyResponse\"\xa0\x01\xbaG]\n" +
+	"\tEndpoints\x1aPRoute an endpoint's deliveries through its own HTTP proxy, or send them directly\x82\xd3\xe4\x93\x02::\x01*\x1a5/v1/tenants/{tenant_id}/endpoints/{endpoint_id}/proxy\x12\x93\x02\n" +
+	"\x1aSetEndpointSignatureScheme\x121.api.webhook.v1.SetEndpointSignatureSchemeRequest\x1a2.api.webhook.v1.SetEndpointSignatureSchemeResponse\"\x8d\x01\xbaG?\n" +
+	"\tEndpoints\x1a2Choose how webhooks sent to an endpoint are signed\x82\xd3\xe4\x93\x02E:\x01*\x1a@/v1/tenants/{tenant_id}/endpoints/{endpoint_id}/signature-scheme\x12\x89\x02\n" +
+	"\x0eGetSigningKeys\x12%.api.webhook.v1.GetSigningKeysRequest\x1a&.api.webhook.v1.GetSigningKeysResponse\"\xa7\x01\xbaGx\n" +
+	"\tEndpoints\x1akGet a tenant's Ed25519 public keys as a JWK set, for receivers verifying ed25519 signatures. Needs no token\x82\xd3\xe4\x93\x02&\x12$/v1/tenants/{tenant_id}/signing-keys\x12\xde\x01\n" +
+	"\fGetEgressIPs\x12#.api.webhook.v1.GetEgressIPsRequest\x1a$.api.webhook.v1.GetEgressIPsResponse\"\x82\x01\xbaGi\n" +
+	"\tEndpoints\x1a\\Get the addresses webhooks are sent from, so receivers can allowlist them in their firewalls\x82\xd3\xe4\x93\x02\x10\x12\x0e/v1/egress-ips\x12\x92\x02\n" +
+	"\x13SetEndpointOrdering\x12*.api.webhook.v1.SetEndpointOrderingRequest\x1a+.api.webhook.v1.SetEndpointOrderingResponse\"\xa1\x01\xbaG[\n" +
+	"\tEndpoints\x1aNDeliver an endpoint's events one at a time per partition key, in publish order\x82\xd3\xe4\x93\x02=:\x01*\x1a8/v1/tenants/{tenant_id}/endpoints/{endpoint_id}/ordering\x12\xaf\x02\n" +
+	"\x16SetEndpointHealthCheck\x12-.api.webhook.v1.SetEndpointHealthCheckRequest\x1a..api.webhook.v1.SetEndpointHealthCheckResponse\"\xb5\x01\xbaGk\n" +
+	"\tEndpoints\x1a^Turn on periodic signed health checks for an endpoint; deliveries are held while it fails them\x82\xd3\xe4\x93\x02A:\x01*\x1a</v1/tenants/{tenant_id}/endpoints/{endpoint_id}/health-check\x12\xf2\x01\n" +
+	"\x0eEnableEndpoint\x12%.api.webhook.v1.EnableEndpointRequest\x1a&.api.webhook.v1.EnableEndpointResponse\"\x90\x01\xbaGL\n" +
+	"\tEndpoints\x1a?Enable an endpoint that was disabled after failing for too long\x82\xd3\xe4\x93\x02;:\x01*\"6/v1/tenants/{tenant_id}/endpoints/{endpoint_id}:enable\x12\xe7\x01\n" +
+	"\x0eDeleteEndpoint\x12%.api.webhook.v1.DeleteEndpointRequest\x1a&.api.webhook.v1.DeleteEndpointResponse\"\x85\x01\xbaGK\n" +
+	"\tEndpoints\x1a>Delete an endpoint along with its subscriptions and deliveries\x82\xd3\xe4\x93\x021*//v1/tenants/{tenant_id}/endpoints/{endpoint_id}\x12\xdf\x01\n" +
 	"\x12CreateSubscription\x12).api.webhook.v1.CreateSubscriptionRequest\x1a*.api.webhook.v1.CreateSubscriptionResponse\"r\xbaG?\n" +
-	"\rSubscriptions\x1a.Subscribe an endpoint to a specific event type\x82\xd3\xe4\x93\x02*:\x01*\"%/v1/tenants/{tenant_id}/subscriptions\x12\xb4\x01\n" +
-	"\fPublishEvent\x12#.api.webhook.v1.PublishEventRequest\x1a$.api.webhook.v1.PublishEventResponse\"Y\xbaG%\n" +
-	"\x06Events\x1a\x1bPublish a new webhook event\x82\xd3\xe4\x93\x02+:\x01*\"&/v1/tenants/{tenant_id}/events:publish\x12\xca\x01\n" +
+	"\rSubscriptions\x1a.Subscribe an endpoint to a specific event type\x82\xd3\xe4\x93\x02*:\x01*\"%/v1/tenants/{tenant_id}/subscriptions\x12\xbb\x02\n" +
+	"\fPublishEvent\x12#.api.webhook.v1.PublishEventRequest\x1a$.api.webhook.v1.PublishEventResponse\"\xdf\x01\xbaG\xaa\x01\n" +
+	"\x06Events\x1a\x9f\x01Publish a new webhook event. Over HTTP an Idempotency-Key header can stand in for idempotency_key; a repeated key returns the original event with duplicate set\x82\xd3\xe4\x93\x02+:\x01*\"&/v1/tenants/{tenant_id}/events:publish\x12\xdf\x01\n" +
+	"\rPublishEvents\x12$.api.webhook.v1.PublishEventsRequest\x1a%.api.webhook.v1.PublishEventsResponse\"\x80\x01\xbaGG\n" +
+	"\x06Events\x1a=Publish up to 500 events in one call, with a result per event\x82\xd3\xe4\x93\x020:\x01*\"+/v1/tenants/{tenant_id}/events:batchPublish\x12\xdd\x01\n" +
+	"\x11CreateEventSchema\x12(.api.webhook.v1.CreateEventSchemaRequest\x1a).api.webhook.v1.CreateEventSchemaResponse\"s\xbaGF\n" +
+	"\aSchemas\x1a;Register a new version of the JSON Schema for an event type\x82\xd3\xe4\x93\x02$:\x01*\"\x1f/v1/tenants/{tenant_id}/schemas\x12\xe5\x01\n" +
+	"\x10ListEventSchemas\x12'.api.webhook.v1.ListEventSchemasRequest\x1a(.api.webhook.v1.ListEventSchemasResponse\"~\xbaGT\n" +
+	"\aSchemas\x1aIList the latest schema per event type, or every version of one event type\x82\xd3\xe4\x93\x02!\x12\x1f/v1/tenants/{tenant_id}/schemas\x12\xe5\x01\n" +
+	"\x0eGetEventSchema\x12%.api.webhook.v1.GetEventSchemaRequest\x1a&.api.webhook.v1.GetEventSchemaResponse\"\x83\x01\xbaGL\n" +
+	"\aSchemas\x1aAGet one version of an event type's schema (the latest by default)\x82\xd3\xe4\x93\x02.\x12,/v1/tenants/{tenant_id}/schemas/{event_type}\x12\xca\x01\n" +
 	"\x11GetDeliveryStatus\x12(.api.webhook.v1.GetDeliveryStatusRequest\x1a).api.webhook.v1.GetDeliveryStatusResponse\"`\xbaG5\n" +
-	"\x06Events\x1a+Get the delivery status of a specific event\x82\xd3\xe4\x93\x02\"\x12 /v1/events/{event_id}/deliveries\x12\xc2\x01\n" +
+	"\x06Events\x1a+Get the delivery status of a specific event\x82\xd3\xe4\x93\x02\"\x12 /v1/events/{event_id}/deliveries\x12\xea\x01\n" +
+	"\x13WatchDeliveryStatus\x12*.api.webhook.v1.WatchDeliveryStatusRequest\x1a+.api.webhook.v1.WatchDeliveryStatusResponse\"x\xbaGY\n" +
+	"\x06Events\x1aOStream the status changes of an event's or endpoint's deliveries as they happen\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/deliveries:watch0\x01\x12\xc2\x01\n" +
 	"\x0eReplayDelivery\x12%.api.webhook.v1.ReplayDeliveryRequest\x1a&.api.webhook.v1.ReplayDeliveryResponse\"a\xbaG0\n" +
 	"\n" +
-	"Deliveries\x1a\"Replay a specific delivery attempt\x82\xd3\xe4\x93\x02(:\x01*\"#/v1/deliveries/{delivery_id}:replay\x12\x98\x01\n" +
+	"Deliveries\x1a\"Replay a specific delivery attempt\x82\xd3\xe4\x93\x02(:\x01*\"#/v1/deliveries/{delivery_id}:replay\x12\xa5\x02\n" +
+	"\x13AcknowledgeDelivery\x12*.api.webhook.v1.AcknowledgeDeliveryRequest\x1a+.api.webhook.v1.AcknowledgeDeliveryResponse\"\xb4\x01\xbaG\x85\x01\n" +
+	"\n" +
+	"Deliveries\x1awConfirm a delivery was processed. Called by receivers with a signature made from the endpoint secret instead of a token\x82\xd3\xe4\x93\x02%:\x01*\" /v1/deliveries/{delivery_id}:ack\x12\x98\x01\n" +
 	"\aListDLQ\x12\x1e.api.webhook.v1.ListDLQRequest\x1a\x1f.api.webhook.v1.ListDLQResponse\"L\xbaG:\n" +
 	"\n" +
-	"Deliveries\x1a,List all deliveries in the dead letter queue\x82\xd3\xe4\x93\x02\t\x12\a/v1/dlqB\x84\x03\xbaG\xb6\x02\n" +
+	"Deliveries\x1a,List all deliveries in the dead letter queue\x82\xd3\xe4\x93\x02\t\x12\a/v1/dlq\x12\xb4\x01\n" +
+	"\tReplayDLQ\x12 .api.webhook.v1.ReplayDLQRequest\x1a!.api.webhook.v1.ReplayDLQResponse\"b\xbaGF\n" +
+	"\n" +
+	"Deliveries\x1a8Replay every dead-lettered delivery matching the filters\x82\xd3\xe4\x93\x02\x13:\x01*\"\x0e/v1/dlq:replay\x12\xd9\x01\n" +
+	"\vGetDLQEntry\x12\".api.webhook.v1.GetDLQEntryRequest\x1a#.api.webhook.v1.GetDLQEntryResponse\"\x80\x01\xbaG`\n" +
+	"\n" +
+	"Deliveries\x1aRGet a dead-lettered delivery with its attempt count, last error and replay history\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/dlq/{delivery_id}\x12\xd2\x01\n" +
+	"\bPurgeDLQ\x12\x1f.api.webhook.v1.PurgeDLQRequest\x1a .api.webhook.v1.PurgeDLQResponse\"\x82\x01\xbaGg\n" +
+	"\n" +
+	"Deliveries\x1aYRemove dead letter queue entries matching the filters. The deliveries themselves are kept\x82\xd3\xe4\x93\x02\x12:\x01*\"\r/v1/dlq:purge\x12\x91\x02\n" +
+	"\x0fSetDLQRetention\x12&.api.webhook.v1.SetDLQRetentionRequest\x1a'.api.webhook.v1.SetDLQRetentionResponse\"\xac\x01\xbaGy\n" +
+	"\n" +
+	"Deliveries\x1akSet how long and how many dead letter queue entries a tenant keeps, and whether purged entries are archived\x82\xd3\xe4\x93\x02*:\x01*\x1a%/v1/tenants/{tenant_id}/dlq-retention\x12\xf1\x01\n" +
+	"\x0fGetDLQRetention\x12&.api.webhook.v1.GetDLQRetentionRequest\x1a'.api.webhook.v1.GetDLQRetentionResponse\"\x8c\x01\xbaG\\\n" +
+	"\n" +
+	"Deliveries\x1aNGet a tenant's dead letter queue retention, or the cluster default it inherits\x82\xd3\xe4\x93\x02'\x12%/v1/tenants/{tenant_id}/dlq-retention\x12\xd5\x01\n" +
+	"\x11SetComplianceMode\x12(.api.webhook.v1.SetComplianceModeRequest\x1a).api.webhook.v1.SetComplianceModeResponse\"k\xbaG;\n" +
+	"\n" +
+	"Compliance\x1a-Turn request recording on or off for a tenant\x82\xd3\xe4\x93\x02':\x01*\x1a\"/v1/tenants/{tenant_id}/compliance\x12\xfc\x01\n" +
+	"\x13SetDeliverySettings\x12*.api.webhook.v1.SetDeliverySettingsRequest\x1a+.api.webhook.v1.SetDeliverySettingsResponse\"\x8b\x01\xbaGT\n" +
+	"\n" +
+	"Deliveries\x1aFChoose which sender identification headers a tenant's deliveries carry\x82\xd3\xe4\x93\x02.:\x01*\x1a)/v1/tenants/{tenant_id}/delivery-settings\x12\xa5\x02\n" +
+	"\x16ListDeliveryRecordings\x12-.api.webhook.v1.ListDeliveryRecordingsRequest\x1a..api.webhook.v1.ListDeliveryRecordingsResponse\"\xab\x01\xbaGe\n" +
+	"\n" +
+	"Compliance\x1aWGet the recorded requests for a delivery. Every call is written to the access audit log\x82\xd3\xe4\x93\x02=\x12;/v1/tenants/{tenant_id}/deliveries/{delivery_id}/recordings\x12\xf4\x01\n" +
+	"\fListAuditLog\x12#.api.webhook.v1.ListAuditLogRequest\x1a$.api.webhook.v1.ListAuditLogResponse\"\x98\x01\xbaGl\n" +
+	"\n" +
+	"Compliance\x1a^List who changed a tenant's endpoints, replayed its deliveries or purged its DLQ, newest first\x82\xd3\xe4\x93\x02#\x12!/v1/tenants/{tenant_id}/audit-log\x12\xed\x01\n" +
+	"\x10FreezeDeliveries\x12'.api.webhook.v1.FreezeDeliveriesRequest\x1a(.api.webhook.v1.FreezeDeliveriesResponse\"\x85\x01\xbaGf\n" +
+	"\x05Admin\x1a]Stop dispatching deliveries for a tenant or endpoint. Matching tasks are parked when dequeued\x82\xd3\xe4\x93\x02\x16:\x01*\"\x11/v1/admin/freezes\x12\xc0\x01\n" +
+	"\n" +
+	"DrainQueue\x12!.api.webhook.v1.DrainQueueRequest\x1a\".api.webhook.v1.DrainQueueResponse\"k\xbaGH\n" +
+	"\x05Admin\x1a?Park every pending delivery for a tenant or endpoint right away\x82\xd3\xe4\x93\x02\x1a:\x01*\"\x15/v1/admin/queue:drain\x12\xdc\x01\n" +
+	"\x10ResumeDeliveries\x12'.api.webhook.v1.ResumeDeliveriesRequest\x1a(.api.webhook.v1.ResumeDeliveriesResponse\"u\xbaGO\n" +
+	"\x05Admin\x1aFLift freezes on a tenant or endpoint and requeue its parked deliveries\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/admin/freezes:resume\x12\xd3\x01\n" +
+	"\rPauseDispatch\x12$.api.webhook.v1.PauseDispatchRequest\x1a%.api.webhook.v1.PauseDispatchResponse\"u\xbaGO\n" +
+	"\x05Admin\x1aFEmergency kill switch: stop all outbound deliveries across the cluster\x82\xd3\xe4\x93\x02\x1d:\x01*\"\x18/v1/admin/dispatch:pause\x12\xdf\x01\n" +
+	"\x0eResumeDispatch\x12%.api.webhook.v1.ResumeDispatchRequest\x1a&.api.webhook.v1.ResumeDispatchResponse\"~\xbaGW\n" +
+	"\x05Admin\x1aNResume outbound deliveries, optionally ramping up from a percentage of traffic\x82\xd3\xe4\x93\x02\x1e:\x01*\"\x19/v1/admin/dispatch:resume\x12\xb6\x01\n" +
+	"\x10GetDispatchState\x12'.api.webhook.v1.GetDispatchStateRequest\x1a(.api.webhook.v1.GetDispatchStateResponse\"O\xbaG2\n" +
+	"\x05Admin\x1a)Get the state of the dispatch kill switch\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/admin/dispatch\x12\xe2\x01\n" +
+	"\x12GetBacklogEstimate\x12).api.webhook.v1.GetBacklogEstimateRequest\x1a*.api.webhook.v1.GetBacklogEstimateResponse\"u\xbaGV\n" +
+	"\n" +
+	"Deliveries\x1aHEstimate when the pending deliveries for a tenant or endpoint will clear\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/backlog/estimate\x12\xd9\x01\n" +
+	"\x0eSetTenantQuota\x12%.api.webhook.v1.SetTenantQuotaRequest\x1a&.api.webhook.v1.SetTenantQuotaResponse\"x\xbaG=\n" +
+	"\x05Admin\x1a4Set a tenant's publishing quotas (admin tenant only)\x82\xd3\xe4\x93\x022:\x05quota\x1a)/v1/admin/tenants/{quota.tenant_id}/quota\x12\xd3\x01\n" +
+	"\x0eGetTenantQuota\x12%.api.webhook.v1.GetTenantQuotaRequest\x1a&.api.webhook.v1.GetTenantQuotaResponse\"r\xbaGJ\n" +
+	"\x06Events\x1a@Get a tenant's publishing quotas and usage in the current minute\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/tenants/{tenant_id}/quota\x12\xee\x01\n" +
+	"\x10GetFailureTrends\x12'.api.webhook.v1.GetFailureTrendsRequest\x1a(.api.webhook.v1.GetFailureTrendsResponse\"\x86\x01\xbaGQ\n" +
+	"\n" +
+	"Deliveries\x1aCTime-bucketed failed delivery counts by failure reason and endpoint\x82\xd3\xe4\x93\x02,\x12*/v1/tenants/{tenant_id}/analytics/failures\x12\xa5\x02\n" +
+	"\x10GetDeliveryStats\x12'.api.webhook.v1.GetDeliveryStatsRequest\x1a(.api.webhook.v1.GetDeliveryStatsResponse\"\xbd\x01\xbaG\x85\x01\n" +
+	"\n" +
+	"Deliveries\x1awDelivery counts by status, success rate, latency percentiles, retries and top failure reasons, overall and per endpoint\x82\xd3\xe4\x93\x02.\x12,/v1/tenants/{tenant_id}/analytics/deliveries\x12\x81\x02\n" +
+	"\x10ListSystemEvents\x12'.api.webhook.v1.ListSystemEventsRequest\x1a(.api.webhook.v1.ListSystemEventsResponse\"\x99\x01\xbaGi\n" +
+	"\tEndpoints\x1a\\List conditions harborhook detected on a tenant's endpoints, such as response-code anomalies\x82\xd3\xe4\x93\x02'\x12%/v1/tenants/{tenant_id}/system-events\x12\xc5\x02\n" +
+	"\x17SetNotificationSettings\x12..api.webhook.v1.SetNotificationSettingsRequest\x1a/.api.webhook.v1.SetNotificationSettingsResponse\"\xc8\x01\xbaG\x8c\x01\n" +
+	"\tEndpoints\x1a\x7fSet where a tenant's operational notices go (an email address and/or one of its endpoints) and the thresholds that trigger them\x82\xd3\xe4\x93\x022:\x01*\x1a-/v1/tenants/{tenant_id}/notification-settings\x12\x92\x02\n" +
+	"\x17GetNotificationSettings\x12..api.webhook.v1.GetNotificationSettingsRequest\x1a/.api.webhook.v1.GetNotificationSettingsResponse\"\x95\x01\xbaG]\n" +
+	"\tEndpoints\x1aPGet where a tenant's operational notices go and the thresholds that trigger them\x82\xd3\xe4\x93\x02/\x12-/v1/tenants/{tenant_id}/notification-settings\x12\xbf\x01\n" +
+	"\vListTenants\x12\".api.webhook.v1.ListTenantsRequest\x1a#.api.webhook.v1.ListTenantsResponse\"g\xbaGK\n" +
+	"\x05Admin\x1aBList tenants with endpoint and delivery counts (admin tenant only)\x82\xd3\xe4\x93\x02\x13\x12\x11/v1/admin/tenants\x12\xc3\x01\n" +
+	"\rListEndpoints\x12$.api.webhook.v1.ListEndpointsRequest\x1a%.api.webhook.v1.ListEndpointsResponse\"e\xbaG6\n" +
+	"\x05Admin\x1a-List a tenant's endpoints (admin tenant only)\x82\xd3\xe4\x93\x02&\x12$/v1/admin/tenants/{tenant}/endpoints\x12\xdd\x01\n" +
+	"\x14ListRecentDeliveries\x12+.api.webhook.v1.ListRecentDeliveriesRequest\x1a,.api.webhook.v1.ListRecentDeliveriesResponse\"j\xbaGK\n" +
+	"\x05Admin\x1aBList the most recent deliveries across tenants (admin tenant only)\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/admin/deliveriesB\xcd\x04\xbaG\xff\x03\n" +
 	"\x053.0.0\x12m\n" +
 	"\n" +
 	"HarborHook\x12(A Go-first multi-tenant webhook platform\".\n" +
@@ -1270,7 +10748,11 @@ const file_api_webhook_v1_service_proto_rawDesc = "" +
 	"\rSubscriptions\x12$Get data about webhook subscriptions:'\n" +
 	"\x06Events\x12\x1dGet data about webhook events:/\n" +
 	"\n" +
-	"Deliveries\x12!Get data about webhook deliveriesZHgithub.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1;webhookv1b\x06proto3"
+	"Deliveries\x12!Get data about webhook deliveries:I\n" +
+	"\aSchemas\x12>Register JSON Schemas that published event payloads must match:<\n" +
+	"\n" +
+	"Compliance\x12.Manage request recording for regulated tenants:>\n" +
+	"\x05Admin\x125Incident controls for pausing and resuming deliveriesZHgithub.com/austindbirch/harbor_hook/protogen/go/api/webhook/v1;webhookv1b\x06proto3"
 
 var (
 	file_api_webhook_v1_service_proto_rawDescOnce sync.Once
@@ -1284,67 +10766,423 @@ func file_api_webhook_v1_service_proto_rawDescGZIP() []byte {
 	return file_api_webhook_v1_service_proto_rawDescData
 }
 
-var file_api_webhook_v1_service_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_api_webhook_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 17)
+var file_api_webhook_v1_service_proto_enumTypes = make([]protoimpl.EnumInfo, 7)
+var file_api_webhook_v1_service_proto_msgTypes = make([]protoimpl.MessageInfo, 143)
 var file_api_webhook_v1_service_proto_goTypes = []any{
-	(DeliveryAttemptStatus)(0),         // 0: api.webhook.v1.DeliveryAttemptStatus
-	(*PingRequest)(nil),                // 1: api.webhook.v1.PingRequest
-	(*PingResponse)(nil),               // 2: api.webhook.v1.PingResponse
-	(*Endpoint)(nil),                   // 3: api.webhook.v1.Endpoint
-	(*Subscription)(nil),               // 4: api.webhook.v1.Subscription
-	(*CreateEndpointRequest)(nil),      // 5: api.webhook.v1.CreateEndpointRequest
-	(*CreateEndpointResponse)(nil),     // 6: api.webhook.v1.CreateEndpointResponse
-	(*CreateSubscriptionRequest)(nil),  // 7: api.webhook.v1.CreateSubscriptionRequest
-	(*CreateSubscriptionResponse)(nil), // 8: api.webhook.v1.CreateSubscriptionResponse
-	(*PublishEventRequest)(nil),        // 9: api.webhook.v1.PublishEventRequest
-	(*PublishEventResponse)(nil),       // 10: api.webhook.v1.PublishEventResponse
-	(*DeliveryAttempt)(nil),            // 11: api.webhook.v1.DeliveryAttempt
-	(*GetDeliveryStatusRequest)(nil),   // 12: api.webhook.v1.GetDeliveryStatusRequest
-	(*GetDeliveryStatusResponse)(nil),  // 13: api.webhook.v1.GetDeliveryStatusResponse
-	(*ReplayDeliveryRequest)(nil),      // 14: api.webhook.v1.ReplayDeliveryRequest
-	(*ReplayDeliveryResponse)(nil),     // 15: api.webhook.v1.ReplayDeliveryResponse
-	(*ListDLQRequest)(nil),             // 16: api.webhook.v1.ListDLQRequest
-	(*ListDLQResponse)(nil),            // 17: api.webhook.v1.ListDLQResponse
-	(*timestamppb.Timestamp)(nil),      // 18: google.protobuf.Timestamp
-	(*structpb.Struct)(nil),            // 19: google.protobuf.Struct
+	(HealthCheckMethod)(0),                       // 0: api.webhook.v1.HealthCheckMethod
+	(EndpointHealthStatus)(0),                    // 1: api.webhook.v1.EndpointHealthStatus
+	(PayloadCompression)(0),                      // 2: api.webhook.v1.PayloadCompression
+	(EndpointType)(0),                            // 3: api.webhook.v1.EndpointType
+	(SignatureScheme)(0),                         // 4: api.webhook.v1.SignatureScheme
+	(EventPriority)(0),                           // 5: api.webhook.v1.EventPriority
+	(DeliveryAttemptStatus)(0),                   // 6: api.webhook.v1.DeliveryAttemptStatus
+	(*PingRequest)(nil),                          // 7: api.webhook.v1.PingRequest
+	(*PingResponse)(nil),                         // 8: api.webhook.v1.PingResponse
+	(*Endpoint)(nil),                             // 9: api.webhook.v1.Endpoint
+	(*EndpointHealthCheck)(nil),                  // 10: api.webhook.v1.EndpointHealthCheck
+	(*EndpointHealth)(nil),                       // 11: api.webhook.v1.EndpointHealth
+	(*DeliveryOrdering)(nil),                     // 12: api.webhook.v1.DeliveryOrdering
+	(*RecoveryRamp)(nil),                         // 13: api.webhook.v1.RecoveryRamp
+	(*RetryPolicy)(nil),                          // 14: api.webhook.v1.RetryPolicy
+	(*ClientCertificate)(nil),                    // 15: api.webhook.v1.ClientCertificate
+	(*Subscription)(nil),                         // 16: api.webhook.v1.Subscription
+	(*CreateEndpointRequest)(nil),                // 17: api.webhook.v1.CreateEndpointRequest
+	(*SetEndpointRecoveryRampRequest)(nil),       // 18: api.webhook.v1.SetEndpointRecoveryRampRequest
+	(*SetEndpointRecoveryRampResponse)(nil),      // 19: api.webhook.v1.SetEndpointRecoveryRampResponse
+	(*SetEndpointRetryPolicyRequest)(nil),        // 20: api.webhook.v1.SetEndpointRetryPolicyRequest
+	(*SetEndpointRetryPolicyResponse)(nil),       // 21: api.webhook.v1.SetEndpointRetryPolicyResponse
+	(*SetEndpointClientCertificateRequest)(nil),  // 22: api.webhook.v1.SetEndpointClientCertificateRequest
+	(*SetEndpointClientCertificateResponse)(nil), // 23: api.webhook.v1.SetEndpointClientCertificateResponse
+	(*SetEndpointCompressionRequest)(nil),        // 24: api.webhook.v1.SetEndpointCompressionRequest
+	(*SetEndpointCompressionResponse)(nil),       // 25: api.webhook.v1.SetEndpointCompressionResponse
+	(*SetEndpointTimeoutRequest)(nil),            // 26: api.webhook.v1.SetEndpointTimeoutRequest
+	(*SetEndpointTimeoutResponse)(nil),           // 27: api.webhook.v1.SetEndpointTimeoutResponse
+	(*SetEndpointProxyRequest)(nil),              // 28: api.webhook.v1.SetEndpointProxyRequest
+	(*SetEndpointProxyResponse)(nil),             // 29: api.webhook.v1.SetEndpointProxyResponse
+	(*SetEndpointSignatureSchemeRequest)(nil),    // 30: api.webhook.v1.SetEndpointSignatureSchemeRequest
+	(*SetEndpointSignatureSchemeResponse)(nil),   // 31: api.webhook.v1.SetEndpointSignatureSchemeResponse
+	(*GetSigningKeysRequest)(nil),                // 32: api.webhook.v1.GetSigningKeysRequest
+	(*SigningKey)(nil),                           // 33: api.webhook.v1.SigningKey
+	(*GetSigningKeysResponse)(nil),               // 34: api.webhook.v1.GetSigningKeysResponse
+	(*GetEgressIPsRequest)(nil),                  // 35: api.webhook.v1.GetEgressIPsRequest
+	(*GetEgressIPsResponse)(nil),                 // 36: api.webhook.v1.GetEgressIPsResponse
+	(*SetEndpointHealthCheckRequest)(nil),        // 37: api.webhook.v1.SetEndpointHealthCheckRequest
+	(*SetEndpointHealthCheckResponse)(nil),       // 38: api.webhook.v1.SetEndpointHealthCheckResponse
+	(*EnableEndpointRequest)(nil),                // 39: api.webhook.v1.EnableEndpointRequest
+	(*EnableEndpointResponse)(nil),               // 40: api.webhook.v1.EnableEndpointResponse
+	(*SetEndpointOrderingRequest)(nil),           // 41: api.webhook.v1.SetEndpointOrderingRequest
+	(*SetEndpointOrderingResponse)(nil),          // 42: api.webhook.v1.SetEndpointOrderingResponse
+	(*DeleteEndpointRequest)(nil),                // 43: api.webhook.v1.DeleteEndpointRequest
+	(*DeleteEndpointResponse)(nil),               // 44: api.webhook.v1.DeleteEndpointResponse
+	(*CreateEndpointResponse)(nil),               // 45: api.webhook.v1.CreateEndpointResponse
+	(*VerifyEndpointRequest)(nil),                // 46: api.webhook.v1.VerifyEndpointRequest
+	(*VerifyEndpointResponse)(nil),               // 47: api.webhook.v1.VerifyEndpointResponse
+	(*CreateSubscriptionRequest)(nil),            // 48: api.webhook.v1.CreateSubscriptionRequest
+	(*CreateSubscriptionResponse)(nil),           // 49: api.webhook.v1.CreateSubscriptionResponse
+	(*PublishEventRequest)(nil),                  // 50: api.webhook.v1.PublishEventRequest
+	(*PublishEventResponse)(nil),                 // 51: api.webhook.v1.PublishEventResponse
+	(*BatchEvent)(nil),                           // 52: api.webhook.v1.BatchEvent
+	(*PublishEventsRequest)(nil),                 // 53: api.webhook.v1.PublishEventsRequest
+	(*PublishEventResult)(nil),                   // 54: api.webhook.v1.PublishEventResult
+	(*PublishEventsResponse)(nil),                // 55: api.webhook.v1.PublishEventsResponse
+	(*EventSchema)(nil),                          // 56: api.webhook.v1.EventSchema
+	(*CreateEventSchemaRequest)(nil),             // 57: api.webhook.v1.CreateEventSchemaRequest
+	(*CreateEventSchemaResponse)(nil),            // 58: api.webhook.v1.CreateEventSchemaResponse
+	(*ListEventSchemasRequest)(nil),              // 59: api.webhook.v1.ListEventSchemasRequest
+	(*ListEventSchemasResponse)(nil),             // 60: api.webhook.v1.ListEventSchemasResponse
+	(*GetEventSchemaRequest)(nil),                // 61: api.webhook.v1.GetEventSchemaRequest
+	(*GetEventSchemaResponse)(nil),               // 62: api.webhook.v1.GetEventSchemaResponse
+	(*DeliveryAttempt)(nil),                      // 63: api.webhook.v1.DeliveryAttempt
+	(*AttemptRecord)(nil),                        // 64: api.webhook.v1.AttemptRecord
+	(*GetDeliveryStatusRequest)(nil),             // 65: api.webhook.v1.GetDeliveryStatusRequest
+	(*GetDeliveryStatusResponse)(nil),            // 66: api.webhook.v1.GetDeliveryStatusResponse
+	(*WatchDeliveryStatusRequest)(nil),           // 67: api.webhook.v1.WatchDeliveryStatusRequest
+	(*WatchDeliveryStatusResponse)(nil),          // 68: api.webhook.v1.WatchDeliveryStatusResponse
+	(*ReplayChain)(nil),                          // 69: api.webhook.v1.ReplayChain
+	(*ReplayDeliveryRequest)(nil),                // 70: api.webhook.v1.ReplayDeliveryRequest
+	(*ReplayDeliveryResponse)(nil),               // 71: api.webhook.v1.ReplayDeliveryResponse
+	(*AcknowledgeDeliveryRequest)(nil),           // 72: api.webhook.v1.AcknowledgeDeliveryRequest
+	(*AcknowledgeDeliveryResponse)(nil),          // 73: api.webhook.v1.AcknowledgeDeliveryResponse
+	(*ListDLQRequest)(nil),                       // 74: api.webhook.v1.ListDLQRequest
+	(*ListDLQResponse)(nil),                      // 75: api.webhook.v1.ListDLQResponse
+	(*ReplayDLQRequest)(nil),                     // 76: api.webhook.v1.ReplayDLQRequest
+	(*ReplayDLQResponse)(nil),                    // 77: api.webhook.v1.ReplayDLQResponse
+	(*DLQEntry)(nil),                             // 78: api.webhook.v1.DLQEntry
+	(*GetDLQEntryRequest)(nil),                   // 79: api.webhook.v1.GetDLQEntryRequest
+	(*GetDLQEntryResponse)(nil),                  // 80: api.webhook.v1.GetDLQEntryResponse
+	(*PurgeDLQRequest)(nil),                      // 81: api.webhook.v1.PurgeDLQRequest
+	(*PurgeDLQResponse)(nil),                     // 82: api.webhook.v1.PurgeDLQResponse
+	(*DLQRetention)(nil),                         // 83: api.webhook.v1.DLQRetention
+	(*SetDLQRetentionRequest)(nil),               // 84: api.webhook.v1.SetDLQRetentionRequest
+	(*SetDLQRetentionResponse)(nil),              // 85: api.webhook.v1.SetDLQRetentionResponse
+	(*GetDLQRetentionRequest)(nil),               // 86: api.webhook.v1.GetDLQRetentionRequest
+	(*GetDLQRetentionResponse)(nil),              // 87: api.webhook.v1.GetDLQRetentionResponse
+	(*ComplianceSettings)(nil),                   // 88: api.webhook.v1.ComplianceSettings
+	(*SetComplianceModeRequest)(nil),             // 89: api.webhook.v1.SetComplianceModeRequest
+	(*SetComplianceModeResponse)(nil),            // 90: api.webhook.v1.SetComplianceModeResponse
+	(*DeliverySettings)(nil),                     // 91: api.webhook.v1.DeliverySettings
+	(*SetDeliverySettingsRequest)(nil),           // 92: api.webhook.v1.SetDeliverySettingsRequest
+	(*SetDeliverySettingsResponse)(nil),          // 93: api.webhook.v1.SetDeliverySettingsResponse
+	(*DeliveryRecording)(nil),                    // 94: api.webhook.v1.DeliveryRecording
+	(*CapturedDelivery)(nil),                     // 95: api.webhook.v1.CapturedDelivery
+	(*GetCapturedDeliveriesRequest)(nil),         // 96: api.webhook.v1.GetCapturedDeliveriesRequest
+	(*GetCapturedDeliveriesResponse)(nil),        // 97: api.webhook.v1.GetCapturedDeliveriesResponse
+	(*ListDeliveryRecordingsRequest)(nil),        // 98: api.webhook.v1.ListDeliveryRecordingsRequest
+	(*ListDeliveryRecordingsResponse)(nil),       // 99: api.webhook.v1.ListDeliveryRecordingsResponse
+	(*AuditLogEntry)(nil),                        // 100: api.webhook.v1.AuditLogEntry
+	(*ListAuditLogRequest)(nil),                  // 101: api.webhook.v1.ListAuditLogRequest
+	(*ListAuditLogResponse)(nil),                 // 102: api.webhook.v1.ListAuditLogResponse
+	(*DeliveryFreeze)(nil),                       // 103: api.webhook.v1.DeliveryFreeze
+	(*FreezeDeliveriesRequest)(nil),              // 104: api.webhook.v1.FreezeDeliveriesRequest
+	(*FreezeDeliveriesResponse)(nil),             // 105: api.webhook.v1.FreezeDeliveriesResponse
+	(*DrainQueueRequest)(nil),                    // 106: api.webhook.v1.DrainQueueRequest
+	(*DrainQueueResponse)(nil),                   // 107: api.webhook.v1.DrainQueueResponse
+	(*ResumeDeliveriesRequest)(nil),              // 108: api.webhook.v1.ResumeDeliveriesRequest
+	(*ResumeDeliveriesResponse)(nil),             // 109: api.webhook.v1.ResumeDeliveriesResponse
+	(*DispatchState)(nil),                        // 110: api.webhook.v1.DispatchState
+	(*PauseDispatchRequest)(nil),                 // 111: api.webhook.v1.PauseDispatchRequest
+	(*PauseDispatchResponse)(nil),                // 112: api.webhook.v1.PauseDispatchResponse
+	(*ResumeDispatchRequest)(nil),                // 113: api.webhook.v1.ResumeDispatchRequest
+	(*ResumeDispatchResponse)(nil),               // 114: api.webhook.v1.ResumeDispatchResponse
+	(*GetDispatchStateRequest)(nil),              // 115: api.webhook.v1.GetDispatchStateRequest
+	(*GetDispatchStateResponse)(nil),             // 116: api.webhook.v1.GetDispatchStateResponse
+	(*GetBacklogEstimateRequest)(nil),            // 117: api.webhook.v1.GetBacklogEstimateRequest
+	(*BacklogEstimate)(nil),                      // 118: api.webhook.v1.BacklogEstimate
+	(*GetBacklogEstimateResponse)(nil),           // 119: api.webhook.v1.GetBacklogEstimateResponse
+	(*TenantQuota)(nil),                          // 120: api.webhook.v1.TenantQuota
+	(*SetTenantQuotaRequest)(nil),                // 121: api.webhook.v1.SetTenantQuotaRequest
+	(*SetTenantQuotaResponse)(nil),               // 122: api.webhook.v1.SetTenantQuotaResponse
+	(*GetTenantQuotaRequest)(nil),                // 123: api.webhook.v1.GetTenantQuotaRequest
+	(*GetTenantQuotaResponse)(nil),               // 124: api.webhook.v1.GetTenantQuotaResponse
+	(*GetFailureTrendsRequest)(nil),              // 125: api.webhook.v1.GetFailureTrendsRequest
+	(*FailureCount)(nil),                         // 126: api.webhook.v1.FailureCount
+	(*FailureBucket)(nil),                        // 127: api.webhook.v1.FailureBucket
+	(*GetFailureTrendsResponse)(nil),             // 128: api.webhook.v1.GetFailureTrendsResponse
+	(*GetDeliveryStatsRequest)(nil),              // 129: api.webhook.v1.GetDeliveryStatsRequest
+	(*DeliveryStats)(nil),                        // 130: api.webhook.v1.DeliveryStats
+	(*GetDeliveryStatsResponse)(nil),             // 131: api.webhook.v1.GetDeliveryStatsResponse
+	(*SystemEvent)(nil),                          // 132: api.webhook.v1.SystemEvent
+	(*ListSystemEventsRequest)(nil),              // 133: api.webhook.v1.ListSystemEventsRequest
+	(*ListSystemEventsResponse)(nil),             // 134: api.webhook.v1.ListSystemEventsResponse
+	(*NotificationSettings)(nil),                 // 135: api.webhook.v1.NotificationSettings
+	(*SetNotificationSettingsRequest)(nil),       // 136: api.webhook.v1.SetNotificationSettingsRequest
+	(*SetNotificationSettingsResponse)(nil),      // 137: api.webhook.v1.SetNotificationSettingsResponse
+	(*GetNotificationSettingsRequest)(nil),       // 138: api.webhook.v1.GetNotificationSettingsRequest
+	(*GetNotificationSettingsResponse)(nil),      // 139: api.webhook.v1.GetNotificationSettingsResponse
+	(*ListTenantsRequest)(nil),                   // 140: api.webhook.v1.ListTenantsRequest
+	(*TenantSummary)(nil),                        // 141: api.webhook.v1.TenantSummary
+	(*ListTenantsResponse)(nil),                  // 142: api.webhook.v1.ListTenantsResponse
+	(*ListEndpointsRequest)(nil),                 // 143: api.webhook.v1.ListEndpointsRequest
+	(*ListEndpointsResponse)(nil),                // 144: api.webhook.v1.ListEndpointsResponse
+	(*ListRecentDeliveriesRequest)(nil),          // 145: api.webhook.v1.ListRecentDeliveriesRequest
+	(*RecentDelivery)(nil),                       // 146: api.webhook.v1.RecentDelivery
+	(*ListRecentDeliveriesResponse)(nil),         // 147: api.webhook.v1.ListRecentDeliveriesResponse
+	nil,                                          // 148: api.webhook.v1.DeliveryRecording.HeadersEntry
+	nil,                                          // 149: api.webhook.v1.CapturedDelivery.HeadersEntry
+	(*timestamppb.Timestamp)(nil),                // 150: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),                      // 151: google.protobuf.Struct
+	(*durationpb.Duration)(nil),                  // 152: google.protobuf.Duration
 }
 var file_api_webhook_v1_service_proto_depIdxs = []int32{
-	18, // 0: api.webhook.v1.Endpoint.created_at:type_name -> google.protobuf.Timestamp
-	18, // 1: api.webhook.v1.Subscription.created_at:type_name -> google.protobuf.Timestamp
-	3,  // 2: api.webhook.v1.CreateEndpointResponse.endpoint:type_name -> api.webhook.v1.Endpoint
-	4,  // 3: api.webhook.v1.CreateSubscriptionResponse.subscription:type_name -> api.webhook.v1.Subscription
-	19, // 4: api.webhook.v1.PublishEventRequest.payload:type_name -> google.protobuf.Struct
-	0,  // 5: api.webhook.v1.DeliveryAttempt.status:type_name -> api.webhook.v1.DeliveryAttemptStatus
-	18, // 6: api.webhook.v1.DeliveryAttempt.enqueued_at:type_name -> google.protobuf.Timestamp
-	18, // 7: api.webhook.v1.DeliveryAttempt.dequeued_at:type_name -> google.protobuf.Timestamp
-	18, // 8: api.webhook.v1.DeliveryAttempt.sent_at:type_name -> google.protobuf.Timestamp
-	18, // 9: api.webhook.v1.DeliveryAttempt.delivered_at:type_name -> google.protobuf.Timestamp
-	18, // 10: api.webhook.v1.DeliveryAttempt.failed_at:type_name -> google.protobuf.Timestamp
-	18, // 11: api.webhook.v1.DeliveryAttempt.dlq_at:type_name -> google.protobuf.Timestamp
-	18, // 12: api.webhook.v1.GetDeliveryStatusRequest.from:type_name -> google.protobuf.Timestamp
-	18, // 13: api.webhook.v1.GetDeliveryStatusRequest.to:type_name -> google.protobuf.Timestamp
-	11, // 14: api.webhook.v1.GetDeliveryStatusResponse.attempts:type_name -> api.webhook.v1.DeliveryAttempt
-	11, // 15: api.webhook.v1.ReplayDeliveryResponse.new_attempt:type_name -> api.webhook.v1.DeliveryAttempt
-	11, // 16: api.webhook.v1.ListDLQResponse.dead:type_name -> api.webhook.v1.DeliveryAttempt
-	1,  // 17: api.webhook.v1.WebhookService.Ping:input_type -> api.webhook.v1.PingRequest
-	5,  // 18: api.webhook.v1.WebhookService.CreateEndpoint:input_type -> api.webhook.v1.CreateEndpointRequest
-	7,  // 19: api.webhook.v1.WebhookService.CreateSubscription:input_type -> api.webhook.v1.CreateSubscriptionRequest
-	9,  // 20: api.webhook.v1.WebhookService.PublishEvent:input_type -> api.webhook.v1.PublishEventRequest
-	12, // 21: api.webhook.v1.WebhookService.GetDeliveryStatus:input_type -> api.webhook.v1.GetDeliveryStatusRequest
-	14, // 22: api.webhook.v1.WebhookService.ReplayDelivery:input_type -> api.webhook.v1.ReplayDeliveryRequest
-	16, // 23: api.webhook.v1.WebhookService.ListDLQ:input_type -> api.webhook.v1.ListDLQRequest
-	2,  // 24: api.webhook.v1.WebhookService.Ping:output_type -> api.webhook.v1.PingResponse
-	6,  // 25: api.webhook.v1.WebhookService.CreateEndpoint:output_type -> api.webhook.v1.CreateEndpointResponse
-	8,  // 26: api.webhook.v1.WebhookService.CreateSubscription:output_type -> api.webhook.v1.CreateSubscriptionResponse
-	10, // 27: api.webhook.v1.WebhookService.PublishEvent:output_type -> api.webhook.v1.PublishEventResponse
-	13, // 28: api.webhook.v1.WebhookService.GetDeliveryStatus:output_type -> api.webhook.v1.GetDeliveryStatusResponse
-	15, // 29: api.webhook.v1.WebhookService.ReplayDelivery:output_type -> api.webhook.v1.ReplayDeliveryResponse
-	17, // 30: api.webhook.v1.WebhookService.ListDLQ:output_type -> api.webhook.v1.ListDLQResponse
-	24, // [24:31] is the sub-list for method output_type
-	17, // [17:24] is the sub-list for method input_type
-	17, // [17:17] is the sub-list for extension type_name
-	17, // [17:17] is the sub-list for extension extendee
-	0,  // [0:17] is the sub-list for field type_name
+	150, // 0: api.webhook.v1.Endpoint.created_at:type_name -> google.protobuf.Timestamp
+	13,  // 1: api.webhook.v1.Endpoint.recovery_ramp:type_name -> api.webhook.v1.RecoveryRamp
+	14,  // 2: api.webhook.v1.Endpoint.retry_policy:type_name -> api.webhook.v1.RetryPolicy
+	150, // 3: api.webhook.v1.Endpoint.verified_at:type_name -> google.protobuf.Timestamp
+	15,  // 4: api.webhook.v1.Endpoint.client_certificate:type_name -> api.webhook.v1.ClientCertificate
+	2,   // 5: api.webhook.v1.Endpoint.compression:type_name -> api.webhook.v1.PayloadCompression
+	12,  // 6: api.webhook.v1.Endpoint.ordering:type_name -> api.webhook.v1.DeliveryOrdering
+	4,   // 7: api.webhook.v1.Endpoint.signature_scheme:type_name -> api.webhook.v1.SignatureScheme
+	3,   // 8: api.webhook.v1.Endpoint.type:type_name -> api.webhook.v1.EndpointType
+	10,  // 9: api.webhook.v1.Endpoint.health_check:type_name -> api.webhook.v1.EndpointHealthCheck
+	11,  // 10: api.webhook.v1.Endpoint.health:type_name -> api.webhook.v1.EndpointHealth
+	150, // 11: api.webhook.v1.Endpoint.disabled_at:type_name -> google.protobuf.Timestamp
+	0,   // 12: api.webhook.v1.EndpointHealthCheck.method:type_name -> api.webhook.v1.HealthCheckMethod
+	1,   // 13: api.webhook.v1.EndpointHealth.status:type_name -> api.webhook.v1.EndpointHealthStatus
+	150, // 14: api.webhook.v1.EndpointHealth.last_checked_at:type_name -> google.protobuf.Timestamp
+	150, // 15: api.webhook.v1.EndpointHealth.changed_at:type_name -> google.protobuf.Timestamp
+	150, // 16: api.webhook.v1.ClientCertificate.not_after:type_name -> google.protobuf.Timestamp
+	150, // 17: api.webhook.v1.Subscription.created_at:type_name -> google.protobuf.Timestamp
+	13,  // 18: api.webhook.v1.CreateEndpointRequest.recovery_ramp:type_name -> api.webhook.v1.RecoveryRamp
+	14,  // 19: api.webhook.v1.CreateEndpointRequest.retry_policy:type_name -> api.webhook.v1.RetryPolicy
+	2,   // 20: api.webhook.v1.CreateEndpointRequest.compression:type_name -> api.webhook.v1.PayloadCompression
+	4,   // 21: api.webhook.v1.CreateEndpointRequest.signature_scheme:type_name -> api.webhook.v1.SignatureScheme
+	3,   // 22: api.webhook.v1.CreateEndpointRequest.type:type_name -> api.webhook.v1.EndpointType
+	13,  // 23: api.webhook.v1.SetEndpointRecoveryRampRequest.recovery_ramp:type_name -> api.webhook.v1.RecoveryRamp
+	9,   // 24: api.webhook.v1.SetEndpointRecoveryRampResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	14,  // 25: api.webhook.v1.SetEndpointRetryPolicyRequest.retry_policy:type_name -> api.webhook.v1.RetryPolicy
+	9,   // 26: api.webhook.v1.SetEndpointRetryPolicyResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	9,   // 27: api.webhook.v1.SetEndpointClientCertificateResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	2,   // 28: api.webhook.v1.SetEndpointCompressionRequest.compression:type_name -> api.webhook.v1.PayloadCompression
+	9,   // 29: api.webhook.v1.SetEndpointCompressionResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	9,   // 30: api.webhook.v1.SetEndpointTimeoutResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	9,   // 31: api.webhook.v1.SetEndpointProxyResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	4,   // 32: api.webhook.v1.SetEndpointSignatureSchemeRequest.signature_scheme:type_name -> api.webhook.v1.SignatureScheme
+	9,   // 33: api.webhook.v1.SetEndpointSignatureSchemeResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	33,  // 34: api.webhook.v1.GetSigningKeysResponse.keys:type_name -> api.webhook.v1.SigningKey
+	10,  // 35: api.webhook.v1.SetEndpointHealthCheckRequest.health_check:type_name -> api.webhook.v1.EndpointHealthCheck
+	9,   // 36: api.webhook.v1.SetEndpointHealthCheckResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	9,   // 37: api.webhook.v1.EnableEndpointResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	12,  // 38: api.webhook.v1.SetEndpointOrderingRequest.ordering:type_name -> api.webhook.v1.DeliveryOrdering
+	9,   // 39: api.webhook.v1.SetEndpointOrderingResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	9,   // 40: api.webhook.v1.CreateEndpointResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	9,   // 41: api.webhook.v1.VerifyEndpointResponse.endpoint:type_name -> api.webhook.v1.Endpoint
+	16,  // 42: api.webhook.v1.CreateSubscriptionResponse.subscription:type_name -> api.webhook.v1.Subscription
+	151, // 43: api.webhook.v1.PublishEventRequest.payload:type_name -> google.protobuf.Struct
+	150, // 44: api.webhook.v1.PublishEventRequest.deliver_by:type_name -> google.protobuf.Timestamp
+	152, // 45: api.webhook.v1.PublishEventRequest.ttl:type_name -> google.protobuf.Duration
+	150, // 46: api.webhook.v1.PublishEventRequest.publish_at:type_name -> google.protobuf.Timestamp
+	5,   // 47: api.webhook.v1.PublishEventRequest.priority:type_name -> api.webhook.v1.EventPriority
+	151, // 48: api.webhook.v1.BatchEvent.payload:type_name -> google.protobuf.Struct
+	150, // 49: api.webhook.v1.BatchEvent.deliver_by:type_name -> google.protobuf.Timestamp
+	152, // 50: api.webhook.v1.BatchEvent.ttl:type_name -> google.protobuf.Duration
+	5,   // 51: api.webhook.v1.BatchEvent.priority:type_name -> api.webhook.v1.EventPriority
+	52,  // 52: api.webhook.v1.PublishEventsRequest.events:type_name -> api.webhook.v1.BatchEvent
+	54,  // 53: api.webhook.v1.PublishEventsResponse.results:type_name -> api.webhook.v1.PublishEventResult
+	151, // 54: api.webhook.v1.EventSchema.schema:type_name -> google.protobuf.Struct
+	150, // 55: api.webhook.v1.EventSchema.created_at:type_name -> google.protobuf.Timestamp
+	151, // 56: api.webhook.v1.CreateEventSchemaRequest.schema:type_name -> google.protobuf.Struct
+	56,  // 57: api.webhook.v1.CreateEventSchemaResponse.schema:type_name -> api.webhook.v1.EventSchema
+	56,  // 58: api.webhook.v1.ListEventSchemasResponse.schemas:type_name -> api.webhook.v1.EventSchema
+	56,  // 59: api.webhook.v1.GetEventSchemaResponse.schema:type_name -> api.webhook.v1.EventSchema
+	6,   // 60: api.webhook.v1.DeliveryAttempt.status:type_name -> api.webhook.v1.DeliveryAttemptStatus
+	150, // 61: api.webhook.v1.DeliveryAttempt.enqueued_at:type_name -> google.protobuf.Timestamp
+	150, // 62: api.webhook.v1.DeliveryAttempt.dequeued_at:type_name -> google.protobuf.Timestamp
+	150, // 63: api.webhook.v1.DeliveryAttempt.sent_at:type_name -> google.protobuf.Timestamp
+	150, // 64: api.webhook.v1.DeliveryAttempt.delivered_at:type_name -> google.protobuf.Timestamp
+	150, // 65: api.webhook.v1.DeliveryAttempt.failed_at:type_name -> google.protobuf.Timestamp
+	150, // 66: api.webhook.v1.DeliveryAttempt.dlq_at:type_name -> google.protobuf.Timestamp
+	150, // 67: api.webhook.v1.DeliveryAttempt.acked_at:type_name -> google.protobuf.Timestamp
+	64,  // 68: api.webhook.v1.DeliveryAttempt.history:type_name -> api.webhook.v1.AttemptRecord
+	150, // 69: api.webhook.v1.AttemptRecord.sent_at:type_name -> google.protobuf.Timestamp
+	150, // 70: api.webhook.v1.AttemptRecord.finished_at:type_name -> google.protobuf.Timestamp
+	150, // 71: api.webhook.v1.GetDeliveryStatusRequest.from:type_name -> google.protobuf.Timestamp
+	150, // 72: api.webhook.v1.GetDeliveryStatusRequest.to:type_name -> google.protobuf.Timestamp
+	63,  // 73: api.webhook.v1.GetDeliveryStatusResponse.attempts:type_name -> api.webhook.v1.DeliveryAttempt
+	69,  // 74: api.webhook.v1.GetDeliveryStatusResponse.replay_chains:type_name -> api.webhook.v1.ReplayChain
+	63,  // 75: api.webhook.v1.WatchDeliveryStatusResponse.delivery:type_name -> api.webhook.v1.DeliveryAttempt
+	6,   // 76: api.webhook.v1.WatchDeliveryStatusResponse.previous_status:type_name -> api.webhook.v1.DeliveryAttemptStatus
+	63,  // 77: api.webhook.v1.ReplayChain.attempts:type_name -> api.webhook.v1.DeliveryAttempt
+	151, // 78: api.webhook.v1.ReplayDeliveryRequest.payload_patch:type_name -> google.protobuf.Struct
+	63,  // 79: api.webhook.v1.ReplayDeliveryResponse.new_attempt:type_name -> api.webhook.v1.DeliveryAttempt
+	150, // 80: api.webhook.v1.AcknowledgeDeliveryResponse.acked_at:type_name -> google.protobuf.Timestamp
+	150, // 81: api.webhook.v1.ListDLQRequest.from:type_name -> google.protobuf.Timestamp
+	150, // 82: api.webhook.v1.ListDLQRequest.to:type_name -> google.protobuf.Timestamp
+	63,  // 83: api.webhook.v1.ListDLQResponse.dead:type_name -> api.webhook.v1.DeliveryAttempt
+	150, // 84: api.webhook.v1.ReplayDLQRequest.from:type_name -> google.protobuf.Timestamp
+	150, // 85: api.webhook.v1.ReplayDLQRequest.to:type_name -> google.protobuf.Timestamp
+	63,  // 86: api.webhook.v1.ReplayDLQResponse.replayed:type_name -> api.webhook.v1.DeliveryAttempt
+	63,  // 87: api.webhook.v1.DLQEntry.attempt:type_name -> api.webhook.v1.DeliveryAttempt
+	78,  // 88: api.webhook.v1.GetDLQEntryResponse.entry:type_name -> api.webhook.v1.DLQEntry
+	78,  // 89: api.webhook.v1.GetDLQEntryResponse.history:type_name -> api.webhook.v1.DLQEntry
+	150, // 90: api.webhook.v1.PurgeDLQRequest.from:type_name -> google.protobuf.Timestamp
+	150, // 91: api.webhook.v1.PurgeDLQRequest.to:type_name -> google.protobuf.Timestamp
+	150, // 92: api.webhook.v1.DLQRetention.updated_at:type_name -> google.protobuf.Timestamp
+	83,  // 93: api.webhook.v1.SetDLQRetentionResponse.retention:type_name -> api.webhook.v1.DLQRetention
+	83,  // 94: api.webhook.v1.GetDLQRetentionResponse.retention:type_name -> api.webhook.v1.DLQRetention
+	150, // 95: api.webhook.v1.ComplianceSettings.updated_at:type_name -> google.protobuf.Timestamp
+	88,  // 96: api.webhook.v1.SetComplianceModeResponse.settings:type_name -> api.webhook.v1.ComplianceSettings
+	150, // 97: api.webhook.v1.DeliverySettings.updated_at:type_name -> google.protobuf.Timestamp
+	91,  // 98: api.webhook.v1.SetDeliverySettingsResponse.settings:type_name -> api.webhook.v1.DeliverySettings
+	148, // 99: api.webhook.v1.DeliveryRecording.headers:type_name -> api.webhook.v1.DeliveryRecording.HeadersEntry
+	150, // 100: api.webhook.v1.DeliveryRecording.recorded_at:type_name -> google.protobuf.Timestamp
+	150, // 101: api.webhook.v1.DeliveryRecording.expires_at:type_name -> google.protobuf.Timestamp
+	149, // 102: api.webhook.v1.CapturedDelivery.headers:type_name -> api.webhook.v1.CapturedDelivery.HeadersEntry
+	150, // 103: api.webhook.v1.CapturedDelivery.captured_at:type_name -> google.protobuf.Timestamp
+	95,  // 104: api.webhook.v1.GetCapturedDeliveriesResponse.captures:type_name -> api.webhook.v1.CapturedDelivery
+	94,  // 105: api.webhook.v1.ListDeliveryRecordingsResponse.recordings:type_name -> api.webhook.v1.DeliveryRecording
+	151, // 106: api.webhook.v1.AuditLogEntry.before:type_name -> google.protobuf.Struct
+	151, // 107: api.webhook.v1.AuditLogEntry.after:type_name -> google.protobuf.Struct
+	150, // 108: api.webhook.v1.AuditLogEntry.created_at:type_name -> google.protobuf.Timestamp
+	150, // 109: api.webhook.v1.ListAuditLogRequest.from:type_name -> google.protobuf.Timestamp
+	150, // 110: api.webhook.v1.ListAuditLogRequest.to:type_name -> google.protobuf.Timestamp
+	100, // 111: api.webhook.v1.ListAuditLogResponse.entries:type_name -> api.webhook.v1.AuditLogEntry
+	150, // 112: api.webhook.v1.DeliveryFreeze.created_at:type_name -> google.protobuf.Timestamp
+	150, // 113: api.webhook.v1.DeliveryFreeze.released_at:type_name -> google.protobuf.Timestamp
+	103, // 114: api.webhook.v1.FreezeDeliveriesResponse.freeze:type_name -> api.webhook.v1.DeliveryFreeze
+	150, // 115: api.webhook.v1.DispatchState.paused_at:type_name -> google.protobuf.Timestamp
+	150, // 116: api.webhook.v1.DispatchState.resumed_at:type_name -> google.protobuf.Timestamp
+	110, // 117: api.webhook.v1.PauseDispatchResponse.state:type_name -> api.webhook.v1.DispatchState
+	110, // 118: api.webhook.v1.ResumeDispatchResponse.state:type_name -> api.webhook.v1.DispatchState
+	110, // 119: api.webhook.v1.GetDispatchStateResponse.state:type_name -> api.webhook.v1.DispatchState
+	150, // 120: api.webhook.v1.BacklogEstimate.clears_at:type_name -> google.protobuf.Timestamp
+	118, // 121: api.webhook.v1.GetBacklogEstimateResponse.total:type_name -> api.webhook.v1.BacklogEstimate
+	118, // 122: api.webhook.v1.GetBacklogEstimateResponse.endpoints:type_name -> api.webhook.v1.BacklogEstimate
+	150, // 123: api.webhook.v1.TenantQuota.updated_at:type_name -> google.protobuf.Timestamp
+	120, // 124: api.webhook.v1.SetTenantQuotaRequest.quota:type_name -> api.webhook.v1.TenantQuota
+	120, // 125: api.webhook.v1.SetTenantQuotaResponse.quota:type_name -> api.webhook.v1.TenantQuota
+	120, // 126: api.webhook.v1.GetTenantQuotaResponse.quota:type_name -> api.webhook.v1.TenantQuota
+	150, // 127: api.webhook.v1.FailureBucket.start:type_name -> google.protobuf.Timestamp
+	126, // 128: api.webhook.v1.FailureBucket.failures:type_name -> api.webhook.v1.FailureCount
+	127, // 129: api.webhook.v1.GetFailureTrendsResponse.buckets:type_name -> api.webhook.v1.FailureBucket
+	126, // 130: api.webhook.v1.GetFailureTrendsResponse.totals:type_name -> api.webhook.v1.FailureCount
+	126, // 131: api.webhook.v1.DeliveryStats.top_failures:type_name -> api.webhook.v1.FailureCount
+	130, // 132: api.webhook.v1.GetDeliveryStatsResponse.totals:type_name -> api.webhook.v1.DeliveryStats
+	130, // 133: api.webhook.v1.GetDeliveryStatsResponse.endpoints:type_name -> api.webhook.v1.DeliveryStats
+	151, // 134: api.webhook.v1.SystemEvent.details:type_name -> google.protobuf.Struct
+	150, // 135: api.webhook.v1.SystemEvent.created_at:type_name -> google.protobuf.Timestamp
+	150, // 136: api.webhook.v1.ListSystemEventsRequest.since:type_name -> google.protobuf.Timestamp
+	132, // 137: api.webhook.v1.ListSystemEventsResponse.events:type_name -> api.webhook.v1.SystemEvent
+	150, // 138: api.webhook.v1.NotificationSettings.updated_at:type_name -> google.protobuf.Timestamp
+	135, // 139: api.webhook.v1.SetNotificationSettingsResponse.settings:type_name -> api.webhook.v1.NotificationSettings
+	135, // 140: api.webhook.v1.GetNotificationSettingsResponse.settings:type_name -> api.webhook.v1.NotificationSettings
+	141, // 141: api.webhook.v1.ListTenantsResponse.tenants:type_name -> api.webhook.v1.TenantSummary
+	9,   // 142: api.webhook.v1.ListEndpointsResponse.endpoints:type_name -> api.webhook.v1.Endpoint
+	6,   // 143: api.webhook.v1.ListRecentDeliveriesRequest.status:type_name -> api.webhook.v1.DeliveryAttemptStatus
+	63,  // 144: api.webhook.v1.RecentDelivery.delivery:type_name -> api.webhook.v1.DeliveryAttempt
+	146, // 145: api.webhook.v1.ListRecentDeliveriesResponse.deliveries:type_name -> api.webhook.v1.RecentDelivery
+	7,   // 146: api.webhook.v1.WebhookService.Ping:input_type -> api.webhook.v1.PingRequest
+	17,  // 147: api.webhook.v1.WebhookService.CreateEndpoint:input_type -> api.webhook.v1.CreateEndpointRequest
+	46,  // 148: api.webhook.v1.WebhookService.VerifyEndpoint:input_type -> api.webhook.v1.VerifyEndpointRequest
+	96,  // 149: api.webhook.v1.WebhookService.GetCapturedDeliveries:input_type -> api.webhook.v1.GetCapturedDeliveriesRequest
+	18,  // 150: api.webhook.v1.WebhookService.SetEndpointRecoveryRamp:input_type -> api.webhook.v1.SetEndpointRecoveryRampRequest
+	20,  // 151: api.webhook.v1.WebhookService.SetEndpointRetryPolicy:input_type -> api.webhook.v1.SetEndpointRetryPolicyRequest
+	22,  // 152: api.webhook.v1.WebhookService.SetEndpointClientCertificate:input_type -> api.webhook.v1.SetEndpointClientCertificateRequest
+	24,  // 153: api.webhook.v1.WebhookService.SetEndpointCompression:input_type -> api.webhook.v1.SetEndpointCompressionRequest
+	26,  // 154: api.webhook.v1.WebhookService.SetEndpointTimeout:input_type -> api.webhook.v1.SetEndpointTimeoutRequest
+	28,  // 155: api.webhook.v1.WebhookService.SetEndpointProxy:input_type -> api.webhook.v1.SetEndpointProxyRequest
+	30,  // 156: api.webhook.v1.WebhookService.SetEndpointSignatureScheme:input_type -> api.webhook.v1.SetEndpointSignatureSchemeRequest
+	32,  // 157: api.webhook.v1.WebhookService.GetSigningKeys:input_type -> api.webhook.v1.GetSigningKeysRequest
+	35,  // 158: api.webhook.v1.WebhookService.GetEgressIPs:input_type -> api.webhook.v1.GetEgressIPsRequest
+	41,  // 159: api.webhook.v1.WebhookService.SetEndpointOrdering:input_type -> api.webhook.v1.SetEndpointOrderingRequest
+	37,  // 160: api.webhook.v1.WebhookService.SetEndpointHealthCheck:input_type -> api.webhook.v1.SetEndpointHealthCheckRequest
+	39,  // 161: api.webhook.v1.WebhookService.EnableEndpoint:input_type -> api.webhook.v1.EnableEndpointRequest
+	43,  // 162: api.webhook.v1.WebhookService.DeleteEndpoint:input_type -> api.webhook.v1.DeleteEndpointRequest
+	48,  // 163: api.webhook.v1.WebhookService.CreateSubscription:input_type -> api.webhook.v1.CreateSubscriptionRequest
+	50,  // 164: api.webhook.v1.WebhookService.PublishEvent:input_type -> api.webhook.v1.PublishEventRequest
+	53,  // 165: api.webhook.v1.WebhookService.PublishEvents:input_type -> api.webhook.v1.PublishEventsRequest
+	57,  // 166: api.webhook.v1.WebhookService.CreateEventSchema:input_type -> api.webhook.v1.CreateEventSchemaRequest
+	59,  // 167: api.webhook.v1.WebhookService.ListEventSchemas:input_type -> api.webhook.v1.ListEventSchemasRequest
+	61,  // 168: api.webhook.v1.WebhookService.GetEventSchema:input_type -> api.webhook.v1.GetEventSchemaRequest
+	65,  // 169: api.webhook.v1.WebhookService.GetDeliveryStatus:input_type -> api.webhook.v1.GetDeliveryStatusRequest
+	67,  // 170: api.webhook.v1.WebhookService.WatchDeliveryStatus:input_type -> api.webhook.v1.WatchDeliveryStatusRequest
+	70,  // 171: api.webhook.v1.WebhookService.ReplayDelivery:input_type -> api.webhook.v1.ReplayDeliveryRequest
+	72,  // 172: api.webhook.v1.WebhookService.AcknowledgeDelivery:input_type -> api.webhook.v1.AcknowledgeDeliveryRequest
+	74,  // 173: api.webhook.v1.WebhookService.ListDLQ:input_type -> api.webhook.v1.ListDLQRequest
+	76,  // 174: api.webhook.v1.WebhookService.ReplayDLQ:input_type -> api.webhook.v1.ReplayDLQRequest
+	79,  // 175: api.webhook.v1.WebhookService.GetDLQEntry:input_type -> api.webhook.v1.GetDLQEntryRequest
+	81,  // 176: api.webhook.v1.WebhookService.PurgeDLQ:input_type -> api.webhook.v1.PurgeDLQRequest
+	84,  // 177: api.webhook.v1.WebhookService.SetDLQRetention:input_type -> api.webhook.v1.SetDLQRetentionRequest
+	86,  // 178: api.webhook.v1.WebhookService.GetDLQRetention:input_type -> api.webhook.v1.GetDLQRetentionRequest
+	89,  // 179: api.webhook.v1.WebhookService.SetComplianceMode:input_type -> api.webhook.v1.SetComplianceModeRequest
+	92,  // 180: api.webhook.v1.WebhookService.SetDeliverySettings:input_type -> api.webhook.v1.SetDeliverySettingsRequest
+	98,  // 181: api.webhook.v1.WebhookService.ListDeliveryRecordings:input_type -> api.webhook.v1.ListDeliveryRecordingsRequest
+	101, // 182: api.webhook.v1.WebhookService.ListAuditLog:input_type -> api.webhook.v1.ListAuditLogRequest
+	104, // 183: api.webhook.v1.WebhookService.FreezeDeliveries:input_type -> api.webhook.v1.FreezeDeliveriesRequest
+	106, // 184: api.webhook.v1.WebhookService.DrainQueue:input_type -> api.webhook.v1.DrainQueueRequest
+	108, // 185: api.webhook.v1.WebhookService.ResumeDeliveries:input_type -> api.webhook.v1.ResumeDeliveriesRequest
+	111, // 186: api.webhook.v1.WebhookService.PauseDispatch:input_type -> api.webhook.v1.PauseDispatchRequest
+	113, // 187: api.webhook.v1.WebhookService.ResumeDispatch:input_type -> api.webhook.v1.ResumeDispatchRequest
+	115, // 188: api.webhook.v1.WebhookService.GetDispatchState:input_type -> api.webhook.v1.GetDispatchStateRequest
+	117, // 189: api.webhook.v1.WebhookService.GetBacklogEstimate:input_type -> api.webhook.v1.GetBacklogEstimateRequest
+	121, // 190: api.webhook.v1.WebhookService.SetTenantQuota:input_type -> api.webhook.v1.SetTenantQuotaRequest
+	123, // 191: api.webhook.v1.WebhookService.GetTenantQuota:input_type -> api.webhook.v1.GetTenantQuotaRequest
+	125, // 192: api.webhook.v1.WebhookService.GetFailureTrends:input_type -> api.webhook.v1.GetFailureTrendsRequest
+	129, // 193: api.webhook.v1.WebhookService.GetDeliveryStats:input_type -> api.webhook.v1.GetDeliveryStatsRequest
+	133, // 194: api.webhook.v1.WebhookService.ListSystemEvents:input_type -> api.webhook.v1.ListSystemEventsRequest
+	136, // 195: api.webhook.v1.WebhookService.SetNotificationSettings:input_type -> api.webhook.v1.SetNotificationSettingsRequest
+	138, // 196: api.webhook.v1.WebhookService.GetNotificationSettings:input_type -> api.webhook.v1.GetNotificationSettingsRequest
+	140, // 197: api.webhook.v1.WebhookService.ListTenants:input_type -> api.webhook.v1.ListTenantsRequest
+	143, // 198: api.webhook.v1.WebhookService.ListEndpoints:input_type -> api.webhook.v1.ListEndpointsRequest
+	145, // 199: api.webhook.v1.WebhookService.ListRecentDeliveries:input_type -> api.webhook.v1.ListRecentDeliveriesRequest
+	8,   // 200: api.webhook.v1.WebhookService.Ping:output_type -> api.webhook.v1.PingResponse
+	45,  // 201: api.webhook.v1.WebhookService.CreateEndpoint:output_type -> api.webhook.v1.CreateEndpointResponse
+	47,  // 202: api.webhook.v1.WebhookService.VerifyEndpoint:output_type -> api.webhook.v1.VerifyEndpointResponse
+	97,  // 203: api.webhook.v1.WebhookService.GetCapturedDeliveries:output_type -> api.webhook.v1.GetCapturedDeliveriesResponse
+	19,  // 204: api.webhook.v1.WebhookService.SetEndpointRecoveryRamp:output_type -> api.webhook.v1.SetEndpointRecoveryRampResponse
+	21,  // 205: api.webhook.v1.WebhookService.SetEndpointRetryPolicy:output_type -> api.webhook.v1.SetEndpointRetryPolicyResponse
+	23,  // 206: api.webhook.v1.WebhookService.SetEndpointClientCertificate:output_type -> api.webhook.v1.SetEndpointClientCertificateResponse
+	25,  // 207: api.webhook.v1.WebhookService.SetEndpointCompression:output_type -> api.webhook.v1.SetEndpointCompressionResponse
+	27,  // 208: api.webhook.v1.WebhookService.SetEndpointTimeout:output_type -> api.webhook.v1.SetEndpointTimeoutResponse
+	29,  // 209: api.webhook.v1.WebhookService.SetEndpointProxy:output_type -> api.webhook.v1.SetEndpointProxyResponse
+	31,  // 210: api.webhook.v1.WebhookService.SetEndpointSignatureScheme:output_type -> api.webhook.v1.SetEndpointSignatureSchemeResponse
+	34,  // 211: api.webhook.v1.WebhookService.GetSigningKeys:output_type -> api.webhook.v1.GetSigningKeysResponse
+	36,  // 212: api.webhook.v1.WebhookService.GetEgressIPs:output_type -> api.webhook.v1.GetEgressIPsResponse
+	42,  // 213: api.webhook.v1.WebhookService.SetEndpointOrdering:output_type -> api.webhook.v1.SetEndpointOrderingResponse
+	38,  // 214: api.webhook.v1.WebhookService.SetEndpointHealthCheck:output_type -> api.webhook.v1.SetEndpointHealthCheckResponse
+	40,  // 215: api.webhook.v1.WebhookService.EnableEndpoint:output_type -> api.webhook.v1.EnableEndpointResponse
+	44,  // 216: api.webhook.v1.WebhookService.DeleteEndpoint:output_type -> api.webhook.v1.DeleteEndpointResponse
+	49,  // 217: api.webhook.v1.WebhookService.CreateSubscription:output_type -> api.webhook.v1.CreateSubscriptionResponse
+	51,  // 218: api.webhook.v1.WebhookService.PublishEvent:output_type -> api.webhook.v1.PublishEventResponse
+	55,  // 219: api.webhook.v1.WebhookService.PublishEvents:output_type -> api.webhook.v1.PublishEventsResponse
+	58,  // 220: api.webhook.v1.WebhookService.CreateEventSchema:output_type -> api.webhook.v1.CreateEventSchemaResponse
+	60,  // 221: api.webhook.v1.WebhookService.ListEventSchemas:output_type -> api.webhook.v1.ListEventSchemasResponse
+	62,  // 222: api.webhook.v1.WebhookService.GetEventSchema:output_type -> api.webhook.v1.GetEventSchemaResponse
+	66,  // 223: api.webhook.v1.WebhookService.GetDeliveryStatus:output_type -> api.webhook.v1.GetDeliveryStatusResponse
+	68,  // 224: api.webhook.v1.WebhookService.WatchDeliveryStatus:output_type -> api.webhook.v1.WatchDeliveryStatusResponse
+	71,  // 225: api.webhook.v1.WebhookService.ReplayDelivery:output_type -> api.webhook.v1.ReplayDeliveryResponse
+	73,  // 226: api.webhook.v1.WebhookService.AcknowledgeDelivery:output_type -> api.webhook.v1.AcknowledgeDeliveryResponse
+	75,  // 227: api.webhook.v1.WebhookService.ListDLQ:output_type -> api.webhook.v1.ListDLQResponse
+	77,  // 228: api.webhook.v1.WebhookService.ReplayDLQ:output_type -> api.webhook.v1.ReplayDLQResponse
+	80,  // 229: api.webhook.v1.WebhookService.GetDLQEntry:output_type -> api.webhook.v1.GetDLQEntryResponse
+	82,  // 230: api.webhook.v1.WebhookService.PurgeDLQ:output_type -> api.webhook.v1.PurgeDLQResponse
+	85,  // 231: api.webhook.v1.WebhookService.SetDLQRetention:output_type -> api.webhook.v1.SetDLQRetentionResponse
+	87,  // 232: api.webhook.v1.WebhookService.GetDLQRetention:output_type -> api.webhook.v1.GetDLQRetentionResponse
+	90,  // 233: api.webhook.v1.WebhookService.SetComplianceMode:output_type -> api.webhook.v1.SetComplianceModeResponse
+	93,  // 234: api.webhook.v1.WebhookService.SetDeliverySettings:output_type -> api.webhook.v1.SetDeliverySettingsResponse
+	99,  // 235: api.webhook.v1.WebhookService.ListDeliveryRecordings:output_type -> api.webhook.v1.ListDeliveryRecordingsResponse
+	102, // 236: api.webhook.v1.WebhookService.ListAuditLog:output_type -> api.webhook.v1.ListAuditLogResponse
+	105, // 237: api.webhook.v1.WebhookService.FreezeDeliveries:output_type -> api.webhook.v1.FreezeDeliveriesResponse
+	107, // 238: api.webhook.v1.WebhookService.DrainQueue:output_type -> api.webhook.v1.DrainQueueResponse
+	109, // 239: api.webhook.v1.WebhookService.ResumeDeliveries:output_type -> api.webhook.v1.ResumeDeliveriesResponse
+	112, // 240: api.webhook.v1.WebhookService.PauseDispatch:output_type -> api.webhook.v1.PauseDispatchResponse
+	114, // 241: api.webhook.v1.WebhookService.ResumeDispatch:output_type -> api.webhook.v1.ResumeDispatchResponse
+	116, // 242: api.webhook.v1.WebhookService.GetDispatchState:output_type -> api.webhook.v1.GetDispatchStateResponse
+	119, // 243: api.webhook.v1.WebhookService.GetBacklogEstimate:output_type -> api.webhook.v1.GetBacklogEstimateResponse
+	122, // 244: api.webhook.v1.WebhookService.SetTenantQuota:output_type -> api.webhook.v1.SetTenantQuotaResponse
+	124, // 245: api.webhook.v1.WebhookService.GetTenantQuota:output_type -> api.webhook.v1.GetTenantQuotaResponse
+	128, // 246: api.webhook.v1.WebhookService.GetFailureTrends:output_type -> api.webhook.v1.GetFailureTrendsResponse
+	131, // 247: api.webhook.v1.WebhookService.GetDeliveryStats:output_type -> api.webhook.v1.GetDeliveryStatsResponse
+	134, // 248: api.webhook.v1.WebhookService.ListSystemEvents:output_type -> api.webhook.v1.ListSystemEventsResponse
+	137, // 249: api.webhook.v1.WebhookService.SetNotificationSettings:output_type -> api.webhook.v1.SetNotificationSettingsResponse
+	139, // 250: api.webhook.v1.WebhookService.GetNotificationSettings:output_type -> api.webhook.v1.GetNotificationSettingsResponse
+	142, // 251: api.webhook.v1.WebhookService.ListTenants:output_type -> api.webhook.v1.ListTenantsResponse
+	144, // 252: api.webhook.v1.WebhookService.ListEndpoints:output_type -> api.webhook.v1.ListEndpointsResponse
+	147, // 253: api.webhook.v1.WebhookService.ListRecentDeliveries:output_type -> api.webhook.v1.ListRecentDeliveriesResponse
+	200, // [200:254] is the sub-list for method output_type
+	146, // [146:200] is the sub-list for method input_type
+	146, // [146:146] is the sub-list for extension type_name
+	146, // [146:146] is the sub-list for extension extendee
+	0,   // [0:146] is the sub-list for field type_name
 }
 
 func init() { file_api_webhook_v1_service_proto_init() }
@@ -1357,8 +11195,8 @@ func file_api_webhook_v1_service_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_api_webhook_v1_service_proto_rawDesc), len(file_api_webhook_v1_service_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   17,
+			NumEnums:      7,
+			NumMessages:   143,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
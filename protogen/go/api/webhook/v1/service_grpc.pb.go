@@ -19,13 +19,60 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	WebhookService_Ping_FullMethodName               = "/api.webhook.v1.WebhookService/Ping"
-	WebhookService_CreateEndpoint_FullMethodName     = "/api.webhook.v1.WebhookService/CreateEndpoint"
-	WebhookService_CreateSubscription_FullMethodName = "/api.webhook.v1.WebhookService/CreateSubscription"
-	WebhookService_PublishEvent_FullMethodName       = "/api.webhook.v1.WebhookService/PublishEvent"
-	WebhookService_GetDeliveryStatus_FullMethodName  = "/api.webhook.v1.WebhookService/GetDeliveryStatus"
-	WebhookService_ReplayDelivery_FullMethodName     = "/api.webhook.v1.WebhookService/ReplayDelivery"
-	WebhookService_ListDLQ_FullMethodName            = "/api.webhook.v1.WebhookService/ListDLQ"
+	WebhookService_Ping_FullMethodName                         = "/api.webhook.v1.WebhookService/Ping"
+	WebhookService_CreateEndpoint_FullMethodName               = "/api.webhook.v1.WebhookService/CreateEndpoint"
+	WebhookService_VerifyEndpoint_FullMethodName               = "/api.webhook.v1.WebhookService/VerifyEndpoint"
+	WebhookService_GetCapturedDeliveries_FullMethodName        = "/api.webhook.v1.WebhookService/GetCapturedDeliveries"
+	WebhookService_SetEndpointRecoveryRamp_FullMethodName      = "/api.webhook.v1.WebhookService/SetEndpointRecoveryRamp"
+	WebhookService_SetEndpointRetryPolicy_FullMethodName       = "/api.webhook.v1.WebhookService/SetEndpointRetryPolicy"
+	WebhookService_SetEndpointClientCertificate_FullMethodName = "/api.webhook.v1.WebhookService/SetEndpointClientCertificate"
+	WebhookService_SetEndpointCompression_FullMethodName       = "/api.webhook.v1.WebhookService/SetEndpointCompression"
+	WebhookService_SetEndpointTimeout_FullMethodName           = "/api.webhook.v1.WebhookService/SetEndpointTimeout"
+	WebhookService_SetEndpointProxy_FullMethodName             = "/api.webhook.v1.WebhookService/SetEndpointProxy"
+	WebhookService_SetEndpointSignatureScheme_FullMethodName   = "/api.webhook.v1.WebhookService/SetEndpointSignatureScheme"
+	WebhookService_GetSigningKeys_FullMethodName               = "/api.webhook.v1.WebhookService/GetSigningKeys"
+	WebhookService_GetEgressIPs_FullMethodName                 = "/api.webhook.v1.WebhookService/GetEgressIPs"
+	WebhookService_SetEndpointOrdering_FullMethodName          = "/api.webhook.v1.WebhookService/SetEndpointOrdering"
+	WebhookService_SetEndpointHealthCheck_FullMethodName       = "/api.webhook.v1.WebhookService/SetEndpointHealthCheck"
+	WebhookService_EnableEndpoint_FullMethodName               = "/api.webhook.v1.WebhookService/EnableEndpoint"
+	WebhookService_DeleteEndpoint_FullMethodName               = "/api.webhook.v1.WebhookService/DeleteEndpoint"
+	WebhookService_CreateSubscription_FullMethodName           = "/api.webhook.v1.WebhookService/CreateSubscription"
+	WebhookService_PublishEvent_FullMethodName                 = "/api.webhook.v1.WebhookService/PublishEvent"
+	WebhookService_PublishEvents_FullMethodName                = "/api.webhook.v1.WebhookService/PublishEvents"
+	WebhookService_CreateEventSchema_FullMethodName            = "/api.webhook.v1.WebhookService/CreateEventSchema"
+	WebhookService_ListEventSchemas_FullMethodName             = "/api.webhook.v1.WebhookService/ListEventSchemas"
+	WebhookService_GetEventSchema_FullMethodName               = "/api.webhook.v1.WebhookService/GetEventSchema"
+	WebhookService_GetDeliveryStatus_FullMethodName            = "/api.webhook.v1.WebhookService/GetDeliveryStatus"
+	WebhookService_WatchDeliveryStatus_FullMethodName          = "/api.webhook.v1.WebhookService/WatchDeliveryStatus"
+	WebhookService_ReplayDelivery_FullMethodName               = "/api.webhook.v1.WebhookService/ReplayDelivery"
+	WebhookService_AcknowledgeDelivery_FullMethodName          = "/api.webhook.v1.WebhookService/AcknowledgeDelivery"
+	WebhookService_ListDLQ_FullMethodName                      = "/api.webhook.v1.WebhookService/ListDLQ"
+	WebhookService_ReplayDLQ_FullMethodName                    = "/api.webhook.v1.WebhookService/ReplayDLQ"
+	WebhookService_GetDLQEntry_FullMethodName                  = "/api.webhook.v1.WebhookService/GetDLQEntry"
+	WebhookService_PurgeDLQ_FullMethodName                     = "/api.webhook.v1.WebhookService/PurgeDLQ"
+	WebhookService_SetDLQRetention_FullMethodName              = "/api.webhook.v1.WebhookService/SetDLQRetention"
+	WebhookService_GetDLQRetention_FullMethodName              = "/api.webhook.v1.WebhookService/GetDLQRetention"
+	WebhookService_SetComplianceMode_FullMethodName            = "/api.webhook.v1.WebhookService/SetComplianceMode"
+	WebhookService_SetDeliverySettings_FullMethodName          = "/api.webhook.v1.WebhookService/SetDeliverySettings"
+	WebhookService_ListDeliveryRecordings_FullMethodName       = "/api.webhook.v1.WebhookService/ListDeliveryRecordings"
+	WebhookService_ListAuditLog_FullMethodName                 = "/api.webhook.v1.WebhookService/ListAuditLog"
+	WebhookService_FreezeDeliveries_FullMethodName             = "/api.webhook.v1.WebhookService/FreezeDeliveries"
+	WebhookService_DrainQueue_FullMethodName                   = "/api.webhook.v1.WebhookService/DrainQueue"
+	WebhookService_ResumeDeliveries_FullMethodName             = "/api.webhook.v1.WebhookService/ResumeDeliveries"
+	WebhookService_PauseDispatch_FullMethodName                = "/api.webhook.v1.WebhookService/PauseDispatch"
+	WebhookService_ResumeDispatch_FullMethodName               = "/api.webhook.v1.WebhookService/ResumeDispatch"
+	WebhookService_GetDispatchState_FullMethodName             = "/api.webhook.v1.WebhookService/GetDispatchState"
+	WebhookService_GetBacklogEstimate_FullMethodName           = "/api.webhook.v1.WebhookService/GetBacklogEstimate"
+	WebhookService_SetTenantQuota_FullMethodName               = "/api.webhook.v1.WebhookService/SetTenantQuota"
+	WebhookService_GetTenantQuota_FullMethodName               = "/api.webhook.v1.WebhookService/GetTenantQuota"
+	WebhookService_GetFailureTrends_FullMethodName             = "/api.webhook.v1.WebhookService/GetFailureTrends"
+	WebhookService_GetDeliveryStats_FullMethodName             = "/api.webhook.v1.WebhookService/GetDeliveryStats"
+	WebhookService_ListSystemEvents_FullMethodName             = "/api.webhook.v1.WebhookService/ListSystemEvents"
+	WebhookService_SetNotificationSettings_FullMethodName      = "/api.webhook.v1.WebhookService/SetNotificationSettings"
+	WebhookService_GetNotificationSettings_FullMethodName      = "/api.webhook.v1.WebhookService/GetNotificationSettings"
+	WebhookService_ListTenants_FullMethodName                  = "/api.webhook.v1.WebhookService/ListTenants"
+	WebhookService_ListEndpoints_FullMethodName                = "/api.webhook.v1.WebhookService/ListEndpoints"
+	WebhookService_ListRecentDeliveries_FullMethodName         = "/api.webhook.v1.WebhookService/ListRecentDeliveries"
 )
 
 // WebhookServiceClient is the client API for WebhookService service.
@@ -37,11 +84,58 @@ type WebhookServiceClient interface {
 	// Placeholder to verify gateway wiring in later phases.
 	Ping(ctx context.Context, in *PingRequest, opts ...grpc.CallOption) (*PingResponse, error)
 	CreateEndpoint(ctx context.Context, in *CreateEndpointRequest, opts ...grpc.CallOption) (*CreateEndpointResponse, error)
+	VerifyEndpoint(ctx context.Context, in *VerifyEndpointRequest, opts ...grpc.CallOption) (*VerifyEndpointResponse, error)
+	GetCapturedDeliveries(ctx context.Context, in *GetCapturedDeliveriesRequest, opts ...grpc.CallOption) (*GetCapturedDeliveriesResponse, error)
+	SetEndpointRecoveryRamp(ctx context.Context, in *SetEndpointRecoveryRampRequest, opts ...grpc.CallOption) (*SetEndpointRecoveryRampResponse, error)
+	SetEndpointRetryPolicy(ctx context.Context, in *SetEndpointRetryPolicyRequest, opts ...grpc.CallOption) (*SetEndpointRetryPolicyResponse, error)
+	SetEndpointClientCertificate(ctx context.Context, in *SetEndpointClientCertificateRequest, opts ...grpc.CallOption) (*SetEndpointClientCertificateResponse, error)
+	SetEndpointCompression(ctx context.Context, in *SetEndpointCompressionRequest, opts ...grpc.CallOption) (*SetEndpointCompressionResponse, error)
+	SetEndpointTimeout(ctx context.Context, in *SetEndpointTimeoutRequest, opts ...grpc.CallOption) (*SetEndpointTimeoutResponse, error)
+	SetEndpointProxy(ctx context.Context, in *SetEndpointProxyRequest, opts ...grpc.CallOption) (*SetEndpointProxyResponse, error)
+	SetEndpointSignatureScheme(ctx context.Context, in *SetEndpointSignatureSchemeRequest, opts ...grpc.CallOption) (*SetEndpointSignatureSchemeResponse, error)
+	GetSigningKeys(ctx context.Context, in *GetSigningKeysRequest, opts ...grpc.CallOption) (*GetSigningKeysResponse, error)
+	GetEgressIPs(ctx context.Context, in *GetEgressIPsRequest, opts ...grpc.CallOption) (*GetEgressIPsResponse, error)
+	SetEndpointOrdering(ctx context.Context, in *SetEndpointOrderingRequest, opts ...grpc.CallOption) (*SetEndpointOrderingResponse, error)
+	SetEndpointHealthCheck(ctx context.Context, in *SetEndpointHealthCheckRequest, opts ...grpc.CallOption) (*SetEndpointHealthCheckResponse, error)
+	EnableEndpoint(ctx context.Context, in *EnableEndpointRequest, opts ...grpc.CallOption) (*EnableEndpointResponse, error)
+	DeleteEndpoint(ctx context.Context, in *DeleteEndpointRequest, opts ...grpc.CallOption) (*DeleteEndpointResponse, error)
 	CreateSubscription(ctx context.Context, in *CreateSubscriptionRequest, opts ...grpc.CallOption) (*CreateSubscriptionResponse, error)
 	PublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error)
+	PublishEvents(ctx context.Context, in *PublishEventsRequest, opts ...grpc.CallOption) (*PublishEventsResponse, error)
+	CreateEventSchema(ctx context.Context, in *CreateEventSchemaRequest, opts ...grpc.CallOption) (*CreateEventSchemaResponse, error)
+	ListEventSchemas(ctx context.Context, in *ListEventSchemasRequest, opts ...grpc.CallOption) (*ListEventSchemasResponse, error)
+	GetEventSchema(ctx context.Context, in *GetEventSchemaRequest, opts ...grpc.CallOption) (*GetEventSchemaResponse, error)
 	GetDeliveryStatus(ctx context.Context, in *GetDeliveryStatusRequest, opts ...grpc.CallOption) (*GetDeliveryStatusResponse, error)
+	WatchDeliveryStatus(ctx context.Context, in *WatchDeliveryStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchDeliveryStatusResponse], error)
 	ReplayDelivery(ctx context.Context, in *ReplayDeliveryRequest, opts ...grpc.CallOption) (*ReplayDeliveryResponse, error)
+	AcknowledgeDelivery(ctx context.Context, in *AcknowledgeDeliveryRequest, opts ...grpc.CallOption) (*AcknowledgeDeliveryResponse, error)
 	ListDLQ(ctx context.Context, in *ListDLQRequest, opts ...grpc.CallOption) (*ListDLQResponse, error)
+	ReplayDLQ(ctx context.Context, in *ReplayDLQRequest, opts ...grpc.CallOption) (*ReplayDLQResponse, error)
+	GetDLQEntry(ctx context.Context, in *GetDLQEntryRequest, opts ...grpc.CallOption) (*GetDLQEntryResponse, error)
+	PurgeDLQ(ctx context.Context, in *PurgeDLQRequest, opts ...grpc.CallOption) (*PurgeDLQResponse, error)
+	SetDLQRetention(ctx context.Context, in *SetDLQRetentionRequest, opts ...grpc.CallOption) (*SetDLQRetentionResponse, error)
+	GetDLQRetention(ctx context.Context, in *GetDLQRetentionRequest, opts ...grpc.CallOption) (*GetDLQRetentionResponse, error)
+	SetComplianceMode(ctx context.Context, in *SetComplianceModeRequest, opts ...grpc.CallOption) (*SetComplianceModeResponse, error)
+	SetDeliverySettings(ctx context.Context, in *SetDeliverySettingsRequest, opts ...grpc.CallOption) (*SetDeliverySettingsResponse, error)
+	ListDeliveryRecordings(ctx context.Context, in *ListDeliveryRecordingsRequest, opts ...grpc.CallOption) (*ListDeliveryRecordingsResponse, error)
+	ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error)
+	FreezeDeliveries(ctx context.Context, in *FreezeDeliveriesRequest, opts ...grpc.CallOption) (*FreezeDeliveriesResponse, error)
+	DrainQueue(ctx context.Context, in *DrainQueueRequest, opts ...grpc.CallOption) (*DrainQueueResponse, error)
+	ResumeDeliveries(ctx context.Context, in *ResumeDeliveriesRequest, opts ...grpc.CallOption) (*ResumeDeliveriesResponse, error)
+	PauseDispatch(ctx context.Context, in *PauseDispatchRequest, opts ...grpc.CallOption) (*PauseDispatchResponse, error)
+	ResumeDispatch(ctx context.Context, in *ResumeDispatchRequest, opts ...grpc.CallOption) (*ResumeDispatchResponse, error)
+	GetDispatchState(ctx context.Context, in *GetDispatchStateRequest, opts ...grpc.CallOption) (*GetDispatchStateResponse, error)
+	GetBacklogEstimate(ctx context.Context, in *GetBacklogEstimateRequest, opts ...grpc.CallOption) (*GetBacklogEstimateResponse, error)
+	SetTenantQuota(ctx context.Context, in *SetTenantQuotaRequest, opts ...grpc.CallOption) (*SetTenantQuotaResponse, error)
+	GetTenantQuota(ctx context.Context, in *GetTenantQuotaRequest, opts ...grpc.CallOption) (*GetTenantQuotaResponse, error)
+	GetFailureTrends(ctx context.Context, in *GetFailureTrendsRequest, opts ...grpc.CallOption) (*GetFailureTrendsResponse, error)
+	GetDeliveryStats(ctx context.Context, in *GetDeliveryStatsRequest, opts ...grpc.CallOption) (*GetDeliveryStatsResponse, error)
+	ListSystemEvents(ctx context.Context, in *ListSystemEventsRequest, opts ...grpc.CallOption) (*ListSystemEventsResponse, error)
+	SetNotificationSettings(ctx context.Context, in *SetNotificationSettingsRequest, opts ...grpc.CallOption) (*SetNotificationSettingsResponse, error)
+	GetNotificationSettings(ctx context.Context, in *GetNotificationSettingsRequest, opts ...grpc.CallOption) (*GetNotificationSettingsResponse, error)
+	ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error)
+	ListEndpoints(ctx context.Context, in *ListEndpointsRequest, opts ...grpc.CallOption) (*ListEndpointsResponse, error)
+	ListRecentDeliveries(ctx context.Context, in *ListRecentDeliveriesRequest, opts ...grpc.CallOption) (*ListRecentDeliveriesResponse, error)
 }
 
 type webhookServiceClient struct {
@@ -72,6 +166,156 @@ func (c *webhookServiceClient) CreateEndpoint(ctx context.Context, in *CreateEnd
 	return out, nil
 }
 
+func (c *webhookServiceClient) VerifyEndpoint(ctx context.Context, in *VerifyEndpointRequest, opts ...grpc.CallOption) (*VerifyEndpointResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VerifyEndpointResponse)
+	err := c.cc.Invoke(ctx, WebhookService_VerifyEndpoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetCapturedDeliveries(ctx context.Context, in *GetCapturedDeliveriesRequest, opts ...grpc.CallOption) (*GetCapturedDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetCapturedDeliveriesResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetCapturedDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointRecoveryRamp(ctx context.Context, in *SetEndpointRecoveryRampRequest, opts ...grpc.CallOption) (*SetEndpointRecoveryRampResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointRecoveryRampResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointRecoveryRamp_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointRetryPolicy(ctx context.Context, in *SetEndpointRetryPolicyRequest, opts ...grpc.CallOption) (*SetEndpointRetryPolicyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointRetryPolicyResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointRetryPolicy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointClientCertificate(ctx context.Context, in *SetEndpointClientCertificateRequest, opts ...grpc.CallOption) (*SetEndpointClientCertificateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointClientCertificateResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointClientCertificate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointCompression(ctx context.Context, in *SetEndpointCompressionRequest, opts ...grpc.CallOption) (*SetEndpointCompressionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointCompressionResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointCompression_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointTimeout(ctx context.Context, in *SetEndpointTimeoutRequest, opts ...grpc.CallOption) (*SetEndpointTimeoutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointTimeoutResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointTimeout_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointProxy(ctx context.Context, in *SetEndpointProxyRequest, opts ...grpc.CallOption) (*SetEndpointProxyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointProxyResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointProxy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointSignatureScheme(ctx context.Context, in *SetEndpointSignatureSchemeRequest, opts ...grpc.CallOption) (*SetEndpointSignatureSchemeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointSignatureSchemeResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointSignatureScheme_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetSigningKeys(ctx context.Context, in *GetSigningKeysRequest, opts ...grpc.CallOption) (*GetSigningKeysResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetSigningKeysResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetSigningKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetEgressIPs(ctx context.Context, in *GetEgressIPsRequest, opts ...grpc.CallOption) (*GetEgressIPsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEgressIPsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetEgressIPs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointOrdering(ctx context.Context, in *SetEndpointOrderingRequest, opts ...grpc.CallOption) (*SetEndpointOrderingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointOrderingResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointOrdering_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetEndpointHealthCheck(ctx context.Context, in *SetEndpointHealthCheckRequest, opts ...grpc.CallOption) (*SetEndpointHealthCheckResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetEndpointHealthCheckResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetEndpointHealthCheck_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) EnableEndpoint(ctx context.Context, in *EnableEndpointRequest, opts ...grpc.CallOption) (*EnableEndpointResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnableEndpointResponse)
+	err := c.cc.Invoke(ctx, WebhookService_EnableEndpoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) DeleteEndpoint(ctx context.Context, in *DeleteEndpointRequest, opts ...grpc.CallOption) (*DeleteEndpointResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteEndpointResponse)
+	err := c.cc.Invoke(ctx, WebhookService_DeleteEndpoint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *webhookServiceClient) CreateSubscription(ctx context.Context, in *CreateSubscriptionRequest, opts ...grpc.CallOption) (*CreateSubscriptionResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(CreateSubscriptionResponse)
@@ -79,235 +323,1591 @@ func (c *webhookServiceClient) CreateSubscription(ctx context.Context, in *Creat
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *webhookServiceClient) PublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublishEventResponse)
+	err := c.cc.Invoke(ctx, WebhookService_PublishEvent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) PublishEvents(ctx context.Context, in *PublishEventsRequest, opts ...grpc.CallOption) (*PublishEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublishEventsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_PublishEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) CreateEventSchema(ctx context.Context, in *CreateEventSchemaRequest, opts ...grpc.CallOption) (*CreateEventSchemaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateEventSchemaResponse)
+	err := c.cc.Invoke(ctx, WebhookService_CreateEventSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListEventSchemas(ctx context.Context, in *ListEventSchemasRequest, opts ...grpc.CallOption) (*ListEventSchemasResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEventSchemasResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListEventSchemas_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetEventSchema(ctx context.Context, in *GetEventSchemaRequest, opts ...grpc.CallOption) (*GetEventSchemaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetEventSchemaResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetEventSchema_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetDeliveryStatus(ctx context.Context, in *GetDeliveryStatusRequest, opts ...grpc.CallOption) (*GetDeliveryStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeliveryStatusResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetDeliveryStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) WatchDeliveryStatus(ctx context.Context, in *WatchDeliveryStatusRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchDeliveryStatusResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &WebhookService_ServiceDesc.Streams[0], WebhookService_WatchDeliveryStatus_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchDeliveryStatusRequest, WatchDeliveryStatusResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WebhookService_WatchDeliveryStatusClient = grpc.ServerStreamingClient[WatchDeliveryStatusResponse]
+
+func (c *webhookServiceClient) ReplayDelivery(ctx context.Context, in *ReplayDeliveryRequest, opts ...grpc.CallOption) (*ReplayDeliveryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayDeliveryResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ReplayDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) AcknowledgeDelivery(ctx context.Context, in *AcknowledgeDeliveryRequest, opts ...grpc.CallOption) (*AcknowledgeDeliveryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcknowledgeDeliveryResponse)
+	err := c.cc.Invoke(ctx, WebhookService_AcknowledgeDelivery_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListDLQ(ctx context.Context, in *ListDLQRequest, opts ...grpc.CallOption) (*ListDLQResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDLQResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListDLQ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ReplayDLQ(ctx context.Context, in *ReplayDLQRequest, opts ...grpc.CallOption) (*ReplayDLQResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayDLQResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ReplayDLQ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetDLQEntry(ctx context.Context, in *GetDLQEntryRequest, opts ...grpc.CallOption) (*GetDLQEntryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDLQEntryResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetDLQEntry_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) PurgeDLQ(ctx context.Context, in *PurgeDLQRequest, opts ...grpc.CallOption) (*PurgeDLQResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PurgeDLQResponse)
+	err := c.cc.Invoke(ctx, WebhookService_PurgeDLQ_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetDLQRetention(ctx context.Context, in *SetDLQRetentionRequest, opts ...grpc.CallOption) (*SetDLQRetentionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDLQRetentionResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetDLQRetention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetDLQRetention(ctx context.Context, in *GetDLQRetentionRequest, opts ...grpc.CallOption) (*GetDLQRetentionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDLQRetentionResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetDLQRetention_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetComplianceMode(ctx context.Context, in *SetComplianceModeRequest, opts ...grpc.CallOption) (*SetComplianceModeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetComplianceModeResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetComplianceMode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetDeliverySettings(ctx context.Context, in *SetDeliverySettingsRequest, opts ...grpc.CallOption) (*SetDeliverySettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDeliverySettingsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetDeliverySettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListDeliveryRecordings(ctx context.Context, in *ListDeliveryRecordingsRequest, opts ...grpc.CallOption) (*ListDeliveryRecordingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeliveryRecordingsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListDeliveryRecordings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListAuditLog(ctx context.Context, in *ListAuditLogRequest, opts ...grpc.CallOption) (*ListAuditLogResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditLogResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListAuditLog_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) FreezeDeliveries(ctx context.Context, in *FreezeDeliveriesRequest, opts ...grpc.CallOption) (*FreezeDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FreezeDeliveriesResponse)
+	err := c.cc.Invoke(ctx, WebhookService_FreezeDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) DrainQueue(ctx context.Context, in *DrainQueueRequest, opts ...grpc.CallOption) (*DrainQueueResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DrainQueueResponse)
+	err := c.cc.Invoke(ctx, WebhookService_DrainQueue_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ResumeDeliveries(ctx context.Context, in *ResumeDeliveriesRequest, opts ...grpc.CallOption) (*ResumeDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeDeliveriesResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ResumeDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) PauseDispatch(ctx context.Context, in *PauseDispatchRequest, opts ...grpc.CallOption) (*PauseDispatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PauseDispatchResponse)
+	err := c.cc.Invoke(ctx, WebhookService_PauseDispatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ResumeDispatch(ctx context.Context, in *ResumeDispatchRequest, opts ...grpc.CallOption) (*ResumeDispatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResumeDispatchResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ResumeDispatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetDispatchState(ctx context.Context, in *GetDispatchStateRequest, opts ...grpc.CallOption) (*GetDispatchStateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDispatchStateResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetDispatchState_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetBacklogEstimate(ctx context.Context, in *GetBacklogEstimateRequest, opts ...grpc.CallOption) (*GetBacklogEstimateResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBacklogEstimateResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetBacklogEstimate_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetTenantQuota(ctx context.Context, in *SetTenantQuotaRequest, opts ...grpc.CallOption) (*SetTenantQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetTenantQuotaResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetTenantQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetTenantQuota(ctx context.Context, in *GetTenantQuotaRequest, opts ...grpc.CallOption) (*GetTenantQuotaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTenantQuotaResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetTenantQuota_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetFailureTrends(ctx context.Context, in *GetFailureTrendsRequest, opts ...grpc.CallOption) (*GetFailureTrendsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetFailureTrendsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetFailureTrends_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetDeliveryStats(ctx context.Context, in *GetDeliveryStatsRequest, opts ...grpc.CallOption) (*GetDeliveryStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDeliveryStatsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetDeliveryStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListSystemEvents(ctx context.Context, in *ListSystemEventsRequest, opts ...grpc.CallOption) (*ListSystemEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSystemEventsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListSystemEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) SetNotificationSettings(ctx context.Context, in *SetNotificationSettingsRequest, opts ...grpc.CallOption) (*SetNotificationSettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetNotificationSettingsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_SetNotificationSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) GetNotificationSettings(ctx context.Context, in *GetNotificationSettingsRequest, opts ...grpc.CallOption) (*GetNotificationSettingsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetNotificationSettingsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_GetNotificationSettings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListTenants(ctx context.Context, in *ListTenantsRequest, opts ...grpc.CallOption) (*ListTenantsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTenantsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListTenants_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListEndpoints(ctx context.Context, in *ListEndpointsRequest, opts ...grpc.CallOption) (*ListEndpointsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListEndpointsResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListEndpoints_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *webhookServiceClient) ListRecentDeliveries(ctx context.Context, in *ListRecentDeliveriesRequest, opts ...grpc.CallOption) (*ListRecentDeliveriesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListRecentDeliveriesResponse)
+	err := c.cc.Invoke(ctx, WebhookService_ListRecentDeliveries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WebhookServiceServer is the server API for WebhookService service.
+// All implementations should embed UnimplementedWebhookServiceServer
+// for forward compatibility.
+//
+// Main service definition. If you're viewing on Swagger/Redoc, this tag contains all paths available.
+type WebhookServiceServer interface {
+	// Placeholder to verify gateway wiring in later phases.
+	Ping(context.Context, *PingRequest) (*PingResponse, error)
+	CreateEndpoint(context.Context, *CreateEndpointRequest) (*CreateEndpointResponse, error)
+	VerifyEndpoint(context.Context, *VerifyEndpointRequest) (*VerifyEndpointResponse, error)
+	GetCapturedDeliveries(context.Context, *GetCapturedDeliveriesRequest) (*GetCapturedDeliveriesResponse, error)
+	SetEndpointRecoveryRamp(context.Context, *SetEndpointRecoveryRampRequest) (*SetEndpointRecoveryRampResponse, error)
+	SetEndpointRetryPolicy(context.Context, *SetEndpointRetryPolicyRequest) (*SetEndpointRetryPolicyResponse, error)
+	SetEndpointClientCertificate(context.Context, *SetEndpointClientCertificateRequest) (*SetEndpointClientCertificateResponse, error)
+	SetEndpointCompression(context.Context, *SetEndpointCompressionRequest) (*SetEndpointCompressionResponse, error)
+	SetEndpointTimeout(context.Context, *SetEndpointTimeoutRequest) (*SetEndpointTimeoutResponse, error)
+	SetEndpointProxy(context.Context, *SetEndpointProxyRequest) (*SetEndpointProxyResponse, error)
+	SetEndpointSignatureScheme(context.Context, *SetEndpointSignatureSchemeRequest) (*SetEndpointSignatureSchemeResponse, error)
+	GetSigningKeys(context.Context, *GetSigningKeysRequest) (*GetSigningKeysResponse, error)
+	GetEgressIPs(context.Context, *GetEgressIPsRequest) (*GetEgressIPsResponse, error)
+	SetEndpointOrdering(context.Context, *SetEndpointOrderingRequest) (*SetEndpointOrderingResponse, error)
+	SetEndpointHealthCheck(context.Context, *SetEndpointHealthCheckRequest) (*SetEndpointHealthCheckResponse, error)
+	EnableEndpoint(context.Context, *EnableEndpointRequest) (*EnableEndpointResponse, error)
+	DeleteEndpoint(context.Context, *DeleteEndpointRequest) (*DeleteEndpointResponse, error)
+	CreateSubscription(context.Context, *CreateSubscriptionRequest) (*CreateSubscriptionResponse, error)
+	PublishEvent(context.Context, *PublishEventRequest) (*PublishEventResponse, error)
+	PublishEvents(context.Context, *PublishEventsRequest) (*PublishEventsResponse, error)
+	CreateEventSchema(context.Context, *CreateEventSchemaRequest) (*CreateEventSchemaResponse, error)
+	ListEventSchemas(context.Context, *ListEventSchemasRequest) (*ListEventSchemasResponse, error)
+	GetEventSchema(context.Context, *GetEventSchemaRequest) (*GetEventSchemaResponse, error)
+	GetDeliveryStatus(context.Context, *GetDeliveryStatusRequest) (*GetDeliveryStatusResponse, error)
+	WatchDeliveryStatus(*WatchDeliveryStatusRequest, grpc.ServerStreamingServer[WatchDeliveryStatusResponse]) error
+	ReplayDelivery(context.Context, *ReplayDeliveryRequest) (*ReplayDeliveryResponse, error)
+	AcknowledgeDelivery(context.Context, *AcknowledgeDeliveryRequest) (*AcknowledgeDeliveryResponse, error)
+	ListDLQ(context.Context, *ListDLQRequest) (*ListDLQResponse, error)
+	ReplayDLQ(context.Context, *ReplayDLQRequest) (*ReplayDLQResponse, error)
+	GetDLQEntry(context.Context, *GetDLQEntryRequest) (*GetDLQEntryResponse, error)
+	PurgeDLQ(context.Context, *PurgeDLQRequest) (*PurgeDLQResponse, error)
+	SetDLQRetention(context.Context, *SetDLQRetentionRequest) (*SetDLQRetentionResponse, error)
+	GetDLQRetention(context.Context, *GetDLQRetentionRequest) (*GetDLQRetentionResponse, error)
+	SetComplianceMode(context.Context, *SetComplianceModeRequest) (*SetComplianceModeResponse, error)
+	SetDeliverySettings(context.Context, *SetDeliverySettingsRequest) (*SetDeliverySettingsResponse, error)
+	ListDeliveryRecordings(context.Context, *ListDeliveryRecordingsRequest) (*ListDeliveryRecordingsResponse, error)
+	ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error)
+	FreezeDeliveries(context.Context, *FreezeDeliveriesRequest) (*FreezeDeliveriesResponse, error)
+	DrainQueue(context.Context, *DrainQueueRequest) (*DrainQueueResponse, error)
+	ResumeDeliveries(context.Context, *ResumeDeliveriesRequest) (*ResumeDeliveriesResponse, error)
+	PauseDispatch(context.Context, *PauseDispatchRequest) (*PauseDispatchResponse, error)
+	ResumeDispatch(context.Context, *ResumeDispatchRequest) (*ResumeDispatchResponse, error)
+	GetDispatchState(context.Context, *GetDispatchStateRequest) (*GetDispatchStateResponse, error)
+	GetBacklogEstimate(context.Context, *GetBacklogEstimateRequest) (*GetBacklogEstimateResponse, error)
+	SetTenantQuota(context.Context, *SetTenantQuotaRequest) (*SetTenantQuotaResponse, error)
+	GetTenantQuota(context.Context, *GetTenantQuotaRequest) (*GetTenantQuotaResponse, error)
+	GetFailureTrends(context.Context, *GetFailureTrendsRequest) (*GetFailureTrendsResponse, error)
+	GetDeliveryStats(context.Context, *GetDeliveryStatsRequest) (*GetDeliveryStatsResponse, error)
+	ListSystemEvents(context.Context, *ListSystemEventsRequest) (*ListSystemEventsResponse, error)
+	SetNotificationSettings(context.Context, *SetNotificationSettingsRequest) (*SetNotificationSettingsResponse, error)
+	GetNotificationSettings(context.Context, *GetNotificationSettingsRequest) (*GetNotificationSettingsResponse, error)
+	ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error)
+	ListEndpoints(context.Context, *ListEndpointsRequest) (*ListEndpointsResponse, error)
+	ListRecentDeliveries(context.Context, *ListRecentDeliveriesRequest) (*ListRecentDeliveriesResponse, error)
+}
+
+// UnimplementedWebhookServiceServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWebhookServiceServer struct{}
+
+func (UnimplementedWebhookServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedWebhookServiceServer) CreateEndpoint(context.Context, *CreateEndpointRequest) (*CreateEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEndpoint not implemented")
+}
+func (UnimplementedWebhookServiceServer) VerifyEndpoint(context.Context, *VerifyEndpointRequest) (*VerifyEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyEndpoint not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetCapturedDeliveries(context.Context, *GetCapturedDeliveriesRequest) (*GetCapturedDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCapturedDeliveries not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointRecoveryRamp(context.Context, *SetEndpointRecoveryRampRequest) (*SetEndpointRecoveryRampResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointRecoveryRamp not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointRetryPolicy(context.Context, *SetEndpointRetryPolicyRequest) (*SetEndpointRetryPolicyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointRetryPolicy not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointClientCertificate(context.Context, *SetEndpointClientCertificateRequest) (*SetEndpointClientCertificateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointClientCertificate not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointCompression(context.Context, *SetEndpointCompressionRequest) (*SetEndpointCompressionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointCompression not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointTimeout(context.Context, *SetEndpointTimeoutRequest) (*SetEndpointTimeoutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointTimeout not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointProxy(context.Context, *SetEndpointProxyRequest) (*SetEndpointProxyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointProxy not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointSignatureScheme(context.Context, *SetEndpointSignatureSchemeRequest) (*SetEndpointSignatureSchemeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointSignatureScheme not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetSigningKeys(context.Context, *GetSigningKeysRequest) (*GetSigningKeysResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSigningKeys not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetEgressIPs(context.Context, *GetEgressIPsRequest) (*GetEgressIPsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEgressIPs not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointOrdering(context.Context, *SetEndpointOrderingRequest) (*SetEndpointOrderingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointOrdering not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetEndpointHealthCheck(context.Context, *SetEndpointHealthCheckRequest) (*SetEndpointHealthCheckResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetEndpointHealthCheck not implemented")
+}
+func (UnimplementedWebhookServiceServer) EnableEndpoint(context.Context, *EnableEndpointRequest) (*EnableEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnableEndpoint not implemented")
+}
+func (UnimplementedWebhookServiceServer) DeleteEndpoint(context.Context, *DeleteEndpointRequest) (*DeleteEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteEndpoint not implemented")
+}
+func (UnimplementedWebhookServiceServer) CreateSubscription(context.Context, *CreateSubscriptionRequest) (*CreateSubscriptionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSubscription not implemented")
+}
+func (UnimplementedWebhookServiceServer) PublishEvent(context.Context, *PublishEventRequest) (*PublishEventResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishEvent not implemented")
+}
+func (UnimplementedWebhookServiceServer) PublishEvents(context.Context, *PublishEventsRequest) (*PublishEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PublishEvents not implemented")
+}
+func (UnimplementedWebhookServiceServer) CreateEventSchema(context.Context, *CreateEventSchemaRequest) (*CreateEventSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateEventSchema not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListEventSchemas(context.Context, *ListEventSchemasRequest) (*ListEventSchemasResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEventSchemas not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetEventSchema(context.Context, *GetEventSchemaRequest) (*GetEventSchemaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventSchema not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetDeliveryStatus(context.Context, *GetDeliveryStatusRequest) (*GetDeliveryStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryStatus not implemented")
+}
+func (UnimplementedWebhookServiceServer) WatchDeliveryStatus(*WatchDeliveryStatusRequest, grpc.ServerStreamingServer[WatchDeliveryStatusResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchDeliveryStatus not implemented")
+}
+func (UnimplementedWebhookServiceServer) ReplayDelivery(context.Context, *ReplayDeliveryRequest) (*ReplayDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDelivery not implemented")
+}
+func (UnimplementedWebhookServiceServer) AcknowledgeDelivery(context.Context, *AcknowledgeDeliveryRequest) (*AcknowledgeDeliveryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AcknowledgeDelivery not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListDLQ(context.Context, *ListDLQRequest) (*ListDLQResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDLQ not implemented")
+}
+func (UnimplementedWebhookServiceServer) ReplayDLQ(context.Context, *ReplayDLQRequest) (*ReplayDLQResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplayDLQ not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetDLQEntry(context.Context, *GetDLQEntryRequest) (*GetDLQEntryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDLQEntry not implemented")
+}
+func (UnimplementedWebhookServiceServer) PurgeDLQ(context.Context, *PurgeDLQRequest) (*PurgeDLQResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeDLQ not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetDLQRetention(context.Context, *SetDLQRetentionRequest) (*SetDLQRetentionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDLQRetention not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetDLQRetention(context.Context, *GetDLQRetentionRequest) (*GetDLQRetentionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDLQRetention not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetComplianceMode(context.Context, *SetComplianceModeRequest) (*SetComplianceModeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetComplianceMode not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetDeliverySettings(context.Context, *SetDeliverySettingsRequest) (*SetDeliverySettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDeliverySettings not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListDeliveryRecordings(context.Context, *ListDeliveryRecordingsRequest) (*ListDeliveryRecordingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDeliveryRecordings not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListAuditLog(context.Context, *ListAuditLogRequest) (*ListAuditLogResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAuditLog not implemented")
+}
+func (UnimplementedWebhookServiceServer) FreezeDeliveries(context.Context, *FreezeDeliveriesRequest) (*FreezeDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FreezeDeliveries not implemented")
+}
+func (UnimplementedWebhookServiceServer) DrainQueue(context.Context, *DrainQueueRequest) (*DrainQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainQueue not implemented")
+}
+func (UnimplementedWebhookServiceServer) ResumeDeliveries(context.Context, *ResumeDeliveriesRequest) (*ResumeDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeDeliveries not implemented")
+}
+func (UnimplementedWebhookServiceServer) PauseDispatch(context.Context, *PauseDispatchRequest) (*PauseDispatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseDispatch not implemented")
+}
+func (UnimplementedWebhookServiceServer) ResumeDispatch(context.Context, *ResumeDispatchRequest) (*ResumeDispatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeDispatch not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetDispatchState(context.Context, *GetDispatchStateRequest) (*GetDispatchStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDispatchState not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetBacklogEstimate(context.Context, *GetBacklogEstimateRequest) (*GetBacklogEstimateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBacklogEstimate not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetTenantQuota(context.Context, *SetTenantQuotaRequest) (*SetTenantQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetTenantQuota not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetTenantQuota(context.Context, *GetTenantQuotaRequest) (*GetTenantQuotaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTenantQuota not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetFailureTrends(context.Context, *GetFailureTrendsRequest) (*GetFailureTrendsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFailureTrends not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetDeliveryStats(context.Context, *GetDeliveryStatsRequest) (*GetDeliveryStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryStats not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListSystemEvents(context.Context, *ListSystemEventsRequest) (*ListSystemEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSystemEvents not implemented")
+}
+func (UnimplementedWebhookServiceServer) SetNotificationSettings(context.Context, *SetNotificationSettingsRequest) (*SetNotificationSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetNotificationSettings not implemented")
+}
+func (UnimplementedWebhookServiceServer) GetNotificationSettings(context.Context, *GetNotificationSettingsRequest) (*GetNotificationSettingsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetNotificationSettings not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListTenants(context.Context, *ListTenantsRequest) (*ListTenantsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTenants not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListEndpoints(context.Context, *ListEndpointsRequest) (*ListEndpointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListEndpoints not implemented")
+}
+func (UnimplementedWebhookServiceServer) ListRecentDeliveries(context.Context, *ListRecentDeliveriesRequest) (*ListRecentDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListRecentDeliveries not implemented")
+}
+func (UnimplementedWebhookServiceServer) testEmbeddedByValue() {}
+
+// UnsafeWebhookServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WebhookServiceServer will
+// result in compilation errors.
+type UnsafeWebhookServiceServer interface {
+	mustEmbedUnimplementedWebhookServiceServer()
+}
+
+func RegisterWebhookServiceServer(s grpc.ServiceRegistrar, srv WebhookServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWebhookServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WebhookService_ServiceDesc, srv)
+}
+
+func _WebhookService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).Ping(ctx, req.(*PingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_CreateEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).CreateEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_CreateEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).CreateEndpoint(ctx, req.(*CreateEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_VerifyEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).VerifyEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_VerifyEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).VerifyEndpoint(ctx, req.(*VerifyEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_GetCapturedDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCapturedDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetCapturedDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetCapturedDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetCapturedDeliveries(ctx, req.(*GetCapturedDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointRecoveryRamp_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointRecoveryRampRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointRecoveryRamp(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointRecoveryRamp_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointRecoveryRamp(ctx, req.(*SetEndpointRecoveryRampRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointRetryPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointRetryPolicyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointRetryPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointRetryPolicy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointRetryPolicy(ctx, req.(*SetEndpointRetryPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointClientCertificate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointClientCertificateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointClientCertificate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointClientCertificate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointClientCertificate(ctx, req.(*SetEndpointClientCertificateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointCompression_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointCompressionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointCompression(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointCompression_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointCompression(ctx, req.(*SetEndpointCompressionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointTimeout_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointTimeoutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointTimeout(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointTimeout_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointTimeout(ctx, req.(*SetEndpointTimeoutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointProxy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointProxyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointProxy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointProxy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointProxy(ctx, req.(*SetEndpointProxyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointSignatureScheme_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointSignatureSchemeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointSignatureScheme(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointSignatureScheme_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointSignatureScheme(ctx, req.(*SetEndpointSignatureSchemeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_GetSigningKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSigningKeysRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetSigningKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetSigningKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetSigningKeys(ctx, req.(*GetSigningKeysRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_GetEgressIPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEgressIPsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetEgressIPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetEgressIPs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetEgressIPs(ctx, req.(*GetEgressIPsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointOrdering_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointOrderingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointOrdering(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointOrdering_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointOrdering(ctx, req.(*SetEndpointOrderingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetEndpointHealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetEndpointHealthCheckRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetEndpointHealthCheck(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetEndpointHealthCheck_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetEndpointHealthCheck(ctx, req.(*SetEndpointHealthCheckRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_EnableEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnableEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).EnableEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_EnableEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).EnableEndpoint(ctx, req.(*EnableEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_DeleteEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).DeleteEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_DeleteEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).DeleteEndpoint(ctx, req.(*DeleteEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_CreateSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).CreateSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_CreateSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).CreateSubscription(ctx, req.(*CreateSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_PublishEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishEventRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).PublishEvent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_PublishEvent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).PublishEvent(ctx, req.(*PublishEventRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_PublishEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).PublishEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_PublishEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).PublishEvents(ctx, req.(*PublishEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_CreateEventSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateEventSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).CreateEventSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_CreateEventSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).CreateEventSchema(ctx, req.(*CreateEventSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ListEventSchemas_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEventSchemasRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ListEventSchemas(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ListEventSchemas_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ListEventSchemas(ctx, req.(*ListEventSchemasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_GetEventSchema_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventSchemaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetEventSchema(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetEventSchema_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetEventSchema(ctx, req.(*GetEventSchemaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_GetDeliveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeliveryStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetDeliveryStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetDeliveryStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetDeliveryStatus(ctx, req.(*GetDeliveryStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_WatchDeliveryStatus_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchDeliveryStatusRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(WebhookServiceServer).WatchDeliveryStatus(m, &grpc.GenericServerStream[WatchDeliveryStatusRequest, WatchDeliveryStatusResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type WebhookService_WatchDeliveryStatusServer = grpc.ServerStreamingServer[WatchDeliveryStatusResponse]
+
+func _WebhookService_ReplayDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ReplayDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ReplayDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ReplayDelivery(ctx, req.(*ReplayDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_AcknowledgeDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcknowledgeDeliveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).AcknowledgeDelivery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_AcknowledgeDelivery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).AcknowledgeDelivery(ctx, req.(*AcknowledgeDeliveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ListDLQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDLQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ListDLQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ListDLQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ListDLQ(ctx, req.(*ListDLQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ReplayDLQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayDLQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ReplayDLQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ReplayDLQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ReplayDLQ(ctx, req.(*ReplayDLQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_GetDLQEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDLQEntryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetDLQEntry(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetDLQEntry_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetDLQEntry(ctx, req.(*GetDLQEntryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_PurgeDLQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeDLQRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).PurgeDLQ(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_PurgeDLQ_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).PurgeDLQ(ctx, req.(*PurgeDLQRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetDLQRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDLQRetentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetDLQRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetDLQRetention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetDLQRetention(ctx, req.(*SetDLQRetentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_GetDLQRetention_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDLQRetentionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetDLQRetention(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetDLQRetention_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetDLQRetention(ctx, req.(*GetDLQRetentionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetComplianceMode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetComplianceModeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetComplianceMode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetComplianceMode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetComplianceMode(ctx, req.(*SetComplianceModeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_SetDeliverySettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDeliverySettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetDeliverySettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetDeliverySettings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetDeliverySettings(ctx, req.(*SetDeliverySettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ListDeliveryRecordings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeliveryRecordingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ListDeliveryRecordings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ListDeliveryRecordings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ListDeliveryRecordings(ctx, req.(*ListDeliveryRecordingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ListAuditLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditLogRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ListAuditLog(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ListAuditLog_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ListAuditLog(ctx, req.(*ListAuditLogRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_FreezeDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FreezeDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).FreezeDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_FreezeDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).FreezeDeliveries(ctx, req.(*FreezeDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_DrainQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).DrainQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_DrainQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).DrainQueue(ctx, req.(*DrainQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ResumeDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ResumeDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ResumeDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ResumeDeliveries(ctx, req.(*ResumeDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_PauseDispatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseDispatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).PauseDispatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_PauseDispatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).PauseDispatch(ctx, req.(*PauseDispatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_ResumeDispatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeDispatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).ResumeDispatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_ResumeDispatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).ResumeDispatch(ctx, req.(*ResumeDispatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WebhookService_GetDispatchState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDispatchStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetDispatchState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetDispatchState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetDispatchState(ctx, req.(*GetDispatchStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *webhookServiceClient) PublishEvent(ctx context.Context, in *PublishEventRequest, opts ...grpc.CallOption) (*PublishEventResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(PublishEventResponse)
-	err := c.cc.Invoke(ctx, WebhookService_PublishEvent_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _WebhookService_GetBacklogEstimate_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBacklogEstimateRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetBacklogEstimate(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetBacklogEstimate_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetBacklogEstimate(ctx, req.(*GetBacklogEstimateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *webhookServiceClient) GetDeliveryStatus(ctx context.Context, in *GetDeliveryStatusRequest, opts ...grpc.CallOption) (*GetDeliveryStatusResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(GetDeliveryStatusResponse)
-	err := c.cc.Invoke(ctx, WebhookService_GetDeliveryStatus_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _WebhookService_SetTenantQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetTenantQuotaRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).SetTenantQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_SetTenantQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).SetTenantQuota(ctx, req.(*SetTenantQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *webhookServiceClient) ReplayDelivery(ctx context.Context, in *ReplayDeliveryRequest, opts ...grpc.CallOption) (*ReplayDeliveryResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ReplayDeliveryResponse)
-	err := c.cc.Invoke(ctx, WebhookService_ReplayDelivery_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _WebhookService_GetTenantQuota_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTenantQuotaRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetTenantQuota(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetTenantQuota_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetTenantQuota(ctx, req.(*GetTenantQuotaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *webhookServiceClient) ListDLQ(ctx context.Context, in *ListDLQRequest, opts ...grpc.CallOption) (*ListDLQResponse, error) {
-	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
-	out := new(ListDLQResponse)
-	err := c.cc.Invoke(ctx, WebhookService_ListDLQ_FullMethodName, in, out, cOpts...)
-	if err != nil {
+func _WebhookService_GetFailureTrends_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFailureTrendsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
-}
-
-// WebhookServiceServer is the server API for WebhookService service.
-// All implementations should embed UnimplementedWebhookServiceServer
-// for forward compatibility.
-//
-// Main service definition. If you're viewing on Swagger/Redoc, this tag contains all paths available.
-type WebhookServiceServer interface {
-	// Placeholder to verify gateway wiring in later phases.
-	Ping(context.Context, *PingRequest) (*PingResponse, error)
-	CreateEndpoint(context.Context, *CreateEndpointRequest) (*CreateEndpointResponse, error)
-	CreateSubscription(context.Context, *CreateSubscriptionRequest) (*CreateSubscriptionResponse, error)
-	PublishEvent(context.Context, *PublishEventRequest) (*PublishEventResponse, error)
-	GetDeliveryStatus(context.Context, *GetDeliveryStatusRequest) (*GetDeliveryStatusResponse, error)
-	ReplayDelivery(context.Context, *ReplayDeliveryRequest) (*ReplayDeliveryResponse, error)
-	ListDLQ(context.Context, *ListDLQRequest) (*ListDLQResponse, error)
-}
-
-// UnimplementedWebhookServiceServer should be embedded to have
-// forward compatible implementations.
-//
-// NOTE: this should be embedded by value instead of pointer to avoid a nil
-// pointer dereference when methods are called.
-type UnimplementedWebhookServiceServer struct{}
-
-func (UnimplementedWebhookServiceServer) Ping(context.Context, *PingRequest) (*PingResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
-}
-func (UnimplementedWebhookServiceServer) CreateEndpoint(context.Context, *CreateEndpointRequest) (*CreateEndpointResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateEndpoint not implemented")
-}
-func (UnimplementedWebhookServiceServer) CreateSubscription(context.Context, *CreateSubscriptionRequest) (*CreateSubscriptionResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method CreateSubscription not implemented")
-}
-func (UnimplementedWebhookServiceServer) PublishEvent(context.Context, *PublishEventRequest) (*PublishEventResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method PublishEvent not implemented")
-}
-func (UnimplementedWebhookServiceServer) GetDeliveryStatus(context.Context, *GetDeliveryStatusRequest) (*GetDeliveryStatusResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method GetDeliveryStatus not implemented")
-}
-func (UnimplementedWebhookServiceServer) ReplayDelivery(context.Context, *ReplayDeliveryRequest) (*ReplayDeliveryResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ReplayDelivery not implemented")
-}
-func (UnimplementedWebhookServiceServer) ListDLQ(context.Context, *ListDLQRequest) (*ListDLQResponse, error) {
-	return nil, status.Errorf(codes.Unimplemented, "method ListDLQ not implemented")
-}
-func (UnimplementedWebhookServiceServer) testEmbeddedByValue() {}
-
-// UnsafeWebhookServiceServer may be embedded to opt out of forward compatibility for this service.
-// Use of this interface is not recommended, as added methods to WebhookServiceServer will
-// result in compilation errors.
-type UnsafeWebhookServiceServer interface {
-	mustEmbedUnimplementedWebhookServiceServer()
-}
-
-func RegisterWebhookServiceServer(s grpc.ServiceRegistrar, srv WebhookServiceServer) {
-	// If the following call pancis, it indicates UnimplementedWebhookServiceServer was
-	// embedded by pointer and is nil.  This will cause panics if an
-	// unimplemented method is ever invoked, so we test this at initialization
-	// time to prevent it from happening at runtime later due to I/O.
-	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
-		t.testEmbeddedByValue()
+	if interceptor == nil {
+		return srv.(WebhookServiceServer).GetFailureTrends(ctx, in)
 	}
-	s.RegisterService(&WebhookService_ServiceDesc, srv)
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WebhookService_GetFailureTrends_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WebhookServiceServer).GetFailureTrends(ctx, req.(*GetFailureTrendsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _WebhookService_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PingRequest)
+func _WebhookService_GetDeliveryStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDeliveryStatsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WebhookServiceServer).Ping(ctx, in)
+		return srv.(WebhookServiceServer).GetDeliveryStats(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: WebhookService_Ping_FullMethodName,
+		FullMethod: WebhookService_GetDeliveryStats_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WebhookServiceServer).Ping(ctx, req.(*PingRequest))
+		return srv.(WebhookServiceServer).GetDeliveryStats(ctx, req.(*GetDeliveryStatsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WebhookService_CreateEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateEndpointRequest)
+func _WebhookService_ListSystemEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSystemEventsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WebhookServiceServer).CreateEndpoint(ctx, in)
+		return srv.(WebhookServiceServer).ListSystemEvents(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: WebhookService_CreateEndpoint_FullMethodName,
+		FullMethod: WebhookService_ListSystemEvents_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WebhookServiceServer).CreateEndpoint(ctx, req.(*CreateEndpointRequest))
+		return srv.(WebhookServiceServer).ListSystemEvents(ctx, req.(*ListSystemEventsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WebhookService_CreateSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateSubscriptionRequest)
+func _WebhookService_SetNotificationSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetNotificationSettingsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WebhookServiceServer).CreateSubscription(ctx, in)
+		return srv.(WebhookServiceServer).SetNotificationSettings(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: WebhookService_CreateSubscription_FullMethodName,
+		FullMethod: WebhookService_SetNotificationSettings_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WebhookServiceServer).CreateSubscription(ctx, req.(*CreateSubscriptionRequest))
+		return srv.(WebhookServiceServer).SetNotificationSettings(ctx, req.(*SetNotificationSettingsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WebhookService_PublishEvent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PublishEventRequest)
+func _WebhookService_GetNotificationSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetNotificationSettingsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WebhookServiceServer).PublishEvent(ctx, in)
+		return srv.(WebhookServiceServer).GetNotificationSettings(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: WebhookService_PublishEvent_FullMethodName,
+		FullMethod: WebhookService_GetNotificationSettings_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WebhookServiceServer).PublishEvent(ctx, req.(*PublishEventRequest))
+		return srv.(WebhookServiceServer).GetNotificationSettings(ctx, req.(*GetNotificationSettingsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WebhookService_GetDeliveryStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetDeliveryStatusRequest)
+func _WebhookService_ListTenants_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTenantsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WebhookServiceServer).GetDeliveryStatus(ctx, in)
+		return srv.(WebhookServiceServer).ListTenants(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: WebhookService_GetDeliveryStatus_FullMethodName,
+		FullMethod: WebhookService_ListTenants_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WebhookServiceServer).GetDeliveryStatus(ctx, req.(*GetDeliveryStatusRequest))
+		return srv.(WebhookServiceServer).ListTenants(ctx, req.(*ListTenantsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WebhookService_ReplayDelivery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ReplayDeliveryRequest)
+func _WebhookService_ListEndpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListEndpointsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WebhookServiceServer).ReplayDelivery(ctx, in)
+		return srv.(WebhookServiceServer).ListEndpoints(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: WebhookService_ReplayDelivery_FullMethodName,
+		FullMethod: WebhookService_ListEndpoints_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WebhookServiceServer).ReplayDelivery(ctx, req.(*ReplayDeliveryRequest))
+		return srv.(WebhookServiceServer).ListEndpoints(ctx, req.(*ListEndpointsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _WebhookService_ListDLQ_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListDLQRequest)
+func _WebhookService_ListRecentDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecentDeliveriesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(WebhookServiceServer).ListDLQ(ctx, in)
+		return srv.(WebhookServiceServer).ListRecentDeliveries(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: WebhookService_ListDLQ_FullMethodName,
+		FullMethod: WebhookService_ListRecentDeliveries_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(WebhookServiceServer).ListDLQ(ctx, req.(*ListDLQRequest))
+		return srv.(WebhookServiceServer).ListRecentDeliveries(ctx, req.(*ListRecentDeliveriesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -327,6 +1927,66 @@ var WebhookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateEndpoint",
 			Handler:    _WebhookService_CreateEndpoint_Handler,
 		},
+		{
+			MethodName: "VerifyEndpoint",
+			Handler:    _WebhookService_VerifyEndpoint_Handler,
+		},
+		{
+			MethodName: "GetCapturedDeliveries",
+			Handler:    _WebhookService_GetCapturedDeliveries_Handler,
+		},
+		{
+			MethodName: "SetEndpointRecoveryRamp",
+			Handler:    _WebhookService_SetEndpointRecoveryRamp_Handler,
+		},
+		{
+			MethodName: "SetEndpointRetryPolicy",
+			Handler:    _WebhookService_SetEndpointRetryPolicy_Handler,
+		},
+		{
+			MethodName: "SetEndpointClientCertificate",
+			Handler:    _WebhookService_SetEndpointClientCertificate_Handler,
+		},
+		{
+			MethodName: "SetEndpointCompression",
+			Handler:    _WebhookService_SetEndpointCompression_Handler,
+		},
+		{
+			MethodName: "SetEndpointTimeout",
+			Handler:    _WebhookService_SetEndpointTimeout_Handler,
+		},
+		{
+			MethodName: "SetEndpointProxy",
+			Handler:    _WebhookService_SetEndpointProxy_Handler,
+		},
+		{
+			MethodName: "SetEndpointSignatureScheme",
+			Handler:    _WebhookService_SetEndpointSignatureScheme_Handler,
+		},
+		{
+			MethodName: "GetSigningKeys",
+			Handler:    _WebhookService_GetSigningKeys_Handler,
+		},
+		{
+			MethodName: "GetEgressIPs",
+			Handler:    _WebhookService_GetEgressIPs_Handler,
+		},
+		{
+			MethodName: "SetEndpointOrdering",
+			Handler:    _WebhookService_SetEndpointOrdering_Handler,
+		},
+		{
+			MethodName: "SetEndpointHealthCheck",
+			Handler:    _WebhookService_SetEndpointHealthCheck_Handler,
+		},
+		{
+			MethodName: "EnableEndpoint",
+			Handler:    _WebhookService_EnableEndpoint_Handler,
+		},
+		{
+			MethodName: "DeleteEndpoint",
+			Handler:    _WebhookService_DeleteEndpoint_Handler,
+		},
 		{
 			MethodName: "CreateSubscription",
 			Handler:    _WebhookService_CreateSubscription_Handler,
@@ -335,6 +1995,22 @@ var WebhookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "PublishEvent",
 			Handler:    _WebhookService_PublishEvent_Handler,
 		},
+		{
+			MethodName: "PublishEvents",
+			Handler:    _WebhookService_PublishEvents_Handler,
+		},
+		{
+			MethodName: "CreateEventSchema",
+			Handler:    _WebhookService_CreateEventSchema_Handler,
+		},
+		{
+			MethodName: "ListEventSchemas",
+			Handler:    _WebhookService_ListEventSchemas_Handler,
+		},
+		{
+			MethodName: "GetEventSchema",
+			Handler:    _WebhookService_GetEventSchema_Handler,
+		},
 		{
 			MethodName: "GetDeliveryStatus",
 			Handler:    _WebhookService_GetDeliveryStatus_Handler,
@@ -343,11 +2019,125 @@ var WebhookService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReplayDelivery",
 			Handler:    _WebhookService_ReplayDelivery_Handler,
 		},
+		{
+			MethodName: "AcknowledgeDelivery",
+			Handler:    _WebhookService_AcknowledgeDelivery_Handler,
+		},
 		{
 			MethodName: "ListDLQ",
 			Handler:    _WebhookService_ListDLQ_Handler,
 		},
+		{
+			MethodName: "ReplayDLQ",
+			Handler:    _WebhookService_ReplayDLQ_Handler,
+		},
+		{
+			MethodName: "GetDLQEntry",
+			Handler:    _WebhookService_GetDLQEntry_Handler,
+		},
+		{
+			MethodName: "PurgeDLQ",
+			Handler:    _WebhookService_PurgeDLQ_Handler,
+		},
+		{
+			MethodName: "SetDLQRetention",
+			Handler:    _WebhookService_SetDLQRetention_Handler,
+		},
+		{
+			MethodName: "GetDLQRetention",
+			Handler:    _WebhookService_GetDLQRetention_Handler,
+		},
+		{
+			MethodName: "SetComplianceMode",
+			Handler:    _WebhookService_SetComplianceMode_Handler,
+		},
+		{
+			MethodName: "SetDeliverySettings",
+			Handler:    _WebhookService_SetDeliverySettings_Handler,
+		},
+		{
+			MethodName: "ListDeliveryRecordings",
+			Handler:    _WebhookService_ListDeliveryRecordings_Handler,
+		},
+		{
+			MethodName: "ListAuditLog",
+			Handler:    _WebhookService_ListAuditLog_Handler,
+		},
+		{
+			MethodName: "FreezeDeliveries",
+			Handler:    _WebhookService_FreezeDeliveries_Handler,
+		},
+		{
+			MethodName: "DrainQueue",
+			Handler:    _WebhookService_DrainQueue_Handler,
+		},
+		{
+			MethodName: "ResumeDeliveries",
+			Handler:    _WebhookService_ResumeDeliveries_Handler,
+		},
+		{
+			MethodName: "PauseDispatch",
+			Handler:    _WebhookService_PauseDispatch_Handler,
+		},
+		{
+			MethodName: "ResumeDispatch",
+			Handler:    _WebhookService_ResumeDispatch_Handler,
+		},
+		{
+			MethodName: "GetDispatchState",
+			Handler:    _WebhookService_GetDispatchState_Handler,
+		},
+		{
+			MethodName: "GetBacklogEstimate",
+			Handler:    _WebhookService_GetBacklogEstimate_Handler,
+		},
+		{
+			MethodName: "SetTenantQuota",
+			Handler:    _WebhookService_SetTenantQuota_Handler,
+		},
+		{
+			MethodName: "GetTenantQuota",
+			Handler:    _WebhookService_GetTenantQuota_Handler,
+		},
+		{
+			MethodName: "GetFailureTrends",
+			Handler:    _WebhookService_GetFailureTrends_Handler,
+		},
+		{
+			MethodName: "GetDeliveryStats",
+			Handler:    _WebhookService_GetDeliveryStats_Handler,
+		},
+		{
+			MethodName: "ListSystemEvents",
+			Handler:    _WebhookService_ListSystemEvents_Handler,
+		},
+		{
+			MethodName: "SetNotificationSettings",
+			Handler:    _WebhookService_SetNotificationSettings_Handler,
+		},
+		{
+			MethodName: "GetNotificationSettings",
+			Handler:    _WebhookService_GetNotificationSettings_Handler,
+		},
+		{
+			MethodName: "ListTenants",
+			Handler:    _WebhookService_ListTenants_Handler,
+		},
+		{
+			MethodName: "ListEndpoints",
+			Handler:    _WebhookService_ListEndpoints_Handler,
+		},
+		{
+			MethodName: "ListRecentDeliveries",
+			Handler:    _WebhookService_ListRecentDeliveries_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchDeliveryStatus",
+			Handler:       _WebhookService_WatchDeliveryStatus_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "api/webhook/v1/service.proto",
 }